@@ -0,0 +1,246 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
+)
+
+// Trade sides
+const (
+	Buy  = "BUY"
+	Sell = "SELL"
+)
+
+// positionTolerance allows for floating point error when comparing a sell
+// with the amount of a position
+const positionTolerance = 1e-9
+
+var (
+	// ErrNoCandles is returned when running a backtest without candles
+	ErrNoCandles = errors.New("backtest requires candles")
+	// ErrInvalidConfig is returned when the initial balance, fee rate or
+	// slippage of a backtest is invalid
+	ErrInvalidConfig = errors.New("backtest requires a positive initial balance and non negative fee rate and slippage")
+	// ErrInsufficientFunds is returned when a buy costs more than the cash
+	// balance
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrInsufficientPosition is returned when selling more than the position
+	ErrInsufficientPosition = errors.New("insufficient position")
+)
+
+// Config holds the settings of a backtest. Fees and slippage are charged on
+// every trade, FeeRate as a fraction of the trade value and SlippageBps in
+// basis points against the trade. Seed seeds the random source handed to the
+// strategy, so a backtest run twice on the same candles with the same seed
+// produces the same report
+type Config struct {
+	InitialBalance float64
+	FeeRate        float64
+	SlippageBps    float64
+	Seed           int64
+}
+
+// StrategyFunc is called with each closed candle in time order and places
+// trades through the tester
+type StrategyFunc func(t *Tester, c kline.Candle)
+
+// Trade holds a trade executed during a backtest. Fees are denominated in
+// the quote currency and Profit is the realised profit of a sell after fees
+type Trade struct {
+	Timestamp time.Time `json:"timestamp"`
+	Pair      string    `json:"pair"`
+	Side      string    `json:"side"`
+	Amount    float64   `json:"amount"`
+	Price     float64   `json:"price"`
+	Fee       float64   `json:"fee"`
+	Profit    float64   `json:"profit"`
+}
+
+// position holds the amount held of a pair and its average cost including
+// fees
+type position struct {
+	amount  float64
+	avgCost float64
+}
+
+// Tester holds the simulated account of a backtest, long only positions in a
+// single quote currency. Trades fill at the close of the current candle of
+// their pair
+type Tester struct {
+	cfg       Config
+	rand      *rand.Rand
+	now       time.Time
+	cash      float64
+	prices    map[string]float64
+	positions map[string]*position
+	trades    []Trade
+}
+
+// Run replays candles through a strategy and returns the report of the
+// resulting trades. Candles are replayed by end time and then by pair so runs
+// are reproducible regardless of the order they are supplied in, and equity is
+// sampled once all candles ending at the same time have been replayed
+func Run(cfg Config, candles []kline.Candle, strategy StrategyFunc) (Report, error) {
+	if len(candles) == 0 {
+		return Report{}, ErrNoCandles
+	}
+
+	if cfg.InitialBalance <= 0 || cfg.FeeRate < 0 || cfg.SlippageBps < 0 {
+		return Report{}, ErrInvalidConfig
+	}
+
+	sorted := make([]kline.Candle, len(candles))
+	copy(sorted, candles)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].EndTime().Equal(sorted[j].EndTime()) {
+			return sorted[i].EndTime().Before(sorted[j].EndTime())
+		}
+		return pairKey(sorted[i]) < pairKey(sorted[j])
+	})
+
+	t := &Tester{
+		cfg:       cfg,
+		rand:      rand.New(rand.NewSource(cfg.Seed)),
+		cash:      cfg.InitialBalance,
+		prices:    make(map[string]float64),
+		positions: make(map[string]*position),
+	}
+
+	r := newReportBuilder(cfg, sorted[0].StartTime)
+	for x := range sorted {
+		t.now = sorted[x].EndTime()
+		t.prices[pairKey(sorted[x])] = sorted[x].Close
+		strategy(t, sorted[x])
+
+		if x == len(sorted)-1 || !sorted[x+1].EndTime().Equal(t.now) {
+			r.sample(t)
+		}
+	}
+	return r.build(t), nil
+}
+
+// Rand returns the random source of the backtest seeded by the config
+func (t *Tester) Rand() *rand.Rand {
+	return t.rand
+}
+
+// Now returns the end time of the candle being replayed
+func (t *Tester) Now() time.Time {
+	return t.now
+}
+
+// Cash returns the cash balance in the quote currency
+func (t *Tester) Cash() float64 {
+	return t.cash
+}
+
+// Position returns the amount held of a pair, as formatted by pairKey
+func (t *Tester) Position(p string) float64 {
+	if pos, ok := t.positions[p]; ok {
+		return pos.amount
+	}
+	return 0
+}
+
+// Equity returns the cash balance plus the value of the positions at the last
+// close of each pair
+func (t *Tester) Equity() float64 {
+	equity := t.cash
+	for _, p := range t.pairs() {
+		equity += t.positions[p].amount * t.prices[p]
+	}
+	return equity
+}
+
+// pairs returns the pairs of the open positions in order, so sums over the
+// positions are reproducible
+func (t *Tester) pairs() []string {
+	pairs := make([]string, 0, len(t.positions))
+	for p := range t.positions {
+		pairs = append(pairs, p)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// Buy buys an amount of a candle's pair at its close plus slippage
+func (t *Tester) Buy(c kline.Candle, amount float64) error {
+	p := pairKey(c)
+	price := t.prices[p] * (1 + t.cfg.SlippageBps/10000)
+	if amount <= 0 || price <= 0 {
+		return fmt.Errorf("invalid buy of %v %s", amount, p)
+	}
+
+	fee := amount * price * t.cfg.FeeRate
+	cost := amount*price + fee
+	if cost > t.cash {
+		return ErrInsufficientFunds
+	}
+
+	pos, ok := t.positions[p]
+	if !ok {
+		pos = &position{}
+		t.positions[p] = pos
+	}
+	pos.avgCost = (pos.avgCost*pos.amount + cost) / (pos.amount + amount)
+	pos.amount += amount
+	t.cash -= cost
+
+	t.trades = append(t.trades, Trade{
+		Timestamp: t.now,
+		Pair:      p,
+		Side:      Buy,
+		Amount:    amount,
+		Price:     price,
+		Fee:       fee,
+	})
+	return nil
+}
+
+// Sell sells an amount of a candle's pair at its close less slippage. A sell
+// within positionTolerance of the position closes it
+func (t *Tester) Sell(c kline.Candle, amount float64) error {
+	p := pairKey(c)
+	price := t.prices[p] * (1 - t.cfg.SlippageBps/10000)
+	if amount <= 0 || price <= 0 {
+		return fmt.Errorf("invalid sell of %v %s", amount, p)
+	}
+
+	pos, ok := t.positions[p]
+	if !ok || amount > pos.amount+positionTolerance {
+		return ErrInsufficientPosition
+	}
+	if amount > pos.amount {
+		amount = pos.amount
+	}
+
+	fee := amount * price * t.cfg.FeeRate
+	proceeds := amount*price - fee
+	profit := proceeds - amount*pos.avgCost
+	pos.amount -= amount
+	if pos.amount < positionTolerance {
+		delete(t.positions, p)
+	}
+	t.cash += proceeds
+
+	t.trades = append(t.trades, Trade{
+		Timestamp: t.now,
+		Pair:      p,
+		Side:      Sell,
+		Amount:    amount,
+		Price:     price,
+		Fee:       fee,
+		Profit:    profit,
+	})
+	return nil
+}
+
+// pairKey returns the key positions and prices of a candle's pair are held by
+func pairKey(c kline.Candle) string {
+	return c.Pair.Display("-", true).String()
+}
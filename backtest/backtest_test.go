@@ -0,0 +1,173 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
+)
+
+var testStart = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testCandles(p pair.CurrencyPair, closes ...float64) []kline.Candle {
+	var c []kline.Candle
+	for x := range closes {
+		c = append(c, kline.Candle{
+			Pair:      p,
+			Interval:  time.Hour,
+			StartTime: testStart.Add(time.Hour * time.Duration(x)),
+			Close:     closes[x],
+			Closed:    true,
+		})
+	}
+	return c
+}
+
+func TestRunInvalid(t *testing.T) {
+	_, err := Run(Config{InitialBalance: 1000}, nil, func(*Tester, kline.Candle) {})
+	if err != ErrNoCandles {
+		t.Errorf("Test failed - Run error expected %v, got %v", ErrNoCandles, err)
+	}
+
+	c := testCandles(pair.NewCurrencyPair("BTC", "USD"), 100)
+	_, err = Run(Config{}, c, func(*Tester, kline.Candle) {})
+	if err != ErrInvalidConfig {
+		t.Errorf("Test failed - Run error expected %v, got %v", ErrInvalidConfig, err)
+	}
+
+	_, err = Run(Config{InitialBalance: 1000, FeeRate: -1}, c, func(*Tester, kline.Candle) {})
+	if err != ErrInvalidConfig {
+		t.Errorf("Test failed - Run error expected %v, got %v", ErrInvalidConfig, err)
+	}
+}
+
+func TestBuySell(t *testing.T) {
+	c := testCandles(pair.NewCurrencyPair("BTC", "USD"), 100, 110)
+	var buyErr, sellErr, fundsErr, positionErr error
+	report, err := Run(Config{InitialBalance: 1000, FeeRate: 0.01}, c,
+		func(tester *Tester, c kline.Candle) {
+			if c.Close == 100 {
+				fundsErr = tester.Buy(c, 10)
+				buyErr = tester.Buy(c, 5)
+				return
+			}
+			positionErr = tester.Sell(c, 6)
+			sellErr = tester.Sell(c, 5)
+		})
+	if err != nil {
+		t.Fatalf("Test failed - Run error: %s", err)
+	}
+
+	if fundsErr != ErrInsufficientFunds {
+		t.Errorf("Test failed - Buy error expected %v, got %v", ErrInsufficientFunds, fundsErr)
+	}
+	if positionErr != ErrInsufficientPosition {
+		t.Errorf("Test failed - Sell error expected %v, got %v", ErrInsufficientPosition, positionErr)
+	}
+	if buyErr != nil || sellErr != nil {
+		t.Fatalf("Test failed - trade errors: %v %v", buyErr, sellErr)
+	}
+
+	// bought 5 at 100 for 505 and sold 5 at 110 for 544.5
+	if report.FinalEquity != 1039.5 {
+		t.Errorf("Test failed - final equity expected 1039.5, got %v", report.FinalEquity)
+	}
+	if len(report.Trades) != 2 || report.Trades[1].Profit != 39.5 {
+		t.Errorf("Test failed - unexpected trades %v", report.Trades)
+	}
+}
+
+func TestSellTolerance(t *testing.T) {
+	c := testCandles(pair.NewCurrencyPair("BTC", "USD"), 100)
+	var sellErr error
+	report, err := Run(Config{InitialBalance: 1000}, c,
+		func(tester *Tester, c kline.Candle) {
+			tester.Buy(c, 0.3)
+			tester.Sell(c, 0.1)
+			// 0.3 less 0.1 leaves 0.19999999999999998
+			sellErr = tester.Sell(c, 0.2)
+		})
+	if err != nil {
+		t.Fatalf("Test failed - Run error: %s", err)
+	}
+
+	if sellErr != nil {
+		t.Fatalf("Test failed - Sell error: %s", sellErr)
+	}
+	if len(report.Exposure) != 1 || report.Exposure[0].TimeInMarket != 0 {
+		t.Errorf("Test failed - position left open after selling it %+v", report.Exposure)
+	}
+}
+
+func TestSlippage(t *testing.T) {
+	c := testCandles(pair.NewCurrencyPair("BTC", "USD"), 100)
+	report, err := Run(Config{InitialBalance: 1000, SlippageBps: 50}, c,
+		func(tester *Tester, c kline.Candle) {
+			tester.Buy(c, 1)
+			tester.Sell(c, 1)
+		})
+	if err != nil {
+		t.Fatalf("Test failed - Run error: %s", err)
+	}
+
+	if math.Abs(report.Trades[0].Price-100.5) > 1e-9 ||
+		math.Abs(report.Trades[1].Price-99.5) > 1e-9 {
+		t.Errorf("Test failed - unexpected slippage on trades %v", report.Trades)
+	}
+	if report.WinRate != 0 {
+		t.Errorf("Test failed - win rate expected 0, got %v", report.WinRate)
+	}
+}
+
+func TestRunOrder(t *testing.T) {
+	btc := testCandles(pair.NewCurrencyPair("BTC", "USD"), 100, 101)
+	ltc := testCandles(pair.NewCurrencyPair("LTC", "USD"), 10, 11)
+	candles := []kline.Candle{ltc[1], btc[1], ltc[0], btc[0]}
+
+	var replayed []string
+	report, err := Run(Config{InitialBalance: 1000}, candles,
+		func(tester *Tester, c kline.Candle) {
+			replayed = append(replayed, pairKey(c))
+		})
+	if err != nil {
+		t.Fatalf("Test failed - Run error: %s", err)
+	}
+
+	expected := []string{"BTC-USD", "LTC-USD", "BTC-USD", "LTC-USD"}
+	for x := range expected {
+		if replayed[x] != expected[x] {
+			t.Fatalf("Test failed - replay order expected %v, got %v", expected, replayed)
+		}
+	}
+	if len(report.EquityCurve) != 2 {
+		t.Errorf("Test failed - expected 2 equity samples, got %d", len(report.EquityCurve))
+	}
+}
+
+func TestRunDeterministic(t *testing.T) {
+	c := testCandles(pair.NewCurrencyPair("BTC", "USD"), 100, 104, 98, 103, 97, 110, 105)
+	strategy := func(tester *Tester, c kline.Candle) {
+		if tester.Rand().Intn(2) == 0 {
+			tester.Buy(c, tester.Rand().Float64())
+			return
+		}
+		tester.Sell(c, tester.Position(pairKey(c)))
+	}
+
+	cfg := Config{InitialBalance: 1000, FeeRate: 0.001, Seed: 1337}
+	a, err := Run(cfg, c, strategy)
+	if err != nil {
+		t.Fatalf("Test failed - Run error: %s", err)
+	}
+	b, err := Run(cfg, c, strategy)
+	if err != nil {
+		t.Fatalf("Test failed - Run error: %s", err)
+	}
+
+	if a.TradeCount == 0 || a.TradeCount != b.TradeCount || a.FinalEquity != b.FinalEquity ||
+		a.SharpeRatio != b.SharpeRatio {
+		t.Error("Test failed - backtests with the same seed should produce the same report")
+	}
+}
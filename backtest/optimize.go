@@ -0,0 +1,329 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
+)
+
+// Optimizer search methods
+const (
+	SearchGrid   = "grid"
+	SearchRandom = "random"
+)
+
+// maxRandomAttempts bounds the draws per requested sample when random search
+// skips parameter sets it has already drawn
+const maxRandomAttempts = 10
+
+var (
+	// ErrNoParameters is returned when optimizing without parameter ranges
+	ErrNoParameters = errors.New("optimizer requires at least one parameter range")
+	// ErrInvalidSearch is returned when optimizing with an unknown search
+	// method or a random search without samples
+	ErrInvalidSearch = errors.New("optimizer search must be grid, or random with a positive sample count")
+)
+
+// ParameterRange holds the values a strategy parameter is optimized over,
+// from Min to Max inclusive in Step increments. A zero Step or equal Min and
+// Max fixes the parameter at Min
+type ParameterRange struct {
+	Name string  `json:"name"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Step float64 `json:"step"`
+}
+
+// Parameters holds the strategy parameter values of an optimizer run by name
+type Parameters map[string]float64
+
+// StrategyFactory returns the strategy of a backtest run with a set of
+// parameters. Each run calls it once, concurrently with other runs, so the
+// returned strategy must not share mutable state
+type StrategyFactory func(params Parameters) StrategyFunc
+
+// OptimizerConfig holds the settings of an optimization. Every run uses the
+// Backtest config, so runs differ only by their parameters. Grid search runs
+// every combination of the ranges while random search runs Samples
+// combinations drawn with Seed. Workers runs in parallel, defaulting to the
+// number of CPUs, and Progress is called after each run completes
+type OptimizerConfig struct {
+	Backtest Config
+	Ranges   []ParameterRange
+	Search   string
+	Samples  int
+	Seed     int64
+	Workers  int
+	Progress func(completed, total int)
+}
+
+// OptimizationResult holds the parameters of an optimizer run and its report,
+// without the equity curve and trades to bound memory across large searches
+type OptimizationResult struct {
+	Parameters Parameters `json:"parameters"`
+	Report     Report     `json:"report"`
+}
+
+// OptimizationReport holds the results of every optimizer run in the order
+// the parameter sets were generated, and the Pareto front of the results on
+// total return, Sharpe ratio and max drawdown ordered by Sharpe ratio
+type OptimizationReport struct {
+	Search      string               `json:"search"`
+	Ranges      []ParameterRange     `json:"ranges"`
+	Results     []OptimizationResult `json:"results"`
+	ParetoFront []OptimizationResult `json:"paretoFront"`
+}
+
+// Optimize runs the backtest over candles with the strategy of each parameter
+// set of the search in parallel, returning the results and their Pareto
+// front. The same config and candles always produce the same report
+func Optimize(cfg OptimizerConfig, candles []kline.Candle, factory StrategyFactory) (OptimizationReport, error) {
+	sets, err := parameterSets(cfg)
+	if err != nil {
+		return OptimizationReport{}, err
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(sets) {
+		workers = len(sets)
+	}
+
+	type runResult struct {
+		index  int
+		report Report
+		err    error
+	}
+
+	jobs := make(chan int)
+	results := make(chan runResult)
+	for x := 0; x < workers; x++ {
+		go func() {
+			for i := range jobs {
+				report, err := Run(cfg.Backtest, candles, factory(sets[i]))
+				report.EquityCurve = nil
+				report.Trades = nil
+				results <- runResult{index: i, report: report, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for x := range sets {
+			jobs <- x
+		}
+		close(jobs)
+	}()
+
+	optimization := OptimizationReport{
+		Search:  cfg.Search,
+		Ranges:  cfg.Ranges,
+		Results: make([]OptimizationResult, len(sets)),
+	}
+	var runErr error
+	for completed := 1; completed <= len(sets); completed++ {
+		r := <-results
+		if r.err != nil && runErr == nil {
+			runErr = fmt.Errorf("parameters %s: %s", formatParameters(sets[r.index]), r.err)
+		}
+		optimization.Results[r.index] = OptimizationResult{
+			Parameters: sets[r.index],
+			Report:     r.report,
+		}
+		if cfg.Progress != nil {
+			cfg.Progress(completed, len(sets))
+		}
+	}
+	if runErr != nil {
+		return OptimizationReport{}, runErr
+	}
+
+	optimization.ParetoFront = paretoFront(optimization.Results)
+	return optimization, nil
+}
+
+// parameterSets returns the parameter sets of the search in a deterministic
+// order
+func parameterSets(cfg OptimizerConfig) ([]Parameters, error) {
+	if len(cfg.Ranges) == 0 {
+		return nil, ErrNoParameters
+	}
+
+	values := make([][]float64, len(cfg.Ranges))
+	seen := make(map[string]bool)
+	for x, r := range cfg.Ranges {
+		if r.Name == "" || seen[r.Name] {
+			return nil, fmt.Errorf("parameter range %d requires a unique name", x)
+		}
+		seen[r.Name] = true
+
+		if r.Max < r.Min || r.Step < 0 || (r.Max > r.Min && r.Step == 0) {
+			return nil, fmt.Errorf("parameter %s requires a max no less than its min and a positive step", r.Name)
+		}
+		values[x] = r.values()
+	}
+
+	switch cfg.Search {
+	case SearchGrid:
+		return gridSets(cfg.Ranges, values), nil
+	case SearchRandom:
+		if cfg.Samples <= 0 {
+			return nil, ErrInvalidSearch
+		}
+		return randomSets(cfg.Ranges, values, cfg.Samples, cfg.Seed), nil
+	}
+	return nil, ErrInvalidSearch
+}
+
+// values returns the values of the range in ascending order
+func (r ParameterRange) values() []float64 {
+	if r.Step == 0 || r.Max == r.Min {
+		return []float64{r.Min}
+	}
+
+	// Allow for floating point error in the final step
+	steps := int(math.Floor((r.Max-r.Min)/r.Step + 1e-9))
+	values := make([]float64, steps+1)
+	for x := range values {
+		values[x] = r.Min + float64(x)*r.Step
+	}
+	return values
+}
+
+// gridSets returns every combination of the values, varying the last range
+// fastest
+func gridSets(ranges []ParameterRange, values [][]float64) []Parameters {
+	sets := []Parameters{{}}
+	for x, r := range ranges {
+		var next []Parameters
+		for _, set := range sets {
+			for _, v := range values[x] {
+				params := make(Parameters, len(set)+1)
+				for k, existing := range set {
+					params[k] = existing
+				}
+				params[r.Name] = v
+				next = append(next, params)
+			}
+		}
+		sets = next
+	}
+	return sets
+}
+
+// randomSets returns up to samples distinct combinations of the values drawn
+// from a source seeded by seed, fewer when the grid holds fewer combinations
+func randomSets(ranges []ParameterRange, values [][]float64, samples int, seed int64) []Parameters {
+	source := rand.New(rand.NewSource(seed))
+	seen := make(map[string]bool)
+	var sets []Parameters
+	for attempt := 0; len(sets) < samples && attempt < samples*maxRandomAttempts; attempt++ {
+		params := make(Parameters, len(ranges))
+		for x, r := range ranges {
+			params[r.Name] = values[x][source.Intn(len(values[x]))]
+		}
+
+		key := formatParameters(params)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		sets = append(sets, params)
+	}
+	return sets
+}
+
+// dominates returns whether a report is no worse than another on total
+// return, Sharpe ratio and max drawdown and better on at least one
+func dominates(a, b Report) bool {
+	if a.TotalReturn < b.TotalReturn || a.SharpeRatio < b.SharpeRatio || a.MaxDrawdown > b.MaxDrawdown {
+		return false
+	}
+	return a.TotalReturn > b.TotalReturn || a.SharpeRatio > b.SharpeRatio || a.MaxDrawdown < b.MaxDrawdown
+}
+
+// paretoFront returns the results no other result dominates, ordered by
+// Sharpe ratio and then total return descending
+func paretoFront(results []OptimizationResult) []OptimizationResult {
+	var front []OptimizationResult
+	for x := range results {
+		dominated := false
+		for y := range results {
+			if x != y && dominates(results[y].Report, results[x].Report) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, results[x])
+		}
+	}
+
+	sort.SliceStable(front, func(i, j int) bool {
+		if front[i].Report.SharpeRatio != front[j].Report.SharpeRatio {
+			return front[i].Report.SharpeRatio > front[j].Report.SharpeRatio
+		}
+		return front[i].Report.TotalReturn > front[j].Report.TotalReturn
+	})
+	return front
+}
+
+// formatParameters returns the parameters as name=value pairs ordered by name
+func formatParameters(params Parameters) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for x, name := range names {
+		pairs[x] = name + "=" + formatFloat(params[name])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// WriteResultsCSV writes the parameters and metrics of every run as CSV rows
+func (o *OptimizationReport) WriteResultsCSV(w io.Writer) error {
+	return writeCSV(w, o.csvRows(o.Results))
+}
+
+// WriteParetoCSV writes the parameters and metrics of the Pareto front as CSV
+// rows
+func (o *OptimizationReport) WriteParetoCSV(w io.Writer) error {
+	return writeCSV(w, o.csvRows(o.ParetoFront))
+}
+
+func (o *OptimizationReport) csvRows(results []OptimizationResult) [][]string {
+	header := make([]string, 0, len(o.Ranges)+7)
+	for _, r := range o.Ranges {
+		header = append(header, r.Name)
+	}
+	header = append(header, "totalReturn", "maxDrawdown", "sharpeRatio",
+		"sortinoRatio", "winRate", "tradeCount", "fees")
+
+	rows := [][]string{header}
+	for _, result := range results {
+		row := make([]string, 0, len(header))
+		for _, r := range o.Ranges {
+			row = append(row, formatFloat(result.Parameters[r.Name]))
+		}
+		report := result.Report
+		row = append(row, formatFloat(report.TotalReturn), formatFloat(report.MaxDrawdown),
+			formatFloat(report.SharpeRatio), formatFloat(report.SortinoRatio),
+			formatFloat(report.WinRate), strconv.Itoa(report.TradeCount),
+			formatFloat(report.Fees))
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,115 @@
+package backtest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
+)
+
+// thresholdStrategy buys below the buy parameter and sells above the sell
+// parameter
+func thresholdStrategy(params Parameters) StrategyFunc {
+	return func(tester *Tester, c kline.Candle) {
+		if c.Close < params["buy"] && tester.Position(pairKey(c)) == 0 {
+			tester.Buy(c, 1)
+		} else if c.Close > params["sell"] {
+			tester.Sell(c, tester.Position(pairKey(c)))
+		}
+	}
+}
+
+func TestParameterSets(t *testing.T) {
+	_, err := parameterSets(OptimizerConfig{Search: SearchGrid})
+	if err != ErrNoParameters {
+		t.Errorf("Test failed - parameterSets error expected %v, got %v", ErrNoParameters, err)
+	}
+
+	ranges := []ParameterRange{{Name: "buy", Min: 95, Max: 100, Step: 2.5}, {Name: "sell", Min: 105, Max: 110, Step: 5}}
+	sets, err := parameterSets(OptimizerConfig{Ranges: ranges, Search: SearchGrid})
+	if err != nil {
+		t.Fatalf("Test failed - parameterSets error: %s", err)
+	}
+	if len(sets) != 6 || sets[0]["buy"] != 95 || sets[1]["sell"] != 110 || sets[5]["buy"] != 100 {
+		t.Errorf("Test failed - unexpected grid %v", sets)
+	}
+
+	_, err = parameterSets(OptimizerConfig{Ranges: ranges, Search: SearchRandom})
+	if err != ErrInvalidSearch {
+		t.Errorf("Test failed - parameterSets error expected %v, got %v", ErrInvalidSearch, err)
+	}
+
+	a, _ := parameterSets(OptimizerConfig{Ranges: ranges, Search: SearchRandom, Samples: 4, Seed: 7})
+	b, _ := parameterSets(OptimizerConfig{Ranges: ranges, Search: SearchRandom, Samples: 4, Seed: 7})
+	if len(a) != 4 || formatParameters(a[3]) != formatParameters(b[3]) {
+		t.Errorf("Test failed - random samples with the same seed differ %v %v", a, b)
+	}
+
+	sets, _ = parameterSets(OptimizerConfig{Ranges: ranges, Search: SearchRandom, Samples: 50})
+	if len(sets) != 6 {
+		t.Errorf("Test failed - random search expected the 6 distinct sets, got %d", len(sets))
+	}
+
+	_, err = parameterSets(OptimizerConfig{Ranges: []ParameterRange{{Name: "buy", Min: 2, Max: 1}}, Search: SearchGrid})
+	if err == nil {
+		t.Error("Test failed - parameterSets accepted a max below the min")
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	c := testCandles(pair.NewCurrencyPair("BTC", "USD"), 100, 96, 104, 108, 99, 94, 107, 111)
+	var progress []int
+	cfg := OptimizerConfig{
+		Backtest: Config{InitialBalance: 1000, FeeRate: 0.001},
+		Ranges:   []ParameterRange{{Name: "buy", Min: 95, Max: 100, Step: 5}, {Name: "sell", Min: 103, Max: 110, Step: 1}},
+		Search:   SearchGrid,
+		Workers:  3,
+		Progress: func(completed, total int) { progress = append(progress, completed) },
+	}
+
+	report, err := Optimize(cfg, c, thresholdStrategy)
+	if err != nil {
+		t.Fatalf("Test failed - Optimize error: %s", err)
+	}
+	if len(report.Results) != 16 || len(progress) != 16 || progress[15] != 16 {
+		t.Fatalf("Test failed - expected 16 runs, got %d results and progress %v", len(report.Results), progress)
+	}
+	if report.Results[0].Parameters["buy"] != 95 || report.Results[0].Report.EquityCurve != nil {
+		t.Errorf("Test failed - unexpected first result %+v", report.Results[0])
+	}
+	if len(report.ParetoFront) == 0 {
+		t.Fatal("Test failed - empty Pareto front")
+	}
+	for _, front := range report.ParetoFront {
+		for _, result := range report.Results {
+			if dominates(result.Report, front.Report) {
+				t.Errorf("Test failed - Pareto front result %v dominated by %v", front.Parameters, result.Parameters)
+			}
+		}
+	}
+
+	again, err := Optimize(cfg, c, thresholdStrategy)
+	if err != nil {
+		t.Fatalf("Test failed - Optimize error: %s", err)
+	}
+	for x := range report.Results {
+		if report.Results[x].Report.FinalEquity != again.Results[x].Report.FinalEquity {
+			t.Fatal("Test failed - optimizations with the same config should produce the same results")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = report.WriteParetoCSV(&buf); err != nil {
+		t.Fatalf("Test failed - WriteParetoCSV error: %s", err)
+	}
+	if !strings.HasPrefix(buf.String(), "buy,sell,totalReturn") {
+		t.Errorf("Test failed - unexpected Pareto CSV header %q", buf.String())
+	}
+
+	cfg.Backtest = Config{}
+	if _, err = Optimize(cfg, c, thresholdStrategy); err == nil {
+		t.Error("Test failed - Optimize accepted an invalid backtest config")
+	}
+}
@@ -0,0 +1,284 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// year annualises the Sharpe and Sortino ratios from the average
+// period between equity samples
+const year = time.Hour * 24 * 365
+
+// EquityPoint holds the account equity after the candles ending at Timestamp
+// and its drawdown from the previous peak as a fraction of the peak
+type EquityPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Equity    float64   `json:"equity"`
+	Drawdown  float64   `json:"drawdown"`
+}
+
+// PairExposure holds the exposure to a pair over a backtest. TimeInMarket is
+// the fraction of equity samples with an open position and AverageExposure
+// the average position value as a fraction of equity across all samples
+type PairExposure struct {
+	Pair            string  `json:"pair"`
+	TimeInMarket    float64 `json:"timeInMarket"`
+	AverageExposure float64 `json:"averageExposure"`
+	Trades          int     `json:"trades"`
+	Volume          float64 `json:"volume"`
+	Fees            float64 `json:"fees"`
+	Profit          float64 `json:"profit"`
+}
+
+// Report holds the performance of a backtest. Returns, drawdowns, exposures
+// and the win rate are fractions. The Sharpe and Sortino ratios are annualised
+// from the per sample returns with a zero risk free rate, and the win rate is
+// the fraction of sells which realised a profit
+type Report struct {
+	Seed          int64          `json:"seed"`
+	Start         time.Time      `json:"start"`
+	End           time.Time      `json:"end"`
+	InitialEquity float64        `json:"initialEquity"`
+	FinalEquity   float64        `json:"finalEquity"`
+	TotalReturn   float64        `json:"totalReturn"`
+	MaxDrawdown   float64        `json:"maxDrawdown"`
+	SharpeRatio   float64        `json:"sharpeRatio"`
+	SortinoRatio  float64        `json:"sortinoRatio"`
+	WinRate       float64        `json:"winRate"`
+	TradeCount    int            `json:"tradeCount"`
+	Fees          float64        `json:"fees"`
+	Exposure      []PairExposure `json:"exposure"`
+	EquityCurve   []EquityPoint  `json:"equityCurve"`
+	Trades        []Trade        `json:"trades"`
+}
+
+// reportBuilder accumulates the equity samples and exposures of a backtest
+type reportBuilder struct {
+	cfg      Config
+	start    time.Time
+	curve    []EquityPoint
+	peak     float64
+	exposure map[string]*PairExposure
+}
+
+func newReportBuilder(cfg Config, start time.Time) *reportBuilder {
+	return &reportBuilder{
+		cfg:      cfg,
+		start:    start,
+		peak:     cfg.InitialBalance,
+		exposure: make(map[string]*PairExposure),
+	}
+}
+
+// sample records the equity and exposures of the tester
+func (r *reportBuilder) sample(t *Tester) {
+	equity := t.Equity()
+	if equity > r.peak {
+		r.peak = equity
+	}
+
+	var drawdown float64
+	if r.peak > 0 {
+		drawdown = (r.peak - equity) / r.peak
+	}
+	r.curve = append(r.curve, EquityPoint{
+		Timestamp: t.now,
+		Equity:    equity,
+		Drawdown:  drawdown,
+	})
+
+	for _, p := range t.pairs() {
+		e := r.getExposure(p)
+		e.TimeInMarket++
+		if equity > 0 {
+			e.AverageExposure += t.positions[p].amount * t.prices[p] / equity
+		}
+	}
+}
+
+func (r *reportBuilder) getExposure(p string) *PairExposure {
+	e, ok := r.exposure[p]
+	if !ok {
+		e = &PairExposure{Pair: p}
+		r.exposure[p] = e
+	}
+	return e
+}
+
+// build returns the report of the tester's trades and the sampled equity
+func (r *reportBuilder) build(t *Tester) Report {
+	report := Report{
+		Seed:          r.cfg.Seed,
+		Start:         r.start,
+		End:           t.now,
+		InitialEquity: r.cfg.InitialBalance,
+		FinalEquity:   t.Equity(),
+		TradeCount:    len(t.trades),
+		EquityCurve:   r.curve,
+		Trades:        t.trades,
+	}
+	report.TotalReturn = report.FinalEquity/report.InitialEquity - 1
+
+	var sells, wins int
+	for x := range t.trades {
+		trade := t.trades[x]
+		report.Fees += trade.Fee
+
+		e := r.getExposure(trade.Pair)
+		e.Trades++
+		e.Volume += trade.Amount * trade.Price
+		e.Fees += trade.Fee
+		if trade.Side == Sell {
+			e.Profit += trade.Profit
+			sells++
+			if trade.Profit > 0 {
+				wins++
+			}
+		}
+	}
+	if sells > 0 {
+		report.WinRate = float64(wins) / float64(sells)
+	}
+
+	for x := range r.curve {
+		if r.curve[x].Drawdown > report.MaxDrawdown {
+			report.MaxDrawdown = r.curve[x].Drawdown
+		}
+	}
+
+	for _, e := range r.exposure {
+		e.TimeInMarket /= float64(len(r.curve))
+		e.AverageExposure /= float64(len(r.curve))
+		report.Exposure = append(report.Exposure, *e)
+	}
+	sort.Slice(report.Exposure, func(i, j int) bool {
+		return report.Exposure[i].Pair < report.Exposure[j].Pair
+	})
+
+	report.SharpeRatio, report.SortinoRatio = riskRatios(r.cfg.InitialBalance, r.start, r.curve)
+	return report
+}
+
+// riskRatios returns the annualised Sharpe and Sortino ratios of the returns
+// between equity samples, the first return spanning from the start of the
+// backtest to the first sample. The ratios are zero when there are too few
+// samples or no variance
+func riskRatios(initial float64, start time.Time, curve []EquityPoint) (sharpe, sortino float64) {
+	if len(curve) < 2 {
+		return 0, 0
+	}
+
+	returns := make([]float64, len(curve))
+	previous := initial
+	var mean float64
+	for x := range curve {
+		returns[x] = curve[x].Equity/previous - 1
+		previous = curve[x].Equity
+		mean += returns[x]
+	}
+	mean /= float64(len(returns))
+
+	var variance, downside float64
+	for x := range returns {
+		variance += (returns[x] - mean) * (returns[x] - mean)
+		if returns[x] < 0 {
+			downside += returns[x] * returns[x]
+		}
+	}
+	variance /= float64(len(returns) - 1)
+	downside /= float64(len(returns))
+
+	period := curve[len(curve)-1].Timestamp.Sub(start) / time.Duration(len(curve))
+	if period <= 0 {
+		return 0, 0
+	}
+	annualise := math.Sqrt(float64(year) / float64(period))
+
+	if variance > 0 {
+		sharpe = mean / math.Sqrt(variance) * annualise
+	}
+	if downside > 0 {
+		sortino = mean / math.Sqrt(downside) * annualise
+	}
+	return sharpe, sortino
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(r)
+}
+
+// WriteSummaryCSV writes the report metrics as metric and value rows
+func (r *Report) WriteSummaryCSV(w io.Writer) error {
+	return writeCSV(w, [][]string{
+		{"metric", "value"},
+		{"seed", strconv.FormatInt(r.Seed, 10)},
+		{"start", formatTime(r.Start)},
+		{"end", formatTime(r.End)},
+		{"initialEquity", formatFloat(r.InitialEquity)},
+		{"finalEquity", formatFloat(r.FinalEquity)},
+		{"totalReturn", formatFloat(r.TotalReturn)},
+		{"maxDrawdown", formatFloat(r.MaxDrawdown)},
+		{"sharpeRatio", formatFloat(r.SharpeRatio)},
+		{"sortinoRatio", formatFloat(r.SortinoRatio)},
+		{"winRate", formatFloat(r.WinRate)},
+		{"tradeCount", strconv.Itoa(r.TradeCount)},
+		{"fees", formatFloat(r.Fees)},
+	})
+}
+
+// WriteExposureCSV writes the exposure to each pair as CSV rows
+func (r *Report) WriteExposureCSV(w io.Writer) error {
+	rows := [][]string{{"pair", "timeInMarket", "averageExposure", "trades", "volume", "fees", "profit"}}
+	for _, e := range r.Exposure {
+		rows = append(rows, []string{e.Pair, formatFloat(e.TimeInMarket),
+			formatFloat(e.AverageExposure), strconv.Itoa(e.Trades), formatFloat(e.Volume),
+			formatFloat(e.Fees), formatFloat(e.Profit)})
+	}
+	return writeCSV(w, rows)
+}
+
+// WriteEquityCSV writes the equity curve as CSV rows
+func (r *Report) WriteEquityCSV(w io.Writer) error {
+	rows := [][]string{{"timestamp", "equity", "drawdown"}}
+	for _, p := range r.EquityCurve {
+		rows = append(rows, []string{formatTime(p.Timestamp), formatFloat(p.Equity),
+			formatFloat(p.Drawdown)})
+	}
+	return writeCSV(w, rows)
+}
+
+// WriteTradesCSV writes the trades as CSV rows
+func (r *Report) WriteTradesCSV(w io.Writer) error {
+	rows := [][]string{{"timestamp", "pair", "side", "amount", "price", "fee", "profit"}}
+	for _, t := range r.Trades {
+		rows = append(rows, []string{formatTime(t.Timestamp), t.Pair, t.Side,
+			formatFloat(t.Amount), formatFloat(t.Price), formatFloat(t.Fee),
+			formatFloat(t.Profit)})
+	}
+	return writeCSV(w, rows)
+}
+
+func writeCSV(w io.Writer, rows [][]string) error {
+	c := csv.NewWriter(w)
+	err := c.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return c.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,144 @@
+package backtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
+)
+
+func testReport(t *testing.T) Report {
+	c := testCandles(pair.NewCurrencyPair("BTC", "USD"), 100, 120, 90, 110)
+	report, err := Run(Config{InitialBalance: 1000, FeeRate: 0.01}, c,
+		func(tester *Tester, c kline.Candle) {
+			switch c.Close {
+			case 100, 90:
+				tester.Buy(c, 5)
+			case 120, 110:
+				tester.Sell(c, 5)
+			}
+		})
+	if err != nil {
+		t.Fatalf("Test failed - Run error: %s", err)
+	}
+	return report
+}
+
+func TestReportMetrics(t *testing.T) {
+	report := testReport(t)
+
+	if report.TradeCount != 4 || report.WinRate != 1 {
+		t.Errorf("Test failed - expected 4 trades and a win rate of 1, got %d and %v",
+			report.TradeCount, report.WinRate)
+	}
+
+	// fees of 5, 6, 4.5 and 5.5
+	if math.Abs(report.Fees-21) > 1e-9 {
+		t.Errorf("Test failed - fees expected 21, got %v", report.Fees)
+	}
+
+	// the fee of the first buy draws equity down to 995 from the initial 1000,
+	// deeper than the fall to 1084.5 from the peak of 1089 after the first sell
+	if math.Abs(report.MaxDrawdown-0.005) > 1e-9 {
+		t.Errorf("Test failed - unexpected max drawdown %v", report.MaxDrawdown)
+	}
+
+	if report.SharpeRatio <= 0 || report.SortinoRatio <= report.SharpeRatio {
+		t.Errorf("Test failed - unexpected Sharpe %v and Sortino %v",
+			report.SharpeRatio, report.SortinoRatio)
+	}
+
+	if len(report.Exposure) != 1 {
+		t.Fatalf("Test failed - expected exposure to 1 pair, got %d", len(report.Exposure))
+	}
+	e := report.Exposure[0]
+	if e.Pair != "BTC-USD" || e.TimeInMarket != 0.5 || e.Trades != 4 {
+		t.Errorf("Test failed - unexpected exposure %+v", e)
+	}
+}
+
+func TestRiskRatios(t *testing.T) {
+	sharpe, sortino := riskRatios(1000, testStart, nil)
+	if sharpe != 0 || sortino != 0 {
+		t.Error("Test failed - ratios of an empty curve should be zero")
+	}
+
+	flat := testReport(t).EquityCurve[:1]
+	sharpe, sortino = riskRatios(flat[0].Equity, flat[0].Timestamp, append(flat, flat[0]))
+	if sharpe != 0 || sortino != 0 {
+		t.Error("Test failed - ratios of samples at the same time should be zero")
+	}
+
+	// returns of 10% and 5% over three hours from the start, an average
+	// period of an hour and a half
+	sharpe, _ = riskRatios(100, testStart, []EquityPoint{
+		{Timestamp: testStart.Add(time.Hour * 2), Equity: 110},
+		{Timestamp: testStart.Add(time.Hour * 3), Equity: 115.5},
+	})
+	expected := 0.075 / math.Sqrt(0.00125) * math.Sqrt(float64(year)/float64(time.Hour*3/2))
+	if math.Abs(sharpe-expected) > 1e-6 {
+		t.Errorf("Test failed - Sharpe expected %v, got %v", expected, sharpe)
+	}
+}
+
+func TestReportExport(t *testing.T) {
+	report := testReport(t)
+
+	var b bytes.Buffer
+	err := report.WriteJSON(&b)
+	if err != nil {
+		t.Fatalf("Test failed - WriteJSON error: %s", err)
+	}
+	var decoded Report
+	err = json.Unmarshal(b.Bytes(), &decoded)
+	if err != nil {
+		t.Fatalf("Test failed - unable to decode report: %s", err)
+	}
+	if decoded.FinalEquity != report.FinalEquity || len(decoded.Trades) != 4 ||
+		len(decoded.EquityCurve) != 4 {
+		t.Error("Test failed - decoded report does not match")
+	}
+
+	b.Reset()
+	err = report.WriteSummaryCSV(&b)
+	if err != nil {
+		t.Fatalf("Test failed - WriteSummaryCSV error: %s", err)
+	}
+	if !strings.HasPrefix(b.String(), "metric,value\nseed,0\nstart,2018-01-01T00:00:00Z\n") ||
+		!strings.Contains(b.String(), "\nwinRate,1\ntradeCount,4\n") {
+		t.Errorf("Test failed - unexpected summary CSV %s", b.String())
+	}
+
+	b.Reset()
+	err = report.WriteEquityCSV(&b)
+	if err != nil {
+		t.Fatalf("Test failed - WriteEquityCSV error: %s", err)
+	}
+	if strings.Count(b.String(), "\n") != 5 ||
+		!strings.HasPrefix(b.String(), "timestamp,equity,drawdown\n2018-01-01T01:00:00Z,") {
+		t.Errorf("Test failed - unexpected equity CSV %s", b.String())
+	}
+
+	b.Reset()
+	err = report.WriteExposureCSV(&b)
+	if err != nil {
+		t.Fatalf("Test failed - WriteExposureCSV error: %s", err)
+	}
+	if !strings.Contains(b.String(), "\nBTC-USD,0.5,") {
+		t.Errorf("Test failed - unexpected exposure CSV %s", b.String())
+	}
+
+	b.Reset()
+	err = report.WriteTradesCSV(&b)
+	if err != nil {
+		t.Fatalf("Test failed - WriteTradesCSV error: %s", err)
+	}
+	if strings.Count(b.String(), "\n") != 5 || !strings.Contains(b.String(), ",BTC-USD,SELL,5,120,") {
+		t.Errorf("Test failed - unexpected trades CSV %s", b.String())
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding/base32"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
@@ -134,6 +136,43 @@ func GetHMAC(hashType int, input, key []byte) []byte {
 	return hmac.Sum(nil)
 }
 
+// totpStep is the time step of time based one time passwords
+const totpStep = 30
+
+// GenerateTOTP returns the six digit RFC 6238 time based one time password of
+// a base32 encoded secret at a point in time
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	secret = strings.TrimRight(strings.ToUpper(strings.Replace(secret, " ", "", -1)), "=")
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return "", err
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(t.Unix()/totpStep))
+	sum := GetHMAC(HashSHA1, counter, key)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", code%1000000), nil
+}
+
+// VerifyTOTP returns whether a time based one time password is valid for a
+// base32 encoded secret at a point in time, allowing one time step of clock
+// drift either side
+func VerifyTOTP(secret, code string, t time.Time) bool {
+	for step := -1; step <= 1; step++ {
+		expected, err := GenerateTOTP(secret, t.Add(time.Duration(step*totpStep)*time.Second))
+		if err != nil {
+			return false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
 // Sha1ToHex takes a string, sha1 hashes it and return a hex string of the
 // result
 func Sha1ToHex(data string) string {
@@ -302,6 +341,10 @@ func IsValidCryptoAddress(address, crypto string) (bool, error) {
 		return regexp.MatchString("^[L3M][a-km-zA-HJ-NP-Z1-9]{25,34}$", address)
 	case "eth":
 		return regexp.MatchString("^0x[a-km-z0-9]{40}$", address)
+	case "bch":
+		return regexp.MatchString("^([13][a-km-zA-HJ-NP-Z1-9]{25,34}|(bitcoincash:)?[qp][a-z0-9]{41})$", address)
+	case "xrp":
+		return regexp.MatchString("^r[1-9A-HJ-NP-Za-km-z]{24,34}$", address)
 	default:
 		return false, errors.New("Invalid crypto currency")
 	}
@@ -62,6 +62,21 @@ func TestIsValidCryptoAddress(t *testing.T) {
 	if err == nil && b {
 		t.Error("Test Failed - Common IsValidCryptoAddress error")
 	}
+	b, err = IsValidCryptoAddress(
+		"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+		"bch",
+	)
+	if err != nil || !b {
+		t.Errorf("Test Failed - Common IsValidCryptoAddress error: %s", err)
+	}
+	b, err = IsValidCryptoAddress("rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", "XRP")
+	if err != nil || !b {
+		t.Errorf("Test Failed - Common IsValidCryptoAddress error: %s", err)
+	}
+	b, err = IsValidCryptoAddress("xPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", "xrp")
+	if err == nil && b {
+		t.Error("Test Failed - Common IsValidCryptoAddress error")
+	}
 	b, err = IsValidCryptoAddress(
 		"xxb794f5ea0ba39494ce839613fffba74279579268",
 		"ding",
@@ -197,6 +212,45 @@ func TestGetHMAC(t *testing.T) {
 
 }
 
+func TestGenerateTOTP(t *testing.T) {
+	t.Parallel()
+	// RFC 6238 SHA1 test vectors truncated to six digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	expected := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+	}
+	for ts, code := range expected {
+		actual, err := GenerateTOTP(secret, time.Unix(ts, 0))
+		if err != nil || actual != code {
+			t.Errorf("Test failed. Common GenerateTOTP error: Expected '%s'. Actual '%s'",
+				code, actual)
+		}
+	}
+
+	_, err := GenerateTOTP("not base32!", time.Now())
+	if err == nil {
+		t.Error("Test failed. Common GenerateTOTP expected invalid secret error")
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	t.Parallel()
+	secret := "gezd gnbv gy3t qojq gezd gnbv gy3t qojq"
+	if !VerifyTOTP(secret, "287082", time.Unix(59+totpStep, 0)) {
+		t.Error("Test failed. Common VerifyTOTP rejected code within drift")
+	}
+
+	if VerifyTOTP(secret, "287082", time.Unix(59+totpStep*3, 0)) {
+		t.Error("Test failed. Common VerifyTOTP accepted expired code")
+	}
+
+	if VerifyTOTP("not base32!", "287082", time.Unix(59, 0)) {
+		t.Error("Test failed. Common VerifyTOTP accepted invalid secret")
+	}
+}
+
 func TestSha1Tohex(t *testing.T) {
 	t.Parallel()
 	expectedResult := "fcfbfcd7d31d994ef660f6972399ab5d7a890149"
@@ -17,8 +17,12 @@ import (
 	"github.com/thrasher-/gocryptotrader/currency"
 	"github.com/thrasher-/gocryptotrader/currency/forexprovider"
 	"github.com/thrasher-/gocryptotrader/currency/forexprovider/base"
+	"github.com/thrasher-/gocryptotrader/currency/marketdata"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
 	"github.com/thrasher-/gocryptotrader/portfolio"
+	"github.com/thrasher-/gocryptotrader/publisher"
+	"github.com/thrasher-/gocryptotrader/scheduler"
 )
 
 // Constants declared here are filename strings and test strings
@@ -33,6 +37,28 @@ const (
 	configPairsLastUpdatedWarningThreshold = 30 // 30 days
 	configDefaultHTTPTimeout               = time.Duration(time.Second * 15)
 	configMaxAuthFailres                   = 3
+	configDefaultDepositWatcherDelay       = time.Minute
+	configDefaultOrderMaxDataAge           = time.Second * 5
+	configDefaultShutdownTimeout           = time.Second * 10
+	configDefaultStrategyShutdownTimeout   = time.Second * 5
+	configDefaultWarmCacheMaxAge           = time.Hour * 24
+	configDefaultNewListingsPollingDelay   = time.Minute * 5
+	configDefaultPublisherURL              = "nats://127.0.0.1:4222"
+	configDefaultPublisherTopicPrefix      = "gct"
+	configDefaultTickerConflationRate      = 1
+	configDefaultBookTickerMaxLag          = time.Second * 5
+	configDefaultBookTickerLevels          = 5
+	configDefaultTickerHistoryEntries      = 1000
+	configDefaultRebalanceCheckInterval    = time.Minute
+	configDefaultArbitrageCheckInterval    = time.Second
+	configDefaultArbitrageHedgeTimeout     = time.Second * 30
+	configDefaultArbitrageImbalance        = 0.25
+	configDefaultStrategyPluginInterval    = time.Second
+	configDefaultStrategyPluginTimeout     = time.Second * 5
+	configDefaultConsistencyCheckInterval  = time.Minute
+	configDefaultConsistencyDepth          = 10
+	configDefaultSharedRateLimitAddress    = "127.0.0.1:6379"
+	configDefaultSharedRateLimitNamespace  = "gct"
 )
 
 // Constants here hold some messages
@@ -53,8 +79,17 @@ const (
 	WarningWebserverCredentialValuesEmpty           = "WARNING -- Webserver support disabled due to empty Username/Password values."
 	WarningWebserverListenAddressInvalid            = "WARNING -- Webserver support disabled due to invalid listen address."
 	WarningWebserverRootWebFolderNotFound           = "WARNING -- Webserver support disabled due to missing web folder."
+	WarningWebserverWithdrawalTOTPSecretInvalid     = "WARNING -- Webserver support disabled due to invalid base32 withdrawal TOTP secret."
+	WarningWebserverUserInvalid                     = "WARNING -- Webserver support disabled due to user #%d having an empty or duplicate username, empty password or invalid role."
 	WarningExchangeAuthAPIDefaultOrEmptyValues      = "WARNING -- Exchange %s: Authenticated API support disabled due to default/empty APIKey/Secret/ClientID values."
 	WarningCurrencyExchangeProvider                 = "WARNING -- Currency exchange provider invalid valid. Reset to Fixer."
+	WarningDepositWatcherAddressInvalid             = "WARNING -- Deposit watcher address #%d disabled due to unsupported coin type or empty address."
+	WarningPairFilterInvalid                        = "WARNING -- Exchange %s: Pair filter disabled due to invalid rule. Error: %s"
+	WarningNewListingsFilterInvalid                 = "WARNING -- Exchange %s: New listing pairs will not be enabled due to invalid rule. Error: %s"
+	WarningFeeDiscountInvalid                       = "WARNING -- Exchange %s: Fee discount disabled due to empty token or rate outside of 0 to 1."
+	WarningChaosInvalid                             = "WARNING -- Exchange %s: Chaos mode disabled due to failure probability outside of 0 to 1."
+	WarningSchedulerJobInvalid                      = "WARNING -- Scheduled job %s disabled due to invalid schedule. Error: %s"
+	WarningPublisherBrokerUnsupported               = "WARNING -- Publisher disabled due to unsupported message broker %s."
 	WarningPairsLastUpdatedThresholdExceeded        = "WARNING -- Exchange %s: Last manual update of available currency pairs has exceeded %d days. Manual update required!"
 	APIURLNonDefaultMessage                         = "NON_DEFAULT_HTTP_LINK_TO_EXCHANGE_API"
 	WebsocketURLNonDefaultMessage                   = "NON_DEFAULT_HTTP_LINK_TO_WEBSOCKET_EXCHANGE_API"
@@ -77,6 +112,242 @@ type WebserverConfig struct {
 	WebsocketConnectionLimit     int    `json:"websocketConnectionLimit"`
 	WebsocketMaxAuthFailures     int    `json:"websocketMaxAuthFailures"`
 	WebsocketAllowInsecureOrigin bool   `json:"websocketAllowInsecureOrigin"`
+	WithdrawalTOTPSecret         string `json:"withdrawalTOTPSecret,omitempty"`
+
+	// Users enables multi tenant mode when set, requiring every management API
+	// request to authenticate as the admin or one of the users
+	Users []WebserverUserConfig `json:"users,omitempty"`
+}
+
+// Webserver user roles
+const (
+	WebserverRoleAdmin = "admin"
+	WebserverRoleUser  = "user"
+)
+
+// WebserverUserConfig holds a management API user in multi tenant mode.
+// Exchanges and Strategies are comma separated lists of the exchanges and
+// strategy IDs the user can view and trade, users with the admin role can
+// access all of them along with global operations
+type WebserverUserConfig struct {
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	Role       string `json:"role"`
+	Exchanges  string `json:"exchanges,omitempty"`
+	Strategies string `json:"strategies,omitempty"`
+}
+
+// DepositWatcherConfig holds the settings for the blockchain deposit watcher
+type DepositWatcherConfig struct {
+	Enabled    bool                       `json:"enabled"`
+	Verbose    bool                       `json:"verbose"`
+	CheckDelay time.Duration              `json:"checkDelay"`
+	Addresses  []portfolio.WatchedAddress `json:"addresses"`
+}
+
+// OrderManagerConfig holds the settings for order submission
+type OrderManagerConfig struct {
+	// MaxDataAge is the maximum age of the market data a trading decision was
+	// based on before the resulting order is rejected
+	MaxDataAge time.Duration `json:"maxDataAge"`
+	// ProfitLossReportInterval is how often the realised profit and loss of
+	// the current session is sent to the communication mediums, zero disables
+	// the report
+	ProfitLossReportInterval time.Duration `json:"profitLossReportInterval"`
+	// StrategyLimits holds the risk limits of each strategy by strategy ID
+	StrategyLimits map[string]orders.StrategyLimits `json:"strategyLimits,omitempty"`
+	// PairThrottle limits the order submissions per exchange pair
+	PairThrottle orders.ThrottleLimits `json:"pairThrottle"`
+	// StrategyThrottle limits the order submissions per strategy
+	StrategyThrottle orders.ThrottleLimits `json:"strategyThrottle"`
+	// AdoptUnknownOrders adopts open orders found on the exchanges at startup
+	// which weren't submitted by the bot into the order manager instead of
+	// alerting on them
+	AdoptUnknownOrders bool `json:"adoptUnknownOrders"`
+	// Slippage is the slippage model and latency applied to simulated fills,
+	// StrategySlippage overrides it per strategy ID and the exchange config
+	// slippage overrides it per exchange
+	Slippage         orders.SlippageConfig            `json:"slippage"`
+	StrategySlippage map[string]orders.SlippageConfig `json:"strategySlippage,omitempty"`
+	// StatusPolling polls the status of managed orders on exchanges without
+	// private websockets, more often for orders near the touch
+	StatusPolling orders.StatusPollConfig `json:"statusPolling"`
+	// StrategyShutdown holds the shutdown policy of each strategy by strategy
+	// ID, leaving, cancelling the open orders or flattening the positions of
+	// the strategy on shutdown or when its loss limit trips
+	StrategyShutdown map[string]string `json:"strategyShutdown,omitempty"`
+}
+
+// ShutdownConfig holds the settings for shutting down the bot
+type ShutdownConfig struct {
+	// Timeout is the maximum time allowed for the bot routines to stop before
+	// the remaining shutdown steps are run regardless
+	Timeout time.Duration `json:"timeout"`
+	// CancelOrdersOnExit cancels all resting orders on exchanges with
+	// authenticated API support before the websockets are closed
+	CancelOrdersOnExit bool `json:"cancelOrdersOnExit"`
+	// StrategyTimeout is the time allowed for the strategy shutdown policies
+	// to cancel orders and flatten positions, within Timeout on shutdown
+	StrategyTimeout time.Duration `json:"strategyTimeout"`
+}
+
+// SchedulerConfig holds the settings for the background job scheduler
+type SchedulerConfig struct {
+	Enabled bool                 `json:"enabled"`
+	Jobs    []SchedulerJobConfig `json:"jobs"`
+}
+
+// SchedulerJobConfig holds the settings of a scheduled job. Schedule is a cron
+// expression or descriptor such as @hourly or @every 30m, Exchanges is a comma
+// separated list which defaults to every enabled exchange and RateBudget is
+// the number of requests per minute the job may make on each exchange
+type SchedulerJobConfig struct {
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+	Schedule   string `json:"schedule"`
+	Exchanges  string `json:"exchanges,omitempty"`
+	RateBudget int    `json:"rateBudget,omitempty"`
+}
+
+// PublisherConfig holds the settings for publishing normalised ticker, trade
+// and orderbook delta messages to a message broker for external consumers
+type PublisherConfig struct {
+	Enabled     bool   `json:"enabled"`
+	Broker      string `json:"broker"`
+	URL         string `json:"url"`
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	TopicPrefix string `json:"topicPrefix"`
+}
+
+// TickerConflationConfig holds the settings for coalescing ticker updates to
+// the latest price of each exchange pair, delivering at most
+// MaxUpdatesPerSecond updates per pair to the websocket stream and publisher
+type TickerConflationConfig struct {
+	Enabled             bool    `json:"enabled"`
+	MaxUpdatesPerSecond float64 `json:"maxUpdatesPerSecond"`
+}
+
+// BookTickerConfig holds the settings for taking the bid and ask of tickers
+// from the live orderbook once the exchange's ticker lags it by more than
+// MaxLag. Book pressure is measured over the Levels nearest the touch
+type BookTickerConfig struct {
+	Enabled bool          `json:"enabled"`
+	MaxLag  time.Duration `json:"maxLag"`
+	Levels  int           `json:"levels"`
+}
+
+// TickerHistoryConfig holds the settings for keeping the last updates of each
+// ticker, up to MaxEntries updates and MaxAge old, either being unbounded when
+// zero, so indicators and events can measure short term price changes
+type TickerHistoryConfig struct {
+	Enabled    bool          `json:"enabled"`
+	MaxEntries int           `json:"maxEntries"`
+	MaxAge     time.Duration `json:"maxAge"`
+}
+
+// RebalanceConfig holds the settings for the portfolio rebalancing strategy.
+// Targets are the weights of each currency of the exchange holdings valued in
+// the valuation currency, summing to one. A rebalance is triggered when a
+// weight drifts from its target by DriftThreshold or every Interval, checked
+// every CheckInterval, and trades worth less than MinTradeValue are skipped.
+// DryRun reports the trades without submitting them
+type RebalanceConfig struct {
+	Enabled        bool               `json:"enabled"`
+	DryRun         bool               `json:"dryRun"`
+	Targets        map[string]float64 `json:"targets"`
+	DriftThreshold float64            `json:"driftThreshold"`
+	Interval       time.Duration      `json:"interval"`
+	CheckInterval  time.Duration      `json:"checkInterval"`
+	MinTradeValue  float64            `json:"minTradeValue"`
+}
+
+// ArbitrageConfig holds the settings for the two exchange arbitrage strategy,
+// trading Pair, formatted as BTC-USD, against the inventory held on both
+// Exchanges. Opportunities returning MinProfit after fees, a fraction of the
+// buy value, are traded up to MaxAmount at a time and checked every
+// CheckInterval. A leg left unhedged for HedgeTimeout halts trading. Every
+// RebalanceInterval the inventory of both currencies is evened out between the
+// exchanges once either holds more than RebalanceThreshold of the total away
+// from an even share. DryRun reports opportunities and transfers only
+type ArbitrageConfig struct {
+	Enabled            bool          `json:"enabled"`
+	DryRun             bool          `json:"dryRun"`
+	Exchanges          []string      `json:"exchanges"`
+	Pair               string        `json:"pair"`
+	MinProfit          float64       `json:"minProfit"`
+	MaxAmount          float64       `json:"maxAmount"`
+	HedgeTimeout       time.Duration `json:"hedgeTimeout"`
+	CheckInterval      time.Duration `json:"checkInterval"`
+	RebalanceInterval  time.Duration `json:"rebalanceInterval"`
+	RebalanceThreshold float64       `json:"rebalanceThreshold"`
+}
+
+// StrategyPluginConfig holds the settings of an external strategy, loaded from
+// the Go plugin file Plugin or run as the sidecar process Command with Args,
+// either but not both being set. Name is the strategy ID its orders are tagged
+// with. Ticker updates of its Pairs, formatted as BTC-USD, on its Exchanges
+// and the fills of its orders are delivered every Interval, and a sidecar
+// which doesn't respond within Timeout is stopped
+type StrategyPluginConfig struct {
+	Name      string            `json:"name"`
+	Enabled   bool              `json:"enabled"`
+	DryRun    bool              `json:"dryRun"`
+	Plugin    string            `json:"plugin,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Exchanges []string          `json:"exchanges"`
+	Pairs     []string          `json:"pairs"`
+	Settings  map[string]string `json:"settings,omitempty"`
+	Interval  time.Duration     `json:"interval"`
+	Timeout   time.Duration     `json:"timeout"`
+}
+
+// OrderbookConsistencyConfig holds the settings for monitoring the websocket
+// maintained orderbooks against REST snapshots fetched every Interval. The top
+// Depth levels of each side are compared and an alert is sent when more than
+// MaxMismatchedLevels levels mismatch or a price or size differs by more than
+// the MaxPriceDiff or MaxSizeDiff fraction, each check disabled when zero.
+// After ResyncAfter consecutive breaches the book is resynced from the
+// snapshot, or the websocket is reconnected when Reconnect is set
+type OrderbookConsistencyConfig struct {
+	Enabled             bool          `json:"enabled"`
+	Interval            time.Duration `json:"interval"`
+	Depth               int           `json:"depth"`
+	MaxMismatchedLevels int           `json:"maxMismatchedLevels"`
+	MaxPriceDiff        float64       `json:"maxPriceDiff"`
+	MaxSizeDiff         float64       `json:"maxSizeDiff"`
+	ResyncAfter         int           `json:"resyncAfter"`
+	Reconnect           bool          `json:"reconnect"`
+}
+
+// SharedRateLimitConfig holds the settings of the Redis server rate limit
+// buckets are shared through, so bot processes using the same API keys stay
+// within one exchange quota. Buckets are keyed under Namespace, and Timeout
+// bounds each call to Redis after which requests fall back to the local rate
+// limiter
+type SharedRateLimitConfig struct {
+	Enabled   bool          `json:"enabled"`
+	Address   string        `json:"address"`
+	Password  string        `json:"password,omitempty"`
+	DB        int           `json:"db"`
+	Namespace string        `json:"namespace"`
+	Timeout   time.Duration `json:"timeout"`
+}
+
+// AssetMetadataConfig overrides the deposit and withdrawal details of a
+// currency on a chain of an exchange, such as USDT on ERC20 or TRC20. Fields
+// left unset keep the exchange's defaults and Default marks the chain used
+// when none is requested
+type AssetMetadataConfig struct {
+	Currency             string        `json:"currency"`
+	Chain                string        `json:"chain"`
+	Default              bool          `json:"default,omitempty"`
+	MinWithdrawal        float64       `json:"minWithdrawal,omitempty"`
+	WithdrawalFee        float64       `json:"withdrawalFee,omitempty"`
+	DepositConfirmations int64         `json:"depositConfirmations,omitempty"`
+	WithdrawalDisabled   bool          `json:"withdrawalDisabled,omitempty"`
+	ProcessingTime       time.Duration `json:"processingTime,omitempty"`
 }
 
 // Post holds the bot configuration data
@@ -96,15 +367,29 @@ type CurrencyPairFormatConfig struct {
 // prestart management of Portfolio, Communications, Webserver and Enabled
 // Exchanges
 type Config struct {
-	Name              string               `json:"name"`
-	EncryptConfig     int                  `json:"encryptConfig"`
-	GlobalHTTPTimeout time.Duration        `json:"globalHTTPTimeout"`
-	Currency          CurrencyConfig       `json:"currencyConfig"`
-	Communications    CommunicationsConfig `json:"communications"`
-	Portfolio         portfolio.Base       `json:"portfolioAddresses"`
-	Webserver         WebserverConfig      `json:"webserver"`
-	Exchanges         []ExchangeConfig     `json:"exchanges"`
-	BankAccounts      []BankAccount        `json:"bankAccounts"`
+	Name              string                     `json:"name"`
+	EncryptConfig     int                        `json:"encryptConfig"`
+	GlobalHTTPTimeout time.Duration              `json:"globalHTTPTimeout"`
+	Currency          CurrencyConfig             `json:"currencyConfig"`
+	Communications    CommunicationsConfig       `json:"communications"`
+	Portfolio         portfolio.Base             `json:"portfolioAddresses"`
+	DepositWatcher    DepositWatcherConfig       `json:"depositWatcher"`
+	OrderManager      OrderManagerConfig         `json:"orderManager"`
+	Shutdown          ShutdownConfig             `json:"shutdown"`
+	WarmCache         WarmCacheConfig            `json:"warmCache"`
+	Scheduler         SchedulerConfig            `json:"scheduler"`
+	Publisher         PublisherConfig            `json:"publisher"`
+	TickerConflation  TickerConflationConfig     `json:"tickerConflation"`
+	BookTicker        BookTickerConfig           `json:"bookTicker"`
+	TickerHistory     TickerHistoryConfig        `json:"tickerHistory"`
+	Rebalance         RebalanceConfig            `json:"rebalance"`
+	Arbitrage         ArbitrageConfig            `json:"arbitrage"`
+	StrategyPlugins   []StrategyPluginConfig     `json:"strategyPlugins,omitempty"`
+	Consistency       OrderbookConsistencyConfig `json:"orderbookConsistency"`
+	SharedRateLimit   SharedRateLimitConfig      `json:"sharedRateLimit"`
+	Webserver         WebserverConfig            `json:"webserver"`
+	Exchanges         []ExchangeConfig           `json:"exchanges"`
+	BankAccounts      []BankAccount              `json:"bankAccounts"`
 
 	// Deprecated config settings, will be removed at a future date
 	CurrencyPairFormat  *CurrencyPairFormatConfig `json:"currencyPairFormat,omitempty"`
@@ -115,33 +400,193 @@ type Config struct {
 
 // ExchangeConfig holds all the information needed for each enabled Exchange.
 type ExchangeConfig struct {
-	Name                      string                    `json:"name"`
-	Enabled                   bool                      `json:"enabled"`
-	Verbose                   bool                      `json:"verbose"`
-	Websocket                 bool                      `json:"websocket"`
-	UseSandbox                bool                      `json:"useSandbox"`
-	RESTPollingDelay          time.Duration             `json:"restPollingDelay"`
-	HTTPTimeout               time.Duration             `json:"httpTimeout"`
-	HTTPUserAgent             string                    `json:"httpUserAgent"`
-	AuthenticatedAPISupport   bool                      `json:"authenticatedApiSupport"`
-	APIKey                    string                    `json:"apiKey"`
-	APISecret                 string                    `json:"apiSecret"`
-	APIAuthPEMKeySupport      bool                      `json:"apiAuthPemKeySupport,omitempty"`
-	APIAuthPEMKey             string                    `json:"apiAuthPemKey,omitempty"`
-	APIURL                    string                    `json:"apiUrl"`
-	APIURLSecondary           string                    `json:"apiUrlSecondary"`
-	ProxyAddress              string                    `json:"proxyAddress"`
-	WebsocketURL              string                    `json:"websocketUrl"`
-	ClientID                  string                    `json:"clientId,omitempty"`
-	AvailablePairs            string                    `json:"availablePairs"`
-	EnabledPairs              string                    `json:"enabledPairs"`
-	BaseCurrencies            string                    `json:"baseCurrencies"`
-	AssetTypes                string                    `json:"assetTypes"`
-	SupportsAutoPairUpdates   bool                      `json:"supportsAutoPairUpdates"`
-	PairsLastUpdated          int64                     `json:"pairsLastUpdated,omitempty"`
-	ConfigCurrencyPairFormat  *CurrencyPairFormatConfig `json:"configCurrencyPairFormat"`
-	RequestCurrencyPairFormat *CurrencyPairFormatConfig `json:"requestCurrencyPairFormat"`
-	BankAccounts              []BankAccount             `json:"bankAccounts"`
+	Name                      string                       `json:"name"`
+	Enabled                   bool                         `json:"enabled"`
+	Verbose                   bool                         `json:"verbose"`
+	Websocket                 bool                         `json:"websocket"`
+	UseSandbox                bool                         `json:"useSandbox"`
+	RESTPollingDelay          time.Duration                `json:"restPollingDelay"`
+	HTTPTimeout               time.Duration                `json:"httpTimeout"`
+	HTTPUserAgent             string                       `json:"httpUserAgent"`
+	AuthenticatedAPISupport   bool                         `json:"authenticatedApiSupport"`
+	APIKey                    string                       `json:"apiKey"`
+	APISecret                 string                       `json:"apiSecret"`
+	APIAuthPEMKeySupport      bool                         `json:"apiAuthPemKeySupport,omitempty"`
+	APIAuthPEMKey             string                       `json:"apiAuthPemKey,omitempty"`
+	APIURL                    string                       `json:"apiUrl"`
+	APIURLSecondary           string                       `json:"apiUrlSecondary"`
+	ProxyAddress              string                       `json:"proxyAddress"`
+	HTTPTransport             *HTTPTransportConfig         `json:"httpTransport,omitempty"`
+	TLS                       *TLSConfig                   `json:"tls,omitempty"`
+	WebsocketURL              string                       `json:"websocketUrl"`
+	WebsocketSubscriptions    *WebsocketSubscriptionConfig `json:"websocketSubscriptions,omitempty"`
+	WebsocketCompression      *WebsocketCompressionConfig  `json:"websocketCompression,omitempty"`
+	WebsocketKeepalive        *WebsocketKeepaliveConfig    `json:"websocketKeepalive,omitempty"`
+	WebsocketConnections      *WebsocketConnectionConfig   `json:"websocketConnections,omitempty"`
+	OrderbookDepth            *OrderbookDepthConfig        `json:"orderbookDepth,omitempty"`
+	CandleIntervals           string                       `json:"candleIntervals,omitempty"`
+	OrderbookRecordPath       string                       `json:"orderbookRecordPath,omitempty"`
+	ClientID                  string                       `json:"clientId,omitempty"`
+	BrokerID                  string                       `json:"brokerID,omitempty"`
+	AssetMetadata             []AssetMetadataConfig        `json:"assetMetadata,omitempty"`
+	Slippage                  *orders.SlippageConfig       `json:"slippage,omitempty"`
+	MaintenanceWindows        []orders.MaintenanceWindow   `json:"maintenanceWindows,omitempty"`
+	AvailablePairs            string                       `json:"availablePairs"`
+	EnabledPairs              string                       `json:"enabledPairs"`
+	BaseCurrencies            string                       `json:"baseCurrencies"`
+	AssetTypes                string                       `json:"assetTypes"`
+	SupportsAutoPairUpdates   bool                         `json:"supportsAutoPairUpdates"`
+	PairFilter                *PairFilterConfig            `json:"pairFilter,omitempty"`
+	NewListings               *NewListingsConfig           `json:"newListings,omitempty"`
+	FeeDiscount               *FeeDiscountConfig           `json:"feeDiscount,omitempty"`
+	Virtual                   *VirtualExchangeConfig       `json:"virtual,omitempty"`
+	Chaos                     *ChaosConfig                 `json:"chaos,omitempty"`
+	PairsLastUpdated          int64                        `json:"pairsLastUpdated,omitempty"`
+	ConfigCurrencyPairFormat  *CurrencyPairFormatConfig    `json:"configCurrencyPairFormat"`
+	RequestCurrencyPairFormat *CurrencyPairFormatConfig    `json:"requestCurrencyPairFormat"`
+	BankAccounts              []BankAccount                `json:"bankAccounts"`
+}
+
+// NewListingsConfig holds the settings of the poller which watches an exchange
+// for newly listed currency pairs. New pairs are added to the available pairs
+// and those matching the Enable filter are also enabled, no filter enables
+// none of them
+type NewListingsConfig struct {
+	Enabled      bool              `json:"enabled"`
+	PollingDelay time.Duration     `json:"pollingDelay,omitempty"`
+	Enable       *PairFilterConfig `json:"enable,omitempty"`
+}
+
+// FeeDiscountConfig holds the discount on trading fees paid in an exchange
+// token, Rate is the fraction taken off the fee e.g. 0.25 for BNB. Enabled is
+// whether discount mode is enabled on the account, exchanges which can detect
+// it from their API override it
+type FeeDiscountConfig struct {
+	Token   string  `json:"token"`
+	Rate    float64 `json:"rate"`
+	Enabled bool    `json:"enabled"`
+}
+
+// ChaosConfig holds the probabilities, from 0 to 1, of the artificial failures
+// injected into an exchange's REST requests and websocket reads for resilience
+// testing. Requests time out at TimeoutRate and are answered with a 503 error
+// page at ServerErrorRate, websocket connections are dropped at DisconnectRate
+// and messages corrupted at CorruptFrameRate per message read
+type ChaosConfig struct {
+	Enabled          bool    `json:"enabled"`
+	TimeoutRate      float64 `json:"timeoutRate,omitempty"`
+	ServerErrorRate  float64 `json:"serverErrorRate,omitempty"`
+	DisconnectRate   float64 `json:"disconnectRate,omitempty"`
+	CorruptFrameRate float64 `json:"corruptFrameRate,omitempty"`
+}
+
+// IsValid returns whether each chaos probability is within 0 to 1
+func (c *ChaosConfig) IsValid() bool {
+	for _, rate := range []float64{c.TimeoutRate, c.ServerErrorRate,
+		c.DisconnectRate, c.CorruptFrameRate} {
+		if rate < 0 || rate > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// VirtualExchangeConfig holds the starting state of the virtual exchange, an
+// in process matching engine for running the bot without external
+// dependencies. Balances are the account balance of each currency and
+// Liquidity the orders resting on the orderbook at startup, which belong to
+// the exchange rather than the account. Fees are fractions of the trade value
+type VirtualExchangeConfig struct {
+	Balances  map[string]float64       `json:"balances,omitempty"`
+	Liquidity []VirtualLiquidityConfig `json:"liquidity,omitempty"`
+	MakerFee  float64                  `json:"makerFee,omitempty"`
+	TakerFee  float64                  `json:"takerFee,omitempty"`
+}
+
+// VirtualLiquidityConfig holds an order seeded on the virtual exchange
+// orderbook, Pair is config formatted and Side is buy or sell
+type VirtualLiquidityConfig struct {
+	Pair   string  `json:"pair"`
+	Side   string  `json:"side"`
+	Price  float64 `json:"price"`
+	Amount float64 `json:"amount"`
+}
+
+// WebsocketSubscriptionConfig holds the websocket channels and pairs an
+// exchange subscribes to, applied on every connect and reconnect. Empty
+// channels and pairs default to all supported channels and the enabled pairs
+type WebsocketSubscriptionConfig struct {
+	Channels       []string `json:"channels,omitempty"`
+	Pairs          string   `json:"pairs,omitempty"`
+	OrderbookDepth int      `json:"orderbookDepth,omitempty"`
+	DisableTrades  bool     `json:"disableTrades,omitempty"`
+}
+
+// WebsocketConnectionConfig holds how an exchange splits its websocket
+// streams across connections. Each pair group is a comma separated list of
+// pairs served by its own connection, remaining enabled pairs are split into
+// connections of PairsPerConnection pairs, zero serving them all on one
+type WebsocketConnectionConfig struct {
+	PairGroups         []string `json:"pairGroups,omitempty"`
+	PairsPerConnection int      `json:"pairsPerConnection,omitempty"`
+}
+
+// WebsocketCompressionConfig holds the websocket frame compression used by an
+// exchange. Method is one of none, gzip, deflate or auto, defaulting to the
+// exchange's own compression. PermessageDeflate negotiates the permessage
+// deflate extension when connecting
+type WebsocketCompressionConfig struct {
+	Method            string `json:"method,omitempty"`
+	PermessageDeflate bool   `json:"permessageDeflate,omitempty"`
+}
+
+// WebsocketKeepaliveConfig holds how an exchange websocket connection is kept
+// alive, overriding the exchange's default. Method is one of none, ping, text
+// or pong. The ping and text methods send Payload each Interval as a protocol
+// ping frame or a text message, the pong method answers server text pings
+// containing Payload by replacing it with Response
+type WebsocketKeepaliveConfig struct {
+	Method   string        `json:"method,omitempty"`
+	Interval time.Duration `json:"interval,omitempty"`
+	Payload  string        `json:"payload,omitempty"`
+	Response string        `json:"response,omitempty"`
+}
+
+// OrderbookDepthConfig holds the number of orderbook levels kept on each side
+// for REST polling and websocket subscriptions, e.g. 5, 25 or 0 for the full
+// orderbook. Pairs maps config formatted pairs to their depth, overriding
+// Default
+type OrderbookDepthConfig struct {
+	Default int            `json:"default,omitempty"`
+	Pairs   map[string]int `json:"pairs,omitempty"`
+}
+
+// HTTPTransportConfig holds optional HTTP transport tuning for an exchange.
+// Zero values leave the Go defaults in place. CacheResponses caches public
+// GET responses, revalidating them by ETag or Last-Modified and serving them
+// without a request for MicroCacheTTL
+type HTTPTransportConfig struct {
+	MaxIdleConns        int           `json:"maxIdleConns"`
+	MaxIdleConnsPerHost int           `json:"maxIdleConnsPerHost"`
+	IdleConnTimeout     time.Duration `json:"idleConnTimeout"`
+	KeepAlive           time.Duration `json:"keepAlive"`
+	TLSMinVersion       string        `json:"tlsMinVersion"`
+	DisableHTTP2        bool          `json:"disableHttp2"`
+	RecordTimings       bool          `json:"recordTimings"`
+	CacheResponses      bool          `json:"cacheResponses"`
+	MicroCacheTTL       time.Duration `json:"microCacheTTL"`
+}
+
+// TLSConfig holds the TLS settings of the REST and websocket connections to an
+// exchange, such as when its traffic is routed through a proxy requiring
+// client certificates. Files are PEM encoded, CAFile adds its certificate
+// authorities to the system roots and ServerName overrides the server name
+// sent via SNI and verified against the server certificate
+type TLSConfig struct {
+	ClientCertFile string `json:"clientCertFile,omitempty"`
+	ClientKeyFile  string `json:"clientKeyFile,omitempty"`
+	CAFile         string `json:"caFile,omitempty"`
+	ServerName     string `json:"serverName,omitempty"`
 }
 
 // BankAccount holds differing bank account details by supported funding
@@ -172,6 +617,17 @@ type CurrencyConfig struct {
 	Cryptocurrencies    string                    `json:"cryptocurrencies"`
 	CurrencyPairFormat  *CurrencyPairFormatConfig `json:"currencyPairFormat"`
 	FiatDisplayCurrency string                    `json:"fiatDisplayCurrency"`
+	ValuationCurrency   string                    `json:"valuationCurrency,omitempty"`
+	MarketDataProviders []marketdata.Settings     `json:"marketDataProviders,omitempty"`
+}
+
+// WarmCacheConfig holds the settings for persisting the last known tickers,
+// orderbooks and symbol rules on shutdown and restoring them on start
+type WarmCacheConfig struct {
+	Enabled bool `json:"enabled"`
+	// MaxAge is the maximum age of a persisted ticker or orderbook for it to
+	// be restored
+	MaxAge time.Duration `json:"maxAge"`
 }
 
 // CommunicationsConfig holds all the information needed for each
@@ -751,6 +1207,36 @@ func (c *Config) CheckExchangeConfigValues() error {
 				c.Exchanges[i].HTTPTimeout = configDefaultHTTPTimeout
 			}
 
+			if exch.PairFilter != nil {
+				if err := exch.PairFilter.Validate(); err != nil {
+					log.Printf(WarningPairFilterInvalid, exch.Name, err)
+					c.Exchanges[i].PairFilter = nil
+				}
+			}
+
+			if exch.FeeDiscount != nil &&
+				(exch.FeeDiscount.Token == "" || exch.FeeDiscount.Rate <= 0 || exch.FeeDiscount.Rate >= 1) {
+				log.Printf(WarningFeeDiscountInvalid, exch.Name)
+				c.Exchanges[i].FeeDiscount = nil
+			}
+
+			if exch.Chaos != nil && !exch.Chaos.IsValid() {
+				log.Printf(WarningChaosInvalid, exch.Name)
+				c.Exchanges[i].Chaos = nil
+			}
+
+			if exch.NewListings != nil {
+				if exch.NewListings.PollingDelay <= 0 {
+					c.Exchanges[i].NewListings.PollingDelay = configDefaultNewListingsPollingDelay
+				}
+				if exch.NewListings.Enable != nil {
+					if err := exch.NewListings.Enable.Validate(); err != nil {
+						log.Printf(WarningNewListingsFilterInvalid, exch.Name, err)
+						c.Exchanges[i].NewListings.Enable = nil
+					}
+				}
+			}
+
 			err := c.CheckPairConsistency(exch.Name)
 			if err != nil {
 				log.Printf("Exchange %s: CheckPairConsistency error: %s", exch.Name, err)
@@ -822,9 +1308,436 @@ func (c *Config) CheckWebserverConfigValues() error {
 		c.Webserver.WebsocketMaxAuthFailures = 3
 	}
 
+	if c.Webserver.WithdrawalTOTPSecret != "" {
+		if _, err := common.GenerateTOTP(c.Webserver.WithdrawalTOTPSecret, time.Now()); err != nil {
+			return errors.New(WarningWebserverWithdrawalTOTPSecretInvalid)
+		}
+	}
+
+	usernames := []string{c.Webserver.AdminUsername}
+	for x := range c.Webserver.Users {
+		user := &c.Webserver.Users[x]
+		if user.Role == "" {
+			user.Role = WebserverRoleUser
+		}
+
+		if user.Username == "" || user.Password == "" ||
+			common.StringDataCompare(usernames, user.Username) ||
+			(user.Role != WebserverRoleAdmin && user.Role != WebserverRoleUser) {
+			return fmt.Errorf(WarningWebserverUserInvalid, x)
+		}
+		usernames = append(usernames, user.Username)
+	}
+
 	return nil
 }
 
+// CheckDepositWatcherConfigValues checks the deposit watcher settings, removing
+// any addresses which cannot be watched
+func (c *Config) CheckDepositWatcherConfigValues() {
+	if c.DepositWatcher.CheckDelay <= 0 {
+		c.DepositWatcher.CheckDelay = configDefaultDepositWatcherDelay
+	}
+
+	var addresses []portfolio.WatchedAddress
+	for i, addr := range c.DepositWatcher.Addresses {
+		if addr.Address == "" || !portfolio.IsDepositWatcherSupported(addr.CoinType) {
+			log.Printf(WarningDepositWatcherAddressInvalid, i)
+			continue
+		}
+		addresses = append(addresses, addr)
+	}
+	c.DepositWatcher.Addresses = addresses
+
+	if c.DepositWatcher.Enabled && len(c.DepositWatcher.Addresses) == 0 {
+		log.Println("Deposit watcher enabled but no addresses are set, disabling.")
+		c.DepositWatcher.Enabled = false
+	}
+}
+
+// CheckOrderManagerConfigValues checks the order manager settings
+func (c *Config) CheckOrderManagerConfigValues() {
+	if c.OrderManager.MaxDataAge <= 0 {
+		log.Printf("Order manager max data age not set, defaulting to %v.",
+			configDefaultOrderMaxDataAge)
+		c.OrderManager.MaxDataAge = configDefaultOrderMaxDataAge
+	}
+
+	if c.OrderManager.ProfitLossReportInterval < 0 {
+		c.OrderManager.ProfitLossReportInterval = 0
+	}
+
+	for strategyID, limits := range c.OrderManager.StrategyLimits {
+		if limits.MaxOrderValue < 0 || limits.MaxPosition < 0 || limits.MaxLoss < 0 {
+			log.Printf("Strategy %s has negative risk limits, removing them.",
+				strategyID)
+			delete(c.OrderManager.StrategyLimits, strategyID)
+		}
+	}
+
+	for _, limits := range []*orders.ThrottleLimits{&c.OrderManager.PairThrottle, &c.OrderManager.StrategyThrottle} {
+		if limits.PerSecond < 0 {
+			limits.PerSecond = 0
+		}
+		if limits.PerMinute < 0 {
+			limits.PerMinute = 0
+		}
+	}
+
+	if err := c.OrderManager.Slippage.Validate(); err != nil {
+		log.Printf("Order manager slippage invalid, disabling it. Err: %s", err)
+		c.OrderManager.Slippage = orders.SlippageConfig{}
+	}
+
+	for strategyID, slippage := range c.OrderManager.StrategySlippage {
+		if err := slippage.Validate(); err != nil {
+			log.Printf("Strategy %s slippage invalid, removing it. Err: %s",
+				strategyID, err)
+			delete(c.OrderManager.StrategySlippage, strategyID)
+		}
+	}
+
+	for strategyID, policy := range c.OrderManager.StrategyShutdown {
+		if _, err := orders.ParseShutdownPolicy(policy); err != nil {
+			log.Printf("Strategy %s shutdown policy invalid, removing it. Err: %s",
+				strategyID, err)
+			delete(c.OrderManager.StrategyShutdown, strategyID)
+		}
+	}
+
+	for i := range c.Exchanges {
+		if c.Exchanges[i].Slippage == nil {
+			continue
+		}
+		if err := c.Exchanges[i].Slippage.Validate(); err != nil {
+			log.Printf("Exchange %s slippage invalid, removing it. Err: %s",
+				c.Exchanges[i].Name, err)
+			c.Exchanges[i].Slippage = nil
+		}
+	}
+
+	for i := range c.Exchanges {
+		var windows []orders.MaintenanceWindow
+		for _, w := range c.Exchanges[i].MaintenanceWindows {
+			if err := w.Validate(); err != nil {
+				log.Printf("Exchange %s maintenance window %s invalid, removing it. Err: %s",
+					c.Exchanges[i].Name, w.Name, err)
+				continue
+			}
+			windows = append(windows, w)
+		}
+		c.Exchanges[i].MaintenanceWindows = windows
+	}
+}
+
+// CheckShutdownConfigValues checks the shutdown settings
+func (c *Config) CheckShutdownConfigValues() {
+	if c.Shutdown.Timeout <= 0 {
+		log.Printf("Shutdown timeout not set, defaulting to %v.",
+			configDefaultShutdownTimeout)
+		c.Shutdown.Timeout = configDefaultShutdownTimeout
+	}
+
+	if c.Shutdown.StrategyTimeout <= 0 || c.Shutdown.StrategyTimeout > c.Shutdown.Timeout {
+		timeout := configDefaultStrategyShutdownTimeout
+		if timeout > c.Shutdown.Timeout {
+			timeout = c.Shutdown.Timeout
+		}
+		log.Printf("Strategy shutdown timeout not set or above the shutdown timeout, defaulting to %v.",
+			timeout)
+		c.Shutdown.StrategyTimeout = timeout
+	}
+}
+
+// CheckWarmCacheConfigValues checks the warm cache settings
+func (c *Config) CheckWarmCacheConfigValues() {
+	if c.WarmCache.Enabled && c.WarmCache.MaxAge <= 0 {
+		log.Printf("Warm cache max age not set, defaulting to %v.",
+			configDefaultWarmCacheMaxAge)
+		c.WarmCache.MaxAge = configDefaultWarmCacheMaxAge
+	}
+}
+
+// CheckSchedulerConfigValues checks the scheduled job settings, disabling
+// jobs with an invalid schedule
+func (c *Config) CheckSchedulerConfigValues() {
+	for x := range c.Scheduler.Jobs {
+		job := &c.Scheduler.Jobs[x]
+		if !job.Enabled {
+			continue
+		}
+
+		_, err := scheduler.ParseSchedule(job.Schedule)
+		if err != nil {
+			log.Printf(WarningSchedulerJobInvalid, job.Name, err)
+			job.Enabled = false
+			continue
+		}
+
+		if job.RateBudget < 0 {
+			job.RateBudget = 0
+		}
+	}
+}
+
+// CheckPublisherConfigValues checks the message broker publisher settings,
+// disabling the publisher for an unsupported broker
+func (c *Config) CheckPublisherConfigValues() {
+	if !c.Publisher.Enabled {
+		return
+	}
+
+	if c.Publisher.Broker == "" {
+		c.Publisher.Broker = publisher.BrokerNATS
+	}
+
+	if !publisher.IsSupportedBroker(c.Publisher.Broker) {
+		log.Printf(WarningPublisherBrokerUnsupported, c.Publisher.Broker)
+		c.Publisher.Enabled = false
+		return
+	}
+
+	if c.Publisher.URL == "" {
+		log.Printf("Publisher URL not set, defaulting to %s.", configDefaultPublisherURL)
+		c.Publisher.URL = configDefaultPublisherURL
+	}
+
+	if c.Publisher.TopicPrefix == "" {
+		c.Publisher.TopicPrefix = configDefaultPublisherTopicPrefix
+	}
+}
+
+// CheckTickerConflationConfigValues checks the ticker conflation settings,
+// defaulting an unset update rate
+func (c *Config) CheckTickerConflationConfigValues() {
+	if !c.TickerConflation.Enabled {
+		return
+	}
+
+	if c.TickerConflation.MaxUpdatesPerSecond <= 0 {
+		log.Printf("Ticker conflation max updates per second not set, defaulting to %v.",
+			configDefaultTickerConflationRate)
+		c.TickerConflation.MaxUpdatesPerSecond = configDefaultTickerConflationRate
+	}
+}
+
+// CheckBookTickerConfigValues checks the orderbook ticker settings,
+// defaulting an unset max lag and level count
+func (c *Config) CheckBookTickerConfigValues() {
+	if !c.BookTicker.Enabled {
+		return
+	}
+
+	if c.BookTicker.MaxLag <= 0 {
+		log.Printf("Book ticker max lag not set, defaulting to %v.",
+			configDefaultBookTickerMaxLag)
+		c.BookTicker.MaxLag = configDefaultBookTickerMaxLag
+	}
+
+	if c.BookTicker.Levels <= 0 {
+		log.Printf("Book ticker levels not set, defaulting to %d.",
+			configDefaultBookTickerLevels)
+		c.BookTicker.Levels = configDefaultBookTickerLevels
+	}
+}
+
+// CheckTickerHistoryConfigValues checks the ticker history settings,
+// defaulting the max entries when neither they nor the max age are set
+func (c *Config) CheckTickerHistoryConfigValues() {
+	if !c.TickerHistory.Enabled {
+		return
+	}
+
+	if c.TickerHistory.MaxEntries < 0 {
+		c.TickerHistory.MaxEntries = 0
+	}
+
+	if c.TickerHistory.MaxAge < 0 {
+		c.TickerHistory.MaxAge = 0
+	}
+
+	if c.TickerHistory.MaxEntries == 0 && c.TickerHistory.MaxAge == 0 {
+		log.Printf("Ticker history max entries and max age not set, defaulting to %d entries.",
+			configDefaultTickerHistoryEntries)
+		c.TickerHistory.MaxEntries = configDefaultTickerHistoryEntries
+	}
+}
+
+// CheckArbitrageConfigValues checks the arbitrage strategy settings, disabling
+// it when it doesn't have two exchanges, a pair and a max amount and
+// defaulting an unset check interval, hedge timeout and rebalance threshold
+func (c *Config) CheckArbitrageConfigValues() {
+	if !c.Arbitrage.Enabled {
+		return
+	}
+
+	if len(c.Arbitrage.Exchanges) != 2 || len(common.SplitStrings(c.Arbitrage.Pair, "-")) != 2 {
+		log.Println("Arbitrage requires two exchanges and a pair formatted as BTC-USD, disabling arbitrage.")
+		c.Arbitrage.Enabled = false
+		return
+	}
+
+	_, err := orders.NewArbitrageExecutor([2]string{c.Arbitrage.Exchanges[0], c.Arbitrage.Exchanges[1]},
+		pair.NewCurrencyPairDelimiter(c.Arbitrage.Pair, "-"), c.Arbitrage.MinProfit,
+		c.Arbitrage.MaxAmount, c.Arbitrage.HedgeTimeout)
+	if err != nil {
+		log.Printf("Arbitrage settings invalid, disabling arbitrage. Err: %s", err)
+		c.Arbitrage.Enabled = false
+		return
+	}
+
+	if c.Arbitrage.CheckInterval <= 0 {
+		log.Printf("Arbitrage check interval not set, defaulting to %v.",
+			configDefaultArbitrageCheckInterval)
+		c.Arbitrage.CheckInterval = configDefaultArbitrageCheckInterval
+	}
+
+	if c.Arbitrage.HedgeTimeout <= 0 {
+		log.Printf("Arbitrage hedge timeout not set, defaulting to %v.",
+			configDefaultArbitrageHedgeTimeout)
+		c.Arbitrage.HedgeTimeout = configDefaultArbitrageHedgeTimeout
+	}
+
+	if c.Arbitrage.RebalanceInterval > 0 && c.Arbitrage.RebalanceThreshold <= 0 {
+		log.Printf("Arbitrage rebalance threshold not set, defaulting to %v.",
+			configDefaultArbitrageImbalance)
+		c.Arbitrage.RebalanceThreshold = configDefaultArbitrageImbalance
+	}
+}
+
+// CheckStrategyPluginConfigValues checks the external strategy settings,
+// disabling strategies without a unique name, exchanges, pairs formatted as
+// BTC-USD or exactly one of a plugin and command and defaulting an unset
+// interval and timeout
+func (c *Config) CheckStrategyPluginConfigValues() {
+	names := make(map[string]bool)
+	for i := range c.StrategyPlugins {
+		s := &c.StrategyPlugins[i]
+		if !s.Enabled {
+			continue
+		}
+
+		if s.Name == "" || names[common.StringToLower(s.Name)] {
+			log.Printf("Strategy plugin %d requires a unique name, disabling strategy.", i)
+			s.Enabled = false
+			continue
+		}
+		names[common.StringToLower(s.Name)] = true
+
+		if (s.Plugin == "") == (s.Command == "") {
+			log.Printf("Strategy %s requires either a plugin or a command, disabling strategy.", s.Name)
+			s.Enabled = false
+			continue
+		}
+
+		valid := len(s.Exchanges) > 0 && len(s.Pairs) > 0
+		for _, p := range s.Pairs {
+			if len(common.SplitStrings(p, "-")) != 2 {
+				valid = false
+			}
+		}
+		if !valid {
+			log.Printf("Strategy %s requires exchanges and pairs formatted as BTC-USD, disabling strategy.", s.Name)
+			s.Enabled = false
+			continue
+		}
+
+		if s.Interval <= 0 {
+			log.Printf("Strategy %s interval not set, defaulting to %v.",
+				s.Name, configDefaultStrategyPluginInterval)
+			s.Interval = configDefaultStrategyPluginInterval
+		}
+
+		if s.Timeout <= 0 {
+			log.Printf("Strategy %s timeout not set, defaulting to %v.",
+				s.Name, configDefaultStrategyPluginTimeout)
+			s.Timeout = configDefaultStrategyPluginTimeout
+		}
+	}
+}
+
+// CheckRebalanceConfigValues checks the rebalancing strategy settings,
+// disabling it when its targets are invalid or it has no trigger and
+// defaulting an unset check interval
+func (c *Config) CheckRebalanceConfigValues() {
+	if !c.Rebalance.Enabled {
+		return
+	}
+
+	_, err := portfolio.NewRebalancer(c.Rebalance.Targets, c.Rebalance.DriftThreshold,
+		c.Rebalance.Interval, c.Rebalance.MinTradeValue)
+	if err != nil {
+		log.Printf("Rebalance targets invalid, disabling rebalancing. Err: %s", err)
+		c.Rebalance.Enabled = false
+		return
+	}
+
+	if c.Rebalance.DriftThreshold <= 0 && c.Rebalance.Interval <= 0 {
+		log.Println("Rebalance drift threshold and interval not set, disabling rebalancing.")
+		c.Rebalance.Enabled = false
+		return
+	}
+
+	if c.Rebalance.CheckInterval <= 0 {
+		log.Printf("Rebalance check interval not set, defaulting to %v.",
+			configDefaultRebalanceCheckInterval)
+		c.Rebalance.CheckInterval = configDefaultRebalanceCheckInterval
+	}
+}
+
+// CheckOrderbookConsistencyConfigValues checks the orderbook consistency
+// monitor settings, disabling it when no threshold is set and defaulting an
+// unset interval and depth
+func (c *Config) CheckOrderbookConsistencyConfigValues() {
+	if !c.Consistency.Enabled {
+		return
+	}
+
+	if c.Consistency.MaxMismatchedLevels <= 0 && c.Consistency.MaxPriceDiff <= 0 &&
+		c.Consistency.MaxSizeDiff <= 0 {
+		log.Println("Orderbook consistency thresholds not set, disabling orderbook consistency monitoring.")
+		c.Consistency.Enabled = false
+		return
+	}
+
+	if c.Consistency.Interval <= 0 {
+		log.Printf("Orderbook consistency interval not set, defaulting to %v.",
+			configDefaultConsistencyCheckInterval)
+		c.Consistency.Interval = configDefaultConsistencyCheckInterval
+	}
+
+	if c.Consistency.Depth <= 0 {
+		log.Printf("Orderbook consistency depth not set, defaulting to %v.",
+			configDefaultConsistencyDepth)
+		c.Consistency.Depth = configDefaultConsistencyDepth
+	}
+}
+
+// CheckSharedRateLimitConfigValues checks the shared rate limiter settings,
+// defaulting an unset address and namespace
+func (c *Config) CheckSharedRateLimitConfigValues() {
+	if !c.SharedRateLimit.Enabled {
+		return
+	}
+
+	if c.SharedRateLimit.Address == "" {
+		log.Printf("Shared rate limit address not set, defaulting to %s.",
+			configDefaultSharedRateLimitAddress)
+		c.SharedRateLimit.Address = configDefaultSharedRateLimitAddress
+	}
+
+	if c.SharedRateLimit.Namespace == "" {
+		log.Printf("Shared rate limit namespace not set, defaulting to %s.",
+			configDefaultSharedRateLimitNamespace)
+		c.SharedRateLimit.Namespace = configDefaultSharedRateLimitNamespace
+	}
+
+	if c.SharedRateLimit.DB < 0 {
+		log.Println("Shared rate limit database invalid, defaulting to 0.")
+		c.SharedRateLimit.DB = 0
+	}
+}
+
 // CheckCurrencyConfigValues checks to see if the currency config values are correct or not
 func (c *Config) CheckCurrencyConfigValues() error {
 	if len(c.Currency.ForexProviders) == 0 {
@@ -906,6 +1819,33 @@ func (c *Config) CheckCurrencyConfigValues() error {
 			c.Currency.FiatDisplayCurrency = "USD"
 		}
 	}
+
+	if c.Currency.ValuationCurrency == "" {
+		c.Currency.ValuationCurrency = c.Currency.FiatDisplayCurrency
+	}
+
+	for i := range c.Currency.MarketDataProviders {
+		provider := &c.Currency.MarketDataProviders[i]
+		if !provider.Enabled {
+			continue
+		}
+
+		if !common.StringDataCompare(marketdata.GetAvailableProviders(), provider.Name) {
+			log.Printf("WARNING -- %s market data provider not supported, disabling.", provider.Name)
+			provider.Enabled = false
+			continue
+		}
+
+		if provider.Name == "CoinMarketCap" && (provider.APIKey == "" || provider.APIKey == "Key") {
+			log.Printf("WARNING -- %s market data provider API key not set, disabling.", provider.Name)
+			provider.Enabled = false
+			continue
+		}
+
+		if provider.PollingDelay <= 0 {
+			provider.PollingDelay = marketdata.DefaultPollingDelay
+		}
+	}
 	return nil
 }
 
@@ -1167,6 +2107,21 @@ func (c *Config) CheckConfig() error {
 		return err
 	}
 
+	c.CheckDepositWatcherConfigValues()
+	c.CheckOrderManagerConfigValues()
+	c.CheckShutdownConfigValues()
+	c.CheckWarmCacheConfigValues()
+	c.CheckSchedulerConfigValues()
+	c.CheckPublisherConfigValues()
+	c.CheckTickerConflationConfigValues()
+	c.CheckRebalanceConfigValues()
+	c.CheckOrderbookConsistencyConfigValues()
+	c.CheckSharedRateLimitConfigValues()
+	c.CheckBookTickerConfigValues()
+	c.CheckTickerHistoryConfigValues()
+	c.CheckArbitrageConfigValues()
+	c.CheckStrategyPluginConfigValues()
+
 	if c.GlobalHTTPTimeout <= 0 {
 		log.Printf("Global HTTP Timeout value not set, defaulting to %v.", configDefaultHTTPTimeout)
 		c.GlobalHTTPTimeout = configDefaultHTTPTimeout
@@ -1202,6 +2157,9 @@ func (c *Config) UpdateConfig(configPath string, newCfg Config) error {
 	c.Currency = newCfg.Currency
 	c.GlobalHTTPTimeout = newCfg.GlobalHTTPTimeout
 	c.Portfolio = newCfg.Portfolio
+	c.DepositWatcher = newCfg.DepositWatcher
+	c.OrderManager = newCfg.OrderManager
+	c.Shutdown = newCfg.Shutdown
 	c.Communications = newCfg.Communications
 	c.Webserver = newCfg.Webserver
 	c.Exchanges = newCfg.Exchanges
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// pairFilterRegexPrefix marks a pair filter rule as a regular expression,
+// rules without it are glob patterns e.g. *DOGE*
+const pairFilterRegexPrefix = "regex:"
+
+// PairFilterConfig holds the currency pair inclusion and exclusion rules
+// applied when an exchange automatically updates its currency pairs. Rules are
+// case insensitive glob patterns, or regular expressions when prefixed with
+// regex:
+type PairFilterConfig struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Validate checks that all of the pair filter rules are valid
+func (p *PairFilterConfig) Validate() error {
+	for _, rule := range append(p.Include, p.Exclude...) {
+		if _, err := matchPairRule(rule, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsPairAllowed returns whether the currency pair passes the filter. A pair
+// must match an include rule, if any are set, and must not match any exclude
+// rules
+func (p *PairFilterConfig) IsPairAllowed(currencyPair string) bool {
+	if p == nil {
+		return true
+	}
+
+	if len(p.Include) > 0 && !matchPairRules(p.Include, currencyPair) {
+		return false
+	}
+	return !matchPairRules(p.Exclude, currencyPair)
+}
+
+// FilterPairs returns the currency pairs allowed by the filter
+func (p *PairFilterConfig) FilterPairs(pairs []string) []string {
+	if p == nil {
+		return pairs
+	}
+
+	var allowed []string
+	for x := range pairs {
+		if p.IsPairAllowed(pairs[x]) {
+			allowed = append(allowed, pairs[x])
+		}
+	}
+	return allowed
+}
+
+func matchPairRules(rules []string, currencyPair string) bool {
+	for x := range rules {
+		if ok, _ := matchPairRule(rules[x], currencyPair); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPairRule(rule, currencyPair string) (bool, error) {
+	currencyPair = common.StringToUpper(currencyPair)
+	if strings.HasPrefix(rule, pairFilterRegexPrefix) {
+		expr, err := regexp.Compile("(?i)" + strings.TrimPrefix(rule, pairFilterRegexPrefix))
+		if err != nil {
+			return false, fmt.Errorf("invalid pair filter regex %s: %s", rule, err)
+		}
+		return expr.MatchString(currencyPair), nil
+	}
+
+	ok, err := path.Match(common.StringToUpper(rule), currencyPair)
+	if err != nil {
+		return false, fmt.Errorf("invalid pair filter pattern %s: %s", rule, err)
+	}
+	return ok, nil
+}
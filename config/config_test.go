@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -391,7 +392,7 @@ func TestGetEnabledExchanges(t *testing.T) {
 	}
 
 	exchanges := cfg.GetEnabledExchanges()
-	if len(exchanges) != 30 {
+	if len(exchanges) != 31 {
 		t.Error(
 			"Test failed. TestGetEnabledExchanges. Enabled exchanges value mismatch",
 		)
@@ -443,7 +444,7 @@ func TestGetDisabledExchanges(t *testing.T) {
 }
 
 func TestCountEnabledExchanges(t *testing.T) {
-	defaultEnabledExchanges := 30
+	defaultEnabledExchanges := 31
 	GetConfigEnabledExchanges := GetConfig()
 	err := GetConfigEnabledExchanges.LoadConfig(ConfigTestFile)
 	if err != nil {
@@ -749,6 +750,35 @@ func TestCheckWebserverConfigValues(t *testing.T) {
 		)
 	}
 
+	checkWebserverConfigValues.Webserver.WithdrawalTOTPSecret = "not base32!"
+	err = checkWebserverConfigValues.CheckWebserverConfigValues()
+	if err == nil {
+		t.Error(
+			"Test failed. checkWebserverConfigValues.CheckWebserverConfigValues expected invalid TOTP secret error",
+		)
+	}
+	checkWebserverConfigValues.Webserver.WithdrawalTOTPSecret = ""
+
+	checkWebserverConfigValues.Webserver.Users = []WebserverUserConfig{
+		{Username: "trader", Password: "pw"},
+	}
+	err = checkWebserverConfigValues.CheckWebserverConfigValues()
+	if err != nil || checkWebserverConfigValues.Webserver.Users[0].Role != WebserverRoleUser {
+		t.Error(
+			"Test failed. checkWebserverConfigValues.CheckWebserverConfigValues user role not defaulted", err,
+		)
+	}
+
+	checkWebserverConfigValues.Webserver.Users = append(checkWebserverConfigValues.Webserver.Users,
+		WebserverUserConfig{Username: "trader", Password: "pw", Role: WebserverRoleAdmin})
+	err = checkWebserverConfigValues.CheckWebserverConfigValues()
+	if err == nil {
+		t.Error(
+			"Test failed. checkWebserverConfigValues.CheckWebserverConfigValues expected duplicate user error",
+		)
+	}
+	checkWebserverConfigValues.Webserver.Users = nil
+
 	checkWebserverConfigValues.Webserver.ListenAddress = ":0"
 	err = checkWebserverConfigValues.CheckWebserverConfigValues()
 	if err == nil {
@@ -905,3 +935,308 @@ func TestUpdateConfig(t *testing.T) {
 		t.Fatalf("Test failed. Cryptocurrencies should have been repopulated")
 	}
 }
+
+func TestPairFilter(t *testing.T) {
+	var nilFilter *PairFilterConfig
+	if !nilFilter.IsPairAllowed("BTCUSD") {
+		t.Error("Test failed. IsPairAllowed() nil filter excluded pair")
+	}
+
+	filter := &PairFilterConfig{
+		Include: []string{"*USD", "regex:^ETH"},
+		Exclude: []string{"*doge*"},
+	}
+
+	err := filter.Validate()
+	if err != nil {
+		t.Errorf("Test failed. Validate() error %s", err)
+	}
+
+	pairs := filter.FilterPairs([]string{"BTCUSD", "DOGEUSD", "ETHBTC", "LTCBTC"})
+	if len(pairs) != 2 || pairs[0] != "BTCUSD" || pairs[1] != "ETHBTC" {
+		t.Errorf("Test failed. FilterPairs() unexpected result %s", pairs)
+	}
+
+	filter.Exclude = []string{"regex:("}
+	if filter.Validate() == nil {
+		t.Error("Test failed. Validate() invalid regex returned no error")
+	}
+
+	filter.Exclude = []string{"[BTC"}
+	if filter.Validate() == nil {
+		t.Error("Test failed. Validate() invalid glob returned no error")
+	}
+}
+
+func TestCheckShutdownConfigValues(t *testing.T) {
+	var c Config
+	c.CheckShutdownConfigValues()
+	if c.Shutdown.Timeout != configDefaultShutdownTimeout {
+		t.Errorf("Test failed. CheckShutdownConfigValues() unexpected timeout %v",
+			c.Shutdown.Timeout)
+	}
+
+	c.Shutdown.Timeout = time.Minute
+	c.CheckShutdownConfigValues()
+	if c.Shutdown.Timeout != time.Minute {
+		t.Error("Test failed. CheckShutdownConfigValues() overrode configured timeout")
+	}
+	if c.Shutdown.StrategyTimeout != configDefaultStrategyShutdownTimeout {
+		t.Errorf("Test failed. CheckShutdownConfigValues() unexpected strategy timeout %v",
+			c.Shutdown.StrategyTimeout)
+	}
+
+	c.Shutdown.Timeout, c.Shutdown.StrategyTimeout = time.Second, time.Minute
+	c.CheckShutdownConfigValues()
+	if c.Shutdown.StrategyTimeout != time.Second {
+		t.Errorf("Test failed. CheckShutdownConfigValues() expected strategy timeout within the shutdown timeout, got %v",
+			c.Shutdown.StrategyTimeout)
+	}
+}
+
+func TestCheckStrategyShutdownConfigValues(t *testing.T) {
+	var c Config
+	c.OrderManager.StrategyShutdown = map[string]string{
+		"momentum": "flatten_positions",
+		"grid":     "close",
+	}
+	c.CheckOrderManagerConfigValues()
+	if _, ok := c.OrderManager.StrategyShutdown["grid"]; ok {
+		t.Error("Test failed. CheckOrderManagerConfigValues() invalid shutdown policy not removed")
+	}
+	if _, ok := c.OrderManager.StrategyShutdown["momentum"]; !ok {
+		t.Error("Test failed. CheckOrderManagerConfigValues() valid shutdown policy removed")
+	}
+}
+
+func TestCheckWarmCacheConfigValues(t *testing.T) {
+	var c Config
+	c.CheckWarmCacheConfigValues()
+	if c.WarmCache.MaxAge != 0 {
+		t.Error("Test failed. CheckWarmCacheConfigValues() set max age when disabled")
+	}
+
+	c.WarmCache.Enabled = true
+	c.CheckWarmCacheConfigValues()
+	if c.WarmCache.MaxAge != configDefaultWarmCacheMaxAge {
+		t.Errorf("Test failed. CheckWarmCacheConfigValues() unexpected max age %v",
+			c.WarmCache.MaxAge)
+	}
+}
+
+func TestCheckSchedulerConfigValues(t *testing.T) {
+	var c Config
+	c.Scheduler.Jobs = []SchedulerJobConfig{
+		{Name: "ledger_backfill", Enabled: true, Schedule: "@hourly", RateBudget: -1},
+		{Name: "balance_snapshot", Enabled: true, Schedule: "61 * * * *"},
+	}
+
+	c.CheckSchedulerConfigValues()
+	if !c.Scheduler.Jobs[0].Enabled || c.Scheduler.Jobs[0].RateBudget != 0 {
+		t.Errorf("Test failed. CheckSchedulerConfigValues() unexpected job %+v",
+			c.Scheduler.Jobs[0])
+	}
+
+	if c.Scheduler.Jobs[1].Enabled {
+		t.Error("Test failed. CheckSchedulerConfigValues() invalid schedule not disabled")
+	}
+}
+
+func TestCheckPublisherConfigValues(t *testing.T) {
+	var c Config
+	c.Publisher.Enabled = true
+	c.CheckPublisherConfigValues()
+	if !c.Publisher.Enabled || c.Publisher.Broker != "nats" ||
+		c.Publisher.URL != configDefaultPublisherURL ||
+		c.Publisher.TopicPrefix != configDefaultPublisherTopicPrefix {
+		t.Errorf("Test failed. CheckPublisherConfigValues() unexpected defaults %+v",
+			c.Publisher)
+	}
+
+	c.Publisher.Broker = "kafka"
+	c.CheckPublisherConfigValues()
+	if c.Publisher.Enabled {
+		t.Error("Test failed. CheckPublisherConfigValues() unsupported broker not disabled")
+	}
+}
+
+func TestCheckTickerConflationConfigValues(t *testing.T) {
+	var c Config
+	c.CheckTickerConflationConfigValues()
+	if c.TickerConflation.MaxUpdatesPerSecond != 0 {
+		t.Error("Test failed. CheckTickerConflationConfigValues() disabled config altered")
+	}
+
+	c.TickerConflation.Enabled = true
+	c.TickerConflation.MaxUpdatesPerSecond = -1
+	c.CheckTickerConflationConfigValues()
+	if c.TickerConflation.MaxUpdatesPerSecond != configDefaultTickerConflationRate {
+		t.Errorf("Test failed. CheckTickerConflationConfigValues() expected rate %v, got %v",
+			configDefaultTickerConflationRate, c.TickerConflation.MaxUpdatesPerSecond)
+	}
+}
+
+func TestCheckBookTickerConfigValues(t *testing.T) {
+	var c Config
+	c.CheckBookTickerConfigValues()
+	if c.BookTicker.MaxLag != 0 || c.BookTicker.Levels != 0 {
+		t.Error("Test failed. CheckBookTickerConfigValues() disabled config altered")
+	}
+
+	c.BookTicker.Enabled = true
+	c.BookTicker.Levels = -1
+	c.CheckBookTickerConfigValues()
+	if c.BookTicker.MaxLag != configDefaultBookTickerMaxLag ||
+		c.BookTicker.Levels != configDefaultBookTickerLevels {
+		t.Errorf("Test failed. CheckBookTickerConfigValues() unexpected defaults %+v", c.BookTicker)
+	}
+}
+
+func TestCheckTickerHistoryConfigValues(t *testing.T) {
+	var c Config
+	c.CheckTickerHistoryConfigValues()
+	if c.TickerHistory.MaxEntries != 0 {
+		t.Error("Test failed. CheckTickerHistoryConfigValues() disabled config altered")
+	}
+
+	c.TickerHistory.Enabled = true
+	c.TickerHistory.MaxAge = -1
+	c.CheckTickerHistoryConfigValues()
+	if c.TickerHistory.MaxEntries != configDefaultTickerHistoryEntries ||
+		c.TickerHistory.MaxAge != 0 {
+		t.Errorf("Test failed. CheckTickerHistoryConfigValues() unexpected defaults %+v", c.TickerHistory)
+	}
+
+	c.TickerHistory.MaxEntries = 0
+	c.TickerHistory.MaxAge = time.Minute * 15
+	c.CheckTickerHistoryConfigValues()
+	if c.TickerHistory.MaxEntries != 0 {
+		t.Errorf("Test failed. CheckTickerHistoryConfigValues() expected unbounded entries, got %d",
+			c.TickerHistory.MaxEntries)
+	}
+}
+
+func TestCheckRebalanceConfigValues(t *testing.T) {
+	var c Config
+	c.Rebalance.Enabled = true
+	c.Rebalance.Targets = map[string]float64{"BTC": 0.5, "USD": 0.6}
+	c.Rebalance.DriftThreshold = 0.05
+	c.CheckRebalanceConfigValues()
+	if c.Rebalance.Enabled {
+		t.Error("Test failed. CheckRebalanceConfigValues() invalid targets not disabled")
+	}
+
+	c.Rebalance.Enabled = true
+	c.Rebalance.Targets["USD"] = 0.5
+	c.Rebalance.DriftThreshold = 0
+	c.CheckRebalanceConfigValues()
+	if c.Rebalance.Enabled {
+		t.Error("Test failed. CheckRebalanceConfigValues() rebalancing without a trigger not disabled")
+	}
+
+	c.Rebalance.Enabled = true
+	c.Rebalance.Interval = time.Hour * 24 * 7
+	c.CheckRebalanceConfigValues()
+	if !c.Rebalance.Enabled || c.Rebalance.CheckInterval != configDefaultRebalanceCheckInterval {
+		t.Errorf("Test failed. CheckRebalanceConfigValues() expected check interval %v, got %v",
+			configDefaultRebalanceCheckInterval, c.Rebalance.CheckInterval)
+	}
+}
+
+func TestCheckArbitrageConfigValues(t *testing.T) {
+	var c Config
+	c.Arbitrage.Enabled = true
+	c.Arbitrage.Exchanges = []string{"Bitstamp"}
+	c.CheckArbitrageConfigValues()
+	if c.Arbitrage.Enabled {
+		t.Error("Test failed. CheckArbitrageConfigValues() single exchange not disabled")
+	}
+
+	c.Arbitrage.Enabled = true
+	c.Arbitrage.Exchanges = []string{"Bitstamp", "Kraken"}
+	c.Arbitrage.Pair = "BTCUSD"
+	c.Arbitrage.MaxAmount = 1
+	c.CheckArbitrageConfigValues()
+	if c.Arbitrage.Enabled {
+		t.Error("Test failed. CheckArbitrageConfigValues() pair without a delimiter not disabled")
+	}
+
+	c.Arbitrage.Enabled = true
+	c.Arbitrage.Pair = "BTC-USD"
+	c.Arbitrage.RebalanceInterval = time.Hour
+	c.CheckArbitrageConfigValues()
+	if !c.Arbitrage.Enabled || c.Arbitrage.CheckInterval != configDefaultArbitrageCheckInterval ||
+		c.Arbitrage.HedgeTimeout != configDefaultArbitrageHedgeTimeout ||
+		c.Arbitrage.RebalanceThreshold != configDefaultArbitrageImbalance {
+		t.Errorf("Test failed. CheckArbitrageConfigValues() unexpected defaults %+v", c.Arbitrage)
+	}
+}
+
+func TestCheckStrategyPluginConfigValues(t *testing.T) {
+	var c Config
+	c.StrategyPlugins = []StrategyPluginConfig{
+		{Name: "momentum", Enabled: true, Command: "python3", Args: []string{"momentum.py"},
+			Exchanges: []string{"Bitstamp"}, Pairs: []string{"BTC-USD"}},
+		{Name: "MOMENTUM", Enabled: true, Plugin: "momentum.so",
+			Exchanges: []string{"Bitstamp"}, Pairs: []string{"BTC-USD"}},
+		{Name: "both", Enabled: true, Plugin: "both.so", Command: "both",
+			Exchanges: []string{"Bitstamp"}, Pairs: []string{"BTC-USD"}},
+		{Name: "undelimited", Enabled: true, Plugin: "undelimited.so",
+			Exchanges: []string{"Bitstamp"}, Pairs: []string{"BTCUSD"}},
+		{Name: "disabled"},
+	}
+	c.CheckStrategyPluginConfigValues()
+
+	s := c.StrategyPlugins
+	if !s[0].Enabled || s[0].Interval != configDefaultStrategyPluginInterval ||
+		s[0].Timeout != configDefaultStrategyPluginTimeout {
+		t.Errorf("Test failed. CheckStrategyPluginConfigValues() unexpected defaults %+v", s[0])
+	}
+	if s[1].Enabled {
+		t.Error("Test failed. CheckStrategyPluginConfigValues() duplicate name not disabled")
+	}
+	if s[2].Enabled {
+		t.Error("Test failed. CheckStrategyPluginConfigValues() plugin and command not disabled")
+	}
+	if s[3].Enabled {
+		t.Error("Test failed. CheckStrategyPluginConfigValues() pair without a delimiter not disabled")
+	}
+	if s[4].Interval != 0 {
+		t.Error("Test failed. CheckStrategyPluginConfigValues() disabled config altered")
+	}
+}
+
+func TestCheckOrderbookConsistencyConfigValues(t *testing.T) {
+	var c Config
+	c.Consistency.Enabled = true
+	c.CheckOrderbookConsistencyConfigValues()
+	if c.Consistency.Enabled {
+		t.Error("Test failed. CheckOrderbookConsistencyConfigValues() monitoring without thresholds not disabled")
+	}
+
+	c.Consistency.Enabled = true
+	c.Consistency.MaxPriceDiff = 0.001
+	c.CheckOrderbookConsistencyConfigValues()
+	if !c.Consistency.Enabled || c.Consistency.Interval != configDefaultConsistencyCheckInterval ||
+		c.Consistency.Depth != configDefaultConsistencyDepth {
+		t.Errorf("Test failed. CheckOrderbookConsistencyConfigValues() expected interval %v and depth %v, got %v and %v",
+			configDefaultConsistencyCheckInterval, configDefaultConsistencyDepth,
+			c.Consistency.Interval, c.Consistency.Depth)
+	}
+}
+
+func TestCheckSharedRateLimitConfigValues(t *testing.T) {
+	var c Config
+	c.CheckSharedRateLimitConfigValues()
+	if c.SharedRateLimit.Address != "" {
+		t.Error("Test failed. CheckSharedRateLimitConfigValues() defaulted a disabled shared rate limiter")
+	}
+
+	c.SharedRateLimit.Enabled = true
+	c.SharedRateLimit.DB = -1
+	c.CheckSharedRateLimitConfigValues()
+	if c.SharedRateLimit.Address != configDefaultSharedRateLimitAddress ||
+		c.SharedRateLimit.Namespace != configDefaultSharedRateLimitNamespace || c.SharedRateLimit.DB != 0 {
+		t.Errorf("Test failed. CheckSharedRateLimitConfigValues() unexpected defaults %+v", c.SharedRateLimit)
+	}
+}
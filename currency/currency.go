@@ -112,8 +112,10 @@ func IsFiatPair(p pair.CurrencyPair) bool {
 		IsFiatCurrency(p.SecondCurrency.String())
 }
 
-// Update updates the local crypto currency or base currency store
+// Update updates the local crypto currency or base currency store, adding the
+// currencies to the known currencies used to split pairs
 func Update(input []string, cryptos bool) {
+	pair.RegisterCurrencies(input...)
 	for x := range input {
 		if cryptos {
 			if !common.StringDataCompare(CryptoCurrencies, input[x]) {
@@ -0,0 +1,79 @@
+package marketdata
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+const (
+	coinGeckoAPIURL  = "https://api.coingecko.com/api/v3/"
+	coinGeckoMarkets = "coins/markets"
+
+	// coinGeckoPageSize is the maximum number of coins returned per request
+	coinGeckoPageSize = 250
+)
+
+// CoinGecko is a market data provider at https://www.coingecko.com. An API
+// key is optional and is sent as a demo API key
+type CoinGecko struct {
+	Base
+	apiURL string
+}
+
+// Setup sets appropriate values for CoinGecko
+func (c *CoinGecko) Setup(settings Settings) {
+	c.Settings = settings
+	c.apiURL = coinGeckoAPIURL
+}
+
+// GetMarketData returns the market data of the symbols quoted in the quote
+// currency. Symbols shared by several coins resolve to the coin with the
+// largest market capitalisation
+func (c *CoinGecko) GetMarketData(symbols []string, quote string) ([]MarketData, error) {
+	markets, err := c.GetCoinMarkets(symbols, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []MarketData
+	found := make(map[string]bool)
+	for x := range markets {
+		symbol := common.StringToUpper(markets[x].Symbol)
+		if found[symbol] {
+			continue
+		}
+		found[symbol] = true
+
+		data = append(data, MarketData{
+			Symbol:      symbol,
+			Quote:       common.StringToUpper(quote),
+			Price:       markets[x].CurrentPrice,
+			MarketCap:   markets[x].MarketCap,
+			Volume24h:   markets[x].TotalVolume,
+			LastUpdated: markets[x].LastUpdated,
+			Provider:    c.Name,
+		})
+	}
+	return data, nil
+}
+
+// GetCoinMarkets returns the coin markets of the symbols quoted in the quote
+// currency ordered by market capitalisation
+func (c *CoinGecko) GetCoinMarkets(symbols []string, quote string) ([]CoinGeckoMarket, error) {
+	v := url.Values{}
+	v.Set("vs_currency", common.StringToLower(quote))
+	v.Set("symbols", common.StringToLower(common.JoinStrings(symbols, ",")))
+	v.Set("order", "market_cap_desc")
+	v.Set("per_page", strconv.Itoa(coinGeckoPageSize))
+	if c.APIKey != "" {
+		v.Set("x_cg_demo_api_key", c.APIKey)
+	}
+
+	var markets []CoinGeckoMarket
+	path := fmt.Sprintf("%s%s?%s", c.apiURL, coinGeckoMarkets, v.Encode())
+	err := common.SendHTTPGetRequest(path, true, c.Verbose, &markets)
+	return markets, err
+}
@@ -0,0 +1,96 @@
+package marketdata
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+const (
+	coinMarketCapAPIURL       = "https://pro-api.coinmarketcap.com/v1/"
+	coinMarketCapQuotesLatest = "cryptocurrency/quotes/latest"
+	coinMarketCapAPIKeyHeader = "X-CMC_PRO_API_KEY"
+)
+
+// CoinMarketCap is a market data provider at https://coinmarketcap.com which
+// requires an API key
+type CoinMarketCap struct {
+	Base
+	apiURL string
+}
+
+// Setup sets appropriate values for CoinMarketCap
+func (c *CoinMarketCap) Setup(settings Settings) {
+	c.Settings = settings
+	c.apiURL = coinMarketCapAPIURL
+}
+
+// GetMarketData returns the market data of the symbols quoted in the quote
+// currency
+func (c *CoinMarketCap) GetMarketData(symbols []string, quote string) ([]MarketData, error) {
+	quotes, err := c.GetLatestQuotes(symbols, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	quote = common.StringToUpper(quote)
+	var data []MarketData
+	for symbol, listing := range quotes {
+		q, ok := listing.Quote[quote]
+		if !ok {
+			continue
+		}
+
+		data = append(data, MarketData{
+			Symbol:      symbol,
+			Quote:       quote,
+			Price:       q.Price,
+			MarketCap:   q.MarketCap,
+			Volume24h:   q.Volume24h,
+			LastUpdated: q.LastUpdated,
+			Provider:    c.Name,
+		})
+	}
+	return data, nil
+}
+
+// GetLatestQuotes returns the latest quotes of the symbols converted into the
+// quote currency, keyed by symbol
+func (c *CoinMarketCap) GetLatestQuotes(symbols []string, quote string) (map[string]CoinMarketCapListing, error) {
+	if c.APIKey == "" {
+		return nil, ErrProviderAPIKeyRequired
+	}
+
+	v := url.Values{}
+	v.Set("symbol", common.StringToUpper(common.JoinStrings(symbols, ",")))
+	v.Set("convert", common.StringToUpper(quote))
+
+	path := fmt.Sprintf("%s%s?%s", c.apiURL, coinMarketCapQuotesLatest, v.Encode())
+	headers := map[string]string{
+		coinMarketCapAPIKeyHeader: c.APIKey,
+		"Accept":                  "application/json",
+	}
+
+	resp, err := common.SendHTTPRequest("GET", path, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Verbose {
+		log.Printf("%s raw response: %s", c.Name, resp)
+	}
+
+	var result CoinMarketCapQuotes
+	err = common.JSONDecode([]byte(resp), &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Status.ErrorCode != 0 {
+		return nil, errors.New(result.Status.ErrorMessage)
+	}
+	return result.Data, nil
+}
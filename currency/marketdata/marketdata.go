@@ -0,0 +1,224 @@
+// Package marketdata retrieves reference prices and market capitalisations
+// from market data providers which aggregate prices across venues. Reference
+// prices are kept apart from exchange tickers and are used where exchange
+// data is missing
+package marketdata
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// DefaultPollingDelay is the default delay between market data updates
+const DefaultPollingDelay = 5 * time.Minute
+
+// Vars for the market data store
+var (
+	referenceData = make(map[string]map[string]MarketData)
+	referenceMtx  sync.Mutex
+
+	ErrNoProvidersEnabled     = errors.New("no market data providers enabled")
+	ErrReferenceDataNotFound  = errors.New("reference market data not found")
+	ErrMarketDataUnavailable  = errors.New("market data providers failed to return data")
+	ErrProviderAPIKeyRequired = errors.New("market data provider requires an API key")
+)
+
+// Settings holds the settings of a market data provider
+type Settings struct {
+	Name            string        `json:"name"`
+	Enabled         bool          `json:"enabled"`
+	Verbose         bool          `json:"verbose"`
+	PollingDelay    time.Duration `json:"pollingDelay"`
+	APIKey          string        `json:"apiKey"`
+	PrimaryProvider bool          `json:"primaryProvider"`
+}
+
+// MarketData holds the reference price, market capitalisation and 24 hour
+// volume of a currency quoted in another currency
+type MarketData struct {
+	Symbol      string    `json:"symbol"`
+	Quote       string    `json:"quote"`
+	Price       float64   `json:"price"`
+	MarketCap   float64   `json:"marketCap"`
+	Volume24h   float64   `json:"volume24h"`
+	LastUpdated time.Time `json:"lastUpdated"`
+	Provider    string    `json:"provider"`
+}
+
+// Provider enforces standard functions for all market data providers
+type Provider interface {
+	Setup(settings Settings)
+	GetMarketData(symbols []string, quote string) ([]MarketData, error)
+	GetName() string
+	IsEnabled() bool
+	IsPrimaryProvider() bool
+	GetPollingDelay() time.Duration
+}
+
+// Base holds the settings shared by the market data providers
+type Base struct {
+	Settings
+}
+
+// GetName returns the name of the provider
+func (b *Base) GetName() string {
+	return b.Name
+}
+
+// IsEnabled returns whether the provider is enabled
+func (b *Base) IsEnabled() bool {
+	return b.Enabled
+}
+
+// IsPrimaryProvider returns whether the provider is the primary provider
+func (b *Base) IsPrimaryProvider() bool {
+	return b.PrimaryProvider
+}
+
+// GetPollingDelay returns the delay between market data updates
+func (b *Base) GetPollingDelay() time.Duration {
+	if b.PollingDelay <= 0 {
+		return DefaultPollingDelay
+	}
+	return b.PollingDelay
+}
+
+// Providers holds the enabled market data providers
+type Providers []Provider
+
+// GetAvailableProviders returns a list of supported market data providers
+func GetAvailableProviders() []string {
+	return []string{"CoinGecko", "CoinMarketCap"}
+}
+
+// NewProviders returns the enabled market data providers
+func NewProviders(settings []Settings) Providers {
+	var providers Providers
+	for x := range settings {
+		if !settings[x].Enabled {
+			continue
+		}
+
+		var p Provider
+		switch settings[x].Name {
+		case "CoinGecko":
+			p = new(CoinGecko)
+		case "CoinMarketCap":
+			p = new(CoinMarketCap)
+		default:
+			log.Printf("Unsupported market data provider %s.\n", settings[x].Name)
+			continue
+		}
+		p.Setup(settings[x])
+		providers = append(providers, p)
+	}
+	return providers
+}
+
+// GetPollingDelay returns the shortest polling delay of the providers
+func (p Providers) GetPollingDelay() time.Duration {
+	delay := DefaultPollingDelay
+	for x := range p {
+		if x == 0 || p[x].GetPollingDelay() < delay {
+			delay = p[x].GetPollingDelay()
+		}
+	}
+	return delay
+}
+
+// Update fetches the market data of the symbols quoted in the quote currency
+// from the primary provider, falling back to the other providers, and stores
+// it as reference data
+func (p Providers) Update(symbols []string, quote string) error {
+	if len(p) == 0 {
+		return ErrNoProvidersEnabled
+	}
+
+	ordered := make(Providers, 0, len(p))
+	for x := range p {
+		if p[x].IsPrimaryProvider() {
+			ordered = append(Providers{p[x]}, ordered...)
+			continue
+		}
+		ordered = append(ordered, p[x])
+	}
+
+	for x := range ordered {
+		if !ordered[x].IsEnabled() {
+			continue
+		}
+
+		data, err := ordered[x].GetMarketData(symbols, quote)
+		if err != nil {
+			log.Printf("%s market data error: %s", ordered[x].GetName(), err)
+			continue
+		}
+
+		for y := range data {
+			SetReferenceData(data[y])
+		}
+		return nil
+	}
+	return ErrMarketDataUnavailable
+}
+
+// SetReferenceData stores the reference market data of a currency
+func SetReferenceData(data MarketData) {
+	data.Symbol = common.StringToUpper(data.Symbol)
+	data.Quote = common.StringToUpper(data.Quote)
+
+	referenceMtx.Lock()
+	defer referenceMtx.Unlock()
+
+	if _, ok := referenceData[data.Symbol]; !ok {
+		referenceData[data.Symbol] = make(map[string]MarketData)
+	}
+	referenceData[data.Symbol][data.Quote] = data
+}
+
+// GetReferenceData returns the reference market data of a currency quoted in
+// the quote currency
+func GetReferenceData(symbol, quote string) (MarketData, error) {
+	referenceMtx.Lock()
+	defer referenceMtx.Unlock()
+
+	data, ok := referenceData[common.StringToUpper(symbol)][common.StringToUpper(quote)]
+	if !ok {
+		return MarketData{}, ErrReferenceDataNotFound
+	}
+	return data, nil
+}
+
+// GetAllReferenceData returns all stored reference market data
+func GetAllReferenceData() []MarketData {
+	referenceMtx.Lock()
+	defer referenceMtx.Unlock()
+
+	var data []MarketData
+	for symbol := range referenceData {
+		for quote := range referenceData[symbol] {
+			data = append(data, referenceData[symbol][quote])
+		}
+	}
+	return data
+}
+
+// GetReferencePrice returns the reference price of a currency in the quote
+// currency, inverting the reference price of the quote currency if that is
+// all that is stored
+func GetReferencePrice(symbol, quote string) (float64, bool) {
+	data, err := GetReferenceData(symbol, quote)
+	if err == nil && data.Price > 0 {
+		return data.Price, true
+	}
+
+	data, err = GetReferenceData(quote, symbol)
+	if err == nil && data.Price > 0 {
+		return 1 / data.Price, true
+	}
+	return 0, false
+}
@@ -0,0 +1,151 @@
+package marketdata
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testProvider struct {
+	Base
+	data []MarketData
+	err  error
+}
+
+func (t *testProvider) Setup(settings Settings) {
+	t.Settings = settings
+}
+
+func (t *testProvider) GetMarketData(symbols []string, quote string) ([]MarketData, error) {
+	return t.data, t.err
+}
+
+func TestReferencePrice(t *testing.T) {
+	SetReferenceData(MarketData{Symbol: "xyz", Quote: "usd", Price: 4})
+
+	price, ok := GetReferencePrice("XYZ", "USD")
+	if !ok || price != 4 {
+		t.Error("Test failed - GetReferencePrice() unexpected price", price)
+	}
+
+	price, ok = GetReferencePrice("USD", "XYZ")
+	if !ok || price != 0.25 {
+		t.Error("Test failed - GetReferencePrice() unexpected inverted price", price)
+	}
+
+	_, ok = GetReferencePrice("XYZ", "EUR")
+	if ok {
+		t.Error("Test failed - GetReferencePrice() returned a missing price")
+	}
+
+	_, err := GetReferenceData("ABC", "USD")
+	if err != ErrReferenceDataNotFound {
+		t.Error("Test failed - GetReferenceData() missing data error", err)
+	}
+}
+
+func TestProvidersUpdate(t *testing.T) {
+	var providers Providers
+	if providers.Update([]string{"BTC"}, "USD") != ErrNoProvidersEnabled {
+		t.Error("Test failed - Update() expected no providers error")
+	}
+
+	primary := &testProvider{err: errors.New("unavailable")}
+	primary.Setup(Settings{Name: "Primary", Enabled: true, PrimaryProvider: true})
+	fallback := &testProvider{data: []MarketData{{Symbol: "FALLBACK", Quote: "USD", Price: 2}}}
+	fallback.Setup(Settings{Name: "Fallback", Enabled: true, PollingDelay: time.Minute})
+
+	providers = Providers{fallback, primary}
+	err := providers.Update([]string{"FALLBACK"}, "USD")
+	if err != nil {
+		t.Fatal("Test failed - Update() error", err)
+	}
+
+	data, err := GetReferenceData("FALLBACK", "USD")
+	if err != nil || data.Price != 2 {
+		t.Error("Test failed - Update() fallback data not stored", err)
+	}
+
+	if providers.GetPollingDelay() != time.Minute {
+		t.Error("Test failed - GetPollingDelay() unexpected delay", providers.GetPollingDelay())
+	}
+
+	fallback.err = errors.New("unavailable")
+	if providers.Update([]string{"FALLBACK"}, "USD") != ErrMarketDataUnavailable {
+		t.Error("Test failed - Update() expected unavailable error")
+	}
+}
+
+func TestNewProviders(t *testing.T) {
+	providers := NewProviders([]Settings{
+		{Name: "CoinGecko", Enabled: true},
+		{Name: "CoinMarketCap", Enabled: false},
+		{Name: "Blah", Enabled: true},
+	})
+	if len(providers) != 1 || providers[0].GetName() != "CoinGecko" {
+		t.Error("Test failed - NewProviders() unexpected providers", providers)
+	}
+}
+
+func TestCoinGeckoGetMarketData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("vs_currency") != "usd" {
+			t.Error("Test failed - CoinGecko unexpected quote currency", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[{"id":"bitcoin","symbol":"btc","current_price":10000,"market_cap":2e11,"total_volume":1e10,"last_updated":"2018-10-01T00:00:00.000Z"},
+			{"id":"other-btc","symbol":"btc","current_price":1,"market_cap":5,"total_volume":1,"last_updated":"2018-10-01T00:00:00.000Z"}]`))
+	}))
+	defer server.Close()
+
+	c := new(CoinGecko)
+	c.Setup(Settings{Name: "CoinGecko", Enabled: true})
+	c.apiURL = server.URL + "/"
+
+	data, err := c.GetMarketData([]string{"BTC"}, "USD")
+	if err != nil {
+		t.Fatal("Test failed - CoinGecko GetMarketData() error", err)
+	}
+
+	if len(data) != 1 || data[0].Symbol != "BTC" || data[0].Price != 10000 || data[0].MarketCap != 2e11 {
+		t.Errorf("Test failed - CoinGecko GetMarketData() unexpected data %+v", data)
+	}
+}
+
+func TestCoinMarketCapGetMarketData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(coinMarketCapAPIKeyHeader) != "key" {
+			w.Write([]byte(`{"status":{"error_code":1002,"error_message":"API key missing."}}`))
+			return
+		}
+		w.Write([]byte(`{"status":{"error_code":0,"error_message":null},"data":{"ETH":{"id":1027,"name":"Ethereum","symbol":"ETH",
+			"quote":{"USD":{"price":200,"volume_24h":1e9,"market_cap":2e10,"last_updated":"2018-10-01T00:00:00.000Z"}}}}}`))
+	}))
+	defer server.Close()
+
+	c := new(CoinMarketCap)
+	c.Setup(Settings{Name: "CoinMarketCap", Enabled: true})
+	c.apiURL = server.URL + "/"
+
+	_, err := c.GetMarketData([]string{"ETH"}, "USD")
+	if err != ErrProviderAPIKeyRequired {
+		t.Error("Test failed - CoinMarketCap GetMarketData() expected API key error", err)
+	}
+
+	c.APIKey = "bad"
+	_, err = c.GetMarketData([]string{"ETH"}, "USD")
+	if err == nil {
+		t.Error("Test failed - CoinMarketCap GetMarketData() expected status error")
+	}
+
+	c.APIKey = "key"
+	data, err := c.GetMarketData([]string{"ETH"}, "usd")
+	if err != nil {
+		t.Fatal("Test failed - CoinMarketCap GetMarketData() error", err)
+	}
+
+	if len(data) != 1 || data[0].Symbol != "ETH" || data[0].Price != 200 || data[0].Volume24h != 1e9 {
+		t.Errorf("Test failed - CoinMarketCap GetMarketData() unexpected data %+v", data)
+	}
+}
@@ -0,0 +1,40 @@
+package marketdata
+
+import "time"
+
+// CoinGeckoMarket holds the market data of a coin returned by CoinGecko
+type CoinGeckoMarket struct {
+	ID           string    `json:"id"`
+	Symbol       string    `json:"symbol"`
+	Name         string    `json:"name"`
+	CurrentPrice float64   `json:"current_price"`
+	MarketCap    float64   `json:"market_cap"`
+	TotalVolume  float64   `json:"total_volume"`
+	LastUpdated  time.Time `json:"last_updated"`
+}
+
+// CoinMarketCapQuotes holds the latest quotes response from CoinMarketCap
+type CoinMarketCapQuotes struct {
+	Status struct {
+		ErrorCode    int    `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"status"`
+	Data map[string]CoinMarketCapListing `json:"data"`
+}
+
+// CoinMarketCapListing holds a cryptocurrency listing and its quotes by
+// convert currency
+type CoinMarketCapListing struct {
+	ID     int                           `json:"id"`
+	Name   string                        `json:"name"`
+	Symbol string                        `json:"symbol"`
+	Quote  map[string]CoinMarketCapQuote `json:"quote"`
+}
+
+// CoinMarketCapQuote holds the quote of a listing in a convert currency
+type CoinMarketCapQuote struct {
+	Price       float64   `json:"price"`
+	Volume24h   float64   `json:"volume_24h"`
+	MarketCap   float64   `json:"market_cap"`
+	LastUpdated time.Time `json:"last_updated"`
+}
@@ -119,7 +119,8 @@ func NewCurrencyPairFromIndex(currency, index string) CurrencyPair {
 }
 
 // NewCurrencyPairFromString converts currency string into a new CurrencyPair
-// with or without delimeter
+// with or without delimeter, see NewCurrencyPairFromConcatenated for how
+// pairs without a delimiter are split
 func NewCurrencyPairFromString(currency string) CurrencyPair {
 	delimiters := []string{"_", "-"}
 	var delimiter string
@@ -129,7 +130,7 @@ func NewCurrencyPairFromString(currency string) CurrencyPair {
 			return NewCurrencyPairDelimiter(currency, delimiter)
 		}
 	}
-	return NewCurrencyPair(currency[0:3], currency[3:])
+	return NewCurrencyPairFromConcatenated(currency)
 }
 
 // Contains checks to see if a specified pair exists inside a currency pair
@@ -177,7 +178,7 @@ func FormatPairs(pairs []string, delimiter, index string) []CurrencyPair {
 			if index != "" {
 				p = NewCurrencyPairFromIndex(pairs[x], index)
 			} else {
-				p = NewCurrencyPair(pairs[x][0:3], pairs[x][3:])
+				p = NewCurrencyPairFromConcatenated(pairs[x])
 			}
 		}
 		result = append(result, p)
@@ -0,0 +1,107 @@
+package pair
+
+import (
+	"strings"
+	"sync"
+)
+
+// defaultCurrencies seeds the known currency registry with widely traded
+// codes, including the four letter codes fixed offset splitting breaks on
+var defaultCurrencies = []string{
+	"USD", "EUR", "GBP", "JPY", "AUD", "CAD", "CHF", "CNY", "HKD", "KRW",
+	"NZD", "RUB", "SGD", "TRY", "UAH", "PLN", "BRL", "ZAR", "IDR", "INR",
+	"BTC", "XBT", "ETH", "LTC", "BCH", "XRP", "DOGE", "DASH", "XMR", "ETC",
+	"ZEC", "EOS", "TRX", "ADA", "XLM", "NEO", "BNB", "LINK", "AVAX", "DOT",
+	"SOL", "ATOM", "XTZ", "QTUM", "IOTA", "OMG", "ZRX", "BAT", "USDT", "USDC",
+	"TUSD", "BUSD", "PAX", "DAI",
+}
+
+var registry = struct {
+	currencies map[string]bool
+	pairs      map[string]CurrencyPair
+	mtx        sync.RWMutex
+}{
+	currencies: make(map[string]bool),
+	pairs:      make(map[string]CurrencyPair),
+}
+
+func init() {
+	RegisterCurrencies(defaultCurrencies...)
+}
+
+// RegisterCurrencies adds currency codes to the known currency registry used
+// to split currency pairs which have no delimiter
+func RegisterCurrencies(codes ...string) {
+	registry.mtx.Lock()
+	for x := range codes {
+		if codes[x] == "" {
+			continue
+		}
+		registry.currencies[strings.ToUpper(codes[x])] = true
+	}
+	registry.mtx.Unlock()
+}
+
+// IsKnownCurrency returns whether a currency code is in the known currency
+// registry
+func IsKnownCurrency(code string) bool {
+	registry.mtx.RLock()
+	defer registry.mtx.RUnlock()
+	return registry.currencies[strings.ToUpper(code)]
+}
+
+// RegisterPair records the base and quote currencies of a pair as provided by
+// an exchange, so its symbol without a delimiter splits exactly. Both
+// currencies are added to the known currency registry
+func RegisterPair(p CurrencyPair) {
+	if p.Empty() {
+		return
+	}
+
+	RegisterCurrencies(p.FirstCurrency.String(), p.SecondCurrency.String())
+	registry.mtx.Lock()
+	registry.pairs[p.Display("", true).String()] = NewCurrencyPair(
+		p.FirstCurrency.Upper().String(), p.SecondCurrency.Upper().String())
+	registry.mtx.Unlock()
+}
+
+// NewCurrencyPairFromConcatenated splits a currency pair which has no
+// delimiter, such as USDTBTC or DOGEUSDT. Pairs registered by an exchange are
+// split as registered, otherwise the longest known base followed by a known
+// quote is used, then the longest known quote and then the longest known
+// base. Pairs of unknown currencies fall back to a three letter base. The
+// case of the currency string is kept
+func NewCurrencyPairFromConcatenated(currency string) CurrencyPair {
+	upper := strings.ToUpper(currency)
+
+	registry.mtx.RLock()
+	defer registry.mtx.RUnlock()
+
+	if p, ok := registry.pairs[upper]; ok {
+		i := len(p.FirstCurrency)
+		return NewCurrencyPair(currency[:i], currency[i:])
+	}
+
+	for i := len(upper) - 1; i > 0; i-- {
+		if registry.currencies[upper[:i]] && registry.currencies[upper[i:]] {
+			return NewCurrencyPair(currency[:i], currency[i:])
+		}
+	}
+
+	for i := 1; i < len(upper); i++ {
+		if registry.currencies[upper[i:]] {
+			return NewCurrencyPair(currency[:i], currency[i:])
+		}
+	}
+
+	for i := len(upper) - 1; i > 0; i-- {
+		if registry.currencies[upper[:i]] {
+			return NewCurrencyPair(currency[:i], currency[i:])
+		}
+	}
+
+	if len(currency) < 3 {
+		return NewCurrencyPair(currency, "")
+	}
+	return NewCurrencyPair(currency[:3], currency[3:])
+}
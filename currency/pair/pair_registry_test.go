@@ -0,0 +1,48 @@
+package pair
+
+import "testing"
+
+func TestNewCurrencyPairFromConcatenated(t *testing.T) {
+	tests := []struct {
+		currency string
+		first    CurrencyItem
+		second   CurrencyItem
+	}{
+		{"BTCUSD", "BTC", "USD"},
+		{"USDTBTC", "USDT", "BTC"},
+		{"DOGEUSDT", "DOGE", "USDT"},
+		{"linkbtc", "link", "btc"},
+		{"AVAXUSDC", "AVAX", "USDC"},
+		{"XYZUSDT", "XYZ", "USDT"},
+		{"DASHXYZ", "DASH", "XYZ"},
+		{"ABCDEF", "ABC", "DEF"},
+		{"AB", "AB", ""},
+	}
+
+	for _, test := range tests {
+		p := NewCurrencyPairFromConcatenated(test.currency)
+		if p.FirstCurrency != test.first || p.SecondCurrency != test.second {
+			t.Errorf("Test failed. NewCurrencyPairFromConcatenated(%s) returned %s/%s, expected %s/%s",
+				test.currency, p.FirstCurrency, p.SecondCurrency, test.first, test.second)
+		}
+	}
+
+	if IsKnownCurrency("GOLDX") {
+		t.Error("Test failed. IsKnownCurrency() unexpected known currency")
+	}
+
+	RegisterPair(NewCurrencyPair("GOLDX", "MONA"))
+	if !IsKnownCurrency("goldx") || !IsKnownCurrency("MONA") {
+		t.Error("Test failed. RegisterPair() currencies not registered")
+	}
+
+	p := NewCurrencyPairFromConcatenated("GOLDXMONA")
+	if p.FirstCurrency != "GOLDX" || p.SecondCurrency != "MONA" {
+		t.Errorf("Test failed. NewCurrencyPairFromConcatenated() unexpected registered pair %s", p.Pair())
+	}
+
+	pairs := FormatPairs([]string{"DOGEBTC"}, "", "")
+	if len(pairs) != 1 || pairs[0].FirstCurrency != "DOGE" {
+		t.Errorf("Test failed. FormatPairs() unexpected pairs %v", pairs)
+	}
+}
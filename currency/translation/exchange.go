@@ -0,0 +1,121 @@
+package translation
+
+import (
+	"sync"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// ExchangeCode maps a currency code used by an exchange to its canonical code.
+// Codes which only appear in responses, such as Kraken balance asset names, are
+// translated to canonical codes but never sent to the exchange
+type ExchangeCode struct {
+	Code         pair.CurrencyItem
+	Canonical    pair.CurrencyItem
+	ResponseOnly bool
+}
+
+// exchangeCodes holds the currency codes which differ from the canonical codes
+// per exchange
+var (
+	exchangeCodes = map[string][]ExchangeCode{
+		"kraken": {
+			{"XBT", "BTC", false},
+			{"XDG", "DOGE", false},
+			{"XXBT", "BTC", true},
+			{"XXDG", "DOGE", true},
+			{"XETH", "ETH", true},
+			{"XETC", "ETC", true},
+			{"XLTC", "LTC", true},
+			{"XXRP", "XRP", true},
+			{"XXLM", "XLM", true},
+			{"XXMR", "XMR", true},
+			{"XZEC", "ZEC", true},
+			{"XREP", "REP", true},
+			{"XMLN", "MLN", true},
+			{"XICN", "ICN", true},
+			{"ZUSD", "USD", true},
+			{"ZEUR", "EUR", true},
+			{"ZGBP", "GBP", true},
+			{"ZJPY", "JPY", true},
+			{"ZCAD", "CAD", true},
+		},
+		"bitfinex": {
+			{"DSH", "DASH", false},
+			{"QTM", "QTUM", false},
+			{"IOT", "IOTA", false},
+			{"DAT", "DATA", false},
+			{"MNA", "MANA", false},
+			{"QSH", "QASH", false},
+			{"SNG", "SNGLS", false},
+			{"SPK", "SPANK", false},
+			{"YYW", "YOYOW", false},
+			{"UST", "USDT", false},
+		},
+		"poloniex": {
+			{"STR", "XLM", false},
+		},
+	}
+	exchangeCodesMtx sync.RWMutex
+)
+
+// SetExchangeCodes replaces the currency code translations for an exchange
+func SetExchangeCodes(exchange string, codes []ExchangeCode) {
+	exchangeCodesMtx.Lock()
+	exchangeCodes[common.StringToLower(exchange)] = codes
+	exchangeCodesMtx.Unlock()
+}
+
+// GetExchangeCodes returns the currency code translations for an exchange
+func GetExchangeCodes(exchange string) []ExchangeCode {
+	exchangeCodesMtx.RLock()
+	defer exchangeCodesMtx.RUnlock()
+	return append([]ExchangeCode(nil), exchangeCodes[common.StringToLower(exchange)]...)
+}
+
+// ToCanonical returns the canonical code for a currency code used by an
+// exchange, codes without a translation are returned unchanged
+func ToCanonical(exchange string, code pair.CurrencyItem) pair.CurrencyItem {
+	exchangeCodesMtx.RLock()
+	defer exchangeCodesMtx.RUnlock()
+
+	upper := code.Upper()
+	for _, x := range exchangeCodes[common.StringToLower(exchange)] {
+		if x.Code == upper {
+			return x.Canonical
+		}
+	}
+	return code
+}
+
+// ToExchange returns the code an exchange uses for a canonical currency code,
+// codes without a translation are returned unchanged
+func ToExchange(exchange string, code pair.CurrencyItem) pair.CurrencyItem {
+	exchangeCodesMtx.RLock()
+	defer exchangeCodesMtx.RUnlock()
+
+	upper := code.Upper()
+	for _, x := range exchangeCodes[common.StringToLower(exchange)] {
+		if x.Canonical == upper && !x.ResponseOnly {
+			return x.Code
+		}
+	}
+	return code
+}
+
+// PairToCanonical translates both currencies of an exchange pair to their
+// canonical codes
+func PairToCanonical(exchange string, p pair.CurrencyPair) pair.CurrencyPair {
+	p.FirstCurrency = ToCanonical(exchange, p.FirstCurrency)
+	p.SecondCurrency = ToCanonical(exchange, p.SecondCurrency)
+	return p
+}
+
+// PairToExchange translates both currencies of a canonical pair to the codes
+// used by the exchange
+func PairToExchange(exchange string, p pair.CurrencyPair) pair.CurrencyPair {
+	p.FirstCurrency = ToExchange(exchange, p.FirstCurrency)
+	p.SecondCurrency = ToExchange(exchange, p.SecondCurrency)
+	return p
+}
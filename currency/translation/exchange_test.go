@@ -0,0 +1,62 @@
+package translation
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestToCanonical(t *testing.T) {
+	if actual := ToCanonical("Kraken", "XXBT"); actual != "BTC" {
+		t.Errorf("Test Failed - ToCanonical() expected BTC, got %s", actual)
+	}
+
+	if actual := ToCanonical("bitfinex", "dsh"); actual != "DASH" {
+		t.Errorf("Test Failed - ToCanonical() expected DASH, got %s", actual)
+	}
+
+	if actual := ToCanonical("Bitstamp", "XBT"); actual != "XBT" {
+		t.Errorf("Test Failed - ToCanonical() translated code for another exchange %s", actual)
+	}
+}
+
+func TestToExchange(t *testing.T) {
+	if actual := ToExchange("Kraken", "btc"); actual != "XBT" {
+		t.Errorf("Test Failed - ToExchange() expected XBT, got %s", actual)
+	}
+
+	if actual := ToExchange("Poloniex", "XLM"); actual != "STR" {
+		t.Errorf("Test Failed - ToExchange() expected STR, got %s", actual)
+	}
+
+	if actual := ToExchange("Kraken", "USD"); actual != "USD" {
+		t.Errorf("Test Failed - ToExchange() used response only code %s", actual)
+	}
+
+	if actual := ToExchange("Kraken", "NEO"); actual != "NEO" {
+		t.Errorf("Test Failed - ToExchange() expected NEO, got %s", actual)
+	}
+}
+
+func TestPairTranslation(t *testing.T) {
+	p := PairToCanonical("Kraken", pair.NewCurrencyPairDelimiter("XDG-XBT", "-"))
+	if p.Pair() != "DOGE-BTC" {
+		t.Errorf("Test Failed - PairToCanonical() unexpected pair %s", p.Pair())
+	}
+
+	p = PairToExchange("Kraken", p)
+	if p.Pair() != "XDG-XBT" {
+		t.Errorf("Test Failed - PairToExchange() unexpected pair %s", p.Pair())
+	}
+}
+
+func TestSetExchangeCodes(t *testing.T) {
+	SetExchangeCodes("TestExchange", []ExchangeCode{{Code: "BCC", Canonical: "BCH"}})
+	if actual := ToCanonical("testexchange", "BCC"); actual != "BCH" {
+		t.Errorf("Test Failed - SetExchangeCodes() expected BCH, got %s", actual)
+	}
+
+	if len(GetExchangeCodes("TESTEXCHANGE")) != 1 {
+		t.Error("Test Failed - GetExchangeCodes() unexpected codes")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/communications"
@@ -16,6 +17,7 @@ import (
 
 const (
 	itemPrice          = "PRICE"
+	itemPriceChange    = "PRICE_CHANGE"
 	greaterThan        = ">"
 	greaterThanOrEqual = ">="
 	lessThan           = "<"
@@ -129,6 +131,9 @@ func (e *Event) ExecuteAction() bool {
 // EventToString turns the structure event into a string
 func (e *Event) String() string {
 	condition := common.SplitStrings(e.Condition, ",")
+	if len(condition) > 2 {
+		condition[1] += "% over " + condition[2]
+	}
 	return fmt.Sprintf(
 		"If the %s%s [%s] %s on %s is %s then %s.", e.Pair.FirstCurrency.String(),
 		e.Pair.SecondCurrency.String(), e.Asset, e.Item, e.Exchange, condition[0]+" "+condition[1], e.Action,
@@ -136,20 +141,34 @@ func (e *Event) String() string {
 }
 
 // CheckCondition will check the event structure to see if there is a condition
-// met
+// met. Price change events compare the percentage change of the last price
+// over their window, taken from the ticker history
 func (e *Event) CheckCondition() bool {
 	condition := common.SplitStrings(e.Condition, ",")
 	targetPrice, _ := strconv.ParseFloat(condition[1], 64)
 
-	t, err := ticker.GetTicker(e.Exchange, e.Pair, e.Asset)
-	if err != nil {
-		return false
-	}
+	var lastPrice float64
+	if common.StringToUpper(e.Item) == itemPriceChange {
+		window, err := time.ParseDuration(condition[2])
+		if err != nil {
+			return false
+		}
 
-	lastPrice := t.Last
+		lastPrice, err = ticker.GetTickerChange(e.Exchange, e.Pair, e.Asset, window)
+		if err != nil {
+			return false
+		}
+	} else {
+		t, err := ticker.GetTickerOrSynthetic(e.Exchange, e.Pair, e.Asset)
+		if err != nil {
+			return false
+		}
 
-	if lastPrice == 0 {
-		return false
+		lastPrice = t.Last
+
+		if lastPrice == 0 {
+			return false
+		}
 	}
 
 	switch condition[0] {
@@ -211,6 +230,17 @@ func IsValidEvent(Exchange, Item, Condition, Action string) error {
 		return errInvalidCondition
 	}
 
+	// Price change conditions also take the window of the change, e.g.
+	// ">=,5,15m" for a rise of at least 5% over 15 minutes
+	if Item == itemPriceChange {
+		if len(condition) != 3 {
+			return errInvalidCondition
+		}
+		if window, err := time.ParseDuration(condition[2]); err != nil || window <= 0 {
+			return errInvalidCondition
+		}
+	}
+
 	if common.StringContains(Action, ",") {
 		action := common.SplitStrings(Action, ",")
 
@@ -286,7 +316,7 @@ func IsValidAction(Action string) bool {
 func IsValidItem(Item string) bool {
 	Item = common.StringToUpper(Item)
 	switch Item {
-	case itemPrice:
+	case itemPrice, itemPriceChange:
 		return true
 	}
 	return false
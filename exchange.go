@@ -1,51 +1,169 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
-	"github.com/thrasher-/gocryptotrader/exchanges/anx"
-	"github.com/thrasher-/gocryptotrader/exchanges/binance"
-	"github.com/thrasher-/gocryptotrader/exchanges/bitfinex"
-	"github.com/thrasher-/gocryptotrader/exchanges/bitflyer"
-	"github.com/thrasher-/gocryptotrader/exchanges/bithumb"
-	"github.com/thrasher-/gocryptotrader/exchanges/bitmex"
-	"github.com/thrasher-/gocryptotrader/exchanges/bitstamp"
-	"github.com/thrasher-/gocryptotrader/exchanges/bittrex"
-	"github.com/thrasher-/gocryptotrader/exchanges/btcc"
-	"github.com/thrasher-/gocryptotrader/exchanges/btcmarkets"
-	"github.com/thrasher-/gocryptotrader/exchanges/coinbasepro"
-	"github.com/thrasher-/gocryptotrader/exchanges/coinut"
-	"github.com/thrasher-/gocryptotrader/exchanges/exmo"
-	"github.com/thrasher-/gocryptotrader/exchanges/gateio"
-	"github.com/thrasher-/gocryptotrader/exchanges/gemini"
-	"github.com/thrasher-/gocryptotrader/exchanges/hitbtc"
-	"github.com/thrasher-/gocryptotrader/exchanges/huobi"
-	"github.com/thrasher-/gocryptotrader/exchanges/huobihadax"
-	"github.com/thrasher-/gocryptotrader/exchanges/itbit"
-	"github.com/thrasher-/gocryptotrader/exchanges/kraken"
-	"github.com/thrasher-/gocryptotrader/exchanges/lakebtc"
-	"github.com/thrasher-/gocryptotrader/exchanges/liqui"
-	"github.com/thrasher-/gocryptotrader/exchanges/localbitcoins"
-	"github.com/thrasher-/gocryptotrader/exchanges/okcoin"
-	"github.com/thrasher-/gocryptotrader/exchanges/okex"
-	"github.com/thrasher-/gocryptotrader/exchanges/poloniex"
-	"github.com/thrasher-/gocryptotrader/exchanges/wex"
-	"github.com/thrasher-/gocryptotrader/exchanges/yobit"
-	"github.com/thrasher-/gocryptotrader/exchanges/zb"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
+	"github.com/thrasher-/gocryptotrader/portfolio"
+
+	// Exchanges register themselves with the exchange registry when imported
+	_ "github.com/thrasher-/gocryptotrader/exchanges/anx"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/binance"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bitfinex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bitflyer"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bithumb"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bitmex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bitstamp"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bittrex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/btcc"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/btcmarkets"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/coinbasepro"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/coinut"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/exmo"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/gateio"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/gemini"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/hitbtc"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/huobi"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/huobihadax"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/itbit"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/kraken"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/lakebtc"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/liqui"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/localbitcoins"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/okcoin"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/okex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/poloniex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/virtual"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/wex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/yobit"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/zb"
 )
 
 // vars related to exchange functions
 var (
-	ErrNoExchangesLoaded     = errors.New("no exchanges have been loaded")
-	ErrExchangeNotFound      = errors.New("exchange not found")
-	ErrExchangeAlreadyLoaded = errors.New("exchange already loaded")
-	ErrExchangeFailedToLoad  = errors.New("exchange failed to load")
+	ErrNoExchangesLoaded        = errors.New("no exchanges have been loaded")
+	ErrExchangeNotFound         = errors.New("exchange not found")
+	ErrExchangeAlreadyLoaded    = errors.New("exchange already loaded")
+	ErrExchangeFailedToLoad     = errors.New("exchange failed to load")
+	ErrTradePermissionDenied    = errors.New("exchange API key is missing trade permissions")
+	ErrWithdrawPermissionDenied = errors.New("exchange API key is missing withdraw permissions")
+)
+
+// exchangeContext holds the context the routines of a loaded exchange run
+// under, cancelled when the exchange is unloaded
+type exchangeContext struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// vars for the contexts of loaded exchanges
+var (
+	exchangeContexts    = make(map[string]exchangeContext)
+	exchangeContextsMtx sync.Mutex
 )
 
+// ExchangeFailure holds the error an exchange failed to load with, the
+// exchange is left unloaded while the others keep running
+type ExchangeFailure struct {
+	ExchangeName string    `json:"exchangeName"`
+	Error        string    `json:"error"`
+	Time         time.Time `json:"time"`
+}
+
+// vars for the exchanges which failed to load
+var (
+	exchangeFailures    = make(map[string]ExchangeFailure)
+	exchangeFailuresMtx sync.Mutex
+)
+
+// recordExchangeFailure records the error an exchange failed to load with
+func recordExchangeFailure(name string, err error) {
+	exchangeFailuresMtx.Lock()
+	exchangeFailures[common.StringToLower(name)] = ExchangeFailure{
+		ExchangeName: name,
+		Error:        err.Error(),
+		Time:         time.Now(),
+	}
+	exchangeFailuresMtx.Unlock()
+}
+
+// clearExchangeFailure removes the failure of an exchange once it has loaded
+func clearExchangeFailure(name string) {
+	exchangeFailuresMtx.Lock()
+	delete(exchangeFailures, common.StringToLower(name))
+	exchangeFailuresMtx.Unlock()
+}
+
+// GetExchangeFailures returns the exchanges which failed to load sorted by
+// name
+func GetExchangeFailures() []ExchangeFailure {
+	exchangeFailuresMtx.Lock()
+	defer exchangeFailuresMtx.Unlock()
+
+	failures := make([]ExchangeFailure, 0, len(exchangeFailures))
+	for _, x := range exchangeFailures {
+		failures = append(failures, x)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].ExchangeName < failures[j].ExchangeName
+	})
+	return failures
+}
+
+// newExchangeContext returns a context for the routines of an exchange which
+// is cancelled when the exchange is unloaded or the bot shuts down
+func newExchangeContext(name string) context.Context {
+	parent := bot.ctx
+	if parent == nil {
+		// Exchanges loaded outside of the bot lifecycle are never cancelled
+		parent = context.Background()
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	exchangeContextsMtx.Lock()
+	if existing, ok := exchangeContexts[common.StringToLower(name)]; ok {
+		existing.cancel()
+	}
+	exchangeContexts[common.StringToLower(name)] = exchangeContext{ctx, cancel}
+	exchangeContextsMtx.Unlock()
+	return ctx
+}
+
+// getExchangeContext returns the context for the routines of a loaded
+// exchange
+func getExchangeContext(name string) context.Context {
+	exchangeContextsMtx.Lock()
+	defer exchangeContextsMtx.Unlock()
+
+	if e, ok := exchangeContexts[common.StringToLower(name)]; ok {
+		return e.ctx
+	}
+	if bot.ctx != nil {
+		return bot.ctx
+	}
+	return context.Background()
+}
+
+// cancelExchangeContext stops the routines of an exchange
+func cancelExchangeContext(name string) {
+	exchangeContextsMtx.Lock()
+	if e, ok := exchangeContexts[common.StringToLower(name)]; ok {
+		e.cancel()
+		delete(exchangeContexts, common.StringToLower(name))
+	}
+	exchangeContextsMtx.Unlock()
+}
+
 // CheckExchangeExists returns true whether or not an exchange has already
 // been loaded
 func CheckExchangeExists(exchName string) bool {
@@ -85,7 +203,17 @@ func ReloadExchange(name string) error {
 	}
 
 	e := GetExchangeByName(nameLower)
-	e.Setup(exchCfg)
+	err = e.Setup(exchCfg)
+	if err == nil {
+		err = e.SetTLSConfig(exchCfg.TLS)
+	}
+	if err != nil {
+		err = fmt.Errorf("%s setup failed: %s", name, err)
+		recordExchangeFailure(name, err)
+		unloadExchange(e.GetName())
+		return err
+	}
+	clearExchangeFailure(name)
 	log.Printf("%s exchange reloaded successfully.\n", name)
 	return nil
 }
@@ -112,10 +240,23 @@ func UnloadExchange(name string) error {
 	if err != nil {
 		return err
 	}
+	return unloadExchange(name)
+}
 
+// unloadExchange stops an exchange and removes it from the loaded exchanges
+// without changing its config
+func unloadExchange(name string) error {
 	for x := range bot.exchanges {
 		if bot.exchanges[x].GetName() == name {
 			bot.exchanges[x].SetEnabled(false)
+			cancelExchangeContext(name)
+			kline.SetBuilder(name, nil)
+			if ws, err := bot.exchanges[x].GetWebsocket(); err == nil {
+				if ws.IsEnabled() {
+					ws.Shutdown()
+				}
+				ws.Orderbook.StopRecording()
+			}
 			bot.exchanges = append(bot.exchanges[:x], bot.exchanges[x+1:]...)
 			return nil
 		}
@@ -124,10 +265,76 @@ func UnloadExchange(name string) error {
 	return ErrExchangeNotFound
 }
 
+// EnableExchange loads and enables an exchange while the bot is running,
+// starting its websocket and saving it as enabled in the config
+func EnableExchange(name string) error {
+	err := LoadExchange(name, false, nil)
+	if err != nil {
+		return err
+	}
+
+	exchCfg, err := bot.config.GetExchangeConfig(name)
+	if err != nil {
+		return err
+	}
+
+	exchCfg.Enabled = true
+	err = bot.config.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		return err
+	}
+
+	exch := GetExchangeByName(name)
+	skew, err := exchange.MeasureClockSkew(exch)
+	if err == nil {
+		exch.SetClockSkew(skew)
+	}
+
+	StartExchangeWebsocket(getExchangeContext(name), exch, bot.verbose)
+	log.Printf("%s exchange enabled.\n", exch.GetName())
+	return nil
+}
+
+// DisableExchange stops and unloads an exchange while the bot is running,
+// saving it as disabled in the config. When cancelOrders is set the open
+// orders submitted by the bot to the exchange are cancelled first and the
+// exchange is left enabled if any fail to cancel
+func DisableExchange(name string, cancelOrders bool) error {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return ErrExchangeNotFound
+	}
+
+	if cancelOrders {
+		managed := orders.GetClientOrders(exch.GetName(), orders.ClientOrderSubmitted)
+		var failed int
+		for x := range managed {
+			err := CancelExchangeOrderByClientID(exch.GetName(), managed[x].ClientID)
+			if err != nil {
+				log.Printf("%s failed to cancel order with client order ID %s. Error: %s",
+					exch.GetName(), managed[x].ClientID, err)
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%s failed to cancel %d of %d orders",
+				exch.GetName(), failed, len(managed))
+		}
+	}
+
+	err := UnloadExchange(exch.GetName())
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%s exchange disabled.\n", exch.GetName())
+	return nil
+}
+
 // LoadExchange loads an exchange by name
 func LoadExchange(name string, useWG bool, wg *sync.WaitGroup) error {
 	nameLower := common.StringToLower(name)
-	var exch exchange.IBotExchange
 
 	if len(bot.exchanges) > 0 {
 		if CheckExchangeExists(nameLower) {
@@ -135,68 +342,8 @@ func LoadExchange(name string, useWG bool, wg *sync.WaitGroup) error {
 		}
 	}
 
-	switch nameLower {
-	case "anx":
-		exch = new(anx.ANX)
-	case "binance":
-		exch = new(binance.Binance)
-	case "bitfinex":
-		exch = new(bitfinex.Bitfinex)
-	case "bitflyer":
-		exch = new(bitflyer.Bitflyer)
-	case "bithumb":
-		exch = new(bithumb.Bithumb)
-	case "bitmex":
-		exch = new(bitmex.Bitmex)
-	case "bitstamp":
-		exch = new(bitstamp.Bitstamp)
-	case "bittrex":
-		exch = new(bittrex.Bittrex)
-	case "btcc":
-		exch = new(btcc.BTCC)
-	case "btc markets":
-		exch = new(btcmarkets.BTCMarkets)
-	case "coinut":
-		exch = new(coinut.COINUT)
-	case "exmo":
-		exch = new(exmo.EXMO)
-	case "coinbasepro":
-		exch = new(coinbasepro.CoinbasePro)
-	case "gateio":
-		exch = new(gateio.Gateio)
-	case "gemini":
-		exch = new(gemini.Gemini)
-	case "hitbtc":
-		exch = new(hitbtc.HitBTC)
-	case "huobi":
-		exch = new(huobi.HUOBI)
-	case "huobihadax":
-		exch = new(huobihadax.HUOBIHADAX)
-	case "itbit":
-		exch = new(itbit.ItBit)
-	case "kraken":
-		exch = new(kraken.Kraken)
-	case "lakebtc":
-		exch = new(lakebtc.LakeBTC)
-	case "liqui":
-		exch = new(liqui.Liqui)
-	case "localbitcoins":
-		exch = new(localbitcoins.LocalBitcoins)
-	case "okcoin china":
-		exch = new(okcoin.OKCoin)
-	case "okcoin international":
-		exch = new(okcoin.OKCoin)
-	case "okex":
-		exch = new(okex.OKEX)
-	case "poloniex":
-		exch = new(poloniex.Poloniex)
-	case "wex":
-		exch = new(wex.WEX)
-	case "yobit":
-		exch = new(yobit.Yobit)
-	case "zb":
-		exch = new(zb.ZB)
-	default:
+	exch, err := exchange.NewExchangeByName(nameLower)
+	if err != nil {
 		return ErrExchangeNotFound
 	}
 
@@ -205,25 +352,271 @@ func LoadExchange(name string, useWG bool, wg *sync.WaitGroup) error {
 	}
 
 	exch.SetDefaults()
-	bot.exchanges = append(bot.exchanges, exch)
 	exchCfg, err := bot.config.GetExchangeConfig(name)
 	if err != nil {
 		return err
 	}
 
 	exchCfg.Enabled = true
-	exch.Setup(exchCfg)
+	err = exch.Setup(exchCfg)
+	if err == nil {
+		// Exchanges behind mTLS proxies can't be reached without their TLS
+		// config, so failing to apply it fails the setup
+		err = exch.SetTLSConfig(exchCfg.TLS)
+	}
+	if err != nil {
+		err = fmt.Errorf("%s setup failed: %s", exchCfg.Name, err)
+		recordExchangeFailure(exchCfg.Name, err)
+		return err
+	}
+	clearExchangeFailure(exchCfg.Name)
+	bot.exchanges = append(bot.exchanges, exch)
+
+	// Requests are cancelled when the exchange is unloaded or the bot shuts
+	// down
+	ctx := newExchangeContext(exchCfg.Name)
+	exch.SetRequestContext(ctx)
+	if exchCfg.FeeDiscount != nil {
+		exch.SetFeeDiscount(*exchCfg.FeeDiscount)
+	}
+	if exchCfg.Chaos != nil {
+		exch.SetChaos(*exchCfg.Chaos)
+	}
+	if exchCfg.BrokerID != "" {
+		err = exch.SetBrokerID(exchCfg.BrokerID)
+		if err != nil {
+			log.Printf("%s unable to tag orders with broker ID. Error: %s",
+				exchCfg.Name, err)
+		}
+	}
+	verifyExchangeCredentials(exch)
+
+	exch.SetAssetMetadataOverrides(exchCfg.AssetMetadata)
+	err = exch.UpdateAssetMetadata()
+	if err != nil && err != exchange.ErrAssetMetadataNotSupported {
+		log.Printf("%s unable to fetch asset metadata, using defaults. Error: %s",
+			exchCfg.Name, err)
+	}
+
+	intervals, err := kline.ParseIntervals(exchCfg.CandleIntervals)
+	if err != nil {
+		log.Printf("%s invalid candle intervals %s. Error: %s",
+			exchCfg.Name, exchCfg.CandleIntervals, err)
+	}
+	kline.SetBuilder(exchCfg.Name, intervals)
+
+	if exchCfg.WebsocketSubscriptions != nil {
+		warnUnsupportedWebsocketSubscriptions(exch)
+	}
+
+	if exchCfg.OrderbookRecordPath != "" {
+		err = startOrderbookRecording(exch, exchCfg.OrderbookRecordPath)
+		if err != nil {
+			log.Printf("%s unable to record websocket orderbooks. Error: %s",
+				exchCfg.Name, err)
+		}
+	}
 
 	if useWG {
-		exch.Start(wg)
+		exch.Start(ctx, wg)
 	} else {
 		wg := sync.WaitGroup{}
-		exch.Start(&wg)
+		exch.Start(ctx, &wg)
 		wg.Wait()
 	}
 	return nil
 }
 
+// warnUnsupportedWebsocketSubscriptions logs a warning when websocket
+// subscriptions are configured for an exchange whose websocket doesn't manage
+// its subscriptions, as the configuration is ignored
+func warnUnsupportedWebsocketSubscriptions(exch exchange.IBotExchange) {
+	ws, err := exch.GetWebsocket()
+	if err == nil && ws.SupportsSubscriptions() {
+		return
+	}
+	log.Printf("%s websocket subscriptions configured but not supported by the exchange websocket, ignoring them.\n",
+		exch.GetName())
+}
+
+// verifyExchangeCredentials confirms the API credentials of an exchange with
+// authenticated API support work before it starts, the exchange disables its
+// authenticated API support if they are rejected
+func verifyExchangeCredentials(exch exchange.IBotExchange) {
+	if !exch.GetAuthenticatedAPISupport() {
+		return
+	}
+
+	err := exch.VerifyAPICredentials(func() error {
+		_, err := exch.GetExchangeAccountInfo()
+		return err
+	})
+	if err != nil {
+		log.Println(err)
+		if bot.comms != nil {
+			bot.comms.PushEvent(base.Event{
+				Type:         "api_credentials_rejected",
+				TradeDetails: err.Error(),
+			})
+		}
+		return
+	}
+
+	if !exch.GetAPIPermissions().Has(exchange.APIPermissionTrade) {
+		log.Printf("%s API key is missing trade permissions, order submission disabled.\n",
+			exch.GetName())
+	}
+}
+
+// ReconcileExchangeOrders reconciles the open orders of each authenticated
+// exchange against the client order registry, adopting unknown open orders
+// when adopt is set and alerting on unknown and missing orders otherwise.
+// Exchanges which can't list their open orders are skipped
+func ReconcileExchangeOrders(adopt bool) {
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil || !bot.exchanges[x].GetAuthenticatedAPISupport() {
+			continue
+		}
+
+		openOrders, err := bot.exchanges[x].GetExchangeOpenOrders()
+		if err != nil {
+			continue
+		}
+
+		open := make([]orders.OpenOrder, len(openOrders))
+		for y := range openOrders {
+			open[y] = orders.OpenOrder{
+				OrderID:  openOrders[y].ID,
+				ClientID: openOrders[y].ClientID,
+			}
+		}
+
+		exchName := bot.exchanges[x].GetName()
+		result := orders.ReconcileOpenOrders(exchName, open, adopt)
+		log.Printf("%s reconciled open orders - matched: %d adopted: %d unknown: %d missing: %d unresolved: %d.\n",
+			exchName, len(result.Matched), len(result.Adopted), len(result.Unknown),
+			len(result.Missing), len(result.Unresolved))
+
+		if bot.comms == nil {
+			continue
+		}
+
+		if len(result.Unknown) > 0 {
+			bot.comms.PushEvent(base.Event{
+				Type: "unknown_open_orders",
+				TradeDetails: fmt.Sprintf("%s has %d open orders not submitted by the bot",
+					exchName, len(result.Unknown)),
+			})
+		}
+
+		if len(result.Missing) > 0 {
+			bot.comms.PushEvent(base.Event{
+				Type: "missing_orders",
+				TradeDetails: fmt.Sprintf("%s has %d submitted orders no longer open on the exchange",
+					exchName, len(result.Missing)),
+			})
+		}
+
+		if len(result.Unresolved) > 0 {
+			bot.comms.PushEvent(base.Event{
+				Type: "unresolved_orders",
+				TradeDetails: fmt.Sprintf("%s has %d orders with an unknown submission outcome not open on the exchange",
+					exchName, len(result.Unresolved)),
+			})
+		}
+	}
+}
+
+// LoadWithdrawalLimits fetches the withdrawal limits of each authenticated
+// exchange without configured limits. Exchanges which can't report their
+// limits are skipped
+func LoadWithdrawalLimits() {
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil || !bot.exchanges[x].GetAuthenticatedAPISupport() {
+			continue
+		}
+
+		exchName := bot.exchanges[x].GetName()
+		if len(portfolio.GetWithdrawalLimits(exchName)) > 0 {
+			continue
+		}
+
+		fetched, err := bot.exchanges[x].GetWithdrawalLimits()
+		if err != nil {
+			continue
+		}
+
+		limits := make([]portfolio.WithdrawalLimit, len(fetched))
+		for y := range fetched {
+			limits[y] = portfolio.WithdrawalLimit{
+				Currency: fetched[y].Currency.String(),
+				Amount:   fetched[y].Amount,
+				Window:   fetched[y].Window,
+			}
+		}
+
+		err = portfolio.SetExchangeWithdrawalLimits(exchName, limits)
+		if err != nil {
+			log.Printf("%s unable to set withdrawal limits: %s\n", exchName, err)
+			continue
+		}
+		log.Printf("%s loaded %d withdrawal limits.\n", exchName, len(limits))
+	}
+}
+
+// UpdateFeeDiscounts refreshes the fee token discount of each exchange,
+// seeding the token balance from the portfolio before exchanges which can
+// detect the discount mode and balance from their API update it
+func UpdateFeeDiscounts() {
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+
+		exchName := bot.exchanges[x].GetName()
+		discount, err := bot.exchanges[x].GetFeeDiscount()
+		if err == nil {
+			balance, ok := GetExchangeBalance(exchName, discount.Token)
+			if ok {
+				bot.exchanges[x].SetFeeTokenBalance(balance)
+			}
+		}
+
+		if !bot.exchanges[x].GetAuthenticatedAPISupport() {
+			continue
+		}
+
+		discount, err = bot.exchanges[x].UpdateFeeDiscount()
+		if err != nil {
+			if err != exchange.ErrFeeDiscountNotConfigured {
+				log.Printf("%s unable to update fee discount: %s\n", exchName, err)
+			}
+			continue
+		}
+
+		log.Printf("%s fee discount paying in %s: %s (rate: %v, balance: %v).\n",
+			exchName, discount.Token, common.IsEnabled(discount.Enabled),
+			discount.Rate, discount.Balance)
+	}
+}
+
+// startOrderbookRecording archives the websocket orderbook snapshots and deltas
+// of an exchange to the file at path for later replay
+func startOrderbookRecording(exch exchange.IBotExchange, path string) error {
+	ws, err := exch.GetWebsocket()
+	if err != nil {
+		return err
+	}
+
+	recorder, err := exchange.NewOrderbookRecorder(path)
+	if err != nil {
+		return err
+	}
+
+	ws.Orderbook.SetRecorder(recorder)
+	return nil
+}
+
 // SetupExchanges sets up the exchanges used by the bot
 func SetupExchanges() {
 	var wg sync.WaitGroup
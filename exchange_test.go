@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/thrasher-/gocryptotrader/config"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 )
 
 var testSetup = false
@@ -131,8 +132,127 @@ func TestUnloadExchange(t *testing.T) {
 	CleanupTest(t)
 }
 
+func TestEnableDisableExchange(t *testing.T) {
+	SetupTest(t)
+
+	err := DisableExchange("asdf", false)
+	if err != ErrExchangeNotFound {
+		t.Errorf("Test failed. TestEnableDisableExchange: Incorrect result: %s",
+			err)
+	}
+
+	err = DisableExchange("Bitfinex", true)
+	if err != nil {
+		t.Errorf("Test failed. TestEnableDisableExchange: Failed to disable exchange. %s",
+			err)
+	}
+
+	if CheckExchangeExists("Bitfinex") {
+		t.Error("Test failed. TestEnableDisableExchange: Exchange still loaded")
+	}
+
+	err = EnableExchange("Bitfinex")
+	if err != nil {
+		t.Errorf("Test failed. TestEnableDisableExchange: Failed to enable exchange. %s",
+			err)
+	}
+
+	err = EnableExchange("Bitfinex")
+	if err != ErrExchangeAlreadyLoaded {
+		t.Errorf("Test failed. TestEnableDisableExchange: Incorrect result: %s",
+			err)
+	}
+
+	CleanupTest(t)
+}
+
 func TestSetupExchanges(t *testing.T) {
 	SetupTest(t)
 	SetupExchanges()
 	CleanupTest(t)
 }
+
+func TestExchangesRegistered(t *testing.T) {
+	SetupTest(t)
+
+	for x := range bot.config.Exchanges {
+		if !exchange.IsRegistered(bot.config.Exchanges[x].Name) {
+			t.Errorf("Test failed. TestExchangesRegistered: %s not registered",
+				bot.config.Exchanges[x].Name)
+		}
+	}
+
+	if err := LoadExchange("Asdsad", false, nil); err != ErrExchangeNotFound {
+		t.Errorf("Test failed. TestExchangesRegistered: Expected %s, got %v",
+			ErrExchangeNotFound, err)
+	}
+}
+
+func TestExchangePairFormatRoundTrip(t *testing.T) {
+	SetupTest(t)
+
+	for x := range bot.config.Exchanges {
+		exchCfg := bot.config.Exchanges[x]
+		exchCfg.Enabled = true
+		exch, err := exchange.NewExchangeByName(exchCfg.Name)
+		if err != nil {
+			t.Fatalf("Test failed. TestExchangePairFormatRoundTrip: %s", err)
+		}
+		exch.SetDefaults()
+		if err = exch.Setup(exchCfg); err != nil {
+			t.Errorf("Test failed. TestExchangePairFormatRoundTrip: %s setup error %s",
+				exchCfg.Name, err)
+			continue
+		}
+
+		for _, p := range exch.GetEnabledCurrencies() {
+			for _, context := range []string{exchange.PairFormatRequest, exchange.PairFormatConfig} {
+				formatted := exch.FormatPair(p, context)
+				parsed, err := exch.ParsePair(formatted, context)
+				if err != nil || !parsed.Equal(p, false) || exch.FormatPair(parsed, context) != formatted {
+					t.Errorf("Test failed. TestExchangePairFormatRoundTrip: %s %s round trip of %s via %s returned %s %v",
+						exchCfg.Name, context, p.Pair(), formatted, parsed.Pair(), err)
+				}
+			}
+		}
+	}
+}
+
+func TestLoadExchangeSetupFailure(t *testing.T) {
+	SetupTest(t)
+	CleanupTest(t)
+
+	exchCfg, err := bot.config.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatalf("Test failed. TestLoadExchangeSetupFailure: %s", err)
+	}
+
+	proxyAddress := exchCfg.ProxyAddress
+	exchCfg.ProxyAddress = "http://[::1"
+	bot.config.UpdateExchangeConfig(exchCfg)
+
+	err = LoadExchange("Bitfinex", false, nil)
+	if err == nil {
+		t.Error("Test failed. TestLoadExchangeSetupFailure: Invalid config loaded")
+	}
+
+	if CheckExchangeExists("Bitfinex") {
+		t.Error("Test failed. TestLoadExchangeSetupFailure: Failed exchange loaded")
+	}
+
+	failures := GetExchangeFailures()
+	if len(failures) != 1 || failures[0].ExchangeName != "Bitfinex" {
+		t.Errorf("Test failed. TestLoadExchangeSetupFailure: Unexpected failures %v",
+			failures)
+	}
+
+	exchCfg.ProxyAddress = proxyAddress
+	bot.config.UpdateExchangeConfig(exchCfg)
+	SetupTest(t)
+
+	if len(GetExchangeFailures()) != 0 {
+		t.Error("Test failed. TestLoadExchangeSetupFailure: Failure not cleared")
+	}
+
+	CleanupTest(t)
+}
@@ -52,6 +52,10 @@ type Alphapoint struct {
 func (a *Alphapoint) SetDefaults() {
 	a.APIUrl = alphapointDefaultAPIURL
 	a.WebsocketURL = alphapointDefaultWebsocketURL
+	a.RequestCurrencyPairFormat.Delimiter = ""
+	a.RequestCurrencyPairFormat.Uppercase = true
+	a.ConfigCurrencyPairFormat.Delimiter = ""
+	a.ConfigCurrencyPairFormat.Uppercase = true
 	a.AssetTypes = []string{ticker.Spot}
 	a.SupportsAutoPairUpdating = false
 	a.SupportsRESTTickerBatching = false
@@ -533,7 +537,7 @@ func (a *Alphapoint) SendAuthenticatedHTTPRequest(method, path string, data map[
 	}
 
 	if a.Nonce.Get() == 0 {
-		a.Nonce.Set(time.Now().UnixNano())
+		a.Nonce.Set(a.GetAdjustedTime().UnixNano())
 	} else {
 		a.Nonce.Inc()
 	}
@@ -504,3 +504,17 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestConvertAccountCurrencies(t *testing.T) {
+	var account AccountInfo
+	err := common.JSONDecode([]byte(`{"currencies":[{"name":"BTC","balance":5,"hold":2}]}`), &account)
+	if err != nil {
+		t.Fatal("Test Failed - unable to decode account", err)
+	}
+
+	currencies := convertAccountCurrencies(account)
+	if len(currencies) != 1 || currencies[0].CurrencyName != "BTC" || currencies[0].Free != 3 ||
+		currencies[0].Locked != 2 || currencies[0].Total != 5 {
+		t.Errorf("Test Failed - convertAccountCurrencies() unexpected result %+v", currencies)
+	}
+}
@@ -16,6 +16,7 @@ const (
 func (a *Alphapoint) WebsocketClient() {
 	for a.Enabled {
 		var Dialer websocket.Dialer
+		Dialer.TLSClientConfig = a.GetTLSConfig()
 		var err error
 		a.WebsocketConn, _, err = Dialer.Dial(a.WebsocketURL, http.Header{})
 
@@ -36,7 +37,7 @@ func (a *Alphapoint) WebsocketClient() {
 		}
 
 		for a.Enabled {
-			msgType, resp, err := a.WebsocketConn.ReadMessage()
+			msgType, resp, err := a.Websocket.ReadMessage(a.WebsocketConn)
 			if err != nil {
 				log.Println(err)
 				break
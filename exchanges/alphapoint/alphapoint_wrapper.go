@@ -2,14 +2,21 @@ package alphapoint
 
 import (
 	"errors"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
 // GetExchangeAccountInfo retrieves balances for all enabled currencies on the
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (a *Alphapoint) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // Alphapoint exchange
 func (a *Alphapoint) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	var response exchange.AccountInfo
@@ -18,22 +25,25 @@ func (a *Alphapoint) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	if err != nil {
 		return response, err
 	}
-	for i := 0; i < len(account.Currencies); i++ {
-		var exchangeCurrency exchange.AccountCurrencyInfo
-		exchangeCurrency.CurrencyName = account.Currencies[i].Name
-		exchangeCurrency.TotalValue = float64(account.Currencies[i].Balance)
-		exchangeCurrency.Hold = float64(account.Currencies[i].Hold)
+	response.Currencies = convertAccountCurrencies(account)
+	return response, nil
+}
 
-		response.Currencies = append(response.Currencies, exchangeCurrency)
+// convertAccountCurrencies converts account balances into account currency
+// info, Alphapoint balances include the amount on hold
+func convertAccountCurrencies(account AccountInfo) []exchange.AccountCurrencyInfo {
+	var currencies []exchange.AccountCurrencyInfo
+	for _, c := range account.Currencies {
+		currencies = append(currencies, exchange.NewAccountCurrencyInfo(c.Name,
+			float64(c.Balance-c.Hold), float64(c.Hold)))
 	}
-	//If it all works out
-	return response, nil
+	return currencies
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
 func (a *Alphapoint) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
-	tick, err := a.GetTicker(p.Pair().String())
+	tick, err := a.GetTicker(a.FormatPair(p, exchange.PairFormatRequest))
 	if err != nil {
 		return tickerPrice, err
 	}
@@ -61,7 +71,7 @@ func (a *Alphapoint) GetTickerPrice(p pair.CurrencyPair, assetType string) (tick
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (a *Alphapoint) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
-	orderbookNew, err := a.GetOrderbook(p.Pair().String())
+	orderbookNew, err := a.GetOrderbook(a.FormatPair(p, exchange.PairFormatRequest))
 	if err != nil {
 		return orderBook, err
 	}
@@ -76,7 +86,7 @@ func (a *Alphapoint) UpdateOrderbook(p pair.CurrencyPair, assetType string) (ord
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: data.Quantity, Price: data.Price})
 	}
 
-	orderbook.ProcessOrderbook(a.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(a.GetName(), p, a.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(a.Name, p, assetType)
 }
 
@@ -96,6 +106,18 @@ func (a *Alphapoint) GetExchangeFundTransferHistory() ([]exchange.FundHistory, e
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (a *Alphapoint) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (a *Alphapoint) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (a *Alphapoint) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -103,29 +125,47 @@ func (a *Alphapoint) GetExchangeHistory(p pair.CurrencyPair, assetType string) (
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (a *Alphapoint) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order and returns a true value when
 // successfully submitted
 func (a *Alphapoint) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
-	//return a.CreateOrder(p.Pair().String(), side, orderType, amount, price)
+	//return a.CreateOrder(a.FormatPair(p, exchange.PairFormatRequest), side, orderType, amount, price)
 	return 0, errors.New("not yet implemented")
 }
 
 // ModifyExchangeOrder will allow of changing orderbook placement and limit to
 // market conversion
 func (a *Alphapoint) ModifyExchangeOrder(orderID int64, action exchange.ModifyOrder) (int64, error) {
-	//return a.ModifyOrder(p.Pair().String(), orderID, action)
+	//return a.ModifyOrder(a.FormatPair(p, exchange.PairFormatRequest), orderID, action)
 	return 0, errors.New("not yet implemented")
 }
 
 // CancelExchangeOrder cancels an order by its corresponding ID number
 func (a *Alphapoint) CancelExchangeOrder(orderID int64) error {
-	//return a.CancelOrder(p.Pair().String(), orderID)
+	//return a.CancelOrder(a.FormatPair(p, exchange.PairFormatRequest), orderID)
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (a *Alphapoint) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, a.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (a *Alphapoint) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, a.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (a *Alphapoint) CancelAllExchangeOrders() error {
-	//return a.CancelAllOrders(p.Pair().String())
+	//return a.CancelAllOrders(a.FormatPair(p, exchange.PairFormatRequest))
 	return errors.New("not yet implemented")
 }
 
@@ -146,6 +186,11 @@ func (a *Alphapoint) GetExchangeOrderInfo(orderID int64) (float64, error) {
 	return 0, errors.New("order not found")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (a *Alphapoint) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (a *Alphapoint) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	addreses, err := a.GetDepositAddresses()
@@ -172,11 +217,21 @@ func (a *Alphapoint) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amoun
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (a *Alphapoint) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetWebsocket returns a pointer to the exchange websocket
 func (a *Alphapoint) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (a *Alphapoint) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (a *Alphapoint) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return 0, errors.New("not yet implemented")
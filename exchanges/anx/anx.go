@@ -60,6 +60,7 @@ func (a *ANX) SetDefaults() {
 	a.APIWithdrawPermissions = exchange.WithdrawCryptoWithEmail | exchange.AutoWithdrawCryptoWithSetup |
 		exchange.WithdrawCryptoWith2FA | exchange.WithdrawFiatViaWebsiteOnly
 	a.AssetTypes = []string{ticker.Spot}
+	a.SetAPIPermissionsFunc(a.GetAPIKeyPermissions)
 	a.SupportsAutoPairUpdating = true
 	a.SupportsRESTTickerBatching = false
 	a.Requester = request.New(a.Name,
@@ -72,7 +73,7 @@ func (a *ANX) SetDefaults() {
 }
 
 //Setup is run on startup to setup exchange with config values
-func (a *ANX) Setup(exch config.ExchangeConfig) {
+func (a *ANX) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		a.SetEnabled(false)
 	} else {
@@ -88,25 +89,34 @@ func (a *ANX) Setup(exch config.ExchangeConfig) {
 		a.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := a.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = a.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = a.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = a.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = a.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = a.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = a.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetCurrencies returns a list of supported currencies (both fiat
@@ -142,7 +152,7 @@ func (a *ANX) GetDepth(currency string) (Depth, error) {
 // GetAPIKey returns a new generated API key set.
 func (a *ANX) GetAPIKey(username, password, otp, deviceID string) (string, string, error) {
 	request := make(map[string]interface{})
-	request["nonce"] = strconv.FormatInt(time.Now().UnixNano(), 10)[0:13]
+	request["nonce"] = strconv.FormatInt(a.GetAdjustedTime().UnixNano(), 10)[0:13]
 	request["username"] = username
 	request["password"] = password
 
@@ -369,7 +379,7 @@ func (a *ANX) SendAuthenticatedHTTPRequest(path string, params map[string]interf
 	}
 
 	if a.Nonce.Get() == 0 {
-		a.Nonce.Set(time.Now().UnixNano())
+		a.Nonce.Set(a.GetAdjustedTime().UnixNano())
 	} else {
 		a.Nonce.Inc()
 	}
@@ -488,3 +498,23 @@ func (a *ANX) CheckAPIWithdrawPermission() (bool, error) {
 
 	return apiAllowsWithdraw, nil
 }
+
+// GetAPIKeyPermissions returns the permission scopes of the API key from its
+// account rights
+func (a *ANX) GetAPIKeyPermissions() ([]string, error) {
+	accountInfo, err := a.GetAccountInformation()
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []string{exchange.APIPermissionRead}
+	for _, right := range accountInfo.Rights {
+		switch common.StringToLower(right) {
+		case "trade":
+			scopes = append(scopes, exchange.APIPermissionTrade)
+		case "withdraw":
+			scopes = append(scopes, exchange.APIPermissionWithdraw)
+		}
+	}
+	return scopes, nil
+}
@@ -1,34 +1,46 @@
 package anx
 
 import (
+	"context"
 	"errors"
 	"log"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the ANX exchange
+func init() {
+	exchange.Register("ANX", func() exchange.IBotExchange { return new(ANX) })
+}
+
 // Start starts the ANX go routine
-func (a *ANX) Start(wg *sync.WaitGroup) {
+func (a *ANX) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		a.Run()
+		a.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the ANX wrapper
-func (a *ANX) Run() {
+func (a *ANX) Run(ctx context.Context) {
 	if a.Verbose {
 		log.Printf("%s polling delay: %ds.\n", a.GetName(), a.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", a.GetName(), len(a.EnabledPairs), a.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := a.GetTradablePairs()
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", a.GetName())
@@ -54,6 +66,11 @@ func (a *ANX) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (a *ANX) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetTradablePairs returns a list of available
 func (a *ANX) GetTradablePairs() ([]string, error) {
 	result, err := a.GetCurrencies()
@@ -170,7 +187,7 @@ func (a *ANX) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.
 		orderBook.Bids = append(orderBook.Bids, orderbook.Item{Price: orderbookNew.Data.Bids[x].Price, Amount: orderbookNew.Data.Bids[x].Amount})
 	}
 
-	orderbook.ProcessOrderbook(a.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(a.GetName(), p, a.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(a.Name, p, assetType)
 }
 
@@ -188,6 +205,18 @@ func (a *ANX) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error) {
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (a *ANX) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (a *ANX) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (a *ANX) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -195,6 +224,12 @@ func (a *ANX) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]excha
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (a *ANX) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (a *ANX) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -211,6 +246,18 @@ func (a *ANX) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (a *ANX) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, a.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (a *ANX) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, a.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (a *ANX) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -222,6 +269,11 @@ func (a *ANX) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, error)
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (a *ANX) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (a *ANX) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -239,9 +291,14 @@ func (a *ANX) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount float
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (a *ANX) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a withdrawal is
 // submitted
-func (a *ANX) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (a *ANX) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -250,6 +307,11 @@ func (a *ANX) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (a *ANX) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (a *ANX) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return a.GetFee(feeBuilder)
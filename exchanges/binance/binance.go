@@ -2,17 +2,20 @@ package binance
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
 	"github.com/thrasher-/gocryptotrader/exchanges/request"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
@@ -20,8 +23,6 @@ import (
 // Binance is the overarching type across the Bithumb package
 type Binance struct {
 	exchange.Base
-	WebsocketConn *websocket.Conn
-
 	// Valid string list that is required by the exchange
 	validLimits    []int
 	validIntervals []TimeInterval
@@ -30,7 +31,20 @@ type Binance struct {
 const (
 	apiURL = "https://api.binance.com"
 
+	// binanceBNBFeeDiscount is the discount on trading fees paid in BNB
+	binanceBNBFeeDiscount = 0.25
+
+	// binanceKlineLimit is the maximum number of klines returned per request
+	binanceKlineLimit = 1000
+
+	// binanceBrokerPrefix prefixes the broker ID in the client order IDs of
+	// orders placed through a broker account
+	binanceBrokerPrefix = "x-"
+	// binanceMaxClientOrderIDLength is the maximum length of a client order ID
+	binanceMaxClientOrderIDLength = 36
+
 	// Public endpoints
+	serverTime       = "/api/v1/time"
 	exchangeInfo     = "/api/v1/exchangeInfo"
 	orderBookDepth   = "/api/v1/depth"
 	recentTrades     = "/api/v1/trades"
@@ -42,6 +56,8 @@ const (
 	symbolPrice      = "/api/v3/ticker/price"
 	bestPrice        = "/api/v3/ticker/bookTicker"
 	accountInfo      = "/api/v3/account"
+	bnbBurn          = "/sapi/v1/bnbBurn"
+	allCoinsInfo     = "/sapi/v1/capital/config/getall"
 
 	// Authenticated endpoints
 	newOrderTest = "/api/v3/order/test"
@@ -70,6 +86,9 @@ func (b *Binance) SetDefaults() {
 	b.AssetTypes = []string{ticker.Spot}
 	b.SupportsAutoPairUpdating = true
 	b.SupportsRESTTickerBatching = true
+	b.SupportsBrokerID = true
+	b.SetAssetMetadata(defaultAssetMetadata())
+	b.SetAssetMetadataFetcher(b.fetchAssetMetadata)
 	b.APIWithdrawPermissions = exchange.AutoWithdrawCrypto
 	b.SetValues()
 	b.Requester = request.New(b.Name,
@@ -82,7 +101,7 @@ func (b *Binance) SetDefaults() {
 }
 
 // Setup takes in the supplied exchange configuration details and sets params
-func (b *Binance) Setup(exch config.ExchangeConfig) {
+func (b *Binance) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		b.SetEnabled(false)
 	} else {
@@ -98,23 +117,31 @@ func (b *Binance) Setup(exch config.ExchangeConfig) {
 		b.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := b.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = b.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = b.WebsocketSetup(b.WSConnect,
 			exch.Name,
@@ -122,9 +149,11 @@ func (b *Binance) Setup(exch config.ExchangeConfig) {
 			binanceDefaultWebsocketURL,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
+		b.WebsocketConnectionSetup(exch.WebsocketConnections)
 	}
+	return nil
 }
 
 // GetExchangeValidCurrencyPairs returns the full pair list from the exchange
@@ -138,13 +167,41 @@ func (b *Binance) GetExchangeValidCurrencyPairs() ([]string, error) {
 	}
 
 	for _, symbol := range info.Symbols {
-		if symbol.Status == "TRADING" {
-			validCurrencyPairs = append(validCurrencyPairs, symbol.BaseAsset+"-"+symbol.QuoteAsset)
+		if symbol.Status != "TRADING" {
+			continue
+		}
+		validCurrencyPairs = append(validCurrencyPairs, symbol.BaseAsset+"-"+symbol.QuoteAsset)
+		pair.RegisterPair(pair.NewCurrencyPair(symbol.BaseAsset, symbol.QuoteAsset))
+
+		var rules orders.SymbolRules
+		for _, filter := range symbol.Filters {
+			switch filter.FilterType {
+			case "PRICE_FILTER":
+				rules.MinPrice = filter.MinPrice
+				rules.MaxPrice = filter.MaxPrice
+				rules.PriceStep = filter.TickSize
+			case "LOT_SIZE":
+				rules.MinAmount = filter.MinQty
+				rules.MaxAmount = filter.MaxQty
+				rules.AmountStep = filter.StepSize
+			case "MIN_NOTIONAL":
+				rules.MinNotional = filter.MinNotional
+			}
 		}
+		orders.SetSymbolRules(b.Name,
+			pair.NewCurrencyPair(symbol.BaseAsset, symbol.QuoteAsset), rules)
 	}
 	return validCurrencyPairs, nil
 }
 
+// GetServerTime returns the current Binance server time
+func (b *Binance) GetServerTime() (ServerTime, error) {
+	var resp ServerTime
+	path := b.APIUrl + serverTime
+
+	return resp, b.SendHTTPRequest(path, &resp)
+}
+
 // GetExchangeInfo returns exchange information. Check binance_types for more
 // information
 func (b *Binance) GetExchangeInfo() (ExchangeInfo, error) {
@@ -378,9 +435,15 @@ func (b *Binance) GetPriceChangeStats(symbol string) (PriceChangeStats, error) {
 
 // GetTickers returns the ticker data for the last 24 hrs
 func (b *Binance) GetTickers() ([]PriceChangeStats, error) {
+	return b.GetTickersContext(context.Background())
+}
+
+// GetTickersContext returns the ticker data for the last 24 hrs under a call
+// context
+func (b *Binance) GetTickersContext(ctx context.Context) ([]PriceChangeStats, error) {
 	var resp []PriceChangeStats
 	path := fmt.Sprintf("%s%s", b.APIUrl, priceChange)
-	return resp, b.SendHTTPRequest(path, &resp)
+	return resp, b.SendHTTPRequestContext(ctx, path, &resp)
 }
 
 // GetLatestSpotPrice returns latest spot price of symbol
@@ -436,6 +499,11 @@ func (b *Binance) NewOrderTest() (interface{}, error) {
 
 // NewOrder sends a new order to Binance
 func (b *Binance) NewOrder(o NewOrderRequest) (NewOrderResponse, error) {
+	return b.NewOrderContext(context.Background(), o)
+}
+
+// NewOrderContext sends a new order to Binance under a call context
+func (b *Binance) NewOrderContext(ctx context.Context, o NewOrderRequest) (NewOrderResponse, error) {
 	var resp NewOrderResponse
 
 	path := fmt.Sprintf("%s%s", b.APIUrl, newOrder)
@@ -445,11 +513,18 @@ func (b *Binance) NewOrder(o NewOrderRequest) (NewOrderResponse, error) {
 	params.Set("side", string(o.Side))
 	params.Set("type", string(o.TradeType))
 	params.Set("quantity", strconv.FormatFloat(o.Quantity, 'f', -1, 64))
-	params.Set("price", strconv.FormatFloat(o.Price, 'f', -1, 64))
-	params.Set("timeInForce", string(o.TimeInForce))
 
-	if o.NewClientOrderID != "" {
-		params.Set("newClientOrderID", o.NewClientOrderID)
+	if o.Price != 0 {
+		params.Set("price", strconv.FormatFloat(o.Price, 'f', -1, 64))
+	}
+
+	if o.TimeInForce != "" {
+		params.Set("timeInForce", string(o.TimeInForce))
+	}
+
+	clientOrderID := b.brokerClientOrderID(o.NewClientOrderID)
+	if clientOrderID != "" {
+		params.Set("newClientOrderId", clientOrderID)
 	}
 
 	if o.StopPrice != 0 {
@@ -464,7 +539,7 @@ func (b *Binance) NewOrder(o NewOrderRequest) (NewOrderResponse, error) {
 		params.Set("newOrderRespType", o.NewOrderRespType)
 	}
 
-	if err := b.SendAuthHTTPRequest("POST", path, params, &resp); err != nil {
+	if err := b.SendAuthHTTPRequestContext(ctx, "POST", path, params, &resp); err != nil {
 		return resp, err
 	}
 
@@ -474,6 +549,27 @@ func (b *Binance) NewOrder(o NewOrderRequest) (NewOrderResponse, error) {
 	return resp, nil
 }
 
+// brokerClientOrderID returns the client order ID of an order tagged with
+// the broker ID, which Binance requires as a prefix of the client order ID for
+// the order to earn broker rebates. The end of the client order ID is kept
+// when it is too long to tag, and an ID is generated when none was supplied
+func (b *Binance) brokerClientOrderID(clientID string) string {
+	brokerID := b.GetBrokerID()
+	if brokerID == "" {
+		return clientID
+	}
+
+	if clientID == "" {
+		clientID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	prefix := binanceBrokerPrefix + brokerID
+	if remaining := binanceMaxClientOrderIDLength - len(prefix); len(clientID) > remaining {
+		clientID = clientID[len(clientID)-remaining:]
+	}
+	return prefix + clientID
+}
+
 // CancelOrder sends a cancel order to Binance
 func (b *Binance) CancelOrder(symbol string, orderID int64, origClientOrderID string) (CancelOrderResponse, error) {
 
@@ -587,13 +683,102 @@ func (b *Binance) GetAccount() (*Account, error) {
 	return &resp.Account, nil
 }
 
+// GetBNBBurnStatus returns whether spot trading fees are paid in BNB
+func (b *Binance) GetBNBBurnStatus() (BNBBurnStatus, error) {
+	var resp BNBBurnStatus
+	path := fmt.Sprintf("%s%s", b.APIUrl, bnbBurn)
+	return resp, b.SendAuthHTTPRequest("GET", path, url.Values{}, &resp)
+}
+
+// GetAllCoinsInfo returns the deposit and withdrawal details of each coin on
+// each of its networks
+func (b *Binance) GetAllCoinsInfo() ([]CoinInfo, error) {
+	var resp []CoinInfo
+	path := fmt.Sprintf("%s%s", b.APIUrl, allCoinsInfo)
+	return resp, b.SendAuthHTTPRequest("GET", path, url.Values{}, &resp)
+}
+
+// fetchAssetMetadata returns the asset metadata of every coin, which needs
+// authenticated API support
+func (b *Binance) fetchAssetMetadata() ([]exchange.AssetMetadata, error) {
+	if !b.AuthenticatedAPISupport {
+		return nil, exchange.ErrAssetMetadataNotSupported
+	}
+
+	coins, err := b.GetAllCoinsInfo()
+	if err != nil {
+		return nil, err
+	}
+	return convertCoinsInfo(coins), nil
+}
+
+// convertCoinsInfo returns the asset metadata of each network of the coins
+func convertCoinsInfo(coins []CoinInfo) []exchange.AssetMetadata {
+	var metadata []exchange.AssetMetadata
+	for x := range coins {
+		for _, network := range coins[x].NetworkList {
+			metadata = append(metadata, exchange.AssetMetadata{
+				Currency:             coins[x].Coin,
+				Chain:                network.Network,
+				Default:              network.IsDefault,
+				MinWithdrawal:        network.WithdrawMin,
+				WithdrawalFee:        network.WithdrawFee,
+				DepositConfirmations: network.MinConfirm,
+				WithdrawalDisabled:   !network.WithdrawEnable,
+				DepositDisabled:      !network.DepositEnable,
+			})
+		}
+	}
+	return metadata
+}
+
+// defaultAssetMetadata returns the asset metadata used until it is fetched,
+// the known networks of the main coins followed by a single network of each
+// other coin with its withdrawal fee
+func defaultAssetMetadata() []exchange.AssetMetadata {
+	metadata := append([]exchange.AssetMetadata(nil), AssetMetadata...)
+	known := make(map[string]bool)
+	for x := range AssetMetadata {
+		known[AssetMetadata[x].Currency] = true
+	}
+
+	var coins []string
+	for coin := range WithdrawalFees {
+		if !known[coin] {
+			coins = append(coins, coin)
+		}
+	}
+	sort.Strings(coins)
+
+	for _, coin := range coins {
+		metadata = append(metadata, exchange.AssetMetadata{
+			Currency:      coin,
+			Chain:         coin,
+			Default:       true,
+			WithdrawalFee: WithdrawalFees[coin],
+		})
+	}
+	return metadata
+}
+
 // SendHTTPRequest sends an unauthenticated request
 func (b *Binance) SendHTTPRequest(path string, result interface{}) error {
-	return b.SendPayload("GET", path, nil, nil, result, false, b.Verbose)
+	return b.SendHTTPRequestContext(context.Background(), path, result)
+}
+
+// SendHTTPRequestContext sends an unauthenticated request under a call context
+func (b *Binance) SendHTTPRequestContext(ctx context.Context, path string, result interface{}) error {
+	return b.SendPayloadContext(ctx, "GET", path, nil, nil, result, false, b.Verbose)
 }
 
 // SendAuthHTTPRequest sends an authenticated HTTP request
 func (b *Binance) SendAuthHTTPRequest(method, path string, params url.Values, result interface{}) error {
+	return b.SendAuthHTTPRequestContext(context.Background(), method, path, params, result)
+}
+
+// SendAuthHTTPRequestContext sends an authenticated HTTP request under a call
+// context
+func (b *Binance) SendAuthHTTPRequestContext(ctx context.Context, method, path string, params url.Values, result interface{}) error {
 	if !b.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, b.Name)
 	}
@@ -602,7 +787,7 @@ func (b *Binance) SendAuthHTTPRequest(method, path string, params url.Values, re
 		params = url.Values{}
 	}
 	params.Set("recvWindow", strconv.FormatInt(common.RecvWindow(5*time.Second), 10))
-	params.Set("timestamp", strconv.FormatInt(time.Now().Unix()*1000, 10))
+	params.Set("timestamp", strconv.FormatInt(b.GetAdjustedTime().Unix()*1000, 10))
 
 	signature := params.Encode()
 	hmacSigned := common.GetHMAC(common.HashSHA256, []byte(signature), []byte(b.APISecret))
@@ -617,7 +802,7 @@ func (b *Binance) SendAuthHTTPRequest(method, path string, params url.Values, re
 	}
 	path = common.EncodeURLValues(path, params)
 
-	return b.SendPayload(method, path, headers, bytes.NewBufferString(""), result, true, b.Verbose)
+	return b.SendPayloadContext(ctx, method, path, headers, bytes.NewBufferString(""), result, true, b.Verbose)
 }
 
 // CheckLimit checks value against a variable list
@@ -683,7 +868,8 @@ func (b *Binance) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 		if err != nil {
 			return 0, err
 		}
-		fee = calculateTradingFee(feeBuilder.PurchasePrice, feeBuilder.Amount, multiplier)
+		fee = b.DiscountTradingFee(feeBuilder,
+			calculateTradingFee(feeBuilder.PurchasePrice, feeBuilder.Amount, multiplier))
 	case exchange.CryptocurrencyWithdrawalFee:
 		fee = getCryptocurrencyWithdrawalFee(feeBuilder.FirstCurrency, feeBuilder.PurchasePrice, feeBuilder.Amount)
 	}
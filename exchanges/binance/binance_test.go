@@ -1,8 +1,12 @@
 package binance
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 
 	"github.com/thrasher-/gocryptotrader/config"
@@ -96,6 +100,91 @@ func TestGetSpotKline(t *testing.T) {
 	}
 }
 
+func TestConvertCandles(t *testing.T) {
+	t.Parallel()
+	if _, err := klineInterval(time.Second); err == nil {
+		t.Error("Test Failed - Binance klineInterval() expected unsupported interval error")
+	}
+	interval, err := klineInterval(time.Minute * 5)
+	if err != nil || interval != TimeIntervalFiveMinutes {
+		t.Error("Test Failed - Binance klineInterval() unexpected interval", interval, err)
+	}
+
+	p := pair.NewCurrencyPair("BTC", "USDT")
+	candles := convertCandles([]CandleStick{
+		{OpenTime: 1500000000000, Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 10, TradeCount: 4},
+		{OpenTime: 1500000300000, Open: 2, High: 2, Low: 2, Close: 2},
+	}, p, "SPOT", time.Minute*5, time.Unix(1500000400, 0))
+	if len(candles) != 2 {
+		t.Fatal("Test Failed - Binance convertCandles() unexpected candles", candles)
+	}
+	if !candles[0].StartTime.Equal(time.Unix(1500000000, 0)) || candles[0].High != 3 ||
+		candles[0].Trades != 4 || !candles[0].Closed {
+		t.Error("Test Failed - Binance convertCandles() unexpected candle", candles[0])
+	}
+	if candles[1].Closed {
+		t.Error("Test Failed - Binance convertCandles() open candle marked closed")
+	}
+}
+
+func TestConvertCoinsInfo(t *testing.T) {
+	t.Parallel()
+	metadata := convertCoinsInfo([]CoinInfo{{Coin: "USDT", NetworkList: []CoinNetwork{
+		{Network: "ETH", IsDefault: true, DepositEnable: true, WithdrawEnable: true, WithdrawMin: 20, WithdrawFee: 5, MinConfirm: 12},
+		{Network: "TRX", DepositEnable: true, MinConfirm: 1},
+	}}})
+	if len(metadata) != 2 {
+		t.Fatal("Test Failed - Binance convertCoinsInfo() unexpected metadata", metadata)
+	}
+	if metadata[0].Chain != "ETH" || !metadata[0].Default || metadata[0].MinWithdrawal != 20 ||
+		metadata[0].DepositConfirmations != 12 || metadata[0].WithdrawalDisabled {
+		t.Error("Test Failed - Binance convertCoinsInfo() unexpected metadata", metadata[0])
+	}
+	if !metadata[1].WithdrawalDisabled || metadata[1].DepositDisabled {
+		t.Error("Test Failed - Binance convertCoinsInfo() unexpected enabled flags", metadata[1])
+	}
+
+	var exch Binance
+	exch.SetDefaults()
+	chain, err := exch.GetChainMetadata("USDT", "")
+	if err != nil || chain.Chain != "ETH" {
+		t.Error("Test Failed - Binance default asset metadata unexpected USDT chain", chain, err)
+	}
+	if len(exch.GetAssetMetadata("USDT")) != 3 {
+		t.Error("Test Failed - Binance default asset metadata expected 3 USDT chains")
+	}
+	chain, err = exch.GetChainMetadata("EOS", "")
+	if err != nil || chain.WithdrawalFee != WithdrawalFees["EOS"] {
+		t.Error("Test Failed - Binance default asset metadata unexpected EOS chain", chain, err)
+	}
+}
+
+func TestBrokerClientOrderID(t *testing.T) {
+	var exch Binance
+	exch.SetDefaults()
+	if id := exch.brokerClientOrderID("order1"); id != "order1" {
+		t.Error("Test Failed - Binance brokerClientOrderID() untagged ID altered", id)
+	}
+
+	err := exch.SetBrokerID("GCT")
+	if err != nil {
+		t.Fatal("Test Failed - Binance SetBrokerID() error", err)
+	}
+
+	if id := exch.brokerClientOrderID("order1"); id != "x-GCTorder1" {
+		t.Error("Test Failed - Binance brokerClientOrderID() unexpected ID", id)
+	}
+
+	if id := exch.brokerClientOrderID(""); !common.StringContains(id, "x-GCT") || len(id) <= 5 {
+		t.Error("Test Failed - Binance brokerClientOrderID() expected generated ID", id)
+	}
+
+	id := exch.brokerClientOrderID("0123456789012345678901234567890123456789")
+	if id != "x-GCT9012345678901234567890123456789" {
+		t.Error("Test Failed - Binance brokerClientOrderID() unexpected truncated ID", id)
+	}
+}
+
 func TestGetAveragePrice(t *testing.T) {
 	t.Parallel()
 	_, err := b.GetAveragePrice("BTCUSDT")
@@ -120,6 +209,17 @@ func TestGetTickers(t *testing.T) {
 	}
 }
 
+func TestUpdateTickerContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := b.UpdateTickerContext(ctx, pair.NewCurrencyPair("BTC", "USDT"), "SPOT")
+	if err != context.Canceled {
+		t.Error("Test Failed - Binance UpdateTickerContext() expected context cancelled error", err)
+	}
+}
+
 func TestGetLatestSpotPrice(t *testing.T) {
 	t.Parallel()
 	_, err := b.GetLatestSpotPrice("BTCUSDT")
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 )
 
 // Response holds basic binance api response data
@@ -12,6 +13,11 @@ type Response struct {
 	Msg  string `json:"msg"`
 }
 
+// ServerTime holds the current Binance server time in milliseconds
+type ServerTime struct {
+	ServerTime int64 `json:"serverTime"`
+}
+
 // ExchangeInfo holds the full exchange information type
 type ExchangeInfo struct {
 	Code       int    `json:"code"`
@@ -366,6 +372,13 @@ type Account struct {
 	Balances         []Balance `json:"balances"`
 }
 
+// BNBBurnStatus holds whether trading fees and margin interest are paid in
+// BNB
+type BNBBurnStatus struct {
+	SpotBNBBurn     bool `json:"spotBNBBurn"`
+	InterestBNBBurn bool `json:"interestBNBBurn"`
+}
+
 // RequestParamsSideType trade order side (buy or sell)
 type RequestParamsSideType string
 
@@ -421,7 +434,7 @@ var (
 type KlinesRequestParams struct {
 	Symbol    string       // Required field; example LTCBTC, BTCUSDT
 	Interval  TimeInterval // Time interval period
-	Limit     int          // Default 500; max 500.
+	Limit     int          // Default 500; max 1000.
 	StartTime int64
 	EndTime   int64
 }
@@ -448,6 +461,47 @@ var (
 	TimeIntervalMonth          = TimeInterval("1M")
 )
 
+// CoinInfo holds the deposit and withdrawal details of a coin
+type CoinInfo struct {
+	Coin              string        `json:"coin"`
+	Name              string        `json:"name"`
+	DepositAllEnable  bool          `json:"depositAllEnable"`
+	WithdrawAllEnable bool          `json:"withdrawAllEnable"`
+	NetworkList       []CoinNetwork `json:"networkList"`
+}
+
+// CoinNetwork holds the deposit and withdrawal details of a coin on a network
+type CoinNetwork struct {
+	Network        string  `json:"network"`
+	Coin           string  `json:"coin"`
+	Name           string  `json:"name"`
+	IsDefault      bool    `json:"isDefault"`
+	DepositEnable  bool    `json:"depositEnable"`
+	WithdrawEnable bool    `json:"withdrawEnable"`
+	WithdrawFee    float64 `json:"withdrawFee,string"`
+	WithdrawMin    float64 `json:"withdrawMin,string"`
+	WithdrawMax    float64 `json:"withdrawMax,string"`
+	MinConfirm     int64   `json:"minConfirm"`
+	UnLockConfirm  int64   `json:"unLockConfirm"`
+}
+
+// AssetMetadata holds the predefined networks of the main coins, used until
+// the asset metadata is fetched
+// Prone to change
+var AssetMetadata = []exchange.AssetMetadata{
+	{Currency: symbol.BTC, Chain: "BTC", Default: true, MinWithdrawal: 0.001, WithdrawalFee: 0.0005, DepositConfirmations: 1},
+	{Currency: symbol.BTC, Chain: "BSC", MinWithdrawal: 0.00001, WithdrawalFee: 0.0000051, DepositConfirmations: 15},
+	{Currency: symbol.ETH, Chain: "ETH", Default: true, MinWithdrawal: 0.02, WithdrawalFee: 0.01, DepositConfirmations: 12},
+	{Currency: symbol.ETH, Chain: "BSC", MinWithdrawal: 0.0001, WithdrawalFee: 0.000073, DepositConfirmations: 15},
+	{Currency: symbol.LTC, Chain: "LTC", Default: true, MinWithdrawal: 0.002, WithdrawalFee: 0.001, DepositConfirmations: 4},
+	{Currency: symbol.BNB, Chain: "BNB", Default: true, MinWithdrawal: 0.01, WithdrawalFee: 0.13, DepositConfirmations: 1},
+	{Currency: symbol.BNB, Chain: "BSC", MinWithdrawal: 0.01, WithdrawalFee: 0.0005, DepositConfirmations: 15},
+	{Currency: symbol.USDT, Chain: "ETH", Default: true, MinWithdrawal: 10, WithdrawalFee: 3.4, DepositConfirmations: 12},
+	{Currency: symbol.USDT, Chain: "TRX", MinWithdrawal: 10, WithdrawalFee: 1, DepositConfirmations: 1},
+	{Currency: symbol.USDT, Chain: "BSC", MinWithdrawal: 10, WithdrawalFee: 0.8, DepositConfirmations: 15},
+	{Currency: symbol.TRX, Chain: "TRX", Default: true, MinWithdrawal: 2, WithdrawalFee: 1, DepositConfirmations: 1},
+}
+
 // WithdrawalFees the large list of predefined withdrawal fees
 // Prone to change
 var WithdrawalFees = map[string]float64{
@@ -122,38 +122,68 @@ func (b *Binance) UpdateLocalCache(ob WebsocketDepthStream) error {
 		"SPOT")
 }
 
-// WSConnect intiates a websocket connection
+// WSConnect intiates the websocket connections, splitting the enabled pairs
+// into the configured pair groups with a connection for each
 func (b *Binance) WSConnect() error {
 	if !b.Websocket.IsEnabled() || !b.IsEnabled() {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var Dialer websocket.Dialer
-	var err error
-
-	ticker := strings.ToLower(
-		strings.Replace(
-			strings.Join(b.EnabledPairs, "@ticker/"), "-", "", -1)) + "@ticker"
-	trade := strings.ToLower(
-		strings.Replace(
-			strings.Join(b.EnabledPairs, "@trade/"), "-", "", -1)) + "@trade"
-	kline := strings.ToLower(
-		strings.Replace(
-			strings.Join(b.EnabledPairs, "@kline_1m/"), "-", "", -1)) + "@kline_1m"
-	depth := strings.ToLower(
-		strings.Replace(
-			strings.Join(b.EnabledPairs, "@depth/"), "-", "", -1)) + "@depth"
-
-	wsurl := b.Websocket.GetWebsocketURL() +
-		"/stream?streams=" +
-		ticker +
-		"/" +
-		trade +
-		"/" +
-		kline +
-		"/" +
-		depth
+	for _, ePair := range b.GetEnabledCurrencies() {
+		err := b.SeedLocalCache(ePair)
+		if err != nil {
+			return err
+		}
+	}
+
+	groups := b.Websocket.GetPairGroups(b.GetEnabledCurrencies())
+	var conns []*exchange.WebsocketConnection
+	for x := range groups {
+		name := exchange.WebsocketConnectionPublic
+		if len(groups) > 1 {
+			name = fmt.Sprintf("%s-%d", name, x+1)
+		}
+		conns = append(conns, &exchange.WebsocketConnection{
+			Name:  name,
+			URL:   b.getStreamURL(groups[x]),
+			Pairs: groups[x],
+		})
+	}
+
+	err := b.Websocket.SetConnections(conns, b.wsConnectStream)
+	if err != nil {
+		return err
+	}
+
+	go b.WsHandleData()
+
+	return nil
+}
+
+// getStreamURL returns the combined stream URL of the ticker, trade, kline
+// and depth streams of pairs
+func (b *Binance) getStreamURL(pairs []pair.CurrencyPair) string {
+	var symbols []string
+	for x := range pairs {
+		symbols = append(symbols, exchange.FormatExchangeCurrency(b.Name, pairs[x]).String())
+	}
+
+	var streams []string
+	for _, stream := range []string{"ticker", "trade", "kline_1m", "depth"} {
+		for x := range symbols {
+			streams = append(streams, strings.ToLower(symbols[x])+"@"+stream)
+		}
+	}
+
+	return b.Websocket.GetWebsocketURL() + "/stream?streams=" +
+		strings.Join(streams, "/")
+}
 
+// wsConnectStream dials a combined stream connection and starts its read
+// routine
+func (b *Binance) wsConnectStream(conn *exchange.WebsocketConnection) error {
+	var Dialer websocket.Dialer
+	b.Websocket.ConfigureDialer(&Dialer)
 	if b.Websocket.GetProxyAddress() != "" {
 		url, err := url.Parse(b.Websocket.GetProxyAddress())
 		if err != nil {
@@ -164,52 +194,53 @@ func (b *Binance) WSConnect() error {
 		Dialer.Proxy = http.ProxyURL(url)
 	}
 
-	for _, ePair := range b.GetEnabledCurrencies() {
-		err := b.SeedLocalCache(ePair)
-		if err != nil {
-			return err
-		}
-	}
-
-	b.WebsocketConn, _, err = Dialer.Dial(wsurl, http.Header{})
+	wsConn, _, err := Dialer.Dial(conn.URL, http.Header{})
 	if err != nil {
 		return fmt.Errorf("binance_websocket.go - Unable to connect to Websocket. Error: %s",
 			err)
 	}
 
-	go b.WsHandleData()
+	conn.Wg.Add(1)
+	go b.WSReadData(conn, wsConn)
 
 	return nil
 }
 
-// WSReadData reads from the websocket connection
-func (b *Binance) WSReadData() {
-	b.Websocket.Wg.Add(1)
-
+// WSReadData reads from a websocket connection until it is shut down
+func (b *Binance) WSReadData(conn *exchange.WebsocketConnection, wsConn *websocket.Conn) {
+	closed := make(chan struct{})
 	defer func() {
-		err := b.WebsocketConn.Close()
-		if err != nil {
-			b.Websocket.DataHandler <- fmt.Errorf("binance_websocket.go - Unable to to close Websocket connection. Error: %s",
-				err)
-		}
-		b.Websocket.Wg.Done()
+		close(closed)
+		wsConn.Close()
+		conn.Wg.Done()
 	}()
 
-	for {
+	// Closing the connection on shutdown unblocks the read
+	go func() {
 		select {
-		case <-b.Websocket.ShutdownC:
-			return
+		case <-conn.ShutdownC:
+			wsConn.Close()
+		case <-closed:
+		}
+	}()
 
-		default:
-			msgType, resp, err := b.WebsocketConn.ReadMessage()
-			if err != nil {
-				b.Websocket.DataHandler <- fmt.Errorf("binance_websocket.go - Websocket Read Data. Error: %s",
-					err)
-				return
+	for {
+		msgType, resp, err := b.Websocket.ReadMessage(wsConn)
+		if err != nil {
+			select {
+			case <-conn.ShutdownC:
+			default:
+				b.Websocket.DataHandler <- conn.Failed(fmt.Errorf("binance_websocket.go - Websocket Read Data. Error: %s",
+					err))
 			}
+			return
+		}
 
-			b.Websocket.TrafficAlert <- struct{}{}
-			b.Websocket.Intercomm <- exchange.WebsocketResponse{Type: msgType, Raw: resp}
+		b.Websocket.TrafficAlert <- struct{}{}
+		select {
+		case b.Websocket.Intercomm <- exchange.WebsocketResponse{Type: msgType, Raw: resp}:
+		case <-conn.ShutdownC:
+			return
 		}
 	}
 }
@@ -219,8 +250,6 @@ func (b *Binance) WsHandleData() {
 	b.Websocket.Wg.Add(1)
 	defer b.Websocket.Wg.Done()
 
-	go b.WSReadData()
-
 	for {
 		select {
 		case <-b.Websocket.ShutdownC:
@@ -263,6 +292,7 @@ func (b *Binance) WsHandleData() {
 					}
 
 					b.Websocket.DataHandler <- exchange.TradeData{
+						TradeID:      strconv.FormatInt(trade.TradeID, 10),
 						CurrencyPair: pair.NewCurrencyPairFromString(trade.Symbol),
 						Timestamp:    time.Unix(0, trade.TimeStamp),
 						Price:        price,
@@ -314,8 +344,8 @@ func (b *Binance) WsHandleData() {
 					wsKline.Pair = pair.NewCurrencyPairFromString(kline.Symbol)
 					wsKline.AssetType = "SPOT"
 					wsKline.Exchange = b.GetName()
-					wsKline.StartTime = time.Unix(0, kline.Kline.StartTime)
-					wsKline.CloseTime = time.Unix(0, kline.Kline.CloseTime)
+					wsKline.StartTime = time.Unix(0, kline.Kline.StartTime*int64(time.Millisecond))
+					wsKline.CloseTime = time.Unix(0, kline.Kline.CloseTime*int64(time.Millisecond))
 					wsKline.Interval = kline.Kline.Interval
 					wsKline.OpenPrice, _ = strconv.ParseFloat(kline.Kline.OpenPrice, 64)
 					wsKline.ClosePrice, _ = strconv.ParseFloat(kline.Kline.ClosePrice, 64)
@@ -1,34 +1,50 @@
 package binance
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Binance exchange
+func init() {
+	exchange.Register("Binance", func() exchange.IBotExchange { return new(Binance) })
+}
+
 // Start starts the OKEX go routine
-func (b *Binance) Start(wg *sync.WaitGroup) {
+func (b *Binance) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		b.Run()
+		b.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the OKEX wrapper
-func (b *Binance) Run() {
+func (b *Binance) Run(ctx context.Context) {
 	if b.Verbose {
 		log.Printf("%s Websocket: %s. (url: %s).\n", b.GetName(), common.IsEnabled(b.Websocket.IsEnabled()), b.Websocket.GetWebsocketURL())
 		log.Printf("%s polling delay: %ds.\n", b.GetName(), b.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	symbols, err := b.GetExchangeValidCurrencyPairs()
 	if err != nil {
 		log.Printf("%s Failed to get exchange info.\n", b.GetName())
@@ -54,11 +70,22 @@ func (b *Binance) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (b *Binance) GetListedPairs() ([]string, error) {
+	return b.GetExchangeValidCurrencyPairs()
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (b *Binance) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	return b.UpdateTickerContext(context.Background(), p, assetType)
+}
+
+// UpdateTickerContext updates and returns the ticker for a currency pair under
+// a call context
+func (b *Binance) UpdateTickerContext(ctx context.Context, p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
 
-	tick, err := b.GetTickers()
+	tick, err := b.GetTickersContext(ctx)
 	if err != nil {
 		return tickerPrice, err
 	}
@@ -102,7 +129,7 @@ func (b *Binance) GetOrderbookEx(currency pair.CurrencyPair, assetType string) (
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (b *Binance) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
-	orderbookNew, err := b.GetOrderBook(OrderBookDataRequestParams{Symbol: exchange.FormatExchangeCurrency(b.Name, p).String(), Limit: 1000})
+	orderbookNew, err := b.GetOrderBook(OrderBookDataRequestParams{Symbol: exchange.FormatExchangeCurrency(b.Name, p).String(), Limit: b.GetOrderbookRequestDepth(p, b.validLimits, 1000)})
 	if err != nil {
 		return orderBook, err
 	}
@@ -115,7 +142,7 @@ func (b *Binance) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderb
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: asks.Quantity, Price: asks.Price})
 	}
 
-	orderbook.ProcessOrderbook(b.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(b.GetName(), p, b.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(b.Name, p, assetType)
 }
 
@@ -133,6 +160,18 @@ func (b *Binance) GetExchangeFundTransferHistory() ([]exchange.FundHistory, erro
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (b *Binance) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (b *Binance) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (b *Binance) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -140,9 +179,122 @@ func (b *Binance) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]e
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end, paginated across requests of at most binanceKlineLimit candles
+func (b *Binance) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	timeInterval, err := klineInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeSymbol := exchange.FormatExchangeCurrency(b.Name, currencyPair).String()
+	return kline.Paginate(start, end, interval, binanceKlineLimit, func(from, to time.Time) ([]kline.Candle, error) {
+		candles, err := b.GetSpotKline(KlinesRequestParams{
+			Symbol:    exchangeSymbol,
+			Interval:  timeInterval,
+			Limit:     binanceKlineLimit,
+			StartTime: from.UnixNano() / int64(time.Millisecond),
+			EndTime:   to.UnixNano() / int64(time.Millisecond),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return convertCandles(candles, currencyPair, assetType, interval, time.Now()), nil
+	})
+}
+
+// klineInterval returns the Binance kline interval of a candle duration
+func klineInterval(interval time.Duration) (TimeInterval, error) {
+	switch interval {
+	case time.Minute:
+		return TimeIntervalMinute, nil
+	case time.Minute * 3:
+		return TimeIntervalThreeMinutes, nil
+	case time.Minute * 5:
+		return TimeIntervalFiveMinutes, nil
+	case time.Minute * 15:
+		return TimeIntervalFifteenMinutes, nil
+	case time.Minute * 30:
+		return TimeIntervalThirtyMinutes, nil
+	case time.Hour:
+		return TimeIntervalHour, nil
+	case time.Hour * 2:
+		return TimeIntervalTwoHours, nil
+	case time.Hour * 4:
+		return TimeIntervalFourHours, nil
+	case time.Hour * 6:
+		return TimeIntervalSixHours, nil
+	case time.Hour * 8:
+		return TimeIntervalEightHours, nil
+	case time.Hour * 12:
+		return TimeIntervalTwelveHours, nil
+	case time.Hour * 24:
+		return TimeIntervalDay, nil
+	case time.Hour * 24 * 3:
+		return TimeIntervalThreeDays, nil
+	case time.Hour * 24 * 7:
+		return TimeIntervalWeek, nil
+	}
+	return "", fmt.Errorf("unsupported kline interval %v", interval)
+}
+
+// convertCandles converts Binance klines to candles, marking those closed
+// before now as closed
+func convertCandles(candles []CandleStick, p pair.CurrencyPair, assetType string, interval time.Duration, now time.Time) []kline.Candle {
+	result := make([]kline.Candle, 0, len(candles))
+	for x := range candles {
+		openTime := time.Unix(0, int64(candles[x].OpenTime)*int64(time.Millisecond))
+		result = append(result, kline.Candle{
+			Pair:      p,
+			AssetType: assetType,
+			Interval:  interval,
+			StartTime: openTime,
+			Open:      candles[x].Open,
+			High:      candles[x].High,
+			Low:       candles[x].Low,
+			Close:     candles[x].Close,
+			Volume:    candles[x].Volume,
+			Trades:    int64(candles[x].TradeCount),
+			Closed:    !openTime.Add(interval).After(now),
+			Source:    kline.SourceExchange,
+		})
+	}
+	return result
+}
+
 // SubmitExchangeOrder submits a new order
 func (b *Binance) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
-	return 0, errors.New("not yet implemented")
+	return b.SubmitExchangeOrderContext(context.Background(), p, side, orderType, amount, price, clientID)
+}
+
+// SubmitExchangeOrderContext submits a new order under a call context
+func (b *Binance) SubmitExchangeOrderContext(ctx context.Context, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	o := NewOrderRequest{
+		Symbol:           exchange.FormatExchangeCurrency(b.Name, p).String(),
+		Side:             BinanceRequestParamsSideSell,
+		Quantity:         amount,
+		NewClientOrderID: clientID,
+	}
+	if side == exchange.OrderSideBuy() {
+		o.Side = BinanceRequestParamsSideBuy
+	}
+
+	switch orderType {
+	case exchange.OrderTypeMarket():
+		o.TradeType = BinanceRequestParamsOrderMarket
+	case exchange.OrderTypeLimit():
+		o.TradeType = BinanceRequestParamsOrderLimit
+		o.Price = price
+		o.TimeInForce = BinanceRequestParamsTimeGTC
+	default:
+		return 0, fmt.Errorf("unsupported order type %s", orderType)
+	}
+
+	resp, err := b.NewOrderContext(ctx, o)
+	if err != nil {
+		return 0, err
+	}
+	return resp.OrderID, nil
 }
 
 // ModifyExchangeOrder will allow of changing orderbook placement and limit to
@@ -156,6 +308,18 @@ func (b *Binance) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (b *Binance) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, b.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (b *Binance) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, b.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (b *Binance) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -167,6 +331,11 @@ func (b *Binance) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, err
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (b *Binance) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (b *Binance) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -184,9 +353,14 @@ func (b *Binance) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount f
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (b *Binance) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (b *Binance) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (b *Binance) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -195,11 +369,57 @@ func (b *Binance) GetWebsocket() (*exchange.Websocket, error) {
 	return b.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (b *Binance) GetExchangeServerTime() (time.Time, error) {
+	resp, err := b.GetServerTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, resp.ServerTime*int64(time.Millisecond)), nil
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (b *Binance) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return b.GetFee(feeBuilder)
 }
 
+// UpdateFeeDiscount detects whether BNB fee discount mode is enabled on the
+// account and refreshes the BNB balance used to pay fees
+func (b *Binance) UpdateFeeDiscount() (exchange.FeeDiscount, error) {
+	discount, err := b.GetFeeDiscount()
+	if err != nil {
+		discount = exchange.FeeDiscount{Token: symbol.BNB, Rate: binanceBNBFeeDiscount}
+	}
+
+	status, err := b.GetBNBBurnStatus()
+	if err != nil {
+		return discount, err
+	}
+
+	account, err := b.GetAccount()
+	if err != nil {
+		return discount, err
+	}
+
+	b.SetFeeDiscount(config.FeeDiscountConfig{
+		Token:   discount.Token,
+		Rate:    discount.Rate,
+		Enabled: status.SpotBNBBurn,
+	})
+
+	for x := range account.Balances {
+		if account.Balances[x].Asset != discount.Token {
+			continue
+		}
+		balance, err := strconv.ParseFloat(account.Balances[x].Free, 64)
+		if err != nil {
+			return discount, err
+		}
+		b.SetFeeTokenBalance(balance)
+	}
+	return b.GetFeeDiscount()
+}
+
 // GetWithdrawCapabilities returns the types of withdrawal methods permitted by the exchange
 func (b *Binance) GetWithdrawCapabilities() uint32 {
 	return b.GetWithdrawPermissions()
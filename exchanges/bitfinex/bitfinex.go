@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -76,6 +77,13 @@ const (
 	bitfinexOperativeMode   = 1
 )
 
+// bitfinexWalletTypes maps wallet types to the Bitfinex wallet names
+var bitfinexWalletTypes = map[string]string{
+	exchange.WalletExchange: "exchange",
+	exchange.WalletMargin:   "trading",
+	exchange.WalletFunding:  "deposit",
+}
+
 // Bitfinex is the overarching type across the bitfinex package
 // Notes: Bitfinex has added a rate limit to the number of REST requests.
 // Rate limit policy can vary in a range of 10 to 90 requests per minute
@@ -84,6 +92,7 @@ type Bitfinex struct {
 	exchange.Base
 	WebsocketConn         *websocket.Conn
 	WebsocketSubdChannels map[int]WebsocketChanInfo
+	subdChannelsMtx       sync.Mutex
 }
 
 // SetDefaults sets the basic defaults for bitfinex
@@ -107,11 +116,17 @@ func (b *Bitfinex) SetDefaults() {
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	b.APIUrlDefault = bitfinexAPIURLBase
 	b.APIUrl = b.APIUrlDefault
+	b.SetFeeTable(exchange.FeeTable{
+		Maker:             0.001,
+		Taker:             0.002,
+		Withdrawal:        WithdrawalFees,
+		WithdrawalMinimum: WithdrawalMinimums,
+	})
 	b.WebsocketInit()
 }
 
 // Setup takes in the supplied exchange configuration details and sets params
-func (b *Bitfinex) Setup(exch config.ExchangeConfig) {
+func (b *Bitfinex) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		b.SetEnabled(false)
 	} else {
@@ -128,23 +143,31 @@ func (b *Bitfinex) Setup(exch config.ExchangeConfig) {
 		b.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := b.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = b.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = b.WebsocketSetup(b.WsConnect,
 			exch.Name,
@@ -152,9 +175,19 @@ func (b *Bitfinex) Setup(exch config.ExchangeConfig) {
 			bitfinexWebsocket,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.WebsocketSubscriptionSetup(exch.WebsocketSubscriptions,
+			[]string{exchange.WebsocketChannelOrderbook,
+				exchange.WebsocketChannelTrades,
+				exchange.WebsocketChannelTicker},
+			b.WsSubscribeChannel,
+			b.WsUnsubscribeChannel)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetPlatformStatus returns the Bifinex platform status
@@ -881,7 +914,7 @@ func (b *Bitfinex) SendAuthenticatedHTTPRequest(method, path string, params map[
 	}
 
 	if b.Nonce.Get() == 0 {
-		b.Nonce.Set(time.Now().UnixNano())
+		b.Nonce.Set(b.GetAdjustedTime().UnixNano())
 	} else {
 		b.Nonce.Inc()
 	}
@@ -921,6 +954,10 @@ func (b *Bitfinex) SendAuthenticatedHTTPRequest(method, path string, params map[
 
 // GetFee returns an estimate of fee based on type of transaction
 func (b *Bitfinex) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
+	if b.IsFeeEstimated(feeBuilder) {
+		return b.EstimateFee(feeBuilder)
+	}
+
 	var fee float64
 
 	switch feeBuilder.FeeType {
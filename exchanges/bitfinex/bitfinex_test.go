@@ -678,6 +678,24 @@ func TestGetFee(t *testing.T) {
 			t.Errorf("Test Failed - GetFee() error. Expected: %f, Recieved: %f", float64(0.0004), resp)
 			t.Error(err)
 		}
+	} else {
+		b.AuthenticatedAPISupport = false
+		// CryptocurrencyTradeFee Estimate
+		if resp, err := b.GetFee(feeBuilder); resp != float64(0.002) || err != nil ||
+			!b.IsFeeEstimated(feeBuilder) {
+			t.Errorf("Test Failed - GetFee() error. Expected: %f, Recieved: %f", float64(0.002), resp)
+			t.Error(err)
+		}
+
+		// CryptocurrencyWithdrawalFee Estimate
+		feeBuilder = setFeeBuilder()
+		feeBuilder.FeeType = exchange.CryptocurrencyWithdrawalFee
+		if resp, err := b.GetFee(feeBuilder); resp != float64(0.0004) || err != nil ||
+			!b.IsFeeEstimated(feeBuilder) {
+			t.Errorf("Test Failed - GetFee() error. Expected: %f, Recieved: %f", float64(0.0004), resp)
+			t.Error(err)
+		}
+		b.AuthenticatedAPISupport = true
 	}
 
 	// CyptocurrencyDepositFee Basic
@@ -718,3 +736,53 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestConvertBalances(t *testing.T) {
+	t.Parallel()
+
+	currencies := b.convertBalances([]Balance{
+		{Type: "exchange", Currency: "btc", Amount: 1.5, Available: 1},
+		{Type: "trading", Currency: "btc", Amount: 2, Available: 2},
+		{Type: "exchange", Currency: "usd", Amount: 100, Available: 100},
+	})
+	if len(currencies) != 2 {
+		t.Fatalf("Test Failed - convertBalances() expected 2 currencies, got %d", len(currencies))
+	}
+
+	btc := currencies[0]
+	if btc.CurrencyName != symbol.BTC || btc.Free != 3 || btc.Locked != 0.5 || btc.Total != 3.5 {
+		t.Errorf("Test Failed - convertBalances() unexpected result %+v", btc)
+	}
+	if len(btc.Wallets) != 2 || btc.Wallets[0].Wallet != "exchange" || btc.Wallets[0].Locked != 0.5 ||
+		btc.Wallets[1].Wallet != "trading" || btc.Wallets[1].Free != 2 {
+		t.Errorf("Test Failed - convertBalances() unexpected wallets %+v", btc.Wallets)
+	}
+}
+
+func TestWsTradeUpdateFill(t *testing.T) {
+	t.Parallel()
+
+	fill, err := b.wsTradeUpdateFill([]interface{}{"123-BTCUSD", float64(401597395), "BTCUSD",
+		float64(1531308013), float64(1133411090), -0.5, float64(6500), "EXCHANGE LIMIT",
+		float64(6500), -0.001, "BTC"})
+	if err != nil {
+		t.Fatal("Test Failed - wsTradeUpdateFill() error", err)
+	}
+	if fill.TradeID != "401597395" || fill.OrderID != 1133411090 || fill.Side != "sell" ||
+		fill.Amount != 0.5 || fill.Fee != 6.5 || fill.Timestamp.Unix() != 1531308013 ||
+		fill.CurrencyPair.Pair().String() != "BTCUSD" {
+		t.Errorf("Test Failed - wsTradeUpdateFill() unexpected fill %+v", fill)
+	}
+
+	_, err = b.wsTradeUpdateFill([]interface{}{"123-BTCUSD", "401597395"})
+	if err == nil {
+		t.Error("Test Failed - wsTradeUpdateFill() accepted a short trade update")
+	}
+}
+
+func TestWalletTransferInterface(t *testing.T) {
+	var exch exchange.IBotExchange = &b
+	if _, ok := exch.(exchange.IWalletTransferer); !ok {
+		t.Error("Test Failed - Bitfinex does not implement IWalletTransferer")
+	}
+}
@@ -1,5 +1,7 @@
 package bitfinex
 
+import "github.com/thrasher-/gocryptotrader/currency/symbol"
+
 // Ticker holds basic ticker information from the exchange
 type Ticker struct {
 	Mid       float64 `json:"mid,string"`
@@ -470,3 +472,34 @@ var (
 	TimeIntervalFourteenDays   = TimeInterval("14d")
 	TimeIntervalMonth          = TimeInterval("1M")
 )
+
+// WithdrawalFees the default cryptocurrency withdrawal fees, used to estimate
+// withdrawal fees when the account fees can't be requested
+var WithdrawalFees = map[string]float64{
+	symbol.BTC:  0.0004,
+	symbol.BCH:  0.0001,
+	symbol.LTC:  0.001,
+	symbol.ETH:  0.00135,
+	symbol.ETC:  0.01,
+	symbol.ZEC:  0.001,
+	symbol.XMR:  0.04,
+	symbol.DASH: 0.01,
+	symbol.XRP:  0.02,
+	symbol.EOS:  0.1,
+	symbol.IOT:  0.5,
+	symbol.NEO:  0,
+	symbol.OMG:  0.1,
+	symbol.USDT: 20,
+}
+
+// WithdrawalMinimums the smallest cryptocurrency amounts which can be
+// withdrawn
+var WithdrawalMinimums = map[string]float64{
+	symbol.BTC:  0.001,
+	symbol.BCH:  0.001,
+	symbol.LTC:  0.01,
+	symbol.ETH:  0.01,
+	symbol.ETC:  0.01,
+	symbol.XRP:  20,
+	symbol.USDT: 100,
+}
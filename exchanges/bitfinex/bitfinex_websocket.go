@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -13,6 +14,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
 	"github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 )
@@ -31,6 +33,7 @@ const (
 	bitfinexWebsocketOrderUpdate        = "ou"
 	bitfinexWebsocketOrderCancel        = "oc"
 	bitfinexWebsocketTradeExecuted      = "te"
+	bitfinexWebsocketTradeUpdate        = "tu"
 	bitfinexWebsocketHeartbeat          = "hb"
 	bitfinexWebsocketAlertRestarting    = "20051"
 	bitfinexWebsocketAlertRefreshing    = "20060"
@@ -42,6 +45,14 @@ const (
 	bitfinexWebsocketUnknownChannel     = "10302"
 )
 
+// bitfinexWebsocketChannels maps websocket subscription channels to Bitfinex
+// channel names
+var bitfinexWebsocketChannels = map[string]string{
+	exchange.WebsocketChannelTicker:    "ticker",
+	exchange.WebsocketChannelOrderbook: "book",
+	exchange.WebsocketChannelTrades:    "trades",
+}
+
 // WebsocketHandshake defines the communication between the websocket API for
 // initial connection
 type WebsocketHandshake struct {
@@ -83,6 +94,47 @@ func (b *Bitfinex) WsSubscribe(channel string, params map[string]string) error {
 	return b.WsSend(request)
 }
 
+// WsSubscribeChannel subscribes to the Bitfinex channel for a websocket
+// subscription
+func (b *Bitfinex) WsSubscribeChannel(sub exchange.WebsocketSubscription) error {
+	channel, ok := bitfinexWebsocketChannels[sub.Channel]
+	if !ok {
+		return fmt.Errorf("%s websocket channel %s not supported", b.Name, sub.Channel)
+	}
+
+	params := make(map[string]string)
+	params["pair"] = exchange.FormatExchangeCurrency(b.Name, sub.Pair).String()
+	if channel == "book" {
+		params["prec"] = "P0"
+		if sub.Depth > 0 {
+			params["len"] = strconv.Itoa(sub.Depth)
+		}
+	}
+	return b.WsSubscribe(channel, params)
+}
+
+// WsUnsubscribeChannel unsubscribes from the Bitfinex channel for a websocket
+// subscription
+func (b *Bitfinex) WsUnsubscribeChannel(sub exchange.WebsocketSubscription) error {
+	channel := bitfinexWebsocketChannels[sub.Channel]
+	p := exchange.FormatExchangeCurrency(b.Name, sub.Pair).String()
+
+	b.subdChannelsMtx.Lock()
+	defer b.subdChannelsMtx.Unlock()
+	for chanID, info := range b.WebsocketSubdChannels {
+		if info.Channel != channel || info.Pair != p {
+			continue
+		}
+
+		request := make(map[string]interface{})
+		request["event"] = "unsubscribe"
+		request["chanId"] = chanID
+		delete(b.WebsocketSubdChannels, chanID)
+		return b.WsSend(request)
+	}
+	return exchange.ErrWebsocketNotSubscribed
+}
+
 // WsSendAuth sends a autheticated event payload
 func (b *Bitfinex) WsSendAuth() error {
 	request := make(map[string]interface{})
@@ -113,7 +165,9 @@ func (b *Bitfinex) WsSendUnauth() error {
 // WebsocketSubdChannels map in bitfinex.go (Bitfinex struct)
 func (b *Bitfinex) WsAddSubscriptionChannel(chanID int, channel, pair string) {
 	chanInfo := WebsocketChanInfo{Pair: pair, Channel: channel}
+	b.subdChannelsMtx.Lock()
 	b.WebsocketSubdChannels[chanID] = chanInfo
+	b.subdChannelsMtx.Unlock()
 
 	if b.Verbose {
 		log.Printf("%s Subscribed to Channel: %s Pair: %s ChannelID: %d\n",
@@ -130,9 +184,9 @@ func (b *Bitfinex) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var channels = []string{"book", "trades", "ticker"}
 	var Dialer websocket.Dialer
 	var err error
+	b.Websocket.ConfigureDialer(&Dialer)
 
 	if b.Websocket.GetProxyAddress() != "" {
 		proxy, err := url.Parse(b.Websocket.GetProxyAddress())
@@ -147,7 +201,7 @@ func (b *Bitfinex) WsConnect() error {
 		return fmt.Errorf("Unable to connect to Websocket. Error: %s", err)
 	}
 
-	_, resp, err := b.WebsocketConn.ReadMessage()
+	_, resp, err := b.Websocket.ReadMessage(b.WebsocketConn)
 	if err != nil {
 		return fmt.Errorf("Unable to read from Websocket. Error: %s", err)
 	}
@@ -164,18 +218,9 @@ func (b *Bitfinex) WsConnect() error {
 		}
 	}
 
-	for _, x := range channels {
-		for _, y := range b.EnabledPairs {
-			params := make(map[string]string)
-			if x == "book" {
-				params["prec"] = "P0"
-			}
-			params["pair"] = y
-			err := b.WsSubscribe(x, params)
-			if err != nil {
-				return err
-			}
-		}
+	err = b.Websocket.SubscribeToChannels()
+	if err != nil {
+		return err
 	}
 
 	if b.AuthenticatedAPISupport {
@@ -211,7 +256,7 @@ func (b *Bitfinex) WsReadData() {
 		case <-b.Websocket.ShutdownC:
 			return
 		default:
-			msgType, resp, err := b.WebsocketConn.ReadMessage()
+			msgType, resp, err := b.Websocket.ReadMessage(b.WebsocketConn)
 			if err != nil {
 				b.Websocket.DataHandler <- err
 				return
@@ -273,7 +318,9 @@ func (b *Bitfinex) WsDataHandler() {
 					chanData := result.([]interface{})
 					chanID := int(chanData[0].(float64))
 
+					b.subdChannelsMtx.Lock()
 					chanInfo, ok := b.WebsocketSubdChannels[chanID]
+					b.subdChannelsMtx.Unlock()
 					if !ok {
 						b.Websocket.DataHandler <- fmt.Errorf("bitfinex.go error - Unable to locate chanID: %d",
 							chanID)
@@ -312,7 +359,7 @@ func (b *Bitfinex) WsDataHandler() {
 							}
 
 							if len(newOrderbook) > 1 {
-								err := b.WsInsertSnapshot(pair.NewCurrencyPairFromString(chanInfo.Pair),
+								err := b.WsInsertSnapshot(translation.PairToCanonical(b.Name, pair.NewCurrencyPairFromString(chanInfo.Pair)),
 									"SPOT",
 									newOrderbook)
 
@@ -324,7 +371,7 @@ func (b *Bitfinex) WsDataHandler() {
 								continue
 							}
 
-							err := b.WsUpdateOrderbook(pair.NewCurrencyPairFromString(chanInfo.Pair),
+							err := b.WsUpdateOrderbook(translation.PairToCanonical(b.Name, pair.NewCurrencyPairFromString(chanInfo.Pair)),
 								"SPOT",
 								newOrderbook[0])
 
@@ -339,7 +386,7 @@ func (b *Bitfinex) WsDataHandler() {
 								ClosePrice: chanData[7].(float64),
 								HighPrice:  chanData[9].(float64),
 								LowPrice:   chanData[10].(float64),
-								Pair:       pair.NewCurrencyPairFromString(chanInfo.Pair),
+								Pair:       translation.PairToCanonical(b.Name, pair.NewCurrencyPairFromString(chanInfo.Pair)),
 								Exchange:   b.GetName(),
 								AssetType:  "SPOT",
 							}
@@ -451,6 +498,15 @@ func (b *Bitfinex) WsDataHandler() {
 									PriceExecuted:  data[5].(float64)}
 
 								b.Websocket.DataHandler <- trade
+
+							case bitfinexWebsocketTradeUpdate:
+								fill, err := b.wsTradeUpdateFill(chanData[2].([]interface{}))
+								if err != nil {
+									b.Websocket.DataHandler <- err
+									continue
+								}
+
+								b.Websocket.DataHandler <- fill
 							}
 
 						case "trades":
@@ -492,7 +548,8 @@ func (b *Bitfinex) WsDataHandler() {
 								}
 
 								b.Websocket.DataHandler <- exchange.TradeData{
-									CurrencyPair: pair.NewCurrencyPairFromString(chanInfo.Pair),
+									TradeID:      strconv.FormatInt(trades[0].ID, 10),
+									CurrencyPair: translation.PairToCanonical(b.Name, pair.NewCurrencyPairFromString(chanInfo.Pair)),
 									Timestamp:    time.Unix(trades[0].Timestamp, 0),
 									Price:        trades[0].Price,
 									Amount:       newAmount,
@@ -634,3 +691,49 @@ func (b *Bitfinex) WsUpdateOrderbook(p pair.CurrencyPair, assetType string, book
 
 	return nil
 }
+
+// wsTradeUpdateFill converts a trade update, sent once the fee of an executed
+// trade is known, to a fill. Bitfinex reports executed amounts negative for
+// sells and fees negative, fees charged in the base currency are converted to
+// the quote currency at the executed price
+func (b *Bitfinex) wsTradeUpdateFill(data []interface{}) (exchange.FillData, error) {
+	if len(data) < 11 {
+		return exchange.FillData{}, errors.New("bitfinex trade update has too few fields")
+	}
+
+	tradeID, ok1 := data[1].(float64)
+	symbol, ok2 := data[2].(string)
+	timestamp, ok3 := data[3].(float64)
+	orderID, ok4 := data[4].(float64)
+	amount, ok5 := data[5].(float64)
+	price, ok6 := data[6].(float64)
+	fee, ok7 := data[9].(float64)
+	feeCurrency, ok8 := data[10].(string)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 || !ok7 || !ok8 {
+		return exchange.FillData{}, errors.New("bitfinex trade update has malformed fields")
+	}
+
+	p := translation.PairToCanonical(b.Name, pair.NewCurrencyPairFromString(symbol))
+	side := "buy"
+	if amount < 0 {
+		side = "sell"
+	}
+
+	fee = math.Abs(fee)
+	if common.StringToUpper(feeCurrency) == p.FirstCurrency.Upper().String() {
+		fee *= price
+	}
+
+	return exchange.FillData{
+		Exchange:     b.GetName(),
+		TradeID:      strconv.FormatInt(int64(tradeID), 10),
+		OrderID:      int64(orderID),
+		CurrencyPair: p,
+		AssetType:    "SPOT",
+		Side:         side,
+		Price:        price,
+		Amount:       math.Abs(amount),
+		Fee:          fee,
+		Timestamp:    time.Unix(int64(timestamp), 0),
+	}, nil
+}
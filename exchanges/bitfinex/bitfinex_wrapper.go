@@ -1,35 +1,49 @@
 package bitfinex
 
 import (
+	"context"
 	"errors"
 	"log"
 	"net/url"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Bitfinex exchange
+func init() {
+	exchange.Register("Bitfinex", func() exchange.IBotExchange { return new(Bitfinex) })
+}
+
 // Start starts the Bitfinex go routine
-func (b *Bitfinex) Start(wg *sync.WaitGroup) {
+func (b *Bitfinex) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		b.Run()
+		b.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the Bitfinex wrapper
-func (b *Bitfinex) Run() {
+func (b *Bitfinex) Run(ctx context.Context) {
 	if b.Verbose {
 		log.Printf("%s Websocket: %s.", b.GetName(), common.IsEnabled(b.Websocket.IsEnabled()))
 		log.Printf("%s polling delay: %ds.\n", b.GetName(), b.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := b.GetSymbols()
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", b.GetName())
@@ -41,6 +55,11 @@ func (b *Bitfinex) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (b *Bitfinex) GetListedPairs() ([]string, error) {
+	return b.GetSymbols()
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (b *Bitfinex) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
@@ -48,7 +67,7 @@ func (b *Bitfinex) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.P
 
 	var pairs []string
 	for x := range enabledPairs {
-		pairs = append(pairs, "t"+enabledPairs[x].Pair().String())
+		pairs = append(pairs, "t"+b.FormatPair(enabledPairs[x], exchange.PairFormatRequest))
 	}
 
 	tickerNew, err := b.GetTickersV2(common.JoinStrings(pairs, ","))
@@ -57,7 +76,8 @@ func (b *Bitfinex) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.P
 	}
 
 	for x := range tickerNew {
-		newP := pair.NewCurrencyPair(tickerNew[x].Symbol[1:4], tickerNew[x].Symbol[4:])
+		newP := translation.PairToCanonical(b.Name,
+			pair.NewCurrencyPair(tickerNew[x].Symbol[1:4], tickerNew[x].Symbol[4:]))
 		var tick ticker.Price
 		tick.Pair = newP
 		tick.Ask = tickerNew[x].Ask
@@ -92,10 +112,11 @@ func (b *Bitfinex) GetOrderbookEx(p pair.CurrencyPair, assetType string) (orderb
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (b *Bitfinex) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
+	depth := strconv.Itoa(b.GetOrderbookRequestDepth(p, nil, 100))
 	urlVals := url.Values{}
-	urlVals.Set("limit_bids", "100")
-	urlVals.Set("limit_asks", "100")
-	orderbookNew, err := b.GetOrderbook(p.Pair().String(), urlVals)
+	urlVals.Set("limit_bids", depth)
+	urlVals.Set("limit_asks", depth)
+	orderbookNew, err := b.GetOrderbook(b.FormatPair(p, exchange.PairFormatRequest), urlVals)
 	if err != nil {
 		return orderBook, err
 	}
@@ -108,7 +129,7 @@ func (b *Bitfinex) UpdateOrderbook(p pair.CurrencyPair, assetType string) (order
 		orderBook.Bids = append(orderBook.Bids, orderbook.Item{Price: orderbookNew.Bids[x].Price, Amount: orderbookNew.Bids[x].Amount})
 	}
 
-	orderbook.ProcessOrderbook(b.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(b.GetName(), p, b.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(b.Name, p, assetType)
 }
 
@@ -125,38 +146,28 @@ func (b *Bitfinex) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 		return response, nil
 	}
 
-	type bfxCoins struct {
-		OnHold    float64
-		Available float64
-	}
-
-	accounts := make(map[string]bfxCoins)
+	response.Currencies = b.convertBalances(accountBalance)
+	return response, nil
+}
 
-	for i := range accountBalance {
-		onHold := accountBalance[i].Amount - accountBalance[i].Available
-		coins := bfxCoins{
-			OnHold:    onHold,
-			Available: accountBalance[i].Available,
-		}
-		result, ok := accounts[accountBalance[i].Currency]
+// convertBalances merges the exchange, trading and deposit wallet balances of
+// each currency, keeping the balance of each wallet in the breakdown
+func (b *Bitfinex) convertBalances(balances []Balance) []exchange.AccountCurrencyInfo {
+	var currencies []exchange.AccountCurrencyInfo
+	index := make(map[string]int)
+	for _, balance := range balances {
+		currency := translation.ToCanonical(b.Name, pair.CurrencyItem(balance.Currency)).Upper().String()
+		x, ok := index[currency]
 		if !ok {
-			accounts[accountBalance[i].Currency] = coins
-		} else {
-			result.Available += accountBalance[i].Available
-			result.OnHold += onHold
-			accounts[accountBalance[i].Currency] = result
+			x = len(currencies)
+			index[currency] = x
+			currencies = append(currencies,
+				exchange.AccountCurrencyInfo{CurrencyName: currency})
 		}
+		currencies[x].AddWallet(balance.Type, balance.Available,
+			balance.Amount-balance.Available)
 	}
-
-	for x, y := range accounts {
-		var exchangeCurrency exchange.AccountCurrencyInfo
-		exchangeCurrency.CurrencyName = common.StringToUpper(x)
-		exchangeCurrency.TotalValue = y.Available + y.OnHold
-		exchangeCurrency.Hold = y.OnHold
-		response.Currencies = append(response.Currencies, exchangeCurrency)
-	}
-
-	return response, nil
+	return currencies
 }
 
 // GetExchangeFundTransferHistory returns funding history, deposits and
@@ -166,6 +177,18 @@ func (b *Bitfinex) GetExchangeFundTransferHistory() ([]exchange.FundHistory, err
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (b *Bitfinex) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (b *Bitfinex) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (b *Bitfinex) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -173,6 +196,12 @@ func (b *Bitfinex) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (b *Bitfinex) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (b *Bitfinex) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -189,6 +218,58 @@ func (b *Bitfinex) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple exchange wallet orders in a single
+// request
+func (b *Bitfinex) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	results := make([]exchange.BatchOrderResult, len(orders))
+	placeOrders := make([]PlaceOrder, len(orders))
+	for x := range orders {
+		results[x].ClientID = orders[x].ClientID
+
+		orderType := "exchange limit"
+		if orders[x].OrderType == exchange.OrderTypeMarket() {
+			orderType = "exchange market"
+		}
+
+		placeOrders[x] = PlaceOrder{
+			Symbol:   exchange.FormatExchangeCurrency(b.Name, orders[x].Pair).String(),
+			Amount:   orders[x].Amount,
+			Price:    orders[x].Price,
+			Exchange: "bitfinex",
+			Side:     common.StringToLower(string(orders[x].Side)),
+			Type:     orderType,
+		}
+	}
+
+	resp, err := b.NewOrderMulti(placeOrders)
+	for x := range results {
+		switch {
+		case err != nil:
+			results[x].Err = err
+		case x < len(resp.Orders):
+			results[x].OrderID = resp.Orders[x].ID
+		default:
+			results[x].Err = errors.New("order not acknowledged by exchange")
+		}
+	}
+	return results
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers in a single
+// request, the orders are cancelled or rejected together
+func (b *Bitfinex) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	if len(orderIDs) == 0 {
+		return nil
+	}
+
+	_, err := b.CancelMultipleOrders(orderIDs)
+	return exchange.NewBatchCancelResults(orderIDs, err)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (b *Bitfinex) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -200,6 +281,11 @@ func (b *Bitfinex) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, er
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (b *Bitfinex) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (b *Bitfinex) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -216,9 +302,40 @@ func (b *Bitfinex) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (b *Bitfinex) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
+// TransferWalletFunds moves funds between the exchange, margin (trading) and
+// funding (deposit) wallets
+func (b *Bitfinex) TransferWalletFunds(transfer exchange.WalletTransfer) (string, error) {
+	err := transfer.Validate(exchange.WalletExchange, exchange.WalletMargin, exchange.WalletFunding)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := b.WalletTransfer(transfer.Amount,
+		transfer.Currency.Upper().String(),
+		bitfinexWalletTypes[transfer.From],
+		bitfinexWalletTypes[transfer.To])
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp) == 0 {
+		return "", errors.New("no wallet transfer response returned")
+	}
+
+	if resp[0].Status != "success" {
+		return "", errors.New(resp[0].Message)
+	}
+	return "", nil
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (b *Bitfinex) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (b *Bitfinex) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -227,6 +344,11 @@ func (b *Bitfinex) GetWebsocket() (*exchange.Websocket, error) {
 	return b.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (b *Bitfinex) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (b *Bitfinex) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return b.GetFee(feeBuilder)
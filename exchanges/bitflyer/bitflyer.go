@@ -3,7 +3,6 @@ package bitflyer
 import (
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"strconv"
 	"time"
@@ -101,7 +100,7 @@ func (b *Bitflyer) SetDefaults() {
 }
 
 // Setup takes in the supplied exchange configuration details and sets params
-func (b *Bitflyer) Setup(exch config.ExchangeConfig) {
+func (b *Bitflyer) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		b.SetEnabled(false)
 	} else {
@@ -118,25 +117,34 @@ func (b *Bitflyer) Setup(exch config.ExchangeConfig) {
 		b.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := b.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = b.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetLatestBlockCA returns the latest block information from bitflyer chain
@@ -385,7 +393,7 @@ func (b *Bitflyer) SendHTTPRequest(path string, result interface{}) error {
 func (b *Bitflyer) SendAuthHTTPRequest(path string, params url.Values, result interface{}) {
 	headers := make(map[string]string)
 	headers["ACCESS-KEY"] = b.APIKey
-	headers["ACCESS-TIMESTAMP"] = strconv.FormatInt(int64(time.Now().UnixNano()), 10)
+	headers["ACCESS-TIMESTAMP"] = strconv.FormatInt(int64(b.GetAdjustedTime().UnixNano()), 10)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
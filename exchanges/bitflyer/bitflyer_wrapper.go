@@ -1,34 +1,46 @@
 package bitflyer
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Bitflyer exchange
+func init() {
+	exchange.Register("Bitflyer", func() exchange.IBotExchange { return new(Bitflyer) })
+}
+
 // Start starts the Bitflyer go routine
-func (b *Bitflyer) Start(wg *sync.WaitGroup) {
+func (b *Bitflyer) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		b.Run()
+		b.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the Bitflyer wrapper
-func (b *Bitflyer) Run() {
+func (b *Bitflyer) Run(ctx context.Context) {
 	if b.Verbose {
 		log.Printf("%s Websocket: %s.", b.GetName(), common.IsEnabled(b.Websocket.IsEnabled()))
 		log.Printf("%s polling delay: %ds.\n", b.GetName(), b.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	/*
 		marketInfo, err := b.GetMarkets()
 		if err != nil {
@@ -48,13 +60,18 @@ func (b *Bitflyer) Run() {
 	*/
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (b *Bitflyer) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (b *Bitflyer) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
 
 	p = b.CheckFXString(p)
 
-	tickerNew, err := b.GetTicker(p.Pair().String())
+	tickerNew, err := b.GetTicker(b.FormatPair(p, exchange.PairFormatRequest))
 	if err != nil {
 		return tickerPrice, err
 	}
@@ -103,7 +120,7 @@ func (b *Bitflyer) UpdateOrderbook(p pair.CurrencyPair, assetType string) (order
 
 	p = b.CheckFXString(p)
 
-	orderbookNew, err := b.GetOrderBook(p.Pair().String())
+	orderbookNew, err := b.GetOrderBook(b.FormatPair(p, exchange.PairFormatRequest))
 	if err != nil {
 		return orderBook, err
 	}
@@ -116,7 +133,7 @@ func (b *Bitflyer) UpdateOrderbook(p pair.CurrencyPair, assetType string) (order
 		orderBook.Bids = append(orderBook.Bids, orderbook.Item{Price: orderbookNew.Bids[x].Price, Amount: orderbookNew.Bids[x].Size})
 	}
 
-	orderbook.ProcessOrderbook(b.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(b.GetName(), p, b.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(b.Name, p, assetType)
 }
 
@@ -145,6 +162,18 @@ func (b *Bitflyer) GetExchangeFundTransferHistory() ([]exchange.FundHistory, err
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (b *Bitflyer) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (b *Bitflyer) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (b *Bitflyer) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -152,6 +181,12 @@ func (b *Bitflyer) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (b *Bitflyer) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (b *Bitflyer) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -168,6 +203,18 @@ func (b *Bitflyer) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (b *Bitflyer) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, b.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (b *Bitflyer) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, b.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (b *Bitflyer) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -179,6 +226,11 @@ func (b *Bitflyer) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, er
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (b *Bitflyer) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (b *Bitflyer) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -196,9 +248,14 @@ func (b *Bitflyer) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (b *Bitflyer) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (b *Bitflyer) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (b *Bitflyer) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -207,6 +264,16 @@ func (b *Bitflyer) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (b *Bitflyer) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
+// GetFeeByType returns an estimate of fee based on type of transaction
+func (b *Bitflyer) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
+	return b.GetFee(feeBuilder)
+}
+
 // GetWithdrawCapabilities returns the types of withdrawal methods permitted by the exchange
 func (b *Bitflyer) GetWithdrawCapabilities() uint32 {
 	return b.GetWithdrawPermissions()
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"strconv"
 	"time"
@@ -81,7 +80,7 @@ func (b *Bithumb) SetDefaults() {
 }
 
 // Setup takes in the supplied exchange configuration details and sets params
-func (b *Bithumb) Setup(exch config.ExchangeConfig) {
+func (b *Bithumb) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		b.SetEnabled(false)
 	} else {
@@ -98,25 +97,34 @@ func (b *Bithumb) Setup(exch config.ExchangeConfig) {
 		b.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := b.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = b.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetTradablePairs returns a list of tradable currencies
@@ -547,7 +555,7 @@ func (b *Bithumb) SendAuthenticatedHTTPRequest(path string, params url.Values, r
 	}
 
 	if b.Nonce.Get() == 0 {
-		b.Nonce.Set(time.Now().UnixNano() / int64(time.Millisecond))
+		b.Nonce.Set(b.GetAdjustedTime().UnixNano() / int64(time.Millisecond))
 	} else {
 		b.Nonce.Inc()
 	}
@@ -1,34 +1,46 @@
 package bithumb
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Bithumb exchange
+func init() {
+	exchange.Register("Bithumb", func() exchange.IBotExchange { return new(Bithumb) })
+}
+
 // Start starts the OKEX go routine
-func (b *Bithumb) Start(wg *sync.WaitGroup) {
+func (b *Bithumb) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		b.Run()
+		b.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the OKEX wrapper
-func (b *Bithumb) Run() {
+func (b *Bithumb) Run(ctx context.Context) {
 	if b.Verbose {
 		log.Printf("%s Websocket: %s. (url: %s).\n", b.GetName(), common.IsEnabled(b.Websocket.IsEnabled()), b.WebsocketURL)
 		log.Printf("%s polling delay: %ds.\n", b.GetName(), b.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := b.GetTradingPairs()
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", b.GetName())
@@ -40,6 +52,11 @@ func (b *Bithumb) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (b *Bithumb) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetTradingPairs gets the available trading currencies
 func (b *Bithumb) GetTradingPairs() ([]string, error) {
 	currencies, err := b.GetTradablePairs()
@@ -114,7 +131,7 @@ func (b *Bithumb) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderb
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: asks.Quantity, Price: asks.Price})
 	}
 
-	orderbook.ProcessOrderbook(b.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(b.GetName(), p, b.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(b.Name, p, assetType)
 }
 
@@ -132,6 +149,18 @@ func (b *Bithumb) GetExchangeFundTransferHistory() ([]exchange.FundHistory, erro
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (b *Bithumb) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (b *Bithumb) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (b *Bithumb) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -139,6 +168,12 @@ func (b *Bithumb) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]e
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (b *Bithumb) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (b *Bithumb) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -155,6 +190,18 @@ func (b *Bithumb) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (b *Bithumb) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, b.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (b *Bithumb) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, b.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (b *Bithumb) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -166,6 +213,11 @@ func (b *Bithumb) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, err
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (b *Bithumb) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (b *Bithumb) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -183,9 +235,14 @@ func (b *Bithumb) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount f
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (b *Bithumb) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (b *Bithumb) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (b *Bithumb) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -194,6 +251,11 @@ func (b *Bithumb) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (b *Bithumb) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (b *Bithumb) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return b.GetFee(feeBuilder)
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
 	"strconv"
 	"time"
 
@@ -27,6 +26,9 @@ const (
 	bitmexAPIURL        = "https://www.bitmex.com/api/v1"
 	bitmexAPItestnetURL = "https://testnet.bitmex.com/api/v1"
 
+	// bitmexMinLeverage is the lowest isolated margin leverage accepted
+	bitmexMinLeverage = 0.01
+
 	// Public endpoints
 	bitmexEndpointAnnouncement              = "/announcement"
 	bitmexEndpointAnnouncementUrgent        = "/announcement/urgent"
@@ -108,6 +110,17 @@ const (
 	ContractUpsideProfit
 )
 
+// Instrument type codes returned by the instrument endpoints
+const (
+	bitmexInstrumentPerpetual      = "FFWCSX"
+	bitmexInstrumentFutures        = "FFCCSX"
+	bitmexInstrumentDownsideProfit = "OPECCS"
+	bitmexInstrumentUpsideProfit   = "OCECCS"
+
+	// bitmexSatoshisPerBitcoin is used to convert XBt denominated margin values
+	bitmexSatoshisPerBitcoin = 100000000
+)
+
 // SetDefaults sets the basic defaults for Bitmex
 func (b *Bitmex) SetDefaults() {
 	b.Name = "Bitmex"
@@ -131,7 +144,7 @@ func (b *Bitmex) SetDefaults() {
 }
 
 // Setup takes in the supplied exchange configuration details and sets params
-func (b *Bitmex) Setup(exch config.ExchangeConfig) {
+func (b *Bitmex) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		b.SetEnabled(false)
 	} else {
@@ -146,23 +159,31 @@ func (b *Bitmex) Setup(exch config.ExchangeConfig) {
 		b.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := b.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = b.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = b.WebsocketSetup(b.WsConnector,
 			exch.Name,
@@ -170,9 +191,10 @@ func (b *Bitmex) Setup(exch config.ExchangeConfig) {
 			bitmexWSURL,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 	}
+	return nil
 }
 
 // GetAnnouncement returns the general announcements from Bitmex
@@ -317,6 +339,44 @@ func (b *Bitmex) GetActiveInstruments(params GenericRequestParams) ([]Instrument
 		&activeInstruments)
 }
 
+// GetContractType returns the contract type for a Bitmex instrument type code
+func GetContractType(instrumentType string) (int, error) {
+	switch instrumentType {
+	case bitmexInstrumentPerpetual:
+		return ContractPerpetual, nil
+	case bitmexInstrumentFutures:
+		return ContractFutures, nil
+	case bitmexInstrumentDownsideProfit:
+		return ContractDownsideProfit, nil
+	case bitmexInstrumentUpsideProfit:
+		return ContractUpsideProfit, nil
+	}
+	return 0, fmt.Errorf("unknown instrument type %s", instrumentType)
+}
+
+// GetActiveContracts returns active instruments matching the supplied contract
+// type e.g. ContractPerpetual or ContractFutures
+func (b *Bitmex) GetActiveContracts(contractType int) ([]Instrument, error) {
+	instruments, err := b.GetActiveInstruments(GenericRequestParams{})
+	if err != nil {
+		return nil, err
+	}
+	return filterContracts(instruments, contractType), nil
+}
+
+// filterContracts returns the instruments matching the contract type
+func filterContracts(instruments []Instrument, contractType int) []Instrument {
+	var contracts []Instrument
+	for x := range instruments {
+		typ, err := GetContractType(instruments[x].Typ)
+		if err != nil || typ != contractType {
+			continue
+		}
+		contracts = append(contracts, instruments[x])
+	}
+	return contracts
+}
+
 // GetActiveAndIndexInstruments returns all active instruments and all indices
 func (b *Bitmex) GetActiveAndIndexInstruments() ([]Instrument, error) {
 	var activeAndIndices []Instrument
@@ -744,6 +804,16 @@ func (b *Bitmex) GetUserMargin(currency string) (UserMargin, error) {
 		&info)
 }
 
+// GetAllUserMargin returns user margin information for all currencies
+func (b *Bitmex) GetAllUserMargin() ([]UserMargin, error) {
+	var info []UserMargin
+
+	return info, b.SendAuthenticatedHTTPRequest("GET",
+		bitmexEndpointUserMargin,
+		UserCurrencyParams{Currency: "all"},
+		&info)
+}
+
 // GetMinimumWithdrawalFee returns minimum withdrawal fee information
 func (b *Bitmex) GetMinimumWithdrawalFee(currency string) (MinWithdrawalFee, error) {
 	var fee MinWithdrawalFee
@@ -847,7 +917,7 @@ func (b *Bitmex) SendAuthenticatedHTTPRequest(verb, path string, params Paramete
 			b.Name)
 	}
 
-	timestamp := time.Now().Add(time.Second * 10).UnixNano()
+	timestamp := b.GetAdjustedTime().Add(time.Second * 10).UnixNano()
 	timestampStr := strconv.FormatInt(timestamp, 10)
 	timestampNew := timestampStr[:13]
 
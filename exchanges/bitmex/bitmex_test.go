@@ -1,11 +1,13 @@
 package bitmex
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 )
@@ -39,7 +41,7 @@ func TestSetup(t *testing.T) {
 
 func TestStart(t *testing.T) {
 	var testWg sync.WaitGroup
-	b.Start(&testWg)
+	b.Start(context.Background(), &testWg)
 	testWg.Wait()
 }
 
@@ -461,3 +463,74 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestGetContractType(t *testing.T) {
+	typ, err := GetContractType("FFWCSX")
+	if err != nil || typ != ContractPerpetual {
+		t.Error("Test Failed - GetContractType() perpetual contract not matched", err)
+	}
+
+	typ, err = GetContractType("FFCCSX")
+	if err != nil || typ != ContractFutures {
+		t.Error("Test Failed - GetContractType() futures contract not matched", err)
+	}
+
+	_, err = GetContractType("MRCXXX")
+	if err == nil {
+		t.Error("Test Failed - GetContractType() index type returned no error")
+	}
+}
+
+func TestFilterContracts(t *testing.T) {
+	instruments := []Instrument{
+		{Symbol: "XBTUSD", Typ: "FFWCSX"},
+		{Symbol: "XBTZ18", Typ: "FFCCSX"},
+		{Symbol: ".BXBT", Typ: "MRCXXX"},
+	}
+
+	perpetuals := filterContracts(instruments, ContractPerpetual)
+	if len(perpetuals) != 1 || perpetuals[0].Symbol != "XBTUSD" {
+		t.Error("Test Failed - filterContracts() perpetual contracts incorrect")
+	}
+
+	futures := filterContracts(instruments, ContractFutures)
+	if len(futures) != 1 || futures[0].Symbol != "XBTZ18" {
+		t.Error("Test Failed - filterContracts() futures contracts incorrect")
+	}
+}
+
+func TestConvertUserMargin(t *testing.T) {
+	info := convertUserMargin(UserMargin{
+		Currency:        "XBt",
+		WalletBalance:   150000000,
+		AvailableMargin: 100000000,
+	})
+	if info.CurrencyName != symbol.BTC || info.Free != 1 || info.Locked != 0.5 || info.Total != 1.5 {
+		t.Errorf("Test Failed - convertUserMargin() unexpected result %+v", info)
+	}
+}
+
+func TestGetExchangeAccountInfo(t *testing.T) {
+	_, err := b.GetExchangeAccountInfo()
+	if err == nil {
+		t.Error("test failed - GetExchangeAccountInfo() error", err)
+	}
+}
+
+func TestSetLeverage(t *testing.T) {
+	_, err := b.SetLeverage(exchange.Leverage{
+		Pair:       pair.NewCurrencyPair("XBT", "USD"),
+		Leverage:   500,
+		MarginMode: exchange.MarginModeIsolated,
+	})
+	if err == nil {
+		t.Error("Test Failed - SetLeverage() leverage outside of range accepted")
+	}
+}
+
+func TestLeverageInterface(t *testing.T) {
+	var exch exchange.IBotExchange = &b
+	if _, ok := exch.(exchange.ILeverageTrader); !ok {
+		t.Error("Test Failed - Bitmex does not implement ILeverageTrader")
+	}
+}
@@ -71,6 +71,7 @@ func (b *Bitmex) WsConnector() error {
 
 	var dialer websocket.Dialer
 	var err error
+	b.Websocket.ConfigureDialer(&dialer)
 
 	if b.Websocket.GetProxyAddress() != "" {
 		proxy, err := url.Parse(b.Websocket.GetProxyAddress())
@@ -86,7 +87,7 @@ func (b *Bitmex) WsConnector() error {
 		return err
 	}
 
-	_, p, err := b.WebsocketConn.ReadMessage()
+	_, p, err := b.Websocket.ReadMessage(b.WebsocketConn)
 	if err != nil {
 		return err
 	}
@@ -144,7 +145,7 @@ func (b *Bitmex) wsReadData() {
 			return
 
 		default:
-			_, resp, err := b.WebsocketConn.ReadMessage()
+			_, resp, err := b.Websocket.ReadMessage(b.WebsocketConn)
 			if err != nil {
 				b.Websocket.DataHandler <- fmt.Errorf("bitmex_websocket.go - websocket connection Error: %s",
 					err)
@@ -261,6 +262,7 @@ func (b *Bitmex) wsHandleIncomingData() {
 						}
 
 						b.Websocket.DataHandler <- exchange.TradeData{
+							TradeID:      trade.TrdMatchID,
 							Timestamp:    timestamp,
 							Price:        trade.Price,
 							Amount:       float64(trade.Size),
@@ -408,11 +410,11 @@ func (b *Bitmex) websocketSubscribe() error {
 	for _, contract := range contracts {
 		// Orderbook subscribe
 		subscriber.Arguments = append(subscriber.Arguments,
-			bitmexWSOrderbookL2+":"+contract.Pair().String())
+			bitmexWSOrderbookL2+":"+b.FormatPair(contract, exchange.PairFormatRequest))
 
 		// Trade subscribe
 		subscriber.Arguments = append(subscriber.Arguments,
-			bitmexWSTrade+":"+contract.Pair().String())
+			bitmexWSTrade+":"+b.FormatPair(contract, exchange.PairFormatRequest))
 
 		// NOTE more added here in future
 	}
@@ -1,35 +1,49 @@
 package bitmex
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Bitmex exchange
+func init() {
+	exchange.Register("Bitmex", func() exchange.IBotExchange { return new(Bitmex) })
+}
+
 // Start starts the Bitmex go routine
-func (b *Bitmex) Start(wg *sync.WaitGroup) {
+func (b *Bitmex) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		b.Run()
+		b.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the Bitmex wrapper
-func (b *Bitmex) Run() {
+func (b *Bitmex) Run(ctx context.Context) {
 	if b.Verbose {
 		log.Printf("%s Websocket: %s. (url: %s).\n", b.GetName(), common.IsEnabled(b.Websocket.IsEnabled()), b.WebsocketURL)
 		log.Printf("%s polling delay: %ds.\n", b.GetName(), b.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	marketInfo, err := b.GetActiveInstruments(GenericRequestParams{})
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", b.GetName())
@@ -47,6 +61,11 @@ func (b *Bitmex) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (b *Bitmex) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (b *Bitmex) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
@@ -117,7 +136,7 @@ func (b *Bitmex) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbo
 			continue
 		}
 	}
-	orderbook.ProcessOrderbook(b.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(b.GetName(), p, b.TruncateOrderbook(p, orderBook), assetType)
 
 	return orderbook.GetOrderbook(b.Name, p, assetType)
 }
@@ -126,7 +145,32 @@ func (b *Bitmex) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbo
 // Bitmex exchange
 func (b *Bitmex) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	var response exchange.AccountInfo
-	return response, errors.New("not implemented")
+	response.ExchangeName = b.GetName()
+
+	margins, err := b.GetAllUserMargin()
+	if err != nil {
+		return response, err
+	}
+
+	for x := range margins {
+		response.Currencies = append(response.Currencies,
+			convertUserMargin(margins[x]))
+	}
+	return response, nil
+}
+
+// convertUserMargin converts a Bitmex margin account into account currency
+// info, Bitmex reports bitcoin balances in satoshis (XBt)
+func convertUserMargin(margin UserMargin) exchange.AccountCurrencyInfo {
+	free := float64(margin.AvailableMargin)
+	locked := float64(margin.WalletBalance - margin.AvailableMargin)
+	currency := common.StringToUpper(margin.Currency)
+	if margin.Currency == "XBt" {
+		currency = symbol.BTC
+		free /= bitmexSatoshisPerBitcoin
+		locked /= bitmexSatoshisPerBitcoin
+	}
+	return exchange.NewAccountCurrencyInfo(currency, free, locked)
 }
 
 // GetExchangeFundTransferHistory returns funding history, deposits and
@@ -136,6 +180,18 @@ func (b *Bitmex) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (b *Bitmex) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (b *Bitmex) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (b *Bitmex) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -143,6 +199,12 @@ func (b *Bitmex) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]ex
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (b *Bitmex) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (b *Bitmex) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -159,6 +221,18 @@ func (b *Bitmex) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (b *Bitmex) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, b.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (b *Bitmex) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, b.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (b *Bitmex) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -170,9 +244,18 @@ func (b *Bitmex) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, erro
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (b *Bitmex) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (b *Bitmex) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
-	return "", errors.New("not yet implemented")
+	if cryptocurrency.String() != symbol.BTC {
+		return "", fmt.Errorf("%s deposits not supported on exchange",
+			cryptocurrency)
+	}
+	return b.GetDepositAddress("XBt")
 }
 
 // WithdrawCryptoExchangeFunds returns a withdrawal ID when a withdrawal is
@@ -187,6 +270,108 @@ func (b *Bitmex) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount fl
 	return "", errors.New("not yet implemented")
 }
 
+// WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a withdrawal is
+// submitted
+func (b *Bitmex) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
+	return "", errors.New("not yet implemented")
+}
+
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (b *Bitmex) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
+// GetLeverage returns the leverage and margin mode of a pair and the leverage
+// range the exchange allows for it. Pairs without a position use cross margin
+func (b *Bitmex) GetLeverage(p pair.CurrencyPair, assetType string) (exchange.Leverage, error) {
+	leverage, err := b.getLeverageRange(p, assetType)
+	if err != nil {
+		return leverage, err
+	}
+
+	instrument := exchange.FormatExchangeCurrency(b.Name, p).String()
+	positions, err := b.GetPositions(PositionGetParams{
+		Filter: fmt.Sprintf(`{"symbol":%q}`, instrument),
+	})
+	if err != nil {
+		return leverage, err
+	}
+
+	leverage.MarginMode = exchange.MarginModeCross
+	leverage.Leverage = leverage.MaxLeverage
+	for x := range positions {
+		if positions[x].Symbol != instrument {
+			continue
+		}
+		leverage.Leverage = positions[x].Leverage
+		if !positions[x].CrossMargin {
+			leverage.MarginMode = exchange.MarginModeIsolated
+		}
+	}
+	return leverage, nil
+}
+
+// SetLeverage sets the leverage and margin mode of a pair, returning the
+// applied setting. Cross margin always uses the maximum leverage of the
+// instrument
+func (b *Bitmex) SetLeverage(l exchange.Leverage) (exchange.Leverage, error) {
+	allowed, err := b.getLeverageRange(l.Pair, l.AssetType)
+	if err != nil {
+		return exchange.Leverage{}, err
+	}
+	l.MinLeverage = allowed.MinLeverage
+	l.MaxLeverage = allowed.MaxLeverage
+
+	err = exchange.ValidateLeverage(l)
+	if err != nil {
+		return exchange.Leverage{}, err
+	}
+
+	params := PositionUpdateLeverageParams{
+		Symbol: exchange.FormatExchangeCurrency(b.Name, l.Pair).String(),
+	}
+	if l.MarginMode == exchange.MarginModeIsolated {
+		params.Leverage = l.Leverage
+	} else if l.Leverage != 0 && l.Leverage != l.MaxLeverage {
+		return exchange.Leverage{}, errors.New("bitmex cross margin uses the maximum leverage of the instrument")
+	}
+
+	position, err := b.LeveragePosition(params)
+	if err != nil {
+		return exchange.Leverage{}, err
+	}
+
+	l.Leverage = position.Leverage
+	l.MarginMode = exchange.MarginModeIsolated
+	if position.CrossMargin {
+		l.MarginMode = exchange.MarginModeCross
+	}
+	return l, nil
+}
+
+// getLeverageRange returns the leverage range of a pair, the maximum leverage
+// being the inverse of the instrument's initial margin requirement
+func (b *Bitmex) getLeverageRange(p pair.CurrencyPair, assetType string) (exchange.Leverage, error) {
+	instrument := exchange.FormatExchangeCurrency(b.Name, p).String()
+	instruments, err := b.GetActiveInstruments(GenericRequestParams{Symbol: instrument})
+	if err != nil {
+		return exchange.Leverage{}, err
+	}
+
+	for x := range instruments {
+		if instruments[x].Symbol != instrument || instruments[x].InitMargin <= 0 {
+			continue
+		}
+		return exchange.Leverage{
+			Pair:        p,
+			AssetType:   assetType,
+			MinLeverage: bitmexMinLeverage,
+			MaxLeverage: math.Floor(1/instruments[x].InitMargin*100) / 100,
+		}, nil
+	}
+	return exchange.Leverage{}, fmt.Errorf("bitmex instrument %s not found", instrument)
+}
+
 // WithdrawExchangeFiatFundsToInternationalBank returns a withdrawal ID when a withdrawal is
 // submitted
 func (b *Bitmex) WithdrawExchangeFiatFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
@@ -198,6 +383,11 @@ func (b *Bitmex) GetWebsocket() (*exchange.Websocket, error) {
 	return b.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (b *Bitmex) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (b *Bitmex) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return b.GetFee(feeBuilder)
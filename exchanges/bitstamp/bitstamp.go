@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -39,6 +40,7 @@ const (
 	bitstampAPIBitcoinWithdrawal  = "bitcoin_withdrawal"
 	bitstampAPILTCWithdrawal      = "ltc_withdrawal"
 	bitstampAPIETHWithdrawal      = "eth_withdrawal"
+	bitstampAPIOpenWithdrawal     = "withdrawal/open"
 	bitstampAPIBitcoinDeposit     = "bitcoin_deposit_address"
 	bitstampAPILitecoinDeposit    = "ltc_address"
 	bitstampAPIEthereumDeposit    = "eth_address"
@@ -50,6 +52,26 @@ const (
 	bitstampAPIReturnType         = "string"
 	bitstampAPITradingPairsInfo   = "trading-pairs-info"
 
+	bitstampTransactionDeposit            = 0
+	bitstampTransactionWithdrawal         = 1
+	bitstampTransactionSubAccountTransfer = 14
+	bitstampTransactionsLimit             = 1000
+	bitstampTimeLayout                    = "2006-01-02 15:04:05"
+
+	bitstampWithdrawalSEPA          = "sepa"
+	bitstampWithdrawalInternational = "international"
+
+	// Withdrawal request statuses
+	bitstampWithdrawalOpen      = 0
+	bitstampWithdrawalInProcess = 1
+	bitstampWithdrawalFinished  = 2
+	bitstampWithdrawalCancelled = 3
+	bitstampWithdrawalFailed    = 4
+
+	// bitstampWithdrawalTimedelta is how far back in seconds withdrawal
+	// requests are searched when tracking fiat transfers
+	bitstampWithdrawalTimedelta = 60 * 60 * 24 * 30
+
 	bitstampAuthRate   = 600
 	bitstampUnauthRate = 600
 )
@@ -81,11 +103,16 @@ func (b *Bitstamp) SetDefaults() {
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	b.APIUrlDefault = bitstampAPIURL
 	b.APIUrl = b.APIUrlDefault
+	b.SetFeeTable(exchange.FeeTable{Maker: 0.0025, Taker: 0.0025})
+	b.SetFiatWithdrawalSupport(
+		exchange.FiatWithdrawalSupport{Type: exchange.FiatWithdrawalSEPA, Currencies: []string{"EUR"}},
+		exchange.FiatWithdrawalSupport{Type: exchange.FiatWithdrawalSWIFT, Currencies: []string{"USD", "EUR", "GBP"}},
+	)
 	b.WebsocketInit()
 }
 
 // Setup sets configuration values to bitstamp
-func (b *Bitstamp) Setup(exch config.ExchangeConfig) {
+func (b *Bitstamp) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		b.SetEnabled(false)
 	} else {
@@ -102,23 +129,31 @@ func (b *Bitstamp) Setup(exch config.ExchangeConfig) {
 		b.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := b.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = b.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = b.WebsocketSetup(b.WsConnect,
 			exch.Name,
@@ -126,24 +161,27 @@ func (b *Bitstamp) Setup(exch config.ExchangeConfig) {
 			BitstampPusherKey,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 	}
+	return nil
 }
 
 // GetFee returns an estimate of fee based on type of transaction
 func (b *Bitstamp) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
+	if b.IsFeeEstimated(feeBuilder) {
+		return b.EstimateFee(feeBuilder)
+	}
+
 	var fee float64
 
 	switch feeBuilder.FeeType {
 	case exchange.CryptocurrencyTradeFee:
 		var err error
-
-		b.Balance, err = b.GetBalance()
+		fee, err = b.AccountTradingFee(feeBuilder, b.getAccountFees)
 		if err != nil {
 			return 0, err
 		}
-		fee = b.CalculateTradingFee(feeBuilder.FirstCurrency+feeBuilder.SecondCurrency, feeBuilder.PurchasePrice, feeBuilder.Amount)
 	case exchange.CyptocurrencyDepositFee:
 		fee = 0
 	case exchange.InternationalBankDepositFee:
@@ -157,6 +195,44 @@ func (b *Bitstamp) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return fee, nil
 }
 
+// getAccountFees retrieves the trading fee of each pair from the fee fields of
+// the account balance
+func (b *Bitstamp) getAccountFees() (exchange.AccountFees, error) {
+	var raw map[string]interface{}
+	err := b.SendAuthenticatedHTTPRequest(bitstampAPIBalance, true, nil, &raw)
+	if err != nil {
+		return exchange.AccountFees{}, err
+	}
+	return convertAccountFees(raw), nil
+}
+
+// convertAccountFees converts the <pair>_fee percentages of an account balance
+// to account fees. Bitstamp charges makers and takers alike, the account rate
+// is the fee field when returned or otherwise the BTCUSD fee
+func convertAccountFees(raw map[string]interface{}) exchange.AccountFees {
+	fees := exchange.AccountFees{Pairs: make(map[string]exchange.AccountFeeRate)}
+	for k, v := range raw {
+		if !strings.HasSuffix(k, "_fee") {
+			continue
+		}
+
+		rate := parseTransactionValue(v) / 100
+		fees.Pairs[common.StringToUpper(strings.TrimSuffix(k, "_fee"))] =
+			exchange.AccountFeeRate{Maker: rate, Taker: rate}
+	}
+
+	account, ok := fees.Pairs[symbol.BTC+symbol.USD]
+	if _, found := raw["fee"]; found {
+		rate := parseTransactionValue(raw["fee"]) / 100
+		account = exchange.AccountFeeRate{Maker: rate, Taker: rate}
+		ok = true
+	}
+	if ok {
+		fees.AccountFeeRate = account
+	}
+	return fees
+}
+
 // getInternationalBankWithdrawalFee returns international withdrawal fee
 func getInternationalBankWithdrawalFee(amount float64) float64 {
 	fee := amount * 0.0009
@@ -222,7 +298,7 @@ func (b *Bitstamp) GetTicker(currency string, hourly bool) (Ticker, error) {
 
 // GetOrderbook Returns a JSON dictionary with "bids" and "asks". Each is a list
 // of open orders and each order is represented as a list holding the price and
-//the amount.
+// the amount.
 func (b *Bitstamp) GetOrderbook(currency string) (Orderbook, error) {
 	type response struct {
 		Timestamp int64      `json:"timestamp,string"`
@@ -310,6 +386,69 @@ func (b *Bitstamp) GetTransactions(currencyPair string, values url.Values) ([]Tr
 	return transactions, b.SendHTTPRequest(path, &transactions)
 }
 
+// GetTransactionsByTimeRange returns the transactions from the last day
+// executed between start and end inclusive
+func (b *Bitstamp) GetTransactionsByTimeRange(currencyPair string, start, end time.Time) ([]Transactions, error) {
+	values := url.Values{}
+	values.Set("time", "day")
+	transactions, err := b.GetTransactions(currencyPair, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Transactions
+	for x := range transactions {
+		if transactions[x].Date >= start.Unix() && transactions[x].Date <= end.Unix() {
+			result = append(result, transactions[x])
+		}
+	}
+	return result, nil
+}
+
+// GetVerifiedTradeHistory returns the trade history of the last hour for a
+// currency pair verified by timestamp continuity. Bitstamp trade IDs are
+// shared across all currency pairs so gaps in a pair's trade IDs don't
+// indicate missing trades, instead periods without trades longer than maxGap
+// are refetched from the last day's transactions
+func (b *Bitstamp) GetVerifiedTradeHistory(currencyPair string, maxGap time.Duration) ([]exchange.TradeHistory, []exchange.TradeTimeGap, error) {
+	values := url.Values{}
+	values.Set("time", "hour")
+	transactions, err := b.GetTransactions(currencyPair, values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return exchange.FillTradeTimeGaps(b.convertTransactions(transactions),
+		func(start, end time.Time) ([]exchange.TradeHistory, error) {
+			fetched, err := b.GetTransactionsByTimeRange(currencyPair, start, end)
+			if err != nil {
+				return nil, err
+			}
+			return b.convertTransactions(fetched), nil
+		}, maxGap, 0)
+}
+
+// convertTransactions converts Bitstamp transactions into trade history
+func (b *Bitstamp) convertTransactions(transactions []Transactions) []exchange.TradeHistory {
+	var trades []exchange.TradeHistory
+	for x := range transactions {
+		side := string(exchange.OrderSideBuy())
+		if transactions[x].Type == 1 {
+			side = string(exchange.OrderSideSell())
+		}
+
+		trades = append(trades, exchange.TradeHistory{
+			Timestamp: transactions[x].Date,
+			TID:       transactions[x].TradeID,
+			Price:     transactions[x].Price,
+			Amount:    transactions[x].Amount,
+			Exchange:  b.Name,
+			Type:      side,
+		})
+	}
+	return trades
+}
+
 // GetEURUSDConversionRate returns the conversion rate between Euro and USD
 func (b *Bitstamp) GetEURUSDConversionRate() (EURUSDConversionRate, error) {
 	rate := EURUSDConversionRate{}
@@ -321,9 +460,8 @@ func (b *Bitstamp) GetEURUSDConversionRate() (EURUSDConversionRate, error) {
 // GetBalance returns full balance of currency held on the exchange
 func (b *Bitstamp) GetBalance() (Balances, error) {
 	balance := Balances{}
-	path := fmt.Sprintf("%s/%s", b.APIUrl, bitstampAPIBalance)
-
-	return balance, b.SendHTTPRequest(path, &balance)
+	return balance,
+		b.SendAuthenticatedHTTPRequest(bitstampAPIBalance, true, nil, &balance)
 }
 
 // GetUserTransactions returns an array of transactions
@@ -342,7 +480,7 @@ func (b *Bitstamp) GetUserTransactions(currencyPair string) ([]UserTransactions,
 	}
 	response := []Response{}
 
-	if currencyPair != "" {
+	if currencyPair == "" {
 		if err := b.SendAuthenticatedHTTPRequest(bitstampAPIUserTransactions, true, url.Values{}, &response); err != nil {
 			return nil, err
 		}
@@ -393,6 +531,85 @@ func (b *Bitstamp) GetUserTransactions(currencyPair string) ([]UserTransactions,
 	return transactions, nil
 }
 
+// GetFundingTransactions returns a page of deposit, withdrawal and sub account
+// transfer transactions, newest first. The number of user transactions in the
+// page, including non funding transactions, is returned for pagination
+func (b *Bitstamp) GetFundingTransactions(offset, limit int64) ([]FundingTransaction, int, error) {
+	var response []map[string]interface{}
+	req := url.Values{}
+	req.Set("offset", strconv.FormatInt(offset, 10))
+	req.Set("limit", strconv.FormatInt(limit, 10))
+	req.Set("sort", "desc")
+
+	err := b.SendAuthenticatedHTTPRequest(bitstampAPIUserTransactions, true, req, &response)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var transactions []FundingTransaction
+	for x := range response {
+		tx, ok := convertFundingTransaction(response[x])
+		if !ok {
+			continue
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, len(response), nil
+}
+
+// convertFundingTransaction converts a raw user transaction into a funding
+// transaction. Bitstamp returns a field for every currency so the currency
+// is the one with a non zero amount
+func convertFundingTransaction(raw map[string]interface{}) (FundingTransaction, bool) {
+	var tx FundingTransaction
+	tx.Type = int(parseTransactionValue(raw["type"]))
+	if tx.Type != bitstampTransactionDeposit &&
+		tx.Type != bitstampTransactionWithdrawal &&
+		tx.Type != bitstampTransactionSubAccountTransfer {
+		return tx, false
+	}
+
+	tx.ID = int64(parseTransactionValue(raw["id"]))
+	tx.Fee = parseTransactionValue(raw["fee"])
+	if date, ok := raw["datetime"].(string); ok {
+		tx.Date = date
+	}
+
+	for k, v := range raw {
+		switch k {
+		case "id", "type", "fee", "datetime", "order_id":
+			continue
+		}
+
+		if common.StringContains(k, "_") {
+			continue
+		}
+
+		amount := parseTransactionValue(v)
+		if amount == 0 {
+			continue
+		}
+
+		tx.Currency = common.StringToUpper(k)
+		tx.Amount = amount
+		return tx, true
+	}
+	return tx, false
+}
+
+// parseTransactionValue parses a transaction value which may be returned as
+// a string or number
+func parseTransactionValue(v interface{}) float64 {
+	switch value := v.(type) {
+	case float64:
+		return value
+	case string:
+		f, _ := strconv.ParseFloat(value, 64)
+		return f
+	}
+	return 0
+}
+
 // GetOpenOrders returns all open orders on the exchange
 func (b *Bitstamp) GetOpenOrders(currencyPair string) ([]Order, error) {
 	resp := []Order{}
@@ -480,15 +697,20 @@ func (b *Bitstamp) GetWithdrawalRequests(timedelta int64) ([]WithdrawalRequests,
 // destTag - only for XRP  default to ""
 // instant - only for bitcoins
 func (b *Bitstamp) CryptoWithdrawal(amount float64, address, symbol, destTag string, instant bool) (string, error) {
+	if amount <= 0 {
+		return "", errors.New("withdrawal amount must be greater than zero")
+	}
+
+	err := validateCryptoAddress(address, symbol)
+	if err != nil {
+		return "", err
+	}
+
 	var req = url.Values{}
 	req.Add("amount", strconv.FormatFloat(amount, 'f', -1, 64))
 	req.Add("address", address)
 
-	type response struct {
-		ID string `json:"id"`
-	}
-	resp := response{}
-
+	resp := WithdrawalResponse{}
 	switch common.StringToLower(symbol) {
 	case "btc":
 		if instant {
@@ -496,23 +718,144 @@ func (b *Bitstamp) CryptoWithdrawal(amount float64, address, symbol, destTag str
 		} else {
 			req.Add("instant", "0")
 		}
-		return resp.ID,
-			b.SendAuthenticatedHTTPRequest(bitstampAPIBitcoinWithdrawal, false, req, &resp)
+		err = b.SendAuthenticatedHTTPRequest(bitstampAPIBitcoinWithdrawal, false, req, &resp)
 	case "ltc":
-		return resp.ID,
-			b.SendAuthenticatedHTTPRequest(bitstampAPILTCWithdrawal, true, req, &resp)
+		err = b.SendAuthenticatedHTTPRequest(bitstampAPILTCWithdrawal, true, req, &resp)
 	case "eth":
-		return resp.ID,
-			b.SendAuthenticatedHTTPRequest(bitstampAPIETHWithdrawal, true, req, &resp)
+		err = b.SendAuthenticatedHTTPRequest(bitstampAPIETHWithdrawal, true, req, &resp)
 	case "xrp":
 		if destTag != "" {
 			req.Add("destination_tag", destTag)
 		}
-		return resp.ID,
-			b.SendAuthenticatedHTTPRequest(bitstampAPIXrpWithdrawal, true, req, &resp)
+		err = b.SendAuthenticatedHTTPRequest(bitstampAPIXrpWithdrawal, true, req, &resp)
+	}
+	if err != nil {
+		return "", err
+	}
+	return getWithdrawalID(resp)
+}
+
+// OpenBankWithdrawal requests a SEPA or international wire withdrawal and
+// returns the withdrawal ID
+func (b *Bitstamp) OpenBankWithdrawal(withdrawal FiatWithdrawalRequest) (string, error) {
+	if withdrawal.Amount <= 0 {
+		return "", errors.New("withdrawal amount must be greater than zero")
+	}
+
+	if withdrawal.Name == "" || withdrawal.IBAN == "" {
+		return "", errors.New("account holder name and IBAN must be supplied")
+	}
+
+	req := url.Values{}
+	req.Add("amount", strconv.FormatFloat(withdrawal.Amount, 'f', -1, 64))
+	req.Add("account_currency", common.StringToUpper(withdrawal.AccountCurrency))
+	req.Add("name", withdrawal.Name)
+	req.Add("iban", withdrawal.IBAN)
+	req.Add("bic", withdrawal.BIC)
+	req.Add("address", withdrawal.Address)
+	req.Add("postal_code", withdrawal.PostalCode)
+	req.Add("city", withdrawal.City)
+	req.Add("country", withdrawal.Country)
+
+	switch withdrawal.Type {
+	case bitstampWithdrawalSEPA:
+		req.Add("type", bitstampWithdrawalSEPA)
+	case bitstampWithdrawalInternational:
+		if withdrawal.BIC == "" || withdrawal.BankName == "" {
+			return "", errors.New("international withdrawals require a BIC and bank name")
+		}
+		req.Add("type", bitstampWithdrawalInternational)
+		req.Add("bank_name", withdrawal.BankName)
+		req.Add("bank_address", withdrawal.BankAddress)
+		req.Add("bank_postal_code", withdrawal.BankPostalCode)
+		req.Add("bank_city", withdrawal.BankCity)
+		req.Add("bank_country", withdrawal.BankCountry)
+		req.Add("currency", common.StringToUpper(withdrawal.Currency))
+	default:
+		return "", fmt.Errorf("unsupported bank withdrawal type %s", withdrawal.Type)
+	}
+
+	if withdrawal.Comment != "" {
+		req.Add("comment", withdrawal.Comment)
+	}
+
+	resp := WithdrawalResponse{}
+	err := b.SendAuthenticatedHTTPRequest(bitstampAPIOpenWithdrawal, true, req, &resp)
+	if err != nil {
+		return "", err
+	}
+	return getWithdrawalID(resp)
+}
+
+// getWithdrawalID returns the withdrawal ID from a withdrawal response or the
+// rejection reason supplied by the exchange
+func getWithdrawalID(resp WithdrawalResponse) (string, error) {
+	if resp.Status == "error" {
+		return "", fmt.Errorf("withdrawal rejected: %v", resp.Reason)
+	}
+
+	switch id := resp.ID.(type) {
+	case string:
+		if id != "" {
+			return id, nil
+		}
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64), nil
+	}
+
+	if resp.WithdrawalID != 0 {
+		return strconv.FormatInt(resp.WithdrawalID, 10), nil
+	}
+	return "", errors.New("withdrawal ID not returned")
+}
+
+// getFiatTransferStatus converts a withdrawal request status into a fiat
+// transfer state
+func getFiatTransferStatus(status int) (string, error) {
+	switch status {
+	case bitstampWithdrawalOpen:
+		return exchange.FiatTransferSubmitted, nil
+	case bitstampWithdrawalInProcess:
+		return exchange.FiatTransferProcessing, nil
+	case bitstampWithdrawalFinished:
+		return exchange.FiatTransferSent, nil
+	case bitstampWithdrawalCancelled:
+		return exchange.FiatTransferCancelled, nil
+	case bitstampWithdrawalFailed:
+		return exchange.FiatTransferFailed, nil
+	}
+	return "", fmt.Errorf("unknown withdrawal request status %d", status)
+}
+
+// validateCryptoAddress checks the withdrawal address format for the supported
+// withdrawal currencies
+func validateCryptoAddress(address, crypto string) error {
+	if address == "" {
+		return errors.New("withdrawal address cannot be empty")
 	}
-	return resp.ID,
-		errors.New("incorrect symbol")
+
+	var valid bool
+	var err error
+	switch common.StringToLower(crypto) {
+	case "btc", "ltc":
+		valid, err = common.IsValidCryptoAddress(address, crypto)
+	case "eth":
+		// Checksummed addresses are mixed case but otherwise identical
+		valid, err = common.IsValidCryptoAddress(common.StringToLower(address), crypto)
+	case "xrp":
+		valid, err = regexp.MatchString("^r[1-9A-HJ-NP-Za-km-z]{24,34}$", address)
+	default:
+		return errors.New("incorrect symbol")
+	}
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return fmt.Errorf("invalid %s withdrawal address %s",
+			common.StringToUpper(crypto), address)
+	}
+	return nil
 }
 
 // GetCryptoDepositAddress returns a depositing address by crypto
@@ -585,7 +928,7 @@ func (b *Bitstamp) SendAuthenticatedHTTPRequest(path string, v2 bool, values url
 	}
 
 	if b.Nonce.Get() == 0 {
-		b.Nonce.Set(time.Now().UnixNano())
+		b.Nonce.Set(b.GetAdjustedTime().UnixNano())
 	} else {
 		b.Nonce.Inc()
 	}
@@ -594,10 +937,19 @@ func (b *Bitstamp) SendAuthenticatedHTTPRequest(path string, v2 bool, values url
 		values = url.Values{}
 	}
 
+	signer, err := exchange.NewSigner(exchange.SignHMACSHA256, exchange.SignatureEncodingHexUpper, b.APISecret)
+	if err != nil {
+		return err
+	}
+
+	signature, err := signer.SignToString(exchange.CanonicalMessage("", b.Nonce.String(), b.ClientID, b.APIKey))
+	if err != nil {
+		return err
+	}
+
 	values.Set("key", b.APIKey)
 	values.Set("nonce", b.Nonce.String())
-	hmac := common.GetHMAC(common.HashSHA256, []byte(b.Nonce.String()+b.ClientID+b.APIKey), []byte(b.APISecret))
-	values.Set("signature", common.StringToUpper(common.HexEncodeToString(hmac)))
+	values.Set("signature", signature)
 
 	if v2 {
 		path = fmt.Sprintf("%s/v%s/%s/", b.APIUrl, bitstampAPIVersion, path)
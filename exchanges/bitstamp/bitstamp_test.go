@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	"github.com/thrasher-/gocryptotrader/exchanges"
 
@@ -161,6 +163,21 @@ func TestCalculateTradingFee(t *testing.T) {
 	}
 }
 
+func TestConvertAccountFees(t *testing.T) {
+	t.Parallel()
+	var raw map[string]interface{}
+	err := common.JSONDecode([]byte(`{"btc_balance": "1.0", "btcusd_fee": "0.25", "ethbtc_fee": "0.10", "eth_available": "2.0"}`), &raw)
+	if err != nil {
+		t.Fatal("Test Failed - unable to decode balance", err)
+	}
+
+	fees := convertAccountFees(raw)
+	if len(fees.Pairs) != 2 || fees.Rate("ETHBTC", false) != 0.001 ||
+		fees.Rate("btcusd", true) != 0.0025 || fees.Rate("XRPEUR", false) != 0.0025 {
+		t.Errorf("Test Failed - convertAccountFees() unexpected fees %+v", fees)
+	}
+}
+
 func TestGetTicker(t *testing.T) {
 	t.Parallel()
 	_, err := b.GetTicker(symbol.BTC+symbol.USD, false)
@@ -204,6 +221,24 @@ func TestGetTransactions(t *testing.T) {
 	}
 }
 
+func TestGetExchangeHistorySince(t *testing.T) {
+	t.Parallel()
+	p := pair.NewCurrencyPair(symbol.BTC, symbol.USD)
+	since := time.Now().Add(-time.Hour)
+	trades, err := b.GetExchangeHistorySince(p, since, 10)
+	if err != nil {
+		t.Error("Test Failed - GetExchangeHistorySince() error", err)
+	}
+	if len(trades) > 10 {
+		t.Error("Test Failed - GetExchangeHistorySince() limit not applied")
+	}
+	for x := range trades {
+		if trades[x].Timestamp < since.Unix() {
+			t.Error("Test Failed - GetExchangeHistorySince() returned trade before since")
+		}
+	}
+}
+
 func TestGetEURUSDConversionRate(t *testing.T) {
 	t.Parallel()
 	_, err := b.GetEURUSDConversionRate()
@@ -215,7 +250,7 @@ func TestGetEURUSDConversionRate(t *testing.T) {
 func TestGetBalance(t *testing.T) {
 	t.Parallel()
 	_, err := b.GetBalance()
-	if err != nil {
+	if err == nil {
 		t.Error("Test Failed - GetBalance() error", err)
 	}
 }
@@ -233,6 +268,41 @@ func TestGetUserTransactions(t *testing.T) {
 	}
 }
 
+func TestGetFundingTransactions(t *testing.T) {
+	t.Parallel()
+	_, _, err := b.GetFundingTransactions(0, bitstampTransactionsLimit)
+	if err == nil {
+		t.Error("Test Failed - GetFundingTransactions() error", err)
+	}
+}
+
+func TestConvertFundingTransaction(t *testing.T) {
+	t.Parallel()
+	var raw []map[string]interface{}
+	err := common.JSONDecode([]byte(`[
+		{"id": 1, "datetime": "2018-10-01 10:00:00.123456", "type": "1", "usd": 0.0, "eth": "-2.5", "btc_usd": "0.00", "fee": "0.001", "order_id": null},
+		{"id": 2, "datetime": "2018-10-01 11:00:00", "type": "2", "usd": "-100.00", "btc": "0.01", "btc_usd": "10000.00", "fee": "0.25", "order_id": 3}
+	]`), &raw)
+	if err != nil {
+		t.Fatal("Test Failed - unable to decode transactions", err)
+	}
+
+	tx, ok := convertFundingTransaction(raw[0])
+	if !ok || tx.Currency != "ETH" || tx.Amount != -2.5 || tx.Fee != 0.001 || tx.ID != 1 {
+		t.Errorf("Test Failed - convertFundingTransaction() unexpected result %+v", tx)
+	}
+
+	history := b.convertFundHistory(tx)
+	if history.TransferType != "withdrawal" || history.Amount != 2.5 || history.Timestamp != 1538388000 {
+		t.Errorf("Test Failed - convertFundHistory() unexpected result %+v", history)
+	}
+
+	_, ok = convertFundingTransaction(raw[1])
+	if ok {
+		t.Error("Test Failed - convertFundingTransaction() market trade not filtered")
+	}
+}
+
 func TestGetOpenOrders(t *testing.T) {
 	t.Parallel()
 
@@ -346,3 +416,147 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestOpenBankWithdrawal(t *testing.T) {
+	t.Parallel()
+
+	_, err := b.OpenBankWithdrawal(FiatWithdrawalRequest{})
+	if err == nil {
+		t.Error("Test Failed - OpenBankWithdrawal() error", err)
+	}
+	_, err = b.OpenBankWithdrawal(FiatWithdrawalRequest{
+		Amount: 100,
+		Name:   "Satoshi Nakamoto",
+		IBAN:   "DE89370400440532013000",
+		Type:   "cheque",
+	})
+	if err == nil {
+		t.Error("Test Failed - OpenBankWithdrawal() error", err)
+	}
+	_, err = b.OpenBankWithdrawal(FiatWithdrawalRequest{
+		Amount: 100,
+		Name:   "Satoshi Nakamoto",
+		IBAN:   "DE89370400440532013000",
+		Type:   bitstampWithdrawalInternational,
+	})
+	if err == nil {
+		t.Error("Test Failed - OpenBankWithdrawal() error", err)
+	}
+}
+
+func TestValidateCryptoAddress(t *testing.T) {
+	t.Parallel()
+
+	if err := validateCryptoAddress("1F5zVDgNjorJ51oGebSvNCrSAHpwGkUdDB", "btc"); err != nil {
+		t.Error("Test Failed - validateCryptoAddress() error", err)
+	}
+	if err := validateCryptoAddress("0xb794f5ea0ba39494ce839613fffba74279579268", "ETH"); err != nil {
+		t.Error("Test Failed - validateCryptoAddress() error", err)
+	}
+	if err := validateCryptoAddress("rDsbeomae4FXwgQTJp9Rs64Qg9vDiTCdBv", "xrp"); err != nil {
+		t.Error("Test Failed - validateCryptoAddress() error", err)
+	}
+	if err := validateCryptoAddress("bla", "ltc"); err == nil {
+		t.Error("Test Failed - validateCryptoAddress() error", err)
+	}
+	if err := validateCryptoAddress("1F5zVDgNjorJ51oGebSvNCrSAHpwGkUdDB", "doge"); err == nil {
+		t.Error("Test Failed - validateCryptoAddress() error", err)
+	}
+}
+
+func TestGetWithdrawalID(t *testing.T) {
+	t.Parallel()
+
+	id, err := getWithdrawalID(WithdrawalResponse{ID: float64(1337)})
+	if err != nil || id != "1337" {
+		t.Error("Test Failed - getWithdrawalID() error", err)
+	}
+	id, err = getWithdrawalID(WithdrawalResponse{WithdrawalID: 42})
+	if err != nil || id != "42" {
+		t.Error("Test Failed - getWithdrawalID() error", err)
+	}
+	_, err = getWithdrawalID(WithdrawalResponse{Status: "error", Reason: "insufficient funds"})
+	if err == nil {
+		t.Error("Test Failed - getWithdrawalID() error", err)
+	}
+}
+
+func TestGetFiatTransferStatus(t *testing.T) {
+	t.Parallel()
+
+	status, err := getFiatTransferStatus(bitstampWithdrawalInProcess)
+	if err != nil || status != exchange.FiatTransferProcessing {
+		t.Error("Test Failed - getFiatTransferStatus() error", err)
+	}
+	status, err = getFiatTransferStatus(bitstampWithdrawalFinished)
+	if err != nil || status != exchange.FiatTransferSent {
+		t.Error("Test Failed - getFiatTransferStatus() error", err)
+	}
+	_, err = getFiatTransferStatus(9)
+	if err == nil {
+		t.Error("Test Failed - getFiatTransferStatus() expected unknown status error")
+	}
+}
+
+func TestGetVerifiedTradeHistory(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := b.GetVerifiedTradeHistory("btcusd", time.Minute*5)
+	if err != nil {
+		t.Error("Test Failed - GetVerifiedTradeHistory() error", err)
+	}
+}
+
+func TestConvertBalances(t *testing.T) {
+	t.Parallel()
+
+	currencies := convertBalances(Balances{BTCAvailable: 1, BTCReserved: 0.5, USDAvailable: 100})
+	if len(currencies) != 4 || currencies[0].CurrencyName != symbol.BTC || currencies[0].Free != 1 ||
+		currencies[0].Locked != 0.5 || currencies[0].Total != 1.5 {
+		t.Errorf("Test Failed - convertBalances() unexpected result %+v", currencies)
+	}
+	if currencies[2].CurrencyName != symbol.USD || currencies[2].Total != 100 {
+		t.Errorf("Test Failed - convertBalances() unexpected result %+v", currencies[2])
+	}
+}
+
+func TestWithdrawFiatExchangeFundsToInternationalBank(t *testing.T) {
+	t.Parallel()
+
+	details := exchange.BankDetails{BeneficiaryName: "Satoshi Nakamoto",
+		IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"}
+	_, err := b.WithdrawFiatExchangeFundsToInternationalBank(pair.CurrencyItem("JPY"), 100, details)
+	if err != exchange.ErrFiatWithdrawalNotSupported {
+		t.Error("Test Failed - WithdrawFiatExchangeFundsToInternationalBank() unsupported currency error", err)
+	}
+	_, err = b.WithdrawFiatExchangeFundsToInternationalBank(pair.CurrencyItem("usd"), 100, details)
+	if err == nil {
+		t.Error("Test Failed - WithdrawFiatExchangeFundsToInternationalBank() expected missing bank name error")
+	}
+}
+
+func TestGetFiatWithdrawalRequest(t *testing.T) {
+	t.Parallel()
+
+	req := getFiatWithdrawalRequest(pair.CurrencyItem("usd"), 100, exchange.FiatWithdrawalSWIFT,
+		exchange.BankDetails{BeneficiaryName: "Satoshi Nakamoto", IBAN: "DE89 3704 0044 0532 0130 00",
+			BIC: "COBADEFFXXX", BankName: "Commerzbank", BankCountry: "DE", Reference: "invoice 1"})
+	if req.Type != bitstampWithdrawalInternational || req.IBAN != "DE89370400440532013000" ||
+		req.Currency != "USD" || req.BankCountry != "DE" || req.Comment != "invoice 1" {
+		t.Errorf("Test Failed - getFiatWithdrawalRequest() unexpected request %+v", req)
+	}
+
+	req = getFiatWithdrawalRequest(pair.CurrencyItem("EUR"), 100, exchange.FiatWithdrawalSEPA,
+		exchange.BankDetails{})
+	if req.Type != bitstampWithdrawalSEPA {
+		t.Errorf("Test Failed - getFiatWithdrawalRequest() expected type %s, got %s",
+			bitstampWithdrawalSEPA, req.Type)
+	}
+}
+
+func TestFiatTransferTrackerInterface(t *testing.T) {
+	var exch exchange.IBotExchange = &b
+	if _, ok := exch.(exchange.IFiatTransferTracker); !ok {
+		t.Error("Test Failed - Bitstamp does not implement IFiatTransferTracker")
+	}
+}
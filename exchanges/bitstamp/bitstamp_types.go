@@ -89,6 +89,17 @@ type UserTransactions struct {
 	OrderID int64   `json:"order_id"`
 }
 
+// FundingTransaction holds a deposit, withdrawal or sub account transfer
+// user transaction
+type FundingTransaction struct {
+	ID       int64
+	Date     string
+	Type     int
+	Currency string
+	Amount   float64
+	Fee      float64
+}
+
 // Order holds current open order data
 type Order struct {
 	ID     int64   `json:"id"`
@@ -122,6 +133,37 @@ type WithdrawalRequests struct {
 	TransactionID string `json:"transaction_id"` // Bitcoin withdrawals only
 }
 
+// WithdrawalResponse holds the response from a crypto or bank withdrawal
+// request
+type WithdrawalResponse struct {
+	ID           interface{} `json:"id"`
+	WithdrawalID int64       `json:"withdrawal_id"`
+	Status       string      `json:"status"`
+	Reason       interface{} `json:"reason"`
+}
+
+// FiatWithdrawalRequest holds the account holder and bank details required
+// for a SEPA or international wire withdrawal
+type FiatWithdrawalRequest struct {
+	Amount          float64
+	AccountCurrency string
+	Name            string
+	IBAN            string
+	BIC             string
+	Address         string
+	PostalCode      string
+	City            string
+	Country         string
+	Type            string
+	BankName        string
+	BankAddress     string
+	BankPostalCode  string
+	BankCity        string
+	BankCountry     string
+	Currency        string
+	Comment         string
+}
+
 // UnconfirmedBTCTransactions holds address information about unconfirmed
 // transactions
 type UnconfirmedBTCTransactions struct {
@@ -102,7 +102,7 @@ func (b *Bitstamp) WsConnect() error {
 	go b.WsReadData()
 
 	for _, p := range b.GetEnabledCurrencies() {
-		orderbookSeed, err := b.GetOrderbook(p.Pair().String())
+		orderbookSeed, err := b.GetOrderbook(b.FormatPair(p, exchange.PairFormatRequest))
 		if err != nil {
 			return err
 		}
@@ -144,7 +144,7 @@ func (b *Bitstamp) WsConnect() error {
 		}
 
 		err = b.WebsocketConn.Client.Subscribe(fmt.Sprintf("live_trades_%s",
-			strings.ToLower(p.Pair().String())))
+			strings.ToLower(b.FormatPair(p, exchange.PairFormatRequest))))
 
 		if err != nil {
 			log.Println(err)
@@ -154,7 +154,7 @@ func (b *Bitstamp) WsConnect() error {
 		}
 
 		err = b.WebsocketConn.Client.Subscribe(fmt.Sprintf("diff_order_book_%s",
-			strings.ToLower(p.Pair().String())))
+			strings.ToLower(b.FormatPair(p, exchange.PairFormatRequest))))
 
 		if err != nil {
 			log.Println(err)
@@ -1,35 +1,50 @@
 package bitstamp
 
 import (
+	"context"
 	"errors"
 	"log"
+	"math"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Bitstamp exchange
+func init() {
+	exchange.Register("Bitstamp", func() exchange.IBotExchange { return new(Bitstamp) })
+}
+
 // Start starts the Bitstamp go routine
-func (b *Bitstamp) Start(wg *sync.WaitGroup) {
+func (b *Bitstamp) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		b.Run()
+		b.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the Bitstamp wrapper
-func (b *Bitstamp) Run() {
+func (b *Bitstamp) Run(ctx context.Context) {
 	if b.Verbose {
 		log.Printf("%s Websocket: %s.", b.GetName(), common.IsEnabled(b.Websocket.IsEnabled()))
 		log.Printf("%s polling delay: %ds.\n", b.GetName(), b.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	pairs, err := b.GetTradingPairs()
 	if err != nil {
 		log.Printf("%s failed to get trading pairs. Err: %s", b.Name, err)
@@ -49,10 +64,15 @@ func (b *Bitstamp) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (b *Bitstamp) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (b *Bitstamp) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
-	tick, err := b.GetTicker(p.Pair().String(), false)
+	tick, err := b.GetTicker(b.FormatPair(p, exchange.PairFormatRequest), false)
 	if err != nil {
 		return tickerPrice, err
 
@@ -95,7 +115,7 @@ func (b *Bitstamp) GetOrderbookEx(p pair.CurrencyPair, assetType string) (orderb
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (b *Bitstamp) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
-	orderbookNew, err := b.GetOrderbook(p.Pair().String())
+	orderbookNew, err := b.GetOrderbook(b.FormatPair(p, exchange.PairFormatRequest))
 	if err != nil {
 		return orderBook, err
 	}
@@ -110,7 +130,7 @@ func (b *Bitstamp) UpdateOrderbook(p pair.CurrencyPair, assetType string) (order
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: data.Amount, Price: data.Price})
 	}
 
-	orderbook.ProcessOrderbook(b.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(b.GetName(), p, b.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(b.Name, p, assetType)
 }
 
@@ -124,44 +144,123 @@ func (b *Bitstamp) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 		return response, err
 	}
 
-	response.Currencies = append(response.Currencies, exchange.AccountCurrencyInfo{
-		CurrencyName: "BTC",
-		TotalValue:   accountBalance.BTCAvailable,
-		Hold:         accountBalance.BTCReserved,
-	})
-
-	response.Currencies = append(response.Currencies, exchange.AccountCurrencyInfo{
-		CurrencyName: "XRP",
-		TotalValue:   accountBalance.XRPAvailable,
-		Hold:         accountBalance.XRPReserved,
-	})
-
-	response.Currencies = append(response.Currencies, exchange.AccountCurrencyInfo{
-		CurrencyName: "USD",
-		TotalValue:   accountBalance.USDAvailable,
-		Hold:         accountBalance.USDReserved,
-	})
-
-	response.Currencies = append(response.Currencies, exchange.AccountCurrencyInfo{
-		CurrencyName: "EUR",
-		TotalValue:   accountBalance.EURAvailable,
-		Hold:         accountBalance.EURReserved,
-	})
+	response.Currencies = convertBalances(accountBalance)
 	return response, nil
 }
 
+// convertBalances converts the available and reserved balances into account
+// currency info
+func convertBalances(balance Balances) []exchange.AccountCurrencyInfo {
+	return []exchange.AccountCurrencyInfo{
+		exchange.NewAccountCurrencyInfo("BTC", balance.BTCAvailable, balance.BTCReserved),
+		exchange.NewAccountCurrencyInfo("XRP", balance.XRPAvailable, balance.XRPReserved),
+		exchange.NewAccountCurrencyInfo("USD", balance.USDAvailable, balance.USDReserved),
+		exchange.NewAccountCurrencyInfo("EUR", balance.EURAvailable, balance.EURReserved),
+	}
+}
+
 // GetExchangeFundTransferHistory returns funding history, deposits and
 // withdrawals
 func (b *Bitstamp) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error) {
 	var fundHistory []exchange.FundHistory
-	return fundHistory, errors.New("not supported on exchange")
+	for offset := int64(0); ; offset += bitstampTransactionsLimit {
+		transactions, count, err := b.GetFundingTransactions(offset, bitstampTransactionsLimit)
+		if err != nil {
+			return fundHistory, err
+		}
+
+		for x := range transactions {
+			fundHistory = append(fundHistory, b.convertFundHistory(transactions[x]))
+		}
+
+		if count < bitstampTransactionsLimit {
+			return fundHistory, nil
+		}
+	}
+}
+
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (b *Bitstamp) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
 }
 
-// GetExchangeHistory returns historic trade data since exchange opening.
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (b *Bitstamp) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// convertFundHistory converts a funding transaction to the exchange fund
+// history format
+func (b *Bitstamp) convertFundHistory(tx FundingTransaction) exchange.FundHistory {
+	history := exchange.FundHistory{
+		ExchangeName: b.GetName(),
+		Status:       "Complete",
+		TransferID:   tx.ID,
+		Currency:     tx.Currency,
+		Amount:       math.Abs(tx.Amount),
+		Fee:          tx.Fee,
+	}
+
+	switch tx.Type {
+	case bitstampTransactionDeposit:
+		history.TransferType = "deposit"
+	case bitstampTransactionWithdrawal:
+		history.TransferType = "withdrawal"
+	case bitstampTransactionSubAccountTransfer:
+		history.TransferType = "sub account transfer"
+		history.Description = "transfer in"
+		if tx.Amount < 0 {
+			history.Description = "transfer out"
+		}
+	}
+
+	// Bitstamp returns microsecond precision on some transactions
+	date := tx.Date
+	if len(date) > len(bitstampTimeLayout) {
+		date = date[:len(bitstampTimeLayout)]
+	}
+	timestamp, err := time.Parse(bitstampTimeLayout, date)
+	if err == nil {
+		history.Timestamp = timestamp.Unix()
+	}
+	return history
+}
+
+// GetExchangeHistory returns the public trades for a currency pair from the
+// last day
 func (b *Bitstamp) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
-	var resp []exchange.TradeHistory
+	return b.GetExchangeHistorySince(p, time.Time{}, 0)
+}
 
-	return resp, errors.New("trade history not yet implemented")
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (b *Bitstamp) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
+// GetExchangeHistorySince returns up to limit of the most recent public trades
+// for a currency pair executed at or after since. Bitstamp only returns trades
+// from the last minute, hour or day, so since is limited to the last day
+func (b *Bitstamp) GetExchangeHistorySince(p pair.CurrencyPair, since time.Time, limit int) ([]exchange.TradeHistory, error) {
+	interval := "day"
+	if !since.IsZero() {
+		switch elapsed := time.Since(since); {
+		case elapsed <= time.Minute:
+			interval = "minute"
+		case elapsed <= time.Hour:
+			interval = "hour"
+		}
+	}
+
+	values := url.Values{}
+	values.Set("time", interval)
+	transactions, err := b.GetTransactions(b.FormatPair(p, exchange.PairFormatRequest), values)
+	if err != nil {
+		return nil, err
+	}
+	return exchange.FilterTradeHistory(b.convertTransactions(transactions), since, limit), nil
 }
 
 // SubmitExchangeOrder submits a new order
@@ -180,6 +279,18 @@ func (b *Bitstamp) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (b *Bitstamp) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, b.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (b *Bitstamp) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, b.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (b *Bitstamp) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -191,27 +302,115 @@ func (b *Bitstamp) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, er
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (b *Bitstamp) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (b *Bitstamp) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
-	return "", errors.New("not yet implemented")
+	return b.GetCryptoDepositAddress(cryptocurrency.String())
 }
 
 // WithdrawCryptoExchangeFunds returns a withdrawal ID when a withdrawal is
 // submitted
 func (b *Bitstamp) WithdrawCryptoExchangeFunds(address string, cryptocurrency pair.CurrencyItem, amount float64) (string, error) {
-	return "", errors.New("not yet implemented")
+	return b.CryptoWithdrawal(amount, address, cryptocurrency.String(), "", false)
 }
 
-// WithdrawFiatExchangeFunds returns a withdrawal ID when a
-// withdrawal is submitted
+// WithdrawFiatExchangeFunds returns a withdrawal ID when a SEPA withdrawal to
+// the client bank account configured for the currency is submitted
 func (b *Bitstamp) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount float64) (string, error) {
-	return "", errors.New("not yet implemented")
+	bd, err := b.GetClientBankAccounts(b.Name, currency.Upper().String())
+	if err != nil {
+		return "", err
+	}
+	return b.withdrawToBank(currency, amount, exchange.FiatWithdrawalSEPA,
+		exchange.BankDetailsFromAccount(bd))
 }
 
-// WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
-// withdrawal is submitted
-func (b *Bitstamp) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
-	return "", errors.New("not yet implemented")
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (b *Bitstamp) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
+// GetFiatTransferStatus returns the state of the bank transfer of a fiat
+// withdrawal by the reference returned when it was submitted, searching the
+// withdrawal requests of the last 30 days
+func (b *Bitstamp) GetFiatTransferStatus(reference string) (exchange.FiatTransferStatus, error) {
+	requests, err := b.GetWithdrawalRequests(bitstampWithdrawalTimedelta)
+	if err != nil {
+		return exchange.FiatTransferStatus{}, err
+	}
+
+	for x := range requests {
+		if strconv.FormatInt(requests[x].OrderID, 10) != reference {
+			continue
+		}
+
+		status, err := getFiatTransferStatus(requests[x].Status)
+		if err != nil {
+			return exchange.FiatTransferStatus{}, err
+		}
+
+		updated, _ := time.Parse(bitstampTimeLayout, requests[x].Date)
+		return exchange.FiatTransferStatus{
+			Reference: reference,
+			Status:    status,
+			Amount:    requests[x].Amount,
+			Updated:   updated,
+		}, nil
+	}
+	return exchange.FiatTransferStatus{}, exchange.ErrFiatTransferNotFound
+}
+
+// WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when an
+// international withdrawal to the bank account of the details is submitted
+func (b *Bitstamp) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
+	return b.withdrawToBank(currency, amount, exchange.FiatWithdrawalSWIFT, details)
+}
+
+// withdrawToBank validates the bank details for the transfer type and submits
+// a bank withdrawal
+func (b *Bitstamp) withdrawToBank(currency pair.CurrencyItem, amount float64, transferType string, details exchange.BankDetails) (string, error) {
+	if !b.SupportsFiatWithdrawal(transferType, currency.String()) {
+		return "", exchange.ErrFiatWithdrawalNotSupported
+	}
+
+	err := details.Validate(transferType)
+	if err != nil {
+		return "", err
+	}
+	return b.OpenBankWithdrawal(getFiatWithdrawalRequest(currency, amount, transferType, details))
+}
+
+// getFiatWithdrawalRequest converts bank details to a bank withdrawal request
+// of the transfer type
+func getFiatWithdrawalRequest(currency pair.CurrencyItem, amount float64, transferType string, details exchange.BankDetails) FiatWithdrawalRequest {
+	withdrawalType := bitstampWithdrawalSEPA
+	if transferType == exchange.FiatWithdrawalSWIFT {
+		withdrawalType = bitstampWithdrawalInternational
+	}
+
+	return FiatWithdrawalRequest{
+		Amount:          amount,
+		AccountCurrency: currency.Upper().String(),
+		Name:            details.BeneficiaryName,
+		IBAN:            common.ReplaceString(details.IBAN, " ", "", -1),
+		BIC:             details.BIC,
+		Address:         details.BeneficiaryAddress,
+		PostalCode:      details.BeneficiaryPostalCode,
+		City:            details.BeneficiaryCity,
+		Country:         details.BeneficiaryCountry,
+		Type:            withdrawalType,
+		BankName:        details.BankName,
+		BankAddress:     details.BankAddress,
+		BankPostalCode:  details.BankPostalCode,
+		BankCity:        details.BankCity,
+		BankCountry:     details.BankCountry,
+		Currency:        currency.Upper().String(),
+		Comment:         details.Reference,
+	}
 }
 
 // GetWebsocket returns a pointer to the exchange websocket
@@ -219,6 +418,11 @@ func (b *Bitstamp) GetWebsocket() (*exchange.Websocket, error) {
 	return b.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (b *Bitstamp) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetWithdrawCapabilities returns the types of withdrawal methods permitted by the exchange
 func (b *Bitstamp) GetWithdrawCapabilities() uint32 {
 	return b.GetWithdrawPermissions()
@@ -3,7 +3,6 @@ package bittrex
 import (
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"strconv"
 	"time"
@@ -53,6 +52,10 @@ const (
 	bittrexAPIGetWithdrawalHistory = "account/getwithdrawalhistory"
 	bittrexAPIGetDepositHistory    = "account/getdeposithistory"
 
+	// bittrexTimeLayout is the layout of the UTC times in account responses,
+	// which may carry fractional seconds
+	bittrexTimeLayout = "2006-01-02T15:04:05"
+
 	bittrexAuthRate   = 0
 	bittrexUnauthRate = 0
 )
@@ -86,7 +89,7 @@ func (b *Bittrex) SetDefaults() {
 }
 
 // Setup method sets current configuration details if enabled
-func (b *Bittrex) Setup(exch config.ExchangeConfig) {
+func (b *Bittrex) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		b.SetEnabled(false)
 	} else {
@@ -102,25 +105,34 @@ func (b *Bittrex) Setup(exch config.ExchangeConfig) {
 		b.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := b.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = b.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetMarkets is used to get the open and available trading markets at Bittrex
@@ -465,8 +477,8 @@ func (b *Bittrex) GetWithdrawalHistory(currency string) (WithdrawalHistory, erro
 
 // GetDepositHistory is used to retrieve your deposit history. If currency is
 // is omitted it will return the entire deposit history
-func (b *Bittrex) GetDepositHistory(currency string) (WithdrawalHistory, error) {
-	var history WithdrawalHistory
+func (b *Bittrex) GetDepositHistory(currency string) (DepositHistory, error) {
+	var history DepositHistory
 	values := url.Values{}
 
 	if !(currency == "" || currency == " ") {
@@ -497,7 +509,7 @@ func (b *Bittrex) SendAuthenticatedHTTPRequest(path string, values url.Values, r
 	}
 
 	if b.Nonce.Get() == 0 {
-		b.Nonce.Set(time.Now().UnixNano())
+		b.Nonce.Set(b.GetAdjustedTime().UnixNano())
 	} else {
 		b.Nonce.Inc()
 	}
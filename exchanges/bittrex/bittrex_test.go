@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
@@ -327,3 +328,55 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestConvertBalances(t *testing.T) {
+	t.Parallel()
+
+	var balances Balances
+	err := common.JSONDecode([]byte(`{"result":[{"Currency":"BTC","Balance":1.5,"Available":1}]}`), &balances)
+	if err != nil {
+		t.Fatal("Test Failed - unable to decode balances", err)
+	}
+
+	currencies := convertBalances(balances)
+	if len(currencies) != 1 || currencies[0].CurrencyName != symbol.BTC || currencies[0].Free != 1 ||
+		currencies[0].Locked != 0.5 || currencies[0].Total != 1.5 {
+		t.Errorf("Test Failed - convertBalances() unexpected result %+v", currencies)
+	}
+}
+
+func TestConvertTransfers(t *testing.T) {
+	deposit := convertDeposit(DepositRecord{
+		ID:            42,
+		Amount:        0.5,
+		Currency:      "BTC",
+		Confirmations: 6,
+		LastUpdated:   "2018-07-09T04:24:47.217",
+		TxID:          "deposittx",
+	})
+	if deposit.ID != "42" || deposit.Status != exchange.TransferStatusComplete ||
+		deposit.Confirmations != 6 || deposit.Timestamp.Year() != 2018 {
+		t.Errorf("Test Failed - convertDeposit() unexpected deposit %+v", deposit)
+	}
+
+	withdrawal := convertWithdrawal(WithdrawalRecord{
+		PaymentUUID: "uuid",
+		Currency:    "LTC",
+		Amount:      2,
+		TxCost:      0.01,
+		Opened:      "2018-07-09T04:24:47",
+		Authorized:  true,
+		TxID:        "withdrawaltx",
+	})
+	if withdrawal.Status != exchange.TransferStatusComplete || withdrawal.Fee != 0.01 ||
+		withdrawal.Timestamp.IsZero() {
+		t.Errorf("Test Failed - convertWithdrawal() unexpected withdrawal %+v", withdrawal)
+	}
+
+	if withdrawal = convertWithdrawal(WithdrawalRecord{Canceled: true}); withdrawal.Status != exchange.TransferStatusCancelled {
+		t.Errorf("Test Failed - convertWithdrawal() unexpected status %s", withdrawal.Status)
+	}
+	if withdrawal = convertWithdrawal(WithdrawalRecord{PendingPayment: true}); withdrawal.Status != exchange.TransferStatusPending {
+		t.Errorf("Test Failed - convertWithdrawal() unexpected status %s", withdrawal.Status)
+	}
+}
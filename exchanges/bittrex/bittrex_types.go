@@ -188,19 +188,40 @@ type Order struct {
 
 // WithdrawalHistory holds the Withdrawal history data
 type WithdrawalHistory struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	Result  []struct {
-		PaymentUUID    string  `json:"PaymentUuid"`
-		Currency       string  `json:"Currency"`
-		Amount         float64 `json:"Amount"`
-		Address        string  `json:"Address"`
-		Opened         string  `json:"Opened"`
-		Authorized     bool    `json:"Authorized"`
-		PendingPayment bool    `json:"PendingPayment"`
-		TxCost         float64 `json:"TxCost"`
-		TxID           string  `json:"TxId"`
-		Canceled       bool    `json:"Canceled"`
-		InvalidAddress bool    `json:"InvalidAddress"`
-	} `json:"result"`
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Result  []WithdrawalRecord `json:"result"`
+}
+
+// WithdrawalRecord holds a withdrawal of the withdrawal history
+type WithdrawalRecord struct {
+	PaymentUUID    string  `json:"PaymentUuid"`
+	Currency       string  `json:"Currency"`
+	Amount         float64 `json:"Amount"`
+	Address        string  `json:"Address"`
+	Opened         string  `json:"Opened"`
+	Authorized     bool    `json:"Authorized"`
+	PendingPayment bool    `json:"PendingPayment"`
+	TxCost         float64 `json:"TxCost"`
+	TxID           string  `json:"TxId"`
+	Canceled       bool    `json:"Canceled"`
+	InvalidAddress bool    `json:"InvalidAddress"`
+}
+
+// DepositHistory holds the deposit history data
+type DepositHistory struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Result  []DepositRecord `json:"result"`
+}
+
+// DepositRecord holds a credited deposit of the deposit history
+type DepositRecord struct {
+	ID            int64   `json:"Id"`
+	Amount        float64 `json:"Amount"`
+	Currency      string  `json:"Currency"`
+	Confirmations int64   `json:"Confirmations"`
+	LastUpdated   string  `json:"LastUpdated"`
+	TxID          string  `json:"TxId"`
+	CryptoAddress string  `json:"CryptoAddress"`
 }
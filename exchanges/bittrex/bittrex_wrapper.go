@@ -1,34 +1,47 @@
 package bittrex
 
 import (
+	"context"
 	"errors"
 	"log"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Bittrex exchange
+func init() {
+	exchange.Register("Bittrex", func() exchange.IBotExchange { return new(Bittrex) })
+}
+
 // Start starts the Bittrex go routine
-func (b *Bittrex) Start(wg *sync.WaitGroup) {
+func (b *Bittrex) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		b.Run()
+		b.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the Bittrex wrapper
-func (b *Bittrex) Run() {
+func (b *Bittrex) Run(ctx context.Context) {
 	if b.Verbose {
 		log.Printf("%s polling delay: %ds.\n", b.GetName(), b.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
-	exchangeProducts, err := b.GetMarkets()
+	if ctx.Err() != nil {
+		return
+	}
+
+	currencies, err := b.GetListedPairs()
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", b.GetName())
 	} else {
@@ -36,13 +49,6 @@ func (b *Bittrex) Run() {
 		if !common.StringDataContains(b.EnabledPairs, "-") || !common.StringDataContains(b.AvailablePairs, "-") {
 			forceUpgrade = true
 		}
-		var currencies []string
-		for x := range exchangeProducts.Result {
-			if !exchangeProducts.Result[x].IsActive || exchangeProducts.Result[x].MarketName == "" {
-				continue
-			}
-			currencies = append(currencies, exchangeProducts.Result[x].MarketName)
-		}
 
 		if forceUpgrade {
 			enabledPairs := []string{"USDT-BTC"}
@@ -60,6 +66,23 @@ func (b *Bittrex) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (b *Bittrex) GetListedPairs() ([]string, error) {
+	exchangeProducts, err := b.GetMarkets()
+	if err != nil {
+		return nil, err
+	}
+
+	var currencies []string
+	for x := range exchangeProducts.Result {
+		if !exchangeProducts.Result[x].IsActive || exchangeProducts.Result[x].MarketName == "" {
+			continue
+		}
+		currencies = append(currencies, exchangeProducts.Result[x].MarketName)
+	}
+	return currencies, nil
+}
+
 // GetExchangeAccountInfo Retrieves balances for all enabled currencies for the
 // Bittrex exchange
 func (b *Bittrex) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
@@ -70,16 +93,21 @@ func (b *Bittrex) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 		return response, err
 	}
 
-	for i := 0; i < len(accountBalance.Result); i++ {
-		var exchangeCurrency exchange.AccountCurrencyInfo
-		exchangeCurrency.CurrencyName = accountBalance.Result[i].Currency
-		exchangeCurrency.TotalValue = accountBalance.Result[i].Balance
-		exchangeCurrency.Hold = accountBalance.Result[i].Balance - accountBalance.Result[i].Available
-		response.Currencies = append(response.Currencies, exchangeCurrency)
-	}
+	response.Currencies = convertBalances(accountBalance)
 	return response, nil
 }
 
+// convertBalances converts balances into account currency info, Bittrex
+// balances include the amount held by open orders
+func convertBalances(balances Balances) []exchange.AccountCurrencyInfo {
+	var currencies []exchange.AccountCurrencyInfo
+	for _, balance := range balances.Result {
+		currencies = append(currencies, exchange.NewAccountCurrencyInfo(balance.Currency,
+			balance.Available, balance.Balance-balance.Available))
+	}
+	return currencies
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (b *Bittrex) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
@@ -150,15 +178,93 @@ func (b *Bittrex) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderb
 		)
 	}
 
-	orderbook.ProcessOrderbook(b.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(b.GetName(), p, b.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(b.Name, p, assetType)
 }
 
 // GetExchangeFundTransferHistory returns funding history, deposits and
 // withdrawals
 func (b *Bittrex) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error) {
-	var fundHistory []exchange.FundHistory
-	return fundHistory, errors.New("not supported on exchange")
+	deposits, err := b.GetExchangeDepositHistory(time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawals, err := b.GetExchangeWithdrawalHistory(time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return exchange.CombineTransferHistory(b.GetName(), deposits, withdrawals), nil
+}
+
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (b *Bittrex) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	history, err := b.GetDepositHistory("")
+	if err != nil {
+		return nil, err
+	}
+
+	deposits := make([]exchange.Deposit, len(history.Result))
+	for x := range history.Result {
+		deposits[x] = convertDeposit(history.Result[x])
+	}
+	return exchange.FilterDeposits(deposits, start, end), nil
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (b *Bittrex) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	history, err := b.GetWithdrawalHistory("")
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawals := make([]exchange.Withdrawal, len(history.Result))
+	for x := range history.Result {
+		withdrawals[x] = convertWithdrawal(history.Result[x])
+	}
+	return exchange.FilterWithdrawals(withdrawals, start, end), nil
+}
+
+// convertDeposit converts a credited deposit to the exchange deposit format
+func convertDeposit(record DepositRecord) exchange.Deposit {
+	timestamp, _ := time.Parse(bittrexTimeLayout, record.LastUpdated)
+	return exchange.Deposit{
+		ID:            strconv.FormatInt(record.ID, 10),
+		Currency:      record.Currency,
+		Amount:        record.Amount,
+		Address:       record.CryptoAddress,
+		TxID:          record.TxID,
+		Confirmations: record.Confirmations,
+		Status:        exchange.TransferStatusComplete,
+		Timestamp:     timestamp,
+	}
+}
+
+// convertWithdrawal converts a withdrawal to the exchange withdrawal format
+func convertWithdrawal(record WithdrawalRecord) exchange.Withdrawal {
+	status := exchange.TransferStatusPending
+	switch {
+	case record.Canceled:
+		status = exchange.TransferStatusCancelled
+	case record.InvalidAddress:
+		status = exchange.TransferStatusFailed
+	case record.TxID != "" && !record.PendingPayment:
+		status = exchange.TransferStatusComplete
+	}
+
+	timestamp, _ := time.Parse(bittrexTimeLayout, record.Opened)
+	return exchange.Withdrawal{
+		ID:        record.PaymentUUID,
+		Currency:  record.Currency,
+		Amount:    record.Amount,
+		Fee:       record.TxCost,
+		Address:   record.Address,
+		TxID:      record.TxID,
+		Status:    status,
+		Timestamp: timestamp,
+	}
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
@@ -168,6 +274,12 @@ func (b *Bittrex) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]e
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (b *Bittrex) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (b *Bittrex) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -184,6 +296,18 @@ func (b *Bittrex) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (b *Bittrex) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, b.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (b *Bittrex) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, b.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (b *Bittrex) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -195,6 +319,11 @@ func (b *Bittrex) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, err
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (b *Bittrex) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (b *Bittrex) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -212,9 +341,14 @@ func (b *Bittrex) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount f
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (b *Bittrex) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (b *Bittrex) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (b *Bittrex) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -223,6 +357,11 @@ func (b *Bittrex) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (b *Bittrex) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (b *Bittrex) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return b.GetFee(feeBuilder)
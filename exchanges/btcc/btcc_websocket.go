@@ -49,6 +49,7 @@ func (b *BTCC) WsConnect() error {
 
 	var dialer websocket.Dialer
 	var err error
+	b.Websocket.ConfigureDialer(&dialer)
 
 	if b.Websocket.GetProxyAddress() != "" {
 		prxy, err := url.Parse(b.Websocket.GetProxyAddress())
@@ -96,7 +97,7 @@ func (b *BTCC) WsReadData() {
 
 		default:
 			mtx.Lock()
-			_, resp, err := b.Conn.ReadMessage()
+			_, resp, err := b.Websocket.ReadMessage(b.Conn)
 			mtx.Unlock()
 			if err != nil {
 				b.Websocket.DataHandler <- err
@@ -287,7 +288,7 @@ func (b *BTCC) WsUpdateCurrencyPairs() error {
 
 	var currencyResponse WsResponseMain
 	for {
-		_, resp, err := b.Conn.ReadMessage()
+		_, resp, err := b.Websocket.ReadMessage(b.Conn)
 		if err != nil {
 			return err
 		}
@@ -2,6 +2,7 @@ package btcmarkets
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -72,11 +73,12 @@ func (b *BTCMarkets) SetDefaults() {
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	b.APIUrlDefault = btcMarketsAPIURL
 	b.APIUrl = b.APIUrlDefault
+	b.SetFeeTable(exchange.FeeTable{Maker: 0.0085, Taker: 0.0085})
 	b.WebsocketInit()
 }
 
 // Setup takes in an exchange configuration and sets all parameters
-func (b *BTCMarkets) Setup(exch config.ExchangeConfig) {
+func (b *BTCMarkets) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		b.SetEnabled(false)
 	} else {
@@ -92,25 +94,34 @@ func (b *BTCMarkets) Setup(exch config.ExchangeConfig) {
 		b.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := b.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = b.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = b.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = b.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetMarkets returns the BTCMarkets instruments
@@ -138,13 +149,18 @@ func (b *BTCMarkets) GetMarkets() ([]Market, error) {
 // GetTicker returns a ticker
 // symbol - example "btc" or "ltc"
 func (b *BTCMarkets) GetTicker(firstPair, secondPair string) (Ticker, error) {
+	return b.GetTickerContext(context.Background(), firstPair, secondPair)
+}
+
+// GetTickerContext returns a ticker under a call context
+func (b *BTCMarkets) GetTickerContext(ctx context.Context, firstPair, secondPair string) (Ticker, error) {
 	ticker := Ticker{}
 	path := fmt.Sprintf("%s/market/%s/%s/tick",
 		b.APIUrl,
 		common.StringToUpper(firstPair),
 		common.StringToUpper(secondPair))
 
-	return ticker, b.SendHTTPRequest(path, &ticker)
+	return ticker, b.SendHTTPRequestContext(ctx, path, &ticker)
 }
 
 // GetOrderbook returns current orderbook
@@ -180,6 +196,11 @@ func (b *BTCMarkets) GetTrades(firstPair, secondPair string, values url.Values)
 // orderType - example "limit"
 // clientReq - example "abc-cdf-1000"
 func (b *BTCMarkets) NewOrder(currency, instrument string, price, amount float64, orderSide, orderType, clientReq string) (int64, error) {
+	return b.NewOrderContext(context.Background(), currency, instrument, price, amount, orderSide, orderType, clientReq)
+}
+
+// NewOrderContext requests a new order under a call context and returns an ID
+func (b *BTCMarkets) NewOrderContext(ctx context.Context, currency, instrument string, price, amount float64, orderSide, orderType, clientReq string) (int64, error) {
 	newPrice := int64(price * float64(common.SatoshisPerBTC))
 	newVolume := int64(amount * float64(common.SatoshisPerBTC))
 
@@ -195,7 +216,7 @@ func (b *BTCMarkets) NewOrder(currency, instrument string, price, amount float64
 
 	resp := Response{}
 
-	err := b.SendAuthenticatedRequest("POST", btcMarketsOrderCreate, order, &resp)
+	err := b.SendAuthenticatedRequestContext(ctx, "POST", btcMarketsOrderCreate, order, &resp)
 	if err != nil {
 		return 0, err
 	}
@@ -209,22 +230,11 @@ func (b *BTCMarkets) NewOrder(currency, instrument string, price, amount float64
 // CancelOrder cancels an order by its ID
 // orderID - id for order example "1337"
 func (b *BTCMarkets) CancelOrder(orderID []int64) (bool, error) {
-	resp := Response{}
-	type CancelOrder struct {
-		OrderIDs []int64 `json:"orderIds"`
-	}
-	orders := CancelOrder{}
-	orders.OrderIDs = append(orders.OrderIDs, orderID...)
-
-	err := b.SendAuthenticatedRequest("POST", btcMarketsOrderCancel, orders, &resp)
+	resp, err := b.CancelOrderBatch(orderID)
 	if err != nil {
 		return false, err
 	}
 
-	if !resp.Success {
-		return false, fmt.Errorf("%s Unable to cancel order. Error message: %s", b.GetName(), resp.ErrorMessage)
-	}
-
 	ordersToBeCancelled := len(orderID)
 	ordersCancelled := 0
 	for _, y := range resp.Responses {
@@ -242,6 +252,27 @@ func (b *BTCMarkets) CancelOrder(orderID []int64) (bool, error) {
 	return false, fmt.Errorf("%s Unable to cancel order(s)", b.GetName())
 }
 
+// CancelOrderBatch cancels orders by their IDs in a single request, returning
+// the result for each order in resp.Responses
+func (b *BTCMarkets) CancelOrderBatch(orderIDs []int64) (Response, error) {
+	resp := Response{}
+	type CancelOrder struct {
+		OrderIDs []int64 `json:"orderIds"`
+	}
+	orders := CancelOrder{}
+	orders.OrderIDs = append(orders.OrderIDs, orderIDs...)
+
+	err := b.SendAuthenticatedRequest("POST", btcMarketsOrderCancel, orders, &resp)
+	if err != nil {
+		return resp, err
+	}
+
+	if !resp.Success {
+		return resp, fmt.Errorf("%s Unable to cancel order. Error message: %s", b.GetName(), resp.ErrorMessage)
+	}
+	return resp, nil
+}
+
 // GetOrders returns current order information on the exchange
 // currency - example "AUD"
 // instrument - example "BTC"
@@ -404,17 +435,29 @@ func (b *BTCMarkets) WithdrawAUD(accountName, accountNumber, bankName, bsbNumber
 
 // SendHTTPRequest sends an unauthenticated HTTP request
 func (b *BTCMarkets) SendHTTPRequest(path string, result interface{}) error {
-	return b.SendPayload("GET", path, nil, nil, result, false, b.Verbose)
+	return b.SendHTTPRequestContext(context.Background(), path, result)
+}
+
+// SendHTTPRequestContext sends an unauthenticated HTTP request under a call
+// context
+func (b *BTCMarkets) SendHTTPRequestContext(ctx context.Context, path string, result interface{}) error {
+	return b.SendPayloadContext(ctx, "GET", path, nil, nil, result, false, b.Verbose)
 }
 
 // SendAuthenticatedRequest sends an authenticated HTTP request
-func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data interface{}, result interface{}) (err error) {
+func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data interface{}, result interface{}) error {
+	return b.SendAuthenticatedRequestContext(context.Background(), reqType, path, data, result)
+}
+
+// SendAuthenticatedRequestContext sends an authenticated HTTP request under a
+// call context
+func (b *BTCMarkets) SendAuthenticatedRequestContext(ctx context.Context, reqType, path string, data interface{}, result interface{}) (err error) {
 	if !b.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, b.Name)
 	}
 
 	if b.Nonce.Get() == 0 {
-		b.Nonce.Set(time.Now().UnixNano())
+		b.Nonce.Set(b.GetAdjustedTime().UnixNano())
 	} else {
 		b.Nonce.Inc()
 	}
@@ -445,11 +488,15 @@ func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data interfa
 	headers["timestamp"] = b.Nonce.String()[0:13]
 	headers["signature"] = common.Base64Encode(hmac)
 
-	return b.SendPayload(reqType, b.APIUrl+path, headers, bytes.NewBuffer(payload), result, true, b.Verbose)
+	return b.SendPayloadContext(ctx, reqType, b.APIUrl+path, headers, bytes.NewBuffer(payload), result, true, b.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
 func (b *BTCMarkets) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
+	if b.IsFeeEstimated(feeBuilder) {
+		return b.EstimateFee(feeBuilder)
+	}
+
 	var fee float64
 
 	switch feeBuilder.FeeType {
@@ -308,3 +308,15 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestConvertAccountBalances(t *testing.T) {
+	t.Parallel()
+
+	currencies := convertAccountBalances([]AccountBalance{
+		{Currency: "BTC", Balance: 1.5, PendingFunds: 0.5},
+	})
+	if len(currencies) != 1 || currencies[0].CurrencyName != symbol.BTC || currencies[0].Free != 1 ||
+		currencies[0].Locked != 0.5 || currencies[0].Total != 1.5 {
+		t.Errorf("Test Failed - convertAccountBalances() unexpected result %+v", currencies)
+	}
+}
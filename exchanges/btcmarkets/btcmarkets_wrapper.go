@@ -1,34 +1,46 @@
 package btcmarkets
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the BTCMarkets exchange
+func init() {
+	exchange.Register("BTC Markets", func() exchange.IBotExchange { return new(BTCMarkets) })
+}
+
 // Start starts the BTC Markets go routine
-func (b *BTCMarkets) Start(wg *sync.WaitGroup) {
+func (b *BTCMarkets) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		b.Run()
+		b.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the BTC Markets wrapper
-func (b *BTCMarkets) Run() {
+func (b *BTCMarkets) Run(ctx context.Context) {
 	if b.Verbose {
 		log.Printf("%s polling delay: %ds.\n", b.GetName(), b.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	markets, err := b.GetMarkets()
 	if err != nil {
 		log.Printf("%s failed to get active market. Err: %s", b.Name, err)
@@ -59,10 +71,21 @@ func (b *BTCMarkets) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (b *BTCMarkets) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (b *BTCMarkets) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	return b.UpdateTickerContext(context.Background(), p, assetType)
+}
+
+// UpdateTickerContext updates and returns the ticker for a currency pair under
+// a call context
+func (b *BTCMarkets) UpdateTickerContext(ctx context.Context, p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
-	tick, err := b.GetTicker(p.FirstCurrency.String(),
+	tick, err := b.GetTickerContext(ctx, p.FirstCurrency.String(),
 		p.SecondCurrency.String())
 	if err != nil {
 		return tickerPrice, err
@@ -112,7 +135,7 @@ func (b *BTCMarkets) UpdateOrderbook(p pair.CurrencyPair, assetType string) (ord
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: data[1], Price: data[0]})
 	}
 
-	orderbook.ProcessOrderbook(b.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(b.GetName(), p, b.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(b.Name, p, assetType)
 }
 
@@ -127,15 +150,19 @@ func (b *BTCMarkets) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 		return response, err
 	}
 
-	for i := 0; i < len(accountBalance); i++ {
-		var exchangeCurrency exchange.AccountCurrencyInfo
-		exchangeCurrency.CurrencyName = accountBalance[i].Currency
-		exchangeCurrency.TotalValue = accountBalance[i].Balance
-		exchangeCurrency.Hold = accountBalance[i].PendingFunds
+	response.Currencies = convertAccountBalances(accountBalance)
+	return response, nil
+}
 
-		response.Currencies = append(response.Currencies, exchangeCurrency)
+// convertAccountBalances converts balances into account currency info,
+// BTC Markets balances include the pending funds held by open orders
+func convertAccountBalances(balances []AccountBalance) []exchange.AccountCurrencyInfo {
+	var currencies []exchange.AccountCurrencyInfo
+	for _, balance := range balances {
+		currencies = append(currencies, exchange.NewAccountCurrencyInfo(balance.Currency,
+			balance.Balance-balance.PendingFunds, balance.PendingFunds))
 	}
-	return response, nil
+	return currencies
 }
 
 // GetExchangeFundTransferHistory returns funding history, deposits and
@@ -145,6 +172,18 @@ func (b *BTCMarkets) GetExchangeFundTransferHistory() ([]exchange.FundHistory, e
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (b *BTCMarkets) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (b *BTCMarkets) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (b *BTCMarkets) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -152,9 +191,20 @@ func (b *BTCMarkets) GetExchangeHistory(p pair.CurrencyPair, assetType string) (
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (b *BTCMarkets) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (b *BTCMarkets) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
-	return b.NewOrder(p.FirstCurrency.Upper().String(), p.SecondCurrency.Upper().String(), price, amount, side.Format(b.GetName()), orderType.Format(b.GetName()), clientID)
+	return b.SubmitExchangeOrderContext(context.Background(), p, side, orderType, amount, price, clientID)
+}
+
+// SubmitExchangeOrderContext submits a new order under a call context
+func (b *BTCMarkets) SubmitExchangeOrderContext(ctx context.Context, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	return b.NewOrderContext(ctx, p.FirstCurrency.Upper().String(), p.SecondCurrency.Upper().String(), price, amount, side.Format(b.GetName()), orderType.Format(b.GetName()), clientID)
 }
 
 // ModifyExchangeOrder will allow of changing orderbook placement and limit to
@@ -172,6 +222,40 @@ func (b *BTCMarkets) CancelExchangeOrder(orderID int64) error {
 	return nil
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (b *BTCMarkets) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, b.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers in a single
+// request
+func (b *BTCMarkets) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	if len(orderIDs) == 0 {
+		return nil
+	}
+
+	resp, err := b.CancelOrderBatch(orderIDs)
+	if err != nil {
+		return exchange.NewBatchCancelResults(orderIDs, err)
+	}
+
+	results := exchange.NewBatchCancelResults(orderIDs,
+		errors.New("order cancellation not acknowledged by exchange"))
+	for _, y := range resp.Responses {
+		for x := range results {
+			if results[x].OrderID != y.ID {
+				continue
+			}
+			results[x].Err = nil
+			if !y.Success {
+				results[x].Err = errors.New(y.ErrorMessage)
+			}
+		}
+	}
+	return results
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (b *BTCMarkets) CancelAllExchangeOrders() error {
 	orders, err := b.GetOrders("", "", 0, 0, true)
@@ -225,6 +309,35 @@ func (b *BTCMarkets) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail,
 	return OrderDetail, nil
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (b *BTCMarkets) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	var openOrders []exchange.OrderDetail
+	for _, p := range b.GetEnabledCurrencies() {
+		orders, err := b.GetOrders(p.SecondCurrency.String(), p.FirstCurrency.String(), 0, 0, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, order := range orders {
+			openOrders = append(openOrders, exchange.OrderDetail{
+				Exchange:      b.GetName(),
+				ID:            order.ID,
+				ClientID:      order.ClientRequestID,
+				BaseCurrency:  order.Currency,
+				QuoteCurrency: order.Instrument,
+				OrderSide:     order.OrderSide,
+				OrderType:     order.OrderType,
+				CreationTime:  int64(order.CreationTime),
+				Status:        order.Status,
+				Price:         order.Price,
+				Amount:        order.Volume,
+				OpenVolume:    order.OpenVolume,
+			})
+		}
+	}
+	return openOrders, nil
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (b *BTCMarkets) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not supported on exchange")
@@ -245,9 +358,14 @@ func (b *BTCMarkets) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amoun
 	return b.WithdrawAUD(bd.AccountName, bd.AccountNumber, bd.BankName, bd.BSBNumber, amount)
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (b *BTCMarkets) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (b *BTCMarkets) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (b *BTCMarkets) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -256,6 +374,11 @@ func (b *BTCMarkets) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (b *BTCMarkets) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (b *BTCMarkets) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return b.GetFee(feeBuilder)
@@ -84,11 +84,12 @@ func (c *CoinbasePro) SetDefaults() {
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	c.APIUrlDefault = coinbaseproAPIURL
 	c.APIUrl = c.APIUrlDefault
+	c.SetFeeTable(exchange.FeeTable{Maker: 0, Taker: 0.003})
 	c.WebsocketInit()
 }
 
 // Setup initialises the exchange parameters with the current configuration
-func (c *CoinbasePro) Setup(exch config.ExchangeConfig) {
+func (c *CoinbasePro) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		c.SetEnabled(false)
 	} else {
@@ -108,23 +109,31 @@ func (c *CoinbasePro) Setup(exch config.ExchangeConfig) {
 		}
 		err := c.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = c.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = c.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = c.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = c.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = c.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = c.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = c.WebsocketSetup(c.WsConnect,
 			exch.Name,
@@ -132,9 +141,10 @@ func (c *CoinbasePro) Setup(exch config.ExchangeConfig) {
 			coinbaseproWebsocketURL,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 	}
+	return nil
 }
 
 // GetProducts returns supported currency pairs on the exchange with specific
@@ -491,6 +501,13 @@ func (c *CoinbasePro) CancelOrder(orderID string) error {
 	return c.SendAuthenticatedHTTPRequest("DELETE", path, nil, nil)
 }
 
+// CancelOrderByClientOID cancels an order by the client_oid it was placed with
+func (c *CoinbasePro) CancelOrderByClientOID(clientOID string) error {
+	path := fmt.Sprintf("%s/client:%s", coinbaseproOrders, clientOID)
+
+	return c.SendAuthenticatedHTTPRequest("DELETE", path, nil, nil)
+}
+
 // CancelAllOrders cancels all open orders on the exchange and returns and array
 // of order IDs
 // currencyPair - [optional] all orders for a currencyPair string will be
@@ -826,6 +843,10 @@ func (c *CoinbasePro) SendAuthenticatedHTTPRequest(method, path string, params m
 
 // GetFee returns an estimate of fee based on type of transaction
 func (c *CoinbasePro) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
+	if c.IsFeeEstimated(feeBuilder) {
+		return c.EstimateFee(feeBuilder)
+	}
+
 	var fee float64
 	switch feeBuilder.FeeType {
 	case exchange.CryptocurrencyTradeFee:
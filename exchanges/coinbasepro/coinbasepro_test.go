@@ -402,3 +402,13 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestConvertAccounts(t *testing.T) {
+	currencies := convertAccounts([]AccountResponse{
+		{Currency: "BTC", Balance: 1.5, Available: 1, Hold: 0.5},
+	})
+	if len(currencies) != 1 || currencies[0].CurrencyName != symbol.BTC || currencies[0].Free != 1 ||
+		currencies[0].Locked != 0.5 || currencies[0].Total != 1.5 {
+		t.Errorf("Test Failed - convertAccounts() unexpected result %+v", currencies)
+	}
+}
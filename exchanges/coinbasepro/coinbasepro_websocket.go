@@ -24,9 +24,8 @@ const (
 // currencies
 func (c *CoinbasePro) WebsocketSubscriber() error {
 	currencies := []string{}
-	for _, x := range c.EnabledPairs {
-		currency := x[0:3] + "-" + x[3:]
-		currencies = append(currencies, currency)
+	for _, x := range c.GetEnabledCurrencies() {
+		currencies = append(currencies, c.FormatPair(x, exchange.PairFormatRequest))
 	}
 
 	var channels []WsChannels
@@ -62,6 +61,7 @@ func (c *CoinbasePro) WsConnect() error {
 	}
 
 	var dialer websocket.Dialer
+	c.Websocket.ConfigureDialer(&dialer)
 
 	if c.Websocket.GetProxyAddress() != "" {
 		proxy, err := url.Parse(c.Websocket.GetProxyAddress())
@@ -111,7 +111,7 @@ func (c *CoinbasePro) WsReadData() {
 			return
 
 		default:
-			_, resp, err := c.WebsocketConn.ReadMessage()
+			_, resp, err := c.Websocket.ReadMessage(c.WebsocketConn)
 			if err != nil {
 				c.Websocket.DataHandler <- err
 				return
@@ -1,34 +1,46 @@
 package coinbasepro
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the CoinbasePro exchange
+func init() {
+	exchange.Register("CoinbasePro", func() exchange.IBotExchange { return new(CoinbasePro) })
+}
+
 // Start starts the coinbasepro go routine
-func (c *CoinbasePro) Start(wg *sync.WaitGroup) {
+func (c *CoinbasePro) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		c.Run()
+		c.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the coinbasepro wrapper
-func (c *CoinbasePro) Run() {
+func (c *CoinbasePro) Run(ctx context.Context) {
 	if c.Verbose {
 		log.Printf("%s Websocket: %s. (url: %s).\n", c.GetName(), common.IsEnabled(c.Websocket.IsEnabled()), coinbaseproWebsocketURL)
 		log.Printf("%s polling delay: %ds.\n", c.GetName(), c.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", c.GetName(), len(c.EnabledPairs), c.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := c.GetProducts()
 	if err != nil {
 		log.Printf("%s Failed to get available products.\n", c.GetName())
@@ -36,7 +48,8 @@ func (c *CoinbasePro) Run() {
 		currencies := []string{}
 		for _, x := range exchangeProducts {
 			if x.ID != "BTC" && x.ID != "USD" && x.ID != "GBP" {
-				currencies = append(currencies, x.ID[0:3]+x.ID[4:])
+				currencies = append(currencies, x.BaseCurrency+x.QuoteCurrency)
+				pair.RegisterPair(pair.NewCurrencyPair(x.BaseCurrency, x.QuoteCurrency))
 			}
 		}
 		err = c.UpdateCurrencies(currencies, false, false)
@@ -46,6 +59,11 @@ func (c *CoinbasePro) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (c *CoinbasePro) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeAccountInfo retrieves balances for all enabled currencies for the
 // coinbasepro exchange
 func (c *CoinbasePro) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
@@ -55,15 +73,19 @@ func (c *CoinbasePro) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	if err != nil {
 		return response, err
 	}
-	for i := 0; i < len(accountBalance); i++ {
-		var exchangeCurrency exchange.AccountCurrencyInfo
-		exchangeCurrency.CurrencyName = accountBalance[i].Currency
-		exchangeCurrency.TotalValue = accountBalance[i].Available
-		exchangeCurrency.Hold = accountBalance[i].Hold
+	response.Currencies = convertAccounts(accountBalance)
+	return response, nil
+}
 
-		response.Currencies = append(response.Currencies, exchangeCurrency)
+// convertAccounts converts the available and held balances of accounts into
+// account currency info
+func convertAccounts(accounts []AccountResponse) []exchange.AccountCurrencyInfo {
+	var currencies []exchange.AccountCurrencyInfo
+	for _, account := range accounts {
+		currencies = append(currencies, exchange.NewAccountCurrencyInfo(account.Currency,
+			account.Available, account.Hold))
 	}
-	return response, nil
+	return currencies
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -125,7 +147,7 @@ func (c *CoinbasePro) UpdateOrderbook(p pair.CurrencyPair, assetType string) (or
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: obNew.Asks[x].Amount, Price: obNew.Asks[x].Price})
 	}
 
-	orderbook.ProcessOrderbook(c.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(c.GetName(), p, c.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(c.Name, p, assetType)
 }
 
@@ -136,6 +158,18 @@ func (c *CoinbasePro) GetExchangeFundTransferHistory() ([]exchange.FundHistory,
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (c *CoinbasePro) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (c *CoinbasePro) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (c *CoinbasePro) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -143,6 +177,12 @@ func (c *CoinbasePro) GetExchangeHistory(p pair.CurrencyPair, assetType string)
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (c *CoinbasePro) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (c *CoinbasePro) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -159,6 +199,18 @@ func (c *CoinbasePro) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (c *CoinbasePro) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, c.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (c *CoinbasePro) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, c.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (c *CoinbasePro) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -170,6 +222,11 @@ func (c *CoinbasePro) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail,
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (c *CoinbasePro) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (c *CoinbasePro) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -187,11 +244,31 @@ func (c *CoinbasePro) WithdrawFiatExchangeFunds(cryptocurrency pair.CurrencyItem
 	return "", errors.New("not yet implemented")
 }
 
+// WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a withdrawal is
+// submitted
+func (c *CoinbasePro) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
+	return "", errors.New("not yet implemented")
+}
+
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (c *CoinbasePro) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetWebsocket returns a pointer to the exchange websocket
 func (c *CoinbasePro) GetWebsocket() (*exchange.Websocket, error) {
 	return c.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (c *CoinbasePro) GetExchangeServerTime() (time.Time, error) {
+	resp, err := c.GetServerTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(resp.Epoch*float64(time.Second))), nil
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (c *CoinbasePro) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return c.GetFee(feeBuilder)
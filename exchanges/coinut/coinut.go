@@ -74,7 +74,7 @@ func (c *COINUT) SetDefaults() {
 }
 
 // Setup sets the current exchange configuration
-func (c *COINUT) Setup(exch config.ExchangeConfig) {
+func (c *COINUT) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		c.SetEnabled(false)
 	} else {
@@ -91,23 +91,31 @@ func (c *COINUT) Setup(exch config.ExchangeConfig) {
 		c.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := c.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = c.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = c.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = c.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = c.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = c.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = c.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = c.WebsocketSetup(c.WsConnect,
 			exch.Name,
@@ -115,9 +123,10 @@ func (c *COINUT) Setup(exch config.ExchangeConfig) {
 			coinutWebsocketURL,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 	}
+	return nil
 }
 
 // GetInstruments returns instruments
@@ -303,7 +312,7 @@ func (c *COINUT) SendHTTPRequest(apiRequest string, params map[string]interface{
 	}
 
 	if c.Nonce.Get() == 0 {
-		c.Nonce.Set(time.Now().Unix())
+		c.Nonce.Set(c.GetAdjustedTime().Unix())
 	} else {
 		c.Nonce.Inc()
 	}
@@ -47,7 +47,7 @@ func (c *COINUT) WsReadData() {
 			return
 
 		default:
-			_, resp, err := c.WebsocketConn.ReadMessage()
+			_, resp, err := c.Websocket.ReadMessage(c.WebsocketConn)
 			if err != nil {
 				c.Websocket.DataHandler <- err
 				return
@@ -173,6 +173,7 @@ func (c *COINUT) WsConnect() error {
 	}
 
 	var Dialer websocket.Dialer
+	c.Websocket.ConfigureDialer(&Dialer)
 
 	if c.Websocket.GetProxyAddress() != "" {
 		proxy, err := url.Parse(c.Websocket.GetProxyAddress())
@@ -220,7 +221,7 @@ func (c *COINUT) WsConnect() error {
 // GetNonce returns a nonce for a required request
 func (c *COINUT) GetNonce() int64 {
 	if c.Nonce.Get() == 0 {
-		c.Nonce.Set(time.Now().Unix())
+		c.Nonce.Set(c.GetAdjustedTime().Unix())
 	} else {
 		c.Nonce.Inc()
 	}
@@ -245,7 +246,7 @@ func (c *COINUT) WsSetInstrumentList() error {
 		return err
 	}
 
-	_, resp, err := c.WebsocketConn.ReadMessage()
+	_, resp, err := c.Websocket.ReadMessage(c.WebsocketConn)
 	if err != nil {
 		return err
 	}
@@ -277,7 +278,7 @@ func (c *COINUT) WsSubscribe() error {
 	for _, p := range pairs {
 		ticker := wsRequest{
 			Request:   "inst_tick",
-			InstID:    instrumentListByString[p.Pair().String()],
+			InstID:    instrumentListByString[c.FormatPair(p, exchange.PairFormatRequest)],
 			Subscribe: true,
 			Nonce:     c.GetNonce(),
 		}
@@ -294,7 +295,7 @@ func (c *COINUT) WsSubscribe() error {
 
 		orderbook := wsRequest{
 			Request:   "inst_order_book",
-			InstID:    instrumentListByString[p.Pair().String()],
+			InstID:    instrumentListByString[c.FormatPair(p, exchange.PairFormatRequest)],
 			Subscribe: true,
 			Nonce:     c.GetNonce(),
 		}
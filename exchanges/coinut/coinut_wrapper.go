@@ -1,34 +1,46 @@
 package coinut
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the COINUT exchange
+func init() {
+	exchange.Register("COINUT", func() exchange.IBotExchange { return new(COINUT) })
+}
+
 // Start starts the COINUT go routine
-func (c *COINUT) Start(wg *sync.WaitGroup) {
+func (c *COINUT) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		c.Run()
+		c.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the COINUT wrapper
-func (c *COINUT) Run() {
+func (c *COINUT) Run(ctx context.Context) {
 	if c.Verbose {
 		log.Printf("%s Websocket: %s. (url: %s).\n", c.GetName(), common.IsEnabled(c.Websocket.IsEnabled()), coinutWebsocketURL)
 		log.Printf("%s polling delay: %ds.\n", c.GetName(), c.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", c.GetName(), len(c.EnabledPairs), c.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := c.GetInstruments()
 	if err != nil {
 		log.Printf("%s Failed to get available products.\n", c.GetName())
@@ -48,6 +60,11 @@ func (c *COINUT) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (c *COINUT) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeAccountInfo retrieves balances for all enabled currencies for the
 // COINUT exchange
 func (c *COINUT) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
@@ -59,12 +76,9 @@ func (c *COINUT) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 			return response, err
 		}
 		for i := 0; i < len(accountBalance); i++ {
-			var exchangeCurrency exchange.AccountCurrencyInfo
-			exchangeCurrency.CurrencyName = accountBalance[i].Currency
-			exchangeCurrency.TotalValue = accountBalance[i].Available
-			exchangeCurrency.Hold = accountBalance[i].Hold
-
-			response.Currencies = append(response.Currencies, exchangeCurrency)
+			response.Currencies = append(response.Currencies,
+				exchange.NewAccountCurrencyInfo(accountBalance[i].Currency,
+					accountBalance[i].Available, accountBalance[i].Hold))
 		}
 	*/
 	return response, nil
@@ -73,7 +87,7 @@ func (c *COINUT) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 // UpdateTicker updates and returns the ticker for a currency pair
 func (c *COINUT) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
-	tick, err := c.GetInstrumentTicker(c.InstrumentMap[p.Pair().String()])
+	tick, err := c.GetInstrumentTicker(c.InstrumentMap[c.FormatPair(p, exchange.PairFormatRequest)])
 	if err != nil {
 		return ticker.Price{}, err
 	}
@@ -109,7 +123,7 @@ func (c *COINUT) GetOrderbookEx(p pair.CurrencyPair, assetType string) (orderboo
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (c *COINUT) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
-	orderbookNew, err := c.GetInstrumentOrderbook(c.InstrumentMap[p.Pair().String()], 200)
+	orderbookNew, err := c.GetInstrumentOrderbook(c.InstrumentMap[c.FormatPair(p, exchange.PairFormatRequest)], 200)
 	if err != nil {
 		return orderBook, err
 	}
@@ -122,7 +136,7 @@ func (c *COINUT) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbo
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: orderbookNew.Sell[x].Quantity, Price: orderbookNew.Sell[x].Price})
 	}
 
-	orderbook.ProcessOrderbook(c.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(c.GetName(), p, c.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(c.Name, p, assetType)
 }
 
@@ -133,6 +147,18 @@ func (c *COINUT) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (c *COINUT) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (c *COINUT) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (c *COINUT) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -140,6 +166,12 @@ func (c *COINUT) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]ex
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (c *COINUT) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (c *COINUT) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -156,6 +188,18 @@ func (c *COINUT) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (c *COINUT) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, c.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (c *COINUT) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, c.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (c *COINUT) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -167,6 +211,11 @@ func (c *COINUT) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, erro
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (c *COINUT) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (c *COINUT) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -184,9 +233,14 @@ func (c *COINUT) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount fl
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (c *COINUT) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (c *COINUT) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (c *COINUT) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -195,6 +249,11 @@ func (c *COINUT) GetWebsocket() (*exchange.Websocket, error) {
 	return c.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (c *COINUT) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (c *COINUT) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return c.GetFee(feeBuilder)
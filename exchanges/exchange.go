@@ -1,9 +1,12 @@
 package exchange
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -13,6 +16,8 @@ import (
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/nonce"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/request"
@@ -27,8 +32,18 @@ const (
 	ErrExchangeNotFound = "Exchange not found in dataset."
 	// DefaultHTTPTimeout is the default HTTP/HTTPS Timeout for exchange requests
 	DefaultHTTPTimeout = time.Second * 15
+
+	defaultHTTPKeepAlive       = time.Second * 30
+	defaultHTTPIdleConnTimeout = time.Second * 90
+	defaultHTTPMaxIdleConns    = 100
+	defaultHTTPDialTimeout     = time.Second * 30
+	defaultHTTPTLSTimeout      = time.Second * 10
 )
 
+// ErrHistoricCandlesNotSupported is returned by exchanges which are unable to
+// retrieve historic candles
+var ErrHistoricCandlesNotSupported = errors.New("historic candle retrieval not supported")
+
 // FeeType custom type for calculating fees based on method
 type FeeType string
 
@@ -81,6 +96,9 @@ type FeeBuilder struct {
 	// Used to multiply for fee calculations
 	PurchasePrice float64
 	Amount        float64
+	// Price of the fee discount token in the currency the fee is calculated
+	// in, used to check the token balance covers the discounted fee
+	FeeTokenPrice float64
 }
 
 // Definitions for each type of withdrawal method for a given exchange
@@ -126,8 +144,67 @@ const (
 	WithdrawFiatViaWebsiteOnlyText          string = "WITHDRAW FIAT VIA WEBSITE ONLY"
 
 	UnknownWithdrawalTypeText string = "UNKNOWN"
+	UnknownWithdrawalType     string = "unknown"
+)
+
+// Machine readable withdrawal method classifications
+const (
+	WithdrawalAssetCrypto = "crypto"
+	WithdrawalAssetFiat   = "fiat"
+
+	WithdrawalAutomationAuto                  = "auto"
+	WithdrawalAutomationAutoWithAPIPermission = "auto_with_api_permission"
+	WithdrawalAutomationAutoWithSetup         = "auto_with_setup"
+	WithdrawalAutomationManual                = "manual"
+	WithdrawalAutomationWebsiteOnly           = "website_only"
+
+	WithdrawalRequirement2FA             = "2fa"
+	WithdrawalRequirementSMS             = "sms"
+	WithdrawalRequirementEmail           = "email"
+	WithdrawalRequirementWebsiteApproval = "website_approval"
+	WithdrawalRequirementAPIPermission   = "api_permission"
 )
 
+// WithdrawalMethod is a machine readable description of a single withdrawal
+// permission supported by an exchange
+type WithdrawalMethod struct {
+	Permission  uint32 `json:"permission"`
+	Asset       string `json:"asset"`
+	Automation  string `json:"automation"`
+	Requirement string `json:"requirement,omitempty"`
+	Description string `json:"description"`
+}
+
+// WithdrawalLimit holds the maximum amount of a currency an exchange allows
+// to be withdrawn over a rolling window
+type WithdrawalLimit struct {
+	Currency pair.CurrencyItem
+	Amount   float64
+	Window   time.Duration
+}
+
+// withdrawalMethods maps each withdrawal permission to its classification
+var withdrawalMethods = map[uint32]WithdrawalMethod{
+	AutoWithdrawCrypto:                  {Asset: WithdrawalAssetCrypto, Automation: WithdrawalAutomationAuto, Description: AutoWithdrawCryptoText},
+	AutoWithdrawCryptoWithAPIPermission: {Asset: WithdrawalAssetCrypto, Automation: WithdrawalAutomationAutoWithAPIPermission, Requirement: WithdrawalRequirementAPIPermission, Description: AutoWithdrawCryptoWithAPIPermissionText},
+	AutoWithdrawCryptoWithSetup:         {Asset: WithdrawalAssetCrypto, Automation: WithdrawalAutomationAutoWithSetup, Description: AutoWithdrawCryptoWithSetupText},
+	WithdrawCryptoWith2FA:               {Asset: WithdrawalAssetCrypto, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirement2FA, Description: WithdrawCryptoWith2FAText},
+	WithdrawCryptoWithSMS:               {Asset: WithdrawalAssetCrypto, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirementSMS, Description: WithdrawCryptoWithSMSText},
+	WithdrawCryptoWithEmail:             {Asset: WithdrawalAssetCrypto, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirementEmail, Description: WithdrawCryptoWithEmailText},
+	WithdrawCryptoWithWebsiteApproval:   {Asset: WithdrawalAssetCrypto, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirementWebsiteApproval, Description: WithdrawCryptoWithWebsiteApprovalText},
+	WithdrawCryptoWithAPIPermission:     {Asset: WithdrawalAssetCrypto, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirementAPIPermission, Description: WithdrawCryptoWithAPIPermissionText},
+	AutoWithdrawFiat:                    {Asset: WithdrawalAssetFiat, Automation: WithdrawalAutomationAuto, Description: AutoWithdrawFiatText},
+	AutoWithdrawFiatWithAPIPermission:   {Asset: WithdrawalAssetFiat, Automation: WithdrawalAutomationAutoWithAPIPermission, Requirement: WithdrawalRequirementAPIPermission, Description: AutoWithdrawFiatWithAPIPermissionText},
+	AutoWithdrawFiatWithSetup:           {Asset: WithdrawalAssetFiat, Automation: WithdrawalAutomationAutoWithSetup, Description: AutoWithdrawFiatWithSetupText},
+	WithdrawFiatWith2FA:                 {Asset: WithdrawalAssetFiat, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirement2FA, Description: WithdrawFiatWith2FAText},
+	WithdrawFiatWithSMS:                 {Asset: WithdrawalAssetFiat, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirementSMS, Description: WithdrawFiatWithSMSText},
+	WithdrawFiatWithEmail:               {Asset: WithdrawalAssetFiat, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirementEmail, Description: WithdrawFiatWithEmailText},
+	WithdrawFiatWithWebsiteApproval:     {Asset: WithdrawalAssetFiat, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirementWebsiteApproval, Description: WithdrawFiatWithWebsiteApprovalText},
+	WithdrawFiatWithAPIPermission:       {Asset: WithdrawalAssetFiat, Automation: WithdrawalAutomationManual, Requirement: WithdrawalRequirementAPIPermission, Description: WithdrawFiatWithAPIPermissionText},
+	WithdrawCryptoViaWebsiteOnly:        {Asset: WithdrawalAssetCrypto, Automation: WithdrawalAutomationWebsiteOnly, Description: WithdrawCryptoViaWebsiteOnlyText},
+	WithdrawFiatViaWebsiteOnly:          {Asset: WithdrawalAssetFiat, Automation: WithdrawalAutomationWebsiteOnly, Description: WithdrawFiatViaWebsiteOnlyText},
+}
+
 // AccountInfo is a Generic type to hold each exchange's holdings in
 // all enabled currencies
 type AccountInfo struct {
@@ -135,14 +212,54 @@ type AccountInfo struct {
 	Currencies   []AccountCurrencyInfo
 }
 
-// AccountCurrencyInfo is a sub type to store currency name and value
+// AccountCurrencyInfo holds the balance of a currency on an exchange. Free
+// is available to trade or withdraw, Locked is held by open orders or pending
+// withdrawals and Total is their sum. Exchanges which only report a total
+// balance report it as free. Wallets optionally breaks the balance down by
+// the exchange wallets or accounts holding it
 type AccountCurrencyInfo struct {
 	CurrencyName string
-	TotalValue   float64
-	Hold         float64
+	Free         float64
+	Locked       float64
+	Total        float64
+	Wallets      []WalletBalance `json:",omitempty"`
+}
+
+// WalletBalance holds the balance of a currency in one exchange wallet, such
+// as an exchange, margin or funding wallet
+type WalletBalance struct {
+	Wallet string
+	Free   float64
+	Locked float64
+	Total  float64
+}
+
+// NewAccountCurrencyInfo returns the balance of a currency from its free and
+// locked amounts
+func NewAccountCurrencyInfo(currencyName string, free, locked float64) AccountCurrencyInfo {
+	return AccountCurrencyInfo{
+		CurrencyName: currencyName,
+		Free:         free,
+		Locked:       locked,
+		Total:        free + locked,
+	}
+}
+
+// AddWallet adds the balance of a wallet to the breakdown and to the balance
+// of the currency
+func (a *AccountCurrencyInfo) AddWallet(wallet string, free, locked float64) {
+	a.Wallets = append(a.Wallets, WalletBalance{
+		Wallet: wallet,
+		Free:   free,
+		Locked: locked,
+		Total:  free + locked,
+	})
+	a.Free += free
+	a.Locked += locked
+	a.Total += free + locked
 }
 
-// TradeHistory holds exchange history data
+// TradeHistory holds exchange history data, timestamps are in Unix seconds
 type TradeHistory struct {
 	Timestamp int64
 	TID       int64
@@ -156,6 +273,7 @@ type TradeHistory struct {
 type OrderDetail struct {
 	Exchange      string
 	ID            int64
+	ClientID      string
 	BaseCurrency  string
 	QuoteCurrency string
 	OrderSide     string
@@ -204,6 +322,7 @@ type Base struct {
 	PairsLastUpdated                           int64
 	SupportsAutoPairUpdating                   bool
 	SupportsRESTTickerBatching                 bool
+	SupportsBrokerID                           bool
 	HTTPTimeout                                time.Duration
 	HTTPUserAgent                              string
 	WebsocketURL                               string
@@ -214,14 +333,35 @@ type Base struct {
 	RequestCurrencyPairFormat                  config.CurrencyPairFormatConfig
 	ConfigCurrencyPairFormat                   config.CurrencyPairFormatConfig
 	Websocket                                  *Websocket
+	clockSkew                                  time.Duration
+	clockSkewMtx                               sync.Mutex
+	apiPermissions                             APIPermissions
+	apiPermissionsFunc                         APIPermissionsFunc
+	apiPermissionsMtx                          sync.Mutex
+	feeDiscount                                *FeeDiscount
+	feeDiscountMtx                             sync.Mutex
+	feeTable                                   *FeeTable
+	accountFees                                *AccountFees
+	accountFeesMtx                             sync.Mutex
+	fiatWithdrawalSupport                      []FiatWithdrawalSupport
+	orderbookDepthDefault                      int
+	orderbookDepths                            []pairDepth
+	orderbookDepthMtx                          sync.Mutex
+	brokerID                                   string
+	assetMetadata                              []AssetMetadata
+	assetMetadataOverrides                     []AssetMetadata
+	assetMetadataFetcher                       AssetMetadataFetcher
+	assetMetadataMtx                           sync.Mutex
+	tlsConfig                                  *tls.Config
+	tlsConfigMtx                               sync.Mutex
 	*request.Requester
 }
 
 // IBotExchange enforces standard functions for all exchanges supported in
 // GoCryptoTrader
 type IBotExchange interface {
-	Setup(exch config.ExchangeConfig)
-	Start(wg *sync.WaitGroup)
+	Setup(exch config.ExchangeConfig) error
+	Start(ctx context.Context, wg *sync.WaitGroup)
 	SetDefaults()
 	GetName() string
 	IsEnabled() bool
@@ -235,28 +375,146 @@ type IBotExchange interface {
 	GetAssetTypes() []string
 	GetExchangeAccountInfo() (AccountInfo, error)
 	GetAuthenticatedAPISupport() bool
+	VerifyAPICredentials(authCheck func() error) error
+	GetAPIPermissions() APIPermissions
 	SetCurrencies(pairs []pair.CurrencyPair, enabledPairs bool) error
 	GetExchangeHistory(pair.CurrencyPair, string) ([]TradeHistory, error)
+	GetHistoricCandles(p pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error)
 	SupportsAutoPairUpdates() bool
 	GetLastPairsUpdateTime() int64
+	GetListedPairs() ([]string, error)
+	AddNewListings(exchangeProducts []string, enable *config.PairFilterConfig) ([]string, []string, error)
+	FormatPair(p pair.CurrencyPair, context string) string
+	ParsePair(s, context string) (pair.CurrencyPair, error)
 	SupportsRESTTickerBatchUpdates() bool
 
 	GetWithdrawPermissions() uint32
 	FormatWithdrawPermissions() string
+	GetWithdrawalMethods() []WithdrawalMethod
 	SupportsWithdrawPermissions(permissions uint32) bool
+	GetWithdrawalLimits() ([]WithdrawalLimit, error)
+
+	GetFeeByType(feeBuilder FeeBuilder) (float64, error)
+	IsFeeEstimated(feeBuilder FeeBuilder) bool
+	GetOrderbookDepth(p pair.CurrencyPair) int
+	GetWithdrawalMinimum(currency string) (float64, bool)
+	GetAssetMetadata(currency string) []AssetMetadata
+	GetChainMetadata(currency, chain string) (AssetMetadata, error)
+	CheckWithdrawal(currency, chain string, amount float64) error
+	SetAssetMetadataOverrides(overrides []config.AssetMetadataConfig)
+	UpdateAssetMetadata() error
+	SetFeeDiscount(cfg config.FeeDiscountConfig)
+	SetBrokerID(brokerID string) error
+	SetRequestContext(ctx context.Context)
+	SetTLSConfig(cfg *config.TLSConfig) error
+	GetBrokerID() string
+	SetChaos(cfg config.ChaosConfig)
+	GetFeeDiscount() (FeeDiscount, error)
+	UpdateFeeDiscount() (FeeDiscount, error)
+	SetFeeTokenBalance(balance float64)
+	DepleteFeeToken(amount float64) (float64, error)
 
 	GetExchangeFundTransferHistory() ([]FundHistory, error)
+	GetExchangeDepositHistory(start, end time.Time) ([]Deposit, error)
+	GetExchangeWithdrawalHistory(start, end time.Time) ([]Withdrawal, error)
 	SubmitExchangeOrder(p pair.CurrencyPair, side OrderSide, orderType OrderType, amount, price float64, clientID string) (int64, error)
 	ModifyExchangeOrder(orderID int64, modify ModifyOrder) (int64, error)
 	CancelExchangeOrder(orderID int64) error
 	CancelAllExchangeOrders() error
+	SubmitExchangeOrders(orders []BatchOrder) []BatchOrderResult
+	CancelExchangeOrders(orderIDs []int64) []BatchCancelResult
 	GetExchangeOrderInfo(orderID int64) (OrderDetail, error)
+	GetExchangeOpenOrders() ([]OrderDetail, error)
 	GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error)
 
 	WithdrawCryptoExchangeFunds(address string, cryptocurrency pair.CurrencyItem, amount float64) (string, error)
 	WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount float64) (string, error)
+	WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details BankDetails) (string, error)
+	GetFiatWithdrawalSupport() []FiatWithdrawalSupport
+	SupportsFiatWithdrawal(transferType, currency string) bool
 
 	GetWebsocket() (*Websocket, error)
+
+	GetExchangeServerTime() (time.Time, error)
+	SetClockSkew(skew time.Duration)
+	GetClockSkew() time.Duration
+	GetHTTPClockSkew() (time.Duration, error)
+}
+
+// IClientIDCanceller is implemented by exchanges which can cancel an order
+// natively by the client order ID it was submitted with
+type IClientIDCanceller interface {
+	CancelOrderByClientID(clientID string) error
+}
+
+// IContextExchange is implemented by exchanges whose ticker and order
+// submission requests can be made under a call context, abandoning them when
+// the context is cancelled or its deadline passes
+type IContextExchange interface {
+	UpdateTickerContext(ctx context.Context, p pair.CurrencyPair, assetType string) (ticker.Price, error)
+	SubmitExchangeOrderContext(ctx context.Context, p pair.CurrencyPair, side OrderSide, orderType OrderType, amount, price float64, clientID string) (int64, error)
+}
+
+// SetClockSkew sets the measured difference between the exchange server clock
+// and the local clock, which is applied to generated timestamps and nonces
+func (e *Base) SetClockSkew(skew time.Duration) {
+	e.clockSkewMtx.Lock()
+	e.clockSkew = skew
+	e.clockSkewMtx.Unlock()
+	e.Nonce.SetOffset(skew)
+}
+
+// GetClockSkew returns the measured difference between the exchange server
+// clock and the local clock
+func (e *Base) GetClockSkew() time.Duration {
+	e.clockSkewMtx.Lock()
+	defer e.clockSkewMtx.Unlock()
+	return e.clockSkew
+}
+
+// GetAdjustedTime returns the current local time corrected by the measured
+// exchange clock skew
+func (e *Base) GetAdjustedTime() time.Time {
+	return time.Now().Add(e.GetClockSkew())
+}
+
+// CalculateClockSkew measures the difference between an exchange server clock
+// and the local clock, using the midpoint of the request round trip as the
+// local reference time
+func CalculateClockSkew(getServerTime func() (time.Time, error)) (time.Duration, error) {
+	start := time.Now()
+	serverTime, err := getServerTime()
+	if err != nil {
+		return 0, err
+	}
+	end := time.Now()
+
+	if serverTime.IsZero() {
+		return 0, errors.New("exchange returned an empty server time")
+	}
+
+	midpoint := start.Add(end.Sub(start) / 2)
+	return serverTime.Sub(midpoint), nil
+}
+
+// GetHTTPClockSkew returns the difference between the exchange server clock
+// and the local clock estimated from the Date headers of its REST responses
+func (e *Base) GetHTTPClockSkew() (time.Duration, error) {
+	if e.Requester == nil {
+		return 0, request.ErrNoServerDate
+	}
+	return e.Requester.GetServerClockSkew()
+}
+
+// MeasureClockSkew measures the difference between an exchange server clock
+// and the local clock from its server time endpoint, falling back to the Date
+// headers of its REST responses when it doesn't expose its server time
+func MeasureClockSkew(exch IBotExchange) (time.Duration, error) {
+	skew, err := CalculateClockSkew(exch.GetExchangeServerTime)
+	if err == nil {
+		return skew, nil
+	}
+	return exch.GetHTTPClockSkew()
 }
 
 // SupportsRESTTickerBatchUpdates returns whether or not the
@@ -316,6 +574,83 @@ func (e *Base) GetHTTPClientUserAgent() string {
 	return e.HTTPUserAgent
 }
 
+// SetHTTPClientTransport applies the supplied transport tuning to the
+// exchanges HTTP client. A nil config leaves the default transport in place
+func (e *Base) SetHTTPClientTransport(cfg *config.HTTPTransportConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	tlsMinVersion, err := getTLSVersion(cfg.TLSMinVersion)
+	if err != nil {
+		return fmt.Errorf("exchange.go - %s setting HTTP transport error %s",
+			e.Name, err)
+	}
+
+	keepAlive := cfg.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = defaultHTTPKeepAlive
+	}
+
+	idleConnTimeout := cfg.IdleConnTimeout
+	if idleConnTimeout == 0 {
+		idleConnTimeout = defaultHTTPIdleConnTimeout
+	}
+
+	maxIdleConns := cfg.MaxIdleConns
+	if maxIdleConns == 0 {
+		maxIdleConns = defaultHTTPMaxIdleConns
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   defaultHTTPDialTimeout,
+			KeepAlive: keepAlive,
+		}).DialContext,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   defaultHTTPTLSTimeout,
+		ExpectContinueTimeout: time.Second,
+		ForceAttemptHTTP2:     !cfg.DisableHTTP2,
+	}
+
+	// The TLS config of the exchange carries over to the new transport
+	if tlsConfig := e.GetTLSConfig(); tlsConfig != nil {
+		tlsConfig.MinVersion = tlsMinVersion
+		transport.TLSClientConfig = tlsConfig
+	} else if tlsMinVersion != 0 {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tlsMinVersion}
+	}
+
+	if cfg.DisableHTTP2 {
+		// A non-nil empty map prevents the transport from negotiating HTTP/2
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	e.GetHTTPClient().Transport = transport
+	e.Requester.EnableConnectionStats(cfg.RecordTimings)
+	e.Requester.SetResponseCache(cfg.CacheResponses, cfg.MicroCacheTTL)
+	return nil
+}
+
+// getTLSVersion converts a TLS version string such as "1.2" into its
+// crypto/tls constant. An empty string returns zero for the Go default
+func getTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "":
+		return 0, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %s", version)
+}
+
 // SetClientProxyAddress sets a proxy address for REST and websocket requests
 func (e *Base) SetClientProxyAddress(addr string) error {
 	if addr != "" {
@@ -517,17 +852,64 @@ func (e *Base) GetName() string {
 // GetEnabledCurrencies is a method that returns the enabled currency pairs of
 // the exchange base
 func (e *Base) GetEnabledCurrencies() []pair.CurrencyPair {
-	return pair.FormatPairs(e.EnabledPairs,
+	return e.toCanonicalPairs(pair.FormatPairs(e.EnabledPairs,
 		e.ConfigCurrencyPairFormat.Delimiter,
-		e.ConfigCurrencyPairFormat.Index)
+		e.ConfigCurrencyPairFormat.Index))
 }
 
 // GetAvailableCurrencies is a method that returns the available currency pairs
 // of the exchange base
 func (e *Base) GetAvailableCurrencies() []pair.CurrencyPair {
-	return pair.FormatPairs(e.AvailablePairs,
+	return e.toCanonicalPairs(pair.FormatPairs(e.AvailablePairs,
 		e.ConfigCurrencyPairFormat.Delimiter,
-		e.ConfigCurrencyPairFormat.Index)
+		e.ConfigCurrencyPairFormat.Index))
+}
+
+// toCanonicalPairs translates exchange specific currency codes to their
+// canonical codes
+func (e *Base) toCanonicalPairs(pairs []pair.CurrencyPair) []pair.CurrencyPair {
+	for x := range pairs {
+		pairs[x] = translation.PairToCanonical(e.Name, pairs[x])
+	}
+	return pairs
+}
+
+// toCanonicalProducts translates the currency codes of exchange products to
+// their canonical codes. Products are left untranslated when the canonical
+// pair cannot be parsed back using the config pair format, for example an
+// unknown four character code on an exchange without a pair delimiter
+func (e *Base) toCanonicalProducts(products []string) []string {
+	if len(translation.GetExchangeCodes(e.Name)) == 0 {
+		return products
+	}
+
+	format := e.ConfigCurrencyPairFormat
+	result := make([]string, len(products))
+	for x := range products {
+		result[x] = products[x]
+		if (format.Delimiter != "" && !common.StringContains(products[x], format.Delimiter)) ||
+			(format.Index != "" && !common.StringContains(products[x], format.Index)) ||
+			(format.Delimiter == "" && format.Index == "" && len(products[x]) <= 3) {
+			continue
+		}
+
+		parsed := pair.FormatPairs([]string{products[x]}, format.Delimiter, format.Index)
+		if len(parsed) == 0 {
+			continue
+		}
+
+		canonical := translation.PairToCanonical(e.Name, parsed[0])
+		if canonical.Equal(parsed[0], true) {
+			continue
+		}
+
+		formatted := canonical.Display(format.Delimiter, format.Uppercase).String()
+		reparsed := pair.FormatPairs([]string{formatted}, format.Delimiter, format.Index)
+		if len(reparsed) == 1 && reparsed[0].Equal(canonical, true) {
+			result[x] = formatted
+		}
+	}
+	return result
 }
 
 // SupportsCurrency returns true or not whether a currency pair exists in the
@@ -575,21 +957,20 @@ func GetAndFormatExchangeCurrencies(exchName string, pairs []pair.CurrencyPair)
 }
 
 // FormatExchangeCurrency is a method that formats and returns a currency pair
-// based on the user currency display preferences
+// based on the user currency display preferences. Canonical currency codes are
+// translated to the codes used by the exchange
 func FormatExchangeCurrency(exchName string, p pair.CurrencyPair) pair.CurrencyItem {
 	cfg := config.GetConfig()
 	exch, _ := cfg.GetExchangeConfig(exchName)
 
-	return p.Display(exch.RequestCurrencyPairFormat.Delimiter,
-		exch.RequestCurrencyPairFormat.Uppercase)
+	return formatPair(translation.PairToExchange(exchName, p), *exch.RequestCurrencyPairFormat)
 }
 
 // FormatCurrency is a method that formats and returns a currency pair
 // based on the user currency display preferences
 func FormatCurrency(p pair.CurrencyPair) pair.CurrencyItem {
 	cfg := config.GetConfig()
-	return p.Display(cfg.Currency.CurrencyPairFormat.Delimiter,
-		cfg.Currency.CurrencyPairFormat.Uppercase)
+	return formatPair(p, *cfg.Currency.CurrencyPairFormat)
 }
 
 // SetEnabled is a method that sets if the exchange is enabled
@@ -610,6 +991,11 @@ func (e *Base) SetAPIKeys(APIKey, APISecret, ClientID string, b64Decode bool) {
 
 	e.APIKey = APIKey
 	e.ClientID = ClientID
+	if e.Requester != nil {
+		// Processes using the same API key share its rate limit buckets
+		// without the key itself being stored in the shared backend
+		e.Requester.SetSharedLimitID(common.HexEncodeToString(common.GetSHA256([]byte(APIKey)))[:16])
+	}
 
 	if b64Decode {
 		result, err := common.Base64Decode(APISecret)
@@ -654,61 +1040,18 @@ func (e *Base) SetCurrencies(pairs []pair.CurrencyPair, enabledPairs bool) error
 }
 
 // UpdateCurrencies updates the exchange currency pairs for either enabledPairs or
-// availablePairs
+// availablePairs, discovering the pairs from the exchange products, storing
+// them and persisting them to the config when they change
 func (e *Base) UpdateCurrencies(exchangeProducts []string, enabled, force bool) error {
-	if len(exchangeProducts) == 0 {
-		return fmt.Errorf("%s UpdateCurrencies error - exchangeProducts is empty", e.Name)
-	}
-
-	exchangeProducts = common.SplitStrings(common.StringToUpper(common.JoinStrings(exchangeProducts, ",")), ",")
-	var products []string
-
-	for x := range exchangeProducts {
-		if exchangeProducts[x] == "" {
-			continue
-		}
-		products = append(products, exchangeProducts[x])
-	}
-
-	var newPairs, removedPairs []string
-	var updateType string
-
-	if enabled {
-		newPairs, removedPairs = pair.FindPairDifferences(e.EnabledPairs, products)
-		updateType = "enabled"
-	} else {
-		newPairs, removedPairs = pair.FindPairDifferences(e.AvailablePairs, products)
-		updateType = "available"
+	products, err := e.DiscoverPairs(exchangeProducts)
+	if err != nil {
+		return err
 	}
 
-	if force || len(newPairs) > 0 || len(removedPairs) > 0 {
-		cfg := config.GetConfig()
-		exch, err := cfg.GetExchangeConfig(e.Name)
-		if err != nil {
-			return err
-		}
-
-		if force {
-			log.Printf("%s forced update of %s pairs.", e.Name, updateType)
-		} else {
-			if len(newPairs) > 0 {
-				log.Printf("%s Updating pairs - New: %s.\n", e.Name, newPairs)
-			}
-			if len(removedPairs) > 0 {
-				log.Printf("%s Updating pairs - Removed: %s.\n", e.Name, removedPairs)
-			}
-		}
-
-		if enabled {
-			exch.EnabledPairs = common.JoinStrings(products, ",")
-			e.EnabledPairs = products
-		} else {
-			exch.AvailablePairs = common.JoinStrings(products, ",")
-			e.AvailablePairs = products
-		}
-		return cfg.UpdateExchangeConfig(exch)
+	if !e.StorePairs(products, enabled, force) {
+		return nil
 	}
-	return nil
+	return e.PersistPairs()
 }
 
 // ModifyOrder is a an order modifyer
@@ -838,54 +1181,34 @@ func (e *Base) SupportsWithdrawPermissions(permissions uint32) bool {
 	return false
 }
 
-// FormatWithdrawPermissions will return each of the exchange's compatible withdrawal methods in readable form
-func (e *Base) FormatWithdrawPermissions() string {
-	services := []string{}
+// GetWithdrawalMethods returns each of the exchange's compatible withdrawal
+// methods in a machine readable form
+func (e *Base) GetWithdrawalMethods() []WithdrawalMethod {
+	methods := []WithdrawalMethod{}
 	for i := 0; i < 32; i++ {
 		var check uint32 = 1 << uint32(i)
 		if e.GetWithdrawPermissions()&check != 0 {
-			switch check {
-			case AutoWithdrawCrypto:
-				services = append(services, AutoWithdrawCryptoText)
-			case AutoWithdrawCryptoWithAPIPermission:
-				services = append(services, AutoWithdrawCryptoWithAPIPermissionText)
-			case AutoWithdrawCryptoWithSetup:
-				services = append(services, AutoWithdrawCryptoWithSetupText)
-			case WithdrawCryptoWith2FA:
-				services = append(services, WithdrawCryptoWith2FAText)
-			case WithdrawCryptoWithSMS:
-				services = append(services, WithdrawCryptoWithSMSText)
-			case WithdrawCryptoWithEmail:
-				services = append(services, WithdrawCryptoWithEmailText)
-			case WithdrawCryptoWithWebsiteApproval:
-				services = append(services, WithdrawCryptoWithWebsiteApprovalText)
-			case WithdrawCryptoWithAPIPermission:
-				services = append(services, WithdrawCryptoWithAPIPermissionText)
-			case AutoWithdrawFiat:
-				services = append(services, AutoWithdrawFiatText)
-			case AutoWithdrawFiatWithAPIPermission:
-				services = append(services, AutoWithdrawFiatWithAPIPermissionText)
-			case AutoWithdrawFiatWithSetup:
-				services = append(services, AutoWithdrawFiatWithSetupText)
-			case WithdrawFiatWith2FA:
-				services = append(services, WithdrawFiatWith2FAText)
-			case WithdrawFiatWithSMS:
-				services = append(services, WithdrawFiatWithSMSText)
-			case WithdrawFiatWithEmail:
-				services = append(services, WithdrawFiatWithEmailText)
-			case WithdrawFiatWithWebsiteApproval:
-				services = append(services, WithdrawFiatWithWebsiteApprovalText)
-			case WithdrawFiatWithAPIPermission:
-				services = append(services, WithdrawFiatWithAPIPermissionText)
-			case WithdrawCryptoViaWebsiteOnly:
-				services = append(services, WithdrawCryptoViaWebsiteOnlyText)
-			case WithdrawFiatViaWebsiteOnly:
-				services = append(services, WithdrawFiatViaWebsiteOnlyText)
-			default:
-				services = append(services, fmt.Sprintf("%s[%v]", UnknownWithdrawalTypeText, check))
+			method, ok := withdrawalMethods[check]
+			if !ok {
+				method = WithdrawalMethod{
+					Asset:       UnknownWithdrawalType,
+					Automation:  UnknownWithdrawalType,
+					Description: fmt.Sprintf("%s[%v]", UnknownWithdrawalTypeText, check),
+				}
 			}
+			method.Permission = check
+			methods = append(methods, method)
 		}
 	}
+	return methods
+}
+
+// FormatWithdrawPermissions will return each of the exchange's compatible withdrawal methods in readable form
+func (e *Base) FormatWithdrawPermissions() string {
+	services := []string{}
+	for _, method := range e.GetWithdrawalMethods() {
+		services = append(services, method.Description)
+	}
 	if len(services) > 0 {
 		return strings.Join(services, " & ")
 	}
@@ -0,0 +1,105 @@
+package exchange
+
+import (
+	"log"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// DefaultAccountFeesMaxAge is how long the trading fee rates of an account are
+// used before being retrieved from the exchange again, as volume tiers only
+// change as the trailing volume of the account does
+const DefaultAccountFeesMaxAge = time.Hour
+
+// AccountFeeRate holds maker and taker trading fee rates as fractions of the
+// trade value
+type AccountFeeRate struct {
+	Maker float64 `json:"maker"`
+	Taker float64 `json:"taker"`
+}
+
+// AccountFees holds the trading fee rates charged to the account, retrieved
+// from the account endpoints of an exchange. Pairs holds the rates of pairs
+// charged differently to the account rate, keyed by the uppercase pair symbol
+// without a delimiter such as BTCUSD
+type AccountFees struct {
+	AccountFeeRate
+	Pairs   map[string]AccountFeeRate `json:"pairs,omitempty"`
+	Updated time.Time                 `json:"updated"`
+}
+
+// AccountFeesFunc retrieves the trading fee rates of the account from the
+// exchange
+type AccountFeesFunc func() (AccountFees, error)
+
+// Rate returns the maker or taker fee rate of a pair, the account rate when
+// the pair has no rate of its own
+func (a *AccountFees) Rate(pairSymbol string, isMaker bool) float64 {
+	rate, ok := a.Pairs[common.StringToUpper(pairSymbol)]
+	if !ok {
+		rate = a.AccountFeeRate
+	}
+
+	if isMaker {
+		return rate.Maker
+	}
+	return rate.Taker
+}
+
+// SetAccountFees sets the trading fee rates of the account, stamping them with
+// the current time when not already
+func (e *Base) SetAccountFees(fees AccountFees) {
+	if fees.Updated.IsZero() {
+		fees.Updated = time.Now()
+	}
+
+	e.accountFeesMtx.Lock()
+	e.accountFees = &fees
+	e.accountFeesMtx.Unlock()
+}
+
+// GetAccountFees returns the trading fee rates of the account last retrieved
+// and whether they were retrieved within DefaultAccountFeesMaxAge
+func (e *Base) GetAccountFees() (AccountFees, bool) {
+	e.accountFeesMtx.Lock()
+	defer e.accountFeesMtx.Unlock()
+
+	if e.accountFees == nil {
+		return AccountFees{}, false
+	}
+	return *e.accountFees, time.Since(e.accountFees.Updated) < DefaultAccountFeesMaxAge
+}
+
+// AccountTradingFee returns the trading fee of a fee builder at the rates
+// charged to the account, retrieving them with fetch when they are missing or
+// older than DefaultAccountFeesMaxAge. When the rates can't be retrieved the
+// last retrieved rates are used, or the static fee table when none have been
+func (e *Base) AccountTradingFee(feeBuilder FeeBuilder, fetch AccountFeesFunc) (float64, error) {
+	fees, current := e.GetAccountFees()
+	if !current {
+		fetched, err := fetch()
+		switch {
+		case err == nil:
+			e.SetAccountFees(fetched)
+			fees, _ = e.GetAccountFees()
+		case fees.Updated.IsZero():
+			if e.feeTable == nil {
+				return 0, err
+			}
+			log.Printf("%s unable to retrieve account trading fees, using default fees. Error: %s",
+				e.Name, err)
+			return e.EstimateFee(feeBuilder)
+		default:
+			log.Printf("%s unable to refresh account trading fees, using fees retrieved %s. Error: %s",
+				e.Name, fees.Updated.Format(time.RFC3339), err)
+		}
+	}
+
+	rate := fees.Rate(feeBuilder.FirstCurrency+feeBuilder.SecondCurrency, feeBuilder.IsMaker)
+	fee := rate * feeBuilder.PurchasePrice * feeBuilder.Amount
+	if fee < 0 {
+		fee = 0
+	}
+	return fee, nil
+}
@@ -0,0 +1,60 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAccountTradingFee(t *testing.T) {
+	var b Base
+	b.Name = "TESTNAME"
+	feeBuilder := FeeBuilder{FeeType: CryptocurrencyTradeFee, FirstCurrency: "BTC",
+		SecondCurrency: "USD", PurchasePrice: 1000, Amount: 2}
+
+	failing := func() (AccountFees, error) { return AccountFees{}, errors.New("unavailable") }
+	if _, err := b.AccountTradingFee(feeBuilder, failing); err == nil {
+		t.Error("Test Failed - AccountTradingFee() expected error without fees or fee table")
+	}
+
+	b.SetFeeTable(FeeTable{Maker: 0.001, Taker: 0.002})
+	fee, err := b.AccountTradingFee(feeBuilder, failing)
+	if err != nil || fee != 4 {
+		t.Errorf("Test Failed - AccountTradingFee() expected fee table fee 4, got %v %v", fee, err)
+	}
+
+	fetches := 0
+	fetch := func() (AccountFees, error) {
+		fetches++
+		return AccountFees{
+			AccountFeeRate: AccountFeeRate{Maker: 0.0005, Taker: 0.001},
+			Pairs:          map[string]AccountFeeRate{"ETHBTC": {Maker: 0, Taker: 0.0001}},
+		}, nil
+	}
+	fee, err = b.AccountTradingFee(feeBuilder, fetch)
+	if err != nil || fee != 2 {
+		t.Errorf("Test Failed - AccountTradingFee() expected account fee 2, got %v %v", fee, err)
+	}
+
+	feeBuilder.FirstCurrency, feeBuilder.SecondCurrency = "eth", "btc"
+	feeBuilder.IsMaker = true
+	fee, err = b.AccountTradingFee(feeBuilder, fetch)
+	if err != nil || fee != 0 || fetches != 1 {
+		t.Errorf("Test Failed - AccountTradingFee() expected cached pair fee 0, got %v %v after %d fetches",
+			fee, err, fetches)
+	}
+
+	// Stale fees are used when they can't be refreshed
+	fees, _ := b.GetAccountFees()
+	fees.Updated = time.Now().Add(-DefaultAccountFeesMaxAge * 2)
+	b.SetAccountFees(fees)
+	if _, current := b.GetAccountFees(); current {
+		t.Error("Test Failed - GetAccountFees() expected stale fees")
+	}
+
+	feeBuilder.FirstCurrency, feeBuilder.SecondCurrency = "BTC", "USD"
+	fee, err = b.AccountTradingFee(feeBuilder, failing)
+	if err != nil || fee != 1 {
+		t.Errorf("Test Failed - AccountTradingFee() expected stale account fee 1, got %v %v", fee, err)
+	}
+}
@@ -0,0 +1,247 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// Asset metadata errors
+var (
+	// ErrAssetMetadataNotSupported is returned when updating the asset
+	// metadata of an exchange which has no API to fetch it from
+	ErrAssetMetadataNotSupported = errors.New("exchange does not support fetching asset metadata")
+	// ErrAssetMetadataNotFound is returned when an exchange has no metadata for
+	// a currency or chain
+	ErrAssetMetadataNotFound = errors.New("no asset metadata found")
+	// ErrWithdrawalDisabled is returned when withdrawals of a currency on a
+	// chain are suspended by the exchange
+	ErrWithdrawalDisabled = errors.New("withdrawals are disabled for this currency and chain")
+)
+
+// AssetMetadata holds the deposit and withdrawal details of a currency on a
+// chain of an exchange. Chain names the network, such as ERC20 or TRC20, and
+// Default marks the chain used when none is requested. ProcessingTime is the
+// typical time from requesting a withdrawal to it being confirmed on the
+// chain. A zero MinWithdrawal, DepositConfirmations or ProcessingTime is
+// unknown
+type AssetMetadata struct {
+	Currency             string        `json:"currency"`
+	Chain                string        `json:"chain"`
+	Default              bool          `json:"default,omitempty"`
+	MinWithdrawal        float64       `json:"minWithdrawal"`
+	WithdrawalFee        float64       `json:"withdrawalFee"`
+	DepositConfirmations int64         `json:"depositConfirmations"`
+	ProcessingTime       time.Duration `json:"processingTime,omitempty"`
+	WithdrawalDisabled   bool          `json:"withdrawalDisabled,omitempty"`
+	DepositDisabled      bool          `json:"depositDisabled,omitempty"`
+}
+
+// WithdrawalMinimumError is returned when withdrawing less than the minimum
+// amount of a currency on a chain
+type WithdrawalMinimumError struct {
+	Currency string
+	Chain    string
+	Amount   float64
+	Minimum  float64
+}
+
+func (w *WithdrawalMinimumError) Error() string {
+	return fmt.Sprintf("withdrawal of %v %s on %s is below the minimum of %v",
+		w.Amount, w.Currency, w.Chain, w.Minimum)
+}
+
+// AssetMetadataFetcher fetches the asset metadata of every currency from an
+// exchange's API
+type AssetMetadataFetcher func() ([]AssetMetadata, error)
+
+// SetAssetMetadata sets the default asset metadata of the exchange, replacing
+// any previously set or fetched
+func (e *Base) SetAssetMetadata(metadata []AssetMetadata) {
+	e.assetMetadataMtx.Lock()
+	e.assetMetadata = normaliseAssetMetadata(metadata)
+	e.assetMetadataMtx.Unlock()
+}
+
+// SetAssetMetadataFetcher sets the func fetching the asset metadata of the
+// exchange from its API
+func (e *Base) SetAssetMetadataFetcher(fetcher AssetMetadataFetcher) {
+	e.assetMetadataMtx.Lock()
+	e.assetMetadataFetcher = fetcher
+	e.assetMetadataMtx.Unlock()
+}
+
+// SetAssetMetadataOverrides sets the configured asset metadata, applied over
+// the default and fetched metadata. The set fields of an override replace
+// those of the chain of the currency, or add the chain when it isn't known
+func (e *Base) SetAssetMetadataOverrides(overrides []config.AssetMetadataConfig) {
+	metadata := make([]AssetMetadata, len(overrides))
+	for x := range overrides {
+		metadata[x] = AssetMetadata{
+			Currency:             overrides[x].Currency,
+			Chain:                overrides[x].Chain,
+			Default:              overrides[x].Default,
+			MinWithdrawal:        overrides[x].MinWithdrawal,
+			WithdrawalFee:        overrides[x].WithdrawalFee,
+			DepositConfirmations: overrides[x].DepositConfirmations,
+			ProcessingTime:       overrides[x].ProcessingTime,
+			WithdrawalDisabled:   overrides[x].WithdrawalDisabled,
+		}
+	}
+
+	e.assetMetadataMtx.Lock()
+	e.assetMetadataOverrides = normaliseAssetMetadata(metadata)
+	e.assetMetadataMtx.Unlock()
+}
+
+// UpdateAssetMetadata fetches the asset metadata of the exchange from its API,
+// replacing the default metadata
+func (e *Base) UpdateAssetMetadata() error {
+	e.assetMetadataMtx.Lock()
+	fetcher := e.assetMetadataFetcher
+	e.assetMetadataMtx.Unlock()
+	if fetcher == nil {
+		return ErrAssetMetadataNotSupported
+	}
+
+	metadata, err := fetcher()
+	if err != nil {
+		return err
+	}
+	e.SetAssetMetadata(metadata)
+	return nil
+}
+
+// GetAssetMetadata returns the metadata of each chain of a currency with the
+// configured overrides applied, or of every currency when currency is blank
+func (e *Base) GetAssetMetadata(currency string) []AssetMetadata {
+	currency = common.StringToUpper(currency)
+	e.assetMetadataMtx.Lock()
+	defer e.assetMetadataMtx.Unlock()
+
+	result := []AssetMetadata{}
+	for x := range e.assetMetadata {
+		if currency == "" || e.assetMetadata[x].Currency == currency {
+			result = append(result, e.assetMetadata[x])
+		}
+	}
+
+	for x := range e.assetMetadataOverrides {
+		override := e.assetMetadataOverrides[x]
+		if currency != "" && override.Currency != currency {
+			continue
+		}
+
+		found := false
+		for y := range result {
+			if result[y].Currency != override.Currency {
+				continue
+			}
+			if result[y].Chain != override.Chain {
+				// A configured default chain replaces the exchange's default
+				if override.Default {
+					result[y].Default = false
+				}
+				continue
+			}
+			applyAssetMetadataOverride(&result[y], override)
+			found = true
+		}
+		if !found {
+			result = append(result, override)
+		}
+	}
+	return result
+}
+
+// GetChainMetadata returns the metadata of a currency on a chain, or on its
+// default chain when chain is blank. A currency without a chain marked as
+// default defaults to its first chain
+func (e *Base) GetChainMetadata(currency, chain string) (AssetMetadata, error) {
+	metadata := e.GetAssetMetadata(currency)
+	if len(metadata) == 0 {
+		return AssetMetadata{}, ErrAssetMetadataNotFound
+	}
+
+	chain = common.StringToUpper(chain)
+	if chain == "" {
+		for x := range metadata {
+			if metadata[x].Default {
+				return metadata[x], nil
+			}
+		}
+		return metadata[0], nil
+	}
+
+	for x := range metadata {
+		if metadata[x].Chain == chain {
+			return metadata[x], nil
+		}
+	}
+	return AssetMetadata{}, ErrAssetMetadataNotFound
+}
+
+// CheckWithdrawal validates a withdrawal of an amount of a currency on a
+// chain, or its default chain when chain is blank, against the asset metadata
+// of the exchange. Withdrawals of currencies without metadata are allowed
+func (e *Base) CheckWithdrawal(currency, chain string, amount float64) error {
+	metadata, err := e.GetChainMetadata(currency, chain)
+	if err != nil {
+		if chain != "" && len(e.GetAssetMetadata(currency)) > 0 {
+			return err
+		}
+		return nil
+	}
+
+	if metadata.WithdrawalDisabled {
+		return ErrWithdrawalDisabled
+	}
+
+	if metadata.MinWithdrawal > 0 && amount < metadata.MinWithdrawal {
+		return &WithdrawalMinimumError{
+			Currency: metadata.Currency,
+			Chain:    metadata.Chain,
+			Amount:   amount,
+			Minimum:  metadata.MinWithdrawal,
+		}
+	}
+	return nil
+}
+
+// applyAssetMetadataOverride replaces the fields of the metadata which are
+// set on the override
+func applyAssetMetadataOverride(metadata *AssetMetadata, override AssetMetadata) {
+	if override.Default {
+		metadata.Default = true
+	}
+	if override.MinWithdrawal > 0 {
+		metadata.MinWithdrawal = override.MinWithdrawal
+	}
+	if override.WithdrawalFee > 0 {
+		metadata.WithdrawalFee = override.WithdrawalFee
+	}
+	if override.DepositConfirmations > 0 {
+		metadata.DepositConfirmations = override.DepositConfirmations
+	}
+	if override.ProcessingTime > 0 {
+		metadata.ProcessingTime = override.ProcessingTime
+	}
+	if override.WithdrawalDisabled {
+		metadata.WithdrawalDisabled = true
+	}
+}
+
+// normaliseAssetMetadata returns a copy of the metadata with upper case
+// currencies and chains
+func normaliseAssetMetadata(metadata []AssetMetadata) []AssetMetadata {
+	result := make([]AssetMetadata, len(metadata))
+	for x := range metadata {
+		result[x] = metadata[x]
+		result[x].Currency = common.StringToUpper(metadata[x].Currency)
+		result[x].Chain = common.StringToUpper(metadata[x].Chain)
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+func TestAssetMetadata(t *testing.T) {
+	var b Base
+	if err := b.CheckWithdrawal("USDT", "", 1); err != nil {
+		t.Error("Test Failed - CheckWithdrawal() refused a currency without metadata", err)
+	}
+	if err := b.UpdateAssetMetadata(); err != ErrAssetMetadataNotSupported {
+		t.Error("Test Failed - UpdateAssetMetadata() expected not supported error", err)
+	}
+
+	b.SetAssetMetadata([]AssetMetadata{
+		{Currency: "usdt", Chain: "erc20", Default: true, MinWithdrawal: 20, WithdrawalFee: 5, DepositConfirmations: 12},
+		{Currency: "USDT", Chain: "TRC20", MinWithdrawal: 10, WithdrawalFee: 1, DepositConfirmations: 20},
+		{Currency: "BTC", Chain: "BTC", MinWithdrawal: 0.001},
+	})
+
+	metadata, err := b.GetChainMetadata("usdt", "")
+	if err != nil || metadata.Chain != "ERC20" || metadata.DepositConfirmations != 12 {
+		t.Errorf("Test Failed - GetChainMetadata() unexpected default chain %+v %v", metadata, err)
+	}
+	if _, err = b.GetChainMetadata("USDT", "SOL"); err != ErrAssetMetadataNotFound {
+		t.Error("Test Failed - GetChainMetadata() expected chain not found error", err)
+	}
+	if minimum, ok := b.GetWithdrawalMinimum("BTC"); !ok || minimum != 0.001 {
+		t.Error("Test Failed - GetWithdrawalMinimum() unexpected minimum", minimum, ok)
+	}
+
+	err = b.CheckWithdrawal("USDT", "", 15)
+	if minErr, ok := err.(*WithdrawalMinimumError); !ok || minErr.Minimum != 20 {
+		t.Error("Test Failed - CheckWithdrawal() expected minimum error", err)
+	}
+	if err = b.CheckWithdrawal("USDT", "trc20", 15); err != nil {
+		t.Error("Test Failed - CheckWithdrawal() refused a withdrawal above the chain minimum", err)
+	}
+	if err = b.CheckWithdrawal("USDT", "SOL", 15); err != ErrAssetMetadataNotFound {
+		t.Error("Test Failed - CheckWithdrawal() expected unknown chain error", err)
+	}
+
+	b.SetAssetMetadataOverrides([]config.AssetMetadataConfig{
+		{Currency: "USDT", Chain: "TRC20", Default: true, WithdrawalDisabled: true,
+			ProcessingTime: time.Minute * 5},
+		{Currency: "USDT", Chain: "BEP20", MinWithdrawal: 5},
+	})
+	if chains := b.GetAssetMetadata("USDT"); len(chains) != 3 {
+		t.Fatalf("Test Failed - GetAssetMetadata() expected 3 chains, got %+v", chains)
+	}
+	metadata, err = b.GetChainMetadata("USDT", "")
+	if err != nil || metadata.Chain != "TRC20" || metadata.MinWithdrawal != 10 ||
+		metadata.ProcessingTime != time.Minute*5 {
+		t.Errorf("Test Failed - GetChainMetadata() unexpected overridden default chain %+v %v", metadata, err)
+	}
+	if err = b.CheckWithdrawal("USDT", "", 15); err != ErrWithdrawalDisabled {
+		t.Error("Test Failed - CheckWithdrawal() expected withdrawal disabled error", err)
+	}
+	if len(b.GetAssetMetadata("")) != 4 {
+		t.Error("Test Failed - GetAssetMetadata() expected the metadata of every currency")
+	}
+
+	b.SetAssetMetadataFetcher(func() ([]AssetMetadata, error) {
+		return []AssetMetadata{{Currency: "USDT", Chain: "ERC20", MinWithdrawal: 50}}, nil
+	})
+	if err = b.UpdateAssetMetadata(); err != nil {
+		t.Fatal("Test Failed - UpdateAssetMetadata() error", err)
+	}
+	metadata, err = b.GetChainMetadata("USDT", "ERC20")
+	if err != nil || metadata.MinWithdrawal != 50 {
+		t.Errorf("Test Failed - UpdateAssetMetadata() unexpected fetched metadata %+v %v", metadata, err)
+	}
+}
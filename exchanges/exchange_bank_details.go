@@ -0,0 +1,199 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// Fiat withdrawal transfer types, SEPA transfers move euros between banks in
+// the Single Euro Payments Area by IBAN and SWIFT transfers move any currency
+// internationally by BIC
+const (
+	FiatWithdrawalSEPA  = "SEPA"
+	FiatWithdrawalSWIFT = "SWIFT"
+)
+
+var (
+	// ErrInvalidIBAN is returned when an IBAN is malformed or fails its
+	// check digits
+	ErrInvalidIBAN = errors.New("invalid IBAN")
+	// ErrInvalidBIC is returned when a BIC is malformed
+	ErrInvalidBIC = errors.New("invalid BIC")
+	// ErrFiatWithdrawalNotSupported is returned by exchanges which don't
+	// support a fiat withdrawal type for a currency
+	ErrFiatWithdrawalNotSupported = errors.New("exchange does not support the fiat withdrawal")
+)
+
+// BankDetails holds the beneficiary and bank details of a fiat withdrawal to
+// a bank account
+type BankDetails struct {
+	BeneficiaryName       string `json:"beneficiaryName"`
+	BeneficiaryAddress    string `json:"beneficiaryAddress"`
+	BeneficiaryPostalCode string `json:"beneficiaryPostalCode"`
+	BeneficiaryCity       string `json:"beneficiaryCity"`
+	BeneficiaryCountry    string `json:"beneficiaryCountry"`
+	IBAN                  string `json:"iban"`
+	BIC                   string `json:"bic"`
+	AccountNumber         string `json:"accountNumber"`
+	BankName              string `json:"bankName"`
+	BankAddress           string `json:"bankAddress"`
+	BankPostalCode        string `json:"bankPostalCode"`
+	BankCity              string `json:"bankCity"`
+	BankCountry           string `json:"bankCountry"`
+	Reference             string `json:"reference"`
+}
+
+// BankDetailsFromAccount returns the bank details of a configured bank
+// account
+func BankDetailsFromAccount(account config.BankAccount) BankDetails {
+	return BankDetails{
+		BeneficiaryName: account.AccountName,
+		IBAN:            account.IBAN,
+		BIC:             account.SWIFTCode,
+		AccountNumber:   account.AccountNumber,
+		BankName:        account.BankName,
+		BankAddress:     account.BankAddress,
+	}
+}
+
+// Validate checks the bank details hold what a transfer type needs, SEPA
+// transfers need an IBAN and SWIFT transfers need a BIC, bank name and either
+// an IBAN or account number
+func (b *BankDetails) Validate(transferType string) error {
+	if b.BeneficiaryName == "" {
+		return errors.New("beneficiary name must be supplied")
+	}
+
+	if b.IBAN != "" {
+		if err := ValidateIBAN(b.IBAN); err != nil {
+			return err
+		}
+	}
+
+	if b.BIC != "" {
+		if err := ValidateBIC(b.BIC); err != nil {
+			return err
+		}
+	}
+
+	switch transferType {
+	case FiatWithdrawalSEPA:
+		if b.IBAN == "" {
+			return errors.New("SEPA withdrawals require an IBAN")
+		}
+	case FiatWithdrawalSWIFT:
+		if b.BIC == "" || b.BankName == "" {
+			return errors.New("SWIFT withdrawals require a BIC and bank name")
+		}
+		if b.IBAN == "" && b.AccountNumber == "" {
+			return errors.New("SWIFT withdrawals require an IBAN or account number")
+		}
+	default:
+		return fmt.Errorf("unsupported fiat withdrawal type %s", transferType)
+	}
+	return nil
+}
+
+// ValidateIBAN checks an IBAN is a country code, two check digits and up to
+// 30 alphanumeric characters which pass the ISO 7064 mod 97 check, ignoring
+// spaces and case
+func ValidateIBAN(iban string) error {
+	iban = common.StringToUpper(common.ReplaceString(iban, " ", "", -1))
+	if len(iban) < 15 || len(iban) > 34 ||
+		!isUpperAlpha(iban[:2]) || !isNumeric(iban[2:4]) || !isAlphanumeric(iban[4:]) {
+		return ErrInvalidIBAN
+	}
+
+	// Move the country code and check digits to the end and replace letters
+	// with two digit numbers, A being 10, the result mod 97 must be 1
+	remainder := 0
+	for _, c := range iban[4:] + iban[:4] {
+		if c >= 'A' {
+			remainder = (remainder*100 + int(c-'A'+10)) % 97
+			continue
+		}
+		remainder = (remainder*10 + int(c-'0')) % 97
+	}
+
+	if remainder != 1 {
+		return ErrInvalidIBAN
+	}
+	return nil
+}
+
+// ValidateBIC checks a BIC is a four letter bank code, two letter country
+// code, two character location code and optional three character branch code
+func ValidateBIC(bic string) error {
+	bic = common.StringToUpper(bic)
+	if len(bic) != 8 && len(bic) != 11 {
+		return ErrInvalidBIC
+	}
+
+	if !isUpperAlpha(bic[:6]) || !isAlphanumeric(bic[6:]) {
+		return ErrInvalidBIC
+	}
+	return nil
+}
+
+// FiatWithdrawalSupport holds the currencies an exchange withdraws to bank
+// accounts by a transfer type
+type FiatWithdrawalSupport struct {
+	Type       string   `json:"type"`
+	Currencies []string `json:"currencies"`
+}
+
+// SetFiatWithdrawalSupport sets the fiat withdrawal types and currencies the
+// exchange supports
+func (e *Base) SetFiatWithdrawalSupport(support ...FiatWithdrawalSupport) {
+	e.fiatWithdrawalSupport = support
+}
+
+// GetFiatWithdrawalSupport returns the fiat withdrawal types and currencies
+// the exchange supports
+func (e *Base) GetFiatWithdrawalSupport() []FiatWithdrawalSupport {
+	return e.fiatWithdrawalSupport
+}
+
+// SupportsFiatWithdrawal returns whether the exchange supports withdrawing a
+// currency to bank accounts by a transfer type
+func (e *Base) SupportsFiatWithdrawal(transferType, currency string) bool {
+	for x := range e.fiatWithdrawalSupport {
+		if e.fiatWithdrawalSupport[x].Type != transferType {
+			continue
+		}
+		if common.StringDataCompareUpper(e.fiatWithdrawalSupport[x].Currencies, currency) {
+			return true
+		}
+	}
+	return false
+}
+
+func isUpperAlpha(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumeric(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9') && !(c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package exchange
+
+import (
+	"testing"
+)
+
+func TestValidateIBAN(t *testing.T) {
+	for _, iban := range []string{"DE89370400440532013000", "gb29 nwbk 6016 1331 9268 19"} {
+		if err := ValidateIBAN(iban); err != nil {
+			t.Errorf("Test Failed - ValidateIBAN() %s error %s", iban, err)
+		}
+	}
+
+	for _, iban := range []string{"", "DE88370400440532013000", "DE8937040044053201300!", "1289370400440532013000"} {
+		if err := ValidateIBAN(iban); err != ErrInvalidIBAN {
+			t.Errorf("Test Failed - ValidateIBAN() %s expected invalid IBAN", iban)
+		}
+	}
+}
+
+func TestValidateBIC(t *testing.T) {
+	for _, bic := range []string{"COBADEFF", "cobadeffxxx"} {
+		if err := ValidateBIC(bic); err != nil {
+			t.Errorf("Test Failed - ValidateBIC() %s error %s", bic, err)
+		}
+	}
+
+	for _, bic := range []string{"", "COBADEF", "COBADEFFXX", "C0BADEFF"} {
+		if err := ValidateBIC(bic); err != ErrInvalidBIC {
+			t.Errorf("Test Failed - ValidateBIC() %s expected invalid BIC", bic)
+		}
+	}
+}
+
+func TestBankDetailsValidate(t *testing.T) {
+	details := BankDetails{BeneficiaryName: "Satoshi Nakamoto", IBAN: "DE89370400440532013000"}
+	if err := details.Validate(FiatWithdrawalSEPA); err != nil {
+		t.Error("Test Failed - Validate() SEPA error", err)
+	}
+	if err := details.Validate(FiatWithdrawalSWIFT); err == nil {
+		t.Error("Test Failed - Validate() expected SWIFT error without a BIC")
+	}
+
+	details.IBAN, details.AccountNumber = "", "123456789"
+	details.BIC, details.BankName = "CHASUS33", "JPMorgan Chase"
+	if err := details.Validate(FiatWithdrawalSWIFT); err != nil {
+		t.Error("Test Failed - Validate() SWIFT error", err)
+	}
+	if err := details.Validate(FiatWithdrawalSEPA); err == nil {
+		t.Error("Test Failed - Validate() expected SEPA error without an IBAN")
+	}
+	if err := details.Validate("CHEQUE"); err == nil {
+		t.Error("Test Failed - Validate() expected unsupported type error")
+	}
+}
+
+func TestSupportsFiatWithdrawal(t *testing.T) {
+	var b Base
+	if b.SupportsFiatWithdrawal(FiatWithdrawalSEPA, "EUR") {
+		t.Error("Test Failed - SupportsFiatWithdrawal() expected no support by default")
+	}
+
+	b.SetFiatWithdrawalSupport(FiatWithdrawalSupport{Type: FiatWithdrawalSEPA, Currencies: []string{"EUR"}})
+	if !b.SupportsFiatWithdrawal(FiatWithdrawalSEPA, "eur") {
+		t.Error("Test Failed - SupportsFiatWithdrawal() expected SEPA EUR support")
+	}
+	if b.SupportsFiatWithdrawal(FiatWithdrawalSWIFT, "EUR") || len(b.GetFiatWithdrawalSupport()) != 1 {
+		t.Error("Test Failed - SupportsFiatWithdrawal() unexpected SWIFT EUR support")
+	}
+}
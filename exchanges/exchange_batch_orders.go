@@ -0,0 +1,70 @@
+package exchange
+
+import (
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// BatchOrder holds the details of an order submitted as part of a batch
+type BatchOrder struct {
+	Pair      pair.CurrencyPair
+	Side      OrderSide
+	OrderType OrderType
+	Amount    float64
+	Price     float64
+	ClientID  string
+}
+
+// BatchOrderResult holds the result of an order submitted as part of a batch,
+// results are returned in the same order as the submitted orders
+type BatchOrderResult struct {
+	ClientID string
+	OrderID  int64
+	Err      error
+}
+
+// BatchCancelResult holds the result of an order cancelled as part of a batch,
+// results are returned in the same order as the order IDs
+type BatchCancelResult struct {
+	OrderID int64
+	Err     error
+}
+
+// OrderSubmitter submits a single order, matching SubmitExchangeOrder
+type OrderSubmitter func(p pair.CurrencyPair, side OrderSide, orderType OrderType, amount, price float64, clientID string) (int64, error)
+
+// SubmitOrdersIndividually submits a batch of orders one request at a time,
+// for exchanges which don't support batch order submission
+func SubmitOrdersIndividually(orders []BatchOrder, submit OrderSubmitter) []BatchOrderResult {
+	results := make([]BatchOrderResult, len(orders))
+	for x := range orders {
+		results[x].ClientID = orders[x].ClientID
+		results[x].OrderID, results[x].Err = submit(orders[x].Pair,
+			orders[x].Side,
+			orders[x].OrderType,
+			orders[x].Amount,
+			orders[x].Price,
+			orders[x].ClientID)
+	}
+	return results
+}
+
+// CancelOrdersIndividually cancels a batch of orders one request at a time,
+// for exchanges which don't support batch order cancellation
+func CancelOrdersIndividually(orderIDs []int64, cancel func(orderID int64) error) []BatchCancelResult {
+	results := make([]BatchCancelResult, len(orderIDs))
+	for x := range orderIDs {
+		results[x].OrderID = orderIDs[x]
+		results[x].Err = cancel(orderIDs[x])
+	}
+	return results
+}
+
+// NewBatchCancelResults returns cancellation results for the order IDs all
+// set to the same error, for exchanges which cancel a batch atomically
+func NewBatchCancelResults(orderIDs []int64, err error) []BatchCancelResult {
+	results := make([]BatchCancelResult, len(orderIDs))
+	for x := range orderIDs {
+		results[x] = BatchCancelResult{OrderID: orderIDs[x], Err: err}
+	}
+	return results
+}
@@ -0,0 +1,53 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestSubmitOrdersIndividually(t *testing.T) {
+	batch := []BatchOrder{
+		{Pair: pair.NewCurrencyPair("BTC", "USD"), Side: OrderSideBuy(), Amount: 1, Price: 100, ClientID: "a"},
+		{Pair: pair.NewCurrencyPair("BTC", "USD"), Side: OrderSideSell(), Amount: 0, Price: 100, ClientID: "b"},
+	}
+
+	results := SubmitOrdersIndividually(batch,
+		func(p pair.CurrencyPair, side OrderSide, orderType OrderType, amount, price float64, clientID string) (int64, error) {
+			if amount == 0 {
+				return 0, errors.New("invalid amount")
+			}
+			return 1337, nil
+		})
+
+	if len(results) != 2 {
+		t.Fatalf("Test Failed - SubmitOrdersIndividually() expected 2 results received %d", len(results))
+	}
+
+	if results[0].ClientID != "a" || results[0].OrderID != 1337 || results[0].Err != nil {
+		t.Errorf("Test Failed - SubmitOrdersIndividually() unexpected result %+v", results[0])
+	}
+
+	if results[1].ClientID != "b" || results[1].Err == nil {
+		t.Errorf("Test Failed - SubmitOrdersIndividually() unexpected result %+v", results[1])
+	}
+}
+
+func TestCancelOrdersIndividually(t *testing.T) {
+	results := CancelOrdersIndividually([]int64{1, 2}, func(orderID int64) error {
+		if orderID == 2 {
+			return errors.New("order not found")
+		}
+		return nil
+	})
+
+	if len(results) != 2 || results[0].Err != nil || results[1].Err == nil || results[1].OrderID != 2 {
+		t.Errorf("Test Failed - CancelOrdersIndividually() unexpected results %+v", results)
+	}
+
+	results = NewBatchCancelResults([]int64{3, 4}, errors.New("rejected"))
+	if len(results) != 2 || results[0].Err == nil || results[1].OrderID != 4 {
+		t.Errorf("Test Failed - NewBatchCancelResults() unexpected results %+v", results)
+	}
+}
@@ -0,0 +1,24 @@
+package exchange
+
+import (
+	"errors"
+)
+
+// ErrBrokerIDNotSupported is returned when setting a broker ID on an exchange
+// whose order API doesn't accept broker or affiliate tags
+var ErrBrokerIDNotSupported = errors.New("exchange does not support broker IDs on orders")
+
+// SetBrokerID sets the broker, referral or affiliate code the exchange tags
+// submitted orders with to earn fee rebates, a blank ID disables tagging
+func (e *Base) SetBrokerID(brokerID string) error {
+	if brokerID != "" && !e.SupportsBrokerID {
+		return ErrBrokerIDNotSupported
+	}
+	e.brokerID = brokerID
+	return nil
+}
+
+// GetBrokerID returns the broker ID the exchange tags submitted orders with
+func (e *Base) GetBrokerID() string {
+	return e.brokerID
+}
@@ -0,0 +1,91 @@
+package exchange
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+)
+
+// ErrWebsocketChaosDisconnect is returned when chaos mode drops a websocket
+// connection
+var ErrWebsocketChaosDisconnect = errors.New("chaos: injected websocket disconnect")
+
+// WebsocketChaos holds the probabilities, from 0 to 1, of the artificial
+// failures injected into websocket reads for resilience testing
+type WebsocketChaos struct {
+	DisconnectRate   float64
+	CorruptFrameRate float64
+}
+
+// SetChaos sets the failures injected into the exchange's REST requests and
+// websocket reads from its config, a disabled config turns chaos mode off
+func (e *Base) SetChaos(cfg config.ChaosConfig) {
+	if !cfg.Enabled {
+		cfg = config.ChaosConfig{}
+	} else {
+		log.Printf("WARNING -- %s chaos mode enabled, injecting artificial failures.\n",
+			e.Name)
+	}
+
+	if e.Requester != nil {
+		e.Requester.SetChaos(request.Chaos{
+			TimeoutRate:     cfg.TimeoutRate,
+			ServerErrorRate: cfg.ServerErrorRate,
+		})
+	}
+
+	if e.Websocket != nil {
+		e.Websocket.SetChaos(WebsocketChaos{
+			DisconnectRate:   cfg.DisconnectRate,
+			CorruptFrameRate: cfg.CorruptFrameRate,
+		})
+	}
+}
+
+// SetChaos sets the failures injected into websocket reads
+func (w *Websocket) SetChaos(c WebsocketChaos) {
+	w.chaosMtx.Lock()
+	w.chaos = c
+	w.chaosMtx.Unlock()
+}
+
+// GetChaos returns the failures injected into websocket reads
+func (w *Websocket) GetChaos() WebsocketChaos {
+	w.chaosMtx.Lock()
+	defer w.chaosMtx.Unlock()
+	return w.chaos
+}
+
+// ReadMessage reads the next message from a websocket connection, dropping the
+// connection or corrupting the message at the chaos mode probabilities so the
+// exchange's read routine handles them as it would real failures
+func (w *Websocket) ReadMessage(conn *websocket.Conn) (int, []byte, error) {
+	c := w.GetChaos()
+	if c.DisconnectRate > 0 && rand.Float64() < c.DisconnectRate {
+		conn.Close()
+		return 0, nil, ErrWebsocketChaosDisconnect
+	}
+
+	msgType, data, err := conn.ReadMessage()
+	if err != nil || len(data) == 0 {
+		return msgType, data, err
+	}
+
+	if c.CorruptFrameRate > 0 && rand.Float64() < c.CorruptFrameRate {
+		data = corruptFrame(data)
+	}
+	return msgType, data, nil
+}
+
+// corruptFrame truncates a message at a random point and flips the bits of
+// its last remaining byte
+func corruptFrame(data []byte) []byte {
+	corrupted := make([]byte, rand.Intn(len(data))+1)
+	copy(corrupted, data)
+	corrupted[len(corrupted)-1] ^= 0xff
+	return corrupted
+}
@@ -0,0 +1,86 @@
+package exchange
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+)
+
+func TestSetChaos(t *testing.T) {
+	b := Base{Name: "test"}
+	b.Requester = request.New("test", request.NewRateLimit(0, 0), request.NewRateLimit(0, 0),
+		new(http.Client))
+	b.WebsocketInit()
+
+	b.SetChaos(config.ChaosConfig{
+		Enabled:          true,
+		TimeoutRate:      0.1,
+		ServerErrorRate:  0.2,
+		DisconnectRate:   0.3,
+		CorruptFrameRate: 0.4,
+	})
+	if b.Requester.GetChaos().ServerErrorRate != 0.2 ||
+		b.Websocket.GetChaos().CorruptFrameRate != 0.4 {
+		t.Error("Test Failed - SetChaos() chaos settings not applied")
+	}
+
+	b.SetChaos(config.ChaosConfig{TimeoutRate: 0.1, DisconnectRate: 0.3})
+	if b.Requester.GetChaos().TimeoutRate != 0 || b.Websocket.GetChaos().DisconnectRate != 0 {
+		t.Error("Test Failed - SetChaos() disabled chaos settings applied")
+	}
+}
+
+func TestWebsocketChaosReadMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for i := 0; i < 2; i++ {
+			if conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"trade"}`)) != nil {
+				return
+			}
+		}
+		conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal("Test Failed - Dial() error", err)
+	}
+	defer conn.Close()
+
+	b := Base{}
+	b.WebsocketInit()
+	_, data, err := b.Websocket.ReadMessage(conn)
+	if err != nil || string(data) != `{"event":"trade"}` {
+		t.Error("Test Failed - ReadMessage() unexpected message", string(data), err)
+	}
+
+	b.Websocket.SetChaos(WebsocketChaos{CorruptFrameRate: 1})
+	_, data, err = b.Websocket.ReadMessage(conn)
+	if err != nil || string(data) == `{"event":"trade"}` {
+		t.Error("Test Failed - ReadMessage() message not corrupted", err)
+	}
+
+	b.Websocket.SetChaos(WebsocketChaos{DisconnectRate: 1})
+	_, _, err = b.Websocket.ReadMessage(conn)
+	if err != ErrWebsocketChaosDisconnect {
+		t.Error("Test Failed - ReadMessage() disconnect not injected", err)
+	}
+
+	b.Websocket.SetChaos(WebsocketChaos{})
+	_, _, err = b.Websocket.ReadMessage(conn)
+	if err == nil {
+		t.Error("Test Failed - ReadMessage() read from dropped connection")
+	}
+}
@@ -0,0 +1,101 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// API key permission scopes, exchanges map their reported permissions to
+// these
+const (
+	APIPermissionRead     = "read"
+	APIPermissionTrade    = "trade"
+	APIPermissionWithdraw = "withdraw"
+)
+
+// ErrAuthenticatedAPINotEnabled is returned when verifying the API credentials
+// of an exchange without authenticated API support enabled
+var ErrAuthenticatedAPINotEnabled = errors.New("authenticated API support not enabled")
+
+// APIPermissions holds the result of verifying the API credentials of an
+// exchange. Scopes are only set when the exchange reports the permissions of
+// the API key
+type APIPermissions struct {
+	Verified bool      `json:"verified"`
+	Reported bool      `json:"reported"`
+	Scopes   []string  `json:"scopes,omitempty"`
+	Checked  time.Time `json:"checked"`
+}
+
+// Has returns whether the API key has a permission scope. Permissions are
+// assumed to be granted when the exchange doesn't report them
+func (p APIPermissions) Has(scope string) bool {
+	if !p.Reported {
+		return true
+	}
+	return common.StringDataCompare(p.Scopes, scope)
+}
+
+// APIPermissionsFunc performs an authenticated request which reports the
+// permission scopes of the API key
+type APIPermissionsFunc func() ([]string, error)
+
+// SetAPIPermissionsFunc sets the request used to detect the permission scopes
+// of the API key, for exchanges which report them
+func (e *Base) SetAPIPermissionsFunc(fn APIPermissionsFunc) {
+	e.apiPermissionsMtx.Lock()
+	e.apiPermissionsFunc = fn
+	e.apiPermissionsMtx.Unlock()
+}
+
+// VerifyAPICredentials performs a harmless authenticated request to confirm
+// the API credentials work, detecting the permission scopes of the API key
+// where the exchange reports them and otherwise calling authCheck. Rejected
+// credentials disable authenticated API support so failures surface on
+// startup rather than mid-trade
+func (e *Base) VerifyAPICredentials(authCheck func() error) error {
+	if !e.AuthenticatedAPISupport {
+		return ErrAuthenticatedAPINotEnabled
+	}
+
+	e.apiPermissionsMtx.Lock()
+	defer e.apiPermissionsMtx.Unlock()
+
+	permissions := APIPermissions{Checked: time.Now()}
+	var err error
+	if e.apiPermissionsFunc != nil {
+		permissions.Scopes, err = e.apiPermissionsFunc()
+		permissions.Reported = true
+	} else {
+		err = authCheck()
+	}
+
+	if err != nil {
+		e.AuthenticatedAPISupport = false
+		e.apiPermissions = APIPermissions{Checked: permissions.Checked}
+		return fmt.Errorf("%s API credentials rejected, authenticated API support disabled: %s",
+			e.Name, err)
+	}
+
+	permissions.Verified = true
+	e.apiPermissions = permissions
+	if permissions.Reported {
+		log.Printf("%s API credentials verified with permissions %v.\n",
+			e.Name, permissions.Scopes)
+	} else {
+		log.Printf("%s API credentials verified.\n", e.Name)
+	}
+	return nil
+}
+
+// GetAPIPermissions returns the result of the last API credentials
+// verification
+func (e *Base) GetAPIPermissions() APIPermissions {
+	e.apiPermissionsMtx.Lock()
+	defer e.apiPermissionsMtx.Unlock()
+	return e.apiPermissions
+}
@@ -0,0 +1,50 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyAPICredentials(t *testing.T) {
+	b := Base{Name: "RAWR"}
+	check := func() error { return nil }
+
+	err := b.VerifyAPICredentials(check)
+	if err != ErrAuthenticatedAPINotEnabled {
+		t.Error("Test Failed - VerifyAPICredentials() authenticated API disabled error", err)
+	}
+
+	b.AuthenticatedAPISupport = true
+	err = b.VerifyAPICredentials(check)
+	if err != nil {
+		t.Error("Test Failed - VerifyAPICredentials() error", err)
+	}
+
+	permissions := b.GetAPIPermissions()
+	if !permissions.Verified || permissions.Reported || !permissions.Has(APIPermissionWithdraw) {
+		t.Errorf("Test Failed - GetAPIPermissions() unexpected permissions %+v", permissions)
+	}
+
+	b.SetAPIPermissionsFunc(func() ([]string, error) {
+		return []string{APIPermissionRead, APIPermissionTrade}, nil
+	})
+	err = b.VerifyAPICredentials(check)
+	if err != nil {
+		t.Error("Test Failed - VerifyAPICredentials() permissions error", err)
+	}
+
+	permissions = b.GetAPIPermissions()
+	if !permissions.Reported || !permissions.Has(APIPermissionTrade) || permissions.Has(APIPermissionWithdraw) {
+		t.Errorf("Test Failed - GetAPIPermissions() unexpected reported permissions %+v", permissions)
+	}
+
+	b.SetAPIPermissionsFunc(nil)
+	err = b.VerifyAPICredentials(func() error { return errors.New("invalid API key") })
+	if err == nil {
+		t.Error("Test Failed - VerifyAPICredentials() rejected credentials error")
+	}
+
+	if b.AuthenticatedAPISupport || b.GetAPIPermissions().Verified {
+		t.Error("Test Failed - VerifyAPICredentials() authenticated API support not disabled")
+	}
+}
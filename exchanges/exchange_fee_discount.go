@@ -0,0 +1,107 @@
+package exchange
+
+import (
+	"errors"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// ErrFeeDiscountNotConfigured is returned when an exchange has no fee token
+// discount configured or detected
+var ErrFeeDiscountNotConfigured = errors.New("fee token discount not configured")
+
+// FeeDiscount holds the discount on trading fees paid in an exchange token
+// such as BNB, OKB or HT. Enabled is whether discount mode is enabled on the
+// account and Balance is the token balance left to pay fees with
+type FeeDiscount struct {
+	Token   string  `json:"token"`
+	Rate    float64 `json:"rate"`
+	Enabled bool    `json:"enabled"`
+	Balance float64 `json:"balance"`
+}
+
+// SetFeeDiscount sets the fee token discount of the exchange from its config,
+// keeping the tracked token balance
+func (e *Base) SetFeeDiscount(cfg config.FeeDiscountConfig) {
+	e.feeDiscountMtx.Lock()
+	defer e.feeDiscountMtx.Unlock()
+
+	var balance float64
+	if e.feeDiscount != nil {
+		balance = e.feeDiscount.Balance
+	}
+
+	e.feeDiscount = &FeeDiscount{
+		Token:   common.StringToUpper(cfg.Token),
+		Rate:    cfg.Rate,
+		Enabled: cfg.Enabled,
+		Balance: balance,
+	}
+}
+
+// GetFeeDiscount returns the fee token discount of the exchange
+func (e *Base) GetFeeDiscount() (FeeDiscount, error) {
+	e.feeDiscountMtx.Lock()
+	defer e.feeDiscountMtx.Unlock()
+
+	if e.feeDiscount == nil {
+		return FeeDiscount{}, ErrFeeDiscountNotConfigured
+	}
+	return *e.feeDiscount, nil
+}
+
+// UpdateFeeDiscount returns the fee token discount of the exchange. Exchanges
+// which can detect whether discount mode is enabled on the account override
+// this to refresh it and the token balance
+func (e *Base) UpdateFeeDiscount() (FeeDiscount, error) {
+	return e.GetFeeDiscount()
+}
+
+// SetFeeTokenBalance sets the token balance left to pay fees with
+func (e *Base) SetFeeTokenBalance(balance float64) {
+	e.feeDiscountMtx.Lock()
+	defer e.feeDiscountMtx.Unlock()
+
+	if e.feeDiscount != nil {
+		e.feeDiscount.Balance = balance
+	}
+}
+
+// DepleteFeeToken deducts the token amount paid in fees from the tracked token
+// balance, returning the balance left
+func (e *Base) DepleteFeeToken(amount float64) (float64, error) {
+	e.feeDiscountMtx.Lock()
+	defer e.feeDiscountMtx.Unlock()
+
+	if e.feeDiscount == nil {
+		return 0, ErrFeeDiscountNotConfigured
+	}
+
+	e.feeDiscount.Balance -= amount
+	if e.feeDiscount.Balance < 0 {
+		e.feeDiscount.Balance = 0
+	}
+	return e.feeDiscount.Balance, nil
+}
+
+// DiscountTradingFee applies the fee token discount to a trading fee when
+// discount mode is enabled. When the fee builder holds the fee token price the
+// discount is only applied if the token balance covers the discounted fee, as
+// the exchange charges the full fee otherwise
+func (e *Base) DiscountTradingFee(feeBuilder FeeBuilder, fee float64) float64 {
+	if feeBuilder.FeeType != CryptocurrencyTradeFee {
+		return fee
+	}
+
+	discount, err := e.GetFeeDiscount()
+	if err != nil || !discount.Enabled || discount.Rate <= 0 {
+		return fee
+	}
+
+	discounted := fee * (1 - discount.Rate)
+	if feeBuilder.FeeTokenPrice > 0 && discount.Balance*feeBuilder.FeeTokenPrice < discounted {
+		return fee
+	}
+	return discounted
+}
@@ -0,0 +1,90 @@
+package exchange
+
+import (
+	"errors"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// ErrNoFeeEstimate is returned when the static fee table of an exchange has
+// no fee for the fee type or currency
+var ErrNoFeeEstimate = errors.New("no static fee estimate available")
+
+// FeeTable holds the default fees of an exchange, used to estimate the fees
+// which need authenticated requests when the exchange has no API credentials.
+// Maker and Taker are fractions of the trade value, Withdrawal holds the flat
+// cryptocurrency withdrawal fee of each currency and WithdrawalMinimum the
+// smallest amount of each currency which can be withdrawn
+type FeeTable struct {
+	Maker             float64
+	Taker             float64
+	Withdrawal        map[string]float64
+	WithdrawalMinimum map[string]float64
+}
+
+// SetFeeTable sets the static fee table of the exchange
+func (e *Base) SetFeeTable(table FeeTable) {
+	e.feeTable = &table
+}
+
+// IsFeeEstimated returns whether a fee of the exchange is estimated from its
+// static fee table, which happens when the exchange has no API credentials
+// and its table holds the fee
+func (e *Base) IsFeeEstimated(feeBuilder FeeBuilder) bool {
+	if e.AuthenticatedAPISupport || e.feeTable == nil {
+		return false
+	}
+
+	switch feeBuilder.FeeType {
+	case CryptocurrencyTradeFee:
+		return true
+	case CryptocurrencyWithdrawalFee:
+		_, ok := e.feeTable.Withdrawal[common.StringToUpper(feeBuilder.FirstCurrency)]
+		return ok
+	}
+	return false
+}
+
+// GetWithdrawalMinimum returns the smallest amount of a currency which can be
+// withdrawn from the exchange on its default chain and whether the minimum is
+// known, taken from the asset metadata before the static fee table
+func (e *Base) GetWithdrawalMinimum(currency string) (float64, bool) {
+	metadata, err := e.GetChainMetadata(currency, "")
+	if err == nil && metadata.MinWithdrawal > 0 {
+		return metadata.MinWithdrawal, true
+	}
+
+	if e.feeTable == nil {
+		return 0, false
+	}
+
+	minimum, ok := e.feeTable.WithdrawalMinimum[common.StringToUpper(currency)]
+	return minimum, ok
+}
+
+// EstimateFee returns a fee estimated from the static fee table of the
+// exchange
+func (e *Base) EstimateFee(feeBuilder FeeBuilder) (float64, error) {
+	if e.feeTable == nil {
+		return 0, ErrNoFeeEstimate
+	}
+
+	switch feeBuilder.FeeType {
+	case CryptocurrencyTradeFee:
+		rate := e.feeTable.Taker
+		if feeBuilder.IsMaker {
+			rate = e.feeTable.Maker
+		}
+		fee := rate * feeBuilder.PurchasePrice * feeBuilder.Amount
+		if fee < 0 {
+			fee = 0
+		}
+		return fee, nil
+	case CryptocurrencyWithdrawalFee:
+		fee, ok := e.feeTable.Withdrawal[common.StringToUpper(feeBuilder.FirstCurrency)]
+		if ok {
+			return fee, nil
+		}
+	}
+	return 0, ErrNoFeeEstimate
+}
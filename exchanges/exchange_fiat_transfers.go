@@ -0,0 +1,193 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Fiat transfer states, a bank transfer moves from submitted to processing to
+// sent unless it fails or is cancelled
+const (
+	FiatTransferSubmitted  = "SUBMITTED"
+	FiatTransferProcessing = "PROCESSING"
+	FiatTransferSent       = "SENT"
+	FiatTransferFailed     = "FAILED"
+	FiatTransferCancelled  = "CANCELLED"
+)
+
+// ErrFiatTransferNotFound is returned when an exchange has no fiat transfer
+// with the reference
+var ErrFiatTransferNotFound = errors.New("fiat transfer not found")
+
+// IFiatTransferTracker is implemented by exchanges which return references for
+// fiat withdrawals to track the bank transfers by
+type IFiatTransferTracker interface {
+	GetFiatTransferStatus(reference string) (FiatTransferStatus, error)
+}
+
+// FiatTransferStatus holds the state of a bank transfer initiated by a fiat
+// withdrawal, Reference is the withdrawal ID returned by the exchange
+type FiatTransferStatus struct {
+	Reference string
+	Status    string
+	Amount    float64
+	Updated   time.Time
+}
+
+// IsFiatTransferFinal returns whether a fiat transfer state is final
+func IsFiatTransferFinal(status string) bool {
+	return status == FiatTransferSent || status == FiatTransferFailed ||
+		status == FiatTransferCancelled
+}
+
+// FiatTransfer holds a bank transfer initiated by a fiat withdrawal, tracked
+// by the reference the exchange returned for it
+type FiatTransfer struct {
+	Exchange  string    `json:"exchangeName"`
+	Reference string    `json:"reference"`
+	Currency  string    `json:"currency"`
+	Amount    float64   `json:"amount"`
+	Status    string    `json:"status"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+// Vars for the fiat transfer registry
+var (
+	fiatTransfers     []FiatTransfer
+	fiatTransfersFile string
+	fiatTransfersMtx  sync.Mutex
+)
+
+// fiatTransferRank orders the fiat transfer states so a transfer never moves
+// back to an earlier state
+func fiatTransferRank(status string) int {
+	switch status {
+	case FiatTransferSubmitted:
+		return 0
+	case FiatTransferProcessing:
+		return 1
+	}
+	return 2
+}
+
+// AddFiatTransfer registers a submitted fiat withdrawal by its reference
+func AddFiatTransfer(transfer FiatTransfer) (FiatTransfer, error) {
+	if transfer.Exchange == "" || transfer.Reference == "" {
+		return FiatTransfer{}, errors.New("fiat transfer requires an exchange and reference")
+	}
+
+	fiatTransfersMtx.Lock()
+	defer fiatTransfersMtx.Unlock()
+
+	if getFiatTransferIndex(transfer.Exchange, transfer.Reference) != -1 {
+		return FiatTransfer{}, fmt.Errorf("%s fiat transfer %s already registered",
+			transfer.Exchange, transfer.Reference)
+	}
+
+	transfer.Status = FiatTransferSubmitted
+	transfer.Created = time.Now()
+	transfer.Updated = transfer.Created
+	fiatTransfers = append(fiatTransfers, transfer)
+	return transfer, saveFiatTransfers()
+}
+
+// UpdateFiatTransfer applies the state reported by an exchange to a
+// registered fiat transfer, returning whether the state changed. States which
+// would move the transfer back to an earlier state are ignored
+func UpdateFiatTransfer(exchangeName string, status FiatTransferStatus) (FiatTransfer, bool, error) {
+	fiatTransfersMtx.Lock()
+	defer fiatTransfersMtx.Unlock()
+
+	x := getFiatTransferIndex(exchangeName, status.Reference)
+	if x == -1 {
+		return FiatTransfer{}, false, ErrFiatTransferNotFound
+	}
+
+	transfer := &fiatTransfers[x]
+	if transfer.Status == status.Status || IsFiatTransferFinal(transfer.Status) ||
+		fiatTransferRank(status.Status) < fiatTransferRank(transfer.Status) {
+		return *transfer, false, nil
+	}
+
+	transfer.Status = status.Status
+	transfer.Updated = time.Now()
+	return *transfer, true, saveFiatTransfers()
+}
+
+// GetFiatTransfers returns all registered fiat transfers
+func GetFiatTransfers() []FiatTransfer {
+	fiatTransfersMtx.Lock()
+	defer fiatTransfersMtx.Unlock()
+	return append([]FiatTransfer(nil), fiatTransfers...)
+}
+
+// GetPendingFiatTransfers returns the registered fiat transfers which haven't
+// reached a final state
+func GetPendingFiatTransfers() []FiatTransfer {
+	fiatTransfersMtx.Lock()
+	defer fiatTransfersMtx.Unlock()
+
+	var pending []FiatTransfer
+	for x := range fiatTransfers {
+		if !IsFiatTransferFinal(fiatTransfers[x].Status) {
+			pending = append(pending, fiatTransfers[x])
+		}
+	}
+	return pending
+}
+
+// LoadFiatTransfers loads the fiat transfer registry from a file, which the
+// registry is persisted to from then on. A missing file is not an error
+func LoadFiatTransfers(path string) error {
+	fiatTransfersMtx.Lock()
+	defer fiatTransfersMtx.Unlock()
+
+	fiatTransfersFile = path
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var loaded []FiatTransfer
+	err = common.JSONDecode(data, &loaded)
+	if err != nil {
+		return err
+	}
+	fiatTransfers = loaded
+	return nil
+}
+
+// getFiatTransferIndex returns the index of a fiat transfer or -1,
+// fiatTransfersMtx must be held by the caller
+func getFiatTransferIndex(exchangeName, reference string) int {
+	for x := range fiatTransfers {
+		if fiatTransfers[x].Exchange == exchangeName &&
+			fiatTransfers[x].Reference == reference {
+			return x
+		}
+	}
+	return -1
+}
+
+// saveFiatTransfers persists the fiat transfer registry if a file has been
+// loaded, fiatTransfersMtx must be held by the caller
+func saveFiatTransfers() error {
+	if fiatTransfersFile == "" {
+		return nil
+	}
+
+	data, err := common.JSONEncode(fiatTransfers)
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(fiatTransfersFile, data)
+}
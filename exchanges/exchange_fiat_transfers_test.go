@@ -0,0 +1,80 @@
+package exchange
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFiatTransfers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fiattransfers")
+	if err != nil {
+		t.Fatal("Test Failed - TempDir() error", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fiattransfers.json")
+
+	err = LoadFiatTransfers(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadFiatTransfers() error", err)
+	}
+
+	if _, err = AddFiatTransfer(FiatTransfer{Exchange: "Bitstamp"}); err == nil {
+		t.Error("Test Failed - AddFiatTransfer() expected missing reference error")
+	}
+
+	transfer, err := AddFiatTransfer(FiatTransfer{Exchange: "Bitstamp", Reference: "1337",
+		Currency: "EUR", Amount: 500})
+	if err != nil || transfer.Status != FiatTransferSubmitted {
+		t.Fatal("Test Failed - AddFiatTransfer() error", err)
+	}
+
+	if _, err = AddFiatTransfer(FiatTransfer{Exchange: "Bitstamp", Reference: "1337"}); err == nil {
+		t.Error("Test Failed - AddFiatTransfer() expected duplicate reference error")
+	}
+
+	transfer, changed, err := UpdateFiatTransfer("Bitstamp",
+		FiatTransferStatus{Reference: "1337", Status: FiatTransferProcessing})
+	if err != nil || !changed || transfer.Status != FiatTransferProcessing {
+		t.Error("Test Failed - UpdateFiatTransfer() error", err)
+	}
+
+	_, changed, _ = UpdateFiatTransfer("Bitstamp",
+		FiatTransferStatus{Reference: "1337", Status: FiatTransferSubmitted})
+	if changed {
+		t.Error("Test Failed - UpdateFiatTransfer() moved back to an earlier state")
+	}
+
+	_, _, err = UpdateFiatTransfer("Kraken",
+		FiatTransferStatus{Reference: "1337", Status: FiatTransferSent})
+	if err != ErrFiatTransferNotFound {
+		t.Error("Test Failed - UpdateFiatTransfer() expected not found error", err)
+	}
+
+	_, changed, _ = UpdateFiatTransfer("Bitstamp",
+		FiatTransferStatus{Reference: "1337", Status: FiatTransferSent})
+	if !changed || len(GetPendingFiatTransfers()) != 0 {
+		t.Error("Test Failed - UpdateFiatTransfer() transfer still pending")
+	}
+
+	_, changed, _ = UpdateFiatTransfer("Bitstamp",
+		FiatTransferStatus{Reference: "1337", Status: FiatTransferFailed})
+	if changed {
+		t.Error("Test Failed - UpdateFiatTransfer() changed a final state")
+	}
+
+	fiatTransfers = nil
+	err = LoadFiatTransfers(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadFiatTransfers() error", err)
+	}
+
+	loaded := GetFiatTransfers()
+	if len(loaded) != 1 || loaded[0].Status != FiatTransferSent || loaded[0].Amount != 500 {
+		t.Errorf("Test Failed - LoadFiatTransfers() unexpected transfers %+v", loaded)
+	}
+
+	fiatTransfersFile = ""
+	fiatTransfers = nil
+}
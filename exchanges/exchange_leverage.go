@@ -0,0 +1,62 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Margin modes, cross margin shares the account balance between positions
+// while isolated margin limits the loss of a position to its own margin
+const (
+	MarginModeCross    = "cross"
+	MarginModeIsolated = "isolated"
+)
+
+// ErrInvalidMarginMode is returned when setting an unknown margin mode
+var ErrInvalidMarginMode = errors.New("invalid margin mode, expected cross or isolated")
+
+// ILeverageTrader is implemented by derivatives exchanges which support
+// configuring the leverage and margin mode of a pair through the wrapper
+type ILeverageTrader interface {
+	GetLeverage(p pair.CurrencyPair, assetType string) (Leverage, error)
+	SetLeverage(l Leverage) (Leverage, error)
+}
+
+// Leverage holds the leverage and margin mode of a pair on a derivatives
+// exchange, and the leverage range the exchange allows for it. A zero
+// leverage in cross margin mode uses the exchange's default cross leverage
+type Leverage struct {
+	Pair        pair.CurrencyPair
+	AssetType   string
+	Leverage    float64
+	MarginMode  string
+	MinLeverage float64
+	MaxLeverage float64
+}
+
+// ValidateLeverage checks the margin mode of the leverage setting and that its
+// leverage is within the range the exchange allows. A zero maximum leverage
+// leaves the range unchecked
+func ValidateLeverage(l Leverage) error {
+	switch l.MarginMode {
+	case MarginModeCross:
+		if l.Leverage == 0 {
+			return nil
+		}
+	case MarginModeIsolated:
+	default:
+		return ErrInvalidMarginMode
+	}
+
+	if l.Leverage <= 0 {
+		return fmt.Errorf("%s margin leverage must be greater than zero", l.MarginMode)
+	}
+
+	if l.MaxLeverage > 0 && (l.Leverage < l.MinLeverage || l.Leverage > l.MaxLeverage) {
+		return fmt.Errorf("leverage %v outside of the allowed range %v to %v for %s",
+			l.Leverage, l.MinLeverage, l.MaxLeverage, l.Pair.Pair())
+	}
+	return nil
+}
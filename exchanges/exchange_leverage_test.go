@@ -0,0 +1,44 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestValidateLeverage(t *testing.T) {
+	l := Leverage{
+		Pair:        pair.NewCurrencyPair("XBT", "USD"),
+		MarginMode:  MarginModeCross,
+		MinLeverage: 1,
+		MaxLeverage: 100,
+	}
+	if err := ValidateLeverage(l); err != nil {
+		t.Error("Test Failed - ValidateLeverage() default cross leverage error", err)
+	}
+
+	l.MarginMode = "portfolio"
+	if err := ValidateLeverage(l); err != ErrInvalidMarginMode {
+		t.Error("Test Failed - ValidateLeverage() invalid margin mode error", err)
+	}
+
+	l.MarginMode = MarginModeIsolated
+	if err := ValidateLeverage(l); err == nil {
+		t.Error("Test Failed - ValidateLeverage() zero isolated leverage accepted")
+	}
+
+	l.Leverage = 25
+	if err := ValidateLeverage(l); err != nil {
+		t.Error("Test Failed - ValidateLeverage() error", err)
+	}
+
+	l.Leverage = 125
+	if err := ValidateLeverage(l); err == nil {
+		t.Error("Test Failed - ValidateLeverage() leverage above range accepted")
+	}
+
+	l.MaxLeverage = 0
+	if err := ValidateLeverage(l); err != nil {
+		t.Error("Test Failed - ValidateLeverage() unknown range error", err)
+	}
+}
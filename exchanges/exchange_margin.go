@@ -0,0 +1,94 @@
+package exchange
+
+import (
+	"errors"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Margin position sides
+const (
+	MarginPositionLong  = "long"
+	MarginPositionShort = "short"
+)
+
+// IMarginTrader is implemented by exchanges which support margin trading
+// through the wrapper
+type IMarginTrader interface {
+	SubmitExchangeMarginOrder(order MarginOrder) (int64, error)
+	GetExchangeMarginPositions() ([]MarginPosition, error)
+	CloseExchangeMarginPosition(p pair.CurrencyPair) error
+}
+
+// ILender is implemented by exchanges which support lending offers through the
+// wrapper
+type ILender interface {
+	GetExchangeLendingOffers() ([]LendingOffer, error)
+	CreateExchangeLendingOffer(offer LendingOffer) (int64, error)
+	CancelExchangeLendingOffer(offerID int64) error
+}
+
+// MarginOrder holds a limit order buying or selling a pair on margin.
+// MaxLendingRate caps the daily rate paid on the borrowed funds, a zero rate
+// accepting the exchange's default
+type MarginOrder struct {
+	Pair           pair.CurrencyPair
+	Side           OrderSide
+	Amount         float64
+	Price          float64
+	MaxLendingRate float64
+}
+
+// MarginPosition holds an open margin position of a pair. Side is long or
+// short, BasePrice the average entry price and ProfitLoss and LendingFees are
+// denominated in the quote currency
+type MarginPosition struct {
+	Pair             pair.CurrencyPair `json:"pair"`
+	Side             string            `json:"side"`
+	Amount           float64           `json:"amount"`
+	Total            float64           `json:"total"`
+	BasePrice        float64           `json:"basePrice"`
+	LiquidationPrice float64           `json:"liquidationPrice"`
+	ProfitLoss       float64           `json:"profitLoss"`
+	LendingFees      float64           `json:"lendingFees"`
+}
+
+// LendingOffer holds an offer lending an amount of a currency to margin
+// traders at a daily Rate for Duration days, renewed on repayment when
+// AutoRenew is set. ID and Created are set by the exchange
+type LendingOffer struct {
+	ID        int64     `json:"id"`
+	Currency  string    `json:"currency"`
+	Amount    float64   `json:"amount"`
+	Rate      float64   `json:"rate"`
+	Duration  int       `json:"duration"`
+	AutoRenew bool      `json:"autoRenew"`
+	Created   time.Time `json:"created,omitempty"`
+}
+
+// ValidateMarginOrder checks a margin order has a side, amount and price
+func ValidateMarginOrder(order MarginOrder) error {
+	if order.Side != OrderSideBuy() && order.Side != OrderSideSell() {
+		return errors.New("margin order side must be buy or sell")
+	}
+	if order.Amount <= 0 || order.Price <= 0 {
+		return errors.New("margin order amount and price must be greater than zero")
+	}
+	if order.MaxLendingRate < 0 {
+		return errors.New("margin order lending rate cannot be negative")
+	}
+	return nil
+}
+
+// ValidateLendingOffer checks a lending offer has a currency, amount, rate and
+// duration
+func ValidateLendingOffer(offer LendingOffer) error {
+	if offer.Currency == "" {
+		return errors.New("lending offer currency not set")
+	}
+	if offer.Amount <= 0 || offer.Rate <= 0 || offer.Duration <= 0 {
+		return errors.New("lending offer amount, rate and duration must be greater than zero")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestValidateMarginOrder(t *testing.T) {
+	order := MarginOrder{
+		Pair:   pair.NewCurrencyPair("ETH", "BTC"),
+		Side:   OrderSideBuy(),
+		Amount: 1,
+		Price:  0.03,
+	}
+	if err := ValidateMarginOrder(order); err != nil {
+		t.Error("Test Failed - ValidateMarginOrder() error", err)
+	}
+
+	order.Side = "Long"
+	if err := ValidateMarginOrder(order); err == nil {
+		t.Error("Test Failed - ValidateMarginOrder() invalid side accepted")
+	}
+
+	order.Side = OrderSideSell()
+	order.Price = 0
+	if err := ValidateMarginOrder(order); err == nil {
+		t.Error("Test Failed - ValidateMarginOrder() zero price accepted")
+	}
+
+	order.Price = 0.03
+	order.MaxLendingRate = -0.01
+	if err := ValidateMarginOrder(order); err == nil {
+		t.Error("Test Failed - ValidateMarginOrder() negative lending rate accepted")
+	}
+}
+
+func TestValidateLendingOffer(t *testing.T) {
+	offer := LendingOffer{Currency: "BTC", Amount: 1, Rate: 0.0002, Duration: 2}
+	if err := ValidateLendingOffer(offer); err != nil {
+		t.Error("Test Failed - ValidateLendingOffer() error", err)
+	}
+
+	offer.Duration = 0
+	if err := ValidateLendingOffer(offer); err == nil {
+		t.Error("Test Failed - ValidateLendingOffer() zero duration accepted")
+	}
+
+	offer.Duration = 2
+	offer.Currency = ""
+	if err := ValidateLendingOffer(offer); err == nil {
+		t.Error("Test Failed - ValidateLendingOffer() blank currency accepted")
+	}
+}
@@ -0,0 +1,116 @@
+package exchange
+
+import (
+	"errors"
+
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+// Common orderbook depths, the number of levels kept on each side
+const (
+	OrderbookDepthFull  = 0
+	OrderbookDepthTop5  = 5
+	OrderbookDepthTop25 = 25
+)
+
+// ErrInvalidOrderbookDepth is returned when an orderbook depth is negative or
+// set for a pair which can't be parsed
+var ErrInvalidOrderbookDepth = errors.New("invalid orderbook depth")
+
+// pairDepth holds the orderbook depth of a currency pair
+type pairDepth struct {
+	Pair  pair.CurrencyPair
+	Depth int
+}
+
+// SetOrderbookDepth sets the orderbook depth of each pair from the exchange
+// configuration, used to limit REST orderbook requests, websocket orderbook
+// subscriptions and the stored orderbooks
+func (e *Base) SetOrderbookDepth(cfg *config.OrderbookDepthConfig) error {
+	var defaultDepth int
+	var depths []pairDepth
+	if cfg != nil {
+		if cfg.Default < 0 {
+			return ErrInvalidOrderbookDepth
+		}
+		defaultDepth = cfg.Default
+
+		for p, depth := range cfg.Pairs {
+			if depth < 0 || p == "" {
+				return ErrInvalidOrderbookDepth
+			}
+
+			pairs := e.toCanonicalPairs(pair.FormatPairs([]string{p},
+				e.ConfigCurrencyPairFormat.Delimiter,
+				e.ConfigCurrencyPairFormat.Index))
+			if len(pairs) != 1 || pairs[0].SecondCurrency == "" {
+				return ErrInvalidOrderbookDepth
+			}
+			depths = append(depths, pairDepth{Pair: pairs[0], Depth: depth})
+		}
+	}
+
+	e.orderbookDepthMtx.Lock()
+	e.orderbookDepthDefault = defaultDepth
+	e.orderbookDepths = depths
+	e.orderbookDepthMtx.Unlock()
+	return nil
+}
+
+// GetOrderbookDepth returns the number of orderbook levels kept on each side
+// for a pair, zero keeping the full orderbook
+func (e *Base) GetOrderbookDepth(p pair.CurrencyPair) int {
+	e.orderbookDepthMtx.Lock()
+	defer e.orderbookDepthMtx.Unlock()
+	for x := range e.orderbookDepths {
+		if e.orderbookDepths[x].Pair.Equal(p, true) {
+			return e.orderbookDepths[x].Depth
+		}
+	}
+	return e.orderbookDepthDefault
+}
+
+// GetOrderbookRequestDepth returns the depth to request from an exchange for
+// a pair. Exchanges accepting an ascending list of supported depths get the
+// smallest covering the configured depth, or the largest for the full
+// orderbook or when none cover it. Exchanges accepting any depth pass no
+// supported depths and get fallback for the full orderbook
+func (e *Base) GetOrderbookRequestDepth(p pair.CurrencyPair, supported []int, fallback int) int {
+	depth := e.GetOrderbookDepth(p)
+	if len(supported) == 0 {
+		if depth == OrderbookDepthFull {
+			return fallback
+		}
+		return depth
+	}
+
+	if depth == OrderbookDepthFull {
+		return supported[len(supported)-1]
+	}
+
+	for x := range supported {
+		if supported[x] >= depth {
+			return supported[x]
+		}
+	}
+	return supported[len(supported)-1]
+}
+
+// TruncateOrderbook trims an orderbook to the configured depth of its pair,
+// for exchanges which return more levels than requested
+func (e *Base) TruncateOrderbook(p pair.CurrencyPair, ob orderbook.Base) orderbook.Base {
+	depth := e.GetOrderbookDepth(p)
+	if depth == OrderbookDepthFull {
+		return ob
+	}
+
+	if len(ob.Bids) > depth {
+		ob.Bids = ob.Bids[:depth]
+	}
+	if len(ob.Asks) > depth {
+		ob.Asks = ob.Asks[:depth]
+	}
+	return ob
+}
@@ -0,0 +1,81 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+func TestOrderbookDepth(t *testing.T) {
+	b := Base{
+		Name:                     "testExchange",
+		EnabledPairs:             []string{"BTC-USD", "LTC-USD"},
+		ConfigCurrencyPairFormat: config.CurrencyPairFormatConfig{Delimiter: "-"},
+	}
+	btc := pair.NewCurrencyPairDelimiter("BTC-USD", "-")
+	ltc := pair.NewCurrencyPairDelimiter("LTC-USD", "-")
+
+	if b.GetOrderbookDepth(btc) != OrderbookDepthFull {
+		t.Error("Test Failed - GetOrderbookDepth() expected full orderbook by default")
+	}
+
+	err := b.SetOrderbookDepth(&config.OrderbookDepthConfig{Default: -1})
+	if err != ErrInvalidOrderbookDepth {
+		t.Error("Test Failed - SetOrderbookDepth() expected invalid depth error", err)
+	}
+
+	err = b.SetOrderbookDepth(&config.OrderbookDepthConfig{
+		Default: OrderbookDepthTop25,
+		Pairs:   map[string]int{"btc-usd": OrderbookDepthTop5},
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SetOrderbookDepth() error", err)
+	}
+
+	if b.GetOrderbookDepth(btc) != OrderbookDepthTop5 ||
+		b.GetOrderbookDepth(ltc) != OrderbookDepthTop25 {
+		t.Error("Test Failed - GetOrderbookDepth() unexpected pair depths",
+			b.GetOrderbookDepth(btc), b.GetOrderbookDepth(ltc))
+	}
+
+	supported := []int{10, 50, 100}
+	if b.GetOrderbookRequestDepth(btc, supported, 1000) != 10 ||
+		b.GetOrderbookRequestDepth(ltc, supported, 1000) != 50 {
+		t.Error("Test Failed - GetOrderbookRequestDepth() unexpected supported depth")
+	}
+
+	if b.GetOrderbookRequestDepth(btc, nil, 1000) != OrderbookDepthTop5 {
+		t.Error("Test Failed - GetOrderbookRequestDepth() unexpected depth")
+	}
+
+	ob := orderbook.Base{
+		Bids: make([]orderbook.Item, 10),
+		Asks: make([]orderbook.Item, 3),
+	}
+	ob = b.TruncateOrderbook(btc, ob)
+	if len(ob.Bids) != 5 || len(ob.Asks) != 3 {
+		t.Error("Test Failed - TruncateOrderbook() unexpected levels", len(ob.Bids), len(ob.Asks))
+	}
+
+	b.WebsocketInit()
+	subscriber := func(sub WebsocketSubscription) error { return nil }
+	err = b.WebsocketSubscriptionSetup(&config.WebsocketSubscriptionConfig{
+		Channels: []string{WebsocketChannelOrderbook},
+	}, []string{WebsocketChannelOrderbook}, subscriber, subscriber)
+	if err != nil {
+		t.Fatal("Test Failed - WebsocketSubscriptionSetup() error", err)
+	}
+
+	subs := b.Websocket.GetSubscriptions()
+	if len(subs) != 2 || subs[0].Depth != OrderbookDepthTop5 ||
+		subs[1].Depth != OrderbookDepthTop25 {
+		t.Errorf("Test Failed - WebsocketSubscriptionSetup() unexpected depths %v", subs)
+	}
+
+	err = b.SetOrderbookDepth(nil)
+	if err != nil || b.GetOrderbookRequestDepth(btc, supported, 1000) != 100 {
+		t.Error("Test Failed - SetOrderbookDepth() depth not reset", err)
+	}
+}
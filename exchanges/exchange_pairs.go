@@ -0,0 +1,216 @@
+package exchange
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
+)
+
+// Currency pair format contexts
+const (
+	PairFormatRequest = "request"
+	PairFormatConfig  = "config"
+)
+
+// FormatPair formats a canonical currency pair for a context, request
+// formatted pairs use the currency codes of the exchange. Wrappers format
+// pairs sent to the exchange through it rather than formatting them by hand
+func (e *Base) FormatPair(p pair.CurrencyPair, context string) string {
+	if context == PairFormatRequest {
+		return formatPair(translation.PairToExchange(e.Name, p), e.RequestCurrencyPairFormat).String()
+	}
+	return formatPair(p, e.ConfigCurrencyPairFormat).String()
+}
+
+// ParsePair parses a currency pair formatted for a context, the inverse of
+// FormatPair. Request formatted pairs have their currency codes translated
+// to the canonical codes
+func (e *Base) ParsePair(s, context string) (pair.CurrencyPair, error) {
+	format := e.ConfigCurrencyPairFormat
+	if context == PairFormatRequest {
+		format = e.RequestCurrencyPairFormat
+	}
+
+	p, err := parsePair(s, format)
+	if err != nil {
+		return p, fmt.Errorf("%s %s", e.Name, err)
+	}
+
+	if context == PairFormatRequest {
+		p = translation.PairToCanonical(e.Name, p)
+	}
+	return p, nil
+}
+
+// FormatPairs formats canonical currency pairs for a context
+func (e *Base) FormatPairs(pairs []pair.CurrencyPair, context string) []string {
+	result := make([]string, len(pairs))
+	for x := range pairs {
+		result[x] = e.FormatPair(pairs[x], context)
+	}
+	return result
+}
+
+// formatPair formats a currency pair with the delimiter and case of a currency
+// pair format
+func formatPair(p pair.CurrencyPair, format config.CurrencyPairFormatConfig) pair.CurrencyItem {
+	return p.Display(format.Delimiter, format.Uppercase)
+}
+
+// parsePair parses a currency pair formatted with a currency pair format,
+// splitting it at the delimiter or index of the format or otherwise as a
+// concatenated pair. The index is matched regardless of case, as lowercase
+// formats lowercase the index too
+func parsePair(s string, format config.CurrencyPairFormatConfig) (pair.CurrencyPair, error) {
+	var p pair.CurrencyPair
+	switch {
+	case format.Delimiter != "":
+		if len(common.SplitStrings(s, format.Delimiter)) != 2 {
+			return p, fmt.Errorf("currency pair %s not delimited by %s", s, format.Delimiter)
+		}
+		p = pair.NewCurrencyPairDelimiter(s, format.Delimiter)
+	case format.Index != "":
+		i := strings.Index(common.StringToUpper(s), common.StringToUpper(format.Index))
+		if i < 0 {
+			return p, fmt.Errorf("currency pair %s does not contain index %s", s, format.Index)
+		}
+		if i == 0 {
+			i = len(format.Index)
+		}
+		p = pair.NewCurrencyPair(s[:i], s[i:])
+	default:
+		p = pair.NewCurrencyPairFromConcatenated(s)
+	}
+
+	if p.Empty() {
+		return p, fmt.Errorf("invalid currency pair %s", s)
+	}
+	return p, nil
+}
+
+// DiscoverPairs normalises the products returned by an exchange into the
+// config pair format, keeping their delimiters, translating currency codes to
+// their canonical codes and removing pairs excluded by the pair filter of the
+// exchange. Nothing is stored
+func (e *Base) DiscoverPairs(exchangeProducts []string) ([]string, error) {
+	if len(exchangeProducts) == 0 {
+		return nil, fmt.Errorf("%s UpdateCurrencies error - exchangeProducts is empty", e.Name)
+	}
+
+	var products []string
+	for x := range exchangeProducts {
+		product := common.StringToUpper(exchangeProducts[x])
+		if product == "" {
+			continue
+		}
+		products = append(products, product)
+	}
+	products = e.toCanonicalProducts(products)
+
+	exch, err := config.GetConfig().GetExchangeConfig(e.Name)
+	if err == nil && exch.PairFilter != nil {
+		filtered := exch.PairFilter.FilterPairs(products)
+		if len(filtered) != len(products) {
+			log.Printf("%s %d pairs excluded by pair filter.\n", e.Name,
+				len(products)-len(filtered))
+		}
+		products = filtered
+		if len(products) == 0 {
+			return nil, fmt.Errorf("%s UpdateCurrencies error - all pairs excluded by pair filter", e.Name)
+		}
+	}
+	return products, nil
+}
+
+// StorePairs stores discovered pairs as the enabled or available pairs of the
+// exchange when they differ from the stored pairs or force is set, returning
+// whether they were stored. The config is left untouched until PersistPairs
+// is called
+func (e *Base) StorePairs(products []string, enabled, force bool) bool {
+	stored := e.AvailablePairs
+	updateType := "available"
+	if enabled {
+		stored = e.EnabledPairs
+		updateType = "enabled"
+	}
+
+	newPairs, removedPairs := pair.FindPairDifferences(stored, products)
+	if !force && len(newPairs) == 0 && len(removedPairs) == 0 {
+		return false
+	}
+
+	if force {
+		log.Printf("%s forced update of %s pairs.", e.Name, updateType)
+	} else {
+		if len(newPairs) > 0 {
+			log.Printf("%s Updating pairs - New: %s.\n", e.Name, newPairs)
+		}
+		if len(removedPairs) > 0 {
+			log.Printf("%s Updating pairs - Removed: %s.\n", e.Name, removedPairs)
+		}
+	}
+
+	if enabled {
+		e.EnabledPairs = products
+	} else {
+		e.AvailablePairs = products
+	}
+	return true
+}
+
+// PersistPairs writes the stored enabled and available pairs of the exchange
+// to its config, empty pair lists leave the config pairs in place
+func (e *Base) PersistPairs() error {
+	cfg := config.GetConfig()
+	exch, err := cfg.GetExchangeConfig(e.Name)
+	if err != nil {
+		return err
+	}
+
+	if len(e.EnabledPairs) > 0 {
+		exch.EnabledPairs = common.JoinStrings(e.EnabledPairs, ",")
+	}
+	if len(e.AvailablePairs) > 0 {
+		exch.AvailablePairs = common.JoinStrings(e.AvailablePairs, ",")
+	}
+	return cfg.UpdateExchangeConfig(exch)
+}
+
+// AddNewListings adds the pairs listed on the exchange which aren't in its
+// available pairs, enabling those allowed by the enable filter and persisting
+// the pairs to the config. A nil filter enables none of the new pairs. The
+// new and newly enabled pairs are returned in the config pair format
+func (e *Base) AddNewListings(exchangeProducts []string, enable *config.PairFilterConfig) ([]string, []string, error) {
+	products, err := e.DiscoverPairs(exchangeProducts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newPairs, _ := pair.FindPairDifferences(e.AvailablePairs, products)
+	if len(newPairs) == 0 {
+		return nil, nil, nil
+	}
+
+	var enabledPairs []string
+	if enable != nil {
+		for x := range newPairs {
+			if enable.IsPairAllowed(newPairs[x]) &&
+				!common.StringDataCompareUpper(e.EnabledPairs, newPairs[x]) {
+				enabledPairs = append(enabledPairs, newPairs[x])
+			}
+		}
+	}
+
+	log.Printf("%s new listings: %s.\n", e.Name, newPairs)
+	e.AvailablePairs = append(e.AvailablePairs, newPairs...)
+	if len(enabledPairs) > 0 {
+		log.Printf("%s enabling new listings: %s.\n", e.Name, enabledPairs)
+		e.EnabledPairs = append(e.EnabledPairs, enabledPairs...)
+	}
+	return newPairs, enabledPairs, e.PersistPairs()
+}
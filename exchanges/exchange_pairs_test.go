@@ -0,0 +1,208 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestFormatPair(t *testing.T) {
+	b := Base{Name: "RAWR"}
+	b.RequestCurrencyPairFormat = config.CurrencyPairFormatConfig{Delimiter: "_"}
+	b.ConfigCurrencyPairFormat = config.CurrencyPairFormatConfig{Delimiter: "-", Uppercase: true}
+
+	p := pair.NewCurrencyPair("btc", "usd")
+	if b.FormatPair(p, PairFormatRequest) != "btc_usd" {
+		t.Error("Test Failed - FormatPair() unexpected request format", b.FormatPair(p, PairFormatRequest))
+	}
+
+	formatted := b.FormatPairs([]pair.CurrencyPair{p}, PairFormatConfig)
+	if len(formatted) != 1 || formatted[0] != "BTC-USD" {
+		t.Error("Test Failed - FormatPairs() unexpected config format", formatted)
+	}
+}
+
+func TestPairFormatRoundTrip(t *testing.T) {
+	pairs := []pair.CurrencyPair{
+		pair.NewCurrencyPair("BTC", "USD"),
+		pair.NewCurrencyPair("LTC", "BTC"),
+		pair.NewCurrencyPair("DOGE", "USDT"),
+		pair.NewCurrencyPair("BTC", "KRW"),
+	}
+
+	for _, delimiter := range []string{"", "-", "_", "/"} {
+		for _, index := range []string{"", "KRW"} {
+			for _, uppercase := range []bool{true, false} {
+				b := Base{Name: "RAWR"}
+				b.RequestCurrencyPairFormat = config.CurrencyPairFormatConfig{
+					Delimiter: delimiter, Uppercase: uppercase, Index: index}
+				b.ConfigCurrencyPairFormat = b.RequestCurrencyPairFormat
+
+				for _, context := range []string{PairFormatRequest, PairFormatConfig} {
+					for x := range pairs {
+						if delimiter == "" && index != "" && pairs[x].SecondCurrency != "KRW" {
+							continue
+						}
+
+						formatted := b.FormatPair(pairs[x], context)
+						parsed, err := b.ParsePair(formatted, context)
+						if err != nil || !parsed.Equal(pairs[x], false) {
+							t.Errorf("Test Failed - ParsePair() %s round trip of %s with format %+v returned %s %v",
+								context, pairs[x].Pair(), b.RequestCurrencyPairFormat, parsed.Pair(), err)
+							continue
+						}
+
+						if b.FormatPair(parsed, context) != formatted {
+							t.Errorf("Test Failed - FormatPair() %s round trip of %s returned %s",
+								context, formatted, b.FormatPair(parsed, context))
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPairFormatTranslation(t *testing.T) {
+	b := Base{Name: "Kraken"}
+	b.RequestCurrencyPairFormat = config.CurrencyPairFormatConfig{Uppercase: true}
+	b.ConfigCurrencyPairFormat = config.CurrencyPairFormatConfig{Delimiter: "-", Uppercase: true}
+	p := pair.NewCurrencyPair("BTC", "USD")
+
+	if b.FormatPair(p, PairFormatRequest) != "XBTUSD" || b.FormatPair(p, PairFormatConfig) != "BTC-USD" {
+		t.Error("Test Failed - FormatPair() unexpected translation", b.FormatPair(p, PairFormatRequest))
+	}
+
+	parsed, err := b.ParsePair("XBTUSD", PairFormatRequest)
+	if err != nil || parsed.Pair() != "BTCUSD" {
+		t.Errorf("Test Failed - ParsePair() unexpected request pair %s %v", parsed.Pair(), err)
+	}
+
+	parsed, err = b.ParsePair("XBT-USD", PairFormatConfig)
+	if err != nil || parsed.Pair() != "XBT-USD" {
+		t.Errorf("Test Failed - ParsePair() config pairs should not be translated %s %v", parsed.Pair(), err)
+	}
+}
+
+func TestParsePairInvalid(t *testing.T) {
+	b := Base{Name: "RAWR"}
+	b.RequestCurrencyPairFormat = config.CurrencyPairFormatConfig{Delimiter: "-"}
+	b.ConfigCurrencyPairFormat = config.CurrencyPairFormatConfig{Index: "KRW"}
+
+	for _, s := range []string{"", "BTCUSD", "BTC-USD-EUR", "-USD", "BTC-"} {
+		if _, err := b.ParsePair(s, PairFormatRequest); err == nil {
+			t.Errorf("Test Failed - ParsePair() expected an error parsing %q", s)
+		}
+	}
+
+	if _, err := b.ParsePair("BTCUSD", PairFormatConfig); err == nil {
+		t.Error("Test Failed - ParsePair() expected an error parsing a pair without the index")
+	}
+}
+
+func TestStorePairs(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatal("Test failed. TestStorePairs failed to load config")
+	}
+
+	b := Base{Name: "ANX"}
+	b.ConfigCurrencyPairFormat.Delimiter = "-"
+
+	_, err = b.DiscoverPairs(nil)
+	if err == nil {
+		t.Error("Test Failed - DiscoverPairs() expected empty products error")
+	}
+
+	products, err := b.DiscoverPairs([]string{"btc-usd", "", "ltc-btc"})
+	if err != nil {
+		t.Fatal("Test Failed - DiscoverPairs() error", err)
+	}
+
+	if len(products) != 2 || products[0] != "BTC-USD" || products[1] != "LTC-BTC" {
+		t.Fatal("Test Failed - DiscoverPairs() unexpected products", products)
+	}
+
+	if !b.StorePairs(products, false, false) {
+		t.Fatal("Test Failed - StorePairs() new pairs not stored")
+	}
+
+	if b.StorePairs(products, false, false) {
+		t.Error("Test Failed - StorePairs() unchanged pairs stored")
+	}
+
+	exch, err := cfg.GetExchangeConfig("ANX")
+	if err != nil {
+		t.Fatal("Test Failed - GetExchangeConfig() error", err)
+	}
+
+	if exch.AvailablePairs == "BTC-USD,LTC-BTC" {
+		t.Fatal("Test Failed - StorePairs() pairs persisted to config")
+	}
+
+	if !pair.Contains(b.GetAvailableCurrencies(), pair.NewCurrencyPair("LTC", "BTC"), true) {
+		t.Error("Test Failed - GetAvailableCurrencies() stored pair not found")
+	}
+
+	err = b.PersistPairs()
+	if err != nil {
+		t.Fatal("Test Failed - PersistPairs() error", err)
+	}
+
+	exch, _ = cfg.GetExchangeConfig("ANX")
+	if exch.AvailablePairs != "BTC-USD,LTC-BTC" || exch.EnabledPairs == "" {
+		t.Errorf("Test Failed - PersistPairs() unexpected config pairs %s %s",
+			exch.AvailablePairs, exch.EnabledPairs)
+	}
+
+	b.Name = "Blah"
+	err = b.PersistPairs()
+	if err == nil {
+		t.Error("Test Failed - PersistPairs() expected missing exchange error")
+	}
+}
+
+func TestAddNewListings(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatal("Test failed. TestAddNewListings failed to load config")
+	}
+
+	b := Base{Name: "ANX"}
+	b.ConfigCurrencyPairFormat.Delimiter = "-"
+	b.ConfigCurrencyPairFormat.Uppercase = true
+	b.AvailablePairs = []string{"BTC-USD"}
+	b.EnabledPairs = []string{"BTC-USD"}
+
+	newPairs, enabledPairs, err := b.AddNewListings([]string{"btc-usd", "ltc-usd"}, nil)
+	if err != nil {
+		t.Fatal("Test Failed - AddNewListings() error", err)
+	}
+
+	if len(newPairs) != 1 || newPairs[0] != "LTC-USD" || len(enabledPairs) != 0 {
+		t.Errorf("Test Failed - AddNewListings() unexpected pairs %s %s", newPairs, enabledPairs)
+	}
+
+	newPairs, enabledPairs, err = b.AddNewListings([]string{"btc-usd", "ltc-usd", "eth-usd", "doge-usd"},
+		&config.PairFilterConfig{Include: []string{"*-USD"}, Exclude: []string{"DOGE*"}})
+	if err != nil {
+		t.Fatal("Test Failed - AddNewListings() error", err)
+	}
+
+	if len(newPairs) != 2 || len(enabledPairs) != 1 || enabledPairs[0] != "ETH-USD" {
+		t.Errorf("Test Failed - AddNewListings() unexpected pairs %s %s", newPairs, enabledPairs)
+	}
+
+	exch, _ := cfg.GetExchangeConfig("ANX")
+	if exch.EnabledPairs != "BTC-USD,ETH-USD" {
+		t.Error("Test Failed - AddNewListings() enabled pairs not persisted", exch.EnabledPairs)
+	}
+
+	newPairs, _, err = b.AddNewListings([]string{"eth-usd"}, nil)
+	if err != nil || len(newPairs) != 0 {
+		t.Error("Test Failed - AddNewListings() unexpected new pairs", newPairs, err)
+	}
+}
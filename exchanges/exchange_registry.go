@@ -0,0 +1,82 @@
+package exchange
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// ErrExchangeNotRegistered is returned when no exchange has been registered
+// under a name
+var ErrExchangeNotRegistered = errors.New("exchange not registered")
+
+// Factory returns a new exchange wrapper, before its defaults are set
+type Factory func() IBotExchange
+
+// registeredExchange holds the name an exchange was registered under and its
+// factory
+type registeredExchange struct {
+	name    string
+	factory Factory
+}
+
+var (
+	registry    = make(map[string]registeredExchange)
+	registryMtx sync.RWMutex
+)
+
+// Register registers the factory of an exchange under its name, matched
+// without regard to case. Exchange packages register themselves from an init
+// func, so importing a package, including a third party one, makes the
+// exchange available to the bot. It panics when the name is empty, the
+// factory nil or the name already registered
+func Register(name string, factory Factory) {
+	if name == "" || factory == nil {
+		panic("exchange: Register requires a name and factory")
+	}
+
+	key := common.StringToLower(name)
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+	if _, ok := registry[key]; ok {
+		panic("exchange: Register called twice for exchange " + name)
+	}
+	registry[key] = registeredExchange{name: name, factory: factory}
+}
+
+// NewExchangeByName returns a new exchange wrapper of a registered exchange
+func NewExchangeByName(name string) (IBotExchange, error) {
+	registryMtx.RLock()
+	r, ok := registry[common.StringToLower(name)]
+	registryMtx.RUnlock()
+	if !ok {
+		return nil, ErrExchangeNotRegistered
+	}
+	return r.factory(), nil
+}
+
+// IsRegistered returns whether an exchange has been registered under a name
+func IsRegistered(name string) bool {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	_, ok := registry[common.StringToLower(name)]
+	return ok
+}
+
+// RegisteredExchanges returns the names of the registered exchanges in
+// alphabetical order
+func RegisteredExchanges() []string {
+	registryMtx.RLock()
+	names := make([]string, 0, len(registry))
+	for _, r := range registry {
+		names = append(names, r.name)
+	}
+	registryMtx.RUnlock()
+
+	sort.Slice(names, func(i, j int) bool {
+		return common.StringToLower(names[i]) < common.StringToLower(names[j])
+	})
+	return names
+}
@@ -0,0 +1,38 @@
+package exchange
+
+import (
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	created := 0
+	Register("Registry Test", func() IBotExchange {
+		created++
+		return nil
+	})
+
+	if !IsRegistered("registry test") || IsRegistered("registry tests") {
+		t.Error("Test Failed - IsRegistered() unexpected result")
+	}
+
+	if _, err := NewExchangeByName("REGISTRY TEST"); err != nil || created != 1 {
+		t.Errorf("Test Failed - NewExchangeByName() error %v, created %d", err, created)
+	}
+
+	if _, err := NewExchangeByName("registry tests"); err != ErrExchangeNotRegistered {
+		t.Errorf("Test Failed - NewExchangeByName() expected %s, got %v", ErrExchangeNotRegistered, err)
+	}
+
+	Register("Another Registry Test", func() IBotExchange { return nil })
+	names := RegisteredExchanges()
+	if len(names) != 2 || names[0] != "Another Registry Test" || names[1] != "Registry Test" {
+		t.Errorf("Test Failed - RegisteredExchanges() unexpected names %v", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Test Failed - Register() didn't panic registering a name twice")
+		}
+	}()
+	Register("REGISTRY TEST", func() IBotExchange { return nil })
+}
@@ -0,0 +1,238 @@
+package exchange
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strings"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Request signing algorithms
+const (
+	SignHMACSHA256 = "HMAC-SHA256"
+	SignHMACSHA384 = "HMAC-SHA384"
+	SignHMACSHA512 = "HMAC-SHA512"
+	SignRSASHA256  = "RSA-SHA256"
+	SignEd25519    = "ED25519"
+)
+
+// Signature encodings
+const (
+	SignatureEncodingHex      = "hex"
+	SignatureEncodingHexUpper = "HEX"
+	SignatureEncodingBase64   = "base64"
+)
+
+// Vars for request signing
+var (
+	// ErrUnsupportedSigningAlgorithm is returned when creating a signer for an
+	// unknown algorithm
+	ErrUnsupportedSigningAlgorithm = errors.New("unsupported signing algorithm")
+	// ErrUnsupportedSignatureEncoding is returned when creating a signer for
+	// an unknown signature encoding
+	ErrUnsupportedSignatureEncoding = errors.New("unsupported signature encoding")
+	// ErrSigningKeyRequired is returned when creating a signer without a key
+	ErrSigningKeyRequired = errors.New("signing key required")
+)
+
+// Signer signs authenticated request payloads with an exchange API secret.
+// HMAC algorithms use the secret as is, RSA expects a PEM encoded PKCS1 or
+// PKCS8 private key and Ed25519 a PEM encoded PKCS8 private key or a base64
+// encoded seed
+type Signer struct {
+	Algorithm string
+	Encoding  string
+
+	secret     []byte
+	rsaKey     *rsa.PrivateKey
+	ed25519Key ed25519.PrivateKey
+}
+
+// NewSigner returns a signer for the algorithm, encoding signatures with the
+// signature encoding
+func NewSigner(algorithm, encoding, secret string) (*Signer, error) {
+	if secret == "" {
+		return nil, ErrSigningKeyRequired
+	}
+
+	switch encoding {
+	case SignatureEncodingHex, SignatureEncodingHexUpper, SignatureEncodingBase64:
+	default:
+		return nil, ErrUnsupportedSignatureEncoding
+	}
+
+	s := &Signer{Algorithm: algorithm, Encoding: encoding}
+	switch algorithm {
+	case SignHMACSHA256, SignHMACSHA384, SignHMACSHA512:
+		s.secret = []byte(secret)
+	case SignRSASHA256:
+		key, err := parseRSAPrivateKey(secret)
+		if err != nil {
+			return nil, err
+		}
+		s.rsaKey = key
+	case SignEd25519:
+		key, err := parseEd25519PrivateKey(secret)
+		if err != nil {
+			return nil, err
+		}
+		s.ed25519Key = key
+	default:
+		return nil, ErrUnsupportedSigningAlgorithm
+	}
+	return s, nil
+}
+
+func parseRSAPrivateKey(secret string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(secret))
+	if block == nil {
+		return nil, errors.New("RSA signing key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse RSA signing key: %s", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("signing key is not an RSA private key")
+	}
+	return key, nil
+}
+
+func parseEd25519PrivateKey(secret string) (ed25519.PrivateKey, error) {
+	if block, _ := pem.Decode([]byte(secret)); block != nil {
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse Ed25519 signing key: %s", err)
+		}
+
+		key, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("signing key is not an Ed25519 private key")
+		}
+		return key, nil
+	}
+
+	seed, err := common.Base64Decode(secret)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, errors.New("Ed25519 signing key must be PEM encoded or a base64 encoded seed")
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+func (s *Signer) hmacHash() func() hash.Hash {
+	switch s.Algorithm {
+	case SignHMACSHA384:
+		return sha512.New384
+	case SignHMACSHA512:
+		return sha512.New
+	default:
+		return sha256.New
+	}
+}
+
+// Sign returns the raw signature of the payload
+func (s *Signer) Sign(payload []byte) ([]byte, error) {
+	switch s.Algorithm {
+	case SignHMACSHA256, SignHMACSHA384, SignHMACSHA512:
+		mac := hmac.New(s.hmacHash(), s.secret)
+		mac.Write(payload)
+		return mac.Sum(nil), nil
+	case SignRSASHA256:
+		digest := sha256.Sum256(payload)
+		return rsa.SignPKCS1v15(rand.Reader, s.rsaKey, crypto.SHA256, digest[:])
+	case SignEd25519:
+		return ed25519.Sign(s.ed25519Key, payload), nil
+	}
+	return nil, ErrUnsupportedSigningAlgorithm
+}
+
+// SignToString returns the signature of the payload in the signature
+// encoding of the signer
+func (s *Signer) SignToString(payload []byte) (string, error) {
+	signature, err := s.Sign(payload)
+	if err != nil {
+		return "", err
+	}
+	return s.encode(signature), nil
+}
+
+func (s *Signer) encode(signature []byte) string {
+	switch s.Encoding {
+	case SignatureEncodingHexUpper:
+		return common.StringToUpper(common.HexEncodeToString(signature))
+	case SignatureEncodingBase64:
+		return common.Base64Encode(signature)
+	default:
+		return common.HexEncodeToString(signature)
+	}
+}
+
+func (s *Signer) decode(signature string) ([]byte, error) {
+	if s.Encoding == SignatureEncodingBase64 {
+		return common.Base64Decode(signature)
+	}
+	return hex.DecodeString(signature)
+}
+
+// Verify returns whether an encoded signature matches the payload, comparing
+// HMAC signatures in constant time
+func (s *Signer) Verify(payload []byte, signature string) bool {
+	decoded, err := s.decode(signature)
+	if err != nil {
+		return false
+	}
+
+	switch s.Algorithm {
+	case SignHMACSHA256, SignHMACSHA384, SignHMACSHA512:
+		expected, _ := s.Sign(payload)
+		return hmac.Equal(expected, decoded)
+	case SignRSASHA256:
+		digest := sha256.Sum256(payload)
+		return rsa.VerifyPKCS1v15(&s.rsaKey.PublicKey, crypto.SHA256, digest[:], decoded) == nil
+	case SignEd25519:
+		return ed25519.Verify(s.ed25519Key.Public().(ed25519.PublicKey), payload, decoded)
+	}
+	return false
+}
+
+// SignaturesEqual compares two encoded signatures in constant time
+func SignaturesEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// CanonicalQuery returns url values encoded with their keys sorted, for
+// exchanges which sign the query string
+func CanonicalQuery(values url.Values) string {
+	return values.Encode()
+}
+
+// CanonicalJSON returns a request payload JSON encoded with its keys sorted
+func CanonicalJSON(payload map[string]interface{}) ([]byte, error) {
+	return common.JSONEncode(payload)
+}
+
+// CanonicalMessage joins the parts of a message to sign with a separator
+func CanonicalMessage(separator string, parts ...string) []byte {
+	return []byte(strings.Join(parts, separator))
+}
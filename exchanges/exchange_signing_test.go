@@ -0,0 +1,108 @@
+package exchange
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+func TestSigner(t *testing.T) {
+	_, err := NewSigner(SignHMACSHA256, SignatureEncodingHex, "")
+	if err != ErrSigningKeyRequired {
+		t.Error("Test Failed - NewSigner() key required error", err)
+	}
+
+	_, err = NewSigner("MD5", SignatureEncodingHex, "Jefe")
+	if err != ErrUnsupportedSigningAlgorithm {
+		t.Error("Test Failed - NewSigner() unsupported algorithm error", err)
+	}
+
+	_, err = NewSigner(SignHMACSHA256, "base32", "Jefe")
+	if err != ErrUnsupportedSignatureEncoding {
+		t.Error("Test Failed - NewSigner() unsupported encoding error", err)
+	}
+
+	payload := []byte("what do ya want for nothing?")
+	signer, err := NewSigner(SignHMACSHA256, SignatureEncodingHex, "Jefe")
+	if err != nil {
+		t.Fatal("Test Failed - NewSigner() error", err)
+	}
+
+	signature, err := signer.SignToString(payload)
+	expected := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if err != nil || signature != expected {
+		t.Errorf("Test Failed - SignToString() expected %s, got %s %v", expected, signature, err)
+	}
+
+	if !signer.Verify(payload, signature) || signer.Verify([]byte("rawr"), signature) {
+		t.Error("Test Failed - Verify() HMAC signature")
+	}
+
+	signer.Encoding = SignatureEncodingHexUpper
+	signature, _ = signer.SignToString(payload)
+	if signature != common.StringToUpper(expected) || !signer.Verify(payload, signature) {
+		t.Error("Test Failed - SignToString() upper case hex signature", signature)
+	}
+
+	if !SignaturesEqual(signature, common.StringToUpper(expected)) || SignaturesEqual(signature, expected) {
+		t.Error("Test Failed - SignaturesEqual() unexpected result")
+	}
+}
+
+func TestSignerAsymmetric(t *testing.T) {
+	payload := CanonicalMessage(":", "POST", "/v1/order", "1337")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal("Test Failed - rsa.GenerateKey() error", err)
+	}
+	rsaPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	signer, err := NewSigner(SignRSASHA256, SignatureEncodingBase64, string(rsaPEM))
+	if err != nil {
+		t.Fatal("Test Failed - NewSigner() RSA error", err)
+	}
+
+	signature, err := signer.SignToString(payload)
+	if err != nil || !signer.Verify(payload, signature) {
+		t.Error("Test Failed - SignToString() RSA signature", err)
+	}
+
+	seed := make([]byte, 32)
+	signer, err = NewSigner(SignEd25519, SignatureEncodingBase64, common.Base64Encode(seed))
+	if err != nil {
+		t.Fatal("Test Failed - NewSigner() Ed25519 error", err)
+	}
+
+	signature, err = signer.SignToString(payload)
+	if err != nil || !signer.Verify(payload, signature) || signer.Verify([]byte("rawr"), signature) {
+		t.Error("Test Failed - SignToString() Ed25519 signature", err)
+	}
+
+	_, err = NewSigner(SignEd25519, SignatureEncodingBase64, "rawr")
+	if err == nil {
+		t.Error("Test Failed - NewSigner() expected invalid Ed25519 key error")
+	}
+}
+
+func TestCanonicalPayloads(t *testing.T) {
+	values := url.Values{}
+	values.Set("nonce", "2")
+	values.Set("amount", "1")
+	if CanonicalQuery(values) != "amount=1&nonce=2" {
+		t.Error("Test Failed - CanonicalQuery() unexpected result", CanonicalQuery(values))
+	}
+
+	payload, err := CanonicalJSON(map[string]interface{}{"nonce": 2, "amount": 1})
+	if err != nil || string(payload) != `{"amount":1,"nonce":2}` {
+		t.Errorf("Test Failed - CanonicalJSON() unexpected result %s %v", payload, err)
+	}
+}
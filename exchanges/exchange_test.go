@@ -1,6 +1,7 @@
 package exchange
 
 import (
+	"crypto/tls"
 	"net/http"
 	"testing"
 	"time"
@@ -94,6 +95,60 @@ func TestSetClientProxyAddress(t *testing.T) {
 	}
 }
 
+func TestSetHTTPClientTransport(t *testing.T) {
+	requester := request.New("testicles",
+		&request.RateLimit{},
+		&request.RateLimit{},
+		&http.Client{})
+
+	newBase := Base{Name: "Testicles", Requester: requester}
+
+	err := newBase.SetHTTPClientTransport(nil)
+	if err != nil || newBase.GetHTTPClient().Transport != nil {
+		t.Error("Test failed. SetHTTPClientTransport nil config altered transport")
+	}
+
+	err = newBase.SetHTTPClientTransport(&config.HTTPTransportConfig{TLSMinVersion: "0.9"})
+	if err == nil {
+		t.Error("Test failed. SetHTTPClientTransport accepted invalid TLS version")
+	}
+
+	err = newBase.SetHTTPClientTransport(&config.HTTPTransportConfig{
+		MaxIdleConnsPerHost: 10,
+		TLSMinVersion:       "1.2",
+		DisableHTTP2:        true,
+		RecordTimings:       true,
+	})
+	if err != nil {
+		t.Fatal("Test failed. SetHTTPClientTransport error", err)
+	}
+
+	transport, ok := newBase.GetHTTPClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatal("Test failed. SetHTTPClientTransport transport not set")
+	}
+
+	if transport.MaxIdleConnsPerHost != 10 ||
+		transport.TLSClientConfig.MinVersion != tls.VersionTLS12 ||
+		transport.TLSNextProto == nil {
+		t.Error("Test failed. SetHTTPClientTransport transport values not applied")
+	}
+
+	if !newBase.IsConnectionStatsEnabled() {
+		t.Error("Test failed. SetHTTPClientTransport timings not enabled")
+	}
+
+	err = newBase.SetClientProxyAddress("http://www.valid.com")
+	if err != nil {
+		t.Error("Test failed. SetClientProxyAddress error", err)
+	}
+
+	if newBase.GetHTTPClient().Transport != transport ||
+		transport.MaxIdleConnsPerHost != 10 {
+		t.Error("Test failed. SetClientProxyAddress replaced tuned transport")
+	}
+}
+
 func TestSetAutoPairDefaults(t *testing.T) {
 	cfg := config.GetConfig()
 	err := cfg.LoadConfig(config.ConfigTestFile)
@@ -621,6 +676,44 @@ func TestFormatExchangeCurrency(t *testing.T) {
 	}
 }
 
+func TestFormatExchangeCurrencyTranslation(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Failed to load config file. Error: %s", err)
+	}
+
+	actual := FormatExchangeCurrency("Kraken", pair.NewCurrencyPair("BTC", "USD"))
+	if actual.String() != "XBTUSD" {
+		t.Errorf("Test failed - Exchange FormatExchangeCurrency %s != XBTUSD", actual)
+	}
+}
+
+func TestToCanonicalProducts(t *testing.T) {
+	b := Base{Name: "Kraken"}
+	b.ConfigCurrencyPairFormat = config.CurrencyPairFormatConfig{Delimiter: "-", Uppercase: true}
+	products := b.toCanonicalProducts([]string{"XBT-USD", "XDG-XBT", "ETH-EUR", "XBT"})
+	expected := []string{"BTC-USD", "DOGE-BTC", "ETH-EUR", "XBT"}
+	for x := range expected {
+		if products[x] != expected[x] {
+			t.Errorf("Test failed - toCanonicalProducts() %s != %s", products[x], expected[x])
+		}
+	}
+
+	b.EnabledPairs = []string{"XBT-USD"}
+	if p := b.GetEnabledCurrencies(); p[0].Pair() != "BTC-USD" {
+		t.Errorf("Test failed - GetEnabledCurrencies() untranslated pair %s", p[0].Pair())
+	}
+
+	// Known four character codes are parsed without a delimiter
+	b = Base{Name: "Bitfinex"}
+	b.ConfigCurrencyPairFormat = config.CurrencyPairFormatConfig{Uppercase: true}
+	products = b.toCanonicalProducts([]string{"DSHUSD", "BTCUSD"})
+	if products[0] != "DASHUSD" || products[1] != "BTCUSD" {
+		t.Errorf("Test failed - toCanonicalProducts() unexpected products %v", products)
+	}
+}
+
 func TestFormatCurrency(t *testing.T) {
 	cfg := config.GetConfig()
 	err := cfg.LoadConfig(config.ConfigTestFile)
@@ -802,6 +895,46 @@ func TestUpdateCurrencies(t *testing.T) {
 	}
 }
 
+func TestUpdateCurrenciesPairFilter(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatal("Test failed. TestUpdateCurrenciesPairFilter failed to load config")
+	}
+
+	exch, err := cfg.GetExchangeConfig("ANX")
+	if err != nil {
+		t.Fatal("Test failed. TestUpdateCurrenciesPairFilter failed to get exchange config")
+	}
+
+	exch.PairFilter = &config.PairFilterConfig{Exclude: []string{"*DOGE*"}}
+	err = cfg.UpdateExchangeConfig(exch)
+	if err != nil {
+		t.Fatal("Test failed. TestUpdateCurrenciesPairFilter failed to update exchange config")
+	}
+
+	defer func() {
+		exch.PairFilter = nil
+		cfg.UpdateExchangeConfig(exch)
+	}()
+
+	UAC := Base{Name: "ANX"}
+	err = UAC.UpdateCurrencies([]string{"BTCUSD", "DOGEBTC", "LTCDOGE"}, false, true)
+	if err != nil {
+		t.Errorf("Test Failed - Exchange UpdateCurrencies() error: %s", err)
+	}
+
+	if len(UAC.AvailablePairs) != 1 || UAC.AvailablePairs[0] != "BTCUSD" {
+		t.Errorf("Test Failed - Exchange UpdateCurrencies() pairs not filtered: %s",
+			UAC.AvailablePairs)
+	}
+
+	err = UAC.UpdateCurrencies([]string{"DOGEBTC"}, false, true)
+	if err == nil {
+		t.Error("Test Failed - Exchange UpdateCurrencies() all pairs excluded returned no error")
+	}
+}
+
 func TestAPIURL(t *testing.T) {
 	testURL := "https://api.something.com"
 	testURLSecondary := "https://api.somethingelse.com"
@@ -890,3 +1023,215 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 	}
 
 }
+
+func TestGetWithdrawalMethods(t *testing.T) {
+	UAC := Base{Name: "ANX"}
+	if len(UAC.GetWithdrawalMethods()) != 0 {
+		t.Error("Test failed. GetWithdrawalMethods returned methods with no permissions set")
+	}
+
+	UAC.APIWithdrawPermissions = AutoWithdrawCrypto | WithdrawFiatWith2FA | (1 << 31)
+	methods := UAC.GetWithdrawalMethods()
+	if len(methods) != 3 {
+		t.Fatalf("Test failed. GetWithdrawalMethods expected 3 methods received %d",
+			len(methods))
+	}
+
+	if methods[0].Permission != AutoWithdrawCrypto ||
+		methods[0].Asset != WithdrawalAssetCrypto ||
+		methods[0].Automation != WithdrawalAutomationAuto {
+		t.Error("Test failed. GetWithdrawalMethods incorrect crypto method", methods[0])
+	}
+
+	if methods[1].Asset != WithdrawalAssetFiat ||
+		methods[1].Automation != WithdrawalAutomationManual ||
+		methods[1].Requirement != WithdrawalRequirement2FA {
+		t.Error("Test failed. GetWithdrawalMethods incorrect fiat method", methods[1])
+	}
+
+	if methods[2].Asset != UnknownWithdrawalType {
+		t.Error("Test failed. GetWithdrawalMethods incorrect unknown method", methods[2])
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	b := Base{Name: "RAWR"}
+	if b.GetClockSkew() != 0 {
+		t.Error("Test failed - GetClockSkew() default value incorrect")
+	}
+
+	skew, err := CalculateClockSkew(func() (time.Time, error) {
+		return time.Now().Add(time.Hour), nil
+	})
+	if err != nil {
+		t.Fatal("Test failed - CalculateClockSkew() error", err)
+	}
+
+	if skew < time.Hour-time.Second || skew > time.Hour+time.Second {
+		t.Errorf("Test failed - CalculateClockSkew() expected ~1h received %v",
+			skew)
+	}
+
+	b.SetClockSkew(skew)
+	if b.GetClockSkew() != skew {
+		t.Error("Test failed - SetClockSkew() value not set")
+	}
+
+	if time.Until(b.GetAdjustedTime()) < time.Hour-time.Second {
+		t.Error("Test failed - GetAdjustedTime() skew not applied")
+	}
+
+	_, err = CalculateClockSkew(func() (time.Time, error) {
+		return time.Time{}, nil
+	})
+	if err == nil {
+		t.Error("Test failed - CalculateClockSkew() empty server time accepted")
+	}
+
+	if _, err = b.GetHTTPClockSkew(); err != request.ErrNoServerDate {
+		t.Error("Test failed - GetHTTPClockSkew() expected no server date error", err)
+	}
+}
+
+func TestEstimateFee(t *testing.T) {
+	b := Base{Name: "RAWR"}
+	feeBuilder := FeeBuilder{
+		FeeType:       CryptocurrencyTradeFee,
+		PurchasePrice: 100,
+		Amount:        2,
+	}
+
+	if b.IsFeeEstimated(feeBuilder) {
+		t.Error("Test Failed - IsFeeEstimated() estimated without a fee table")
+	}
+
+	_, err := b.EstimateFee(feeBuilder)
+	if err != ErrNoFeeEstimate {
+		t.Error("Test Failed - EstimateFee() expected no estimate error", err)
+	}
+
+	b.SetFeeTable(FeeTable{
+		Maker:      0.001,
+		Taker:      0.002,
+		Withdrawal: map[string]float64{"BTC": 0.0005},
+	})
+
+	if !b.IsFeeEstimated(feeBuilder) {
+		t.Error("Test Failed - IsFeeEstimated() trade fee not estimated")
+	}
+
+	fee, err := b.EstimateFee(feeBuilder)
+	if err != nil || fee != 0.4 {
+		t.Error("Test Failed - EstimateFee() unexpected taker fee", fee, err)
+	}
+
+	feeBuilder.IsMaker = true
+	fee, err = b.EstimateFee(feeBuilder)
+	if err != nil || fee != 0.2 {
+		t.Error("Test Failed - EstimateFee() unexpected maker fee", fee, err)
+	}
+
+	withdrawal := FeeBuilder{FeeType: CryptocurrencyWithdrawalFee, FirstCurrency: "btc"}
+	if !b.IsFeeEstimated(withdrawal) {
+		t.Error("Test Failed - IsFeeEstimated() withdrawal fee not estimated")
+	}
+
+	fee, err = b.EstimateFee(withdrawal)
+	if err != nil || fee != 0.0005 {
+		t.Error("Test Failed - EstimateFee() unexpected withdrawal fee", fee, err)
+	}
+
+	withdrawal.FirstCurrency = "LTC"
+	if b.IsFeeEstimated(withdrawal) {
+		t.Error("Test Failed - IsFeeEstimated() estimated withdrawal fee missing from table")
+	}
+
+	_, err = b.EstimateFee(withdrawal)
+	if err != ErrNoFeeEstimate {
+		t.Error("Test Failed - EstimateFee() expected no estimate error", err)
+	}
+
+	b.AuthenticatedAPISupport = true
+	if b.IsFeeEstimated(feeBuilder) {
+		t.Error("Test Failed - IsFeeEstimated() estimated with API credentials")
+	}
+}
+
+func TestFeeDiscount(t *testing.T) {
+	b := Base{Name: "RAWR"}
+	feeBuilder := FeeBuilder{FeeType: CryptocurrencyTradeFee}
+
+	if b.DiscountTradingFee(feeBuilder, 1) != 1 {
+		t.Error("Test Failed - DiscountTradingFee() discounted fee without discount")
+	}
+
+	_, err := b.DepleteFeeToken(1)
+	if err != ErrFeeDiscountNotConfigured {
+		t.Error("Test Failed - DepleteFeeToken() expected not configured error", err)
+	}
+
+	b.SetFeeDiscount(config.FeeDiscountConfig{Token: "bnb", Rate: 0.25, Enabled: true})
+	b.SetFeeTokenBalance(2)
+
+	discount, err := b.GetFeeDiscount()
+	if err != nil || discount.Token != "BNB" || discount.Balance != 2 {
+		t.Errorf("Test Failed - GetFeeDiscount() unexpected discount %+v %v", discount, err)
+	}
+
+	if b.DiscountTradingFee(feeBuilder, 1) != 0.75 {
+		t.Error("Test Failed - DiscountTradingFee() discount not applied")
+	}
+
+	if b.DiscountTradingFee(FeeBuilder{FeeType: CryptocurrencyWithdrawalFee}, 1) != 1 {
+		t.Error("Test Failed - DiscountTradingFee() discounted withdrawal fee")
+	}
+
+	feeBuilder.FeeTokenPrice = 0.5
+	if b.DiscountTradingFee(feeBuilder, 1) != 0.75 {
+		t.Error("Test Failed - DiscountTradingFee() discount not applied with sufficient balance")
+	}
+
+	remaining, err := b.DepleteFeeToken(1.5)
+	if err != nil || remaining != 0.5 {
+		t.Error("Test Failed - DepleteFeeToken() unexpected balance", remaining, err)
+	}
+
+	if b.DiscountTradingFee(feeBuilder, 1) != 1 {
+		t.Error("Test Failed - DiscountTradingFee() discount applied with insufficient balance")
+	}
+
+	b.SetFeeDiscount(config.FeeDiscountConfig{Token: "BNB", Rate: 0.25})
+	discount, _ = b.GetFeeDiscount()
+	if discount.Balance != 0.5 || b.DiscountTradingFee(FeeBuilder{FeeType: CryptocurrencyTradeFee}, 1) != 1 {
+		t.Error("Test Failed - SetFeeDiscount() balance not kept or disabled discount applied")
+	}
+}
+
+func TestAccountCurrencyInfo(t *testing.T) {
+	info := NewAccountCurrencyInfo("BTC", 1, 0.5)
+	if info.Free != 1 || info.Locked != 0.5 || info.Total != 1.5 {
+		t.Errorf("Test failed. NewAccountCurrencyInfo() unexpected balance %+v", info)
+	}
+
+	info.AddWallet("margin", 2, 1)
+	if info.Free != 3 || info.Locked != 1.5 || info.Total != 4.5 || len(info.Wallets) != 1 ||
+		info.Wallets[0].Wallet != "margin" || info.Wallets[0].Total != 3 {
+		t.Errorf("Test failed. AddWallet() unexpected balance %+v", info)
+	}
+}
+
+func TestSetBrokerID(t *testing.T) {
+	var b Base
+	if err := b.SetBrokerID("GCT"); err != ErrBrokerIDNotSupported {
+		t.Error("Test failed. SetBrokerID() unsupported exchange error", err)
+	}
+
+	if err := b.SetBrokerID(""); err != nil {
+		t.Error("Test failed. SetBrokerID() blank broker ID error", err)
+	}
+
+	b.SupportsBrokerID = true
+	if err := b.SetBrokerID("GCT"); err != nil || b.GetBrokerID() != "GCT" {
+		t.Error("Test failed. SetBrokerID() unexpected broker ID", b.GetBrokerID(), err)
+	}
+}
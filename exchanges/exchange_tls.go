@@ -0,0 +1,130 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// ErrTLSClientCertificate is returned when a TLS config holds a client
+// certificate without its key or a key without its certificate
+var ErrTLSClientCertificate = errors.New("TLS client certificate and key must be set together")
+
+// NewTLSConfig returns the TLS client config of an exchange TLS config,
+// loading its client certificate and adding its CA bundle to the system
+// roots. A nil config returns nil, leaving the Go defaults in place
+func NewTLSConfig(cfg *config.TLSConfig) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	if (cfg.ClientCertFile == "") != (cfg.ClientKeyFile == "") {
+		return nil, ErrTLSClientCertificate
+	}
+
+	tlsConfig := &tls.Config{ServerName: cfg.ServerName}
+	if cfg.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load TLS client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.CAFile != "" {
+		bundle, err := ioutil.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read TLS CA bundle: %s", err)
+		}
+
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in TLS CA bundle %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	return tlsConfig, nil
+}
+
+// SetTLSConfig applies a TLS config to the HTTP client and websocket of the
+// exchange, keeping the minimum TLS version of its HTTP transport. Websocket
+// connections use it from their next connection. A nil config restores the Go
+// defaults
+func (e *Base) SetTLSConfig(cfg *config.TLSConfig) error {
+	tlsConfig, err := NewTLSConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("exchange.go - %s setting TLS config error %s", e.Name, err)
+	}
+	if tlsConfig == nil {
+		if e.GetTLSConfig() == nil {
+			return nil
+		}
+		// Clears the TLS config previously applied
+		tlsConfig = &tls.Config{}
+	}
+
+	client := e.GetHTTPClient()
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		if client.Transport != nil {
+			return fmt.Errorf("exchange.go - %s setting TLS config error unsupported HTTP transport %T",
+				e.Name, client.Transport)
+		}
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		client.Transport = transport
+	}
+
+	if transport.TLSClientConfig != nil {
+		tlsConfig.MinVersion = transport.TLSClientConfig.MinVersion
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg == nil {
+		tlsConfig = nil
+	}
+	e.tlsConfigMtx.Lock()
+	e.tlsConfig = tlsConfig
+	e.tlsConfigMtx.Unlock()
+
+	if e.Websocket != nil {
+		e.Websocket.SetTLSConfig(tlsConfig)
+	}
+	return nil
+}
+
+// GetTLSConfig returns a copy of the TLS config of the exchange, nil when the
+// Go defaults are used
+func (e *Base) GetTLSConfig() *tls.Config {
+	e.tlsConfigMtx.Lock()
+	defer e.tlsConfigMtx.Unlock()
+	if e.tlsConfig == nil {
+		return nil
+	}
+	return e.tlsConfig.Clone()
+}
+
+// SetTLSConfig sets the TLS config websocket dialers are configured with
+func (w *Websocket) SetTLSConfig(tlsConfig *tls.Config) {
+	w.tlsConfigMtx.Lock()
+	w.tlsConfig = tlsConfig
+	w.tlsConfigMtx.Unlock()
+}
+
+// GetTLSConfig returns a copy of the TLS config of the websocket, nil when
+// the Go defaults are used
+func (w *Websocket) GetTLSConfig() *tls.Config {
+	w.tlsConfigMtx.Lock()
+	defer w.tlsConfigMtx.Unlock()
+	if w.tlsConfig == nil {
+		return nil
+	}
+	return w.tlsConfig.Clone()
+}
@@ -0,0 +1,179 @@
+package exchange
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// writeTestCertificate writes a self signed certificate valid for localhost
+// and its key to dir, returning their paths
+func writeTestCertificate(t *testing.T, dir, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("Test Failed - unable to generate key", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal("Test Failed - unable to create certificate", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal("Test Failed - unable to marshal key", err)
+	}
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal("Test Failed - unable to write certificate", err)
+	}
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	if err != nil {
+		t.Fatal("Test Failed - unable to write key", err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gct-tls")
+	if err != nil {
+		t.Fatal("Test Failed - unable to create temp dir", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeTestCertificate(t, dir, "client")
+
+	if tlsConfig, err := NewTLSConfig(nil); tlsConfig != nil || err != nil {
+		t.Errorf("Test Failed - NewTLSConfig() expected no config, got %v %v", tlsConfig, err)
+	}
+
+	if _, err = NewTLSConfig(&config.TLSConfig{ClientCertFile: certFile}); err != ErrTLSClientCertificate {
+		t.Errorf("Test Failed - NewTLSConfig() expected %s, got %v", ErrTLSClientCertificate, err)
+	}
+
+	if _, err = NewTLSConfig(&config.TLSConfig{CAFile: filepath.Join(dir, "missing.crt")}); err == nil {
+		t.Error("Test Failed - NewTLSConfig() expected an error reading a missing CA bundle")
+	}
+
+	if _, err = NewTLSConfig(&config.TLSConfig{CAFile: keyFile}); err == nil {
+		t.Error("Test Failed - NewTLSConfig() expected an error for a CA bundle without certificates")
+	}
+
+	tlsConfig, err := NewTLSConfig(&config.TLSConfig{ClientCertFile: certFile, ClientKeyFile: keyFile,
+		CAFile: certFile, ServerName: "proxy.internal"})
+	if err != nil {
+		t.Fatal("Test Failed - NewTLSConfig() error", err)
+	}
+	if len(tlsConfig.Certificates) != 1 || tlsConfig.RootCAs == nil || tlsConfig.ServerName != "proxy.internal" {
+		t.Errorf("Test Failed - NewTLSConfig() unexpected config %+v", tlsConfig)
+	}
+}
+
+func TestSetTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gct-tls")
+	if err != nil {
+		t.Fatal("Test Failed - unable to create temp dir", err)
+	}
+	defer os.RemoveAll(dir)
+	serverCert, serverKey := writeTestCertificate(t, dir, "server")
+	clientCert, clientKey := writeTestCertificate(t, dir, "client")
+
+	// The server only accepts clients presenting the client certificate
+	cert, err := tls.LoadX509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatal("Test Failed - unable to load server certificate", err)
+	}
+	clientCAs, err := NewTLSConfig(&config.TLSConfig{CAFile: clientCert})
+	if err != nil {
+		t.Fatal("Test Failed - NewTLSConfig() error", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs.RootCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	b := Base{Name: "RAWR"}
+	b.WebsocketInit()
+	err = b.SetHTTPClientTransport(&config.HTTPTransportConfig{TLSMinVersion: "1.2"})
+	if err != nil {
+		t.Fatal("Test Failed - SetHTTPClientTransport() error", err)
+	}
+
+	if _, err = b.GetHTTPClient().Get(server.URL); err == nil {
+		t.Error("Test Failed - SetTLSConfig() request without a client certificate succeeded")
+	}
+
+	err = b.SetTLSConfig(&config.TLSConfig{ClientCertFile: clientCert, ClientKeyFile: clientKey,
+		CAFile: serverCert, ServerName: "localhost"})
+	if err != nil {
+		t.Fatal("Test Failed - SetTLSConfig() error", err)
+	}
+
+	resp, err := b.GetHTTPClient().Get(server.URL)
+	if err != nil {
+		t.Fatal("Test Failed - SetTLSConfig() request error", err)
+	}
+	resp.Body.Close()
+
+	transport := b.GetHTTPClient().Transport.(*http.Transport)
+	if transport.TLSClientConfig.MinVersion != tls.VersionTLS12 {
+		t.Error("Test Failed - SetTLSConfig() did not keep the minimum TLS version")
+	}
+	if tlsConfig := b.Websocket.GetTLSConfig(); tlsConfig == nil || len(tlsConfig.Certificates) != 1 {
+		t.Error("Test Failed - SetTLSConfig() websocket TLS config not set")
+	}
+
+	// Transport tuning applied later keeps the TLS config
+	err = b.SetHTTPClientTransport(&config.HTTPTransportConfig{DisableHTTP2: true})
+	if err != nil {
+		t.Fatal("Test Failed - SetHTTPClientTransport() error", err)
+	}
+	transport = b.GetHTTPClient().Transport.(*http.Transport)
+	if transport.TLSClientConfig == nil || len(transport.TLSClientConfig.Certificates) != 1 {
+		t.Error("Test Failed - SetHTTPClientTransport() dropped the TLS config")
+	}
+
+	if err = b.SetTLSConfig(nil); err != nil {
+		t.Fatal("Test Failed - SetTLSConfig() error", err)
+	}
+	transport = b.GetHTTPClient().Transport.(*http.Transport)
+	if b.GetTLSConfig() != nil || b.Websocket.GetTLSConfig() != nil ||
+		len(transport.TLSClientConfig.Certificates) != 0 {
+		t.Error("Test Failed - SetTLSConfig() nil config did not clear the TLS config")
+	}
+}
@@ -0,0 +1,204 @@
+package exchange
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// defaultTradeGapAttempts is the default number of times a missing trade ID
+// range is refetched before it is reported as unresolved
+const defaultTradeGapAttempts = 3
+
+// TradeGap holds an inclusive range of missing sequential trade IDs
+type TradeGap struct {
+	Start int64
+	End   int64
+}
+
+// TradeTimeGap holds a period between consecutive trades longer than the
+// allowed gap
+type TradeTimeGap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// TradeRangeFetcher returns the trades with IDs between start and end
+// inclusive. Fetchers may return trades outside of the range, which are
+// merged into the dataset
+type TradeRangeFetcher func(start, end int64) ([]TradeHistory, error)
+
+// TradeTimeRangeFetcher returns the trades executed between start and end
+// inclusive. Fetchers may return trades outside of the range, which are
+// merged into the dataset
+type TradeTimeRangeFetcher func(start, end time.Time) ([]TradeHistory, error)
+
+// FilterTradeHistory returns the trades executed at or after since, ordered
+// by ascending timestamp and trade ID. A zero since returns all trades and a
+// positive limit keeps only the most recent trades
+func FilterTradeHistory(trades []TradeHistory, since time.Time, limit int) []TradeHistory {
+	var result []TradeHistory
+	for x := range trades {
+		if !since.IsZero() && trades[x].Timestamp < since.Unix() {
+			continue
+		}
+		result = append(result, trades[x])
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Timestamp == result[j].Timestamp {
+			return result[i].TID < result[j].TID
+		}
+		return result[i].Timestamp < result[j].Timestamp
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// InferTradeSides sets the side of trades without one by the tick rule, for
+// exchanges which don't report the taker side. Trades are expected in
+// execution order, a trade above the previous price is a buy and one below it
+// a sell, while a trade at the same price takes the side of the previous trade.
+// Leading trades before the first price change are left without a side
+func InferTradeSides(trades []TradeHistory) {
+	var side string
+	for x := range trades {
+		if x > 0 {
+			switch {
+			case trades[x].Price > trades[x-1].Price:
+				side = string(OrderSideBuy())
+			case trades[x].Price < trades[x-1].Price:
+				side = string(OrderSideSell())
+			}
+		}
+		if trades[x].Type == "" {
+			trades[x].Type = side
+		}
+	}
+}
+
+// SortTradeHistory sorts trades by ascending trade ID and removes duplicate
+// trade IDs
+func SortTradeHistory(trades []TradeHistory) []TradeHistory {
+	sorted := make([]TradeHistory, len(trades))
+	copy(sorted, trades)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].TID < sorted[j].TID
+	})
+
+	var result []TradeHistory
+	for x := range sorted {
+		if len(result) > 0 && result[len(result)-1].TID == sorted[x].TID {
+			continue
+		}
+		result = append(result, sorted[x])
+	}
+	return result
+}
+
+// FindTradeGaps returns the ranges of sequential trade IDs missing between
+// the lowest and highest trade IDs in the supplied trades
+func FindTradeGaps(trades []TradeHistory) []TradeGap {
+	sorted := SortTradeHistory(trades)
+	var gaps []TradeGap
+	for x := 1; x < len(sorted); x++ {
+		if sorted[x].TID-sorted[x-1].TID > 1 {
+			gaps = append(gaps, TradeGap{
+				Start: sorted[x-1].TID + 1,
+				End:   sorted[x].TID - 1,
+			})
+		}
+	}
+	return gaps
+}
+
+// FillTradeGaps verifies a trade history sync for exchanges with sequential
+// trade IDs per currency pair. Missing ranges are refetched up to attempts
+// times each, stopping early once refetching finds no new trades, and the
+// sorted, de-duplicated dataset is returned along with any gaps that could not
+// be resolved. Exchanges sharing trade IDs across pairs should use
+// FillTradeTimeGaps as their per pair trade IDs always have gaps
+func FillTradeGaps(trades []TradeHistory, fetch TradeRangeFetcher, attempts int) ([]TradeHistory, []TradeGap, error) {
+	if fetch == nil {
+		return nil, nil, errors.New("trade range fetcher cannot be nil")
+	}
+
+	if attempts <= 0 {
+		attempts = defaultTradeGapAttempts
+	}
+
+	result := SortTradeHistory(trades)
+	gaps := FindTradeGaps(result)
+	for i := 0; i < attempts && len(gaps) > 0; i++ {
+		known := len(result)
+		for _, gap := range gaps {
+			fetched, err := fetch(gap.Start, gap.End)
+			if err != nil {
+				return result, gaps, err
+			}
+			result = append(result, fetched...)
+		}
+		result = SortTradeHistory(result)
+		gaps = FindTradeGaps(result)
+		if len(result) == known {
+			break
+		}
+	}
+	return result, gaps, nil
+}
+
+// FindTradeTimeGaps returns the periods between consecutive trades, ordered by
+// timestamp, which are longer than maxGap
+func FindTradeTimeGaps(trades []TradeHistory, maxGap time.Duration) []TradeTimeGap {
+	sorted := FilterTradeHistory(SortTradeHistory(trades), time.Time{}, 0)
+	var gaps []TradeTimeGap
+	for x := 1; x < len(sorted); x++ {
+		if time.Duration(sorted[x].Timestamp-sorted[x-1].Timestamp)*time.Second > maxGap {
+			gaps = append(gaps, TradeTimeGap{
+				Start: time.Unix(sorted[x-1].Timestamp, 0),
+				End:   time.Unix(sorted[x].Timestamp, 0),
+			})
+		}
+	}
+	return gaps
+}
+
+// FillTradeTimeGaps verifies a trade history sync by timestamp continuity, for
+// exchanges whose trade IDs are shared across currency pairs. Periods without
+// trades longer than maxGap are refetched with a single request per attempt
+// spanning all of them, up to attempts times and stopping early once
+// refetching finds no new trades. The de-duplicated dataset ordered by
+// timestamp is returned along with the gaps remaining, which may be quiet
+// markets rather than missing trades
+func FillTradeTimeGaps(trades []TradeHistory, fetch TradeTimeRangeFetcher, maxGap time.Duration, attempts int) ([]TradeHistory, []TradeTimeGap, error) {
+	if fetch == nil {
+		return nil, nil, errors.New("trade range fetcher cannot be nil")
+	}
+
+	if maxGap <= 0 {
+		return nil, nil, errors.New("maximum trade gap must be positive")
+	}
+
+	if attempts <= 0 {
+		attempts = defaultTradeGapAttempts
+	}
+
+	result := SortTradeHistory(trades)
+	gaps := FindTradeTimeGaps(result, maxGap)
+	for i := 0; i < attempts && len(gaps) > 0; i++ {
+		known := len(result)
+		fetched, err := fetch(gaps[0].Start, gaps[len(gaps)-1].End)
+		if err != nil {
+			return FilterTradeHistory(result, time.Time{}, 0), gaps, err
+		}
+		result = SortTradeHistory(append(result, fetched...))
+		gaps = FindTradeTimeGaps(result, maxGap)
+		if len(result) == known {
+			break
+		}
+	}
+	return FilterTradeHistory(result, time.Time{}, 0), gaps, nil
+}
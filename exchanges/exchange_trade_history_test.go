@@ -0,0 +1,173 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFindTradeGaps(t *testing.T) {
+	trades := []TradeHistory{{TID: 5}, {TID: 1}, {TID: 2}, {TID: 2}, {TID: 9}}
+	gaps := FindTradeGaps(trades)
+	if len(gaps) != 2 {
+		t.Fatalf("Test failed. FindTradeGaps expected 2 gaps received %d", len(gaps))
+	}
+
+	if gaps[0] != (TradeGap{Start: 3, End: 4}) || gaps[1] != (TradeGap{Start: 6, End: 8}) {
+		t.Error("Test failed. FindTradeGaps incorrect gaps", gaps)
+	}
+
+	if len(FindTradeGaps(nil)) != 0 {
+		t.Error("Test failed. FindTradeGaps returned gaps for empty trades")
+	}
+}
+
+func TestFillTradeGaps(t *testing.T) {
+	_, _, err := FillTradeGaps(nil, nil, 0)
+	if err == nil {
+		t.Error("Test failed. FillTradeGaps accepted nil fetcher")
+	}
+
+	trades := []TradeHistory{{TID: 1}, {TID: 5}, {TID: 10}}
+	var calls int
+	result, gaps, err := FillTradeGaps(trades, func(start, end int64) ([]TradeHistory, error) {
+		calls++
+		var fetched []TradeHistory
+		for x := start; x <= end; x++ {
+			// Trade 8 never becomes available
+			if x != 8 {
+				fetched = append(fetched, TradeHistory{TID: x})
+			}
+		}
+		return fetched, nil
+	}, 2)
+	if err != nil {
+		t.Fatal("Test failed. FillTradeGaps error", err)
+	}
+
+	if len(result) != 9 {
+		t.Errorf("Test failed. FillTradeGaps expected 9 trades received %d", len(result))
+	}
+
+	if len(gaps) != 1 || gaps[0] != (TradeGap{Start: 8, End: 8}) {
+		t.Error("Test failed. FillTradeGaps incorrect unresolved gaps", gaps)
+	}
+
+	if calls != 3 {
+		t.Errorf("Test failed. FillTradeGaps expected 3 fetches received %d", calls)
+	}
+
+	_, _, err = FillTradeGaps(trades, func(start, end int64) ([]TradeHistory, error) {
+		return nil, errors.New("fetch failed")
+	}, 1)
+	if err == nil {
+		t.Error("Test failed. FillTradeGaps did not return fetch error")
+	}
+}
+
+func TestFindTradeTimeGaps(t *testing.T) {
+	trades := []TradeHistory{
+		{TID: 7, Timestamp: 1500000400},
+		{TID: 1, Timestamp: 1500000000},
+		{TID: 4, Timestamp: 1500000060},
+		{TID: 9, Timestamp: 1500000460},
+	}
+	gaps := FindTradeTimeGaps(trades, time.Minute)
+	if len(gaps) != 1 {
+		t.Fatalf("Test failed. FindTradeTimeGaps expected 1 gap received %d", len(gaps))
+	}
+
+	if !gaps[0].Start.Equal(time.Unix(1500000060, 0)) || !gaps[0].End.Equal(time.Unix(1500000400, 0)) {
+		t.Error("Test failed. FindTradeTimeGaps incorrect gap", gaps)
+	}
+}
+
+func TestFillTradeTimeGaps(t *testing.T) {
+	if _, _, err := FillTradeTimeGaps(nil, nil, time.Minute, 0); err == nil {
+		t.Error("Test failed. FillTradeTimeGaps accepted nil fetcher")
+	}
+
+	// Trade IDs shared across pairs leave gaps in a pair's trade IDs which
+	// aren't missing trades
+	trades := []TradeHistory{
+		{TID: 100, Timestamp: 1500000000},
+		{TID: 150, Timestamp: 1500000030},
+		{TID: 400, Timestamp: 1500000600},
+		{TID: 420, Timestamp: 1500000630},
+	}
+	var calls int
+	result, gaps, err := FillTradeTimeGaps(trades, func(start, end time.Time) ([]TradeHistory, error) {
+		calls++
+		// A quiet period remains between 1500000300 and 1500000600
+		return []TradeHistory{
+			{TID: 210, Timestamp: 1500000090},
+			{TID: 230, Timestamp: 1500000200},
+			{TID: 260, Timestamp: 1500000300},
+		}, nil
+	}, time.Minute*2, 0)
+	if err != nil {
+		t.Fatal("Test failed. FillTradeTimeGaps error", err)
+	}
+
+	if len(result) != 7 || result[2].TID != 210 || result[4].TID != 260 {
+		t.Errorf("Test failed. FillTradeTimeGaps unexpected result %v", result)
+	}
+
+	if len(gaps) != 1 || !gaps[0].Start.Equal(time.Unix(1500000300, 0)) {
+		t.Error("Test failed. FillTradeTimeGaps incorrect remaining gaps", gaps)
+	}
+
+	// The second refetch finds no new trades so the quiet period isn't
+	// requested again
+	if calls != 2 {
+		t.Errorf("Test failed. FillTradeTimeGaps expected 2 fetches received %d", calls)
+	}
+
+	if len(FindTradeTimeGaps(SortTradeHistory(trades), time.Hour)) != 0 {
+		t.Error("Test failed. FindTradeTimeGaps reported gaps shorter than the maximum")
+	}
+}
+
+func TestFilterTradeHistory(t *testing.T) {
+	trades := []TradeHistory{
+		{TID: 3, Timestamp: 1500000300},
+		{TID: 1, Timestamp: 1500000100},
+		{TID: 2, Timestamp: 1500000200},
+		{TID: 4, Timestamp: 1500000300},
+	}
+
+	result := FilterTradeHistory(trades, time.Time{}, 0)
+	if len(result) != 4 || result[0].TID != 1 || result[3].TID != 4 {
+		t.Errorf("Test failed. FilterTradeHistory unexpected result %v", result)
+	}
+
+	result = FilterTradeHistory(trades, time.Unix(1500000200, 0), 0)
+	if len(result) != 3 || result[0].TID != 2 {
+		t.Errorf("Test failed. FilterTradeHistory since unexpected result %v", result)
+	}
+
+	result = FilterTradeHistory(trades, time.Unix(1500000200, 0), 2)
+	if len(result) != 2 || result[0].TID != 3 || result[1].TID != 4 {
+		t.Errorf("Test failed. FilterTradeHistory limit unexpected result %v", result)
+	}
+}
+
+func TestInferTradeSides(t *testing.T) {
+	trades := []TradeHistory{
+		{TID: 1, Price: 100},
+		{TID: 2, Price: 100},
+		{TID: 3, Price: 101},
+		{TID: 4, Price: 101},
+		{TID: 5, Price: 99},
+		{TID: 6, Price: 98, Type: string(OrderSideBuy())},
+	}
+
+	InferTradeSides(trades)
+	expected := []string{"", "", "Buy", "Buy", "Sell", "Buy"}
+	for x := range trades {
+		if trades[x].Type != expected[x] {
+			t.Errorf("Test failed. InferTradeSides trade %d expected %q got %q",
+				trades[x].TID, expected[x], trades[x].Type)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package exchange
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// TransferStatus is the normalised status of a deposit or withdrawal
+type TransferStatus string
+
+// Transfer statuses
+const (
+	TransferStatusPending   TransferStatus = "pending"
+	TransferStatusComplete  TransferStatus = "complete"
+	TransferStatusCancelled TransferStatus = "cancelled"
+	TransferStatusFailed    TransferStatus = "failed"
+	TransferStatusUnknown   TransferStatus = "unknown"
+)
+
+// Fund history transfer types of the combined view
+const (
+	TransferTypeDeposit    = "deposit"
+	TransferTypeWithdrawal = "withdrawal"
+)
+
+// ErrTransferHistoryNotSupported is returned by exchanges which don't support
+// retrieving their deposit and withdrawal history separately
+var ErrTransferHistoryNotSupported = errors.New("exchange does not support deposit and withdrawal history")
+
+// Deposit holds a deposit to an exchange account at one of its deposit
+// addresses. Chain is blank when the exchange doesn't report the network and
+// Confirmations is the number seen so far
+type Deposit struct {
+	ID            string         `json:"id"`
+	Currency      string         `json:"currency"`
+	Chain         string         `json:"chain,omitempty"`
+	Amount        float64        `json:"amount"`
+	Fee           float64        `json:"fee"`
+	Address       string         `json:"address"`
+	TxID          string         `json:"txid"`
+	Confirmations int64          `json:"confirmations"`
+	Status        TransferStatus `json:"status"`
+	Timestamp     time.Time      `json:"timestamp"`
+}
+
+// Withdrawal holds a withdrawal from an exchange account, Amount excluding
+// the Fee charged by the exchange
+type Withdrawal struct {
+	ID            string         `json:"id"`
+	Currency      string         `json:"currency"`
+	Chain         string         `json:"chain,omitempty"`
+	Amount        float64        `json:"amount"`
+	Fee           float64        `json:"fee"`
+	Address       string         `json:"address"`
+	TxID          string         `json:"txid"`
+	Confirmations int64          `json:"confirmations"`
+	Status        TransferStatus `json:"status"`
+	Timestamp     time.Time      `json:"timestamp"`
+}
+
+// inTimeRange returns whether t falls between start and end inclusive, a zero
+// start or end leaving that side of the range open
+func inTimeRange(t, start, end time.Time) bool {
+	return (start.IsZero() || !t.Before(start)) && (end.IsZero() || !t.After(end))
+}
+
+// FilterDeposits returns the deposits made between start and end, for
+// exchanges whose history endpoints don't filter by time
+func FilterDeposits(deposits []Deposit, start, end time.Time) []Deposit {
+	var result []Deposit
+	for x := range deposits {
+		if inTimeRange(deposits[x].Timestamp, start, end) {
+			result = append(result, deposits[x])
+		}
+	}
+	return result
+}
+
+// FilterWithdrawals returns the withdrawals made between start and end, for
+// exchanges whose history endpoints don't filter by time
+func FilterWithdrawals(withdrawals []Withdrawal, start, end time.Time) []Withdrawal {
+	var result []Withdrawal
+	for x := range withdrawals {
+		if inTimeRange(withdrawals[x].Timestamp, start, end) {
+			result = append(result, withdrawals[x])
+		}
+	}
+	return result
+}
+
+// CombineTransferHistory returns deposits and withdrawals as the combined
+// fund history, ordered oldest first
+func CombineTransferHistory(exchName string, deposits []Deposit, withdrawals []Withdrawal) []FundHistory {
+	var result []FundHistory
+	for x := range deposits {
+		result = append(result, FundHistory{
+			ExchangeName:    exchName,
+			Status:          string(deposits[x].Status),
+			Timestamp:       deposits[x].Timestamp.Unix(),
+			Currency:        deposits[x].Currency,
+			Amount:          deposits[x].Amount,
+			Fee:             deposits[x].Fee,
+			TransferType:    TransferTypeDeposit,
+			CryptoToAddress: deposits[x].Address,
+			CryptoTxID:      deposits[x].TxID,
+		})
+	}
+
+	for x := range withdrawals {
+		result = append(result, FundHistory{
+			ExchangeName:    exchName,
+			Status:          string(withdrawals[x].Status),
+			Timestamp:       withdrawals[x].Timestamp.Unix(),
+			Currency:        withdrawals[x].Currency,
+			Amount:          withdrawals[x].Amount,
+			Fee:             withdrawals[x].Fee,
+			TransferType:    TransferTypeWithdrawal,
+			CryptoToAddress: withdrawals[x].Address,
+			CryptoTxID:      withdrawals[x].TxID,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp < result[j].Timestamp
+	})
+	return result
+}
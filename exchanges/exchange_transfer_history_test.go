@@ -0,0 +1,38 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransferHistory(t *testing.T) {
+	deposits := []Deposit{
+		{ID: "1", Currency: "BTC", Amount: 1, Address: "deposit", Status: TransferStatusComplete, Timestamp: time.Unix(100, 0)},
+		{ID: "2", Currency: "BTC", Amount: 2, Status: TransferStatusPending, Timestamp: time.Unix(300, 0)},
+	}
+	withdrawals := []Withdrawal{
+		{ID: "3", Currency: "ETH", Amount: 5, Fee: 0.01, Address: "withdraw", Status: TransferStatusComplete, Timestamp: time.Unix(200, 0)},
+	}
+
+	if filtered := FilterDeposits(deposits, time.Unix(200, 0), time.Time{}); len(filtered) != 1 || filtered[0].ID != "2" {
+		t.Errorf("Test Failed - FilterDeposits() unexpected deposits %+v", filtered)
+	}
+	if filtered := FilterDeposits(deposits, time.Time{}, time.Unix(100, 0)); len(filtered) != 1 || filtered[0].ID != "1" {
+		t.Errorf("Test Failed - FilterDeposits() unexpected deposits %+v", filtered)
+	}
+	if filtered := FilterWithdrawals(withdrawals, time.Unix(201, 0), time.Unix(400, 0)); len(filtered) != 0 {
+		t.Errorf("Test Failed - FilterWithdrawals() unexpected withdrawals %+v", filtered)
+	}
+
+	history := CombineTransferHistory("Test", deposits, withdrawals)
+	if len(history) != 3 {
+		t.Fatalf("Test Failed - CombineTransferHistory() expected 3 transfers, got %+v", history)
+	}
+	if history[1].TransferType != TransferTypeWithdrawal || history[1].CryptoToAddress != "withdraw" ||
+		history[1].Fee != 0.01 || history[1].Status != "complete" {
+		t.Errorf("Test Failed - CombineTransferHistory() unexpected withdrawal %+v", history[1])
+	}
+	if history[0].TransferType != TransferTypeDeposit || history[2].Timestamp != 300 {
+		t.Errorf("Test Failed - CombineTransferHistory() unexpected order %+v", history)
+	}
+}
@@ -0,0 +1,54 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Exchange internal wallet types
+const (
+	WalletExchange = "exchange"
+	WalletMargin   = "margin"
+	WalletFunding  = "funding"
+	WalletFutures  = "futures"
+)
+
+// IWalletTransferer is implemented by exchanges which support transferring
+// funds between their internal wallets through the wrapper
+type IWalletTransferer interface {
+	TransferWalletFunds(transfer WalletTransfer) (string, error)
+}
+
+// WalletTransfer holds a transfer of funds between the internal wallets of an
+// exchange. Pair is required by exchanges with isolated margin accounts per
+// currency pair
+type WalletTransfer struct {
+	Currency pair.CurrencyItem
+	Amount   float64
+	From     string
+	To       string
+	Pair     pair.CurrencyPair
+}
+
+// Validate returns an error if the transfer is missing details or moves funds
+// to or from a wallet type the exchange doesn't support
+func (w WalletTransfer) Validate(supportedWallets ...string) error {
+	if w.Currency == "" || w.Amount <= 0 {
+		return errors.New("wallet transfer requires a currency and a positive amount")
+	}
+
+	if w.From == w.To {
+		return fmt.Errorf("wallet transfer source and destination are both %s", w.From)
+	}
+
+	for _, wallet := range []string{w.From, w.To} {
+		if !common.StringDataCompare(supportedWallets, wallet) {
+			return fmt.Errorf("unsupported wallet type %s, supported wallets %v",
+				wallet, supportedWallets)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package exchange
+
+import (
+	"testing"
+)
+
+func TestWalletTransferValidate(t *testing.T) {
+	transfer := WalletTransfer{
+		Currency: "BTC",
+		Amount:   1,
+		From:     WalletExchange,
+		To:       WalletMargin,
+	}
+
+	err := transfer.Validate(WalletExchange, WalletMargin, WalletFunding)
+	if err != nil {
+		t.Error("Test Failed - Validate() error", err)
+	}
+
+	err = transfer.Validate(WalletExchange, WalletFunding)
+	if err == nil {
+		t.Error("Test Failed - Validate() expected unsupported wallet error")
+	}
+
+	transfer.To = WalletExchange
+	err = transfer.Validate(WalletExchange, WalletMargin)
+	if err == nil {
+		t.Error("Test Failed - Validate() expected same wallet error")
+	}
+
+	transfer.To = WalletMargin
+	transfer.Amount = 0
+	err = transfer.Validate(WalletExchange, WalletMargin)
+	if err == nil {
+		t.Error("Test Failed - Validate() expected amount error")
+	}
+}
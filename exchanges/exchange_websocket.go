@@ -1,6 +1,7 @@
 package exchange
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"sync"
@@ -48,9 +49,13 @@ func (e *Base) WebsocketSetup(connector func() error,
 	e.Websocket.Intercomm = make(chan WebsocketResponse, 1)
 	e.Websocket.TrafficAlert = make(chan struct{}, 1)
 
-	err := e.Websocket.SetEnabled(wsEnabled)
-	if err != nil {
-		return err
+	// The websocket is already enabled or disabled when an exchange is set up
+	// again after reloading its config
+	if e.Websocket.init || e.Websocket.IsEnabled() != wsEnabled {
+		err := e.Websocket.SetEnabled(wsEnabled)
+		if err != nil {
+			return err
+		}
 	}
 
 	e.Websocket.SetDefaultURL(defaultURL)
@@ -75,6 +80,31 @@ type Websocket struct {
 	connected    bool
 	connector    func() error
 	m            sync.Mutex
+	messages     int64
+	messagesMtx  sync.Mutex
+
+	subscriptions    []WebsocketSubscription
+	subscriber       WebsocketSubscriber
+	unsubscriber     WebsocketSubscriber
+	subscriptionsMtx sync.Mutex
+
+	compression       string
+	permessageDeflate bool
+	compressionMtx    sync.Mutex
+
+	tlsConfig    *tls.Config
+	tlsConfigMtx sync.Mutex
+
+	keepalive    WebsocketKeepalive
+	keepaliveMtx sync.Mutex
+
+	connections        []*WebsocketConnection
+	pairGroups         [][]pair.CurrencyPair
+	pairsPerConnection int
+	connectionsMtx     sync.Mutex
+
+	chaos    WebsocketChaos
+	chaosMtx sync.Mutex
 
 	// Connected denotes a channel switch for diversion of request flow
 	Connected chan struct{}
@@ -125,6 +155,7 @@ func (w *Websocket) trafficMonitor(wg *sync.WaitGroup) {
 			return
 
 		case <-w.TrafficAlert: // Resets timer on traffic
+			w.incrementMessages()
 			if !w.connected {
 				w.Connected <- struct{}{}
 				w.connected = true
@@ -149,6 +180,7 @@ func (w *Websocket) trafficMonitor(wg *sync.WaitGroup) {
 				return
 
 			case <-w.TrafficAlert: // If in this time response traffic comes through
+				w.incrementMessages()
 				trafficTimer.Reset(WebsocketTrafficLimitTime)
 				if !w.connected {
 					// If not connected divert traffic from REST to websocket
@@ -192,6 +224,7 @@ func (w *Websocket) Connect() error {
 	w.Connected <- struct{}{}
 	w.connected = true
 
+	w.connectConnections()
 	return nil
 }
 
@@ -209,6 +242,10 @@ func (w *Websocket) Shutdown() error {
 		return errors.New("exchange_websocket.go error - System not connected to shut down")
 	}
 
+	// Named connections are shut down first, the main routines still stop
+	// if any fail to
+	connErr := w.shutdownConnections()
+
 	timer := time.NewTimer(5 * time.Second)
 	c := make(chan struct{}, 1)
 
@@ -221,7 +258,7 @@ func (w *Websocket) Shutdown() error {
 	select {
 	case <-c:
 		w.connected = false
-		return nil
+		return connErr
 	case <-timer.C:
 		return fmt.Errorf("%s - Websocket routines failed to shutdown",
 			w.GetName())
@@ -326,12 +363,28 @@ func (w *Websocket) GetName() string {
 	return w.exchangeName
 }
 
+// GetMessageCount returns the number of messages received over the websocket
+// connection
+func (w *Websocket) GetMessageCount() int64 {
+	w.messagesMtx.Lock()
+	defer w.messagesMtx.Unlock()
+	return w.messages
+}
+
+func (w *Websocket) incrementMessages() {
+	w.messagesMtx.Lock()
+	w.messages++
+	w.messagesMtx.Unlock()
+}
+
 // WebsocketOrderbookLocal defines a local cache of orderbooks for ammending,
 // appending and deleting changes and updates the main store in orderbook.go
 type WebsocketOrderbookLocal struct {
 	ob          []orderbook.Base
 	lastUpdated time.Time
 	m           sync.Mutex
+	recorder    *OrderbookRecorder
+	recorderMtx sync.Mutex
 }
 
 // Update updates a local cache using bid targets and ask targets then updates
@@ -342,7 +395,18 @@ type WebsocketOrderbookLocal struct {
 func (w *WebsocketOrderbookLocal) Update(bidTargets, askTargets []orderbook.Item,
 	p pair.CurrencyPair,
 	updated time.Time,
-	exchName, assetType string) error {
+	exchName, assetType string) (err error) {
+	rec := OrderbookRecord{
+		Type:      OrderbookRecordUpdate,
+		Exchange:  exchName,
+		Pair:      p,
+		AssetType: assetType,
+		Updated:   updated,
+		Bids:      copyOrderbookItems(bidTargets),
+		Asks:      copyOrderbookItems(askTargets),
+	}
+	defer func() { w.record(rec, err) }()
+
 	if bidTargets == nil && askTargets == nil {
 		return errors.New("exchange.go websocket orderbook cache Update() error - cannot have bids and ask targets both nil")
 	}
@@ -442,7 +506,18 @@ func (w *WebsocketOrderbookLocal) Update(bidTargets, askTargets []orderbook.Item
 }
 
 // LoadSnapshot loads initial snapshot of orderbook data
-func (w *WebsocketOrderbookLocal) LoadSnapshot(newOrderbook orderbook.Base, exchName string) error {
+func (w *WebsocketOrderbookLocal) LoadSnapshot(newOrderbook orderbook.Base, exchName string) (err error) {
+	rec := OrderbookRecord{
+		Type:      OrderbookRecordSnapshot,
+		Exchange:  exchName,
+		Pair:      newOrderbook.Pair,
+		AssetType: newOrderbook.AssetType,
+		Updated:   newOrderbook.LastUpdated,
+		Bids:      copyOrderbookItems(newOrderbook.Bids),
+		Asks:      copyOrderbookItems(newOrderbook.Asks),
+	}
+	defer func() { w.record(rec, err) }()
+
 	if len(newOrderbook.Asks) == 0 || len(newOrderbook.Bids) == 0 {
 		return errors.New("exchange.go websocket orderbook cache LoadSnapshot() error - snapshot ask and bids are nil")
 	}
@@ -471,7 +546,19 @@ func (w *WebsocketOrderbookLocal) LoadSnapshot(newOrderbook orderbook.Base, exch
 func (w *WebsocketOrderbookLocal) UpdateUsingID(bidTargets, askTargets []orderbook.Item,
 	p pair.CurrencyPair,
 	updated time.Time,
-	exchName, assetType, action string) error {
+	exchName, assetType, action string) (err error) {
+	rec := OrderbookRecord{
+		Type:      OrderbookRecordUpdateID,
+		Exchange:  exchName,
+		Pair:      p,
+		AssetType: assetType,
+		Action:    action,
+		Updated:   updated,
+		Bids:      copyOrderbookItems(bidTargets),
+		Asks:      copyOrderbookItems(askTargets),
+	}
+	defer func() { w.record(rec, err) }()
+
 	w.m.Lock()
 	defer w.m.Unlock()
 
@@ -545,6 +632,67 @@ func (w *WebsocketOrderbookLocal) UpdateUsingID(bidTargets, askTargets []orderbo
 	return nil
 }
 
+// GetOrderbook returns a copy of the locally maintained orderbook of a pair
+// and asset type regardless of the pair's delimiter, and whether it was found
+func (w *WebsocketOrderbookLocal) GetOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, bool) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	for i := range w.ob {
+		if w.ob[i].Pair.Equal(p, true) && w.ob[i].AssetType == assetType {
+			result := w.ob[i]
+			result.Bids = copyOrderbookItems(w.ob[i].Bids)
+			result.Asks = copyOrderbookItems(w.ob[i].Asks)
+			return result, true
+		}
+	}
+	return orderbook.Base{}, false
+}
+
+// Resync replaces the locally maintained orderbook of a pair and asset type
+// with a snapshot, loading it when not yet cached, and updates the main
+// cache in orderbook.go. Subsequent updates are applied on top of the snapshot
+func (w *WebsocketOrderbookLocal) Resync(newOrderbook orderbook.Base, exchName string) (err error) {
+	rec := OrderbookRecord{
+		Type:      OrderbookRecordResync,
+		Exchange:  exchName,
+		Pair:      newOrderbook.Pair,
+		AssetType: newOrderbook.AssetType,
+		Updated:   newOrderbook.LastUpdated,
+		Bids:      copyOrderbookItems(newOrderbook.Bids),
+		Asks:      copyOrderbookItems(newOrderbook.Asks),
+	}
+	defer func() { w.record(rec, err) }()
+
+	if len(newOrderbook.Asks) == 0 || len(newOrderbook.Bids) == 0 {
+		return errors.New("exchange.go websocket orderbook cache Resync() error - snapshot ask and bids are nil")
+	}
+
+	newOrderbook.Bids = copyOrderbookItems(newOrderbook.Bids)
+	newOrderbook.Asks = copyOrderbookItems(newOrderbook.Asks)
+
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	replaced := false
+	for i := range w.ob {
+		if w.ob[i].Pair == newOrderbook.Pair && w.ob[i].AssetType == newOrderbook.AssetType {
+			w.ob[i] = newOrderbook
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		w.ob = append(w.ob, newOrderbook)
+	}
+
+	orderbook.ProcessOrderbook(exchName,
+		newOrderbook.Pair,
+		newOrderbook,
+		newOrderbook.AssetType)
+
+	return nil
+}
+
 // FlushCache flushes w.ob data to be garbage collected and refreshed when a
 // connection is lost and reconnected
 func (w *WebsocketOrderbookLocal) FlushCache() {
@@ -567,8 +715,10 @@ type WebsocketOrderbookUpdate struct {
 	Exchange string
 }
 
-// TradeData defines trade data
+// TradeData defines trade data, TradeID is the trade ID assigned by the
+// exchange used to drop duplicate trades and is empty when not provided
 type TradeData struct {
+	TradeID      string
 	Timestamp    time.Time
 	CurrencyPair pair.CurrencyPair
 	AssetType    string
@@ -580,6 +730,25 @@ type TradeData struct {
 	Side         string
 }
 
+// FillData defines a fill of one of the account's orders received over a
+// private websocket. Side and Liquidity hold the exchange's own spellings,
+// Liquidity is empty when the exchange doesn't report whether the fill made
+// or took liquidity
+type FillData struct {
+	Exchange     string
+	TradeID      string
+	OrderID      int64
+	ClientID     string
+	CurrencyPair pair.CurrencyPair
+	AssetType    string
+	Side         string
+	Price        float64
+	Amount       float64
+	Fee          float64
+	Liquidity    string
+	Timestamp    time.Time
+}
+
 // TickerData defines ticker feed
 type TickerData struct {
 	Timestamp  time.Time
@@ -0,0 +1,125 @@
+package exchange
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// Websocket frame compression methods
+const (
+	WebsocketCompressionNone    = "none"
+	WebsocketCompressionGzip    = "gzip"
+	WebsocketCompressionDeflate = "deflate"
+	WebsocketCompressionAuto    = "auto"
+)
+
+// ErrUnsupportedWebsocketCompression is returned when setting an unknown
+// websocket compression method
+var ErrUnsupportedWebsocketCompression = errors.New("unsupported websocket compression method")
+
+// WebsocketCompressionSetup sets the websocket frame compression of the
+// exchange from its config, falling back to the default method of the
+// exchange when no method is configured
+func (e *Base) WebsocketCompressionSetup(cfg *config.WebsocketCompressionConfig, defaultMethod string) error {
+	method := defaultMethod
+	var permessageDeflate bool
+	if cfg != nil {
+		if cfg.Method != "" {
+			method = cfg.Method
+		}
+		permessageDeflate = cfg.PermessageDeflate
+	}
+	return e.Websocket.SetCompression(method, permessageDeflate)
+}
+
+// SetCompression sets the compression method used to decompress binary
+// websocket frames and whether to negotiate the permessage deflate extension
+func (w *Websocket) SetCompression(method string, permessageDeflate bool) error {
+	switch method {
+	case "":
+		method = WebsocketCompressionNone
+	case WebsocketCompressionNone, WebsocketCompressionGzip, WebsocketCompressionDeflate, WebsocketCompressionAuto:
+	default:
+		return ErrUnsupportedWebsocketCompression
+	}
+
+	w.compressionMtx.Lock()
+	w.compression = method
+	w.permessageDeflate = permessageDeflate
+	w.compressionMtx.Unlock()
+	return nil
+}
+
+// GetCompression returns the websocket frame compression method and whether
+// the permessage deflate extension is negotiated
+func (w *Websocket) GetCompression() (method string, permessageDeflate bool) {
+	w.compressionMtx.Lock()
+	defer w.compressionMtx.Unlock()
+	if w.compression == "" {
+		return WebsocketCompressionNone, w.permessageDeflate
+	}
+	return w.compression, w.permessageDeflate
+}
+
+// ConfigureDialer applies the TLS config of the exchange to a websocket dialer
+// and enables permessage deflate negotiation when configured, frames are then
+// decompressed transparently by the connection
+func (w *Websocket) ConfigureDialer(dialer *websocket.Dialer) {
+	dialer.TLSClientConfig = w.GetTLSConfig()
+	_, permessageDeflate := w.GetCompression()
+	dialer.EnableCompression = permessageDeflate
+}
+
+// Decompress returns the payload of a websocket frame decompressed using the
+// compression method of the connection. Text frames are never compressed and
+// are returned as is
+func (w *Websocket) Decompress(messageType int, data []byte) ([]byte, error) {
+	if messageType == websocket.TextMessage {
+		return data, nil
+	}
+	method, _ := w.GetCompression()
+	return DecompressWebsocketMessage(method, data)
+}
+
+// DecompressWebsocketMessage decompresses a websocket frame payload. The auto
+// method detects gzip by its header and otherwise tries raw deflate, returning
+// the payload unchanged when it isn't compressed
+func DecompressWebsocketMessage(method string, data []byte) ([]byte, error) {
+	switch method {
+	case WebsocketCompressionGzip:
+		return gunzip(data)
+	case WebsocketCompressionDeflate:
+		return inflate(data)
+	case WebsocketCompressionAuto:
+		if len(data) > 1 && data[0] == 0x1f && data[1] == 0x8b {
+			return gunzip(data)
+		}
+		decompressed, err := inflate(data)
+		if err != nil {
+			return data, nil
+		}
+		return decompressed, nil
+	}
+	return data, nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+func inflate(data []byte) ([]byte, error) {
+	reader := flate.NewReader(bytes.NewReader(data))
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
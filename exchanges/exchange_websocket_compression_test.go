@@ -0,0 +1,75 @@
+package exchange
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+func TestWebsocketCompression(t *testing.T) {
+	payload := []byte(`{"ping":1337}`)
+
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	gw.Write(payload)
+	gw.Close()
+
+	var deflated bytes.Buffer
+	fw, _ := flate.NewWriter(&deflated, flate.DefaultCompression)
+	fw.Write(payload)
+	fw.Close()
+
+	b := Base{}
+	b.WebsocketInit()
+
+	err := b.WebsocketCompressionSetup(&config.WebsocketCompressionConfig{Method: "zstd"},
+		WebsocketCompressionGzip)
+	if err != ErrUnsupportedWebsocketCompression {
+		t.Error("Test Failed - WebsocketCompressionSetup() unsupported method error", err)
+	}
+
+	err = b.WebsocketCompressionSetup(nil, WebsocketCompressionGzip)
+	if err != nil {
+		t.Fatal("Test Failed - WebsocketCompressionSetup() error", err)
+	}
+
+	data, err := b.Websocket.Decompress(websocket.BinaryMessage, gzipped.Bytes())
+	if err != nil || !bytes.Equal(data, payload) {
+		t.Errorf("Test Failed - Decompress() gzip unexpected result %s %v", data, err)
+	}
+
+	data, err = b.Websocket.Decompress(websocket.TextMessage, payload)
+	if err != nil || !bytes.Equal(data, payload) {
+		t.Errorf("Test Failed - Decompress() text unexpected result %s %v", data, err)
+	}
+
+	err = b.WebsocketCompressionSetup(&config.WebsocketCompressionConfig{
+		Method:            WebsocketCompressionAuto,
+		PermessageDeflate: true,
+	}, WebsocketCompressionGzip)
+	if err != nil {
+		t.Fatal("Test Failed - WebsocketCompressionSetup() auto error", err)
+	}
+
+	var dialer websocket.Dialer
+	b.Websocket.ConfigureDialer(&dialer)
+	if !dialer.EnableCompression {
+		t.Error("Test Failed - ConfigureDialer() permessage deflate not enabled")
+	}
+
+	for _, compressed := range [][]byte{gzipped.Bytes(), deflated.Bytes()} {
+		data, err = b.Websocket.Decompress(websocket.BinaryMessage, compressed)
+		if err != nil || !bytes.Equal(data, payload) {
+			t.Errorf("Test Failed - Decompress() auto unexpected result %s %v", data, err)
+		}
+	}
+
+	data, err = DecompressWebsocketMessage(WebsocketCompressionNone, deflated.Bytes())
+	if err != nil || !bytes.Equal(data, deflated.Bytes()) {
+		t.Error("Test Failed - DecompressWebsocketMessage() none altered payload", err)
+	}
+}
@@ -0,0 +1,383 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Websocket connection names, sharded connections are suffixed with their
+// shard number
+const (
+	WebsocketConnectionPublic  = "public"
+	WebsocketConnectionPrivate = "private"
+)
+
+var (
+	// ErrWebsocketConnectionNotFound is returned when no websocket connection
+	// exists with the name
+	ErrWebsocketConnectionNotFound = errors.New("websocket connection not found")
+	// ErrWebsocketConnectionExists is returned when adding a websocket
+	// connection with the name of an existing connection
+	ErrWebsocketConnectionExists = errors.New("websocket connection already exists")
+	// ErrWebsocketConnectionNotConnected is returned when shutting down a
+	// websocket connection which is not connected
+	ErrWebsocketConnectionNotConnected = errors.New("websocket connection not connected")
+)
+
+// WebsocketConnector dials a named websocket connection and starts its read
+// routine, which must track itself in the connection's wait group and return
+// once the connection's shutdown channel is closed
+type WebsocketConnector func(conn *WebsocketConnection) error
+
+// WebsocketConnection holds a named websocket connection of an exchange, such
+// as a public market data connection, a private order entry connection or a
+// shard of the enabled pairs. Each connection connects, shuts down and
+// reconnects independently of the others
+type WebsocketConnection struct {
+	Name          string
+	URL           string
+	Pairs         []pair.CurrencyPair
+	Authenticated bool
+
+	// ShutdownC is closed when the connection is shut down
+	ShutdownC chan struct{}
+
+	// Wg defines a wait group for the routines of the connection
+	Wg sync.WaitGroup
+
+	exchangeName  string
+	connector     WebsocketConnector
+	connected     bool
+	reconnects    int
+	lastConnected time.Time
+	lastError     string
+	m             sync.Mutex
+}
+
+// WebsocketConnectionStatus holds the state of a named websocket connection
+type WebsocketConnectionStatus struct {
+	Name          string    `json:"name"`
+	URL           string    `json:"url"`
+	Pairs         []string  `json:"pairs,omitempty"`
+	Authenticated bool      `json:"authenticated"`
+	Connected     bool      `json:"connected"`
+	Reconnects    int       `json:"reconnects"`
+	LastConnected time.Time `json:"lastConnected"`
+	LastError     string    `json:"lastError,omitempty"`
+}
+
+// WebsocketConnectionError is sent to the data handler when a named websocket
+// connection fails, so only that connection is reconnected
+type WebsocketConnectionError struct {
+	Exchange   string
+	Connection string
+	Err        error
+}
+
+func (e *WebsocketConnectionError) Error() string {
+	return fmt.Sprintf("%s websocket connection %s error - %s", e.Exchange,
+		e.Connection, e.Err)
+}
+
+// WebsocketConnectionSetup sets how the exchange splits its websocket streams
+// across connections from its config
+func (e *Base) WebsocketConnectionSetup(cfg *config.WebsocketConnectionConfig) {
+	e.Websocket.connectionsMtx.Lock()
+	defer e.Websocket.connectionsMtx.Unlock()
+
+	e.Websocket.pairGroups = nil
+	e.Websocket.pairsPerConnection = 0
+	if cfg == nil {
+		return
+	}
+
+	for x := range cfg.PairGroups {
+		e.Websocket.pairGroups = append(e.Websocket.pairGroups,
+			e.toCanonicalPairs(pair.FormatPairs(common.SplitStrings(cfg.PairGroups[x], ","),
+				e.ConfigCurrencyPairFormat.Delimiter,
+				e.ConfigCurrencyPairFormat.Index)))
+	}
+	e.Websocket.pairsPerConnection = cfg.PairsPerConnection
+}
+
+// GetPairGroups splits pairs into the groups served by separate connections.
+// Configured pair groups are used first, with any remaining pairs split into
+// groups of the configured pairs per connection. Without either all pairs are
+// served by a single connection
+func (w *Websocket) GetPairGroups(pairs []pair.CurrencyPair) [][]pair.CurrencyPair {
+	w.connectionsMtx.Lock()
+	configured := w.pairGroups
+	perConnection := w.pairsPerConnection
+	w.connectionsMtx.Unlock()
+
+	var groups [][]pair.CurrencyPair
+	var grouped []pair.CurrencyPair
+	for x := range configured {
+		var group []pair.CurrencyPair
+		for y := range configured[x] {
+			if pair.Contains(pairs, configured[x][y], true) &&
+				!pair.Contains(grouped, configured[x][y], true) {
+				group = append(group, configured[x][y])
+				grouped = append(grouped, configured[x][y])
+			}
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	var remaining []pair.CurrencyPair
+	for x := range pairs {
+		if !pair.Contains(grouped, pairs[x], true) {
+			remaining = append(remaining, pairs[x])
+		}
+	}
+
+	if perConnection <= 0 {
+		perConnection = len(remaining)
+	}
+
+	for len(remaining) > 0 {
+		n := perConnection
+		if n > len(remaining) {
+			n = len(remaining)
+		}
+		groups = append(groups, remaining[:n])
+		remaining = remaining[n:]
+	}
+	return groups
+}
+
+// AddConnection adds a named connection to the websocket, connected along
+// with the websocket and reconnected on its own when it fails
+func (w *Websocket) AddConnection(conn *WebsocketConnection, connector WebsocketConnector) error {
+	if conn.Name == "" || connector == nil {
+		return errors.New("exchange_websocket_connections.go error - connection requires a name and connector")
+	}
+
+	w.connectionsMtx.Lock()
+	defer w.connectionsMtx.Unlock()
+
+	for x := range w.connections {
+		if w.connections[x].Name == conn.Name {
+			return ErrWebsocketConnectionExists
+		}
+	}
+
+	conn.exchangeName = w.GetName()
+	conn.connector = connector
+	w.connections = append(w.connections, conn)
+	return nil
+}
+
+// SetConnections replaces the named connections of the websocket, shutting
+// down any which are still connected
+func (w *Websocket) SetConnections(conns []*WebsocketConnection, connector WebsocketConnector) error {
+	w.connectionsMtx.Lock()
+	old := w.connections
+	w.connections = nil
+	w.connectionsMtx.Unlock()
+
+	for x := range old {
+		if old[x].IsConnected() {
+			old[x].Shutdown()
+		}
+	}
+
+	for x := range conns {
+		err := w.AddConnection(conns[x], connector)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetConnection returns a named connection of the websocket
+func (w *Websocket) GetConnection(name string) (*WebsocketConnection, error) {
+	w.connectionsMtx.Lock()
+	defer w.connectionsMtx.Unlock()
+
+	for x := range w.connections {
+		if w.connections[x].Name == name {
+			return w.connections[x], nil
+		}
+	}
+	return nil, ErrWebsocketConnectionNotFound
+}
+
+// GetConnections returns the named connections of the websocket
+func (w *Websocket) GetConnections() []*WebsocketConnection {
+	w.connectionsMtx.Lock()
+	defer w.connectionsMtx.Unlock()
+	return append([]*WebsocketConnection(nil), w.connections...)
+}
+
+// GetConnectionStatus returns the state of each named connection of the
+// websocket
+func (w *Websocket) GetConnectionStatus() []WebsocketConnectionStatus {
+	conns := w.GetConnections()
+	status := make([]WebsocketConnectionStatus, 0, len(conns))
+	for x := range conns {
+		status = append(status, conns[x].GetStatus())
+	}
+	return status
+}
+
+// connectConnections connects every named connection, a connection which
+// fails is reported to the data handler to be reconnected without failing the
+// others
+func (w *Websocket) connectConnections() {
+	conns := w.GetConnections()
+	for x := range conns {
+		err := conns[x].Connect()
+		if err == nil {
+			continue
+		}
+
+		connErr := &WebsocketConnectionError{
+			Exchange:   w.GetName(),
+			Connection: conns[x].Name,
+			Err:        err,
+		}
+		go func() {
+			select {
+			case w.DataHandler <- connErr:
+			case <-w.ShutdownC:
+			}
+		}()
+	}
+}
+
+// shutdownConnections shuts down every connected named connection
+func (w *Websocket) shutdownConnections() error {
+	var errs []string
+	conns := w.GetConnections()
+	for x := range conns {
+		if !conns[x].IsConnected() {
+			continue
+		}
+
+		err := conns[x].Shutdown()
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(common.JoinStrings(errs, ", "))
+	}
+	return nil
+}
+
+// Connect dials the connection using its connector
+func (c *WebsocketConnection) Connect() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.connected {
+		return fmt.Errorf("exchange_websocket_connections.go %s error - connection %s already connected",
+			c.exchangeName, c.Name)
+	}
+
+	c.ShutdownC = make(chan struct{})
+	err := c.connector(c)
+	if err != nil {
+		close(c.ShutdownC)
+		c.lastError = err.Error()
+		return err
+	}
+
+	c.connected = true
+	c.lastConnected = time.Now()
+	c.lastError = ""
+	return nil
+}
+
+// Shutdown closes the connection and waits for its routines to stop
+func (c *WebsocketConnection) Shutdown() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if !c.connected {
+		return ErrWebsocketConnectionNotConnected
+	}
+
+	done := make(chan struct{})
+	go func() {
+		close(c.ShutdownC)
+		c.Wg.Wait()
+		close(done)
+	}()
+
+	c.connected = false
+	select {
+	case <-done:
+		return nil
+	case <-time.After(5 * time.Second):
+		return fmt.Errorf("%s - websocket connection %s routines failed to shutdown",
+			c.exchangeName, c.Name)
+	}
+}
+
+// Reconnect shuts down the connection if it is still connected and dials it
+// again, counting the attempt against the connection
+func (c *WebsocketConnection) Reconnect() error {
+	if c.IsConnected() {
+		err := c.Shutdown()
+		if err != nil {
+			return err
+		}
+	}
+
+	c.m.Lock()
+	c.reconnects++
+	c.m.Unlock()
+	return c.Connect()
+}
+
+// Failed records an error from the connection's read routine, returning the
+// error to send to the data handler so the connection is reconnected
+func (c *WebsocketConnection) Failed(err error) error {
+	c.m.Lock()
+	c.lastError = err.Error()
+	c.m.Unlock()
+	return &WebsocketConnectionError{
+		Exchange:   c.exchangeName,
+		Connection: c.Name,
+		Err:        err,
+	}
+}
+
+// IsConnected returns whether the connection is connected
+func (c *WebsocketConnection) IsConnected() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.connected
+}
+
+// GetStatus returns the state of the connection
+func (c *WebsocketConnection) GetStatus() WebsocketConnectionStatus {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	status := WebsocketConnectionStatus{
+		Name:          c.Name,
+		URL:           c.URL,
+		Authenticated: c.Authenticated,
+		Connected:     c.connected,
+		Reconnects:    c.reconnects,
+		LastConnected: c.lastConnected,
+		LastError:     c.lastError,
+	}
+	for x := range c.Pairs {
+		status.Pairs = append(status.Pairs, c.Pairs[x].Pair().String())
+	}
+	return status
+}
@@ -0,0 +1,134 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestGetPairGroups(t *testing.T) {
+	b := Base{
+		Name:                     "testExchange",
+		EnabledPairs:             []string{"BTC-USD", "LTC-USD", "ETH-USD", "XRP-USD"},
+		ConfigCurrencyPairFormat: config.CurrencyPairFormatConfig{Delimiter: "-"},
+	}
+	b.WebsocketInit()
+	pairs := b.GetEnabledCurrencies()
+
+	b.WebsocketConnectionSetup(nil)
+	groups := b.Websocket.GetPairGroups(pairs)
+	if len(groups) != 1 || len(groups[0]) != 4 {
+		t.Errorf("Test failed - GetPairGroups() expected a single group received %v", groups)
+	}
+
+	b.WebsocketConnectionSetup(&config.WebsocketConnectionConfig{
+		PairGroups:         []string{"ETH-USD,DOGE-USD"},
+		PairsPerConnection: 2,
+	})
+	groups = b.Websocket.GetPairGroups(pairs)
+	if len(groups) != 3 || len(groups[0]) != 1 ||
+		!groups[0][0].Equal(pair.NewCurrencyPairDelimiter("ETH-USD", "-"), true) ||
+		len(groups[1]) != 2 || len(groups[2]) != 1 {
+		t.Errorf("Test failed - GetPairGroups() unexpected groups %v", groups)
+	}
+}
+
+func TestWebsocketConnections(t *testing.T) {
+	var b Base
+	b.WebsocketInit()
+	err := b.WebsocketSetup(func() error { return nil }, "testExchange", true,
+		"testDefaultURL", "testRunningURL")
+	if err != nil {
+		t.Fatal("Test failed - WebsocketSetup() error", err)
+	}
+
+	dials := make(map[string]int)
+	failPrivate := true
+	connector := func(conn *WebsocketConnection) error {
+		if conn.Name == WebsocketConnectionPrivate && failPrivate {
+			return errors.New("auth failed")
+		}
+		dials[conn.Name]++
+		conn.Wg.Add(1)
+		go func() {
+			defer conn.Wg.Done()
+			<-conn.ShutdownC
+		}()
+		return nil
+	}
+
+	err = b.Websocket.AddConnection(&WebsocketConnection{Name: WebsocketConnectionPublic}, connector)
+	if err != nil {
+		t.Fatal("Test failed - AddConnection() error", err)
+	}
+
+	err = b.Websocket.AddConnection(&WebsocketConnection{Name: WebsocketConnectionPublic}, connector)
+	if err != ErrWebsocketConnectionExists {
+		t.Error("Test failed - AddConnection() expected connection exists error", err)
+	}
+
+	err = b.Websocket.AddConnection(&WebsocketConnection{Name: WebsocketConnectionPrivate,
+		Authenticated: true}, connector)
+	if err != nil {
+		t.Fatal("Test failed - AddConnection() error", err)
+	}
+
+	err = b.Websocket.Connect()
+	if err != nil {
+		t.Fatal("Test failed - Connect() error", err)
+	}
+	<-b.Websocket.Connected
+
+	connErr, ok := (<-b.Websocket.DataHandler).(*WebsocketConnectionError)
+	if !ok || connErr.Connection != WebsocketConnectionPrivate {
+		t.Fatalf("Test failed - Connect() expected private connection error received %v", connErr)
+	}
+
+	public, err := b.Websocket.GetConnection(WebsocketConnectionPublic)
+	if err != nil || !public.IsConnected() {
+		t.Fatal("Test failed - GetConnection() public connection not connected", err)
+	}
+
+	private, err := b.Websocket.GetConnection(WebsocketConnectionPrivate)
+	if err != nil || private.IsConnected() || private.GetStatus().LastError != "auth failed" {
+		t.Fatal("Test failed - GetConnection() unexpected private connection state", err)
+	}
+
+	failPrivate = false
+	err = private.Reconnect()
+	if err != nil {
+		t.Fatal("Test failed - Reconnect() error", err)
+	}
+
+	status := private.GetStatus()
+	if !status.Connected || status.Reconnects != 1 || status.LastError != "" {
+		t.Errorf("Test failed - Reconnect() unexpected status %+v", status)
+	}
+
+	if dials[WebsocketConnectionPublic] != 1 {
+		t.Error("Test failed - Reconnect() reconnected the public connection")
+	}
+
+	if _, ok := public.Failed(errors.New("read failed")).(*WebsocketConnectionError); !ok {
+		t.Error("Test failed - Failed() expected a connection error")
+	}
+
+	if len(b.Websocket.GetConnectionStatus()) != 2 {
+		t.Error("Test failed - GetConnectionStatus() expected 2 connections")
+	}
+
+	err = b.Websocket.Shutdown()
+	if err != nil {
+		t.Fatal("Test failed - Shutdown() error", err)
+	}
+
+	if public.IsConnected() || private.IsConnected() {
+		t.Error("Test failed - Shutdown() named connections still connected")
+	}
+
+	if _, err = b.Websocket.GetConnection("blah"); err != ErrWebsocketConnectionNotFound {
+		t.Error("Test failed - GetConnection() expected not found error", err)
+	}
+}
@@ -0,0 +1,163 @@
+package exchange
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// Websocket keepalive methods
+const (
+	// WebsocketKeepaliveNone leaves keepalive to the websocket library, which
+	// answers protocol pings from the server
+	WebsocketKeepaliveNone = "none"
+	// WebsocketKeepalivePing sends protocol ping frames each interval
+	WebsocketKeepalivePing = "ping"
+	// WebsocketKeepaliveText sends the text payload each interval
+	WebsocketKeepaliveText = "text"
+	// WebsocketKeepalivePong answers text pings sent by the server, replacing
+	// the payload in the ping with the response
+	WebsocketKeepalivePong = "pong"
+)
+
+const websocketKeepaliveWriteTimeout = time.Second * 5
+
+var (
+	// ErrUnsupportedWebsocketKeepalive is returned when setting an unknown
+	// websocket keepalive method
+	ErrUnsupportedWebsocketKeepalive = errors.New("unsupported websocket keepalive method")
+	// ErrInvalidWebsocketKeepalive is returned when a websocket keepalive is
+	// missing the interval, payload or response its method requires
+	ErrInvalidWebsocketKeepalive = errors.New("websocket keepalive missing interval, payload or response")
+)
+
+// WebsocketKeepalive holds how a websocket connection is kept alive. Payload
+// is the ping sent for the ping and text methods and the server ping matched
+// for the pong method
+type WebsocketKeepalive struct {
+	Method   string
+	Interval time.Duration
+	Payload  string
+	Response string
+}
+
+// WebsocketKeepaliveSetup sets the websocket keepalive of the exchange from
+// its config, with unset values falling back to the exchange's defaults
+func (e *Base) WebsocketKeepaliveSetup(cfg *config.WebsocketKeepaliveConfig, defaults WebsocketKeepalive) error {
+	keepalive := defaults
+	if cfg != nil {
+		if cfg.Method != "" && cfg.Method != keepalive.Method {
+			keepalive = WebsocketKeepalive{Method: cfg.Method}
+		}
+		if cfg.Interval > 0 {
+			keepalive.Interval = cfg.Interval
+		}
+		if cfg.Payload != "" {
+			keepalive.Payload = cfg.Payload
+		}
+		if cfg.Response != "" {
+			keepalive.Response = cfg.Response
+		}
+	}
+	return e.Websocket.SetKeepalive(keepalive)
+}
+
+// SetKeepalive sets how the websocket connection is kept alive
+func (w *Websocket) SetKeepalive(k WebsocketKeepalive) error {
+	switch k.Method {
+	case "":
+		k.Method = WebsocketKeepaliveNone
+	case WebsocketKeepaliveNone:
+	case WebsocketKeepalivePing:
+		if k.Interval <= 0 {
+			return ErrInvalidWebsocketKeepalive
+		}
+	case WebsocketKeepaliveText:
+		if k.Interval <= 0 || k.Payload == "" {
+			return ErrInvalidWebsocketKeepalive
+		}
+	case WebsocketKeepalivePong:
+		if k.Payload == "" || k.Response == "" {
+			return ErrInvalidWebsocketKeepalive
+		}
+	default:
+		return ErrUnsupportedWebsocketKeepalive
+	}
+
+	w.keepaliveMtx.Lock()
+	w.keepalive = k
+	w.keepaliveMtx.Unlock()
+	return nil
+}
+
+// GetKeepalive returns how the websocket connection is kept alive
+func (w *Websocket) GetKeepalive() WebsocketKeepalive {
+	w.keepaliveMtx.Lock()
+	defer w.keepaliveMtx.Unlock()
+	if w.keepalive.Method == "" {
+		return WebsocketKeepalive{Method: WebsocketKeepaliveNone}
+	}
+	return w.keepalive
+}
+
+// StartKeepalive starts the routine sending the keepalive pings of the ping
+// and text methods on a connection until the websocket is shut down. Text
+// pings are sent with write, which should hold the exchange's write lock, or
+// directly on the connection when write is nil. Write errors are sent to the
+// data handler and stop the routine
+func (w *Websocket) StartKeepalive(conn *websocket.Conn, write func(data []byte) error) {
+	keepalive := w.GetKeepalive()
+	if keepalive.Method != WebsocketKeepalivePing &&
+		keepalive.Method != WebsocketKeepaliveText {
+		return
+	}
+
+	if write == nil {
+		write = func(data []byte) error {
+			return conn.WriteMessage(websocket.TextMessage, data)
+		}
+	}
+
+	w.Wg.Add(1)
+	go func() {
+		defer w.Wg.Done()
+		ticker := time.NewTicker(keepalive.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.ShutdownC:
+				return
+			case <-ticker.C:
+				var err error
+				if keepalive.Method == WebsocketKeepalivePing {
+					err = conn.WriteControl(websocket.PingMessage,
+						[]byte(keepalive.Payload),
+						time.Now().Add(websocketKeepaliveWriteTimeout))
+				} else {
+					err = write([]byte(keepalive.Payload))
+				}
+
+				if err != nil {
+					w.DataHandler <- err
+					return
+				}
+			}
+		}
+	}()
+}
+
+// KeepaliveResponse returns the response to a text ping from the server for
+// the pong method, along with whether the message was a ping
+func (w *Websocket) KeepaliveResponse(message []byte) ([]byte, bool) {
+	keepalive := w.GetKeepalive()
+	if keepalive.Method != WebsocketKeepalivePong ||
+		!bytes.Contains(message, []byte(keepalive.Payload)) {
+		return nil, false
+	}
+	return bytes.Replace(message, []byte(keepalive.Payload),
+		[]byte(keepalive.Response), 1), true
+}
@@ -0,0 +1,162 @@
+package exchange
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+func TestWebsocketKeepaliveSetup(t *testing.T) {
+	b := Base{}
+	b.WebsocketInit()
+
+	if b.Websocket.GetKeepalive().Method != WebsocketKeepaliveNone {
+		t.Error("Test Failed - GetKeepalive() default method incorrect")
+	}
+
+	err := b.WebsocketKeepaliveSetup(&config.WebsocketKeepaliveConfig{Method: "heartbeat"},
+		WebsocketKeepalive{})
+	if err != ErrUnsupportedWebsocketKeepalive {
+		t.Error("Test Failed - WebsocketKeepaliveSetup() unsupported method error", err)
+	}
+
+	err = b.WebsocketKeepaliveSetup(&config.WebsocketKeepaliveConfig{Method: WebsocketKeepaliveText},
+		WebsocketKeepalive{})
+	if err != ErrInvalidWebsocketKeepalive {
+		t.Error("Test Failed - WebsocketKeepaliveSetup() missing payload error", err)
+	}
+
+	defaults := WebsocketKeepalive{
+		Method:   WebsocketKeepaliveText,
+		Interval: time.Second * 30,
+		Payload:  "ping",
+	}
+	err = b.WebsocketKeepaliveSetup(&config.WebsocketKeepaliveConfig{Interval: time.Second * 10},
+		defaults)
+	if err != nil {
+		t.Fatal("Test Failed - WebsocketKeepaliveSetup() error", err)
+	}
+
+	keepalive := b.Websocket.GetKeepalive()
+	if keepalive.Method != WebsocketKeepaliveText || keepalive.Interval != time.Second*10 ||
+		keepalive.Payload != "ping" {
+		t.Errorf("Test Failed - WebsocketKeepaliveSetup() unexpected keepalive %+v", keepalive)
+	}
+
+	// Changing the method drops the default payload of the previous method
+	err = b.WebsocketKeepaliveSetup(&config.WebsocketKeepaliveConfig{
+		Method:   WebsocketKeepalivePing,
+		Interval: time.Second * 15,
+	}, defaults)
+	if err != nil {
+		t.Fatal("Test Failed - WebsocketKeepaliveSetup() ping error", err)
+	}
+
+	keepalive = b.Websocket.GetKeepalive()
+	if keepalive.Method != WebsocketKeepalivePing || keepalive.Payload != "" {
+		t.Errorf("Test Failed - WebsocketKeepaliveSetup() unexpected keepalive %+v", keepalive)
+	}
+}
+
+func TestKeepaliveResponse(t *testing.T) {
+	b := Base{}
+	b.WebsocketInit()
+
+	if _, ok := b.Websocket.KeepaliveResponse([]byte(`{"ping":1337}`)); ok {
+		t.Error("Test Failed - KeepaliveResponse() answered without the pong method")
+	}
+
+	err := b.Websocket.SetKeepalive(WebsocketKeepalive{
+		Method:   WebsocketKeepalivePong,
+		Payload:  `"ping"`,
+		Response: `"pong"`,
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SetKeepalive() error", err)
+	}
+
+	pong, ok := b.Websocket.KeepaliveResponse([]byte(`{"ping":1337}`))
+	if !ok || string(pong) != `{"pong":1337}` {
+		t.Errorf("Test Failed - KeepaliveResponse() unexpected response %s", pong)
+	}
+
+	if _, ok = b.Websocket.KeepaliveResponse([]byte(`{"ch":"market.btcusdt.depth"}`)); ok {
+		t.Error("Test Failed - KeepaliveResponse() answered a market data message")
+	}
+}
+
+func TestStartKeepalive(t *testing.T) {
+	received := make(chan string, 10)
+	record := func(msg string) {
+		select {
+		case received <- msg:
+		default:
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.SetPingHandler(func(data string) error {
+			record("ping:" + data)
+			return nil
+		})
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			record("text:" + string(data))
+		}
+	}))
+	defer server.Close()
+
+	for _, method := range []string{WebsocketKeepaliveText, WebsocketKeepalivePing} {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		if err != nil {
+			t.Fatal("Test Failed - Dial() error", err)
+		}
+
+		b := Base{}
+		b.WebsocketInit()
+		b.Websocket.ShutdownC = make(chan struct{})
+		b.Websocket.DataHandler = make(chan interface{}, 1)
+		err = b.Websocket.SetKeepalive(WebsocketKeepalive{
+			Method:   method,
+			Interval: time.Millisecond * 10,
+			Payload:  "hello",
+		})
+		if err != nil {
+			t.Fatal("Test Failed - SetKeepalive() error", err)
+		}
+
+		b.Websocket.StartKeepalive(conn, nil)
+		timeout := time.After(time.Second * 5)
+	wait:
+		for {
+			select {
+			case msg := <-received:
+				// Keepalives of the previous connection may still arrive
+				if msg == method+":hello" {
+					break wait
+				}
+			case <-timeout:
+				t.Errorf("Test Failed - StartKeepalive() %s no keepalive sent", method)
+				break wait
+			}
+		}
+
+		close(b.Websocket.ShutdownC)
+		b.Websocket.Wg.Wait()
+		conn.Close()
+	}
+}
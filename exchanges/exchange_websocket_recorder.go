@@ -0,0 +1,249 @@
+package exchange
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+// Orderbook record types
+const (
+	OrderbookRecordSnapshot = "snapshot"
+	OrderbookRecordUpdate   = "update"
+	OrderbookRecordUpdateID = "update_id"
+	OrderbookRecordResync   = "resync"
+)
+
+// ErrOrderbookRecordUnknown is returned when replaying a record of an unknown
+// type
+var ErrOrderbookRecordUnknown = errors.New("unknown orderbook record type")
+
+// OrderbookRecord holds a single orderbook snapshot or delta as it was passed
+// to the local websocket orderbook cache. Error holds the error returned when
+// the record was applied, if any
+type OrderbookRecord struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Type      string            `json:"type"`
+	Exchange  string            `json:"exchange"`
+	Pair      pair.CurrencyPair `json:"pair"`
+	AssetType string            `json:"assetType"`
+	Action    string            `json:"action,omitempty"`
+	Updated   time.Time         `json:"updated,omitempty"`
+	Bids      []orderbook.Item  `json:"bids"`
+	Asks      []orderbook.Item  `json:"asks"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// OrderbookRecorder archives the orderbook snapshots and deltas applied to a
+// local websocket orderbook cache to a file, one JSON record per line
+type OrderbookRecorder struct {
+	file *os.File
+	enc  *json.Encoder
+	m    sync.Mutex
+}
+
+// NewOrderbookRecorder returns an orderbook recorder appending to the file at
+// path, the file is created if it doesn't exist
+func NewOrderbookRecorder(path string) (*OrderbookRecorder, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderbookRecorder{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Record writes an orderbook record to the archive
+func (r *OrderbookRecorder) Record(rec OrderbookRecord) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.enc.Encode(rec)
+}
+
+// Close closes the archive file
+func (r *OrderbookRecorder) Close() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.file.Close()
+}
+
+// SetRecorder sets the recorder the orderbook snapshots and deltas applied to
+// the cache are archived with, nil stops recording
+func (w *WebsocketOrderbookLocal) SetRecorder(r *OrderbookRecorder) {
+	w.recorderMtx.Lock()
+	w.recorder = r
+	w.recorderMtx.Unlock()
+}
+
+// StopRecording stops recording and closes the recorder, if set
+func (w *WebsocketOrderbookLocal) StopRecording() error {
+	w.recorderMtx.Lock()
+	defer w.recorderMtx.Unlock()
+
+	if w.recorder == nil {
+		return nil
+	}
+
+	err := w.recorder.Close()
+	w.recorder = nil
+	return err
+}
+
+// record archives an applied orderbook record when a recorder is set
+func (w *WebsocketOrderbookLocal) record(rec OrderbookRecord, applyErr error) {
+	w.recorderMtx.Lock()
+	defer w.recorderMtx.Unlock()
+
+	if w.recorder == nil {
+		return
+	}
+
+	rec.Timestamp = time.Now()
+	if applyErr != nil {
+		rec.Error = applyErr.Error()
+	}
+
+	err := w.recorder.Record(rec)
+	if err != nil {
+		// Stop recording rather than archive a stream with gaps
+		w.recorder.Close()
+		w.recorder = nil
+	}
+}
+
+func copyOrderbookItems(items []orderbook.Item) []orderbook.Item {
+	if items == nil {
+		return nil
+	}
+	return append([]orderbook.Item(nil), items...)
+}
+
+// OrderbookRecordReader reads orderbook records from an archive one at a time
+// so large archives don't have to be held in memory
+type OrderbookRecordReader struct {
+	scanner *bufio.Scanner
+	read    int
+}
+
+// NewOrderbookRecordReader returns an orderbook record reader for an archive
+func NewOrderbookRecordReader(r io.Reader) *OrderbookRecordReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	return &OrderbookRecordReader{scanner: scanner}
+}
+
+// Next returns the next orderbook record in the archive, io.EOF is returned
+// once all records have been read
+func (o *OrderbookRecordReader) Next() (OrderbookRecord, error) {
+	for o.scanner.Scan() {
+		if len(o.scanner.Bytes()) == 0 {
+			continue
+		}
+
+		o.read++
+		var rec OrderbookRecord
+		err := json.Unmarshal(o.scanner.Bytes(), &rec)
+		if err != nil {
+			return OrderbookRecord{}, fmt.Errorf("orderbook record %d: %s", o.read, err)
+		}
+		return rec, nil
+	}
+
+	err := o.scanner.Err()
+	if err != nil {
+		return OrderbookRecord{}, err
+	}
+	return OrderbookRecord{}, io.EOF
+}
+
+// ReadOrderbookRecords reads all orderbook records from an archive
+func ReadOrderbookRecords(r io.Reader) ([]OrderbookRecord, error) {
+	var records []OrderbookRecord
+	reader := NewOrderbookRecordReader(r)
+	for {
+		rec, err := reader.Next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// ApplyOrderbookRecord applies an orderbook record to a local websocket
+// orderbook cache
+func ApplyOrderbookRecord(w *WebsocketOrderbookLocal, rec OrderbookRecord) error {
+	switch rec.Type {
+	case OrderbookRecordSnapshot:
+		return w.LoadSnapshot(orderbook.Base{
+			Pair:         rec.Pair,
+			CurrencyPair: rec.Pair.Pair().String(),
+			Bids:         rec.Bids,
+			Asks:         rec.Asks,
+			LastUpdated:  rec.Updated,
+			AssetType:    rec.AssetType,
+		}, rec.Exchange)
+	case OrderbookRecordResync:
+		return w.Resync(orderbook.Base{
+			Pair:         rec.Pair,
+			CurrencyPair: rec.Pair.Pair().String(),
+			Bids:         rec.Bids,
+			Asks:         rec.Asks,
+			LastUpdated:  rec.Updated,
+			AssetType:    rec.AssetType,
+		}, rec.Exchange)
+	case OrderbookRecordUpdate:
+		return w.Update(rec.Bids, rec.Asks, rec.Pair, rec.Updated,
+			rec.Exchange, rec.AssetType)
+	case OrderbookRecordUpdateID:
+		return w.UpdateUsingID(rec.Bids, rec.Asks, rec.Pair, rec.Updated,
+			rec.Exchange, rec.AssetType, rec.Action)
+	}
+	return ErrOrderbookRecordUnknown
+}
+
+// ReplayOrderbook streams recorded orderbook snapshots and deltas from an
+// archive back through a local websocket orderbook cache, keeping the recorded
+// time between records divided by speed. A speed of zero or less replays as
+// fast as possible. Records which failed when recorded are expected to fail
+// again, any other error stops the replay. If applied is set it is called with
+// each record once it has been replayed
+func ReplayOrderbook(w *WebsocketOrderbookLocal, r io.Reader, speed float64, applied func(OrderbookRecord)) error {
+	reader := NewOrderbookRecordReader(r)
+	var last time.Time
+	for x := 1; ; x++ {
+		rec, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if x > 1 && speed > 0 {
+			wait := rec.Timestamp.Sub(last)
+			if wait > 0 {
+				time.Sleep(time.Duration(float64(wait) / speed))
+			}
+		}
+		last = rec.Timestamp
+
+		err = ApplyOrderbookRecord(w, rec)
+		if err != nil && rec.Error == "" {
+			return fmt.Errorf("orderbook record %d: %s", x, err)
+		}
+
+		if applied != nil {
+			applied(rec)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+func TestOrderbookRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "obrecord")
+	if err != nil {
+		t.Fatal("Test Failed - TempDir() error", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "orderbook.json")
+
+	recorder, err := NewOrderbookRecorder(path)
+	if err != nil {
+		t.Fatal("Test Failed - NewOrderbookRecorder() error", err)
+	}
+
+	p := pair.NewCurrencyPairFromString("LTCUSD")
+	var live WebsocketOrderbookLocal
+	live.SetRecorder(recorder)
+
+	updated := time.Now()
+	err = live.LoadSnapshot(orderbook.Base{
+		Pair:        p,
+		AssetType:   "SPOT",
+		LastUpdated: updated,
+		Bids:        []orderbook.Item{{Price: 99, Amount: 1}, {Price: 98, Amount: 2}},
+		Asks:        []orderbook.Item{{Price: 101, Amount: 1}, {Price: 102, Amount: 2}},
+	}, "RecordTest")
+	if err != nil {
+		t.Fatal("Test Failed - LoadSnapshot() error", err)
+	}
+
+	err = live.Update([]orderbook.Item{{Price: 97, Amount: 4}},
+		[]orderbook.Item{{Price: 101, Amount: 3}},
+		p, updated.Add(time.Millisecond), "RecordTest", "SPOT")
+	if err != nil {
+		t.Fatal("Test Failed - Update() error", err)
+	}
+
+	err = live.Update(nil, nil, p, updated.Add(2*time.Millisecond), "RecordTest", "SPOT")
+	if err == nil {
+		t.Fatal("Test Failed - Update() expected error for empty update")
+	}
+
+	live.SetRecorder(nil)
+	err = recorder.Close()
+	if err != nil {
+		t.Fatal("Test Failed - Close() error", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal("Test Failed - Open() error", err)
+	}
+	defer f.Close()
+
+	records, err := ReadOrderbookRecords(f)
+	if err != nil {
+		t.Fatal("Test Failed - ReadOrderbookRecords() error", err)
+	}
+
+	if len(records) != 3 || records[0].Type != OrderbookRecordSnapshot ||
+		records[1].Type != OrderbookRecordUpdate || records[2].Error == "" {
+		t.Fatalf("Test Failed - ReadOrderbookRecords() unexpected records %+v", records)
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		t.Fatal("Test Failed - Seek() error", err)
+	}
+
+	var replayed WebsocketOrderbookLocal
+	var applied int
+	err = ReplayOrderbook(&replayed, f, 0, func(OrderbookRecord) { applied++ })
+	if err != nil {
+		t.Fatal("Test Failed - ReplayOrderbook() error", err)
+	}
+
+	if applied != 3 || len(replayed.ob) != 1 || len(replayed.ob[0].Bids) != 3 ||
+		replayed.ob[0].Asks[0].Amount != 3 {
+		t.Errorf("Test Failed - ReplayOrderbook() unexpected orderbook %+v", replayed.ob)
+	}
+
+	records[1].Type = "bogus"
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for x := range records {
+		err = enc.Encode(records[x])
+		if err != nil {
+			t.Fatal("Test Failed - Encode() error", err)
+		}
+	}
+
+	var failed WebsocketOrderbookLocal
+	err = ReplayOrderbook(&failed, &buf, 0, nil)
+	if err == nil {
+		t.Error("Test Failed - ReplayOrderbook() expected unknown record error")
+	}
+}
+
+func TestOrderbookRecordFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "obrecord")
+	if err != nil {
+		t.Fatal("Test Failed - TempDir() error", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "orderbook.json")
+
+	err = ioutil.WriteFile(path, nil, 0644)
+	if err != nil {
+		t.Fatal("Test Failed - WriteFile() error", err)
+	}
+
+	// A read only file makes every write fail
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal("Test Failed - Open() error", err)
+	}
+
+	var live WebsocketOrderbookLocal
+	live.SetRecorder(&OrderbookRecorder{file: f, enc: json.NewEncoder(f)})
+	err = live.LoadSnapshot(orderbook.Base{
+		Pair:      pair.NewCurrencyPairFromString("LTCUSD"),
+		AssetType: "SPOT",
+		Bids:      []orderbook.Item{{Price: 99, Amount: 1}},
+		Asks:      []orderbook.Item{{Price: 101, Amount: 1}},
+	}, "RecordFailTest")
+	if err != nil {
+		t.Fatal("Test Failed - LoadSnapshot() error", err)
+	}
+
+	if live.recorder != nil {
+		t.Error("Test Failed - record() recorder not stopped after write failure")
+	}
+
+	if err = f.Close(); err == nil {
+		t.Error("Test Failed - record() archive not closed after write failure")
+	}
+}
@@ -0,0 +1,200 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Websocket subscription channels, exchanges map these to their own channel
+// names
+const (
+	WebsocketChannelTicker    = "ticker"
+	WebsocketChannelOrderbook = "orderbook"
+	WebsocketChannelTrades    = "trades"
+)
+
+var (
+	// ErrWebsocketSubscriptionsNotSupported is returned when an exchange
+	// websocket does not support managing subscriptions at runtime
+	ErrWebsocketSubscriptionsNotSupported = errors.New("exchange websocket does not support runtime subscriptions")
+	// ErrWebsocketAlreadySubscribed is returned when subscribing to a channel
+	// and pair which is already subscribed
+	ErrWebsocketAlreadySubscribed = errors.New("websocket channel already subscribed")
+	// ErrWebsocketNotSubscribed is returned when unsubscribing from a channel
+	// and pair which is not subscribed
+	ErrWebsocketNotSubscribed = errors.New("websocket channel not subscribed")
+)
+
+// WebsocketSubscription holds a websocket channel subscription for a currency
+// pair. Depth is the number of orderbook levels, zero uses the exchange
+// default
+type WebsocketSubscription struct {
+	Channel string            `json:"channel"`
+	Pair    pair.CurrencyPair `json:"pair"`
+	Depth   int               `json:"depth,omitempty"`
+}
+
+// Equal returns whether two subscriptions are for the same channel and pair
+func (s WebsocketSubscription) Equal(sub WebsocketSubscription) bool {
+	return s.Channel == sub.Channel && s.Pair.Equal(sub.Pair, true)
+}
+
+// WebsocketSubscriber subscribes or unsubscribes a channel on a connected
+// exchange websocket
+type WebsocketSubscriber func(sub WebsocketSubscription) error
+
+// WebsocketSubscriptionSetup sets the websocket subscriptions from the
+// exchange configuration and the functions used to apply them. Unconfigured
+// channels default to all supported channels and unconfigured pairs default
+// to the enabled pairs. An unconfigured subscription orderbook depth defaults
+// to the orderbook depth of each pair
+func (e *Base) WebsocketSubscriptionSetup(cfg *config.WebsocketSubscriptionConfig,
+	supported []string,
+	subscribe,
+	unsubscribe WebsocketSubscriber) error {
+
+	var channels []string
+	pairs := e.GetEnabledCurrencies()
+	var depth int
+	var disableTrades bool
+	if cfg != nil {
+		channels = cfg.Channels
+		if cfg.Pairs != "" {
+			pairs = e.toCanonicalPairs(pair.FormatPairs(common.SplitStrings(cfg.Pairs, ","),
+				e.ConfigCurrencyPairFormat.Delimiter,
+				e.ConfigCurrencyPairFormat.Index))
+		}
+		depth = cfg.OrderbookDepth
+		disableTrades = cfg.DisableTrades
+	}
+
+	if len(channels) == 0 {
+		channels = supported
+	}
+
+	var subs []WebsocketSubscription
+	for _, channel := range channels {
+		channel = common.StringToLower(channel)
+		if !common.StringDataCompare(supported, channel) {
+			return fmt.Errorf("%s websocket channel %s not supported, supported channels %v",
+				e.Name, channel, supported)
+		}
+
+		if disableTrades && channel == WebsocketChannelTrades {
+			continue
+		}
+
+		for x := range pairs {
+			sub := WebsocketSubscription{Channel: channel, Pair: pairs[x]}
+			if channel == WebsocketChannelOrderbook {
+				sub.Depth = depth
+				if sub.Depth == 0 {
+					sub.Depth = e.GetOrderbookDepth(pairs[x])
+				}
+			}
+			subs = append(subs, sub)
+		}
+	}
+
+	e.Websocket.subscriptionsMtx.Lock()
+	e.Websocket.subscriptions = subs
+	e.Websocket.subscriber = subscribe
+	e.Websocket.unsubscriber = unsubscribe
+	e.Websocket.subscriptionsMtx.Unlock()
+	return nil
+}
+
+// SupportsSubscriptions returns whether the exchange websocket manages its
+// subscriptions through the configured channels and pairs
+func (w *Websocket) SupportsSubscriptions() bool {
+	w.subscriptionsMtx.Lock()
+	defer w.subscriptionsMtx.Unlock()
+	return w.subscriber != nil
+}
+
+// GetSubscriptions returns the current websocket subscriptions
+func (w *Websocket) GetSubscriptions() []WebsocketSubscription {
+	w.subscriptionsMtx.Lock()
+	defer w.subscriptionsMtx.Unlock()
+	return append([]WebsocketSubscription(nil), w.subscriptions...)
+}
+
+// SubscribeToChannels applies all subscriptions, called by exchange connectors
+// once connected so the subscriptions are restored on every reconnect
+func (w *Websocket) SubscribeToChannels() error {
+	w.subscriptionsMtx.Lock()
+	defer w.subscriptionsMtx.Unlock()
+
+	if w.subscriber == nil {
+		return ErrWebsocketSubscriptionsNotSupported
+	}
+
+	for x := range w.subscriptions {
+		err := w.subscriber(w.subscriptions[x])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe adds a subscription, subscribing immediately when connected. The
+// subscription is kept across reconnects until unsubscribed
+func (w *Websocket) Subscribe(sub WebsocketSubscription) error {
+	w.subscriptionsMtx.Lock()
+	defer w.subscriptionsMtx.Unlock()
+
+	if w.subscriber == nil {
+		return ErrWebsocketSubscriptionsNotSupported
+	}
+
+	sub.Channel = common.StringToLower(sub.Channel)
+	for x := range w.subscriptions {
+		if w.subscriptions[x].Equal(sub) {
+			return ErrWebsocketAlreadySubscribed
+		}
+	}
+
+	if w.connected {
+		err := w.subscriber(sub)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.subscriptions = append(w.subscriptions, sub)
+	return nil
+}
+
+// Unsubscribe removes a subscription, unsubscribing immediately when
+// connected
+func (w *Websocket) Unsubscribe(sub WebsocketSubscription) error {
+	w.subscriptionsMtx.Lock()
+	defer w.subscriptionsMtx.Unlock()
+
+	if w.unsubscriber == nil {
+		return ErrWebsocketSubscriptionsNotSupported
+	}
+
+	sub.Channel = common.StringToLower(sub.Channel)
+	for x := range w.subscriptions {
+		if !w.subscriptions[x].Equal(sub) {
+			continue
+		}
+
+		if w.connected {
+			err := w.unsubscriber(w.subscriptions[x])
+			if err != nil {
+				return err
+			}
+		}
+
+		w.subscriptions = append(w.subscriptions[:x], w.subscriptions[x+1:]...)
+		return nil
+	}
+	return ErrWebsocketNotSubscribed
+}
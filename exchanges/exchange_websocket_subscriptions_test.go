@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestWebsocketSubscriptions(t *testing.T) {
+	b := Base{
+		Name:                     "testExchange",
+		EnabledPairs:             []string{"BTC-USD", "LTC-USD"},
+		ConfigCurrencyPairFormat: config.CurrencyPairFormatConfig{Delimiter: "-"},
+	}
+	b.WebsocketInit()
+
+	var subscribed, unsubscribed []WebsocketSubscription
+	subscribe := func(sub WebsocketSubscription) error {
+		subscribed = append(subscribed, sub)
+		return nil
+	}
+	unsubscribe := func(sub WebsocketSubscription) error {
+		unsubscribed = append(unsubscribed, sub)
+		return nil
+	}
+	supported := []string{WebsocketChannelTicker, WebsocketChannelOrderbook, WebsocketChannelTrades}
+
+	if b.Websocket.SupportsSubscriptions() {
+		t.Error("Test failed - SupportsSubscriptions() supported before setup")
+	}
+
+	err := b.WebsocketSubscriptionSetup(&config.WebsocketSubscriptionConfig{
+		Channels: []string{"candles"},
+	}, supported, subscribe, unsubscribe)
+	if err == nil {
+		t.Error("Test failed - WebsocketSubscriptionSetup() unsupported channel error")
+	}
+
+	err = b.WebsocketSubscriptionSetup(nil, supported, subscribe, unsubscribe)
+	if err != nil {
+		t.Fatal("Test failed - WebsocketSubscriptionSetup() error", err)
+	}
+	if !b.Websocket.SupportsSubscriptions() {
+		t.Error("Test failed - SupportsSubscriptions() not supported after setup")
+	}
+	if len(b.Websocket.GetSubscriptions()) != 6 {
+		t.Errorf("Test failed - WebsocketSubscriptionSetup() expected 6 default subscriptions received %d",
+			len(b.Websocket.GetSubscriptions()))
+	}
+
+	err = b.WebsocketSubscriptionSetup(&config.WebsocketSubscriptionConfig{
+		Pairs:          "BTC-USD",
+		OrderbookDepth: 25,
+		DisableTrades:  true,
+	}, supported, subscribe, unsubscribe)
+	if err != nil {
+		t.Fatal("Test failed - WebsocketSubscriptionSetup() error", err)
+	}
+
+	subs := b.Websocket.GetSubscriptions()
+	if len(subs) != 2 || subs[0].Channel != WebsocketChannelTicker ||
+		subs[1].Channel != WebsocketChannelOrderbook || subs[1].Depth != 25 {
+		t.Errorf("Test failed - WebsocketSubscriptionSetup() unexpected subscriptions %v", subs)
+	}
+
+	err = b.Websocket.SubscribeToChannels()
+	if err != nil || len(subscribed) != 2 {
+		t.Error("Test failed - SubscribeToChannels() error", err)
+	}
+
+	ltc := WebsocketSubscription{Channel: "Trades", Pair: pair.NewCurrencyPair("LTC", "USD")}
+	err = b.Websocket.Subscribe(ltc)
+	if err != nil {
+		t.Error("Test failed - Subscribe() error", err)
+	}
+	if len(subscribed) != 2 {
+		t.Error("Test failed - Subscribe() subscribed whilst disconnected")
+	}
+
+	err = b.Websocket.Subscribe(ltc)
+	if err != ErrWebsocketAlreadySubscribed {
+		t.Error("Test failed - Subscribe() duplicate error", err)
+	}
+
+	b.Websocket.connected = true
+	err = b.Websocket.Unsubscribe(ltc)
+	if err != nil || len(unsubscribed) != 1 || len(b.Websocket.GetSubscriptions()) != 2 {
+		t.Error("Test failed - Unsubscribe() error", err)
+	}
+
+	err = b.Websocket.Unsubscribe(ltc)
+	if err != ErrWebsocketNotSubscribed {
+		t.Error("Test failed - Unsubscribe() not subscribed error", err)
+	}
+}
@@ -309,3 +309,43 @@ func TestUpdate(t *testing.T) {
 		t.Error("test failed - OrderbookUpdate error", err)
 	}
 }
+
+func TestOrderbookResync(t *testing.T) {
+	p := pair.NewCurrencyPairFromString("ETHUSD")
+	var local WebsocketOrderbookLocal
+	if _, ok := local.GetOrderbook(p, "SPOT"); ok {
+		t.Error("test failed - GetOrderbook() found an orderbook in an empty cache")
+	}
+
+	snapshot := orderbook.Base{
+		Pair:      p,
+		AssetType: "SPOT",
+		Bids:      []orderbook.Item{{Price: 99, Amount: 1}},
+		Asks:      []orderbook.Item{{Price: 101, Amount: 1}},
+	}
+	err := local.Resync(snapshot, "ResyncTest")
+	if err != nil {
+		t.Fatal("test failed - Resync() error", err)
+	}
+
+	snapshot.Bids = []orderbook.Item{{Price: 100, Amount: 2}}
+	err = local.Resync(snapshot, "ResyncTest")
+	if err != nil {
+		t.Fatal("test failed - Resync() error", err)
+	}
+
+	result, ok := local.GetOrderbook(p, "SPOT")
+	if !ok || len(result.Bids) != 1 || result.Bids[0].Price != 100 {
+		t.Errorf("test failed - Resync() unexpected orderbook %+v", result)
+	}
+
+	result.Bids[0].Amount = 5
+	result, _ = local.GetOrderbook(p, "SPOT")
+	if result.Bids[0].Amount != 2 {
+		t.Error("test failed - GetOrderbook() returned the cached levels rather than a copy")
+	}
+
+	if err = local.Resync(orderbook.Base{Pair: p, AssetType: "SPOT"}, "ResyncTest"); err == nil {
+		t.Error("test failed - Resync() accepted an empty snapshot")
+	}
+}
@@ -76,7 +76,7 @@ func (e *EXMO) SetDefaults() {
 }
 
 // Setup takes in the supplied exchange configuration details and sets params
-func (e *EXMO) Setup(exch config.ExchangeConfig) {
+func (e *EXMO) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		e.SetEnabled(false)
 	} else {
@@ -92,25 +92,34 @@ func (e *EXMO) Setup(exch config.ExchangeConfig) {
 		e.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := e.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = e.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = e.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = e.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = e.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = e.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = e.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetTrades returns the trades for a symbol or symbols
@@ -343,7 +352,7 @@ func (e *EXMO) SendAuthenticatedHTTPRequest(method, endpoint string, vals url.Va
 	}
 
 	if e.Nonce.Get() == 0 {
-		e.Nonce.Set(time.Now().UnixNano())
+		e.Nonce.Set(e.GetAdjustedTime().UnixNano())
 	} else {
 		e.Nonce.Inc()
 	}
@@ -1,34 +1,46 @@
 package exmo
 
 import (
+	"context"
 	"errors"
 	"log"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the EXMO exchange
+func init() {
+	exchange.Register("EXMO", func() exchange.IBotExchange { return new(EXMO) })
+}
+
 // Start starts the EXMO go routine
-func (e *EXMO) Start(wg *sync.WaitGroup) {
+func (e *EXMO) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		e.Run()
+		e.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the EXMO wrapper
-func (e *EXMO) Run() {
+func (e *EXMO) Run(ctx context.Context) {
 	if e.Verbose {
 		log.Printf("%s polling delay: %ds.\n", e.GetName(), e.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", e.GetName(), len(e.EnabledPairs), e.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := e.GetPairSettings()
 	if err != nil {
 		log.Printf("%s Failed to get available products.\n", e.GetName())
@@ -44,6 +56,11 @@ func (e *EXMO) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (e *EXMO) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (e *EXMO) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
@@ -130,7 +147,7 @@ func (e *EXMO) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook
 		}
 
 		orderBook.Bids = obItems
-		orderbook.ProcessOrderbook(e.Name, x, orderBook, assetType)
+		orderbook.ProcessOrderbook(e.Name, x, e.TruncateOrderbook(x, orderBook), assetType)
 	}
 	return orderbook.GetOrderbook(e.Name, p, assetType)
 }
@@ -145,22 +162,23 @@ func (e *EXMO) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 		return response, err
 	}
 
-	for x, y := range result.Balances {
-		var exchangeCurrency exchange.AccountCurrencyInfo
-		exchangeCurrency.CurrencyName = common.StringToUpper(x)
-		for z, w := range result.Reserved {
-			if z == x {
-				avail, _ := strconv.ParseFloat(y, 64)
-				reserved, _ := strconv.ParseFloat(w, 64)
-				exchangeCurrency.TotalValue = avail + reserved
-				exchangeCurrency.Hold = reserved
-			}
-		}
-		response.Currencies = append(response.Currencies, exchangeCurrency)
-	}
+	response.Currencies = convertUserInfo(result)
 	return response, nil
 }
 
+// convertUserInfo converts the available and reserved balances into account
+// currency info
+func convertUserInfo(info UserInfo) []exchange.AccountCurrencyInfo {
+	var currencies []exchange.AccountCurrencyInfo
+	for x, y := range info.Balances {
+		free, _ := strconv.ParseFloat(y, 64)
+		reserved, _ := strconv.ParseFloat(info.Reserved[x], 64)
+		currencies = append(currencies,
+			exchange.NewAccountCurrencyInfo(common.StringToUpper(x), free, reserved))
+	}
+	return currencies
+}
+
 // GetExchangeFundTransferHistory returns funding history, deposits and
 // withdrawals
 func (e *EXMO) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error) {
@@ -168,6 +186,18 @@ func (e *EXMO) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error)
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (e *EXMO) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (e *EXMO) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (e *EXMO) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -175,6 +205,12 @@ func (e *EXMO) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exch
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (e *EXMO) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (e *EXMO) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -191,6 +227,18 @@ func (e *EXMO) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (e *EXMO) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, e.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (e *EXMO) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, e.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (e *EXMO) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -202,6 +250,11 @@ func (e *EXMO) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, error)
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (e *EXMO) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (e *EXMO) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -219,9 +272,14 @@ func (e *EXMO) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount floa
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (e *EXMO) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (e *EXMO) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (e *EXMO) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -230,6 +288,11 @@ func (e *EXMO) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (e *EXMO) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (e *EXMO) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return e.GetFee(feeBuilder)
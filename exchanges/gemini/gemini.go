@@ -41,6 +41,15 @@ const (
 	geminiWithdraw           = "withdraw/"
 	geminiHeartbeat          = "heartbeat"
 	geminiVolume             = "notionalvolume"
+	geminiTransfers          = "transfers"
+
+	// geminiTransfersLimit is the maximum number of transfers returned per
+	// request
+	geminiTransfersLimit = 50
+
+	// geminiMaxTradesLimit is the maximum number of public trades returned per
+	// request
+	geminiMaxTradesLimit = 500
 
 	// gemini limit rates
 	geminiAuthRate   = 600
@@ -116,11 +125,12 @@ func (g *Gemini) SetDefaults() {
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	g.APIUrlDefault = geminiAPIURL
 	g.APIUrl = g.APIUrlDefault
+	g.SetFeeTable(exchange.FeeTable{Maker: 0.001, Taker: 0.0035})
 	g.WebsocketInit()
 }
 
 // Setup sets exchange configuration parameters
-func (g *Gemini) Setup(exch config.ExchangeConfig) {
+func (g *Gemini) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		g.SetEnabled(false)
 	} else {
@@ -137,28 +147,37 @@ func (g *Gemini) Setup(exch config.ExchangeConfig) {
 
 		err := g.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = g.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = g.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = g.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		if exch.UseSandbox {
 			g.APIUrl = geminiSandboxAPIURL
 		}
+		err = g.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
+		}
 		err = g.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = g.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetSymbols returns all available symbols for trading
@@ -391,6 +410,20 @@ func (g *Gemini) GetTradeHistory(currencyPair string, timestamp int64) ([]TradeH
 		g.SendAuthenticatedHTTPRequest("POST", geminiMyTrades, request, &response)
 }
 
+// GetTransfers returns up to limit deposits and withdrawals on or after the
+// timestamp in milliseconds, in ascending order
+func (g *Gemini) GetTransfers(timestamp int64, limit int) ([]Transfer, error) {
+	response := []Transfer{}
+	request := make(map[string]interface{})
+	request["timestamp"] = timestamp
+	if limit > 0 {
+		request["limit_transfers"] = limit
+	}
+
+	return response,
+		g.SendAuthenticatedHTTPRequest("POST", geminiTransfers, request, &response)
+}
+
 // GetNotionalVolume returns  the volume in price currency that has been traded across all pairs over a period of 30 days
 func (g *Gemini) GetNotionalVolume() (NotionalVolume, error) {
 	response := NotionalVolume{}
@@ -429,23 +462,51 @@ func (g *Gemini) GetDepositAddress(depositAddlabel, currency string) (DepositAdd
 	return response, nil
 }
 
-// WithdrawCrypto withdraws crypto currency to a whitelisted address
+// WithdrawCrypto withdraws crypto currency to a whitelisted address. If the
+// account requires approved addresses and the destination has not been
+// approved, an *ApprovedAddressError is returned
 func (g *Gemini) WithdrawCrypto(address, currency string, amount float64) (WithdrawalAddress, error) {
 	response := WithdrawalAddress{}
 	request := make(map[string]interface{})
 	request["address"] = address
 	request["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
 
-	err := g.SendAuthenticatedHTTPRequest("POST", geminiWithdraw+currency, nil, &response)
+	err := g.SendAuthenticatedHTTPRequest("POST", geminiWithdraw+common.StringToLower(currency), request, &response)
 	if err != nil {
 		return response, err
 	}
-	if response.Message != "" {
-		return response, errors.New(response.Message)
+
+	if response.Result == "error" || response.Message != "" {
+		if isApprovedAddressRejection(response.Reason, response.Message) {
+			return response, &ApprovedAddressError{
+				Currency: common.StringToUpper(currency),
+				Address:  address,
+				Reason:   response.Message,
+			}
+		}
+
+		if response.Message != "" {
+			return response, errors.New(response.Message)
+		}
+		return response, errors.New(response.Reason)
 	}
 	return response, nil
 }
 
+// Error implements the error interface
+func (a *ApprovedAddressError) Error() string {
+	return fmt.Sprintf("%s withdrawal address %s has not been approved, add it to the account's approved address list via the Gemini website before withdrawing: %s",
+		a.Currency, a.Address, a.Reason)
+}
+
+// isApprovedAddressRejection returns whether a withdrawal error was caused by
+// the destination not being on the account's approved address list
+func isApprovedAddressRejection(reason, message string) bool {
+	text := common.StringToLower(reason + " " + message)
+	return common.StringContains(text, "approved") ||
+		common.StringContains(text, "whitelist")
+}
+
 // PostHeartbeat sends a maintenance heartbeat to the exchange for all heartbeat
 // maintaned sessions
 func (g *Gemini) PostHeartbeat() (string, error) {
@@ -488,7 +549,7 @@ func (g *Gemini) SendAuthenticatedHTTPRequest(method, path string, params map[st
 		}
 	}
 
-	PayloadJSON, err := common.JSONEncode(request)
+	PayloadJSON, err := exchange.CanonicalJSON(request)
 	if err != nil {
 		return errors.New("SendAuthenticatedHTTPRequest: Unable to JSON request")
 	}
@@ -497,26 +558,38 @@ func (g *Gemini) SendAuthenticatedHTTPRequest(method, path string, params map[st
 		log.Printf("Request JSON: %s\n", PayloadJSON)
 	}
 
+	signer, err := exchange.NewSigner(exchange.SignHMACSHA384, exchange.SignatureEncodingHex, g.APISecret)
+	if err != nil {
+		return err
+	}
+
 	PayloadBase64 := common.Base64Encode(PayloadJSON)
-	hmac := common.GetHMAC(common.HashSHA512_384, []byte(PayloadBase64), []byte(g.APISecret))
+	signature, err := signer.SignToString([]byte(PayloadBase64))
+	if err != nil {
+		return err
+	}
 
 	headers["X-GEMINI-APIKEY"] = g.APIKey
 	headers["X-GEMINI-PAYLOAD"] = PayloadBase64
-	headers["X-GEMINI-SIGNATURE"] = common.HexEncodeToString(hmac)
+	headers["X-GEMINI-SIGNATURE"] = signature
 
 	return g.SendPayload(method, g.APIUrl+"/v1/"+path, headers, strings.NewReader(""), result, true, g.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
 func (g *Gemini) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
+	if g.IsFeeEstimated(feeBuilder) {
+		return g.EstimateFee(feeBuilder)
+	}
+
 	var fee float64
 	switch feeBuilder.FeeType {
 	case exchange.CryptocurrencyTradeFee:
-		notionVolume, err := g.GetNotionalVolume()
+		var err error
+		fee, err = g.AccountTradingFee(feeBuilder, g.getAccountFees)
 		if err != nil {
 			return 0, err
 		}
-		fee = calculateTradingFee(notionVolume, feeBuilder.PurchasePrice, feeBuilder.Amount, feeBuilder.IsMaker)
 	case exchange.CryptocurrencyWithdrawalFee:
 		// TODO: no free transactions after 10; Need database to know how many trades have been done
 		// Could do via trade history, but would require analysis of response and dates to determine level of fee
@@ -530,13 +603,21 @@ func (g *Gemini) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return fee, nil
 }
 
-func calculateTradingFee(notionVolume NotionalVolume, purchasePrice, amount float64, isMaker bool) float64 {
-	var volumeFee float64
-	if isMaker {
-		volumeFee = (float64(notionVolume.MakerFee) / 100)
-	} else {
-		volumeFee = (float64(notionVolume.TakerFee) / 100)
+// getAccountFees retrieves the maker and taker fee rates of the account's
+// volume tier from its notional volume
+func (g *Gemini) getAccountFees() (exchange.AccountFees, error) {
+	volume, err := g.GetNotionalVolume()
+	if err != nil {
+		return exchange.AccountFees{}, err
 	}
+	return convertAccountFees(volume), nil
+}
 
-	return volumeFee * amount * purchasePrice
+// convertAccountFees converts the basis point fee rates of the notional
+// volume to account fees
+func convertAccountFees(volume NotionalVolume) exchange.AccountFees {
+	var fees exchange.AccountFees
+	fees.Maker = float64(volume.MakerFee) / 10000
+	fees.Taker = float64(volume.TakerFee) / 10000
+	return fees
 }
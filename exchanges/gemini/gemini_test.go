@@ -3,8 +3,10 @@ package gemini
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 )
@@ -96,6 +98,20 @@ func TestGetTrades(t *testing.T) {
 	}
 }
 
+func TestGetExchangeHistorySince(t *testing.T) {
+	t.Parallel()
+	var g Gemini
+	g.SetDefaults()
+	p := pair.NewCurrencyPair(symbol.BTC, symbol.USD)
+	trades, err := g.GetExchangeHistorySince(p, time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Error("Test Failed - GetExchangeHistorySince() error", err)
+	}
+	if len(trades) > 10 {
+		t.Error("Test Failed - GetExchangeHistorySince() limit not applied")
+	}
+}
+
 func TestGetNotionalVolume(t *testing.T) {
 	if apiKey2 != "" && apiSecret2 != "" {
 		t.Parallel()
@@ -178,6 +194,34 @@ func TestGetTradeHistory(t *testing.T) {
 	}
 }
 
+func TestGetTransfers(t *testing.T) {
+	t.Parallel()
+	_, err := Session[1].GetTransfers(0, geminiTransfersLimit)
+	if err == nil {
+		t.Error("Test Failed - GetTransfers() error", err)
+	}
+}
+
+func TestConvertFundHistory(t *testing.T) {
+	t.Parallel()
+	history := Session[1].convertFundHistory(Transfer{
+		Type:        "Withdrawal",
+		Status:      "Complete",
+		TimestampMS: 1507913541275,
+		EID:         320013281,
+		Currency:    "BTC",
+		Amount:      1.5,
+		TXHash:      "7a1f",
+		Destination: "1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy",
+	})
+
+	if history.TransferType != "withdrawal" || history.Timestamp != 1507913541 ||
+		history.CryptoToAddress != "1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy" ||
+		history.TransferID != 320013281 || history.Amount != 1.5 {
+		t.Errorf("Test Failed - convertFundHistory() unexpected result %+v", history)
+	}
+}
+
 func TestGetTradeVolume(t *testing.T) {
 	t.Parallel()
 	_, err := Session[2].GetTradeVolume()
@@ -210,6 +254,21 @@ func TestWithdrawCrypto(t *testing.T) {
 	}
 }
 
+func TestApprovedAddressError(t *testing.T) {
+	t.Parallel()
+	if !isApprovedAddressRejection("", "Address 1F5zVDgNjorJ51oGebSvNCrSAHpwGkUdDB is not an approved address") {
+		t.Error("Test Failed - isApprovedAddressRejection() error")
+	}
+	if isApprovedAddressRejection("InsufficientFunds", "Insufficient funds") {
+		t.Error("Test Failed - isApprovedAddressRejection() error")
+	}
+
+	var err error = &ApprovedAddressError{Currency: "BTC", Address: "LOL123"}
+	if _, ok := err.(*ApprovedAddressError); !ok || err.Error() == "" {
+		t.Error("Test Failed - ApprovedAddressError error")
+	}
+}
+
 func TestPostHeartbeat(t *testing.T) {
 	t.Parallel()
 	_, err := Session[2].PostHeartbeat()
@@ -320,3 +379,22 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestConvertBalances(t *testing.T) {
+	t.Parallel()
+
+	currencies := convertBalances([]Balance{{Currency: "BTC", Amount: 1.5, Available: 1}})
+	if len(currencies) != 1 || currencies[0].CurrencyName != symbol.BTC || currencies[0].Free != 1 ||
+		currencies[0].Locked != 0.5 || currencies[0].Total != 1.5 {
+		t.Errorf("Test Failed - convertBalances() unexpected result %+v", currencies)
+	}
+}
+
+func TestConvertAccountFees(t *testing.T) {
+	t.Parallel()
+
+	fees := convertAccountFees(NotionalVolume{MakerFee: 10, TakerFee: 35})
+	if fees.Rate("BTCUSD", true) != 0.001 || fees.Rate("BTCUSD", false) != 0.0035 {
+		t.Errorf("Test Failed - convertAccountFees() unexpected rates %+v", fees)
+	}
+}
@@ -117,6 +117,20 @@ type TradeHistory struct {
 	ClientOrderID   string  `json:"client_order_id"`
 }
 
+// Transfer holds deposit and withdrawal information
+type Transfer struct {
+	Type         string  `json:"type"`
+	Status       string  `json:"status"`
+	TimestampMS  int64   `json:"timestampms"`
+	EID          int64   `json:"eid"`
+	Currency     string  `json:"currency"`
+	Amount       float64 `json:"amount,string"`
+	Method       string  `json:"method"`
+	TXHash       string  `json:"txHash"`
+	Destination  string  `json:"destination"`
+	WithdrawalID string  `json:"withdrawalId"`
+}
+
 // TradeVolume holds Volume information
 type TradeVolume struct {
 	AccountID         int64   `json:"account_id"`
@@ -175,10 +189,21 @@ type DepositAddress struct {
 
 // WithdrawalAddress holds withdrawal information
 type WithdrawalAddress struct {
-	Address string  `json:"address"`
-	Amount  float64 `json:"amount"`
-	TXHash  string  `json:"txHash"`
-	Message string  `json:"message"`
+	Address      string  `json:"address"`
+	Amount       float64 `json:"amount,string"`
+	TXHash       string  `json:"txHash"`
+	WithdrawalID string  `json:"withdrawalId"`
+	Result       string  `json:"result"`
+	Reason       string  `json:"reason"`
+	Message      string  `json:"message"`
+}
+
+// ApprovedAddressError is returned when a withdrawal is rejected because the
+// destination address is not on the account's approved address list
+type ApprovedAddressError struct {
+	Currency string
+	Address  string
+	Reason   string
 }
 
 // ErrorCapture is a generlized error response from the server
@@ -1,33 +1,47 @@
 package gemini
 
 import (
+	"context"
 	"errors"
 	"log"
 	"net/url"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Gemini exchange
+func init() {
+	exchange.Register("Gemini", func() exchange.IBotExchange { return new(Gemini) })
+}
+
 // Start starts the Gemini go routine
-func (g *Gemini) Start(wg *sync.WaitGroup) {
+func (g *Gemini) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		g.Run()
+		g.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the Gemini wrapper
-func (g *Gemini) Run() {
+func (g *Gemini) Run(ctx context.Context) {
 	if g.Verbose {
 		log.Printf("%s polling delay: %ds.\n", g.GetName(), g.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", g.GetName(), len(g.EnabledPairs), g.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := g.GetSymbols()
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", g.GetName())
@@ -39,6 +53,11 @@ func (g *Gemini) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (g *Gemini) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeAccountInfo Retrieves balances for all enabled currencies for the
 // Gemini exchange
 func (g *Gemini) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
@@ -48,20 +67,25 @@ func (g *Gemini) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	if err != nil {
 		return response, err
 	}
-	for i := 0; i < len(accountBalance); i++ {
-		var exchangeCurrency exchange.AccountCurrencyInfo
-		exchangeCurrency.CurrencyName = accountBalance[i].Currency
-		exchangeCurrency.TotalValue = accountBalance[i].Amount
-		exchangeCurrency.Hold = accountBalance[i].Available
-		response.Currencies = append(response.Currencies, exchangeCurrency)
-	}
+	response.Currencies = convertBalances(accountBalance)
 	return response, nil
 }
 
+// convertBalances converts balances into account currency info, Gemini
+// amounts include the amount held by open orders
+func convertBalances(balances []Balance) []exchange.AccountCurrencyInfo {
+	var currencies []exchange.AccountCurrencyInfo
+	for _, balance := range balances {
+		currencies = append(currencies, exchange.NewAccountCurrencyInfo(balance.Currency,
+			balance.Available, balance.Amount-balance.Available))
+	}
+	return currencies
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (g *Gemini) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
-	tick, err := g.GetTicker(p.Pair().String())
+	tick, err := g.GetTicker(g.FormatPair(p, exchange.PairFormatRequest))
 	if err != nil {
 		return tickerPrice, err
 	}
@@ -95,7 +119,7 @@ func (g *Gemini) GetOrderbookEx(p pair.CurrencyPair, assetType string) (orderboo
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (g *Gemini) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
-	orderbookNew, err := g.GetOrderbook(p.Pair().String(), url.Values{})
+	orderbookNew, err := g.GetOrderbook(g.FormatPair(p, exchange.PairFormatRequest), url.Values{})
 	if err != nil {
 		return orderBook, err
 	}
@@ -108,7 +132,7 @@ func (g *Gemini) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbo
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: orderbookNew.Asks[x].Amount, Price: orderbookNew.Asks[x].Price})
 	}
 
-	orderbook.ProcessOrderbook(g.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(g.GetName(), p, g.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(g.Name, p, assetType)
 }
 
@@ -116,14 +140,120 @@ func (g *Gemini) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbo
 // withdrawals
 func (g *Gemini) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error) {
 	var fundHistory []exchange.FundHistory
-	return fundHistory, errors.New("not supported on exchange")
+	seen := make(map[int64]bool)
+	var timestamp int64
+	for {
+		transfers, err := g.GetTransfers(timestamp, geminiTransfersLimit)
+		if err != nil {
+			return fundHistory, err
+		}
+
+		var added int
+		for x := range transfers {
+			// Pages overlap on the boundary timestamp
+			if seen[transfers[x].EID] {
+				continue
+			}
+			seen[transfers[x].EID] = true
+			added++
+			fundHistory = append(fundHistory, g.convertFundHistory(transfers[x]))
+			if transfers[x].TimestampMS > timestamp {
+				timestamp = transfers[x].TimestampMS
+			}
+		}
+
+		if len(transfers) < geminiTransfersLimit {
+			return fundHistory, nil
+		}
+
+		// A full page of transfers sharing the boundary timestamp would
+		// otherwise be requested indefinitely
+		if added == 0 {
+			timestamp++
+		}
+	}
+}
+
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (g *Gemini) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
 }
 
-// GetExchangeHistory returns historic trade data since exchange opening.
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (g *Gemini) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// convertFundHistory converts a transfer to the exchange fund history format
+func (g *Gemini) convertFundHistory(transfer Transfer) exchange.FundHistory {
+	history := exchange.FundHistory{
+		ExchangeName: g.GetName(),
+		Status:       transfer.Status,
+		TransferID:   transfer.EID,
+		Description:  transfer.Method,
+		Timestamp:    transfer.TimestampMS / 1000,
+		Currency:     transfer.Currency,
+		Amount:       transfer.Amount,
+		TransferType: common.StringToLower(transfer.Type),
+		CryptoTxID:   transfer.TXHash,
+	}
+
+	if history.TransferType == "withdrawal" {
+		history.CryptoToAddress = transfer.Destination
+	}
+	return history
+}
+
+// GetExchangeHistory returns the most recent public trades for a currency pair
 func (g *Gemini) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+	return g.GetExchangeHistorySince(p, time.Time{}, 0)
+}
+
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (g *Gemini) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
+// GetExchangeHistorySince returns up to limit of the most recent public trades
+// for a currency pair executed at or after since. A zero limit uses the
+// exchange default and limits above geminiMaxTradesLimit are capped
+func (g *Gemini) GetExchangeHistorySince(p pair.CurrencyPair, since time.Time, limit int) ([]exchange.TradeHistory, error) {
+	values := url.Values{}
+	if !since.IsZero() {
+		values.Set("since", strconv.FormatInt(since.Unix(), 10))
+	}
+	if limit > 0 {
+		if limit > geminiMaxTradesLimit {
+			limit = geminiMaxTradesLimit
+		}
+		values.Set("limit_trades", strconv.Itoa(limit))
+	}
+
+	trades, err := g.GetTrades(g.FormatPair(p, exchange.PairFormatRequest), values)
+	if err != nil {
+		return nil, err
+	}
+
 	var resp []exchange.TradeHistory
+	for x := range trades {
+		side := string(exchange.OrderSideBuy())
+		if common.StringToLower(trades[x].Side) == "sell" {
+			side = string(exchange.OrderSideSell())
+		}
 
-	return resp, errors.New("trade history not yet implemented")
+		resp = append(resp, exchange.TradeHistory{
+			Timestamp: trades[x].Timestamp,
+			TID:       trades[x].TID,
+			Price:     trades[x].Price,
+			Amount:    trades[x].Amount,
+			Exchange:  g.Name,
+			Type:      side,
+		})
+	}
+	return exchange.FilterTradeHistory(resp, since, limit), nil
 }
 
 // SubmitExchangeOrder submits a new order
@@ -142,6 +272,18 @@ func (g *Gemini) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (g *Gemini) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, g.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (g *Gemini) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, g.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (g *Gemini) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -153,6 +295,11 @@ func (g *Gemini) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, erro
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (g *Gemini) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (g *Gemini) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -161,7 +308,15 @@ func (g *Gemini) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (st
 // WithdrawCryptoExchangeFunds returns a withdrawal ID when a withdrawal is
 // submitted
 func (g *Gemini) WithdrawCryptoExchangeFunds(address string, cryptocurrency pair.CurrencyItem, amount float64) (string, error) {
-	return "", errors.New("not yet implemented")
+	resp, err := g.WithdrawCrypto(address, cryptocurrency.String(), amount)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.WithdrawalID != "" {
+		return resp.WithdrawalID, nil
+	}
+	return resp.TXHash, nil
 }
 
 // WithdrawFiatExchangeFunds returns a withdrawal ID when a
@@ -170,9 +325,14 @@ func (g *Gemini) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount fl
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (g *Gemini) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (g *Gemini) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (g *Gemini) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -181,6 +341,11 @@ func (g *Gemini) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (g *Gemini) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (g *Gemini) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return g.GetFee(feeBuilder)
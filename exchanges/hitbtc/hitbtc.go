@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"strconv"
 	"time"
@@ -75,11 +74,12 @@ func (h *HitBTC) SetDefaults() {
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	h.APIUrlDefault = apiURL
 	h.APIUrl = h.APIUrlDefault
+	h.SetFeeTable(exchange.FeeTable{Maker: 0, Taker: 0.001})
 	h.WebsocketInit()
 }
 
 // Setup sets user exchange configuration settings
-func (h *HitBTC) Setup(exch config.ExchangeConfig) {
+func (h *HitBTC) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		h.SetEnabled(false)
 	} else {
@@ -96,23 +96,31 @@ func (h *HitBTC) Setup(exch config.ExchangeConfig) {
 		h.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := h.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = h.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = h.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = h.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = h.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = h.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = h.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = h.WebsocketSetup(h.WsConnect,
 			exch.Name,
@@ -120,9 +128,10 @@ func (h *HitBTC) Setup(exch config.ExchangeConfig) {
 			hitbtcWebsocketAddress,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 	}
+	return nil
 }
 
 // Public Market Data
@@ -586,6 +595,10 @@ func (h *HitBTC) SendAuthenticatedHTTPRequest(method, endpoint string, values ur
 
 // GetFee returns an estimate of fee based on type of transaction
 func (h *HitBTC) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
+	if h.IsFeeEstimated(feeBuilder) {
+		return h.EstimateFee(feeBuilder)
+	}
+
 	var fee float64
 	switch feeBuilder.FeeType {
 	case exchange.CryptocurrencyTradeFee:
@@ -27,6 +27,7 @@ func (h *HitBTC) WsConnect() error {
 	}
 
 	var dialer websocket.Dialer
+	h.Websocket.ConfigureDialer(&dialer)
 
 	if h.Websocket.GetProxyAddress() != "" {
 		proxy, err := url.Parse(h.Websocket.GetProxyAddress())
@@ -124,7 +125,7 @@ func (h *HitBTC) WsReadData() {
 			return
 
 		default:
-			_, resp, err := h.WebsocketConn.ReadMessage()
+			_, resp, err := h.Websocket.ReadMessage(h.WebsocketConn)
 			if err != nil {
 				h.Websocket.DataHandler <- err
 				return
@@ -1,34 +1,46 @@
 package hitbtc
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the HitBTC exchange
+func init() {
+	exchange.Register("HitBTC", func() exchange.IBotExchange { return new(HitBTC) })
+}
+
 // Start starts the HitBTC go routine
-func (h *HitBTC) Start(wg *sync.WaitGroup) {
+func (h *HitBTC) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		h.Run()
+		h.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the HitBTC wrapper
-func (h *HitBTC) Run() {
+func (h *HitBTC) Run(ctx context.Context) {
 	if h.Verbose {
 		log.Printf("%s Websocket: %s (url: %s).\n", h.GetName(), common.IsEnabled(h.Websocket.IsEnabled()), hitbtcWebsocketAddress)
 		log.Printf("%s polling delay: %ds.\n", h.GetName(), h.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", h.GetName(), len(h.EnabledPairs), h.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := h.GetSymbolsDetailed()
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", h.GetName())
@@ -40,6 +52,8 @@ func (h *HitBTC) Run() {
 		var currencies []string
 		for x := range exchangeProducts {
 			currencies = append(currencies, exchangeProducts[x].BaseCurrency+"-"+exchangeProducts[x].QuoteCurrency)
+			pair.RegisterPair(pair.NewCurrencyPair(exchangeProducts[x].BaseCurrency,
+				exchangeProducts[x].QuoteCurrency))
 		}
 
 		if forceUpgrade {
@@ -58,6 +72,11 @@ func (h *HitBTC) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (h *HitBTC) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (h *HitBTC) UpdateTicker(currencyPair pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	tick, err := h.GetTicker("")
@@ -101,7 +120,8 @@ func (h *HitBTC) GetOrderbookEx(currencyPair pair.CurrencyPair, assetType string
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (h *HitBTC) UpdateOrderbook(currencyPair pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
-	orderbookNew, err := h.GetOrderbook(exchange.FormatExchangeCurrency(h.GetName(), currencyPair).String(), 1000)
+	orderbookNew, err := h.GetOrderbook(exchange.FormatExchangeCurrency(h.GetName(), currencyPair).String(),
+		h.GetOrderbookRequestDepth(currencyPair, nil, 1000))
 	if err != nil {
 		return orderBook, err
 	}
@@ -116,7 +136,7 @@ func (h *HitBTC) UpdateOrderbook(currencyPair pair.CurrencyPair, assetType strin
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: data.Amount, Price: data.Price})
 	}
 
-	orderbook.ProcessOrderbook(h.GetName(), currencyPair, orderBook, assetType)
+	orderbook.ProcessOrderbook(h.GetName(), currencyPair, h.TruncateOrderbook(currencyPair, orderBook), assetType)
 	return orderbook.GetOrderbook(h.Name, currencyPair, assetType)
 }
 
@@ -131,11 +151,8 @@ func (h *HitBTC) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	}
 
 	for _, item := range accountBalance {
-		var exchangeCurrency exchange.AccountCurrencyInfo
-		exchangeCurrency.CurrencyName = item.Currency
-		exchangeCurrency.TotalValue = item.Available
-		exchangeCurrency.Hold = item.Reserved
-		response.Currencies = append(response.Currencies, exchangeCurrency)
+		response.Currencies = append(response.Currencies,
+			exchange.NewAccountCurrencyInfo(item.Currency, item.Available, item.Reserved))
 	}
 	return response, nil
 }
@@ -147,6 +164,18 @@ func (h *HitBTC) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (h *HitBTC) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (h *HitBTC) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (h *HitBTC) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -154,6 +183,12 @@ func (h *HitBTC) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]ex
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (h *HitBTC) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (h *HitBTC) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -170,6 +205,18 @@ func (h *HitBTC) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (h *HitBTC) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, h.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (h *HitBTC) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, h.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (h *HitBTC) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -181,6 +228,11 @@ func (h *HitBTC) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, erro
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (h *HitBTC) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (h *HitBTC) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -198,9 +250,14 @@ func (h *HitBTC) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount fl
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (h *HitBTC) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (h *HitBTC) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (h *HitBTC) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -209,6 +266,11 @@ func (h *HitBTC) GetWebsocket() (*exchange.Websocket, error) {
 	return h.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (h *HitBTC) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (h *HitBTC) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return h.GetFee(feeBuilder)
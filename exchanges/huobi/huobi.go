@@ -2,19 +2,18 @@ package huobi
 
 import (
 	"bytes"
+	"context"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -26,8 +25,9 @@ import (
 )
 
 const (
-	huobiAPIURL     = "https://api.huobi.pro"
-	huobiAPIVersion = "1"
+	huobiAPIURL      = "https://api.huobi.pro"
+	huobiAPIVersion  = "1"
+	huobiAPIVersion2 = "2"
 
 	huobiMarketHistoryKline   = "market/history/kline"
 	huobiMarketDetail         = "market/detail"
@@ -43,6 +43,7 @@ const (
 	huobiOrderPlace           = "order/orders/place"
 	huobiOrderCancel          = "order/orders/%s/submitcancel"
 	huobiOrderCancelBatch     = "order/orders/batchcancel"
+	huobiOrderCancelClient    = "order/orders/submitCancelClientOrder"
 	huobiGetOrder             = "order/orders/%s"
 	huobiGetOrderMatch        = "order/orders/%s/matchresults"
 	huobiGetOrders            = "order/orders"
@@ -55,11 +56,34 @@ const (
 	huobiMarginAccountBalance = "margin/accounts/balance"
 	huobiWithdrawCreate       = "dw/withdraw/api/create"
 	huobiWithdrawCancel       = "dw/withdraw-virtual/%s/cancel"
+	huobiWithdrawQuota        = "account/withdraw/quota"
+	huobiAggregatedBalance    = "subuser/aggregate-balance"
+	huobiSubAccountBalance    = "account/accounts/%s"
+	huobiSubAccountTransfer   = "subuser/transfer"
+
+	// huobiMaxBatchCancel is the maximum number of orders cancelled per
+	// batch cancel request
+	huobiMaxBatchCancel = 50
+
+	huobiSpotAccount   = "spot"
+	huobiBalanceFrozen = "frozen"
 
 	huobiAuthRate   = 100
 	huobiUnauthRate = 100
+
+	// huobiSignatureHost is the host signed by signature version 2 requests
+	huobiSignatureHost = "api.huobi.pro"
 )
 
+// huobiCanonicalRules are the canonicalization rules of signature version 2,
+// the method, host, path and sorted query string on separate lines
+var huobiCanonicalRules = request.CanonicalRules{
+	Separator: "\n",
+	Method:    true,
+	Host:      true,
+	Path:      true,
+}
+
 // HUOBI is the overarching type across this package
 type HUOBI struct {
 	exchange.Base
@@ -85,13 +109,14 @@ func (h *HUOBI) SetDefaults() {
 		request.NewRateLimit(time.Second*10, huobiAuthRate),
 		request.NewRateLimit(time.Second*10, huobiUnauthRate),
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
+	h.SetCanonicalizer(huobiCanonicalRules.Canonicalize)
 	h.APIUrlDefault = huobiAPIURL
 	h.APIUrl = h.APIUrlDefault
 	h.WebsocketInit()
 }
 
 // Setup sets user configuration
-func (h *HUOBI) Setup(exch config.ExchangeConfig) {
+func (h *HUOBI) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		h.SetEnabled(false)
 	} else {
@@ -110,23 +135,31 @@ func (h *HUOBI) Setup(exch config.ExchangeConfig) {
 		h.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := h.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = h.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = h.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = h.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = h.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = h.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = h.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = h.WebsocketSetup(h.WsConnect,
 			exch.Name,
@@ -134,9 +167,24 @@ func (h *HUOBI) Setup(exch config.ExchangeConfig) {
 			huobiSocketIOAddress,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = h.WebsocketCompressionSetup(exch.WebsocketCompression,
+			exchange.WebsocketCompressionGzip)
+		if err != nil {
+			return err
+		}
+		err = h.WebsocketKeepaliveSetup(exch.WebsocketKeepalive,
+			exchange.WebsocketKeepalive{
+				Method:   exchange.WebsocketKeepalivePong,
+				Payload:  wsPingPayload,
+				Response: wsPongResponse,
+			})
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetSpotKline returns kline data
@@ -167,6 +215,12 @@ func (h *HUOBI) GetSpotKline(arg KlinesRequestParams) ([]KlineItem, error) {
 
 // GetMarketDetailMerged returns the ticker for the specified symbol
 func (h *HUOBI) GetMarketDetailMerged(symbol string) (DetailMerged, error) {
+	return h.GetMarketDetailMergedContext(context.Background(), symbol)
+}
+
+// GetMarketDetailMergedContext returns the ticker for the specified symbol
+// under a call context
+func (h *HUOBI) GetMarketDetailMergedContext(ctx context.Context, symbol string) (DetailMerged, error) {
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
 
@@ -178,7 +232,7 @@ func (h *HUOBI) GetMarketDetailMerged(symbol string) (DetailMerged, error) {
 	var result response
 	url := fmt.Sprintf("%s/%s", h.APIUrl, huobiMarketDetailMerged)
 
-	err := h.SendHTTPRequest(common.EncodeURLValues(url, vals), &result)
+	err := h.SendHTTPRequestContext(ctx, common.EncodeURLValues(url, vals), &result)
 	if result.ErrorMessage != "" {
 		return result.Tick, errors.New(result.ErrorMessage)
 	}
@@ -344,13 +398,18 @@ func (h *HUOBI) GetTimestamp() (int64, error) {
 
 // GetAccounts returns the Huobi user accounts
 func (h *HUOBI) GetAccounts() ([]Account, error) {
+	return h.GetAccountsContext(context.Background())
+}
+
+// GetAccountsContext returns the Huobi user accounts under a call context
+func (h *HUOBI) GetAccountsContext(ctx context.Context) ([]Account, error) {
 	type response struct {
 		Response
 		AccountData []Account `json:"data"`
 	}
 
 	var result response
-	err := h.SendAuthenticatedHTTPRequest("GET", huobiAccounts, url.Values{}, nil, &result)
+	err := h.SendAuthenticatedHTTPRequestContext(ctx, "GET", huobiAccounts, url.Values{}, nil, &result)
 
 	if result.ErrorMessage != "" {
 		return nil, errors.New(result.ErrorMessage)
@@ -375,20 +434,115 @@ func (h *HUOBI) GetAccountBalance(accountID string) ([]AccountBalanceDetail, err
 	return result.AccountBalanceData.AccountBalanceDetails, err
 }
 
+// GetAccountID returns the first account ID matching the account type e.g.
+// spot or margin
+func (h *HUOBI) GetAccountID(accountType string) (int64, error) {
+	return h.GetAccountIDContext(context.Background(), accountType)
+}
+
+// GetAccountIDContext returns the first account ID matching the account type
+// under a call context
+func (h *HUOBI) GetAccountIDContext(ctx context.Context, accountType string) (int64, error) {
+	accounts, err := h.GetAccountsContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for x := range accounts {
+		if accounts[x].Type == accountType {
+			return accounts[x].ID, nil
+		}
+	}
+	return 0, fmt.Errorf("Huobi %s account not found", accountType)
+}
+
+// GetAggregatedBalance returns the aggregated balances of all sub-accounts
+func (h *HUOBI) GetAggregatedBalance() ([]AggregatedBalance, error) {
+	type response struct {
+		Response
+		AggregatedBalances []AggregatedBalance `json:"data"`
+	}
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequest("GET", huobiAggregatedBalance, url.Values{}, nil, &result)
+
+	if result.ErrorMessage != "" {
+		return nil, errors.New(result.ErrorMessage)
+	}
+	return result.AggregatedBalances, err
+}
+
+// GetSubAccountBalances returns the account balances of a sub-account
+func (h *HUOBI) GetSubAccountBalances(subUID int64) ([]AccountBalance, error) {
+	type response struct {
+		Response
+		SubAccountBalances []AccountBalance `json:"data"`
+	}
+
+	var result response
+	endpoint := fmt.Sprintf(huobiSubAccountBalance, strconv.FormatInt(subUID, 10))
+	err := h.SendAuthenticatedHTTPRequest("GET", endpoint, url.Values{}, nil, &result)
+
+	if result.ErrorMessage != "" {
+		return nil, errors.New(result.ErrorMessage)
+	}
+	return result.SubAccountBalances, err
+}
+
+// TransferSubAccount transfers funds between the parent account and a
+// sub-account. Transfer types are master-transfer-in or master-transfer-out
+func (h *HUOBI) TransferSubAccount(subUID int64, currency, transferType string, amount float64) (int64, error) {
+	if transferType != SubAccountTransferIn && transferType != SubAccountTransferOut {
+		return 0, fmt.Errorf("Huobi invalid sub-account transfer type %s", transferType)
+	}
+
+	data := struct {
+		SubUID   int64  `json:"sub-uid"`
+		Currency string `json:"currency"`
+		Amount   string `json:"amount"`
+		Type     string `json:"type"`
+	}{
+		SubUID:   subUID,
+		Currency: common.StringToLower(currency),
+		Amount:   strconv.FormatFloat(amount, 'f', -1, 64),
+		Type:     transferType,
+	}
+
+	type response struct {
+		Response
+		TransferID int64 `json:"data"`
+	}
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequest("POST", huobiSubAccountTransfer, nil, data, &result)
+
+	if result.ErrorMessage != "" {
+		return 0, errors.New(result.ErrorMessage)
+	}
+	return result.TransferID, err
+}
+
 // SpotNewOrder submits an order to Huobi
 func (h *HUOBI) SpotNewOrder(arg SpotNewOrderRequestParams) (int64, error) {
+	return h.SpotNewOrderContext(context.Background(), arg)
+}
+
+// SpotNewOrderContext submits an order to Huobi under a call context
+func (h *HUOBI) SpotNewOrderContext(ctx context.Context, arg SpotNewOrderRequestParams) (int64, error) {
 	data := struct {
-		AccountID int    `json:"account-id,string"`
-		Amount    string `json:"amount"`
-		Price     string `json:"price"`
-		Source    string `json:"source"`
-		Symbol    string `json:"symbol"`
-		Type      string `json:"type"`
+		AccountID     int    `json:"account-id,string"`
+		Amount        string `json:"amount"`
+		Price         string `json:"price"`
+		Source        string `json:"source"`
+		Symbol        string `json:"symbol"`
+		Type          string `json:"type"`
+		ClientOrderID string `json:"client-order-id,omitempty"`
 	}{
-		AccountID: arg.AccountID,
-		Amount:    strconv.FormatFloat(arg.Amount, 'f', -1, 64),
-		Symbol:    arg.Symbol,
-		Type:      string(arg.Type),
+		AccountID:     arg.AccountID,
+		Amount:        strconv.FormatFloat(arg.Amount, 'f', -1, 64),
+		Symbol:        arg.Symbol,
+		Type:          string(arg.Type),
+		ClientOrderID: arg.ClientOrderID,
 	}
 
 	// Only set price if order type is not equal to buy-market or sell-market
@@ -406,7 +560,7 @@ func (h *HUOBI) SpotNewOrder(arg SpotNewOrderRequestParams) (int64, error) {
 	}
 
 	var result response
-	err := h.SendAuthenticatedHTTPRequest("POST", huobiOrderPlace, nil, data, &result)
+	err := h.SendAuthenticatedHTTPRequestContext(ctx, "POST", huobiOrderPlace, nil, data, &result)
 
 	if result.ErrorMessage != "" {
 		return 0, errors.New(result.ErrorMessage)
@@ -431,18 +585,48 @@ func (h *HUOBI) CancelOrder(orderID int64) (int64, error) {
 	return result.OrderID, err
 }
 
-// CancelOrderBatch cancels a batch of orders -- to-do
-func (h *HUOBI) CancelOrderBatch(orderIDs []int64) ([]CancelOrderBatch, error) {
+// CancelOrderByClientOrderID cancels an order by the client order ID it was
+// submitted with
+func (h *HUOBI) CancelOrderByClientOrderID(clientOrderID string) (int64, error) {
 	type response struct {
 		Response
-		Data []CancelOrderBatch `json:"data"`
+		Status int64 `json:"data"`
+	}
+
+	data := struct {
+		ClientOrderID string `json:"client-order-id"`
+	}{
+		ClientOrderID: clientOrderID,
 	}
 
 	var result response
-	err := h.SendAuthenticatedHTTPRequest("POST", huobiOrderCancelBatch, url.Values{}, nil, &result)
+	err := h.SendAuthenticatedHTTPRequest("POST", huobiOrderCancelClient, url.Values{}, data, &result)
 
 	if result.ErrorMessage != "" {
-		return nil, errors.New(result.ErrorMessage)
+		return 0, errors.New(result.ErrorMessage)
+	}
+	return result.Status, err
+}
+
+// CancelOrderBatch cancels a batch of orders
+func (h *HUOBI) CancelOrderBatch(orderIDs []int64) (CancelOrderBatch, error) {
+	type response struct {
+		Response
+		Data CancelOrderBatch `json:"data"`
+	}
+
+	data := struct {
+		OrderIDs []string `json:"order-ids"`
+	}{}
+	for _, orderID := range orderIDs {
+		data.OrderIDs = append(data.OrderIDs, strconv.FormatInt(orderID, 10))
+	}
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequest("POST", huobiOrderCancelBatch, url.Values{}, data, &result)
+
+	if result.ErrorMessage != "" {
+		return result.Data, errors.New(result.ErrorMessage)
 	}
 	return result.Data, err
 }
@@ -769,13 +953,54 @@ func (h *HUOBI) CancelWithdraw(withdrawID int64) (int64, error) {
 	return result.WithdrawID, err
 }
 
+// GetWithdrawQuota returns the withdrawal quotas of a currency per chain
+func (h *HUOBI) GetWithdrawQuota(currency string) (WithdrawQuota, error) {
+	type response struct {
+		Code    int           `json:"code"`
+		Message string        `json:"message"`
+		Data    WithdrawQuota `json:"data"`
+	}
+
+	vals := url.Values{}
+	vals.Set("currency", common.StringToLower(currency))
+
+	var result response
+	err := h.sendAuthenticatedHTTPRequest(context.Background(), "GET", huobiAPIVersion2, huobiWithdrawQuota, vals, nil, &result)
+	if err != nil {
+		return result.Data, err
+	}
+
+	if result.Code != 200 {
+		return result.Data, errors.New(result.Message)
+	}
+	return result.Data, nil
+}
+
 // SendHTTPRequest sends an unauthenticated HTTP request
 func (h *HUOBI) SendHTTPRequest(path string, result interface{}) error {
-	return h.SendPayload("GET", path, nil, nil, result, false, h.Verbose)
+	return h.SendHTTPRequestContext(context.Background(), path, result)
+}
+
+// SendHTTPRequestContext sends an unauthenticated HTTP request under a call
+// context
+func (h *HUOBI) SendHTTPRequestContext(ctx context.Context, path string, result interface{}) error {
+	return h.SendPayloadContext(ctx, "GET", path, nil, nil, result, false, h.Verbose)
 }
 
 // SendAuthenticatedHTTPRequest sends authenticated requests to the HUOBI API
 func (h *HUOBI) SendAuthenticatedHTTPRequest(method, endpoint string, values url.Values, data interface{}, result interface{}) error {
+	return h.SendAuthenticatedHTTPRequestContext(context.Background(), method, endpoint, values, data, result)
+}
+
+// SendAuthenticatedHTTPRequestContext sends authenticated requests to the
+// HUOBI API under a call context
+func (h *HUOBI) SendAuthenticatedHTTPRequestContext(ctx context.Context, method, endpoint string, values url.Values, data interface{}, result interface{}) error {
+	return h.sendAuthenticatedHTTPRequest(ctx, method, huobiAPIVersion, endpoint, values, data, result)
+}
+
+// sendAuthenticatedHTTPRequest sends authenticated requests to a version of
+// the HUOBI API
+func (h *HUOBI) sendAuthenticatedHTTPRequest(ctx context.Context, method, version, endpoint string, values url.Values, data interface{}, result interface{}) error {
 	if !h.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, h.Name)
 	}
@@ -787,11 +1012,15 @@ func (h *HUOBI) SendAuthenticatedHTTPRequest(method, endpoint string, values url
 	values.Set("AccessKeyId", h.APIKey)
 	values.Set("SignatureMethod", "HmacSHA256")
 	values.Set("SignatureVersion", "2")
-	values.Set("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05"))
+	values.Set("Timestamp", h.GetAdjustedTime().UTC().Format("2006-01-02T15:04:05"))
 
-	endpoint = fmt.Sprintf("/v%s/%s", huobiAPIVersion, endpoint)
-	payload := fmt.Sprintf("%s\napi.huobi.pro\n%s\n%s",
-		method, endpoint, values.Encode())
+	endpoint = fmt.Sprintf("/v%s/%s", version, endpoint)
+	payload := h.Canonicalize(&request.CanonicalRequest{
+		Method: method,
+		Host:   huobiSignatureHost,
+		Path:   endpoint,
+		Params: values,
+	})
 
 	headers := make(map[string]string)
 
@@ -805,32 +1034,12 @@ func (h *HUOBI) SendAuthenticatedHTTPRequest(method, endpoint string, values url
 	signature := common.Base64Encode(hmac)
 	values.Set("Signature", signature)
 
-	if h.APIAuthPEMKeySupport == true {
-		pemKey := strings.NewReader(h.APIAuthPEMKey)
-		pemBytes, err := ioutil.ReadAll(pemKey)
-		if err != nil {
-			return fmt.Errorf("Huobi unable to ioutil.ReadAll PEM key: %s", err)
-		}
-
-		block, _ := pem.Decode(pemBytes)
-		if block == nil {
-			return fmt.Errorf("Huobi block is nil")
-		}
-
-		x509Encoded := block.Bytes
-		privKey, err := x509.ParseECPrivateKey(x509Encoded)
-		if err != nil {
-			return fmt.Errorf("Huobi unable to ParseECPrivKey: %s", err)
-		}
-
-		r, s, err := ecdsa.Sign(rand.Reader, privKey, common.GetSHA256([]byte(signature)))
+	if h.APIAuthPEMKeySupport {
+		privSig, err := h.signPrivate(signature)
 		if err != nil {
-			return fmt.Errorf("Huobi unable to sign: %s", err)
+			return err
 		}
-
-		privSig := r.Bytes()
-		privSig = append(privSig, s.Bytes()...)
-		values.Set("PrivateSignature", common.Base64Encode(privSig))
+		values.Set("PrivateSignature", privSig)
 	}
 
 	url := fmt.Sprintf("%s%s", h.APIUrl, endpoint)
@@ -847,7 +1056,41 @@ func (h *HUOBI) SendAuthenticatedHTTPRequest(method, endpoint string, values url
 		body = encoded
 	}
 
-	return h.SendPayload(method, url, headers, bytes.NewReader(body), result, true, h.Verbose)
+	return h.SendPayloadContext(ctx, method, url, headers, bytes.NewReader(body), result, true, h.Verbose)
+}
+
+// signPrivate signs the request signature with the configured PEM private key.
+// Both ECDSA (SEC 1 or PKCS #8) and Ed25519 (PKCS #8) keys are supported
+func (h *HUOBI) signPrivate(signature string) (string, error) {
+	block, _ := pem.Decode([]byte(h.APIAuthPEMKey))
+	if block == nil {
+		return "", errors.New("Huobi unable to decode PEM key")
+	}
+
+	var privKey interface{}
+	var err error
+	if block.Type == "EC PRIVATE KEY" {
+		privKey, err = x509.ParseECPrivateKey(block.Bytes)
+	} else {
+		privKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Huobi unable to parse PEM key: %s", err)
+	}
+
+	switch key := privKey.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, common.GetSHA256([]byte(signature)))
+		if err != nil {
+			return "", fmt.Errorf("Huobi unable to sign: %s", err)
+		}
+		privSig := r.Bytes()
+		privSig = append(privSig, s.Bytes()...)
+		return common.Base64Encode(privSig), nil
+	case ed25519.PrivateKey:
+		return common.Base64Encode(ed25519.Sign(key, []byte(signature))), nil
+	}
+	return "", fmt.Errorf("Huobi unsupported PEM key type %T", privKey)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
@@ -855,7 +1098,8 @@ func (h *HUOBI) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 	var fee float64
 	switch feeBuilder.FeeType {
 	case exchange.CryptocurrencyTradeFee:
-		fee = calculateTradingFee(feeBuilder.PurchasePrice, feeBuilder.Amount)
+		fee = h.DiscountTradingFee(feeBuilder,
+			calculateTradingFee(feeBuilder.PurchasePrice, feeBuilder.Amount))
 	}
 	if fee < 0 {
 		fee = 0
@@ -2,6 +2,8 @@ package huobi
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
 	"encoding/pem"
@@ -222,6 +224,15 @@ func TestCancelOrder(t *testing.T) {
 	}
 }
 
+func TestCancelOrderByClientOrderID(t *testing.T) {
+	t.Parallel()
+
+	_, err := h.CancelOrderByClientOrderID("1337")
+	if err == nil {
+		t.Error("Test failed - Huobi TestCancelOrderByClientOrderID: Invalid client order ID returned true")
+	}
+}
+
 func TestGetOrder(t *testing.T) {
 	t.Parallel()
 
@@ -393,3 +404,94 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestSignPrivate(t *testing.T) {
+	t.Parallel()
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal("Test failed - unable to generate Ed25519 key", err)
+	}
+
+	edBytes, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatal("Test failed - unable to marshal Ed25519 key", err)
+	}
+
+	var hPEM HUOBI
+	hPEM.APIAuthPEMKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edBytes}))
+	signature, err := hPEM.signPrivate("test")
+	if err != nil {
+		t.Fatal("Test failed - Huobi signPrivate Ed25519 error", err)
+	}
+
+	sig, err := common.Base64Decode(signature)
+	if err != nil {
+		t.Fatal("Test failed - Huobi signPrivate Ed25519 signature not base64", err)
+	}
+
+	if !ed25519.Verify(edKey.Public().(ed25519.PublicKey), []byte("test"), sig) {
+		t.Error("Test failed - Huobi signPrivate Ed25519 signature invalid")
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("Test failed - unable to generate ECDSA key", err)
+	}
+
+	ecBytes, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal("Test failed - unable to marshal ECDSA key", err)
+	}
+
+	hPEM.APIAuthPEMKey = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecBytes}))
+	_, err = hPEM.signPrivate("test")
+	if err != nil {
+		t.Error("Test failed - Huobi signPrivate ECDSA error", err)
+	}
+
+	hPEM.APIAuthPEMKey = "invalid"
+	_, err = hPEM.signPrivate("test")
+	if err == nil {
+		t.Error("Test failed - Huobi signPrivate invalid key returned no error")
+	}
+}
+
+func TestConvertAccountBalances(t *testing.T) {
+	t.Parallel()
+
+	currencies := convertAccountBalances([]AccountBalanceDetail{
+		{Currency: "btc", Type: "trade", Balance: 1},
+		{Currency: "btc", Type: "frozen", Balance: 0.5},
+		{Currency: "usdt", Type: "trade", Balance: 100},
+	})
+
+	if len(currencies) != 2 {
+		t.Fatalf("Test failed - expected 2 currencies received %d", len(currencies))
+	}
+
+	if currencies[0].CurrencyName != symbol.BTC || currencies[0].Free != 1 ||
+		currencies[0].Locked != 0.5 || currencies[0].Total != 1.5 {
+		t.Errorf("Test failed - convertAccountBalances() unexpected result %+v",
+			currencies[0])
+	}
+}
+
+func TestGetSpotOrderType(t *testing.T) {
+	t.Parallel()
+
+	orderType, err := getSpotOrderType(exchange.OrderSideBuy(), exchange.OrderTypeLimit())
+	if err != nil || orderType != SpotNewOrderRequestTypeBuyLimit {
+		t.Error("Test failed - getSpotOrderType() buy limit incorrect", err)
+	}
+
+	orderType, err = getSpotOrderType(exchange.OrderSideSell(), exchange.OrderTypeMarket())
+	if err != nil || orderType != SpotNewOrderRequestTypeSellMarket {
+		t.Error("Test failed - getSpotOrderType() sell market incorrect", err)
+	}
+
+	_, err = getSpotOrderType("Hodl", exchange.OrderTypeLimit())
+	if err == nil {
+		t.Error("Test failed - getSpotOrderType() invalid side returned no error")
+	}
+}
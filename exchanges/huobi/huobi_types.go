@@ -118,6 +118,19 @@ type AccountBalanceDetail struct {
 	Balance  float64 `json:"balance,string"`
 }
 
+// AggregatedBalance stores the aggregated balance of all sub-accounts for a
+// currency
+type AggregatedBalance struct {
+	Currency string  `json:"currency"`
+	Balance  float64 `json:"balance,string"`
+}
+
+// Sub-account transfer types
+const (
+	SubAccountTransferIn  = "master-transfer-in"
+	SubAccountTransferOut = "master-transfer-out"
+)
+
 // CancelOrderBatch stores the cancel order batch data
 type CancelOrderBatch struct {
 	Success []string `json:"success"`
@@ -147,6 +160,7 @@ type OrderInfo struct {
 	CanceledAt      int    `json:"canceled-at"`
 	Exchange        string `json:"exchange"`
 	Batch           string `json:"batch"`
+	ClientOrderID   string `json:"client-order-id"`
 }
 
 // OrderMatchInfo stores the order match info
@@ -196,12 +210,13 @@ type MarginAccountBalance struct {
 // SpotNewOrderRequestParams holds the params required to place
 // an order
 type SpotNewOrderRequestParams struct {
-	AccountID int                           `json:"account-id"` // Account ID, obtained using the accounts method. Curency trades use the accountid of the ‘spot’ account; for loan asset transactions, please use the accountid of the ‘margin’ account.
-	Amount    float64                       `json:"amount"`     // The limit price indicates the quantity of the order, the market price indicates how much to buy when the order is paid, and the market price indicates how much the coin is sold when the order is sold.
-	Price     float64                       `json:"price"`      // Order price, market price does not use  this parameter
-	Source    string                        `json:"source"`     // Order source, api: API call, margin-api: loan asset transaction
-	Symbol    string                        `json:"symbol"`     // The symbol to use; example btcusdt, bccbtc......
-	Type      SpotNewOrderRequestParamsType `json:"type"`       // 订单类型, buy-market: 市价买, sell-market: 市价卖, buy-limit: 限价买, sell-limit: 限价卖
+	AccountID     int                           `json:"account-id"`      // Account ID, obtained using the accounts method. Curency trades use the accountid of the ‘spot’ account; for loan asset transactions, please use the accountid of the ‘margin’ account.
+	Amount        float64                       `json:"amount"`          // The limit price indicates the quantity of the order, the market price indicates how much to buy when the order is paid, and the market price indicates how much the coin is sold when the order is sold.
+	Price         float64                       `json:"price"`           // Order price, market price does not use  this parameter
+	Source        string                        `json:"source"`          // Order source, api: API call, margin-api: loan asset transaction
+	Symbol        string                        `json:"symbol"`          // The symbol to use; example btcusdt, bccbtc......
+	Type          SpotNewOrderRequestParamsType `json:"type"`            // 订单类型, buy-market: 市价买, sell-market: 市价卖, buy-limit: 限价买, sell-limit: 限价卖
+	ClientOrderID string                        `json:"client-order-id"` // Optional client order ID, duplicate IDs are rejected by the exchange
 }
 
 // SpotNewOrderRequestParamsType order type
@@ -245,3 +260,21 @@ var (
 	TimeIntervalMohth          = TimeInterval("1mon")
 	TimeIntervalYear           = TimeInterval("1year")
 )
+
+// WithdrawQuota stores the withdrawal quotas of a currency per chain
+type WithdrawQuota struct {
+	Currency string               `json:"currency"`
+	Chains   []WithdrawChainQuota `json:"chains"`
+}
+
+// WithdrawChainQuota stores the withdrawal quotas of a currency on a chain
+type WithdrawChainQuota struct {
+	Chain                      string  `json:"chain"`
+	MaxWithdrawAmount          float64 `json:"maxWithdrawAmt,string"`
+	WithdrawQuotaPerDay        float64 `json:"withdrawQuotaPerDay,string"`
+	RemainWithdrawQuotaPerDay  float64 `json:"remainWithdrawQuotaPerDay,string"`
+	WithdrawQuotaPerYear       float64 `json:"withdrawQuotaPerYear,string"`
+	RemainWithdrawQuotaPerYear float64 `json:"remainWithdrawQuotaPerYear,string"`
+	WithdrawQuotaTotal         float64 `json:"withdrawQuotaTotal,string"`
+	RemainWithdrawQuotaTotal   float64 `json:"remainWithdrawQuotaTotal,string"`
+}
@@ -1,11 +1,8 @@
 package huobi
 
 import (
-	"bytes"
-	"compress/gzip"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"math/big"
 	"net/http"
@@ -24,6 +21,10 @@ const (
 	wsMarketKline        = "market.%s.kline.1min"
 	wsMarketDepth        = "market.%s.depth.step0"
 	wsMarketTrade        = "market.%s.trade.detail"
+
+	// The server sends {"ping":n} which is answered with {"pong":n}
+	wsPingPayload  = `"ping"`
+	wsPongResponse = `"pong"`
 )
 
 // WsConnect initiates a new websocket connection
@@ -33,6 +34,7 @@ func (h *HUOBI) WsConnect() error {
 	}
 
 	var dialer websocket.Dialer
+	h.Websocket.ConfigureDialer(&dialer)
 
 	if h.Websocket.GetProxyAddress() != "" {
 		proxy, err := url.Parse(h.Websocket.GetProxyAddress())
@@ -79,24 +81,17 @@ func (h *HUOBI) WsReadData() {
 			return
 
 		default:
-			_, resp, err := h.WebsocketConn.ReadMessage()
+			mType, resp, err := h.Websocket.ReadMessage(h.WebsocketConn)
 			if err != nil {
 				log.Fatal(err)
 			}
 
 			h.Websocket.TrafficAlert <- struct{}{}
 
-			b := bytes.NewReader(resp)
-			gReader, err := gzip.NewReader(b)
-			if err != nil {
-				log.Fatal(err)
-			}
-
-			unzipped, err := ioutil.ReadAll(gReader)
+			unzipped, err := h.Websocket.Decompress(mType, resp)
 			if err != nil {
 				log.Fatal(err)
 			}
-			gReader.Close()
 
 			h.Websocket.Intercomm <- exchange.WebsocketResponse{Raw: unzipped}
 		}
@@ -112,6 +107,14 @@ func (h *HUOBI) WsHandleData() {
 		select {
 		case <-h.Websocket.ShutdownC:
 		case resp := <-h.Websocket.Intercomm:
+			if pong, ok := h.Websocket.KeepaliveResponse(resp.Raw); ok {
+				err := h.WebsocketConn.WriteMessage(websocket.TextMessage, pong)
+				if err != nil {
+					h.Websocket.DataHandler <- err
+				}
+				continue
+			}
+
 			var init WsResponse
 			err := common.JSONDecode(resp.Raw, &init)
 			if err != nil {
@@ -129,14 +132,6 @@ func (h *HUOBI) WsHandleData() {
 				continue
 			}
 
-			if init.Ping != 0 {
-				err = h.WebsocketConn.WriteJSON(`{"pong":1337}`)
-				if err != nil {
-					log.Fatal(err)
-				}
-				continue
-			}
-
 			switch {
 			case common.StringContains(init.Channel, "depth"):
 				var depth WsDepth
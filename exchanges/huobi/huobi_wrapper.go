@@ -1,35 +1,49 @@
 package huobi
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the HUOBI exchange
+func init() {
+	exchange.Register("Huobi", func() exchange.IBotExchange { return new(HUOBI) })
+}
+
 // Start starts the HUOBI go routine
-func (h *HUOBI) Start(wg *sync.WaitGroup) {
+func (h *HUOBI) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		h.Run()
+		h.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the HUOBI wrapper
-func (h *HUOBI) Run() {
+func (h *HUOBI) Run(ctx context.Context) {
 	if h.Verbose {
 		log.Printf("%s Websocket: %s (url: %s).\n", h.GetName(), common.IsEnabled(h.Websocket.IsEnabled()), huobiSocketIOAddress)
 		log.Printf("%s polling delay: %ds.\n", h.GetName(), h.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", h.GetName(), len(h.EnabledPairs), h.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := h.GetSymbols()
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", h.GetName())
@@ -78,10 +92,21 @@ func (h *HUOBI) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (h *HUOBI) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (h *HUOBI) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	return h.UpdateTickerContext(context.Background(), p, assetType)
+}
+
+// UpdateTickerContext updates and returns the ticker for a currency pair under
+// a call context
+func (h *HUOBI) UpdateTickerContext(ctx context.Context, p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
-	tick, err := h.GetMarketDetailMerged(exchange.FormatExchangeCurrency(h.Name, p).String())
+	tick, err := h.GetMarketDetailMergedContext(ctx, exchange.FormatExchangeCurrency(h.Name, p).String())
 	if err != nil {
 		return tickerPrice, err
 	}
@@ -143,18 +168,55 @@ func (h *HUOBI) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderboo
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: data[1], Price: data[0]})
 	}
 
-	orderbook.ProcessOrderbook(h.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(h.GetName(), p, h.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(h.Name, p, assetType)
 }
 
-//GetExchangeAccountInfo retrieves balances for all enabled currencies for the
-// HUOBI exchange - to-do
+// GetExchangeAccountInfo retrieves balances for all enabled currencies for the
+// HUOBI exchange
 func (h *HUOBI) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	var response exchange.AccountInfo
 	response.ExchangeName = h.GetName()
+
+	accountID, err := h.GetAccountID(huobiSpotAccount)
+	if err != nil {
+		return response, err
+	}
+
+	balances, err := h.GetAccountBalance(strconv.FormatInt(accountID, 10))
+	if err != nil {
+		return response, err
+	}
+
+	response.Currencies = convertAccountBalances(balances)
 	return response, nil
 }
 
+// convertAccountBalances merges the trade and frozen balances of each
+// currency into its free and locked balances
+func convertAccountBalances(balances []AccountBalanceDetail) []exchange.AccountCurrencyInfo {
+	var currencies []exchange.AccountCurrencyInfo
+	index := make(map[string]int)
+	for _, balance := range balances {
+		currency := common.StringToUpper(balance.Currency)
+		x, ok := index[currency]
+		if !ok {
+			x = len(currencies)
+			index[currency] = x
+			currencies = append(currencies,
+				exchange.AccountCurrencyInfo{CurrencyName: currency})
+		}
+
+		currencies[x].Total += balance.Balance
+		if balance.Type == huobiBalanceFrozen {
+			currencies[x].Locked += balance.Balance
+		} else {
+			currencies[x].Free += balance.Balance
+		}
+	}
+	return currencies
+}
+
 // GetExchangeFundTransferHistory returns funding history, deposits and
 // withdrawals
 func (h *HUOBI) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error) {
@@ -162,6 +224,18 @@ func (h *HUOBI) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error)
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (h *HUOBI) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (h *HUOBI) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (h *HUOBI) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -169,9 +243,53 @@ func (h *HUOBI) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exc
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (h *HUOBI) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (h *HUOBI) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
-	return 0, errors.New("not yet implemented")
+	return h.SubmitExchangeOrderContext(context.Background(), p, side, orderType, amount, price, clientID)
+}
+
+// SubmitExchangeOrderContext submits a new order under a call context
+func (h *HUOBI) SubmitExchangeOrderContext(ctx context.Context, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	requestType, err := getSpotOrderType(side, orderType)
+	if err != nil {
+		return 0, err
+	}
+
+	accountID, err := h.GetAccountIDContext(ctx, huobiSpotAccount)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.SpotNewOrderContext(ctx, SpotNewOrderRequestParams{
+		AccountID:     int(accountID),
+		Amount:        amount,
+		Price:         price,
+		Source:        "api",
+		Symbol:        exchange.FormatExchangeCurrency(h.Name, p).String(),
+		Type:          requestType,
+		ClientOrderID: clientID,
+	})
+}
+
+// getSpotOrderType converts an order side and type to a Huobi order type
+func getSpotOrderType(side exchange.OrderSide, orderType exchange.OrderType) (SpotNewOrderRequestParamsType, error) {
+	switch {
+	case side == exchange.OrderSideBuy() && orderType == exchange.OrderTypeMarket():
+		return SpotNewOrderRequestTypeBuyMarket, nil
+	case side == exchange.OrderSideSell() && orderType == exchange.OrderTypeMarket():
+		return SpotNewOrderRequestTypeSellMarket, nil
+	case side == exchange.OrderSideBuy() && orderType == exchange.OrderTypeLimit():
+		return SpotNewOrderRequestTypeBuyLimit, nil
+	case side == exchange.OrderSideSell() && orderType == exchange.OrderTypeLimit():
+		return SpotNewOrderRequestTypeSellLimit, nil
+	}
+	return "", fmt.Errorf("unsupported order side %s and type %s", side, orderType)
 }
 
 // ModifyExchangeOrder will allow of changing orderbook placement and limit to
@@ -182,7 +300,51 @@ func (h *HUOBI) ModifyExchangeOrder(orderID int64, action exchange.ModifyOrder)
 
 // CancelExchangeOrder cancels an order by its corresponding ID number
 func (h *HUOBI) CancelExchangeOrder(orderID int64) error {
-	return errors.New("not yet implemented")
+	_, err := h.CancelOrder(orderID)
+	return err
+}
+
+// CancelOrderByClientID cancels an order by its client order ID
+func (h *HUOBI) CancelOrderByClientID(clientID string) error {
+	_, err := h.CancelOrderByClientOrderID(clientID)
+	return err
+}
+
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (h *HUOBI) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, h.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, batching
+// up to huobiMaxBatchCancel orders per request
+func (h *HUOBI) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	var results []exchange.BatchCancelResult
+	for start := 0; start < len(orderIDs); start += huobiMaxBatchCancel {
+		end := start + huobiMaxBatchCancel
+		if end > len(orderIDs) {
+			end = len(orderIDs)
+		}
+
+		batch := orderIDs[start:end]
+		resp, err := h.CancelOrderBatch(batch)
+		if err != nil {
+			results = append(results, exchange.NewBatchCancelResults(batch, err)...)
+			continue
+		}
+
+		batchResults := exchange.NewBatchCancelResults(batch, nil)
+		for _, failed := range resp.Failed {
+			for x := range batchResults {
+				if batchResults[x].OrderID == failed.OrderID {
+					batchResults[x].Err = fmt.Errorf("%s: %s", failed.ErrorCode,
+						failed.ErrorMessage)
+				}
+			}
+		}
+		results = append(results, batchResults...)
+	}
+	return results
 }
 
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
@@ -193,7 +355,70 @@ func (h *HUOBI) CancelAllExchangeOrders() error {
 // GetExchangeOrderInfo returns information on a current open order
 func (h *HUOBI) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
-	return orderDetail, errors.New("not yet implemented")
+	order, err := h.GetOrder(orderID)
+	if err != nil {
+		return orderDetail, err
+	}
+
+	orderDetail.Exchange = h.GetName()
+	orderDetail.ID = int64(order.ID)
+	orderDetail.CreationTime = order.CreatedAt
+	orderDetail.Status = order.State
+	orderDetail.Price, _ = strconv.ParseFloat(order.Price, 64)
+	orderDetail.Amount, _ = strconv.ParseFloat(order.Amount, 64)
+	filled, _ := strconv.ParseFloat(order.FieldAmount, 64)
+	orderDetail.OpenVolume = orderDetail.Amount - filled
+
+	orderDetail.OrderSide = string(exchange.OrderSideSell())
+	if common.StringContains(order.Type, "buy") {
+		orderDetail.OrderSide = string(exchange.OrderSideBuy())
+	}
+
+	orderDetail.OrderType = string(exchange.OrderTypeLimit())
+	if common.StringContains(order.Type, "market") {
+		orderDetail.OrderType = string(exchange.OrderTypeMarket())
+	}
+	return orderDetail, nil
+}
+
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (h *HUOBI) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	var openOrders []exchange.OrderDetail
+	for _, p := range h.GetEnabledCurrencies() {
+		orders, err := h.GetOrders(exchange.FormatExchangeCurrency(h.Name, p).String(),
+			"", "", "", "submitted,partial-filled", "", "", "")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, order := range orders {
+			orderDetail := exchange.OrderDetail{
+				Exchange:      h.GetName(),
+				ID:            int64(order.ID),
+				ClientID:      order.ClientOrderID,
+				BaseCurrency:  p.FirstCurrency.String(),
+				QuoteCurrency: p.SecondCurrency.String(),
+				CreationTime:  order.CreatedAt,
+				Status:        order.State,
+			}
+			orderDetail.Price, _ = strconv.ParseFloat(order.Price, 64)
+			orderDetail.Amount, _ = strconv.ParseFloat(order.Amount, 64)
+			filled, _ := strconv.ParseFloat(order.FieldAmount, 64)
+			orderDetail.OpenVolume = orderDetail.Amount - filled
+
+			orderDetail.OrderSide = string(exchange.OrderSideSell())
+			if common.StringContains(order.Type, "buy") {
+				orderDetail.OrderSide = string(exchange.OrderSideBuy())
+			}
+
+			orderDetail.OrderType = string(exchange.OrderTypeLimit())
+			if common.StringContains(order.Type, "market") {
+				orderDetail.OrderType = string(exchange.OrderTypeMarket())
+			}
+			openOrders = append(openOrders, orderDetail)
+		}
+	}
+	return openOrders, nil
 }
 
 // GetExchangeDepositAddress returns a deposit address for a specified currency
@@ -213,9 +438,72 @@ func (h *HUOBI) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount flo
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (h *HUOBI) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	var limits []exchange.WithdrawalLimit
+	checked := make(map[pair.CurrencyItem]bool)
+	for _, p := range h.GetEnabledCurrencies() {
+		for _, c := range []pair.CurrencyItem{p.FirstCurrency, p.SecondCurrency} {
+			if checked[c] {
+				continue
+			}
+			checked[c] = true
+
+			quota, err := h.GetWithdrawQuota(c.String())
+			if err != nil {
+				return nil, err
+			}
+
+			if len(quota.Chains) == 0 {
+				continue
+			}
+
+			// Quotas are shared across the chains of a currency
+			chain := quota.Chains[0]
+			if chain.WithdrawQuotaPerDay > 0 {
+				limits = append(limits, exchange.WithdrawalLimit{
+					Currency: c,
+					Amount:   chain.WithdrawQuotaPerDay,
+					Window:   time.Hour * 24,
+				})
+			}
+			if chain.WithdrawQuotaPerYear > 0 {
+				limits = append(limits, exchange.WithdrawalLimit{
+					Currency: c,
+					Amount:   chain.WithdrawQuotaPerYear,
+					Window:   time.Hour * 24 * 365,
+				})
+			}
+		}
+	}
+	return limits, nil
+}
+
+// TransferWalletFunds moves funds between the spot (exchange) account and the
+// isolated margin account of the transfer pair
+func (h *HUOBI) TransferWalletFunds(transfer exchange.WalletTransfer) (string, error) {
+	err := transfer.Validate(exchange.WalletExchange, exchange.WalletMargin)
+	if err != nil {
+		return "", err
+	}
+
+	if transfer.Pair.Empty() {
+		return "", errors.New("margin wallet transfers require a currency pair")
+	}
+
+	transferID, err := h.MarginTransfer(transfer.Pair.Pair().Lower().String(),
+		transfer.Currency.Lower().String(),
+		transfer.Amount,
+		transfer.To == exchange.WalletMargin)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(transferID, 10), nil
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (h *HUOBI) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (h *HUOBI) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -224,6 +512,15 @@ func (h *HUOBI) GetWebsocket() (*exchange.Websocket, error) {
 	return h.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (h *HUOBI) GetExchangeServerTime() (time.Time, error) {
+	resp, err := h.GetTimestamp()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, resp*int64(time.Millisecond)), nil
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (h *HUOBI) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return h.GetFee(feeBuilder)
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"strconv"
 	"time"
@@ -51,8 +50,21 @@ const (
 
 	huobihadaxAuthRate   = 100
 	huobihadaxUnauthRate = 100
+
+	// huobihadaxSignatureHost is the host signed by signature version 2
+	// requests
+	huobihadaxSignatureHost = "api.huobi.pro"
 )
 
+// huobihadaxCanonicalRules are the canonicalization rules of signature
+// version 2, the method, host, path and sorted query string on separate lines
+var huobihadaxCanonicalRules = request.CanonicalRules{
+	Separator: "\n",
+	Method:    true,
+	Host:      true,
+	Path:      true,
+}
+
 // HUOBIHADAX is the overarching type across this package
 type HUOBIHADAX struct {
 	exchange.Base
@@ -77,13 +89,14 @@ func (h *HUOBIHADAX) SetDefaults() {
 		request.NewRateLimit(time.Second*10, huobihadaxAuthRate),
 		request.NewRateLimit(time.Second*10, huobihadaxUnauthRate),
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
+	h.SetCanonicalizer(huobihadaxCanonicalRules.Canonicalize)
 	h.APIUrlDefault = huobihadaxAPIURL
 	h.APIUrl = h.APIUrlDefault
 	h.WebsocketInit()
 }
 
 // Setup sets user configuration
-func (h *HUOBIHADAX) Setup(exch config.ExchangeConfig) {
+func (h *HUOBIHADAX) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		h.SetEnabled(false)
 	} else {
@@ -101,25 +114,34 @@ func (h *HUOBIHADAX) Setup(exch config.ExchangeConfig) {
 		h.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := h.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = h.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = h.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = h.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = h.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = h.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = h.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetSpotKline returns kline data
@@ -760,11 +782,15 @@ func (h *HUOBIHADAX) SendAuthenticatedHTTPPostRequest(method, endpoint, postBody
 	signatureParams.Set("AccessKeyId", h.APIKey)
 	signatureParams.Set("SignatureMethod", "HmacSHA256")
 	signatureParams.Set("SignatureVersion", "2")
-	signatureParams.Set("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05"))
+	signatureParams.Set("Timestamp", h.GetAdjustedTime().UTC().Format("2006-01-02T15:04:05"))
 
 	endpoint = fmt.Sprintf("/v%s/%s", huobihadaxAPIVersion, endpoint)
-	payload := fmt.Sprintf("%s\napi.huobi.pro\n%s\n%s",
-		method, endpoint, signatureParams.Encode())
+	payload := h.Canonicalize(&request.CanonicalRequest{
+		Method: method,
+		Host:   huobihadaxSignatureHost,
+		Path:   endpoint,
+		Params: signatureParams,
+	})
 
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
@@ -788,11 +814,15 @@ func (h *HUOBIHADAX) SendAuthenticatedHTTPRequest(method, endpoint string, value
 	values.Set("AccessKeyId", h.APIKey)
 	values.Set("SignatureMethod", "HmacSHA256")
 	values.Set("SignatureVersion", "2")
-	values.Set("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05"))
+	values.Set("Timestamp", h.GetAdjustedTime().UTC().Format("2006-01-02T15:04:05"))
 
 	endpoint = fmt.Sprintf("/v%s/%s", huobihadaxAPIVersion, endpoint)
-	payload := fmt.Sprintf("%s\napi.huobi.pro\n%s\n%s",
-		method, endpoint, values.Encode())
+	payload := h.Canonicalize(&request.CanonicalRequest{
+		Method: method,
+		Host:   huobihadaxSignatureHost,
+		Path:   endpoint,
+		Params: values,
+	})
 
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
@@ -811,7 +841,8 @@ func (h *HUOBIHADAX) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 	var fee float64
 	switch feeBuilder.FeeType {
 	case exchange.CryptocurrencyTradeFee:
-		fee = calculateTradingFee(feeBuilder.PurchasePrice, feeBuilder.Amount)
+		fee = h.DiscountTradingFee(feeBuilder,
+			calculateTradingFee(feeBuilder.PurchasePrice, feeBuilder.Amount))
 	}
 	if fee < 0 {
 		fee = 0
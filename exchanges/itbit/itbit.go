@@ -66,7 +66,7 @@ func (i *ItBit) SetDefaults() {
 }
 
 // Setup sets the exchange parameters from exchange config
-func (i *ItBit) Setup(exch config.ExchangeConfig) {
+func (i *ItBit) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		i.SetEnabled(false)
 	} else {
@@ -82,25 +82,34 @@ func (i *ItBit) Setup(exch config.ExchangeConfig) {
 		i.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := i.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = i.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = i.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = i.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = i.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = i.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = i.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetTicker returns ticker info for a specified market.
@@ -351,16 +360,23 @@ func (i *ItBit) SendAuthenticatedHTTPRequest(method string, path string, params
 	}
 
 	nonce := i.Nonce.GetValue(i.Name, false).String()
-	timestamp := strconv.FormatInt(time.Now().UnixNano()/1000000, 10)
+	timestamp := strconv.FormatInt(i.GetAdjustedTime().UnixNano()/1000000, 10)
 
 	message, err := common.JSONEncode([]string{method, url, string(PayloadJSON), nonce, timestamp})
 	if err != nil {
 		return err
 	}
 
-	hash := common.GetSHA256([]byte(nonce + string(message)))
-	hmac := common.GetHMAC(common.HashSHA512, []byte(url+string(hash)), []byte(i.APISecret))
-	signature := common.Base64Encode(hmac)
+	signer, err := exchange.NewSigner(exchange.SignHMACSHA512, exchange.SignatureEncodingBase64, i.APISecret)
+	if err != nil {
+		return err
+	}
+
+	hash := common.GetSHA256(exchange.CanonicalMessage("", nonce, string(message)))
+	signature, err := signer.SignToString(exchange.CanonicalMessage("", url, string(hash)))
+	if err != nil {
+		return err
+	}
 
 	headers := make(map[string]string)
 	headers["Authorization"] = i.ClientID + ":" + signature
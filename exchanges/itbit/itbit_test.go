@@ -3,8 +3,10 @@ package itbit
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 )
@@ -62,6 +64,18 @@ func TestGetTradeHistory(t *testing.T) {
 	}
 }
 
+func TestGetExchangeHistorySince(t *testing.T) {
+	t.Parallel()
+	p := pair.NewCurrencyPair(symbol.XBT, symbol.USD)
+	trades, err := i.GetExchangeHistorySince(p, time.Time{}, 10)
+	if err != nil {
+		t.Error("Test Failed - GetExchangeHistorySince() error", err)
+	}
+	if len(trades) > 10 {
+		t.Error("Test Failed - GetExchangeHistorySince() limit not applied")
+	}
+}
+
 func TestGetWallets(t *testing.T) {
 	_, err := i.GetWallets(url.Values{})
 	if err == nil {
@@ -1,34 +1,47 @@
 package itbit
 
 import (
+	"context"
 	"errors"
 	"log"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the ItBit exchange
+func init() {
+	exchange.Register("ITBIT", func() exchange.IBotExchange { return new(ItBit) })
+}
+
 // Start starts the ItBit go routine
-func (i *ItBit) Start(wg *sync.WaitGroup) {
+func (i *ItBit) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		i.Run()
+		i.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the ItBit wrapper
-func (i *ItBit) Run() {
+func (i *ItBit) Run(ctx context.Context) {
 	if i.Verbose {
 		log.Printf("%s polling delay: %ds.\n", i.GetName(), i.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", i.GetName(), len(i.EnabledPairs), i.EnabledPairs)
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (i *ItBit) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (i *ItBit) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
@@ -102,12 +115,12 @@ func (i *ItBit) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderboo
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: amount, Price: price})
 	}
 
-	orderbook.ProcessOrderbook(i.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(i.GetName(), p, i.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(i.Name, p, assetType)
 }
 
 // GetExchangeAccountInfo retrieves balances for all enabled currencies for the
-//ItBit exchange - to-do
+// ItBit exchange - to-do
 func (i *ItBit) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	var response exchange.AccountInfo
 	response.ExchangeName = i.GetName()
@@ -121,11 +134,57 @@ func (i *ItBit) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error)
 	return fundHistory, errors.New("not supported on exchange")
 }
 
-// GetExchangeHistory returns historic trade data since exchange opening.
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (i *ItBit) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (i *ItBit) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeHistory returns the most recent public trades for a currency pair
 func (i *ItBit) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+	return i.GetExchangeHistorySince(p, time.Time{}, 0)
+}
+
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (i *ItBit) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
+// GetExchangeHistorySince returns up to limit of the most recent public trades
+// for a currency pair executed at or after since. ItBit filters trades by
+// match number rather than time, so the recent trades are filtered locally.
+// ItBit doesn't report the taker side, so it is inferred from the price moves
+func (i *ItBit) GetExchangeHistorySince(p pair.CurrencyPair, since time.Time, limit int) ([]exchange.TradeHistory, error) {
+	trades, err := i.GetTradeHistory(i.FormatPair(p, exchange.PairFormatRequest), "0")
+	if err != nil {
+		return nil, err
+	}
+
 	var resp []exchange.TradeHistory
+	for x := range trades.RecentTrades {
+		timestamp, err := time.Parse(time.RFC3339, trades.RecentTrades[x].Timestamp)
+		if err != nil {
+			return nil, err
+		}
 
-	return resp, errors.New("trade history not yet implemented")
+		resp = append(resp, exchange.TradeHistory{
+			Timestamp: timestamp.Unix(),
+			TID:       trades.RecentTrades[x].MatchNumber,
+			Price:     trades.RecentTrades[x].Price,
+			Amount:    trades.RecentTrades[x].Amount,
+			Exchange:  i.Name,
+		})
+	}
+	resp = exchange.FilterTradeHistory(resp, time.Time{}, 0)
+	exchange.InferTradeSides(resp)
+	return exchange.FilterTradeHistory(resp, since, limit), nil
 }
 
 // SubmitExchangeOrder submits a new order
@@ -144,6 +203,18 @@ func (i *ItBit) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (i *ItBit) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, i.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (i *ItBit) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, i.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (i *ItBit) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -155,6 +226,11 @@ func (i *ItBit) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, error
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (i *ItBit) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (i *ItBit) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -172,9 +248,14 @@ func (i *ItBit) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount flo
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (i *ItBit) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (i *ItBit) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (i *ItBit) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -183,6 +264,11 @@ func (i *ItBit) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (i *ItBit) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (i *ItBit) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return i.GetFee(feeBuilder)
@@ -0,0 +1,159 @@
+package kline
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Vars for the candle builder registry
+var (
+	builders    = make(map[string]*Builder)
+	buildersMtx sync.Mutex
+
+	// ErrInvalidTrade is returned when a trade is missing required details
+	ErrInvalidTrade = errors.New("trade requires a pair, price, amount and timestamp")
+)
+
+// Builder aggregates the trades of an exchange into candles at each of its
+// intervals in real time. The candle in progress is kept up to date in the
+// kline store and marked closed once a trade arrives for a later interval or
+// the builder is flushed after the interval ends
+type Builder struct {
+	exchange  string
+	intervals []time.Duration
+	current   map[string]*Candle
+	closed    map[string]time.Time
+	m         sync.Mutex
+}
+
+// NewBuilder returns a candle builder for an exchange
+func NewBuilder(exchange string, intervals []time.Duration) *Builder {
+	return &Builder{
+		exchange:  exchange,
+		intervals: intervals,
+		current:   make(map[string]*Candle),
+		closed:    make(map[string]time.Time),
+	}
+}
+
+// AddTrade adds a trade to the candle in progress for each interval. Trades
+// older than the candle in progress or belonging to a candle which has already
+// been closed are ignored so closed candles are never rewritten
+func (b *Builder) AddTrade(p pair.CurrencyPair, assetType string, price, amount float64, timestamp time.Time) error {
+	if p.Empty() || price <= 0 || amount < 0 || timestamp.IsZero() {
+		return ErrInvalidTrade
+	}
+
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	var updated []Candle
+	for _, interval := range b.intervals {
+		key := candlesKey(b.exchange, p, assetType, interval)
+		start := timestamp.Truncate(interval)
+
+		if last, ok := b.closed[key]; ok && !start.After(last) {
+			continue
+		}
+
+		c, ok := b.current[key]
+		if ok && start.Before(c.StartTime) {
+			continue
+		}
+
+		if ok && start.After(c.StartTime) {
+			c.Closed = true
+			b.closed[key] = c.StartTime
+			updated = append(updated, *c)
+			ok = false
+		}
+
+		if !ok {
+			c = &Candle{
+				Pair:      p,
+				AssetType: assetType,
+				Interval:  interval,
+				StartTime: start,
+				Open:      price,
+				High:      price,
+				Low:       price,
+				Source:    SourceTrades,
+			}
+			b.current[key] = c
+		}
+
+		if price > c.High {
+			c.High = price
+		}
+		if price < c.Low {
+			c.Low = price
+		}
+		c.Close = price
+		c.Volume += amount
+		c.Trades++
+		updated = append(updated, *c)
+	}
+	return ProcessCandles(b.exchange, updated)
+}
+
+// Flush closes the candles in progress whose interval ended before now
+func (b *Builder) Flush(now time.Time) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	var closed []Candle
+	for key, c := range b.current {
+		if now.Before(c.EndTime()) {
+			continue
+		}
+		c.Closed = true
+		closed = append(closed, *c)
+		b.closed[key] = c.StartTime
+		delete(b.current, key)
+	}
+	return ProcessCandles(b.exchange, closed)
+}
+
+// SetBuilder sets the intervals candles are built at from the trades of an
+// exchange, no intervals removes the builder
+func SetBuilder(exchange string, intervals []time.Duration) {
+	buildersMtx.Lock()
+	defer buildersMtx.Unlock()
+
+	if len(intervals) == 0 {
+		delete(builders, common.StringToLower(exchange))
+		return
+	}
+	builders[common.StringToLower(exchange)] = NewBuilder(exchange, intervals)
+}
+
+// ProcessTrade adds a trade to the candle builder of the exchange, trades for
+// exchanges without a builder are ignored
+func ProcessTrade(exchange string, p pair.CurrencyPair, assetType string, price, amount float64, timestamp time.Time) error {
+	buildersMtx.Lock()
+	b, ok := builders[common.StringToLower(exchange)]
+	buildersMtx.Unlock()
+	if !ok {
+		return nil
+	}
+	return b.AddTrade(p, assetType, price, amount, timestamp)
+}
+
+// FlushBuilders closes the candles in progress of every builder whose
+// interval ended before now
+func FlushBuilders(now time.Time) error {
+	buildersMtx.Lock()
+	defer buildersMtx.Unlock()
+
+	for _, b := range builders {
+		err := b.Flush(now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
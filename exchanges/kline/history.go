@@ -0,0 +1,95 @@
+package kline
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrInvalidRange is returned when requesting candles for an empty time
+	// range or a non positive interval or limit
+	ErrInvalidRange = errors.New("candle requests require an end after the start and a positive interval and limit")
+)
+
+// GapError is returned with a candle series which is missing bars, listing
+// the start time of each missing bar
+type GapError struct {
+	Interval time.Duration
+	Missing  []time.Time
+}
+
+func (g *GapError) Error() string {
+	return fmt.Sprintf("candle series missing %d %v bars, first missing at %s",
+		len(g.Missing), g.Interval, g.Missing[0].UTC().Format(time.RFC3339))
+}
+
+// FetchFunc fetches the candles starting within a time range from an
+// exchange in a single request
+type FetchFunc func(start, end time.Time) ([]Candle, error)
+
+// Paginate fetches the candles starting from start up to end, splitting the
+// range into requests of at most limit candles. Overlapping bars returned by
+// more than one request are deduplicated, keeping the most recently fetched,
+// and the series is returned in start time order. When bars are missing from
+// the series it is returned along with a GapError
+func Paginate(start, end time.Time, interval time.Duration, limit int, fetch FetchFunc) ([]Candle, error) {
+	start = start.Truncate(interval)
+	if !end.After(start) || interval <= 0 || limit <= 0 {
+		return nil, ErrInvalidRange
+	}
+
+	window := interval * time.Duration(limit)
+	bars := make(map[int64]Candle)
+	for from := start; from.Before(end); from = from.Add(window) {
+		to := from.Add(window - interval)
+		if !to.Before(end) {
+			to = end.Add(-interval)
+		}
+
+		result, err := fetch(from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		for x := range result {
+			if result[x].StartTime.Before(start) || !result[x].StartTime.Before(end) {
+				continue
+			}
+			bars[result[x].StartTime.UnixNano()] = result[x]
+		}
+	}
+
+	series := make([]Candle, 0, len(bars))
+	for _, c := range bars {
+		series = append(series, c)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].StartTime.Before(series[j].StartTime)
+	})
+	return series, ValidateContinuity(series, start, end, interval)
+}
+
+// ValidateContinuity checks a candle series in start time order has a bar for
+// every interval from start up to end, returning a GapError listing the
+// missing bars
+func ValidateContinuity(series []Candle, start, end time.Time, interval time.Duration) error {
+	var missing []time.Time
+	next := start
+	for x := range series {
+		for next.Before(series[x].StartTime) {
+			missing = append(missing, next)
+			next = next.Add(interval)
+		}
+		next = series[x].StartTime.Add(interval)
+	}
+	for ; next.Before(end); next = next.Add(interval) {
+		missing = append(missing, next)
+	}
+
+	if len(missing) > 0 {
+		return &GapError{Interval: interval, Missing: missing}
+	}
+	return nil
+}
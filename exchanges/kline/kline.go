@@ -0,0 +1,140 @@
+package kline
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Candle sources
+const (
+	SourceExchange = "EXCHANGE"
+	SourceTrades   = "TRADES"
+)
+
+// MaxCandles is the maximum number of candles kept per exchange, pair, asset
+// type and interval, the oldest candles are dropped first
+const MaxCandles = 1000
+
+// Vars for the kline store
+var (
+	candles    = make(map[string][]Candle)
+	candlesMtx sync.Mutex
+
+	// ErrInvalidCandle is returned when a candle is missing required details
+	ErrInvalidCandle = errors.New("candle requires a pair, interval and start time")
+)
+
+// Candle holds an OHLCV bar for a currency pair over an interval. Candles
+// built from trades are marked Closed once their interval has ended
+type Candle struct {
+	Pair      pair.CurrencyPair `json:"pair"`
+	AssetType string            `json:"assetType"`
+	Interval  time.Duration     `json:"interval"`
+	StartTime time.Time         `json:"startTime"`
+	Open      float64           `json:"open"`
+	High      float64           `json:"high"`
+	Low       float64           `json:"low"`
+	Close     float64           `json:"close"`
+	Volume    float64           `json:"volume"`
+	Trades    int64             `json:"trades"`
+	Closed    bool              `json:"closed"`
+	Source    string            `json:"source"`
+}
+
+// EndTime returns the time the candle interval ends
+func (c *Candle) EndTime() time.Time {
+	return c.StartTime.Add(c.Interval)
+}
+
+func candlesKey(exchange string, p pair.CurrencyPair, assetType string, interval time.Duration) string {
+	return common.StringToLower(exchange) + " " + p.FirstCurrency.Upper().String() +
+		p.SecondCurrency.Upper().String() + " " + assetType + " " + interval.String()
+}
+
+// ProcessCandles stores candles for an exchange, replacing any stored candle
+// with the same pair, asset type, interval and start time
+func ProcessCandles(exchange string, c []Candle) error {
+	for x := range c {
+		if c[x].Pair.Empty() || c[x].Interval <= 0 || c[x].StartTime.IsZero() {
+			return ErrInvalidCandle
+		}
+	}
+
+	candlesMtx.Lock()
+	defer candlesMtx.Unlock()
+
+	for x := range c {
+		key := candlesKey(exchange, c[x].Pair, c[x].AssetType, c[x].Interval)
+		candles[key] = insertCandle(candles[key], c[x])
+	}
+	return nil
+}
+
+// insertCandle inserts or replaces a candle keeping the candles ordered by
+// start time and capped at MaxCandles
+func insertCandle(stored []Candle, c Candle) []Candle {
+	i := sort.Search(len(stored), func(i int) bool {
+		return !stored[i].StartTime.Before(c.StartTime)
+	})
+
+	if i < len(stored) && stored[i].StartTime.Equal(c.StartTime) {
+		stored[i] = c
+		return stored
+	}
+
+	stored = append(stored, Candle{})
+	copy(stored[i+1:], stored[i:])
+	stored[i] = c
+	if len(stored) > MaxCandles {
+		stored = stored[len(stored)-MaxCandles:]
+	}
+	return stored
+}
+
+// GetCandles returns the stored candles for an exchange pair, asset type and
+// interval which start within the time range in start time order. A zero
+// start or end leaves that side of the range open
+func GetCandles(exchange string, p pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) []Candle {
+	candlesMtx.Lock()
+	defer candlesMtx.Unlock()
+
+	var result []Candle
+	for _, c := range candles[candlesKey(exchange, p, assetType, interval)] {
+		if !start.IsZero() && c.StartTime.Before(start) {
+			continue
+		}
+		if !end.IsZero() && c.StartTime.After(end) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// ParseIntervals parses a comma separated list of candle intervals such as
+// "1m,5m,1h"
+func ParseIntervals(intervals string) ([]time.Duration, error) {
+	var result []time.Duration
+	for _, x := range common.SplitStrings(intervals, ",") {
+		x = common.StringToLower(common.TrimString(x, " "))
+		if x == "" {
+			continue
+		}
+
+		interval, err := time.ParseDuration(x)
+		if err != nil {
+			return nil, err
+		}
+
+		if interval <= 0 {
+			return nil, errors.New("candle interval must be positive")
+		}
+		result = append(result, interval)
+	}
+	return result, nil
+}
@@ -0,0 +1,184 @@
+package kline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestProcessCandles(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	start := time.Unix(1500000000, 0)
+	err := ProcessCandles("Binance", []Candle{{Pair: p, AssetType: "SPOT", Interval: time.Minute}})
+	if err != ErrInvalidCandle {
+		t.Error("Test Failed - ProcessCandles() invalid candle error", err)
+	}
+
+	err = ProcessCandles("Binance", []Candle{
+		{Pair: p, AssetType: "SPOT", Interval: time.Minute, StartTime: start.Add(time.Minute), Close: 2},
+		{Pair: p, AssetType: "SPOT", Interval: time.Minute, StartTime: start, Close: 1},
+		{Pair: p, AssetType: "SPOT", Interval: time.Minute, StartTime: start.Add(time.Minute), Close: 3},
+	})
+	if err != nil {
+		t.Fatal("Test Failed - ProcessCandles() error", err)
+	}
+
+	result := GetCandles("binance", p, "SPOT", time.Minute, time.Time{}, time.Time{})
+	if len(result) != 2 || result[0].Close != 1 || result[1].Close != 3 {
+		t.Errorf("Test Failed - GetCandles() unexpected candles %v", result)
+	}
+
+	result = GetCandles("binance", p, "SPOT", time.Minute, start.Add(time.Second), time.Time{})
+	if len(result) != 1 || result[0].Close != 3 {
+		t.Errorf("Test Failed - GetCandles() range unexpected candles %v", result)
+	}
+}
+
+func TestParseIntervals(t *testing.T) {
+	intervals, err := ParseIntervals("1m, 5M,1h")
+	if err != nil {
+		t.Fatal("Test Failed - ParseIntervals() error", err)
+	}
+	if len(intervals) != 3 || intervals[1] != 5*time.Minute || intervals[2] != time.Hour {
+		t.Errorf("Test Failed - ParseIntervals() unexpected intervals %v", intervals)
+	}
+
+	_, err = ParseIntervals("1m,daily")
+	if err == nil {
+		t.Error("Test Failed - ParseIntervals() invalid interval error")
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	p := pair.NewCurrencyPair("LTC", "BTC")
+	start := time.Unix(1500000000, 0).Truncate(5 * time.Minute)
+	SetBuilder("Bitstamp", []time.Duration{time.Minute, 5 * time.Minute})
+
+	trades := []struct {
+		offset time.Duration
+		price  float64
+		amount float64
+	}{
+		{0, 10, 1},
+		{20 * time.Second, 12, 2},
+		{40 * time.Second, 9, 1},
+		{70 * time.Second, 11, 3},
+		{30 * time.Second, 50, 1},
+	}
+
+	for _, x := range trades {
+		err := ProcessTrade("bitstamp", p, "SPOT", x.price, x.amount, start.Add(x.offset))
+		if err != nil {
+			t.Fatal("Test Failed - ProcessTrade() error", err)
+		}
+	}
+
+	minute := GetCandles("Bitstamp", p, "SPOT", time.Minute, time.Time{}, time.Time{})
+	if len(minute) != 2 {
+		t.Fatalf("Test Failed - AddTrade() expected 2 minute candles received %d", len(minute))
+	}
+
+	c := minute[0]
+	if !c.Closed || c.Open != 10 || c.High != 12 || c.Low != 9 || c.Close != 9 ||
+		c.Volume != 4 || c.Trades != 3 || c.Source != SourceTrades {
+		t.Errorf("Test Failed - AddTrade() unexpected closed candle %+v", c)
+	}
+
+	if minute[1].Closed || minute[1].Open != 11 {
+		t.Errorf("Test Failed - AddTrade() unexpected candle in progress %+v", minute[1])
+	}
+
+	fiveMinute := GetCandles("Bitstamp", p, "SPOT", 5*time.Minute, time.Time{}, time.Time{})
+	if len(fiveMinute) != 1 || fiveMinute[0].High != 50 || fiveMinute[0].Volume != 8 {
+		t.Errorf("Test Failed - AddTrade() unexpected five minute candle %+v", fiveMinute)
+	}
+
+	err := FlushBuilders(start.Add(2 * time.Minute))
+	if err != nil {
+		t.Fatal("Test Failed - FlushBuilders() error", err)
+	}
+
+	minute = GetCandles("Bitstamp", p, "SPOT", time.Minute, time.Time{}, time.Time{})
+	if !minute[1].Closed {
+		t.Error("Test Failed - FlushBuilders() candle not closed")
+	}
+
+	fiveMinute = GetCandles("Bitstamp", p, "SPOT", 5*time.Minute, time.Time{}, time.Time{})
+	if fiveMinute[0].Closed {
+		t.Error("Test Failed - FlushBuilders() closed candle before interval ended")
+	}
+
+	// Late trades for closed candles are dropped rather than reopening them
+	err = ProcessTrade("Bitstamp", p, "SPOT", 100, 5, start.Add(70*time.Second))
+	if err != nil {
+		t.Fatal("Test Failed - ProcessTrade() error", err)
+	}
+
+	minute = GetCandles("Bitstamp", p, "SPOT", time.Minute, time.Time{}, time.Time{})
+	if len(minute) != 2 || !minute[1].Closed || minute[1].High != 11 || minute[1].Volume != 3 {
+		t.Errorf("Test Failed - AddTrade() late trade changed closed candle %+v", minute)
+	}
+
+	fiveMinute = GetCandles("Bitstamp", p, "SPOT", 5*time.Minute, time.Time{}, time.Time{})
+	if fiveMinute[0].High != 100 || fiveMinute[0].Volume != 13 {
+		t.Errorf("Test Failed - AddTrade() trade not added to open candle %+v", fiveMinute[0])
+	}
+
+	SetBuilder("Bitstamp", nil)
+	err = ProcessTrade("Bitstamp", p, "SPOT", 1, 1, start.Add(time.Hour))
+	if err != nil {
+		t.Error("Test Failed - ProcessTrade() without builder error", err)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	start := time.Unix(1500000000, 0).Truncate(time.Minute)
+	end := start.Add(time.Minute * 25)
+
+	var requests int
+	fetch := func(from, to time.Time) ([]Candle, error) {
+		requests++
+		var result []Candle
+		// Return an overlapping bar before the window to test deduplication
+		for c := from.Add(-time.Minute); !c.After(to); c = c.Add(time.Minute) {
+			result = append(result, Candle{Pair: p, Interval: time.Minute,
+				StartTime: c, Close: float64(requests)})
+		}
+		return result, nil
+	}
+
+	_, err := Paginate(end, start, time.Minute, 10, fetch)
+	if err != ErrInvalidRange {
+		t.Error("Test Failed - Paginate() invalid range error", err)
+	}
+
+	result, err := Paginate(start, end, time.Minute, 10, fetch)
+	if err != nil {
+		t.Fatal("Test Failed - Paginate() error", err)
+	}
+	if requests != 3 {
+		t.Errorf("Test Failed - Paginate() expected 3 requests, got %d", requests)
+	}
+	if len(result) != 25 || !result[0].StartTime.Equal(start) ||
+		!result[24].StartTime.Equal(end.Add(-time.Minute)) {
+		t.Fatalf("Test Failed - Paginate() unexpected series %v", result)
+	}
+	if result[10].Close != 2 || result[20].Close != 3 {
+		t.Error("Test Failed - Paginate() overlapping bars not replaced by later requests")
+	}
+
+	gaps := func(from, to time.Time) ([]Candle, error) {
+		return []Candle{{Pair: p, Interval: time.Minute, StartTime: from.Add(time.Minute)}}, nil
+	}
+	result, err = Paginate(start, start.Add(time.Minute*3), time.Minute, 10, gaps)
+	gapErr, ok := err.(*GapError)
+	if !ok {
+		t.Fatal("Test Failed - Paginate() expected gap error", err)
+	}
+	if len(result) != 1 || len(gapErr.Missing) != 2 ||
+		!gapErr.Missing[0].Equal(start) || !gapErr.Missing[1].Equal(start.Add(time.Minute*2)) {
+		t.Errorf("Test Failed - Paginate() unexpected gaps %v", gapErr.Missing)
+	}
+}
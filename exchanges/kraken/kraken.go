@@ -6,8 +6,10 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/exchanges"
@@ -41,6 +43,7 @@ const (
 	krakenOrderPlace     = "AddOrder"
 	krakenWithdrawInfo   = "WithdrawInfo"
 	krakenDepositMethods = "DepositMethods"
+	krakenWebsocketToken = "GetWebSocketsToken"
 
 	krakenAuthRate   = 0
 	krakenUnauthRate = 0
@@ -49,7 +52,13 @@ const (
 // Kraken is the overarching type across the alphapoint package
 type Kraken struct {
 	exchange.Base
-	CryptoFee, FiatFee float64
+	CryptoFee, FiatFee    float64
+	WebsocketConn         *websocket.Conn
+	AuthWebsocketConn     *websocket.Conn
+	WebsocketSubdChannels map[int64]WebsocketChannelData
+	wsOrderbooks          map[string]*wsOrderbook
+	wsMtx                 sync.Mutex
+	wsWriteMtx            sync.Mutex
 }
 
 // SetDefaults sets current default settings
@@ -79,7 +88,7 @@ func (k *Kraken) SetDefaults() {
 }
 
 // Setup sets current exchange configuration
-func (k *Kraken) Setup(exch config.ExchangeConfig) {
+func (k *Kraken) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		k.SetEnabled(false)
 	} else {
@@ -90,30 +99,48 @@ func (k *Kraken) Setup(exch config.ExchangeConfig) {
 		k.SetHTTPClientUserAgent(exch.HTTPUserAgent)
 		k.RESTPollingDelay = exch.RESTPollingDelay
 		k.Verbose = exch.Verbose
+		k.Websocket.SetEnabled(exch.Websocket)
 		k.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
 		k.AvailablePairs = common.SplitStrings(exch.AvailablePairs, ",")
 		k.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := k.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = k.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = k.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = k.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = k.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = k.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = k.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
+		}
+		err = k.WebsocketSetup(k.WsConnect,
+			exch.Name,
+			exch.Websocket,
+			krakenWebsocketURL,
+			exch.WebsocketURL)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetServerTime returns current server time
@@ -485,6 +512,21 @@ func (k *Kraken) GetDepositMethods(currency string) ([]DepositMethods, error) {
 	return response.Result, GetError(response.Error)
 }
 
+// GetWebsocketToken returns a token used to authenticate private websocket
+// subscriptions. The token must be used within 15 minutes of creation
+func (k *Kraken) GetWebsocketToken() (WebsocketToken, error) {
+	var response struct {
+		Error  []string       `json:"error"`
+		Result WebsocketToken `json:"result"`
+	}
+
+	if err := k.SendAuthenticatedHTTPRequest(krakenWebsocketToken, url.Values{}, &response); err != nil {
+		return response.Result, err
+	}
+
+	return response.Result, GetError(response.Error)
+}
+
 // GetTradeBalance returns full information about your trades on Kraken
 func (k *Kraken) GetTradeBalance(args ...TradeBalanceOptions) (TradeBalanceInfo, error) {
 	params := url.Values{}
@@ -895,7 +937,7 @@ func (k *Kraken) SendAuthenticatedHTTPRequest(method string, params url.Values,
 
 	path := fmt.Sprintf("/%s/private/%s", krakenAPIVersion, method)
 	if k.Nonce.Get() == 0 {
-		k.Nonce.Set(time.Now().UnixNano())
+		k.Nonce.Set(k.GetAdjustedTime().UnixNano())
 	} else {
 		k.Nonce.Inc()
 	}
@@ -1,6 +1,8 @@
 package kraken
 
 import (
+	"hash/crc32"
+	"strconv"
 	"testing"
 
 	"github.com/thrasher-/gocryptotrader/config"
@@ -327,3 +329,80 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestWebsocketChecksum(t *testing.T) {
+	t.Parallel()
+	ob := &wsOrderbook{}
+	ob.Asks = applyWebsocketLevels(nil, [][]string{
+		{"5541.30000", "2.50700000", "1534614248.123678"},
+		{"5541.80000", "0.33000000", "1534614098.345543"},
+	}, true)
+	ob.Bids = applyWebsocketLevels(nil, [][]string{
+		{"5541.20000", "1.52900000", "1534614248.765567"},
+		{"5539.90000", "0.30000000", "1534614241.769870"},
+	}, false)
+
+	expected := strconv.FormatUint(uint64(crc32.ChecksumIEEE(
+		[]byte("554130000250700000"+"55418000033000000"+"554120000152900000"+"55399000030000000"))), 10)
+	if calculateWebsocketChecksum(ob) != expected {
+		t.Error("Test Failed - calculateWebsocketChecksum() incorrect checksum")
+	}
+}
+
+func TestApplyWebsocketLevels(t *testing.T) {
+	t.Parallel()
+	var changes [][]string
+	for x := 0; x < krakenWsOrderbookDepth+2; x++ {
+		changes = append(changes, []string{strconv.Itoa(100 - x), "1.0", "0"})
+	}
+
+	bids := applyWebsocketLevels(nil, changes, false)
+	if len(bids) != krakenWsOrderbookDepth || bids[0].Price != 100 {
+		t.Error("Test Failed - applyWebsocketLevels() did not sort and truncate bids")
+	}
+
+	bids = applyWebsocketLevels(bids, [][]string{{"100", "0.00000000", "0"}, {"99", "5.0", "0"}}, false)
+	if bids[0].Price != 99 || bids[0].Amount != 5 {
+		t.Error("Test Failed - applyWebsocketLevels() did not apply updates")
+	}
+}
+
+func TestWsHandleData(t *testing.T) {
+	t.Parallel()
+	var w Kraken
+	w.SetDefaults()
+	w.Websocket.DataHandler = make(chan interface{}, 10)
+	w.WebsocketSubdChannels = make(map[int64]WebsocketChannelData)
+	w.wsOrderbooks = make(map[string]*wsOrderbook)
+
+	err := w.wsHandleEvent([]byte(`{"channelID":42,"event":"subscriptionStatus","pair":"XBT/USD","status":"subscribed","subscription":{"name":"trade"}}`))
+	if err != nil {
+		t.Fatal("Test Failed - wsHandleEvent() error", err)
+	}
+
+	err = w.wsHandleChannelData([]byte(`[42,[["5541.20000","0.15850568","1534614057.321597","s","l",""]],"trade","XBT/USD"]`))
+	if err != nil {
+		t.Fatal("Test Failed - wsHandleChannelData() error", err)
+	}
+
+	trade, ok := (<-w.Websocket.DataHandler).(exchange.TradeData)
+	if !ok || trade.Price != 5541.2 || trade.Side != "Sell" ||
+		trade.CurrencyPair.Pair().String() != "BTC-USD" {
+		t.Error("Test Failed - wsHandleChannelData() incorrect trade", trade)
+	}
+
+	err = w.wsHandleChannelData([]byte(`[[{"TDLH43-DVQXD-2KHVYY":{"ordertxid":"TDLH43-DVQXD-2KHVYY","pair":"XBT/EUR","time":"1560516023.070651","type":"sell","ordertype":"limit","price":"100000.00000","cost":"1000000.00000","fee":"1600.00000","vol":"1000000000.00000000","margin":"0.00000"}}],"ownTrades",{"sequence":1}]`))
+	if err != nil {
+		t.Fatal("Test Failed - wsHandleChannelData() error", err)
+	}
+
+	ownTrade, ok := (<-w.Websocket.DataHandler).(WsOwnTrade)
+	if !ok || ownTrade.TradeID != "TDLH43-DVQXD-2KHVYY" || ownTrade.Price != 100000 {
+		t.Error("Test Failed - wsHandleChannelData() incorrect own trade", ownTrade)
+	}
+
+	err = w.wsHandleChannelData([]byte(`[99,{},"ticker","XBT/USD"]`))
+	if err == nil {
+		t.Error("Test Failed - wsHandleChannelData() accepted unknown channel")
+	}
+}
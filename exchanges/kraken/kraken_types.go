@@ -365,3 +365,107 @@ var WithdrawalFees = map[string]float64{
 	symbol.XTZ:  0.05,
 	symbol.ZEC:  0.0001,
 }
+
+// WebsocketToken holds the token used for private websocket subscriptions
+type WebsocketToken struct {
+	Token   string `json:"token"`
+	Expires int64  `json:"expires"`
+}
+
+// WebsocketSubscription holds the subscription details for a channel
+type WebsocketSubscription struct {
+	Name  string `json:"name"`
+	Depth int64  `json:"depth,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+// WebsocketSubscriptionRequest holds a subscribe or unsubscribe request
+type WebsocketSubscriptionRequest struct {
+	Event        string                `json:"event"`
+	Pairs        []string              `json:"pair,omitempty"`
+	Subscription WebsocketSubscription `json:"subscription"`
+}
+
+// WebsocketEventResponse holds a websocket event or subscription status
+// response
+type WebsocketEventResponse struct {
+	Event        string                `json:"event"`
+	Status       string                `json:"status"`
+	ChannelID    int64                 `json:"channelID"`
+	ChannelName  string                `json:"channelName"`
+	Pair         string                `json:"pair"`
+	Subscription WebsocketSubscription `json:"subscription"`
+	ErrorMessage string                `json:"errorMessage"`
+	Version      string                `json:"version"`
+}
+
+// WebsocketChannelData holds the subscription details for a channel ID
+type WebsocketChannelData struct {
+	Subscription string
+	Pair         string
+}
+
+// WsTicker holds websocket ticker data
+type WsTicker struct {
+	Ask    []string `json:"a"`
+	Bid    []string `json:"b"`
+	Close  []string `json:"c"`
+	Volume []string `json:"v"`
+	VWAP   []string `json:"p"`
+	Trades []int64  `json:"t"`
+	Low    []string `json:"l"`
+	High   []string `json:"h"`
+	Open   []string `json:"o"`
+}
+
+// WsOwnTrade holds a private websocket trade execution
+type WsOwnTrade struct {
+	TradeID   string
+	OrderID   string  `json:"ordertxid"`
+	PosID     string  `json:"postxid"`
+	Pair      string  `json:"pair"`
+	Time      float64 `json:"time,string"`
+	Type      string  `json:"type"`
+	OrderType string  `json:"ordertype"`
+	Price     float64 `json:"price,string"`
+	Cost      float64 `json:"cost,string"`
+	Fee       float64 `json:"fee,string"`
+	Volume    float64 `json:"vol,string"`
+	Margin    float64 `json:"margin,string"`
+}
+
+// WsOpenOrder holds a private websocket open order update
+type WsOpenOrder struct {
+	OrderID        string
+	Status         string  `json:"status"`
+	UserReference  int64   `json:"userref"`
+	OpenTime       float64 `json:"opentm,string"`
+	Volume         float64 `json:"vol,string"`
+	VolumeExecuted float64 `json:"vol_exec,string"`
+	Cost           float64 `json:"cost,string"`
+	Fee            float64 `json:"fee,string"`
+	AveragePrice   float64 `json:"avg_price,string"`
+	Description    struct {
+		Pair      string  `json:"pair"`
+		Type      string  `json:"type"`
+		OrderType string  `json:"ordertype"`
+		Price     float64 `json:"price,string"`
+		Leverage  string  `json:"leverage"`
+		Order     string  `json:"order"`
+	} `json:"descr"`
+}
+
+// wsOrderbookLevel holds a websocket orderbook price level. The raw price and
+// volume strings are retained for checksum verification
+type wsOrderbookLevel struct {
+	Price     float64
+	Amount    float64
+	PriceRaw  string
+	AmountRaw string
+}
+
+// wsOrderbook holds a local websocket orderbook for checksum verification
+type wsOrderbook struct {
+	Asks []wsOrderbookLevel
+	Bids []wsOrderbookLevel
+}
@@ -0,0 +1,652 @@
+package kraken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
+	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+const (
+	krakenWebsocketURL     = "wss://ws.kraken.com"
+	krakenWebsocketAuthURL = "wss://ws-auth.kraken.com"
+
+	krakenWsTicker     = "ticker"
+	krakenWsTrade      = "trade"
+	krakenWsBook       = "book"
+	krakenWsOwnTrades  = "ownTrades"
+	krakenWsOpenOrders = "openOrders"
+
+	krakenWsHeartbeat          = "heartbeat"
+	krakenWsSystemStatus       = "systemStatus"
+	krakenWsSubscriptionStatus = "subscriptionStatus"
+	krakenWsSubscribe          = "subscribe"
+	krakenWsUnsubscribe        = "unsubscribe"
+
+	krakenWsOrderbookDepth = 10
+	krakenWsChecksumDepth  = 10
+)
+
+// WsConnect initiates the public websocket connection and, when API
+// credentials are set, the authenticated connection for private channels
+func (k *Kraken) WsConnect() error {
+	if !k.Websocket.IsEnabled() || !k.IsEnabled() {
+		return errors.New(exchange.WebsocketNotEnabled)
+	}
+
+	k.wsMtx.Lock()
+	k.WebsocketSubdChannels = make(map[int64]WebsocketChannelData)
+	k.wsOrderbooks = make(map[string]*wsOrderbook)
+	k.wsMtx.Unlock()
+
+	var err error
+	k.WebsocketConn, err = k.wsDial(k.Websocket.GetWebsocketURL())
+	if err != nil {
+		return err
+	}
+
+	go k.WsReadData(k.WebsocketConn)
+	go k.WsHandleData()
+
+	err = k.WsSubscribe()
+	if err != nil {
+		return err
+	}
+
+	if k.AuthenticatedAPISupport {
+		err = k.wsConnectAuthenticated()
+		if err != nil {
+			k.Websocket.DataHandler <- fmt.Sprintf("kraken_websocket.go - %s private channels unavailable: %s",
+				k.Name, err)
+		}
+	}
+	return nil
+}
+
+// wsDial dials a websocket address using the configured proxy
+func (k *Kraken) wsDial(address string) (*websocket.Conn, error) {
+	var dialer websocket.Dialer
+	k.Websocket.ConfigureDialer(&dialer)
+	if k.Websocket.GetProxyAddress() != "" {
+		proxy, err := url.Parse(k.Websocket.GetProxyAddress())
+		if err != nil {
+			return nil, err
+		}
+
+		dialer.Proxy = http.ProxyURL(proxy)
+	}
+
+	conn, _, err := dialer.Dial(address, http.Header{})
+	return conn, err
+}
+
+// wsConnectAuthenticated fetches a websocket token and subscribes to the
+// private ownTrades and openOrders channels
+func (k *Kraken) wsConnectAuthenticated() error {
+	token, err := k.GetWebsocketToken()
+	if err != nil {
+		return err
+	}
+
+	k.AuthWebsocketConn, err = k.wsDial(krakenWebsocketAuthURL)
+	if err != nil {
+		return err
+	}
+
+	go k.WsReadData(k.AuthWebsocketConn)
+
+	for _, channel := range []string{krakenWsOwnTrades, krakenWsOpenOrders} {
+		err = k.wsSend(k.AuthWebsocketConn, WebsocketSubscriptionRequest{
+			Event: krakenWsSubscribe,
+			Subscription: WebsocketSubscription{
+				Name:  channel,
+				Token: token.Token,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wsSend sends a JSON request over a websocket connection
+func (k *Kraken) wsSend(conn *websocket.Conn, data interface{}) error {
+	request, err := common.JSONEncode(data)
+	if err != nil {
+		return err
+	}
+
+	if k.Verbose {
+		log.Printf("%s websocket sending: %s", k.Name, request)
+	}
+
+	k.wsWriteMtx.Lock()
+	defer k.wsWriteMtx.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, request)
+}
+
+// WsSubscribe subscribes to the ticker, trade and book channels for all
+// enabled currency pairs
+func (k *Kraken) WsSubscribe() error {
+	var pairs []string
+	for _, p := range k.GetEnabledCurrencies() {
+		pairs = append(pairs, formatWebsocketPair(p))
+	}
+
+	if len(pairs) == 0 {
+		return errors.New("kraken_websocket.go error - no enabled pairs to subscribe to")
+	}
+
+	subscriptions := []WebsocketSubscription{
+		{Name: krakenWsTicker},
+		{Name: krakenWsTrade},
+		{Name: krakenWsBook, Depth: krakenWsOrderbookDepth},
+	}
+
+	for _, subscription := range subscriptions {
+		err := k.wsSend(k.WebsocketConn, WebsocketSubscriptionRequest{
+			Event:        krakenWsSubscribe,
+			Pairs:        pairs,
+			Subscription: subscription,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wsResubscribeOrderbook requests a fresh orderbook snapshot for a pair
+func (k *Kraken) wsResubscribeOrderbook(wsPair string) error {
+	k.wsMtx.Lock()
+	delete(k.wsOrderbooks, wsPair)
+	k.wsMtx.Unlock()
+
+	for _, event := range []string{krakenWsUnsubscribe, krakenWsSubscribe} {
+		err := k.wsSend(k.WebsocketConn, WebsocketSubscriptionRequest{
+			Event: event,
+			Pairs: []string{wsPair},
+			Subscription: WebsocketSubscription{
+				Name:  krakenWsBook,
+				Depth: krakenWsOrderbookDepth,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WsReadData reads from a websocket connection
+func (k *Kraken) WsReadData(conn *websocket.Conn) {
+	k.Websocket.Wg.Add(1)
+
+	defer func() {
+		err := conn.Close()
+		if err != nil {
+			k.Websocket.DataHandler <- fmt.Errorf("kraken_websocket.go - Unable to to close Websocket connection. Error: %s",
+				err)
+		}
+		k.Websocket.Wg.Done()
+	}()
+
+	for {
+		select {
+		case <-k.Websocket.ShutdownC:
+			return
+
+		default:
+			_, resp, err := k.Websocket.ReadMessage(conn)
+			if err != nil {
+				k.Websocket.DataHandler <- err
+				return
+			}
+
+			k.Websocket.TrafficAlert <- struct{}{}
+			k.Websocket.Intercomm <- exchange.WebsocketResponse{Raw: resp}
+		}
+	}
+}
+
+// WsHandleData handles websocket data
+func (k *Kraken) WsHandleData() {
+	k.Websocket.Wg.Add(1)
+	defer k.Websocket.Wg.Done()
+
+	for {
+		select {
+		case <-k.Websocket.ShutdownC:
+			return
+
+		case resp := <-k.Websocket.Intercomm:
+			if len(resp.Raw) == 0 {
+				continue
+			}
+
+			var err error
+			if resp.Raw[0] == '{' {
+				err = k.wsHandleEvent(resp.Raw)
+			} else {
+				err = k.wsHandleChannelData(resp.Raw)
+			}
+
+			if err != nil {
+				k.Websocket.DataHandler <- fmt.Sprintf("kraken_websocket.go - %s",
+					err)
+			}
+		}
+	}
+}
+
+// wsHandleEvent processes event messages such as subscription statuses
+func (k *Kraken) wsHandleEvent(raw []byte) error {
+	var event WebsocketEventResponse
+	err := common.JSONDecode(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	switch event.Event {
+	case krakenWsHeartbeat:
+	case krakenWsSystemStatus:
+		if k.Verbose {
+			log.Printf("%s websocket system status: %s version: %s",
+				k.Name, event.Status, event.Version)
+		}
+	case krakenWsSubscriptionStatus:
+		if event.Status == "error" {
+			return fmt.Errorf("%s subscription to %s %s failed: %s",
+				k.Name, event.Subscription.Name, event.Pair, event.ErrorMessage)
+		}
+
+		if event.ChannelID == 0 {
+			return nil
+		}
+
+		k.wsMtx.Lock()
+		if event.Status == "subscribed" {
+			k.WebsocketSubdChannels[event.ChannelID] = WebsocketChannelData{
+				Subscription: event.Subscription.Name,
+				Pair:         event.Pair,
+			}
+		} else {
+			delete(k.WebsocketSubdChannels, event.ChannelID)
+		}
+		k.wsMtx.Unlock()
+	default:
+		if event.Status == "error" {
+			return fmt.Errorf("%s %s error: %s", k.Name, event.Event,
+				event.ErrorMessage)
+		}
+	}
+	return nil
+}
+
+// wsHandleChannelData processes public and private channel data arrays
+func (k *Kraken) wsHandleChannelData(raw []byte) error {
+	var data []json.RawMessage
+	err := common.JSONDecode(raw, &data)
+	if err != nil {
+		return err
+	}
+
+	if len(data) < 2 {
+		return fmt.Errorf("unhandled websocket message %s", raw)
+	}
+
+	// Private channel messages are formatted [data, channelName, sequence]
+	if data[0][0] == '[' {
+		var channelName string
+		err = common.JSONDecode(data[1], &channelName)
+		if err != nil {
+			return err
+		}
+
+		switch channelName {
+		case krakenWsOwnTrades:
+			return k.wsProcessOwnTrades(data[0])
+		case krakenWsOpenOrders:
+			return k.wsProcessOpenOrders(data[0])
+		}
+		return fmt.Errorf("unhandled private channel %s", channelName)
+	}
+
+	// Public channel messages are formatted [channelID, data..., channelName,
+	// pair]
+	if len(data) < 4 {
+		return fmt.Errorf("unhandled websocket message %s", raw)
+	}
+
+	var channelID int64
+	err = common.JSONDecode(data[0], &channelID)
+	if err != nil {
+		return err
+	}
+
+	k.wsMtx.Lock()
+	channel, ok := k.WebsocketSubdChannels[channelID]
+	k.wsMtx.Unlock()
+	if !ok {
+		return fmt.Errorf("received data for unknown channel ID %d", channelID)
+	}
+
+	payload := data[1 : len(data)-2]
+	switch channel.Subscription {
+	case krakenWsTicker:
+		return k.wsProcessTicker(channel.Pair, payload[0])
+	case krakenWsTrade:
+		return k.wsProcessTrades(channel.Pair, payload[0])
+	case krakenWsBook:
+		return k.wsProcessOrderbook(channel.Pair, payload)
+	}
+	return fmt.Errorf("unhandled channel %s", channel.Subscription)
+}
+
+// wsProcessTicker sends ticker data to the websocket data handler
+func (k *Kraken) wsProcessTicker(wsPair string, raw json.RawMessage) error {
+	var t WsTicker
+	err := common.JSONDecode(raw, &t)
+	if err != nil {
+		return err
+	}
+
+	if len(t.Close) == 0 || len(t.Volume) < 2 || len(t.Open) == 0 ||
+		len(t.High) < 2 || len(t.Low) < 2 {
+		return fmt.Errorf("incomplete ticker data for %s", wsPair)
+	}
+
+	k.Websocket.DataHandler <- exchange.TickerData{
+		Timestamp:  time.Now(),
+		Pair:       parseWebsocketPair(wsPair),
+		AssetType:  ticker.Spot,
+		Exchange:   k.GetName(),
+		ClosePrice: parseWebsocketFloat(t.Close[0]),
+		Quantity:   parseWebsocketFloat(t.Volume[1]),
+		OpenPrice:  parseWebsocketFloat(t.Open[0]),
+		HighPrice:  parseWebsocketFloat(t.High[1]),
+		LowPrice:   parseWebsocketFloat(t.Low[1]),
+	}
+	return nil
+}
+
+// wsProcessTrades sends trade data to the websocket data handler. Each trade
+// is formatted [price, volume, time, side, orderType, misc]
+func (k *Kraken) wsProcessTrades(wsPair string, raw json.RawMessage) error {
+	var trades [][]string
+	err := common.JSONDecode(raw, &trades)
+	if err != nil {
+		return err
+	}
+
+	p := parseWebsocketPair(wsPair)
+	for x := range trades {
+		if len(trades[x]) < 5 {
+			continue
+		}
+
+		side := string(exchange.OrderSideBuy())
+		if trades[x][3] == "s" {
+			side = string(exchange.OrderSideSell())
+		}
+
+		orderType := string(exchange.OrderTypeLimit())
+		if trades[x][4] == "m" {
+			orderType = string(exchange.OrderTypeMarket())
+		}
+
+		k.Websocket.DataHandler <- exchange.TradeData{
+			Timestamp:    parseWebsocketTime(trades[x][2]),
+			CurrencyPair: p,
+			AssetType:    ticker.Spot,
+			Exchange:     k.GetName(),
+			EventType:    orderType,
+			Price:        parseWebsocketFloat(trades[x][0]),
+			Amount:       parseWebsocketFloat(trades[x][1]),
+			Side:         side,
+		}
+	}
+	return nil
+}
+
+// wsProcessOrderbook applies an orderbook snapshot or update, verifies the
+// checksum and updates the orderbook store
+func (k *Kraken) wsProcessOrderbook(wsPair string, payload []json.RawMessage) error {
+	var snapshot, checksum bool
+	var asks, bids [][]string
+	var expected string
+	for x := range payload {
+		var book map[string]json.RawMessage
+		err := common.JSONDecode(payload[x], &book)
+		if err != nil {
+			return err
+		}
+
+		for key, value := range book {
+			switch key {
+			case "as", "bs":
+				snapshot = true
+				fallthrough
+			case "a", "b":
+				var levels [][]string
+				err = common.JSONDecode(value, &levels)
+				if err != nil {
+					return err
+				}
+				if key[0] == 'a' {
+					asks = append(asks, levels...)
+				} else {
+					bids = append(bids, levels...)
+				}
+			case "c":
+				checksum = true
+				err = common.JSONDecode(value, &expected)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	k.wsMtx.Lock()
+	ob, ok := k.wsOrderbooks[wsPair]
+	if snapshot || !ok {
+		if !snapshot {
+			k.wsMtx.Unlock()
+			return fmt.Errorf("orderbook update received for %s before snapshot",
+				wsPair)
+		}
+		ob = &wsOrderbook{}
+		k.wsOrderbooks[wsPair] = ob
+	}
+
+	ob.Asks = applyWebsocketLevels(ob.Asks, asks, true)
+	ob.Bids = applyWebsocketLevels(ob.Bids, bids, false)
+
+	if checksum && calculateWebsocketChecksum(ob) != expected {
+		k.wsMtx.Unlock()
+		err := k.wsResubscribeOrderbook(wsPair)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%s orderbook checksum mismatch, resubscribing",
+			wsPair)
+	}
+
+	base := orderbook.Base{
+		Asks:      convertWebsocketLevels(ob.Asks),
+		Bids:      convertWebsocketLevels(ob.Bids),
+		AssetType: ticker.Spot,
+	}
+	k.wsMtx.Unlock()
+
+	p := parseWebsocketPair(wsPair)
+	orderbook.ProcessOrderbook(k.GetName(), p, base, ticker.Spot)
+
+	k.Websocket.DataHandler <- exchange.WebsocketOrderbookUpdate{
+		Exchange: k.GetName(),
+		Asset:    ticker.Spot,
+		Pair:     p,
+	}
+	return nil
+}
+
+// wsProcessOwnTrades sends private trade executions to the websocket data
+// handler
+func (k *Kraken) wsProcessOwnTrades(raw json.RawMessage) error {
+	var trades []map[string]WsOwnTrade
+	err := common.JSONDecode(raw, &trades)
+	if err != nil {
+		return err
+	}
+
+	for x := range trades {
+		for id, trade := range trades[x] {
+			trade.TradeID = id
+			k.Websocket.DataHandler <- trade
+		}
+	}
+	return nil
+}
+
+// wsProcessOpenOrders sends private open order updates to the websocket data
+// handler
+func (k *Kraken) wsProcessOpenOrders(raw json.RawMessage) error {
+	var orders []map[string]WsOpenOrder
+	err := common.JSONDecode(raw, &orders)
+	if err != nil {
+		return err
+	}
+
+	for x := range orders {
+		for id, order := range orders[x] {
+			order.OrderID = id
+			k.Websocket.DataHandler <- order
+		}
+	}
+	return nil
+}
+
+// applyWebsocketLevels applies price level changes to one side of a local
+// orderbook, keeping it sorted and truncated to the subscribed depth. A zero
+// volume removes the price level
+func applyWebsocketLevels(book []wsOrderbookLevel, changes [][]string, ascending bool) []wsOrderbookLevel {
+	for x := range changes {
+		if len(changes[x]) < 2 {
+			continue
+		}
+
+		level := wsOrderbookLevel{
+			Price:     parseWebsocketFloat(changes[x][0]),
+			Amount:    parseWebsocketFloat(changes[x][1]),
+			PriceRaw:  changes[x][0],
+			AmountRaw: changes[x][1],
+		}
+
+		found := false
+		for y := range book {
+			if book[y].Price == level.Price {
+				if level.Amount == 0 {
+					book = append(book[:y], book[y+1:]...)
+				} else {
+					book[y] = level
+				}
+				found = true
+				break
+			}
+		}
+
+		if !found && level.Amount != 0 {
+			book = append(book, level)
+		}
+	}
+
+	sort.Slice(book, func(i, j int) bool {
+		if ascending {
+			return book[i].Price < book[j].Price
+		}
+		return book[i].Price > book[j].Price
+	})
+
+	if len(book) > krakenWsOrderbookDepth {
+		book = book[:krakenWsOrderbookDepth]
+	}
+	return book
+}
+
+// calculateWebsocketChecksum returns the CRC32 checksum of the top ten asks
+// and bids, built from each level's price and volume with the decimal point
+// and leading zeros removed
+func calculateWebsocketChecksum(ob *wsOrderbook) string {
+	var b strings.Builder
+	for _, side := range [][]wsOrderbookLevel{ob.Asks, ob.Bids} {
+		for x := 0; x < len(side) && x < krakenWsChecksumDepth; x++ {
+			b.WriteString(trimChecksumValue(side[x].PriceRaw))
+			b.WriteString(trimChecksumValue(side[x].AmountRaw))
+		}
+	}
+	return strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(b.String()))), 10)
+}
+
+// trimChecksumValue removes the decimal point and leading zeros from a value
+func trimChecksumValue(value string) string {
+	return strings.TrimLeft(strings.Replace(value, ".", "", 1), "0")
+}
+
+// convertWebsocketLevels converts local orderbook levels to orderbook items
+func convertWebsocketLevels(levels []wsOrderbookLevel) []orderbook.Item {
+	items := make([]orderbook.Item, 0, len(levels))
+	for x := range levels {
+		items = append(items, orderbook.Item{
+			Price:  levels[x].Price,
+			Amount: levels[x].Amount,
+		})
+	}
+	return items
+}
+
+// formatWebsocketPair converts a currency pair into the websocket format
+// e.g. XBT/USD
+func formatWebsocketPair(p pair.CurrencyPair) string {
+	p = translation.PairToExchange("Kraken", p)
+	return p.FirstCurrency.Upper().String() + "/" + p.SecondCurrency.Upper().String()
+}
+
+// parseWebsocketPair converts a websocket pair into the config pair format
+// with canonical currency codes
+func parseWebsocketPair(wsPair string) pair.CurrencyPair {
+	return translation.PairToCanonical("Kraken",
+		pair.NewCurrencyPairDelimiter(strings.Replace(wsPair, "/", "-", 1), "-"))
+}
+
+// parseWebsocketFloat parses a websocket string value, returning zero on
+// failure
+func parseWebsocketFloat(value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// parseWebsocketTime parses a websocket seconds.microseconds timestamp
+func parseWebsocketTime(value string) time.Time {
+	seconds := parseWebsocketFloat(value)
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
@@ -1,33 +1,47 @@
 package kraken
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Kraken exchange
+func init() {
+	exchange.Register("Kraken", func() exchange.IBotExchange { return new(Kraken) })
+}
+
 // Start starts the Kraken go routine
-func (k *Kraken) Start(wg *sync.WaitGroup) {
+func (k *Kraken) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		k.Run()
+		k.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the Kraken wrapper
-func (k *Kraken) Run() {
+func (k *Kraken) Run(ctx context.Context) {
 	if k.Verbose {
+		log.Printf("%s Websocket: %s (url: %s).\n", k.GetName(), common.IsEnabled(k.Websocket.IsEnabled()), krakenWebsocketURL)
 		log.Printf("%s polling delay: %ds.\n", k.GetName(), k.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", k.GetName(), len(k.EnabledPairs), k.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	assetPairs, err := k.GetAssetPairs()
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", k.GetName())
@@ -69,6 +83,11 @@ func (k *Kraken) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (k *Kraken) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (k *Kraken) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
@@ -83,8 +102,9 @@ func (k *Kraken) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pri
 	}
 
 	for _, x := range pairs {
+		exchangePair := translation.PairToExchange(k.Name, x)
 		for y, z := range tickers {
-			if common.StringContains(y, x.FirstCurrency.Upper().String()) && common.StringContains(y, x.SecondCurrency.Upper().String()) {
+			if common.StringContains(y, exchangePair.FirstCurrency.Upper().String()) && common.StringContains(y, exchangePair.SecondCurrency.Upper().String()) {
 				var tp ticker.Price
 				tp.Pair = x
 				tp.Last = z.Last
@@ -134,15 +154,25 @@ func (k *Kraken) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbo
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: orderbookNew.Asks[x].Amount, Price: orderbookNew.Asks[x].Price})
 	}
 
-	orderbook.ProcessOrderbook(k.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(k.GetName(), p, k.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(k.Name, p, assetType)
 }
 
 // GetExchangeAccountInfo retrieves balances for all enabled currencies for the
-// Kraken exchange - to-do
+// Kraken exchange
 func (k *Kraken) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	var response exchange.AccountInfo
 	response.ExchangeName = k.GetName()
+	balances, err := k.GetBalance()
+	if err != nil {
+		return response, err
+	}
+
+	// Kraken balances are totals without the amount held by open orders
+	for x, y := range balances {
+		response.Currencies = append(response.Currencies, exchange.NewAccountCurrencyInfo(
+			translation.ToCanonical(k.Name, pair.CurrencyItem(x)).String(), y, 0))
+	}
 	return response, nil
 }
 
@@ -153,6 +183,18 @@ func (k *Kraken) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (k *Kraken) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (k *Kraken) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (k *Kraken) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -160,6 +202,12 @@ func (k *Kraken) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]ex
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (k *Kraken) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (k *Kraken) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -176,6 +224,18 @@ func (k *Kraken) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (k *Kraken) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, k.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (k *Kraken) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, k.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (k *Kraken) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -187,6 +247,11 @@ func (k *Kraken) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, erro
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (k *Kraken) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (k *Kraken) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -204,15 +269,29 @@ func (k *Kraken) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount fl
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (k *Kraken) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (k *Kraken) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (k *Kraken) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
 // GetWebsocket returns a pointer to the exchange websocket
 func (k *Kraken) GetWebsocket() (*exchange.Websocket, error) {
-	return nil, errors.New("not yet implemented")
+	return k.Websocket, nil
+}
+
+// GetExchangeServerTime returns the current exchange server time
+func (k *Kraken) GetExchangeServerTime() (time.Time, error) {
+	resp, err := k.GetServerTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(resp.Unixtime, 0), nil
 }
 
 // GetFeeByType returns an estimate of fee based on type of transaction
@@ -67,7 +67,7 @@ func (l *LakeBTC) SetDefaults() {
 }
 
 // Setup sets exchange configuration profile
-func (l *LakeBTC) Setup(exch config.ExchangeConfig) {
+func (l *LakeBTC) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		l.SetEnabled(false)
 	} else {
@@ -83,25 +83,34 @@ func (l *LakeBTC) Setup(exch config.ExchangeConfig) {
 		l.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := l.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = l.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = l.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = l.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = l.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = l.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = l.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetTradablePairs returns a list of available pairs from the exchange
@@ -317,7 +326,7 @@ func (l *LakeBTC) SendAuthenticatedHTTPRequest(method, params string, result int
 	}
 
 	if l.Nonce.Get() == 0 {
-		l.Nonce.Set(time.Now().UnixNano())
+		l.Nonce.Set(l.GetAdjustedTime().UnixNano())
 	} else {
 		l.Nonce.Inc()
 	}
@@ -245,3 +245,21 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestConvertAccountInfo(t *testing.T) {
+	t.Parallel()
+
+	currencies := convertAccountInfo(AccountInfo{
+		Balance: map[string]string{"btc": "1", "usd": "100"},
+		Locked:  map[string]string{"btc": "0.5"},
+	})
+	if len(currencies) != 2 {
+		t.Fatalf("Test Failed - convertAccountInfo() expected 2 currencies, got %d", len(currencies))
+	}
+	for _, c := range currencies {
+		if c.CurrencyName == symbol.BTC && (c.Free != 1 || c.Locked != 0.5 || c.Total != 1.5) ||
+			c.CurrencyName == symbol.USD && (c.Free != 100 || c.Locked != 0 || c.Total != 100) {
+			t.Errorf("Test Failed - convertAccountInfo() unexpected result %+v", c)
+		}
+	}
+}
@@ -1,34 +1,46 @@
 package lakebtc
 
 import (
+	"context"
 	"errors"
 	"log"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the LakeBTC exchange
+func init() {
+	exchange.Register("LakeBTC", func() exchange.IBotExchange { return new(LakeBTC) })
+}
+
 // Start starts the LakeBTC go routine
-func (l *LakeBTC) Start(wg *sync.WaitGroup) {
+func (l *LakeBTC) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		l.Run()
+		l.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the LakeBTC wrapper
-func (l *LakeBTC) Run() {
+func (l *LakeBTC) Run(ctx context.Context) {
 	if l.Verbose {
 		log.Printf("%s polling delay: %ds.\n", l.GetName(), l.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", l.GetName(), len(l.EnabledPairs), l.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeProducts, err := l.GetTradablePairs()
 	if err != nil {
 		log.Printf("%s Failed to get available products.\n", l.GetName())
@@ -40,6 +52,11 @@ func (l *LakeBTC) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (l *LakeBTC) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (l *LakeBTC) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	tick, err := l.GetTicker()
@@ -83,7 +100,7 @@ func (l *LakeBTC) GetOrderbookEx(p pair.CurrencyPair, assetType string) (orderbo
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (l *LakeBTC) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
-	orderbookNew, err := l.GetOrderBook(p.Pair().String())
+	orderbookNew, err := l.GetOrderBook(l.FormatPair(p, exchange.PairFormatRequest))
 	if err != nil {
 		return orderBook, err
 	}
@@ -96,7 +113,7 @@ func (l *LakeBTC) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderb
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: orderbookNew.Asks[x].Amount, Price: orderbookNew.Asks[x].Price})
 	}
 
-	orderbook.ProcessOrderbook(l.GetName(), p, orderBook, assetType)
+	orderbook.ProcessOrderbook(l.GetName(), p, l.TruncateOrderbook(p, orderBook), assetType)
 	return orderbook.GetOrderbook(l.Name, p, assetType)
 }
 
@@ -110,20 +127,23 @@ func (l *LakeBTC) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 		return response, err
 	}
 
-	for x, y := range accountInfo.Balance {
-		for z, w := range accountInfo.Locked {
-			if z == x {
-				var exchangeCurrency exchange.AccountCurrencyInfo
-				exchangeCurrency.CurrencyName = common.StringToUpper(x)
-				exchangeCurrency.TotalValue, _ = strconv.ParseFloat(y, 64)
-				exchangeCurrency.Hold, _ = strconv.ParseFloat(w, 64)
-				response.Currencies = append(response.Currencies, exchangeCurrency)
-			}
-		}
-	}
+	response.Currencies = convertAccountInfo(accountInfo)
 	return response, nil
 }
 
+// convertAccountInfo converts the available and locked balances into account
+// currency info
+func convertAccountInfo(info AccountInfo) []exchange.AccountCurrencyInfo {
+	var currencies []exchange.AccountCurrencyInfo
+	for x, y := range info.Balance {
+		free, _ := strconv.ParseFloat(y, 64)
+		locked, _ := strconv.ParseFloat(info.Locked[x], 64)
+		currencies = append(currencies,
+			exchange.NewAccountCurrencyInfo(common.StringToUpper(x), free, locked))
+	}
+	return currencies
+}
+
 // GetExchangeFundTransferHistory returns funding history, deposits and
 // withdrawals
 func (l *LakeBTC) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error) {
@@ -131,6 +151,18 @@ func (l *LakeBTC) GetExchangeFundTransferHistory() ([]exchange.FundHistory, erro
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (l *LakeBTC) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (l *LakeBTC) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (l *LakeBTC) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -138,6 +170,12 @@ func (l *LakeBTC) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]e
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (l *LakeBTC) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (l *LakeBTC) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -154,6 +192,18 @@ func (l *LakeBTC) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (l *LakeBTC) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, l.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (l *LakeBTC) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, l.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (l *LakeBTC) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -165,6 +215,11 @@ func (l *LakeBTC) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, err
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (l *LakeBTC) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (l *LakeBTC) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -182,9 +237,14 @@ func (l *LakeBTC) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount f
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (l *LakeBTC) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (l *LakeBTC) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (l *LakeBTC) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -193,6 +253,11 @@ func (l *LakeBTC) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, errors.New("not yet implemented")
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (l *LakeBTC) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (l *LakeBTC) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return l.GetFee(feeBuilder)
@@ -69,10 +69,11 @@ func (l *Liqui) SetDefaults() {
 	l.APIUrlSecondaryDefault = liquiAPIPrivateURL
 	l.APIUrlSecondary = l.APIUrlSecondaryDefault
 	l.WebsocketInit()
+	l.SetAPIPermissionsFunc(l.GetAPIKeyPermissions)
 }
 
 // Setup sets exchange configuration parameters for liqui
-func (l *Liqui) Setup(exch config.ExchangeConfig) {
+func (l *Liqui) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		l.SetEnabled(false)
 	} else {
@@ -88,25 +89,34 @@ func (l *Liqui) Setup(exch config.ExchangeConfig) {
 		l.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := l.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = l.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = l.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = l.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = l.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = l.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = l.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetAvailablePairs returns all available pairs
@@ -193,6 +203,27 @@ func (l *Liqui) GetAccountInfo() (AccountInfo, error) {
 		l.SendAuthenticatedHTTPRequest(liquiAccountInfo, url.Values{}, &result)
 }
 
+// GetAPIKeyPermissions returns the permission scopes of the API key from its
+// account privileges
+func (l *Liqui) GetAPIKeyPermissions() ([]string, error) {
+	result, err := l.GetAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	var scopes []string
+	if result.Rights.Info {
+		scopes = append(scopes, exchange.APIPermissionRead)
+	}
+	if result.Rights.Trade {
+		scopes = append(scopes, exchange.APIPermissionTrade)
+	}
+	if result.Rights.Withdraw {
+		scopes = append(scopes, exchange.APIPermissionWithdraw)
+	}
+	return scopes, nil
+}
+
 // Trade creates orders on the exchange.
 // to-do: convert orderid to int64
 func (l *Liqui) Trade(pair, orderType string, amount, price float64) (float64, error) {
@@ -278,7 +309,7 @@ func (l *Liqui) SendAuthenticatedHTTPRequest(method string, values url.Values, r
 	}
 
 	if l.Nonce.Get() == 0 {
-		l.Nonce.Set(time.Now().Unix())
+		l.Nonce.Set(l.GetAdjustedTime().Unix())
 	} else {
 		l.Nonce.Inc()
 	}
@@ -134,7 +134,7 @@ func (l *LocalBitcoins) SetDefaults() {
 }
 
 // Setup sets exchange configuration parameters
-func (l *LocalBitcoins) Setup(exch config.ExchangeConfig) {
+func (l *LocalBitcoins) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		l.SetEnabled(false)
 	} else {
@@ -150,21 +150,30 @@ func (l *LocalBitcoins) Setup(exch config.ExchangeConfig) {
 		l.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := l.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = l.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = l.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = l.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = l.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = l.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetAccountInfo lets you retrieve the public user information on a
@@ -703,7 +712,7 @@ func (l *LocalBitcoins) SendAuthenticatedHTTPRequest(method, path string, params
 	}
 
 	if l.Nonce.Get() == 0 {
-		l.Nonce.Set(time.Now().UnixNano())
+		l.Nonce.Set(l.GetAdjustedTime().UnixNano())
 	} else {
 		l.Nonce.Inc()
 	}
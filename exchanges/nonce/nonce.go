@@ -11,9 +11,41 @@ type Nonce struct {
 	// Standard nonce
 	n   int64
 	mtx sync.Mutex
+	// Set when the clock skew correction changes so the standard nonce is
+	// reseeded
+	reseed bool
 	// Hash table exclusive exchange specific nonce values
-	boundedCall map[string]int64
-	boundedMtx  sync.Mutex
+	boundedCall   map[string]int64
+	boundedReseed map[string]bool
+	boundedMtx    sync.Mutex
+	// Clock skew correction applied when seeding time based nonce values
+	offset time.Duration
+}
+
+// SetOffset sets a clock skew correction which is applied when time based
+// nonce values are seeded. A changed correction reseeds nonce values already
+// seeded: Get returns zero until the standard nonce is Set again and exchange
+// specific values are seeded again on their next GetValue call. Reseeded
+// values never fall below the values already issued
+func (n *Nonce) SetOffset(offset time.Duration) {
+	n.boundedMtx.Lock()
+	changed := offset != n.offset
+	n.offset = offset
+	if changed {
+		for exchName := range n.boundedCall {
+			if n.boundedReseed == nil {
+				n.boundedReseed = make(map[string]bool)
+			}
+			n.boundedReseed[exchName] = true
+		}
+	}
+	n.boundedMtx.Unlock()
+
+	if changed {
+		n.mtx.Lock()
+		n.reseed = n.n != 0
+		n.mtx.Unlock()
+	}
 }
 
 // Inc increments the nonce value
@@ -23,10 +55,13 @@ func (n *Nonce) Inc() {
 	n.mtx.Unlock()
 }
 
-// Get retrives the nonce value
+// Get retrives the nonce value, zero when it needs seeding
 func (n *Nonce) Get() int64 {
 	n.mtx.Lock()
 	defer n.mtx.Unlock()
+	if n.reseed {
+		return 0
+	}
 	return n.n
 }
 
@@ -35,13 +70,19 @@ func (n *Nonce) GetInc() int64 {
 	n.mtx.Lock()
 	defer n.mtx.Unlock()
 	n.n++
+	n.reseed = false
 	return n.n
 }
 
-// Set sets the nonce value
+// Set sets the nonce value. When reseeding after the clock skew correction
+// changed, a value below the last nonce issued continues from it instead
 func (n *Nonce) Set(val int64) {
 	n.mtx.Lock()
+	if n.reseed && val <= n.n {
+		val = n.n + 1
+	}
 	n.n = val
+	n.reseed = false
 	n.mtx.Unlock()
 }
 
@@ -66,12 +107,18 @@ func (n *Nonce) GetValue(exchName string, nanoPrecision bool) Value {
 		n.boundedCall = make(map[string]int64)
 	}
 
-	if n.boundedCall[exchName] == 0 {
+	if n.boundedCall[exchName] == 0 || n.boundedReseed[exchName] {
+		delete(n.boundedReseed, exchName)
+		last := n.boundedCall[exchName]
+		now := time.Now().Add(n.offset)
 		if nanoPrecision {
-			n.boundedCall[exchName] = time.Now().UnixNano()
-			return Value(n.boundedCall[exchName])
+			n.boundedCall[exchName] = now.UnixNano()
+		} else {
+			n.boundedCall[exchName] = now.Unix()
+		}
+		if n.boundedCall[exchName] <= last {
+			n.boundedCall[exchName] = last + 1
 		}
-		n.boundedCall[exchName] = time.Now().Unix()
 		return Value(n.boundedCall[exchName])
 	}
 	n.boundedCall[exchName]++
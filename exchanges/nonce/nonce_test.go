@@ -99,3 +99,53 @@ func TestNonceConcurrency(t *testing.T) {
 		t.Errorf("Test failed. Expected %d got %d", expected, result)
 	}
 }
+
+func TestSetOffset(t *testing.T) {
+	var nonce Nonce
+	nonce.SetOffset(time.Hour)
+	n := nonce.GetValue("offset", false)
+	expected := time.Now().Add(time.Hour).Unix()
+	if int64(n) < expected-1 || int64(n) > expected {
+		t.Errorf("Test failed. Expected %d got %d", expected, n)
+	}
+}
+
+func TestSetOffsetReseed(t *testing.T) {
+	var nonce Nonce
+	nonce.Set(time.Now().Unix())
+	seeded := nonce.Get()
+
+	nonce.SetOffset(time.Hour)
+	if nonce.Get() != 0 {
+		t.Fatal("Test failed. Expected the nonce to need reseeding after the offset changed")
+	}
+	nonce.Set(time.Now().Add(time.Hour).Unix())
+	if result := nonce.Get(); result < seeded+3599 {
+		t.Errorf("Test failed. Expected the nonce reseeded with the offset, got %d", result)
+	}
+
+	// A correction moving the clock back never reissues lower nonce values
+	last := nonce.Get()
+	nonce.SetOffset(0)
+	nonce.Set(time.Now().Unix())
+	if result := nonce.Get(); result != last+1 {
+		t.Errorf("Test failed. Expected %d got %d", last+1, result)
+	}
+
+	// An unchanged offset leaves the nonce seeded
+	nonce.SetOffset(0)
+	if nonce.Get() == 0 {
+		t.Error("Test failed. Expected the nonce to remain seeded")
+	}
+
+	v := nonce.GetValue("reseed", false)
+	nonce.SetOffset(time.Hour)
+	expected := time.Now().Add(time.Hour).Unix()
+	if n := nonce.GetValue("reseed", false); int64(n) < expected-1 || int64(n) <= int64(v) {
+		t.Errorf("Test failed. Expected %d got %d", expected, n)
+	}
+	nonce.SetOffset(0)
+	if n := nonce.GetValue("reseed", false); int64(n) != expected+1 && int64(n) != expected {
+		t.Errorf("Test failed. Expected values to continue from %d got %d", expected, n)
+	}
+}
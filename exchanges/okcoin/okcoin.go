@@ -106,7 +106,7 @@ func (o *OKCoin) SetDefaults() {
 }
 
 // Setup sets exchange configuration parameters
-func (o *OKCoin) Setup(exch config.ExchangeConfig) {
+func (o *OKCoin) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		o.SetEnabled(false)
 	} else {
@@ -154,23 +154,31 @@ func (o *OKCoin) Setup(exch config.ExchangeConfig) {
 		o.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := o.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = o.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = o.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = o.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = o.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = o.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = o.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = o.WebsocketSetup(o.WsConnect,
 			exch.Name,
@@ -178,9 +186,10 @@ func (o *OKCoin) Setup(exch config.ExchangeConfig) {
 			okcoinWebsocketURL,
 			o.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 	}
+	return nil
 }
 
 // GetTicker returns the current ticker
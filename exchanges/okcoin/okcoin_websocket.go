@@ -52,6 +52,7 @@ func (o *OKCoin) WsConnect() error {
 		"2hour", "4hour", "6hour", "12hour", "day", "3day", "week"}
 
 	var dialer websocket.Dialer
+	o.Websocket.ConfigureDialer(&dialer)
 
 	if o.Websocket.GetProxyAddress() != "" {
 		proxy, err := url.Parse(o.Websocket.GetProxyAddress())
@@ -105,7 +106,7 @@ func (o *OKCoin) WsReadData() {
 			return
 
 		default:
-			_, resp, err := o.WebsocketConn.ReadMessage()
+			_, resp, err := o.Websocket.ReadMessage(o.WebsocketConn)
 			if err != nil {
 				o.Websocket.DataHandler <- err
 				return
@@ -1,34 +1,47 @@
 package okcoin
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the OKCoin exchange
+func init() {
+	exchange.Register("OKCOIN China", func() exchange.IBotExchange { return new(OKCoin) })
+	exchange.Register("OKCOIN International", func() exchange.IBotExchange { return new(OKCoin) })
+}
+
 // Start starts the OKCoin go routine
-func (o *OKCoin) Start(wg *sync.WaitGroup) {
+func (o *OKCoin) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		o.Run()
+		o.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the OKCoin wrapper
-func (o *OKCoin) Run() {
+func (o *OKCoin) Run(ctx context.Context) {
 	if o.Verbose {
 		log.Printf("%s Websocket: %s. (url: %s).\n", o.GetName(), common.IsEnabled(o.Websocket.IsEnabled()), o.WebsocketURL)
 		log.Printf("%s polling delay: %ds.\n", o.GetName(), o.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", o.GetName(), len(o.EnabledPairs), o.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	if o.APIUrl == okcoinAPIURL {
 		// OKCoin International
 		forceUpgrade := false
@@ -38,7 +51,7 @@ func (o *OKCoin) Run() {
 
 		var currencies []string
 		for x := range o.AvailablePairs {
-			currencies = append(currencies, o.AvailablePairs[x][0:3]+"_"+o.AvailablePairs[x][3:])
+			currencies = append(currencies, pair.NewCurrencyPairFromString(o.AvailablePairs[x]).Display("_", false).String())
 		}
 
 		if forceUpgrade {
@@ -58,6 +71,11 @@ func (o *OKCoin) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (o *OKCoin) GetListedPairs() ([]string, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (o *OKCoin) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	currency := exchange.FormatExchangeCurrency(o.Name, p).String()
@@ -130,7 +148,7 @@ func (o *OKCoin) UpdateOrderbook(currency pair.CurrencyPair, assetType string) (
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: data[1], Price: data[0]})
 	}
 
-	orderbook.ProcessOrderbook(o.GetName(), currency, orderBook, assetType)
+	orderbook.ProcessOrderbook(o.GetName(), currency, o.TruncateOrderbook(currency, orderBook), assetType)
 	return orderbook.GetOrderbook(o.Name, currency, assetType)
 }
 
@@ -144,30 +162,13 @@ func (o *OKCoin) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 		return response, err
 	}
 
-	response.Currencies = append(response.Currencies, exchange.AccountCurrencyInfo{
-		CurrencyName: "BTC",
-		TotalValue:   assets.Info.Funds.Free.BTC,
-		Hold:         assets.Info.Funds.Freezed.BTC,
-	})
-
-	response.Currencies = append(response.Currencies, exchange.AccountCurrencyInfo{
-		CurrencyName: "LTC",
-		TotalValue:   assets.Info.Funds.Free.LTC,
-		Hold:         assets.Info.Funds.Freezed.LTC,
-	})
-
-	response.Currencies = append(response.Currencies, exchange.AccountCurrencyInfo{
-		CurrencyName: "USD",
-		TotalValue:   assets.Info.Funds.Free.USD,
-		Hold:         assets.Info.Funds.Freezed.USD,
-	})
-
-	response.Currencies = append(response.Currencies, exchange.AccountCurrencyInfo{
-		CurrencyName: "CNY",
-		TotalValue:   assets.Info.Funds.Free.CNY,
-		Hold:         assets.Info.Funds.Freezed.CNY,
-	})
-
+	funds := assets.Info.Funds
+	response.Currencies = []exchange.AccountCurrencyInfo{
+		exchange.NewAccountCurrencyInfo("BTC", funds.Free.BTC, funds.Freezed.BTC),
+		exchange.NewAccountCurrencyInfo("LTC", funds.Free.LTC, funds.Freezed.LTC),
+		exchange.NewAccountCurrencyInfo("USD", funds.Free.USD, funds.Freezed.USD),
+		exchange.NewAccountCurrencyInfo("CNY", funds.Free.CNY, funds.Freezed.CNY),
+	}
 	return response, nil
 }
 
@@ -178,6 +179,18 @@ func (o *OKCoin) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error
 	return fundHistory, errors.New("not supported on exchange")
 }
 
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (o *OKCoin) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (o *OKCoin) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
 func (o *OKCoin) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
@@ -185,6 +198,12 @@ func (o *OKCoin) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]ex
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (o *OKCoin) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (o *OKCoin) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -201,6 +220,18 @@ func (o *OKCoin) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (o *OKCoin) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, o.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (o *OKCoin) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, o.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (o *OKCoin) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -212,6 +243,11 @@ func (o *OKCoin) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, erro
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (o *OKCoin) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (o *OKCoin) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -229,9 +265,14 @@ func (o *OKCoin) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount fl
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (o *OKCoin) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (o *OKCoin) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (o *OKCoin) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -240,6 +281,11 @@ func (o *OKCoin) GetWebsocket() (*exchange.Websocket, error) {
 	return o.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (o *OKCoin) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (o *OKCoin) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return o.GetFee(feeBuilder)
@@ -68,6 +68,7 @@ const (
 	spotCancelWithdraw = "cancel_withdraw"
 	spotWithdrawInfo   = "withdraw_info"
 	spotAccountRecords = "account_records"
+	spotFundsTransfer  = "funds_transfer"
 
 	// just your average return type from okex
 	returnTypeOne = "map[string]interface {}"
@@ -78,6 +79,14 @@ const (
 
 var errMissValue = errors.New("warning - resp value is missing from exchange")
 
+// okexWalletTypes maps wallet types to the OKEX wallet IDs used by funds
+// transfers
+var okexWalletTypes = map[string]int{
+	exchange.WalletExchange: 1,
+	exchange.WalletFutures:  3,
+	exchange.WalletFunding:  6,
+}
+
 // OKEX is the overaching type across the OKEX methods
 type OKEX struct {
 	exchange.Base
@@ -120,7 +129,7 @@ func (o *OKEX) SetDefaults() {
 }
 
 // Setup method sets current configuration details if enabled
-func (o *OKEX) Setup(exch config.ExchangeConfig) {
+func (o *OKEX) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		o.SetEnabled(false)
 	} else {
@@ -137,23 +146,31 @@ func (o *OKEX) Setup(exch config.ExchangeConfig) {
 		o.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := o.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = o.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = o.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = o.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = o.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = o.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = o.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = o.WebsocketSetup(o.WsConnect,
 			exch.Name,
@@ -161,9 +178,24 @@ func (o *OKEX) Setup(exch config.ExchangeConfig) {
 			okexDefaultWebsocketURL,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = o.WebsocketCompressionSetup(exch.WebsocketCompression,
+			exchange.WebsocketCompressionDeflate)
+		if err != nil {
+			return err
+		}
+		err = o.WebsocketKeepaliveSetup(exch.WebsocketKeepalive,
+			exchange.WebsocketKeepalive{
+				Method:   exchange.WebsocketKeepaliveText,
+				Interval: okexWebsocketPingDelay,
+				Payload:  okexWebsocketPing,
+			})
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetContractPrice returns current contract prices
@@ -694,6 +726,33 @@ func (o *OKEX) SpotCancelOrder(symbol string, argOrderID int64) (int64, error) {
 	return returnOrderID, nil
 }
 
+// FundsTransfer moves funds between the spot, futures and funding wallets
+// symbol such as btc_usd
+// from and to are wallet IDs, 1 spot, 3 futures and 6 funding
+func (o *OKEX) FundsTransfer(symbol string, amount float64, from, to int) error {
+	type response struct {
+		Result    bool `json:"result"`
+		ErrorCode int  `json:"error_code"`
+	}
+
+	var res response
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+	params.Set("from", strconv.Itoa(from))
+	params.Set("to", strconv.Itoa(to))
+
+	err := o.SendAuthenticatedHTTPRequest(spotFundsTransfer, params, &res)
+	if err != nil && res.ErrorCode == 0 {
+		return err
+	}
+	if res.ErrorCode != 0 {
+		return fmt.Errorf("ErrCode:%d ErrMsg:%s", res.ErrorCode, o.ErrorCodes[strconv.Itoa(res.ErrorCode)])
+	}
+	return nil
+}
+
 // GetLatestSpotPrice returns latest spot price of symbol
 //
 // symbol: string of currency pair
@@ -906,7 +965,8 @@ func (o *OKEX) SendAuthenticatedHTTPRequest(method string, values url.Values, re
 	}
 
 	values.Set("api_key", o.APIKey)
-	hasher := common.GetMD5([]byte(values.Encode() + "&secret_key=" + o.APISecret))
+	payload := o.Canonicalize(&request.CanonicalRequest{Params: values})
+	hasher := common.GetMD5([]byte(payload + "&secret_key=" + o.APISecret))
 	values.Set("sign", strings.ToUpper(common.HexEncodeToString(hasher)))
 
 	encoded := values.Encode()
@@ -1114,7 +1174,8 @@ func (o *OKEX) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 	var fee float64
 	switch feeBuilder.FeeType {
 	case exchange.CryptocurrencyTradeFee:
-		fee = calculateTradingFee(feeBuilder.PurchasePrice, feeBuilder.Amount, feeBuilder.IsMaker)
+		fee = o.DiscountTradingFee(feeBuilder,
+			calculateTradingFee(feeBuilder.PurchasePrice, feeBuilder.Amount, feeBuilder.IsMaker))
 	case exchange.CryptocurrencyWithdrawalFee:
 		fee = getWithdrawalFee(feeBuilder.FirstCurrency)
 	}
@@ -1,11 +1,8 @@
 package okex
 
 import (
-	"bytes"
-	"compress/flate"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
@@ -21,6 +18,8 @@ import (
 
 const (
 	okexDefaultWebsocketURL = "wss://real.okex.com:10440/websocket/okexapi"
+	okexWebsocketPing       = "{'event':'ping'}"
+	okexWebsocketPingDelay  = time.Second * 27
 )
 
 func (o *OKEX) writeToWebsocket(message string) error {
@@ -37,6 +36,7 @@ func (o *OKEX) WsConnect() error {
 	}
 
 	var dialer websocket.Dialer
+	o.Websocket.ConfigureDialer(&dialer)
 
 	if o.Websocket.GetProxyAddress() != "" {
 		proxy, err := url.Parse(o.Websocket.GetProxyAddress())
@@ -58,7 +58,9 @@ func (o *OKEX) WsConnect() error {
 
 	go o.WsHandleData()
 	go o.WsReadData()
-	go o.wsPingHandler()
+	o.Websocket.StartKeepalive(o.WebsocketConn, func(data []byte) error {
+		return o.writeToWebsocket(string(data))
+	})
 
 	err = o.WsSubscribe()
 	if err != nil {
@@ -135,7 +137,7 @@ func (o *OKEX) WsReadData() {
 			return
 
 		default:
-			mType, resp, err := o.WebsocketConn.ReadMessage()
+			mType, resp, err := o.Websocket.ReadMessage(o.WebsocketConn)
 			if err != nil {
 				o.Websocket.DataHandler <- err
 				return
@@ -143,44 +145,13 @@ func (o *OKEX) WsReadData() {
 
 			o.Websocket.TrafficAlert <- struct{}{}
 
-			var standardMessage []byte
-
-			switch mType {
-			case websocket.TextMessage:
-				standardMessage = resp
-
-			case websocket.BinaryMessage:
-				reader := flate.NewReader(bytes.NewReader(resp))
-				standardMessage, err = ioutil.ReadAll(reader)
-				reader.Close()
-				if err != nil {
-					o.Websocket.DataHandler <- err
-					return
-				}
-			}
-
-			o.Websocket.Intercomm <- exchange.WebsocketResponse{Raw: standardMessage}
-		}
-	}
-}
-
-func (o *OKEX) wsPingHandler() {
-	o.Websocket.Wg.Add(1)
-	defer o.Websocket.Wg.Done()
-
-	ticker := time.NewTicker(time.Second * 27)
-
-	for {
-		select {
-		case <-o.Websocket.ShutdownC:
-			return
-
-		case <-ticker.C:
-			err := o.writeToWebsocket("{'event':'ping'}")
+			standardMessage, err := o.Websocket.Decompress(mType, resp)
 			if err != nil {
 				o.Websocket.DataHandler <- err
 				return
 			}
+
+			o.Websocket.Intercomm <- exchange.WebsocketResponse{Raw: standardMessage}
 		}
 	}
 }
@@ -0,0 +1,194 @@
+package orderbook
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Vars for the orderbook consistency metrics
+var (
+	consistencyStats    = make(map[string]*ConsistencyStats)
+	consistencyStatsMtx sync.Mutex
+)
+
+// Divergence holds the mismatches between the top levels of a locally
+// maintained orderbook and a snapshot of the same book. A level mismatches
+// when its price or size differs or it is missing from either book, and the
+// price and size differences are relative to the snapshot, a missing level
+// counting as a difference of one
+type Divergence struct {
+	Depth         int     `json:"depth"`
+	BidMismatches int     `json:"bidMismatches"`
+	AskMismatches int     `json:"askMismatches"`
+	MaxPriceDiff  float64 `json:"maxPriceDiff"`
+	MaxSizeDiff   float64 `json:"maxSizeDiff"`
+}
+
+// ConsistencyThresholds holds the divergence at which a local orderbook is
+// considered out of sync with its snapshot, each check is disabled when zero
+type ConsistencyThresholds struct {
+	MaxMismatchedLevels int
+	MaxPriceDiff        float64
+	MaxSizeDiff         float64
+}
+
+// ConsistencyStats holds the consistency check metrics of an orderbook
+type ConsistencyStats struct {
+	Exchange            string            `json:"exchange"`
+	Pair                pair.CurrencyPair `json:"pair"`
+	AssetType           string            `json:"assetType"`
+	Checks              int64             `json:"checks"`
+	Breaches            int64             `json:"breaches"`
+	ConsecutiveBreaches int64             `json:"consecutiveBreaches"`
+	Resyncs             int64             `json:"resyncs"`
+	LastDivergence      Divergence        `json:"lastDivergence"`
+	LastChecked         time.Time         `json:"lastChecked"`
+	LastResync          time.Time         `json:"lastResync,omitempty"`
+}
+
+// Mismatches returns the total number of mismatched levels
+func (d Divergence) Mismatches() int {
+	return d.BidMismatches + d.AskMismatches
+}
+
+// Exceeds returns whether the divergence breaches any of the thresholds
+func (d Divergence) Exceeds(t ConsistencyThresholds) bool {
+	return (t.MaxMismatchedLevels > 0 && d.Mismatches() > t.MaxMismatchedLevels) ||
+		(t.MaxPriceDiff > 0 && d.MaxPriceDiff > t.MaxPriceDiff) ||
+		(t.MaxSizeDiff > 0 && d.MaxSizeDiff > t.MaxSizeDiff)
+}
+
+// Compare measures the divergence of the top depth levels of each side of a
+// local orderbook from a snapshot, bids are compared best first from the
+// highest price and asks from the lowest
+func Compare(local, snapshot Base, depth int) Divergence {
+	d := Divergence{Depth: depth}
+	d.BidMismatches = compareLevels(&d, topLevels(local.Bids, depth, true),
+		topLevels(snapshot.Bids, depth, true))
+	d.AskMismatches = compareLevels(&d, topLevels(local.Asks, depth, false),
+		topLevels(snapshot.Asks, depth, false))
+	return d
+}
+
+// topLevels returns a sorted copy of the best depth levels of a side
+func topLevels(items []Item, depth int, descending bool) []Item {
+	levels := make([]Item, len(items))
+	copy(levels, items)
+	sort.Slice(levels, func(i, j int) bool {
+		if descending {
+			return levels[i].Price > levels[j].Price
+		}
+		return levels[i].Price < levels[j].Price
+	})
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+	return levels
+}
+
+// compareLevels compares the levels of a side level by level, updating the
+// maximum differences of the divergence and returning the mismatch count
+func compareLevels(d *Divergence, local, snapshot []Item) int {
+	levels := len(local)
+	if len(snapshot) > levels {
+		levels = len(snapshot)
+	}
+
+	var mismatches int
+	for x := 0; x < levels; x++ {
+		if x >= len(local) || x >= len(snapshot) {
+			mismatches++
+			d.MaxPriceDiff = math.Max(d.MaxPriceDiff, 1)
+			d.MaxSizeDiff = math.Max(d.MaxSizeDiff, 1)
+			continue
+		}
+
+		priceDiff := relativeDiff(local[x].Price, snapshot[x].Price)
+		sizeDiff := relativeDiff(local[x].Amount, snapshot[x].Amount)
+		if priceDiff == 0 && sizeDiff == 0 {
+			continue
+		}
+		mismatches++
+		d.MaxPriceDiff = math.Max(d.MaxPriceDiff, priceDiff)
+		d.MaxSizeDiff = math.Max(d.MaxSizeDiff, sizeDiff)
+	}
+	return mismatches
+}
+
+// relativeDiff returns the difference of a value from its reference relative
+// to the reference
+func relativeDiff(value, reference float64) float64 {
+	if value == reference {
+		return 0
+	}
+	if reference == 0 {
+		return 1
+	}
+	return math.Abs(value-reference) / math.Abs(reference)
+}
+
+// RecordConsistencyCheck records the result of a consistency check of an
+// orderbook and returns its updated metrics
+func RecordConsistencyCheck(exchange string, p pair.CurrencyPair, assetType string, d Divergence, breached bool) ConsistencyStats {
+	consistencyStatsMtx.Lock()
+	defer consistencyStatsMtx.Unlock()
+	stats := getConsistencyStats(exchange, p, assetType)
+	stats.Checks++
+	stats.LastDivergence = d
+	stats.LastChecked = time.Now()
+	if breached {
+		stats.Breaches++
+		stats.ConsecutiveBreaches++
+	} else {
+		stats.ConsecutiveBreaches = 0
+	}
+	return *stats
+}
+
+// RecordResync records an orderbook being resynced from a snapshot after
+// diverging, resetting its consecutive breaches
+func RecordResync(exchange string, p pair.CurrencyPair, assetType string) {
+	consistencyStatsMtx.Lock()
+	defer consistencyStatsMtx.Unlock()
+	stats := getConsistencyStats(exchange, p, assetType)
+	stats.Resyncs++
+	stats.ConsecutiveBreaches = 0
+	stats.LastResync = time.Now()
+}
+
+// GetConsistencyStats returns the consistency check metrics of every checked
+// orderbook ordered by exchange, pair and asset type
+func GetConsistencyStats() []ConsistencyStats {
+	consistencyStatsMtx.Lock()
+	defer consistencyStatsMtx.Unlock()
+	result := make([]ConsistencyStats, 0, len(consistencyStats))
+	for _, stats := range consistencyStats {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return consistencyStatsKey(result[i].Exchange, result[i].Pair, result[i].AssetType) <
+			consistencyStatsKey(result[j].Exchange, result[j].Pair, result[j].AssetType)
+	})
+	return result
+}
+
+// getConsistencyStats returns the metrics of an orderbook, creating them when
+// not found. The caller must hold consistencyStatsMtx
+func getConsistencyStats(exchange string, p pair.CurrencyPair, assetType string) *ConsistencyStats {
+	key := consistencyStatsKey(exchange, p, assetType)
+	stats, ok := consistencyStats[key]
+	if !ok {
+		stats = &ConsistencyStats{Exchange: exchange, Pair: p, AssetType: assetType}
+		consistencyStats[key] = stats
+	}
+	return stats
+}
+
+// consistencyStatsKey returns the key of an orderbook's metrics
+func consistencyStatsKey(exchange string, p pair.CurrencyPair, assetType string) string {
+	return exchange + " " + p.Pair().String() + " " + assetType
+}
@@ -0,0 +1,71 @@
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestCompare(t *testing.T) {
+	snapshot := Base{
+		Bids: []Item{{Price: 99, Amount: 1}, {Price: 98, Amount: 2}, {Price: 97, Amount: 3}},
+		Asks: []Item{{Price: 101, Amount: 1}, {Price: 102, Amount: 2}, {Price: 103, Amount: 3}},
+	}
+	local := Base{
+		Bids: []Item{{Price: 98, Amount: 2}, {Price: 99, Amount: 1}, {Price: 96, Amount: 5}},
+		Asks: []Item{{Price: 102, Amount: 2}, {Price: 101, Amount: 1.5}},
+	}
+
+	d := Compare(local, snapshot, 2)
+	if d.Mismatches() != 1 || d.AskMismatches != 1 || d.MaxSizeDiff != 0.5 || d.MaxPriceDiff != 0 {
+		t.Errorf("Test Failed - Compare() unexpected top 2 divergence %+v", d)
+	}
+
+	d = Compare(local, snapshot, 3)
+	if d.BidMismatches != 1 || d.AskMismatches != 2 || d.MaxPriceDiff != 1 || d.MaxSizeDiff != 1 {
+		t.Errorf("Test Failed - Compare() unexpected top 3 divergence %+v", d)
+	}
+
+	if d.Exceeds(ConsistencyThresholds{}) {
+		t.Error("Test Failed - Exceeds() breached disabled thresholds")
+	}
+	if d.Exceeds(ConsistencyThresholds{MaxMismatchedLevels: 3}) {
+		t.Error("Test Failed - Exceeds() breached mismatched levels at the threshold")
+	}
+	if !d.Exceeds(ConsistencyThresholds{MaxMismatchedLevels: 2}) {
+		t.Error("Test Failed - Exceeds() didn't breach mismatched levels")
+	}
+	if !d.Exceeds(ConsistencyThresholds{MaxPriceDiff: 0.01}) {
+		t.Error("Test Failed - Exceeds() didn't breach price difference")
+	}
+
+	if d = Compare(snapshot, snapshot, 0); d.Mismatches() != 0 {
+		t.Errorf("Test Failed - Compare() identical books diverged %+v", d)
+	}
+}
+
+func TestConsistencyStats(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	RecordConsistencyCheck("ConsistencyTest", p, Spot, Divergence{BidMismatches: 2}, true)
+	stats := RecordConsistencyCheck("ConsistencyTest", p, Spot, Divergence{AskMismatches: 1}, true)
+	if stats.Checks != 2 || stats.Breaches != 2 || stats.ConsecutiveBreaches != 2 ||
+		stats.LastDivergence.AskMismatches != 1 {
+		t.Errorf("Test Failed - RecordConsistencyCheck() unexpected stats %+v", stats)
+	}
+
+	RecordResync("ConsistencyTest", p, Spot)
+	stats = RecordConsistencyCheck("ConsistencyTest", p, Spot, Divergence{}, false)
+	if stats.Resyncs != 1 || stats.ConsecutiveBreaches != 0 || stats.Breaches != 2 {
+		t.Errorf("Test Failed - RecordResync() unexpected stats %+v", stats)
+	}
+
+	var found bool
+	for _, s := range GetConsistencyStats() {
+		if s.Exchange == "ConsistencyTest" && s.Checks == 3 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Test Failed - GetConsistencyStats() missing recorded stats")
+	}
+}
@@ -0,0 +1,241 @@
+package orderbook
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultSnapshotInterval is the number of delta updates sent between full
+// orderbook snapshots
+const DefaultSnapshotInterval = 100
+
+// Delta errors
+var (
+	// ErrDeltaSequenceGap is returned when a delta does not follow on from the
+	// last applied sequence number, the book is unusable until the next
+	// snapshot is applied
+	ErrDeltaSequenceGap = errors.New("orderbook delta sequence gap, awaiting snapshot")
+	// ErrDeltaNotSynced is returned when a delta is applied before a snapshot
+	ErrDeltaNotSynced = errors.New("orderbook delta received before snapshot")
+)
+
+// Delta holds either a full orderbook snapshot or the price levels which have
+// changed since the previous sequence number. Levels with a zero amount have
+// been removed from the book
+type Delta struct {
+	Exchange  string `json:"exchange"`
+	Pair      string `json:"pair"`
+	AssetType string `json:"assetType"`
+	Sequence  uint64 `json:"sequence"`
+	Snapshot  bool   `json:"snapshot"`
+	Bids      []Item `json:"bids"`
+	Asks      []Item `json:"asks"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// DeltaEncoder converts successive orderbooks for a single exchange, pair and
+// asset type into sequenced deltas, sending a full snapshot periodically so
+// clients can recover from missed updates
+type DeltaEncoder struct {
+	exchange         string
+	snapshotInterval int
+	sequence         uint64
+	sinceSnapshot    int
+	bids             map[float64]float64
+	asks             map[float64]float64
+	last             Base
+	m                sync.Mutex
+}
+
+// NewDeltaEncoder returns a delta encoder for an exchange orderbook, a zero
+// snapshot interval uses DefaultSnapshotInterval
+func NewDeltaEncoder(exchange string, snapshotInterval int) *DeltaEncoder {
+	if snapshotInterval <= 0 {
+		snapshotInterval = DefaultSnapshotInterval
+	}
+	return &DeltaEncoder{
+		exchange:         exchange,
+		snapshotInterval: snapshotInterval,
+	}
+}
+
+// Encode returns the changes between the orderbook and the previously encoded
+// orderbook. The first update and every snapshot interval updates are sent as
+// full snapshots. False is returned when the orderbook has not changed
+func (d *DeltaEncoder) Encode(b Base) (Delta, bool) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	bids := aggregateLevels(b.Bids)
+	asks := aggregateLevels(b.Asks)
+
+	snapshot := d.bids == nil || d.sinceSnapshot >= d.snapshotInterval
+	delta := d.newDelta(b)
+	if snapshot {
+		delta.Bids = sortLevels(bids, true)
+		delta.Asks = sortLevels(asks, false)
+	} else {
+		delta.Bids = sortLevels(diffLevels(d.bids, bids), true)
+		delta.Asks = sortLevels(diffLevels(d.asks, asks), false)
+		if len(delta.Bids) == 0 && len(delta.Asks) == 0 {
+			return Delta{}, false
+		}
+	}
+
+	d.sequence++
+	delta.Sequence = d.sequence
+	delta.Snapshot = snapshot
+	if snapshot {
+		d.sinceSnapshot = 0
+	} else {
+		d.sinceSnapshot++
+	}
+
+	d.bids = bids
+	d.asks = asks
+	d.last = b
+	return delta, true
+}
+
+// Snapshot returns a full snapshot of the last encoded orderbook at the current
+// sequence number, used to bring a new subscriber up to date. False is returned
+// if no orderbook has been encoded
+func (d *DeltaEncoder) Snapshot() (Delta, bool) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if d.bids == nil {
+		return Delta{}, false
+	}
+
+	delta := d.newDelta(d.last)
+	delta.Sequence = d.sequence
+	delta.Snapshot = true
+	delta.Bids = sortLevels(d.bids, true)
+	delta.Asks = sortLevels(d.asks, false)
+	return delta, true
+}
+
+func (d *DeltaEncoder) newDelta(b Base) Delta {
+	timestamp := b.LastUpdated
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return Delta{
+		Exchange:  d.exchange,
+		Pair:      b.Pair.Pair().String(),
+		AssetType: b.AssetType,
+		Timestamp: timestamp.Unix(),
+	}
+}
+
+// DeltaBook maintains an orderbook from a stream of deltas on the client side
+type DeltaBook struct {
+	Sequence uint64
+	synced   bool
+	bids     map[float64]float64
+	asks     map[float64]float64
+}
+
+// Apply applies a snapshot or delta to the book. Deltas older than the current
+// sequence number are ignored, while a gap in sequence numbers leaves the book
+// unsynced until the next snapshot
+func (b *DeltaBook) Apply(d Delta) error {
+	if d.Snapshot {
+		b.bids = aggregateLevels(d.Bids)
+		b.asks = aggregateLevels(d.Asks)
+		b.Sequence = d.Sequence
+		b.synced = true
+		return nil
+	}
+
+	if !b.synced {
+		return ErrDeltaNotSynced
+	}
+
+	if d.Sequence <= b.Sequence {
+		return nil
+	}
+
+	if d.Sequence != b.Sequence+1 {
+		b.synced = false
+		return ErrDeltaSequenceGap
+	}
+
+	applyLevels(b.bids, d.Bids)
+	applyLevels(b.asks, d.Asks)
+	b.Sequence = d.Sequence
+	return nil
+}
+
+// IsSynced returns whether the book is up to date with the stream
+func (b *DeltaBook) IsSynced() bool {
+	return b.synced
+}
+
+// GetBids returns the bids ordered from the best price
+func (b *DeltaBook) GetBids() []Item {
+	return sortLevels(b.bids, true)
+}
+
+// GetAsks returns the asks ordered from the best price
+func (b *DeltaBook) GetAsks() []Item {
+	return sortLevels(b.asks, false)
+}
+
+// aggregateLevels sums the amounts of the items at each price level
+func aggregateLevels(items []Item) map[float64]float64 {
+	levels := make(map[float64]float64, len(items))
+	for x := range items {
+		levels[items[x].Price] += items[x].Amount
+	}
+	return levels
+}
+
+// diffLevels returns the price levels which have been added, changed or
+// removed between two books, removed levels have a zero amount
+func diffLevels(previous, current map[float64]float64) map[float64]float64 {
+	changes := make(map[float64]float64)
+	for price, amount := range current {
+		if prev, ok := previous[price]; !ok || prev != amount {
+			changes[price] = amount
+		}
+	}
+
+	for price := range previous {
+		if _, ok := current[price]; !ok {
+			changes[price] = 0
+		}
+	}
+	return changes
+}
+
+// applyLevels updates the price levels, removing those with a zero amount
+func applyLevels(levels map[float64]float64, changes []Item) {
+	for x := range changes {
+		if changes[x].Amount == 0 {
+			delete(levels, changes[x].Price)
+			continue
+		}
+		levels[changes[x].Price] = changes[x].Amount
+	}
+}
+
+// sortLevels returns the price levels as items ordered from the best price
+func sortLevels(levels map[float64]float64, descending bool) []Item {
+	items := make([]Item, 0, len(levels))
+	for price, amount := range levels {
+		items = append(items, Item{Price: price, Amount: amount})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if descending {
+			return items[i].Price > items[j].Price
+		}
+		return items[i].Price < items[j].Price
+	})
+	return items
+}
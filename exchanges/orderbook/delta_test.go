@@ -0,0 +1,101 @@
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestDeltaEncoder(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	encoder := NewDeltaEncoder("Bitstamp", 2)
+	if _, ok := encoder.Snapshot(); ok {
+		t.Error("Test Failed - DeltaEncoder Snapshot() returned a snapshot before encoding")
+	}
+
+	book := Base{
+		Pair:      p,
+		AssetType: Spot,
+		Bids:      []Item{{Price: 99, Amount: 1}, {Price: 98, Amount: 2}},
+		Asks:      []Item{{Price: 101, Amount: 1}, {Price: 102, Amount: 2}},
+	}
+
+	var client DeltaBook
+	delta, ok := encoder.Encode(book)
+	if !ok || !delta.Snapshot || delta.Sequence != 1 || len(delta.Bids) != 2 {
+		t.Fatalf("Test Failed - DeltaEncoder Encode() unexpected initial snapshot %+v", delta)
+	}
+	if err := client.Apply(delta); err != nil {
+		t.Fatal("Test Failed - DeltaBook Apply() error", err)
+	}
+
+	if _, ok = encoder.Encode(book); ok {
+		t.Error("Test Failed - DeltaEncoder Encode() returned a delta for an unchanged book")
+	}
+
+	book.Bids = []Item{{Price: 99, Amount: 3}}
+	book.Asks = []Item{{Price: 101, Amount: 1}, {Price: 102, Amount: 2}, {Price: 103, Amount: 1}}
+	delta, ok = encoder.Encode(book)
+	if !ok || delta.Snapshot || delta.Sequence != 2 {
+		t.Fatalf("Test Failed - DeltaEncoder Encode() unexpected delta %+v", delta)
+	}
+
+	// Bid at 99 changed and 98 removed, ask at 103 added
+	if len(delta.Bids) != 2 || delta.Bids[0].Price != 99 || delta.Bids[0].Amount != 3 ||
+		delta.Bids[1].Price != 98 || delta.Bids[1].Amount != 0 ||
+		len(delta.Asks) != 1 || delta.Asks[0].Price != 103 {
+		t.Errorf("Test Failed - DeltaEncoder Encode() unexpected levels %+v", delta)
+	}
+
+	if err := client.Apply(delta); err != nil {
+		t.Fatal("Test Failed - DeltaBook Apply() error", err)
+	}
+
+	bids := client.GetBids()
+	asks := client.GetAsks()
+	if len(bids) != 1 || bids[0].Amount != 3 || len(asks) != 3 ||
+		asks[0].Price != 101 || asks[2].Price != 103 {
+		t.Errorf("Test Failed - DeltaBook unexpected book bids %v asks %v", bids, asks)
+	}
+
+	snapshot, ok := encoder.Snapshot()
+	if !ok || !snapshot.Snapshot || snapshot.Sequence != 2 || len(snapshot.Asks) != 3 {
+		t.Errorf("Test Failed - DeltaEncoder Snapshot() unexpected snapshot %+v", snapshot)
+	}
+
+	book.Asks = book.Asks[:2]
+	encoder.Encode(book)
+	book.Bids = []Item{{Price: 97, Amount: 1}}
+	delta, ok = encoder.Encode(book)
+	if !ok || !delta.Snapshot || delta.Sequence != 4 {
+		t.Errorf("Test Failed - DeltaEncoder Encode() expected periodic snapshot %+v", delta)
+	}
+}
+
+func TestDeltaBookApply(t *testing.T) {
+	var book DeltaBook
+	err := book.Apply(Delta{Sequence: 1})
+	if err != ErrDeltaNotSynced {
+		t.Error("Test Failed - DeltaBook Apply() expected not synced error", err)
+	}
+
+	err = book.Apply(Delta{Sequence: 5, Snapshot: true, Bids: []Item{{Price: 1, Amount: 1}}})
+	if err != nil || !book.IsSynced() {
+		t.Fatal("Test Failed - DeltaBook Apply() snapshot error", err)
+	}
+
+	err = book.Apply(Delta{Sequence: 4, Bids: []Item{{Price: 1, Amount: 0}}})
+	if err != nil || len(book.GetBids()) != 1 {
+		t.Error("Test Failed - DeltaBook Apply() stale delta not ignored", err)
+	}
+
+	err = book.Apply(Delta{Sequence: 7})
+	if err != ErrDeltaSequenceGap || book.IsSynced() {
+		t.Error("Test Failed - DeltaBook Apply() expected sequence gap error", err)
+	}
+
+	err = book.Apply(Delta{Sequence: 8})
+	if err != ErrDeltaNotSynced {
+		t.Error("Test Failed - DeltaBook Apply() expected not synced error", err)
+	}
+}
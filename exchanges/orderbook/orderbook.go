@@ -21,6 +21,9 @@ const (
 var (
 	Orderbooks []Orderbook
 	m          sync.Mutex
+
+	cacheStats    CacheStats
+	cacheStatsMtx sync.Mutex
 )
 
 // Item stores the amount and price values
@@ -30,7 +33,8 @@ type Item struct {
 	ID     int64
 }
 
-// Base holds the fields for the orderbook base
+// Base holds the fields for the orderbook base. Stale is set on orderbooks
+// restored from disk until the exchange refreshes them
 type Base struct {
 	Pair         pair.CurrencyPair `json:"pair"`
 	CurrencyPair string            `json:"CurrencyPair"`
@@ -38,6 +42,7 @@ type Base struct {
 	Asks         []Item            `json:"asks"`
 	LastUpdated  time.Time         `json:"last_updated"`
 	AssetType    string
+	Stale        bool `json:"stale"`
 }
 
 // Orderbook holds the orderbook information for a currency pair and type
@@ -77,22 +82,59 @@ func (o *Base) Update(Bids, Asks []Item) {
 	o.LastUpdated = time.Now()
 }
 
+// CacheStats holds the number of orderbook lookups served from the store and
+// the number which were not found
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// HitRate returns the percentage of lookups served from the orderbook store
+func (c CacheStats) HitRate() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total) * 100
+}
+
+// GetCacheStats returns the orderbook store lookup counts
+func GetCacheStats() CacheStats {
+	cacheStatsMtx.Lock()
+	defer cacheStatsMtx.Unlock()
+	return cacheStats
+}
+
+func recordCacheLookup(hit bool) {
+	cacheStatsMtx.Lock()
+	if hit {
+		cacheStats.Hits++
+	} else {
+		cacheStats.Misses++
+	}
+	cacheStatsMtx.Unlock()
+}
+
 // GetOrderbook checks and returns the orderbook given an exchange name and
 // currency pair if it exists
 func GetOrderbook(exchange string, p pair.CurrencyPair, orderbookType string) (Base, error) {
 	orderbook, err := GetOrderbookByExchange(exchange)
 	if err != nil {
+		recordCacheLookup(false)
 		return Base{}, err
 	}
 
 	if !FirstCurrencyExists(exchange, p.FirstCurrency) {
+		recordCacheLookup(false)
 		return Base{}, errors.New(ErrPrimaryCurrencyNotFound)
 	}
 
 	if !SecondCurrencyExists(exchange, p) {
+		recordCacheLookup(false)
 		return Base{}, errors.New(ErrSecondaryCurrencyNotFound)
 	}
 
+	recordCacheLookup(true)
 	return orderbook.Orderbook[p.FirstCurrency][p.SecondCurrency][orderbookType], nil
 }
 
@@ -328,3 +328,28 @@ func TestProcessOrderbook(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestGetCacheStats(t *testing.T) {
+	before := GetCacheStats()
+
+	newPair := pair.NewCurrencyPair("BTC", "AUD")
+	ProcessOrderbook("cachestats", newPair, Base{Pair: newPair, CurrencyPair: newPair.Pair().String()}, Spot)
+	_, err := GetOrderbook("cachestats", newPair, Spot)
+	if err != nil {
+		t.Fatal("Test Failed - GetOrderbook() error", err)
+	}
+
+	_, err = GetOrderbook("cachestats", pair.NewCurrencyPair("LTC", "AUD"), Spot)
+	if err == nil {
+		t.Fatal("Test Failed - GetOrderbook() returned nil error on missing pair")
+	}
+
+	after := GetCacheStats()
+	if after.Hits < before.Hits+1 || after.Misses < before.Misses+1 {
+		t.Errorf("Test Failed - GetCacheStats() lookups not recorded %+v", after)
+	}
+
+	if (CacheStats{Hits: 3, Misses: 1}).HitRate() != 75 || (CacheStats{}).HitRate() != 0 {
+		t.Error("Test Failed - CacheStats HitRate() incorrect")
+	}
+}
@@ -0,0 +1,117 @@
+package orderbook
+
+import (
+	"os"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// storedOrderbook holds an orderbook persisted to disk
+type storedOrderbook struct {
+	Exchange       string            `json:"exchange"`
+	FirstCurrency  pair.CurrencyItem `json:"firstCurrency"`
+	SecondCurrency pair.CurrencyItem `json:"secondCurrency"`
+	OrderbookType  string            `json:"orderbookType"`
+	Orderbook      Base              `json:"orderbook"`
+}
+
+// SaveOrderbooks persists the last known orderbooks to the file at path so
+// they can be restored on the next start
+func SaveOrderbooks(path string) error {
+	m.Lock()
+	var stored []storedOrderbook
+	for x := range Orderbooks {
+		for first, seconds := range Orderbooks[x].Orderbook {
+			for second, orderbookTypes := range seconds {
+				for orderbookType, ob := range orderbookTypes {
+					stored = append(stored, storedOrderbook{
+						Exchange:       Orderbooks[x].ExchangeName,
+						FirstCurrency:  first,
+						SecondCurrency: second,
+						OrderbookType:  orderbookType,
+						Orderbook:      ob,
+					})
+				}
+			}
+		}
+	}
+	m.Unlock()
+
+	data, err := common.JSONEncode(stored)
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(path, data)
+}
+
+// LoadOrderbooks restores the orderbooks persisted to the file at path,
+// marking them stale until the exchange refreshes them. Orderbooks last
+// updated before maxAge ago are discarded and a zero maxAge keeps every
+// orderbook. Orderbooks already in the store are never replaced
+func LoadOrderbooks(path string, maxAge time.Duration) (int, error) {
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var stored []storedOrderbook
+	err = common.JSONDecode(data, &stored)
+	if err != nil {
+		return 0, err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	var restored int
+	for x := range stored {
+		if maxAge > 0 && time.Since(stored[x].Orderbook.LastUpdated) > maxAge {
+			continue
+		}
+		stored[x].Orderbook.Stale = true
+		if restoreOrderbook(stored[x]) {
+			restored++
+		}
+	}
+	return restored, nil
+}
+
+// restoreOrderbook adds a persisted orderbook to the store unless one already
+// exists for the exchange, pair and orderbook type, m must be held by the
+// caller
+func restoreOrderbook(s storedOrderbook) bool {
+	var o *Orderbook
+	for x := range Orderbooks {
+		if Orderbooks[x].ExchangeName == s.Exchange {
+			o = &Orderbooks[x]
+			break
+		}
+	}
+
+	if o == nil {
+		Orderbooks = append(Orderbooks, Orderbook{
+			ExchangeName: s.Exchange,
+			Orderbook:    make(map[pair.CurrencyItem]map[pair.CurrencyItem]map[string]Base),
+		})
+		o = &Orderbooks[len(Orderbooks)-1]
+	}
+
+	if _, ok := o.Orderbook[s.FirstCurrency]; !ok {
+		o.Orderbook[s.FirstCurrency] = make(map[pair.CurrencyItem]map[string]Base)
+	}
+
+	if _, ok := o.Orderbook[s.FirstCurrency][s.SecondCurrency]; !ok {
+		o.Orderbook[s.FirstCurrency][s.SecondCurrency] = make(map[string]Base)
+	}
+
+	if _, ok := o.Orderbook[s.FirstCurrency][s.SecondCurrency][s.OrderbookType]; ok {
+		return false
+	}
+	o.Orderbook[s.FirstCurrency][s.SecondCurrency][s.OrderbookType] = s.Orderbook
+	return true
+}
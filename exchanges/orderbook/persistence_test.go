@@ -0,0 +1,55 @@
+package orderbook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestSaveLoadOrderbooks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "orderbooks")
+	if err != nil {
+		t.Fatal("Test Failed - TempDir() error", err)
+	}
+	defer os.RemoveAll(dir)
+
+	stored := Orderbooks
+	defer func() { Orderbooks = stored }()
+	Orderbooks = nil
+	path := filepath.Join(dir, "orderbooks.json")
+
+	p := pair.NewCurrencyPair("XMR", "EUR")
+	ProcessOrderbook("WarmCache", p, Base{Bids: []Item{{Price: 99, Amount: 1}}}, Spot)
+
+	err = SaveOrderbooks(path)
+	if err != nil {
+		t.Fatal("Test Failed - SaveOrderbooks() error", err)
+	}
+
+	Orderbooks = nil
+	restored, err := LoadOrderbooks(path, time.Hour)
+	if err != nil || restored != 1 {
+		t.Fatal("Test Failed - LoadOrderbooks() error", err)
+	}
+
+	ob, err := GetOrderbook("WarmCache", p, Spot)
+	if err != nil || len(ob.Bids) != 1 || ob.Bids[0].Price != 99 || !ob.Stale {
+		t.Error("Test Failed - LoadOrderbooks() orderbook not restored as stale", err)
+	}
+
+	ProcessOrderbook("WarmCache", p, Base{Bids: []Item{{Price: 100, Amount: 1}}}, Spot)
+	ob, err = GetOrderbook("WarmCache", p, Spot)
+	if err != nil || ob.Stale {
+		t.Error("Test Failed - ProcessOrderbook() refreshed orderbook still stale", err)
+	}
+
+	Orderbooks = nil
+	restored, err = LoadOrderbooks(path, time.Nanosecond)
+	if err != nil || restored != 0 {
+		t.Error("Test Failed - LoadOrderbooks() restored an expired orderbook", err)
+	}
+}
@@ -0,0 +1,229 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// ErrInvalidArbitrage is returned when an arbitrage executor is missing
+// required details
+var ErrInvalidArbitrage = errors.New("arbitrage requires two different exchanges, a pair and a max amount")
+
+// ArbitrageVenue holds the top of book and taker fee rate of the pair on one
+// of the two exchanges of an arbitrage, with the inventory pre-positioned on
+// the exchange to trade it. BaseBalance funds sells and QuoteBalance funds
+// buys. BuySlippage and SellSlippage are the slippage in basis points expected
+// by a buy or sell of the max amount of the arbitrage
+type ArbitrageVenue struct {
+	Venue
+	BaseBalance  float64 `json:"baseBalance"`
+	QuoteBalance float64 `json:"quoteBalance"`
+	BuySlippage  float64 `json:"buySlippage,omitempty"`
+	SellSlippage float64 `json:"sellSlippage,omitempty"`
+}
+
+// ArbitrageOpportunity holds a profitable price difference between the two
+// exchanges of an arbitrage, buying at the ask of one and selling at the bid
+// of the other. ProfitRate is the profit after fees and slippage as a
+// fraction of the buy value and Profit is in the quote currency
+type ArbitrageOpportunity struct {
+	Pair         pair.CurrencyPair `json:"pair"`
+	BuyExchange  string            `json:"buyExchange"`
+	SellExchange string            `json:"sellExchange"`
+	BuyPrice     float64           `json:"buyPrice"`
+	SellPrice    float64           `json:"sellPrice"`
+	Amount       float64           `json:"amount"`
+	ProfitRate   float64           `json:"profitRate"`
+	Profit       float64           `json:"profit"`
+	// MinDifferential is the lowest sell less buy price still returning
+	// the minimum profit after fees, checked again before the legs are
+	// submitted
+	MinDifferential float64 `json:"minDifferential"`
+}
+
+// EvaluateArbitrage returns the most profitable direction of an arbitrage
+// between two venues and whether it returns at least minProfit after fees and
+// slippage.
+// The amount is limited to maxAmount and to the inventory on each venue, the
+// quote balance of the buy venue and the base balance of the sell venue, so
+// both legs trade without funds being transferred
+func EvaluateArbitrage(p pair.CurrencyPair, a, b ArbitrageVenue, minProfit, maxAmount float64) (ArbitrageOpportunity, bool) {
+	best, ok := evaluateArbitrageDirection(p, a, b, minProfit, maxAmount)
+	reverse, reverseOK := evaluateArbitrageDirection(p, b, a, minProfit, maxAmount)
+	if reverseOK && (!ok || reverse.Profit > best.Profit) {
+		return reverse, true
+	}
+	return best, ok
+}
+
+// evaluateArbitrageDirection returns the opportunity of buying on one venue
+// and selling on the other
+func evaluateArbitrageDirection(p pair.CurrencyPair, buy, sell ArbitrageVenue, minProfit, maxAmount float64) (ArbitrageOpportunity, bool) {
+	if buy.Ask <= 0 || sell.Bid <= 0 {
+		return ArbitrageOpportunity{}, false
+	}
+
+	cost := EffectivePrice(FillBuy, buy.Ask, buy.FeeRate, buy.BuySlippage)
+	proceeds := EffectivePrice(FillSell, sell.Bid, sell.FeeRate, sell.SellSlippage)
+	rate := (proceeds - cost) / cost
+	if rate < minProfit || rate <= 0 {
+		return ArbitrageOpportunity{}, false
+	}
+
+	amount := math.Min(maxAmount, math.Min(buy.QuoteBalance/cost, sell.BaseBalance))
+	if amount <= 0 {
+		return ArbitrageOpportunity{}, false
+	}
+
+	return ArbitrageOpportunity{
+		Pair:            p,
+		BuyExchange:     buy.Exchange,
+		SellExchange:    sell.Exchange,
+		BuyPrice:        buy.Ask,
+		SellPrice:       sell.Bid,
+		Amount:          amount,
+		ProfitRate:      rate,
+		Profit:          (proceeds - cost) * amount,
+		MinDifferential: cost - buy.Ask + sell.Bid - proceeds + minProfit*cost,
+	}, true
+}
+
+// SpreadOrder returns a simultaneous spread order buying the opportunity on
+// one exchange and selling it on the other with market orders, so the legs
+// fill together once the differential is confirmed and a leg left unhedged is
+// flagged after hedgeTimeout
+func (o *ArbitrageOpportunity) SpreadOrder(hedgeTimeout time.Duration) SpreadOrder {
+	return SpreadOrder{
+		Legs: []SpreadLeg{
+			{Exchange: o.BuyExchange, Pair: o.Pair, Side: FillBuy, Ratio: 1},
+			{Exchange: o.SellExchange, Pair: o.Pair, Side: FillSell, Ratio: 1},
+		},
+		Quantity:           o.Amount,
+		TargetDifferential: o.MinDifferential,
+		Mode:               SpreadSimultaneous,
+		HedgeTimeout:       hedgeTimeout,
+	}
+}
+
+// ArbitrageResult holds the outcome of a step of an arbitrage executor.
+// Opportunity is set when one was found, Spread is the spread order submitted
+// for it or the in flight spread which has completed or been left with leg
+// risk, when LegRisk is set
+type ArbitrageResult struct {
+	Opportunity *ArbitrageOpportunity `json:"opportunity,omitempty"`
+	Spread      *SpreadOrder          `json:"spread,omitempty"`
+	LegRisk     bool                  `json:"legRisk"`
+}
+
+// ArbitrageExecutor executes two exchange arbitrage of a pair against the
+// inventory held on both exchanges, one spread order at a time. A spread
+// which fails or is left unhedged beyond the hedge timeout halts the executor
+// until it is resumed, as the residual position needs to be closed first
+type ArbitrageExecutor struct {
+	Exchanges    [2]string
+	Pair         pair.CurrencyPair
+	MinProfit    float64
+	MaxAmount    float64
+	HedgeTimeout time.Duration
+
+	spreadID int64
+	halted   string
+	mtx      sync.Mutex
+}
+
+// NewArbitrageExecutor returns an arbitrage executor of a pair between two
+// exchanges, trading opportunities returning at least minProfit after fees up
+// to maxAmount at a time
+func NewArbitrageExecutor(exchanges [2]string, p pair.CurrencyPair, minProfit, maxAmount float64, hedgeTimeout time.Duration) (*ArbitrageExecutor, error) {
+	if exchanges[0] == "" || exchanges[1] == "" ||
+		common.StringToLower(exchanges[0]) == common.StringToLower(exchanges[1]) ||
+		p.Empty() || maxAmount <= 0 || minProfit < 0 {
+		return nil, ErrInvalidArbitrage
+	}
+
+	return &ArbitrageExecutor{
+		Exchanges:    exchanges,
+		Pair:         p,
+		MinProfit:    minProfit,
+		MaxAmount:    maxAmount,
+		HedgeTimeout: hedgeTimeout,
+	}, nil
+}
+
+// Step monitors the in flight spread order of the executor and, once none is
+// in flight, evaluates the venues of its two exchanges for an opportunity.
+// Opportunities are added as spread orders to be executed by the spread order
+// processor unless dryRun is set, in which case they are only returned. A
+// halted executor returns an empty result
+func (a *ArbitrageExecutor) Step(venues [2]ArbitrageVenue, dryRun bool) (ArbitrageResult, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.spreadID != 0 {
+		spread, err := GetSpreadOrder(a.spreadID)
+		if err == ErrSpreadOrderNotFound {
+			a.spreadID = 0
+			return ArbitrageResult{}, nil
+		}
+
+		switch spread.Status {
+		case SpreadFilled:
+			a.spreadID = 0
+			return ArbitrageResult{Spread: &spread}, nil
+		case SpreadFailed, SpreadHedgeTimeout:
+			a.spreadID = 0
+			a.halted = fmt.Sprintf("spread order %d %s with residual leg amounts %v",
+				spread.ID, common.StringToLower(spread.Status), spread.Residuals())
+			return ArbitrageResult{Spread: &spread, LegRisk: true}, nil
+		}
+		return ArbitrageResult{}, nil
+	}
+
+	if a.halted != "" {
+		return ArbitrageResult{}, nil
+	}
+
+	opportunity, ok := EvaluateArbitrage(a.Pair, venues[0], venues[1], a.MinProfit, a.MaxAmount)
+	if !ok {
+		return ArbitrageResult{}, nil
+	}
+
+	result := ArbitrageResult{Opportunity: &opportunity}
+	if dryRun {
+		return result, nil
+	}
+
+	spread := opportunity.SpreadOrder(a.HedgeTimeout)
+	id, err := AddSpreadOrder(spread)
+	if err != nil {
+		return result, err
+	}
+
+	a.spreadID = id
+	spread, err = GetSpreadOrder(id)
+	if err == nil {
+		result.Spread = &spread
+	}
+	return result, nil
+}
+
+// Halted returns why the executor halted, empty when it is trading
+func (a *ArbitrageExecutor) Halted() string {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return a.halted
+}
+
+// Resume resumes trading after the executor halted, once the residual
+// position of the spread has been closed
+func (a *ArbitrageExecutor) Resume() {
+	a.mtx.Lock()
+	a.halted = ""
+	a.mtx.Unlock()
+}
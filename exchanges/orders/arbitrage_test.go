@@ -0,0 +1,107 @@
+package orders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func newTestArbitrageVenues() [2]ArbitrageVenue {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	return [2]ArbitrageVenue{
+		{Venue: Venue{Exchange: "Bitstamp", Pair: p, Bid: 99, Ask: 100, FeeRate: 0.001},
+			BaseBalance: 1, QuoteBalance: 1000},
+		{Venue: Venue{Exchange: "Kraken", Pair: p, Bid: 102, Ask: 103, FeeRate: 0.001},
+			BaseBalance: 15, QuoteBalance: 1000},
+	}
+}
+
+func TestEvaluateArbitrage(t *testing.T) {
+	venues := newTestArbitrageVenues()
+	p := venues[0].Pair
+
+	o, ok := EvaluateArbitrage(p, venues[0], venues[1], 0.01, 20)
+	if !ok || o.BuyExchange != "Bitstamp" || o.SellExchange != "Kraken" {
+		t.Fatalf("Test Failed - EvaluateArbitrage() unexpected opportunity %+v %v", o, ok)
+	}
+	// Limited by the quote balance on the buy venue
+	if o.Amount < 9.99 || o.Amount > 10 || o.Profit <= 0 {
+		t.Errorf("Test Failed - EvaluateArbitrage() unexpected amount %v profit %v", o.Amount, o.Profit)
+	}
+
+	// The reverse direction is found when prices swap
+	venues[0].Bid, venues[0].Ask, venues[1].Bid, venues[1].Ask = 102, 103, 99, 100
+	o, ok = EvaluateArbitrage(p, venues[0], venues[1], 0.01, 20)
+	if !ok || o.BuyExchange != "Kraken" || o.Amount != 1 {
+		t.Errorf("Test Failed - EvaluateArbitrage() unexpected reverse opportunity %+v %v", o, ok)
+	}
+
+	// Slippage expected selling on Bitstamp erases the spread
+	venues[0].SellSlippage = 200
+	if _, ok = EvaluateArbitrage(p, venues[0], venues[1], 0.01, 20); ok {
+		t.Error("Test Failed - EvaluateArbitrage() returned an opportunity erased by slippage")
+	}
+	venues[0].SellSlippage = 0
+
+	if _, ok = EvaluateArbitrage(p, venues[0], venues[1], 0.05, 20); ok {
+		t.Error("Test Failed - EvaluateArbitrage() returned an opportunity below the min profit")
+	}
+
+	venues[0].BaseBalance = 0
+	if _, ok = EvaluateArbitrage(p, venues[0], venues[1], 0.01, 20); ok {
+		t.Error("Test Failed - EvaluateArbitrage() returned an opportunity without inventory")
+	}
+}
+
+func TestArbitrageExecutor(t *testing.T) {
+	venues := newTestArbitrageVenues()
+	_, err := NewArbitrageExecutor([2]string{"Bitstamp", "bitstamp"}, venues[0].Pair, 0.01, 1, time.Minute)
+	if err != ErrInvalidArbitrage {
+		t.Error("Test Failed - NewArbitrageExecutor() same exchange error", err)
+	}
+
+	a, err := NewArbitrageExecutor([2]string{"Bitstamp", "Kraken"}, venues[0].Pair, 0.01, 1, time.Minute)
+	if err != nil {
+		t.Fatal("Test Failed - NewArbitrageExecutor() error", err)
+	}
+
+	result, err := a.Step(venues, true)
+	if err != nil || result.Opportunity == nil || result.Spread != nil {
+		t.Errorf("Test Failed - Step() dry run unexpected result %+v %v", result, err)
+	}
+
+	result, err = a.Step(venues, false)
+	if err != nil || result.Spread == nil || result.Spread.Mode != SpreadSimultaneous ||
+		result.Spread.Quantity != 1 || result.Spread.Legs[0].Side != FillBuy {
+		t.Fatalf("Test Failed - Step() unexpected result %+v %v", result, err)
+	}
+	id := result.Spread.ID
+	defer RemoveSpreadOrder(id)
+
+	// No new opportunities are taken while a spread is in flight
+	if result, _ = a.Step(venues, false); result.Opportunity != nil {
+		t.Error("Test Failed - Step() took an opportunity with a spread in flight")
+	}
+
+	exch := &testSpreadExchange{
+		prices: map[string]float64{"Bitstamp": 100, "Kraken": 102},
+		orders: make(map[string]int64),
+		fail:   "Kraken",
+	}
+	ProcessSpreadOrders(exch.price, exch.submit, time.Now())
+
+	result, _ = a.Step(venues, false)
+	if !result.LegRisk || result.Spread == nil || result.Spread.ID != id || a.Halted() == "" {
+		t.Errorf("Test Failed - Step() failed spread not flagged as leg risk %+v", result)
+	}
+	if result, _ = a.Step(venues, false); result.Opportunity != nil {
+		t.Error("Test Failed - Step() took an opportunity while halted")
+	}
+
+	a.Resume()
+	result, _ = a.Step(venues, true)
+	if result.Opportunity == nil {
+		t.Error("Test Failed - Step() didn't take an opportunity after resuming")
+	}
+}
@@ -0,0 +1,357 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Client order states
+const (
+	ClientOrderPending   = "PENDING"
+	ClientOrderSubmitted = "SUBMITTED"
+	ClientOrderCancelled = "CANCELLED"
+	ClientOrderMissing   = "MISSING"
+	ClientOrderFilled    = "FILLED"
+	ClientOrderUnknown   = "UNKNOWN"
+)
+
+// Vars for the client order ID registry
+var (
+	clientOrders    = make(map[string]map[string]*ClientOrder)
+	clientOrdersMtx sync.Mutex
+
+	// ErrClientIDRequired is returned when an order is submitted without a
+	// client order ID
+	ErrClientIDRequired = errors.New("client order ID required")
+	// ErrClientOrderPending is returned when an order with the same client
+	// order ID is still being submitted
+	ErrClientOrderPending = errors.New("order with client order ID is pending submission")
+	// ErrClientOrderUnknown is returned when the outcome of an earlier
+	// submission with the same client order ID is unknown and awaiting
+	// reconciliation
+	ErrClientOrderUnknown = errors.New("order with client order ID has an unknown submission outcome, awaiting reconciliation")
+	// ErrClientOrderNotFound is returned when no order has been submitted
+	// with the client order ID
+	ErrClientOrderNotFound = errors.New("client order ID not found")
+)
+
+// ClientOrder holds an order submission tracked by its client order ID.
+// Adopted is set on open orders found on the exchange during reconciliation
+// which weren't submitted through the registry
+type ClientOrder struct {
+	ClientID  string
+	Exchange  string
+	OrderID   int64
+	Status    string
+	Submitted time.Time
+	Adopted   bool
+}
+
+// SubmitFunc submits an order tagged with the client order ID and returns the
+// exchange order ID. Exchanges with a native client order ID field should
+// pass it through so retries are also rejected exchange side
+type SubmitFunc func(clientID string) (int64, error)
+
+// CancelFunc cancels an order by its exchange order ID
+type CancelFunc func(orderID int64) error
+
+// SubmitWithClientID submits an order at most once per exchange and client
+// order ID. Retrying a submission which has already succeeded returns the
+// original order ID without resubmitting, while a retry made whilst the
+// original submission is in flight returns ErrClientOrderPending. Rejected
+// submissions are removed from the registry so they can be retried, while
+// submissions which failed without a definitive answer from the exchange, such
+// as a timeout, are kept as unknown and return ErrClientOrderUnknown on retry
+// until reconciled against the open orders of the exchange
+func SubmitWithClientID(exchange, clientID string, submit SubmitFunc) (int64, error) {
+	if clientID == "" {
+		return 0, ErrClientIDRequired
+	}
+
+	exchange = common.StringToLower(exchange)
+	clientOrdersMtx.Lock()
+	if _, ok := clientOrders[exchange]; !ok {
+		clientOrders[exchange] = make(map[string]*ClientOrder)
+	}
+
+	if existing, ok := clientOrders[exchange][clientID]; ok {
+		clientOrdersMtx.Unlock()
+		switch existing.Status {
+		case ClientOrderPending:
+			return 0, ErrClientOrderPending
+		case ClientOrderUnknown:
+			return 0, ErrClientOrderUnknown
+		}
+		return existing.OrderID, nil
+	}
+
+	order := &ClientOrder{
+		ClientID:  clientID,
+		Exchange:  exchange,
+		Status:    ClientOrderPending,
+		Submitted: time.Now(),
+	}
+	clientOrders[exchange][clientID] = order
+	clientOrdersMtx.Unlock()
+
+	orderID, err := submit(clientID)
+
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+	if err != nil {
+		if IsIndeterminate(err) {
+			order.Status = ClientOrderUnknown
+			saveClientOrders()
+			return 0, err
+		}
+		delete(clientOrders[exchange], clientID)
+		return 0, err
+	}
+
+	order.OrderID = orderID
+	order.Status = ClientOrderSubmitted
+	saveClientOrders()
+	return orderID, nil
+}
+
+// BatchSubmitFunc submits the orders tagged with the client order IDs in a
+// single request, returning the exchange order ID and error of each order in
+// the same order as the client order IDs
+type BatchSubmitFunc func(clientIDs []string) ([]int64, []error)
+
+// SubmitBatchWithClientIDs submits a batch of orders at most once per exchange
+// and client order ID, following the same rules as SubmitWithClientID for each
+// order. Only orders which haven't already been submitted are passed to
+// submit. The order ID and error of each order are returned in the same order
+// as the client order IDs
+func SubmitBatchWithClientIDs(exchange string, clientIDs []string, submit BatchSubmitFunc) ([]int64, []error) {
+	orderIDs := make([]int64, len(clientIDs))
+	errs := make([]error, len(clientIDs))
+
+	exchange = common.StringToLower(exchange)
+	clientOrdersMtx.Lock()
+	if _, ok := clientOrders[exchange]; !ok {
+		clientOrders[exchange] = make(map[string]*ClientOrder)
+	}
+
+	var pending []int
+	for x, clientID := range clientIDs {
+		if clientID == "" {
+			errs[x] = ErrClientIDRequired
+			continue
+		}
+
+		if existing, ok := clientOrders[exchange][clientID]; ok {
+			switch existing.Status {
+			case ClientOrderPending:
+				errs[x] = ErrClientOrderPending
+				continue
+			case ClientOrderUnknown:
+				errs[x] = ErrClientOrderUnknown
+				continue
+			}
+			orderIDs[x] = existing.OrderID
+			continue
+		}
+
+		clientOrders[exchange][clientID] = &ClientOrder{
+			ClientID:  clientID,
+			Exchange:  exchange,
+			Status:    ClientOrderPending,
+			Submitted: time.Now(),
+		}
+		pending = append(pending, x)
+	}
+	clientOrdersMtx.Unlock()
+
+	if len(pending) == 0 {
+		return orderIDs, errs
+	}
+
+	submitIDs := make([]string, len(pending))
+	for x := range pending {
+		submitIDs[x] = clientIDs[pending[x]]
+	}
+	submittedIDs, submitErrs := submit(submitIDs)
+
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+	for x, i := range pending {
+		var err error
+		if x < len(submitErrs) {
+			err = submitErrs[x]
+		}
+		if err == nil && x >= len(submittedIDs) {
+			err = errors.New("no order ID returned for client order ID")
+		}
+
+		if err != nil {
+			if IsIndeterminate(err) {
+				clientOrders[exchange][clientIDs[i]].Status = ClientOrderUnknown
+			} else {
+				delete(clientOrders[exchange], clientIDs[i])
+			}
+			errs[i] = err
+			continue
+		}
+
+		order := clientOrders[exchange][clientIDs[i]]
+		order.OrderID = submittedIDs[x]
+		order.Status = ClientOrderSubmitted
+		orderIDs[i] = submittedIDs[x]
+	}
+	saveClientOrders()
+	return orderIDs, errs
+}
+
+// GetOrderByClientID returns the order submitted with the client order ID
+func GetOrderByClientID(exchange, clientID string) (ClientOrder, error) {
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+
+	order, ok := clientOrders[common.StringToLower(exchange)][clientID]
+	if !ok {
+		return ClientOrder{}, ErrClientOrderNotFound
+	}
+	return *order, nil
+}
+
+// GetClientOrders returns the orders submitted to an exchange with the status,
+// an empty status returns orders of any status
+func GetClientOrders(exchange, status string) []ClientOrder {
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+
+	var result []ClientOrder
+	for _, order := range clientOrders[common.StringToLower(exchange)] {
+		if status != "" && order.Status != status {
+			continue
+		}
+		result = append(result, *order)
+	}
+	return result
+}
+
+// CancelByClientID cancels the order submitted with the client order ID
+func CancelByClientID(exchange, clientID string, cancel CancelFunc) error {
+	order, err := GetOrderByClientID(exchange, clientID)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != ClientOrderSubmitted {
+		return fmt.Errorf("unable to cancel client order ID %s with status %s",
+			clientID, order.Status)
+	}
+
+	err = cancel(order.OrderID)
+	if err != nil {
+		return err
+	}
+
+	MarkClientOrderCancelled(exchange, clientID)
+	return nil
+}
+
+// MarkClientOrderCancelled marks the order submitted with the client order ID
+// as cancelled, for use when the order was cancelled natively by client order
+// ID. Unknown client order IDs are ignored as the order may have been
+// submitted before a restart
+func MarkClientOrderCancelled(exchange, clientID string) {
+	clientOrdersMtx.Lock()
+	if o, ok := clientOrders[common.StringToLower(exchange)][clientID]; ok {
+		o.Status = ClientOrderCancelled
+		saveClientOrders()
+	}
+	clientOrdersMtx.Unlock()
+}
+
+// SetClientOrderStatus applies the status of an order reported by its exchange
+// to the order submitted with the client order ID, returning the order and
+// whether its status changed. Submitted and missing orders can move to any of
+// submitted, missing, filled or cancelled, while filled and cancelled orders
+// are closed and keep their status
+func SetClientOrderStatus(exchange, clientID, status string) (ClientOrder, bool, error) {
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+
+	order, ok := clientOrders[common.StringToLower(exchange)][clientID]
+	if !ok {
+		return ClientOrder{}, false, ErrClientOrderNotFound
+	}
+
+	switch status {
+	case ClientOrderSubmitted, ClientOrderMissing, ClientOrderFilled, ClientOrderCancelled:
+	default:
+		return *order, false, fmt.Errorf("invalid client order status %s", status)
+	}
+
+	if order.Status != ClientOrderSubmitted && order.Status != ClientOrderMissing {
+		if order.Status == status {
+			return *order, false, nil
+		}
+		return *order, false, fmt.Errorf("unable to change client order ID %s from %s to %s",
+			clientID, order.Status, status)
+	}
+
+	if order.Status == status {
+		return *order, false, nil
+	}
+
+	order.Status = status
+	saveClientOrders()
+	return *order, true, nil
+}
+
+// RemoveClientOrders removes filled and cancelled client order IDs submitted
+// before the supplied time so the registry does not grow unbounded. Orders
+// which may still be open on the exchange are kept
+func RemoveClientOrders(before time.Time) {
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+
+	var removed bool
+	for exchange := range clientOrders {
+		for clientID, order := range clientOrders[exchange] {
+			if order.Status != ClientOrderFilled && order.Status != ClientOrderCancelled {
+				continue
+			}
+			if order.Submitted.Before(before) {
+				delete(clientOrders[exchange], clientID)
+				removed = true
+			}
+		}
+	}
+
+	if removed {
+		saveClientOrders()
+	}
+}
+
+// IsIndeterminate returns whether a submission error leaves the outcome of the
+// request unknown, as it may have reached the exchange before the connection
+// failed or the response could not be read
+func IsIndeterminate(err error) bool {
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &netErr),
+		errors.As(err, &syntaxErr),
+		errors.As(err, &typeErr),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+	return false
+}
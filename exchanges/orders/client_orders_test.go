@@ -0,0 +1,242 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSubmitWithClientID(t *testing.T) {
+	var submissions int
+	submit := func(clientID string) (int64, error) {
+		submissions++
+		return 1337, nil
+	}
+
+	_, err := SubmitWithClientID("Bitstamp", "", submit)
+	if err != ErrClientIDRequired {
+		t.Error("Test Failed - SubmitWithClientID() blank client ID error", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		orderID, err := SubmitWithClientID("Bitstamp", "order-1", submit)
+		if err != nil || orderID != 1337 {
+			t.Error("Test Failed - SubmitWithClientID() error", err)
+		}
+	}
+
+	if submissions != 1 {
+		t.Errorf("Test Failed - SubmitWithClientID() duplicate submission, submitted %d times",
+			submissions)
+	}
+
+	_, err = SubmitWithClientID("Bitstamp", "order-2", func(clientID string) (int64, error) {
+		_, pendingErr := SubmitWithClientID("BITSTAMP", clientID, submit)
+		if pendingErr != ErrClientOrderPending {
+			t.Error("Test Failed - SubmitWithClientID() in flight retry error", pendingErr)
+		}
+		return 0, errors.New("rejected")
+	})
+	if err == nil {
+		t.Error("Test Failed - SubmitWithClientID() submission error not returned")
+	}
+
+	_, err = GetOrderByClientID("Bitstamp", "order-2")
+	if err != ErrClientOrderNotFound {
+		t.Error("Test Failed - failed submission not removed from registry", err)
+	}
+}
+
+func TestSubmitBatchWithClientIDs(t *testing.T) {
+	_, err := SubmitWithClientID("Kraken", "batch-1", func(clientID string) (int64, error) {
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+
+	var submitted []string
+	orderIDs, errs := SubmitBatchWithClientIDs("Kraken",
+		[]string{"batch-1", "", "batch-2", "batch-3"},
+		func(clientIDs []string) ([]int64, []error) {
+			submitted = clientIDs
+			return []int64{2, 0}, []error{nil, errors.New("rejected")}
+		})
+
+	if len(submitted) != 2 || submitted[0] != "batch-2" || submitted[1] != "batch-3" {
+		t.Errorf("Test Failed - SubmitBatchWithClientIDs() unexpected submission %v", submitted)
+	}
+
+	if orderIDs[0] != 1 || errs[0] != nil {
+		t.Error("Test Failed - SubmitBatchWithClientIDs() existing order resubmitted", errs[0])
+	}
+
+	if errs[1] != ErrClientIDRequired {
+		t.Error("Test Failed - SubmitBatchWithClientIDs() blank client ID error", errs[1])
+	}
+
+	if orderIDs[2] != 2 || errs[2] != nil {
+		t.Error("Test Failed - SubmitBatchWithClientIDs() error", errs[2])
+	}
+
+	if errs[3] == nil {
+		t.Error("Test Failed - SubmitBatchWithClientIDs() submission error not returned")
+	}
+
+	_, err = GetOrderByClientID("Kraken", "batch-3")
+	if err != ErrClientOrderNotFound {
+		t.Error("Test Failed - failed submission not removed from registry", err)
+	}
+}
+
+func TestCancelByClientID(t *testing.T) {
+	_, err := SubmitWithClientID("Gemini", "order-3", func(clientID string) (int64, error) {
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+
+	var cancelled int64
+	err = CancelByClientID("Gemini", "order-3", func(orderID int64) error {
+		cancelled = orderID
+		return nil
+	})
+	if err != nil || cancelled != 42 {
+		t.Error("Test Failed - CancelByClientID() error", err)
+	}
+
+	order, err := GetOrderByClientID("gemini", "order-3")
+	if err != nil || order.Status != ClientOrderCancelled {
+		t.Error("Test Failed - CancelByClientID() status not updated", err)
+	}
+
+	err = CancelByClientID("Gemini", "order-3", func(orderID int64) error { return nil })
+	if err == nil {
+		t.Error("Test Failed - CancelByClientID() cancelled order twice")
+	}
+
+	err = CancelByClientID("Gemini", "order-4", func(orderID int64) error { return nil })
+	if err != ErrClientOrderNotFound {
+		t.Error("Test Failed - CancelByClientID() unknown client ID error", err)
+	}
+
+	RemoveClientOrders(time.Now().Add(time.Minute))
+	_, err = GetOrderByClientID("Gemini", "order-3")
+	if err != ErrClientOrderNotFound {
+		t.Error("Test Failed - RemoveClientOrders() order not removed", err)
+	}
+}
+
+func TestSubmitWithClientIDUnknown(t *testing.T) {
+	var submissions int
+	timeout := func(clientID string) (int64, error) {
+		submissions++
+		return 0, context.DeadlineExceeded
+	}
+
+	_, err := SubmitWithClientID("Bitflyer", "unknown-1", timeout)
+	if err != context.DeadlineExceeded {
+		t.Error("Test Failed - SubmitWithClientID() submission error not returned", err)
+	}
+
+	order, err := GetOrderByClientID("Bitflyer", "unknown-1")
+	if err != nil || order.Status != ClientOrderUnknown {
+		t.Error("Test Failed - SubmitWithClientID() timed out order not kept as unknown", order.Status, err)
+	}
+
+	_, err = SubmitWithClientID("Bitflyer", "unknown-1", timeout)
+	if err != ErrClientOrderUnknown || submissions != 1 {
+		t.Error("Test Failed - SubmitWithClientID() unknown order resubmitted", err)
+	}
+
+	_, errs := SubmitBatchWithClientIDs("Bitflyer", []string{"unknown-1", "unknown-2"},
+		func(clientIDs []string) ([]int64, []error) {
+			return nil, []error{io.ErrUnexpectedEOF}
+		})
+	if errs[0] != ErrClientOrderUnknown || errs[1] != io.ErrUnexpectedEOF {
+		t.Error("Test Failed - SubmitBatchWithClientIDs() unexpected errors", errs)
+	}
+
+	order, err = GetOrderByClientID("Bitflyer", "unknown-2")
+	if err != nil || order.Status != ClientOrderUnknown {
+		t.Error("Test Failed - SubmitBatchWithClientIDs() failed order not kept as unknown", order.Status, err)
+	}
+}
+
+func TestRemoveClientOrders(t *testing.T) {
+	submit := func(clientID string) (int64, error) {
+		return 42, nil
+	}
+
+	for _, clientID := range []string{"prune-1", "prune-2", "prune-3"} {
+		_, err := SubmitWithClientID("Coinut", clientID, submit)
+		if err != nil {
+			t.Fatal("Test Failed - SubmitWithClientID() error", err)
+		}
+	}
+	MarkClientOrderCancelled("Coinut", "prune-2")
+	_, _, err := SetClientOrderStatus("Coinut", "prune-3", ClientOrderFilled)
+	if err != nil {
+		t.Fatal("Test Failed - SetClientOrderStatus() error", err)
+	}
+
+	RemoveClientOrders(time.Now().Add(-time.Minute))
+	if len(GetClientOrders("Coinut", "")) != 3 {
+		t.Error("Test Failed - RemoveClientOrders() removed orders submitted after the cutoff")
+	}
+
+	RemoveClientOrders(time.Now().Add(time.Minute))
+	remaining := GetClientOrders("Coinut", "")
+	if len(remaining) != 1 || remaining[0].ClientID != "prune-1" {
+		t.Error("Test Failed - RemoveClientOrders() unexpected remaining orders", remaining)
+	}
+}
+
+func TestMarkClientOrderCancelled(t *testing.T) {
+	_, err := SubmitWithClientID("Huobi", "order-5", func(clientID string) (int64, error) {
+		return 7, nil
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+
+	MarkClientOrderCancelled("HUOBI", "order-5")
+	order, err := GetOrderByClientID("Huobi", "order-5")
+	if err != nil || order.Status != ClientOrderCancelled {
+		t.Error("Test Failed - MarkClientOrderCancelled() status not updated", err)
+	}
+
+	// Orders submitted before a restart are not in the registry
+	MarkClientOrderCancelled("Huobi", "order-6")
+}
+
+func TestGetClientOrders(t *testing.T) {
+	submit := func(clientID string) (int64, error) {
+		return 42, nil
+	}
+
+	for _, clientID := range []string{"open-1", "open-2", "closed-1"} {
+		_, err := SubmitWithClientID("Gemini", clientID, submit)
+		if err != nil {
+			t.Fatal("Test Failed - SubmitWithClientID() error", err)
+		}
+	}
+	MarkClientOrderCancelled("Gemini", "closed-1")
+
+	if len(GetClientOrders("gemini", "")) != 3 {
+		t.Error("Test Failed - GetClientOrders() expected all orders")
+	}
+
+	open := GetClientOrders("GEMINI", ClientOrderSubmitted)
+	if len(open) != 2 {
+		t.Errorf("Test Failed - GetClientOrders() expected 2 submitted orders received %d",
+			len(open))
+	}
+
+	if len(GetClientOrders("ANX", "")) != 0 {
+		t.Error("Test Failed - GetClientOrders() unexpected orders for exchange")
+	}
+}
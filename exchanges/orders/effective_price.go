@@ -0,0 +1,53 @@
+package orders
+
+import (
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+// EffectivePrice returns the price per unit paid by a buy or received by a
+// sell at price once the fee rate and the expected slippage, in basis points,
+// are included, so prices of venues with different fees and depth compare
+// like for like. Buys pay more than the price and sells receive less
+func EffectivePrice(side string, price, feeRate, slippageBPS float64) float64 {
+	if common.StringToUpper(side) == FillBuy {
+		return price * (1 + slippageBPS/10000) * (1 + feeRate)
+	}
+	return price * (1 - slippageBPS/10000) * (1 - feeRate)
+}
+
+// ExpectedSlippage returns the slippage in basis points expected by an order
+// for amount walking the levels of the orderbook from the top of the book,
+// zero when the book is empty on the side executed against. Amounts beyond
+// the depth of the book are priced at the depth available
+func ExpectedSlippage(side string, amount float64, ob orderbook.Base) float64 {
+	fill, err := SlippageConfig{Model: SlippageOrderbookWalk}.Simulate(side, amount,
+		SimulationMarket{Orderbook: ob})
+	if err != nil {
+		return 0
+	}
+	return fill.Slippage
+}
+
+// EffectivePrice returns the effective price of an order for the side on the
+// venue, including its fee rate and expected slippage, zero when the venue
+// has no price on the side
+func (v *Venue) EffectivePrice(side string) float64 {
+	price := v.Bid
+	if common.StringToUpper(side) == FillBuy {
+		price = v.Ask
+	}
+	if price <= 0 {
+		return 0
+	}
+	return EffectivePrice(side, price, v.FeeRate, v.Slippage)
+}
+
+// FillEffectivePrice returns the price per unit a fill paid or received once
+// its fee is included
+func FillEffectivePrice(f *Fill) float64 {
+	if f.Amount <= 0 || f.Price <= 0 {
+		return 0
+	}
+	return EffectivePrice(f.Side, f.Price, f.Fee/(f.Amount*f.Price), 0)
+}
@@ -0,0 +1,59 @@
+package orders
+
+import (
+	"math"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+func TestEffectivePrice(t *testing.T) {
+	if p := EffectivePrice("buy", 100, 0.01, 10); math.Abs(p-100*1.001*1.01) > 1e-9 {
+		t.Errorf("Test Failed - EffectivePrice() unexpected buy price %v", p)
+	}
+
+	if p := EffectivePrice("sell", 100, 0.01, 10); math.Abs(p-100*0.999*0.99) > 1e-9 {
+		t.Errorf("Test Failed - EffectivePrice() unexpected sell price %v", p)
+	}
+
+	v := Venue{Exchange: "Bitstamp", Bid: 0, Ask: 100, FeeRate: 0.01}
+	if p := v.EffectivePrice(FillBuy); p != 101 {
+		t.Errorf("Test Failed - Venue.EffectivePrice() unexpected buy price %v", p)
+	}
+	if p := v.EffectivePrice(FillSell); p != 0 {
+		t.Errorf("Test Failed - Venue.EffectivePrice() expected no sell price, got %v", p)
+	}
+}
+
+func TestExpectedSlippage(t *testing.T) {
+	ob := orderbook.Base{
+		Asks: []orderbook.Item{{Price: 100, Amount: 1}, {Price: 102, Amount: 1}},
+		Bids: []orderbook.Item{{Price: 99, Amount: 2}},
+	}
+
+	if s := ExpectedSlippage("buy", 1, ob); s != 0 {
+		t.Errorf("Test Failed - ExpectedSlippage() expected no slippage at the top of book, got %v", s)
+	}
+
+	if s := ExpectedSlippage("buy", 2, ob); math.Abs(s-100) > 1e-9 {
+		t.Errorf("Test Failed - ExpectedSlippage() unexpected buy slippage %v", s)
+	}
+
+	if s := ExpectedSlippage("sell", 1, orderbook.Base{}); s != 0 {
+		t.Errorf("Test Failed - ExpectedSlippage() expected no slippage for an empty book, got %v", s)
+	}
+}
+
+func TestFillEffectivePrice(t *testing.T) {
+	if p := FillEffectivePrice(&Fill{Side: FillBuy, Amount: 2, Price: 100, Fee: 2}); p != 101 {
+		t.Errorf("Test Failed - FillEffectivePrice() unexpected buy price %v", p)
+	}
+
+	if p := FillEffectivePrice(&Fill{Side: FillSell, Amount: 2, Price: 100, Fee: 2}); p != 99 {
+		t.Errorf("Test Failed - FillEffectivePrice() unexpected sell price %v", p)
+	}
+
+	if p := FillEffectivePrice(&Fill{Side: FillSell}); p != 0 {
+		t.Errorf("Test Failed - FillEffectivePrice() expected zero for an empty fill, got %v", p)
+	}
+}
@@ -0,0 +1,279 @@
+package orders
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Fill sides
+const (
+	FillBuy  = "BUY"
+	FillSell = "SELL"
+)
+
+// Fill liquidity flags, fills whose liquidity the exchange doesn't report are
+// left blank
+const (
+	FillMaker = "MAKER"
+	FillTaker = "TAKER"
+)
+
+// Vars for the fill record store
+var (
+	fills     []Fill
+	fillIDs   = make(map[string]bool)
+	fillsFile string
+	fillsMtx  sync.Mutex
+
+	// ErrInvalidFill is returned when a fill is missing required details
+	ErrInvalidFill = errors.New("fill requires an exchange, pair, side, amount and price")
+	// ErrDuplicateFill is returned when a fill with the same exchange trade ID
+	// has already been recorded, such as a fill received over a private
+	// websocket and again by polling
+	ErrDuplicateFill = errors.New("fill already recorded")
+)
+
+// Fill holds an executed trade against an order, in the same form whichever
+// exchange reported it. Fees are denominated in the quote currency of the
+// pair. TradeID is the exchange trade ID used to drop fills reported twice,
+// ClientID the client order ID of the order and Liquidity whether the fill
+// made or took liquidity. StrategyID attributes the fill to the strategy
+// which placed the order, fills recorded without one inherit the strategy the
+// order was tagged with
+type Fill struct {
+	Exchange   string
+	Pair       string
+	Side       string
+	OrderID    int64
+	ClientID   string
+	TradeID    string
+	Amount     float64
+	Price      float64
+	Fee        float64
+	Liquidity  string
+	Timestamp  time.Time
+	StrategyID string
+}
+
+// NormaliseFill returns a fill with the side and liquidity flag in their
+// standard forms, accepting the bid, ask, maker and taker spellings used
+// across exchanges, and the client order ID and strategy filled in from the
+// order registries when missing
+func NormaliseFill(f Fill) (Fill, error) {
+	switch common.StringToLower(f.Side) {
+	case "buy", "bid", "b":
+		f.Side = FillBuy
+	case "sell", "ask", "s":
+		f.Side = FillSell
+	default:
+		return f, ErrInvalidFill
+	}
+
+	if f.Exchange == "" || f.Pair == "" || f.Amount <= 0 || f.Price <= 0 {
+		return f, ErrInvalidFill
+	}
+	f.Pair = common.StringToUpper(f.Pair)
+
+	switch common.StringToLower(f.Liquidity) {
+	case "maker", "m", "add", "added":
+		f.Liquidity = FillMaker
+	case "taker", "t", "remove", "removed":
+		f.Liquidity = FillTaker
+	default:
+		f.Liquidity = ""
+	}
+
+	if f.Timestamp.IsZero() {
+		f.Timestamp = time.Now()
+	}
+
+	if f.OrderID != 0 {
+		if f.ClientID == "" {
+			f.ClientID = getOrderClientID(f.Exchange, f.OrderID)
+		}
+		if f.StrategyID == "" {
+			f.StrategyID = GetOrderStrategy(f.Exchange, f.OrderID)
+		}
+	}
+	return f, nil
+}
+
+// RecordFill normalises and records an executed trade, persisting it when a
+// fills file has been loaded. ErrDuplicateFill is returned when a fill with
+// the same trade ID was already recorded
+func RecordFill(f Fill) error {
+	f, err := NormaliseFill(f)
+	if err != nil {
+		return err
+	}
+
+	fillsMtx.Lock()
+	if f.TradeID != "" {
+		key := fillKey(f)
+		if fillIDs[key] {
+			fillsMtx.Unlock()
+			return ErrDuplicateFill
+		}
+		fillIDs[key] = true
+	}
+	fills = append(fills, f)
+	appendFill(f)
+	fillsMtx.Unlock()
+
+	recordSpreadFill(f)
+	return nil
+}
+
+// GetOrderFilledAmount returns the amount of an exchange order filled by the
+// recorded fills
+func GetOrderFilledAmount(exchange string, orderID int64) float64 {
+	fillsMtx.Lock()
+	defer fillsMtx.Unlock()
+
+	var filled float64
+	for x := range fills {
+		if fills[x].OrderID == orderID &&
+			common.StringToLower(fills[x].Exchange) == common.StringToLower(exchange) {
+			filled += fills[x].Amount
+		}
+	}
+	return filled
+}
+
+// LoadFills loads the fills persisted as JSON lines to path, replacing the
+// recorded fills, and persists fills recorded from then on to it. A missing
+// file loads no fills
+func LoadFills(path string) error {
+	fillsMtx.Lock()
+	defer fillsMtx.Unlock()
+
+	fillsFile = path
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var loaded []Fill
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 4096), len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var f Fill
+		err = common.JSONDecode(line, &f)
+		if err != nil {
+			return err
+		}
+		loaded = append(loaded, f)
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	fills = loaded
+	fillIDs = make(map[string]bool)
+	for x := range fills {
+		if fills[x].TradeID != "" {
+			fillIDs[fillKey(fills[x])] = true
+		}
+	}
+	return nil
+}
+
+// appendFill appends a fill to the fills file if one has been loaded,
+// fillsMtx must be held by the caller
+func appendFill(f Fill) {
+	if fillsFile == "" {
+		return
+	}
+
+	data, err := common.JSONEncode(f)
+	if err != nil {
+		log.Printf("Unable to persist fill to %s. Err: %s", fillsFile, err)
+		return
+	}
+
+	file, err := os.OpenFile(fillsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err == nil {
+		_, err = file.Write(append(data, '\n'))
+		file.Close()
+	}
+	if err != nil {
+		log.Printf("Unable to persist fill to %s. Err: %s", fillsFile, err)
+	}
+}
+
+// fillKey returns the key of a fill's trade ID, unique across exchanges
+func fillKey(f Fill) string {
+	return common.StringToLower(f.Exchange) + "|" + f.TradeID
+}
+
+// getOrderClientID returns the client order ID of an exchange order from the
+// client order registry, or a blank string if it isn't registered
+func getOrderClientID(exchange string, orderID int64) string {
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+
+	for _, order := range clientOrders[common.StringToLower(exchange)] {
+		if order.OrderID == orderID {
+			return order.ClientID
+		}
+	}
+	return ""
+}
+
+// GetFills returns the recorded fills for an exchange and pair executed before
+// the end time in time order, a blank exchange or pair matches all
+func GetFills(exchange, pair string, end time.Time) []Fill {
+	fillsMtx.Lock()
+	defer fillsMtx.Unlock()
+
+	var result []Fill
+	for x := range fills {
+		if exchange != "" && common.StringToLower(fills[x].Exchange) != common.StringToLower(exchange) {
+			continue
+		}
+		if pair != "" && common.StringToUpper(fills[x].Pair) != common.StringToUpper(pair) {
+			continue
+		}
+		if !end.IsZero() && fills[x].Timestamp.After(end) {
+			continue
+		}
+		result = append(result, fills[x])
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
+
+// RemoveFills removes fills executed before the supplied time
+func RemoveFills(before time.Time) {
+	fillsMtx.Lock()
+	defer fillsMtx.Unlock()
+
+	var kept []Fill
+	for x := range fills {
+		if !fills[x].Timestamp.Before(before) {
+			kept = append(kept, fills[x])
+		} else if fills[x].TradeID != "" {
+			delete(fillIDs, fillKey(fills[x]))
+		}
+	}
+	fills = kept
+}
@@ -0,0 +1,94 @@
+package orders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNormaliseFill(t *testing.T) {
+	f, err := NormaliseFill(Fill{Exchange: "Bitfinex", Pair: "btcusd", Side: "ask",
+		Amount: 1, Price: 100, Liquidity: "m"})
+	if err != nil {
+		t.Fatal("Test Failed - NormaliseFill() error", err)
+	}
+	if f.Side != FillSell || f.Pair != "BTCUSD" || f.Liquidity != FillMaker || f.Timestamp.IsZero() {
+		t.Errorf("Test Failed - NormaliseFill() unexpected fill %+v", f)
+	}
+
+	f, _ = NormaliseFill(Fill{Exchange: "Bitfinex", Pair: "BTCUSD", Side: "bid",
+		Amount: 1, Price: 100, Liquidity: "unknown"})
+	if f.Side != FillBuy || f.Liquidity != "" {
+		t.Errorf("Test Failed - NormaliseFill() unexpected fill %+v", f)
+	}
+
+	_, err = NormaliseFill(Fill{Exchange: "Bitfinex", Pair: "BTCUSD", Side: "buy", Price: 100})
+	if err != ErrInvalidFill {
+		t.Error("Test Failed - NormaliseFill() zero amount error", err)
+	}
+}
+
+func TestNormaliseFillClientID(t *testing.T) {
+	_, err := SubmitWithClientID("Bitfinex", "fill-client-1", func(clientID string) (int64, error) {
+		return 7001, nil
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+
+	f, err := NormaliseFill(Fill{Exchange: "bitfinex", Pair: "BTCUSD", Side: "buy",
+		OrderID: 7001, Amount: 1, Price: 100})
+	if err != nil || f.ClientID != "fill-client-1" {
+		t.Errorf("Test Failed - NormaliseFill() client order ID expected fill-client-1, got %s %v",
+			f.ClientID, err)
+	}
+}
+
+func TestLoadFills(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fills")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "fills.jsonl")
+	err = LoadFills(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadFills() missing file error", err)
+	}
+	defer func() {
+		LoadFills("")
+		RemoveFills(time.Now().Add(time.Hour))
+	}()
+
+	fill := Fill{Exchange: "Kraken", Pair: "ETHUSD", Side: "sell", OrderID: 9001,
+		TradeID: "T1", Amount: 0.25, Price: 400, Timestamp: time.Now().Add(-time.Minute)}
+	err = RecordFill(fill)
+	if err != nil {
+		t.Fatal("Test Failed - RecordFill() error", err)
+	}
+	if err = RecordFill(fill); err != ErrDuplicateFill {
+		t.Error("Test Failed - RecordFill() duplicate trade ID error", err)
+	}
+
+	fill.TradeID = "T2"
+	err = RecordFill(fill)
+	if err != nil {
+		t.Fatal("Test Failed - RecordFill() error", err)
+	}
+
+	RemoveFills(time.Now().Add(time.Hour))
+	err = LoadFills(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadFills() error", err)
+	}
+
+	if filled := GetOrderFilledAmount("kraken", 9001); filled != 0.5 {
+		t.Errorf("Test Failed - GetOrderFilledAmount() expected 0.5, got %v", filled)
+	}
+	if err = RecordFill(fill); err != ErrDuplicateFill {
+		t.Error("Test Failed - RecordFill() duplicate trade ID after load error", err)
+	}
+}
@@ -0,0 +1,181 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// maxMaintenanceWindowDuration is the longest a recurring window may last, so
+// occurrences on successive weeks never overlap
+const maxMaintenanceWindowDuration = time.Hour * 24 * 7
+
+// Vars for the maintenance window registry
+var (
+	maintenanceWindows    = make(map[string][]MaintenanceWindow)
+	maintenanceWindowsMtx sync.Mutex
+
+	// ErrInvalidMaintenanceWindow is returned when a maintenance window has
+	// neither a valid start and end nor a valid recurring schedule
+	ErrInvalidMaintenanceWindow = errors.New("maintenance windows require an end after the start, or a start time of HH:MM and a duration up to a week")
+)
+
+// MaintenanceWindow holds a period during which an exchange is under
+// maintenance or trading is blacked out. A one off window runs from Start to
+// End. A recurring window starts at StartTime, as HH:MM in UTC, and lasts
+// Duration, on each of Weekdays such as "Tuesday" or every day when none are
+// set
+type MaintenanceWindow struct {
+	Name      string        `json:"name"`
+	Start     time.Time     `json:"start,omitempty"`
+	End       time.Time     `json:"end,omitempty"`
+	Weekdays  []string      `json:"weekdays,omitempty"`
+	StartTime string        `json:"startTime,omitempty"`
+	Duration  time.Duration `json:"duration,omitempty"`
+}
+
+// MaintenanceError is returned when submitting an order to an exchange during
+// one of its maintenance windows, Until is when submissions resume
+type MaintenanceError struct {
+	Exchange string
+	Window   string
+	Until    time.Time
+}
+
+func (e *MaintenanceError) Error() string {
+	return fmt.Sprintf("%s order submissions paused for maintenance window %s until %s",
+		e.Exchange, e.Window, e.Until.UTC().Format(time.RFC3339))
+}
+
+// Validate checks the window is either a one off window ending after it
+// starts or a recurring window with a valid schedule
+func (w MaintenanceWindow) Validate() error {
+	if w.StartTime == "" {
+		if w.Start.IsZero() || !w.End.After(w.Start) {
+			return ErrInvalidMaintenanceWindow
+		}
+		return nil
+	}
+
+	_, err := w.startOffset()
+	if err != nil || w.Duration <= 0 || w.Duration > maxMaintenanceWindowDuration {
+		return ErrInvalidMaintenanceWindow
+	}
+	_, err = w.weekdays()
+	return err
+}
+
+// ActiveUntil returns the end of the occurrence of the window covering now and
+// whether one does
+func (w MaintenanceWindow) ActiveUntil(now time.Time) (time.Time, bool) {
+	if w.StartTime == "" {
+		if !now.Before(w.Start) && now.Before(w.End) {
+			return w.End, true
+		}
+		return time.Time{}, false
+	}
+
+	offset, err := w.startOffset()
+	if err != nil {
+		return time.Time{}, false
+	}
+	days, err := w.weekdays()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// An occurrence may have started on any of the preceding days of the week
+	for x := 0; x <= 7; x++ {
+		day := midnight.AddDate(0, 0, -x)
+		if len(days) > 0 && !days[day.Weekday()] {
+			continue
+		}
+
+		start := day.Add(offset)
+		end := start.Add(w.Duration)
+		if !now.Before(start) && now.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// startOffset returns the time of day a recurring window starts at
+func (w MaintenanceWindow) startOffset() (time.Duration, error) {
+	t, err := time.Parse("15:04", w.StartTime)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// weekdays returns the days a recurring window occurs on
+func (w MaintenanceWindow) weekdays() (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	for _, name := range w.Weekdays {
+		found := false
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if common.StringToLower(d.String()) == common.StringToLower(name) {
+				days[d] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid maintenance window weekday %s", name)
+		}
+	}
+	return days, nil
+}
+
+// SetMaintenanceWindows sets the maintenance windows of an exchange,
+// replacing any previously set
+func SetMaintenanceWindows(exchange string, windows []MaintenanceWindow) error {
+	for x := range windows {
+		err := windows[x].Validate()
+		if err != nil {
+			return fmt.Errorf("maintenance window %d: %s", x, err)
+		}
+	}
+
+	maintenanceWindowsMtx.Lock()
+	maintenanceWindows[common.StringToLower(exchange)] = append([]MaintenanceWindow(nil), windows...)
+	maintenanceWindowsMtx.Unlock()
+	return nil
+}
+
+// GetMaintenanceWindows returns the maintenance windows of an exchange
+func GetMaintenanceWindows(exchange string) []MaintenanceWindow {
+	maintenanceWindowsMtx.Lock()
+	defer maintenanceWindowsMtx.Unlock()
+	return append([]MaintenanceWindow(nil), maintenanceWindows[common.StringToLower(exchange)]...)
+}
+
+// InMaintenance returns the maintenance window of an exchange covering now
+// which ends last, when it ends and whether the exchange is under maintenance
+func InMaintenance(exchange string, now time.Time) (MaintenanceWindow, time.Time, bool) {
+	var active MaintenanceWindow
+	var until time.Time
+	for _, w := range GetMaintenanceWindows(exchange) {
+		end, ok := w.ActiveUntil(now)
+		if ok && end.After(until) {
+			active = w
+			until = end
+		}
+	}
+	return active, until, !until.IsZero()
+}
+
+// CheckMaintenance returns a *MaintenanceError when an exchange is under
+// maintenance, order submissions resume automatically once it ends
+func CheckMaintenance(exchange string, now time.Time) error {
+	w, until, ok := InMaintenance(exchange, now)
+	if !ok {
+		return nil
+	}
+	return &MaintenanceError{Exchange: exchange, Window: w.Name, Until: until}
+}
@@ -0,0 +1,91 @@
+package orders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	invalid := []MaintenanceWindow{
+		{Name: "empty"},
+		{Name: "reversed", Start: time.Unix(2000, 0), End: time.Unix(1000, 0)},
+		{Name: "time", StartTime: "25:00", Duration: time.Hour},
+		{Name: "duration", StartTime: "04:00"},
+		{Name: "weekday", StartTime: "04:00", Duration: time.Hour, Weekdays: []string{"Funday"}},
+	}
+	for x := range invalid {
+		if invalid[x].Validate() == nil {
+			t.Errorf("Test Failed - Validate() accepted invalid window %s", invalid[x].Name)
+		}
+	}
+
+	oneOff := MaintenanceWindow{Name: "upgrade", Start: time.Unix(1000, 0), End: time.Unix(2000, 0)}
+	if end, ok := oneOff.ActiveUntil(time.Unix(1500, 0)); !ok || !end.Equal(oneOff.End) {
+		t.Error("Test Failed - ActiveUntil() one off window not active")
+	}
+	if _, ok := oneOff.ActiveUntil(time.Unix(2000, 0)); ok {
+		t.Error("Test Failed - ActiveUntil() one off window active at its end")
+	}
+
+	// Tuesday 22:00 to Wednesday 02:00 UTC
+	weekly := MaintenanceWindow{Name: "weekly", StartTime: "22:00", Duration: time.Hour * 4,
+		Weekdays: []string{"tuesday"}}
+	if err := weekly.Validate(); err != nil {
+		t.Fatal("Test Failed - Validate() error", err)
+	}
+	wednesday := time.Date(2019, 1, 2, 1, 30, 0, 0, time.UTC)
+	end, ok := weekly.ActiveUntil(wednesday)
+	if !ok || !end.Equal(time.Date(2019, 1, 2, 2, 0, 0, 0, time.UTC)) {
+		t.Error("Test Failed - ActiveUntil() window spanning midnight not active", end, ok)
+	}
+	if _, ok = weekly.ActiveUntil(wednesday.Add(time.Hour)); ok {
+		t.Error("Test Failed - ActiveUntil() window active after its end")
+	}
+	if _, ok = weekly.ActiveUntil(wednesday.AddDate(0, 0, 1)); ok {
+		t.Error("Test Failed - ActiveUntil() window active on the wrong weekday")
+	}
+
+	daily := MaintenanceWindow{Name: "daily", StartTime: "01:00", Duration: time.Minute * 10}
+	if _, ok = daily.ActiveUntil(time.Date(2019, 1, 5, 1, 5, 0, 0, time.UTC)); !ok {
+		t.Error("Test Failed - ActiveUntil() daily window not active")
+	}
+}
+
+func TestCheckMaintenance(t *testing.T) {
+	defer SetMaintenanceWindows("Bitstamp", nil)
+
+	if err := SetMaintenanceWindows("Bitstamp", []MaintenanceWindow{{Name: "bad"}}); err == nil {
+		t.Error("Test Failed - SetMaintenanceWindows() accepted an invalid window")
+	}
+
+	now := time.Now()
+	err := SetMaintenanceWindows("Bitstamp", []MaintenanceWindow{
+		{Name: "short", Start: now.Add(-time.Minute), End: now.Add(time.Minute)},
+		{Name: "long", Start: now.Add(-time.Minute), End: now.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SetMaintenanceWindows() error", err)
+	}
+
+	maintenanceErr, ok := CheckMaintenance("BITSTAMP", now).(*MaintenanceError)
+	if !ok || maintenanceErr.Window != "long" || !maintenanceErr.Until.Equal(now.Add(time.Hour)) {
+		t.Error("Test Failed - CheckMaintenance() expected maintenance error", maintenanceErr)
+	}
+	if err = CheckMaintenance("Bitstamp", now.Add(time.Hour*2)); err != nil {
+		t.Error("Test Failed - CheckMaintenance() submissions not resumed", err)
+	}
+	if err = CheckMaintenance("Gemini", now); err != nil {
+		t.Error("Test Failed - CheckMaintenance() unexpected error", err)
+	}
+
+	p := pair.NewCurrencyPair("BTC", "USD")
+	route, err := RouteOrder("buy", 1, []Venue{
+		{Exchange: "Bitstamp", Pair: p, Bid: 99, Ask: 100},
+		{Exchange: "Gemini", Pair: p, Bid: 100, Ask: 102},
+	})
+	if err != nil || route.Venue.Exchange != "Gemini" {
+		t.Error("Test Failed - RouteOrder() routed to a venue under maintenance", route, err)
+	}
+}
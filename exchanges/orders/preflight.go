@@ -0,0 +1,263 @@
+package orders
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Pre-flight rejection reasons
+const (
+	PreflightAmountBelowMinimum   = "AMOUNT_BELOW_MINIMUM"
+	PreflightAmountAboveMaximum   = "AMOUNT_ABOVE_MAXIMUM"
+	PreflightAmountStep           = "AMOUNT_STEP"
+	PreflightPriceBelowMinimum    = "PRICE_BELOW_MINIMUM"
+	PreflightPriceAboveMaximum    = "PRICE_ABOVE_MAXIMUM"
+	PreflightPriceStep            = "PRICE_STEP"
+	PreflightNotionalBelowMinimum = "NOTIONAL_BELOW_MINIMUM"
+	PreflightInsufficientBalance  = "INSUFFICIENT_BALANCE"
+)
+
+// stepTolerance allows for floating point error when checking step multiples
+const stepTolerance = 1e-9
+
+// Vars for the symbol rules cache
+var (
+	symbolRules    = make(map[string]SymbolRules)
+	symbolRulesMtx sync.Mutex
+)
+
+// SymbolRules holds the order size and price rules for an exchange pair, zero
+// values are not enforced. Notional is the amount multiplied by the price
+type SymbolRules struct {
+	MinAmount   float64 `json:"minAmount"`
+	MaxAmount   float64 `json:"maxAmount"`
+	AmountStep  float64 `json:"amountStep"`
+	MinPrice    float64 `json:"minPrice"`
+	MaxPrice    float64 `json:"maxPrice"`
+	PriceStep   float64 `json:"priceStep"`
+	MinNotional float64 `json:"minNotional"`
+}
+
+// PreflightOrder holds the details of an order to validate before it is
+// submitted. A zero price is treated as a market order, skipping the price and
+// notional checks
+type PreflightOrder struct {
+	Exchange string
+	Pair     pair.CurrencyPair
+	Side     string
+	Amount   float64
+	Price    float64
+}
+
+// PreflightError is returned when an order fails pre-flight validation. Limit
+// is the rule which was broken and Suggested is the closest valid value, for
+// the amount or price depending on the reason, so callers can adjust and
+// resubmit without parsing exchange rejections
+type PreflightError struct {
+	Reason    string
+	Limit     float64
+	Suggested float64
+}
+
+func (e *PreflightError) Error() string {
+	return fmt.Sprintf("order failed pre-flight validation: %s, limit %v, suggested %v",
+		e.Reason, e.Limit, e.Suggested)
+}
+
+// BalanceFunc returns the cached balance of a currency on an exchange and
+// whether the balance is known
+type BalanceFunc func(exchange, currency string) (float64, bool)
+
+func symbolRulesKey(exchange string, p pair.CurrencyPair) string {
+	return common.StringToLower(exchange) + " " +
+		p.FirstCurrency.Upper().String() + p.SecondCurrency.Upper().String()
+}
+
+// SetSymbolRules caches the order rules for an exchange pair
+func SetSymbolRules(exchange string, p pair.CurrencyPair, rules SymbolRules) {
+	symbolRulesMtx.Lock()
+	symbolRules[symbolRulesKey(exchange, p)] = rules
+	symbolRulesMtx.Unlock()
+}
+
+// GetSymbolRules returns the cached order rules for an exchange pair
+func GetSymbolRules(exchange string, p pair.CurrencyPair) (SymbolRules, bool) {
+	symbolRulesMtx.Lock()
+	defer symbolRulesMtx.Unlock()
+	rules, ok := symbolRules[symbolRulesKey(exchange, p)]
+	return rules, ok
+}
+
+// SaveSymbolRules persists the cached symbol rules to the file at path so they
+// can be restored on the next start
+func SaveSymbolRules(path string) error {
+	symbolRulesMtx.Lock()
+	data, err := common.JSONEncode(symbolRules)
+	symbolRulesMtx.Unlock()
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(path, data)
+}
+
+// LoadSymbolRules restores the symbol rules persisted to the file at path,
+// rules already cached are never replaced
+func LoadSymbolRules(path string) (int, error) {
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var stored map[string]SymbolRules
+	err = common.JSONDecode(data, &stored)
+	if err != nil {
+		return 0, err
+	}
+
+	symbolRulesMtx.Lock()
+	defer symbolRulesMtx.Unlock()
+
+	var restored int
+	for key, rules := range stored {
+		if _, ok := symbolRules[key]; ok {
+			continue
+		}
+		symbolRules[key] = rules
+		restored++
+	}
+	return restored, nil
+}
+
+// roundDownToStep rounds a value down to a multiple of the step
+func roundDownToStep(value, step float64) float64 {
+	return math.Floor(value/step+stepTolerance) * step
+}
+
+// isStepMultiple returns whether a value is a multiple of the step
+func isStepMultiple(value, step float64) bool {
+	steps := value / step
+	return math.Abs(steps-math.Round(steps)) < stepTolerance*math.Max(1, steps)
+}
+
+// ValidateOrder checks an order against the cached rules for the exchange pair
+// and, if a balance function is supplied, the cached account balance. Orders
+// for pairs without cached rules are only checked against the balance. A
+// *PreflightError is returned for the first rule the order breaks
+func ValidateOrder(o PreflightOrder, balance BalanceFunc) error {
+	rules, _ := GetSymbolRules(o.Exchange, o.Pair)
+
+	if o.Amount < rules.MinAmount || o.Amount <= 0 {
+		return &PreflightError{
+			Reason:    PreflightAmountBelowMinimum,
+			Limit:     rules.MinAmount,
+			Suggested: rules.MinAmount,
+		}
+	}
+
+	if rules.MaxAmount > 0 && o.Amount > rules.MaxAmount {
+		return &PreflightError{
+			Reason:    PreflightAmountAboveMaximum,
+			Limit:     rules.MaxAmount,
+			Suggested: rules.MaxAmount,
+		}
+	}
+
+	if rules.AmountStep > 0 && !isStepMultiple(o.Amount, rules.AmountStep) {
+		return &PreflightError{
+			Reason:    PreflightAmountStep,
+			Limit:     rules.AmountStep,
+			Suggested: roundDownToStep(o.Amount, rules.AmountStep),
+		}
+	}
+
+	if o.Price > 0 {
+		if o.Price < rules.MinPrice {
+			return &PreflightError{
+				Reason:    PreflightPriceBelowMinimum,
+				Limit:     rules.MinPrice,
+				Suggested: rules.MinPrice,
+			}
+		}
+
+		if rules.MaxPrice > 0 && o.Price > rules.MaxPrice {
+			return &PreflightError{
+				Reason:    PreflightPriceAboveMaximum,
+				Limit:     rules.MaxPrice,
+				Suggested: rules.MaxPrice,
+			}
+		}
+
+		if rules.PriceStep > 0 && !isStepMultiple(o.Price, rules.PriceStep) {
+			return &PreflightError{
+				Reason:    PreflightPriceStep,
+				Limit:     rules.PriceStep,
+				Suggested: roundDownToStep(o.Price, rules.PriceStep),
+			}
+		}
+
+		if o.Amount*o.Price < rules.MinNotional {
+			suggested := rules.MinNotional / o.Price
+			if rules.AmountStep > 0 {
+				suggested = math.Ceil(suggested/rules.AmountStep-stepTolerance) * rules.AmountStep
+			}
+			return &PreflightError{
+				Reason:    PreflightNotionalBelowMinimum,
+				Limit:     rules.MinNotional,
+				Suggested: suggested,
+			}
+		}
+	}
+
+	if balance == nil {
+		return nil
+	}
+	return validateBalance(o, rules, balance)
+}
+
+// validateBalance checks a sell order against the base currency balance and a
+// limit buy order against the quote currency balance, suggesting the largest
+// amount the balance allows
+func validateBalance(o PreflightOrder, rules SymbolRules, balance BalanceFunc) error {
+	var currency string
+	var required float64
+	switch common.StringToUpper(o.Side) {
+	case FillSell:
+		currency = o.Pair.FirstCurrency.Upper().String()
+		required = o.Amount
+	case FillBuy:
+		if o.Price <= 0 {
+			return nil
+		}
+		currency = o.Pair.SecondCurrency.Upper().String()
+		required = o.Amount * o.Price
+	default:
+		return nil
+	}
+
+	available, ok := balance(o.Exchange, currency)
+	if !ok || available >= required {
+		return nil
+	}
+
+	suggested := available
+	if o.Price > 0 && common.StringToUpper(o.Side) == FillBuy {
+		suggested = available / o.Price
+	}
+	if rules.AmountStep > 0 {
+		suggested = roundDownToStep(suggested, rules.AmountStep)
+	}
+
+	return &PreflightError{
+		Reason:    PreflightInsufficientBalance,
+		Limit:     available,
+		Suggested: suggested,
+	}
+}
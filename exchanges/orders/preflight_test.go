@@ -0,0 +1,129 @@
+package orders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func testBalance(exchange, currency string) (float64, bool) {
+	switch currency {
+	case "BTC":
+		return 0.5, true
+	case "USDT":
+		return 1000, true
+	}
+	return 0, false
+}
+
+func checkPreflightError(t *testing.T, err error, reason string, suggested float64) {
+	t.Helper()
+	preflightErr, ok := err.(*PreflightError)
+	if !ok {
+		t.Fatalf("Test Failed - ValidateOrder() expected %s error, received %v", reason, err)
+	}
+	if preflightErr.Reason != reason {
+		t.Errorf("Test Failed - ValidateOrder() expected reason %s, received %s",
+			reason, preflightErr.Reason)
+	}
+	if suggested-preflightErr.Suggested > 1e-9 || preflightErr.Suggested-suggested > 1e-9 {
+		t.Errorf("Test Failed - ValidateOrder() %s expected suggestion %v, received %v",
+			reason, suggested, preflightErr.Suggested)
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USDT")
+	SetSymbolRules("Binance", p, SymbolRules{
+		MinAmount:   0.001,
+		MaxAmount:   100,
+		AmountStep:  0.001,
+		MinPrice:    0.01,
+		MaxPrice:    100000,
+		PriceStep:   0.01,
+		MinNotional: 10,
+	})
+
+	rules, ok := GetSymbolRules("binance", pair.NewCurrencyPair("btc", "usdt"))
+	if !ok || rules.MinNotional != 10 {
+		t.Fatal("Test Failed - GetSymbolRules() did not return cached rules")
+	}
+
+	order := PreflightOrder{Exchange: "Binance", Pair: p, Side: "Buy", Amount: 0.01, Price: 5000}
+	err := ValidateOrder(order, testBalance)
+	if err != nil {
+		t.Error("Test Failed - ValidateOrder() error", err)
+	}
+
+	order.Amount = 0.0001
+	checkPreflightError(t, ValidateOrder(order, testBalance), PreflightAmountBelowMinimum, 0.001)
+
+	order.Amount = 0.0125
+	checkPreflightError(t, ValidateOrder(order, testBalance), PreflightAmountStep, 0.012)
+
+	order.Amount = 0.01
+	order.Price = 5000.005
+	checkPreflightError(t, ValidateOrder(order, testBalance), PreflightPriceStep, 5000)
+
+	order.Amount = 0.001
+	order.Price = 5000
+	checkPreflightError(t, ValidateOrder(order, testBalance), PreflightNotionalBelowMinimum, 0.002)
+
+	order.Amount = 1
+	checkPreflightError(t, ValidateOrder(order, testBalance), PreflightInsufficientBalance, 0.2)
+
+	order.Side = "Sell"
+	checkPreflightError(t, ValidateOrder(order, testBalance), PreflightInsufficientBalance, 0.5)
+
+	order.Price = 0
+	checkPreflightError(t, ValidateOrder(order, testBalance), PreflightInsufficientBalance, 0.5)
+
+	err = ValidateOrder(order, nil)
+	if err != nil {
+		t.Error("Test Failed - ValidateOrder() without balance error", err)
+	}
+}
+
+func TestSaveLoadSymbolRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "symbolrules")
+	if err != nil {
+		t.Fatal("Test Failed - TempDir() error", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "symbolrules.json")
+
+	p := pair.NewCurrencyPair("XMR", "EUR")
+	SetSymbolRules("WarmCache", p, SymbolRules{MinAmount: 0.1})
+	err = SaveSymbolRules(path)
+	if err != nil {
+		t.Fatal("Test Failed - SaveSymbolRules() error", err)
+	}
+
+	SetSymbolRules("WarmCache", p, SymbolRules{MinAmount: 0.2})
+	_, err = LoadSymbolRules(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadSymbolRules() error", err)
+	}
+
+	rules, ok := GetSymbolRules("warmcache", p)
+	if !ok || rules.MinAmount != 0.2 {
+		t.Errorf("Test Failed - LoadSymbolRules() replaced cached rules %+v", rules)
+	}
+
+	symbolRulesMtx.Lock()
+	delete(symbolRules, symbolRulesKey("WarmCache", p))
+	symbolRulesMtx.Unlock()
+
+	restored, err := LoadSymbolRules(path)
+	if err != nil || restored == 0 {
+		t.Fatal("Test Failed - LoadSymbolRules() error", err)
+	}
+
+	rules, ok = GetSymbolRules("WarmCache", p)
+	if !ok || rules.MinAmount != 0.1 {
+		t.Errorf("Test Failed - LoadSymbolRules() unexpected rules %+v", rules)
+	}
+}
@@ -0,0 +1,185 @@
+package orders
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// ProfitLoss holds the realised profit and loss for an exchange pair over a
+// reporting period. Amounts are denominated in the quote currency of the pair.
+// EffectiveBuyPrice and EffectiveSellPrice are the volume weighted prices
+// paid and received over the period once fees are included
+type ProfitLoss struct {
+	Strategy           string  `json:"strategy,omitempty"`
+	Exchange           string  `json:"exchange"`
+	Pair               string  `json:"pair"`
+	Fills              int     `json:"fills"`
+	BuyVolume          float64 `json:"buyVolume"`
+	SellVolume         float64 `json:"sellVolume"`
+	EffectiveBuyPrice  float64 `json:"effectiveBuyPrice"`
+	EffectiveSellPrice float64 `json:"effectiveSellPrice"`
+	GrossPnL           float64 `json:"grossPnL"`
+	Fees               float64 `json:"fees"`
+	NetPnL             float64 `json:"netPnL"`
+	OpenPosition       float64 `json:"openPosition"`
+	AverageCost        float64 `json:"averageCost"`
+	PeriodStarted      int64   `json:"periodStarted"`
+	PeriodEnded        int64   `json:"periodEnded"`
+}
+
+// position tracks an open position using the average cost method
+type position struct {
+	amount      float64
+	averageCost float64
+}
+
+// apply adds a fill to the position, returning the profit realised by any
+// amount the fill closed
+func (p *position) apply(side string, amount, price float64) float64 {
+	direction := 1.0
+	if side == FillSell {
+		direction = -1
+	}
+
+	var realised float64
+	if p.amount*direction < 0 {
+		closed := math.Min(amount, math.Abs(p.amount))
+		// Closing a long profits when the price rises, closing a short when
+		// it falls
+		realised = (price - p.averageCost) * closed * -direction
+		p.amount += closed * direction
+		amount -= closed
+		if p.amount == 0 {
+			p.averageCost = 0
+		}
+	}
+
+	if amount > 0 {
+		total := math.Abs(p.amount) + amount
+		p.averageCost = (math.Abs(p.amount)*p.averageCost + amount*price) / total
+		p.amount += amount * direction
+	}
+	return realised
+}
+
+// GetProfitLossReport returns the realised profit and loss per exchange pair
+// for fills executed between the start and end times. Fills prior to the start
+// time establish the cost basis but are not reported
+func GetProfitLossReport(start, end time.Time) []ProfitLoss {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return buildProfitLossReport(GetFills("", "", end), start, end, false)
+}
+
+// buildProfitLossReport returns the realised profit and loss per exchange pair
+// for the fills executed from the start time, optionally tracking positions
+// and reporting separately per strategy
+func buildProfitLossReport(recorded []Fill, start, end time.Time, byStrategy bool) []ProfitLoss {
+	positions := make(map[string]*position)
+	reports := make(map[string]*ProfitLoss)
+	for x := range recorded {
+		key := common.StringToLower(recorded[x].Exchange) + " " +
+			common.StringToUpper(recorded[x].Pair)
+		if byStrategy {
+			key = recorded[x].StrategyID + " " + key
+		}
+		pos, ok := positions[key]
+		if !ok {
+			pos = &position{}
+			positions[key] = pos
+		}
+
+		realised := pos.apply(recorded[x].Side, recorded[x].Amount, recorded[x].Price)
+		if recorded[x].Timestamp.Before(start) {
+			continue
+		}
+
+		report, ok := reports[key]
+		if !ok {
+			report = &ProfitLoss{
+				Exchange:      recorded[x].Exchange,
+				Pair:          common.StringToUpper(recorded[x].Pair),
+				PeriodStarted: start.Unix(),
+				PeriodEnded:   end.Unix(),
+			}
+			if byStrategy {
+				report.Strategy = recorded[x].StrategyID
+			}
+			reports[key] = report
+		}
+
+		report.Fills++
+		report.GrossPnL += realised
+		report.Fees += recorded[x].Fee
+		// Effective prices are summed by value until the volumes are known
+		value := FillEffectivePrice(&recorded[x]) * recorded[x].Amount
+		if recorded[x].Side == FillBuy {
+			report.BuyVolume += recorded[x].Amount
+			report.EffectiveBuyPrice += value
+		} else {
+			report.SellVolume += recorded[x].Amount
+			report.EffectiveSellPrice += value
+		}
+	}
+
+	var result []ProfitLoss
+	for key, report := range reports {
+		if report.BuyVolume > 0 {
+			report.EffectiveBuyPrice /= report.BuyVolume
+		}
+		if report.SellVolume > 0 {
+			report.EffectiveSellPrice /= report.SellVolume
+		}
+		report.NetPnL = report.GrossPnL - report.Fees
+		report.OpenPosition = positions[key].amount
+		report.AverageCost = positions[key].averageCost
+		result = append(result, *report)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Strategy != result[j].Strategy {
+			return result[i].Strategy < result[j].Strategy
+		}
+		if result[i].Exchange != result[j].Exchange {
+			return result[i].Exchange < result[j].Exchange
+		}
+		return result[i].Pair < result[j].Pair
+	})
+	return result
+}
+
+// FormatProfitLossReport returns a human readable summary of a profit and loss
+// report suitable for the communication mediums
+func FormatProfitLossReport(report []ProfitLoss) string {
+	if len(report) == 0 {
+		return "No fills recorded for the reporting period."
+	}
+
+	var lines []string
+	for x := range report {
+		var strategy string
+		if report[x].Strategy != "" {
+			strategy = "[" + report[x].Strategy + "] "
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%s%s %s: %d fills, realised P&L %.8f (gross %.8f, fees %.8f), effective buy %.8f sell %.8f, open position %.8f @ %.8f",
+			strategy,
+			report[x].Exchange,
+			report[x].Pair,
+			report[x].Fills,
+			report[x].NetPnL,
+			report[x].GrossPnL,
+			report[x].Fees,
+			report[x].EffectiveBuyPrice,
+			report[x].EffectiveSellPrice,
+			report[x].OpenPosition,
+			report[x].AverageCost,
+		))
+	}
+	return common.JoinStrings(lines, "\n")
+}
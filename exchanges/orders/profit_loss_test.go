@@ -0,0 +1,66 @@
+package orders
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRecordFill(t *testing.T) {
+	err := RecordFill(Fill{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "hold", Amount: 1, Price: 1})
+	if err != ErrInvalidFill {
+		t.Error("Test Failed - RecordFill() invalid side error", err)
+	}
+
+	err = RecordFill(Fill{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "buy"})
+	if err != ErrInvalidFill {
+		t.Error("Test Failed - RecordFill() zero amount error", err)
+	}
+}
+
+func TestGetProfitLossReport(t *testing.T) {
+	now := time.Now()
+	defer RemoveFills(now.Add(time.Hour))
+
+	testFills := []Fill{
+		{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "buy", Amount: 2, Price: 100, Fee: 1, Timestamp: now.Add(-time.Hour)},
+		{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "buy", Amount: 2, Price: 200, Fee: 1, Timestamp: now.Add(-time.Minute * 3)},
+		{Exchange: "Bitstamp", Pair: "btcusd", Side: "sell", Amount: 3, Price: 250, Fee: 2, Timestamp: now.Add(-time.Minute * 2)},
+		{Exchange: "Gemini", Pair: "ETHUSD", Side: "sell", Amount: 1, Price: 500, Timestamp: now.Add(-time.Minute * 2)},
+		{Exchange: "Gemini", Pair: "ETHUSD", Side: "buy", Amount: 3, Price: 400, Timestamp: now.Add(-time.Minute)},
+	}
+	for x := range testFills {
+		err := RecordFill(testFills[x])
+		if err != nil {
+			t.Fatal("Test Failed - RecordFill() error", err)
+		}
+	}
+
+	report := GetProfitLossReport(now.Add(-time.Minute*5), now)
+	if len(report) != 2 {
+		t.Fatalf("Test Failed - GetProfitLossReport() expected 2 pairs, got %d", len(report))
+	}
+
+	// Cost basis of 150 from the fill prior to the reporting period
+	bitstamp := report[0]
+	if bitstamp.Exchange != "Bitstamp" || bitstamp.Fills != 2 ||
+		bitstamp.GrossPnL != 300 || bitstamp.Fees != 3 || bitstamp.NetPnL != 297 ||
+		bitstamp.OpenPosition != 1 || bitstamp.AverageCost != 150 {
+		t.Errorf("Test Failed - GetProfitLossReport() unexpected Bitstamp report %+v", bitstamp)
+	}
+	if bitstamp.EffectiveBuyPrice != 200.5 || math.Abs(bitstamp.EffectiveSellPrice-(250-2.0/3)) > 1e-9 {
+		t.Errorf("Test Failed - GetProfitLossReport() unexpected Bitstamp effective prices %v %v",
+			bitstamp.EffectiveBuyPrice, bitstamp.EffectiveSellPrice)
+	}
+
+	// Short closed at a profit, remaining buy opens a long position
+	gemini := report[1]
+	if gemini.GrossPnL != 100 || gemini.OpenPosition != 2 || gemini.AverageCost != 400 ||
+		gemini.BuyVolume != 3 || gemini.SellVolume != 1 {
+		t.Errorf("Test Failed - GetProfitLossReport() unexpected Gemini report %+v", gemini)
+	}
+
+	if FormatProfitLossReport(nil) == "" || FormatProfitLossReport(report) == "" {
+		t.Error("Test Failed - FormatProfitLossReport() empty summary")
+	}
+}
@@ -0,0 +1,222 @@
+package orders
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Vars for client order persistence
+var (
+	clientOrdersFile string
+)
+
+// OpenOrder holds an order reported as open by an exchange. ClientID is blank
+// for exchanges which don't return the client order ID
+type OpenOrder struct {
+	OrderID  int64
+	ClientID string
+}
+
+// Reconciliation holds the result of reconciling the open orders of an
+// exchange against the client order registry. Adopted holds open orders the
+// registry didn't know about, Unknown holds them instead when adoption is
+// disabled, Missing holds submitted orders no longer open on the exchange and
+// Unresolved holds orders with an unknown submission outcome which aren't open
+// on the exchange
+type Reconciliation struct {
+	Exchange   string        `json:"exchange"`
+	Matched    []ClientOrder `json:"matched,omitempty"`
+	Adopted    []ClientOrder `json:"adopted,omitempty"`
+	Unknown    []OpenOrder   `json:"unknown,omitempty"`
+	Missing    []ClientOrder `json:"missing,omitempty"`
+	Unresolved []ClientOrder `json:"unresolved,omitempty"`
+}
+
+// LoadClientOrders loads the client order registry persisted to path, changes
+// to the registry are persisted to path from then on. Orders whose submission
+// was interrupted are loaded as unknown. A missing file is not an error
+func LoadClientOrders(path string) error {
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+
+	clientOrdersFile = path
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var loaded []ClientOrder
+	err = common.JSONDecode(data, &loaded)
+	if err != nil {
+		return err
+	}
+
+	clientOrders = make(map[string]map[string]*ClientOrder)
+	for x := range loaded {
+		exchange := common.StringToLower(loaded[x].Exchange)
+		if _, ok := clientOrders[exchange]; !ok {
+			clientOrders[exchange] = make(map[string]*ClientOrder)
+		}
+		order := loaded[x]
+		if order.Status == ClientOrderPending {
+			// The submission was interrupted and may have reached the
+			// exchange, retries are refused until reconciliation adopts or
+			// clears the order
+			order.Status = ClientOrderUnknown
+		}
+		clientOrders[exchange][order.ClientID] = &order
+	}
+	return nil
+}
+
+// saveClientOrders persists the client order registry if a file has been
+// loaded, clientOrdersMtx must be held by the caller
+func saveClientOrders() {
+	if clientOrdersFile == "" {
+		return
+	}
+
+	var orders []ClientOrder
+	for exchange := range clientOrders {
+		for _, order := range clientOrders[exchange] {
+			orders = append(orders, *order)
+		}
+	}
+
+	data, err := common.JSONEncode(orders)
+	if err == nil {
+		err = common.WriteFile(clientOrdersFile, data)
+	}
+	if err != nil {
+		log.Printf("Unable to persist client orders to %s. Err: %s", clientOrdersFile, err)
+	}
+}
+
+// ReconcileOpenOrders matches the open orders reported by an exchange against
+// the submitted orders in the client order registry by client order ID, or by
+// order ID where the exchange doesn't report client order IDs. Orders with an
+// unknown submission outcome found open are marked submitted. Unmatched open
+// orders are adopted into the registry when adopt is set, using their client
+// order ID or one derived from the order ID, and submitted orders which are no
+// longer open on the exchange are marked missing
+func ReconcileOpenOrders(exchange string, open []OpenOrder, adopt bool) Reconciliation {
+	result := Reconciliation{Exchange: exchange}
+	exchange = common.StringToLower(exchange)
+
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+
+	if _, ok := clientOrders[exchange]; !ok {
+		clientOrders[exchange] = make(map[string]*ClientOrder)
+	}
+
+	matched := make(map[string]bool)
+	for x := range open {
+		order := findOpenOrder(exchange, open[x])
+		if order != nil {
+			if order.Status == ClientOrderMissing || order.Status == ClientOrderUnknown {
+				order.Status = ClientOrderSubmitted
+			}
+			if order.OrderID == 0 {
+				order.OrderID = open[x].OrderID
+			}
+			matched[order.ClientID] = true
+			result.Matched = append(result.Matched, *order)
+			continue
+		}
+
+		if !adopt {
+			result.Unknown = append(result.Unknown, open[x])
+			continue
+		}
+
+		clientID := open[x].ClientID
+		if clientID == "" {
+			clientID = "adopted-" + strconv.FormatInt(open[x].OrderID, 10)
+		}
+
+		adopted := &ClientOrder{
+			ClientID:  clientID,
+			Exchange:  exchange,
+			OrderID:   open[x].OrderID,
+			Status:    ClientOrderSubmitted,
+			Submitted: time.Now(),
+			Adopted:   true,
+		}
+		clientOrders[exchange][clientID] = adopted
+		matched[clientID] = true
+		result.Adopted = append(result.Adopted, *adopted)
+	}
+
+	for clientID, order := range clientOrders[exchange] {
+		if matched[clientID] {
+			continue
+		}
+		switch order.Status {
+		case ClientOrderSubmitted:
+			order.Status = ClientOrderMissing
+			result.Missing = append(result.Missing, *order)
+		case ClientOrderUnknown:
+			result.Unresolved = append(result.Unresolved, *order)
+		}
+	}
+
+	saveClientOrders()
+	return result
+}
+
+// ResolveUnknownOrders matches the orders with an unknown submission outcome
+// against the open orders reported by an exchange by client order ID. Orders
+// found open are marked submitted with their exchange order ID and returned,
+// orders not found are left unknown as they may have filled or not reached the
+// exchange at all. Exchanges which don't report client order IDs can't be
+// resolved this way
+func ResolveUnknownOrders(exchange string, open []OpenOrder) []ClientOrder {
+	exchange = common.StringToLower(exchange)
+
+	clientOrdersMtx.Lock()
+	defer clientOrdersMtx.Unlock()
+
+	var resolved []ClientOrder
+	for x := range open {
+		if open[x].ClientID == "" {
+			continue
+		}
+		order, ok := clientOrders[exchange][open[x].ClientID]
+		if !ok || order.Status != ClientOrderUnknown {
+			continue
+		}
+		order.OrderID = open[x].OrderID
+		order.Status = ClientOrderSubmitted
+		resolved = append(resolved, *order)
+	}
+
+	if len(resolved) > 0 {
+		saveClientOrders()
+	}
+	return resolved
+}
+
+// findOpenOrder returns the registry entry for an open order,
+// clientOrdersMtx must be held by the caller
+func findOpenOrder(exchange string, open OpenOrder) *ClientOrder {
+	if open.ClientID != "" {
+		if order, ok := clientOrders[exchange][open.ClientID]; ok {
+			return order
+		}
+	}
+
+	for _, order := range clientOrders[exchange] {
+		if order.OrderID != 0 && order.OrderID == open.OrderID {
+			return order
+		}
+	}
+	return nil
+}
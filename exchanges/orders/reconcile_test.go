@@ -0,0 +1,170 @@
+package orders
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+func TestReconcileOpenOrders(t *testing.T) {
+	submit := func(orderID int64) func(string) (int64, error) {
+		return func(clientID string) (int64, error) {
+			return orderID, nil
+		}
+	}
+
+	_, err := SubmitWithClientID("Poloniex", "reconcile-1", submit(1))
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+	_, err = SubmitWithClientID("Poloniex", "reconcile-2", submit(2))
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+
+	open := []OpenOrder{{OrderID: 1, ClientID: "reconcile-1"}, {OrderID: 3}}
+	result := ReconcileOpenOrders("Poloniex", open, false)
+	if len(result.Matched) != 1 || result.Matched[0].ClientID != "reconcile-1" {
+		t.Error("Test Failed - ReconcileOpenOrders() unexpected matched orders", result.Matched)
+	}
+
+	if len(result.Unknown) != 1 || result.Unknown[0].OrderID != 3 || len(result.Adopted) != 0 {
+		t.Error("Test Failed - ReconcileOpenOrders() unexpected unknown orders", result.Unknown)
+	}
+
+	if len(result.Missing) != 1 || result.Missing[0].ClientID != "reconcile-2" {
+		t.Error("Test Failed - ReconcileOpenOrders() unexpected missing orders", result.Missing)
+	}
+
+	order, err := GetOrderByClientID("Poloniex", "reconcile-2")
+	if err != nil || order.Status != ClientOrderMissing {
+		t.Error("Test Failed - ReconcileOpenOrders() missing order not flagged", order.Status, err)
+	}
+
+	open = append(open, OpenOrder{OrderID: 2})
+	result = ReconcileOpenOrders("Poloniex", open, true)
+	if len(result.Matched) != 2 || len(result.Missing) != 0 {
+		t.Errorf("Test Failed - ReconcileOpenOrders() unexpected result %+v", result)
+	}
+
+	if len(result.Adopted) != 1 || result.Adopted[0].ClientID != "adopted-3" {
+		t.Error("Test Failed - ReconcileOpenOrders() unexpected adopted orders", result.Adopted)
+	}
+
+	order, err = GetOrderByClientID("Poloniex", "adopted-3")
+	if err != nil || !order.Adopted || order.Status != ClientOrderSubmitted {
+		t.Errorf("Test Failed - ReconcileOpenOrders() adopted order not registered %+v %v", order, err)
+	}
+}
+
+func TestResolveUnknownOrders(t *testing.T) {
+	for _, clientID := range []string{"resolve-1", "resolve-2"} {
+		_, err := SubmitWithClientID("LocalBitcoins", clientID, func(clientID string) (int64, error) {
+			return 0, context.DeadlineExceeded
+		})
+		if err == nil {
+			t.Fatal("Test Failed - SubmitWithClientID() submission error not returned")
+		}
+	}
+
+	resolved := ResolveUnknownOrders("localbitcoins", []OpenOrder{{OrderID: 9, ClientID: "resolve-1"}, {OrderID: 10}})
+	if len(resolved) != 1 || resolved[0].ClientID != "resolve-1" || resolved[0].OrderID != 9 {
+		t.Error("Test Failed - ResolveUnknownOrders() unexpected resolved orders", resolved)
+	}
+
+	orderID, err := SubmitWithClientID("LocalBitcoins", "resolve-1", nil)
+	if err != nil || orderID != 9 {
+		t.Error("Test Failed - SubmitWithClientID() resolved order not returned", err)
+	}
+
+	result := ReconcileOpenOrders("LocalBitcoins", []OpenOrder{{OrderID: 9, ClientID: "resolve-1"}}, false)
+	if len(result.Unresolved) != 1 || result.Unresolved[0].ClientID != "resolve-2" {
+		t.Error("Test Failed - ReconcileOpenOrders() unexpected unresolved orders", result.Unresolved)
+	}
+
+	order, err := GetOrderByClientID("LocalBitcoins", "resolve-2")
+	if err != nil || order.Status != ClientOrderUnknown {
+		t.Error("Test Failed - ReconcileOpenOrders() unresolved order status changed", order.Status, err)
+	}
+}
+
+func TestLoadClientOrders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clientorders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "clientorders.json")
+	err = LoadClientOrders(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadClientOrders() missing file error", err)
+	}
+	defer LoadClientOrders("")
+
+	_, err = SubmitWithClientID("Kraken", "persisted-1", func(clientID string) (int64, error) {
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+
+	clientOrdersMtx.Lock()
+	clientOrders = make(map[string]map[string]*ClientOrder)
+	clientOrdersMtx.Unlock()
+
+	err = LoadClientOrders(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadClientOrders() error", err)
+	}
+
+	order, err := GetOrderByClientID("Kraken", "persisted-1")
+	if err != nil || order.OrderID != 42 || order.Status != ClientOrderSubmitted {
+		t.Errorf("Test Failed - LoadClientOrders() unexpected order %+v %v", order, err)
+	}
+}
+
+func TestLoadClientOrdersPending(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clientorders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "clientorders.json")
+	data, err := common.JSONEncode([]ClientOrder{
+		{Exchange: "Kraken", ClientID: "pending-1", Status: ClientOrderPending},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = common.WriteFile(path, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = LoadClientOrders(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadClientOrders() error", err)
+	}
+	defer LoadClientOrders("")
+
+	order, err := GetOrderByClientID("Kraken", "pending-1")
+	if err != nil || order.Status != ClientOrderUnknown {
+		t.Errorf("Test Failed - LoadClientOrders() pending order not loaded as unknown %+v %v", order, err)
+	}
+
+	var submissions int
+	_, err = SubmitWithClientID("Kraken", "pending-1", func(clientID string) (int64, error) {
+		submissions++
+		return 43, nil
+	})
+	if err != ErrClientOrderUnknown || submissions != 0 {
+		t.Error("Test Failed - SubmitWithClientID() interrupted order resubmitted", err)
+	}
+}
@@ -0,0 +1,86 @@
+package orders
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// ErrNoRoute is returned when no venue has a price for the side of an order
+var ErrNoRoute = errors.New("no venue available to route order")
+
+// Venue holds the top of book and taker fee rate of a pair on an exchange
+// considered when routing an order. Slippage is the slippage in basis points
+// expected by the order beyond the top of book, if known
+type Venue struct {
+	Exchange string            `json:"exchange"`
+	Pair     pair.CurrencyPair `json:"pair"`
+	Bid      float64           `json:"bid"`
+	Ask      float64           `json:"ask"`
+	FeeRate  float64           `json:"feeRate"`
+	Slippage float64           `json:"slippage,omitempty"`
+}
+
+// Route holds the venue an order was routed to, the price it is expected to
+// fill at, its effective price including fees and slippage and its estimated
+// cost in the quote currency. Cost is the fee and slippage plus the half
+// spread paid crossing the book from the mid price
+type Route struct {
+	Venue          Venue   `json:"venue"`
+	Side           string  `json:"side"`
+	Amount         float64 `json:"amount"`
+	Price          float64 `json:"price"`
+	EffectivePrice float64 `json:"effectivePrice"`
+	Fee            float64 `json:"fee"`
+	Cost           float64 `json:"cost"`
+}
+
+// RouteOrder returns the route of an order to the venue with the best
+// effective price, the ask plus fees and slippage for a buy and the bid less
+// fees and slippage for a sell. Venues without a price on the side of the
+// order or under maintenance are skipped
+func RouteOrder(side string, amount float64, venues []Venue) (Route, error) {
+	side = common.StringToUpper(side)
+	now := time.Now()
+	var routes []Route
+	for x := range venues {
+		v := venues[x]
+		if _, _, ok := InMaintenance(v.Exchange, now); ok {
+			continue
+		}
+
+		price := v.Bid
+		if side == FillBuy {
+			price = v.Ask
+		}
+		if price <= 0 {
+			continue
+		}
+
+		fee := amount * price * v.FeeRate
+		route := Route{Venue: v, Side: side, Amount: amount, Price: price,
+			EffectivePrice: v.EffectivePrice(side), Fee: fee, Cost: fee}
+		if v.Slippage > 0 {
+			route.Cost += amount * price * v.Slippage / 10000
+		}
+		if v.Bid > 0 && v.Ask > 0 {
+			route.Cost += amount * (v.Ask - v.Bid) / 2
+		}
+		routes = append(routes, route)
+	}
+
+	if len(routes) == 0 {
+		return Route{}, ErrNoRoute
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if side == FillBuy {
+			return routes[i].EffectivePrice < routes[j].EffectivePrice
+		}
+		return routes[i].EffectivePrice > routes[j].EffectivePrice
+	})
+	return routes[0], nil
+}
@@ -0,0 +1,55 @@
+package orders
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestRouteOrder(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	venues := []Venue{
+		{Exchange: "Bitstamp", Pair: p, Bid: 99, Ask: 101, FeeRate: 0.0025},
+		{Exchange: "Gemini", Pair: p, Bid: 100, Ask: 100.5, FeeRate: 0.01},
+		{Exchange: "Kraken", Pair: p, Bid: 98, Ask: 0, FeeRate: 0},
+	}
+
+	_, err := RouteOrder("buy", 1, nil)
+	if err != ErrNoRoute {
+		t.Error("Test Failed - RouteOrder() no route error", err)
+	}
+
+	route, err := RouteOrder("buy", 2, venues)
+	if err != nil {
+		t.Fatal("Test Failed - RouteOrder() error", err)
+	}
+	if route.Venue.Exchange != "Bitstamp" || route.Side != FillBuy || route.Price != 101 {
+		t.Errorf("Test Failed - RouteOrder() unexpected buy route %v", route)
+	}
+	if route.Fee != 0.505 || route.Cost != 2.505 {
+		t.Errorf("Test Failed - RouteOrder() unexpected buy cost %v %v", route.Fee, route.Cost)
+	}
+
+	route, err = RouteOrder("sell", 1, venues)
+	if err != nil {
+		t.Fatal("Test Failed - RouteOrder() error", err)
+	}
+	if route.Venue.Exchange != "Gemini" || route.Price != 100 {
+		t.Errorf("Test Failed - RouteOrder() unexpected sell route %v", route)
+	}
+
+	// Slippage expected on the cheaper venue routes the buy elsewhere
+	venues[0].Slippage = 100
+	route, err = RouteOrder("buy", 2, venues)
+	if err != nil || route.Venue.Exchange != "Gemini" {
+		t.Errorf("Test Failed - RouteOrder() expected slippage to reroute the buy %v %v", route, err)
+	}
+	venues[0].Slippage = 0
+
+	venues[0].FeeRate = 0.05
+	venues[1].FeeRate = 0.05
+	route, err = RouteOrder("sell", 1, venues)
+	if err != nil || route.Venue.Exchange != "Kraken" || route.Cost != 0 {
+		t.Errorf("Test Failed - RouteOrder() expected one sided venue route %v %v", route, err)
+	}
+}
@@ -0,0 +1,254 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+// Slippage models for simulated fills
+const (
+	SlippageNone                = ""
+	SlippageFixedBPS            = "fixed_bps"
+	SlippageOrderbookWalk       = "orderbook_walk"
+	SlippageVolumeParticipation = "volume_participation"
+)
+
+// Vars for the slippage model registry
+var (
+	defaultSlippage  SlippageConfig
+	exchangeSlippage = make(map[string]SlippageConfig)
+	strategySlippage = make(map[string]SlippageConfig)
+	slippageMtx      sync.Mutex
+
+	// ErrNoMarketPrice is returned when a fill is simulated without a price
+	// for the side being executed against
+	ErrNoMarketPrice = errors.New("no market price to simulate fill against")
+
+	// ErrNoLiquidity is returned when a simulated fill can't execute any of
+	// the order amount
+	ErrNoLiquidity = errors.New("no liquidity to simulate fill against")
+)
+
+// SlippageConfig holds the slippage model and latency applied to simulated
+// fills. BPS is the slippage in basis points for the fixed model and the
+// price impact at full participation of the traded volume for the volume
+// participation model. MaxParticipation caps the share of the traded volume
+// a fill may take, zero leaves it uncapped. Fills execute against the market
+// after Latency plus a random delay up to LatencyJitter
+type SlippageConfig struct {
+	Model            string        `json:"model"`
+	BPS              float64       `json:"bps,omitempty"`
+	MaxParticipation float64       `json:"maxParticipation,omitempty"`
+	Latency          time.Duration `json:"latency,omitempty"`
+	LatencyJitter    time.Duration `json:"latencyJitter,omitempty"`
+}
+
+// SimulationMarket holds the market state a simulated fill executes against.
+// Volume is the amount traded over the period the order participates in and
+// Orderbook is only required by the orderbook walk model
+type SimulationMarket struct {
+	Bid       float64
+	Ask       float64
+	Last      float64
+	Volume    float64
+	Orderbook orderbook.Base
+}
+
+// SimulatedFill holds the result of a simulated execution. Amount is less
+// than the order amount when the market couldn't fill all of it, Slippage is
+// the difference between the fill price and the reference price in basis
+// points
+type SimulatedFill struct {
+	Amount   float64
+	Price    float64
+	Slippage float64
+	Latency  time.Duration
+}
+
+// Validate returns an error if the slippage config is invalid
+func (c SlippageConfig) Validate() error {
+	switch c.Model {
+	case SlippageNone, SlippageFixedBPS, SlippageOrderbookWalk, SlippageVolumeParticipation:
+	default:
+		return fmt.Errorf("unsupported slippage model %s", c.Model)
+	}
+
+	if c.BPS < 0 || c.MaxParticipation < 0 || c.MaxParticipation > 1 ||
+		c.Latency < 0 || c.LatencyJitter < 0 {
+		return errors.New("slippage bps and latency cannot be negative and max participation must be between 0 and 1")
+	}
+	return nil
+}
+
+// GetLatency returns the simulated latency of a fill, the configured latency
+// plus a random jitter
+func (c SlippageConfig) GetLatency() time.Duration {
+	latency := c.Latency
+	if c.LatencyJitter > 0 {
+		latency += time.Duration(rand.Int63n(int64(c.LatencyJitter)))
+	}
+	return latency
+}
+
+// Simulate returns the fill of an order for amount executed against the
+// market using the slippage model, without latency
+func (c SlippageConfig) Simulate(side string, amount float64, market SimulationMarket) (SimulatedFill, error) {
+	side = common.StringToUpper(side)
+	if amount <= 0 || (side != FillBuy && side != FillSell) {
+		return SimulatedFill{}, ErrInvalidFill
+	}
+
+	reference := market.Bid
+	direction := -1.0
+	if side == FillBuy {
+		reference = market.Ask
+		direction = 1
+	}
+	if reference <= 0 {
+		reference = market.Last
+	}
+
+	fill := SimulatedFill{Amount: amount, Price: reference}
+	switch c.Model {
+	case SlippageFixedBPS:
+		fill.Price = reference * (1 + direction*c.BPS/10000)
+	case SlippageOrderbookWalk:
+		levels := market.Orderbook.Bids
+		if side == FillBuy {
+			levels = market.Orderbook.Asks
+		}
+		fill.Amount, fill.Price = walkOrderbook(levels, amount)
+		if fill.Amount <= 0 {
+			return SimulatedFill{}, ErrNoLiquidity
+		}
+		if reference <= 0 {
+			reference = levels[0].Price
+		}
+	case SlippageVolumeParticipation:
+		if market.Volume <= 0 {
+			return SimulatedFill{}, ErrNoLiquidity
+		}
+		if c.MaxParticipation > 0 {
+			fill.Amount = math.Min(amount, market.Volume*c.MaxParticipation)
+		}
+		participation := math.Min(fill.Amount/market.Volume, 1)
+		fill.Price = reference * (1 + direction*c.BPS*participation/10000)
+	}
+
+	if reference <= 0 || fill.Price <= 0 {
+		return SimulatedFill{}, ErrNoMarketPrice
+	}
+	fill.Slippage = direction * (fill.Price - reference) / reference * 10000
+	return fill, nil
+}
+
+// SimulateExecution waits for the simulated latency, fetches the market state
+// at that point and returns the fill of an order for amount executed against
+// it, so prices moving during the delay are reflected in the fill
+func (c SlippageConfig) SimulateExecution(side string, amount float64, market func() (SimulationMarket, error)) (SimulatedFill, error) {
+	latency := c.GetLatency()
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	m, err := market()
+	if err != nil {
+		return SimulatedFill{}, err
+	}
+
+	fill, err := c.Simulate(side, amount, m)
+	fill.Latency = latency
+	return fill, err
+}
+
+// walkOrderbook executes amount against the orderbook levels from the top
+// of the book, returning the amount filled and its average price
+func walkOrderbook(levels []orderbook.Item, amount float64) (float64, float64) {
+	var filled, cost float64
+	for x := range levels {
+		if filled >= amount {
+			break
+		}
+		if levels[x].Amount <= 0 || levels[x].Price <= 0 {
+			continue
+		}
+		take := math.Min(levels[x].Amount, amount-filled)
+		filled += take
+		cost += take * levels[x].Price
+	}
+
+	if filled == 0 {
+		return 0, 0
+	}
+	return filled, cost / filled
+}
+
+// SetDefaultSlippage sets the slippage config used by exchanges and
+// strategies without their own
+func SetDefaultSlippage(cfg SlippageConfig) error {
+	err := cfg.Validate()
+	if err != nil {
+		return err
+	}
+
+	slippageMtx.Lock()
+	defaultSlippage = cfg
+	slippageMtx.Unlock()
+	return nil
+}
+
+// SetExchangeSlippage sets the slippage config of simulated fills on an
+// exchange
+func SetExchangeSlippage(exchange string, cfg SlippageConfig) error {
+	err := cfg.Validate()
+	if err != nil {
+		return err
+	}
+
+	slippageMtx.Lock()
+	exchangeSlippage[common.StringToLower(exchange)] = cfg
+	slippageMtx.Unlock()
+	return nil
+}
+
+// SetStrategySlippage sets the slippage config of simulated fills of orders
+// placed by a strategy, which takes precedence over the exchange config
+func SetStrategySlippage(strategyID string, cfg SlippageConfig) error {
+	if strategyID == "" {
+		return ErrStrategyIDRequired
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		return err
+	}
+
+	slippageMtx.Lock()
+	strategySlippage[strategyID] = cfg
+	slippageMtx.Unlock()
+	return nil
+}
+
+// GetSlippage returns the slippage config for a simulated fill on an exchange
+// for a strategy, preferring the strategy config, then the exchange config and
+// then the default config. A blank strategy ID skips the strategy config
+func GetSlippage(exchange, strategyID string) SlippageConfig {
+	slippageMtx.Lock()
+	defer slippageMtx.Unlock()
+
+	if cfg, ok := strategySlippage[strategyID]; ok && strategyID != "" {
+		return cfg
+	}
+
+	if cfg, ok := exchangeSlippage[common.StringToLower(exchange)]; ok {
+		return cfg
+	}
+	return defaultSlippage
+}
@@ -0,0 +1,145 @@
+package orders
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+func TestSlippageConfigValidate(t *testing.T) {
+	valid := []SlippageConfig{
+		{},
+		{Model: SlippageFixedBPS, BPS: 5},
+		{Model: SlippageVolumeParticipation, BPS: 50, MaxParticipation: 0.1},
+	}
+	for x := range valid {
+		if err := valid[x].Validate(); err != nil {
+			t.Error("Test Failed - Validate() error", err)
+		}
+	}
+
+	invalid := []SlippageConfig{
+		{Model: "blah"},
+		{Model: SlippageFixedBPS, BPS: -1},
+		{Model: SlippageVolumeParticipation, MaxParticipation: 2},
+		{Latency: -time.Second},
+	}
+	for x := range invalid {
+		if err := invalid[x].Validate(); err == nil {
+			t.Errorf("Test Failed - Validate() expected error for %+v", invalid[x])
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	market := SimulationMarket{
+		Bid:    99,
+		Ask:    100,
+		Volume: 10,
+		Orderbook: orderbook.Base{
+			Asks: []orderbook.Item{{Price: 100, Amount: 1}, {Price: 102, Amount: 1}},
+			Bids: []orderbook.Item{{Price: 99, Amount: 0.5}},
+		},
+	}
+
+	fill, err := SlippageConfig{Model: SlippageFixedBPS, BPS: 10}.Simulate("buy", 1, market)
+	if err != nil || math.Abs(fill.Price-100.1) > 1e-9 || math.Abs(fill.Slippage-10) > 1e-9 {
+		t.Errorf("Test Failed - Simulate() fixed bps unexpected fill %+v %v", fill, err)
+	}
+
+	fill, err = SlippageConfig{Model: SlippageFixedBPS, BPS: 10}.Simulate("sell", 1, market)
+	if err != nil || math.Abs(fill.Price-98.901) > 1e-9 {
+		t.Errorf("Test Failed - Simulate() fixed bps sell unexpected fill %+v %v", fill, err)
+	}
+
+	fill, err = SlippageConfig{Model: SlippageOrderbookWalk}.Simulate("buy", 1.5, market)
+	if err != nil || fill.Amount != 1.5 || math.Abs(fill.Price-(100+51)/1.5) > 1e-9 {
+		t.Errorf("Test Failed - Simulate() orderbook walk unexpected fill %+v %v", fill, err)
+	}
+
+	fill, err = SlippageConfig{Model: SlippageOrderbookWalk}.Simulate("sell", 2, market)
+	if err != nil || fill.Amount != 0.5 || fill.Price != 99 {
+		t.Errorf("Test Failed - Simulate() orderbook walk partial unexpected fill %+v %v", fill, err)
+	}
+
+	fill, err = SlippageConfig{Model: SlippageVolumeParticipation, BPS: 100,
+		MaxParticipation: 0.2}.Simulate("buy", 5, market)
+	if err != nil || fill.Amount != 2 || math.Abs(fill.Price-100.2) > 1e-9 {
+		t.Errorf("Test Failed - Simulate() volume participation unexpected fill %+v %v", fill, err)
+	}
+
+	_, err = SlippageConfig{Model: SlippageVolumeParticipation}.Simulate("buy", 1, SimulationMarket{Ask: 100})
+	if err != ErrNoLiquidity {
+		t.Error("Test Failed - Simulate() expected no liquidity error", err)
+	}
+
+	_, err = SlippageConfig{}.Simulate("buy", 1, SimulationMarket{})
+	if err != ErrNoMarketPrice {
+		t.Error("Test Failed - Simulate() expected no market price error", err)
+	}
+}
+
+func TestSimulateExecution(t *testing.T) {
+	cfg := SlippageConfig{Latency: time.Millisecond * 10}
+
+	start := time.Now()
+	fill, err := cfg.SimulateExecution("sell", 1, func() (SimulationMarket, error) {
+		return SimulationMarket{Bid: 50}, nil
+	})
+	if err != nil || fill.Price != 50 || fill.Latency != time.Millisecond*10 {
+		t.Errorf("Test Failed - SimulateExecution() unexpected fill %+v %v", fill, err)
+	}
+
+	if time.Since(start) < cfg.Latency {
+		t.Error("Test Failed - SimulateExecution() latency not applied")
+	}
+
+	_, err = SlippageConfig{}.SimulateExecution("sell", 1, func() (SimulationMarket, error) {
+		return SimulationMarket{}, errors.New("no market")
+	})
+	if err == nil {
+		t.Error("Test Failed - SimulateExecution() expected market error")
+	}
+}
+
+func TestGetSlippage(t *testing.T) {
+	defer SetDefaultSlippage(SlippageConfig{})
+
+	err := SetDefaultSlippage(SlippageConfig{Model: SlippageFixedBPS, BPS: 1})
+	if err != nil {
+		t.Fatal("Test Failed - SetDefaultSlippage() error", err)
+	}
+
+	err = SetExchangeSlippage("Bitstamp", SlippageConfig{Model: SlippageOrderbookWalk})
+	if err != nil {
+		t.Fatal("Test Failed - SetExchangeSlippage() error", err)
+	}
+
+	err = SetStrategySlippage("slippage-strategy", SlippageConfig{Model: SlippageVolumeParticipation})
+	if err != nil {
+		t.Fatal("Test Failed - SetStrategySlippage() error", err)
+	}
+
+	if SetStrategySlippage("", SlippageConfig{}) != ErrStrategyIDRequired {
+		t.Error("Test Failed - SetStrategySlippage() expected strategy ID error")
+	}
+
+	if SetExchangeSlippage("Bitstamp", SlippageConfig{Model: "blah"}) == nil {
+		t.Error("Test Failed - SetExchangeSlippage() expected invalid model error")
+	}
+
+	if GetSlippage("BITSTAMP", "slippage-strategy").Model != SlippageVolumeParticipation {
+		t.Error("Test Failed - GetSlippage() strategy config not preferred")
+	}
+
+	if GetSlippage("bitstamp", "").Model != SlippageOrderbookWalk {
+		t.Error("Test Failed - GetSlippage() exchange config not used")
+	}
+
+	if GetSlippage("Kraken", "other").Model != SlippageFixedBPS {
+		t.Error("Test Failed - GetSlippage() default config not used")
+	}
+}
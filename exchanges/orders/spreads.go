@@ -0,0 +1,457 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Spread execution modes
+const (
+	// SpreadLegged submits the first leg once the target differential is
+	// reached and hedges its fills with the remaining legs
+	SpreadLegged = "LEGGED"
+	// SpreadSimultaneous submits every leg at once when the target
+	// differential is reached
+	SpreadSimultaneous = "SIMULTANEOUS"
+)
+
+// Spread order states
+const (
+	SpreadPending      = "PENDING"
+	SpreadWorking      = "WORKING"
+	SpreadFilled       = "FILLED"
+	SpreadHedgeTimeout = "HEDGE_TIMEOUT"
+	SpreadFailed       = "FAILED"
+)
+
+// spreadTolerance allows for floating point error when comparing leg amounts
+const spreadTolerance = 1e-9
+
+// maxUnmatchedSpreadFills is the number of fills without a client order ID
+// held for leg orders whose order ID is not yet known
+const maxUnmatchedSpreadFills = 100
+
+// Vars for the spread order store
+var (
+	spreadOrders    []*SpreadOrder
+	spreadOrdersID  int64
+	spreadOrdersMtx sync.Mutex
+
+	// unmatchedSpreadFills holds recent fills reported only by order ID which
+	// didn't match a leg, as a leg order can fill before its submission
+	// returns the order ID
+	unmatchedSpreadFills []Fill
+
+	// ErrInvalidSpreadOrder is returned when a spread order is missing
+	// required details
+	ErrInvalidSpreadOrder = errors.New("spread order requires a quantity, execution mode and at least two legs")
+	// ErrInvalidSpreadLeg is returned when a spread leg is missing required
+	// details
+	ErrInvalidSpreadLeg = errors.New("spread leg requires an exchange, pair, side and ratio")
+	// ErrSpreadOrderNotFound is returned when no spread order exists with the
+	// ID
+	ErrSpreadOrderNotFound = errors.New("spread order not found")
+)
+
+// SpreadLeg holds a single leg of a spread order. Ratio is the amount of the
+// leg traded per unit of the spread and a zero price submits market orders.
+// ClientIDs holds the client order ID of every leg order, registered before
+// the order is submitted so fills arriving before the order ID are matched
+type SpreadLeg struct {
+	Exchange     string            `json:"exchange"`
+	Pair         pair.CurrencyPair `json:"pair"`
+	Side         string            `json:"side"`
+	Ratio        float64           `json:"ratio"`
+	Price        float64           `json:"price"`
+	Submitted    float64           `json:"submitted"`
+	Filled       float64           `json:"filled"`
+	AveragePrice float64           `json:"averagePrice"`
+	OrderIDs     []int64           `json:"orderIDs"`
+	ClientIDs    []string          `json:"clientIDs"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// SpreadOrder holds a multi-leg order, possibly across exchanges, which is
+// executed once the price differential between the legs reaches the target.
+// The differential is the value of the sell legs less the value of the buy
+// legs per unit of the spread
+type SpreadOrder struct {
+	ID                 int64         `json:"id"`
+	Legs               []SpreadLeg   `json:"legs"`
+	Quantity           float64       `json:"quantity"`
+	TargetDifferential float64       `json:"targetDifferential"`
+	Mode               string        `json:"mode"`
+	HedgeTimeout       time.Duration `json:"hedgeTimeout"`
+	Status             string        `json:"status"`
+	UnhedgedSince      time.Time     `json:"unhedgedSince"`
+	Owner              string        `json:"owner,omitempty"`
+	Created            time.Time     `json:"created"`
+	Updated            time.Time     `json:"updated"`
+}
+
+// SpreadPriceFunc returns the executable price of a leg, the best ask for a
+// buy or the best bid for a sell, and whether the price is known
+type SpreadPriceFunc func(leg SpreadLeg) (float64, bool)
+
+// SpreadSubmitFunc submits an order for an amount of a spread leg tagged with
+// the client order ID and returns the exchange order ID
+type SpreadSubmitFunc func(leg SpreadLeg, amount float64, clientID string) (int64, error)
+
+// FilledQuantity returns the number of complete spread units filled across all
+// legs
+func (s *SpreadOrder) FilledQuantity() float64 {
+	filled := math.MaxFloat64
+	for x := range s.Legs {
+		filled = math.Min(filled, s.Legs[x].Filled/s.Legs[x].Ratio)
+	}
+	return filled
+}
+
+// Residuals returns the filled amount of each leg which is not matched by the
+// other legs, the unhedged exposure of the spread
+func (s *SpreadOrder) Residuals() []float64 {
+	filled := s.FilledQuantity()
+	residuals := make([]float64, len(s.Legs))
+	for x := range s.Legs {
+		residuals[x] = s.Legs[x].Filled - filled*s.Legs[x].Ratio
+		if residuals[x] < spreadTolerance {
+			residuals[x] = 0
+		}
+	}
+	return residuals
+}
+
+// IsHedged returns whether every leg is filled in proportion to its ratio
+func (s *SpreadOrder) IsHedged() bool {
+	for _, residual := range s.Residuals() {
+		if residual > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Differential returns the value of the sell legs less the value of the buy
+// legs per unit of the spread for the supplied leg prices
+func (s *SpreadOrder) Differential(prices []float64) float64 {
+	var differential float64
+	for x := range s.Legs {
+		value := prices[x] * s.Legs[x].Ratio
+		if s.Legs[x].Side == FillBuy {
+			value = -value
+		}
+		differential += value
+	}
+	return differential
+}
+
+// AddSpreadOrder validates and adds a spread order, returning its ID
+func AddSpreadOrder(s SpreadOrder) (int64, error) {
+	s.Mode = common.StringToUpper(s.Mode)
+	if s.Quantity <= 0 || len(s.Legs) < 2 ||
+		(s.Mode != SpreadLegged && s.Mode != SpreadSimultaneous) {
+		return 0, ErrInvalidSpreadOrder
+	}
+
+	legs := make([]SpreadLeg, len(s.Legs))
+	for x := range s.Legs {
+		leg := s.Legs[x]
+		leg.Side = common.StringToUpper(leg.Side)
+		if leg.Exchange == "" || leg.Pair.Empty() || leg.Ratio <= 0 ||
+			leg.Price < 0 || (leg.Side != FillBuy && leg.Side != FillSell) {
+			return 0, ErrInvalidSpreadLeg
+		}
+		leg.Submitted = 0
+		leg.Filled = 0
+		leg.AveragePrice = 0
+		leg.OrderIDs = nil
+		leg.ClientIDs = nil
+		leg.Error = ""
+		legs[x] = leg
+	}
+
+	spreadOrdersMtx.Lock()
+	defer spreadOrdersMtx.Unlock()
+
+	spreadOrdersID++
+	s.ID = spreadOrdersID
+	s.Legs = legs
+	s.Status = SpreadPending
+	s.UnhedgedSince = time.Time{}
+	s.Created = time.Now()
+	s.Updated = s.Created
+	spreadOrders = append(spreadOrders, &s)
+	return s.ID, nil
+}
+
+// GetSpreadOrder returns a spread order by its ID
+func GetSpreadOrder(id int64) (SpreadOrder, error) {
+	spreadOrdersMtx.Lock()
+	defer spreadOrdersMtx.Unlock()
+
+	for x := range spreadOrders {
+		if spreadOrders[x].ID == id {
+			return copySpreadOrder(spreadOrders[x]), nil
+		}
+	}
+	return SpreadOrder{}, ErrSpreadOrderNotFound
+}
+
+// GetSpreadOrders returns all spread orders ordered by ID
+func GetSpreadOrders() []SpreadOrder {
+	spreadOrdersMtx.Lock()
+	defer spreadOrdersMtx.Unlock()
+
+	result := make([]SpreadOrder, 0, len(spreadOrders))
+	for x := range spreadOrders {
+		result = append(result, copySpreadOrder(spreadOrders[x]))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// RemoveSpreadOrder removes a spread order by its ID. Orders already submitted
+// for its legs are left on the exchanges
+func RemoveSpreadOrder(id int64) error {
+	spreadOrdersMtx.Lock()
+	defer spreadOrdersMtx.Unlock()
+
+	for x := range spreadOrders {
+		if spreadOrders[x].ID == id {
+			spreadOrders = append(spreadOrders[:x], spreadOrders[x+1:]...)
+			return nil
+		}
+	}
+	return ErrSpreadOrderNotFound
+}
+
+// spreadSubmission holds a leg order to submit outside of the store lock
+type spreadSubmission struct {
+	spread   *SpreadOrder
+	leg      int
+	details  SpreadLeg
+	amount   float64
+	clientID string
+	orderID  int64
+	err      error
+}
+
+// ProcessSpreadOrders executes pending spread orders whose target differential
+// has been reached, submits hedging orders for legged spreads as the first leg
+// fills and flags spreads left unhedged beyond their hedge timeout. Spreads
+// which have failed or timed out during processing are returned so their
+// residual risk can be reported
+func ProcessSpreadOrders(price SpreadPriceFunc, submit SpreadSubmitFunc, now time.Time) []SpreadOrder {
+	spreadOrdersMtx.Lock()
+	var submissions []*spreadSubmission
+	var flagged []SpreadOrder
+	for x := range spreadOrders {
+		s := spreadOrders[x]
+		switch s.Status {
+		case SpreadPending:
+			if !s.targetReached(price) {
+				continue
+			}
+
+			s.Status = SpreadWorking
+			s.Updated = now
+			if s.Mode == SpreadSimultaneous {
+				for y := range s.Legs {
+					submissions = append(submissions, s.newSubmission(y, s.Quantity*s.Legs[y].Ratio))
+				}
+				continue
+			}
+			submissions = append(submissions, s.newSubmission(0, s.Quantity*s.Legs[0].Ratio))
+		case SpreadWorking:
+			if s.HedgeTimeout > 0 && !s.UnhedgedSince.IsZero() &&
+				now.Sub(s.UnhedgedSince) > s.HedgeTimeout {
+				s.Status = SpreadHedgeTimeout
+				s.Updated = now
+				flagged = append(flagged, copySpreadOrder(s))
+				continue
+			}
+
+			if s.Mode != SpreadLegged {
+				continue
+			}
+
+			hedged := s.Legs[0].Filled / s.Legs[0].Ratio
+			for y := 1; y < len(s.Legs); y++ {
+				amount := hedged*s.Legs[y].Ratio - s.Legs[y].Submitted
+				if amount > spreadTolerance {
+					submissions = append(submissions, s.newSubmission(y, amount))
+				}
+			}
+		}
+	}
+	spreadOrdersMtx.Unlock()
+
+	var wg sync.WaitGroup
+	for x := range submissions {
+		wg.Add(1)
+		go func(sub *spreadSubmission) {
+			defer wg.Done()
+			sub.orderID, sub.err = submit(sub.details, sub.amount, sub.clientID)
+		}(submissions[x])
+	}
+	wg.Wait()
+
+	spreadOrdersMtx.Lock()
+	defer spreadOrdersMtx.Unlock()
+	var failed []*SpreadOrder
+	for _, sub := range submissions {
+		leg := &sub.spread.Legs[sub.leg]
+		sub.spread.Updated = time.Now()
+		if sub.err != nil {
+			leg.Submitted -= sub.amount
+			leg.Error = sub.err.Error()
+			if sub.spread.Status != SpreadFailed {
+				sub.spread.Status = SpreadFailed
+				failed = append(failed, sub.spread)
+			}
+			continue
+		}
+		leg.OrderIDs = append(leg.OrderIDs, sub.orderID)
+		applyUnmatchedSpreadFills(sub.spread, sub.leg, sub.orderID)
+	}
+
+	for x := range failed {
+		flagged = append(flagged, copySpreadOrder(failed[x]))
+	}
+	return flagged
+}
+
+// targetReached returns whether the differential of the current leg prices
+// has reached the target, spreadOrdersMtx must be held by the caller
+func (s *SpreadOrder) targetReached(price SpreadPriceFunc) bool {
+	prices := make([]float64, len(s.Legs))
+	for x := range s.Legs {
+		p, ok := price(s.Legs[x])
+		if !ok || p <= 0 {
+			return false
+		}
+		prices[x] = p
+	}
+	return s.Differential(prices) >= s.TargetDifferential
+}
+
+// newSubmission reserves an amount of a leg for submission and registers its
+// client order ID, spreadOrdersMtx must be held by the caller
+func (s *SpreadOrder) newSubmission(leg int, amount float64) *spreadSubmission {
+	clientID := fmt.Sprintf("spread-%d-%d-%d", s.ID, leg, len(s.Legs[leg].ClientIDs)+1)
+	s.Legs[leg].Submitted += amount
+	s.Legs[leg].ClientIDs = append(s.Legs[leg].ClientIDs, clientID)
+	return &spreadSubmission{
+		spread:   s,
+		leg:      leg,
+		details:  s.Legs[leg],
+		amount:   amount,
+		clientID: clientID,
+	}
+}
+
+// recordSpreadFill applies a fill to the spread leg which submitted the order,
+// matched by client order ID or order ID, updating the combined fill state of
+// the spread. Fills reported only by an order ID not yet known are held until
+// the leg order submission returns
+func recordSpreadFill(f Fill) {
+	if f.OrderID == 0 && f.ClientID == "" {
+		return
+	}
+
+	spreadOrdersMtx.Lock()
+	defer spreadOrdersMtx.Unlock()
+
+	for x := range spreadOrders {
+		s := spreadOrders[x]
+		for y := range s.Legs {
+			leg := &s.Legs[y]
+			if common.StringToLower(leg.Exchange) != common.StringToLower(f.Exchange) {
+				continue
+			}
+
+			if (f.ClientID != "" && common.StringDataCompare(leg.ClientIDs, f.ClientID)) ||
+				(f.OrderID != 0 && containsOrderID(leg.OrderIDs, f.OrderID)) {
+				s.applyFill(y, f)
+				return
+			}
+		}
+	}
+
+	if f.ClientID == "" {
+		unmatchedSpreadFills = append(unmatchedSpreadFills, f)
+		if len(unmatchedSpreadFills) > maxUnmatchedSpreadFills {
+			unmatchedSpreadFills = unmatchedSpreadFills[1:]
+		}
+	}
+}
+
+// applyUnmatchedSpreadFills applies the held fills of a leg order once its
+// order ID is known, spreadOrdersMtx must be held by the caller
+func applyUnmatchedSpreadFills(s *SpreadOrder, leg int, orderID int64) {
+	exchange := common.StringToLower(s.Legs[leg].Exchange)
+	remaining := unmatchedSpreadFills[:0]
+	for x := range unmatchedSpreadFills {
+		f := unmatchedSpreadFills[x]
+		if f.OrderID == orderID && common.StringToLower(f.Exchange) == exchange {
+			s.applyFill(leg, f)
+			continue
+		}
+		remaining = append(remaining, f)
+	}
+	unmatchedSpreadFills = remaining
+}
+
+// applyFill adds a fill to a leg and updates the hedge and fill state of the
+// spread, spreadOrdersMtx must be held by the caller
+func (s *SpreadOrder) applyFill(leg int, f Fill) {
+	l := &s.Legs[leg]
+	l.AveragePrice = (l.AveragePrice*l.Filled + f.Price*f.Amount) /
+		(l.Filled + f.Amount)
+	l.Filled += f.Amount
+	s.Updated = f.Timestamp
+
+	hedged := s.IsHedged()
+	if !hedged && s.UnhedgedSince.IsZero() {
+		s.UnhedgedSince = f.Timestamp
+	} else if hedged {
+		s.UnhedgedSince = time.Time{}
+	}
+
+	if s.Status == SpreadWorking && s.FilledQuantity() >= s.Quantity-spreadTolerance {
+		s.Status = SpreadFilled
+	}
+}
+
+// containsOrderID returns whether the order ID is in the list
+func containsOrderID(orderIDs []int64, orderID int64) bool {
+	for x := range orderIDs {
+		if orderIDs[x] == orderID {
+			return true
+		}
+	}
+	return false
+}
+
+// copySpreadOrder returns a copy of a spread order which does not share legs
+// with the store, spreadOrdersMtx must be held by the caller
+func copySpreadOrder(s *SpreadOrder) SpreadOrder {
+	result := *s
+	result.Legs = make([]SpreadLeg, len(s.Legs))
+	for x := range s.Legs {
+		result.Legs[x] = s.Legs[x]
+		result.Legs[x].OrderIDs = append([]int64(nil), s.Legs[x].OrderIDs...)
+		result.Legs[x].ClientIDs = append([]string(nil), s.Legs[x].ClientIDs...)
+	}
+	return result
+}
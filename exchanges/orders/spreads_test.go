@@ -0,0 +1,251 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+type testSpreadExchange struct {
+	m       sync.Mutex
+	prices  map[string]float64
+	orderID int64
+	orders  map[string]int64
+	fail    string
+}
+
+func (e *testSpreadExchange) price(leg SpreadLeg) (float64, bool) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	p, ok := e.prices[leg.Exchange]
+	return p, ok
+}
+
+func (e *testSpreadExchange) submit(leg SpreadLeg, amount float64, clientID string) (int64, error) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	if leg.Exchange == e.fail {
+		return 0, errors.New("order rejected")
+	}
+	e.orderID++
+	e.orders[clientID] = e.orderID
+	return e.orderID, nil
+}
+
+func newTestSpread(mode string) SpreadOrder {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	return SpreadOrder{
+		Quantity:           2,
+		TargetDifferential: 10,
+		Mode:               mode,
+		HedgeTimeout:       time.Minute,
+		Legs: []SpreadLeg{
+			{Exchange: "Bitstamp", Pair: p, Side: "buy", Ratio: 1},
+			{Exchange: "Kraken", Pair: p, Side: "sell", Ratio: 1},
+		},
+	}
+}
+
+func TestAddSpreadOrder(t *testing.T) {
+	s := newTestSpread("simultaneous")
+	s.Legs = s.Legs[:1]
+	_, err := AddSpreadOrder(s)
+	if err != ErrInvalidSpreadOrder {
+		t.Error("Test Failed - AddSpreadOrder() single leg error", err)
+	}
+
+	s = newTestSpread("simultaneous")
+	s.Legs[1].Ratio = 0
+	_, err = AddSpreadOrder(s)
+	if err != ErrInvalidSpreadLeg {
+		t.Error("Test Failed - AddSpreadOrder() invalid leg error", err)
+	}
+
+	id, err := AddSpreadOrder(newTestSpread("simultaneous"))
+	if err != nil {
+		t.Fatal("Test Failed - AddSpreadOrder() error", err)
+	}
+
+	s, err = GetSpreadOrder(id)
+	if err != nil || s.Status != SpreadPending || s.Legs[0].Side != FillBuy {
+		t.Error("Test Failed - GetSpreadOrder() unexpected spread", s, err)
+	}
+
+	err = RemoveSpreadOrder(id)
+	if err != nil {
+		t.Error("Test Failed - RemoveSpreadOrder() error", err)
+	}
+
+	_, err = GetSpreadOrder(id)
+	if err != ErrSpreadOrderNotFound {
+		t.Error("Test Failed - GetSpreadOrder() removed spread error", err)
+	}
+}
+
+func TestProcessSpreadOrdersSimultaneous(t *testing.T) {
+	exch := &testSpreadExchange{
+		prices: map[string]float64{"Bitstamp": 100, "Kraken": 105},
+		orders: make(map[string]int64),
+	}
+
+	id, err := AddSpreadOrder(newTestSpread("simultaneous"))
+	if err != nil {
+		t.Fatal("Test Failed - AddSpreadOrder() error", err)
+	}
+	defer RemoveSpreadOrder(id)
+
+	now := time.Now()
+	ProcessSpreadOrders(exch.price, exch.submit, now)
+	s, _ := GetSpreadOrder(id)
+	if s.Status != SpreadPending || len(exch.orders) != 0 {
+		t.Fatal("Test Failed - ProcessSpreadOrders() executed before target differential")
+	}
+
+	exch.prices["Kraken"] = 111
+	ProcessSpreadOrders(exch.price, exch.submit, now)
+	s, _ = GetSpreadOrder(id)
+	if s.Status != SpreadWorking || len(s.Legs[0].OrderIDs) != 1 ||
+		len(s.Legs[1].OrderIDs) != 1 || s.Legs[1].Submitted != 2 {
+		t.Fatal("Test Failed - ProcessSpreadOrders() legs not submitted", s)
+	}
+
+	err = RecordFill(Fill{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "buy",
+		OrderID: s.Legs[0].OrderIDs[0], Amount: 2, Price: 100, Timestamp: now})
+	if err != nil {
+		t.Fatal("Test Failed - RecordFill() error", err)
+	}
+
+	s, _ = GetSpreadOrder(id)
+	residuals := s.Residuals()
+	if s.IsHedged() || residuals[0] != 2 || residuals[1] != 0 || s.UnhedgedSince != now {
+		t.Error("Test Failed - RecordFill() residual risk not tracked", residuals)
+	}
+
+	err = RecordFill(Fill{Exchange: "Kraken", Pair: "BTCUSD", Side: "sell",
+		OrderID: s.Legs[1].OrderIDs[0], Amount: 2, Price: 111, Timestamp: now})
+	if err != nil {
+		t.Fatal("Test Failed - RecordFill() error", err)
+	}
+
+	s, _ = GetSpreadOrder(id)
+	if s.Status != SpreadFilled || !s.IsHedged() || s.FilledQuantity() != 2 ||
+		!s.UnhedgedSince.IsZero() {
+		t.Error("Test Failed - RecordFill() spread not filled", s)
+	}
+}
+
+func TestProcessSpreadOrdersLegged(t *testing.T) {
+	exch := &testSpreadExchange{
+		prices: map[string]float64{"Bitstamp": 100, "Kraken": 120},
+		orders: make(map[string]int64),
+	}
+
+	id, err := AddSpreadOrder(newTestSpread("legged"))
+	if err != nil {
+		t.Fatal("Test Failed - AddSpreadOrder() error", err)
+	}
+	defer RemoveSpreadOrder(id)
+
+	now := time.Now()
+	ProcessSpreadOrders(exch.price, exch.submit, now)
+	s, _ := GetSpreadOrder(id)
+	if len(s.Legs[0].OrderIDs) != 1 || len(s.Legs[1].OrderIDs) != 0 {
+		t.Fatal("Test Failed - ProcessSpreadOrders() legged spread submitted hedge before fill")
+	}
+
+	err = RecordFill(Fill{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "buy",
+		OrderID: s.Legs[0].OrderIDs[0], Amount: 0.5, Price: 100, Timestamp: now})
+	if err != nil {
+		t.Fatal("Test Failed - RecordFill() error", err)
+	}
+
+	ProcessSpreadOrders(exch.price, exch.submit, now)
+	s, _ = GetSpreadOrder(id)
+	if len(s.Legs[1].OrderIDs) != 1 || s.Legs[1].Submitted != 0.5 {
+		t.Fatal("Test Failed - ProcessSpreadOrders() hedge not submitted for fill", s.Legs[1])
+	}
+
+	if exch.orders[fmt.Sprintf("spread-%d-1-1", id)] == 0 {
+		t.Error("Test Failed - ProcessSpreadOrders() unexpected hedge client ID", exch.orders)
+	}
+
+	flagged := ProcessSpreadOrders(exch.price, exch.submit, now.Add(2*time.Minute))
+	if len(flagged) != 1 || flagged[0].Status != SpreadHedgeTimeout ||
+		flagged[0].Residuals()[0] != 0.5 {
+		t.Error("Test Failed - ProcessSpreadOrders() hedge timeout not flagged", flagged)
+	}
+}
+
+func TestProcessSpreadOrdersFailed(t *testing.T) {
+	exch := &testSpreadExchange{
+		prices: map[string]float64{"Bitstamp": 100, "Kraken": 120},
+		orders: make(map[string]int64),
+		fail:   "Kraken",
+	}
+
+	id, err := AddSpreadOrder(newTestSpread("simultaneous"))
+	if err != nil {
+		t.Fatal("Test Failed - AddSpreadOrder() error", err)
+	}
+	defer RemoveSpreadOrder(id)
+
+	flagged := ProcessSpreadOrders(exch.price, exch.submit, time.Now())
+	if len(flagged) != 1 || flagged[0].Status != SpreadFailed ||
+		flagged[0].Legs[1].Error == "" || flagged[0].Legs[1].Submitted != 0 ||
+		len(flagged[0].Legs[0].OrderIDs) != 1 {
+		t.Error("Test Failed - ProcessSpreadOrders() failed leg not flagged", flagged)
+	}
+}
+
+func TestProcessSpreadOrdersEarlyFill(t *testing.T) {
+	exch := &testSpreadExchange{
+		prices:  map[string]float64{"Bitstamp": 100, "Kraken": 111},
+		orderID: 1000,
+		orders:  make(map[string]int64),
+	}
+
+	spread := newTestSpread("simultaneous")
+	for x := range spread.Legs {
+		spread.Legs[x].Pair = pair.NewCurrencyPair("ETH", "USD")
+	}
+	id, err := AddSpreadOrder(spread)
+	if err != nil {
+		t.Fatal("Test Failed - AddSpreadOrder() error", err)
+	}
+	defer RemoveSpreadOrder(id)
+
+	// Both legs fill before their submissions return, one reported by client
+	// order ID and the other only by order ID
+	now := time.Now()
+	submit := func(leg SpreadLeg, amount float64, clientID string) (int64, error) {
+		orderID, err := exch.submit(leg, amount, clientID)
+		if err != nil {
+			return 0, err
+		}
+
+		f := Fill{Exchange: leg.Exchange, Pair: "ETHUSD", Side: leg.Side,
+			OrderID: orderID, Amount: amount, Price: 100, Timestamp: now}
+		if leg.Exchange == "Bitstamp" {
+			f.OrderID = 0
+			f.ClientID = clientID
+		}
+		if err := RecordFill(f); err != nil {
+			t.Error("Test Failed - RecordFill() error", err)
+		}
+		return orderID, nil
+	}
+
+	ProcessSpreadOrders(exch.price, submit, now)
+	s, _ := GetSpreadOrder(id)
+	if s.Legs[0].Filled != 2 || s.Legs[1].Filled != 2 || s.Status != SpreadFilled {
+		t.Errorf("Test Failed - ProcessSpreadOrders() early fills not applied %+v", s)
+	}
+
+	if len(s.Legs[0].ClientIDs) != 1 || s.Legs[0].ClientIDs[0] != fmt.Sprintf("spread-%d-0-1", id) {
+		t.Error("Test Failed - ProcessSpreadOrders() client ID not registered", s.Legs[0].ClientIDs)
+	}
+}
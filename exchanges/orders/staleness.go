@@ -0,0 +1,111 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+// DefaultMaxDataAge is the default maximum age of the market data an order
+// decision can be based on
+const DefaultMaxDataAge = time.Second * 5
+
+// Vars for the staleness guard
+var (
+	maxDataAge    = DefaultMaxDataAge
+	maxDataAgeMtx sync.Mutex
+
+	// ErrDataStampRequired is returned when a decision has not been stamped
+	// with the time of the market data it was based on
+	ErrDataStampRequired = errors.New("decision data stamp required")
+)
+
+// DataStamp records the market data a trading decision was based on
+type DataStamp struct {
+	Exchange    string
+	Pair        string
+	AssetType   string
+	DataUpdated time.Time
+}
+
+// StaleDataError is returned when an order decision was based on market data
+// older than the maximum data age
+type StaleDataError struct {
+	Stamp DataStamp
+	Age   time.Duration
+	Max   time.Duration
+}
+
+func (e *StaleDataError) Error() string {
+	return fmt.Sprintf("%s %s %s decision data is stale, age %v exceeds maximum %v",
+		e.Stamp.Exchange, e.Stamp.Pair, e.Stamp.AssetType, e.Age, e.Max)
+}
+
+// SetMaxDataAge sets the maximum age of the market data an order decision can
+// be based on, a zero or negative age resets it to the default
+func SetMaxDataAge(age time.Duration) {
+	if age <= 0 {
+		age = DefaultMaxDataAge
+	}
+	maxDataAgeMtx.Lock()
+	maxDataAge = age
+	maxDataAgeMtx.Unlock()
+}
+
+// GetMaxDataAge returns the maximum age of the market data an order decision
+// can be based on
+func GetMaxDataAge() time.Duration {
+	maxDataAgeMtx.Lock()
+	defer maxDataAgeMtx.Unlock()
+	return maxDataAge
+}
+
+// NewOrderbookStamp stamps a decision with the orderbook it was based on
+func NewOrderbookStamp(exchange string, ob orderbook.Base) DataStamp {
+	return DataStamp{
+		Exchange:    exchange,
+		Pair:        ob.Pair.Pair().String(),
+		AssetType:   ob.AssetType,
+		DataUpdated: ob.LastUpdated,
+	}
+}
+
+// VerifyDataStamp returns an error if the decision data stamp is missing,
+// belongs to another exchange or pair than the order or is older than the
+// maximum data age
+func VerifyDataStamp(exchange string, p pair.CurrencyPair, stamp DataStamp) error {
+	if stamp.DataUpdated.IsZero() {
+		return ErrDataStampRequired
+	}
+
+	if common.StringToLower(stamp.Exchange) != common.StringToLower(exchange) {
+		return fmt.Errorf("decision data stamp exchange %s does not match %s",
+			stamp.Exchange, exchange)
+	}
+
+	if stampPairKey(stamp.Pair) != p.Display("", true).String() {
+		return fmt.Errorf("decision data stamp pair %s does not match %s",
+			stamp.Pair, p.Pair())
+	}
+
+	maxAge := GetMaxDataAge()
+	age := time.Since(stamp.DataUpdated)
+	if age > maxAge {
+		return &StaleDataError{Stamp: stamp, Age: age, Max: maxAge}
+	}
+	return nil
+}
+
+// stampPairKey returns the uppercase stamp pair without a delimiter so stamps
+// match orders regardless of the delimiter the exchange formats pairs with
+func stampPairKey(p string) string {
+	for _, delimiter := range []string{"-", "_", "/"} {
+		p = common.ReplaceString(p, delimiter, "", -1)
+	}
+	return common.StringToUpper(p)
+}
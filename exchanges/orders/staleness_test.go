@@ -0,0 +1,59 @@
+package orders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+func TestSetMaxDataAge(t *testing.T) {
+	SetMaxDataAge(time.Second)
+	if GetMaxDataAge() != time.Second {
+		t.Error("Test Failed - SetMaxDataAge() age not set")
+	}
+
+	SetMaxDataAge(0)
+	if GetMaxDataAge() != DefaultMaxDataAge {
+		t.Error("Test Failed - SetMaxDataAge() default age not set")
+	}
+}
+
+func TestVerifyDataStamp(t *testing.T) {
+	SetMaxDataAge(time.Second)
+	defer SetMaxDataAge(DefaultMaxDataAge)
+
+	p := pair.NewCurrencyPairDelimiter("BTC-USD", "-")
+	err := VerifyDataStamp("Bitstamp", p, DataStamp{})
+	if err != ErrDataStampRequired {
+		t.Error("Test Failed - VerifyDataStamp() blank stamp error", err)
+	}
+
+	stamp := NewOrderbookStamp("Bitstamp", orderbook.Base{
+		Pair:        pair.NewCurrencyPair("btc", "usd"),
+		AssetType:   orderbook.Spot,
+		LastUpdated: time.Now(),
+	})
+
+	err = VerifyDataStamp("bitstamp", p, stamp)
+	if err != nil {
+		t.Error("Test Failed - VerifyDataStamp() fresh stamp error", err)
+	}
+
+	err = VerifyDataStamp("Gemini", p, stamp)
+	if err == nil {
+		t.Error("Test Failed - VerifyDataStamp() mismatched exchange returned no error")
+	}
+
+	err = VerifyDataStamp("Bitstamp", pair.NewCurrencyPair("ETH", "USD"), stamp)
+	if err == nil {
+		t.Error("Test Failed - VerifyDataStamp() mismatched pair returned no error")
+	}
+
+	stamp.DataUpdated = time.Now().Add(-time.Minute)
+	err = VerifyDataStamp("Bitstamp", p, stamp)
+	if _, ok := err.(*StaleDataError); !ok {
+		t.Error("Test Failed - VerifyDataStamp() stale stamp error", err)
+	}
+}
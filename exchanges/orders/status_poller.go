@@ -0,0 +1,172 @@
+package orders
+
+import (
+	"sort"
+	"time"
+)
+
+// Order status polling defaults
+const (
+	DefaultPollMinInterval       = time.Second * 5
+	DefaultPollMaxInterval       = time.Minute * 2
+	DefaultPollNearDistance      = 0.001
+	DefaultPollFarDistance       = 0.02
+	DefaultPollRequestsPerMinute = 30
+)
+
+// StatusPollConfig holds the settings for polling the state of managed orders
+// on exchanges without private websockets. Orders within NearDistance of the
+// touch, as a fraction of its price, are polled every MinInterval and orders
+// FarDistance or more away every MaxInterval, scaling linearly in between.
+// RequestsPerMinute budgets the status requests made to each exchange, zero
+// values use the defaults
+type StatusPollConfig struct {
+	Enabled           bool          `json:"enabled"`
+	MinInterval       time.Duration `json:"minInterval"`
+	MaxInterval       time.Duration `json:"maxInterval"`
+	NearDistance      float64       `json:"nearDistance"`
+	FarDistance       float64       `json:"farDistance"`
+	RequestsPerMinute int           `json:"requestsPerMinute"`
+}
+
+// withDefaults returns the config with unset values replaced by the defaults
+func (c StatusPollConfig) withDefaults() StatusPollConfig {
+	if c.MinInterval <= 0 {
+		c.MinInterval = DefaultPollMinInterval
+	}
+	if c.MaxInterval < c.MinInterval {
+		c.MaxInterval = DefaultPollMaxInterval
+		if c.MaxInterval < c.MinInterval {
+			c.MaxInterval = c.MinInterval
+		}
+	}
+	if c.NearDistance <= 0 {
+		c.NearDistance = DefaultPollNearDistance
+	}
+	if c.FarDistance <= c.NearDistance {
+		c.FarDistance = DefaultPollFarDistance
+		if c.FarDistance <= c.NearDistance {
+			c.FarDistance = c.NearDistance * 2
+		}
+	}
+	if c.RequestsPerMinute <= 0 {
+		c.RequestsPerMinute = DefaultPollRequestsPerMinute
+	}
+	return c
+}
+
+// Interval returns the poll interval of an order distance away from the touch
+// as a fraction of its price. Orders with an unknown, negative, distance are
+// polled every MaxInterval
+func (c StatusPollConfig) Interval(distance float64) time.Duration {
+	c = c.withDefaults()
+	if distance < 0 || distance >= c.FarDistance {
+		return c.MaxInterval
+	}
+	if distance <= c.NearDistance {
+		return c.MinInterval
+	}
+
+	scale := (distance - c.NearDistance) / (c.FarDistance - c.NearDistance)
+	return c.MinInterval + time.Duration(scale*float64(c.MaxInterval-c.MinInterval))
+}
+
+// OrderState holds the state of a managed order reported by its exchange.
+// Status is ClientOrderSubmitted while the order is open and ClientOrderFilled
+// or ClientOrderCancelled once closed. Distance is how far the order price is
+// from the touch it fills against as a fraction of the touch, negative when
+// unknown
+type OrderState struct {
+	Status   string
+	Distance float64
+}
+
+// OrderStateFunc returns the state of a managed order from its exchange
+type OrderStateFunc func(order ClientOrder) (OrderState, error)
+
+// StatusPoller polls the state of the submitted and missing orders of an
+// exchange in the client order registry, applying state changes to it
+type StatusPoller struct {
+	exchange string
+	cfg      StatusPollConfig
+	state    OrderStateFunc
+	next     map[string]time.Time
+	tokens   float64
+	refilled time.Time
+}
+
+// NewStatusPoller returns a status poller for an exchange, starting with a
+// full request budget
+func NewStatusPoller(exchange string, cfg StatusPollConfig, state OrderStateFunc) *StatusPoller {
+	cfg = cfg.withDefaults()
+	return &StatusPoller{
+		exchange: exchange,
+		cfg:      cfg,
+		state:    state,
+		next:     make(map[string]time.Time),
+		tokens:   float64(cfg.RequestsPerMinute),
+	}
+}
+
+// Poll requests the state of the orders due a poll at now, most overdue first,
+// until the request budget runs out. Orders left unpolled stay due for the
+// next call. Orders whose status changed are returned with their new status,
+// orders which fail to poll are retried after MaxInterval
+func (p *StatusPoller) Poll(now time.Time) []ClientOrder {
+	p.refill(now)
+
+	var due []ClientOrder
+	tracked := make(map[string]bool)
+	for _, order := range GetClientOrders(p.exchange, "") {
+		if order.Status != ClientOrderSubmitted && order.Status != ClientOrderMissing {
+			continue
+		}
+		tracked[order.ClientID] = true
+		if next, ok := p.next[order.ClientID]; ok && now.Before(next) {
+			continue
+		}
+		due = append(due, order)
+	}
+
+	for clientID := range p.next {
+		if !tracked[clientID] {
+			delete(p.next, clientID)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return p.next[due[i].ClientID].Before(p.next[due[j].ClientID])
+	})
+
+	var changed []ClientOrder
+	for x := range due {
+		if p.tokens < 1 {
+			break
+		}
+		p.tokens--
+
+		state, err := p.state(due[x])
+		if err != nil {
+			p.next[due[x].ClientID] = now.Add(p.cfg.MaxInterval)
+			continue
+		}
+		p.next[due[x].ClientID] = now.Add(p.cfg.Interval(state.Distance))
+
+		order, updated, err := SetClientOrderStatus(p.exchange, due[x].ClientID, state.Status)
+		if err == nil && updated {
+			changed = append(changed, order)
+		}
+	}
+	return changed
+}
+
+// refill tops up the request budget for the time passed since the last poll
+func (p *StatusPoller) refill(now time.Time) {
+	if !p.refilled.IsZero() {
+		p.tokens += now.Sub(p.refilled).Minutes() * float64(p.cfg.RequestsPerMinute)
+		if p.tokens > float64(p.cfg.RequestsPerMinute) {
+			p.tokens = float64(p.cfg.RequestsPerMinute)
+		}
+	}
+	p.refilled = now
+}
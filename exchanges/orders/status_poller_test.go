@@ -0,0 +1,142 @@
+package orders
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatusPollInterval(t *testing.T) {
+	cfg := StatusPollConfig{
+		MinInterval:  time.Second,
+		MaxInterval:  time.Second * 11,
+		NearDistance: 0.01,
+		FarDistance:  0.06,
+	}
+
+	tests := []struct {
+		distance float64
+		expected time.Duration
+	}{
+		{-1, time.Second * 11},
+		{0, time.Second},
+		{0.01, time.Second},
+		{0.035, time.Second * 6},
+		{0.06, time.Second * 11},
+		{0.5, time.Second * 11},
+	}
+
+	for _, test := range tests {
+		if interval := cfg.Interval(test.distance); interval != test.expected {
+			t.Errorf("Test Failed - Interval(%v) returned %v, expected %v",
+				test.distance, interval, test.expected)
+		}
+	}
+
+	if (StatusPollConfig{}).Interval(0) != DefaultPollMinInterval {
+		t.Error("Test Failed - Interval() defaults not applied")
+	}
+}
+
+func TestSetClientOrderStatus(t *testing.T) {
+	_, _, err := SetClientOrderStatus("Gemini", "unknown", ClientOrderFilled)
+	if err != ErrClientOrderNotFound {
+		t.Error("Test Failed - SetClientOrderStatus() unknown client ID error", err)
+	}
+
+	_, err = SubmitWithClientID("Gemini", "status-1", func(clientID string) (int64, error) {
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+
+	_, _, err = SetClientOrderStatus("Gemini", "status-1", ClientOrderPending)
+	if err == nil {
+		t.Error("Test Failed - SetClientOrderStatus() invalid status accepted")
+	}
+
+	_, changed, err := SetClientOrderStatus("Gemini", "status-1", ClientOrderSubmitted)
+	if err != nil || changed {
+		t.Error("Test Failed - SetClientOrderStatus() unchanged status reported changed", err)
+	}
+
+	order, changed, err := SetClientOrderStatus("GEMINI", "status-1", ClientOrderFilled)
+	if err != nil || !changed || order.Status != ClientOrderFilled {
+		t.Error("Test Failed - SetClientOrderStatus() filled status not applied", err)
+	}
+
+	_, _, err = SetClientOrderStatus("Gemini", "status-1", ClientOrderSubmitted)
+	if err == nil {
+		t.Error("Test Failed - SetClientOrderStatus() filled order reopened")
+	}
+}
+
+func TestStatusPoller(t *testing.T) {
+	for _, clientID := range []string{"poll-near", "poll-far", "poll-error"} {
+		_, err := SubmitWithClientID("Bittrex", clientID, func(clientID string) (int64, error) {
+			return 1, nil
+		})
+		if err != nil {
+			t.Fatal("Test Failed - SubmitWithClientID() error", err)
+		}
+	}
+
+	polled := make(map[string]int)
+	states := map[string]OrderState{
+		"poll-near": {Status: ClientOrderSubmitted, Distance: 0},
+		"poll-far":  {Status: ClientOrderSubmitted, Distance: 1},
+	}
+	poller := NewStatusPoller("Bittrex", StatusPollConfig{
+		MinInterval:       time.Second,
+		MaxInterval:       time.Minute,
+		RequestsPerMinute: 60,
+	}, func(order ClientOrder) (OrderState, error) {
+		polled[order.ClientID]++
+		if order.ClientID == "poll-error" {
+			return OrderState{}, errors.New("order status unavailable")
+		}
+		return states[order.ClientID], nil
+	})
+
+	now := time.Now()
+	if changed := poller.Poll(now); len(changed) != 0 {
+		t.Error("Test Failed - Poll() unexpected changed orders", changed)
+	}
+
+	states["poll-near"] = OrderState{Status: ClientOrderFilled}
+	changed := poller.Poll(now.Add(time.Second * 2))
+	if len(changed) != 1 || changed[0].ClientID != "poll-near" ||
+		changed[0].Status != ClientOrderFilled {
+		t.Error("Test Failed - Poll() filled order not returned", changed)
+	}
+
+	if polled["poll-near"] != 2 || polled["poll-far"] != 1 || polled["poll-error"] != 1 {
+		t.Error("Test Failed - Poll() orders not polled at their interval", polled)
+	}
+
+	poller.Poll(now.Add(time.Second * 4))
+	if polled["poll-near"] != 2 {
+		t.Error("Test Failed - Poll() filled order polled")
+	}
+
+	budget := NewStatusPoller("Bittrex", StatusPollConfig{RequestsPerMinute: 1},
+		func(order ClientOrder) (OrderState, error) {
+			return OrderState{Status: ClientOrderSubmitted, Distance: -1}, nil
+		})
+	budget.Poll(now)
+	budget.next = make(map[string]time.Time)
+	var requests int
+	budget.state = func(order ClientOrder) (OrderState, error) {
+		requests++
+		return OrderState{Status: ClientOrderSubmitted}, nil
+	}
+	budget.Poll(now.Add(time.Second * 30))
+	if requests != 0 {
+		t.Error("Test Failed - Poll() request budget exceeded", requests)
+	}
+	budget.Poll(now.Add(time.Second * 61))
+	if requests != 1 {
+		t.Error("Test Failed - Poll() request budget not refilled", requests)
+	}
+}
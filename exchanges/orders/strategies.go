@@ -0,0 +1,184 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Strategy limit rejection reasons
+const (
+	StrategyLimitOrderValue = "ORDER_VALUE"
+	StrategyLimitPosition   = "POSITION"
+	StrategyLimitLoss       = "LOSS"
+)
+
+// Vars for the strategy attribution registry
+var (
+	orderStrategies    = make(map[string]map[int64]string)
+	strategyLimits     = make(map[string]StrategyLimits)
+	orderStrategiesMtx sync.Mutex
+
+	// ErrStrategyIDRequired is returned when tagging an order without a
+	// strategy ID
+	ErrStrategyIDRequired = errors.New("strategy ID required")
+)
+
+// StrategyLimits holds the risk limits of a strategy, zero values are not
+// enforced. MaxOrderValue is the amount multiplied by the price of a single
+// order, MaxPosition is the absolute open position per exchange pair and
+// MaxLoss is the realised net loss across all pairs after which new orders
+// are rejected
+type StrategyLimits struct {
+	MaxOrderValue float64 `json:"maxOrderValue"`
+	MaxPosition   float64 `json:"maxPosition"`
+	MaxLoss       float64 `json:"maxLoss"`
+}
+
+// StrategyLimitError is returned when an order would break a risk limit of
+// the strategy placing it
+type StrategyLimitError struct {
+	StrategyID string
+	Reason     string
+	Limit      float64
+	Value      float64
+}
+
+func (e *StrategyLimitError) Error() string {
+	return fmt.Sprintf("strategy %s order rejected: %s limit %v, would be %v",
+		e.StrategyID, e.Reason, e.Limit, e.Value)
+}
+
+// TagOrder attributes an exchange order to a strategy so its fills, positions
+// and profit and loss are reported against the strategy
+func TagOrder(exchange string, orderID int64, strategyID string) error {
+	if strategyID == "" {
+		return ErrStrategyIDRequired
+	}
+
+	exchange = common.StringToLower(exchange)
+	orderStrategiesMtx.Lock()
+	defer orderStrategiesMtx.Unlock()
+
+	if _, ok := orderStrategies[exchange]; !ok {
+		orderStrategies[exchange] = make(map[int64]string)
+	}
+	orderStrategies[exchange][orderID] = strategyID
+	return nil
+}
+
+// GetOrderStrategy returns the strategy an exchange order was tagged with, or
+// a blank string if the order wasn't tagged
+func GetOrderStrategy(exchange string, orderID int64) string {
+	orderStrategiesMtx.Lock()
+	defer orderStrategiesMtx.Unlock()
+	return orderStrategies[common.StringToLower(exchange)][orderID]
+}
+
+// SetStrategyLimits sets the risk limits of a strategy, zero limits remove
+// them
+func SetStrategyLimits(strategyID string, limits StrategyLimits) {
+	orderStrategiesMtx.Lock()
+	defer orderStrategiesMtx.Unlock()
+
+	if limits == (StrategyLimits{}) {
+		delete(strategyLimits, strategyID)
+		return
+	}
+	strategyLimits[strategyID] = limits
+}
+
+// GetStrategyLimits returns the risk limits of a strategy
+func GetStrategyLimits(strategyID string) (StrategyLimits, bool) {
+	orderStrategiesMtx.Lock()
+	defer orderStrategiesMtx.Unlock()
+	limits, ok := strategyLimits[strategyID]
+	return limits, ok
+}
+
+// getStrategyFills returns the fills attributed to a strategy executed before
+// the end time, a blank strategy ID matches all attributed fills
+func getStrategyFills(strategyID string, end time.Time) []Fill {
+	var result []Fill
+	for _, f := range GetFills("", "", end) {
+		if f.StrategyID == "" || (strategyID != "" && f.StrategyID != strategyID) {
+			continue
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// GetStrategyProfitLossReport returns the realised profit and loss of a
+// strategy per exchange pair for fills executed between the start and end
+// times, with positions tracked separately from other strategies trading the
+// same account. A blank strategy ID reports every strategy
+func GetStrategyProfitLossReport(strategyID string, start, end time.Time) []ProfitLoss {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return buildProfitLossReport(getStrategyFills(strategyID, end), start, end, true)
+}
+
+// CheckStrategyLimits returns a *StrategyLimitError if an order placed by a
+// strategy would break its risk limits. Market orders should pass a zero
+// price, skipping the order value check
+func CheckStrategyLimits(strategyID, exchange, pair, side string, amount, price float64) error {
+	limits, ok := GetStrategyLimits(strategyID)
+	if !ok {
+		return nil
+	}
+
+	if limits.MaxOrderValue > 0 && price > 0 && amount*price > limits.MaxOrderValue {
+		return &StrategyLimitError{
+			StrategyID: strategyID,
+			Reason:     StrategyLimitOrderValue,
+			Limit:      limits.MaxOrderValue,
+			Value:      amount * price,
+		}
+	}
+
+	if limits.MaxPosition == 0 && limits.MaxLoss == 0 {
+		return nil
+	}
+
+	var netPnL, openPosition float64
+	for _, report := range GetStrategyProfitLossReport(strategyID, time.Time{}, time.Time{}) {
+		netPnL += report.NetPnL
+		if common.StringToLower(report.Exchange) == common.StringToLower(exchange) &&
+			report.Pair == common.StringToUpper(pair) {
+			openPosition = report.OpenPosition
+		}
+	}
+
+	if limits.MaxLoss > 0 && -netPnL >= limits.MaxLoss {
+		return &StrategyLimitError{
+			StrategyID: strategyID,
+			Reason:     StrategyLimitLoss,
+			Limit:      limits.MaxLoss,
+			Value:      -netPnL,
+		}
+	}
+
+	if limits.MaxPosition > 0 {
+		if common.StringToUpper(side) == FillSell {
+			openPosition -= amount
+		} else {
+			openPosition += amount
+		}
+
+		if math.Abs(openPosition) > limits.MaxPosition {
+			return &StrategyLimitError{
+				StrategyID: strategyID,
+				Reason:     StrategyLimitPosition,
+				Limit:      limits.MaxPosition,
+				Value:      math.Abs(openPosition),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package orders
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStrategyProfitLossReport(t *testing.T) {
+	now := time.Now()
+	defer RemoveFills(now.Add(time.Hour))
+
+	err := TagOrder("Bitstamp", 1, "")
+	if err != ErrStrategyIDRequired {
+		t.Error("Test Failed - TagOrder() blank strategy ID error", err)
+	}
+
+	err = TagOrder("Bitstamp", 1, "momentum")
+	if err != nil {
+		t.Fatal("Test Failed - TagOrder() error", err)
+	}
+
+	if GetOrderStrategy("BITSTAMP", 1) != "momentum" {
+		t.Error("Test Failed - GetOrderStrategy() strategy not found")
+	}
+
+	testFills := []Fill{
+		{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "buy", OrderID: 1, Amount: 2, Price: 100, Timestamp: now.Add(-time.Minute * 3)},
+		{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "buy", StrategyID: "grid", Amount: 1, Price: 300, Timestamp: now.Add(-time.Minute * 2)},
+		{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "sell", StrategyID: "momentum", Amount: 1, Price: 200, Fee: 1, Timestamp: now.Add(-time.Minute)},
+		{Exchange: "Bitstamp", Pair: "BTCUSD", Side: "sell", Amount: 1, Price: 200, Timestamp: now.Add(-time.Minute)},
+	}
+	for x := range testFills {
+		err = RecordFill(testFills[x])
+		if err != nil {
+			t.Fatal("Test Failed - RecordFill() error", err)
+		}
+	}
+
+	report := GetStrategyProfitLossReport("", time.Time{}, now)
+	if len(report) != 2 {
+		t.Fatalf("Test Failed - GetStrategyProfitLossReport() expected 2 strategies, got %d", len(report))
+	}
+
+	// Positions are tracked separately so the grid buy doesn't change the
+	// momentum cost basis
+	if report[1].Strategy != "momentum" || report[1].NetPnL != 99 || report[1].OpenPosition != 1 {
+		t.Errorf("Test Failed - GetStrategyProfitLossReport() unexpected momentum report %+v", report[1])
+	}
+
+	if report[0].Strategy != "grid" || report[0].OpenPosition != 1 || report[0].AverageCost != 300 {
+		t.Errorf("Test Failed - GetStrategyProfitLossReport() unexpected grid report %+v", report[0])
+	}
+
+	report = GetStrategyProfitLossReport("grid", time.Time{}, now)
+	if len(report) != 1 || report[0].Fills != 1 {
+		t.Errorf("Test Failed - GetStrategyProfitLossReport() unexpected single strategy report %+v", report)
+	}
+}
+
+func TestCheckStrategyLimits(t *testing.T) {
+	now := time.Now()
+	defer RemoveFills(now.Add(time.Hour))
+	defer SetStrategyLimits("arb", StrategyLimits{})
+
+	err := CheckStrategyLimits("arb", "Gemini", "ETHUSD", "Buy", 100, 1000)
+	if err != nil {
+		t.Error("Test Failed - CheckStrategyLimits() without limits error", err)
+	}
+
+	SetStrategyLimits("arb", StrategyLimits{MaxOrderValue: 1000, MaxPosition: 2, MaxLoss: 50})
+	err = CheckStrategyLimits("arb", "Gemini", "ETHUSD", "Buy", 3, 500)
+	if e, ok := err.(*StrategyLimitError); !ok || e.Reason != StrategyLimitOrderValue {
+		t.Error("Test Failed - CheckStrategyLimits() order value error", err)
+	}
+
+	err = RecordFill(Fill{Exchange: "Gemini", Pair: "ETHUSD", Side: "buy", StrategyID: "arb", Amount: 2, Price: 100, Timestamp: now.Add(-time.Minute)})
+	if err != nil {
+		t.Fatal("Test Failed - RecordFill() error", err)
+	}
+
+	err = CheckStrategyLimits("arb", "Gemini", "ethusd", "Buy", 1, 100)
+	if e, ok := err.(*StrategyLimitError); !ok || e.Reason != StrategyLimitPosition {
+		t.Error("Test Failed - CheckStrategyLimits() position error", err)
+	}
+
+	err = CheckStrategyLimits("arb", "Gemini", "ETHUSD", "Sell", 1, 100)
+	if err != nil {
+		t.Error("Test Failed - CheckStrategyLimits() reducing position error", err)
+	}
+
+	err = RecordFill(Fill{Exchange: "Gemini", Pair: "ETHUSD", Side: "sell", StrategyID: "arb", Amount: 1, Price: 40, Timestamp: now})
+	if err != nil {
+		t.Fatal("Test Failed - RecordFill() error", err)
+	}
+
+	err = CheckStrategyLimits("arb", "Gemini", "ETHUSD", "Sell", 1, 100)
+	if e, ok := err.(*StrategyLimitError); !ok || e.Reason != StrategyLimitLoss {
+		t.Error("Test Failed - CheckStrategyLimits() loss error", err)
+	}
+}
@@ -0,0 +1,269 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Strategy shutdown policies, applied when the bot shuts down gracefully or
+// the risk limits of the strategy trip
+const (
+	// ShutdownLeave leaves the orders and positions of the strategy as they
+	// are
+	ShutdownLeave = "LEAVE"
+	// ShutdownCancelOrders cancels the open orders of the strategy
+	ShutdownCancelOrders = "CANCEL_ORDERS"
+	// ShutdownFlattenPositions cancels the open orders of the strategy and
+	// closes its open positions with market orders
+	ShutdownFlattenPositions = "FLATTEN_POSITIONS"
+)
+
+// Vars for the strategy shutdown policies
+var (
+	shutdownPolicies    = make(map[string]string)
+	shutdownStrategies  = make(map[string]bool)
+	shutdownPoliciesMtx sync.Mutex
+
+	// ErrInvalidShutdownPolicy is returned when a strategy shutdown policy
+	// isn't one of leave, cancel orders or flatten positions
+	ErrInvalidShutdownPolicy = errors.New("shutdown policy must be LEAVE, CANCEL_ORDERS or FLATTEN_POSITIONS")
+)
+
+// StrategyCancelFunc cancels an open order of a strategy, abandoning the
+// cancellation when the context is done
+type StrategyCancelFunc func(ctx context.Context, order ClientOrder) error
+
+// StrategyFlattenFunc submits a market order on behalf of a strategy closing
+// its open position of a pair, formatted as in its fills, and returns the
+// exchange order ID. The order must not be submitted once the context is done
+type StrategyFlattenFunc func(ctx context.Context, strategyID, exchange, pair, side string, amount float64) (int64, error)
+
+// ShutdownOrder holds an order cancelled or submitted by a strategy shutdown
+type ShutdownOrder struct {
+	Exchange string  `json:"exchange"`
+	Pair     string  `json:"pair,omitempty"`
+	Side     string  `json:"side,omitempty"`
+	Amount   float64 `json:"amount,omitempty"`
+	OrderID  int64   `json:"orderID"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// StrategyShutdown holds the outcome of applying the shutdown policy of a
+// strategy. TimedOut is set when the time budget ran out before every order
+// was cancelled or submitted
+type StrategyShutdown struct {
+	StrategyID string          `json:"strategyID"`
+	Policy     string          `json:"policy"`
+	Reason     string          `json:"reason"`
+	Cancelled  []ShutdownOrder `json:"cancelled,omitempty"`
+	Flattened  []ShutdownOrder `json:"flattened,omitempty"`
+	TimedOut   bool            `json:"timedOut"`
+}
+
+// ParseShutdownPolicy returns the shutdown policy in upper case, an empty
+// policy being ShutdownLeave
+func ParseShutdownPolicy(policy string) (string, error) {
+	policy = common.StringToUpper(policy)
+	switch policy {
+	case "":
+		return ShutdownLeave, nil
+	case ShutdownLeave, ShutdownCancelOrders, ShutdownFlattenPositions:
+		return policy, nil
+	}
+	return "", ErrInvalidShutdownPolicy
+}
+
+// SetStrategyShutdownPolicy sets the shutdown policy of a strategy
+func SetStrategyShutdownPolicy(strategyID, policy string) error {
+	if strategyID == "" {
+		return ErrStrategyIDRequired
+	}
+
+	policy, err := ParseShutdownPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	shutdownPoliciesMtx.Lock()
+	defer shutdownPoliciesMtx.Unlock()
+	if policy == ShutdownLeave {
+		delete(shutdownPolicies, strategyID)
+		return nil
+	}
+	shutdownPolicies[strategyID] = policy
+	return nil
+}
+
+// GetStrategyShutdownPolicy returns the shutdown policy of a strategy,
+// ShutdownLeave when none is set
+func GetStrategyShutdownPolicy(strategyID string) string {
+	shutdownPoliciesMtx.Lock()
+	defer shutdownPoliciesMtx.Unlock()
+	if policy, ok := shutdownPolicies[strategyID]; ok {
+		return policy
+	}
+	return ShutdownLeave
+}
+
+// GetShutdownStrategies returns the IDs of the strategies with a shutdown
+// policy other than ShutdownLeave
+func GetShutdownStrategies() []string {
+	shutdownPoliciesMtx.Lock()
+	defer shutdownPoliciesMtx.Unlock()
+	var result []string
+	for strategyID := range shutdownPolicies {
+		result = append(result, strategyID)
+	}
+	return result
+}
+
+// ShutdownStrategy applies the shutdown policy of a strategy once, cancelling
+// its open orders and then, when flattening, closing its open positions until
+// the deadline. Cancellations and orders still running when the deadline
+// passes are abandoned through their context. It returns false without applying the policy when the
+// strategy has already been shut down, so a policy triggered by a risk limit
+// isn't repeated during the graceful shutdown of the bot
+func ShutdownStrategy(strategyID, reason string, deadline time.Time, cancel StrategyCancelFunc, flatten StrategyFlattenFunc) (StrategyShutdown, bool) {
+	result := StrategyShutdown{
+		StrategyID: strategyID,
+		Policy:     GetStrategyShutdownPolicy(strategyID),
+		Reason:     reason,
+	}
+
+	shutdownPoliciesMtx.Lock()
+	if shutdownStrategies[strategyID] {
+		shutdownPoliciesMtx.Unlock()
+		return result, false
+	}
+	shutdownStrategies[strategyID] = true
+	shutdownPoliciesMtx.Unlock()
+
+	if result.Policy == ShutdownLeave {
+		return result, true
+	}
+
+	ctx, cancelCtx := context.WithDeadline(context.Background(), deadline)
+	defer cancelCtx()
+
+	open := getStrategyOpenOrders(strategyID)
+	results := make(chan ShutdownOrder, len(open))
+	for x := range open {
+		go func(order ClientOrder) {
+			s := ShutdownOrder{Exchange: order.Exchange, OrderID: order.OrderID}
+			if err := cancel(ctx, order); err != nil {
+				s.Error = err.Error()
+			} else {
+				MarkClientOrderCancelled(order.Exchange, order.ClientID)
+			}
+			results <- s
+		}(open[x])
+	}
+	result.Cancelled, result.TimedOut = collectShutdownOrders(results, len(open), deadline)
+
+	if result.Policy != ShutdownFlattenPositions || result.TimedOut {
+		return result, true
+	}
+
+	var positions []ShutdownOrder
+	for _, report := range GetStrategyProfitLossReport(strategyID, time.Time{}, time.Time{}) {
+		if math.Abs(report.OpenPosition) < spreadTolerance {
+			continue
+		}
+
+		s := ShutdownOrder{Exchange: report.Exchange, Pair: report.Pair,
+			Side: FillSell, Amount: report.OpenPosition}
+		if report.OpenPosition < 0 {
+			s.Side, s.Amount = FillBuy, -report.OpenPosition
+		}
+		positions = append(positions, s)
+	}
+
+	results = make(chan ShutdownOrder, len(positions))
+	for x := range positions {
+		go func(s ShutdownOrder) {
+			orderID, err := flatten(ctx, strategyID, s.Exchange, s.Pair, s.Side, s.Amount)
+			if err != nil {
+				s.Error = err.Error()
+			}
+			s.OrderID = orderID
+			results <- s
+		}(positions[x])
+	}
+	result.Flattened, result.TimedOut = collectShutdownOrders(results, len(positions), deadline)
+	return result, true
+}
+
+// String returns a report of a strategy shutdown
+func (s *StrategyShutdown) String() string {
+	report := fmt.Sprintf("Strategy %s shut down (%s) with policy %s",
+		s.StrategyID, s.Reason, s.Policy)
+	if s.TimedOut {
+		report += ", timed out"
+	}
+	report += "\n"
+
+	for _, o := range s.Cancelled {
+		report += fmt.Sprintf("Cancel %s order %d", o.Exchange, o.OrderID)
+		if o.Error != "" {
+			report += " failed: " + o.Error
+		}
+		report += "\n"
+	}
+	for _, o := range s.Flattened {
+		report += fmt.Sprintf("Flatten %s %s %f on %s", o.Side, o.Pair, o.Amount, o.Exchange)
+		if o.Error != "" {
+			report += " failed: " + o.Error
+		} else {
+			report += fmt.Sprintf(", order %d", o.OrderID)
+		}
+		report += "\n"
+	}
+	return report
+}
+
+// collectShutdownOrders returns the orders received until count have been
+// received or the deadline passes, and whether it passed
+func collectShutdownOrders(results <-chan ShutdownOrder, count int, deadline time.Time) ([]ShutdownOrder, bool) {
+	timeout := time.NewTimer(time.Until(deadline))
+	defer timeout.Stop()
+
+	var collected []ShutdownOrder
+	for len(collected) < count {
+		select {
+		case s := <-results:
+			collected = append(collected, s)
+		case <-timeout.C:
+			return collected, true
+		}
+	}
+	return collected, false
+}
+
+// getStrategyOpenOrders returns the submitted orders of every exchange which
+// were tagged with the strategy
+func getStrategyOpenOrders(strategyID string) []ClientOrder {
+	clientOrdersMtx.Lock()
+	var submitted []ClientOrder
+	for _, exchangeOrders := range clientOrders {
+		for _, order := range exchangeOrders {
+			if order.Status == ClientOrderSubmitted {
+				submitted = append(submitted, *order)
+			}
+		}
+	}
+	clientOrdersMtx.Unlock()
+
+	var result []ClientOrder
+	for x := range submitted {
+		if GetOrderStrategy(submitted[x].Exchange, submitted[x].OrderID) == strategyID {
+			result = append(result, submitted[x])
+		}
+	}
+	return result
+}
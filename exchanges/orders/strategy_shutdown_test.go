@@ -0,0 +1,115 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetStrategyShutdownPolicy(t *testing.T) {
+	if err := SetStrategyShutdownPolicy("momentum", "sell_everything"); err != ErrInvalidShutdownPolicy {
+		t.Error("Test Failed - SetStrategyShutdownPolicy() invalid policy error", err)
+	}
+
+	if err := SetStrategyShutdownPolicy("momentum", "cancel_orders"); err != nil {
+		t.Fatal("Test Failed - SetStrategyShutdownPolicy() error", err)
+	}
+	if policy := GetStrategyShutdownPolicy("momentum"); policy != ShutdownCancelOrders {
+		t.Errorf("Test Failed - GetStrategyShutdownPolicy() expected %s, got %s", ShutdownCancelOrders, policy)
+	}
+
+	if err := SetStrategyShutdownPolicy("momentum", ""); err != nil {
+		t.Fatal("Test Failed - SetStrategyShutdownPolicy() error", err)
+	}
+	if policy := GetStrategyShutdownPolicy("momentum"); policy != ShutdownLeave {
+		t.Errorf("Test Failed - GetStrategyShutdownPolicy() expected %s, got %s", ShutdownLeave, policy)
+	}
+}
+
+func TestShutdownStrategy(t *testing.T) {
+	now := time.Now()
+	defer RemoveFills(now.Add(time.Hour))
+
+	_, err := SubmitWithClientID("Kraken", "shutdown-flat-1", func(clientID string) (int64, error) {
+		return 8101, nil
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+	TagOrder("Kraken", 8101, "shutdown-flat")
+
+	err = RecordFill(Fill{Exchange: "Kraken", Pair: "BTCUSD", Side: "buy", StrategyID: "shutdown-flat",
+		Amount: 2, Price: 100, Timestamp: now.Add(-time.Minute)})
+	if err != nil {
+		t.Fatal("Test Failed - RecordFill() error", err)
+	}
+
+	if err = SetStrategyShutdownPolicy("shutdown-flat", ShutdownFlattenPositions); err != nil {
+		t.Fatal("Test Failed - SetStrategyShutdownPolicy() error", err)
+	}
+
+	var cancelled []int64
+	cancel := func(ctx context.Context, order ClientOrder) error {
+		cancelled = append(cancelled, order.OrderID)
+		return nil
+	}
+	flatten := func(ctx context.Context, strategyID, exchange, pair, side string, amount float64) (int64, error) {
+		if strategyID != "shutdown-flat" || pair != "BTCUSD" || side != FillSell || amount != 2 {
+			return 0, errors.New("unexpected flatten order")
+		}
+		return 8102, nil
+	}
+
+	result, ok := ShutdownStrategy("shutdown-flat", "test", now.Add(time.Second), cancel, flatten)
+	if !ok || result.TimedOut || len(cancelled) != 1 || cancelled[0] != 8101 ||
+		len(result.Flattened) != 1 || result.Flattened[0].OrderID != 8102 || result.Flattened[0].Error != "" {
+		t.Fatalf("Test Failed - ShutdownStrategy() unexpected result %+v", result)
+	}
+
+	order, _ := GetOrderByClientID("Kraken", "shutdown-flat-1")
+	if order.Status != ClientOrderCancelled {
+		t.Errorf("Test Failed - ShutdownStrategy() expected order status %s, got %s",
+			ClientOrderCancelled, order.Status)
+	}
+
+	if _, ok = ShutdownStrategy("shutdown-flat", "test", now.Add(time.Second), cancel, flatten); ok {
+		t.Error("Test Failed - ShutdownStrategy() applied the policy twice")
+	}
+}
+
+func TestShutdownStrategyTimeout(t *testing.T) {
+	_, err := SubmitWithClientID("Kraken", "shutdown-slow-1", func(clientID string) (int64, error) {
+		return 8201, nil
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SubmitWithClientID() error", err)
+	}
+	TagOrder("Kraken", 8201, "shutdown-slow")
+	SetStrategyShutdownPolicy("shutdown-slow", ShutdownCancelOrders)
+
+	abandoned := make(chan error, 1)
+	cancel := func(ctx context.Context, order ClientOrder) error {
+		<-ctx.Done()
+		abandoned <- ctx.Err()
+		return ctx.Err()
+	}
+	result, ok := ShutdownStrategy("shutdown-slow", "test", time.Now().Add(time.Millisecond*10), cancel, nil)
+	if !ok || !result.TimedOut || len(result.Cancelled) != 0 {
+		t.Errorf("Test Failed - ShutdownStrategy() expected time out, got %+v", result)
+	}
+
+	select {
+	case err = <-abandoned:
+		if err == nil {
+			t.Error("Test Failed - ShutdownStrategy() cancellation context not done")
+		}
+	case <-time.After(time.Second):
+		t.Error("Test Failed - ShutdownStrategy() timed out cancellation not abandoned")
+	}
+
+	result, ok = ShutdownStrategy("shutdown-leave", "test", time.Now().Add(time.Second), cancel, nil)
+	if !ok || result.Policy != ShutdownLeave || len(result.Cancelled) != 0 {
+		t.Errorf("Test Failed - ShutdownStrategy() unexpected leave result %+v", result)
+	}
+}
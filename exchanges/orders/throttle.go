@@ -0,0 +1,125 @@
+package orders
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Order throttle scopes
+const (
+	ThrottleScopePair     = "pair"
+	ThrottleScopeStrategy = "strategy"
+)
+
+// Vars for the order throttle
+var (
+	pairThrottle        ThrottleLimits
+	strategyThrottle    ThrottleLimits
+	throttleSubmissions = make(map[string][]time.Time)
+	throttleMtx         sync.Mutex
+)
+
+// ThrottleLimits holds the maximum number of order submissions allowed per
+// second and per minute, zero values are not enforced
+type ThrottleLimits struct {
+	PerSecond int `json:"perSecond"`
+	PerMinute int `json:"perMinute"`
+}
+
+// ThrottleError is returned when an order submission would exceed the order
+// throttle of a pair or strategy
+type ThrottleError struct {
+	Scope  string
+	Key    string
+	Limit  int
+	Window time.Duration
+}
+
+func (e *ThrottleError) Error() string {
+	return fmt.Sprintf("%s %s order throttled: limit of %d orders per %v reached",
+		e.Scope, e.Key, e.Limit, e.Window)
+}
+
+// SetThrottleLimits sets the order submission limits applied to each
+// exchange pair and to each strategy
+func SetThrottleLimits(perPair, perStrategy ThrottleLimits) {
+	throttleMtx.Lock()
+	pairThrottle = perPair
+	strategyThrottle = perStrategy
+	throttleMtx.Unlock()
+}
+
+// GetThrottleLimits returns the order submission limits applied to each
+// exchange pair and to each strategy
+func GetThrottleLimits() (perPair, perStrategy ThrottleLimits) {
+	throttleMtx.Lock()
+	defer throttleMtx.Unlock()
+	return pairThrottle, strategyThrottle
+}
+
+// checkThrottle returns a *ThrottleError if another submission would exceed
+// the limits, pruning submissions older than a minute
+func checkThrottle(scope, key string, limits ThrottleLimits, now time.Time) error {
+	if limits.PerSecond <= 0 && limits.PerMinute <= 0 {
+		return nil
+	}
+
+	mapKey := scope + ":" + key
+	var recent []time.Time
+	var lastSecond int
+	for _, t := range throttleSubmissions[mapKey] {
+		if now.Sub(t) >= time.Minute {
+			continue
+		}
+		recent = append(recent, t)
+		if now.Sub(t) < time.Second {
+			lastSecond++
+		}
+	}
+	throttleSubmissions[mapKey] = recent
+
+	if limits.PerSecond > 0 && lastSecond >= limits.PerSecond {
+		return &ThrottleError{Scope: scope, Key: key, Limit: limits.PerSecond, Window: time.Second}
+	}
+
+	if limits.PerMinute > 0 && len(recent) >= limits.PerMinute {
+		return &ThrottleError{Scope: scope, Key: key, Limit: limits.PerMinute, Window: time.Minute}
+	}
+	return nil
+}
+
+// Throttle records an order submission for an exchange pair and optional
+// strategy, returning a *ThrottleError without recording it if it would exceed
+// the pair or strategy limits. This is independent of the exchange HTTP rate
+// limiter and guards against order storms from misbehaving strategies
+func Throttle(exchange, pair, strategyID string) error {
+	throttleMtx.Lock()
+	defer throttleMtx.Unlock()
+
+	now := time.Now()
+	pairKey := common.StringToLower(exchange) + " " + common.StringToUpper(pair)
+	err := checkThrottle(ThrottleScopePair, pairKey, pairThrottle, now)
+	if err != nil {
+		return err
+	}
+
+	if strategyID != "" {
+		err = checkThrottle(ThrottleScopeStrategy, strategyID, strategyThrottle, now)
+		if err != nil {
+			return err
+		}
+		if strategyThrottle != (ThrottleLimits{}) {
+			strategyKey := ThrottleScopeStrategy + ":" + strategyID
+			throttleSubmissions[strategyKey] = append(throttleSubmissions[strategyKey], now)
+		}
+	}
+
+	if pairThrottle != (ThrottleLimits{}) {
+		pairMapKey := ThrottleScopePair + ":" + pairKey
+		throttleSubmissions[pairMapKey] = append(throttleSubmissions[pairMapKey], now)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package orders
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	defer SetThrottleLimits(ThrottleLimits{}, ThrottleLimits{})
+
+	err := Throttle("Bitstamp", "BTCUSD", "momentum")
+	if err != nil {
+		t.Error("Test Failed - Throttle() unlimited error", err)
+	}
+
+	SetThrottleLimits(ThrottleLimits{PerSecond: 2}, ThrottleLimits{PerMinute: 3})
+	perPair, perStrategy := GetThrottleLimits()
+	if perPair.PerSecond != 2 || perStrategy.PerMinute != 3 {
+		t.Error("Test Failed - GetThrottleLimits() unexpected limits")
+	}
+
+	for x := 0; x < 2; x++ {
+		err = Throttle("Bitstamp", "BTCUSD", "momentum")
+		if err != nil {
+			t.Fatal("Test Failed - Throttle() error", err)
+		}
+	}
+
+	err = Throttle("BITSTAMP", "btcusd", "")
+	throttleErr, ok := err.(*ThrottleError)
+	if !ok || throttleErr.Scope != ThrottleScopePair || throttleErr.Window != time.Second {
+		t.Fatal("Test Failed - Throttle() expected pair throttle error", err)
+	}
+
+	err = Throttle("Bitstamp", "LTCUSD", "momentum")
+	if err != nil {
+		t.Fatal("Test Failed - Throttle() other pair error", err)
+	}
+
+	err = Throttle("Bitstamp", "ETHUSD", "momentum")
+	throttleErr, ok = err.(*ThrottleError)
+	if !ok || throttleErr.Scope != ThrottleScopeStrategy || throttleErr.Limit != 3 {
+		t.Fatal("Test Failed - Throttle() expected strategy throttle error", err)
+	}
+
+	err = Throttle("Bitstamp", "ETHUSD", "grid")
+	if err != nil {
+		t.Error("Test Failed - Throttle() other strategy error", err)
+	}
+}
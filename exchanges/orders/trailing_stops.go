@@ -0,0 +1,341 @@
+package orders
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Trailing stop offset types
+const (
+	TrailAbsolute   = "ABSOLUTE"
+	TrailPercentage = "PERCENTAGE"
+)
+
+// Consts for trailing stops
+const (
+	// TrailingStopMaxAttempts is the number of times a stop is triggered
+	// before a failed order submission leaves it triggered
+	TrailingStopMaxAttempts = 3
+
+	// trailingStopSaveInterval limits how often stop price moves alone are
+	// persisted, stops being added, adjusted, triggered or re-armed are
+	// persisted straight away
+	trailingStopSaveInterval = time.Minute
+)
+
+// Vars for the trailing stop store
+var (
+	trailingStops     []*TrailingStop
+	trailingStopsID   int64
+	trailingStopsFile string
+	trailingStopsMtx  sync.Mutex
+	trailingStopsSave time.Time
+	trailingStopsMove bool
+
+	// ErrInvalidTrailingStop is returned when a trailing stop is missing
+	// required details
+	ErrInvalidTrailingStop = errors.New("trailing stop requires an exchange, pair, side, amount and offset")
+	// ErrInvalidTrailOffset is returned when a trail offset is not positive or
+	// a percentage offset is not below 100
+	ErrInvalidTrailOffset = errors.New("invalid trailing stop offset")
+	// ErrTrailingStopNotFound is returned when no live trailing stop exists
+	// with the ID
+	ErrTrailingStopNotFound = errors.New("trailing stop not found")
+)
+
+// TrailingStop holds a stop order which follows the market by an offset. A sell
+// stop protects a long position, trailing below the best bid as it rises, while
+// a buy stop protects a short position, trailing above the best ask as it
+// falls. The stop triggers once the market moves back through the stop price
+type TrailingStop struct {
+	ID           int64             `json:"id"`
+	Exchange     string            `json:"exchange"`
+	Pair         pair.CurrencyPair `json:"pair"`
+	AssetType    string            `json:"assetType"`
+	Side         string            `json:"side"`
+	Amount       float64           `json:"amount"`
+	OffsetType   string            `json:"offsetType"`
+	Offset       float64           `json:"offset"`
+	BestPrice    float64           `json:"bestPrice"`
+	StopPrice    float64           `json:"stopPrice"`
+	Triggered    bool              `json:"triggered"`
+	TriggerPrice float64           `json:"triggerPrice,omitempty"`
+	Attempts     int               `json:"attempts,omitempty"`
+	Owner        string            `json:"owner,omitempty"`
+	Created      time.Time         `json:"created"`
+	Updated      time.Time         `json:"updated"`
+}
+
+// calculateStopPrice sets the stop price from the best price and offset
+func (t *TrailingStop) calculateStopPrice() {
+	if t.BestPrice == 0 {
+		return
+	}
+
+	offset := t.Offset
+	if t.OffsetType == TrailPercentage {
+		offset = t.BestPrice * t.Offset / 100
+	}
+
+	if t.Side == FillSell {
+		t.StopPrice = t.BestPrice - offset
+		return
+	}
+	t.StopPrice = t.BestPrice + offset
+}
+
+// update moves the stop with the best bid or ask and returns whether the stop
+// has triggered
+func (t *TrailingStop) update(bid, ask float64) bool {
+	price := ask
+	if t.Side == FillSell {
+		price = bid
+	}
+
+	if price <= 0 {
+		return false
+	}
+
+	if t.BestPrice == 0 ||
+		(t.Side == FillSell && price > t.BestPrice) ||
+		(t.Side == FillBuy && price < t.BestPrice) {
+		t.BestPrice = price
+		t.calculateStopPrice()
+		t.Updated = time.Now()
+		return false
+	}
+
+	if (t.Side == FillSell && price <= t.StopPrice) ||
+		(t.Side == FillBuy && price >= t.StopPrice) {
+		t.Triggered = true
+		t.TriggerPrice = price
+		t.Attempts++
+		t.Updated = time.Now()
+		return true
+	}
+	return false
+}
+
+// isValidTrailOffset checks the offset type and distance
+func isValidTrailOffset(offsetType string, offset float64) bool {
+	switch offsetType {
+	case TrailAbsolute:
+		return offset > 0
+	case TrailPercentage:
+		return offset > 0 && offset < 100
+	}
+	return false
+}
+
+// AddTrailingStop adds a trailing stop and returns its ID. The stop price is
+// set from the best price if supplied, otherwise from the next market update
+func AddTrailingStop(t TrailingStop) (int64, error) {
+	t.Side = common.StringToUpper(t.Side)
+	t.OffsetType = common.StringToUpper(t.OffsetType)
+	if t.Exchange == "" || t.Pair.Empty() || t.Amount <= 0 ||
+		(t.Side != FillBuy && t.Side != FillSell) {
+		return 0, ErrInvalidTrailingStop
+	}
+
+	if !isValidTrailOffset(t.OffsetType, t.Offset) {
+		return 0, ErrInvalidTrailOffset
+	}
+
+	trailingStopsMtx.Lock()
+	defer trailingStopsMtx.Unlock()
+
+	trailingStopsID++
+	t.ID = trailingStopsID
+	t.Triggered = false
+	t.Created = time.Now()
+	t.Updated = t.Created
+	t.calculateStopPrice()
+	trailingStops = append(trailingStops, &t)
+	return t.ID, saveTrailingStops()
+}
+
+// SetTrailingStopOffset adjusts the trail distance of a live trailing stop,
+// recalculating the stop price from the best price reached so far
+func SetTrailingStopOffset(id int64, offsetType string, offset float64) (TrailingStop, error) {
+	offsetType = common.StringToUpper(offsetType)
+	if !isValidTrailOffset(offsetType, offset) {
+		return TrailingStop{}, ErrInvalidTrailOffset
+	}
+
+	trailingStopsMtx.Lock()
+	defer trailingStopsMtx.Unlock()
+
+	for x := range trailingStops {
+		if trailingStops[x].ID != id || trailingStops[x].Triggered {
+			continue
+		}
+
+		trailingStops[x].OffsetType = offsetType
+		trailingStops[x].Offset = offset
+		trailingStops[x].Updated = time.Now()
+		trailingStops[x].calculateStopPrice()
+		return *trailingStops[x], saveTrailingStops()
+	}
+	return TrailingStop{}, ErrTrailingStopNotFound
+}
+
+// UpdateTrailingStops recalculates the live trailing stops for an exchange pair
+// from the best bid and ask, returning the stops which have triggered. A zero
+// bid or ask leaves stops on that side unchanged. Triggered stops are persisted
+// straight away while stop price moves are persisted at most once per
+// trailingStopSaveInterval
+func UpdateTrailingStops(exchange string, p pair.CurrencyPair, assetType string, bid, ask float64) ([]TrailingStop, error) {
+	trailingStopsMtx.Lock()
+	defer trailingStopsMtx.Unlock()
+
+	var triggered []TrailingStop
+	for x := range trailingStops {
+		t := trailingStops[x]
+		if t.Triggered || common.StringToLower(t.Exchange) != common.StringToLower(exchange) ||
+			!t.Pair.Equal(p, true) || t.AssetType != assetType {
+			continue
+		}
+
+		stopPrice := t.StopPrice
+		if t.update(bid, ask) {
+			triggered = append(triggered, *t)
+		}
+		if t.StopPrice != stopPrice {
+			trailingStopsMove = true
+		}
+	}
+
+	if len(triggered) > 0 ||
+		(trailingStopsMove && time.Since(trailingStopsSave) >= trailingStopSaveInterval) {
+		return triggered, saveTrailingStops()
+	}
+	return triggered, nil
+}
+
+// RearmTrailingStop returns a triggered trailing stop to live after its order
+// failed to submit so it triggers again on the next market update. Stops which
+// have triggered TrailingStopMaxAttempts times are left triggered, returning
+// false
+func RearmTrailingStop(id int64) (bool, error) {
+	trailingStopsMtx.Lock()
+	defer trailingStopsMtx.Unlock()
+
+	for x := range trailingStops {
+		t := trailingStops[x]
+		if t.ID != id {
+			continue
+		}
+
+		if !t.Triggered || t.Attempts >= TrailingStopMaxAttempts {
+			return false, nil
+		}
+
+		t.Triggered = false
+		t.TriggerPrice = 0
+		t.Updated = time.Now()
+		return true, saveTrailingStops()
+	}
+	return false, ErrTrailingStopNotFound
+}
+
+// GetTrailingStop returns a trailing stop by its ID
+func GetTrailingStop(id int64) (TrailingStop, error) {
+	trailingStopsMtx.Lock()
+	defer trailingStopsMtx.Unlock()
+
+	for x := range trailingStops {
+		if trailingStops[x].ID == id {
+			return *trailingStops[x], nil
+		}
+	}
+	return TrailingStop{}, ErrTrailingStopNotFound
+}
+
+// GetTrailingStops returns all trailing stops ordered by ID
+func GetTrailingStops() []TrailingStop {
+	trailingStopsMtx.Lock()
+	defer trailingStopsMtx.Unlock()
+
+	result := make([]TrailingStop, 0, len(trailingStops))
+	for x := range trailingStops {
+		result = append(result, *trailingStops[x])
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// RemoveTrailingStop removes a trailing stop by its ID
+func RemoveTrailingStop(id int64) error {
+	trailingStopsMtx.Lock()
+	defer trailingStopsMtx.Unlock()
+
+	for x := range trailingStops {
+		if trailingStops[x].ID == id {
+			trailingStops = append(trailingStops[:x], trailingStops[x+1:]...)
+			return saveTrailingStops()
+		}
+	}
+	return ErrTrailingStopNotFound
+}
+
+// LoadTrailingStops loads persisted trailing stops from the file, which is
+// then kept up to date as stops are added, moved, adjusted and triggered. A
+// missing file is not an error
+func LoadTrailingStops(path string) error {
+	trailingStopsMtx.Lock()
+	defer trailingStopsMtx.Unlock()
+
+	trailingStopsFile = path
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var loaded []*TrailingStop
+	err = common.JSONDecode(data, &loaded)
+	if err != nil {
+		return err
+	}
+
+	trailingStops = loaded
+	trailingStopsID = 0
+	for x := range trailingStops {
+		if trailingStops[x].ID > trailingStopsID {
+			trailingStopsID = trailingStops[x].ID
+		}
+	}
+	return nil
+}
+
+// saveTrailingStops persists the trailing stops if a file has been loaded,
+// trailingStopsMtx must be held by the caller
+func saveTrailingStops() error {
+	if trailingStopsFile == "" {
+		return nil
+	}
+
+	data, err := common.JSONEncode(trailingStops)
+	if err != nil {
+		return err
+	}
+
+	err = common.WriteFile(trailingStopsFile, data)
+	if err != nil {
+		return err
+	}
+	trailingStopsSave = time.Now()
+	trailingStopsMove = false
+	return nil
+}
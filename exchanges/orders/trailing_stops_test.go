@@ -0,0 +1,205 @@
+package orders
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestAddTrailingStop(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	_, err := AddTrailingStop(TrailingStop{Exchange: "Bitstamp", Pair: p, Side: "hold", Amount: 1,
+		OffsetType: TrailAbsolute, Offset: 1})
+	if err != ErrInvalidTrailingStop {
+		t.Error("Test Failed - AddTrailingStop() invalid side error", err)
+	}
+
+	_, err = AddTrailingStop(TrailingStop{Exchange: "Bitstamp", Pair: p, Side: "sell", Amount: 1,
+		OffsetType: TrailPercentage, Offset: 100})
+	if err != ErrInvalidTrailOffset {
+		t.Error("Test Failed - AddTrailingStop() invalid offset error", err)
+	}
+}
+
+func TestUpdateTrailingStops(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	sellID, err := AddTrailingStop(TrailingStop{Exchange: "Bitstamp", Pair: p, AssetType: "SPOT",
+		Side: "sell", Amount: 1, OffsetType: "absolute", Offset: 10})
+	if err != nil {
+		t.Fatal("Test Failed - AddTrailingStop() error", err)
+	}
+	defer RemoveTrailingStop(sellID)
+
+	buyID, err := AddTrailingStop(TrailingStop{Exchange: "Bitstamp", Pair: p, AssetType: "SPOT",
+		Side: "buy", Amount: 1, OffsetType: "percentage", Offset: 10, BestPrice: 100})
+	if err != nil {
+		t.Fatal("Test Failed - AddTrailingStop() error", err)
+	}
+	defer RemoveTrailingStop(buyID)
+
+	// Bid rises to 120 moving the sell stop to 110, ask falls to 90 moving the
+	// buy stop to 99
+	bids := []float64{100, 120, 115}
+	asks := []float64{95, 90, 92}
+	for x := range bids {
+		triggered, err := UpdateTrailingStops("bitstamp", p, "SPOT", bids[x], asks[x])
+		if err != nil || len(triggered) != 0 {
+			t.Fatalf("Test Failed - UpdateTrailingStops() unexpected trigger %v %v", triggered, err)
+		}
+	}
+
+	sell, _ := GetTrailingStop(sellID)
+	buy, _ := GetTrailingStop(buyID)
+	if sell.BestPrice != 120 || sell.StopPrice != 110 || buy.BestPrice != 90 || buy.StopPrice != 99 {
+		t.Errorf("Test Failed - UpdateTrailingStops() unexpected stops %+v %+v", sell, buy)
+	}
+
+	// Other exchanges do not move the stops
+	triggered, _ := UpdateTrailingStops("Gemini", p, "SPOT", 1, 1)
+	if len(triggered) != 0 {
+		t.Error("Test Failed - UpdateTrailingStops() triggered stop on another exchange")
+	}
+
+	buy, err = SetTrailingStopOffset(buyID, TrailAbsolute, 5)
+	if err != nil || buy.StopPrice != 95 {
+		t.Errorf("Test Failed - SetTrailingStopOffset() unexpected stop %+v %v", buy, err)
+	}
+
+	triggered, err = UpdateTrailingStops("Bitstamp", p, "SPOT", 109, 96)
+	if err != nil || len(triggered) != 2 || triggered[0].TriggerPrice != 109 {
+		t.Fatalf("Test Failed - UpdateTrailingStops() expected both stops to trigger %v %v", triggered, err)
+	}
+
+	_, err = SetTrailingStopOffset(sellID, TrailAbsolute, 5)
+	if err != ErrTrailingStopNotFound {
+		t.Error("Test Failed - SetTrailingStopOffset() adjusted a triggered stop", err)
+	}
+}
+
+func TestLoadTrailingStops(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trailingstops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "trailingstops.json")
+	err = LoadTrailingStops(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadTrailingStops() missing file error", err)
+	}
+	defer LoadTrailingStops("")
+
+	id, err := AddTrailingStop(TrailingStop{Exchange: "Bitstamp", Pair: pair.NewCurrencyPair("BTC", "USD"),
+		Side: "sell", Amount: 1, OffsetType: TrailAbsolute, Offset: 10, BestPrice: 100})
+	if err != nil {
+		t.Fatal("Test Failed - AddTrailingStop() error", err)
+	}
+	defer RemoveTrailingStop(id)
+
+	trailingStopsMtx.Lock()
+	trailingStops = nil
+	trailingStopsMtx.Unlock()
+
+	err = LoadTrailingStops(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadTrailingStops() error", err)
+	}
+
+	stop, err := GetTrailingStop(id)
+	if err != nil || stop.StopPrice != 90 || stop.Pair.Pair() != "BTCUSD" {
+		t.Errorf("Test Failed - LoadTrailingStops() unexpected stop %+v %v", stop, err)
+	}
+}
+
+func TestRearmTrailingStop(t *testing.T) {
+	p := pair.NewCurrencyPair("LTC", "USD")
+	id, err := AddTrailingStop(TrailingStop{Exchange: "Bitstamp", Pair: p, AssetType: "SPOT",
+		Side: "sell", Amount: 1, OffsetType: TrailAbsolute, Offset: 10, BestPrice: 100})
+	if err != nil {
+		t.Fatal("Test Failed - AddTrailingStop() error", err)
+	}
+	defer RemoveTrailingStop(id)
+
+	rearmed, err := RearmTrailingStop(id)
+	if err != nil || rearmed {
+		t.Error("Test Failed - RearmTrailingStop() re-armed a live stop", err)
+	}
+
+	for i := 1; i <= TrailingStopMaxAttempts; i++ {
+		triggered, err := UpdateTrailingStops("Bitstamp", p, "SPOT", 80, 0)
+		if err != nil || len(triggered) != 1 || triggered[0].Attempts != i {
+			t.Fatalf("Test Failed - UpdateTrailingStops() expected stop to trigger %v %v", triggered, err)
+		}
+
+		rearmed, err = RearmTrailingStop(id)
+		if err != nil || rearmed != (i < TrailingStopMaxAttempts) {
+			t.Errorf("Test Failed - RearmTrailingStop() attempt %d re-armed %v %v", i, rearmed, err)
+		}
+	}
+
+	stop, _ := GetTrailingStop(id)
+	if !stop.Triggered {
+		t.Error("Test Failed - RearmTrailingStop() stop re-armed after the maximum attempts")
+	}
+
+	_, err = RearmTrailingStop(-1)
+	if err != ErrTrailingStopNotFound {
+		t.Error("Test Failed - RearmTrailingStop() unknown stop error", err)
+	}
+}
+
+func TestTrailingStopsSaveOnStateChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trailingstops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "trailingstops.json")
+	err = LoadTrailingStops(path)
+	if err != nil {
+		t.Fatal("Test Failed - LoadTrailingStops() error", err)
+	}
+	defer LoadTrailingStops("")
+
+	p := pair.NewCurrencyPair("ETH", "USD")
+	id, err := AddTrailingStop(TrailingStop{Exchange: "Bitstamp", Pair: p, AssetType: "SPOT",
+		Side: "sell", Amount: 1, OffsetType: TrailAbsolute, Offset: 10, BestPrice: 100})
+	if err != nil {
+		t.Fatal("Test Failed - AddTrailingStop() error", err)
+	}
+	defer RemoveTrailingStop(id)
+
+	saved := func() TrailingStop {
+		var stops []TrailingStop
+		data, err := ioutil.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, &stops)
+		}
+		if err != nil || len(stops) != 1 {
+			t.Fatal("Test Failed - unable to read persisted trailing stops", err)
+		}
+		return stops[0]
+	}
+
+	_, err = UpdateTrailingStops("Bitstamp", p, "SPOT", 120, 0)
+	if err != nil {
+		t.Fatal("Test Failed - UpdateTrailingStops() error", err)
+	}
+	if stop := saved(); stop.BestPrice != 100 {
+		t.Errorf("Test Failed - UpdateTrailingStops() persisted a stop price move %+v", stop)
+	}
+
+	_, err = UpdateTrailingStops("Bitstamp", p, "SPOT", 105, 0)
+	if err != nil {
+		t.Fatal("Test Failed - UpdateTrailingStops() error", err)
+	}
+	if stop := saved(); !stop.Triggered || stop.BestPrice != 120 {
+		t.Errorf("Test Failed - UpdateTrailingStops() triggered stop not persisted %+v", stop)
+	}
+}
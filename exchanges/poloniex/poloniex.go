@@ -49,6 +49,9 @@ const (
 	poloniexLendingHistory       = "returnLendingHistory"
 	poloniexAutoRenew            = "toggleAutoRenew"
 
+	// poloniexDateLayout is the layout of the UTC dates in private responses
+	poloniexDateLayout = "2006-01-02 15:04:05"
+
 	poloniexAuthRate   = 6
 	poloniexUnauthRate = 6
 )
@@ -80,11 +83,12 @@ func (p *Poloniex) SetDefaults() {
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	p.APIUrlDefault = poloniexAPIURL
 	p.APIUrl = p.APIUrlDefault
+	p.SetFeeTable(exchange.FeeTable{Maker: 0.001, Taker: 0.002})
 	p.WebsocketInit()
 }
 
 // Setup sets user exchange configuration settings
-func (p *Poloniex) Setup(exch config.ExchangeConfig) {
+func (p *Poloniex) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		p.SetEnabled(false)
 	} else {
@@ -101,23 +105,31 @@ func (p *Poloniex) Setup(exch config.ExchangeConfig) {
 		p.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := p.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = p.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = p.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = p.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = p.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = p.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = p.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 		err = p.WebsocketSetup(p.WsConnect,
 			exch.Name,
@@ -125,9 +137,10 @@ func (p *Poloniex) Setup(exch config.ExchangeConfig) {
 			poloniexWebsocketAddress,
 			exch.WebsocketURL)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 	}
+	return nil
 }
 
 // GetTicker returns current ticker information
@@ -682,8 +695,9 @@ func (p *Poloniex) PlaceMarginOrder(currency string, rate, amount, lendingRate f
 	return result, nil
 }
 
-// GetMarginPosition returns a position on a margin order
-func (p *Poloniex) GetMarginPosition(currency string) (interface{}, error) {
+// GetMarginPosition returns the margin positions keyed by currency pair, of a
+// single pair or of every pair when currency is blank or all
+func (p *Poloniex) GetMarginPosition(currency string) (map[string]MarginPosition, error) {
 	values := url.Values{}
 
 	if currency != "" && currency != "all" {
@@ -692,21 +706,17 @@ func (p *Poloniex) GetMarginPosition(currency string) (interface{}, error) {
 
 		err := p.SendAuthenticatedHTTPRequest("POST", poloniexMarginPosition, values, &result)
 		if err != nil {
-			return result, err
+			return nil, err
 		}
 
-		return result, nil
+		return map[string]MarginPosition{currency: result}, nil
 	}
 	values.Set("currencyPair", "all")
 
-	type Response struct {
-		Data map[string]MarginPosition
-	}
-	result := Response{}
-
-	err := p.SendAuthenticatedHTTPRequest("POST", poloniexMarginPosition, values, &result.Data)
+	result := make(map[string]MarginPosition)
+	err := p.SendAuthenticatedHTTPRequest("POST", poloniexMarginPosition, values, &result)
 	if err != nil {
-		return result, err
+		return nil, err
 	}
 
 	return result, nil
@@ -873,7 +883,7 @@ func (p *Poloniex) SendAuthenticatedHTTPRequest(method, endpoint string, values
 	headers["Key"] = p.APIKey
 
 	if p.Nonce.Get() == 0 {
-		p.Nonce.Set(time.Now().UnixNano())
+		p.Nonce.Set(p.GetAdjustedTime().UnixNano())
 	} else {
 		p.Nonce.Inc()
 	}
@@ -890,6 +900,10 @@ func (p *Poloniex) SendAuthenticatedHTTPRequest(method, endpoint string, values
 
 // GetFee returns an estimate of fee based on type of transaction
 func (p *Poloniex) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
+	if p.IsFeeEstimated(feeBuilder) {
+		return p.EstimateFee(feeBuilder)
+	}
+
 	var fee float64
 	switch feeBuilder.FeeType {
 	case exchange.CryptocurrencyTradeFee:
@@ -190,3 +190,68 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestMarginInterfaces(t *testing.T) {
+	var exch exchange.IBotExchange = &p
+	if _, ok := exch.(exchange.IMarginTrader); !ok {
+		t.Error("Test Failed - Poloniex does not implement IMarginTrader")
+	}
+	if _, ok := exch.(exchange.ILender); !ok {
+		t.Error("Test Failed - Poloniex does not implement ILender")
+	}
+}
+
+func TestConvertMarginPositions(t *testing.T) {
+	p.SetDefaults()
+	positions := p.convertMarginPositions(map[string]MarginPosition{
+		"BTC_ETH":  {Amount: -2, BasePrice: 0.03, LiquidationPrice: 0.05, Type: "short"},
+		"BTC_LTC":  {Type: "none"},
+		"USDT_BTC": {Amount: 0.5, BasePrice: 6000, ProfitLoss: 12.5, Type: "long"},
+	})
+	if len(positions) != 2 {
+		t.Fatalf("Test Failed - convertMarginPositions() expected 2 positions, got %+v", positions)
+	}
+	if positions[0].Side != exchange.MarginPositionShort || positions[0].LiquidationPrice != 0.05 ||
+		positions[0].Pair.FirstCurrency.String() != "BTC" {
+		t.Errorf("Test Failed - convertMarginPositions() unexpected short position %+v", positions[0])
+	}
+	if positions[1].Side != exchange.MarginPositionLong || positions[1].ProfitLoss != 12.5 {
+		t.Errorf("Test Failed - convertMarginPositions() unexpected long position %+v", positions[1])
+	}
+}
+
+func TestConvertLoanOffers(t *testing.T) {
+	offers := convertLoanOffers(map[string][]LoanOffer{
+		"BTC": {{ID: 2, Rate: 0.0002, Amount: 1, Duration: 2, AutoRenew: true, Date: "2018-05-10 23:33:50"}},
+		"LTC": {{ID: 1, Rate: 0.0001, Amount: 10, Duration: 5}},
+	})
+	if len(offers) != 2 || offers[0].Currency != "LTC" || offers[1].Currency != "BTC" {
+		t.Fatalf("Test Failed - convertLoanOffers() unexpected offers %+v", offers)
+	}
+	if !offers[1].AutoRenew || offers[1].Created.Year() != 2018 || offers[1].Duration != 2 {
+		t.Errorf("Test Failed - convertLoanOffers() unexpected offer %+v", offers[1])
+	}
+}
+
+func TestConvertDepositsWithdrawals(t *testing.T) {
+	deposits, withdrawals := convertDepositsWithdrawals(DepositsWithdrawals{
+		Deposits: []DepositRecord{
+			{Currency: "BTC", Amount: 1, Confirmations: 2, TransactionID: "tx1", Timestamp: 1531000000, Status: "PENDING"},
+		},
+		Withdrawals: []WithdrawalRecord{
+			{WithdrawalNumber: 7, Currency: "ETH", Amount: 3, Fee: 0.01, Timestamp: 1531000100, Status: "COMPLETE: tx2"},
+			{WithdrawalNumber: 8, Currency: "ETH", Amount: 1, Status: "AWAITING APPROVAL"},
+		},
+	})
+	if len(deposits) != 1 || deposits[0].Status != exchange.TransferStatusPending ||
+		deposits[0].Confirmations != 2 || deposits[0].Timestamp.Unix() != 1531000000 {
+		t.Errorf("Test Failed - convertDepositsWithdrawals() unexpected deposits %+v", deposits)
+	}
+	if len(withdrawals) != 2 || withdrawals[0].ID != "7" || withdrawals[0].Status != exchange.TransferStatusComplete ||
+		withdrawals[1].Status != exchange.TransferStatusPending {
+		t.Errorf("Test Failed - convertDepositsWithdrawals() unexpected withdrawals %+v", withdrawals)
+	}
+	if status := convertTransferStatus("CANCELED"); status != exchange.TransferStatusCancelled {
+		t.Errorf("Test Failed - convertTransferStatus() unexpected status %s", status)
+	}
+}
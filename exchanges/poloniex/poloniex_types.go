@@ -119,26 +119,34 @@ type DepositAddresses struct {
 
 // DepositsWithdrawals holds withdrawal information
 type DepositsWithdrawals struct {
-	Deposits []struct {
-		Currency      string  `json:"currency"`
-		Address       string  `json:"address"`
-		Amount        float64 `json:"amount,string"`
-		Confirmations int     `json:"confirmations"`
-		TransactionID string  `json:"txid"`
-		Timestamp     int64   `json:"timestamp"`
-		Status        string  `json:"status"`
-	} `json:"deposits"`
-	Withdrawals []struct {
-		WithdrawalNumber int64   `json:"withdrawalNumber"`
-		Currency         string  `json:"currency"`
-		Address          string  `json:"address"`
-		Amount           float64 `json:"amount,string"`
-		Confirmations    int     `json:"confirmations"`
-		TransactionID    string  `json:"txid"`
-		Timestamp        int64   `json:"timestamp"`
-		Status           string  `json:"status"`
-		IPAddress        string  `json:"ipAddress"`
-	} `json:"withdrawals"`
+	Deposits    []DepositRecord    `json:"deposits"`
+	Withdrawals []WithdrawalRecord `json:"withdrawals"`
+}
+
+// DepositRecord holds a deposit of the deposit and withdrawal history
+type DepositRecord struct {
+	Currency      string  `json:"currency"`
+	Address       string  `json:"address"`
+	Amount        float64 `json:"amount,string"`
+	Confirmations int     `json:"confirmations"`
+	TransactionID string  `json:"txid"`
+	Timestamp     int64   `json:"timestamp"`
+	Status        string  `json:"status"`
+}
+
+// WithdrawalRecord holds a withdrawal of the deposit and withdrawal history,
+// a completed withdrawal's status is suffixed with its transaction ID
+type WithdrawalRecord struct {
+	WithdrawalNumber int64   `json:"withdrawalNumber"`
+	Currency         string  `json:"currency"`
+	Address          string  `json:"address"`
+	Amount           float64 `json:"amount,string"`
+	Fee              float64 `json:"fee,string"`
+	Confirmations    int     `json:"confirmations"`
+	TransactionID    string  `json:"txid"`
+	Timestamp        int64   `json:"timestamp"`
+	Status           string  `json:"status"`
+	IPAddress        string  `json:"ipAddress"`
 }
 
 // Order hold order information
@@ -241,13 +249,13 @@ type Margin struct {
 
 // MarginPosition holds margin positional information
 type MarginPosition struct {
-	Amount            float64 `json:"amount,string"`
-	Total             float64 `json:"total,string"`
-	BasePrice         float64 `json:"basePrice,string"`
-	LiquidiationPrice float64 `json:"liquidiationPrice"`
-	ProfitLoss        float64 `json:"pl,string"`
-	LendingFees       float64 `json:"lendingFees,string"`
-	Type              string  `json:"type"`
+	Amount           float64 `json:"amount,string"`
+	Total            float64 `json:"total,string"`
+	BasePrice        float64 `json:"basePrice,string"`
+	LiquidationPrice float64 `json:"liquidationPrice"`
+	ProfitLoss       float64 `json:"pl,string"`
+	LendingFees      float64 `json:"lendingFees,string"`
+	Type             string  `json:"type"`
 }
 
 // LoanOffer holds loan offer information
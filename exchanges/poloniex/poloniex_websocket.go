@@ -12,6 +12,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
 	"github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 )
@@ -31,6 +32,7 @@ func (p *Poloniex) WsConnect() error {
 	}
 
 	var dialer websocket.Dialer
+	p.Websocket.ConfigureDialer(&dialer)
 	if p.Websocket.GetProxyAddress() != "" {
 		proxy, err := url.Parse(p.Websocket.GetProxyAddress())
 		if err != nil {
@@ -103,7 +105,7 @@ func (p *Poloniex) WsReadData() {
 			return
 
 		default:
-			_, resp, err := p.WebsocketConn.ReadMessage()
+			_, resp, err := p.Websocket.ReadMessage(p.WebsocketConn)
 			if err != nil {
 				p.Websocket.DataHandler <- err
 				return
@@ -202,7 +204,7 @@ func (p *Poloniex) WsHandleData() {
 						p.Websocket.DataHandler <- exchange.WebsocketOrderbookUpdate{
 							Exchange: p.GetName(),
 							Asset:    "SPOT",
-							Pair:     pair.NewCurrencyPairFromString(currencyPair),
+							Pair:     translation.PairToCanonical(p.Name, pair.NewCurrencyPairFromString(currencyPair)),
 						}
 						continue
 					}
@@ -314,7 +316,7 @@ func (p *Poloniex) WsProcessOrderbookSnapshot(ob []interface{}, symbol string) e
 	newOrderbook.AssetType = "SPOT"
 	newOrderbook.CurrencyPair = symbol
 	newOrderbook.LastUpdated = time.Now()
-	newOrderbook.Pair = pair.NewCurrencyPairFromString(symbol)
+	newOrderbook.Pair = translation.PairToCanonical(p.Name, pair.NewCurrencyPairFromString(symbol))
 
 	return p.Websocket.Orderbook.LoadSnapshot(newOrderbook, p.GetName())
 }
@@ -323,7 +325,7 @@ func (p *Poloniex) WsProcessOrderbookSnapshot(ob []interface{}, symbol string) e
 func (p *Poloniex) WsProcessOrderbookUpdate(target []interface{}, symbol string) error {
 	sideCheck := target[1].(float64)
 
-	cP := pair.NewCurrencyPairFromString(symbol)
+	cP := translation.PairToCanonical(p.Name, pair.NewCurrencyPairFromString(symbol))
 
 	price, err := strconv.ParseFloat(target[2].(string), 64)
 	if err != nil {
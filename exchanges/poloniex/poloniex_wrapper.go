@@ -1,34 +1,49 @@
 package poloniex
 
 import (
+	"context"
 	"errors"
 	"log"
+	"sort"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
+// init registers the Poloniex exchange
+func init() {
+	exchange.Register("Poloniex", func() exchange.IBotExchange { return new(Poloniex) })
+}
+
 // Start starts the Poloniex go routine
-func (p *Poloniex) Start(wg *sync.WaitGroup) {
+func (p *Poloniex) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		p.Run()
+		p.Run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the Poloniex wrapper
-func (p *Poloniex) Run() {
+func (p *Poloniex) Run(ctx context.Context) {
 	if p.Verbose {
 		log.Printf("%s Websocket: %s (url: %s).\n", p.GetName(), common.IsEnabled(p.Websocket.IsEnabled()), poloniexWebsocketAddress)
 		log.Printf("%s polling delay: %ds.\n", p.GetName(), p.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", p.GetName(), len(p.EnabledPairs), p.EnabledPairs)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	exchangeCurrencies, err := p.GetExchangeCurrencies()
 	if err != nil {
 		log.Printf("%s Failed to get available symbols.\n", p.GetName())
@@ -46,6 +61,11 @@ func (p *Poloniex) Run() {
 	}
 }
 
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (p *Poloniex) GetListedPairs() ([]string, error) {
+	return p.GetExchangeCurrencies()
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (p *Poloniex) UpdateTicker(currencyPair pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
@@ -116,7 +136,7 @@ func (p *Poloniex) UpdateOrderbook(currencyPair pair.CurrencyPair, assetType str
 			obItems = append(obItems, orderbook.Item{Amount: obData.Amount, Price: obData.Price})
 		}
 		orderBook.Asks = obItems
-		orderbook.ProcessOrderbook(p.Name, x, orderBook, assetType)
+		orderbook.ProcessOrderbook(p.Name, x, p.TruncateOrderbook(x, orderBook), assetType)
 	}
 	return orderbook.GetOrderbook(p.Name, currencyPair, assetType)
 }
@@ -126,16 +146,15 @@ func (p *Poloniex) UpdateOrderbook(currencyPair pair.CurrencyPair, assetType str
 func (p *Poloniex) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 	var response exchange.AccountInfo
 	response.ExchangeName = p.GetName()
-	accountBalance, err := p.GetBalances()
+	accountBalance, err := p.GetCompleteBalances()
 	if err != nil {
 		return response, err
 	}
 
 	for x, y := range accountBalance.Currency {
-		var exchangeCurrency exchange.AccountCurrencyInfo
-		exchangeCurrency.CurrencyName = x
-		exchangeCurrency.TotalValue = y
-		response.Currencies = append(response.Currencies, exchangeCurrency)
+		response.Currencies = append(response.Currencies, exchange.NewAccountCurrencyInfo(
+			translation.ToCanonical(p.Name, pair.CurrencyItem(x)).String(),
+			y.Available, y.OnOrders))
 	}
 	return response, nil
 }
@@ -143,8 +162,104 @@ func (p *Poloniex) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
 // GetExchangeFundTransferHistory returns funding history, deposits and
 // withdrawals
 func (p *Poloniex) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error) {
-	var fundHistory []exchange.FundHistory
-	return fundHistory, errors.New("not supported on exchange")
+	history, err := p.getDepositsWithdrawals(time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	deposits, withdrawals := convertDepositsWithdrawals(history)
+	return exchange.CombineTransferHistory(p.GetName(), deposits, withdrawals), nil
+}
+
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (p *Poloniex) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	history, err := p.getDepositsWithdrawals(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	deposits, _ := convertDepositsWithdrawals(history)
+	return deposits, nil
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (p *Poloniex) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	history, err := p.getDepositsWithdrawals(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	_, withdrawals := convertDepositsWithdrawals(history)
+	return withdrawals, nil
+}
+
+// getDepositsWithdrawals returns the deposits and withdrawals made between
+// start and end, the exchange filtering both by the range
+func (p *Poloniex) getDepositsWithdrawals(start, end time.Time) (DepositsWithdrawals, error) {
+	var from, to string
+	if !start.IsZero() {
+		from = strconv.FormatInt(start.Unix(), 10)
+	}
+	if !end.IsZero() {
+		to = strconv.FormatInt(end.Unix(), 10)
+	}
+	return p.GetDepositsWithdrawals(from, to)
+}
+
+// convertDepositsWithdrawals converts the deposit and withdrawal history to
+// the exchange deposit and withdrawal formats
+func convertDepositsWithdrawals(history DepositsWithdrawals) ([]exchange.Deposit, []exchange.Withdrawal) {
+	deposits := make([]exchange.Deposit, len(history.Deposits))
+	for x, d := range history.Deposits {
+		deposits[x] = exchange.Deposit{
+			ID:            d.TransactionID,
+			Currency:      d.Currency,
+			Amount:        d.Amount,
+			Address:       d.Address,
+			TxID:          d.TransactionID,
+			Confirmations: int64(d.Confirmations),
+			Status:        convertTransferStatus(d.Status),
+			Timestamp:     time.Unix(d.Timestamp, 0),
+		}
+	}
+
+	withdrawals := make([]exchange.Withdrawal, len(history.Withdrawals))
+	for x, w := range history.Withdrawals {
+		withdrawals[x] = exchange.Withdrawal{
+			ID:            strconv.FormatInt(w.WithdrawalNumber, 10),
+			Currency:      w.Currency,
+			Amount:        w.Amount,
+			Fee:           w.Fee,
+			Address:       w.Address,
+			TxID:          w.TransactionID,
+			Confirmations: int64(w.Confirmations),
+			Status:        convertTransferStatus(w.Status),
+			Timestamp:     time.Unix(w.Timestamp, 0),
+		}
+	}
+	return deposits, withdrawals
+}
+
+// convertTransferStatus converts a deposit or withdrawal status, such as
+// PENDING or COMPLETE: <txid>, to a transfer status
+func convertTransferStatus(status string) exchange.TransferStatus {
+	status = common.StringToUpper(status)
+	switch {
+	case common.StringContains(status, "COMPLETE"):
+		return exchange.TransferStatusComplete
+	case common.StringContains(status, "CANCEL"):
+		return exchange.TransferStatusCancelled
+	case common.StringContains(status, "PENDING"),
+		common.StringContains(status, "AWAITING"),
+		common.StringContains(status, "PROCESSING"):
+		return exchange.TransferStatusPending
+	case common.StringContains(status, "FAIL"),
+		common.StringContains(status, "INVALID"):
+		return exchange.TransferStatusFailed
+	}
+	return exchange.TransferStatusUnknown
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
@@ -154,6 +269,12 @@ func (p *Poloniex) GetExchangeHistory(currencyPair pair.CurrencyPair, assetType
 	return resp, errors.New("trade history not yet implemented")
 }
 
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (p *Poloniex) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
 // SubmitExchangeOrder submits a new order
 func (p *Poloniex) SubmitExchangeOrder(currencyPair pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
 	return 0, errors.New("not yet implemented")
@@ -170,6 +291,18 @@ func (p *Poloniex) CancelExchangeOrder(orderID int64) error {
 	return errors.New("not yet implemented")
 }
 
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (p *Poloniex) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, p.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (p *Poloniex) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, p.CancelExchangeOrder)
+}
+
 // CancelAllExchangeOrders cancels all orders associated with a currency pair
 func (p *Poloniex) CancelAllExchangeOrders() error {
 	return errors.New("not yet implemented")
@@ -181,6 +314,11 @@ func (p *Poloniex) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, er
 	return orderDetail, errors.New("not yet implemented")
 }
 
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (p *Poloniex) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	return nil, errors.New("not yet implemented")
+}
+
 // GetExchangeDepositAddress returns a deposit address for a specified currency
 func (p *Poloniex) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
 	return "", errors.New("not yet implemented")
@@ -198,9 +336,117 @@ func (p *Poloniex) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount
 	return "", errors.New("not yet implemented")
 }
 
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account
+func (p *Poloniex) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, errors.New("not yet implemented")
+}
+
+// SubmitExchangeMarginOrder submits a margin order and returns its order ID
+func (p *Poloniex) SubmitExchangeMarginOrder(order exchange.MarginOrder) (int64, error) {
+	err := exchange.ValidateMarginOrder(order)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := p.PlaceMarginOrder(exchange.FormatExchangeCurrency(p.Name, order.Pair).String(),
+		order.Price, order.Amount, order.MaxLendingRate, order.Side == exchange.OrderSideBuy())
+	if err != nil {
+		return 0, err
+	}
+	return response.OrderNumber, nil
+}
+
+// GetExchangeMarginPositions returns the open margin positions of the account
+func (p *Poloniex) GetExchangeMarginPositions() ([]exchange.MarginPosition, error) {
+	positions, err := p.GetMarginPosition("all")
+	if err != nil {
+		return nil, err
+	}
+	return p.convertMarginPositions(positions), nil
+}
+
+// CloseExchangeMarginPosition closes the margin position of a pair at market
+func (p *Poloniex) CloseExchangeMarginPosition(currencyPair pair.CurrencyPair) error {
+	_, err := p.CloseMarginPosition(exchange.FormatExchangeCurrency(p.Name, currencyPair).String())
+	return err
+}
+
+// GetExchangeLendingOffers returns the open lending offers of the account
+func (p *Poloniex) GetExchangeLendingOffers() ([]exchange.LendingOffer, error) {
+	offers, err := p.GetOpenLoanOffers()
+	if err != nil {
+		return nil, err
+	}
+	return convertLoanOffers(offers), nil
+}
+
+// CreateExchangeLendingOffer places a lending offer and returns its ID
+func (p *Poloniex) CreateExchangeLendingOffer(offer exchange.LendingOffer) (int64, error) {
+	err := exchange.ValidateLendingOffer(offer)
+	if err != nil {
+		return 0, err
+	}
+	return p.CreateLoanOffer(common.StringToUpper(offer.Currency), offer.Amount,
+		offer.Rate, offer.Duration, offer.AutoRenew)
+}
+
+// CancelExchangeLendingOffer cancels an open lending offer
+func (p *Poloniex) CancelExchangeLendingOffer(offerID int64) error {
+	_, err := p.CancelLoanOffer(offerID)
+	return err
+}
+
+// convertMarginPositions converts the margin positions keyed by Poloniex
+// currency pair, skipping pairs without an open position
+func (p *Poloniex) convertMarginPositions(positions map[string]MarginPosition) []exchange.MarginPosition {
+	var result []exchange.MarginPosition
+	for symbol, position := range positions {
+		if position.Type != exchange.MarginPositionLong && position.Type != exchange.MarginPositionShort {
+			continue
+		}
+		result = append(result, exchange.MarginPosition{
+			Pair:             translation.PairToCanonical(p.Name, pair.NewCurrencyPairFromString(symbol)),
+			Side:             position.Type,
+			Amount:           position.Amount,
+			Total:            position.Total,
+			BasePrice:        position.BasePrice,
+			LiquidationPrice: position.LiquidationPrice,
+			ProfitLoss:       position.ProfitLoss,
+			LendingFees:      position.LendingFees,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Pair.Pair() < result[j].Pair.Pair()
+	})
+	return result
+}
+
+// convertLoanOffers converts the open loan offers keyed by currency
+func convertLoanOffers(offers map[string][]LoanOffer) []exchange.LendingOffer {
+	var result []exchange.LendingOffer
+	for currency, loans := range offers {
+		for x := range loans {
+			created, _ := time.Parse(poloniexDateLayout, loans[x].Date)
+			result = append(result, exchange.LendingOffer{
+				ID:        loans[x].ID,
+				Currency:  currency,
+				Amount:    loans[x].Amount,
+				Rate:      loans[x].Rate,
+				Duration:  loans[x].Duration,
+				AutoRenew: loans[x].AutoRenew,
+				Created:   created,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
 // WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
 // withdrawal is submitted
-func (p *Poloniex) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64) (string, error) {
+func (p *Poloniex) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
 	return "", errors.New("not yet implemented")
 }
 
@@ -209,6 +455,11 @@ func (p *Poloniex) GetWebsocket() (*exchange.Websocket, error) {
 	return p.Websocket, nil
 }
 
+// GetExchangeServerTime returns the current exchange server time
+func (p *Poloniex) GetExchangeServerTime() (time.Time, error) {
+	return time.Time{}, errors.New("not supported on exchange")
+}
+
 // GetFeeByType returns an estimate of fee based on type of transaction
 func (p *Poloniex) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return p.GetFee(feeBuilder)
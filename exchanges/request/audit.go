@@ -0,0 +1,263 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+const (
+	auditRedacted          = "[REDACTED]"
+	auditCorrelationIDSize = 16
+)
+
+// sensitiveFields holds the lower case name fragments of headers and
+// parameters whose values are never written to the audit trail
+var sensitiveFields = []string{
+	"key",
+	"secret",
+	"sign",
+	"passphrase",
+	"password",
+	"token",
+	"auth",
+	"otp",
+}
+
+// Vars for the audit trail
+var (
+	auditor  Auditor
+	auditMtx sync.Mutex
+)
+
+// Auditor records authenticated exchange requests and their raw responses
+type Auditor interface {
+	Record(record AuditRecord) error
+}
+
+// AuditRecord holds a captured request and response with secrets removed
+type AuditRecord struct {
+	CorrelationID string            `json:"correlationId"`
+	Exchange      string            `json:"exchange"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Duration      time.Duration     `json:"duration"`
+	Method        string            `json:"method"`
+	Path          string            `json:"path"`
+	Headers       map[string]string `json:"headers"`
+	Body          string            `json:"body"`
+	StatusCode    int               `json:"statusCode"`
+	Response      string            `json:"response"`
+	Error         string            `json:"error,omitempty"`
+}
+
+// FileAuditor appends audit records as JSON lines to a file
+type FileAuditor struct {
+	file *os.File
+	m    sync.Mutex
+}
+
+// SetAuditor sets the auditor used to capture authenticated requests for
+// all exchanges. Passing nil disables the audit trail
+func SetAuditor(a Auditor) {
+	auditMtx.Lock()
+	auditor = a
+	auditMtx.Unlock()
+}
+
+// GetAuditor returns the current auditor, or nil if auditing is disabled
+func GetAuditor() Auditor {
+	auditMtx.Lock()
+	defer auditMtx.Unlock()
+	return auditor
+}
+
+// IsAuditable returns whether or not a request should be captured in the
+// audit trail. All authenticated requests are captured whatever their HTTP
+// method, as some exchanges place orders and withdraw funds using GET requests
+func IsAuditable(authRequest bool) bool {
+	return authRequest && GetAuditor() != nil
+}
+
+// NewFileAuditor opens or creates the supplied file for appending audit
+// records
+func NewFileAuditor(path string) (*FileAuditor, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditor{file: f}, nil
+}
+
+// Record writes an audit record to the audit file
+func (f *FileAuditor) Record(record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+	_, err = f.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the audit file
+func (f *FileAuditor) Close() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.file.Close()
+}
+
+// bufferBody reads the request body into memory so that it can be replayed
+// into the audit trail after the request has been sent
+func bufferBody(body io.Reader) (io.Reader, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// newAuditRecord captures the outgoing request with secrets removed
+func newAuditRecord(exchName string, req *http.Request) *AuditRecord {
+	record := &AuditRecord{
+		Exchange:  exchName,
+		Timestamp: time.Now(),
+		Method:    req.Method,
+		Path:      redactURL(req.URL),
+		Headers:   make(map[string]string),
+	}
+
+	id, err := common.GetRandomSalt(nil, auditCorrelationIDSize)
+	if err == nil {
+		record.CorrelationID = common.HexEncodeToString(id)
+	}
+
+	for k := range req.Header {
+		if isSensitive(k) {
+			record.Headers[k] = auditRedacted
+			continue
+		}
+		record.Headers[k] = req.Header.Get(k)
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			data, err := ioutil.ReadAll(body)
+			if err == nil {
+				record.Body = redactBody(data)
+			}
+		}
+	}
+	return record
+}
+
+// complete records the response and sends the record to the auditor
+func (a *AuditRecord) complete(statusCode int, response []byte, err error) {
+	a.Duration = time.Since(a.Timestamp)
+	a.StatusCode = statusCode
+	a.Response = string(response)
+	if err != nil {
+		a.Error = err.Error()
+	}
+
+	audit := GetAuditor()
+	if audit == nil {
+		return
+	}
+
+	if recordErr := audit.Record(*a); recordErr != nil {
+		log.Printf("%s audit trail error - failed to record request %s: %s",
+			a.Exchange, a.CorrelationID, recordErr)
+	}
+}
+
+// isSensitive returns whether a header or parameter name may hold a secret
+func isSensitive(name string) bool {
+	name = common.StringToLower(name)
+	for x := range sensitiveFields {
+		if strings.Contains(name, sensitiveFields[x]) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL returns the request URL with sensitive query values removed
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	redacted := *u
+	redacted.RawQuery = redactValues(u.Query()).Encode()
+	return redacted.String()
+}
+
+// redactValues replaces sensitive values in a set of parameters
+func redactValues(values url.Values) url.Values {
+	for k := range values {
+		if isSensitive(k) {
+			values.Set(k, auditRedacted)
+		}
+	}
+	return values
+}
+
+// redactBody removes sensitive values from a JSON or URL encoded body
+func redactBody(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var jsonBody interface{}
+	if err := json.Unmarshal(data, &jsonBody); err == nil {
+		redacted, err := json.Marshal(redactJSON(jsonBody))
+		if err == nil {
+			return string(redacted)
+		}
+	}
+
+	values, err := url.ParseQuery(string(data))
+	if err == nil && len(values) > 0 {
+		return redactValues(values).Encode()
+	}
+	return string(data)
+}
+
+// redactJSON recursively replaces sensitive values in decoded JSON
+func redactJSON(v interface{}) interface{} {
+	switch data := v.(type) {
+	case map[string]interface{}:
+		for k := range data {
+			if isSensitive(k) {
+				data[k] = auditRedacted
+				continue
+			}
+			data[k] = redactJSON(data[k])
+		}
+		return data
+	case []interface{}:
+		for x := range data {
+			data[x] = redactJSON(data[x])
+		}
+		return data
+	default:
+		return v
+	}
+}
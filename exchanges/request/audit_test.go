@@ -0,0 +1,130 @@
+package request
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testAuditor struct {
+	records []AuditRecord
+}
+
+func (a *testAuditor) Record(record AuditRecord) error {
+	a.records = append(a.records, record)
+	return nil
+}
+
+func TestIsAuditable(t *testing.T) {
+	SetAuditor(nil)
+	if IsAuditable(true) {
+		t.Error("Test failed - IsAuditable() true with no auditor set")
+	}
+
+	SetAuditor(&testAuditor{})
+	defer SetAuditor(nil)
+
+	if !IsAuditable(true) {
+		t.Error("Test failed - IsAuditable() authenticated request not audited")
+	}
+
+	if IsAuditable(false) {
+		t.Error("Test failed - IsAuditable() unauthenticated request audited")
+	}
+}
+
+func TestAuditTrail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"1337"}`)
+	}))
+	defer server.Close()
+
+	auditor := &testAuditor{}
+	SetAuditor(auditor)
+	defer SetAuditor(nil)
+
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0), new(http.Client))
+	headers := map[string]string{
+		"X-API-KEY":    "supersecretkey",
+		"Content-Type": "application/x-www-form-urlencoded",
+	}
+	body := bytes.NewBufferString("amount=1&signature=abcdef&key=supersecretkey")
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	err := r.SendPayload("POST", server.URL+"/withdraw?apikey=supersecretkey", headers, body, &result, true, false)
+	if err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	if result.ID != "1337" {
+		t.Error("Test failed - SendPayload() response not decoded after audit")
+	}
+
+	if len(auditor.records) != 1 {
+		t.Fatalf("Test failed - expected 1 audit record received %d",
+			len(auditor.records))
+	}
+
+	record := auditor.records[0]
+	if record.CorrelationID == "" || record.Exchange != "test" {
+		t.Error("Test failed - audit record missing correlation details")
+	}
+
+	if record.StatusCode != http.StatusOK || record.Response != `{"id":"1337"}` {
+		t.Error("Test failed - audit record response not captured")
+	}
+
+	if record.Headers["X-Api-Key"] != auditRedacted {
+		t.Error("Test failed - audit record header secret not redacted")
+	}
+
+	for _, captured := range []string{record.Path, record.Body} {
+		if strings.Contains(captured, "supersecretkey") || strings.Contains(captured, "abcdef") {
+			t.Errorf("Test failed - audit record secret not redacted: %s", captured)
+		}
+	}
+
+	if !strings.Contains(record.Body, "amount=1") {
+		t.Error("Test failed - audit record body not captured")
+	}
+
+	// Authenticated GET requests can also change account state
+	err = r.SendPayload("GET", server.URL+"/market/buylimit?apikey=supersecretkey", nil, nil, nil, true, false)
+	if err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	if len(auditor.records) != 2 {
+		t.Fatal("Test failed - authenticated GET request not captured in audit trail")
+	}
+
+	if record = auditor.records[1]; record.Method != "GET" || strings.Contains(record.Path, "supersecretkey") {
+		t.Errorf("Test failed - unexpected audit record %+v", record)
+	}
+
+	err = r.SendPayload("GET", server.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	if len(auditor.records) != 2 {
+		t.Error("Test failed - unauthenticated request captured in audit trail")
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	redacted := redactBody([]byte(`{"request":"/v1/withdraw/btc","nonce":1,"api_secret":"secret","nested":{"passphrase":"p"}}`))
+	if strings.Contains(redacted, `"secret"`) || strings.Contains(redacted, `"p"`) {
+		t.Errorf("Test failed - redactBody() JSON secrets not redacted: %s", redacted)
+	}
+
+	if !strings.Contains(redacted, "/v1/withdraw/btc") {
+		t.Error("Test failed - redactBody() removed non sensitive values")
+	}
+}
@@ -0,0 +1,194 @@
+package request
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// DefaultMicroCacheTTL is a suggested micro-cache lifetime for hot public
+// endpoints such as tickers
+const DefaultMicroCacheTTL = 500 * time.Millisecond
+
+// cacheEntry holds the last response of a public endpoint. pending is set
+// while a request for the endpoint is in flight so concurrent duplicates wait
+// for it and share its response instead of sending their own
+type cacheEntry struct {
+	contents     []byte
+	etag         string
+	lastModified string
+	fetched      time.Time
+	pending      chan struct{}
+	shared       []byte
+	err          error
+}
+
+// responseCache caches the responses of unauthenticated GET requests by URL
+type responseCache struct {
+	ttl     time.Duration
+	entries map[string]*cacheEntry
+	mtx     sync.Mutex
+}
+
+// SetResponseCache toggles caching of unauthenticated GET responses. Cached
+// responses are revalidated with the ETag and Last-Modified headers returned
+// by the exchange, served without a request for microCacheTTL after being
+// fetched and concurrent duplicate requests are collapsed into one. A zero
+// microCacheTTL always revalidates. Disabling the cache discards it
+func (r *Requester) SetResponseCache(enabled bool, microCacheTTL time.Duration) {
+	r.cacheMtx.Lock()
+	defer r.cacheMtx.Unlock()
+
+	if !enabled {
+		r.cache = nil
+		return
+	}
+
+	if r.cache == nil {
+		r.cache = &responseCache{entries: make(map[string]*cacheEntry)}
+	}
+	r.cache.mtx.Lock()
+	r.cache.ttl = microCacheTTL
+	r.cache.mtx.Unlock()
+}
+
+// IsResponseCacheEnabled returns whether unauthenticated GET responses are
+// cached
+func (r *Requester) IsResponseCacheEnabled() bool {
+	return r.getResponseCache() != nil
+}
+
+// ClearResponseCache discards the cached responses
+func (r *Requester) ClearResponseCache() {
+	cache := r.getResponseCache()
+	if cache == nil {
+		return
+	}
+
+	cache.mtx.Lock()
+	for key, entry := range cache.entries {
+		if entry.pending == nil {
+			delete(cache.entries, key)
+		}
+	}
+	cache.mtx.Unlock()
+}
+
+// getResponseCache returns the response cache or nil when disabled
+func (r *Requester) getResponseCache() *responseCache {
+	r.cacheMtx.Lock()
+	defer r.cacheMtx.Unlock()
+	return r.cache
+}
+
+// isCacheable returns whether the response of a request may be cached
+func isCacheable(method string, authRequest bool) bool {
+	return method == "GET" && !authRequest
+}
+
+// sendCached serves a request from the response cache when the cached
+// response is fresh or another request for the same URL is in flight,
+// otherwise the request is sent with conditional headers through send
+func (r *Requester) sendCached(cache *responseCache, req *http.Request, result interface{}, verbose bool, send func() error) error {
+	key := req.URL.String()
+
+	cache.mtx.Lock()
+	entry, ok := cache.entries[key]
+	if !ok {
+		entry = &cacheEntry{}
+		cache.entries[key] = entry
+	}
+
+	if entry.pending != nil {
+		pending := entry.pending
+		cache.mtx.Unlock()
+		<-pending
+
+		cache.mtx.Lock()
+		contents, err := entry.shared, entry.err
+		cache.mtx.Unlock()
+		if err != nil {
+			return err
+		}
+		if verbose {
+			log.Printf("%s request collapsed into in flight request: %s", r.Name, key)
+		}
+		r.recordCacheHit()
+		return decodeCached(contents, result)
+	}
+
+	if entry.contents != nil && time.Since(entry.fetched) < cache.ttl {
+		contents := entry.contents
+		cache.mtx.Unlock()
+		if verbose {
+			log.Printf("%s request served from cache: %s", r.Name, key)
+		}
+		r.recordCacheHit()
+		return decodeCached(contents, result)
+	}
+
+	pending := make(chan struct{})
+	entry.pending = pending
+	entry.shared = nil
+	if entry.contents != nil {
+		if entry.etag != "" {
+			req.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+	cache.mtx.Unlock()
+
+	err := send()
+
+	cache.mtx.Lock()
+	entry.err = err
+	entry.pending = nil
+	cache.mtx.Unlock()
+	close(pending)
+	return err
+}
+
+// cacheResponse stores the response of a cacheable request and returns the
+// contents to decode, which are the cached contents when the exchange
+// replies that they have not been modified
+func (r *Requester) cacheResponse(req *http.Request, resp *http.Response, contents []byte) []byte {
+	cache := r.getResponseCache()
+	if cache == nil {
+		return contents
+	}
+
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	entry, ok := cache.entries[req.URL.String()]
+	if !ok {
+		return contents
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry.contents != nil:
+		contents = entry.contents
+		entry.fetched = time.Now()
+		r.recordNotModified()
+	case resp.StatusCode == http.StatusOK:
+		entry.contents = contents
+		entry.etag = resp.Header.Get("ETag")
+		entry.lastModified = resp.Header.Get("Last-Modified")
+		entry.fetched = time.Now()
+	}
+	entry.shared = contents
+	return contents
+}
+
+// decodeCached decodes cached contents into result
+func decodeCached(contents []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	return common.JSONDecode(contents, result)
+}
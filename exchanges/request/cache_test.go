@@ -0,0 +1,118 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	r := New("test", NewRateLimit(time.Minute, 0), NewRateLimit(time.Minute, 0), new(http.Client))
+	r.SetResponseCache(true, time.Minute)
+	if !r.IsResponseCacheEnabled() {
+		t.Fatal("Test failed - SetResponseCache() cache not enabled")
+	}
+
+	var result struct {
+		Status string `json:"status"`
+	}
+	for i := 0; i < 2; i++ {
+		result.Status = ""
+		err := r.SendPayload("GET", server.URL, nil, nil, &result, false, false)
+		if err != nil || result.Status != "ok" {
+			t.Fatal("Test failed - SendPayload() error", err)
+		}
+	}
+
+	if atomic.LoadInt32(&hits) != 1 || r.GetUsageStats().CacheHits != 1 {
+		t.Errorf("Test failed - SendPayload() micro-cache not used, %d requests sent", hits)
+	}
+
+	r.SetResponseCache(true, 0)
+	result.Status = ""
+	err := r.SendPayload("GET", server.URL, nil, nil, &result, false, false)
+	if err != nil || result.Status != "ok" {
+		t.Fatal("Test failed - SendPayload() revalidation error", err)
+	}
+
+	if atomic.LoadInt32(&hits) != 2 || r.GetUsageStats().NotModified != 1 {
+		t.Errorf("Test failed - SendPayload() unexpected revalidation stats %+v",
+			r.GetUsageStats())
+	}
+
+	err = r.SendPayload("GET", server.URL, nil, nil, &result, true, false)
+	if err != nil || atomic.LoadInt32(&hits) != 3 {
+		t.Error("Test failed - SendPayload() authenticated request cached", err)
+	}
+
+	r.SetResponseCache(false, 0)
+	if r.IsResponseCacheEnabled() {
+		t.Error("Test failed - SetResponseCache() cache not disabled")
+	}
+}
+
+func TestResponseCacheCollapse(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	r := New("test", NewRateLimit(time.Minute, 0), NewRateLimit(time.Minute, 0), new(http.Client))
+	r.SetResponseCache(true, time.Minute)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result struct {
+				Status string `json:"status"`
+			}
+			err := r.SendPayload("GET", server.URL, nil, nil, &result, false, false)
+			if err == nil && result.Status != "ok" {
+				err = errors.New("unexpected status")
+			}
+			errs <- err
+		}()
+	}
+
+	for atomic.LoadInt32(&hits) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(time.Millisecond * 50)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error("Test failed - SendPayload() collapsed request error", err)
+		}
+	}
+
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("Test failed - SendPayload() duplicate requests not collapsed, %d requests sent",
+			hits)
+	}
+}
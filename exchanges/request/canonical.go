@@ -0,0 +1,108 @@
+package request
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CanonicalRequest holds the parts of a request an exchange signs
+type CanonicalRequest struct {
+	Method  string
+	Host    string
+	Path    string
+	Params  url.Values
+	Headers map[string]string
+	Body    []byte
+}
+
+// CanonicalizeFunc returns the string an exchange signs for a request, so the
+// signature rules of an exchange are declared once rather than repeated in
+// each of its authenticated request funcs
+type CanonicalizeFunc func(req *CanonicalRequest) string
+
+// CanonicalRules holds the common signature canonicalization rules. The
+// signed string joins with Separator the upper case method, host and path
+// when set to be included, the query string of the params sorted by key, the
+// SignedHeaders in their listed order as lower case name:value pairs and the
+// body when included. Params are form encoded, or percent encoded with spaces
+// as %20 when PercentEncodeSpaces is set
+type CanonicalRules struct {
+	Separator           string
+	Method              bool
+	Host                bool
+	Path                bool
+	Body                bool
+	PercentEncodeSpaces bool
+	SignedHeaders       []string
+}
+
+// Canonicalize returns the string to sign for a request under the rules
+func (c CanonicalRules) Canonicalize(req *CanonicalRequest) string {
+	var parts []string
+	if c.Method {
+		parts = append(parts, strings.ToUpper(req.Method))
+	}
+	if c.Host {
+		parts = append(parts, strings.ToLower(req.Host))
+	}
+	if c.Path {
+		parts = append(parts, req.Path)
+	}
+
+	parts = append(parts, CanonicalQuery(req.Params, c.PercentEncodeSpaces))
+
+	for _, name := range c.SignedHeaders {
+		parts = append(parts, strings.ToLower(name)+":"+strings.TrimSpace(getHeader(req.Headers, name)))
+	}
+
+	if c.Body {
+		parts = append(parts, string(req.Body))
+	}
+	return strings.Join(parts, c.Separator)
+}
+
+// getHeader returns the value of a header regardless of the case of its name
+func getHeader(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// CanonicalQuery returns the query string, or URL encoded body, of params
+// sorted by key. Spaces are encoded as %20 when percentEncodeSpaces is set and
+// as + otherwise
+func CanonicalQuery(params url.Values, percentEncodeSpaces bool) string {
+	encoded := params.Encode()
+	if percentEncodeSpaces {
+		// Encode escapes literal plus signs, so any remaining are spaces
+		encoded = strings.Replace(encoded, "+", "%20", -1)
+	}
+	return encoded
+}
+
+// SetCanonicalizer sets the signature canonicalization hook of the requester,
+// a nil hook restores the default of the sorted query string
+func (r *Requester) SetCanonicalizer(fn CanonicalizeFunc) {
+	r.canonicalMtx.Lock()
+	r.canonicalize = fn
+	r.canonicalMtx.Unlock()
+}
+
+// Canonicalize returns the string to sign for a request using the requester's
+// canonicalization hook
+func (r *Requester) Canonicalize(req *CanonicalRequest) string {
+	r.canonicalMtx.Lock()
+	fn := r.canonicalize
+	r.canonicalMtx.Unlock()
+
+	if fn == nil {
+		fn = CanonicalRules{}.Canonicalize
+	}
+	return fn(req)
+}
@@ -0,0 +1,60 @@
+package request
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestCanonicalRules(t *testing.T) {
+	params := url.Values{}
+	params.Set("symbol", "btcusdt")
+	params.Set("AccessKeyId", "key")
+	params.Set("note", "a b+c")
+
+	req := &CanonicalRequest{
+		Method:  "get",
+		Host:    "API.Huobi.pro",
+		Path:    "/v1/order/orders",
+		Params:  params,
+		Headers: map[string]string{"X-Timestamp": " 1 ", "content-type": "application/json"},
+		Body:    []byte(`{"amount":"1"}`),
+	}
+
+	payload := CanonicalRules{}.Canonicalize(req)
+	if payload != "AccessKeyId=key&note=a+b%2Bc&symbol=btcusdt" {
+		t.Error("Test failed - Canonicalize() unexpected default payload", payload)
+	}
+
+	payload = CanonicalRules{
+		Separator:           "\n",
+		Method:              true,
+		Host:                true,
+		Path:                true,
+		Body:                true,
+		PercentEncodeSpaces: true,
+		SignedHeaders:       []string{"Content-Type", "X-Timestamp"},
+	}.Canonicalize(req)
+	expected := "GET\napi.huobi.pro\n/v1/order/orders\nAccessKeyId=key&note=a%20b%2Bc&symbol=btcusdt\n" +
+		"content-type:application/json\nx-timestamp:1\n{\"amount\":\"1\"}"
+	if payload != expected {
+		t.Errorf("Test failed - Canonicalize() expected %q, got %q", expected, payload)
+	}
+}
+
+func TestRequesterCanonicalize(t *testing.T) {
+	r := New("test", NewRateLimit(0, 0), NewRateLimit(0, 0), nil)
+	req := &CanonicalRequest{Method: "POST", Params: url.Values{"b": {"2"}, "a": {"1"}}}
+	if payload := r.Canonicalize(req); payload != "a=1&b=2" {
+		t.Error("Test failed - Canonicalize() unexpected default payload", payload)
+	}
+
+	r.SetCanonicalizer(CanonicalRules{Separator: " ", Method: true}.Canonicalize)
+	if payload := r.Canonicalize(req); payload != "POST a=1&b=2" {
+		t.Error("Test failed - Canonicalize() hook not applied", payload)
+	}
+
+	r.SetCanonicalizer(nil)
+	if payload := r.Canonicalize(req); payload != "a=1&b=2" {
+		t.Error("Test failed - Canonicalize() default not restored", payload)
+	}
+}
@@ -0,0 +1,71 @@
+package request
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Chaos holds the probabilities, from 0 to 1, of the artificial failures
+// injected into requests for resilience testing. TimeoutRate fails a request
+// attempt with a timeout, which is retried like a real timeout, and
+// ServerErrorRate answers a request with a 503 error page in place of the
+// exchange response
+type Chaos struct {
+	TimeoutRate     float64
+	ServerErrorRate float64
+}
+
+// chaosState holds the chaos settings of a requester
+type chaosState struct {
+	chaos Chaos
+	mtx   sync.Mutex
+}
+
+// chaosTimeoutError is the timeout returned for injected timeouts
+type chaosTimeoutError struct{}
+
+func (chaosTimeoutError) Error() string   { return "chaos: injected request timeout" }
+func (chaosTimeoutError) Timeout() bool   { return true }
+func (chaosTimeoutError) Temporary() bool { return true }
+
+// chaosServerErrorPage is the body of injected server errors, an HTML page as
+// returned by exchanges behind a failing load balancer
+const chaosServerErrorPage = "<html><body><h1>503 Service Unavailable</h1>chaos: injected server error</body></html>"
+
+// SetChaos sets the probabilities of the failures injected into requests, the
+// zero value disables chaos mode
+func (r *Requester) SetChaos(c Chaos) {
+	r.chaos.mtx.Lock()
+	r.chaos.chaos = c
+	r.chaos.mtx.Unlock()
+}
+
+// GetChaos returns the probabilities of the failures injected into requests
+func (r *Requester) GetChaos() Chaos {
+	r.chaos.mtx.Lock()
+	defer r.chaos.mtx.Unlock()
+	return r.chaos.chaos
+}
+
+// do sends a request, injecting a timeout or server error in its place at the
+// chaos mode probabilities
+func (r *Requester) do(req *http.Request) (*http.Response, error) {
+	c := r.GetChaos()
+	if c.TimeoutRate > 0 && rand.Float64() < c.TimeoutRate {
+		return nil, chaosTimeoutError{}
+	}
+
+	if c.ServerErrorRate > 0 && rand.Float64() < c.ServerErrorRate {
+		return &http.Response{
+			Status:     "503 Service Unavailable",
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(strings.NewReader(chaosServerErrorPage)),
+			Request:    req,
+		}, nil
+	}
+	return r.getHTTPClient(req).Do(req)
+}
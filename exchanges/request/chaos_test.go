@@ -0,0 +1,44 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChaos(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	r := New("test", NewRateLimit(time.Minute, 0), NewRateLimit(time.Minute, 0), new(http.Client))
+	var result struct {
+		Status string `json:"status"`
+	}
+
+	r.SetChaos(Chaos{TimeoutRate: 1})
+	if r.GetChaos().TimeoutRate != 1 {
+		t.Error("Test failed - GetChaos() unexpected chaos settings")
+	}
+	err := r.SendPayload("GET", server.URL, nil, nil, &result, false, false)
+	if err == nil || atomic.LoadInt32(&hits) != 0 {
+		t.Error("Test failed - SendPayload() injected timeout not returned", err)
+	}
+
+	r.SetChaos(Chaos{ServerErrorRate: 1})
+	err = r.SendPayload("GET", server.URL, nil, nil, &result, false, false)
+	if err == nil || atomic.LoadInt32(&hits) != 0 {
+		t.Error("Test failed - SendPayload() injected server error not returned", err)
+	}
+
+	r.SetChaos(Chaos{})
+	err = r.SendPayload("GET", server.URL, nil, nil, &result, false, false)
+	if err != nil || result.Status != "ok" {
+		t.Error("Test failed - SendPayload() error with chaos mode disabled", err)
+	}
+}
@@ -0,0 +1,61 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrNoServerDate is returned when no response with a Date header has been
+// received to estimate the server clock from
+var ErrNoServerDate = errors.New("no server Date header received")
+
+// clockBounds holds the range of the offset between the server clock and the
+// local clock which is consistent with every Date header received
+type clockBounds struct {
+	lower   time.Duration
+	upper   time.Duration
+	samples int
+}
+
+// recordServerDate narrows the server clock offset bounds using the Date
+// header of a response. The Date header is truncated to the second, so the
+// server time was within [Date, Date+1s) at some point between sending the
+// request and receiving the response. Bounds which no longer overlap mean
+// either clock has drifted, so the estimate restarts from the new response
+func (r *Requester) recordServerDate(resp *http.Response, sent, received time.Time) {
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	lower := date.Sub(received)
+	upper := date.Add(time.Second).Sub(sent)
+
+	r.clockMtx.Lock()
+	defer r.clockMtx.Unlock()
+	if r.clock.samples == 0 || lower > r.clock.upper || upper < r.clock.lower {
+		r.clock = clockBounds{lower: lower, upper: upper, samples: 1}
+		return
+	}
+
+	if lower > r.clock.lower {
+		r.clock.lower = lower
+	}
+	if upper < r.clock.upper {
+		r.clock.upper = upper
+	}
+	r.clock.samples++
+}
+
+// GetServerClockSkew returns the difference between the server clock and the
+// local clock estimated from the Date headers of the responses received,
+// for exchanges which don't expose their server time
+func (r *Requester) GetServerClockSkew() (time.Duration, error) {
+	r.clockMtx.Lock()
+	defer r.clockMtx.Unlock()
+	if r.clock.samples == 0 {
+		return 0, ErrNoServerDate
+	}
+	return r.clock.lower + (r.clock.upper-r.clock.lower)/2, nil
+}
@@ -0,0 +1,52 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServerClockSkew(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	r := New("test", NewRateLimit(time.Minute, 10), NewRateLimit(time.Minute, 10), new(http.Client))
+	if _, err := r.GetServerClockSkew(); err != ErrNoServerDate {
+		t.Error("Test failed - GetServerClockSkew() expected no server date error", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		err := r.SendPayload("GET", server.URL, nil, nil, nil, false, false)
+		if err != nil {
+			t.Fatal("Test failed - SendPayload() error", err)
+		}
+	}
+
+	skew, err := r.GetServerClockSkew()
+	if err != nil {
+		t.Fatal("Test failed - GetServerClockSkew() error", err)
+	}
+
+	if skew < time.Hour-time.Second || skew > time.Hour+time.Second {
+		t.Errorf("Test failed - GetServerClockSkew() expected ~1h received %v", skew)
+	}
+
+	// A Date header outside the current bounds restarts the estimate
+	sent := time.Now()
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Date", sent.Add(-time.Hour).UTC().Format(http.TimeFormat))
+	r.recordServerDate(resp, sent, sent)
+
+	skew, err = r.GetServerClockSkew()
+	if err != nil {
+		t.Fatal("Test failed - GetServerClockSkew() error", err)
+	}
+
+	if skew < -time.Hour-time.Second || skew > -time.Hour+time.Second {
+		t.Errorf("Test failed - GetServerClockSkew() expected ~-1h received %v", skew)
+	}
+}
@@ -0,0 +1,91 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SetRequestContext sets the context every request of the requester is made
+// under, cancelling it aborts in flight and queued requests. It is typically
+// cancelled when the exchange is unloaded or the bot shuts down
+func (r *Requester) SetRequestContext(ctx context.Context) {
+	if r == nil {
+		return
+	}
+
+	r.ctxMtx.Lock()
+	r.ctx = ctx
+	r.ctxMtx.Unlock()
+}
+
+// getRequestContext returns the context of the requester, a background
+// context when none has been set
+func (r *Requester) getRequestContext() context.Context {
+	r.ctxMtx.Lock()
+	defer r.ctxMtx.Unlock()
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// mergeContext returns a context derived from the call context which is also
+// cancelled when the requester's context is done
+func (r *Requester) mergeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	merged, cancel := context.WithCancel(ctx)
+	base := r.getRequestContext()
+	if base.Done() == nil {
+		return merged, cancel
+	}
+
+	go func() {
+		select {
+		case <-base.Done():
+			cancel()
+		case <-merged.Done():
+		}
+	}()
+	return merged, cancel
+}
+
+// SendPayloadContext sends a request as SendPayload under a call context. The
+// request is abandoned when either the call context or the requester's
+// context is done, and a deadline on the call context overrides the timeout of
+// the HTTP client, so a call may wait longer or shorter than the exchange's
+// default timeout
+func (r *Requester) SendPayloadContext(ctx context.Context, method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, verbose bool) error {
+	if r == nil || r.Name == "" {
+		return errors.New("not initiliased, SetDefaults() called before making request?")
+	}
+
+	ctx, cancel := r.mergeContext(ctx)
+	defer cancel()
+	return r.sendPayload(ctx, method, path, headers, body, result, authRequest, verbose)
+}
+
+// SendPayloadTimeout sends a request as SendPayload with a timeout for the
+// single call overriding the timeout of the HTTP client
+func (r *Requester) SendPayloadTimeout(timeout time.Duration, method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, verbose bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return r.SendPayloadContext(ctx, method, path, headers, body, result, authRequest, verbose)
+}
+
+// getHTTPClient returns the client to send a request with, a copy of the
+// client without its timeout when the request's context has a deadline
+func (r *Requester) getHTTPClient(req *http.Request) *http.Client {
+	if _, ok := req.Context().Deadline(); !ok || r.HTTPClient.Timeout == 0 {
+		return r.HTTPClient
+	}
+
+	client := *r.HTTPClient
+	client.Timeout = 0
+	return &client
+}
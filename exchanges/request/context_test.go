@@ -0,0 +1,76 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSendPayloadContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond * 100)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	r := New("test", NewRateLimit(time.Minute, 0), NewRateLimit(time.Minute, 0),
+		&http.Client{Timeout: time.Millisecond * 20})
+	var result struct {
+		Status string `json:"status"`
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	err := r.SendPayloadContext(ctx, "GET", server.URL, nil, nil, &result, false, false)
+	cancel()
+	if err != nil || result.Status != "ok" {
+		t.Error("Test failed - SendPayloadContext() deadline not overriding client timeout", err)
+	}
+
+	err = r.SendPayloadTimeout(time.Millisecond*10, "GET", server.URL, nil, nil, &result, false, false)
+	if err != context.DeadlineExceeded {
+		t.Error("Test failed - SendPayloadTimeout() expected deadline exceeded error", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = r.SendPayloadContext(ctx, "GET", server.URL, nil, nil, &result, false, false)
+	if err != context.Canceled {
+		t.Error("Test failed - SendPayloadContext() expected cancelled error", err)
+	}
+
+	r.HTTPClient.Timeout = 0
+	ctx, cancel = context.WithCancel(context.Background())
+	r.SetRequestContext(ctx)
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		cancel()
+	}()
+	start := time.Now()
+	err = r.SendPayload("GET", server.URL, nil, nil, &result, false, false)
+	if err != context.Canceled || time.Since(start) >= time.Millisecond*100 {
+		t.Error("Test failed - SendPayload() request not cancelled with requester context", err)
+	}
+}
+
+func TestSendPayloadContextQueued(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	r := New("test", NewRateLimit(time.Minute, 1), NewRateLimit(time.Minute, 1), new(http.Client))
+	err := r.SendPayload("GET", server.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	// The rate limit is used up so the next request waits in the queue
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	err = r.SendPayloadContext(ctx, "GET", server.URL, nil, nil, nil, false, false)
+	if err != context.DeadlineExceeded {
+		t.Error("Test failed - SendPayloadContext() queued request not abandoned", err)
+	}
+}
@@ -0,0 +1,228 @@
+package request
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultRedisTimeout bounds connecting to and each command sent to Redis
+const defaultRedisTimeout = time.Second
+
+// redisTokenBucket takes a token from the bucket hash of KEYS[1] holding up to
+// ARGV[1] tokens refilled evenly over ARGV[2] microseconds, returning zero when
+// a token was taken or the microseconds until one is available. The Redis
+// clock is used so processes with skewed clocks agree on the refill
+const redisTokenBucket = `
+if redis.replicate_commands then redis.replicate_commands() end
+local rate = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = rate
+	ts = now
+end
+tokens = math.min(rate, tokens + math.max(0, now - ts) * rate / period)
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+else
+	wait = math.ceil((1 - tokens) * period / rate)
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(period / 1000) * 2)
+return wait
+`
+
+// RedisRateLimiter is a RateLimitBackend holding token buckets in Redis, so
+// every bot process connected to the same Redis server shares them. Buckets
+// are taken from atomically by a Lua script and expire once idle
+type RedisRateLimiter struct {
+	address  string
+	password string
+	db       int
+	timeout  time.Duration
+
+	conn   net.Conn
+	reader *bufio.Reader
+	mtx    sync.Mutex
+}
+
+// NewRedisRateLimiter returns a rate limiter using the Redis server at
+// address, authenticating with password when set and selecting db. A zero
+// timeout defaults to one second
+func NewRedisRateLimiter(address, password string, db int, timeout time.Duration) *RedisRateLimiter {
+	if timeout <= 0 {
+		timeout = defaultRedisTimeout
+	}
+	return &RedisRateLimiter{
+		address:  address,
+		password: password,
+		db:       db,
+		timeout:  timeout,
+	}
+}
+
+// Take takes a token from the bucket of key, holding up to rate tokens
+// refilled evenly over period, returning zero when a token was taken or how
+// long to wait before one is available
+func (r *RedisRateLimiter) Take(key string, rate int, period time.Duration) (time.Duration, error) {
+	if rate <= 0 || period <= 0 {
+		return 0, nil
+	}
+
+	reply, err := r.do("EVAL", redisTokenBucket, "1", key, strconv.Itoa(rate),
+		strconv.FormatInt(int64(period/time.Microsecond), 10))
+	if err != nil {
+		return 0, err
+	}
+
+	wait, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected redis token bucket reply %v", reply)
+	}
+	return time.Duration(wait) * time.Microsecond, nil
+}
+
+// Close closes the connection to Redis
+func (r *RedisRateLimiter) Close() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.conn == nil {
+		return nil
+	}
+	err := r.conn.Close()
+	r.conn = nil
+	return err
+}
+
+// do sends a command and returns its reply, connecting when not connected.
+// The connection is dropped after a failed command so the next reconnects
+func (r *RedisRateLimiter) do(args ...string) (interface{}, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.conn == nil {
+		err := r.connect()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := r.command(args...)
+	if err != nil {
+		if _, ok := err.(redisError); !ok {
+			r.conn.Close()
+			r.conn = nil
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+// connect dials Redis, authenticating and selecting the database. The caller
+// must hold mtx
+func (r *RedisRateLimiter) connect() error {
+	conn, err := net.DialTimeout("tcp", r.address, r.timeout)
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+	r.reader = bufio.NewReader(conn)
+
+	if r.password != "" {
+		_, err = r.command("AUTH", r.password)
+	}
+	if err == nil && r.db != 0 {
+		_, err = r.command("SELECT", strconv.Itoa(r.db))
+	}
+	if err != nil {
+		conn.Close()
+		r.conn = nil
+		return err
+	}
+	return nil
+}
+
+// command writes a command as a RESP array of bulk strings and reads its
+// reply. The caller must hold mtx
+func (r *RedisRateLimiter) command(args ...string) (interface{}, error) {
+	err := r.conn.SetDeadline(time.Now().Add(r.timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"+arg+"\r\n"...)
+	}
+	_, err = r.conn.Write(buf)
+	if err != nil {
+		return nil, err
+	}
+	return readRedisReply(r.reader)
+}
+
+// redisError is an error reply from Redis, the connection remains usable
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// readRedisReply reads a RESP reply, returning simple strings and bulk
+// strings as strings, integers as int64, arrays as []interface{} and nil
+// bulk strings and arrays as nil
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("malformed redis reply")
+	}
+	payload := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		_, err = io.ReadFull(reader, data)
+		if err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for x := range values {
+			values[x], err = readRedisReply(reader)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unknown redis reply type %q", line[0])
+}
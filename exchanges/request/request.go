@@ -1,6 +1,7 @@
 package request
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -35,6 +36,23 @@ type Requester struct {
 	m                    sync.Mutex
 	Jobs                 chan Job
 	WorkerStarted        bool
+	recordStats          bool
+	stats                ConnectionStats
+	statsMtx             sync.Mutex
+	scope                ScopeFunc
+	scopeMtx             sync.Mutex
+	usage                UsageStats
+	usageMtx             sync.Mutex
+	cache                *responseCache
+	cacheMtx             sync.Mutex
+	clock                clockBounds
+	clockMtx             sync.Mutex
+	chaos                chaosState
+	canonicalize         CanonicalizeFunc
+	canonicalMtx         sync.Mutex
+	ctx                  context.Context
+	ctxMtx               sync.Mutex
+	sharedLimitID        string
 }
 
 // RateLimit struct
@@ -262,8 +280,39 @@ func (r *Requester) DoRequest(req *http.Request, method, path string, headers ma
 
 	var timeoutError error
 	for i := 0; i < r.timeoutRetryAttempts+1; i++ {
-		resp, err := r.HTTPClient.Do(req)
+		var record *AuditRecord
+		if IsAuditable(authRequest) {
+			record = newAuditRecord(r.Name, req)
+		}
+
+		httpReq := req
+		var timing *requestTiming
+		if r.IsConnectionStatsEnabled() {
+			httpReq, timing = traceRequest(req)
+		}
+
+		sent := time.Now()
+		resp, err := r.do(httpReq)
+		if err != nil && req.Context().Err() != nil {
+			// The call was cancelled or ran out of time, don't retry it
+			err = req.Context().Err()
+			if record != nil {
+				record.complete(0, nil, err)
+			}
+			if r.RequiresRateLimiter() {
+				r.DecrementRequests(authRequest)
+			}
+			r.recordRequest(authRequest, 0, err)
+			return err
+		}
+		if timing != nil {
+			r.recordTiming(timing, verbose)
+		}
 		if err != nil {
+			if record != nil {
+				record.complete(0, nil, err)
+			}
+
 			if timeoutErr, ok := err.(net.Error); ok && timeoutErr.Timeout() {
 				if verbose {
 					log.Printf("%s request has timed-out retrying request, count %d",
@@ -277,17 +326,26 @@ func (r *Requester) DoRequest(req *http.Request, method, path string, headers ma
 			if r.RequiresRateLimiter() {
 				r.DecrementRequests(authRequest)
 			}
+			r.recordRequest(authRequest, 0, err)
 			return err
 		}
 		if resp == nil {
 			if r.RequiresRateLimiter() {
 				r.DecrementRequests(authRequest)
 			}
-			return errors.New("resp is nil")
+			err = errors.New("resp is nil")
+			r.recordRequest(authRequest, 0, err)
+			return err
 		}
+		r.recordServerDate(resp, sent, time.Now())
 
 		contents, err := ioutil.ReadAll(resp.Body)
+		if record != nil {
+			record.complete(resp.StatusCode, contents, err)
+		}
+
 		if err != nil {
+			r.recordRequest(authRequest, len(contents), err)
 			return err
 		}
 
@@ -296,19 +354,40 @@ func (r *Requester) DoRequest(req *http.Request, method, path string, headers ma
 			log.Printf("%s exchange raw response: %s", r.Name, string(contents[:]))
 		}
 
-		if result != nil {
-			return common.JSONDecode(contents, result)
+		received := len(contents)
+		if isCacheable(method, authRequest) {
+			contents = r.cacheResponse(req, resp, contents)
 		}
 
-		return nil
+		if result != nil {
+			err = common.JSONDecode(contents, result)
+		}
+		r.recordRequest(authRequest, received, err)
+		return err
 	}
-	return fmt.Errorf("request.go error - failed to retry request %s",
+
+	err := fmt.Errorf("request.go error - failed to retry request %w",
 		timeoutError)
+	r.recordRequest(authRequest, 0, err)
+	return err
 }
 
 func (r *Requester) worker() {
 	for {
 		for x := range r.Jobs {
+			if err := x.Request.Context().Err(); err != nil {
+				// The caller has given up on the queued request
+				x.JobResult <- &JobResult{Error: err}
+				continue
+			}
+
+			// Wait for the quota shared with other processes before the
+			// local rate limiter
+			if err := r.waitSharedLimit(x.Request.Context(), x.AuthRequest); err != nil {
+				x.JobResult <- &JobResult{Error: err}
+				continue
+			}
+
 			if !r.IsRateLimited(x.AuthRequest) {
 				r.IncrementRequests(x.AuthRequest)
 
@@ -318,6 +397,7 @@ func (r *Requester) worker() {
 					Result: x.Result,
 				}
 			} else {
+				r.recordRateLimited()
 				limit := r.GetRateLimit(x.AuthRequest)
 				diff := limit.GetDuration() - time.Since(r.Cycle)
 				if x.Verbose {
@@ -346,11 +426,14 @@ func (r *Requester) worker() {
 	}
 }
 
-// SendPayload handles sending HTTP/HTTPS requests
+// SendPayload handles sending HTTP/HTTPS requests under the requester's
+// context
 func (r *Requester) SendPayload(method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, verbose bool) error {
-	if r == nil || r.Name == "" {
-		return errors.New("not initiliased, SetDefaults() called before making request?")
-	}
+	return r.SendPayloadContext(context.Background(), method, path, headers, body, result, authRequest, verbose)
+}
+
+// sendPayload builds and sends a request under a context
+func (r *Requester) sendPayload(ctx context.Context, method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, verbose bool) error {
 
 	if !IsValidMethod(method) {
 		return fmt.Errorf("incorrect method supplied %s: supported %s", method, supportedMethods)
@@ -360,11 +443,32 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 		return errors.New("invalid path")
 	}
 
+	if IsAuditable(authRequest) {
+		// Buffer the body so it can be captured in the audit trail
+		var err error
+		body, err = bufferBody(body)
+		if err != nil {
+			return err
+		}
+	}
+
 	req, err := r.checkRequest(method, path, body, headers)
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
+	r.applyScope(req, authRequest)
+
+	if cache := r.getResponseCache(); cache != nil && isCacheable(method, authRequest) {
+		return r.sendCached(cache, req, result, verbose, func() error {
+			return r.send(req, method, path, headers, body, result, authRequest, verbose)
+		})
+	}
+	return r.send(req, method, path, headers, body, result, authRequest, verbose)
+}
 
+// send performs the request directly or through the rate limited worker
+func (r *Requester) send(req *http.Request, method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, verbose bool) error {
 	if !r.RequiresRateLimiter() {
 		return r.DoRequest(req, method, path, headers, body, result, authRequest, verbose)
 	}
@@ -381,7 +485,8 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 	}
 	r.m.Unlock()
 
-	jobResult := make(chan *JobResult)
+	// Buffered so the worker never blocks on a caller which has given up
+	jobResult := make(chan *JobResult, 1)
 
 	newJob := Job{
 		Request:     req,
@@ -398,12 +503,22 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 	if verbose {
 		log.Printf("%s request. Attaching new job.", r.Name)
 	}
-	r.Jobs <- newJob
+	select {
+	case r.Jobs <- newJob:
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
 
 	if verbose {
 		log.Printf("%s request. Waiting for job to complete.", r.Name)
 	}
-	resp := <-newJob.JobResult
+
+	var resp *JobResult
+	select {
+	case resp = <-newJob.JobResult:
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
 
 	if verbose {
 		log.Printf("%s request. Job complete.", r.Name)
@@ -417,6 +532,15 @@ func (r *Requester) SetProxy(p *url.URL) error {
 		return errors.New("No proxy URL supplied")
 	}
 
+	// Preserve any transport tuning which has already been applied
+	if t, ok := r.HTTPClient.Transport.(*http.Transport); ok {
+		t.Proxy = http.ProxyURL(p)
+		if t.TLSHandshakeTimeout == 0 {
+			t.TLSHandshakeTimeout = proxyTLSTimeout
+		}
+		return nil
+	}
+
 	r.HTTPClient.Transport = &http.Transport{
 		Proxy:               http.ProxyURL(p),
 		TLSHandshakeTimeout: proxyTLSTimeout,
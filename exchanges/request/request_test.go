@@ -1,7 +1,9 @@
 package request
 
 import (
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
@@ -322,3 +324,44 @@ func TestDoRequest(t *testing.T) {
 		t.Error("failed to set proxy")
 	}
 }
+
+func TestConnectionStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0), new(http.Client))
+	err := r.SendPayload("GET", server.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	if r.GetConnectionStats().Requests != 0 {
+		t.Error("Test failed - connection stats recorded while disabled")
+	}
+
+	r.EnableConnectionStats(true)
+	for i := 0; i < 2; i++ {
+		err = r.SendPayload("GET", server.URL, nil, nil, nil, false, false)
+		if err != nil {
+			t.Fatal("Test failed - SendPayload() error", err)
+		}
+	}
+
+	stats := r.GetConnectionStats()
+	if stats.Requests != 2 {
+		t.Errorf("Test failed - expected 2 recorded requests received %d",
+			stats.Requests)
+	}
+
+	if stats.ReusedConns != 2 {
+		t.Errorf("Test failed - expected 2 reused connections received %d",
+			stats.ReusedConns)
+	}
+
+	r.ResetConnectionStats()
+	if r.GetConnectionStats().Requests != 0 {
+		t.Error("Test failed - ResetConnectionStats() did not clear stats")
+	}
+}
@@ -0,0 +1,80 @@
+package request
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Scope holds the headers and query parameters which scope a request to an
+// account context, such as an exchange sub-account
+type Scope struct {
+	Headers map[string]string
+	Params  url.Values
+}
+
+// ScopeFunc returns the scope to apply to a request. It is called for every
+// request so the account context can change at runtime
+type ScopeFunc func(authRequest bool) Scope
+
+// SetScope sets the scoping hook applied to every request sent by the
+// requester, a nil hook disables scoping. Scope parameters are added after
+// the request has been built, exchanges which sign the query string should
+// scope requests using headers instead
+func (r *Requester) SetScope(fn ScopeFunc) {
+	r.scopeMtx.Lock()
+	r.scope = fn
+	r.scopeMtx.Unlock()
+}
+
+// IsScoped returns whether a scoping hook is set
+func (r *Requester) IsScoped() bool {
+	r.scopeMtx.Lock()
+	defer r.scopeMtx.Unlock()
+	return r.scope != nil
+}
+
+// applyScope adds the headers and parameters of the current scope to the
+// request
+func (r *Requester) applyScope(req *http.Request, authRequest bool) {
+	r.scopeMtx.Lock()
+	fn := r.scope
+	r.scopeMtx.Unlock()
+	if fn == nil {
+		return
+	}
+
+	scope := fn(authRequest)
+	for k, v := range scope.Headers {
+		req.Header.Set(k, v)
+	}
+
+	if len(scope.Params) == 0 {
+		return
+	}
+
+	query := req.URL.Query()
+	for k, v := range scope.Params {
+		for x := range v {
+			query.Add(k, v[x])
+		}
+	}
+	req.URL.RawQuery = query.Encode()
+}
+
+// NewSubAccountScope returns a scoping hook which sets the supplied header to
+// the current sub-account on authenticated requests. The sub-account is
+// looked up per request and the header is omitted when it is empty
+func NewSubAccountScope(header string, subAccount func() string) ScopeFunc {
+	return func(authRequest bool) Scope {
+		if !authRequest || subAccount == nil {
+			return Scope{}
+		}
+
+		account := subAccount()
+		if account == "" {
+			return Scope{}
+		}
+
+		return Scope{Headers: map[string]string{header: url.PathEscape(account)}}
+	}
+}
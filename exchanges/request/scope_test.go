@@ -0,0 +1,59 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestScope(t *testing.T) {
+	var header, param string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("TEST-SUBACCOUNT")
+		param = r.URL.Query().Get("account")
+	}))
+	defer server.Close()
+
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0), new(http.Client))
+	if r.IsScoped() {
+		t.Error("Test failed - IsScoped() true with no scope set")
+	}
+
+	subAccount := "main account"
+	r.SetScope(func(authRequest bool) Scope {
+		scope := NewSubAccountScope("TEST-SUBACCOUNT", func() string { return subAccount })(authRequest)
+		scope.Params = url.Values{"account": []string{"1337"}}
+		return scope
+	})
+
+	err := r.SendPayload("GET", server.URL+"?symbol=btcusd", nil, nil, nil, true, false)
+	if err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	if header != "main%20account" || param != "1337" {
+		t.Errorf("Test failed - scope not applied, header: %s param: %s",
+			header, param)
+	}
+
+	err = r.SendPayload("GET", server.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	if header != "" {
+		t.Error("Test failed - sub-account header set on public request")
+	}
+
+	r.SetScope(nil)
+	err = r.SendPayload("GET", server.URL, nil, nil, nil, true, false)
+	if err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	if header != "" || param != "" {
+		t.Error("Test failed - scope applied after being removed")
+	}
+}
@@ -0,0 +1,104 @@
+package request
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// RateLimitBackend takes request tokens from rate limit buckets shared between
+// bot processes, so several processes using one API key stay within a single
+// exchange quota
+type RateLimitBackend interface {
+	// Take takes a token from the bucket of key, holding up to rate tokens
+	// refilled evenly over period. It returns zero when a token was taken or
+	// how long to wait before one is available
+	Take(key string, rate int, period time.Duration) (time.Duration, error)
+}
+
+// Vars for the shared rate limit backend
+var (
+	sharedLimiter          RateLimitBackend
+	sharedLimiterNamespace string
+	sharedLimiterMtx       sync.Mutex
+)
+
+// SetSharedRateLimiter sets the backend every requester takes its rate limit
+// tokens from in addition to its local rate limiter, nil disables it. Buckets
+// are keyed under namespace so unrelated deployments sharing a backend don't
+// share quotas
+func SetSharedRateLimiter(b RateLimitBackend, namespace string) {
+	sharedLimiterMtx.Lock()
+	sharedLimiter = b
+	sharedLimiterNamespace = namespace
+	sharedLimiterMtx.Unlock()
+}
+
+// GetSharedRateLimiter returns the shared rate limit backend and its
+// namespace, a nil backend when requests are only rate limited locally
+func GetSharedRateLimiter() (RateLimitBackend, string) {
+	sharedLimiterMtx.Lock()
+	defer sharedLimiterMtx.Unlock()
+	return sharedLimiter, sharedLimiterNamespace
+}
+
+// SetSharedLimitID sets the identity, typically derived from the API key, the
+// shared bucket of the requester's authenticated requests is keyed by
+func (r *Requester) SetSharedLimitID(id string) {
+	r.m.Lock()
+	r.sharedLimitID = id
+	r.m.Unlock()
+}
+
+// sharedLimitKey returns the shared bucket key of authenticated or
+// unauthenticated requests. Authenticated requests share a bucket per API key
+// and unauthenticated requests one per exchange
+func (r *Requester) sharedLimitKey(namespace string, auth bool) string {
+	key := namespace + ":" + common.StringToLower(r.Name)
+	if !auth {
+		return key + ":unauth"
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	return key + ":auth:" + r.sharedLimitID
+}
+
+// waitSharedLimit waits until a token is taken from the shared bucket of the
+// request or ctx is done. When the backend fails the request proceeds under
+// the local rate limiter alone
+func (r *Requester) waitSharedLimit(ctx context.Context, auth bool) error {
+	backend, namespace := GetSharedRateLimiter()
+	if backend == nil {
+		return nil
+	}
+
+	limit := r.GetRateLimit(auth)
+	rate, period := limit.GetRate(), limit.GetDuration()
+	if rate <= 0 || period <= 0 {
+		return nil
+	}
+
+	key := r.sharedLimitKey(namespace, auth)
+	for {
+		wait, err := backend.Take(key, rate, period)
+		if err != nil {
+			log.Printf("%s shared rate limiter unavailable, using local rate limit. Error: %s",
+				r.Name, err)
+			return nil
+		}
+		if wait <= 0 {
+			return nil
+		}
+
+		r.recordRateLimited()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package request
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testBackend struct {
+	keys []string
+	wait time.Duration
+	mtx  sync.Mutex
+}
+
+func (b *testBackend) Take(key string, rate int, period time.Duration) (time.Duration, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.keys = append(b.keys, key)
+	wait := b.wait
+	b.wait = 0
+	return wait, nil
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	backend := &testBackend{wait: time.Millisecond * 10}
+	SetSharedRateLimiter(backend, "gct")
+	defer SetSharedRateLimiter(nil, "")
+
+	r := New("Test", NewRateLimit(time.Second, 10), NewRateLimit(time.Second, 10), new(http.Client))
+	r.SetSharedLimitID("key")
+	if err := r.SendPayload("GET", server.URL, nil, nil, nil, true, false); err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+	if err := r.SendPayload("GET", server.URL, nil, nil, nil, false, false); err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	expected := []string{"gct:test:auth:key", "gct:test:auth:key", "gct:test:unauth"}
+	if len(backend.keys) != len(expected) {
+		t.Fatalf("Test failed - shared limiter expected takes %v, got %v", expected, backend.keys)
+	}
+	for x := range expected {
+		if backend.keys[x] != expected[x] {
+			t.Errorf("Test failed - shared limiter expected takes %v, got %v", expected, backend.keys)
+		}
+	}
+	if r.GetUsageStats().RateLimited == 0 {
+		t.Error("Test failed - shared limiter wait not recorded as rate limited")
+	}
+}
+
+func TestRedisRateLimiter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Test failed - unable to listen", err)
+	}
+	defer listener.Close()
+
+	commands := make(chan []interface{}, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for _, reply := range []string{"+OK\r\n", "+OK\r\n", ":0\r\n", ":1500\r\n", "-ERR wrong\r\n"} {
+			command, err := readRedisReply(reader)
+			if err != nil {
+				return
+			}
+			commands <- command.([]interface{})
+			conn.Write([]byte(reply))
+		}
+	}()
+
+	r := NewRedisRateLimiter(listener.Addr().String(), "secret", 2, 0)
+	defer r.Close()
+
+	wait, err := r.Take("gct:test:unauth", 5, time.Second)
+	if err != nil || wait != 0 {
+		t.Fatalf("Test failed - Take() expected a token, got %v %v", wait, err)
+	}
+	wait, err = r.Take("gct:test:unauth", 5, time.Second)
+	if err != nil || wait != time.Microsecond*1500 {
+		t.Errorf("Test failed - Take() expected a 1.5ms wait, got %v %v", wait, err)
+	}
+	if _, err = r.Take("gct:test:unauth", 5, time.Second); err == nil {
+		t.Error("Test failed - Take() expected the redis error")
+	}
+
+	auth, selectDB, eval := <-commands, <-commands, <-commands
+	if auth[0] != "AUTH" || auth[1] != "secret" || selectDB[1] != "2" {
+		t.Errorf("Test failed - unexpected connection commands %v %v", auth, selectDB)
+	}
+	if eval[0] != "EVAL" || eval[3] != "gct:test:unauth" || eval[4] != "5" || eval[5] != "1000000" {
+		t.Errorf("Test failed - unexpected token bucket command %v", eval[0:2])
+	}
+}
@@ -0,0 +1,173 @@
+package request
+
+import (
+	"crypto/tls"
+	"log"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ConnectionStats holds cumulative connection level timings for a requester
+// which can be used to diagnose latency and tune the HTTP transport
+type ConnectionStats struct {
+	Requests         int64         `json:"requests"`
+	ReusedConns      int64         `json:"reusedConns"`
+	IdleConns        int64         `json:"idleConns"`
+	DNSLookups       int64         `json:"dnsLookups"`
+	TotalDNSTime     time.Duration `json:"totalDnsTime"`
+	Connects         int64         `json:"connects"`
+	TotalConnectTime time.Duration `json:"totalConnectTime"`
+	TLSHandshakes    int64         `json:"tlsHandshakes"`
+	TotalTLSTime     time.Duration `json:"totalTlsTime"`
+	TotalFirstByte   time.Duration `json:"totalFirstByte"`
+}
+
+// requestTiming holds the connection timings for a single request
+type requestTiming struct {
+	start, dnsStart, connectStart, tlsStart time.Time
+	dns, connect, tls, firstByte            time.Duration
+	reused, wasIdle                         bool
+	m                                       sync.Mutex
+}
+
+// EnableConnectionStats toggles the recording of connection timings
+func (r *Requester) EnableConnectionStats(enabled bool) {
+	r.statsMtx.Lock()
+	r.recordStats = enabled
+	r.statsMtx.Unlock()
+}
+
+// IsConnectionStatsEnabled returns whether connection timings are recorded
+func (r *Requester) IsConnectionStatsEnabled() bool {
+	r.statsMtx.Lock()
+	defer r.statsMtx.Unlock()
+	return r.recordStats
+}
+
+// GetConnectionStats returns a copy of the recorded connection timings
+func (r *Requester) GetConnectionStats() ConnectionStats {
+	r.statsMtx.Lock()
+	defer r.statsMtx.Unlock()
+	return r.stats
+}
+
+// ResetConnectionStats clears the recorded connection timings
+func (r *Requester) ResetConnectionStats() {
+	r.statsMtx.Lock()
+	r.stats = ConnectionStats{}
+	r.statsMtx.Unlock()
+}
+
+// AverageDNSTime returns the mean DNS lookup duration
+func (c *ConnectionStats) AverageDNSTime() time.Duration {
+	return average(c.TotalDNSTime, c.DNSLookups)
+}
+
+// AverageConnectTime returns the mean TCP connect duration
+func (c *ConnectionStats) AverageConnectTime() time.Duration {
+	return average(c.TotalConnectTime, c.Connects)
+}
+
+// AverageTLSTime returns the mean TLS handshake duration
+func (c *ConnectionStats) AverageTLSTime() time.Duration {
+	return average(c.TotalTLSTime, c.TLSHandshakes)
+}
+
+// AverageFirstByte returns the mean time from sending a request until the
+// first response byte is received
+func (c *ConnectionStats) AverageFirstByte() time.Duration {
+	return average(c.TotalFirstByte, c.Requests)
+}
+
+func average(total time.Duration, count int64) time.Duration {
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
+
+// traceRequest attaches a client trace to the request which captures
+// connection reuse, DNS, connect and TLS handshake timings
+func traceRequest(req *http.Request) (*http.Request, *requestTiming) {
+	timing := &requestTiming{start: time.Now()}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			timing.m.Lock()
+			timing.reused = info.Reused
+			timing.wasIdle = info.WasIdle
+			timing.m.Unlock()
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			timing.m.Lock()
+			timing.dnsStart = time.Now()
+			timing.m.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			timing.m.Lock()
+			timing.dns = time.Since(timing.dnsStart)
+			timing.m.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			timing.m.Lock()
+			timing.connectStart = time.Now()
+			timing.m.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			timing.m.Lock()
+			timing.connect = time.Since(timing.connectStart)
+			timing.m.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			timing.m.Lock()
+			timing.tlsStart = time.Now()
+			timing.m.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			timing.m.Lock()
+			timing.tls = time.Since(timing.tlsStart)
+			timing.m.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			timing.m.Lock()
+			timing.firstByte = time.Since(timing.start)
+			timing.m.Unlock()
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), timing
+}
+
+// recordTiming adds a completed request's timings to the requester stats
+func (r *Requester) recordTiming(t *requestTiming, verbose bool) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	r.statsMtx.Lock()
+	r.stats.Requests++
+	r.stats.TotalFirstByte += t.firstByte
+	if t.reused {
+		r.stats.ReusedConns++
+	}
+	if t.wasIdle {
+		r.stats.IdleConns++
+	}
+	if !t.dnsStart.IsZero() {
+		r.stats.DNSLookups++
+		r.stats.TotalDNSTime += t.dns
+	}
+	if !t.connectStart.IsZero() {
+		r.stats.Connects++
+		r.stats.TotalConnectTime += t.connect
+	}
+	if !t.tlsStart.IsZero() {
+		r.stats.TLSHandshakes++
+		r.stats.TotalTLSTime += t.tls
+	}
+	r.statsMtx.Unlock()
+
+	if verbose {
+		log.Printf("%s request timings: reused conn: %v dns: %v connect: %v tls: %v first byte: %v",
+			r.Name, t.reused, t.dns, t.connect, t.tls, t.firstByte)
+	}
+}
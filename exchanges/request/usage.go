@@ -0,0 +1,100 @@
+package request
+
+import (
+	"time"
+)
+
+// UsageStats holds cumulative request counts for a requester which are used
+// to monitor exchange bandwidth and rate limit usage
+type UsageStats struct {
+	Requests      int64 `json:"requests"`
+	AuthRequests  int64 `json:"authRequests"`
+	Errors        int64 `json:"errors"`
+	RateLimited   int64 `json:"rateLimited"`
+	BytesReceived int64 `json:"bytesReceived"`
+	CacheHits     int64 `json:"cacheHits"`
+	NotModified   int64 `json:"notModified"`
+}
+
+// RateLimitUsage holds the usage of a rate limiter for the current cycle
+type RateLimitUsage struct {
+	Rate        int           `json:"rate"`
+	Duration    time.Duration `json:"duration"`
+	Requests    int           `json:"requests"`
+	Utilisation float64       `json:"utilisation"`
+}
+
+// GetUsageStats returns a copy of the recorded request counts
+func (r *Requester) GetUsageStats() UsageStats {
+	r.usageMtx.Lock()
+	defer r.usageMtx.Unlock()
+	return r.usage
+}
+
+// ResetUsageStats clears the recorded request counts
+func (r *Requester) ResetUsageStats() {
+	r.usageMtx.Lock()
+	r.usage = UsageStats{}
+	r.usageMtx.Unlock()
+}
+
+// GetRateLimitUsage returns the number of requests made against the rate
+// limiter in the current cycle and the percentage of the rate used
+func (r *Requester) GetRateLimitUsage(auth bool) RateLimitUsage {
+	limit := r.GetRateLimit(auth)
+	if limit == nil {
+		return RateLimitUsage{}
+	}
+
+	usage := RateLimitUsage{
+		Rate:     limit.GetRate(),
+		Duration: limit.GetDuration(),
+	}
+
+	if time.Since(r.Cycle) < usage.Duration {
+		usage.Requests = limit.GetRequests()
+	}
+
+	if usage.Rate > 0 {
+		usage.Utilisation = float64(usage.Requests) / float64(usage.Rate) * 100
+	}
+	return usage
+}
+
+// recordRequest adds a completed request to the usage stats
+func (r *Requester) recordRequest(auth bool, bytesReceived int, err error) {
+	r.usageMtx.Lock()
+	r.usage.Requests++
+	if auth {
+		r.usage.AuthRequests++
+	}
+	if err != nil {
+		r.usage.Errors++
+	}
+	r.usage.BytesReceived += int64(bytesReceived)
+	r.usageMtx.Unlock()
+}
+
+// recordRateLimited adds a request delayed by the rate limiter to the usage
+// stats
+func (r *Requester) recordRateLimited() {
+	r.usageMtx.Lock()
+	r.usage.RateLimited++
+	r.usageMtx.Unlock()
+}
+
+// recordCacheHit adds a request served from the response cache to the usage
+// stats
+func (r *Requester) recordCacheHit() {
+	r.usageMtx.Lock()
+	r.usage.CacheHits++
+	r.usageMtx.Unlock()
+}
+
+// recordNotModified adds a cached response revalidated by the exchange to the
+// usage stats
+func (r *Requester) recordNotModified() {
+	r.usageMtx.Lock()
+	r.usage.NotModified++
+	r.usageMtx.Unlock()
+}
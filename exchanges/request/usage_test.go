@@ -0,0 +1,50 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUsageStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.Write([]byte("not json"))
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	r := New("test", NewRateLimit(time.Minute, 10), NewRateLimit(time.Minute, 4), new(http.Client))
+
+	var result struct {
+		Status string `json:"status"`
+	}
+	err := r.SendPayload("GET", server.URL, nil, nil, &result, false, false)
+	if err != nil {
+		t.Fatal("Test failed - SendPayload() error", err)
+	}
+
+	err = r.SendPayload("GET", server.URL+"/error", nil, nil, &result, true, false)
+	if err == nil {
+		t.Error("Test failed - SendPayload() invalid JSON returned no error")
+	}
+
+	stats := r.GetUsageStats()
+	if stats.Requests != 2 || stats.AuthRequests != 1 || stats.Errors != 1 ||
+		stats.BytesReceived != 23 {
+		t.Errorf("Test failed - GetUsageStats() unexpected stats %+v", stats)
+	}
+
+	usage := r.GetRateLimitUsage(false)
+	if usage.Requests != 1 || usage.Rate != 4 || usage.Utilisation != 25 {
+		t.Errorf("Test failed - GetRateLimitUsage() unexpected usage %+v", usage)
+	}
+
+	r.ResetUsageStats()
+	if r.GetUsageStats().Requests != 0 {
+		t.Error("Test failed - ResetUsageStats() stats not cleared")
+	}
+}
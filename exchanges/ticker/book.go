@@ -0,0 +1,72 @@
+package ticker
+
+import (
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Ticker price sources
+const (
+	SourceExchange  = "exchange"
+	SourceOrderbook = "orderbook"
+)
+
+// BookTop holds the best bid and ask of a live orderbook and the amounts
+// resting on each side of the levels nearest the touch
+type BookTop struct {
+	Bid      float64
+	Ask      float64
+	BidDepth float64
+	AskDepth float64
+	Updated  time.Time
+}
+
+// Pressure returns the imbalance of the book between -1, when only asks
+// rest near the touch, and 1, when only bids do
+func (b BookTop) Pressure() float64 {
+	total := b.BidDepth + b.AskDepth
+	if total <= 0 {
+		return 0
+	}
+	return (b.BidDepth - b.AskDepth) / total
+}
+
+// ApplyBookTop updates the bid, ask and pressure of a stored ticker from the
+// top of its live orderbook when the exchange last updated the ticker more
+// than maxLag before the book, so strategies which only read tickers still
+// see a fresh top of book. Updated tickers take the book's update time and
+// have their source set to SourceOrderbook until the exchange updates them
+// again. It returns the ticker and whether it was updated
+func ApplyBookTop(exchange string, p pair.CurrencyPair, tickerType string, top BookTop, maxLag time.Duration) (Price, bool) {
+	if top.Bid <= 0 || top.Ask <= 0 || top.Bid > top.Ask {
+		return Price{}, false
+	}
+
+	t, err := GetTickerByExchange(exchange)
+	if err != nil {
+		return Price{}, false
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	price, ok := t.Price[p.FirstCurrency][p.SecondCurrency][tickerType]
+	if !ok || !top.Updated.After(price.LastUpdated) {
+		return price, false
+	}
+
+	if price.Source != SourceOrderbook && top.Updated.Sub(price.LastUpdated) <= maxLag {
+		return price, false
+	}
+
+	price.Bid = top.Bid
+	price.Ask = top.Ask
+	price.Pressure = top.Pressure()
+	price.LastUpdated = top.Updated
+	price.Source = SourceOrderbook
+	price.Stale = false
+	t.Price[p.FirstCurrency][p.SecondCurrency][tickerType] = price
+	recordHistory(exchange, p, tickerType, price)
+	return price, true
+}
@@ -0,0 +1,51 @@
+package ticker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestApplyBookTop(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "AUD")
+	top := BookTop{Bid: 101, Ask: 102, BidDepth: 3, AskDepth: 1, Updated: time.Now().Add(time.Second)}
+
+	if _, ok := ApplyBookTop("booktop", p, Spot, top, time.Second*5); ok {
+		t.Error("Test Failed - ApplyBookTop() updated a ticker which doesn't exist")
+	}
+
+	ProcessTicker("booktop", p, Price{Last: 100, Bid: 99, Ask: 100}, Spot)
+	if _, ok := ApplyBookTop("booktop", p, Spot, top, time.Second*5); ok {
+		t.Error("Test Failed - ApplyBookTop() updated a ticker within the max lag")
+	}
+
+	top.Updated = time.Now().Add(time.Second * 10)
+	price, ok := ApplyBookTop("booktop", p, Spot, top, time.Second*5)
+	if !ok || price.Bid != 101 || price.Ask != 102 || price.Last != 100 ||
+		price.Pressure != 0.5 || price.Source != SourceOrderbook {
+		t.Errorf("Test Failed - ApplyBookTop() unexpected ticker %+v", price)
+	}
+
+	// Tickers already taken from the book follow every newer book
+	top.Bid, top.Updated = 101.5, top.Updated.Add(time.Millisecond)
+	if _, ok = ApplyBookTop("booktop", p, Spot, top, time.Second*5); !ok {
+		t.Error("Test Failed - ApplyBookTop() didn't follow a newer book")
+	}
+
+	stored, err := GetTicker("booktop", p, Spot)
+	if err != nil || stored.Bid != 101.5 || stored.Source != SourceOrderbook {
+		t.Errorf("Test Failed - ApplyBookTop() unexpected stored ticker %+v %v", stored, err)
+	}
+
+	ProcessTicker("booktop", p, Price{Last: 100, Bid: 99, Ask: 100}, Spot)
+	stored, _ = GetTicker("booktop", p, Spot)
+	if stored.Source != SourceExchange {
+		t.Errorf("Test Failed - ProcessTicker() expected source %s, got %s", SourceExchange, stored.Source)
+	}
+
+	top.Bid = 103
+	if _, ok = ApplyBookTop("booktop", p, Spot, top, time.Second*5); ok {
+		t.Error("Test Failed - ApplyBookTop() applied a crossed book")
+	}
+}
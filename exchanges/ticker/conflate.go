@@ -0,0 +1,100 @@
+package ticker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Update holds a ticker update of an exchange pair delivered to subscribers
+type Update struct {
+	Exchange  string
+	AssetType string
+	Price     Price
+}
+
+// Conflator coalesces ticker updates so subscribers receive at most a maximum
+// rate of updates per exchange pair. Updates pushed between deliveries replace
+// the pending update of their pair, so a slow subscriber only ever receives
+// the latest price rather than a backlog of stale ones
+type Conflator struct {
+	interval time.Duration
+	deliver  func(Update)
+
+	mtx       sync.Mutex
+	pending   map[string]Update
+	order     []string
+	conflated uint64
+}
+
+// NewConflator returns a conflator delivering at most maxUpdatesPerSecond
+// updates for each exchange pair to the deliver func
+func NewConflator(maxUpdatesPerSecond float64, deliver func(Update)) *Conflator {
+	return &Conflator{
+		interval: time.Duration(float64(time.Second) / maxUpdatesPerSecond),
+		deliver:  deliver,
+		pending:  make(map[string]Update),
+	}
+}
+
+func updateKey(u *Update) string {
+	return common.StringToLower(u.Exchange) + " " + u.Price.Pair.FirstCurrency.Upper().String() +
+		u.Price.Pair.SecondCurrency.Upper().String() + " " + u.AssetType
+}
+
+// Push stages an update for delivery, replacing any pending update of the
+// same exchange pair
+func (c *Conflator) Push(u Update) {
+	key := updateKey(&u)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.pending[key]; ok {
+		c.conflated++
+	} else {
+		c.order = append(c.order, key)
+	}
+	c.pending[key] = u
+}
+
+// Flush delivers the pending updates in the order their pairs were first
+// pushed since the last flush
+func (c *Conflator) Flush() {
+	c.mtx.Lock()
+	updates := make([]Update, 0, len(c.order))
+	for _, key := range c.order {
+		updates = append(updates, c.pending[key])
+	}
+	c.pending = make(map[string]Update)
+	c.order = nil
+	c.mtx.Unlock()
+
+	for x := range updates {
+		c.deliver(updates[x])
+	}
+}
+
+// Conflated returns the number of updates replaced before delivery
+func (c *Conflator) Conflated() uint64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.conflated
+}
+
+// Run flushes the pending updates at the conflator's rate until the context
+// is cancelled. Deliveries happen on the calling goroutine, so a slow deliver
+// func delays the next flush while later updates keep coalescing
+func (c *Conflator) Run(ctx context.Context) {
+	t := time.NewTicker(c.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.Flush()
+		}
+	}
+}
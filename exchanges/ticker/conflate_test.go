@@ -0,0 +1,84 @@
+package ticker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestConflator(t *testing.T) {
+	var delivered []Update
+	c := NewConflator(10, func(u Update) {
+		delivered = append(delivered, u)
+	})
+
+	btc := pair.NewCurrencyPair("BTC", "USD")
+	ltc := pair.NewCurrencyPair("LTC", "USD")
+	c.Push(Update{Exchange: "Bitstamp", AssetType: Spot, Price: Price{Pair: btc, Last: 100}})
+	c.Push(Update{Exchange: "Bitstamp", AssetType: Spot, Price: Price{Pair: ltc, Last: 10}})
+	c.Push(Update{Exchange: "bitstamp", AssetType: Spot, Price: Price{Pair: btc, Last: 101}})
+	c.Push(Update{Exchange: "Kraken", AssetType: Spot, Price: Price{Pair: btc, Last: 102}})
+	c.Flush()
+
+	if len(delivered) != 3 || delivered[0].Price.Last != 101 ||
+		delivered[1].Price.Last != 10 || delivered[2].Price.Last != 102 {
+		t.Errorf("Test Failed - Flush() unexpected deliveries %+v", delivered)
+	}
+	if c.Conflated() != 1 {
+		t.Errorf("Test Failed - Conflated() expected 1, got %d", c.Conflated())
+	}
+
+	c.Flush()
+	if len(delivered) != 3 {
+		t.Error("Test Failed - Flush() delivered updates twice")
+	}
+}
+
+func TestConflatorRun(t *testing.T) {
+	var mtx sync.Mutex
+	var delivered []Update
+	c := NewConflator(100, func(u Update) {
+		mtx.Lock()
+		delivered = append(delivered, u)
+		mtx.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+
+	btc := pair.NewCurrencyPair("BTC", "USD")
+	for x := 1; x <= 1000; x++ {
+		c.Push(Update{Exchange: "Bitstamp", AssetType: Spot, Price: Price{Pair: btc, Last: float64(x)}})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mtx.Lock()
+		n := len(delivered)
+		var last float64
+		if n > 0 {
+			last = delivered[n-1].Price.Last
+		}
+		mtx.Unlock()
+		if last == 1000 {
+			if n > 10 {
+				t.Errorf("Test Failed - Run() delivered %d updates, expected them conflated", n)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Test Failed - Run() latest update not delivered")
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+
+	cancel()
+	<-done
+}
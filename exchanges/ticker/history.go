@@ -0,0 +1,206 @@
+package ticker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Errors returned by the ticker history
+var (
+	ErrTickerHistoryDisabled = errors.New("ticker history is disabled")
+	ErrTickerHistoryNotFound = errors.New("no ticker history for the exchange pair")
+)
+
+// Vars for the ticker history
+var (
+	historyMaxEntries int
+	historyMaxAge     time.Duration
+	historyEnabled    bool
+	histories         = make(map[string]*history)
+	historyMtx        sync.Mutex
+)
+
+// history is a ring buffer of the updates of a ticker, oldest first. Its
+// capacity is the max entries of the history or, when only a max age is set,
+// grows as needed with older updates dropped as they age out
+type history struct {
+	prices []Price
+	start  int
+	count  int
+}
+
+// push appends a price, overwriting the oldest when the buffer is full
+func (h *history) push(price Price) {
+	if h.count == len(h.prices) {
+		if historyMaxEntries > 0 && h.count >= historyMaxEntries {
+			h.prices[h.start] = price
+			h.start = (h.start + 1) % len(h.prices)
+			return
+		}
+		h.grow()
+	}
+	h.prices[(h.start+h.count)%len(h.prices)] = price
+	h.count++
+}
+
+// grow doubles the capacity of the buffer, up to the max entries
+func (h *history) grow() {
+	size := len(h.prices) * 2
+	if size == 0 {
+		size = 16
+	}
+	if historyMaxEntries > 0 && size > historyMaxEntries {
+		size = historyMaxEntries
+	}
+
+	prices := make([]Price, size)
+	for x := 0; x < h.count; x++ {
+		prices[x] = h.prices[(h.start+x)%len(h.prices)]
+	}
+	h.prices, h.start = prices, 0
+}
+
+// prune drops the updates older than the max age of the history
+func (h *history) prune(now time.Time) {
+	if historyMaxAge <= 0 {
+		return
+	}
+	cutoff := now.Add(-historyMaxAge)
+	for h.count > 0 && h.prices[h.start].LastUpdated.Before(cutoff) {
+		h.prices[h.start] = Price{}
+		h.start = (h.start + 1) % len(h.prices)
+		h.count--
+	}
+}
+
+// get returns the x'th oldest update
+func (h *history) get(x int) Price {
+	return h.prices[(h.start+x)%len(h.prices)]
+}
+
+func historyKey(exchange string, p pair.CurrencyPair, tickerType string) string {
+	return common.StringToLower(exchange) + " " + p.FirstCurrency.Upper().String() +
+		p.SecondCurrency.Upper().String() + " " + tickerType
+}
+
+// EnableHistory keeps the last updates of every ticker, up to maxEntries
+// updates and maxAge old, either being unbounded when zero. Existing
+// histories are cleared
+func EnableHistory(maxEntries int, maxAge time.Duration) {
+	historyMtx.Lock()
+	historyMaxEntries, historyMaxAge = maxEntries, maxAge
+	historyEnabled = maxEntries > 0 || maxAge > 0
+	histories = make(map[string]*history)
+	historyMtx.Unlock()
+}
+
+// DisableHistory stops keeping ticker updates and clears the histories
+func DisableHistory() {
+	EnableHistory(0, 0)
+}
+
+// recordHistory appends a ticker update to the history of its exchange pair
+func recordHistory(exchange string, p pair.CurrencyPair, tickerType string, price Price) {
+	historyMtx.Lock()
+	defer historyMtx.Unlock()
+	if !historyEnabled {
+		return
+	}
+
+	key := historyKey(exchange, p, tickerType)
+	h, ok := histories[key]
+	if !ok {
+		h = &history{}
+		histories[key] = h
+	}
+	h.push(price)
+	h.prune(price.LastUpdated)
+}
+
+// GetTickerHistory returns the updates of a ticker between start and end,
+// oldest first. A zero start or end leaves that side of the range open
+func GetTickerHistory(exchange string, p pair.CurrencyPair, tickerType string, start, end time.Time) ([]Price, error) {
+	historyMtx.Lock()
+	defer historyMtx.Unlock()
+	if !historyEnabled {
+		return nil, ErrTickerHistoryDisabled
+	}
+
+	h, ok := histories[historyKey(exchange, p, tickerType)]
+	if !ok {
+		return nil, ErrTickerHistoryNotFound
+	}
+	h.prune(time.Now())
+
+	var result []Price
+	for x := 0; x < h.count; x++ {
+		price := h.get(x)
+		if !start.IsZero() && price.LastUpdated.Before(start) {
+			continue
+		}
+		if !end.IsZero() && price.LastUpdated.After(end) {
+			break
+		}
+		result = append(result, price)
+	}
+	return result, nil
+}
+
+// SampleTickerHistory returns the updates of a ticker between start and end
+// sampled every interval, each sample being the last update of its interval.
+// Intervals without an update are skipped
+func SampleTickerHistory(exchange string, p pair.CurrencyPair, tickerType string, start, end time.Time, interval time.Duration) ([]Price, error) {
+	prices, err := GetTickerHistory(exchange, p, tickerType, start, end)
+	if err != nil || interval <= 0 || len(prices) == 0 {
+		return prices, err
+	}
+
+	if start.IsZero() {
+		start = prices[0].LastUpdated
+	}
+
+	var result []Price
+	bucket := int64(-1)
+	for x := range prices {
+		b := int64(prices[x].LastUpdated.Sub(start) / interval)
+		if b == bucket {
+			result[len(result)-1] = prices[x]
+			continue
+		}
+		bucket = b
+		result = append(result, prices[x])
+	}
+	return result, nil
+}
+
+// GetTickerChange returns the percentage change of the last price of a
+// ticker over the window, from the last update at or before its start, or
+// the oldest update within it, to the latest update
+func GetTickerChange(exchange string, p pair.CurrencyPair, tickerType string, window time.Duration) (float64, error) {
+	prices, err := GetTickerHistory(exchange, p, tickerType, time.Time{}, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	if len(prices) == 0 {
+		return 0, ErrTickerHistoryNotFound
+	}
+
+	latest := prices[len(prices)-1]
+	cutoff := latest.LastUpdated.Add(-window)
+	from := prices[0]
+	for x := range prices {
+		if prices[x].LastUpdated.After(cutoff) {
+			break
+		}
+		from = prices[x]
+	}
+
+	if from.Last == 0 {
+		return 0, errors.New("ticker history has no last price to compare")
+	}
+	return (latest.Last - from.Last) / from.Last * 100, nil
+}
@@ -0,0 +1,102 @@
+package ticker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestTickerHistory(t *testing.T) {
+	defer DisableHistory()
+	p := pair.NewCurrencyPair("BTC", "USD")
+
+	ProcessTicker("history", p, Price{Last: 100}, Spot)
+	if _, err := GetTickerHistory("history", p, Spot, time.Time{}, time.Time{}); err != ErrTickerHistoryDisabled {
+		t.Errorf("Test Failed - GetTickerHistory() expected %v, got %v", ErrTickerHistoryDisabled, err)
+	}
+
+	EnableHistory(20, 0)
+	if _, err := GetTickerHistory("history", p, Spot, time.Time{}, time.Time{}); err != ErrTickerHistoryNotFound {
+		t.Errorf("Test Failed - GetTickerHistory() expected %v, got %v", ErrTickerHistoryNotFound, err)
+	}
+
+	for x := 1; x <= 25; x++ {
+		ProcessTicker("history", p, Price{Last: float64(x)}, Spot)
+	}
+
+	prices, err := GetTickerHistory("HISTORY", p, Spot, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Test Failed - GetTickerHistory() error: %s", err)
+	}
+	if len(prices) != 20 || prices[0].Last != 6 || prices[19].Last != 25 {
+		t.Errorf("Test Failed - GetTickerHistory() expected the last 20 updates, got %d from %f",
+			len(prices), prices[0].Last)
+	}
+
+	prices, err = GetTickerHistory("history", p, Spot, prices[10].LastUpdated, time.Time{})
+	if err != nil || len(prices) < 10 || prices[len(prices)-1].Last != 25 {
+		t.Errorf("Test Failed - GetTickerHistory() unexpected range %d %v", len(prices), err)
+	}
+}
+
+func TestTickerHistoryMaxAge(t *testing.T) {
+	defer DisableHistory()
+	EnableHistory(0, time.Minute)
+
+	now := time.Now()
+	for x := 0; x < 40; x++ {
+		recordHistory("history", pair.NewCurrencyPair("ETH", "USD"), Spot,
+			Price{Last: float64(x), LastUpdated: now.Add(time.Duration(x-39) * time.Second * 3)})
+	}
+
+	prices, err := GetTickerHistory("history", pair.NewCurrencyPair("ETH", "USD"), Spot, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Test Failed - GetTickerHistory() error: %s", err)
+	}
+	if len(prices) != 20 || prices[0].Last != 20 {
+		t.Errorf("Test Failed - GetTickerHistory() expected the updates within a minute, got %d", len(prices))
+	}
+}
+
+func TestSampleTickerHistory(t *testing.T) {
+	defer DisableHistory()
+	EnableHistory(100, 0)
+
+	p := pair.NewCurrencyPair("LTC", "USD")
+	start := time.Now().Add(-time.Minute)
+	for x := 0; x < 12; x++ {
+		recordHistory("history", p, Spot,
+			Price{Last: float64(x), LastUpdated: start.Add(time.Duration(x) * time.Second * 5)})
+	}
+
+	prices, err := SampleTickerHistory("history", p, Spot, start, time.Time{}, time.Second*20)
+	if err != nil {
+		t.Fatalf("Test Failed - SampleTickerHistory() error: %s", err)
+	}
+	if len(prices) != 3 || prices[0].Last != 3 || prices[1].Last != 7 || prices[2].Last != 11 {
+		t.Errorf("Test Failed - SampleTickerHistory() unexpected samples %v", prices)
+	}
+}
+
+func TestGetTickerChange(t *testing.T) {
+	defer DisableHistory()
+	EnableHistory(100, 0)
+
+	p := pair.NewCurrencyPair("XRP", "USD")
+	now := time.Now()
+	for x, last := range []float64{100, 110, 120, 132} {
+		recordHistory("history", p, Spot,
+			Price{Last: last, LastUpdated: now.Add(time.Duration(x-3) * time.Minute)})
+	}
+
+	change, err := GetTickerChange("history", p, Spot, time.Minute*2)
+	if err != nil || change != 20 {
+		t.Errorf("Test Failed - GetTickerChange() expected 20, got %f %v", change, err)
+	}
+
+	change, err = GetTickerChange("history", p, Spot, time.Hour)
+	if err != nil || change != 32 {
+		t.Errorf("Test Failed - GetTickerChange() expected 32, got %f %v", change, err)
+	}
+}
@@ -0,0 +1,101 @@
+package ticker
+
+import (
+	"sort"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// ExchangeMarket holds the last price and volume of a currency pair on an
+// exchange with its share of the total volume across exchanges
+type ExchangeMarket struct {
+	Exchange    string    `json:"exchange"`
+	Last        float64   `json:"last"`
+	Volume      float64   `json:"volume"`
+	QuoteVolume float64   `json:"quoteVolume"`
+	MarketShare float64   `json:"marketShare"`
+	LastUpdated time.Time `json:"lastUpdated"`
+	Stale       bool      `json:"stale,omitempty"`
+}
+
+// MarketOverview aggregates the volume of a currency pair across exchanges.
+// Volumes are in the first currency and quote volumes in the second
+type MarketOverview struct {
+	Pair                string           `json:"pair"`
+	Exchanges           []ExchangeMarket `json:"exchanges"`
+	TotalVolume         float64          `json:"totalVolume"`
+	TotalQuoteVolume    float64          `json:"totalQuoteVolume"`
+	VolumeWeightedPrice float64          `json:"volumeWeightedPrice"`
+}
+
+// GetMarketOverview returns the volume and last price of each currency pair
+// per exchange from the stored tickers of the ticker type, with aggregate
+// totals and exchange market share, ordered by total quote volume. Include
+// filters the exchange pairs reported, a nil include reports all of them.
+// Synthetic tickers are skipped
+func GetMarketOverview(tickerType string, include func(exchange string, p pair.CurrencyPair) bool) []MarketOverview {
+	overviews := make(map[string]*MarketOverview)
+
+	m.Lock()
+	for x := range Tickers {
+		for first, seconds := range Tickers[x].Price {
+			for second, prices := range seconds {
+				price, ok := prices[tickerType]
+				if !ok || price.Synthetic {
+					continue
+				}
+
+				p := pair.NewCurrencyPair(first.String(), second.String())
+				if include != nil && !include(Tickers[x].ExchangeName, p) {
+					continue
+				}
+
+				key := p.Pair().Upper().String()
+				overview, ok := overviews[key]
+				if !ok {
+					overview = &MarketOverview{Pair: key}
+					overviews[key] = overview
+				}
+
+				overview.Exchanges = append(overview.Exchanges, ExchangeMarket{
+					Exchange:    Tickers[x].ExchangeName,
+					Last:        price.Last,
+					Volume:      price.Volume,
+					QuoteVolume: price.Volume * price.Last,
+					LastUpdated: price.LastUpdated,
+					Stale:       price.Stale,
+				})
+				overview.TotalVolume += price.Volume
+				overview.TotalQuoteVolume += price.Volume * price.Last
+			}
+		}
+	}
+	m.Unlock()
+
+	var result []MarketOverview
+	for _, overview := range overviews {
+		for x := range overview.Exchanges {
+			if overview.TotalVolume > 0 {
+				overview.Exchanges[x].MarketShare = overview.Exchanges[x].Volume / overview.TotalVolume * 100
+			}
+		}
+
+		if overview.TotalVolume > 0 {
+			overview.VolumeWeightedPrice = overview.TotalQuoteVolume / overview.TotalVolume
+		}
+
+		sort.Slice(overview.Exchanges, func(i, j int) bool {
+			return overview.Exchanges[i].Volume > overview.Exchanges[j].Volume
+		})
+		result = append(result, *overview)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalQuoteVolume == result[j].TotalQuoteVolume {
+			return result[i].Pair < result[j].Pair
+		}
+		return result[i].TotalQuoteVolume > result[j].TotalQuoteVolume
+	})
+	return result
+}
@@ -0,0 +1,44 @@
+package ticker
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestGetMarketOverview(t *testing.T) {
+	stored := Tickers
+	defer func() { Tickers = stored }()
+	Tickers = nil
+
+	p := pair.NewCurrencyPair("BTC", "USD")
+	ProcessTicker("Bitstamp", p, Price{Last: 100, Volume: 1}, Spot)
+	ProcessTicker("Kraken", p, Price{Last: 110, Volume: 3}, Spot)
+	ProcessTicker("Kraken", pair.NewCurrencyPair("LTC", "USD"), Price{Last: 10, Volume: 5}, Spot)
+	ProcessTicker("Kraken", pair.NewCurrencyPair("ETH", "USD"), Price{Last: 10, Volume: 5, Synthetic: true}, Spot)
+	ProcessTicker("Disabled", p, Price{Last: 1000, Volume: 100}, Spot)
+
+	overview := GetMarketOverview(Spot, func(exchange string, p pair.CurrencyPair) bool {
+		return exchange != "Disabled"
+	})
+	if len(overview) != 2 {
+		t.Fatalf("Test Failed - GetMarketOverview() expected 2 pairs, got %d", len(overview))
+	}
+
+	btc := overview[0]
+	if btc.Pair != "BTCUSD" || btc.TotalVolume != 4 || btc.TotalQuoteVolume != 430 {
+		t.Errorf("Test Failed - GetMarketOverview() unexpected totals %+v", btc)
+	}
+
+	if btc.VolumeWeightedPrice != 107.5 {
+		t.Error("Test Failed - GetMarketOverview() unexpected volume weighted price", btc.VolumeWeightedPrice)
+	}
+
+	if len(btc.Exchanges) != 2 || btc.Exchanges[0].Exchange != "Kraken" || btc.Exchanges[0].MarketShare != 75 {
+		t.Errorf("Test Failed - GetMarketOverview() unexpected exchange share %+v", btc.Exchanges)
+	}
+
+	if overview[1].Pair != "LTCUSD" || overview[1].Exchanges[0].MarketShare != 100 {
+		t.Errorf("Test Failed - GetMarketOverview() unexpected pair %+v", overview[1])
+	}
+}
@@ -0,0 +1,119 @@
+package ticker
+
+import (
+	"os"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// storedPrice holds a ticker price persisted to disk
+type storedPrice struct {
+	Exchange       string            `json:"exchange"`
+	FirstCurrency  pair.CurrencyItem `json:"firstCurrency"`
+	SecondCurrency pair.CurrencyItem `json:"secondCurrency"`
+	TickerType     string            `json:"tickerType"`
+	Price          Price             `json:"price"`
+}
+
+// SaveTickers persists the last known ticker prices to the file at path so
+// they can be restored on the next start. Synthetic prices are not saved
+func SaveTickers(path string) error {
+	m.Lock()
+	var stored []storedPrice
+	for x := range Tickers {
+		for first, seconds := range Tickers[x].Price {
+			for second, tickerTypes := range seconds {
+				for tickerType, price := range tickerTypes {
+					if price.Synthetic {
+						continue
+					}
+					stored = append(stored, storedPrice{
+						Exchange:       Tickers[x].ExchangeName,
+						FirstCurrency:  first,
+						SecondCurrency: second,
+						TickerType:     tickerType,
+						Price:          price,
+					})
+				}
+			}
+		}
+	}
+	m.Unlock()
+
+	data, err := common.JSONEncode(stored)
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(path, data)
+}
+
+// LoadTickers restores the ticker prices persisted to the file at path,
+// marking them stale until the exchange refreshes them. Prices last updated
+// before maxAge ago are discarded and a zero maxAge keeps every price. Prices
+// already in the store are never replaced
+func LoadTickers(path string, maxAge time.Duration) (int, error) {
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var stored []storedPrice
+	err = common.JSONDecode(data, &stored)
+	if err != nil {
+		return 0, err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	var restored int
+	for x := range stored {
+		if maxAge > 0 && time.Since(stored[x].Price.LastUpdated) > maxAge {
+			continue
+		}
+		stored[x].Price.Stale = true
+		if restorePrice(stored[x]) {
+			restored++
+		}
+	}
+	return restored, nil
+}
+
+// restorePrice adds a persisted price to the store unless a price already
+// exists for the exchange, pair and ticker type, m must be held by the caller
+func restorePrice(s storedPrice) bool {
+	var t *Ticker
+	for x := range Tickers {
+		if Tickers[x].ExchangeName == s.Exchange {
+			t = &Tickers[x]
+			break
+		}
+	}
+
+	if t == nil {
+		Tickers = append(Tickers, Ticker{
+			ExchangeName: s.Exchange,
+			Price:        make(map[pair.CurrencyItem]map[pair.CurrencyItem]map[string]Price),
+		})
+		t = &Tickers[len(Tickers)-1]
+	}
+
+	if _, ok := t.Price[s.FirstCurrency]; !ok {
+		t.Price[s.FirstCurrency] = make(map[pair.CurrencyItem]map[string]Price)
+	}
+
+	if _, ok := t.Price[s.FirstCurrency][s.SecondCurrency]; !ok {
+		t.Price[s.FirstCurrency][s.SecondCurrency] = make(map[string]Price)
+	}
+
+	if _, ok := t.Price[s.FirstCurrency][s.SecondCurrency][s.TickerType]; ok {
+		return false
+	}
+	t.Price[s.FirstCurrency][s.SecondCurrency][s.TickerType] = s.Price
+	return true
+}
@@ -0,0 +1,60 @@
+package ticker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestSaveLoadTickers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tickers")
+	if err != nil {
+		t.Fatal("Test Failed - TempDir() error", err)
+	}
+	defer os.RemoveAll(dir)
+
+	stored := Tickers
+	defer func() { Tickers = stored }()
+	Tickers = nil
+	path := filepath.Join(dir, "tickers.json")
+
+	restored, err := LoadTickers(path, 0)
+	if err != nil || restored != 0 {
+		t.Error("Test Failed - LoadTickers() missing file error", err)
+	}
+
+	p := pair.NewCurrencyPair("XMR", "EUR")
+	ProcessTicker("WarmCache", p, Price{Last: 100}, Spot)
+	ProcessTicker("WarmCache", pair.NewCurrencyPair("ZEC", "EUR"), Price{Last: 50}, Spot)
+
+	err = SaveTickers(path)
+	if err != nil {
+		t.Fatal("Test Failed - SaveTickers() error", err)
+	}
+
+	Tickers = nil
+	ProcessTicker("WarmCache", p, Price{Last: 101}, Spot)
+
+	restored, err = LoadTickers(path, time.Hour)
+	if err != nil {
+		t.Fatal("Test Failed - LoadTickers() error", err)
+	}
+
+	if restored != 1 {
+		t.Errorf("Test Failed - LoadTickers() expected 1 restored ticker received %d", restored)
+	}
+
+	price, err := GetTicker("WarmCache", p, Spot)
+	if err != nil || price.Last != 101 || price.Stale {
+		t.Error("Test Failed - LoadTickers() replaced a fresh ticker", err)
+	}
+
+	price, err = GetTicker("WarmCache", pair.NewCurrencyPair("ZEC", "EUR"), Spot)
+	if err != nil || price.Last != 50 || !price.Stale {
+		t.Error("Test Failed - LoadTickers() ticker not restored as stale", err)
+	}
+}
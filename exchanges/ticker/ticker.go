@@ -15,6 +15,7 @@ const (
 	ErrTickerForExchangeNotFound = "Ticker for exchange does not exist."
 	ErrPrimaryCurrencyNotFound   = "Error primary currency for ticker not found."
 	ErrSecondaryCurrencyNotFound = "Error secondary currency for ticker not found."
+	ErrNoCrossRateFound          = "Error no cross rate found to synthesise ticker."
 
 	Spot = "SPOT"
 )
@@ -23,9 +24,16 @@ const (
 var (
 	Tickers []Ticker
 	m       sync.Mutex
+
+	cacheStats    CacheStats
+	cacheStatsMtx sync.Mutex
 )
 
-// Price struct stores the currency pair and pricing information
+// Price struct stores the currency pair and pricing information. Stale is set
+// on prices restored from disk until the exchange refreshes them. Source is
+// SourceOrderbook when the bid and ask were taken from the live orderbook
+// because the exchange's ticker lagged it, with Pressure holding the
+// imbalance of the book near the touch
 type Price struct {
 	Pair         pair.CurrencyPair `json:"Pair"`
 	LastUpdated  time.Time         `json:"LastUpdated"`
@@ -37,6 +45,10 @@ type Price struct {
 	Ask          float64           `json:"Ask"`
 	Volume       float64           `json:"Volume"`
 	PriceATH     float64           `json:"PriceATH"`
+	Synthetic    bool              `json:"Synthetic"`
+	Stale        bool              `json:"Stale"`
+	Source       string            `json:"Source"`
+	Pressure     float64           `json:"Pressure"`
 }
 
 // Ticker struct holds the ticker information for a currency pair and type
@@ -69,24 +81,157 @@ func (t *Ticker) PriceToString(p pair.CurrencyPair, priceType, tickerType string
 	}
 }
 
+// CacheStats holds the number of ticker lookups served from the store and
+// the number which were not found
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// HitRate returns the percentage of lookups served from the ticker store
+func (c CacheStats) HitRate() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total) * 100
+}
+
+// GetCacheStats returns the ticker store lookup counts
+func GetCacheStats() CacheStats {
+	cacheStatsMtx.Lock()
+	defer cacheStatsMtx.Unlock()
+	return cacheStats
+}
+
+func recordCacheLookup(hit bool) {
+	cacheStatsMtx.Lock()
+	if hit {
+		cacheStats.Hits++
+	} else {
+		cacheStats.Misses++
+	}
+	cacheStatsMtx.Unlock()
+}
+
 // GetTicker checks and returns a requested ticker if it exists
 func GetTicker(exchange string, p pair.CurrencyPair, tickerType string) (Price, error) {
 	ticker, err := GetTickerByExchange(exchange)
 	if err != nil {
+		recordCacheLookup(false)
 		return Price{}, err
 	}
 
 	if !FirstCurrencyExists(exchange, p.FirstCurrency) {
+		recordCacheLookup(false)
 		return Price{}, errors.New(ErrPrimaryCurrencyNotFound)
 	}
 
 	if !SecondCurrencyExists(exchange, p) {
+		recordCacheLookup(false)
 		return Price{}, errors.New(ErrSecondaryCurrencyNotFound)
 	}
 
+	recordCacheLookup(true)
 	return ticker.Price[p.FirstCurrency][p.SecondCurrency][tickerType], nil
 }
 
+// GetTickerOrSynthetic returns a requested ticker if it exists, otherwise it
+// attempts to synthesise one from the exchange's cross rates
+func GetTickerOrSynthetic(exchange string, p pair.CurrencyPair, tickerType string) (Price, error) {
+	t, err := GetTicker(exchange, p, tickerType)
+	if err == nil {
+		return t, nil
+	}
+	return GetSyntheticTicker(exchange, p, tickerType)
+}
+
+// crossRatePreference sets the preferred intermediate currencies when more
+// than one cross rate is available
+var crossRatePreference = []pair.CurrencyItem{"BTC", "USDT", "ETH", "USD", "EUR"}
+
+// GetSyntheticTicker computes a ticker for a currency pair which is not
+// listed on an exchange by routing it through an intermediate currency which
+// is, e.g. ETH/USD derived from ETH/BTC and BTC/USD. The returned price is
+// flagged as synthetic and carries no high, low or volume data
+func GetSyntheticTicker(exchange string, p pair.CurrencyPair, tickerType string) (Price, error) {
+	ticker, err := GetTickerByExchange(exchange)
+	if err != nil {
+		return Price{}, err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	var candidates []pair.CurrencyItem
+	candidates = append(candidates, crossRatePreference...)
+	for first, seconds := range ticker.Price {
+		candidates = append(candidates, first)
+		for second := range seconds {
+			candidates = append(candidates, second)
+		}
+	}
+
+	for _, x := range candidates {
+		if x == p.FirstCurrency || x == p.SecondCurrency {
+			continue
+		}
+
+		firstLeg, ok := crossRateLeg(ticker, p.FirstCurrency, x, tickerType)
+		if !ok {
+			continue
+		}
+
+		secondLeg, ok := crossRateLeg(ticker, x, p.SecondCurrency, tickerType)
+		if !ok {
+			continue
+		}
+
+		lastUpdated := firstLeg.LastUpdated
+		if secondLeg.LastUpdated.Before(lastUpdated) {
+			lastUpdated = secondLeg.LastUpdated
+		}
+
+		return Price{
+			Pair:         p,
+			CurrencyPair: p.Pair().String(),
+			LastUpdated:  lastUpdated,
+			Last:         firstLeg.Last * secondLeg.Last,
+			Bid:          firstLeg.Bid * secondLeg.Bid,
+			Ask:          firstLeg.Ask * secondLeg.Ask,
+			Synthetic:    true,
+		}, nil
+	}
+	return Price{}, errors.New(ErrNoCrossRateFound)
+}
+
+// crossRateLeg returns the price of the base currency quoted in the quote
+// currency, inverting a stored quote/base ticker if that is all that exists.
+// Synthetic tickers are never used as a leg
+func crossRateLeg(t *Ticker, base, quote pair.CurrencyItem, tickerType string) (Price, bool) {
+	if price, ok := t.Price[base][quote][tickerType]; ok && !price.Synthetic && price.Last > 0 {
+		return price, true
+	}
+
+	price, ok := t.Price[quote][base][tickerType]
+	if !ok || price.Synthetic || price.Last <= 0 {
+		return Price{}, false
+	}
+
+	inverted := Price{
+		Last:        1 / price.Last,
+		LastUpdated: price.LastUpdated,
+	}
+	// Inverting swaps the sides of the book
+	if price.Ask > 0 {
+		inverted.Bid = 1 / price.Ask
+	}
+	if price.Bid > 0 {
+		inverted.Ask = 1 / price.Bid
+	}
+	return inverted, true
+}
+
 // GetTickerByExchange returns an exchange Ticker
 func GetTickerByExchange(exchange string) (*Ticker, error) {
 	m.Lock()
@@ -157,6 +302,10 @@ func ProcessTicker(exchangeName string, p pair.CurrencyPair, tickerNew Price, ti
 
 	tickerNew.CurrencyPair = p.Pair().String()
 	tickerNew.LastUpdated = time.Now()
+	if tickerNew.Source == "" {
+		tickerNew.Source = SourceExchange
+	}
+	recordHistory(exchangeName, p, tickerType, tickerNew)
 
 	ticker, err := GetTickerByExchange(exchangeName)
 	if err != nil {
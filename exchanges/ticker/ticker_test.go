@@ -333,3 +333,76 @@ func TestProcessTicker(t *testing.T) { //non-appending function to tickers
 	wg.Wait()
 
 }
+
+func TestGetSyntheticTicker(t *testing.T) {
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	ethbtc := pair.NewCurrencyPair("ETH", "BTC")
+	ethusd := pair.NewCurrencyPair("ETH", "USD")
+	usdeth := pair.NewCurrencyPair("USD", "ETH")
+
+	ProcessTicker("SyntheticTest", btcusd, Price{Last: 10000, Bid: 9990, Ask: 10010}, Spot)
+	ProcessTicker("SyntheticTest", ethbtc, Price{Last: 0.05, Bid: 0.049, Ask: 0.051}, Spot)
+
+	_, err := GetTicker("SyntheticTest", ethusd, Spot)
+	if err == nil {
+		t.Fatal("Test Failed - GetTicker returned an unlisted pair")
+	}
+
+	synthetic, err := GetTickerOrSynthetic("SyntheticTest", ethusd, Spot)
+	if err != nil {
+		t.Fatal("Test Failed - GetTickerOrSynthetic error", err)
+	}
+
+	if !synthetic.Synthetic {
+		t.Error("Test Failed - synthetic ticker not flagged")
+	}
+
+	if synthetic.Last != 500 {
+		t.Errorf("Test Failed - synthetic last expected 500 received %f",
+			synthetic.Last)
+	}
+
+	if synthetic.Bid != 0.049*9990 || synthetic.Ask != 0.051*10010 {
+		t.Error("Test Failed - synthetic bid/ask values incorrect")
+	}
+
+	inverted, err := GetSyntheticTicker("SyntheticTest", usdeth, Spot)
+	if err != nil {
+		t.Fatal("Test Failed - GetSyntheticTicker error", err)
+	}
+
+	if inverted.Last != (1/10000.0)*(1/0.05) {
+		t.Errorf("Test Failed - inverted last expected %f received %f",
+			(1/10000.0)*(1/0.05), inverted.Last)
+	}
+
+	_, err = GetSyntheticTicker("SyntheticTest", pair.NewCurrencyPair("LTC", "USD"), Spot)
+	if err == nil {
+		t.Error("Test Failed - GetSyntheticTicker returned a pair with no cross rate")
+	}
+}
+
+func TestGetCacheStats(t *testing.T) {
+	before := GetCacheStats()
+
+	newPair := pair.NewCurrencyPair("BTC", "AUD")
+	ProcessTicker("cachestats", newPair, Price{Pair: newPair, CurrencyPair: newPair.Pair().String(), Last: 1200}, Spot)
+	_, err := GetTicker("cachestats", newPair, Spot)
+	if err != nil {
+		t.Fatal("Test Failed - GetTicker() error", err)
+	}
+
+	_, err = GetTicker("cachestats", pair.NewCurrencyPair("LTC", "AUD"), Spot)
+	if err == nil {
+		t.Fatal("Test Failed - GetTicker() returned nil error on missing pair")
+	}
+
+	after := GetCacheStats()
+	if after.Hits < before.Hits+1 || after.Misses < before.Misses+1 {
+		t.Errorf("Test Failed - GetCacheStats() lookups not recorded %+v", after)
+	}
+
+	if (CacheStats{Hits: 3, Misses: 1}).HitRate() != 75 || (CacheStats{}).HitRate() != 0 {
+		t.Error("Test Failed - CacheStats HitRate() incorrect")
+	}
+}
@@ -0,0 +1,217 @@
+package trades
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+const (
+	// DefaultWindow is the default period of trades kept per exchange, pair
+	// and asset type, measured back from the newest stored trade
+	DefaultWindow = time.Hour
+	// MaxTrades is the maximum number of trades kept per exchange, pair and
+	// asset type, the oldest trades are dropped first
+	MaxTrades = 10000
+)
+
+// Vars for the trades store
+var (
+	stores    = make(map[string]*store)
+	window    = DefaultWindow
+	storesMtx sync.Mutex
+
+	// ErrInvalidTrade is returned when a trade is missing required details
+	ErrInvalidTrade = errors.New("trade requires a pair and timestamp")
+)
+
+// Trade holds a trade of an exchange. ID is the trade ID assigned by the
+// exchange and is empty when the exchange doesn't provide one
+type Trade struct {
+	ID        string            `json:"id,omitempty"`
+	Pair      pair.CurrencyPair `json:"pair"`
+	AssetType string            `json:"assetType"`
+	Price     float64           `json:"price"`
+	Amount    float64           `json:"amount"`
+	Side      string            `json:"side"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// store holds the trades of an exchange pair and asset type in timestamp
+// order, and the IDs of the stored trades to detect duplicates
+type store struct {
+	trades []Trade
+	ids    map[string]bool
+}
+
+func storeKey(exchange string, p pair.CurrencyPair, assetType string) string {
+	return common.StringToLower(exchange) + " " + p.FirstCurrency.Upper().String() +
+		p.SecondCurrency.Upper().String() + " " + assetType
+}
+
+// SetWindow sets the period of trades kept, trades older than the window
+// before the newest stored trade are dropped and rejected as they can no
+// longer be checked for duplicates. A zero window restores DefaultWindow
+func SetWindow(d time.Duration) {
+	storesMtx.Lock()
+	defer storesMtx.Unlock()
+	if d <= 0 {
+		d = DefaultWindow
+	}
+	window = d
+}
+
+// ProcessTrade stores a trade received from an exchange, returning whether it
+// was stored. Trades with an ID already stored and trades older than the
+// window are duplicates or stale and are not stored. Trades without an ID are
+// always stored as identical trades are common on busy price levels
+func ProcessTrade(exchange string, t Trade) (bool, error) {
+	if t.Pair.Empty() || t.Timestamp.IsZero() {
+		return false, ErrInvalidTrade
+	}
+
+	storesMtx.Lock()
+	defer storesMtx.Unlock()
+	return getStore(exchange, t.Pair, t.AssetType).add(t), nil
+}
+
+// MergeTrades reconciles trades backfilled over REST with the trades stored
+// from the live stream, returning the number of trades added. A backfilled
+// trade matching a stored trade without an ID by time, price and amount sets
+// the ID of the stored trade instead of being added, as streams without trade
+// IDs deliver the same trades
+func MergeTrades(exchange string, p pair.CurrencyPair, assetType string, backfill []Trade) (int, error) {
+	for x := range backfill {
+		if backfill[x].Timestamp.IsZero() {
+			return 0, ErrInvalidTrade
+		}
+	}
+
+	storesMtx.Lock()
+	defer storesMtx.Unlock()
+
+	s := getStore(exchange, p, assetType)
+	var added int
+	for x := range backfill {
+		t := backfill[x]
+		t.Pair = p
+		t.AssetType = assetType
+		if t.ID != "" && !s.ids[t.ID] {
+			if i := s.find(t); i >= 0 {
+				s.trades[i].ID = t.ID
+				s.ids[t.ID] = true
+				continue
+			}
+		}
+
+		if s.add(t) {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// GetTrades returns the stored trades of an exchange pair and asset type
+// within the time range in timestamp order. A zero start or end leaves that
+// side of the range open
+func GetTrades(exchange string, p pair.CurrencyPair, assetType string, start, end time.Time) []Trade {
+	storesMtx.Lock()
+	defer storesMtx.Unlock()
+
+	s, ok := stores[storeKey(exchange, p, assetType)]
+	if !ok {
+		return nil
+	}
+
+	var result []Trade
+	for _, t := range s.trades {
+		if !start.IsZero() && t.Timestamp.Before(start) {
+			continue
+		}
+		if !end.IsZero() && t.Timestamp.After(end) {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// getStore returns the store of an exchange pair and asset type, creating it
+// when it doesn't exist
+func getStore(exchange string, p pair.CurrencyPair, assetType string) *store {
+	key := storeKey(exchange, p, assetType)
+	s, ok := stores[key]
+	if !ok {
+		s = &store{ids: make(map[string]bool)}
+		stores[key] = s
+	}
+	return s
+}
+
+// add inserts a trade in timestamp order unless it is a duplicate or older
+// than the window, then drops the trades which fell out of the window
+func (s *store) add(t Trade) bool {
+	if t.ID != "" && s.ids[t.ID] {
+		return false
+	}
+
+	if len(s.trades) > 0 &&
+		t.Timestamp.Before(s.trades[len(s.trades)-1].Timestamp.Add(-window)) {
+		return false
+	}
+
+	i := sort.Search(len(s.trades), func(i int) bool {
+		return s.trades[i].Timestamp.After(t.Timestamp)
+	})
+	s.trades = append(s.trades, Trade{})
+	copy(s.trades[i+1:], s.trades[i:])
+	s.trades[i] = t
+	if t.ID != "" {
+		s.ids[t.ID] = true
+	}
+
+	s.prune()
+	return true
+}
+
+// find returns the index of a stored trade without an ID with the time, to the
+// second, price and amount of a trade, or -1
+func (s *store) find(t Trade) int {
+	second := t.Timestamp.Unix()
+	for x := len(s.trades) - 1; x >= 0; x-- {
+		stored := s.trades[x]
+		if stored.Timestamp.Unix() < second {
+			break
+		}
+		if stored.Timestamp.Unix() != second || stored.ID != "" {
+			continue
+		}
+		if stored.Price == t.Price && stored.Amount == t.Amount {
+			return x
+		}
+	}
+	return -1
+}
+
+// prune drops the trades older than the window before the newest trade and
+// those over MaxTrades
+func (s *store) prune() {
+	cutoff := s.trades[len(s.trades)-1].Timestamp.Add(-window)
+	drop := sort.Search(len(s.trades), func(i int) bool {
+		return !s.trades[i].Timestamp.Before(cutoff)
+	})
+	if len(s.trades)-drop > MaxTrades {
+		drop = len(s.trades) - MaxTrades
+	}
+
+	for x := 0; x < drop; x++ {
+		if s.trades[x].ID != "" {
+			delete(s.ids, s.trades[x].ID)
+		}
+	}
+	s.trades = s.trades[drop:]
+}
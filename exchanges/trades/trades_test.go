@@ -0,0 +1,94 @@
+package trades
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestProcessTrade(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	now := time.Now()
+
+	_, err := ProcessTrade("Binance", Trade{Timestamp: now})
+	if err != ErrInvalidTrade {
+		t.Error("Test failed - ProcessTrade() expected invalid trade error", err)
+	}
+
+	stored, err := ProcessTrade("Binance", Trade{ID: "1", Pair: p, AssetType: "SPOT",
+		Price: 100, Amount: 1, Timestamp: now})
+	if err != nil || !stored {
+		t.Fatal("Test failed - ProcessTrade() trade not stored", err)
+	}
+
+	stored, _ = ProcessTrade("Binance", Trade{ID: "1", Pair: p, AssetType: "SPOT",
+		Price: 100, Amount: 1, Timestamp: now})
+	if stored {
+		t.Error("Test failed - ProcessTrade() duplicate trade ID stored")
+	}
+
+	stored, _ = ProcessTrade("Binance", Trade{Pair: p, AssetType: "SPOT",
+		Price: 101, Amount: 2, Timestamp: now})
+	if !stored {
+		t.Error("Test failed - ProcessTrade() trade without ID not stored")
+	}
+
+	stored, _ = ProcessTrade("Binance", Trade{Pair: p, AssetType: "SPOT",
+		Price: 101, Amount: 2, Timestamp: now})
+	if !stored {
+		t.Error("Test failed - ProcessTrade() identical trade without ID not stored")
+	}
+
+	stored, _ = ProcessTrade("Binance", Trade{Pair: p, AssetType: "SPOT",
+		Price: 100, Amount: 1, Timestamp: now})
+	if !stored {
+		t.Error("Test failed - ProcessTrade() trade without ID matching a trade ID not stored")
+	}
+
+	stored, _ = ProcessTrade("Binance", Trade{ID: "0", Pair: p, AssetType: "SPOT",
+		Price: 99, Amount: 1, Timestamp: now.Add(-DefaultWindow * 2)})
+	if stored {
+		t.Error("Test failed - ProcessTrade() stale trade stored")
+	}
+
+	if len(GetTrades("binance", p, "SPOT", time.Time{}, time.Time{})) != 4 {
+		t.Error("Test failed - GetTrades() unexpected number of trades")
+	}
+}
+
+func TestMergeTrades(t *testing.T) {
+	p := pair.NewCurrencyPair("ETH", "USD")
+	now := time.Now().Truncate(time.Second)
+
+	_, err := ProcessTrade("Bitstamp", Trade{Pair: p, AssetType: "SPOT",
+		Price: 200, Amount: 1, Timestamp: now.Add(time.Millisecond * 300)})
+	if err != nil {
+		t.Fatal("Test failed - ProcessTrade() error", err)
+	}
+
+	added, err := MergeTrades("Bitstamp", p, "SPOT", []Trade{
+		{ID: "10", Price: 199, Amount: 1, Timestamp: now.Add(-time.Minute)},
+		{ID: "11", Price: 200, Amount: 1, Timestamp: now},
+		{ID: "11", Price: 200, Amount: 1, Timestamp: now},
+	})
+	if err != nil || added != 1 {
+		t.Fatal("Test failed - MergeTrades() unexpected trades added", added, err)
+	}
+
+	result := GetTrades("Bitstamp", p, "SPOT", time.Time{}, time.Time{})
+	if len(result) != 2 || result[0].ID != "10" || result[1].ID != "11" {
+		t.Errorf("Test failed - MergeTrades() trades not reconciled %+v", result)
+	}
+
+	stored, _ := ProcessTrade("Bitstamp", Trade{ID: "11", Pair: p, AssetType: "SPOT",
+		Price: 200, Amount: 1, Timestamp: now})
+	if stored {
+		t.Error("Test failed - ProcessTrade() reconciled trade stored twice")
+	}
+
+	result = GetTrades("Bitstamp", p, "SPOT", now, time.Time{})
+	if len(result) != 1 {
+		t.Error("Test failed - GetTrades() unexpected trades in range", result)
+	}
+}
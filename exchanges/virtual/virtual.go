@@ -0,0 +1,653 @@
+package virtual
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+const (
+	virtualAPIURL = "virtual://localhost"
+
+	virtualDefaultMakerFee = 0.001
+	virtualDefaultTakerFee = 0.002
+
+	virtualTickerWindow = time.Hour * 24
+
+	// dust is the amount below which an order is treated as filled, absorbing
+	// floating point error
+	dust = 1e-12
+)
+
+var (
+	// ErrInvalidOrder is returned when an order has no amount, a limit order
+	// has no price or the side or type is unknown
+	ErrInvalidOrder = errors.New("invalid order")
+	// ErrPairNotAvailable is returned when trading a pair which isn't
+	// available on the exchange
+	ErrPairNotAvailable = errors.New("pair not available")
+	// ErrInsufficientBalance is returned when the account balance can't cover
+	// an order or withdrawal
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrNoLiquidity is returned when a market order has nothing to match
+	ErrNoLiquidity = errors.New("no liquidity")
+	// ErrOrderNotFound is returned when an account order is not open
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrTransferNotFound is returned when a fiat withdrawal reference is
+	// unknown
+	ErrTransferNotFound = errors.New("transfer not found")
+)
+
+// Virtual is an in process exchange matching orders against liquidity seeded
+// from its configuration or by SeedLiquidity, so the bot can run end to end
+// without external dependencies
+type Virtual struct {
+	exchange.Base
+	makerFee  float64
+	takerFee  float64
+	books     map[string]*book
+	orders    map[int64]*Order
+	balances  map[string]*Balance
+	trades    map[string][]Trade
+	transfers []exchange.FundHistory
+	nextID    int64
+	mtx       sync.Mutex
+}
+
+// SetDefaults sets the basic defaults for Virtual
+func (v *Virtual) SetDefaults() {
+	v.Name = "Virtual"
+	v.Enabled = false
+	v.Verbose = false
+	v.RESTPollingDelay = 10
+	v.APIWithdrawPermissions = exchange.AutoWithdrawCrypto | exchange.AutoWithdrawFiat
+	v.RequestCurrencyPairFormat.Delimiter = "-"
+	v.RequestCurrencyPairFormat.Uppercase = true
+	v.ConfigCurrencyPairFormat.Delimiter = "-"
+	v.ConfigCurrencyPairFormat.Uppercase = true
+	v.AssetTypes = []string{ticker.Spot}
+	v.SupportsAutoPairUpdating = false
+	v.SupportsRESTTickerBatching = false
+	v.Requester = request.New(v.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
+	v.APIUrlDefault = virtualAPIURL
+	v.APIUrl = v.APIUrlDefault
+	v.WebsocketInit()
+	v.Reset(virtualDefaultMakerFee, virtualDefaultTakerFee)
+}
+
+// Setup takes in the supplied exchange configuration details and sets params
+func (v *Virtual) Setup(exch config.ExchangeConfig) error {
+	if !exch.Enabled {
+		v.SetEnabled(false)
+	} else {
+		v.Enabled = true
+		v.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
+		v.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
+		v.SetHTTPClientTimeout(exch.HTTPTimeout)
+		v.SetHTTPClientUserAgent(exch.HTTPUserAgent)
+		v.RESTPollingDelay = exch.RESTPollingDelay
+		v.Verbose = exch.Verbose
+		v.Websocket.SetEnabled(exch.Websocket)
+		v.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
+		v.AvailablePairs = common.SplitStrings(exch.AvailablePairs, ",")
+		v.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
+		err := v.SetCurrencyPairFormat()
+		if err != nil {
+			return err
+		}
+		err = v.SetAssetTypes()
+		if err != nil {
+			return err
+		}
+		err = v.SetAutoPairDefaults()
+		if err != nil {
+			return err
+		}
+		err = v.SetAPIURL(exch)
+		if err != nil {
+			return err
+		}
+		err = v.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
+		}
+		err = v.SetClientProxyAddress(exch.ProxyAddress)
+		if err != nil {
+			return err
+		}
+		err = v.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
+		}
+		err = v.Seed(exch.Virtual)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset clears the orderbooks, orders, trades and balances of the exchange
+// and sets its trading fees
+func (v *Virtual) Reset(makerFee, takerFee float64) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	v.makerFee = makerFee
+	v.takerFee = takerFee
+	v.books = make(map[string]*book)
+	v.orders = make(map[int64]*Order)
+	v.balances = make(map[string]*Balance)
+	v.trades = make(map[string][]Trade)
+	v.transfers = nil
+}
+
+// Seed resets the exchange to the balances, liquidity and fees of its
+// configuration
+func (v *Virtual) Seed(cfg *config.VirtualExchangeConfig) error {
+	if cfg == nil {
+		v.Reset(virtualDefaultMakerFee, virtualDefaultTakerFee)
+		return nil
+	}
+
+	makerFee, takerFee := cfg.MakerFee, cfg.TakerFee
+	if makerFee == 0 && takerFee == 0 {
+		makerFee, takerFee = virtualDefaultMakerFee, virtualDefaultTakerFee
+	}
+	v.Reset(makerFee, takerFee)
+
+	for currency, amount := range cfg.Balances {
+		v.SetBalance(currency, amount)
+	}
+
+	for x := range cfg.Liquidity {
+		liquidity := cfg.Liquidity[x]
+		p := pair.NewCurrencyPairDelimiter(liquidity.Pair, v.ConfigCurrencyPairFormat.Delimiter)
+		_, err := v.SeedLiquidity(p, parseSide(liquidity.Side), liquidity.Price, liquidity.Amount)
+		if err != nil {
+			return fmt.Errorf("%s liquidity %s %s %v@%v: %s", v.Name, liquidity.Pair,
+				liquidity.Side, liquidity.Amount, liquidity.Price, err)
+		}
+	}
+	return nil
+}
+
+// parseSide returns the order side of a config side, unknown sides are
+// returned as is and rejected when the order is placed
+func parseSide(side string) exchange.OrderSide {
+	switch common.StringToLower(side) {
+	case "buy", "bid":
+		return exchange.OrderSideBuy()
+	case "sell", "ask":
+		return exchange.OrderSideSell()
+	}
+	return exchange.OrderSide(side)
+}
+
+// SetBalance sets the free account balance of a currency
+func (v *Virtual) SetBalance(currency string, amount float64) {
+	v.mtx.Lock()
+	v.balance(currency).Free = amount
+	v.mtx.Unlock()
+}
+
+// Deposit adds to the free account balance of a currency, recording the
+// deposit in the fund transfer history
+func (v *Virtual) Deposit(currency string, amount float64) error {
+	if amount <= 0 {
+		return ErrInvalidOrder
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	v.balance(currency).Free += amount
+	v.recordTransfer(currency, amount, "deposit", "", "")
+	return nil
+}
+
+// GetBalance returns the account balance of a currency
+func (v *Virtual) GetBalance(currency string) Balance {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return *v.balance(currency)
+}
+
+// balance returns the account balance of a currency, creating it when it
+// doesn't exist
+func (v *Virtual) balance(currency string) *Balance {
+	currency = common.StringToUpper(currency)
+	b, ok := v.balances[currency]
+	if !ok {
+		b = &Balance{}
+		v.balances[currency] = b
+	}
+	return b
+}
+
+// SeedLiquidity places an order owned by the exchange on the orderbook,
+// matching any crossing orders first
+func (v *Virtual) SeedLiquidity(p pair.CurrencyPair, side exchange.OrderSide, price, amount float64) (int64, error) {
+	order := &Order{
+		Pair:   p,
+		Side:   side,
+		Type:   exchange.OrderTypeLimit(),
+		Price:  price,
+		Amount: amount,
+		House:  true,
+	}
+	err := validateOrder(order)
+	if err != nil {
+		return 0, err
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	v.place(order)
+	return order.ID, nil
+}
+
+// SubmitOrder places an account order, locking the balance it needs. Limit
+// orders rest on the orderbook until filled or cancelled, market orders fill
+// against the orderbook and any remainder is cancelled
+func (v *Virtual) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (*Order, error) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.submit(p, side, orderType, amount, price, clientID)
+}
+
+func (v *Virtual) submit(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (*Order, error) {
+	order := &Order{
+		ClientID: clientID,
+		Pair:     p,
+		Side:     side,
+		Type:     orderType,
+		Price:    price,
+		Amount:   amount,
+	}
+	err := validateOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pair.Contains(v.GetAvailableCurrencies(), p, true) {
+		return nil, ErrPairNotAvailable
+	}
+
+	currency := p.FirstCurrency.String()
+	order.locked = amount
+	if side == exchange.OrderSideBuy() {
+		currency = p.SecondCurrency.String()
+		if orderType == exchange.OrderTypeMarket() {
+			order.locked = v.marketCost(p, amount)
+			if order.locked == 0 {
+				return nil, ErrNoLiquidity
+			}
+		} else {
+			order.locked = amount * price
+		}
+	}
+
+	balance := v.balance(currency)
+	if balance.Free < order.locked {
+		return nil, ErrInsufficientBalance
+	}
+	balance.Free -= order.locked
+	balance.Locked += order.locked
+
+	v.place(order)
+	result := *order
+	return &result, nil
+}
+
+// validateOrder checks the side, type, amount and price of an order
+func validateOrder(order *Order) error {
+	if order.Side != exchange.OrderSideBuy() && order.Side != exchange.OrderSideSell() {
+		return ErrInvalidOrder
+	}
+
+	if order.Type != exchange.OrderTypeLimit() && order.Type != exchange.OrderTypeMarket() {
+		return ErrInvalidOrder
+	}
+
+	if order.Amount <= 0 || (order.Type == exchange.OrderTypeLimit() && order.Price <= 0) {
+		return ErrInvalidOrder
+	}
+	return nil
+}
+
+// marketCost returns the cost of buying an amount of a pair at market, the
+// amount the orderbook can't fill isn't included
+func (v *Virtual) marketCost(p pair.CurrencyPair, amount float64) float64 {
+	var cost float64
+	for _, ask := range v.getBook(p).asks {
+		fill := amount
+		if ask.Remaining() < fill {
+			fill = ask.Remaining()
+		}
+		cost += fill * ask.Price
+		amount -= fill
+		if amount < dust {
+			break
+		}
+	}
+	return cost
+}
+
+// getBook returns the orderbook of a pair, creating it when it doesn't exist
+func (v *Virtual) getBook(p pair.CurrencyPair) *book {
+	key := pairKey(p)
+	b, ok := v.books[key]
+	if !ok {
+		b = &book{}
+		v.books[key] = b
+	}
+	return b
+}
+
+// pairKey returns the key of a pair in the orderbook and trade maps
+func pairKey(p pair.CurrencyPair) string {
+	return p.FirstCurrency.Upper().String() + "/" + p.SecondCurrency.Upper().String()
+}
+
+// place assigns an order its ID, matches it against the orderbook and rests
+// any limit order remainder
+func (v *Virtual) place(order *Order) {
+	v.nextID++
+	order.ID = v.nextID
+	order.Status = OrderStatusOpen
+	order.Created = time.Now()
+	v.orders[order.ID] = order
+
+	b := v.getBook(order.Pair)
+	if order.Side == exchange.OrderSideBuy() {
+		b.asks = v.match(order, b.asks)
+	} else {
+		b.bids = v.match(order, b.bids)
+	}
+
+	if order.Remaining() == 0 {
+		v.close(order, OrderStatusFilled)
+		return
+	}
+
+	if order.Type == exchange.OrderTypeMarket() {
+		v.close(order, OrderStatusCancelled)
+		return
+	}
+
+	if order.Side == exchange.OrderSideBuy() {
+		x := sort.Search(len(b.bids), func(i int) bool { return b.bids[i].Price < order.Price })
+		b.bids = append(b.bids, nil)
+		copy(b.bids[x+1:], b.bids[x:])
+		b.bids[x] = order
+	} else {
+		x := sort.Search(len(b.asks), func(i int) bool { return b.asks[i].Price > order.Price })
+		b.asks = append(b.asks, nil)
+		copy(b.asks[x+1:], b.asks[x:])
+		b.asks[x] = order
+	}
+}
+
+// match fills the taker order against the resting orders on the opposite
+// side of the orderbook at their prices, returning the orders left resting
+func (v *Virtual) match(taker *Order, resting []*Order) []*Order {
+	for len(resting) > 0 && taker.Remaining() > 0 {
+		maker := resting[0]
+		if taker.Type == exchange.OrderTypeLimit() &&
+			((taker.Side == exchange.OrderSideBuy() && maker.Price > taker.Price) ||
+				(taker.Side == exchange.OrderSideSell() && maker.Price < taker.Price)) {
+			break
+		}
+
+		amount := taker.Remaining()
+		if maker.Remaining() < amount {
+			amount = maker.Remaining()
+		}
+		if taker.Type == exchange.OrderTypeMarket() && taker.Side == exchange.OrderSideBuy() &&
+			!taker.House && amount*maker.Price > taker.locked {
+			amount = taker.locked / maker.Price
+		}
+
+		v.settle(taker, amount, maker.Price, v.takerFee)
+		v.settle(maker, amount, maker.Price, v.makerFee)
+
+		v.nextID++
+		key := pairKey(taker.Pair)
+		v.trades[key] = append(v.trades[key], Trade{
+			ID:        v.nextID,
+			Pair:      taker.Pair,
+			Price:     maker.Price,
+			Amount:    amount,
+			Side:      taker.Side,
+			Timestamp: time.Now(),
+		})
+
+		if maker.Remaining() == 0 {
+			v.close(maker, OrderStatusFilled)
+			resting = resting[1:]
+		}
+		if amount < dust {
+			break
+		}
+	}
+	return resting
+}
+
+// settle fills an amount of an order at a price, moving the locked balance
+// spent to the balance received less the fee. House orders have no balances
+func (v *Virtual) settle(order *Order, amount, price, fee float64) {
+	order.Filled += amount
+	if order.House {
+		return
+	}
+
+	base := v.balance(order.Pair.FirstCurrency.String())
+	quote := v.balance(order.Pair.SecondCurrency.String())
+	if order.Side == exchange.OrderSideBuy() {
+		spent := amount * price
+		order.locked -= spent
+		quote.Locked -= spent
+		base.Free += amount * (1 - fee)
+	} else {
+		order.locked -= amount
+		base.Locked -= amount
+		quote.Free += amount * price * (1 - fee)
+	}
+}
+
+// close finishes an order, releasing its remaining locked balance
+func (v *Virtual) close(order *Order, status string) {
+	order.Status = status
+	if order.House || order.locked <= 0 {
+		order.locked = 0
+		return
+	}
+
+	currency := order.Pair.FirstCurrency.String()
+	if order.Side == exchange.OrderSideBuy() {
+		currency = order.Pair.SecondCurrency.String()
+	}
+	balance := v.balance(currency)
+	balance.Locked -= order.locked
+	balance.Free += order.locked
+	order.locked = 0
+}
+
+// CancelOrder cancels an open account order, releasing its locked balance
+func (v *Virtual) CancelOrder(orderID int64) error {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.cancel(orderID)
+}
+
+func (v *Virtual) cancel(orderID int64) error {
+	order, ok := v.orders[orderID]
+	if !ok || order.House || order.Status != OrderStatusOpen {
+		return ErrOrderNotFound
+	}
+
+	b := v.getBook(order.Pair)
+	if order.Side == exchange.OrderSideBuy() {
+		b.bids = removeOrder(b.bids, orderID)
+	} else {
+		b.asks = removeOrder(b.asks, orderID)
+	}
+	v.close(order, OrderStatusCancelled)
+	return nil
+}
+
+// ModifyOrder replaces an open account order with one of the modified side,
+// type, price and remaining amount, unset fields keeping those of the order.
+// The order loses its place in the orderbook and stays cancelled when the
+// replacement can't be placed
+func (v *Virtual) ModifyOrder(orderID int64, modify exchange.ModifyOrder) (*Order, error) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	order, ok := v.orders[orderID]
+	if !ok || order.House || order.Status != OrderStatusOpen {
+		return nil, ErrOrderNotFound
+	}
+
+	side, orderType := order.Side, order.Type
+	price, amount := order.Price, order.Remaining()
+	if modify.OrderSide != "" {
+		side = modify.OrderSide
+	}
+	if modify.OrderType != "" {
+		orderType = modify.OrderType
+	}
+	if modify.Price != 0 {
+		price = modify.Price
+	}
+	if modify.Amount != 0 {
+		amount = modify.Amount
+	}
+
+	err := validateOrder(&Order{Side: side, Type: orderType, Price: price, Amount: amount})
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.cancel(orderID)
+	if err != nil {
+		return nil, err
+	}
+	return v.submit(order.Pair, side, orderType, amount, price, order.ClientID)
+}
+
+// removeOrder returns the orders without the order ID
+func removeOrder(orders []*Order, orderID int64) []*Order {
+	for x := range orders {
+		if orders[x].ID == orderID {
+			return append(orders[:x], orders[x+1:]...)
+		}
+	}
+	return orders
+}
+
+// GetOrder returns an account order by its ID
+func (v *Virtual) GetOrder(orderID int64) (Order, error) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	order, ok := v.orders[orderID]
+	if !ok || order.House {
+		return Order{}, ErrOrderNotFound
+	}
+	return *order, nil
+}
+
+// GetOrderByClientID returns the open account order with a client order ID
+func (v *Virtual) GetOrderByClientID(clientID string) (Order, error) {
+	for _, order := range v.GetOpenOrders() {
+		if order.ClientID == clientID {
+			return order, nil
+		}
+	}
+	return Order{}, ErrOrderNotFound
+}
+
+// GetOpenOrders returns the open account orders by ID
+func (v *Virtual) GetOpenOrders() []Order {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	var orders []Order
+	for _, order := range v.orders {
+		if !order.House && order.Status == OrderStatusOpen {
+			orders = append(orders, *order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	return orders
+}
+
+// GetTrades returns the trades of a pair oldest first
+func (v *Virtual) GetTrades(p pair.CurrencyPair) []Trade {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return append([]Trade(nil), v.trades[pairKey(p)]...)
+}
+
+// GetFee returns the trading fee of an order at the maker or taker fee rate,
+// deposits and withdrawals are free
+func (v *Virtual) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
+	if feeBuilder.FeeType != exchange.CryptocurrencyTradeFee {
+		return 0, nil
+	}
+
+	v.mtx.Lock()
+	rate := v.takerFee
+	if feeBuilder.IsMaker {
+		rate = v.makerFee
+	}
+	v.mtx.Unlock()
+	return rate * feeBuilder.PurchasePrice * feeBuilder.Amount, nil
+}
+
+// Withdraw removes an amount of a currency from the free account balance,
+// returning the ID the withdrawal is recorded under in the fund transfer
+// history
+func (v *Virtual) Withdraw(currency string, amount float64, address, bank string) (string, error) {
+	if amount <= 0 {
+		return "", ErrInvalidOrder
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	balance := v.balance(currency)
+	if balance.Free < amount {
+		return "", ErrInsufficientBalance
+	}
+	balance.Free -= amount
+	return v.recordTransfer(currency, amount, "withdrawal", address, bank), nil
+}
+
+// recordTransfer adds a completed deposit or withdrawal to the fund transfer
+// history, returning its ID
+func (v *Virtual) recordTransfer(currency string, amount float64, transferType, address, bank string) string {
+	v.nextID++
+	v.transfers = append(v.transfers, exchange.FundHistory{
+		ExchangeName:    v.Name,
+		Status:          "complete",
+		TransferID:      v.nextID,
+		Timestamp:       time.Now().Unix(),
+		Currency:        common.StringToUpper(currency),
+		Amount:          amount,
+		TransferType:    transferType,
+		CryptoToAddress: address,
+		BankTo:          bank,
+	})
+	return strconv.FormatInt(v.nextID, 10)
+}
@@ -0,0 +1,258 @@
+package virtual
+
+import (
+	"math"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/symbol"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+var v Virtual
+
+var btcusd = pair.NewCurrencyPairDelimiter("BTC-USD", "-")
+
+func TestSetDefaults(t *testing.T) {
+	v.SetDefaults()
+}
+
+func TestSetup(t *testing.T) {
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+	virtualConfig, err := cfg.GetExchangeConfig("Virtual")
+	if err != nil {
+		t.Fatal("Test Failed - Virtual Setup() init error")
+	}
+
+	err = v.Setup(virtualConfig)
+	if err != nil {
+		t.Fatal("Test Failed - Virtual Setup() error", err)
+	}
+
+	if v.GetBalance(symbol.USD).Free != 10000 || v.GetBalance(symbol.BTC).Free != 1 {
+		t.Error("Test Failed - Virtual Setup() balances not seeded")
+	}
+}
+
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSubmitOrder(t *testing.T) {
+	TestSetup(t)
+
+	_, err := v.SubmitOrder(btcusd, exchange.OrderSideBuy(), exchange.OrderTypeLimit(), 0, 6000, "")
+	if err != ErrInvalidOrder {
+		t.Error("Test Failed - SubmitOrder() expected invalid order error", err)
+	}
+
+	_, err = v.SubmitOrder(pair.NewCurrencyPairDelimiter("LTC-USD", "-"),
+		exchange.OrderSideBuy(), exchange.OrderTypeLimit(), 1, 60, "")
+	if err != ErrPairNotAvailable {
+		t.Error("Test Failed - SubmitOrder() expected pair not available error", err)
+	}
+
+	_, err = v.SubmitOrder(btcusd, exchange.OrderSideBuy(), exchange.OrderTypeLimit(), 10, 6000, "")
+	if err != ErrInsufficientBalance {
+		t.Error("Test Failed - SubmitOrder() expected insufficient balance error", err)
+	}
+
+	// Crosses the seeded asks at 6500, filling at the resting price
+	order, err := v.SubmitOrder(btcusd, exchange.OrderSideBuy(), exchange.OrderTypeLimit(), 1, 6600, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if order.Status != OrderStatusFilled {
+		t.Error("Test Failed - SubmitOrder() expected filled order", order.Status)
+	}
+
+	usd, btc := v.GetBalance(symbol.USD), v.GetBalance(symbol.BTC)
+	if !floatEquals(usd.Free, 3500) || usd.Locked != 0 || !floatEquals(btc.Free, 1.998) {
+		t.Errorf("Test Failed - SubmitOrder() unexpected balances %+v %+v", usd, btc)
+	}
+
+	order, err = v.SubmitOrder(btcusd, exchange.OrderSideSell(), exchange.OrderTypeLimit(), 1, 6450, "client")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if order.Status != OrderStatusOpen || !floatEquals(v.GetBalance(symbol.BTC).Locked, 1) {
+		t.Error("Test Failed - SubmitOrder() expected resting order")
+	}
+
+	// A house bid takes the best resting ask, the account pays the maker fee
+	_, err = v.SeedLiquidity(btcusd, exchange.OrderSideBuy(), 6450, 0.5)
+	if err != nil {
+		t.Fatal("Test Failed - SeedLiquidity() error", err)
+	}
+
+	resting, err := v.GetOrder(order.ID)
+	if err != nil || !floatEquals(resting.Remaining(), 0.5) {
+		t.Error("Test Failed - SeedLiquidity() expected partial fill", err)
+	}
+	if !floatEquals(v.GetBalance(symbol.USD).Free, 3500+3225*0.999) {
+		t.Error("Test Failed - SeedLiquidity() unexpected balance", v.GetBalance(symbol.USD))
+	}
+
+	err = v.CancelOrderByClientID("client")
+	if err != nil {
+		t.Error("Test Failed - CancelOrderByClientID() error", err)
+	}
+	btc = v.GetBalance(symbol.BTC)
+	if btc.Locked != 0 || !floatEquals(btc.Free, 1.498) {
+		t.Errorf("Test Failed - CancelOrderByClientID() lock not released %+v", btc)
+	}
+
+	err = v.CancelOrder(order.ID)
+	if err != ErrOrderNotFound {
+		t.Error("Test Failed - CancelOrder() expected order not found error", err)
+	}
+}
+
+func TestMarketOrder(t *testing.T) {
+	TestSetup(t)
+
+	_, err := v.SubmitOrder(btcusd, exchange.OrderSideSell(), exchange.OrderTypeMarket(), 0.5, 0, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if !floatEquals(v.GetBalance(symbol.USD).Free, 10000+3200*0.998) {
+		t.Error("Test Failed - SubmitOrder() unexpected balance", v.GetBalance(symbol.USD))
+	}
+
+	v.SetBalance(symbol.USD, 50000)
+
+	// Only 5 BTC is offered, the rest of the market order is cancelled
+	id, err := v.SubmitExchangeOrder(btcusd, exchange.OrderSideBuy(), exchange.OrderTypeMarket(), 6, 0, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitExchangeOrder() error", err)
+	}
+
+	detail, err := v.GetExchangeOrderInfo(id)
+	if err != nil || detail.Status != OrderStatusCancelled || !floatEquals(detail.OpenVolume, 1) {
+		t.Errorf("Test Failed - GetExchangeOrderInfo() unexpected order %+v %v", detail, err)
+	}
+	if v.GetBalance(symbol.USD).Locked != 0 {
+		t.Error("Test Failed - SubmitExchangeOrder() lock not released")
+	}
+
+	_, err = v.SubmitOrder(btcusd, exchange.OrderSideBuy(), exchange.OrderTypeMarket(), 1, 0, "")
+	if err != ErrNoLiquidity {
+		t.Error("Test Failed - SubmitOrder() expected no liquidity error", err)
+	}
+}
+
+func TestModifyExchangeOrder(t *testing.T) {
+	TestSetup(t)
+
+	id, err := v.SubmitExchangeOrder(btcusd, exchange.OrderSideBuy(), exchange.OrderTypeLimit(), 1, 6000, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitExchangeOrder() error", err)
+	}
+
+	newID, err := v.ModifyExchangeOrder(id, exchange.ModifyOrder{Price: 6100})
+	if err != nil {
+		t.Fatal("Test Failed - ModifyExchangeOrder() error", err)
+	}
+
+	orders, err := v.GetExchangeOpenOrders()
+	if err != nil || len(orders) != 1 || orders[0].ID != newID || orders[0].Price != 6100 {
+		t.Errorf("Test Failed - GetExchangeOpenOrders() unexpected orders %+v %v", orders, err)
+	}
+	if !floatEquals(v.GetBalance(symbol.USD).Locked, 6100) {
+		t.Error("Test Failed - ModifyExchangeOrder() unexpected lock", v.GetBalance(symbol.USD))
+	}
+
+	err = v.CancelAllExchangeOrders()
+	if err != nil || v.GetBalance(symbol.USD).Free != 10000 {
+		t.Error("Test Failed - CancelAllExchangeOrders() error", err)
+	}
+}
+
+func TestMarketData(t *testing.T) {
+	TestSetup(t)
+
+	_, err := v.SeedLiquidity(btcusd, exchange.OrderSideSell(), 6500, 1)
+	if err != nil {
+		t.Fatal("Test Failed - SeedLiquidity() error", err)
+	}
+
+	ob, err := v.UpdateOrderbook(btcusd, ticker.Spot)
+	if err != nil {
+		t.Fatal("Test Failed - UpdateOrderbook() error", err)
+	}
+	if len(ob.Asks) != 1 || ob.Asks[0].Amount != 6 || len(ob.Bids) != 1 {
+		t.Errorf("Test Failed - UpdateOrderbook() unexpected orderbook %+v", ob)
+	}
+
+	_, err = v.SubmitExchangeOrder(btcusd, exchange.OrderSideBuy(), exchange.OrderTypeLimit(), 0.5, 6500, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitExchangeOrder() error", err)
+	}
+
+	tp, err := v.UpdateTicker(btcusd, ticker.Spot)
+	if err != nil {
+		t.Fatal("Test Failed - UpdateTicker() error", err)
+	}
+	if tp.Bid != 6400 || tp.Ask != 6500 || tp.Last != 6500 || tp.Volume != 0.5 {
+		t.Errorf("Test Failed - UpdateTicker() unexpected ticker %+v", tp)
+	}
+
+	history, err := v.GetExchangeHistory(btcusd, ticker.Spot)
+	if err != nil || len(history) != 1 || history[0].Type != string(exchange.OrderSideBuy()) {
+		t.Errorf("Test Failed - GetExchangeHistory() unexpected trades %+v %v", history, err)
+	}
+}
+
+func TestGetFee(t *testing.T) {
+	TestSetup(t)
+
+	fee, err := v.GetFeeByType(exchange.FeeBuilder{
+		FeeType:       exchange.CryptocurrencyTradeFee,
+		PurchasePrice: 6500,
+		Amount:        1,
+	})
+	if err != nil || !floatEquals(fee, 13) {
+		t.Error("Test Failed - GetFeeByType() unexpected fee", fee, err)
+	}
+
+	if v.IsFeeEstimated(exchange.FeeBuilder{FeeType: exchange.CryptocurrencyTradeFee}) {
+		t.Error("Test Failed - IsFeeEstimated() expected exact fee")
+	}
+}
+
+func TestWithdraw(t *testing.T) {
+	TestSetup(t)
+
+	_, err := v.WithdrawCryptoExchangeFunds("address", symbol.BTC, 2)
+	if err != ErrInsufficientBalance {
+		t.Error("Test Failed - WithdrawCryptoExchangeFunds() expected insufficient balance error", err)
+	}
+
+	_, err = v.WithdrawCryptoExchangeFunds("address", symbol.BTC, 0.5)
+	if err != nil || v.GetBalance(symbol.BTC).Free != 0.5 {
+		t.Error("Test Failed - WithdrawCryptoExchangeFunds() error", err)
+	}
+
+	reference, err := v.WithdrawFiatExchangeFunds(symbol.USD, 1000)
+	if err != nil {
+		t.Fatal("Test Failed - WithdrawFiatExchangeFunds() error", err)
+	}
+
+	status, err := v.GetFiatTransferStatus(reference)
+	if err != nil || status.Status != exchange.FiatTransferSent || status.Amount != 1000 {
+		t.Errorf("Test Failed - GetFiatTransferStatus() unexpected status %+v %v", status, err)
+	}
+
+	history, err := v.GetExchangeFundTransferHistory()
+	if err != nil || len(history) != 2 {
+		t.Errorf("Test Failed - GetExchangeFundTransferHistory() unexpected history %+v %v", history, err)
+	}
+
+	account, err := v.GetExchangeAccountInfo()
+	if err != nil || len(account.Currencies) != 2 {
+		t.Errorf("Test Failed - GetExchangeAccountInfo() unexpected account %+v %v", account, err)
+	}
+}
@@ -0,0 +1,68 @@
+package virtual
+
+import (
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// Order states
+const (
+	OrderStatusOpen      = "open"
+	OrderStatusFilled    = "filled"
+	OrderStatusCancelled = "cancelled"
+)
+
+// Order holds an order on the virtual exchange. House orders are seeded
+// liquidity belonging to the exchange, so they don't touch account balances
+type Order struct {
+	ID       int64
+	ClientID string
+	Pair     pair.CurrencyPair
+	Side     exchange.OrderSide
+	Type     exchange.OrderType
+	Price    float64
+	Amount   float64
+	Filled   float64
+	Status   string
+	House    bool
+	Created  time.Time
+
+	// locked is the account balance still held for the order
+	locked float64
+}
+
+// Remaining returns the unfilled amount of the order
+func (o *Order) Remaining() float64 {
+	remaining := o.Amount - o.Filled
+	if remaining < dust {
+		return 0
+	}
+	return remaining
+}
+
+// Trade holds a match between two orders, Side is the side of the order
+// which took liquidity
+type Trade struct {
+	ID        int64
+	Pair      pair.CurrencyPair
+	Price     float64
+	Amount    float64
+	Side      exchange.OrderSide
+	Timestamp time.Time
+}
+
+// Balance holds the account balance of a currency, Locked is held by open
+// orders
+type Balance struct {
+	Free   float64
+	Locked float64
+}
+
+// book holds the open orders of a pair, bids by descending price and asks by
+// ascending price with earlier orders first at each price
+type book struct {
+	bids []*Order
+	asks []*Order
+}
@@ -0,0 +1,363 @@
+package virtual
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// init registers the Virtual exchange
+func init() {
+	exchange.Register("Virtual", func() exchange.IBotExchange { return new(Virtual) })
+}
+
+// Start starts the Virtual go routine
+func (v *Virtual) Start(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		v.Run(ctx)
+		wg.Done()
+	}()
+}
+
+// Run implements the Virtual wrapper
+func (v *Virtual) Run(ctx context.Context) {
+	if v.Verbose {
+		log.Printf("%s polling delay: %ds.\n", v.GetName(), v.RESTPollingDelay)
+		log.Printf("%s %d currencies enabled: %s.\n", v.GetName(), len(v.EnabledPairs), v.EnabledPairs)
+	}
+}
+
+// GetListedPairs returns the currency pairs currently listed on the exchange
+func (v *Virtual) GetListedPairs() ([]string, error) {
+	return v.AvailablePairs, nil
+}
+
+// UpdateTicker updates and returns the ticker for a currency pair from the
+// orderbook and the trades of the last 24 hours
+func (v *Virtual) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	tickerPrice := ticker.Price{Pair: p}
+
+	v.mtx.Lock()
+	b := v.getBook(p)
+	if len(b.bids) > 0 {
+		tickerPrice.Bid = b.bids[0].Price
+	}
+	if len(b.asks) > 0 {
+		tickerPrice.Ask = b.asks[0].Price
+	}
+
+	trades := v.trades[pairKey(p)]
+	if len(trades) > 0 {
+		tickerPrice.Last = trades[len(trades)-1].Price
+	}
+
+	since := time.Now().Add(-virtualTickerWindow)
+	for x := len(trades) - 1; x >= 0 && trades[x].Timestamp.After(since); x-- {
+		if trades[x].Price > tickerPrice.High {
+			tickerPrice.High = trades[x].Price
+		}
+		if tickerPrice.Low == 0 || trades[x].Price < tickerPrice.Low {
+			tickerPrice.Low = trades[x].Price
+		}
+		tickerPrice.Volume += trades[x].Amount
+	}
+	v.mtx.Unlock()
+
+	ticker.ProcessTicker(v.GetName(), p, tickerPrice, assetType)
+	return ticker.GetTicker(v.Name, p, assetType)
+}
+
+// UpdateTickerContext updates and returns the ticker for a currency pair, the
+// virtual exchange makes no requests so the context is only checked up front
+func (v *Virtual) UpdateTickerContext(ctx context.Context, p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	if err := ctx.Err(); err != nil {
+		return ticker.Price{}, err
+	}
+	return v.UpdateTicker(p, assetType)
+}
+
+// GetTickerPrice returns the ticker for a currency pair
+func (v *Virtual) GetTickerPrice(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	tickerNew, err := ticker.GetTicker(v.GetName(), p, assetType)
+	if err != nil {
+		return v.UpdateTicker(p, assetType)
+	}
+	return tickerNew, nil
+}
+
+// GetOrderbookEx returns orderbook base on the currency pair
+func (v *Virtual) GetOrderbookEx(currency pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+	ob, err := orderbook.GetOrderbook(v.GetName(), currency, assetType)
+	if err != nil {
+		return v.UpdateOrderbook(currency, assetType)
+	}
+	return ob, nil
+}
+
+// UpdateOrderbook updates and returns the orderbook for a currency pair,
+// aggregating the open orders at each price
+func (v *Virtual) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+	var orderBook orderbook.Base
+
+	v.mtx.Lock()
+	b := v.getBook(p)
+	orderBook.Bids = aggregate(b.bids)
+	orderBook.Asks = aggregate(b.asks)
+	v.mtx.Unlock()
+
+	orderbook.ProcessOrderbook(v.GetName(), p, v.TruncateOrderbook(p, orderBook), assetType)
+	return orderbook.GetOrderbook(v.Name, p, assetType)
+}
+
+// aggregate returns the price levels of orders sorted by price
+func aggregate(orders []*Order) []orderbook.Item {
+	var levels []orderbook.Item
+	for _, order := range orders {
+		if len(levels) > 0 && levels[len(levels)-1].Price == order.Price {
+			levels[len(levels)-1].Amount += order.Remaining()
+			continue
+		}
+		levels = append(levels, orderbook.Item{Price: order.Price, Amount: order.Remaining()})
+	}
+	return levels
+}
+
+// GetExchangeAccountInfo retrieves balances for all currencies held on the
+// Virtual exchange
+func (v *Virtual) GetExchangeAccountInfo() (exchange.AccountInfo, error) {
+	response := exchange.AccountInfo{ExchangeName: v.GetName()}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	for currency, balance := range v.balances {
+		response.Currencies = append(response.Currencies,
+			exchange.NewAccountCurrencyInfo(currency, balance.Free, balance.Locked))
+	}
+	return response, nil
+}
+
+// GetExchangeFundTransferHistory returns funding history, deposits and
+// withdrawals
+func (v *Virtual) GetExchangeFundTransferHistory() ([]exchange.FundHistory, error) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return append([]exchange.FundHistory(nil), v.transfers...), nil
+}
+
+// GetExchangeDepositHistory returns the deposits made between start and end,
+// a zero start or end leaving that side of the range open
+func (v *Virtual) GetExchangeDepositHistory(start, end time.Time) ([]exchange.Deposit, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeWithdrawalHistory returns the withdrawals made between start and
+// end, a zero start or end leaving that side of the range open
+func (v *Virtual) GetExchangeWithdrawalHistory(start, end time.Time) ([]exchange.Withdrawal, error) {
+	return nil, exchange.ErrTransferHistoryNotSupported
+}
+
+// GetExchangeHistory returns historic trade data since exchange opening.
+func (v *Virtual) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+	var resp []exchange.TradeHistory
+	for _, trade := range v.GetTrades(p) {
+		resp = append(resp, exchange.TradeHistory{
+			Timestamp: trade.Timestamp.Unix(),
+			TID:       trade.ID,
+			Price:     trade.Price,
+			Amount:    trade.Amount,
+			Exchange:  v.Name,
+			Type:      string(trade.Side),
+		})
+	}
+	return resp, nil
+}
+
+// GetHistoricCandles returns the candles of a currency pair starting from start
+// up to end
+func (v *Virtual) GetHistoricCandles(currencyPair pair.CurrencyPair, assetType string, interval time.Duration, start, end time.Time) ([]kline.Candle, error) {
+	return nil, exchange.ErrHistoricCandlesNotSupported
+}
+
+// SubmitExchangeOrder submits a new order
+func (v *Virtual) SubmitExchangeOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	order, err := v.SubmitOrder(p, side, orderType, amount, price, clientID)
+	if err != nil {
+		return 0, err
+	}
+	return order.ID, nil
+}
+
+// SubmitExchangeOrderContext submits a new order, the virtual exchange makes no
+// requests so the context is only checked up front
+func (v *Virtual) SubmitExchangeOrderContext(ctx context.Context, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return v.SubmitExchangeOrder(p, side, orderType, amount, price, clientID)
+}
+
+// ModifyExchangeOrder will allow of changing orderbook placement and limit to
+// market conversion
+func (v *Virtual) ModifyExchangeOrder(orderID int64, action exchange.ModifyOrder) (int64, error) {
+	order, err := v.ModifyOrder(orderID, action)
+	if err != nil {
+		return 0, err
+	}
+	return order.ID, nil
+}
+
+// CancelExchangeOrder cancels an order by its corresponding ID number
+func (v *Virtual) CancelExchangeOrder(orderID int64) error {
+	return v.CancelOrder(orderID)
+}
+
+// CancelOrderByClientID cancels an order by its client order ID
+func (v *Virtual) CancelOrderByClientID(clientID string) error {
+	order, err := v.GetOrderByClientID(clientID)
+	if err != nil {
+		return err
+	}
+	return v.CancelOrder(order.ID)
+}
+
+// SubmitExchangeOrders submits multiple orders, one request per order as the
+// exchange doesn't support batch order submission
+func (v *Virtual) SubmitExchangeOrders(orders []exchange.BatchOrder) []exchange.BatchOrderResult {
+	return exchange.SubmitOrdersIndividually(orders, v.SubmitExchangeOrder)
+}
+
+// CancelExchangeOrders cancels multiple orders by their ID numbers, one request
+// per order as the exchange doesn't support batch order cancellation
+func (v *Virtual) CancelExchangeOrders(orderIDs []int64) []exchange.BatchCancelResult {
+	return exchange.CancelOrdersIndividually(orderIDs, v.CancelExchangeOrder)
+}
+
+// CancelAllExchangeOrders cancels all open orders
+func (v *Virtual) CancelAllExchangeOrders() error {
+	for _, order := range v.GetOpenOrders() {
+		err := v.CancelOrder(order.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetExchangeOrderInfo returns information on a current open order
+func (v *Virtual) GetExchangeOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+	order, err := v.GetOrder(orderID)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+	return v.orderDetail(order), nil
+}
+
+// GetExchangeOpenOrders returns the open orders on the exchange
+func (v *Virtual) GetExchangeOpenOrders() ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	for _, order := range v.GetOpenOrders() {
+		orders = append(orders, v.orderDetail(order))
+	}
+	return orders, nil
+}
+
+// orderDetail returns the order detail of an account order
+func (v *Virtual) orderDetail(order Order) exchange.OrderDetail {
+	return exchange.OrderDetail{
+		Exchange:      v.Name,
+		ID:            order.ID,
+		ClientID:      order.ClientID,
+		BaseCurrency:  order.Pair.FirstCurrency.String(),
+		QuoteCurrency: order.Pair.SecondCurrency.String(),
+		OrderSide:     string(order.Side),
+		OrderType:     string(order.Type),
+		CreationTime:  order.Created.Unix(),
+		Status:        order.Status,
+		Price:         order.Price,
+		Amount:        order.Amount,
+		OpenVolume:    order.Remaining(),
+	}
+}
+
+// GetExchangeDepositAddress returns a deposit address for a specified currency
+func (v *Virtual) GetExchangeDepositAddress(cryptocurrency pair.CurrencyItem) (string, error) {
+	return "virtual-" + common.StringToLower(cryptocurrency.String()), nil
+}
+
+// WithdrawCryptoExchangeFunds returns a withdrawal ID when a withdrawal is
+// submitted
+func (v *Virtual) WithdrawCryptoExchangeFunds(address string, cryptocurrency pair.CurrencyItem, amount float64) (string, error) {
+	if address == "" {
+		return "", errors.New("withdrawal address required")
+	}
+	return v.Withdraw(cryptocurrency.String(), amount, address, "")
+}
+
+// WithdrawFiatExchangeFunds returns a withdrawal ID when a
+// withdrawal is submitted
+func (v *Virtual) WithdrawFiatExchangeFunds(currency pair.CurrencyItem, amount float64) (string, error) {
+	return v.Withdraw(currency.String(), amount, "", v.Name)
+}
+
+// GetWithdrawalLimits returns the withdrawal limits of the exchange account,
+// withdrawals are only limited by the account balance
+func (v *Virtual) GetWithdrawalLimits() ([]exchange.WithdrawalLimit, error) {
+	return nil, nil
+}
+
+// GetFiatTransferStatus returns the state of the bank transfer of a fiat
+// withdrawal by the reference returned when it was submitted, transfers are
+// sent immediately
+func (v *Virtual) GetFiatTransferStatus(reference string) (exchange.FiatTransferStatus, error) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	for _, transfer := range v.transfers {
+		if transfer.BankTo == "" || strconv.FormatInt(transfer.TransferID, 10) != reference {
+			continue
+		}
+		return exchange.FiatTransferStatus{
+			Reference: reference,
+			Status:    exchange.FiatTransferSent,
+			Amount:    transfer.Amount,
+			Updated:   time.Unix(transfer.Timestamp, 0),
+		}, nil
+	}
+	return exchange.FiatTransferStatus{}, exchange.ErrFiatTransferNotFound
+}
+
+// WithdrawFiatExchangeFundsToInternationalBank returns a withdrawal ID when a
+// withdrawal is submitted
+func (v *Virtual) WithdrawFiatExchangeFundsToInternationalBank(currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
+	return v.WithdrawFiatExchangeFunds(currency, amount)
+}
+
+// GetWebsocket returns a pointer to the exchange websocket
+func (v *Virtual) GetWebsocket() (*exchange.Websocket, error) {
+	return nil, errors.New("not supported on exchange")
+}
+
+// GetExchangeServerTime returns the current exchange server time
+func (v *Virtual) GetExchangeServerTime() (time.Time, error) {
+	return time.Now(), nil
+}
+
+// GetFeeByType returns an estimate of fee based on type of transaction
+func (v *Virtual) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
+	return v.GetFee(feeBuilder)
+}
+
+// GetWithdrawCapabilities returns the types of withdrawal methods permitted by the exchange
+func (v *Virtual) GetWithdrawCapabilities() uint32 {
+	return v.GetWithdrawPermissions()
+}
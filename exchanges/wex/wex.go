@@ -74,10 +74,11 @@ func (w *WEX) SetDefaults() {
 	w.APIUrlSecondaryDefault = wexAPIPrivateURL
 	w.APIUrlSecondary = w.APIUrlSecondaryDefault
 	w.WebsocketInit()
+	w.SetAPIPermissionsFunc(w.GetAPIKeyPermissions)
 }
 
 // Setup sets exchange configuration parameters for WEX
-func (w *WEX) Setup(exch config.ExchangeConfig) {
+func (w *WEX) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		w.SetEnabled(false)
 	} else {
@@ -93,25 +94,34 @@ func (w *WEX) Setup(exch config.ExchangeConfig) {
 		w.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := w.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = w.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = w.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = w.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = w.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = w.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = w.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetTradablePairs returns a list of available pairs from the exchange
@@ -188,6 +198,27 @@ func (w *WEX) GetAccountInfo() (AccountInfo, error) {
 	return result, nil
 }
 
+// GetAPIKeyPermissions returns the permission scopes of the API key from its
+// account privileges
+func (w *WEX) GetAPIKeyPermissions() ([]string, error) {
+	result, err := w.GetAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	var scopes []string
+	if result.Rights.Info == 1 {
+		scopes = append(scopes, exchange.APIPermissionRead)
+	}
+	if result.Rights.Trade == 1 {
+		scopes = append(scopes, exchange.APIPermissionTrade)
+	}
+	if result.Rights.Withdraw == 1 {
+		scopes = append(scopes, exchange.APIPermissionWithdraw)
+	}
+	return scopes, nil
+}
+
 // GetActiveOrders returns the active orders for a specific currency
 func (w *WEX) GetActiveOrders(pair string) (map[string]ActiveOrders, error) {
 	req := url.Values{}
@@ -366,7 +397,7 @@ func (w *WEX) SendAuthenticatedHTTPRequest(method string, values url.Values, res
 	}
 
 	if w.Nonce.Get() == 0 {
-		w.Nonce.Set(time.Now().Unix())
+		w.Nonce.Set(w.GetAdjustedTime().Unix())
 	} else {
 		w.Nonce.Inc()
 	}
@@ -76,7 +76,7 @@ func (y *Yobit) SetDefaults() {
 }
 
 // Setup sets exchange configuration parameters for Yobit
-func (y *Yobit) Setup(exch config.ExchangeConfig) {
+func (y *Yobit) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		y.SetEnabled(false)
 	} else {
@@ -93,25 +93,34 @@ func (y *Yobit) Setup(exch config.ExchangeConfig) {
 		y.SetHTTPClientUserAgent(exch.HTTPUserAgent)
 		err := y.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = y.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = y.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = y.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = y.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = y.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = y.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // GetInfo returns the Yobit info
@@ -334,7 +343,7 @@ func (y *Yobit) SendAuthenticatedHTTPRequest(path string, params url.Values, res
 	}
 
 	if y.Nonce.Get() == 0 {
-		y.Nonce.Set(time.Now().Unix())
+		y.Nonce.Set(y.GetAdjustedTime().Unix())
 	} else {
 		y.Nonce.Inc()
 	}
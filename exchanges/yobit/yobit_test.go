@@ -309,3 +309,16 @@ func TestFormatWithdrawPermissions(t *testing.T) {
 		t.Errorf("Expected: %s, Recieved: %s", expectedResult, withdrawPermissions)
 	}
 }
+
+func TestConvertAccountInfo(t *testing.T) {
+	t.Parallel()
+
+	currencies := convertAccountInfo(AccountInfo{
+		Funds:           map[string]float64{"btc": 1},
+		FundsInclOrders: map[string]float64{"btc": 1.5},
+	})
+	if len(currencies) != 1 || currencies[0].CurrencyName != symbol.BTC || currencies[0].Free != 1 ||
+		currencies[0].Locked != 0.5 || currencies[0].Total != 1.5 {
+		t.Errorf("Test Failed - convertAccountInfo() unexpected result %+v", currencies)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"strconv"
 	"strings"
@@ -69,7 +68,7 @@ func (z *ZB) SetDefaults() {
 }
 
 // Setup sets user configuration
-func (z *ZB) Setup(exch config.ExchangeConfig) {
+func (z *ZB) Setup(exch config.ExchangeConfig) error {
 	if !exch.Enabled {
 		z.SetEnabled(false)
 	} else {
@@ -87,25 +86,34 @@ func (z *ZB) Setup(exch config.ExchangeConfig) {
 		z.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
 		err := z.SetCurrencyPairFormat()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = z.SetAssetTypes()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = z.SetAutoPairDefaults()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = z.SetAPIURL(exch)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = z.SetHTTPClientTransport(exch.HTTPTransport)
+		if err != nil {
+			return err
 		}
 		err = z.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = z.SetOrderbookDepth(exch.OrderbookDepth)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // SpotNewOrder submits an order to ZB
@@ -329,7 +337,7 @@ func (z *ZB) SendAuthenticatedHTTPRequest(method, endpoint string, values url.Va
 			[]byte(values.Encode()),
 			[]byte(common.Sha1ToHex(z.APISecret)))))
 
-	values.Set("reqTime", fmt.Sprintf("%d", time.Now().UnixNano()/1e6))
+	values.Set("reqTime", fmt.Sprintf("%d", z.GetAdjustedTime().UnixNano()/1e6))
 
 	url := fmt.Sprintf("%s/%s?%s",
 		z.APIUrlSecondaryDefault,
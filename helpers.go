@@ -1,25 +1,46 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency"
+	"github.com/thrasher-/gocryptotrader/currency/marketdata"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/translation"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
 	"github.com/thrasher-/gocryptotrader/exchanges/stats"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-/gocryptotrader/portfolio"
+	"github.com/thrasher-/gocryptotrader/strategy"
 )
 
 const (
-	logFile = "debug.log"
+	logFile           = "debug.log"
+	auditFile         = "audit.log"
+	trailingStopsFile = "trailingstops.json"
+	clientOrdersFile  = "clientorders.json"
+	tickersFile       = "tickers.json"
+	orderbooksFile    = "orderbooks.json"
+	symbolRulesFile   = "symbolrules.json"
+	fiatTransfersFile = "fiattransfers.json"
+
+	schedulerProgressFile = "schedulerprogress.json"
+	ledgerFile            = "ledger.jsonl"
+	tradeHistoryFile      = "tradehistory.jsonl"
+	balanceSnapshotsFile  = "balancesnapshots.jsonl"
+	fillsFile             = "fills.jsonl"
 )
 
 var (
@@ -48,6 +69,78 @@ func GetLogFile(dir string) string {
 	return dir + common.GetOSPathSlash() + logFile
 }
 
+// GetAuditFile returns the audit.log file
+func GetAuditFile(dir string) string {
+	return dir + common.GetOSPathSlash() + auditFile
+}
+
+// GetTrailingStopsFile returns the file trailing stops are persisted to
+func GetTrailingStopsFile(dir string) string {
+	return dir + common.GetOSPathSlash() + trailingStopsFile
+}
+
+// GetClientOrdersFile returns the file the client order registry is persisted
+// to
+func GetClientOrdersFile(dir string) string {
+	return dir + common.GetOSPathSlash() + clientOrdersFile
+}
+
+// GetFillsFile returns the file the fills of the bot's orders are persisted to
+func GetFillsFile(dir string) string {
+	return dir + common.GetOSPathSlash() + fillsFile
+}
+
+// GetFiatTransfersFile returns the file the fiat transfer registry is
+// persisted to
+func GetFiatTransfersFile(dir string) string {
+	return dir + common.GetOSPathSlash() + fiatTransfersFile
+}
+
+// GetSchedulerProgressFile returns the file the progress of scheduled jobs is
+// persisted to
+func GetSchedulerProgressFile(dir string) string {
+	return dir + common.GetOSPathSlash() + schedulerProgressFile
+}
+
+// LoadWarmCache restores the tickers, orderbooks and symbol rules persisted to
+// the data directory on the last shutdown. Restored tickers and orderbooks are
+// marked stale until the exchanges refresh them
+func LoadWarmCache(dir string, maxAge time.Duration) error {
+	tickers, err := ticker.LoadTickers(dir+common.GetOSPathSlash()+tickersFile, maxAge)
+	if err != nil {
+		return err
+	}
+
+	orderbooks, err := orderbook.LoadOrderbooks(dir+common.GetOSPathSlash()+orderbooksFile, maxAge)
+	if err != nil {
+		return err
+	}
+
+	rules, err := orders.LoadSymbolRules(dir + common.GetOSPathSlash() + symbolRulesFile)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Warm cache restored %d tickers, %d orderbooks and %d symbol rules.\n",
+		tickers, orderbooks, rules)
+	return nil
+}
+
+// SaveWarmCache persists the last known tickers, orderbooks and symbol rules
+// to the data directory
+func SaveWarmCache(dir string) error {
+	err := ticker.SaveTickers(dir + common.GetOSPathSlash() + tickersFile)
+	if err != nil {
+		return err
+	}
+
+	err = orderbook.SaveOrderbooks(dir + common.GetOSPathSlash() + orderbooksFile)
+	if err != nil {
+		return err
+	}
+	return orders.SaveSymbolRules(dir + common.GetOSPathSlash() + symbolRulesFile)
+}
+
 // GetAllAvailablePairs returns a list of all available pairs on either enabled
 // or disabled exchanges
 func GetAllAvailablePairs(enabledExchangesOnly bool) []pair.CurrencyPair {
@@ -276,15 +369,27 @@ func GetSpecificOrderbook(currency, exchangeName, assetType string) (orderbook.B
 // GetSpecificTicker returns a specific ticker given the currency,
 // exchangeName and assetType
 func GetSpecificTicker(currency, exchangeName, assetType string) (ticker.Price, error) {
+	return GetSpecificTickerContext(context.Background(), currency, exchangeName, assetType)
+}
+
+// GetSpecificTickerContext returns a specific ticker as in GetSpecificTicker,
+// a ticker which isn't cached is fetched under the call context
+func GetSpecificTickerContext(ctx context.Context, currency, exchangeName, assetType string) (ticker.Price, error) {
 	var specificTicker ticker.Price
 	var err error
 	for x := range bot.exchanges {
 		if bot.exchanges[x] != nil {
 			if bot.exchanges[x].GetName() == exchangeName {
-				specificTicker, err = bot.exchanges[x].GetTickerPrice(
-					pair.NewCurrencyPairFromString(currency),
-					assetType,
-				)
+				p := pair.NewCurrencyPairFromString(currency)
+				specificTicker, err = getTickerPrice(ctx, bot.exchanges[x], p, assetType)
+				if err != nil && ctx.Err() == nil {
+					// Fall back to a cross rate if the exchange doesn't list
+					// the pair
+					synthetic, synthErr := ticker.GetSyntheticTicker(exchangeName, p, assetType)
+					if synthErr == nil {
+						return synthetic, nil
+					}
+				}
 				break
 			}
 		}
@@ -292,6 +397,787 @@ func GetSpecificTicker(currency, exchangeName, assetType string) (ticker.Price,
 	return specificTicker, err
 }
 
+// getTickerPrice returns the cached ticker for a currency pair, updating it
+// under the call context when the exchange supports it
+func getTickerPrice(ctx context.Context, exch exchange.IBotExchange, p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	if err := ctx.Err(); err != nil {
+		return ticker.Price{}, err
+	}
+
+	c, ok := exch.(exchange.IContextExchange)
+	if !ok {
+		return exch.GetTickerPrice(p, assetType)
+	}
+
+	tick, err := ticker.GetTicker(exch.GetName(), p, assetType)
+	if err != nil {
+		return c.UpdateTickerContext(ctx, p, assetType)
+	}
+	return tick, nil
+}
+
+// SubmitExchangeOrder submits an order to the named exchange. Orders are
+// validated against the cached symbol rules and balances before submission,
+// returning an *orders.PreflightError if they would be rejected, and an
+// *orders.ThrottleError is returned when the order throttle is exceeded. An
+// *orders.MaintenanceError is returned while the exchange is in one of its
+// maintenance windows. Orders are tracked by client order ID so retrying with the same client order
+// ID does not place a duplicate order
+func SubmitExchangeOrder(exchangeName string, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	return submitExchangeOrder(context.Background(), "", exchangeName, p, side, orderType, amount, price, clientID)
+}
+
+// SubmitExchangeOrderContext submits an order as in SubmitExchangeOrder with
+// the submission request made under the call context. An order abandoned
+// after it was sent is kept as unknown until it is reconciled
+func SubmitExchangeOrderContext(ctx context.Context, exchangeName string, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	return submitExchangeOrder(ctx, "", exchangeName, p, side, orderType, amount, price, clientID)
+}
+
+// submitExchangeOrder validates, throttles and submits an order, the strategy
+// ID is blank for orders not placed by a strategy
+func submitExchangeOrder(ctx context.Context, strategyID, exchangeName string, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return 0, ErrExchangeNotFound
+	}
+
+	if !exch.GetAPIPermissions().Has(exchange.APIPermissionTrade) {
+		return 0, ErrTradePermissionDenied
+	}
+
+	err := ctx.Err()
+	if err != nil {
+		return 0, err
+	}
+
+	err = orders.CheckMaintenance(exchangeName, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	preflight := orders.PreflightOrder{
+		Exchange: exchangeName,
+		Pair:     p,
+		Side:     string(side),
+		Amount:   amount,
+		Price:    price,
+	}
+	if orderType == exchange.OrderTypeMarket() {
+		preflight.Price = 0
+	}
+
+	err = orders.ValidateOrder(preflight, GetExchangeBalance)
+	if err != nil {
+		return 0, err
+	}
+
+	err = orders.Throttle(exchangeName,
+		p.FirstCurrency.Upper().String()+p.SecondCurrency.Upper().String(),
+		strategyID)
+	if err != nil {
+		return 0, err
+	}
+
+	return orders.SubmitWithClientID(exchangeName, clientID, func(clientID string) (int64, error) {
+		if c, ok := exch.(exchange.IContextExchange); ok {
+			return c.SubmitExchangeOrderContext(ctx, p, side, orderType, amount, price, clientID)
+		}
+		return exch.SubmitExchangeOrder(p, side, orderType, amount, price, clientID)
+	})
+}
+
+// SubmitStrategyExchangeOrder submits an order on behalf of a strategy as in
+// SubmitExchangeOrder. The order is checked against the risk limits of the
+// strategy, returning an *orders.StrategyLimitError if it would break them,
+// and tagged with the strategy so its fills and profit and loss are
+// attributed to the strategy
+func SubmitStrategyExchangeOrder(strategyID, exchangeName string, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	return SubmitStrategyExchangeOrderContext(context.Background(), strategyID, exchangeName, p, side, orderType, amount, price, clientID)
+}
+
+// SubmitStrategyExchangeOrderContext submits an order on behalf of a strategy
+// as in SubmitStrategyExchangeOrder with the submission request made under the
+// context of the strategy, so stopping the strategy abandons the order
+func SubmitStrategyExchangeOrderContext(ctx context.Context, strategyID, exchangeName string, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	if strategyID == "" {
+		return 0, orders.ErrStrategyIDRequired
+	}
+
+	limitPrice := price
+	if orderType == exchange.OrderTypeMarket() {
+		limitPrice = 0
+	}
+
+	err := orders.CheckStrategyLimits(strategyID,
+		exchangeName,
+		p.FirstCurrency.Upper().String()+p.SecondCurrency.Upper().String(),
+		string(side),
+		amount,
+		limitPrice)
+	if err != nil {
+		if limitErr, ok := err.(*orders.StrategyLimitError); ok && limitErr.Reason == orders.StrategyLimitLoss {
+			go ShutdownStrategy(strategyID, "loss limit tripped",
+				time.Now().Add(bot.config.Shutdown.StrategyTimeout))
+		}
+		return 0, err
+	}
+
+	orderID, err := submitExchangeOrder(ctx, strategyID, exchangeName, p, side, orderType, amount, price, clientID)
+	if err != nil {
+		return 0, err
+	}
+	return orderID, orders.TagOrder(exchangeName, orderID, strategyID)
+}
+
+// ShutdownStrategy applies the shutdown policy of a strategy once, cancelling
+// its open orders and flattening its positions with market orders until the
+// deadline as configured, and reports the outcome to the log and
+// communication mediums
+func ShutdownStrategy(strategyID, reason string, deadline time.Time) {
+	result, ok := orders.ShutdownStrategy(strategyID, reason, deadline,
+		cancelStrategyOrder, flattenStrategyPosition)
+	if !ok || result.Policy == orders.ShutdownLeave {
+		return
+	}
+
+	message := result.String()
+	log.Print(message)
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "strategy_shutdown",
+			TradeDetails: message,
+		})
+	}
+}
+
+// cancelStrategyOrder cancels an open order of a strategy on its exchange
+// unless the shutdown has run out of time
+func cancelStrategyOrder(ctx context.Context, order orders.ClientOrder) error {
+	exch := GetExchangeByName(order.Exchange)
+	if exch == nil {
+		return ErrExchangeNotFound
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return exch.CancelExchangeOrder(order.OrderID)
+}
+
+// flattenStrategyPosition submits a market order attributed to the strategy
+// closing its position of a pair on an exchange. The order isn't checked
+// against the risk limits of the strategy as it only reduces the position.
+// The order is submitted under the context of the shutdown so it isn't placed
+// once the shutdown has run out of time
+func flattenStrategyPosition(ctx context.Context, strategyID, exchangeName, pairSymbol, side string, amount float64) (int64, error) {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return 0, ErrExchangeNotFound
+	}
+
+	var p pair.CurrencyPair
+	enabled := exch.GetEnabledCurrencies()
+	for x := range enabled {
+		if enabled[x].FirstCurrency.Upper().String()+enabled[x].SecondCurrency.Upper().String() == pairSymbol {
+			p = enabled[x]
+			break
+		}
+	}
+	if p.Empty() {
+		return 0, fmt.Errorf("%s pair %s not enabled", exchangeName, pairSymbol)
+	}
+
+	orderSide := exchange.OrderSideSell()
+	if side == orders.FillBuy {
+		orderSide = exchange.OrderSideBuy()
+	}
+
+	orderID, err := submitExchangeOrder(ctx, strategyID, exchangeName, p, orderSide,
+		exchange.OrderTypeMarket(), amount, 0,
+		fmt.Sprintf("%s-flatten-%d", strategyID, time.Now().UnixNano()))
+	if err != nil {
+		return 0, err
+	}
+	return orderID, orders.TagOrder(exchangeName, orderID, strategyID)
+}
+
+// SubmitExchangeOrders submits a batch of orders to the named exchange using
+// the exchange's native batch order submission where supported. Each order is
+// validated and tracked by client order ID as in SubmitExchangeOrder, orders
+// which fail validation are not submitted and no orders are submitted while
+// the exchange is under maintenance. Results are returned in the same order
+// as the batch
+func SubmitExchangeOrders(exchangeName string, batch []exchange.BatchOrder) ([]exchange.BatchOrderResult, error) {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+
+	if !exch.GetAPIPermissions().Has(exchange.APIPermissionTrade) {
+		return nil, ErrTradePermissionDenied
+	}
+
+	err := orders.CheckMaintenance(exchangeName, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]exchange.BatchOrderResult, len(batch))
+	var valid []int
+	var clientIDs []string
+	for x := range batch {
+		results[x].ClientID = batch[x].ClientID
+
+		preflight := orders.PreflightOrder{
+			Exchange: exchangeName,
+			Pair:     batch[x].Pair,
+			Side:     string(batch[x].Side),
+			Amount:   batch[x].Amount,
+			Price:    batch[x].Price,
+		}
+		if batch[x].OrderType == exchange.OrderTypeMarket() {
+			preflight.Price = 0
+		}
+
+		err := orders.ValidateOrder(preflight, GetExchangeBalance)
+		if err != nil {
+			results[x].Err = err
+			continue
+		}
+
+		err = orders.Throttle(exchangeName,
+			batch[x].Pair.FirstCurrency.Upper().String()+batch[x].Pair.SecondCurrency.Upper().String(),
+			"")
+		if err != nil {
+			results[x].Err = err
+			continue
+		}
+		valid = append(valid, x)
+		clientIDs = append(clientIDs, batch[x].ClientID)
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	orderIDs, errs := orders.SubmitBatchWithClientIDs(exchangeName, clientIDs,
+		func(submitIDs []string) ([]int64, []error) {
+			var submit []exchange.BatchOrder
+			for _, clientID := range submitIDs {
+				for _, x := range valid {
+					if batch[x].ClientID == clientID {
+						submit = append(submit, batch[x])
+						break
+					}
+				}
+			}
+
+			submitted := exch.SubmitExchangeOrders(submit)
+			ids := make([]int64, len(submitted))
+			errs := make([]error, len(submitted))
+			for x := range submitted {
+				ids[x] = submitted[x].OrderID
+				errs[x] = submitted[x].Err
+			}
+			return ids, errs
+		})
+
+	for x, i := range valid {
+		results[i].OrderID = orderIDs[x]
+		results[i].Err = errs[x]
+	}
+	return results, nil
+}
+
+// CancelExchangeOrders cancels a batch of orders on the named exchange using
+// the exchange's native batch order cancellation where supported
+func CancelExchangeOrders(exchangeName string, orderIDs []int64) ([]exchange.BatchCancelResult, error) {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	return exch.CancelExchangeOrders(orderIDs), nil
+}
+
+// WithdrawCryptoExchangeFunds withdraws cryptocurrency from the named exchange
+// to an address registered in the withdrawal address book, refusing addresses
+// which aren't registered or amounts above the address limit unless override
+// is set. Amounts below the minimum of the address's chain, or of the default
+// chain for an unregistered address, and chains with withdrawals disabled are
+// always refused
+func WithdrawCryptoExchangeFunds(exchangeName, address string, cryptocurrency pair.CurrencyItem, amount float64, override bool) (string, error) {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return "", ErrExchangeNotFound
+	}
+
+	if !exch.GetAPIPermissions().Has(exchange.APIPermissionWithdraw) {
+		return "", ErrWithdrawPermissionDenied
+	}
+
+	entry, err := portfolio.CheckWithdrawalAddress(exchangeName, address,
+		cryptocurrency.String(), amount)
+	if err != nil {
+		if !override {
+			return "", err
+		}
+		log.Printf("Withdrawal address book check overridden for %v %s to %s on %s: %s\n",
+			amount, cryptocurrency.String(), address, exchangeName, err)
+	} else {
+		log.Printf("Withdrawing %v %s to %s (%s) on %s.\n",
+			amount, cryptocurrency.String(), entry.Label, address, exchangeName)
+	}
+
+	err = exch.CheckWithdrawal(cryptocurrency.String(), entry.Chain, amount)
+	if err != nil {
+		return "", err
+	}
+
+	reservation, err := reserveWithdrawal(exchangeName, cryptocurrency.String(), amount)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := exch.WithdrawCryptoExchangeFunds(address, cryptocurrency, amount)
+	if err != nil {
+		releaseWithdrawal(reservation, err)
+		return id, err
+	}
+	return id, nil
+}
+
+// reserveWithdrawal reserves a withdrawal against the withdrawal limits of the
+// exchange, notifying the communication mediums when a limit is exceeded
+func reserveWithdrawal(exchangeName, currency string, amount float64) (int64, error) {
+	reservation, err := portfolio.ReserveWithdrawal(exchangeName, currency, amount)
+	if err != nil && bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "withdrawal_limit_exceeded",
+			TradeDetails: err.Error(),
+		})
+	}
+	return reservation, err
+}
+
+// releaseWithdrawal releases the reservation of a failed withdrawal unless the
+// error leaves its outcome unknown, as the exchange may have processed it
+func releaseWithdrawal(reservation int64, err error) {
+	if orders.IsIndeterminate(err) {
+		return
+	}
+	portfolio.ReleaseWithdrawal(reservation)
+}
+
+// WithdrawFiatExchangeFunds withdraws fiat from the named exchange to the
+// client bank account configured for the currency, subject to the withdrawal
+// limits. The reference returned by the exchange is registered so the bank
+// transfer is tracked until it is sent
+func WithdrawFiatExchangeFunds(exchangeName string, currency pair.CurrencyItem, amount float64) (string, error) {
+	return withdrawFiat(exchangeName, currency, amount, func(exch exchange.IBotExchange) (string, error) {
+		return exch.WithdrawFiatExchangeFunds(currency, amount)
+	})
+}
+
+// WithdrawFiatExchangeFundsToInternationalBank withdraws fiat from the named
+// exchange to the bank account of the details by an international transfer,
+// subject to the withdrawal limits and the fiat withdrawals the exchange
+// supports
+func WithdrawFiatExchangeFundsToInternationalBank(exchangeName string, currency pair.CurrencyItem, amount float64, details exchange.BankDetails) (string, error) {
+	return withdrawFiat(exchangeName, currency, amount, func(exch exchange.IBotExchange) (string, error) {
+		if !exch.SupportsFiatWithdrawal(exchange.FiatWithdrawalSWIFT, currency.String()) {
+			return "", exchange.ErrFiatWithdrawalNotSupported
+		}
+
+		err := details.Validate(exchange.FiatWithdrawalSWIFT)
+		if err != nil {
+			return "", err
+		}
+		return exch.WithdrawFiatExchangeFundsToInternationalBank(currency, amount, details)
+	})
+}
+
+// withdrawFiat checks the withdraw permission of a fiat withdrawal from the
+// named exchange and reserves it against the withdrawal limits before
+// submitting it with withdraw, registering its reference so the bank transfer
+// is tracked
+func withdrawFiat(exchangeName string, currency pair.CurrencyItem, amount float64, withdraw func(exch exchange.IBotExchange) (string, error)) (string, error) {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return "", ErrExchangeNotFound
+	}
+
+	if !exch.GetAPIPermissions().Has(exchange.APIPermissionWithdraw) {
+		return "", ErrWithdrawPermissionDenied
+	}
+
+	reservation, err := reserveWithdrawal(exchangeName, currency.String(), amount)
+	if err != nil {
+		return "", err
+	}
+
+	reference, err := withdraw(exch)
+	if err != nil {
+		releaseWithdrawal(reservation, err)
+		return reference, err
+	}
+
+	log.Printf("Withdrawing %v %s to bank from %s, reference %s.\n", amount,
+		currency.String(), exchangeName, reference)
+
+	_, err = exchange.AddFiatTransfer(exchange.FiatTransfer{
+		Exchange:  exchangeName,
+		Reference: reference,
+		Currency:  currency.String(),
+		Amount:    amount,
+	})
+	if err != nil {
+		log.Printf("Failed to register %s fiat transfer %s. Error: %s",
+			exchangeName, reference, err)
+	}
+	return reference, nil
+}
+
+// ExecuteWithdrawalRequest submits an approved withdrawal request to its
+// exchange through the address book and withdrawal limit checks, recording
+// the outcome against the request
+func ExecuteWithdrawalRequest(req portfolio.WithdrawalRequest) (portfolio.WithdrawalRequest, error) {
+	id, err := WithdrawCryptoExchangeFunds(req.Exchange, req.Address,
+		pair.CurrencyItem(common.StringToUpper(req.Currency)), req.Amount, false)
+	if err != nil {
+		log.Printf("Withdrawal request %d failed: %s\n", req.ID, err)
+	}
+	return portfolio.SetWithdrawalRequestResult(req.ID, id, err)
+}
+
+// ExchangeFee holds a fee of an exchange and whether it was estimated from
+// the exchange's static fee table as it has no API credentials
+type ExchangeFee struct {
+	Fee      float64 `json:"fee"`
+	Estimate bool    `json:"estimate"`
+}
+
+// GetExchangeFee returns a fee of an exchange, flagging fees estimated from
+// the exchange's static fee table
+func GetExchangeFee(exchName string, feeBuilder exchange.FeeBuilder) (ExchangeFee, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return ExchangeFee{}, ErrExchangeNotFound
+	}
+
+	fee, err := exch.GetFeeByType(feeBuilder)
+	if err != nil {
+		return ExchangeFee{}, err
+	}
+	return ExchangeFee{Fee: fee, Estimate: exch.IsFeeEstimated(feeBuilder)}, nil
+}
+
+// WithdrawalFeeQuote holds the cost of withdrawing a currency from an
+// exchange. Minimum is zero when the exchange's minimum is unknown,
+// Processing is the automation of the exchange's most automated withdrawal
+// method for the currency and Chains holds the minimum, fee and deposit
+// confirmations of each chain the currency can be withdrawn on
+type WithdrawalFeeQuote struct {
+	Exchange   string                      `json:"exchange"`
+	Currency   string                      `json:"currency"`
+	Balance    float64                     `json:"balance"`
+	Fee        float64                     `json:"fee"`
+	Estimate   bool                        `json:"estimate"`
+	Minimum    float64                     `json:"minimum,omitempty"`
+	Sufficient bool                        `json:"sufficient"`
+	Processing string                      `json:"processing"`
+	Methods    []exchange.WithdrawalMethod `json:"methods"`
+	Chains     []exchange.AssetMetadata    `json:"chains,omitempty"`
+	Error      string                      `json:"error,omitempty"`
+}
+
+// CompareWithdrawalFees returns the cost of withdrawing an amount of a
+// currency from each enabled exchange holding it, cheapest first. Sufficient
+// is set when the amount meets the minimum, withdrawals on the default chain
+// are enabled and the balance covers the amount and fee, quotes whose fee
+// couldn't be retrieved are sorted last
+func CompareWithdrawalFees(currencyCode string, amount float64) []WithdrawalFeeQuote {
+	currencyCode = common.StringToUpper(currencyCode)
+	fiat := currency.IsFiatCurrency(currencyCode)
+	feeBuilder := exchange.FeeBuilder{
+		FeeType:       exchange.CryptocurrencyWithdrawalFee,
+		FirstCurrency: currencyCode,
+		Amount:        amount,
+	}
+	asset := exchange.WithdrawalAssetCrypto
+	if fiat {
+		feeBuilder.FeeType = exchange.InternationalBankWithdrawalFee
+		feeBuilder.CurrencyItem = currencyCode
+		asset = exchange.WithdrawalAssetFiat
+	}
+
+	quotes := []WithdrawalFeeQuote{}
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil || !bot.exchanges[x].IsEnabled() {
+			continue
+		}
+
+		exchName := bot.exchanges[x].GetName()
+		balance, ok := GetExchangeBalance(exchName, currencyCode)
+		if !ok || balance <= 0 {
+			continue
+		}
+
+		quote := WithdrawalFeeQuote{
+			Exchange:   exchName,
+			Currency:   currencyCode,
+			Balance:    balance,
+			Processing: exchange.WithdrawalAutomationWebsiteOnly,
+			Methods:    []exchange.WithdrawalMethod{},
+		}
+		for _, method := range bot.exchanges[x].GetWithdrawalMethods() {
+			if method.Asset == asset {
+				quote.Methods = append(quote.Methods, method)
+			}
+		}
+		if len(quote.Methods) > 0 {
+			quote.Processing = quote.Methods[0].Automation
+		}
+
+		quote.Minimum, _ = bot.exchanges[x].GetWithdrawalMinimum(currencyCode)
+		if !fiat {
+			quote.Chains = bot.exchanges[x].GetAssetMetadata(currencyCode)
+		}
+		fee, err := bot.exchanges[x].GetFeeByType(feeBuilder)
+		if err != nil {
+			quote.Error = err.Error()
+		} else {
+			quote.Fee = fee
+			quote.Estimate = bot.exchanges[x].IsFeeEstimated(feeBuilder)
+			quote.Sufficient = amount >= quote.Minimum && balance >= amount+fee
+			if !fiat && bot.exchanges[x].CheckWithdrawal(currencyCode, "", amount) != nil {
+				quote.Sufficient = false
+			}
+		}
+		quotes = append(quotes, quote)
+	}
+
+	sort.SliceStable(quotes, func(i, j int) bool {
+		if (quotes[i].Error == "") != (quotes[j].Error == "") {
+			return quotes[i].Error == ""
+		}
+		return quotes[i].Fee < quotes[j].Fee
+	})
+	return quotes
+}
+
+// WithdrawalPreview holds the outcome of a withdrawal dry run. NetworkFee is
+// the withdrawal fee of the chain from the asset metadata and ExchangeFee the
+// fee quoted by the exchange, from its API where available or estimated from
+// its fee table. Fee is the fee expected to be deducted, the chain fee when
+// known as exchange quotes aren't chain specific, and Receive the amount
+// expected to arrive. Errors lists the checks the withdrawal would fail,
+// Allowed being set when there are none
+type WithdrawalPreview struct {
+	Exchange       string        `json:"exchange"`
+	Currency       string        `json:"currency"`
+	Amount         float64       `json:"amount"`
+	Address        string        `json:"address,omitempty"`
+	Label          string        `json:"label,omitempty"`
+	Chain          string        `json:"chain,omitempty"`
+	Minimum        float64       `json:"minimum,omitempty"`
+	NetworkFee     float64       `json:"networkFee"`
+	ExchangeFee    float64       `json:"exchangeFee"`
+	Estimate       bool          `json:"estimate"`
+	Fee            float64       `json:"fee"`
+	Receive        float64       `json:"receive"`
+	Confirmations  int64         `json:"confirmations,omitempty"`
+	ProcessingTime time.Duration `json:"processingTime,omitempty"`
+	Processing     string        `json:"processing"`
+	Allowed        bool          `json:"allowed"`
+	Errors         []string      `json:"errors,omitempty"`
+}
+
+// PreviewWithdrawal dry runs a withdrawal of an amount of a currency from the
+// named exchange to an address, or to the client bank account for fiat,
+// returning its fees, the amount expected to be received and how long it is
+// expected to take without submitting it. The withdraw permission, address
+// book, chain metadata and withdrawal limit checks of a withdrawal are run
+// and their failures listed on the preview
+func PreviewWithdrawal(exchangeName, currencyCode, address string, amount float64) (WithdrawalPreview, error) {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return WithdrawalPreview{}, ErrExchangeNotFound
+	}
+
+	currencyCode = common.StringToUpper(currencyCode)
+	fiat := currency.IsFiatCurrency(currencyCode)
+	preview := WithdrawalPreview{
+		Exchange:   exch.GetName(),
+		Currency:   currencyCode,
+		Amount:     amount,
+		Processing: exchange.WithdrawalAutomationWebsiteOnly,
+	}
+
+	var failures []error
+	if !exch.GetAPIPermissions().Has(exchange.APIPermissionWithdraw) {
+		failures = append(failures, ErrWithdrawPermissionDenied)
+	}
+
+	feeBuilder := exchange.FeeBuilder{
+		FeeType:       exchange.CryptocurrencyWithdrawalFee,
+		FirstCurrency: currencyCode,
+		Amount:        amount,
+	}
+	asset := exchange.WithdrawalAssetCrypto
+	if fiat {
+		feeBuilder.FeeType = exchange.InternationalBankWithdrawalFee
+		feeBuilder.CurrencyItem = currencyCode
+		asset = exchange.WithdrawalAssetFiat
+	} else {
+		preview.Address = address
+		entry, err := portfolio.CheckWithdrawalAddress(exchangeName, address, currencyCode, amount)
+		if err != nil {
+			failures = append(failures, err)
+		}
+		preview.Label, preview.Chain = entry.Label, entry.Chain
+
+		metadata, err := exch.GetChainMetadata(currencyCode, entry.Chain)
+		if err == nil {
+			preview.Chain = metadata.Chain
+			preview.Minimum = metadata.MinWithdrawal
+			preview.NetworkFee = metadata.WithdrawalFee
+			preview.Confirmations = metadata.DepositConfirmations
+			preview.ProcessingTime = metadata.ProcessingTime
+		}
+
+		err = exch.CheckWithdrawal(currencyCode, entry.Chain, amount)
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+
+	if minimum, ok := exch.GetWithdrawalMinimum(currencyCode); ok && minimum > preview.Minimum {
+		preview.Minimum = minimum
+		if amount < minimum {
+			failures = append(failures, fmt.Errorf("withdrawal of %v %s is below the exchange minimum of %v",
+				amount, currencyCode, minimum))
+		}
+	}
+
+	for _, method := range exch.GetWithdrawalMethods() {
+		if method.Asset == asset {
+			preview.Processing = method.Automation
+			break
+		}
+	}
+
+	err := portfolio.CheckWithdrawalLimits(exchangeName, currencyCode, amount)
+	if err != nil {
+		failures = append(failures, err)
+	}
+
+	fee, err := exch.GetFeeByType(feeBuilder)
+	if err != nil {
+		failures = append(failures, err)
+	} else {
+		preview.ExchangeFee = fee
+		preview.Estimate = exch.IsFeeEstimated(feeBuilder)
+	}
+
+	preview.Fee = preview.ExchangeFee
+	if preview.NetworkFee > 0 {
+		preview.Fee = preview.NetworkFee
+	}
+	preview.Receive = amount - preview.Fee
+	if preview.Receive <= 0 {
+		preview.Receive = 0
+		failures = append(failures, fmt.Errorf("withdrawal fee of %v %s exceeds the amount",
+			preview.Fee, currencyCode))
+	}
+
+	for x := range failures {
+		preview.Errors = append(preview.Errors, failures[x].Error())
+	}
+	preview.Allowed = len(preview.Errors) == 0
+	return preview, nil
+}
+
+// RecordFeeTokenUsage deducts the fee token amount paid on an exchange from
+// its tracked token balance, emitting a fee_token_depleted event once the
+// balance runs out as fees are then charged at the full rate
+func RecordFeeTokenUsage(exchangeName string, amount float64) error {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return ErrExchangeNotFound
+	}
+
+	discount, err := exch.GetFeeDiscount()
+	if err != nil {
+		return err
+	}
+
+	remaining, err := exch.DepleteFeeToken(amount)
+	if err != nil {
+		return err
+	}
+
+	if remaining == 0 && discount.Balance > 0 {
+		message := fmt.Sprintf("%s %s fee token balance depleted, fees will be charged at the full rate",
+			exchangeName, discount.Token)
+		log.Println(message)
+		if bot.comms != nil {
+			bot.comms.PushEvent(base.Event{
+				Type:         "fee_token_depleted",
+				TradeDetails: message,
+			})
+		}
+	}
+	return nil
+}
+
+// GetExchangeBalance returns the cached portfolio balance of a currency on an
+// exchange and whether the balance is known
+func GetExchangeBalance(exchangeName, currency string) (float64, bool) {
+	return portfolio.GetPortfolio().GetAddressBalance(exchangeName, currency,
+		portfolio.PortfolioAddressExchange)
+}
+
+// SubmitStampedExchangeOrder submits an order to the named exchange after
+// verifying the market data the decision was based on is for the same pair and
+// is not stale
+func SubmitStampedExchangeOrder(stamp orders.DataStamp, exchangeName string, p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (int64, error) {
+	err := orders.VerifyDataStamp(exchangeName, p, stamp)
+	if err != nil {
+		return 0, err
+	}
+	return SubmitExchangeOrder(exchangeName, p, side, orderType, amount, price, clientID)
+}
+
+// CancelExchangeOrderByClientID cancels an order previously submitted to the
+// named exchange by its client order ID. Exchanges which support cancellation
+// by client order ID are used directly so orders submitted before a restart can
+// be cancelled, otherwise the exchange order ID is looked up from the client
+// order registry
+func CancelExchangeOrderByClientID(exchangeName, clientID string) error {
+	if clientID == "" {
+		return orders.ErrClientIDRequired
+	}
+
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return ErrExchangeNotFound
+	}
+
+	canceller, ok := exch.(exchange.IClientIDCanceller)
+	if !ok {
+		return orders.CancelByClientID(exchangeName, clientID, exch.CancelExchangeOrder)
+	}
+
+	err := canceller.CancelOrderByClientID(clientID)
+	if err != nil {
+		return err
+	}
+
+	orders.MarkClientOrderCancelled(exchangeName, clientID)
+	return nil
+}
+
 // GetCollatedExchangeAccountInfoByCoin collates individual exchange account
 // information and turns into into a map string of
 // exchange.AccountCurrencyInfo
@@ -299,19 +1185,15 @@ func GetCollatedExchangeAccountInfoByCoin(accounts []exchange.AccountInfo) map[s
 	result := make(map[string]exchange.AccountCurrencyInfo)
 	for i := 0; i < len(accounts); i++ {
 		for j := 0; j < len(accounts[i].Currencies); j++ {
-			currencyName := accounts[i].Currencies[j].CurrencyName
-			avail := accounts[i].Currencies[j].TotalValue
-			onHold := accounts[i].Currencies[j].Hold
-
-			info, ok := result[currencyName]
+			currency := accounts[i].Currencies[j]
+			info, ok := result[currency.CurrencyName]
 			if !ok {
-				accountInfo := exchange.AccountCurrencyInfo{CurrencyName: currencyName, Hold: onHold, TotalValue: avail}
-				result[currencyName] = accountInfo
-			} else {
-				info.Hold += onHold
-				info.TotalValue += avail
-				result[currencyName] = info
+				info = exchange.AccountCurrencyInfo{CurrencyName: currency.CurrencyName}
 			}
+			info.Free += currency.Free
+			info.Locked += currency.Locked
+			info.Total += currency.Total
+			result[currency.CurrencyName] = info
 		}
 	}
 	return result
@@ -349,6 +1231,344 @@ func GetExchangeLowestPriceByCurrencyPair(p pair.CurrencyPair, assetType string)
 	return result[0].Exchange, nil
 }
 
+// getLastPrice returns the last spot price of a currency pair from the first
+// enabled exchange with a cached ticker, falling back to the reference price
+// from the market data providers
+func getLastPrice(first, second string) (float64, bool) {
+	p := pair.NewCurrencyPair(first, second)
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil || !bot.exchanges[x].IsEnabled() {
+			continue
+		}
+
+		tick, err := ticker.GetTicker(bot.exchanges[x].GetName(), p, ticker.Spot)
+		if err != nil || tick.Last <= 0 {
+			continue
+		}
+		return tick.Last, true
+	}
+	return marketdata.GetReferencePrice(first, second)
+}
+
+// convertFiatCurrency converts an amount between two fiat currencies using
+// the forex providers
+func convertFiatCurrency(amount float64, from, to string) (float64, error) {
+	if !currency.IsFiatCurrency(from) || !currency.IsFiatCurrency(to) {
+		return 0, fmt.Errorf("unable to convert %s to %s, forex requires fiat currencies",
+			from, to)
+	}
+	return currency.ConvertCurrency(amount, from, to)
+}
+
+// GetPortfolioValuation returns the portfolio holdings valued in the
+// configured valuation currency using cached tickers, with forex and cross
+// rate fallbacks where no direct pair is available
+func GetPortfolioValuation(p *portfolio.Base) portfolio.Valuation {
+	valuer := portfolio.NewValuer(bot.config.Currency.ValuationCurrency,
+		getLastPrice,
+		convertFiatCurrency)
+	return p.GetValuation(valuer)
+}
+
+// getExchangeHoldings returns the exchange balances of the portfolio, the
+// holdings a rebalance is able to trade
+func getExchangeHoldings() portfolio.Base {
+	var holdings portfolio.Base
+	port := portfolio.GetPortfolio()
+	for x := range port.Addresses {
+		if port.Addresses[x].Description == portfolio.PortfolioAddressExchange {
+			holdings.Addresses = append(holdings.Addresses, port.Addresses[x])
+		}
+	}
+	return holdings
+}
+
+// routeRebalanceTrade returns a router for rebalance trades against the base
+// currency, routing each trade to the enabled exchange with the best all in
+// price from its cached ticker and trading fee. Exchanges with a balance too
+// small to fund the trade are skipped
+func routeRebalanceTrade(baseCurrency string) portfolio.RebalanceRouter {
+	return func(currencyCode, side string, amount float64) (orders.Route, error) {
+		p := pair.NewCurrencyPair(currencyCode, baseCurrency)
+		var venues []orders.Venue
+		for x := range bot.exchanges {
+			exch := bot.exchanges[x]
+			if exch == nil || !exch.IsEnabled() || !pair.Contains(exch.GetEnabledCurrencies(), p, true) {
+				continue
+			}
+
+			tick, err := ticker.GetTicker(exch.GetName(), p, ticker.Spot)
+			if err != nil {
+				continue
+			}
+
+			price, funding, required := tick.Ask, baseCurrency, amount*tick.Ask
+			if side == orders.FillSell {
+				price, funding, required = tick.Bid, currencyCode, amount
+			}
+			if price <= 0 {
+				continue
+			}
+
+			balance, ok := GetExchangeBalance(exch.GetName(), funding)
+			if !ok || balance < required {
+				continue
+			}
+
+			venue := orders.Venue{Exchange: exch.GetName(), Pair: p, Bid: tick.Bid, Ask: tick.Ask}
+			fee, err := exch.GetFeeByType(exchange.FeeBuilder{
+				FeeType:        exchange.CryptocurrencyTradeFee,
+				FirstCurrency:  currencyCode,
+				SecondCurrency: baseCurrency,
+				PurchasePrice:  price,
+				Amount:         amount,
+			})
+			if err == nil {
+				venue.FeeRate = fee / (amount * price)
+			}
+
+			book, err := orderbook.GetOrderbook(exch.GetName(), p, ticker.Spot)
+			if err == nil {
+				venue.Slippage = orders.ExpectedSlippage(side, amount, book)
+			}
+			venues = append(venues, venue)
+		}
+		return orders.RouteOrder(side, amount, venues)
+	}
+}
+
+// executeRebalancePlan submits the routed trades of a rebalance plan as
+// market orders attributed to the rebalance strategy, recording the order ID
+// or error of each trade. Trades are submitted under the context of the
+// rebalance routine
+func executeRebalancePlan(ctx context.Context, plan *portfolio.RebalancePlan) {
+	for x := range plan.Trades {
+		trade := &plan.Trades[x]
+		if trade.Error != "" {
+			continue
+		}
+
+		side := exchange.OrderSideSell()
+		if trade.Side == orders.FillBuy {
+			side = exchange.OrderSideBuy()
+		}
+
+		orderID, err := SubmitStrategyExchangeOrderContext(ctx, rebalanceStrategyID,
+			trade.Route.Venue.Exchange, trade.Route.Venue.Pair, side,
+			exchange.OrderTypeMarket(), trade.Amount, 0, "")
+		if err != nil {
+			trade.Error = err.Error()
+			continue
+		}
+		trade.OrderID = orderID
+	}
+}
+
+// formatRebalancePlan returns a report of the drift and trades of a rebalance
+// plan
+func formatRebalancePlan(plan portfolio.RebalancePlan) string {
+	mode := ""
+	if plan.DryRun {
+		mode = " (dry run)"
+	}
+
+	report := fmt.Sprintf("%s rebalance%s of %.2f %s, max drift %.2f%%, estimated cost %.2f %s\n",
+		common.StringToLower(plan.Trigger), mode, plan.Total, plan.BaseCurrency,
+		plan.MaxDrift*100, plan.EstimatedCost, plan.BaseCurrency)
+	for _, a := range plan.Assets {
+		report += fmt.Sprintf("%s weight %.2f%% target %.2f%% drift %+.2f%%\n",
+			a.Currency, a.Weight*100, a.Target*100, a.Drift*100)
+	}
+	for _, t := range plan.Trades {
+		report += fmt.Sprintf("%s %f %s worth %.2f %s", t.Side, t.Amount, t.Currency,
+			t.Value, plan.BaseCurrency)
+		if t.Error != "" {
+			report += " failed: " + t.Error + "\n"
+			continue
+		}
+		report += fmt.Sprintf(" on %s at %f, estimated cost %.2f\n", t.Route.Venue.Exchange,
+			t.Route.Price, t.Route.Cost)
+	}
+	if len(plan.Unpriced) > 0 {
+		report += fmt.Sprintf("Unpriced currencies: %s\n", common.JoinStrings(plan.Unpriced, ", "))
+	}
+	return report
+}
+
+// getArbitrageVenue returns the top of book and taker fee rate of a pair on an
+// exchange from its cached ticker, the slippage expected by a buy or sell of
+// amount from its cached orderbook, with the inventory of both currencies held
+// on the exchange, and whether the ticker is available
+func getArbitrageVenue(exchangeName string, p pair.CurrencyPair, amount float64) (orders.ArbitrageVenue, bool) {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil || !exch.IsEnabled() {
+		return orders.ArbitrageVenue{}, false
+	}
+
+	tick, err := ticker.GetTicker(exch.GetName(), p, ticker.Spot)
+	if err != nil || tick.Bid <= 0 || tick.Ask <= 0 {
+		return orders.ArbitrageVenue{}, false
+	}
+
+	venue := orders.ArbitrageVenue{
+		Venue: orders.Venue{Exchange: exch.GetName(), Pair: p, Bid: tick.Bid, Ask: tick.Ask},
+	}
+	venue.BaseBalance, _ = GetExchangeBalance(exch.GetName(), p.FirstCurrency.String())
+	venue.QuoteBalance, _ = GetExchangeBalance(exch.GetName(), p.SecondCurrency.String())
+
+	fee, err := exch.GetFeeByType(exchange.FeeBuilder{
+		FeeType:        exchange.CryptocurrencyTradeFee,
+		FirstCurrency:  p.FirstCurrency.String(),
+		SecondCurrency: p.SecondCurrency.String(),
+		PurchasePrice:  tick.Ask,
+		Amount:         1,
+	})
+	if err == nil {
+		venue.FeeRate = fee / tick.Ask
+	}
+
+	book, err := orderbook.GetOrderbook(exch.GetName(), p, ticker.Spot)
+	if err == nil {
+		venue.BuySlippage = orders.ExpectedSlippage(orders.FillBuy, amount, book)
+		venue.SellSlippage = orders.ExpectedSlippage(orders.FillSell, amount, book)
+	}
+	return venue, true
+}
+
+// executeInventoryTransfer withdraws a planned inventory transfer to the
+// deposit address of the receiving exchange, through the address book and
+// withdrawal limit checks. Fiat inventory has to be transferred manually
+func executeInventoryTransfer(transfer portfolio.InventoryTransfer) (string, error) {
+	if currency.IsFiatCurrency(transfer.Currency) {
+		return "", fmt.Errorf("fiat %s inventory must be transferred manually", transfer.Currency)
+	}
+
+	to := GetExchangeByName(transfer.To)
+	if to == nil {
+		return "", ErrExchangeNotFound
+	}
+
+	address, err := to.GetExchangeDepositAddress(pair.CurrencyItem(transfer.Currency))
+	if err != nil {
+		return "", err
+	}
+	return WithdrawCryptoExchangeFunds(transfer.From, address,
+		pair.CurrencyItem(transfer.Currency), transfer.Amount, false)
+}
+
+// getStrategyPluginEvents returns the ticker updates of the pairs of an
+// external strategy since those last delivered, tracked by updated, and the
+// fills of its orders since fillsSince, which is moved on to the last fill
+func getStrategyPluginEvents(cfg config.StrategyPluginConfig, updated map[string]time.Time, fillsSince *time.Time) []strategy.Event {
+	var events []strategy.Event
+	pairs := make(map[string]string)
+	for _, exchName := range cfg.Exchanges {
+		exch := GetExchangeByName(exchName)
+		if exch == nil || !exch.IsEnabled() {
+			continue
+		}
+
+		for _, pairName := range cfg.Pairs {
+			p := pair.NewCurrencyPairDelimiter(pairName, "-")
+			pairs[p.FirstCurrency.Upper().String()+p.SecondCurrency.Upper().String()] = pairName
+
+			tick, err := ticker.GetTicker(exch.GetName(), p, ticker.Spot)
+			if err != nil {
+				continue
+			}
+
+			key := exch.GetName() + " " + pairName
+			if !tick.LastUpdated.After(updated[key]) {
+				continue
+			}
+			updated[key] = tick.LastUpdated
+
+			events = append(events, strategy.Event{
+				Type: strategy.EventTicker,
+				Ticker: &strategy.Ticker{
+					Exchange:  exch.GetName(),
+					Pair:      pairName,
+					AssetType: ticker.Spot,
+					Timestamp: tick.LastUpdated,
+					Last:      tick.Last,
+					Bid:       tick.Bid,
+					Ask:       tick.Ask,
+					Volume:    tick.Volume,
+				},
+			})
+		}
+	}
+
+	for _, f := range orders.GetFills("", "", time.Time{}) {
+		if f.StrategyID != cfg.Name || !f.Timestamp.After(*fillsSince) {
+			continue
+		}
+		*fillsSince = f.Timestamp
+
+		pairName, ok := pairs[common.StringToUpper(common.ReplaceString(f.Pair, "-", "", -1))]
+		if !ok {
+			pairName = f.Pair
+		}
+		events = append(events, strategy.Event{
+			Type: strategy.EventFill,
+			Fill: &strategy.Fill{
+				Exchange:  f.Exchange,
+				Pair:      pairName,
+				Side:      f.Side,
+				OrderID:   f.OrderID,
+				ClientID:  f.ClientID,
+				Amount:    f.Amount,
+				Price:     f.Price,
+				Fee:       f.Fee,
+				Timestamp: f.Timestamp,
+			},
+		})
+	}
+	return events
+}
+
+// submitOrderIntent submits an order intent of an external strategy on its
+// behalf, checked against the risk limits of the strategy, returning the
+// exchange order ID. Intents without a client order ID are given one. The
+// order is submitted under the context of the strategy
+func submitOrderIntent(ctx context.Context, strategyID string, intent strategy.OrderIntent) (int64, error) {
+	err := intent.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	if intent.ClientID == "" {
+		intent.ClientID = fmt.Sprintf("%s-%d", strategyID, time.Now().UnixNano())
+	}
+
+	side := exchange.OrderSideBuy()
+	if intent.Side == strategy.SideSell {
+		side = exchange.OrderSideSell()
+	}
+
+	orderType := exchange.OrderTypeMarket()
+	if intent.Type == strategy.TypeLimit {
+		orderType = exchange.OrderTypeLimit()
+	}
+
+	return SubmitStrategyExchangeOrderContext(ctx, strategyID, intent.Exchange,
+		pair.NewCurrencyPairDelimiter(intent.Pair, "-"), side, orderType,
+		intent.Amount, intent.Price, intent.ClientID)
+}
+
+// GetMarketOverview returns the volume and last price of each enabled pair
+// per enabled exchange from the ticker store, with aggregate totals and
+// exchange market share
+func GetMarketOverview() []ticker.MarketOverview {
+	return ticker.GetMarketOverview(ticker.Spot, func(exchangeName string, p pair.CurrencyPair) bool {
+		exch := GetExchangeByName(exchangeName)
+		if exch == nil || !exch.IsEnabled() {
+			return false
+		}
+		return pair.Contains(exch.GetEnabledCurrencies(), p, false)
+	})
+}
+
 // SeedExchangeAccountInfo seeds account info
 func SeedExchangeAccountInfo(data []exchange.AccountInfo) {
 	if len(data) == 0 {
@@ -361,9 +1581,7 @@ func SeedExchangeAccountInfo(data []exchange.AccountInfo) {
 		exchangeName := data[i].ExchangeName
 		for j := 0; j < len(data[i].Currencies); j++ {
 			currencyName := data[i].Currencies[j].CurrencyName
-			onHold := data[i].Currencies[j].Hold
-			avail := data[i].Currencies[j].TotalValue
-			total := onHold + avail
+			total := data[i].Currencies[j].Total
 
 			if !port.ExchangeAddressExists(exchangeName, currencyName) {
 				if total <= 0 {
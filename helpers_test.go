@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
@@ -10,8 +13,11 @@ import (
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
 	"github.com/thrasher-/gocryptotrader/exchanges/stats"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/portfolio"
+	"github.com/thrasher-/gocryptotrader/strategy"
 )
 
 const (
@@ -276,6 +282,32 @@ func TestGetSpecificTicker(t *testing.T) {
 	UnloadExchange("Bitstamp")
 }
 
+func TestGetSpecificTickerContext(t *testing.T) {
+	SetupTestHelpers(t)
+
+	LoadExchange("Binance", false, nil)
+	p := pair.NewCurrencyPair("BTC", "USDT")
+	ticker.ProcessTicker("Binance", p, ticker.Price{Last: 1000}, ticker.Spot)
+
+	tick, err := GetSpecificTickerContext(context.Background(), "BTCUSDT", "Binance", ticker.Spot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tick.Last != 1000 {
+		t.Fatal("Unexpected result")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = GetSpecificTickerContext(ctx, "ETHUSDT", "Binance", ticker.Spot)
+	if err != context.Canceled {
+		t.Fatal("Unexpected result", err)
+	}
+
+	UnloadExchange("Binance")
+}
+
 func TestGetCollatedExchangeAccountInfoByCoin(t *testing.T) {
 	SetupTestHelpers(t)
 
@@ -284,11 +316,11 @@ func TestGetCollatedExchangeAccountInfoByCoin(t *testing.T) {
 
 	info.ExchangeName = "Bitfinex"
 	info.Currencies = append(info.Currencies,
-		exchange.AccountCurrencyInfo{CurrencyName: "BTC", TotalValue: 100, Hold: 0})
+		exchange.NewAccountCurrencyInfo("BTC", 80, 20))
 	exchangeInfo = append(exchangeInfo, info)
 
 	info.ExchangeName = "Bitstamp"
-	info.Currencies = append(info.Currencies, exchange.AccountCurrencyInfo{CurrencyName: "LTC", TotalValue: 100, Hold: 0})
+	info.Currencies = append(info.Currencies, exchange.NewAccountCurrencyInfo("LTC", 100, 0))
 	exchangeInfo = append(exchangeInfo, info)
 
 	result := GetCollatedExchangeAccountInfoByCoin(exchangeInfo)
@@ -301,7 +333,7 @@ func TestGetCollatedExchangeAccountInfoByCoin(t *testing.T) {
 		t.Fatal("Expected currency was not found in result map")
 	}
 
-	if amount.TotalValue != 200 {
+	if amount.Free != 160 || amount.Locked != 40 || amount.Total != 200 {
 		t.Fatal("Unexpected result")
 	}
 
@@ -318,7 +350,7 @@ func TestGetAccountCurrencyInfoByExchangeName(t *testing.T) {
 	var info exchange.AccountInfo
 	info.ExchangeName = "Bitfinex"
 	info.Currencies = append(info.Currencies,
-		exchange.AccountCurrencyInfo{CurrencyName: "BTC", TotalValue: 100, Hold: 0})
+		exchange.NewAccountCurrencyInfo("BTC", 100, 0))
 	exchangeInfo = append(exchangeInfo, info)
 
 	result, err := GetAccountCurrencyInfoByExchangeName(exchangeInfo, "Bitfinex")
@@ -377,3 +409,233 @@ func TestGetExchangeLowestPriceByCurrencyPair(t *testing.T) {
 		log.Fatal("Unexpected reuslt")
 	}
 }
+
+func TestCancelExchangeOrderByClientID(t *testing.T) {
+	SetupTestHelpers(t)
+	LoadExchange("Bitstamp", false, nil)
+
+	err := CancelExchangeOrderByClientID("Bitstamp", "")
+	if err != orders.ErrClientIDRequired {
+		t.Error("Test Failed - CancelExchangeOrderByClientID() blank client ID error", err)
+	}
+
+	err = CancelExchangeOrderByClientID("Unknown", "order-1")
+	if err != ErrExchangeNotFound {
+		t.Error("Test Failed - CancelExchangeOrderByClientID() unknown exchange error", err)
+	}
+
+	// Bitstamp lacks native support so the registry lookup is used instead
+	err = CancelExchangeOrderByClientID("Bitstamp", "order-1")
+	if err != orders.ErrClientOrderNotFound {
+		t.Error("Test Failed - CancelExchangeOrderByClientID() registry fallback error", err)
+	}
+}
+
+func TestWithdrawCryptoExchangeFunds(t *testing.T) {
+	SetupTestHelpers(t)
+	LoadExchange("Bitstamp", false, nil)
+
+	_, err := WithdrawCryptoExchangeFunds("Unknown", "addr", pair.CurrencyItem("BTC"), 1, false)
+	if err != ErrExchangeNotFound {
+		t.Error("Test Failed - WithdrawCryptoExchangeFunds() unknown exchange error", err)
+	}
+
+	_, err = WithdrawCryptoExchangeFunds("Bitstamp", "addr", pair.CurrencyItem("BTC"), 1, false)
+	if err != portfolio.ErrAddressNotWhitelisted {
+		t.Error("Test Failed - WithdrawCryptoExchangeFunds() address book error", err)
+	}
+}
+
+func TestCompareWithdrawalFees(t *testing.T) {
+	SetupTestHelpers(t)
+	LoadExchange("Bitfinex", false, nil)
+
+	port := portfolio.GetPortfolio()
+	port.AddExchangeAddress("Bitfinex", "BTC", 1)
+	defer port.RemoveExchangeAddress("Bitfinex", "BTC")
+
+	if len(CompareWithdrawalFees("XRP", 1)) != 0 {
+		t.Error("Test failed. CompareWithdrawalFees() quoted exchanges not holding the currency")
+	}
+
+	quotes := CompareWithdrawalFees("btc", 0.5)
+	if len(quotes) != 1 {
+		t.Fatal("Test failed. CompareWithdrawalFees() unexpected quotes", quotes)
+	}
+
+	quote := quotes[0]
+	if quote.Exchange != "Bitfinex" || quote.Fee != 0.0004 || !quote.Estimate ||
+		quote.Minimum != 0.001 || !quote.Sufficient || quote.Error != "" {
+		t.Errorf("Test failed. CompareWithdrawalFees() unexpected quote %+v", quote)
+	}
+
+	if quote.Processing != exchange.WithdrawalAutomationAutoWithAPIPermission ||
+		len(quote.Methods) != 1 {
+		t.Errorf("Test failed. CompareWithdrawalFees() unexpected processing %+v", quote)
+	}
+
+	quotes = CompareWithdrawalFees("BTC", 0.0005)
+	if len(quotes) != 1 || quotes[0].Sufficient {
+		t.Error("Test failed. CompareWithdrawalFees() amount below minimum sufficient")
+	}
+}
+
+func TestPreviewWithdrawal(t *testing.T) {
+	SetupTestHelpers(t)
+	LoadExchange("Bitfinex", false, nil)
+
+	if _, err := PreviewWithdrawal("Asdsad", "BTC", "", 1); err != ErrExchangeNotFound {
+		t.Errorf("Test failed. PreviewWithdrawal() expected %s, got %v", ErrExchangeNotFound, err)
+	}
+
+	preview, err := PreviewWithdrawal("Bitfinex", "btc", "1BTCADDRESS", 0.5)
+	if err != nil {
+		t.Fatal("Test failed. PreviewWithdrawal() error", err)
+	}
+	if preview.Currency != "BTC" || preview.ExchangeFee != 0.0004 || !preview.Estimate ||
+		preview.Fee != 0.0004 || preview.Receive != 0.4996 || preview.Minimum != 0.001 {
+		t.Errorf("Test failed. PreviewWithdrawal() unexpected preview %+v", preview)
+	}
+	// The address isn't in the address book
+	if preview.Allowed || len(preview.Errors) != 1 {
+		t.Errorf("Test failed. PreviewWithdrawal() unexpected checks %v", preview.Errors)
+	}
+
+	err = portfolio.AddWithdrawalAddress(portfolio.WithdrawalAddress{Label: "Cold storage",
+		Address: "1BTCADDRESS", CoinType: "BTC", Chain: "BTC"})
+	if err != nil {
+		t.Fatal("Test failed. AddWithdrawalAddress() error", err)
+	}
+	defer portfolio.RemoveWithdrawalAddress("1BTCADDRESS", "BTC")
+
+	exch := GetExchangeByName("Bitfinex")
+	exch.SetAssetMetadataOverrides([]config.AssetMetadataConfig{{Currency: "BTC", Chain: "BTC",
+		MinWithdrawal: 0.01, WithdrawalFee: 0.0005, DepositConfirmations: 3, ProcessingTime: time.Hour}})
+	defer exch.SetAssetMetadataOverrides(nil)
+
+	preview, err = PreviewWithdrawal("Bitfinex", "BTC", "1BTCADDRESS", 0.005)
+	if err != nil {
+		t.Fatal("Test failed. PreviewWithdrawal() error", err)
+	}
+	if preview.Label != "Cold storage" || preview.Chain != "BTC" || preview.NetworkFee != 0.0005 ||
+		preview.Fee != 0.0005 || math.Abs(preview.Receive-0.0045) > 1e-12 || preview.Minimum != 0.01 ||
+		preview.Confirmations != 3 || preview.ProcessingTime != time.Hour {
+		t.Errorf("Test failed. PreviewWithdrawal() unexpected chain preview %+v", preview)
+	}
+	// The address is known but the amount is below the chain minimum
+	if preview.Allowed || len(preview.Errors) != 1 {
+		t.Errorf("Test failed. PreviewWithdrawal() unexpected chain checks %v", preview.Errors)
+	}
+
+	preview, err = PreviewWithdrawal("Bitfinex", "BTC", "1BTCADDRESS", 0.05)
+	if err != nil || !preview.Allowed || math.Abs(preview.Receive-0.0495) > 1e-12 {
+		t.Errorf("Test failed. PreviewWithdrawal() unexpected allowed preview %+v %v", preview, err)
+	}
+}
+
+func TestFormatRebalancePlan(t *testing.T) {
+	plan := portfolio.RebalancePlan{
+		BaseCurrency: "USD",
+		Total:        20000,
+		Trigger:      portfolio.RebalanceTriggerDrift,
+		MaxDrift:     0.25,
+		DryRun:       true,
+		Assets: []portfolio.RebalanceAsset{
+			{Currency: "BTC", Weight: 0.75, Target: 0.5, Drift: 0.25},
+		},
+		Trades: []portfolio.RebalanceTrade{
+			{Currency: "BTC", Side: orders.FillSell, Amount: 0.5, Value: 5000,
+				Route: orders.Route{Venue: orders.Venue{Exchange: "Bitstamp"}, Price: 10000, Cost: 12.5}},
+			{Currency: "XRP", Side: orders.FillBuy, Amount: 10000, Value: 5000,
+				Error: orders.ErrNoRoute.Error()},
+		},
+	}
+
+	report := formatRebalancePlan(plan)
+	for _, expected := range []string{
+		"drift rebalance (dry run) of 20000.00 USD",
+		"BTC weight 75.00% target 50.00% drift +25.00%",
+		"SELL 0.500000 BTC worth 5000.00 USD on Bitstamp at 10000.000000",
+		"BUY 10000.000000 XRP worth 5000.00 USD failed: " + orders.ErrNoRoute.Error(),
+	} {
+		if !common.StringContains(report, expected) {
+			t.Errorf("Test failed. formatRebalancePlan() expected %q in report %s", expected, report)
+		}
+	}
+}
+
+func TestExecuteInventoryTransfer(t *testing.T) {
+	backup := currency.FiatCurrencies
+	currency.FiatCurrencies = []string{"USD"}
+	defer func() { currency.FiatCurrencies = backup }()
+
+	_, err := executeInventoryTransfer(portfolio.InventoryTransfer{Currency: "USD",
+		From: "Bitstamp", To: "Kraken", Amount: 100})
+	if err == nil || !common.StringContains(err.Error(), "manually") {
+		t.Error("Test failed. executeInventoryTransfer() expected fiat transfer error", err)
+	}
+
+	_, err = executeInventoryTransfer(portfolio.InventoryTransfer{Currency: "BTC",
+		From: "Bitstamp", To: "NotAnExchange", Amount: 1})
+	if err != ErrExchangeNotFound {
+		t.Error("Test failed. executeInventoryTransfer() expected exchange not found error", err)
+	}
+}
+
+func TestFlattenStrategyPosition(t *testing.T) {
+	_, err := flattenStrategyPosition(context.Background(), "momentum", "NotAnExchange", "BTCUSD", orders.FillSell, 1)
+	if err != ErrExchangeNotFound {
+		t.Error("Test failed. flattenStrategyPosition() expected exchange not found error", err)
+	}
+
+	if err = cancelStrategyOrder(context.Background(), orders.ClientOrder{Exchange: "NotAnExchange", OrderID: 1}); err != ErrExchangeNotFound {
+		t.Error("Test failed. cancelStrategyOrder() expected exchange not found error", err)
+	}
+}
+
+func TestGetStrategyPluginEvents(t *testing.T) {
+	SetupTest(t)
+
+	cfg := config.StrategyPluginConfig{Name: "plugin", Exchanges: []string{"Bitfinex"},
+		Pairs: []string{"BTC-USD"}}
+	ticker.ProcessTicker("Bitfinex", pair.NewCurrencyPair("BTC", "USD"),
+		ticker.Price{Last: 100, Bid: 99, Ask: 101}, ticker.Spot)
+
+	updated := make(map[string]time.Time)
+	fillsSince := time.Now()
+	events := getStrategyPluginEvents(cfg, updated, &fillsSince)
+	if len(events) != 1 || events[0].Type != strategy.EventTicker ||
+		events[0].Ticker.Pair != "BTC-USD" || events[0].Ticker.Last != 100 {
+		t.Fatalf("Test failed. getStrategyPluginEvents() unexpected events %+v", events)
+	}
+
+	err := orders.RecordFill(orders.Fill{Exchange: "Bitfinex", Pair: "BTCUSD", Side: orders.FillBuy,
+		OrderID: 1, TradeID: "plugin-1", Amount: 1, Price: 100,
+		Timestamp: fillsSince.Add(time.Second), StrategyID: "plugin"})
+	if err != nil {
+		t.Fatal("Test failed. RecordFill() error", err)
+	}
+
+	events = getStrategyPluginEvents(cfg, updated, &fillsSince)
+	if len(events) != 1 || events[0].Type != strategy.EventFill || events[0].Fill.Pair != "BTC-USD" {
+		t.Errorf("Test failed. getStrategyPluginEvents() expected only the fill, got %+v", events)
+	}
+
+	if events = getStrategyPluginEvents(cfg, updated, &fillsSince); len(events) != 0 {
+		t.Errorf("Test failed. getStrategyPluginEvents() expected no events, got %+v", events)
+	}
+}
+
+func TestSubmitOrderIntent(t *testing.T) {
+	_, err := submitOrderIntent(context.Background(), "plugin", strategy.OrderIntent{Exchange: "Bitfinex", Pair: "BTCUSD",
+		Side: strategy.SideBuy, Type: strategy.TypeMarket, Amount: 1})
+	if err != strategy.ErrInvalidOrderIntent {
+		t.Error("Test failed. submitOrderIntent() expected invalid order intent error", err)
+	}
+
+	_, err = submitOrderIntent(context.Background(), "plugin", strategy.OrderIntent{Exchange: "NotAnExchange", Pair: "BTC-USD",
+		Side: strategy.SideBuy, Type: strategy.TypeMarket, Amount: 1})
+	if err != ErrExchangeNotFound {
+		t.Error("Test failed. submitOrderIntent() expected exchange not found error", err)
+	}
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/exchanges/trades"
+	"github.com/thrasher-/gocryptotrader/scheduler"
+)
+
+// Built in scheduled jobs
+const (
+	JobLedgerBackfill        = "ledger_backfill"
+	JobTradeHistorySync      = "trade_history_sync"
+	JobBalanceSnapshot       = "balance_snapshot"
+	JobSymbolMetadataRefresh = "symbol_metadata_refresh"
+)
+
+// Progress keys of the built in jobs
+const (
+	progressLastTimestamp = "lastTimestamp"
+	progressLastTradeID   = "lastTradeID_"
+)
+
+var (
+	jobScheduler *scheduler.Scheduler
+	jobRecordMtx sync.Mutex
+
+	jobFuncs = map[string]scheduler.JobFunc{
+		JobLedgerBackfill:        LedgerBackfillJob,
+		JobTradeHistorySync:      TradeHistorySyncJob,
+		JobBalanceSnapshot:       BalanceSnapshotJob,
+		JobSymbolMetadataRefresh: SymbolMetadataRefreshJob,
+	}
+
+	errJobExchangeNotFound = errors.New("exchange not loaded or disabled")
+	errJobAuthDisabled     = errors.New("authenticated API support disabled")
+)
+
+// BalanceSnapshot holds the balances of an exchange account at a point in time
+type BalanceSnapshot struct {
+	Timestamp time.Time            `json:"timestamp"`
+	Account   exchange.AccountInfo `json:"account"`
+}
+
+// TradeRecord holds a public trade synced from an exchange
+type TradeRecord struct {
+	Pair  string                `json:"pair"`
+	Trade exchange.TradeHistory `json:"trade"`
+}
+
+// StartScheduler adds the configured jobs to the job scheduler and runs it
+// until the context is cancelled. Jobs without exchanges run on every enabled
+// exchange
+func StartScheduler(ctx context.Context, cfg config.SchedulerConfig) error {
+	s, err := scheduler.New(GetSchedulerProgressFile(bot.dataDir))
+	if err != nil {
+		return err
+	}
+
+	for x := range cfg.Jobs {
+		if !cfg.Jobs[x].Enabled {
+			continue
+		}
+
+		run, ok := jobFuncs[cfg.Jobs[x].Name]
+		if !ok {
+			log.Printf("Scheduler: unsupported job %s, skipping.\n", cfg.Jobs[x].Name)
+			continue
+		}
+
+		schedule, err := scheduler.ParseSchedule(cfg.Jobs[x].Schedule)
+		if err != nil {
+			return err
+		}
+
+		exchanges := common.SplitStrings(cfg.Jobs[x].Exchanges, ",")
+		if cfg.Jobs[x].Exchanges == "" {
+			exchanges = bot.config.GetEnabledExchanges()
+		}
+
+		err = s.Add(scheduler.Job{
+			Name:       cfg.Jobs[x].Name,
+			Schedule:   schedule,
+			Exchanges:  exchanges,
+			RateBudget: cfg.Jobs[x].RateBudget,
+			Run:        run,
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Scheduler: added job %s with schedule %s.\n", cfg.Jobs[x].Name, schedule)
+	}
+
+	jobScheduler = s
+	startRoutine(&bot.routines, func() { s.Start(ctx) })
+	return nil
+}
+
+// GetSchedulerStatus returns the status of the scheduled jobs
+func GetSchedulerStatus() []scheduler.JobStatus {
+	if jobScheduler == nil {
+		return nil
+	}
+	return jobScheduler.GetStatus()
+}
+
+// getJobExchange returns the enabled exchange of a job run
+func getJobExchange(run *scheduler.Run) (exchange.IBotExchange, error) {
+	exch := GetExchangeByName(run.Exchange)
+	if exch == nil || !exch.IsEnabled() {
+		return nil, fmt.Errorf("%s: %s", run.Exchange, errJobExchangeNotFound)
+	}
+	return exch, nil
+}
+
+// appendJobRecords appends records as JSON lines to a file in the data
+// directory
+func appendJobRecords(file string, records []interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var data []byte
+	for x := range records {
+		encoded, err := common.JSONEncode(records[x])
+		if err != nil {
+			return err
+		}
+		data = append(append(data, encoded...), '\n')
+	}
+
+	jobRecordMtx.Lock()
+	defer jobRecordMtx.Unlock()
+
+	f, err := os.OpenFile(bot.dataDir+common.GetOSPathSlash()+file,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return err
+}
+
+// LedgerBackfillJob appends the deposits and withdrawals of an exchange
+// newer than the last backfilled transfer to the ledger file
+func LedgerBackfillJob(ctx context.Context, run *scheduler.Run) error {
+	exch, err := getJobExchange(run)
+	if err != nil {
+		return err
+	}
+
+	if !exch.GetAuthenticatedAPISupport() {
+		return errJobAuthDisabled
+	}
+
+	err = run.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	history, err := exch.GetExchangeFundTransferHistory()
+	if err != nil {
+		return err
+	}
+
+	last, _ := strconv.ParseInt(run.GetProgress(progressLastTimestamp), 10, 64)
+	newest := last
+	var records []interface{}
+	for x := range history {
+		if history[x].Timestamp <= last {
+			continue
+		}
+		history[x].ExchangeName = exch.GetName()
+		records = append(records, history[x])
+		if history[x].Timestamp > newest {
+			newest = history[x].Timestamp
+		}
+	}
+
+	err = appendJobRecords(ledgerFile, records)
+	if err != nil {
+		return err
+	}
+	return run.SetProgress(progressLastTimestamp, strconv.FormatInt(newest, 10))
+}
+
+// TradeHistorySyncJob appends the public trades of each enabled pair of an
+// exchange newer than the last synced trade to the trade history file, and
+// merges them into the trades store to fill gaps in the websocket trades
+func TradeHistorySyncJob(ctx context.Context, run *scheduler.Run) error {
+	exch, err := getJobExchange(run)
+	if err != nil {
+		return err
+	}
+
+	pairs := exch.GetEnabledCurrencies()
+	for x := range pairs {
+		err = run.Wait(ctx)
+		if err != nil {
+			return err
+		}
+
+		p := pairs[x].Pair().String()
+		history, err := exch.GetExchangeHistory(pairs[x], ticker.Spot)
+		if err != nil {
+			return fmt.Errorf("%s: %s", p, err)
+		}
+
+		_, err = trades.MergeTrades(exch.GetName(), pairs[x], ticker.Spot,
+			tradesFromHistory(history))
+		if err != nil {
+			return fmt.Errorf("%s: %s", p, err)
+		}
+
+		key := progressLastTradeID + p
+		last, _ := strconv.ParseInt(run.GetProgress(key), 10, 64)
+		newest := last
+		var records []interface{}
+		for y := range history {
+			if history[y].TID <= last {
+				continue
+			}
+			records = append(records, TradeRecord{Pair: p, Trade: history[y]})
+			if history[y].TID > newest {
+				newest = history[y].TID
+			}
+		}
+
+		err = appendJobRecords(tradeHistoryFile, records)
+		if err != nil {
+			return err
+		}
+
+		err = run.SetProgress(key, strconv.FormatInt(newest, 10))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tradesFromHistory converts REST trade history to trades for the trades store
+func tradesFromHistory(history []exchange.TradeHistory) []trades.Trade {
+	result := make([]trades.Trade, len(history))
+	for x := range history {
+		result[x] = trades.Trade{
+			Price:     history[x].Price,
+			Amount:    history[x].Amount,
+			Side:      history[x].Type,
+			Timestamp: time.Unix(history[x].Timestamp, 0),
+		}
+		if history[x].TID != 0 {
+			result[x].ID = strconv.FormatInt(history[x].TID, 10)
+		}
+	}
+	return result
+}
+
+// BalanceSnapshotJob appends the current account balances of an exchange to
+// the balance snapshots file
+func BalanceSnapshotJob(ctx context.Context, run *scheduler.Run) error {
+	exch, err := getJobExchange(run)
+	if err != nil {
+		return err
+	}
+
+	if !exch.GetAuthenticatedAPISupport() {
+		return errJobAuthDisabled
+	}
+
+	err = run.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	account, err := exch.GetExchangeAccountInfo()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = appendJobRecords(balanceSnapshotsFile, []interface{}{
+		BalanceSnapshot{Timestamp: now, Account: account},
+	})
+	if err != nil {
+		return err
+	}
+	return run.SetProgress(progressLastTimestamp, strconv.FormatInt(now.Unix(), 10))
+}
+
+// SymbolMetadataRefreshJob refreshes the listed pairs and symbol rules of an
+// exchange, adding new listings to its available pairs without enabling them,
+// and persists the symbol rules
+func SymbolMetadataRefreshJob(ctx context.Context, run *scheduler.Run) error {
+	exch, err := getJobExchange(run)
+	if err != nil {
+		return err
+	}
+
+	err = run.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	listed, err := exch.GetListedPairs()
+	if err != nil {
+		return err
+	}
+
+	newPairs, _, err := exch.AddNewListings(listed, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(newPairs) > 0 {
+		log.Printf("Scheduler: %s symbol metadata refresh found %d new pairs: %s\n",
+			exch.GetName(), len(newPairs), common.JoinStrings(newPairs, ","))
+	}
+
+	jobRecordMtx.Lock()
+	err = orders.SaveSymbolRules(bot.dataDir + common.GetOSPathSlash() + symbolRulesFile)
+	jobRecordMtx.Unlock()
+	if err != nil {
+		return err
+	}
+	return run.SetProgress(progressLastTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -9,15 +10,22 @@ import (
 	"os/signal"
 	"runtime"
 	"strconv"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/communications"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency"
 	"github.com/thrasher-/gocryptotrader/currency/forexprovider"
+	"github.com/thrasher-/gocryptotrader/currency/marketdata"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-/gocryptotrader/portfolio"
+	"github.com/thrasher-/gocryptotrader/publisher"
 )
 
 // Bot contains configuration, portfolio, exchange & ticker data and is the
@@ -25,13 +33,31 @@ import (
 type Bot struct {
 	config     *config.Config
 	portfolio  *portfolio.Base
+	deposits   *portfolio.DepositWatcher
+	publisher  *publisher.Publisher
+	conflator  *ticker.Conflator
+	auditor    *request.FileAuditor
+	limiter    *request.RedisRateLimiter
 	exchanges  []exchange.IBotExchange
 	comms      *communications.Communications
 	shutdown   chan bool
 	dryRun     bool
+	verbose    bool
 	configFile string
 	dataDir    string
 	logFile    string
+
+	// ctx is cancelled on shutdown once strategies have stopped and the
+	// websockets are closed, stopping the remaining bot routines
+	ctx      context.Context
+	cancel   context.CancelFunc
+	routines sync.WaitGroup
+
+	// strategyCtx is cancelled first on shutdown, stopping the routines
+	// which submit orders
+	strategyCtx    context.Context
+	stopStrategies context.CancelFunc
+	strategies     sync.WaitGroup
 }
 
 const banner = `
@@ -47,6 +73,8 @@ var bot Bot
 
 func main() {
 	bot.shutdown = make(chan bool)
+	bot.ctx, bot.cancel = context.WithCancel(context.Background())
+	bot.strategyCtx, bot.stopStrategies = context.WithCancel(bot.ctx)
 	HandleInterrupt()
 
 	defaultPath, err := config.GetFilePath("")
@@ -60,6 +88,8 @@ func main() {
 	dryrun := flag.Bool("dryrun", false, "dry runs bot, doesn't save config file")
 	version := flag.Bool("version", false, "retrieves current GoCryptoTrader version")
 	verbosity := flag.Bool("verbose", false, "increases logging verbosity for GoCryptoTrader")
+	auditTrail := flag.Bool("audit", false, "captures authenticated exchange requests and responses to an audit log")
+	preflight := flag.Bool("preflight", false, "checks the config against each enabled exchange without placing orders, printing a go/no-go report")
 
 	flag.Parse()
 
@@ -71,6 +101,7 @@ func main() {
 	if *dryrun {
 		bot.dryRun = true
 	}
+	bot.verbose = *verbosity
 
 	fmt.Println(banner)
 	fmt.Println(BuildVersion(false))
@@ -96,6 +127,25 @@ func main() {
 		log.Printf("Using log file: %s.\n", bot.logFile)
 	}
 
+	if *auditTrail {
+		auditPath := GetAuditFile(bot.dataDir)
+		bot.auditor, err = request.NewFileAuditor(auditPath)
+		if err != nil {
+			log.Fatalf("Failed to open audit log %s. Err: %s", auditPath, err)
+		}
+		request.SetAuditor(bot.auditor)
+		log.Printf("Audit trail enabled. Using audit file: %s.\n", auditPath)
+	}
+
+	if bot.config.SharedRateLimit.Enabled {
+		bot.limiter = request.NewRedisRateLimiter(bot.config.SharedRateLimit.Address,
+			bot.config.SharedRateLimit.Password, bot.config.SharedRateLimit.DB,
+			bot.config.SharedRateLimit.Timeout)
+		request.SetSharedRateLimiter(bot.limiter, bot.config.SharedRateLimit.Namespace)
+		log.Printf("Shared rate limiting enabled. Using Redis server: %s.\n",
+			bot.config.SharedRateLimit.Address)
+	}
+
 	AdjustGoMaxProcs()
 	log.Printf("Bot '%s' started.\n", bot.config.Name)
 	log.Printf("Bot dry run mode: %v.\n", common.IsEnabled(bot.dryRun))
@@ -107,15 +157,112 @@ func main() {
 	common.HTTPClient = common.NewHTTPClientWithTimeout(bot.config.GlobalHTTPTimeout)
 	log.Printf("Global HTTP request timeout: %v.\n", common.HTTPClient.Timeout)
 
+	if *preflight {
+		log.Println("Running preflight checks..")
+		bot.dryRun = true
+		SetupExchanges()
+		report := RunPreflight()
+		fmt.Print(report.String())
+		if !report.IsGo() {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	orders.SetMaxDataAge(bot.config.OrderManager.MaxDataAge)
+	log.Printf("Order manager max decision data age: %v.\n", orders.GetMaxDataAge())
+
+	for strategyID, limits := range bot.config.OrderManager.StrategyLimits {
+		orders.SetStrategyLimits(strategyID, limits)
+	}
+	for strategyID, policy := range bot.config.OrderManager.StrategyShutdown {
+		err = orders.SetStrategyShutdownPolicy(strategyID, policy)
+		if err != nil {
+			log.Printf("Unable to set strategy %s shutdown policy. Err: %s", strategyID, err)
+		}
+	}
+	orders.SetThrottleLimits(bot.config.OrderManager.PairThrottle,
+		bot.config.OrderManager.StrategyThrottle)
+
+	err = orders.SetDefaultSlippage(bot.config.OrderManager.Slippage)
+	if err != nil {
+		log.Printf("Unable to set simulated fill slippage. Err: %s", err)
+	}
+	for strategyID, slippage := range bot.config.OrderManager.StrategySlippage {
+		err = orders.SetStrategySlippage(strategyID, slippage)
+		if err != nil {
+			log.Printf("Unable to set strategy %s simulated fill slippage. Err: %s",
+				strategyID, err)
+		}
+	}
+	for x := range bot.config.Exchanges {
+		if bot.config.Exchanges[x].Slippage == nil {
+			continue
+		}
+		err = orders.SetExchangeSlippage(bot.config.Exchanges[x].Name,
+			*bot.config.Exchanges[x].Slippage)
+		if err != nil {
+			log.Printf("Unable to set %s simulated fill slippage. Err: %s",
+				bot.config.Exchanges[x].Name, err)
+		}
+	}
+	for x := range bot.config.Exchanges {
+		err = orders.SetMaintenanceWindows(bot.config.Exchanges[x].Name,
+			bot.config.Exchanges[x].MaintenanceWindows)
+		if err != nil {
+			log.Printf("Unable to set %s maintenance windows. Err: %s",
+				bot.config.Exchanges[x].Name, err)
+		}
+	}
+
+	trailingStopsPath := GetTrailingStopsFile(bot.dataDir)
+	err = orders.LoadTrailingStops(trailingStopsPath)
+	if err != nil {
+		log.Fatalf("Failed to load trailing stops from %s. Err: %s", trailingStopsPath, err)
+	}
+	log.Printf("Loaded %d trailing stops from %s.\n", len(orders.GetTrailingStops()),
+		trailingStopsPath)
+
+	fiatTransfersPath := GetFiatTransfersFile(bot.dataDir)
+	err = exchange.LoadFiatTransfers(fiatTransfersPath)
+	if err != nil {
+		log.Fatalf("Failed to load fiat transfers from %s. Err: %s", fiatTransfersPath, err)
+	}
+
+	clientOrdersPath := GetClientOrdersFile(bot.dataDir)
+	err = orders.LoadClientOrders(clientOrdersPath)
+	if err != nil {
+		log.Fatalf("Failed to load client orders from %s. Err: %s", clientOrdersPath, err)
+	}
+
+	fillsPath := GetFillsFile(bot.dataDir)
+	err = orders.LoadFills(fillsPath)
+	if err != nil {
+		log.Fatalf("Failed to load fills from %s. Err: %s", fillsPath, err)
+	}
+
+	if bot.config.WarmCache.Enabled {
+		err = LoadWarmCache(bot.dataDir, bot.config.WarmCache.MaxAge)
+		if err != nil {
+			log.Printf("Unable to restore warm cache. Err: %s", err)
+		}
+	}
+
 	SetupExchanges()
 	if len(bot.exchanges) == 0 {
 		log.Fatalf("No exchanges were able to be loaded. Exiting")
 	}
 
+	log.Println("Synchronising exchange clocks..")
+	SyncExchangeClocks()
+
 	log.Println("Starting communication mediums..")
 	bot.comms = communications.NewComm(bot.config.GetCommunicationsConfig())
 	bot.comms.GetEnabledCommunicationMediums()
 
+	log.Println("Reconciling open exchange orders..")
+	ReconcileExchangeOrders(bot.config.OrderManager.AdoptUnknownOrders)
+
 	log.Printf("Fiat display currency: %s.", bot.config.Currency.FiatDisplayCurrency)
 	currency.BaseCurrency = bot.config.Currency.FiatDisplayCurrency
 	currency.FXProviders = forexprovider.StartFXService(bot.config.GetCurrencyConfig().ForexProviders)
@@ -134,6 +281,8 @@ func main() {
 	bot.portfolio = &portfolio.Portfolio
 	bot.portfolio.SeedPortfolio(bot.config.Portfolio)
 	SeedExchangeAccountInfo(GetAllEnabledExchangeAccountInfo().Data)
+	LoadWithdrawalLimits()
+	UpdateFeeDiscounts()
 
 	if bot.config.Webserver.Enabled {
 		listenAddr := bot.config.Webserver.ListenAddress
@@ -159,9 +308,116 @@ func main() {
 
 	go portfolio.StartPortfolioWatcher()
 
-	go TickerUpdaterRoutine()
-	go OrderbookUpdaterRoutine()
-	go WebsocketRoutine(*verbosity)
+	if bot.config.DepositWatcher.Enabled {
+		StartDepositWatcher(bot.ctx)
+	} else {
+		log.Println("Deposit watcher support disabled.")
+	}
+
+	if bot.config.OrderManager.ProfitLossReportInterval > 0 {
+		startRoutine(&bot.routines, func() {
+			ProfitLossReportRoutine(bot.ctx, bot.config.OrderManager.ProfitLossReportInterval)
+		})
+	}
+
+	if bot.config.OrderManager.StatusPolling.Enabled {
+		startRoutine(&bot.routines, func() {
+			OrderStatusPollRoutine(bot.ctx, bot.config.OrderManager.StatusPolling)
+		})
+	} else {
+		log.Println("Order status polling disabled.")
+		startRoutine(&bot.routines, func() { FillPollRoutine(bot.ctx) })
+	}
+
+	if !bot.dryRun {
+		startRoutine(&bot.strategies, func() { SpreadOrderRoutine(bot.strategyCtx) })
+	} else {
+		log.Println("Spread order execution disabled in dry run mode.")
+	}
+
+	if bot.config.Rebalance.Enabled {
+		startRoutine(&bot.strategies, func() {
+			RebalanceRoutine(bot.strategyCtx, bot.config.Rebalance,
+				bot.config.Rebalance.DryRun || bot.dryRun)
+		})
+	} else {
+		log.Println("Portfolio rebalancing disabled.")
+	}
+
+	if bot.config.Arbitrage.Enabled {
+		startRoutine(&bot.strategies, func() {
+			ArbitrageRoutine(bot.strategyCtx, bot.config.Arbitrage,
+				bot.config.Arbitrage.DryRun || bot.dryRun)
+		})
+	} else {
+		log.Println("Arbitrage disabled.")
+	}
+
+	for x := range bot.config.StrategyPlugins {
+		cfg := bot.config.StrategyPlugins[x]
+		if !cfg.Enabled {
+			continue
+		}
+		startRoutine(&bot.strategies, func() {
+			StrategyPluginRoutine(bot.strategyCtx, cfg, cfg.DryRun || bot.dryRun)
+		})
+	}
+
+	marketDataProviders := marketdata.NewProviders(bot.config.Currency.MarketDataProviders)
+	if len(marketDataProviders) > 0 {
+		startRoutine(&bot.routines, func() { MarketDataRoutine(bot.ctx, marketDataProviders) })
+	} else {
+		log.Println("Market data provider support disabled.")
+	}
+
+	StartNewListingsPollers(bot.ctx)
+
+	if bot.config.Scheduler.Enabled {
+		err = StartScheduler(bot.ctx, bot.config.Scheduler)
+		if err != nil {
+			log.Printf("Unable to start job scheduler. Err: %s", err)
+		}
+	} else {
+		log.Println("Job scheduler support disabled.")
+	}
+
+	if bot.config.Publisher.Enabled {
+		err = StartPublisher(bot.ctx)
+		if err != nil {
+			log.Printf("Unable to start message broker publisher. Err: %s", err)
+		}
+	} else {
+		log.Println("Message broker publisher support disabled.")
+	}
+
+	if bot.config.TickerHistory.Enabled {
+		ticker.EnableHistory(bot.config.TickerHistory.MaxEntries, bot.config.TickerHistory.MaxAge)
+	} else {
+		log.Println("Ticker history disabled.")
+	}
+
+	if bot.config.TickerConflation.Enabled {
+		StartTickerConflation(bot.ctx)
+	} else {
+		log.Println("Ticker conflation disabled.")
+	}
+
+	startRoutine(&bot.routines, func() { FiatTransferRoutine(bot.ctx) })
+	startRoutine(&bot.routines, func() { ClientOrderMaintenanceRoutine(bot.ctx) })
+	startRoutine(&bot.routines, func() { MaintenanceWindowRoutine(bot.ctx) })
+	startRoutine(&bot.routines, func() { ClockSkewRoutine(bot.ctx) })
+	startRoutine(&bot.routines, func() { CandleFlushRoutine(bot.ctx) })
+	startRoutine(&bot.routines, func() { TickerUpdaterRoutine(bot.ctx) })
+	startRoutine(&bot.routines, func() { OrderbookUpdaterRoutine(bot.ctx) })
+	WebsocketRoutine(*verbosity)
+
+	if bot.config.Consistency.Enabled {
+		startRoutine(&bot.routines, func() {
+			OrderbookConsistencyRoutine(bot.ctx, bot.config.Consistency)
+		})
+	} else {
+		log.Println("Orderbook consistency monitoring disabled.")
+	}
 
 	<-bot.shutdown
 	Shutdown()
@@ -201,9 +457,144 @@ func HandleInterrupt() {
 	}()
 }
 
-// Shutdown correctly shuts down bot saving configuration files
+// startRoutine runs a bot routine tracked by the wait group so shutdown can
+// wait for it to return
+func startRoutine(wg *sync.WaitGroup, routine func()) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		routine()
+	}()
+}
+
+// waitTimeout waits for the wait group until the deadline, returning false if
+// the deadline passed first
+func waitTimeout(wg *sync.WaitGroup, deadline time.Time) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(time.Until(deadline)):
+		return false
+	}
+}
+
+// cancelAllOrdersOnExit cancels the resting orders on all exchanges with
+// authenticated API support, waiting until the deadline
+func cancelAllOrdersOnExit(deadline time.Time) {
+	var wg sync.WaitGroup
+	for x := range bot.exchanges {
+		if !bot.exchanges[x].GetAuthenticatedAPISupport() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(exch exchange.IBotExchange) {
+			defer wg.Done()
+			err := exch.CancelAllExchangeOrders()
+			if err != nil {
+				log.Printf("%s failed to cancel all orders. Error: %s",
+					exch.GetName(), err)
+				return
+			}
+			log.Printf("%s cancelled all orders.", exch.GetName())
+		}(bot.exchanges[x])
+	}
+
+	if !waitTimeout(&wg, deadline) {
+		log.Println("Timed out cancelling orders.")
+	}
+}
+
+// shutdownStrategiesOnExit applies the shutdown policy of every strategy with
+// one, within the strategy shutdown timeout and the shutdown deadline
+func shutdownStrategiesOnExit(deadline time.Time) {
+	strategyDeadline := time.Now().Add(bot.config.Shutdown.StrategyTimeout)
+	if strategyDeadline.After(deadline) {
+		strategyDeadline = deadline
+	}
+
+	var wg sync.WaitGroup
+	for _, strategyID := range orders.GetShutdownStrategies() {
+		wg.Add(1)
+		go func(strategyID string) {
+			defer wg.Done()
+			ShutdownStrategy(strategyID, "bot shutdown", strategyDeadline)
+		}(strategyID)
+	}
+
+	if !waitTimeout(&wg, strategyDeadline) {
+		log.Println("Timed out applying strategy shutdown policies.")
+	}
+}
+
+// stopOrderbookRecorders closes the websocket orderbook recordings of all
+// exchanges
+func stopOrderbookRecorders() {
+	for x := range bot.exchanges {
+		ws, err := bot.exchanges[x].GetWebsocket()
+		if err != nil {
+			continue
+		}
+
+		err = ws.Orderbook.StopRecording()
+		if err != nil {
+			log.Printf("%s failed to close orderbook recording. Error: %s",
+				bot.exchanges[x].GetName(), err)
+		}
+	}
+}
+
+// Shutdown shuts down the bot in order. Strategies are stopped first and their
+// shutdown policies applied, resting orders are cancelled if configured,
+// websockets are closed and the remaining routines are stopped before the
+// stores and configuration are saved. Routines which don't stop within the
+// shutdown timeout are abandoned
 func Shutdown() {
 	log.Println("Bot shutting down..")
+	deadline := time.Now().Add(bot.config.Shutdown.Timeout)
+
+	log.Println("Stopping strategies..")
+	bot.stopStrategies()
+	if !waitTimeout(&bot.strategies, deadline) {
+		log.Println("Timed out waiting for strategies to stop.")
+	}
+
+	if !bot.dryRun {
+		log.Println("Applying strategy shutdown policies..")
+		shutdownStrategiesOnExit(deadline)
+	}
+
+	if bot.config.Shutdown.CancelOrdersOnExit && !bot.dryRun {
+		log.Println("Cancelling resting orders..")
+		cancelAllOrdersOnExit(deadline)
+	}
+
+	log.Println("Closing websockets..")
+	ShutdownWebsockets()
+
+	log.Println("Stopping routines..")
+	bot.cancel()
+	if bot.deposits != nil {
+		bot.deposits.Stop()
+	}
+	if !waitTimeout(&bot.routines, deadline) {
+		log.Println("Timed out waiting for routines to stop.")
+	}
+
+	stopOrderbookRecorders()
+
+	if bot.config.WarmCache.Enabled {
+		err := SaveWarmCache(bot.dataDir)
+		if err != nil {
+			log.Printf("Unable to save warm cache. Err: %s", err)
+		}
+	}
 
 	if len(portfolio.Portfolio.Addresses) != 0 {
 		bot.config.Portfolio = portfolio.Portfolio
@@ -221,6 +612,16 @@ func Shutdown() {
 
 	log.Println("Exiting.")
 
+	if bot.auditor != nil {
+		request.SetAuditor(nil)
+		bot.auditor.Close()
+	}
+
+	if bot.limiter != nil {
+		request.SetSharedRateLimiter(nil, "")
+		bot.limiter.Close()
+	}
+
 	if logFileHandle != nil {
 		logFileHandle.Close()
 	}
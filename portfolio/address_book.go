@@ -0,0 +1,140 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Vars for the withdrawal address book
+var (
+	addressBook    []WithdrawalAddress
+	addressBookMtx sync.Mutex
+
+	// ErrAddressNotWhitelisted is returned when withdrawing to an address
+	// which isn't in the address book
+	ErrAddressNotWhitelisted = errors.New("withdrawal address not in the address book")
+	// ErrInvalidWithdrawalAddress is returned when an address book entry is
+	// missing required details
+	ErrInvalidWithdrawalAddress = errors.New("address book entry requires a label, address and coin type")
+)
+
+// WithdrawalAddress holds a pre-registered withdrawal destination. A blank
+// exchange allows withdrawals from any exchange and a zero max amount doesn't
+// limit the amount of each withdrawal. Chain is the network the address is
+// on, such as ERC20 or TRC20, the exchange's default chain when blank
+type WithdrawalAddress struct {
+	Label     string  `json:"label"`
+	Address   string  `json:"address"`
+	CoinType  string  `json:"coinType"`
+	Chain     string  `json:"chain,omitempty"`
+	Exchange  string  `json:"exchange,omitempty"`
+	MaxAmount float64 `json:"maxAmount,omitempty"`
+}
+
+func (w *WithdrawalAddress) matches(exchange, address, coinType string) bool {
+	return w.Address == address &&
+		common.StringToUpper(w.CoinType) == common.StringToUpper(coinType) &&
+		(w.Exchange == "" || common.StringToLower(w.Exchange) == common.StringToLower(exchange))
+}
+
+func validateWithdrawalAddress(entry WithdrawalAddress) error {
+	if entry.Label == "" || entry.Address == "" || entry.CoinType == "" || entry.MaxAmount < 0 {
+		return ErrInvalidWithdrawalAddress
+	}
+	return nil
+}
+
+// SetAddressBook replaces the withdrawal address book
+func SetAddressBook(entries []WithdrawalAddress) error {
+	for x := range entries {
+		err := validateWithdrawalAddress(entries[x])
+		if err != nil {
+			return fmt.Errorf("address book entry %d: %s", x, err)
+		}
+	}
+
+	addressBookMtx.Lock()
+	addressBook = append([]WithdrawalAddress(nil), entries...)
+	addressBookMtx.Unlock()
+	return nil
+}
+
+// GetAddressBook returns the withdrawal address book
+func GetAddressBook() []WithdrawalAddress {
+	addressBookMtx.Lock()
+	defer addressBookMtx.Unlock()
+	return append([]WithdrawalAddress(nil), addressBook...)
+}
+
+// AddWithdrawalAddress adds a withdrawal destination to the address book,
+// replacing any entry for the same address, coin type and exchange
+func AddWithdrawalAddress(entry WithdrawalAddress) error {
+	err := validateWithdrawalAddress(entry)
+	if err != nil {
+		return err
+	}
+
+	addressBookMtx.Lock()
+	defer addressBookMtx.Unlock()
+
+	for x := range addressBook {
+		if addressBook[x].Address == entry.Address &&
+			common.StringToUpper(addressBook[x].CoinType) == common.StringToUpper(entry.CoinType) &&
+			common.StringToLower(addressBook[x].Exchange) == common.StringToLower(entry.Exchange) {
+			addressBook[x] = entry
+			return nil
+		}
+	}
+	addressBook = append(addressBook, entry)
+	return nil
+}
+
+// RemoveWithdrawalAddress removes every address book entry for an address and
+// coin type, returning whether any were removed
+func RemoveWithdrawalAddress(address, coinType string) bool {
+	addressBookMtx.Lock()
+	defer addressBookMtx.Unlock()
+
+	var kept []WithdrawalAddress
+	for x := range addressBook {
+		if addressBook[x].Address == address &&
+			common.StringToUpper(addressBook[x].CoinType) == common.StringToUpper(coinType) {
+			continue
+		}
+		kept = append(kept, addressBook[x])
+	}
+
+	removed := len(kept) != len(addressBook)
+	addressBook = kept
+	return removed
+}
+
+// CheckWithdrawalAddress returns the address book entry allowing a withdrawal
+// of an amount to an address from an exchange, or an error if the address
+// isn't registered or the amount is above its limit
+func CheckWithdrawalAddress(exchange, address, coinType string, amount float64) (WithdrawalAddress, error) {
+	addressBookMtx.Lock()
+	defer addressBookMtx.Unlock()
+
+	var limited *WithdrawalAddress
+	for x := range addressBook {
+		if !addressBook[x].matches(exchange, address, coinType) {
+			continue
+		}
+
+		if addressBook[x].MaxAmount > 0 && amount > addressBook[x].MaxAmount {
+			limited = &addressBook[x]
+			continue
+		}
+		return addressBook[x], nil
+	}
+
+	if limited != nil {
+		return *limited, fmt.Errorf("withdrawal of %v %s to %s exceeds the address book limit of %v",
+			amount, coinType, limited.Label, limited.MaxAmount)
+	}
+	return WithdrawalAddress{}, ErrAddressNotWhitelisted
+}
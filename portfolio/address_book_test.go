@@ -0,0 +1,65 @@
+package portfolio
+
+import "testing"
+
+func TestSetAddressBook(t *testing.T) {
+	err := SetAddressBook([]WithdrawalAddress{{Address: "1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy", CoinType: "BTC"}})
+	if err == nil {
+		t.Error("Test Failed - SetAddressBook() missing label error")
+	}
+
+	err = SetAddressBook([]WithdrawalAddress{
+		{Label: "cold", Address: "1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy", CoinType: "BTC", MaxAmount: 1},
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SetAddressBook() error", err)
+	}
+	defer SetAddressBook(nil)
+
+	if len(GetAddressBook()) != 1 {
+		t.Error("Test Failed - GetAddressBook() unexpected entries")
+	}
+}
+
+func TestCheckWithdrawalAddress(t *testing.T) {
+	defer SetAddressBook(nil)
+
+	err := AddWithdrawalAddress(WithdrawalAddress{Label: "cold", Address: "addr1", CoinType: "btc", MaxAmount: 1})
+	if err != nil {
+		t.Fatal("Test Failed - AddWithdrawalAddress() error", err)
+	}
+
+	err = AddWithdrawalAddress(WithdrawalAddress{Label: "desk", Address: "addr2", CoinType: "LTC", Exchange: "Bitstamp"})
+	if err != nil {
+		t.Fatal("Test Failed - AddWithdrawalAddress() error", err)
+	}
+
+	entry, err := CheckWithdrawalAddress("Kraken", "addr1", "BTC", 0.5)
+	if err != nil || entry.Label != "cold" {
+		t.Error("Test Failed - CheckWithdrawalAddress() error", err)
+	}
+
+	_, err = CheckWithdrawalAddress("Kraken", "addr1", "BTC", 2)
+	if err == nil || err == ErrAddressNotWhitelisted {
+		t.Error("Test Failed - CheckWithdrawalAddress() limit error", err)
+	}
+
+	_, err = CheckWithdrawalAddress("Kraken", "addr2", "LTC", 1)
+	if err != ErrAddressNotWhitelisted {
+		t.Error("Test Failed - CheckWithdrawalAddress() exchange restriction error", err)
+	}
+
+	_, err = CheckWithdrawalAddress("bitstamp", "addr2", "LTC", 100)
+	if err != nil {
+		t.Error("Test Failed - CheckWithdrawalAddress() error", err)
+	}
+
+	if !RemoveWithdrawalAddress("addr2", "ltc") || RemoveWithdrawalAddress("addr2", "LTC") {
+		t.Error("Test Failed - RemoveWithdrawalAddress() unexpected result")
+	}
+
+	_, err = CheckWithdrawalAddress("Bitstamp", "addr2", "LTC", 1)
+	if err != ErrAddressNotWhitelisted {
+		t.Error("Test Failed - CheckWithdrawalAddress() removed address error", err)
+	}
+}
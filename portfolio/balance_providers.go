@@ -0,0 +1,426 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+const (
+	blockchairAPIURL     = "https://api.blockchair.com"
+	blockchairDashboards = "dashboards/address"
+
+	bitcoinComAPIURL         = "https://rest.bitcoin.com/v2"
+	bitcoinComAddressDetails = "address/details"
+
+	rippleDataAPIURL = "https://data.ripple.com/v2"
+	rippleAccounts   = "accounts"
+
+	xrpScanAPIURL  = "https://api.xrpscan.com/api/v1"
+	xrpScanAccount = "account"
+)
+
+// BalanceProvider fetches the balance of an address from a block explorer
+type BalanceProvider interface {
+	GetName() string
+	IsCoinSupported(coinType string) bool
+	GetAddressBalance(address, coinType string) (float64, error)
+}
+
+// Vars for the balance provider registry
+var (
+	balanceProviders = []BalanceProvider{
+		&Ethplorer{APIURL: ethplorerAPIURL},
+		&CryptoID{APIURL: cryptoIDAPIURL},
+		&Blockcypher{APIURL: blockcypherAPIURL},
+		&Blockchair{APIURL: blockchairAPIURL},
+		&BitcoinCom{APIURL: bitcoinComAPIURL},
+		&RippleData{APIURL: rippleDataAPIURL},
+		&XRPScan{APIURL: xrpScanAPIURL},
+	}
+	balanceProvidersMtx sync.Mutex
+
+	erc20Tokens    []ERC20Token
+	erc20TokensMtx sync.Mutex
+)
+
+// RegisterBalanceProvider adds a balance provider to the registry. Providers
+// are tried in the order they are registered
+func RegisterBalanceProvider(provider BalanceProvider) {
+	balanceProvidersMtx.Lock()
+	balanceProviders = append(balanceProviders, provider)
+	balanceProvidersMtx.Unlock()
+}
+
+// SetBalanceProviders replaces the registered balance providers
+func SetBalanceProviders(providers ...BalanceProvider) {
+	balanceProvidersMtx.Lock()
+	balanceProviders = providers
+	balanceProvidersMtx.Unlock()
+}
+
+// GetBalanceProviders returns the registered balance providers which support
+// the coin type, in failover order
+func GetBalanceProviders(coinType string) []BalanceProvider {
+	balanceProvidersMtx.Lock()
+	defer balanceProvidersMtx.Unlock()
+
+	var providers []BalanceProvider
+	for x := range balanceProviders {
+		if balanceProviders[x].IsCoinSupported(coinType) {
+			providers = append(providers, balanceProviders[x])
+		}
+	}
+	return providers
+}
+
+// SetERC20Tokens sets the ERC-20 tokens tracked for Ethereum addresses
+func SetERC20Tokens(tokens []ERC20Token) {
+	erc20TokensMtx.Lock()
+	erc20Tokens = tokens
+	erc20TokensMtx.Unlock()
+}
+
+// GetERC20Token returns the tracked ERC-20 token for the symbol
+func GetERC20Token(symbol string) (ERC20Token, bool) {
+	erc20TokensMtx.Lock()
+	defer erc20TokensMtx.Unlock()
+
+	for x := range erc20Tokens {
+		if common.StringToUpper(erc20Tokens[x].Symbol) == common.StringToUpper(symbol) {
+			return erc20Tokens[x], true
+		}
+	}
+	return ERC20Token{}, false
+}
+
+// GetAddressBalance returns the balance of an address, failing over to the
+// next provider supporting the coin type when a provider returns an error
+func GetAddressBalance(address, coinType string) (float64, error) {
+	coinType = common.StringToUpper(coinType)
+
+	validationCoin := coinType
+	if _, ok := GetERC20Token(coinType); ok {
+		validationCoin = "ETH"
+	}
+
+	valid, _ := common.IsValidCryptoAddress(address, validationCoin)
+	if !valid {
+		return 0, fmt.Errorf("invalid %s address %s", coinType, address)
+	}
+
+	providers := GetBalanceProviders(coinType)
+	if len(providers) == 0 {
+		return 0, fmt.Errorf("no balance providers support %s", coinType)
+	}
+
+	var errs []string
+	for x := range providers {
+		balance, err := providers[x].GetAddressBalance(address, coinType)
+		if err == nil {
+			return balance, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %s", providers[x].GetName(), err))
+	}
+	return 0, fmt.Errorf("unable to get %s balance for %s: %s",
+		coinType, address, common.JoinStrings(errs, ", "))
+}
+
+// Ethplorer fetches ETH and ERC-20 token balances from Ethplorer
+type Ethplorer struct {
+	APIURL string
+}
+
+// GetName returns the provider name
+func (e *Ethplorer) GetName() string {
+	return "Ethplorer"
+}
+
+// IsCoinSupported returns whether the provider supports the coin type
+func (e *Ethplorer) IsCoinSupported(coinType string) bool {
+	if common.StringToUpper(coinType) == "ETH" {
+		return true
+	}
+	_, ok := GetERC20Token(coinType)
+	return ok
+}
+
+// GetAddressBalance returns the ETH or ERC-20 token balance for an address
+func (e *Ethplorer) GetAddressBalance(address, coinType string) (float64, error) {
+	url := fmt.Sprintf("%s/%s/%s?apiKey=freekey", e.APIURL, ethplorerAddressInfo, address)
+	result := EthplorerResponse{}
+	err := common.SendHTTPGetRequest(url, true, false, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.Error.Message != "" {
+		return 0, errors.New(result.Error.Message)
+	}
+
+	if common.StringToUpper(coinType) == "ETH" {
+		return result.ETH.Balance, nil
+	}
+
+	token, _ := GetERC20Token(coinType)
+	for x := range result.Tokens {
+		if !isTokenMatch(token, result.Tokens[x]) {
+			continue
+		}
+		decimals, err := parseTokenDecimals(result.Tokens[x].TokenInfo.Decimals)
+		if err != nil {
+			return 0, err
+		}
+		return result.Tokens[x].Balance / math.Pow10(decimals), nil
+	}
+	// Ethplorer omits tokens the address has never held
+	return 0, nil
+}
+
+// CryptoID fetches balances from CryptoID
+type CryptoID struct {
+	APIURL string
+}
+
+// GetName returns the provider name
+func (c *CryptoID) GetName() string {
+	return "CryptoID"
+}
+
+// IsCoinSupported returns whether the provider supports the coin type
+func (c *CryptoID) IsCoinSupported(coinType string) bool {
+	return common.StringToUpper(coinType) == "LTC"
+}
+
+// GetAddressBalance returns the balance for an address
+func (c *CryptoID) GetAddressBalance(address, coinType string) (float64, error) {
+	var result interface{}
+	url := fmt.Sprintf("%s/%s/api.dws?q=getbalance&a=%s",
+		c.APIURL, common.StringToLower(coinType), address)
+	err := common.SendHTTPGetRequest(url, true, false, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	balance, ok := result.(float64)
+	if !ok {
+		return 0, errors.New("unexpected balance response")
+	}
+	return balance, nil
+}
+
+// Blockcypher fetches balances from BlockCypher
+type Blockcypher struct {
+	APIURL string
+}
+
+// GetName returns the provider name
+func (b *Blockcypher) GetName() string {
+	return "BlockCypher"
+}
+
+// IsCoinSupported returns whether the provider supports the coin type
+func (b *Blockcypher) IsCoinSupported(coinType string) bool {
+	return IsDepositWatcherSupported(coinType)
+}
+
+// GetAddressBalance returns the balance for an address
+func (b *Blockcypher) GetAddressBalance(address, coinType string) (float64, error) {
+	coinType = common.StringToUpper(coinType)
+	if coinType == "ETH" {
+		address = strings.TrimPrefix(address, "0x")
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/%s/%s/balance",
+		b.APIURL,
+		common.StringToLower(coinType),
+		blockcypherMainnet,
+		blockcypherAddrs,
+		address,
+	)
+
+	result := BlockcypherAddressResponse{}
+	err := common.SendHTTPGetRequest(url, true, false, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.Error != "" {
+		return 0, errors.New(result.Error)
+	}
+	return convertBlockcypherValue(coinType, result.Balance), nil
+}
+
+// blockchairChains maps coin types to Blockchair chain names
+var blockchairChains = map[string]string{
+	"BTC": "bitcoin",
+	"BCH": "bitcoin-cash",
+	"LTC": "litecoin",
+}
+
+// Blockchair fetches balances from Blockchair
+type Blockchair struct {
+	APIURL string
+}
+
+// GetName returns the provider name
+func (b *Blockchair) GetName() string {
+	return "Blockchair"
+}
+
+// IsCoinSupported returns whether the provider supports the coin type
+func (b *Blockchair) IsCoinSupported(coinType string) bool {
+	_, ok := blockchairChains[common.StringToUpper(coinType)]
+	return ok
+}
+
+// GetAddressBalance returns the balance for an address
+func (b *Blockchair) GetAddressBalance(address, coinType string) (float64, error) {
+	url := fmt.Sprintf("%s/%s/%s/%s",
+		b.APIURL,
+		blockchairChains[common.StringToUpper(coinType)],
+		blockchairDashboards,
+		address,
+	)
+
+	result := BlockchairAddressResponse{}
+	err := common.SendHTTPGetRequest(url, true, false, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.Context.Error != "" {
+		return 0, errors.New(result.Context.Error)
+	}
+
+	for _, data := range result.Data {
+		return data.Address.Balance / common.SatoshisPerBTC, nil
+	}
+	return 0, errors.New("address not found")
+}
+
+// BitcoinCom fetches Bitcoin Cash balances from the Bitcoin.com REST API
+type BitcoinCom struct {
+	APIURL string
+}
+
+// GetName returns the provider name
+func (b *BitcoinCom) GetName() string {
+	return "Bitcoin.com"
+}
+
+// IsCoinSupported returns whether the provider supports the coin type
+func (b *BitcoinCom) IsCoinSupported(coinType string) bool {
+	return common.StringToUpper(coinType) == "BCH"
+}
+
+// GetAddressBalance returns the balance for an address
+func (b *BitcoinCom) GetAddressBalance(address, coinType string) (float64, error) {
+	url := fmt.Sprintf("%s/%s/%s", b.APIURL, bitcoinComAddressDetails, address)
+	result := BitcoinComAddressResponse{}
+	err := common.SendHTTPGetRequest(url, true, false, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.Error != "" {
+		return 0, errors.New(result.Error)
+	}
+	return result.Balance, nil
+}
+
+// RippleData fetches XRP balances from the Ripple data API
+type RippleData struct {
+	APIURL string
+}
+
+// GetName returns the provider name
+func (r *RippleData) GetName() string {
+	return "Ripple Data"
+}
+
+// IsCoinSupported returns whether the provider supports the coin type
+func (r *RippleData) IsCoinSupported(coinType string) bool {
+	return common.StringToUpper(coinType) == "XRP"
+}
+
+// GetAddressBalance returns the balance for an address
+func (r *RippleData) GetAddressBalance(address, coinType string) (float64, error) {
+	url := fmt.Sprintf("%s/%s/%s/balances?currency=XRP",
+		r.APIURL, rippleAccounts, address)
+	result := RippleBalancesResponse{}
+	err := common.SendHTTPGetRequest(url, true, false, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.Result != "success" {
+		return 0, errors.New(result.Message)
+	}
+
+	for x := range result.Balances {
+		if result.Balances[x].Currency == "XRP" {
+			return strconv.ParseFloat(result.Balances[x].Value, 64)
+		}
+	}
+	return 0, nil
+}
+
+// XRPScan fetches XRP balances from XRPScan
+type XRPScan struct {
+	APIURL string
+}
+
+// GetName returns the provider name
+func (x *XRPScan) GetName() string {
+	return "XRPScan"
+}
+
+// IsCoinSupported returns whether the provider supports the coin type
+func (x *XRPScan) IsCoinSupported(coinType string) bool {
+	return common.StringToUpper(coinType) == "XRP"
+}
+
+// GetAddressBalance returns the balance for an address
+func (x *XRPScan) GetAddressBalance(address, coinType string) (float64, error) {
+	url := fmt.Sprintf("%s/%s/%s", x.APIURL, xrpScanAccount, address)
+	result := XRPScanAccountResponse{}
+	err := common.SendHTTPGetRequest(url, true, false, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.Error != "" {
+		return 0, errors.New(result.Error)
+	}
+	return strconv.ParseFloat(result.XRPBalance, 64)
+}
+
+// isTokenMatch returns whether the Ethplorer token balance belongs to the
+// tracked token, preferring the contract address when one is configured
+func isTokenMatch(token ERC20Token, balance EthplorerToken) bool {
+	if token.Contract != "" {
+		return common.StringToLower(token.Contract) ==
+			common.StringToLower(balance.TokenInfo.Address)
+	}
+	return common.StringToUpper(token.Symbol) ==
+		common.StringToUpper(balance.TokenInfo.Symbol)
+}
+
+// parseTokenDecimals parses token decimals which Ethplorer returns as either a
+// number or a string
+func parseTokenDecimals(decimals interface{}) (int, error) {
+	switch d := decimals.(type) {
+	case float64:
+		return int(d), nil
+	case string:
+		return strconv.Atoi(d)
+	default:
+		return 0, errors.New("unable to parse token decimals")
+	}
+}
@@ -0,0 +1,103 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testBalanceProvider struct {
+	name    string
+	balance float64
+	err     error
+	calls   int
+}
+
+func (t *testBalanceProvider) GetName() string {
+	return t.name
+}
+
+func (t *testBalanceProvider) IsCoinSupported(coinType string) bool {
+	return coinType == "XRP"
+}
+
+func (t *testBalanceProvider) GetAddressBalance(address, coinType string) (float64, error) {
+	t.calls++
+	return t.balance, t.err
+}
+
+func TestGetAddressBalanceFailover(t *testing.T) {
+	defaultProviders := GetBalanceProviders("XRP")
+	defer SetBalanceProviders(balanceProviders...)
+
+	if len(defaultProviders) < 2 {
+		t.Error("Test Failed - GetBalanceProviders() XRP failover providers not registered")
+	}
+
+	down := &testBalanceProvider{name: "down", err: errors.New("service unavailable")}
+	up := &testBalanceProvider{name: "up", balance: 1337}
+	SetBalanceProviders(down, up)
+
+	balance, err := GetAddressBalance("rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", "xrp")
+	if err != nil || balance != 1337 {
+		t.Error("Test Failed - GetAddressBalance() failover error", err)
+	}
+
+	if down.calls != 1 || up.calls != 1 {
+		t.Error("Test Failed - GetAddressBalance() providers not tried in order")
+	}
+
+	_, err = GetAddressBalance("Testy", "XRP")
+	if err == nil {
+		t.Error("Test Failed - GetAddressBalance() invalid address accepted")
+	}
+
+	_, err = GetAddressBalance("1Mz7153HMuxXTuR2R1t78mGSdzaAtNbBWX", "BTC")
+	if err == nil {
+		t.Error("Test Failed - GetAddressBalance() unsupported coin type accepted")
+	}
+
+	SetBalanceProviders(down)
+	_, err = GetAddressBalance("rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", "XRP")
+	if err == nil {
+		t.Error("Test Failed - GetAddressBalance() all providers failing returned no error")
+	}
+}
+
+func TestEthplorerTokenBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"address":"0xb794f5ea0ba39494ce839613fffba74279579268",
+			"ETH":{"balance":1.5},
+			"tokens":[{"tokenInfo":{"address":"0xd26114cd6ee289accf82350c8d8487fedb8a0c07","symbol":"OMG","decimals":"18"},"balance":2.5e18}]}`)
+	}))
+	defer server.Close()
+
+	SetERC20Tokens([]ERC20Token{
+		{Symbol: "OMG", Contract: "0xD26114cd6EE289AccF82350c8d8487fedB8A0C07"},
+		{Symbol: "ZRX"},
+	})
+	defer SetERC20Tokens(nil)
+
+	e := Ethplorer{APIURL: server.URL}
+	if !e.IsCoinSupported("omg") || e.IsCoinSupported("LTC") {
+		t.Error("Test Failed - Ethplorer IsCoinSupported() incorrect token support")
+	}
+
+	address := "0xb794f5ea0ba39494ce839613fffba74279579268"
+	balance, err := e.GetAddressBalance(address, "ETH")
+	if err != nil || balance != 1.5 {
+		t.Error("Test Failed - Ethplorer GetAddressBalance() ETH error", err)
+	}
+
+	balance, err = e.GetAddressBalance(address, "OMG")
+	if err != nil || balance != 2.5 {
+		t.Error("Test Failed - Ethplorer GetAddressBalance() token error", err)
+	}
+
+	balance, err = e.GetAddressBalance(address, "ZRX")
+	if err != nil || balance != 0 {
+		t.Error("Test Failed - Ethplorer GetAddressBalance() unheld token error", err)
+	}
+}
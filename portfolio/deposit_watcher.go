@@ -0,0 +1,265 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+const (
+	blockcypherAPIURL     = "https://api.blockcypher.com/v1"
+	blockcypherAddrs      = "addrs"
+	blockcypherMainnet    = "main"
+	blockcypherTXLimit    = 50
+	depositWatcherDelay   = time.Minute
+	depositWatcherChanLen = 100
+
+	// DepositSeen is the event status for a deposit that has been broadcast
+	// to the network but has not yet reached the required confirmations
+	DepositSeen = "seen"
+	// DepositConfirmed is the event status for a deposit that has reached the
+	// required number of confirmations
+	DepositConfirmed = "confirmed"
+)
+
+// DefaultDepositConfirmations holds the default number of blockchain
+// confirmations required before a deposit is classed as confirmed
+var DefaultDepositConfirmations = map[string]int64{
+	"BTC": 3,
+	"LTC": 6,
+	"ETH": 12,
+}
+
+// IsDepositWatcherSupported returns whether or not the deposit watcher can
+// monitor addresses for the supplied coin type
+func IsDepositWatcherSupported(coinType string) bool {
+	_, ok := DefaultDepositConfirmations[common.StringToUpper(coinType)]
+	return ok
+}
+
+// GetBlockcypherAddress returns recent transaction references for an address
+// on a BlockCypher supported blockchain
+func GetBlockcypherAddress(address, coinType string) (BlockcypherAddressResponse, error) {
+	result := BlockcypherAddressResponse{}
+	if !IsDepositWatcherSupported(coinType) {
+		return result, errors.New("unsupported coin type")
+	}
+
+	// BlockCypher expects Ethereum addresses without the 0x prefix
+	if common.StringToUpper(coinType) == "ETH" {
+		address = strings.TrimPrefix(address, "0x")
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/%s/%s?limit=%d",
+		blockcypherAPIURL,
+		common.StringToLower(coinType),
+		blockcypherMainnet,
+		blockcypherAddrs,
+		address,
+		blockcypherTXLimit,
+	)
+
+	err := common.SendHTTPGetRequest(url, true, false, &result)
+	if err != nil {
+		return result, err
+	}
+
+	if result.Error != "" {
+		return result, errors.New(result.Error)
+	}
+	return result, nil
+}
+
+// NewDepositWatcher returns a new deposit watcher which polls public block
+// explorers for incoming transactions at the supplied delay
+func NewDepositWatcher(checkDelay time.Duration, verbose bool) *DepositWatcher {
+	if checkDelay <= 0 {
+		checkDelay = depositWatcherDelay
+	}
+
+	return &DepositWatcher{
+		CheckDelay: checkDelay,
+		Verbose:    verbose,
+		Events:     make(chan DepositEvent, depositWatcherChanLen),
+		seen:       make(map[string]int64),
+		shutdown:   make(chan struct{}),
+	}
+}
+
+// AddAddress adds a deposit address to be watched. If confirmations is zero
+// the default for the coin type is used
+func (d *DepositWatcher) AddAddress(exchangeName, coinType, address string, confirmations int64) error {
+	if !IsDepositWatcherSupported(coinType) {
+		return fmt.Errorf("deposit watcher does not support coin type %s",
+			coinType)
+	}
+
+	if address == "" {
+		return errors.New("deposit watcher address cannot be empty")
+	}
+
+	coinType = common.StringToUpper(coinType)
+	if confirmations <= 0 {
+		confirmations = DefaultDepositConfirmations[coinType]
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+	for x := range d.Addresses {
+		if d.Addresses[x].Address == address &&
+			d.Addresses[x].CoinType == coinType {
+			d.Addresses[x].Exchange = exchangeName
+			d.Addresses[x].Confirmations = confirmations
+			return nil
+		}
+	}
+
+	d.Addresses = append(d.Addresses, WatchedAddress{
+		Exchange:      exchangeName,
+		CoinType:      coinType,
+		Address:       address,
+		Confirmations: confirmations,
+	})
+	return nil
+}
+
+// RemoveAddress removes a deposit address from the watch list
+func (d *DepositWatcher) RemoveAddress(coinType, address string) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	for x := range d.Addresses {
+		if d.Addresses[x].Address == address &&
+			d.Addresses[x].CoinType == common.StringToUpper(coinType) {
+			d.Addresses = append(d.Addresses[:x], d.Addresses[x+1:]...)
+			return nil
+		}
+	}
+	return errors.New("deposit watcher address not found")
+}
+
+// GetAddresses returns a copy of the currently watched addresses
+func (d *DepositWatcher) GetAddresses() []WatchedAddress {
+	d.m.Lock()
+	defer d.m.Unlock()
+	addresses := make([]WatchedAddress, len(d.Addresses))
+	copy(addresses, d.Addresses)
+	return addresses
+}
+
+// Start begins polling all watched addresses until Stop is called
+func (d *DepositWatcher) Start() {
+	log.Printf("DepositWatcher started: Watching %d address(es).\n",
+		len(d.GetAddresses()))
+
+	for {
+		d.CheckAddresses()
+		select {
+		case <-d.shutdown:
+			return
+		case <-time.After(d.CheckDelay):
+		}
+	}
+}
+
+// Stop halts the deposit watcher polling routine
+func (d *DepositWatcher) Stop() {
+	close(d.shutdown)
+}
+
+// CheckAddresses queries each watched address once and emits deposit events
+func (d *DepositWatcher) CheckAddresses() {
+	for _, addr := range d.GetAddresses() {
+		result, err := GetBlockcypherAddress(addr.Address, addr.CoinType)
+		if err != nil {
+			log.Printf("DepositWatcher: Failed to fetch %s address %s. Error: %s\n",
+				addr.CoinType, addr.Address, err)
+			continue
+		}
+		d.processTransactions(addr, result)
+	}
+}
+
+// processTransactions compares the fetched incoming transactions against the
+// known transactions and emits events for new or newly confirmed deposits
+func (d *DepositWatcher) processTransactions(addr WatchedAddress, resp BlockcypherAddressResponse) {
+	txs := make(map[string]BlockcypherTXRef)
+	var order []string
+	refs := make([]BlockcypherTXRef, 0, len(resp.UnconfirmedTXRefs)+len(resp.TXRefs))
+	refs = append(refs, resp.UnconfirmedTXRefs...)
+	refs = append(refs, resp.TXRefs...)
+	for _, tx := range refs {
+		// A negative input index signals the address received an output
+		if tx.TXInputN >= 0 {
+			continue
+		}
+		existing, ok := txs[tx.TXHash]
+		if !ok {
+			order = append(order, tx.TXHash)
+		}
+		existing.TXHash = tx.TXHash
+		existing.Value += tx.Value
+		existing.Confirmations = tx.Confirmations
+		txs[tx.TXHash] = existing
+	}
+
+	for _, hash := range order {
+		tx := txs[hash]
+		key := addr.CoinType + addr.Address + hash
+
+		d.m.Lock()
+		lastConfirmations, known := d.seen[key]
+		status := ""
+		switch {
+		case tx.Confirmations >= addr.Confirmations &&
+			(!known || lastConfirmations < addr.Confirmations):
+			status = DepositConfirmed
+		case !known && tx.Confirmations < addr.Confirmations:
+			status = DepositSeen
+		}
+		d.seen[key] = tx.Confirmations
+		d.m.Unlock()
+
+		if status == "" {
+			continue
+		}
+
+		evt := DepositEvent{
+			Exchange:      addr.Exchange,
+			CoinType:      addr.CoinType,
+			Address:       addr.Address,
+			TXHash:        hash,
+			Amount:        convertBlockcypherValue(addr.CoinType, tx.Value),
+			Confirmations: tx.Confirmations,
+			Status:        status,
+		}
+
+		if d.Verbose {
+			log.Printf("DepositWatcher: %s deposit of %f %s to %s (%d confirmations).\n",
+				status, evt.Amount, evt.CoinType, evt.Address, evt.Confirmations)
+		}
+
+		select {
+		case d.Events <- evt:
+		default:
+			log.Printf("DepositWatcher: Event channel full, dropping %s event for %s.\n",
+				status, hash)
+		}
+	}
+}
+
+// convertBlockcypherValue converts the smallest coin unit returned by
+// BlockCypher into a whole coin amount
+func convertBlockcypherValue(coinType string, value float64) float64 {
+	switch coinType {
+	case "ETH":
+		return value / common.WeiPerEther
+	case "LTC":
+		return value / common.SatoshisPerLTC
+	default:
+		return value / common.SatoshisPerBTC
+	}
+}
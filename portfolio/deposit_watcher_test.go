@@ -0,0 +1,87 @@
+package portfolio
+
+import (
+	"testing"
+)
+
+func TestDepositWatcherAddAddress(t *testing.T) {
+	d := NewDepositWatcher(0, false)
+	if d.CheckDelay != depositWatcherDelay {
+		t.Error("Test Failed - NewDepositWatcher() default delay not set")
+	}
+
+	err := d.AddAddress("Bitstamp", "btc", "1Mz7153HMuxXTuR2R1t78mGSdzaAtNbBWX", 0)
+	if err != nil {
+		t.Error("Test Failed - AddAddress() error", err)
+	}
+
+	err = d.AddAddress("Bitstamp", "doge", "DAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", 0)
+	if err == nil {
+		t.Error("Test Failed - AddAddress() unsupported coin type accepted")
+	}
+
+	addresses := d.GetAddresses()
+	if len(addresses) != 1 || addresses[0].Confirmations != DefaultDepositConfirmations["BTC"] {
+		t.Error("Test Failed - AddAddress() incorrect watched addresses")
+	}
+
+	err = d.RemoveAddress("btc", "1Mz7153HMuxXTuR2R1t78mGSdzaAtNbBWX")
+	if err != nil {
+		t.Error("Test Failed - RemoveAddress() error", err)
+	}
+
+	if len(d.GetAddresses()) != 0 {
+		t.Error("Test Failed - RemoveAddress() address not removed")
+	}
+}
+
+func TestDepositWatcherProcessTransactions(t *testing.T) {
+	d := NewDepositWatcher(0, false)
+	addr := WatchedAddress{
+		Exchange:      "Bitstamp",
+		CoinType:      "BTC",
+		Address:       "1Mz7153HMuxXTuR2R1t78mGSdzaAtNbBWX",
+		Confirmations: 3,
+	}
+
+	resp := BlockcypherAddressResponse{
+		UnconfirmedTXRefs: []BlockcypherTXRef{
+			{TXHash: "deposit", TXInputN: -1, Value: 50000000},
+			{TXHash: "spend", TXInputN: 0, Value: 10000000},
+		},
+	}
+
+	d.processTransactions(addr, resp)
+	if len(d.Events) != 1 {
+		t.Fatal("Test Failed - processTransactions() expected a single event")
+	}
+
+	evt := <-d.Events
+	if evt.Status != DepositSeen || evt.Amount != 0.5 {
+		t.Errorf("Test Failed - processTransactions() unexpected event %v", evt)
+	}
+
+	// Repeated unconfirmed sightings should not emit further events
+	d.processTransactions(addr, resp)
+	if len(d.Events) != 0 {
+		t.Error("Test Failed - processTransactions() duplicate event emitted")
+	}
+
+	resp = BlockcypherAddressResponse{
+		TXRefs: []BlockcypherTXRef{
+			{TXHash: "deposit", TXInputN: -1, Value: 50000000, Confirmations: 3},
+		},
+	}
+
+	d.processTransactions(addr, resp)
+	evt = <-d.Events
+	if evt.Status != DepositConfirmed || evt.Confirmations != 3 {
+		t.Errorf("Test Failed - processTransactions() unexpected event %v", evt)
+	}
+
+	resp.TXRefs[0].Confirmations = 4
+	d.processTransactions(addr, resp)
+	if len(d.Events) != 0 {
+		t.Error("Test Failed - processTransactions() duplicate confirmed event")
+	}
+}
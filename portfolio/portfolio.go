@@ -177,40 +177,23 @@ func (p *Base) RemoveAddress(address, coinType, description string) {
 	}
 }
 
-// UpdatePortfolio adds to the portfolio addresses by coin type
+// UpdatePortfolio adds to the portfolio addresses by coin type, fetching each
+// balance from the registered balance providers
 func (p *Base) UpdatePortfolio(addresses []string, coinType string) bool {
 	if common.StringContains(common.JoinStrings(addresses, ","), PortfolioAddressExchange) || common.StringContains(common.JoinStrings(addresses, ","), PortfolioAddressPersonal) {
 		return true
 	}
 
 	errors := 0
-	if coinType == "ETH" {
-		for x := range addresses {
-			result, err := GetEthereumBalance(addresses[x])
-			if err != nil {
-				errors++
-				continue
-			}
-
-			if result.Error.Message != "" {
-				errors++
-				continue
-			}
-			p.AddAddress(addresses[x], coinType, PortfolioAddressPersonal, result.ETH.Balance)
-		}
-		if errors > 0 {
-			return false
-		}
-		return true
-	}
 	for x := range addresses {
-		result, err := GetCryptoIDAddress(addresses[x], coinType)
+		balance, err := GetAddressBalance(addresses[x], coinType)
 		if err != nil {
-			return false
+			errors++
+			continue
 		}
-		p.AddAddress(addresses[x], coinType, PortfolioAddressPersonal, result)
+		p.AddAddress(addresses[x], coinType, PortfolioAddressPersonal, balance)
 	}
-	return true
+	return errors == 0
 }
 
 // GetPortfolioByExchange returns currency portfolio amount by exchange
@@ -258,6 +241,24 @@ func (p *Base) GetPersonalPortfolio() map[string]float64 {
 	return result
 }
 
+// GetUserPortfolio returns the portfolio visible to a management API user,
+// the personal addresses the user owns and the exchange balances of the
+// exchanges the user can access. Nil exchanges allows every exchange
+func (p *Base) GetUserPortfolio(owner string, exchanges []string) *Base {
+	user := &Base{Tokens: p.Tokens}
+	for _, x := range p.Addresses {
+		if x.Description == PortfolioAddressExchange {
+			if exchanges != nil && !common.StringDataCompareUpper(exchanges, x.Address) {
+				continue
+			}
+		} else if x.Owner != owner {
+			continue
+		}
+		user.Addresses = append(user.Addresses, x)
+	}
+	return user
+}
+
 // getPercentage returns the percentage of the target coin amount against the
 // total coin amount.
 func getPercentage(input map[string]float64, target string, totals map[string]float64) float64 {
@@ -385,6 +386,20 @@ func (p *Base) GetPortfolioGroupedCoin() map[string][]string {
 // addresses
 func (p *Base) SeedPortfolio(port Base) {
 	p.Addresses = port.Addresses
+	p.Tokens = port.Tokens
+	p.AddressBook = port.AddressBook
+	p.WithdrawalLimits = port.WithdrawalLimits
+	SetERC20Tokens(port.Tokens)
+
+	err := SetAddressBook(port.AddressBook)
+	if err != nil {
+		log.Printf("Portfolio: Unable to set withdrawal address book: %s\n", err)
+	}
+
+	err = SetWithdrawalLimits(port.WithdrawalLimits)
+	if err != nil {
+		log.Printf("Portfolio: Unable to set withdrawal limits: %s\n", err)
+	}
 }
 
 // StartPortfolioWatcher observes the portfolio object
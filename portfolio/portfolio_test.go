@@ -291,6 +291,26 @@ func TestGetPersonalPortfolio(t *testing.T) {
 	}
 }
 
+func TestGetUserPortfolio(t *testing.T) {
+	base := Base{}
+	base.AddAddress("someaddress", "LTC", PortfolioAddressPersonal, 1)
+	base.Addresses = append(base.Addresses, Address{Address: "owned", CoinType: "BTC",
+		Balance: 2, Description: PortfolioAddressPersonal, Owner: "trader"})
+	base.AddAddress("Bitstamp", "BTC", PortfolioAddressExchange, 3)
+	base.AddAddress("Kraken", "BTC", PortfolioAddressExchange, 4)
+
+	user := base.GetUserPortfolio("trader", []string{"bitstamp"})
+	if len(user.Addresses) != 2 || user.GetPersonalPortfolio()["BTC"] != 2 ||
+		user.GetExchangePortfolio()["BTC"] != 3 {
+		t.Errorf("Test Failed - GetUserPortfolio() unexpected addresses %v", user.Addresses)
+	}
+
+	user = base.GetUserPortfolio("other", nil)
+	if len(user.Addresses) != 2 || user.GetExchangePortfolio()["BTC"] != 7 {
+		t.Errorf("Test Failed - GetUserPortfolio() unexpected addresses %v", user.Addresses)
+	}
+}
+
 func TestGetPortfolioSummary(t *testing.T) {
 	newbase := Base{}
 	// Personal holdings
@@ -1,8 +1,23 @@
 package portfolio
 
+import (
+	"sync"
+	"time"
+)
+
 // Base holds the portfolio base addresses
 type Base struct {
-	Addresses []Address
+	Addresses        []Address
+	Tokens           []ERC20Token        `json:",omitempty"`
+	AddressBook      []WithdrawalAddress `json:",omitempty"`
+	WithdrawalLimits []WithdrawalLimit   `json:",omitempty"`
+}
+
+// ERC20Token holds an ERC-20 token whose balance is tracked for portfolio
+// addresses with a coin type matching the token symbol
+type ERC20Token struct {
+	Symbol   string
+	Contract string
 }
 
 // Address sub type holding address information for portfolio
@@ -11,6 +26,7 @@ type Address struct {
 	CoinType    string
 	Balance     float64
 	Description string
+	Owner       string `json:",omitempty"`
 }
 
 // EtherchainBalanceResponse holds JSON incoming and outgoing data for
@@ -63,12 +79,62 @@ type EthplorerResponse struct {
 			Currency string `json:"currency"`
 		} `json:"price"`
 	} `json:"tokenInfo"`
-	Error struct {
+	Tokens []EthplorerToken `json:"tokens"`
+	Error  struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
 	} `json:"error"`
 }
 
+// EthplorerToken is a sub type holding an ERC-20 token balance for an address
+type EthplorerToken struct {
+	TokenInfo struct {
+		Address  string      `json:"address"`
+		Symbol   string      `json:"symbol"`
+		Decimals interface{} `json:"decimals"`
+	} `json:"tokenInfo"`
+	Balance float64 `json:"balance"`
+}
+
+// BlockchairAddressResponse holds JSON address data for Blockchair
+type BlockchairAddressResponse struct {
+	Data map[string]struct {
+		Address struct {
+			Balance float64 `json:"balance"`
+		} `json:"address"`
+	} `json:"data"`
+	Context struct {
+		Code  int    `json:"code"`
+		Error string `json:"error"`
+	} `json:"context"`
+}
+
+// BitcoinComAddressResponse holds JSON address data for the Bitcoin.com REST
+// API
+type BitcoinComAddressResponse struct {
+	Balance float64 `json:"balance"`
+	Error   string  `json:"error"`
+}
+
+// RippleBalancesResponse holds JSON account balance data for the Ripple data
+// API
+type RippleBalancesResponse struct {
+	Result   string `json:"result"`
+	Message  string `json:"message"`
+	Balances []struct {
+		Currency     string `json:"currency"`
+		Counterparty string `json:"counterparty"`
+		Value        string `json:"value"`
+	} `json:"balances"`
+}
+
+// XRPScanAccountResponse holds JSON account data for XRPScan
+type XRPScanAccountResponse struct {
+	Account    string `json:"account"`
+	XRPBalance string `json:"xrpBalance"`
+	Error      string `json:"error"`
+}
+
 // ExchangeAccountInfo : Generic type to hold each exchange's holdings in all
 // enabled currencies
 type ExchangeAccountInfo struct {
@@ -115,3 +181,57 @@ type Summary struct {
 	Online         []Coin                                  `json:"coins_online"`
 	OnlineSummary  map[string]map[string]OnlineCoinSummary `json:"online_summary"`
 }
+
+// BlockcypherAddressResponse holds JSON address transaction data for
+// BlockCypher
+type BlockcypherAddressResponse struct {
+	Address           string             `json:"address"`
+	TotalReceived     float64            `json:"total_received"`
+	Balance           float64            `json:"balance"`
+	UnconfirmedTXRefs []BlockcypherTXRef `json:"unconfirmed_txrefs"`
+	TXRefs            []BlockcypherTXRef `json:"txrefs"`
+	Error             string             `json:"error"`
+}
+
+// BlockcypherTXRef is a sub type holding a transaction reference for an
+// address
+type BlockcypherTXRef struct {
+	TXHash        string  `json:"tx_hash"`
+	TXInputN      int64   `json:"tx_input_n"`
+	TXOutputN     int64   `json:"tx_output_n"`
+	Value         float64 `json:"value"`
+	Confirmations int64   `json:"confirmations"`
+	Confirmed     string  `json:"confirmed"`
+}
+
+// DepositWatcher polls public block explorers for incoming transactions to
+// known deposit addresses
+type DepositWatcher struct {
+	Addresses  []WatchedAddress
+	CheckDelay time.Duration
+	Verbose    bool
+	Events     chan DepositEvent
+	seen       map[string]int64
+	shutdown   chan struct{}
+	m          sync.Mutex
+}
+
+// WatchedAddress holds a deposit address and the number of confirmations
+// required before a deposit is classed as confirmed
+type WatchedAddress struct {
+	Exchange      string `json:"exchange"`
+	CoinType      string `json:"coinType"`
+	Address       string `json:"address"`
+	Confirmations int64  `json:"confirmations"`
+}
+
+// DepositEvent is emitted when an incoming transaction is seen or confirmed
+type DepositEvent struct {
+	Exchange      string  `json:"exchange"`
+	CoinType      string  `json:"coinType"`
+	Address       string  `json:"address"`
+	TXHash        string  `json:"txHash"`
+	Amount        float64 `json:"amount"`
+	Confirmations int64   `json:"confirmations"`
+	Status        string  `json:"status"`
+}
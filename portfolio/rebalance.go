@@ -0,0 +1,248 @@
+package portfolio
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
+)
+
+// Rebalance triggers
+const (
+	RebalanceTriggerDrift    = "DRIFT"
+	RebalanceTriggerCalendar = "CALENDAR"
+)
+
+const (
+	// rebalanceWeightTolerance allows for rounding in configured target
+	// weights
+	rebalanceWeightTolerance = 1e-6
+	// maxRebalancePlans is the number of triggered plans kept for reporting
+	maxRebalancePlans = 100
+)
+
+// Vars for the rebalance plan history
+var (
+	rebalancePlans    []RebalancePlan
+	rebalancePlansMtx sync.Mutex
+
+	// ErrInvalidRebalanceTargets is returned when the target weights of a
+	// rebalancer are negative or don't sum to one
+	ErrInvalidRebalanceTargets = errors.New("rebalance target weights must be non negative and sum to one")
+)
+
+// RebalanceRouter routes a trade of an amount of a currency against the
+// valuation base currency, returning the venue and estimated cost in the base
+// currency
+type RebalanceRouter func(currency, side string, amount float64) (orders.Route, error)
+
+// Rebalancer generates the trades returning holdings to their target weights
+// of the total value. A rebalance is triggered when the weight of any
+// currency drifts from its target by at least DriftThreshold or when Interval
+// has elapsed since the last rebalance, either is disabled when zero
+type Rebalancer struct {
+	Targets        map[string]float64
+	DriftThreshold float64
+	Interval       time.Duration
+	MinTradeValue  float64
+
+	lastRebalance time.Time
+}
+
+// RebalanceAsset holds the weight of a currency and its drift from target
+type RebalanceAsset struct {
+	Currency string  `json:"currency"`
+	Balance  float64 `json:"balance"`
+	Value    float64 `json:"value"`
+	Weight   float64 `json:"weight"`
+	Target   float64 `json:"target"`
+	Drift    float64 `json:"drift"`
+}
+
+// RebalanceTrade holds a trade of a rebalance and the route it was given by
+// the router. OrderID is set once the trade is submitted
+type RebalanceTrade struct {
+	Currency string       `json:"currency"`
+	Side     string       `json:"side"`
+	Amount   float64      `json:"amount"`
+	Value    float64      `json:"value"`
+	Route    orders.Route `json:"route"`
+	OrderID  int64        `json:"orderID,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// RebalancePlan holds the drift of the holdings and, when a rebalance was
+// triggered, the trades to return them to target with sells ordered before
+// buys to fund them. EstimatedCost is the total fee and spread cost of the
+// routed trades in the base currency
+type RebalancePlan struct {
+	Time          time.Time        `json:"time"`
+	BaseCurrency  string           `json:"baseCurrency"`
+	Total         float64          `json:"total"`
+	Trigger       string           `json:"trigger,omitempty"`
+	MaxDrift      float64          `json:"maxDrift"`
+	DryRun        bool             `json:"dryRun"`
+	Assets        []RebalanceAsset `json:"assets"`
+	Trades        []RebalanceTrade `json:"trades,omitempty"`
+	EstimatedCost float64          `json:"estimatedCost"`
+	Unpriced      []string         `json:"unpriced,omitempty"`
+}
+
+// NewRebalancer returns a rebalancer for the target weights of each currency,
+// which must be non negative and sum to one
+func NewRebalancer(targets map[string]float64, driftThreshold float64, interval time.Duration, minTradeValue float64) (*Rebalancer, error) {
+	normalised := make(map[string]float64)
+	var total float64
+	for currency, weight := range targets {
+		if weight < 0 {
+			return nil, ErrInvalidRebalanceTargets
+		}
+		normalised[common.StringToUpper(currency)] += weight
+		total += weight
+	}
+
+	if math.Abs(total-1) > rebalanceWeightTolerance {
+		return nil, ErrInvalidRebalanceTargets
+	}
+
+	return &Rebalancer{
+		Targets:        normalised,
+		DriftThreshold: driftThreshold,
+		Interval:       interval,
+		MinTradeValue:  minTradeValue,
+	}, nil
+}
+
+// Plan values the holdings of the portfolio and returns the drift of each
+// currency from its target weight. When a rebalance is triggered the plan
+// holds a trade of each currency other than the base currency whose value is
+// off target by at least MinTradeValue, routed by the router to the cheapest
+// venue, and the rebalance time is recorded. The first plan starts the
+// calendar interval rather than triggering it
+func (r *Rebalancer) Plan(p *Base, v *Valuer, route RebalanceRouter, now time.Time) RebalancePlan {
+	valuation := p.GetValuation(v)
+	plan := RebalancePlan{
+		Time:         now,
+		BaseCurrency: valuation.BaseCurrency,
+		Total:        valuation.Total,
+		Unpriced:     valuation.Unpriced,
+	}
+
+	rates := make(map[string]float64)
+	assets := make(map[string]*RebalanceAsset)
+	for _, h := range valuation.Holdings {
+		rates[h.Coin] = h.Rate
+		assets[h.Coin] = &RebalanceAsset{Currency: h.Coin, Balance: h.Balance, Value: h.Value}
+	}
+	for currency, target := range r.Targets {
+		if _, ok := assets[currency]; !ok {
+			assets[currency] = &RebalanceAsset{Currency: currency}
+		}
+		assets[currency].Target = target
+	}
+
+	for _, a := range assets {
+		if plan.Total > 0 {
+			a.Weight = a.Value / plan.Total
+		}
+		a.Drift = a.Weight - a.Target
+		if math.Abs(a.Drift) > plan.MaxDrift {
+			plan.MaxDrift = math.Abs(a.Drift)
+		}
+		plan.Assets = append(plan.Assets, *a)
+	}
+	sort.Slice(plan.Assets, func(i, j int) bool {
+		return plan.Assets[i].Currency < plan.Assets[j].Currency
+	})
+
+	if r.lastRebalance.IsZero() {
+		r.lastRebalance = now
+	}
+
+	switch {
+	case r.DriftThreshold > 0 && plan.MaxDrift >= r.DriftThreshold:
+		plan.Trigger = RebalanceTriggerDrift
+	case r.Interval > 0 && now.Sub(r.lastRebalance) >= r.Interval:
+		plan.Trigger = RebalanceTriggerCalendar
+	default:
+		return plan
+	}
+	r.lastRebalance = now
+
+	for x := range plan.Assets {
+		a := plan.Assets[x]
+		if a.Currency == plan.BaseCurrency {
+			continue
+		}
+
+		value := a.Target*plan.Total - a.Value
+		if math.Abs(value) < r.MinTradeValue || value == 0 {
+			continue
+		}
+
+		rate, ok := rates[a.Currency]
+		if !ok {
+			var err error
+			rate, err = v.Rate(a.Currency)
+			if err != nil {
+				plan.Unpriced = append(plan.Unpriced, a.Currency)
+				continue
+			}
+		}
+
+		trade := RebalanceTrade{
+			Currency: a.Currency,
+			Side:     orders.FillBuy,
+			Amount:   value / rate,
+			Value:    value,
+		}
+		if value < 0 {
+			trade.Side = orders.FillSell
+			trade.Amount = -trade.Amount
+			trade.Value = -value
+		}
+		plan.Trades = append(plan.Trades, trade)
+	}
+
+	sort.SliceStable(plan.Trades, func(i, j int) bool {
+		return plan.Trades[i].Side == orders.FillSell && plan.Trades[j].Side != orders.FillSell
+	})
+
+	for x := range plan.Trades {
+		trade := &plan.Trades[x]
+		result, err := route(trade.Currency, trade.Side, trade.Amount)
+		if err != nil {
+			trade.Error = err.Error()
+			continue
+		}
+		trade.Route = result
+		plan.EstimatedCost += result.Cost
+	}
+	return plan
+}
+
+// RecordRebalancePlan records a triggered rebalance plan, keeping the most
+// recent plans for reporting
+func RecordRebalancePlan(plan RebalancePlan) {
+	rebalancePlansMtx.Lock()
+	defer rebalancePlansMtx.Unlock()
+	rebalancePlans = append(rebalancePlans, plan)
+	if len(rebalancePlans) > maxRebalancePlans {
+		rebalancePlans = rebalancePlans[len(rebalancePlans)-maxRebalancePlans:]
+	}
+}
+
+// GetRebalancePlans returns the recorded rebalance plans, most recent first
+func GetRebalancePlans() []RebalancePlan {
+	rebalancePlansMtx.Lock()
+	defer rebalancePlansMtx.Unlock()
+	result := make([]RebalancePlan, len(rebalancePlans))
+	for x := range rebalancePlans {
+		result[len(rebalancePlans)-1-x] = rebalancePlans[x]
+	}
+	return result
+}
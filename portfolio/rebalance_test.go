@@ -0,0 +1,91 @@
+package portfolio
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
+)
+
+func TestNewRebalancer(t *testing.T) {
+	_, err := NewRebalancer(map[string]float64{"BTC": 0.5, "USD": 0.6}, 0.05, 0, 0)
+	if err != ErrInvalidRebalanceTargets {
+		t.Error("Test Failed - NewRebalancer() invalid weights error", err)
+	}
+
+	_, err = NewRebalancer(map[string]float64{"BTC": 1.5, "USD": -0.5}, 0.05, 0, 0)
+	if err != ErrInvalidRebalanceTargets {
+		t.Error("Test Failed - NewRebalancer() negative weight error", err)
+	}
+
+	r, err := NewRebalancer(map[string]float64{"btc": 0.5, "USD": 0.5}, 0.05, 0, 0)
+	if err != nil {
+		t.Fatal("Test Failed - NewRebalancer() error", err)
+	}
+	if r.Targets["BTC"] != 0.5 {
+		t.Error("Test Failed - NewRebalancer() target currencies not normalised")
+	}
+}
+
+func TestRebalancerPlan(t *testing.T) {
+	var p Base
+	p.AddAddress("Bitstamp", "BTC", PortfolioAddressExchange, 1)
+	p.AddAddress("Bitstamp", "USD", PortfolioAddressExchange, 5000)
+	p.AddAddress("Kraken", "ETH", PortfolioAddressExchange, 10)
+
+	var routed []string
+	route := func(currency, side string, amount float64) (orders.Route, error) {
+		routed = append(routed, side+" "+currency)
+		if currency == "XRP" {
+			return orders.Route{}, errors.New("no venue")
+		}
+		return orders.Route{Side: side, Amount: amount, Cost: 1}, nil
+	}
+
+	r, err := NewRebalancer(map[string]float64{"BTC": 0.25, "ETH": 0.25, "XRP": 0.25, "USD": 0.25},
+		0.1, time.Hour, 100)
+	if err != nil {
+		t.Fatal("Test Failed - NewRebalancer() error", err)
+	}
+
+	now := time.Now()
+	plan := r.Plan(&p, testValuer(), route, now)
+	if plan.Trigger != RebalanceTriggerDrift || plan.Total != 20000 || plan.MaxDrift != 0.25 {
+		t.Fatalf("Test Failed - Plan() unexpected drift trigger %s %f %f",
+			plan.Trigger, plan.Total, plan.MaxDrift)
+	}
+	if len(plan.Assets) != 4 || plan.Assets[0].Currency != "BTC" || plan.Assets[0].Drift != 0.25 {
+		t.Errorf("Test Failed - Plan() unexpected assets %v", plan.Assets)
+	}
+
+	if len(plan.Trades) != 2 || plan.Trades[0].Side != orders.FillSell ||
+		plan.Trades[0].Currency != "BTC" || plan.Trades[0].Amount != 0.5 {
+		t.Fatalf("Test Failed - Plan() unexpected trades %v", plan.Trades)
+	}
+	if plan.Trades[1].Side != orders.FillBuy || plan.Trades[1].Currency != "XRP" ||
+		plan.Trades[1].Amount != 10000 || plan.Trades[1].Error == "" {
+		t.Errorf("Test Failed - Plan() expected unrouted XRP buy %v", plan.Trades[1])
+	}
+	if plan.EstimatedCost != 1 || len(routed) != 2 {
+		t.Errorf("Test Failed - Plan() unexpected estimated cost %f", plan.EstimatedCost)
+	}
+
+	r.DriftThreshold = 0
+	plan = r.Plan(&p, testValuer(), route, now.Add(time.Minute*30))
+	if plan.Trigger != "" || len(plan.Trades) != 0 {
+		t.Error("Test Failed - Plan() unexpected trigger before calendar interval", plan.Trigger)
+	}
+
+	plan = r.Plan(&p, testValuer(), route, now.Add(time.Hour+time.Minute))
+	if plan.Trigger != RebalanceTriggerCalendar || len(plan.Trades) != 2 {
+		t.Error("Test Failed - Plan() expected calendar trigger", plan.Trigger)
+	}
+
+	RecordRebalancePlan(RebalancePlan{Time: now})
+	RecordRebalancePlan(plan)
+	plans := GetRebalancePlans()
+	if len(plans) != 2 || plans[0].Trigger != RebalanceTriggerCalendar {
+		t.Error("Test Failed - GetRebalancePlans() unexpected plans", plans)
+	}
+}
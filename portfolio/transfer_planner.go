@@ -0,0 +1,87 @@
+package portfolio
+
+import (
+	"math"
+	"sort"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// InventoryTransfer holds a transfer of a currency between two exchanges
+// planned to even out the inventory held on them
+type InventoryTransfer struct {
+	Currency string  `json:"currency"`
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Amount   float64 `json:"amount"`
+}
+
+// PlanInventoryTransfers returns the transfers moving a currency from the
+// exchanges holding more than an even share of the total balance to those
+// holding less. No transfers are planned until the balance of an exchange
+// deviates from its share by at least threshold, a fraction of the total
+func PlanInventoryTransfers(currency string, balances map[string]float64, threshold float64) []InventoryTransfer {
+	if len(balances) < 2 {
+		return nil
+	}
+
+	type deviation struct {
+		exchange string
+		amount   float64
+	}
+
+	var total float64
+	for _, balance := range balances {
+		total += balance
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	share := total / float64(len(balances))
+	var surpluses, deficits []deviation
+	var maxDeviation float64
+	for exchange, balance := range balances {
+		d := balance - share
+		maxDeviation = math.Max(maxDeviation, math.Abs(d)/total)
+		if d > 0 {
+			surpluses = append(surpluses, deviation{exchange, d})
+		} else if d < 0 {
+			deficits = append(deficits, deviation{exchange, -d})
+		}
+	}
+
+	if maxDeviation < threshold || maxDeviation == 0 {
+		return nil
+	}
+
+	for _, d := range [][]deviation{surpluses, deficits} {
+		sort.Slice(d, func(i, j int) bool {
+			if d[i].amount != d[j].amount {
+				return d[i].amount > d[j].amount
+			}
+			return d[i].exchange < d[j].exchange
+		})
+	}
+
+	var transfers []InventoryTransfer
+	for x, y := 0, 0; x < len(surpluses) && y < len(deficits); {
+		amount := math.Min(surpluses[x].amount, deficits[y].amount)
+		transfers = append(transfers, InventoryTransfer{
+			Currency: common.StringToUpper(currency),
+			From:     surpluses[x].exchange,
+			To:       deficits[y].exchange,
+			Amount:   amount,
+		})
+
+		surpluses[x].amount -= amount
+		deficits[y].amount -= amount
+		if surpluses[x].amount <= 0 {
+			x++
+		}
+		if deficits[y].amount <= 0 {
+			y++
+		}
+	}
+	return transfers
+}
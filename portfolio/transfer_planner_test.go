@@ -0,0 +1,28 @@
+package portfolio
+
+import (
+	"testing"
+)
+
+func TestPlanInventoryTransfers(t *testing.T) {
+	balances := map[string]float64{"Bitstamp": 8, "Kraken": 2}
+	if transfers := PlanInventoryTransfers("btc", balances, 0.5); len(transfers) != 0 {
+		t.Errorf("Test Failed - PlanInventoryTransfers() expected no transfers within threshold, got %+v", transfers)
+	}
+
+	transfers := PlanInventoryTransfers("btc", balances, 0.2)
+	if len(transfers) != 1 || transfers[0].Currency != "BTC" || transfers[0].From != "Bitstamp" ||
+		transfers[0].To != "Kraken" || transfers[0].Amount != 3 {
+		t.Errorf("Test Failed - PlanInventoryTransfers() unexpected transfers %+v", transfers)
+	}
+
+	transfers = PlanInventoryTransfers("BTC", map[string]float64{"Bitstamp": 9, "Kraken": 0, "Gemini": 0}, 0.1)
+	if len(transfers) != 2 || transfers[0].To != "Gemini" || transfers[0].Amount != 3 ||
+		transfers[1].To != "Kraken" || transfers[1].Amount != 3 {
+		t.Errorf("Test Failed - PlanInventoryTransfers() unexpected transfers %+v", transfers)
+	}
+
+	if transfers = PlanInventoryTransfers("BTC", map[string]float64{"Bitstamp": 9}, 0); len(transfers) != 0 {
+		t.Errorf("Test Failed - PlanInventoryTransfers() expected no transfers with one exchange, got %+v", transfers)
+	}
+}
@@ -0,0 +1,157 @@
+package portfolio
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// DefaultIntermediaryCurrencies are the currencies tried in order when a
+// holding has no direct rate to the valuation base currency
+var DefaultIntermediaryCurrencies = []string{"USD", "USDT", "BTC", "ETH", "EUR"}
+
+// ErrNoValuationRate is returned when a currency can't be converted to the
+// valuation base currency directly, by forex or through an intermediary
+var ErrNoValuationRate = errors.New("no rate available to convert currency to the valuation base currency")
+
+// PriceSource returns the last price of the first currency in the second
+// currency, ok is false when no price is available
+type PriceSource func(first, second string) (price float64, ok bool)
+
+// ForexConverter converts an amount between two currencies using forex rates,
+// returning an error when either currency isn't a supported fiat currency
+type ForexConverter func(amount float64, from, to string) (float64, error)
+
+// Valuer converts holdings into a single base currency using live prices,
+// falling back to forex rates and then to a cross rate through each of the
+// intermediary currencies
+type Valuer struct {
+	BaseCurrency  string
+	Prices        PriceSource
+	Forex         ForexConverter
+	Intermediates []string
+}
+
+// NewValuer returns a valuer for the base currency using the default
+// intermediary currencies
+func NewValuer(baseCurrency string, prices PriceSource, forex ForexConverter) *Valuer {
+	return &Valuer{
+		BaseCurrency:  common.StringToUpper(baseCurrency),
+		Prices:        prices,
+		Forex:         forex,
+		Intermediates: DefaultIntermediaryCurrencies,
+	}
+}
+
+// directRate returns the rate to convert one unit of from into to using the
+// pair price, the inverse pair price or forex rates
+func (v *Valuer) directRate(from, to string) (float64, bool) {
+	if from == to {
+		return 1, true
+	}
+
+	if v.Prices != nil {
+		if price, ok := v.Prices(from, to); ok && price > 0 {
+			return price, true
+		}
+		if price, ok := v.Prices(to, from); ok && price > 0 {
+			return 1 / price, true
+		}
+	}
+
+	if v.Forex != nil {
+		rate, err := v.Forex(1, from, to)
+		if err == nil && rate > 0 {
+			return rate, true
+		}
+	}
+	return 0, false
+}
+
+// Rate returns the rate to convert one unit of a currency into the base
+// currency
+func (v *Valuer) Rate(currency string) (float64, error) {
+	currency = common.StringToUpper(currency)
+	if rate, ok := v.directRate(currency, v.BaseCurrency); ok {
+		return rate, nil
+	}
+
+	for _, intermediate := range v.Intermediates {
+		intermediate = common.StringToUpper(intermediate)
+		if intermediate == currency || intermediate == v.BaseCurrency {
+			continue
+		}
+
+		first, ok := v.directRate(currency, intermediate)
+		if !ok {
+			continue
+		}
+
+		second, ok := v.directRate(intermediate, v.BaseCurrency)
+		if !ok {
+			continue
+		}
+		return first * second, nil
+	}
+	return 0, ErrNoValuationRate
+}
+
+// ValuedCoin stores a coin balance with its value in the valuation base
+// currency and its percentage of the total value
+type ValuedCoin struct {
+	Coin       string  `json:"coin"`
+	Balance    float64 `json:"balance"`
+	Rate       float64 `json:"rate"`
+	Value      float64 `json:"value"`
+	Percentage float64 `json:"percentage"`
+}
+
+// Valuation stores the portfolio holdings valued in a single base currency.
+// Unpriced lists the coins which couldn't be converted and are excluded from
+// the total
+type Valuation struct {
+	BaseCurrency string       `json:"baseCurrency"`
+	Total        float64      `json:"total"`
+	Holdings     []ValuedCoin `json:"holdings"`
+	Unpriced     []string     `json:"unpriced,omitempty"`
+}
+
+// GetValuation returns the combined offline and exchange holdings valued in
+// the base currency of the valuer, ordered by value
+func (p *Base) GetValuation(v *Valuer) Valuation {
+	totals := make(map[string]float64)
+	for _, x := range p.Addresses {
+		totals[common.StringToUpper(x.CoinType)] += x.Balance
+	}
+
+	result := Valuation{BaseCurrency: v.BaseCurrency}
+	for coin, balance := range totals {
+		rate, err := v.Rate(coin)
+		if err != nil {
+			result.Unpriced = append(result.Unpriced, coin)
+			continue
+		}
+
+		value := balance * rate
+		result.Holdings = append(result.Holdings, ValuedCoin{
+			Coin:    coin,
+			Balance: balance,
+			Rate:    rate,
+			Value:   value,
+		})
+		result.Total += value
+	}
+
+	for x := range result.Holdings {
+		if result.Total > 0 {
+			result.Holdings[x].Percentage = result.Holdings[x].Value / result.Total * 100
+		}
+	}
+
+	sort.Slice(result.Holdings, func(i, j int) bool {
+		return result.Holdings[i].Value > result.Holdings[j].Value
+	})
+	sort.Strings(result.Unpriced)
+	return result
+}
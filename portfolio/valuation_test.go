@@ -0,0 +1,88 @@
+package portfolio
+
+import (
+	"errors"
+	"testing"
+)
+
+func testValuer() *Valuer {
+	prices := map[string]float64{
+		"BTCUSD":  10000,
+		"USDTUSD": 1,
+		"ETHBTC":  0.05,
+		"XRPUSDT": 0.5,
+	}
+	return NewValuer("usd",
+		func(first, second string) (float64, bool) {
+			price, ok := prices[first+second]
+			return price, ok
+		},
+		func(amount float64, from, to string) (float64, error) {
+			if from == "AUD" && to == "USD" {
+				return amount * 0.7, nil
+			}
+			return 0, errors.New("unsupported currency")
+		})
+}
+
+func TestValuerRate(t *testing.T) {
+	v := testValuer()
+
+	tests := []struct {
+		currency string
+		rate     float64
+	}{
+		{"USD", 1},
+		{"btc", 10000},
+		{"AUD", 0.7},
+		{"ETH", 500},
+		{"XRP", 0.5},
+	}
+
+	for _, test := range tests {
+		rate, err := v.Rate(test.currency)
+		if err != nil {
+			t.Errorf("Test Failed - Rate() %s error %s", test.currency, err)
+			continue
+		}
+		if rate != test.rate {
+			t.Errorf("Test Failed - Rate() %s expected %f received %f",
+				test.currency, test.rate, rate)
+		}
+	}
+
+	_, err := v.Rate("DOGE")
+	if err != ErrNoValuationRate {
+		t.Error("Test Failed - Rate() missing rate error", err)
+	}
+
+	v.BaseCurrency = "BTC"
+	rate, err := v.Rate("USD")
+	if err != nil || rate != 0.0001 {
+		t.Errorf("Test Failed - Rate() inverse rate expected 0.0001 received %f %v",
+			rate, err)
+	}
+}
+
+func TestGetValuation(t *testing.T) {
+	var p Base
+	p.AddAddress("Bitstamp", "BTC", PortfolioAddressExchange, 1)
+	p.AddAddress("someaddress", "BTC", PortfolioAddressPersonal, 1)
+	p.AddAddress("Kraken", "ETH", PortfolioAddressExchange, 10)
+	p.AddAddress("Kraken", "DOGE", PortfolioAddressExchange, 100)
+
+	result := p.GetValuation(testValuer())
+	if result.BaseCurrency != "USD" || result.Total != 25000 {
+		t.Errorf("Test Failed - GetValuation() unexpected total %s %f",
+			result.BaseCurrency, result.Total)
+	}
+
+	if len(result.Holdings) != 2 || result.Holdings[0].Coin != "BTC" ||
+		result.Holdings[0].Percentage != 80 {
+		t.Errorf("Test Failed - GetValuation() unexpected holdings %+v", result.Holdings)
+	}
+
+	if len(result.Unpriced) != 1 || result.Unpriced[0] != "DOGE" {
+		t.Errorf("Test Failed - GetValuation() unexpected unpriced coins %v", result.Unpriced)
+	}
+}
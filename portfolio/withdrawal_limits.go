@@ -0,0 +1,269 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Withdrawal limit windows
+const (
+	WithdrawalWindowDaily   = time.Hour * 24
+	WithdrawalWindowMonthly = time.Hour * 24 * 30
+)
+
+// Vars for the withdrawal limit registry
+var (
+	withdrawalLimits    []WithdrawalLimit
+	withdrawalRecords   []withdrawalRecord
+	withdrawalRecordID  int64
+	withdrawalLimitsMtx sync.Mutex
+
+	// ErrInvalidWithdrawalLimit is returned when a withdrawal limit is missing
+	// required details
+	ErrInvalidWithdrawalLimit = errors.New("withdrawal limit requires an exchange, currency, positive amount and window")
+)
+
+// WithdrawalLimit holds the maximum amount of a currency which can be
+// withdrawn from an exchange over a rolling window
+type WithdrawalLimit struct {
+	Exchange string        `json:"exchange"`
+	Currency string        `json:"currency"`
+	Amount   float64       `json:"amount"`
+	Window   time.Duration `json:"window"`
+}
+
+// WithdrawalQuota holds the amount of a withdrawal limit used by the bot
+// within the current window and the amount remaining
+type WithdrawalQuota struct {
+	WithdrawalLimit
+	Used      float64 `json:"used"`
+	Remaining float64 `json:"remaining"`
+}
+
+// WithdrawalLimitError is returned when a withdrawal would exceed the
+// remaining quota of a withdrawal limit
+type WithdrawalLimitError struct {
+	Quota  WithdrawalQuota
+	Amount float64
+}
+
+func (e *WithdrawalLimitError) Error() string {
+	return fmt.Sprintf("withdrawal of %v %s on %s exceeds the remaining quota of %v for the %v limit of %v",
+		e.Amount, e.Quota.Currency, e.Quota.Exchange, e.Quota.Remaining,
+		e.Quota.Window, e.Quota.Amount)
+}
+
+// withdrawalRecord holds a withdrawal made by the bot
+type withdrawalRecord struct {
+	ID        int64
+	Exchange  string
+	Currency  string
+	Amount    float64
+	Timestamp time.Time
+}
+
+func (w *WithdrawalLimit) matches(exchange, currency string) bool {
+	return common.StringToLower(w.Exchange) == common.StringToLower(exchange) &&
+		common.StringToUpper(w.Currency) == common.StringToUpper(currency)
+}
+
+func validateWithdrawalLimit(limit WithdrawalLimit) error {
+	if limit.Exchange == "" || limit.Currency == "" || limit.Amount <= 0 || limit.Window <= 0 {
+		return ErrInvalidWithdrawalLimit
+	}
+	return nil
+}
+
+// SetWithdrawalLimits replaces the withdrawal limits of every exchange
+func SetWithdrawalLimits(limits []WithdrawalLimit) error {
+	for x := range limits {
+		err := validateWithdrawalLimit(limits[x])
+		if err != nil {
+			return fmt.Errorf("withdrawal limit %d: %s", x, err)
+		}
+	}
+
+	withdrawalLimitsMtx.Lock()
+	withdrawalLimits = append([]WithdrawalLimit(nil), limits...)
+	withdrawalLimitsMtx.Unlock()
+	return nil
+}
+
+// SetExchangeWithdrawalLimits replaces the withdrawal limits of an exchange,
+// such as limits fetched from the exchange API
+func SetExchangeWithdrawalLimits(exchange string, limits []WithdrawalLimit) error {
+	for x := range limits {
+		limits[x].Exchange = exchange
+		err := validateWithdrawalLimit(limits[x])
+		if err != nil {
+			return fmt.Errorf("withdrawal limit %d: %s", x, err)
+		}
+	}
+
+	withdrawalLimitsMtx.Lock()
+	defer withdrawalLimitsMtx.Unlock()
+
+	var kept []WithdrawalLimit
+	for x := range withdrawalLimits {
+		if common.StringToLower(withdrawalLimits[x].Exchange) != common.StringToLower(exchange) {
+			kept = append(kept, withdrawalLimits[x])
+		}
+	}
+	withdrawalLimits = append(kept, limits...)
+	return nil
+}
+
+// GetWithdrawalLimits returns the withdrawal limits of an exchange, a blank
+// exchange returns the limits of every exchange
+func GetWithdrawalLimits(exchange string) []WithdrawalLimit {
+	withdrawalLimitsMtx.Lock()
+	defer withdrawalLimitsMtx.Unlock()
+
+	var limits []WithdrawalLimit
+	for x := range withdrawalLimits {
+		if exchange != "" && common.StringToLower(withdrawalLimits[x].Exchange) != common.StringToLower(exchange) {
+			continue
+		}
+		limits = append(limits, withdrawalLimits[x])
+	}
+	return limits
+}
+
+// GetWithdrawalQuotas returns the used and remaining quota of each withdrawal
+// limit of an exchange, a blank exchange returns the quotas of every exchange
+func GetWithdrawalQuotas(exchange string) []WithdrawalQuota {
+	withdrawalLimitsMtx.Lock()
+	defer withdrawalLimitsMtx.Unlock()
+
+	now := time.Now()
+	var quotas []WithdrawalQuota
+	for x := range withdrawalLimits {
+		if exchange != "" && common.StringToLower(withdrawalLimits[x].Exchange) != common.StringToLower(exchange) {
+			continue
+		}
+		quotas = append(quotas, getWithdrawalQuota(withdrawalLimits[x], now))
+	}
+	return quotas
+}
+
+// getWithdrawalQuota returns the quota of a withdrawal limit at a point in
+// time, withdrawalLimitsMtx must be held by the caller
+func getWithdrawalQuota(limit WithdrawalLimit, now time.Time) WithdrawalQuota {
+	quota := WithdrawalQuota{WithdrawalLimit: limit}
+	for x := range withdrawalRecords {
+		if !limit.matches(withdrawalRecords[x].Exchange, withdrawalRecords[x].Currency) ||
+			now.Sub(withdrawalRecords[x].Timestamp) >= limit.Window {
+			continue
+		}
+		quota.Used += withdrawalRecords[x].Amount
+	}
+
+	quota.Remaining = limit.Amount - quota.Used
+	if quota.Remaining < 0 {
+		quota.Remaining = 0
+	}
+	return quota
+}
+
+// CheckWithdrawalLimits returns a WithdrawalLimitError if withdrawing an
+// amount of a currency from an exchange would exceed the remaining quota of
+// any of its withdrawal limits. Currencies without limits are not checked
+func CheckWithdrawalLimits(exchange, currency string, amount float64) error {
+	withdrawalLimitsMtx.Lock()
+	defer withdrawalLimitsMtx.Unlock()
+	return checkWithdrawalLimits(exchange, currency, amount, time.Now())
+}
+
+// checkWithdrawalLimits checks an amount against the remaining quota of the
+// withdrawal limits at a point in time, withdrawalLimitsMtx must be held by the
+// caller
+func checkWithdrawalLimits(exchange, currency string, amount float64, now time.Time) error {
+	for x := range withdrawalLimits {
+		if !withdrawalLimits[x].matches(exchange, currency) {
+			continue
+		}
+
+		quota := getWithdrawalQuota(withdrawalLimits[x], now)
+		if amount > quota.Remaining {
+			return &WithdrawalLimitError{Quota: quota, Amount: amount}
+		}
+	}
+	return nil
+}
+
+// ReserveWithdrawal checks an amount against the withdrawal limits of the
+// exchange and records it as withdrawn in one step, so concurrent withdrawals
+// can't both pass the check before either is recorded. The returned
+// reservation ID releases the amount again with ReleaseWithdrawal if the
+// exchange rejects the withdrawal
+func ReserveWithdrawal(exchange, currency string, amount float64) (int64, error) {
+	withdrawalLimitsMtx.Lock()
+	defer withdrawalLimitsMtx.Unlock()
+
+	now := time.Now()
+	err := checkWithdrawalLimits(exchange, currency, amount, now)
+	if err != nil {
+		return 0, err
+	}
+	return recordWithdrawal(exchange, currency, amount, now), nil
+}
+
+// ReleaseWithdrawal removes a reserved withdrawal from the records, restoring
+// its amount to the quota of the withdrawal limits
+func ReleaseWithdrawal(id int64) {
+	withdrawalLimitsMtx.Lock()
+	defer withdrawalLimitsMtx.Unlock()
+
+	for x := range withdrawalRecords {
+		if withdrawalRecords[x].ID == id {
+			withdrawalRecords = append(withdrawalRecords[:x], withdrawalRecords[x+1:]...)
+			return
+		}
+	}
+}
+
+// RecordWithdrawal records a withdrawal made by the bot against the
+// withdrawal limits of the exchange. Records older than the longest window
+// are discarded
+func RecordWithdrawal(exchange, currency string, amount float64, timestamp time.Time) {
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	withdrawalLimitsMtx.Lock()
+	defer withdrawalLimitsMtx.Unlock()
+	recordWithdrawal(exchange, currency, amount, timestamp)
+}
+
+// recordWithdrawal appends a withdrawal record, discarding records older than
+// the longest window, and returns its ID. withdrawalLimitsMtx must be held by
+// the caller
+func recordWithdrawal(exchange, currency string, amount float64, timestamp time.Time) int64 {
+	longest := WithdrawalWindowMonthly
+	for x := range withdrawalLimits {
+		if withdrawalLimits[x].Window > longest {
+			longest = withdrawalLimits[x].Window
+		}
+	}
+
+	var kept []withdrawalRecord
+	for x := range withdrawalRecords {
+		if time.Since(withdrawalRecords[x].Timestamp) < longest {
+			kept = append(kept, withdrawalRecords[x])
+		}
+	}
+
+	withdrawalRecordID++
+	withdrawalRecords = append(kept, withdrawalRecord{
+		ID:        withdrawalRecordID,
+		Exchange:  exchange,
+		Currency:  currency,
+		Amount:    amount,
+		Timestamp: timestamp,
+	})
+	return withdrawalRecordID
+}
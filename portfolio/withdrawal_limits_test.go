@@ -0,0 +1,133 @@
+package portfolio
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetWithdrawalLimits(t *testing.T) {
+	defer SetWithdrawalLimits(nil)
+
+	err := SetWithdrawalLimits([]WithdrawalLimit{{Exchange: "Bitstamp", Currency: "BTC"}})
+	if err == nil {
+		t.Error("Test Failed - SetWithdrawalLimits() invalid limit error")
+	}
+
+	err = SetWithdrawalLimits([]WithdrawalLimit{
+		{Exchange: "Bitstamp", Currency: "BTC", Amount: 1, Window: WithdrawalWindowDaily},
+		{Exchange: "Huobi", Currency: "BTC", Amount: 2, Window: WithdrawalWindowDaily},
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SetWithdrawalLimits() error", err)
+	}
+
+	err = SetExchangeWithdrawalLimits("huobi", []WithdrawalLimit{
+		{Currency: "ETH", Amount: 5, Window: WithdrawalWindowMonthly},
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SetExchangeWithdrawalLimits() error", err)
+	}
+
+	limits := GetWithdrawalLimits("Huobi")
+	if len(limits) != 1 || limits[0].Currency != "ETH" || limits[0].Exchange != "huobi" {
+		t.Errorf("Test Failed - GetWithdrawalLimits() unexpected limits %+v", limits)
+	}
+
+	if len(GetWithdrawalLimits("")) != 2 {
+		t.Error("Test Failed - GetWithdrawalLimits() unexpected limit count")
+	}
+}
+
+func TestCheckWithdrawalLimits(t *testing.T) {
+	defer SetWithdrawalLimits(nil)
+
+	err := SetWithdrawalLimits([]WithdrawalLimit{
+		{Exchange: "Kraken", Currency: "LTC", Amount: 10, Window: WithdrawalWindowDaily},
+		{Exchange: "Kraken", Currency: "LTC", Amount: 14, Window: WithdrawalWindowMonthly},
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SetWithdrawalLimits() error", err)
+	}
+
+	RecordWithdrawal("kraken", "ltc", 6, time.Now())
+	RecordWithdrawal("Kraken", "LTC", 5, time.Now().Add(-WithdrawalWindowDaily*2))
+
+	if CheckWithdrawalLimits("Kraken", "LTC", 3) != nil {
+		t.Error("Test Failed - CheckWithdrawalLimits() refused withdrawal within quota")
+	}
+
+	err = CheckWithdrawalLimits("Kraken", "LTC", 3.5)
+	limitErr, ok := err.(*WithdrawalLimitError)
+	if !ok || limitErr.Quota.Window != WithdrawalWindowMonthly || limitErr.Quota.Remaining != 3 {
+		t.Error("Test Failed - CheckWithdrawalLimits() expected monthly limit error", err)
+	}
+
+	if CheckWithdrawalLimits("Kraken", "BTC", 100) != nil {
+		t.Error("Test Failed - CheckWithdrawalLimits() checked currency without limits")
+	}
+
+	quotas := GetWithdrawalQuotas("Kraken")
+	if len(quotas) != 2 || quotas[0].Used != 6 || quotas[0].Remaining != 4 || quotas[1].Used != 11 {
+		t.Errorf("Test Failed - GetWithdrawalQuotas() unexpected quotas %+v", quotas)
+	}
+}
+
+func TestReserveWithdrawal(t *testing.T) {
+	defer SetWithdrawalLimits(nil)
+
+	err := SetWithdrawalLimits([]WithdrawalLimit{
+		{Exchange: "Gemini", Currency: "ETH", Amount: 10, Window: WithdrawalWindowDaily},
+	})
+	if err != nil {
+		t.Fatal("Test Failed - SetWithdrawalLimits() error", err)
+	}
+
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var reservations []int64
+	for x := 0; x < 20; x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := ReserveWithdrawal("Gemini", "ETH", 1)
+			if err != nil {
+				if _, ok := err.(*WithdrawalLimitError); !ok {
+					t.Error("Test Failed - ReserveWithdrawal() unexpected error", err)
+				}
+				return
+			}
+			mtx.Lock()
+			reservations = append(reservations, id)
+			mtx.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	defer func() {
+		for x := range reservations {
+			ReleaseWithdrawal(reservations[x])
+		}
+	}()
+
+	if len(reservations) != 10 {
+		t.Fatalf("Test Failed - ReserveWithdrawal() reserved %d withdrawals, expected 10",
+			len(reservations))
+	}
+
+	_, err = ReserveWithdrawal("Gemini", "ETH", 1)
+	if err == nil {
+		t.Error("Test Failed - ReserveWithdrawal() reserved beyond the limit")
+	}
+
+	ReleaseWithdrawal(reservations[0])
+	quotas := GetWithdrawalQuotas("Gemini")
+	if len(quotas) != 1 || quotas[0].Used != 9 || quotas[0].Remaining != 1 {
+		t.Errorf("Test Failed - ReleaseWithdrawal() unexpected quotas %+v", quotas)
+	}
+
+	reservations[0], err = ReserveWithdrawal("Gemini", "ETH", 1)
+	if err != nil {
+		t.Error("Test Failed - ReserveWithdrawal() refused released quota", err)
+	}
+}
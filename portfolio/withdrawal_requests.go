@@ -0,0 +1,237 @@
+package portfolio
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Withdrawal request statuses
+const (
+	WithdrawalRequestAwaitingConfirmation = "AWAITING_CONFIRMATION"
+	WithdrawalRequestApproved             = "APPROVED"
+	WithdrawalRequestSubmitted            = "SUBMITTED"
+	WithdrawalRequestFailed               = "FAILED"
+	WithdrawalRequestRejected             = "REJECTED"
+	WithdrawalRequestCancelled            = "CANCELLED"
+	WithdrawalRequestExpired              = "EXPIRED"
+)
+
+// Withdrawal request confirmation limits
+const (
+	WithdrawalConfirmationTimeout     = time.Minute * 10
+	WithdrawalConfirmationMaxAttempts = 3
+
+	// confirmationCodeLifetime is the longest a one time password stays
+	// valid, allowing for clock drift
+	confirmationCodeLifetime = time.Minute * 2
+)
+
+// Vars for the withdrawal request store
+var (
+	withdrawalRequests    []WithdrawalRequest
+	withdrawalRequestID   int64
+	withdrawalRequestsMtx sync.Mutex
+	usedConfirmationCodes = make(map[string]time.Time)
+
+	// ErrWithdrawalRequestNotFound is returned when a withdrawal request does
+	// not exist
+	ErrWithdrawalRequestNotFound = errors.New("withdrawal request not found")
+
+	// ErrWithdrawalRequestNotPending is returned when a withdrawal request is
+	// no longer awaiting confirmation
+	ErrWithdrawalRequestNotPending = errors.New("withdrawal request is not awaiting confirmation")
+
+	// ErrWithdrawalConfirmationFailed is returned when the second factor of a
+	// withdrawal request confirmation is invalid
+	ErrWithdrawalConfirmationFailed = errors.New("withdrawal request confirmation failed")
+
+	// ErrInvalidWithdrawalRequest is returned when a withdrawal request is
+	// missing required details
+	ErrInvalidWithdrawalRequest = errors.New("withdrawal request requires an exchange, currency, address and positive amount")
+)
+
+// WithdrawalRequest holds a crypto withdrawal requested through the
+// management API and its progress through the approval workflow. WithdrawalID
+// is the ID returned by the exchange once the withdrawal is submitted
+type WithdrawalRequest struct {
+	ID                   int64     `json:"id"`
+	Exchange             string    `json:"exchangeName"`
+	Currency             string    `json:"currency"`
+	Address              string    `json:"address"`
+	Amount               float64   `json:"amount"`
+	Status               string    `json:"status"`
+	ConfirmationAttempts int       `json:"confirmationAttempts"`
+	WithdrawalID         string    `json:"withdrawalId,omitempty"`
+	Error                string    `json:"error,omitempty"`
+	Created              time.Time `json:"created"`
+	Updated              time.Time `json:"updated"`
+}
+
+// AddWithdrawalRequest stores a new withdrawal request, awaiting confirmation
+// when requireConfirmation is set and approved otherwise
+func AddWithdrawalRequest(req WithdrawalRequest, requireConfirmation bool) (WithdrawalRequest, error) {
+	if req.Exchange == "" || req.Currency == "" || req.Address == "" || req.Amount <= 0 {
+		return WithdrawalRequest{}, ErrInvalidWithdrawalRequest
+	}
+
+	withdrawalRequestsMtx.Lock()
+	defer withdrawalRequestsMtx.Unlock()
+
+	withdrawalRequestID++
+	req.ID = withdrawalRequestID
+	req.Status = WithdrawalRequestApproved
+	if requireConfirmation {
+		req.Status = WithdrawalRequestAwaitingConfirmation
+	}
+	req.ConfirmationAttempts = 0
+	req.WithdrawalID = ""
+	req.Error = ""
+	req.Created = time.Now()
+	req.Updated = req.Created
+
+	withdrawalRequests = append(withdrawalRequests, req)
+	return req, nil
+}
+
+// GetWithdrawalRequests returns all withdrawal requests
+func GetWithdrawalRequests() []WithdrawalRequest {
+	withdrawalRequestsMtx.Lock()
+	defer withdrawalRequestsMtx.Unlock()
+
+	expireWithdrawalRequests(time.Now())
+	return append([]WithdrawalRequest(nil), withdrawalRequests...)
+}
+
+// GetWithdrawalRequest returns a withdrawal request by ID
+func GetWithdrawalRequest(id int64) (WithdrawalRequest, error) {
+	withdrawalRequestsMtx.Lock()
+	defer withdrawalRequestsMtx.Unlock()
+
+	expireWithdrawalRequests(time.Now())
+	x := getWithdrawalRequestIndex(id)
+	if x == -1 {
+		return WithdrawalRequest{}, ErrWithdrawalRequestNotFound
+	}
+	return withdrawalRequests[x], nil
+}
+
+// ConfirmWithdrawalRequest approves a withdrawal request awaiting
+// confirmation when confirmed is set. A failed confirmation is counted
+// against the request, which is rejected once it reaches the maximum attempts
+func ConfirmWithdrawalRequest(id int64, confirmed bool) (WithdrawalRequest, error) {
+	withdrawalRequestsMtx.Lock()
+	defer withdrawalRequestsMtx.Unlock()
+
+	expireWithdrawalRequests(time.Now())
+	x := getWithdrawalRequestIndex(id)
+	if x == -1 {
+		return WithdrawalRequest{}, ErrWithdrawalRequestNotFound
+	}
+
+	req := &withdrawalRequests[x]
+	if req.Status != WithdrawalRequestAwaitingConfirmation {
+		return *req, ErrWithdrawalRequestNotPending
+	}
+
+	req.Updated = time.Now()
+	if !confirmed {
+		req.ConfirmationAttempts++
+		if req.ConfirmationAttempts >= WithdrawalConfirmationMaxAttempts {
+			req.Status = WithdrawalRequestRejected
+		}
+		return *req, ErrWithdrawalConfirmationFailed
+	}
+
+	req.Status = WithdrawalRequestApproved
+	return *req, nil
+}
+
+// UseConfirmationCode records a one time password used to confirm a
+// withdrawal request, returning false if it has already been used while still
+// valid so codes can't be replayed
+func UseConfirmationCode(code string, now time.Time) bool {
+	withdrawalRequestsMtx.Lock()
+	defer withdrawalRequestsMtx.Unlock()
+
+	for used, t := range usedConfirmationCodes {
+		if now.Sub(t) > confirmationCodeLifetime {
+			delete(usedConfirmationCodes, used)
+		}
+	}
+
+	if _, ok := usedConfirmationCodes[code]; ok {
+		return false
+	}
+	usedConfirmationCodes[code] = now
+	return true
+}
+
+// SetWithdrawalRequestResult records the outcome of submitting an approved
+// withdrawal request to its exchange
+func SetWithdrawalRequestResult(id int64, withdrawalID string, err error) (WithdrawalRequest, error) {
+	withdrawalRequestsMtx.Lock()
+	defer withdrawalRequestsMtx.Unlock()
+
+	x := getWithdrawalRequestIndex(id)
+	if x == -1 {
+		return WithdrawalRequest{}, ErrWithdrawalRequestNotFound
+	}
+
+	req := &withdrawalRequests[x]
+	req.Updated = time.Now()
+	if err != nil {
+		req.Status = WithdrawalRequestFailed
+		req.Error = err.Error()
+		return *req, nil
+	}
+
+	req.Status = WithdrawalRequestSubmitted
+	req.WithdrawalID = withdrawalID
+	return *req, nil
+}
+
+// CancelWithdrawalRequest cancels a withdrawal request awaiting confirmation
+func CancelWithdrawalRequest(id int64) (WithdrawalRequest, error) {
+	withdrawalRequestsMtx.Lock()
+	defer withdrawalRequestsMtx.Unlock()
+
+	expireWithdrawalRequests(time.Now())
+	x := getWithdrawalRequestIndex(id)
+	if x == -1 {
+		return WithdrawalRequest{}, ErrWithdrawalRequestNotFound
+	}
+
+	req := &withdrawalRequests[x]
+	if req.Status != WithdrawalRequestAwaitingConfirmation {
+		return *req, ErrWithdrawalRequestNotPending
+	}
+
+	req.Status = WithdrawalRequestCancelled
+	req.Updated = time.Now()
+	return *req, nil
+}
+
+// getWithdrawalRequestIndex returns the index of a withdrawal request or -1,
+// withdrawalRequestsMtx must be held by the caller
+func getWithdrawalRequestIndex(id int64) int {
+	for x := range withdrawalRequests {
+		if withdrawalRequests[x].ID == id {
+			return x
+		}
+	}
+	return -1
+}
+
+// expireWithdrawalRequests expires requests which have awaited confirmation
+// longer than the confirmation timeout, withdrawalRequestsMtx must be held by
+// the caller
+func expireWithdrawalRequests(now time.Time) {
+	for x := range withdrawalRequests {
+		if withdrawalRequests[x].Status == WithdrawalRequestAwaitingConfirmation &&
+			now.Sub(withdrawalRequests[x].Created) > WithdrawalConfirmationTimeout {
+			withdrawalRequests[x].Status = WithdrawalRequestExpired
+			withdrawalRequests[x].Updated = now
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package portfolio
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithdrawalRequestConfirmation(t *testing.T) {
+	_, err := AddWithdrawalRequest(WithdrawalRequest{Exchange: "Bitstamp", Currency: "BTC"}, true)
+	if err != ErrInvalidWithdrawalRequest {
+		t.Error("Test Failed - AddWithdrawalRequest() expected invalid request error", err)
+	}
+
+	req, err := AddWithdrawalRequest(WithdrawalRequest{Exchange: "Bitstamp", Currency: "BTC",
+		Address: "addr1", Amount: 1}, true)
+	if err != nil || req.Status != WithdrawalRequestAwaitingConfirmation {
+		t.Fatal("Test Failed - AddWithdrawalRequest() error", err)
+	}
+
+	for x := 1; x < WithdrawalConfirmationMaxAttempts; x++ {
+		_, err = ConfirmWithdrawalRequest(req.ID, false)
+		if err != ErrWithdrawalConfirmationFailed {
+			t.Error("Test Failed - ConfirmWithdrawalRequest() expected confirmation error", err)
+		}
+	}
+
+	req, err = ConfirmWithdrawalRequest(req.ID, true)
+	if err != nil || req.Status != WithdrawalRequestApproved {
+		t.Fatal("Test Failed - ConfirmWithdrawalRequest() error", err)
+	}
+
+	_, err = ConfirmWithdrawalRequest(req.ID, true)
+	if err != ErrWithdrawalRequestNotPending {
+		t.Error("Test Failed - ConfirmWithdrawalRequest() expected not pending error", err)
+	}
+
+	req, err = SetWithdrawalRequestResult(req.ID, "w1", nil)
+	if err != nil || req.Status != WithdrawalRequestSubmitted || req.WithdrawalID != "w1" {
+		t.Errorf("Test Failed - SetWithdrawalRequestResult() unexpected request %+v %v", req, err)
+	}
+
+	req, err = AddWithdrawalRequest(WithdrawalRequest{Exchange: "Bitstamp", Currency: "BTC",
+		Address: "addr1", Amount: 1}, true)
+	if err != nil {
+		t.Fatal("Test Failed - AddWithdrawalRequest() error", err)
+	}
+
+	for x := 0; x < WithdrawalConfirmationMaxAttempts; x++ {
+		ConfirmWithdrawalRequest(req.ID, false)
+	}
+
+	req, _ = GetWithdrawalRequest(req.ID)
+	if req.Status != WithdrawalRequestRejected {
+		t.Error("Test Failed - ConfirmWithdrawalRequest() request not rejected", req.Status)
+	}
+}
+
+func TestWithdrawalRequestLifecycle(t *testing.T) {
+	req, err := AddWithdrawalRequest(WithdrawalRequest{Exchange: "Kraken", Currency: "LTC",
+		Address: "addr2", Amount: 2}, false)
+	if err != nil || req.Status != WithdrawalRequestApproved {
+		t.Fatal("Test Failed - AddWithdrawalRequest() error", err)
+	}
+
+	req, _ = SetWithdrawalRequestResult(req.ID, "", errors.New("insufficient funds"))
+	if req.Status != WithdrawalRequestFailed || req.Error != "insufficient funds" {
+		t.Errorf("Test Failed - SetWithdrawalRequestResult() unexpected request %+v", req)
+	}
+
+	req, err = AddWithdrawalRequest(WithdrawalRequest{Exchange: "Kraken", Currency: "LTC",
+		Address: "addr2", Amount: 2}, true)
+	if err != nil {
+		t.Fatal("Test Failed - AddWithdrawalRequest() error", err)
+	}
+
+	req, err = CancelWithdrawalRequest(req.ID)
+	if err != nil || req.Status != WithdrawalRequestCancelled {
+		t.Error("Test Failed - CancelWithdrawalRequest() error", err)
+	}
+
+	_, err = GetWithdrawalRequest(-1)
+	if err != ErrWithdrawalRequestNotFound {
+		t.Error("Test Failed - GetWithdrawalRequest() expected not found error", err)
+	}
+
+	withdrawalRequestsMtx.Lock()
+	x := getWithdrawalRequestIndex(req.ID)
+	withdrawalRequests[x].Status = WithdrawalRequestAwaitingConfirmation
+	withdrawalRequests[x].Created = time.Now().Add(-WithdrawalConfirmationTimeout * 2)
+	withdrawalRequestsMtx.Unlock()
+
+	req, _ = GetWithdrawalRequest(req.ID)
+	if req.Status != WithdrawalRequestExpired {
+		t.Error("Test Failed - GetWithdrawalRequest() request not expired", req.Status)
+	}
+}
+
+func TestUseConfirmationCode(t *testing.T) {
+	now := time.Now()
+	if !UseConfirmationCode("123456", now) {
+		t.Error("Test Failed - UseConfirmationCode() rejected unused code")
+	}
+
+	if UseConfirmationCode("123456", now.Add(time.Minute)) {
+		t.Error("Test Failed - UseConfirmationCode() accepted replayed code")
+	}
+
+	if !UseConfirmationCode("123456", now.Add(time.Minute*5)) {
+		t.Error("Test Failed - UseConfirmationCode() rejected code after it expired")
+	}
+}
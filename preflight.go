@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// Preflight check results
+const (
+	PreflightPass = "PASS"
+	PreflightWarn = "WARN"
+	PreflightFail = "FAIL"
+	PreflightSkip = "SKIP"
+)
+
+const preflightWebsocketTimeout = time.Second * 15
+
+// PreflightCheck holds the result of a single preflight check of an exchange
+type PreflightCheck struct {
+	Exchange string
+	Check    string
+	Result   string
+	Detail   string
+}
+
+// PreflightReport holds the results of the preflight checks of the enabled
+// exchanges
+type PreflightReport struct {
+	Checks []PreflightCheck
+}
+
+func (r *PreflightReport) add(exchName, check, result, detail string) {
+	r.Checks = append(r.Checks, PreflightCheck{
+		Exchange: exchName,
+		Check:    check,
+		Result:   result,
+		Detail:   detail,
+	})
+}
+
+// IsGo returns whether every preflight check passed, warnings and skipped
+// checks don't prevent going live
+func (r *PreflightReport) IsGo() bool {
+	for x := range r.Checks {
+		if r.Checks[x].Result == PreflightFail {
+			return false
+		}
+	}
+	return len(r.Checks) > 0
+}
+
+// String returns the report as a table followed by the go/no-go result
+func (r *PreflightReport) String() string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Exchange\tCheck\tResult\tDetail")
+	for _, x := range r.Checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", x.Exchange, x.Check, x.Result, x.Detail)
+	}
+	w.Flush()
+
+	if r.IsGo() {
+		b.WriteString("\nPreflight result: GO\n")
+	} else {
+		b.WriteString("\nPreflight result: NO-GO\n")
+	}
+	return b.String()
+}
+
+// RunPreflight checks the credentials, pairs, fees and websocket connectivity
+// of each loaded exchange using read only requests, without placing any
+// orders, so a config can be validated before going live
+func RunPreflight() PreflightReport {
+	var report PreflightReport
+	for x := range bot.config.Exchanges {
+		exchCfg := bot.config.Exchanges[x]
+		if !exchCfg.Enabled {
+			continue
+		}
+
+		exch := GetExchangeByName(exchCfg.Name)
+		if exch == nil {
+			report.add(exchCfg.Name, "load", PreflightFail, ErrExchangeFailedToLoad.Error())
+			continue
+		}
+		preflightExchange(&report, exch, &exchCfg)
+	}
+
+	if len(report.Checks) == 0 {
+		report.add("-", "load", PreflightFail, "no enabled exchanges")
+	}
+	return report
+}
+
+// preflightExchange adds the preflight checks of an exchange to the report
+func preflightExchange(report *PreflightReport, exch exchange.IBotExchange, exchCfg *config.ExchangeConfig) {
+	name := exch.GetName()
+	preflightCredentials(report, exch, exchCfg)
+
+	enabled := exch.GetEnabledCurrencies()
+	if len(enabled) == 0 {
+		report.add(name, "pairs", PreflightFail, "no enabled pairs")
+		return
+	}
+
+	var missing []string
+	available := exch.GetAvailableCurrencies()
+	for x := range enabled {
+		if !pair.Contains(available, enabled[x], true) {
+			missing = append(missing, enabled[x].Pair().String())
+		}
+	}
+	if len(missing) > 0 {
+		report.add(name, "pairs", PreflightFail, "not available: "+common.JoinStrings(missing, ","))
+	} else {
+		report.add(name, "pairs", PreflightPass, fmt.Sprintf("%d enabled pairs available", len(enabled)))
+	}
+
+	var err error
+	assetTypes := exch.GetAssetTypes()
+	if len(assetTypes) == 0 {
+		err = errors.New("no asset types")
+	} else {
+		_, err = exch.UpdateTicker(enabled[0], assetTypes[0])
+	}
+	if err != nil {
+		report.add(name, "ticker", PreflightFail, err.Error())
+	} else {
+		report.add(name, "ticker", PreflightPass, enabled[0].Pair().String())
+	}
+
+	feeBuilder := exchange.FeeBuilder{
+		FeeType:        exchange.CryptocurrencyTradeFee,
+		FirstCurrency:  enabled[0].FirstCurrency.String(),
+		SecondCurrency: enabled[0].SecondCurrency.String(),
+		Delimiter:      enabled[0].Delimiter,
+		PurchasePrice:  1,
+		Amount:         1,
+	}
+	fee, err := exch.GetFeeByType(feeBuilder)
+	if err != nil {
+		report.add(name, "fees", PreflightFail, err.Error())
+	} else {
+		detail := fmt.Sprintf("trade fee %v", fee)
+		if exch.IsFeeEstimated(feeBuilder) {
+			detail += " (estimate)"
+		}
+		report.add(name, "fees", PreflightPass, detail)
+	}
+
+	preflightWebsocket(report, exch)
+}
+
+// preflightCredentials adds the API credential check of an exchange to the
+// report, the credentials are verified when the exchange is loaded
+func preflightCredentials(report *PreflightReport, exch exchange.IBotExchange, exchCfg *config.ExchangeConfig) {
+	name := exch.GetName()
+	if !exchCfg.AuthenticatedAPISupport {
+		report.add(name, "credentials", PreflightSkip, "authenticated API support disabled")
+		return
+	}
+
+	permissions := exch.GetAPIPermissions()
+	if !permissions.Verified {
+		report.add(name, "credentials", PreflightFail, "API credentials rejected")
+		return
+	}
+
+	if !permissions.Has(exchange.APIPermissionTrade) {
+		report.add(name, "credentials", PreflightWarn, "API key is missing trade permissions")
+		return
+	}
+	report.add(name, "credentials", PreflightPass, "API credentials verified")
+}
+
+// preflightWebsocket adds the websocket connectivity check of an exchange to
+// the report, connecting and shutting down its websocket
+func preflightWebsocket(report *PreflightReport, exch exchange.IBotExchange) {
+	name := exch.GetName()
+	ws, err := exch.GetWebsocket()
+	if err != nil || !ws.IsEnabled() {
+		report.add(name, "websocket", PreflightSkip, "websocket disabled")
+		return
+	}
+
+	// Websocket data is discarded until the connection is shut down
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ws.DataHandler:
+			}
+		}
+	}()
+
+	connected := make(chan error, 1)
+	go func() { connected <- ws.Connect() }()
+
+	select {
+	case err = <-connected:
+	case <-time.After(preflightWebsocketTimeout):
+		err = errors.New("timed out connecting")
+	}
+	if err != nil {
+		report.add(name, "websocket", PreflightFail, err.Error())
+		return
+	}
+
+	ws.Shutdown()
+	report.add(name, "websocket", PreflightPass, "connected")
+}
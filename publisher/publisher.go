@@ -0,0 +1,367 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// Supported message brokers
+const (
+	BrokerNATS = "nats"
+)
+
+// Message kinds, each published to the topic
+// <prefix>.<kind>.<exchange>.<pair> such as gct.book.bitstamp.BTC-USD
+const (
+	KindTicker    = "ticker"
+	KindTrade     = "trade"
+	KindBookDelta = "book"
+	KindFill      = "fill"
+)
+
+const (
+	queueSize      = 1024
+	reconnectDelay = time.Second * 5
+)
+
+var (
+	// ErrUnsupportedBroker is returned when creating a broker of an
+	// unsupported type
+	ErrUnsupportedBroker = errors.New("unsupported message broker")
+	// ErrNotConnected is returned when publishing to a broker which is not
+	// connected
+	ErrNotConnected = errors.New("message broker not connected")
+)
+
+// Broker publishes messages to the topics of a message broker
+type Broker interface {
+	Connect() error
+	Publish(topic string, data []byte) error
+	Close() error
+}
+
+// Ticker holds a normalised ticker message
+type Ticker struct {
+	Exchange  string    `json:"exchange"`
+	Pair      string    `json:"pair"`
+	AssetType string    `json:"assetType"`
+	Timestamp time.Time `json:"timestamp"`
+	Last      float64   `json:"last"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Bid       float64   `json:"bid"`
+	Ask       float64   `json:"ask"`
+	Volume    float64   `json:"volume"`
+}
+
+// Trade holds a normalised public trade message
+type Trade struct {
+	Exchange  string    `json:"exchange"`
+	Pair      string    `json:"pair"`
+	AssetType string    `json:"assetType"`
+	Timestamp time.Time `json:"timestamp"`
+	Price     float64   `json:"price"`
+	Amount    float64   `json:"amount"`
+	Side      string    `json:"side,omitempty"`
+}
+
+// Fill holds a normalised fill of one of the bot's orders
+type Fill struct {
+	Exchange  string    `json:"exchange"`
+	Pair      string    `json:"pair"`
+	AssetType string    `json:"assetType"`
+	Timestamp time.Time `json:"timestamp"`
+	OrderID   int64     `json:"orderID"`
+	ClientID  string    `json:"clientID,omitempty"`
+	TradeID   string    `json:"tradeID,omitempty"`
+	Side      string    `json:"side"`
+	Price     float64   `json:"price"`
+	Amount    float64   `json:"amount"`
+	Fee       float64   `json:"fee"`
+	Liquidity string    `json:"liquidity,omitempty"`
+}
+
+// Level holds a price level of an orderbook delta, a zero amount removes the
+// level from the book
+type Level struct {
+	Price  float64 `json:"price"`
+	Amount float64 `json:"amount"`
+}
+
+// BookDelta holds the price levels of an orderbook which changed since the
+// previous delta. Snapshot is set when the delta holds every level of the
+// book, replacing any book the consumer holds, which happens for the first
+// delta of a book and after the broker reconnects. Sequence increases by one
+// with each delta of a book so consumers can detect missed messages
+type BookDelta struct {
+	Exchange  string    `json:"exchange"`
+	Pair      string    `json:"pair"`
+	AssetType string    `json:"assetType"`
+	Timestamp time.Time `json:"timestamp"`
+	Sequence  int64     `json:"sequence"`
+	Snapshot  bool      `json:"snapshot"`
+	Bids      []Level   `json:"bids"`
+	Asks      []Level   `json:"asks"`
+}
+
+type message struct {
+	topic string
+	data  interface{}
+}
+
+type book struct {
+	sequence int64
+	bids     map[float64]float64
+	asks     map[float64]float64
+}
+
+// Publisher normalises ticker, trade, orderbook and fill updates and publishes
+// them to a message broker so external consumers can use the bot's market data
+// and follow its orders.
+// Messages are queued and dropped while the queue is full so publishing never
+// blocks the caller
+type Publisher struct {
+	broker  Broker
+	prefix  string
+	queue   chan message
+	books   map[string]*book
+	dropped int64
+	m       sync.Mutex
+}
+
+// IsSupportedBroker returns whether a message broker type is supported
+func IsSupportedBroker(name string) bool {
+	return common.StringToLower(name) == BrokerNATS
+}
+
+// NewBroker returns a broker of the type for the URL and credentials
+func NewBroker(name, url, username, password string) (Broker, error) {
+	if !IsSupportedBroker(name) {
+		return nil, ErrUnsupportedBroker
+	}
+	return NewNATS(url, username, password), nil
+}
+
+// New returns a publisher of messages to the broker with the topic prefix
+func New(broker Broker, prefix string) *Publisher {
+	return &Publisher{
+		broker: broker,
+		prefix: prefix,
+		queue:  make(chan message, queueSize),
+		books:  make(map[string]*book),
+	}
+}
+
+// Start connects to the broker and publishes queued messages until the
+// context is cancelled, reconnecting when the broker fails
+func (p *Publisher) Start(ctx context.Context) {
+	connected := false
+	defer func() {
+		if connected {
+			p.broker.Close()
+		}
+	}()
+
+	for {
+		if !connected {
+			err := p.broker.Connect()
+			if err != nil {
+				log.Printf("Publisher: failed to connect to message broker. Error: %s", err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(reconnectDelay):
+				}
+				continue
+			}
+			connected = true
+			p.resetBooks()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-p.queue:
+			data, err := common.JSONEncode(msg.data)
+			if err != nil {
+				log.Printf("Publisher: failed to encode %s message. Error: %s", msg.topic, err)
+				continue
+			}
+
+			err = p.broker.Publish(msg.topic, data)
+			if err != nil {
+				log.Printf("Publisher: failed to publish %s message. Error: %s", msg.topic, err)
+				p.broker.Close()
+				connected = false
+			}
+		}
+	}
+}
+
+// GetDropped returns the number of messages dropped while the queue was full
+func (p *Publisher) GetDropped() int64 {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.dropped
+}
+
+// GetTopic returns the topic of a message kind for an exchange pair
+func (p *Publisher) GetTopic(kind, exchangeName string, cp pair.CurrencyPair) string {
+	return common.JoinStrings([]string{p.prefix, kind,
+		topicToken(common.StringToLower(exchangeName)),
+		topicToken(formatPair(cp))}, ".")
+}
+
+// PublishTicker publishes a ticker update of an exchange
+func (p *Publisher) PublishTicker(exchangeName, assetType string, t ticker.Price) {
+	p.enqueue(p.GetTopic(KindTicker, exchangeName, t.Pair), Ticker{
+		Exchange:  exchangeName,
+		Pair:      formatPair(t.Pair),
+		AssetType: assetType,
+		Timestamp: t.LastUpdated,
+		Last:      t.Last,
+		High:      t.High,
+		Low:       t.Low,
+		Bid:       t.Bid,
+		Ask:       t.Ask,
+		Volume:    t.Volume,
+	})
+}
+
+// PublishTrade publishes a public trade of an exchange
+func (p *Publisher) PublishTrade(exchangeName, assetType string, cp pair.CurrencyPair, timestamp time.Time, price, amount float64, side string) {
+	p.enqueue(p.GetTopic(KindTrade, exchangeName, cp), Trade{
+		Exchange:  exchangeName,
+		Pair:      formatPair(cp),
+		AssetType: assetType,
+		Timestamp: timestamp,
+		Price:     price,
+		Amount:    amount,
+		Side:      common.StringToLower(side),
+	})
+}
+
+// PublishFill publishes a fill of one of the bot's orders on an exchange pair
+func (p *Publisher) PublishFill(assetType string, cp pair.CurrencyPair, f orders.Fill) {
+	p.enqueue(p.GetTopic(KindFill, f.Exchange, cp), Fill{
+		Exchange:  f.Exchange,
+		Pair:      formatPair(cp),
+		AssetType: assetType,
+		Timestamp: f.Timestamp,
+		OrderID:   f.OrderID,
+		ClientID:  f.ClientID,
+		TradeID:   f.TradeID,
+		Side:      common.StringToLower(f.Side),
+		Price:     f.Price,
+		Amount:    f.Amount,
+		Fee:       f.Fee,
+		Liquidity: common.StringToLower(f.Liquidity),
+	})
+}
+
+// PublishOrderbook publishes the price levels of an orderbook which changed
+// since it was last published, publishing nothing when it is unchanged
+func (p *Publisher) PublishOrderbook(exchangeName, assetType string, ob orderbook.Base) {
+	key := common.JoinStrings([]string{exchangeName, formatPair(ob.Pair), assetType}, "|")
+	bids := bookLevels(ob.Bids)
+	asks := bookLevels(ob.Asks)
+
+	p.m.Lock()
+	prev, ok := p.books[key]
+	delta := BookDelta{
+		Exchange:  exchangeName,
+		Pair:      formatPair(ob.Pair),
+		AssetType: assetType,
+		Timestamp: ob.LastUpdated,
+		Snapshot:  !ok,
+	}
+	if !ok {
+		prev = &book{}
+		delta.Bids = diffLevels(nil, bids)
+		delta.Asks = diffLevels(nil, asks)
+	} else {
+		delta.Bids = diffLevels(prev.bids, bids)
+		delta.Asks = diffLevels(prev.asks, asks)
+		if len(delta.Bids) == 0 && len(delta.Asks) == 0 {
+			p.m.Unlock()
+			return
+		}
+	}
+	prev.sequence++
+	prev.bids = bids
+	prev.asks = asks
+	p.books[key] = prev
+	delta.Sequence = prev.sequence
+	p.m.Unlock()
+
+	sort.Slice(delta.Bids, func(i, j int) bool { return delta.Bids[i].Price > delta.Bids[j].Price })
+	sort.Slice(delta.Asks, func(i, j int) bool { return delta.Asks[i].Price < delta.Asks[j].Price })
+	p.enqueue(p.GetTopic(KindBookDelta, exchangeName, ob.Pair), delta)
+}
+
+// enqueue queues a message for publishing, dropping it if the queue is full
+func (p *Publisher) enqueue(topic string, data interface{}) {
+	select {
+	case p.queue <- message{topic: topic, data: data}:
+	default:
+		p.m.Lock()
+		p.dropped++
+		p.m.Unlock()
+	}
+}
+
+// resetBooks clears the published orderbooks so the next delta of each book
+// is a snapshot, as consumers may have missed deltas while disconnected
+func (p *Publisher) resetBooks() {
+	p.m.Lock()
+	p.books = make(map[string]*book)
+	p.m.Unlock()
+}
+
+// bookLevels returns the amount at each price of one side of an orderbook
+func bookLevels(items []orderbook.Item) map[float64]float64 {
+	levels := make(map[float64]float64, len(items))
+	for x := range items {
+		levels[items[x].Price] += items[x].Amount
+	}
+	return levels
+}
+
+// diffLevels returns the levels which were added or changed between two
+// sides of an orderbook, with removed levels given a zero amount
+func diffLevels(prev, current map[float64]float64) []Level {
+	var levels []Level
+	for price, amount := range current {
+		if prevAmount, ok := prev[price]; !ok || prevAmount != amount {
+			levels = append(levels, Level{Price: price, Amount: amount})
+		}
+	}
+
+	for price := range prev {
+		if _, ok := current[price]; !ok {
+			levels = append(levels, Level{Price: price})
+		}
+	}
+	return levels
+}
+
+// formatPair returns the normalised form of a pair used across exchanges
+func formatPair(cp pair.CurrencyPair) string {
+	return cp.Display("-", true).String()
+}
+
+// topicToken replaces the characters which separate or match topic tokens
+func topicToken(token string) string {
+	return strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_").Replace(token)
+}
@@ -0,0 +1,172 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+type natsMessage struct {
+	subject string
+	payload []byte
+}
+
+// startNATSServer starts a NATS server which accepts a single client and
+// sends the messages it publishes to the returned channel
+func startNATSServer(t *testing.T) (net.Listener, chan natsMessage) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Test failed - Listen() error", err)
+	}
+
+	msgs := make(chan natsMessage, 10)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		fmt.Fprint(conn, "INFO {\"server_id\":\"test\"}\r\n")
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			switch {
+			case strings.HasPrefix(line, "PING"):
+				fmt.Fprint(conn, "PONG\r\n")
+			case strings.HasPrefix(line, "PUB"):
+				var subject string
+				var size int
+				fmt.Sscanf(line, "PUB %s %d", &subject, &size)
+				payload := make([]byte, size+2)
+				_, err = io.ReadFull(r, payload)
+				if err != nil {
+					return
+				}
+				msgs <- natsMessage{subject: subject, payload: payload[:size]}
+			}
+		}
+	}()
+	return l, msgs
+}
+
+func receive(t *testing.T, msgs chan natsMessage, v interface{}) string {
+	select {
+	case msg := <-msgs:
+		err := json.Unmarshal(msg.payload, v)
+		if err != nil {
+			t.Fatal("Test failed - Unmarshal() error", err)
+		}
+		return msg.subject
+	case <-time.After(time.Second * 5):
+		t.Fatal("Test failed - no message published")
+	}
+	return ""
+}
+
+func TestNewBroker(t *testing.T) {
+	if _, err := NewBroker("zeromq", "tcp://127.0.0.1:5556", "", ""); err != ErrUnsupportedBroker {
+		t.Error("Test failed - NewBroker() expected unsupported broker error", err)
+	}
+
+	if _, err := NewBroker("NATS", "nats://127.0.0.1:4222", "", ""); err != nil {
+		t.Error("Test failed - NewBroker() error", err)
+	}
+
+	if NewNATS("http://127.0.0.1", "", "").Connect() == nil {
+		t.Error("Test failed - Connect() expected invalid URL error")
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	l, msgs := startNATSServer(t)
+	defer l.Close()
+
+	p := New(NewNATS("nats://"+l.Addr().String(), "", ""), "gct")
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		p.Start(ctx)
+		close(stopped)
+	}()
+
+	btcusd := pair.NewCurrencyPairDelimiter("btc_usd", "_")
+	p.PublishTicker("Bitstamp", ticker.Spot, ticker.Price{Pair: btcusd, Last: 100, Bid: 99, Ask: 101})
+
+	var tick Ticker
+	subject := receive(t, msgs, &tick)
+	if subject != "gct.ticker.bitstamp.BTC-USD" || tick.Pair != "BTC-USD" || tick.Last != 100 {
+		t.Errorf("Test failed - PublishTicker() unexpected message %s %+v", subject, tick)
+	}
+
+	ob := orderbook.Base{
+		Pair: btcusd,
+		Bids: []orderbook.Item{{Price: 99, Amount: 1}, {Price: 98, Amount: 2}},
+		Asks: []orderbook.Item{{Price: 101, Amount: 1}},
+	}
+	p.PublishOrderbook("Bitstamp", ticker.Spot, ob)
+
+	var delta BookDelta
+	subject = receive(t, msgs, &delta)
+	if subject != "gct.book.bitstamp.BTC-USD" || !delta.Snapshot || delta.Sequence != 1 ||
+		len(delta.Bids) != 2 || delta.Bids[0].Price != 99 || len(delta.Asks) != 1 {
+		t.Errorf("Test failed - PublishOrderbook() unexpected snapshot %s %+v", subject, delta)
+	}
+
+	// Unchanged books aren't published
+	p.PublishOrderbook("Bitstamp", ticker.Spot, ob)
+
+	ob.Bids = []orderbook.Item{{Price: 99, Amount: 3}}
+	p.PublishOrderbook("Bitstamp", ticker.Spot, ob)
+
+	delta = BookDelta{}
+	receive(t, msgs, &delta)
+	if delta.Snapshot || delta.Sequence != 2 || len(delta.Bids) != 2 ||
+		delta.Bids[0] != (Level{Price: 99, Amount: 3}) ||
+		delta.Bids[1] != (Level{Price: 98}) || len(delta.Asks) != 0 {
+		t.Errorf("Test failed - PublishOrderbook() unexpected delta %+v", delta)
+	}
+
+	p.PublishTrade("Bitstamp", ticker.Spot, btcusd, time.Now(), 100, 0.5, "BUY")
+	var trade Trade
+	subject = receive(t, msgs, &trade)
+	if subject != "gct.trade.bitstamp.BTC-USD" || trade.Amount != 0.5 || trade.Side != "buy" {
+		t.Errorf("Test failed - PublishTrade() unexpected message %s %+v", subject, trade)
+	}
+
+	p.PublishFill(ticker.Spot, btcusd, orders.Fill{Exchange: "Bitstamp", Pair: "BTCUSD",
+		Side: orders.FillSell, OrderID: 42, Price: 100, Amount: 0.5, Fee: 0.1,
+		Liquidity: orders.FillMaker})
+	var fill Fill
+	subject = receive(t, msgs, &fill)
+	if subject != "gct.fill.bitstamp.BTC-USD" || fill.OrderID != 42 || fill.Side != "sell" ||
+		fill.Liquidity != "maker" || fill.Fee != 0.1 {
+		t.Errorf("Test failed - PublishFill() unexpected message %s %+v", subject, fill)
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second * 5):
+		t.Error("Test failed - Start() did not stop on cancel")
+	}
+
+	if p.GetDropped() != 0 {
+		t.Error("Test failed - GetDropped() expected no dropped messages")
+	}
+}
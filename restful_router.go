@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/exchanges"
 )
 
@@ -56,49 +57,259 @@ func NewRouter(exchanges []exchange.IBotExchange) *mux.Router {
 			"GetAllSettings",
 			"GET",
 			"/config/all",
-			RESTGetAllSettings,
+			RESTRequireRole(config.WebserverRoleAdmin, RESTGetAllSettings),
 		},
 		Route{
 			"SaveAllSettings",
 			"POST",
 			"/config/all/save",
-			RESTSaveAllSettings,
+			RESTRequireRole(config.WebserverRoleAdmin, RESTSaveAllSettings),
 		},
 		Route{
 			"AllEnabledAccountInfo",
 			"GET",
 			"/exchanges/enabled/accounts/all",
-			RESTGetAllEnabledAccountInfo,
+			RESTRequireRole(config.WebserverRoleUser, RESTGetAllEnabledAccountInfo),
+		},
+		Route{
+			"AllEnabledWithdrawalMethods",
+			"GET",
+			"/exchanges/enabled/withdrawals/methods",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetAllEnabledWithdrawalMethods),
+		},
+		Route{
+			"AllEnabledWithdrawalFees",
+			"GET",
+			"/exchanges/enabled/withdrawals/fees",
+			RESTRequireRole(config.WebserverRoleUser, RESTCompareWithdrawalFees),
 		},
 		Route{
 			"AllActiveExchangesAndCurrencies",
 			"GET",
 			"/exchanges/enabled/latest/all",
-			RESTGetAllActiveTickers,
+			RESTRequireRole(config.WebserverRoleUser, RESTGetAllActiveTickers),
+		},
+		Route{
+			"SupportedExchanges",
+			"GET",
+			"/exchanges/supported",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetSupportedExchanges),
+		},
+		Route{
+			"MarketOverview",
+			"GET",
+			"/exchanges/enabled/overview",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetMarketOverview),
 		},
 		Route{
 			"IndividualExchangeAndCurrency",
 			"GET",
 			"/exchanges/{exchangeName}/latest/{currency}",
-			RESTGetTicker,
+			RESTRequireRole(config.WebserverRoleUser, RESTGetTicker),
 		},
 		Route{
 			"GetPortfolio",
 			"GET",
 			"/portfolio/all",
-			RESTGetPortfolio,
+			RESTRequireRole(config.WebserverRoleUser, RESTGetPortfolio),
+		},
+		Route{
+			"GetPortfolioValuation",
+			"GET",
+			"/portfolio/valuation",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetPortfolioValuation),
+		},
+		Route{
+			"GetRebalancePlans",
+			"GET",
+			"/portfolio/rebalances",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTGetRebalancePlans),
+		},
+		Route{
+			"GetAddressBook",
+			"GET",
+			"/portfolio/addressbook",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTGetAddressBook),
+		},
+		Route{
+			"GetWithdrawalQuotas",
+			"GET",
+			"/portfolio/withdrawals/quotas",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTGetWithdrawalQuotas),
+		},
+		Route{
+			"GetWithdrawalRequests",
+			"GET",
+			"/portfolio/withdrawals",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTGetWithdrawalRequests),
+		},
+		Route{
+			"AddWithdrawalRequest",
+			"POST",
+			"/portfolio/withdrawals",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTAddWithdrawalRequest),
+		},
+		Route{
+			"GetWithdrawalRequest",
+			"GET",
+			"/portfolio/withdrawals/{id:[0-9]+}",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTGetWithdrawalRequest),
+		},
+		Route{
+			"ConfirmWithdrawalRequest",
+			"POST",
+			"/portfolio/withdrawals/{id:[0-9]+}/confirm",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTConfirmWithdrawalRequest),
+		},
+		Route{
+			"CancelWithdrawalRequest",
+			"DELETE",
+			"/portfolio/withdrawals/{id:[0-9]+}",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTCancelWithdrawalRequest),
+		},
+		Route{
+			"GetFiatTransfers",
+			"GET",
+			"/portfolio/withdrawals/fiat",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTGetFiatTransfers),
 		},
 		Route{
 			"AllActiveExchangesAndOrderbooks",
 			"GET",
 			"/exchanges/orderbook/latest/all",
-			RESTGetAllActiveOrderbooks,
+			RESTRequireRole(config.WebserverRoleUser, RESTGetAllActiveOrderbooks),
+		},
+		Route{
+			"OrderbookConsistency",
+			"GET",
+			"/exchanges/orderbook/consistency",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetOrderbookConsistency),
 		},
 		Route{
 			"IndividualExchangeOrderbook",
 			"GET",
 			"/exchanges/{exchangeName}/orderbook/latest/{currency}",
-			RESTGetOrderbook,
+			RESTRequireRole(config.WebserverRoleUser, RESTGetOrderbook),
+		},
+		Route{
+			"ProfitLossReport",
+			"GET",
+			"/orders/pnl",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetProfitLossReport),
+		},
+		Route{
+			"StrategyProfitLossReport",
+			"GET",
+			"/orders/pnl/strategies",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetStrategyProfitLossReport),
+		},
+		Route{
+			"TrailingStops",
+			"GET",
+			"/orders/trailingstops",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetTrailingStops),
+		},
+		Route{
+			"AddTrailingStop",
+			"POST",
+			"/orders/trailingstops",
+			RESTRequireRole(config.WebserverRoleUser, RESTAddTrailingStop),
+		},
+		Route{
+			"UpdateTrailingStop",
+			"PUT",
+			"/orders/trailingstops/{id}",
+			RESTRequireRole(config.WebserverRoleUser, RESTUpdateTrailingStop),
+		},
+		Route{
+			"RemoveTrailingStop",
+			"DELETE",
+			"/orders/trailingstops/{id}",
+			RESTRequireRole(config.WebserverRoleUser, RESTRemoveTrailingStop),
+		},
+		Route{
+			"SpreadOrders",
+			"GET",
+			"/orders/spreads",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetSpreadOrders),
+		},
+		Route{
+			"AddSpreadOrder",
+			"POST",
+			"/orders/spreads",
+			RESTRequireRole(config.WebserverRoleUser, RESTAddSpreadOrder),
+		},
+		Route{
+			"SpreadOrder",
+			"GET",
+			"/orders/spreads/{id}",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetSpreadOrder),
+		},
+		Route{
+			"RemoveSpreadOrder",
+			"DELETE",
+			"/orders/spreads/{id}",
+			RESTRequireRole(config.WebserverRoleUser, RESTRemoveSpreadOrder),
+		},
+		Route{
+			"WebsocketSubscriptions",
+			"GET",
+			"/exchanges/{exchangeName}/websocket/subscriptions",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetWebsocketSubscriptions),
+		},
+		Route{
+			"WebsocketSubscribe",
+			"POST",
+			"/exchanges/{exchangeName}/websocket/subscriptions",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTWebsocketSubscribe),
+		},
+		Route{
+			"WebsocketUnsubscribe",
+			"DELETE",
+			"/exchanges/{exchangeName}/websocket/subscriptions",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTWebsocketUnsubscribe),
+		},
+		Route{
+			"WebsocketConnections",
+			"GET",
+			"/exchanges/{exchangeName}/websocket/connections",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetWebsocketConnections),
+		},
+		Route{
+			"ExchangeFee",
+			"GET",
+			"/exchanges/{exchangeName}/fee",
+			RESTRequireRole(config.WebserverRoleUser, RESTGetExchangeFee),
+		},
+		Route{
+			"PreviewWithdrawal",
+			"GET",
+			"/exchanges/{exchangeName}/withdrawals/preview",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTPreviewWithdrawal),
+		},
+		Route{
+			"EnableExchange",
+			"POST",
+			"/exchanges/{exchangeName}/enable",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTEnableExchange),
+		},
+		Route{
+			"DisableExchange",
+			"POST",
+			"/exchanges/{exchangeName}/disable",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTDisableExchange),
+		},
+		Route{
+			"UsageStatus",
+			"GET",
+			"/status/usage",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTGetUsageStatus),
+		},
+		Route{
+			"GetSchedulerJobs",
+			"GET",
+			"/scheduler/jobs",
+			RESTRequireRole(config.WebserverRoleAdmin, RESTGetSchedulerJobs),
 		},
 		Route{
 			"ws",
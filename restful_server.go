@@ -2,14 +2,22 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/portfolio"
 )
 
 // AllEnabledExchangeOrderbooks holds the enabled exchange orderbooks
@@ -41,6 +49,19 @@ type AllEnabledExchangeAccounts struct {
 	Data []exchange.AccountInfo `json:"data"`
 }
 
+// AllEnabledExchangeWithdrawalMethods holds the withdrawal methods for all
+// enabled exchanges
+type AllEnabledExchangeWithdrawalMethods struct {
+	Data []EnabledExchangeWithdrawalMethods `json:"data"`
+}
+
+// EnabledExchangeWithdrawalMethods is a sub type for singular exchanges and
+// respective withdrawal methods
+type EnabledExchangeWithdrawalMethods struct {
+	ExchangeName string                      `json:"exchangeName"`
+	Methods      []exchange.WithdrawalMethod `json:"methods"`
+}
+
 // RESTfulJSONResponse outputs a JSON response of the response interface
 func RESTfulJSONResponse(w http.ResponseWriter, r *http.Request, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
@@ -171,15 +192,265 @@ func RESTGetAllActiveOrderbooks(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// RESTGetPortfolio returns the bot portfolio
+// RESTGetOrderbookConsistency returns the consistency check metrics of the
+// websocket maintained orderbooks
+func RESTGetOrderbookConsistency(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, orderbook.GetConsistencyStats())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetPortfolio returns the portfolio of the requesting user, or the bot
+// portfolio for the admin
 func RESTGetPortfolio(w http.ResponseWriter, r *http.Request) {
-	result := bot.portfolio.GetPortfolioSummary()
+	result := getUserPortfolio(getRequestUser(r)).GetPortfolioSummary()
 	err := RESTfulJSONResponse(w, r, result)
 	if err != nil {
 		RESTfulError(r.Method, err)
 	}
 }
 
+// RESTGetAddressBook returns the withdrawal address book
+func RESTGetAddressBook(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, portfolio.GetAddressBook())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetRebalancePlans returns the recent triggered rebalance plans
+func RESTGetRebalancePlans(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, portfolio.GetRebalancePlans())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetWithdrawalQuotas returns the used and remaining quota of each
+// withdrawal limit
+func RESTGetWithdrawalQuotas(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, portfolio.GetWithdrawalQuotas(""))
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetSchedulerJobs returns the status of the scheduled jobs
+func RESTGetSchedulerJobs(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, GetSchedulerStatus())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// WithdrawalRequestBody holds the details of a crypto withdrawal to request
+type WithdrawalRequestBody struct {
+	Exchange string  `json:"exchangeName"`
+	Currency string  `json:"currency"`
+	Address  string  `json:"address"`
+	Amount   float64 `json:"amount"`
+}
+
+// WithdrawalConfirmationBody holds the one time password confirming a
+// withdrawal request
+type WithdrawalConfirmationBody struct {
+	Code string `json:"code"`
+}
+
+// RESTGetWithdrawalRequests via get request returns JSON response of all
+// withdrawal requests
+func RESTGetWithdrawalRequests(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, portfolio.GetWithdrawalRequests())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTAddWithdrawalRequest requests a crypto withdrawal from the JSON request
+// body. When a withdrawal TOTP secret is configured the request awaits
+// confirmation, otherwise it is submitted to the exchange immediately
+func RESTAddWithdrawalRequest(w http.ResponseWriter, r *http.Request) {
+	var body WithdrawalRequestBody
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if GetExchangeByName(body.Exchange) == nil {
+		http.Error(w, ErrExchangeNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, err = portfolio.CheckWithdrawalAddress(body.Exchange, body.Address,
+		body.Currency, body.Amount)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	requireConfirmation := bot.config.Webserver.WithdrawalTOTPSecret != ""
+	req, err := portfolio.AddWithdrawalRequest(portfolio.WithdrawalRequest{
+		Exchange: body.Exchange,
+		Currency: body.Currency,
+		Address:  body.Address,
+		Amount:   body.Amount,
+	}, requireConfirmation)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Withdrawal request %d added for %v %s to %s on %s.\n", req.ID,
+		req.Amount, req.Currency, req.Address, req.Exchange)
+	if !requireConfirmation {
+		req, err = ExecuteWithdrawalRequest(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err = RESTfulJSONResponse(w, r, req)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetWithdrawalRequest via get request returns JSON response of a
+// withdrawal request and its status
+func RESTGetWithdrawalRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid withdrawal request ID", http.StatusBadRequest)
+		return
+	}
+
+	req, err := portfolio.GetWithdrawalRequest(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, req)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTConfirmWithdrawalRequest verifies the TOTP code of the JSON request body
+// against the configured withdrawal TOTP secret and submits the withdrawal
+// request to the exchange once confirmed. Each code can only be used once
+func RESTConfirmWithdrawalRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid withdrawal request ID", http.StatusBadRequest)
+		return
+	}
+
+	var body WithdrawalConfirmationBody
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	secret := bot.config.Webserver.WithdrawalTOTPSecret
+	confirmed := secret != "" && common.VerifyTOTP(secret, body.Code, now) &&
+		portfolio.UseConfirmationCode(body.Code, now)
+
+	req, err := portfolio.ConfirmWithdrawalRequest(id, confirmed)
+	switch err {
+	case nil:
+	case portfolio.ErrWithdrawalRequestNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case portfolio.ErrWithdrawalConfirmationFailed:
+		log.Printf("Withdrawal request %d confirmation failed (attempt %d).\n",
+			req.ID, req.ConfirmationAttempts)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	req, err = ExecuteWithdrawalRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, req)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCancelWithdrawalRequest cancels a withdrawal request awaiting
+// confirmation
+func RESTCancelWithdrawalRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid withdrawal request ID", http.StatusBadRequest)
+		return
+	}
+
+	req, err := portfolio.CancelWithdrawalRequest(id)
+	switch err {
+	case nil:
+	case portfolio.ErrWithdrawalRequestNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, req)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetFiatTransfers via get request returns JSON response of the bank
+// transfers initiated by fiat withdrawals and their state
+func RESTGetFiatTransfers(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, exchange.GetFiatTransfers())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetPortfolioValuation returns the portfolio of the requesting user
+// valued in the configured valuation currency
+func RESTGetPortfolioValuation(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, GetPortfolioValuation(getUserPortfolio(getRequestUser(r))))
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetSupportedExchanges returns the names of the exchanges registered with
+// the bot, which can be enabled in its config
+func RESTGetSupportedExchanges(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, exchange.RegisteredExchanges())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetMarketOverview returns the volume, last price and market share of
+// each enabled pair across the enabled exchanges
+func RESTGetMarketOverview(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, GetMarketOverview())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
 // RESTGetTicker returns ticker info for a given currency, exchange and
 // asset type
 func RESTGetTicker(w http.ResponseWriter, r *http.Request) {
@@ -283,11 +554,745 @@ func GetAllEnabledExchangeAccountInfo() AllEnabledExchangeAccounts {
 }
 
 // RESTGetAllEnabledAccountInfo via get request returns JSON response of account
-// info
+// info for the exchanges the requesting user can access
 func RESTGetAllEnabledAccountInfo(w http.ResponseWriter, r *http.Request) {
-	response := GetAllEnabledExchangeAccountInfo()
+	user := getRequestUser(r)
+	var response AllEnabledExchangeAccounts
+	for _, account := range GetAllEnabledExchangeAccountInfo().Data {
+		if user.CanAccessExchange(account.ExchangeName) {
+			response.Data = append(response.Data, account)
+		}
+	}
 	err := RESTfulJSONResponse(w, r, response)
 	if err != nil {
 		RESTfulError(r.Method, err)
 	}
 }
+
+// GetAllEnabledExchangeWithdrawalMethods returns the withdrawal methods
+// supported by each enabled exchange
+func GetAllEnabledExchangeWithdrawalMethods() AllEnabledExchangeWithdrawalMethods {
+	var response AllEnabledExchangeWithdrawalMethods
+	for _, individualBot := range bot.exchanges {
+		if individualBot != nil && individualBot.IsEnabled() {
+			response.Data = append(response.Data, EnabledExchangeWithdrawalMethods{
+				ExchangeName: individualBot.GetName(),
+				Methods:      individualBot.GetWithdrawalMethods(),
+			})
+		}
+	}
+	return response
+}
+
+// RESTGetAllEnabledWithdrawalMethods via get request returns JSON response of
+// the withdrawal methods supported by each enabled exchange the requesting
+// user can access
+func RESTGetAllEnabledWithdrawalMethods(w http.ResponseWriter, r *http.Request) {
+	user := getRequestUser(r)
+	var response AllEnabledExchangeWithdrawalMethods
+	for _, methods := range GetAllEnabledExchangeWithdrawalMethods().Data {
+		if user.CanAccessExchange(methods.ExchangeName) {
+			response.Data = append(response.Data, methods)
+		}
+	}
+	err := RESTfulJSONResponse(w, r, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCompareWithdrawalFees via get request returns JSON response of the cost
+// of withdrawing the amount query parameter of the currency query parameter
+// from each enabled exchange holding it the requesting user can access,
+// cheapest first
+func RESTCompareWithdrawalFees(w http.ResponseWriter, r *http.Request) {
+	currencyCode := r.URL.Query().Get("currency")
+	if currencyCode == "" {
+		http.Error(w, "currency required", http.StatusBadRequest)
+		return
+	}
+
+	amount, err := strconv.ParseFloat(r.URL.Query().Get("amount"), 64)
+	if err != nil || amount <= 0 {
+		http.Error(w, "invalid amount", http.StatusBadRequest)
+		return
+	}
+
+	user := getRequestUser(r)
+	response := []WithdrawalFeeQuote{}
+	for _, quote := range CompareWithdrawalFees(currencyCode, amount) {
+		if user.CanAccessExchange(quote.Exchange) {
+			response = append(response, quote)
+		}
+	}
+
+	err = RESTfulJSONResponse(w, r, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// parseReportPeriod returns the optional start and end unix timestamp query
+// parameters of a report request
+func parseReportPeriod(r *http.Request) (time.Time, time.Time, error) {
+	var start, end time.Time
+	if v := r.URL.Query().Get("start"); v != "" {
+		ts, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return start, end, errors.New("invalid start timestamp")
+		}
+		start = time.Unix(ts, 0)
+	}
+
+	if v := r.URL.Query().Get("end"); v != "" {
+		ts, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return start, end, errors.New("invalid end timestamp")
+		}
+		end = time.Unix(ts, 0)
+	}
+	return start, end, nil
+}
+
+// RESTGetProfitLossReport via get request returns JSON response of the
+// realised profit and loss per exchange pair. The optional start and end query
+// parameters are unix timestamps, defaulting to all recorded fills
+func RESTGetProfitLossReport(w http.ResponseWriter, r *http.Request) {
+	start, end, err := parseReportPeriod(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report := filterProfitLoss(getRequestUser(r), orders.GetProfitLossReport(start, end))
+	err = RESTfulJSONResponse(w, r, report)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetStrategyProfitLossReport via get request returns JSON response of the
+// realised profit and loss per strategy and exchange pair. The optional
+// strategy query parameter limits the report to a single strategy, start and
+// end are as in RESTGetProfitLossReport
+func RESTGetStrategyProfitLossReport(w http.ResponseWriter, r *http.Request) {
+	start, end, err := parseReportPeriod(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user := getRequestUser(r)
+	strategy := r.URL.Query().Get("strategy")
+	if strategy != "" && !user.CanAccessStrategy(strategy) {
+		http.Error(w, ErrResourceForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	report := filterProfitLoss(user, orders.GetStrategyProfitLossReport(strategy, start, end))
+	err = RESTfulJSONResponse(w, r, report)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// TrailingStopRequest holds the details of a trailing stop to add or the new
+// trail distance of a live trailing stop
+type TrailingStopRequest struct {
+	Exchange   string  `json:"exchangeName"`
+	Currency   string  `json:"currency"`
+	AssetType  string  `json:"assetType"`
+	Side       string  `json:"side"`
+	Amount     float64 `json:"amount"`
+	OffsetType string  `json:"offsetType"`
+	Offset     float64 `json:"offset"`
+	BestPrice  float64 `json:"bestPrice"`
+}
+
+// RESTGetTrailingStops via get request returns JSON response of the trailing
+// stops of the requesting user
+func RESTGetTrailingStops(w http.ResponseWriter, r *http.Request) {
+	user := getRequestUser(r)
+	stops := []orders.TrailingStop{}
+	for _, stop := range orders.GetTrailingStops() {
+		if user.Owns(stop.Owner) {
+			stops = append(stops, stop)
+		}
+	}
+
+	err := RESTfulJSONResponse(w, r, stops)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTAddTrailingStop adds a trailing stop from the JSON request body and
+// returns the new trailing stop
+func RESTAddTrailingStop(w http.ResponseWriter, r *http.Request) {
+	var req TrailingStopRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if GetExchangeByName(req.Exchange) == nil {
+		http.Error(w, ErrExchangeNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user := getRequestUser(r)
+	if !user.CanAccessExchange(req.Exchange) {
+		http.Error(w, ErrResourceForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	if req.AssetType == "" {
+		req.AssetType = ticker.Spot
+	}
+
+	id, err := orders.AddTrailingStop(orders.TrailingStop{
+		Exchange:   req.Exchange,
+		Pair:       pair.NewCurrencyPairFromString(req.Currency),
+		AssetType:  req.AssetType,
+		Side:       req.Side,
+		Amount:     req.Amount,
+		OffsetType: req.OffsetType,
+		Offset:     req.Offset,
+		BestPrice:  req.BestPrice,
+		Owner:      user.Username,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stop, err := orders.GetTrailingStop(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, stop)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTUpdateTrailingStop adjusts the trail distance of a live trailing stop
+// from the offsetType and offset of the JSON request body
+func RESTUpdateTrailingStop(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid trailing stop ID", http.StatusBadRequest)
+		return
+	}
+
+	if !ownsTrailingStop(getRequestUser(r), id) {
+		http.Error(w, orders.ErrTrailingStopNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	var req TrailingStopRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stop, err := orders.SetTrailingStopOffset(id, req.OffsetType, req.Offset)
+	switch err {
+	case nil:
+	case orders.ErrTrailingStopNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case orders.ErrInvalidTrailOffset:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	default:
+		// The offset has been applied but could not be persisted
+		log.Printf("Failed to save trailing stops. Error: %s", err)
+	}
+
+	err = RESTfulJSONResponse(w, r, stop)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTRemoveTrailingStop removes a trailing stop
+func RESTRemoveTrailingStop(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid trailing stop ID", http.StatusBadRequest)
+		return
+	}
+
+	if !ownsTrailingStop(getRequestUser(r), id) {
+		http.Error(w, orders.ErrTrailingStopNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = orders.RemoveTrailingStop(id)
+	if err == orders.ErrTrailingStopNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to save trailing stops. Error: %s", err)
+	}
+
+	err = RESTfulJSONResponse(w, r, id)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// SpreadLegRequest holds the details of a single leg of a spread order
+type SpreadLegRequest struct {
+	Exchange string  `json:"exchangeName"`
+	Currency string  `json:"currency"`
+	Side     string  `json:"side"`
+	Ratio    float64 `json:"ratio"`
+	Price    float64 `json:"price"`
+}
+
+// SpreadOrderRequest holds the details of a spread order to add, the hedge
+// timeout is in seconds
+type SpreadOrderRequest struct {
+	Legs               []SpreadLegRequest `json:"legs"`
+	Quantity           float64            `json:"quantity"`
+	TargetDifferential float64            `json:"targetDifferential"`
+	Mode               string             `json:"mode"`
+	HedgeTimeout       int64              `json:"hedgeTimeout"`
+}
+
+// RESTGetSpreadOrders via get request returns JSON response of the spread
+// orders of the requesting user
+func RESTGetSpreadOrders(w http.ResponseWriter, r *http.Request) {
+	user := getRequestUser(r)
+	spreads := []orders.SpreadOrder{}
+	for _, spread := range orders.GetSpreadOrders() {
+		if user.Owns(spread.Owner) {
+			spreads = append(spreads, spread)
+		}
+	}
+
+	err := RESTfulJSONResponse(w, r, spreads)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTAddSpreadOrder adds a spread order from the JSON request body and
+// returns the new spread order
+func RESTAddSpreadOrder(w http.ResponseWriter, r *http.Request) {
+	var req SpreadOrderRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user := getRequestUser(r)
+	spread := orders.SpreadOrder{
+		Owner:              user.Username,
+		Quantity:           req.Quantity,
+		TargetDifferential: req.TargetDifferential,
+		Mode:               req.Mode,
+		HedgeTimeout:       time.Duration(req.HedgeTimeout) * time.Second,
+	}
+
+	for x := range req.Legs {
+		if GetExchangeByName(req.Legs[x].Exchange) == nil {
+			http.Error(w, ErrExchangeNotFound.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !user.CanAccessExchange(req.Legs[x].Exchange) {
+			http.Error(w, ErrResourceForbidden.Error(), http.StatusForbidden)
+			return
+		}
+
+		spread.Legs = append(spread.Legs, orders.SpreadLeg{
+			Exchange: req.Legs[x].Exchange,
+			Pair:     pair.NewCurrencyPairFromString(req.Legs[x].Currency),
+			Side:     req.Legs[x].Side,
+			Ratio:    req.Legs[x].Ratio,
+			Price:    req.Legs[x].Price,
+		})
+	}
+
+	id, err := orders.AddSpreadOrder(spread)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	spread, err = orders.GetSpreadOrder(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, spread)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetSpreadOrder via get request returns JSON response of a spread order
+// including its combined fill state
+func RESTGetSpreadOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid spread order ID", http.StatusBadRequest)
+		return
+	}
+
+	spread, err := orders.GetSpreadOrder(id)
+	if err != nil || !getRequestUser(r).Owns(spread.Owner) {
+		http.Error(w, orders.ErrSpreadOrderNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, spread)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTRemoveSpreadOrder removes a spread order, orders already submitted for
+// its legs are left on the exchanges
+func RESTRemoveSpreadOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid spread order ID", http.StatusBadRequest)
+		return
+	}
+
+	spread, err := orders.GetSpreadOrder(id)
+	if err != nil || !getRequestUser(r).Owns(spread.Owner) {
+		http.Error(w, orders.ErrSpreadOrderNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = orders.RemoveSpreadOrder(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, id)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// WebsocketSubscriptionRequest holds the channel and currency pair of a
+// websocket subscription to add or remove
+type WebsocketSubscriptionRequest struct {
+	Channel  string `json:"channel"`
+	Currency string `json:"currency"`
+	Depth    int    `json:"depth"`
+}
+
+// getExchangeWebsocket returns the websocket of the exchange named in the
+// request path if the requesting user can access the exchange
+func getExchangeWebsocket(r *http.Request) (*exchange.Websocket, error) {
+	exch := GetExchangeByName(mux.Vars(r)["exchangeName"])
+	if exch == nil || !getRequestUser(r).CanAccessExchange(exch.GetName()) {
+		return nil, ErrExchangeNotFound
+	}
+	return exch.GetWebsocket()
+}
+
+// RESTGetWebsocketSubscriptions via get request returns JSON response of the
+// websocket subscriptions of an exchange
+func RESTGetWebsocketSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ws, err := getExchangeWebsocket(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, ws.GetSubscriptions())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetWebsocketConnections via get request returns JSON response of the
+// state of each named websocket connection of an exchange
+func RESTGetWebsocketConnections(w http.ResponseWriter, r *http.Request) {
+	ws, err := getExchangeWebsocket(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, ws.GetConnectionStatus())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetExchangeFee via get request returns JSON response of a fee of an
+// exchange. The type query parameter is the fee type, first and second are the
+// pair currencies or the withdrawn currency, and price, amount and maker
+// describe the trade. Fees of exchanges without API credentials are estimated
+func RESTGetExchangeFee(w http.ResponseWriter, r *http.Request) {
+	exchName := mux.Vars(r)["exchangeName"]
+	if !getRequestUser(r).CanAccessExchange(exchName) {
+		http.Error(w, ErrExchangeNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	feeBuilder := exchange.FeeBuilder{
+		FeeType:        exchange.FeeType(query.Get("type")),
+		FirstCurrency:  common.StringToUpper(query.Get("first")),
+		SecondCurrency: common.StringToUpper(query.Get("second")),
+		CurrencyItem:   common.StringToUpper(query.Get("currency")),
+		IsMaker:        query.Get("maker") == "true",
+	}
+	if feeBuilder.FeeType == "" {
+		feeBuilder.FeeType = exchange.CryptocurrencyTradeFee
+	}
+
+	for param, value := range map[string]*float64{
+		"price":  &feeBuilder.PurchasePrice,
+		"amount": &feeBuilder.Amount,
+	} {
+		v := query.Get(param)
+		if v == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "invalid "+param, http.StatusBadRequest)
+			return
+		}
+		*value = f
+	}
+
+	fee, err := GetExchangeFee(exchName, feeBuilder)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == ErrExchangeNotFound {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, fee)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTPreviewWithdrawal via get request returns JSON response of a dry run of
+// withdrawing the amount query parameter of the currency query parameter from
+// the exchange to the address query parameter, with its fees, the amount
+// expected to be received and the checks it would fail
+func RESTPreviewWithdrawal(w http.ResponseWriter, r *http.Request) {
+	exchName := mux.Vars(r)["exchangeName"]
+	if !getRequestUser(r).CanAccessExchange(exchName) {
+		http.Error(w, ErrExchangeNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	currencyCode := query.Get("currency")
+	if currencyCode == "" {
+		http.Error(w, "currency required", http.StatusBadRequest)
+		return
+	}
+
+	amount, err := strconv.ParseFloat(query.Get("amount"), 64)
+	if err != nil || amount <= 0 {
+		http.Error(w, "invalid amount", http.StatusBadRequest)
+		return
+	}
+
+	preview, err := PreviewWithdrawal(exchName, currencyCode, query.Get("address"), amount)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, preview)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTWebsocketSubscribe subscribes an exchange websocket to the channel and
+// currency pair of the JSON request body
+func RESTWebsocketSubscribe(w http.ResponseWriter, r *http.Request) {
+	restWebsocketSubscription(w, r, true)
+}
+
+// RESTWebsocketUnsubscribe unsubscribes an exchange websocket from the
+// channel and currency pair of the JSON request body
+func RESTWebsocketUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	restWebsocketSubscription(w, r, false)
+}
+
+func restWebsocketSubscription(w http.ResponseWriter, r *http.Request, subscribe bool) {
+	ws, err := getExchangeWebsocket(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var req WebsocketSubscriptionRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sub := exchange.WebsocketSubscription{
+		Channel: req.Channel,
+		Pair:    pair.NewCurrencyPairFromString(req.Currency),
+		Depth:   req.Depth,
+	}
+
+	if subscribe {
+		err = ws.Subscribe(sub)
+	} else {
+		err = ws.Unsubscribe(sub)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, ws.GetSubscriptions())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// requestUsageReporter is implemented by exchanges which send their HTTP
+// requests through a requester
+type requestUsageReporter interface {
+	GetUsageStats() request.UsageStats
+	GetRateLimitUsage(auth bool) request.RateLimitUsage
+}
+
+// ExchangeUsage holds the request, rate limit and websocket usage for an
+// exchange
+type ExchangeUsage struct {
+	ExchangeName      string                 `json:"exchangeName"`
+	Requests          request.UsageStats     `json:"requests"`
+	AuthRateLimit     request.RateLimitUsage `json:"authRateLimit"`
+	UnauthRateLimit   request.RateLimitUsage `json:"unauthRateLimit"`
+	WebsocketEnabled  bool                   `json:"websocketEnabled"`
+	WebsocketMessages int64                  `json:"websocketMessages"`
+}
+
+// UsageStatus holds the usage of all enabled exchanges, the exchanges which
+// failed to load and the ticker and orderbook stores
+type UsageStatus struct {
+	Timestamp      int64                `json:"timestamp"`
+	Exchanges      []ExchangeUsage      `json:"exchanges"`
+	Failures       []ExchangeFailure    `json:"failures"`
+	TickerCache    ticker.CacheStats    `json:"tickerCache"`
+	OrderbookCache orderbook.CacheStats `json:"orderbookCache"`
+}
+
+// RESTEnableExchange enables the exchange named in the request path while the
+// bot is running
+func RESTEnableExchange(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["exchangeName"]
+	err := EnableExchange(name)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == ErrExchangeNotFound {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, name)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTDisableExchange disables the exchange named in the request path while
+// the bot is running. The optional cancelOrders query parameter cancels the
+// open orders submitted by the bot first
+func RESTDisableExchange(w http.ResponseWriter, r *http.Request) {
+	var cancelOrders bool
+	if v := r.URL.Query().Get("cancelOrders"); v != "" {
+		var err error
+		cancelOrders, err = strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "invalid cancelOrders value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	name := mux.Vars(r)["exchangeName"]
+	err := DisableExchange(name, cancelOrders)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == ErrExchangeNotFound {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, r, name)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// GetUsageStatus returns the request, rate limit and websocket usage of all
+// enabled exchanges and the exchanges which failed to load, along with the
+// ticker and orderbook store hit counts
+func GetUsageStatus() UsageStatus {
+	status := UsageStatus{
+		Timestamp:      time.Now().Unix(),
+		Failures:       GetExchangeFailures(),
+		TickerCache:    ticker.GetCacheStats(),
+		OrderbookCache: orderbook.GetCacheStats(),
+	}
+
+	for _, exch := range bot.exchanges {
+		if exch == nil || !exch.IsEnabled() {
+			continue
+		}
+
+		usage := ExchangeUsage{ExchangeName: exch.GetName()}
+		if reporter, ok := exch.(requestUsageReporter); ok {
+			usage.Requests = reporter.GetUsageStats()
+			usage.AuthRateLimit = reporter.GetRateLimitUsage(true)
+			usage.UnauthRateLimit = reporter.GetRateLimitUsage(false)
+		}
+
+		ws, err := exch.GetWebsocket()
+		if err == nil && ws != nil {
+			usage.WebsocketEnabled = ws.IsEnabled()
+			usage.WebsocketMessages = ws.GetMessageCount()
+		}
+		status.Exchanges = append(status.Exchanges, usage)
+	}
+	return status
+}
+
+// RESTGetUsageStatus via get request returns JSON response of the request,
+// rate limit, websocket and cache usage for the status dashboard
+func RESTGetUsageStatus(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, r, GetUsageStatus())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
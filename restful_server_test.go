@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 )
 
@@ -50,3 +51,69 @@ func TestConfigAllJsonResponse(t *testing.T) {
 		t.Error("Test failed. Json not equal to config")
 	}
 }
+
+func TestRESTRequireRole(t *testing.T) {
+	SetupTestHelpers(t)
+	webserver := bot.config.Webserver
+	defer func() { bot.config.Webserver = webserver }()
+
+	bot.config.Webserver.AdminUsername = "admin"
+	bot.config.Webserver.AdminPassword = "adminpass"
+	bot.config.Webserver.Users = nil
+
+	var user *APIUser
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		user = getRequestUser(r)
+	}
+
+	serve := func(role, username, password string) int {
+		user = nil
+		req := httptest.NewRequest("GET", "http://localhost:9050/orders/trailingstops", nil)
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		}
+		w := httptest.NewRecorder()
+		RESTRequireRole(role, handler)(w, req)
+		return w.Code
+	}
+
+	if serve(config.WebserverRoleUser, "", "") != http.StatusUnauthorized {
+		t.Error("Test failed. RESTRequireRole expected unauthorised without credentials or users")
+	}
+
+	if serve(config.WebserverRoleUser, "admin", "adminpass") != http.StatusOK || user == nil || !user.IsAdmin() {
+		t.Error("Test failed. RESTRequireRole expected the admin credentials without users")
+	}
+
+	bot.config.Webserver.Users = []config.WebserverUserConfig{
+		{Username: "alice", Password: "alicepass", Role: config.WebserverRoleUser,
+			Exchanges: "Bitstamp, Kraken", Strategies: "momentum"},
+	}
+
+	if serve(config.WebserverRoleUser, "", "") != http.StatusUnauthorized {
+		t.Error("Test failed. RESTRequireRole expected unauthorised without credentials")
+	}
+
+	if serve(config.WebserverRoleUser, "alice", "wrong") != http.StatusUnauthorized {
+		t.Error("Test failed. RESTRequireRole expected unauthorised with an invalid password")
+	}
+
+	if serve(config.WebserverRoleAdmin, "alice", "alicepass") != http.StatusForbidden {
+		t.Error("Test failed. RESTRequireRole expected forbidden for a user on an admin route")
+	}
+
+	hashPW := common.HexEncodeToString(common.GetSHA256([]byte("alicepass")))
+	if serve(config.WebserverRoleUser, "alice", hashPW) != http.StatusOK || user == nil {
+		t.Fatal("Test failed. RESTRequireRole expected user authenticated with a hashed password")
+	}
+
+	if user.IsAdmin() || !user.CanAccessExchange("KRAKEN") || user.CanAccessExchange("Binance") ||
+		!user.CanAccessStrategy("momentum") || user.CanAccessStrategy("grid") ||
+		!user.Owns("alice") || user.Owns("bob") {
+		t.Errorf("Test failed. RESTRequireRole unexpected user scope %+v", user)
+	}
+
+	if serve(config.WebserverRoleAdmin, "admin", "adminpass") != http.StatusOK || !user.Owns("bob") {
+		t.Error("Test failed. RESTRequireRole expected the admin to access admin routes")
+	}
+}
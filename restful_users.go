@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
+	"github.com/thrasher-/gocryptotrader/portfolio"
+)
+
+// ErrResourceForbidden is returned when a user requests an exchange or
+// strategy it cannot access
+var ErrResourceForbidden = errors.New("access to resource forbidden")
+
+type apiUserContextKey struct{}
+
+// APIUser holds an authenticated management API user and the exchanges and
+// strategies it can access. Nil exchanges or strategies allow all of them
+type APIUser struct {
+	Username   string
+	Role       string
+	Exchanges  []string
+	Strategies []string
+}
+
+// IsAdmin returns whether the user can perform global operations and access
+// the resources of every user
+func (u *APIUser) IsAdmin() bool {
+	return u.Role == config.WebserverRoleAdmin
+}
+
+// CanAccessExchange returns whether the user can view and trade an exchange
+func (u *APIUser) CanAccessExchange(exchangeName string) bool {
+	return u.IsAdmin() || u.Exchanges == nil ||
+		common.StringDataCompareUpper(u.Exchanges, exchangeName)
+}
+
+// CanAccessStrategy returns whether the user can view a strategy
+func (u *APIUser) CanAccessStrategy(strategyID string) bool {
+	return u.IsAdmin() || u.Strategies == nil ||
+		common.StringDataCompare(u.Strategies, strategyID)
+}
+
+// Owns returns whether the user can view and manage a resource created by
+// owner, such as a trailing stop or spread order
+func (u *APIUser) Owns(owner string) bool {
+	return u.IsAdmin() || u.Username == owner
+}
+
+// AuthenticateAPIUser returns the management API user with the username and
+// password, which may be given in plain text or as its hex encoded SHA256 hash
+func AuthenticateAPIUser(username, password string) (*APIUser, bool) {
+	if username == bot.config.Webserver.AdminUsername &&
+		passwordMatches(password, bot.config.Webserver.AdminPassword) {
+		return &APIUser{Username: username, Role: config.WebserverRoleAdmin}, true
+	}
+
+	for x := range bot.config.Webserver.Users {
+		user := bot.config.Webserver.Users[x]
+		if user.Username != username || !passwordMatches(password, user.Password) {
+			continue
+		}
+
+		apiUser := &APIUser{Username: user.Username, Role: user.Role}
+		if user.Role != config.WebserverRoleAdmin {
+			apiUser.Exchanges = splitUserList(user.Exchanges)
+			apiUser.Strategies = splitUserList(user.Strategies)
+		}
+		return apiUser, true
+	}
+	return nil, false
+}
+
+// passwordMatches compares a password in constant time against the expected
+// password and its hex encoded SHA256 hash
+func passwordMatches(password, expected string) bool {
+	hashPW := common.HexEncodeToString(common.GetSHA256([]byte(expected)))
+	return subtle.ConstantTimeCompare([]byte(password), []byte(expected)) == 1 ||
+		subtle.ConstantTimeCompare([]byte(password), []byte(hashPW)) == 1
+}
+
+// splitUserList splits a comma separated list of a user's exchanges or
+// strategies, an empty list grants access to all of them
+func splitUserList(list string) []string {
+	var result []string
+	for _, x := range common.SplitStrings(list, ",") {
+		x = common.TrimString(x, " ")
+		if x != "" {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// RESTRequireRole requires requests to authenticate with HTTP basic auth as
+// a user with the role, storing the user in the request context. Without
+// configured users only the webserver admin credentials are accepted. The
+// password may be sent as its hex encoded SHA256 hash as with websocket
+// authentication
+func RESTRequireRole(role string, inner http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		user, valid := AuthenticateAPIUser(username, password)
+		if !ok || !valid {
+			w.Header().Set("WWW-Authenticate", `Basic realm="GoCryptoTrader"`)
+			http.Error(w, "invalid username/password", http.StatusUnauthorized)
+			return
+		}
+
+		if role == config.WebserverRoleAdmin && !user.IsAdmin() {
+			http.Error(w, "admin role required", http.StatusForbidden)
+			return
+		}
+
+		inner(w, r.WithContext(context.WithValue(r.Context(), apiUserContextKey{}, user)))
+	}
+}
+
+// getRequestUser returns the user a request authenticated as, or the admin
+// when multi tenant mode is disabled
+func getRequestUser(r *http.Request) *APIUser {
+	if user, ok := r.Context().Value(apiUserContextKey{}).(*APIUser); ok {
+		return user
+	}
+	return &APIUser{Username: bot.config.Webserver.AdminUsername, Role: config.WebserverRoleAdmin}
+}
+
+// getUserPortfolio returns the portfolio visible to a user
+func getUserPortfolio(user *APIUser) *portfolio.Base {
+	if user.IsAdmin() {
+		return bot.portfolio
+	}
+	return bot.portfolio.GetUserPortfolio(user.Username, user.Exchanges)
+}
+
+// ownsTrailingStop returns whether a trailing stop exists and is owned by the
+// user
+func ownsTrailingStop(user *APIUser, id int64) bool {
+	stop, err := orders.GetTrailingStop(id)
+	return err == nil && user.Owns(stop.Owner)
+}
+
+// filterProfitLoss returns the profit and loss entries of the exchanges and
+// strategies a user can access
+func filterProfitLoss(user *APIUser, report []orders.ProfitLoss) []orders.ProfitLoss {
+	if user.IsAdmin() {
+		return report
+	}
+
+	filtered := []orders.ProfitLoss{}
+	for x := range report {
+		if !user.CanAccessExchange(report[x].Exchange) ||
+			(report[x].Strategy != "" && !user.CanAccessStrategy(report[x].Strategy)) {
+			continue
+		}
+		filtered = append(filtered, report[x])
+	}
+	return filtered
+}
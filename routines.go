@@ -1,20 +1,30 @@
 package main
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"log"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency"
+	"github.com/thrasher-/gocryptotrader/currency/marketdata"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/orders"
 	"github.com/thrasher-/gocryptotrader/exchanges/stats"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/exchanges/trades"
+	"github.com/thrasher-/gocryptotrader/portfolio"
+	"github.com/thrasher-/gocryptotrader/publisher"
+	"github.com/thrasher-/gocryptotrader/strategy"
 )
 
 func printCurrencyFormat(price float64) string {
@@ -176,22 +186,161 @@ func relayWebsocketEvent(result interface{}, event, assetType, exchangeName stri
 	}
 }
 
+func streamTickerUpdate(exchangeName, assetType string, p pair.CurrencyPair, result ticker.Price) {
+	err := StreamTickerUpdate(exchangeName, assetType, p, result)
+	if err != nil {
+		log.Println(fmt.Errorf("Failed to stream ticker update. Error: %s",
+			err))
+	}
+}
+
+func streamOrderbookUpdate(exchangeName, assetType string, p pair.CurrencyPair, result orderbook.Base) {
+	err := StreamOrderbookUpdate(exchangeName, assetType, p, result)
+	if err != nil {
+		log.Println(fmt.Errorf("Failed to stream orderbook update. Error: %s",
+			err))
+	}
+}
+
+// StartPublisher starts publishing ticker, trade and orderbook updates to the
+// configured message broker until the context is cancelled
+func StartPublisher(ctx context.Context) error {
+	cfg := bot.config.Publisher
+	broker, err := publisher.NewBroker(cfg.Broker, cfg.URL, cfg.Username, cfg.Password)
+	if err != nil {
+		return err
+	}
+
+	bot.publisher = publisher.New(broker, cfg.TopicPrefix)
+	startRoutine(&bot.routines, func() { bot.publisher.Start(ctx) })
+	log.Printf("Publishing market data to %s broker %s.\n", cfg.Broker, cfg.URL)
+	return nil
+}
+
+// StartTickerConflation coalesces ticker updates to the websocket stream and
+// publisher, delivering at most the configured rate of updates per exchange
+// pair until the context is cancelled
+func StartTickerConflation(ctx context.Context) {
+	rate := bot.config.TickerConflation.MaxUpdatesPerSecond
+	bot.conflator = ticker.NewConflator(rate, deliverTickerUpdate)
+	startRoutine(&bot.routines, func() { bot.conflator.Run(ctx) })
+	log.Printf("Conflating ticker updates to %v per second per pair.\n", rate)
+}
+
+// pushTickerUpdate delivers a ticker update to the websocket stream and
+// publisher, through the conflator when ticker conflation is enabled
+func pushTickerUpdate(exchangeName, assetType string, p pair.CurrencyPair, result ticker.Price) {
+	if result.Pair.Empty() {
+		result.Pair = p
+	}
+
+	u := ticker.Update{Exchange: exchangeName, AssetType: assetType, Price: result}
+	if bot.conflator != nil {
+		bot.conflator.Push(u)
+		return
+	}
+	deliverTickerUpdate(u)
+}
+
+func deliverTickerUpdate(u ticker.Update) {
+	if bot.publisher != nil {
+		bot.publisher.PublishTicker(u.Exchange, u.AssetType, u.Price)
+	}
+	if bot.config.Webserver.Enabled {
+		streamTickerUpdate(u.Exchange, u.AssetType, u.Price.Pair, u.Price)
+	}
+}
+
+// applyOrderbookTicker takes the bid and ask of an exchange pair's ticker
+// from its orderbook when the ticker lags the book, pushing the updated
+// ticker to the websocket stream and publisher
+func applyOrderbookTicker(exchangeName, assetType string, p pair.CurrencyPair, ob orderbook.Base) {
+	cfg := bot.config.BookTicker
+	if !cfg.Enabled || len(ob.Bids) == 0 || len(ob.Asks) == 0 {
+		return
+	}
+
+	top := ticker.BookTop{
+		Bid:     ob.Bids[0].Price,
+		Ask:     ob.Asks[0].Price,
+		Updated: ob.LastUpdated,
+	}
+	for x := 0; x < cfg.Levels && x < len(ob.Bids); x++ {
+		top.BidDepth += ob.Bids[x].Amount
+	}
+	for x := 0; x < cfg.Levels && x < len(ob.Asks); x++ {
+		top.AskDepth += ob.Asks[x].Amount
+	}
+
+	price, ok := ticker.ApplyBookTop(exchangeName, p, assetType, top, cfg.MaxLag)
+	if ok {
+		pushTickerUpdate(exchangeName, assetType, p, price)
+	}
+}
+
+// processTrailingStops moves the trailing stops for an exchange pair with the
+// best bid and ask and submits market orders for those which have triggered
+func processTrailingStops(exchangeName, assetType string, p pair.CurrencyPair, bid, ask float64) {
+	triggered, err := orders.UpdateTrailingStops(exchangeName, p, assetType, bid, ask)
+	if err != nil {
+		log.Printf("Failed to save trailing stops. Error: %s", err)
+	}
+
+	for x := range triggered {
+		stop := triggered[x]
+		side := exchange.OrderSideSell()
+		if stop.Side == orders.FillBuy {
+			side = exchange.OrderSideBuy()
+		}
+
+		message := fmt.Sprintf("%s %s trailing stop %d triggered at %f (stop %f), %s %f",
+			stop.Exchange, stop.Pair.Pair(), stop.ID, stop.TriggerPrice, stop.StopPrice,
+			side, stop.Amount)
+		log.Println(message)
+
+		if !bot.dryRun {
+			_, err = SubmitExchangeOrder(stop.Exchange, stop.Pair, side,
+				exchange.OrderTypeMarket(), stop.Amount, 0,
+				fmt.Sprintf("trailingstop-%d", stop.ID))
+			if err != nil {
+				message = fmt.Sprintf("%s. Failed to submit order: %s", message, err)
+				rearmed, rearmErr := orders.RearmTrailingStop(stop.ID)
+				switch {
+				case rearmErr != nil:
+					message = fmt.Sprintf("%s. Failed to re-arm stop: %s", message, rearmErr)
+				case rearmed:
+					message += ". Stop re-armed"
+				default:
+					message = fmt.Sprintf("%s. Stop left triggered after %d attempts", message, stop.Attempts)
+				}
+				log.Println(message)
+			}
+		}
+
+		bot.comms.PushEvent(base.Event{
+			Type:         "trailing_stop_triggered",
+			TradeDetails: message,
+		})
+	}
+}
+
 // TickerUpdaterRoutine fetches and updates the ticker for all enabled
-// currency pairs and exchanges
-func TickerUpdaterRoutine() {
+// currency pairs and exchanges until the context is cancelled
+func TickerUpdaterRoutine(ctx context.Context) {
 	log.Println("Starting ticker updater routine.")
 	var wg sync.WaitGroup
 	for {
-		wg.Add(len(bot.exchanges))
-		for x := range bot.exchanges {
-			go func(x int, wg *sync.WaitGroup) {
+		exchanges := bot.exchanges
+		wg.Add(len(exchanges))
+		for x := range exchanges {
+			go func(exch exchange.IBotExchange, wg *sync.WaitGroup) {
 				defer wg.Done()
-				if bot.exchanges[x] == nil {
+				if exch == nil {
 					return
 				}
-				exchangeName := bot.exchanges[x].GetName()
-				enabledCurrencies := bot.exchanges[x].GetEnabledCurrencies()
-				supportsBatching := bot.exchanges[x].SupportsRESTTickerBatchUpdates()
+				exchangeName := exch.GetName()
+				enabledCurrencies := exch.GetEnabledCurrencies()
+				supportsBatching := exch.SupportsRESTTickerBatchUpdates()
 				assetTypes, err := exchange.GetExchangeAssetTypes(exchangeName)
 				if err != nil {
 					log.Printf("failed to get %s exchange asset types. Error: %s",
@@ -210,6 +359,8 @@ func TickerUpdaterRoutine() {
 					printTickerSummary(result, c, assetType, exchangeName, err)
 					if err == nil {
 						bot.comms.StageTickerData(exchangeName, assetType, result)
+						processTrailingStops(exchangeName, assetType, c, result.Bid, result.Ask)
+						pushTickerUpdate(exchangeName, assetType, c, result)
 						if bot.config.Webserver.Enabled {
 							relayWebsocketEvent(result, "ticker_update", assetType, exchangeName)
 						}
@@ -219,36 +370,41 @@ func TickerUpdaterRoutine() {
 				for y := range assetTypes {
 					for z := range enabledCurrencies {
 						if supportsBatching && z > 0 {
-							processTicker(bot.exchanges[x], false, enabledCurrencies[z], assetTypes[y])
+							processTicker(exch, false, enabledCurrencies[z], assetTypes[y])
 							continue
 						}
-						processTicker(bot.exchanges[x], true, enabledCurrencies[z], assetTypes[y])
+						processTicker(exch, true, enabledCurrencies[z], assetTypes[y])
 					}
 				}
-			}(x, &wg)
+			}(exchanges[x], &wg)
 		}
 		wg.Wait()
 		log.Println("All enabled currency tickers fetched.")
-		time.Sleep(time.Second * 10)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second * 10):
+		}
 	}
 }
 
 // OrderbookUpdaterRoutine fetches and updates the orderbooks for all enabled
-// currency pairs and exchanges
-func OrderbookUpdaterRoutine() {
+// currency pairs and exchanges until the context is cancelled
+func OrderbookUpdaterRoutine(ctx context.Context) {
 	log.Println("Starting orderbook updater routine.")
 	var wg sync.WaitGroup
 	for {
-		wg.Add(len(bot.exchanges))
-		for x := range bot.exchanges {
-			go func(x int, wg *sync.WaitGroup) {
+		exchanges := bot.exchanges
+		wg.Add(len(exchanges))
+		for x := range exchanges {
+			go func(exch exchange.IBotExchange, wg *sync.WaitGroup) {
 				defer wg.Done()
 
-				if bot.exchanges[x] == nil {
+				if exch == nil {
 					return
 				}
-				exchangeName := bot.exchanges[x].GetName()
-				enabledCurrencies := bot.exchanges[x].GetEnabledCurrencies()
+				exchangeName := exch.GetName()
+				enabledCurrencies := exch.GetEnabledCurrencies()
 				assetTypes, err := exchange.GetExchangeAssetTypes(exchangeName)
 				if err != nil {
 					log.Printf("failed to get %s exchange asset types. Error: %s",
@@ -261,95 +417,206 @@ func OrderbookUpdaterRoutine() {
 					printOrderbookSummary(result, c, assetType, exchangeName, err)
 					if err == nil {
 						bot.comms.StageOrderbookData(exchangeName, assetType, result)
+						applyOrderbookTicker(exchangeName, assetType, c, result)
+						if bot.publisher != nil {
+							bot.publisher.PublishOrderbook(exchangeName, assetType, result)
+						}
 						if bot.config.Webserver.Enabled {
 							relayWebsocketEvent(result, "orderbook_update", assetType, exchangeName)
+							streamOrderbookUpdate(exchangeName, assetType, c, result)
 						}
 					}
 				}
 
 				for y := range assetTypes {
 					for z := range enabledCurrencies {
-						processOrderbook(bot.exchanges[x], enabledCurrencies[z], assetTypes[y])
+						processOrderbook(exch, enabledCurrencies[z], assetTypes[y])
 					}
 				}
-			}(x, &wg)
+			}(exchanges[x], &wg)
 		}
 		wg.Wait()
 		log.Println("All enabled currency orderbooks fetched.")
-		time.Sleep(time.Second * 10)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second * 10):
+		}
 	}
 }
 
-// WebsocketRoutine Initial routine management system for websocket
-func WebsocketRoutine(verbose bool) {
-	log.Println("Connecting exchange websocket services...")
+// OrderbookConsistencyRoutine compares the websocket maintained orderbooks of
+// all exchanges against REST snapshots every interval until the context is
+// cancelled, alerting on orderbooks which diverge beyond the thresholds and
+// resyncing them once they have diverged on consecutive checks
+func OrderbookConsistencyRoutine(ctx context.Context, cfg config.OrderbookConsistencyConfig) {
+	log.Printf("Starting orderbook consistency routine, checking every %v.\n", cfg.Interval)
+	thresholds := orderbook.ConsistencyThresholds{
+		MaxMismatchedLevels: cfg.MaxMismatchedLevels,
+		MaxPriceDiff:        cfg.MaxPriceDiff,
+		MaxSizeDiff:         cfg.MaxSizeDiff,
+	}
 
-	for i := range bot.exchanges {
-		go func(i int) {
-			if verbose {
-				log.Printf("Establishing websocket connection for %s",
-					bot.exchanges[i].GetName())
+	t := time.NewTicker(cfg.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		exchanges := bot.exchanges
+		for x := range exchanges {
+			checkOrderbookConsistency(exchanges[x], cfg, thresholds)
+		}
+	}
+}
+
+// checkOrderbookConsistency compares the websocket maintained orderbooks of
+// an exchange's enabled pairs against REST snapshots, recording the
+// divergence of each. A breach is alerted, and after cfg.ResyncAfter
+// consecutive breaches the orderbook is resynced from the snapshot or the
+// websocket is reconnected
+func checkOrderbookConsistency(exch exchange.IBotExchange, cfg config.OrderbookConsistencyConfig, thresholds orderbook.ConsistencyThresholds) {
+	ws, err := exch.GetWebsocket()
+	if err != nil || !ws.IsEnabled() {
+		return
+	}
+
+	exchangeName := exch.GetName()
+	assetTypes, err := exchange.GetExchangeAssetTypes(exchangeName)
+	if err != nil {
+		log.Printf("failed to get %s exchange asset types. Error: %s",
+			exchangeName, err)
+		return
+	}
+
+	enabledCurrencies := exch.GetEnabledCurrencies()
+	for y := range assetTypes {
+		for z := range enabledCurrencies {
+			p := enabledCurrencies[z]
+			local, ok := ws.Orderbook.GetOrderbook(p, assetTypes[y])
+			if !ok {
+				continue
 			}
 
-			ws, err := bot.exchanges[i].GetWebsocket()
+			snapshot, err := exch.UpdateOrderbook(p, assetTypes[y])
 			if err != nil {
-				return
+				log.Printf("%s failed to fetch %s %s orderbook snapshot. Error: %s",
+					exchangeName, p.Pair(), assetTypes[y], err)
+				continue
 			}
 
-			// Data handler routine
-			go WebsocketDataHandler(ws, verbose)
+			divergence := orderbook.Compare(local, snapshot, cfg.Depth)
+			breached := divergence.Exceeds(thresholds)
+			stats := orderbook.RecordConsistencyCheck(exchangeName, p, assetTypes[y],
+				divergence, breached)
+			if !breached {
+				continue
+			}
 
-			err = ws.Connect()
-			if err != nil {
-				switch err.Error() {
-				case exchange.WebsocketNotEnabled:
-					// Store in memory if enabled in future
-				default:
-					log.Println(err)
+			message := fmt.Sprintf("%s %s %s websocket orderbook diverged from REST snapshot: %d of top %d levels mismatched, max price diff %.4f%%, max size diff %.4f%%",
+				exchangeName, p.Pair(), assetTypes[y], divergence.Mismatches(),
+				divergence.Depth, divergence.MaxPriceDiff*100, divergence.MaxSizeDiff*100)
+
+			if cfg.ResyncAfter > 0 && stats.ConsecutiveBreaches >= int64(cfg.ResyncAfter) {
+				if cfg.Reconnect {
+					message += ", reconnecting websocket"
+					startRoutine(&bot.routines, func() {
+						WebsocketReconnect(getExchangeContext(exchangeName), ws, bot.verbose)
+					})
+				} else {
+					snapshot.Pair = local.Pair
+					snapshot.AssetType = local.AssetType
+					err = ws.Orderbook.Resync(snapshot, exchangeName)
+					if err != nil {
+						message += fmt.Sprintf(", failed to resync: %s", err)
+					} else {
+						message += ", resynced from snapshot"
+					}
 				}
+				orderbook.RecordResync(exchangeName, p, assetTypes[y])
 			}
-		}(i)
+
+			log.Println(message)
+			bot.comms.PushEvent(base.Event{
+				Type:         "orderbook_divergence",
+				TradeDetails: message,
+			})
+		}
 	}
 }
 
-var shutdowner = make(chan struct{}, 1)
-var wg sync.WaitGroup
+// WebsocketRoutine Initial routine management system for websocket, the data
+// handler routines run until the exchange is unloaded or the bot shuts down
+func WebsocketRoutine(verbose bool) {
+	log.Println("Connecting exchange websocket services...")
 
-// Websocketshutdown shuts down the exchange routines and then shuts down
-// governing routines
-func Websocketshutdown(ws *exchange.Websocket) error {
-	err := ws.Shutdown() // shutdown routines on the exchange
-	if err != nil {
-		log.Fatalf("routines.go error - failed to shutodwn %s", err)
+	for i := range bot.exchanges {
+		exch := bot.exchanges[i]
+		StartExchangeWebsocket(getExchangeContext(exch.GetName()), exch, verbose)
 	}
+}
 
-	timer := time.NewTimer(5 * time.Second)
-	c := make(chan struct{}, 1)
+// StartExchangeWebsocket connects the websocket of an exchange and starts its
+// data handler routine, which runs until the context is cancelled
+func StartExchangeWebsocket(ctx context.Context, exch exchange.IBotExchange, verbose bool) {
+	bot.routines.Add(1)
+	go func() {
+		defer bot.routines.Done()
+		if verbose {
+			log.Printf("Establishing websocket connection for %s",
+				exch.GetName())
+		}
 
-	go func(c chan struct{}) {
-		close(shutdowner)
-		wg.Wait()
-		c <- struct{}{}
-	}(c)
+		ws, err := exch.GetWebsocket()
+		if err != nil {
+			return
+		}
 
-	select {
-	case <-timer.C:
-		return errors.New("routines.go error - failed to shutdown routines")
+		// Data handler routine
+		bot.routines.Add(1)
+		go func() {
+			defer bot.routines.Done()
+			WebsocketDataHandler(ctx, ws, verbose)
+		}()
 
-	case <-c:
-		return nil
+		err = ws.Connect()
+		if err != nil {
+			switch err.Error() {
+			case exchange.WebsocketNotEnabled:
+				// Store in memory if enabled in future
+			default:
+				log.Println(err)
+			}
+		}
+	}()
+}
+
+// ShutdownWebsockets closes the websocket connections of all exchanges,
+// stopping the exchange websocket routines
+func ShutdownWebsockets() {
+	for x := range bot.exchanges {
+		ws, err := bot.exchanges[x].GetWebsocket()
+		if err != nil || !ws.IsEnabled() {
+			continue
+		}
+
+		err = ws.Shutdown()
+		if err != nil {
+			log.Printf("%s failed to shutdown websocket. Error: %s",
+				bot.exchanges[x].GetName(), err)
+		}
 	}
 }
 
 // streamDiversion is a diversion switch from websocket to REST or other
 // alternative feed
-func streamDiversion(ws *exchange.Websocket, verbose bool) {
-	wg.Add(1)
-	defer wg.Done()
-
+func streamDiversion(ctx context.Context, ws *exchange.Websocket, verbose bool) {
 	for {
 		select {
-		case <-shutdowner:
+		case <-ctx.Done():
 			return
 
 		case <-ws.Connected:
@@ -367,16 +634,17 @@ func streamDiversion(ws *exchange.Websocket, verbose bool) {
 }
 
 // WebsocketDataHandler handles websocket data coming from a websocket feed
-// associated with an exchange
-func WebsocketDataHandler(ws *exchange.Websocket, verbose bool) {
-	wg.Add(1)
-	defer wg.Done()
-
-	go streamDiversion(ws, verbose)
+// associated with an exchange until the context is cancelled
+func WebsocketDataHandler(ctx context.Context, ws *exchange.Websocket, verbose bool) {
+	bot.routines.Add(1)
+	go func() {
+		defer bot.routines.Done()
+		streamDiversion(ctx, ws, verbose)
+	}()
 
 	for {
 		select {
-		case <-shutdowner:
+		case <-ctx.Done():
 			return
 
 		case data := <-ws.DataHandler:
@@ -393,10 +661,28 @@ func WebsocketDataHandler(ws *exchange.Websocket, verbose bool) {
 					log.Println(data.(string))
 				}
 
+			case *exchange.WebsocketConnectionError:
+				connErr := data.(*exchange.WebsocketConnectionError)
+				log.Println(connErr)
+				conn, err := ws.GetConnection(connErr.Connection)
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+				bot.routines.Add(1)
+				go func() {
+					defer bot.routines.Done()
+					WebsocketConnectionReconnect(ctx, conn, verbose)
+				}()
+
 			case error:
 				switch {
 				case common.StringContains(data.(error).Error(), "close 1006"):
-					go WebsocketReconnect(ws, verbose)
+					bot.routines.Add(1)
+					go func() {
+						defer bot.routines.Done()
+						WebsocketReconnect(ctx, ws, verbose)
+					}()
 					continue
 				default:
 					log.Fatalf("routines.go exchange %s websocket error - %s", ws.GetName(), data)
@@ -407,22 +693,107 @@ func WebsocketDataHandler(ws *exchange.Websocket, verbose bool) {
 				if verbose {
 					log.Println("Websocket trades Updated:   ", data.(exchange.TradeData))
 				}
+				trade := data.(exchange.TradeData)
+				stored, err := trades.ProcessTrade(trade.Exchange, trades.Trade{
+					ID:        trade.TradeID,
+					Pair:      trade.CurrencyPair,
+					AssetType: trade.AssetType,
+					Price:     trade.Price,
+					Amount:    trade.Amount,
+					Side:      trade.Side,
+					Timestamp: trade.Timestamp,
+				})
+				if err != nil {
+					log.Printf("%s failed to store trade. Error: %s",
+						trade.Exchange, err)
+				} else if !stored {
+					// Duplicate or stale trade already counted
+					continue
+				}
+
+				err = kline.ProcessTrade(trade.Exchange, trade.CurrencyPair,
+					trade.AssetType, trade.Price, trade.Amount, trade.Timestamp)
+				if err != nil {
+					log.Printf("%s failed to build candle from trade. Error: %s",
+						trade.Exchange, err)
+				}
+				if bot.publisher != nil {
+					bot.publisher.PublishTrade(trade.Exchange, trade.AssetType,
+						trade.CurrencyPair, trade.Timestamp, trade.Price, trade.Amount,
+						trade.Side)
+				}
+
+			case exchange.FillData:
+				// Private order fill
+				if verbose {
+					log.Println("Websocket Fill Received:    ", data.(exchange.FillData))
+				}
+				fill := data.(exchange.FillData)
+				processFill(fill.AssetType, fill.CurrencyPair, orders.Fill{
+					Exchange:  fill.Exchange,
+					Pair:      fill.CurrencyPair.Display("", true).String(),
+					Side:      fill.Side,
+					OrderID:   fill.OrderID,
+					ClientID:  fill.ClientID,
+					TradeID:   fill.TradeID,
+					Amount:    fill.Amount,
+					Price:     fill.Price,
+					Fee:       fill.Fee,
+					Liquidity: fill.Liquidity,
+					Timestamp: fill.Timestamp,
+				})
 
 			case exchange.TickerData:
 				// Ticker data
 				if verbose {
 					log.Println("Websocket Ticker Updated:   ", data.(exchange.TickerData))
 				}
+				tickerData := data.(exchange.TickerData)
+				// Websocket tickers only carry the last price
+				processTrailingStops(tickerData.Exchange, tickerData.AssetType,
+					tickerData.Pair, tickerData.ClosePrice, tickerData.ClosePrice)
+				price := ticker.Price{
+					Pair:        tickerData.Pair,
+					LastUpdated: tickerData.Timestamp,
+					Last:        tickerData.ClosePrice,
+					High:        tickerData.HighPrice,
+					Low:         tickerData.LowPrice,
+					Volume:      tickerData.Quantity,
+				}
+				pushTickerUpdate(tickerData.Exchange, tickerData.AssetType, tickerData.Pair, price)
 			case exchange.KlineData:
 				// Kline data
 				if verbose {
 					log.Println("Websocket Kline Updated:    ", data.(exchange.KlineData))
 				}
+				err := processWebsocketKline(data.(exchange.KlineData))
+				if err != nil {
+					log.Printf("Failed to store websocket candle. Error: %s", err)
+				}
 			case exchange.WebsocketOrderbookUpdate:
 				// Orderbook data
 				if verbose {
 					log.Println("Websocket Orderbook Updated:", data.(exchange.WebsocketOrderbookUpdate))
 				}
+				update := data.(exchange.WebsocketOrderbookUpdate)
+				result, err := orderbook.GetOrderbook(update.Exchange, update.Pair, update.Asset)
+				if err == nil {
+					var bid, ask float64
+					if len(result.Bids) > 0 {
+						bid = result.Bids[0].Price
+					}
+					if len(result.Asks) > 0 {
+						ask = result.Asks[0].Price
+					}
+					processTrailingStops(update.Exchange, update.Asset, update.Pair, bid, ask)
+					applyOrderbookTicker(update.Exchange, update.Asset, update.Pair, result)
+					if bot.publisher != nil {
+						bot.publisher.PublishOrderbook(update.Exchange, update.Asset, result)
+					}
+					if bot.config.Webserver.Enabled {
+						streamOrderbookUpdate(update.Exchange, update.Asset, update.Pair, result)
+					}
+				}
 			default:
 				if verbose {
 					log.Println("Websocket Unknown type:     ", data)
@@ -433,7 +804,7 @@ func WebsocketDataHandler(ws *exchange.Websocket, verbose bool) {
 }
 
 // WebsocketReconnect tries to reconnect to a websocket stream
-func WebsocketReconnect(ws *exchange.Websocket, verbose bool) {
+func WebsocketReconnect(ctx context.Context, ws *exchange.Websocket, verbose bool) {
 	if verbose {
 		log.Printf("Websocket reconnection requested for %s", ws.GetName())
 	}
@@ -443,13 +814,11 @@ func WebsocketReconnect(ws *exchange.Websocket, verbose bool) {
 		log.Fatal(err)
 	}
 
-	wg.Add(1)
-	defer wg.Done()
-
 	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
 	for {
 		select {
-		case <-shutdowner:
+		case <-ctx.Done():
 			return
 
 		case <-ticker.C:
@@ -460,3 +829,938 @@ func WebsocketReconnect(ws *exchange.Websocket, verbose bool) {
 		}
 	}
 }
+
+// WebsocketConnectionReconnect tries to reconnect a named websocket
+// connection, leaving the other connections of the exchange untouched
+func WebsocketConnectionReconnect(ctx context.Context, conn *exchange.WebsocketConnection, verbose bool) {
+	if verbose {
+		log.Printf("Websocket connection %s reconnection requested", conn.Name)
+	}
+
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			err := conn.Reconnect()
+			if err == nil {
+				return
+			}
+			if verbose {
+				log.Printf("Websocket connection %s reconnection failed. Error: %s",
+					conn.Name, err)
+			}
+		}
+	}
+}
+
+// SyncExchangeClocks measures the clock skew between the local system and each
+// exchange, from its server time or the Date headers of its REST responses,
+// applying the correction to the exchange's generated timestamps and nonces
+func SyncExchangeClocks() {
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+
+		skew, err := exchange.MeasureClockSkew(bot.exchanges[x])
+		if err != nil {
+			continue
+		}
+
+		bot.exchanges[x].SetClockSkew(skew)
+		if skew > time.Second || skew < -time.Second {
+			log.Printf("%s clock skew of %v detected, correcting timestamps.\n",
+				bot.exchanges[x].GetName(), skew)
+		}
+	}
+}
+
+// MarketDataRoutine periodically updates the reference market data of the
+// configured cryptocurrencies in the valuation currency from the market data
+// providers until the context is cancelled
+func MarketDataRoutine(ctx context.Context, providers marketdata.Providers) {
+	log.Println("Starting market data routine.")
+	symbols := common.SplitStrings(bot.config.Currency.Cryptocurrencies, ",")
+	for {
+		err := providers.Update(symbols, bot.config.Currency.ValuationCurrency)
+		if err != nil {
+			log.Printf("Unable to update reference market data. Err: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(providers.GetPollingDelay()):
+		}
+	}
+}
+
+// ClockSkewRoutine periodically resynchronises the exchange clock skew
+// corrections until the context is cancelled
+func ClockSkewRoutine(ctx context.Context) {
+	log.Println("Starting clock skew routine.")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Minute * 10):
+		}
+		SyncExchangeClocks()
+	}
+}
+
+// StartNewListingsPollers starts a new listings routine for each enabled
+// exchange with the new listings poller enabled in its config
+func StartNewListingsPollers(ctx context.Context) {
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil || !bot.exchanges[x].IsEnabled() {
+			continue
+		}
+
+		exchCfg, err := bot.config.GetExchangeConfig(bot.exchanges[x].GetName())
+		if err != nil || exchCfg.NewListings == nil || !exchCfg.NewListings.Enabled {
+			continue
+		}
+
+		exch := bot.exchanges[x]
+		cfg := *exchCfg.NewListings
+		startRoutine(&bot.routines, func() { NewListingsRoutine(ctx, exch, cfg) })
+	}
+}
+
+// NewListingsRoutine periodically polls an exchange for newly listed currency
+// pairs, adding them to its available pairs, enabling those matching the
+// configured filter and emitting a new market event for each until the context
+// is cancelled. The routine stops if the first poll fails, as the exchange
+// can't list its pairs
+func NewListingsRoutine(ctx context.Context, exch exchange.IBotExchange, cfg config.NewListingsConfig) {
+	log.Printf("Starting %s new listings routine.\n", exch.GetName())
+	for polled := false; ; polled = true {
+		if polled {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(cfg.PollingDelay):
+			}
+		}
+
+		listed, err := exch.GetListedPairs()
+		if err != nil {
+			log.Printf("%s unable to get listed pairs. Err: %s\n", exch.GetName(), err)
+			if !polled {
+				return
+			}
+			continue
+		}
+
+		newPairs, enabledPairs, err := exch.AddNewListings(listed, cfg.Enable)
+		if err != nil {
+			log.Printf("%s unable to add new listings. Err: %s\n", exch.GetName(), err)
+		}
+
+		for y := range newPairs {
+			enabled := common.StringDataCompareUpper(enabledPairs, newPairs[y])
+			message := fmt.Sprintf("%s new market %s listed (enabled: %v)",
+				exch.GetName(), newPairs[y], enabled)
+			log.Println(message)
+
+			if bot.comms != nil {
+				bot.comms.PushEvent(base.Event{
+					Type:         "new_market",
+					TradeDetails: message,
+				})
+			}
+
+			if bot.config.Webserver.Enabled {
+				relayWebsocketEvent(map[string]interface{}{
+					"pair":    newPairs[y],
+					"enabled": enabled,
+				}, "new_market", "", exch.GetName())
+			}
+		}
+	}
+}
+
+// processWebsocketKline stores a candle received from an exchange websocket in
+// the kline store. Exchanges name their intervals differently so the interval
+// falls back to the candle open and close times when it can't be parsed.
+// Candles without a start time or interval can't be stored and are ignored
+func processWebsocketKline(data exchange.KlineData) error {
+	var interval time.Duration
+	intervals, err := kline.ParseIntervals(data.Interval)
+	if err == nil && len(intervals) == 1 {
+		interval = intervals[0]
+	} else {
+		interval = data.CloseTime.Sub(data.StartTime).Round(time.Second)
+	}
+
+	if data.StartTime.IsZero() || interval <= 0 {
+		return nil
+	}
+
+	return kline.ProcessCandles(data.Exchange, []kline.Candle{
+		{
+			Pair:      data.Pair,
+			AssetType: data.AssetType,
+			Interval:  interval,
+			StartTime: data.StartTime,
+			Open:      data.OpenPrice,
+			High:      data.HighPrice,
+			Low:       data.LowPrice,
+			Close:     data.ClosePrice,
+			Volume:    data.Volume,
+			Closed:    !data.CloseTime.IsZero() && !time.Now().Before(data.CloseTime),
+			Source:    kline.SourceExchange,
+		},
+	})
+}
+
+// candleFlushInterval is the interval at which candles built from trades are
+// checked for closure
+const candleFlushInterval = time.Second
+
+// CandleFlushRoutine closes the candles built from trades once their interval
+// has ended, so quiet pairs don't leave candles in progress
+func CandleFlushRoutine(ctx context.Context) {
+	log.Println("Starting candle flush routine.")
+	t := time.NewTicker(candleFlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		err := kline.FlushBuilders(time.Now())
+		if err != nil {
+			log.Printf("Failed to flush candle builders. Error: %s", err)
+		}
+	}
+}
+
+// StartDepositWatcher seeds the deposit watcher with the configured addresses
+// and starts the blockchain polling and event relay routines, the deposit
+// watcher is stopped on shutdown
+func StartDepositWatcher(ctx context.Context) {
+	cfg := bot.config.DepositWatcher
+	bot.deposits = portfolio.NewDepositWatcher(cfg.CheckDelay, cfg.Verbose)
+	for x := range cfg.Addresses {
+		err := bot.deposits.AddAddress(cfg.Addresses[x].Exchange,
+			cfg.Addresses[x].CoinType,
+			cfg.Addresses[x].Address,
+			cfg.Addresses[x].Confirmations)
+		if err != nil {
+			log.Printf("Failed to add deposit watcher address. Error: %s", err)
+		}
+	}
+
+	startRoutine(&bot.routines, bot.deposits.Start)
+	startRoutine(&bot.routines, func() { DepositWatcherRoutine(ctx) })
+}
+
+// DepositWatcherRoutine relays deposit events seen on the blockchain to the
+// communication mediums and websocket clients until the context is cancelled
+func DepositWatcherRoutine(ctx context.Context) {
+	log.Println("Starting deposit watcher routine.")
+	for {
+		var evt portfolio.DepositEvent
+		select {
+		case <-ctx.Done():
+			return
+		case evt = <-bot.deposits.Events:
+		}
+
+		message := fmt.Sprintf("%s deposit %s: %f %s to address %s (tx %s, %d confirmations)",
+			evt.Exchange,
+			evt.Status,
+			evt.Amount,
+			evt.CoinType,
+			evt.Address,
+			evt.TXHash,
+			evt.Confirmations)
+		log.Println(message)
+
+		bot.comms.PushEvent(base.Event{
+			Type:         "deposit_" + evt.Status,
+			TradeDetails: message,
+		})
+
+		if bot.config.Webserver.Enabled {
+			relayWebsocketEvent(evt, "deposit_update", "", evt.Exchange)
+		}
+	}
+}
+
+// maintenanceCheckDelay is the interval at which the exchanges are checked
+// for entering or leaving a maintenance window
+const maintenanceCheckDelay = time.Second * 10
+
+// MaintenanceWindowRoutine notifies the communication mediums as each exchange
+// enters and leaves its maintenance windows until the context is cancelled.
+// Order submissions are paused by the order manager during a window and
+// resume once it ends
+func MaintenanceWindowRoutine(ctx context.Context) {
+	inMaintenance := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(maintenanceCheckDelay):
+		}
+
+		now := time.Now()
+		for x := range bot.exchanges {
+			exchName := bot.exchanges[x].GetName()
+			w, until, active := orders.InMaintenance(exchName, now)
+			if active == inMaintenance[exchName] {
+				continue
+			}
+			inMaintenance[exchName] = active
+
+			message := fmt.Sprintf("%s maintenance window ended, order submissions resumed",
+				exchName)
+			eventType := "maintenance_ended"
+			if active {
+				message = fmt.Sprintf("%s entered maintenance window %s, order submissions paused until %s",
+					exchName, w.Name, until.UTC().Format(time.RFC3339))
+				eventType = "maintenance_started"
+			}
+			log.Println(message)
+			bot.comms.PushEvent(base.Event{
+				Type:         eventType,
+				TradeDetails: message,
+			})
+		}
+	}
+}
+
+// fiatTransferCheckDelay is the interval at which pending fiat transfers are
+// checked on their exchanges
+const fiatTransferCheckDelay = time.Minute
+
+// FiatTransferRoutine polls the exchanges for the state of pending fiat
+// transfers, notifying the communication mediums and websocket clients as
+// they move from submitted to processing to sent, until the context is
+// cancelled
+func FiatTransferRoutine(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(fiatTransferCheckDelay):
+		}
+
+		pending := exchange.GetPendingFiatTransfers()
+		for x := range pending {
+			exch := GetExchangeByName(pending[x].Exchange)
+			if exch == nil || !exch.IsEnabled() {
+				continue
+			}
+
+			tracker, ok := exch.(exchange.IFiatTransferTracker)
+			if !ok {
+				continue
+			}
+
+			status, err := tracker.GetFiatTransferStatus(pending[x].Reference)
+			if err != nil {
+				log.Printf("Failed to get %s fiat transfer %s status. Error: %s",
+					pending[x].Exchange, pending[x].Reference, err)
+				continue
+			}
+
+			transfer, changed, err := exchange.UpdateFiatTransfer(pending[x].Exchange, status)
+			if err != nil {
+				log.Printf("Failed to save fiat transfers. Error: %s", err)
+			}
+			if !changed {
+				continue
+			}
+
+			message := fmt.Sprintf("%s fiat transfer %s of %v %s is now %s",
+				transfer.Exchange, transfer.Reference, transfer.Amount,
+				transfer.Currency, transfer.Status)
+			log.Println(message)
+			bot.comms.PushEvent(base.Event{
+				Type:         "fiat_transfer_status",
+				TradeDetails: message,
+			})
+			if bot.config.Webserver.Enabled {
+				relayWebsocketEvent(transfer, "fiat_transfer_status", "", transfer.Exchange)
+			}
+		}
+	}
+}
+
+// orderStatusPollDelay is the interval at which order status pollers check for
+// orders due a poll
+const orderStatusPollDelay = time.Second
+
+// OrderStatusPollRoutine polls the status of the managed orders of enabled
+// exchanges with authenticated API support, applying fills and cancellations
+// to the client order registry. Orders near the touch are polled more often
+// than orders far from the market, within the request budget of each exchange
+func OrderStatusPollRoutine(ctx context.Context, cfg orders.StatusPollConfig) {
+	log.Println("Starting order status poll routine.")
+	pollers := make(map[string]*orders.StatusPoller)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(orderStatusPollDelay):
+		}
+
+		for x := range bot.exchanges {
+			exch := bot.exchanges[x]
+			if exch == nil || !exch.IsEnabled() || !exch.GetAuthenticatedAPISupport() {
+				continue
+			}
+
+			poller, ok := pollers[exch.GetName()]
+			if !ok {
+				poller = orders.NewStatusPoller(exch.GetName(), cfg, orderStateFunc(exch))
+				pollers[exch.GetName()] = poller
+			}
+
+			changed := poller.Poll(time.Now())
+			for y := range changed {
+				message := fmt.Sprintf("%s order %d with client order ID %s is now %s",
+					exch.GetName(), changed[y].OrderID, changed[y].ClientID,
+					changed[y].Status)
+				log.Println(message)
+				bot.comms.PushEvent(base.Event{
+					Type:         "order_status",
+					TradeDetails: message,
+				})
+				if bot.config.Webserver.Enabled {
+					relayWebsocketEvent(changed[y], "order_status", "", exch.GetName())
+				}
+			}
+		}
+	}
+}
+
+// orderStateFunc returns an order state function which requests the order
+// details from the exchange, recording any newly filled amount as a fill and
+// measuring its distance from the touch against the cached ticker
+func orderStateFunc(exch exchange.IBotExchange) orders.OrderStateFunc {
+	return func(order orders.ClientOrder) (orders.OrderState, error) {
+		detail, err := exch.GetExchangeOrderInfo(order.OrderID)
+		if err != nil {
+			return orders.OrderState{}, err
+		}
+		processPolledFill(exch.GetName(), order, detail)
+
+		state := orders.OrderState{Status: orders.ClientOrderSubmitted, Distance: -1}
+		status := common.StringToLower(detail.Status)
+		switch {
+		case common.StringContains(status, "cancel"):
+			state.Status = orders.ClientOrderCancelled
+		case detail.Amount > 0 && detail.OpenVolume == 0,
+			common.StringContains(status, "fill") && !common.StringContains(status, "partial"):
+			state.Status = orders.ClientOrderFilled
+		}
+
+		tick, err := ticker.GetTicker(exch.GetName(),
+			pair.NewCurrencyPair(detail.BaseCurrency, detail.QuoteCurrency), ticker.Spot)
+		if err != nil || detail.Price <= 0 {
+			return state, nil
+		}
+
+		// Orders through the touch are about to fill and poll as if at it
+		side := common.StringToLower(detail.OrderSide)
+		switch {
+		case (side == "buy" || side == "bid") && tick.Ask > 0:
+			state.Distance = math.Max((tick.Ask-detail.Price)/tick.Ask, 0)
+		case (side == "sell" || side == "ask") && tick.Bid > 0:
+			state.Distance = math.Max((detail.Price-tick.Bid)/tick.Bid, 0)
+		}
+		return state, nil
+	}
+}
+
+// processPolledFill records the amount of a polled order filled since the
+// fills already recorded for it, for exchanges without private websockets.
+// Polled fills are priced at the order price and carry no fee or liquidity
+// flag as order details don't report them
+func processPolledFill(exchName string, order orders.ClientOrder, detail exchange.OrderDetail) {
+	executed := detail.Amount - detail.OpenVolume
+	if detail.Amount <= 0 || executed <= 0 || detail.Price <= 0 {
+		return
+	}
+
+	filled := orders.GetOrderFilledAmount(exchName, order.OrderID)
+	if executed-filled <= executed*1e-9 {
+		return
+	}
+
+	cp := pair.NewCurrencyPair(detail.BaseCurrency, detail.QuoteCurrency)
+	processFill(ticker.Spot, cp, orders.Fill{
+		Exchange: exchName,
+		Pair:     cp.Display("", true).String(),
+		Side:     detail.OrderSide,
+		OrderID:  order.OrderID,
+		ClientID: order.ClientID,
+		TradeID:  fmt.Sprintf("poll-%d-%v", order.OrderID, executed),
+		Amount:   executed - filled,
+		Price:    detail.Price,
+	})
+}
+
+// processFill normalises and records a fill of one of the bot's orders,
+// persisting it and publishing it to the communication mediums, message
+// broker and websocket clients. Fills already recorded are dropped
+func processFill(assetType string, cp pair.CurrencyPair, f orders.Fill) {
+	f, err := orders.NormaliseFill(f)
+	if err == nil {
+		err = orders.RecordFill(f)
+	}
+	if err == orders.ErrDuplicateFill {
+		return
+	}
+	if err != nil {
+		log.Printf("%s failed to record fill of order %d. Error: %s",
+			f.Exchange, f.OrderID, err)
+		return
+	}
+
+	message := fmt.Sprintf("%s order %d with client order ID %s filled %s %f %s at %f, fee %f",
+		f.Exchange, f.OrderID, f.ClientID, common.StringToLower(f.Side), f.Amount,
+		f.Pair, f.Price, f.Fee)
+	log.Println(message)
+	bot.comms.PushEvent(base.Event{
+		Type:         "order_fill",
+		TradeDetails: message,
+	})
+	if bot.publisher != nil {
+		bot.publisher.PublishFill(assetType, cp, f)
+	}
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(f, "order_fill", assetType, f.Exchange)
+	}
+}
+
+// spreadOrderInterval is the interval at which spread orders are processed
+const spreadOrderInterval = time.Second
+
+// SpreadOrderRoutine executes spread orders once their target differential is
+// reached and hedges legged spreads as they fill, notifying the communication
+// mediums of spreads which fail or are left unhedged. Spread execution stops
+// when the context is cancelled
+func SpreadOrderRoutine(ctx context.Context) {
+	log.Println("Starting spread order routine.")
+	t := time.NewTicker(spreadOrderInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		flagged := orders.ProcessSpreadOrders(getSpreadLegPrice, submitSpreadLeg, time.Now())
+		for x := range flagged {
+			spread := flagged[x]
+			message := fmt.Sprintf("Spread order %d %s, filled %f of %f, residual leg amounts %v",
+				spread.ID, common.StringToLower(spread.Status), spread.FilledQuantity(),
+				spread.Quantity, spread.Residuals())
+			log.Println(message)
+
+			bot.comms.PushEvent(base.Event{
+				Type:         "spread_order_" + common.StringToLower(spread.Status),
+				TradeDetails: message,
+			})
+		}
+	}
+}
+
+// getSpreadLegPrice returns the best ask for a buy leg or the best bid for a
+// sell leg from the ticker store
+func getSpreadLegPrice(leg orders.SpreadLeg) (float64, bool) {
+	tick, err := ticker.GetTicker(leg.Exchange, leg.Pair, ticker.Spot)
+	if err != nil {
+		return 0, false
+	}
+
+	if leg.Side == orders.FillBuy {
+		return tick.Ask, tick.Ask > 0
+	}
+	return tick.Bid, tick.Bid > 0
+}
+
+// submitSpreadLeg submits an order for a spread leg, a leg without a price is
+// submitted as a market order
+func submitSpreadLeg(leg orders.SpreadLeg, amount float64, clientID string) (int64, error) {
+	side := exchange.OrderSideSell()
+	if leg.Side == orders.FillBuy {
+		side = exchange.OrderSideBuy()
+	}
+
+	orderType := exchange.OrderTypeLimit()
+	if leg.Price == 0 {
+		orderType = exchange.OrderTypeMarket()
+	}
+
+	return SubmitExchangeOrder(leg.Exchange, leg.Pair, side, orderType, amount,
+		leg.Price, clientID)
+}
+
+// ProfitLossReportRoutine sends the realised profit and loss of the current
+// session to the communication mediums at the supplied interval until the
+// context is cancelled
+func ProfitLossReportRoutine(ctx context.Context, interval time.Duration) {
+	log.Printf("Starting profit and loss report routine, reporting every %v.\n", interval)
+	sessionStarted := time.Now()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		report := orders.GetProfitLossReport(sessionStarted, time.Now())
+		var netPnL float64
+		for x := range report {
+			netPnL += report[x].NetPnL
+		}
+
+		message := orders.FormatProfitLossReport(report)
+		strategyReport := orders.GetStrategyProfitLossReport("", sessionStarted, time.Now())
+		if len(strategyReport) > 0 {
+			message += "\nBy strategy:\n" + orders.FormatProfitLossReport(strategyReport)
+		}
+		log.Printf("Profit and loss report:\n%s\n", message)
+
+		bot.comms.PushEvent(base.Event{
+			Type:         "profit_loss_report",
+			GainLoss:     fmt.Sprintf("%.8f", netPnL),
+			TradeDetails: message,
+		})
+	}
+}
+
+// fillPollDelay is the interval at which submitted orders are polled for fills
+const fillPollDelay = time.Second * 30
+
+// FillPollRoutine polls the orders submitted to enabled exchanges with
+// authenticated API support, recording the amount filled since the last poll
+// as a fill so it is included in profit and loss reports, until the context is
+// cancelled. It is used when order status polling, which also records polled
+// fills, is disabled
+func FillPollRoutine(ctx context.Context) {
+	log.Println("Starting fill poll routine.")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(fillPollDelay):
+		}
+
+		for x := range bot.exchanges {
+			exch := bot.exchanges[x]
+			if exch == nil || !exch.IsEnabled() || !exch.GetAuthenticatedAPISupport() {
+				continue
+			}
+
+			submitted := orders.GetClientOrders(exch.GetName(), orders.ClientOrderSubmitted)
+			for y := range submitted {
+				detail, err := exch.GetExchangeOrderInfo(submitted[y].OrderID)
+				if err != nil {
+					continue
+				}
+				processPolledFill(exch.GetName(), submitted[y], detail)
+			}
+		}
+	}
+}
+
+// Consts for client order maintenance
+const (
+	clientOrderMaintenanceDelay = time.Minute
+	clientOrderRetention        = time.Hour * 24
+)
+
+// ClientOrderMaintenanceRoutine resolves client orders with an unknown
+// submission outcome against the open orders of their exchange and prunes
+// filled and cancelled client orders older than the retention period from the
+// registry, until the context is cancelled
+func ClientOrderMaintenanceRoutine(ctx context.Context) {
+	log.Println("Starting client order maintenance routine.")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(clientOrderMaintenanceDelay):
+		}
+
+		for x := range bot.exchanges {
+			exch := bot.exchanges[x]
+			if exch == nil || !exch.IsEnabled() || !exch.GetAuthenticatedAPISupport() {
+				continue
+			}
+
+			exchName := exch.GetName()
+			if len(orders.GetClientOrders(exchName, orders.ClientOrderUnknown)) == 0 {
+				continue
+			}
+
+			openOrders, err := exch.GetExchangeOpenOrders()
+			if err != nil {
+				continue
+			}
+
+			open := make([]orders.OpenOrder, len(openOrders))
+			for y := range openOrders {
+				open[y] = orders.OpenOrder{
+					OrderID:  openOrders[y].ID,
+					ClientID: openOrders[y].ClientID,
+				}
+			}
+
+			resolved := orders.ResolveUnknownOrders(exchName, open)
+			for y := range resolved {
+				log.Printf("%s client order ID %s found open with order ID %d.\n",
+					exchName, resolved[y].ClientID, resolved[y].OrderID)
+			}
+		}
+
+		orders.RemoveClientOrders(time.Now().Add(-clientOrderRetention))
+	}
+}
+
+// rebalanceStrategyID is the strategy rebalance orders are attributed to
+const rebalanceStrategyID = "rebalance"
+
+// RebalanceRoutine checks the exchange holdings against the rebalance targets
+// every check interval until the context is cancelled. The trades of a
+// triggered rebalance are submitted as market orders routed to the cheapest
+// venue, or only reported when in dry run mode
+func RebalanceRoutine(ctx context.Context, cfg config.RebalanceConfig, dryRun bool) {
+	rebalancer, err := portfolio.NewRebalancer(cfg.Targets, cfg.DriftThreshold,
+		cfg.Interval, cfg.MinTradeValue)
+	if err != nil {
+		log.Printf("Unable to start rebalance routine. Err: %s\n", err)
+		return
+	}
+
+	log.Printf("Starting rebalance routine, checking every %v.\n", cfg.CheckInterval)
+	t := time.NewTicker(cfg.CheckInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		valuer := portfolio.NewValuer(bot.config.Currency.ValuationCurrency,
+			getLastPrice,
+			convertFiatCurrency)
+		holdings := getExchangeHoldings()
+		plan := rebalancer.Plan(&holdings, valuer, routeRebalanceTrade(valuer.BaseCurrency), time.Now())
+		if plan.Trigger == "" {
+			continue
+		}
+
+		plan.DryRun = dryRun
+		if !dryRun {
+			executeRebalancePlan(ctx, &plan)
+		}
+		portfolio.RecordRebalancePlan(plan)
+
+		message := formatRebalancePlan(plan)
+		log.Printf("Rebalance report:\n%s\n", message)
+		bot.comms.PushEvent(base.Event{
+			Type:         "rebalance",
+			TradeDetails: message,
+		})
+	}
+}
+
+// ArbitrageRoutine trades price differences of a pair between two exchanges
+// against the inventory held on both every check interval until the context
+// is cancelled. Opportunities are executed as simultaneous spread orders, a
+// spread left with leg risk halts trading until restart, and the inventory is
+// evened out between the exchanges every rebalance interval. In dry run mode
+// opportunities and transfers are only reported
+func ArbitrageRoutine(ctx context.Context, cfg config.ArbitrageConfig, dryRun bool) {
+	p := pair.NewCurrencyPairDelimiter(cfg.Pair, "-")
+	executor, err := orders.NewArbitrageExecutor([2]string{cfg.Exchanges[0], cfg.Exchanges[1]},
+		p, cfg.MinProfit, cfg.MaxAmount, cfg.HedgeTimeout)
+	if err != nil {
+		log.Printf("Unable to start arbitrage routine. Err: %s\n", err)
+		return
+	}
+
+	log.Printf("Starting %s arbitrage routine between %s, checking every %v.\n",
+		p.Pair(), common.JoinStrings(cfg.Exchanges, " and "), cfg.CheckInterval)
+	t := time.NewTicker(cfg.CheckInterval)
+	defer t.Stop()
+	var rebalance <-chan time.Time
+	if cfg.RebalanceInterval > 0 {
+		r := time.NewTicker(cfg.RebalanceInterval)
+		defer r.Stop()
+		rebalance = r.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-rebalance:
+			rebalanceArbitrageInventory(cfg, p, dryRun)
+			continue
+		case <-t.C:
+		}
+
+		var venues [2]orders.ArbitrageVenue
+		var ok bool
+		if venues[0], ok = getArbitrageVenue(cfg.Exchanges[0], p, cfg.MaxAmount); !ok {
+			continue
+		}
+		if venues[1], ok = getArbitrageVenue(cfg.Exchanges[1], p, cfg.MaxAmount); !ok {
+			continue
+		}
+
+		result, err := executor.Step(venues, dryRun)
+		if err != nil {
+			log.Printf("Arbitrage spread order rejected. Err: %s\n", err)
+		}
+
+		var message, eventType string
+		switch {
+		case result.LegRisk:
+			eventType = "arbitrage_leg_risk"
+			message = fmt.Sprintf("%s arbitrage halted, %s", p.Pair(), executor.Halted())
+		case result.Spread != nil && result.Spread.Status == orders.SpreadFilled:
+			buy, sell := result.Spread.Legs[0], result.Spread.Legs[1]
+			eventType = "arbitrage_filled"
+			message = fmt.Sprintf("%s arbitrage spread order %d filled %f, bought on %s at %f and sold on %s at %f",
+				p.Pair(), result.Spread.ID, result.Spread.FilledQuantity(), buy.Exchange,
+				buy.AveragePrice, sell.Exchange, sell.AveragePrice)
+		case result.Opportunity != nil:
+			o := result.Opportunity
+			eventType = "arbitrage_opportunity"
+			message = fmt.Sprintf("%s arbitrage buying %f on %s at %f and selling on %s at %f, expected profit %f %s (%.3f%%)",
+				p.Pair(), o.Amount, o.BuyExchange, o.BuyPrice, o.SellExchange, o.SellPrice,
+				o.Profit, p.SecondCurrency, o.ProfitRate*100)
+			if dryRun {
+				message += " (dry run)"
+			}
+		default:
+			continue
+		}
+
+		log.Println(message)
+		bot.comms.PushEvent(base.Event{
+			Type:         eventType,
+			TradeDetails: message,
+		})
+	}
+}
+
+// StrategyPluginRoutine runs an external strategy loaded from a Go plugin or
+// run as a sidecar process, delivering the ticker updates of its pairs and
+// the fills of its orders every interval and submitting the orders it returns
+// on its behalf, or only reporting them in dry run mode. The strategy is
+// stopped when the context is cancelled or its sidecar fails
+func StrategyPluginRoutine(ctx context.Context, cfg config.StrategyPluginConfig, dryRun bool) {
+	var s strategy.Strategy
+	var err error
+	if cfg.Plugin != "" {
+		s, err = strategy.LoadPlugin(cfg.Plugin)
+	} else {
+		s = strategy.NewSidecar(cfg.Command, cfg.Args, cfg.Timeout)
+	}
+	if err == nil {
+		err = s.Init(cfg.Name, cfg.Settings)
+	}
+	if err != nil {
+		log.Printf("Unable to start strategy %s. Err: %s\n", cfg.Name, err)
+		return
+	}
+	defer func() {
+		if err := s.Stop(); err != nil {
+			log.Printf("Strategy %s failed to stop. Err: %s\n", cfg.Name, err)
+		}
+	}()
+
+	log.Printf("Starting strategy %s, checking every %v.\n", cfg.Name, cfg.Interval)
+	t := time.NewTicker(cfg.Interval)
+	defer t.Stop()
+	updated := make(map[string]time.Time)
+	fillsSince := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		for _, e := range getStrategyPluginEvents(cfg, updated, &fillsSince) {
+			intents, err := s.OnEvent(e)
+			if err != nil {
+				log.Printf("Strategy %s failed to handle %s event. Err: %s\n", cfg.Name, e.Type, err)
+				if err == strategy.ErrSidecarTimeout || err == strategy.ErrSidecarExited {
+					return
+				}
+			}
+
+			for x := range intents {
+				intent := intents[x]
+				message := fmt.Sprintf("Strategy %s %s %s order for %f %s on %s",
+					cfg.Name, intent.Side, intent.Type, intent.Amount, intent.Pair, intent.Exchange)
+				if intent.Price > 0 {
+					message += fmt.Sprintf(" at %f", intent.Price)
+				}
+
+				if dryRun {
+					message += " (dry run)"
+				} else if orderID, err := submitOrderIntent(ctx, cfg.Name, intent); err != nil {
+					message += " failed: " + err.Error()
+				} else {
+					message += fmt.Sprintf(", order %d", orderID)
+				}
+				log.Println(message)
+			}
+		}
+	}
+}
+
+// rebalanceArbitrageInventory evens out the inventory of both currencies of
+// the arbitrage pair between its exchanges with the transfer planner,
+// reporting the planned transfers and withdrawing them unless in dry run mode
+func rebalanceArbitrageInventory(cfg config.ArbitrageConfig, p pair.CurrencyPair, dryRun bool) {
+	for _, c := range []pair.CurrencyItem{p.FirstCurrency, p.SecondCurrency} {
+		balances := make(map[string]float64)
+		for _, exchName := range cfg.Exchanges {
+			balances[exchName], _ = GetExchangeBalance(exchName, c.String())
+		}
+
+		for _, transfer := range portfolio.PlanInventoryTransfers(c.String(), balances, cfg.RebalanceThreshold) {
+			message := fmt.Sprintf("Arbitrage inventory transfer of %f %s from %s to %s",
+				transfer.Amount, transfer.Currency, transfer.From, transfer.To)
+			if dryRun {
+				message += " (dry run)"
+			} else if id, err := executeInventoryTransfer(transfer); err != nil {
+				message += " failed: " + err.Error()
+			} else {
+				message += ", withdrawal ID " + id
+			}
+
+			log.Println(message)
+			bot.comms.PushEvent(base.Event{
+				Type:         "arbitrage_transfer",
+				TradeDetails: message,
+			})
+		}
+	}
+}
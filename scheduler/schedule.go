@@ -0,0 +1,204 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule descriptors which can be used in place of a cron expression
+const (
+	DescriptorEvery    = "@every"
+	DescriptorHourly   = "@hourly"
+	DescriptorDaily    = "@daily"
+	DescriptorMidnight = "@midnight"
+	DescriptorWeekly   = "@weekly"
+	DescriptorMonthly  = "@monthly"
+)
+
+// maxScheduleSearch is how far ahead Next searches for a matching time before
+// giving up on a schedule which can never match, such as the 31st of February
+const maxScheduleSearch = time.Hour * 24 * 366 * 5
+
+// ErrInvalidSchedule is returned when a schedule can't be parsed
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Schedule holds when a job runs, either a cron expression of minute, hour,
+// day of month, month and day of week fields or a fixed interval
+type Schedule struct {
+	spec     string
+	interval time.Duration
+
+	minute, hour, dom, month, dow map[int]bool
+	domAny, dowAny                bool
+}
+
+// ParseSchedule parses a five field cron expression, supporting *, ranges,
+// steps and lists, or one of the @every <duration>, @hourly, @daily,
+// @midnight, @weekly or @monthly descriptors
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	s := Schedule{spec: spec}
+
+	switch {
+	case strings.HasPrefix(spec, DescriptorEvery):
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, DescriptorEvery)))
+		if err != nil || interval < time.Second {
+			return Schedule{}, fmt.Errorf("%s %q: interval must be a duration of at least 1s", ErrInvalidSchedule, spec)
+		}
+		s.interval = interval
+		return s, nil
+	case spec == DescriptorHourly:
+		spec = "0 * * * *"
+	case spec == DescriptorDaily, spec == DescriptorMidnight:
+		spec = "0 0 * * *"
+	case spec == DescriptorWeekly:
+		spec = "0 0 * * 0"
+	case spec == DescriptorMonthly:
+		spec = "0 0 1 * *"
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return Schedule{}, fmt.Errorf("%s %q: expected %d fields", ErrInvalidSchedule, s.spec, len(fields))
+	}
+
+	parsed := make([]map[int]bool, len(fields))
+	for x := range fields {
+		values, err := parseField(parts[x], fields[x])
+		if err != nil {
+			return Schedule{}, fmt.Errorf("%s %q: %s", ErrInvalidSchedule, s.spec, err)
+		}
+		parsed[x] = values
+	}
+
+	// Sunday can be either 0 or 7
+	if parsed[4][7] {
+		parsed[4][0] = true
+	}
+
+	s.minute, s.hour, s.dom, s.month, s.dow = parsed[0], parsed[1], parsed[2], parsed[3], parsed[4]
+	s.domAny = parts[2] == "*"
+	s.dowAny = parts[4] == "*"
+	return s, nil
+}
+
+// parseField parses a comma separated list of values, ranges and steps into
+// the set of values matched by a cron field
+func parseField(expr string, f field) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, item := range strings.Split(expr, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i != -1 {
+			var err error
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("%s step %q is invalid", f.name, item)
+			}
+			item = item[:i]
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case item == "*":
+		case strings.Contains(item, "-"):
+			bounds := strings.SplitN(item, "-", 2)
+			var err error
+			start, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, fmt.Errorf("%s range %q is invalid", f.name, item)
+			}
+			end, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s range %q is invalid", f.name, item)
+			}
+		default:
+			v, err := strconv.Atoi(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s value %q is invalid", f.name, item)
+			}
+			start = v
+			if step == 1 {
+				end = v
+			}
+		}
+
+		if start < f.min || end > f.max || start > end {
+			return nil, fmt.Errorf("%s %q is outside of %d-%d", f.name, item, f.min, f.max)
+		}
+
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// Next returns the first time after t the schedule runs, or the zero time if
+// the schedule never matches
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.interval > 0 {
+		return t.Add(s.interval)
+	}
+
+	if s.minute == nil {
+		return time.Time{}
+	}
+
+	limit := t.Add(maxScheduleSearch)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for t.Before(limit) {
+		if !s.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchDay returns whether the day of t matches the schedule. As with cron,
+// when both the day of month and day of week are restricted either can match
+func (s Schedule) matchDay(t time.Time) bool {
+	dom := s.dom[t.Day()]
+	dow := s.dow[int(t.Weekday())]
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// String returns the schedule as it was configured
+func (s Schedule) String() string {
+	return s.spec
+}
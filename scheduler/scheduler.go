@@ -0,0 +1,393 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// maxIdle is the longest the scheduler sleeps between checking for due jobs,
+// so jobs added while it is running are picked up
+const maxIdle = time.Minute
+
+// Vars for the scheduler
+var (
+	// ErrJobExists is returned when a job is added with the name of an
+	// existing job
+	ErrJobExists = errors.New("job already exists")
+	// ErrInvalidJob is returned when a job is missing a name, schedule or
+	// function
+	ErrInvalidJob = errors.New("job requires a name, schedule and run function")
+	// ErrJobNotFound is returned when no job exists with the name
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning is returned when a job is triggered while it is already
+	// running
+	ErrJobRunning = errors.New("job is already running")
+)
+
+// JobFunc is the function run by a job for each of its exchanges
+type JobFunc func(ctx context.Context, run *Run) error
+
+// Job holds a background job and when it runs. The job function is run
+// concurrently for each exchange, or once with a blank exchange when none are
+// set. RateBudget is the number of requests per minute each exchange run may
+// make, zero leaves it unlimited
+type Job struct {
+	Name       string
+	Schedule   Schedule
+	Exchanges  []string
+	RateBudget int
+	Run        JobFunc
+}
+
+// JobStatus holds the state of a job. Errors holds the error of each exchange
+// which failed on the last run
+type JobStatus struct {
+	Name       string            `json:"name"`
+	Schedule   string            `json:"schedule"`
+	Exchanges  []string          `json:"exchanges"`
+	RateBudget int               `json:"rateBudget"`
+	Running    bool              `json:"running"`
+	LastRun    time.Time         `json:"lastRun"`
+	NextRun    time.Time         `json:"nextRun"`
+	Errors     map[string]string `json:"errors,omitempty"`
+}
+
+type job struct {
+	Job
+	budgets map[string]*Budget
+	running bool
+	lastRun time.Time
+	nextRun time.Time
+	errors  map[string]string
+}
+
+// Scheduler runs jobs on their schedules and persists their progress so runs
+// resume where the previous one stopped
+type Scheduler struct {
+	progressFile string
+	progress     map[string]map[string]string
+	jobs         []*job
+	mtx          sync.Mutex
+	wg           sync.WaitGroup
+}
+
+// New returns a scheduler which persists job progress to progressFile, loading
+// any progress already saved. A blank progress file keeps progress in memory
+func New(progressFile string) (*Scheduler, error) {
+	s := &Scheduler{
+		progressFile: progressFile,
+		progress:     make(map[string]map[string]string),
+	}
+
+	if progressFile == "" {
+		return s, nil
+	}
+
+	data, err := common.ReadFile(progressFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	err = common.JSONDecode(data, &s.progress)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add adds a job to the scheduler, its first run is the next time its
+// schedule matches
+func (s *Scheduler) Add(j Job) error {
+	if j.Name == "" || j.Run == nil || j.Schedule.String() == "" {
+		return ErrInvalidJob
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.getJob(j.Name) != nil {
+		return fmt.Errorf("%s: %s", ErrJobExists, j.Name)
+	}
+
+	next := j.Schedule.Next(time.Now())
+	if next.IsZero() {
+		return fmt.Errorf("%s: schedule %s never runs", ErrInvalidJob, j.Schedule)
+	}
+
+	s.jobs = append(s.jobs, &job{
+		Job:     j,
+		budgets: make(map[string]*Budget),
+		nextRun: next,
+		errors:  make(map[string]string),
+	})
+	return nil
+}
+
+// Start runs jobs as they become due until the context is cancelled, then
+// waits for running jobs to stop. A job which is still running when it is next
+// due is skipped
+func (s *Scheduler) Start(ctx context.Context) {
+	for {
+		now := time.Now()
+		wait := maxIdle
+
+		s.mtx.Lock()
+		for _, j := range s.jobs {
+			if !now.Before(j.nextRun) {
+				if !j.running {
+					s.startJob(ctx, j)
+				}
+				j.nextRun = j.Schedule.Next(now)
+			}
+			if !j.nextRun.IsZero() && j.nextRun.Sub(now) < wait {
+				wait = j.nextRun.Sub(now)
+			}
+		}
+		s.mtx.Unlock()
+
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Trigger runs a job immediately and waits for it to finish, returning the
+// first exchange error of the run
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	s.mtx.Lock()
+	j := s.getJob(name)
+	if j == nil {
+		s.mtx.Unlock()
+		return ErrJobNotFound
+	}
+
+	if j.running {
+		s.mtx.Unlock()
+		return ErrJobRunning
+	}
+	done := s.startJob(ctx, j)
+	s.mtx.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for exch, e := range j.errors {
+		return fmt.Errorf("%s %s: %s", name, exch, e)
+	}
+	return nil
+}
+
+// GetStatus returns the status of every job
+func (s *Scheduler) GetStatus() []JobStatus {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var status []JobStatus
+	for _, j := range s.jobs {
+		js := JobStatus{
+			Name:       j.Name,
+			Schedule:   j.Schedule.String(),
+			Exchanges:  append([]string(nil), j.Exchanges...),
+			RateBudget: j.RateBudget,
+			Running:    j.running,
+			LastRun:    j.lastRun,
+			NextRun:    j.nextRun,
+		}
+		if len(j.errors) > 0 {
+			js.Errors = make(map[string]string)
+			for k, v := range j.errors {
+				js.Errors[k] = v
+			}
+		}
+		status = append(status, js)
+	}
+	return status
+}
+
+// startJob runs the job for each of its exchanges in the background,
+// returning a channel closed once every run has finished. s.mtx must be held
+// by the caller
+func (s *Scheduler) startJob(ctx context.Context, j *job) chan struct{} {
+	exchanges := j.Exchanges
+	if len(exchanges) == 0 {
+		exchanges = []string{""}
+	}
+
+	j.running = true
+	j.lastRun = time.Now()
+	j.errors = make(map[string]string)
+
+	var runs sync.WaitGroup
+	for _, exch := range exchanges {
+		budget, ok := j.budgets[exch]
+		if !ok {
+			budget = NewBudget(j.RateBudget)
+			j.budgets[exch] = budget
+		}
+
+		run := &Run{
+			Job:      j.Name,
+			Exchange: exch,
+			budget:   budget,
+			s:        s,
+		}
+
+		runs.Add(1)
+		go func() {
+			defer runs.Done()
+			err := j.Run(ctx, run)
+			if err != nil {
+				s.mtx.Lock()
+				j.errors[run.Exchange] = err.Error()
+				s.mtx.Unlock()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		runs.Wait()
+		s.mtx.Lock()
+		j.running = false
+		s.mtx.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+// getJob returns the job with the name or nil, s.mtx must be held by the
+// caller
+func (s *Scheduler) getJob(name string) *job {
+	for _, j := range s.jobs {
+		if j.Name == name {
+			return j
+		}
+	}
+	return nil
+}
+
+// getProgress returns a progress value of a job run
+func (s *Scheduler) getProgress(run, key string) string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.progress[run][key]
+}
+
+// setProgress stores a progress value of a job run and persists it
+func (s *Scheduler) setProgress(run, key, value string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.progress[run] == nil {
+		s.progress[run] = make(map[string]string)
+	}
+	s.progress[run][key] = value
+
+	if s.progressFile == "" {
+		return nil
+	}
+
+	data, err := common.JSONEncode(s.progress)
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(s.progressFile, data)
+}
+
+// Run holds a single exchange run of a job, giving the job function access to
+// its rate budget and persisted progress
+type Run struct {
+	Job      string
+	Exchange string
+
+	budget *Budget
+	s      *Scheduler
+}
+
+// Wait blocks until the run's rate budget allows another request
+func (r *Run) Wait(ctx context.Context) error {
+	return r.budget.Wait(ctx)
+}
+
+// GetProgress returns a progress value saved by a previous run of the job on
+// the same exchange, or a blank string
+func (r *Run) GetProgress(key string) string {
+	return r.s.getProgress(r.key(), key)
+}
+
+// SetProgress saves a progress value, such as the last record fetched, so the
+// next run of the job on the same exchange can resume from it
+func (r *Run) SetProgress(key, value string) error {
+	return r.s.setProgress(r.key(), key, value)
+}
+
+func (r *Run) key() string {
+	if r.Exchange == "" {
+		return r.Job
+	}
+	return r.Job + "/" + r.Exchange
+}
+
+// Budget limits the rate of requests made by a job run
+type Budget struct {
+	interval time.Duration
+	next     time.Time
+	mtx      sync.Mutex
+}
+
+// NewBudget returns a budget allowing perMinute requests per minute, zero or
+// less is unlimited
+func NewBudget(perMinute int) *Budget {
+	b := new(Budget)
+	if perMinute > 0 {
+		b.interval = time.Minute / time.Duration(perMinute)
+	}
+	return b
+}
+
+// Wait blocks until the budget allows another request or the context is
+// cancelled
+func (b *Budget) Wait(ctx context.Context) error {
+	if b.interval == 0 {
+		return ctx.Err()
+	}
+
+	b.mtx.Lock()
+	now := time.Now()
+	if b.next.Before(now) {
+		b.next = now
+	}
+	wait := b.next.Sub(now)
+	b.next = b.next.Add(b.interval)
+	b.mtx.Unlock()
+
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
@@ -0,0 +1,211 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	valid := []string{"* * * * *", "*/15 0-6 1,15 * 1-5", "0 9-17/2 * 1-12/3 7",
+		"@every 1h30m", "@hourly", "@daily", "@midnight", "@weekly", "@monthly"}
+	for x := range valid {
+		if _, err := ParseSchedule(valid[x]); err != nil {
+			t.Errorf("Test failed - ParseSchedule(%q) error %s", valid[x], err)
+		}
+	}
+
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *",
+		"@every", "@every 1ms", "@yearly"}
+	for x := range invalid {
+		if _, err := ParseSchedule(invalid[x]); err == nil {
+			t.Errorf("Test failed - ParseSchedule(%q) expected error", invalid[x])
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Monday 2018-10-01 10:20:30 UTC
+	from := time.Date(2018, 10, 1, 10, 20, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2018, 10, 1, 10, 21, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2018, 10, 1, 10, 30, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2018, 10, 1, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2018, 10, 2, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2018, 10, 7, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * 7", time.Date(2018, 10, 7, 2, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either the 15th or a Friday
+		{"0 0 15 * 5", time.Date(2018, 10, 5, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(time.Second * 90)},
+	}
+
+	for x := range tests {
+		s, err := ParseSchedule(tests[x].spec)
+		if err != nil {
+			t.Fatalf("Test failed - ParseSchedule(%q) error %s", tests[x].spec, err)
+		}
+
+		next := s.Next(from)
+		if !next.Equal(tests[x].expected) {
+			t.Errorf("Test failed - Next() %q expected %v got %v", tests[x].spec,
+				tests[x].expected, next)
+		}
+	}
+
+	s, _ := ParseSchedule("0 0 31 2 *")
+	if !s.Next(from).IsZero() {
+		t.Error("Test failed - Next() expected zero time for a schedule which never matches")
+	}
+}
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(6000)
+	start := time.Now()
+	for x := 0; x < 3; x++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatal("Test failed - Wait() error", err)
+		}
+	}
+
+	if time.Since(start) < time.Millisecond*20 {
+		t.Error("Test failed - Wait() budget not applied")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b = NewBudget(1)
+	b.Wait(context.Background())
+	if b.Wait(ctx) != context.Canceled {
+		t.Error("Test failed - Wait() expected cancelled error")
+	}
+
+	if NewBudget(0).Wait(context.Background()) != nil {
+		t.Error("Test failed - Wait() unlimited budget error")
+	}
+}
+
+func TestSchedulerTrigger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scheduler")
+	if err != nil {
+		t.Fatal("Test failed - TempDir() error", err)
+	}
+	defer os.RemoveAll(dir)
+	progressFile := filepath.Join(dir, "progress.json")
+
+	s, err := New(progressFile)
+	if err != nil {
+		t.Fatal("Test failed - New() error", err)
+	}
+
+	schedule, _ := ParseSchedule("@daily")
+	if s.Add(Job{Name: "blank", Schedule: schedule}) != ErrInvalidJob {
+		t.Error("Test failed - Add() expected invalid job error")
+	}
+
+	var mtx sync.Mutex
+	ran := make(map[string]int)
+	err = s.Add(Job{
+		Name:      "backfill",
+		Schedule:  schedule,
+		Exchanges: []string{"Bitstamp", "Kraken"},
+		Run: func(ctx context.Context, run *Run) error {
+			mtx.Lock()
+			ran[run.Exchange]++
+			mtx.Unlock()
+
+			if run.Exchange == "Kraken" {
+				return errors.New("unavailable")
+			}
+			return run.SetProgress("cursor", run.GetProgress("cursor")+"1")
+		},
+	})
+	if err != nil {
+		t.Fatal("Test failed - Add() error", err)
+	}
+
+	if s.Add(Job{Name: "backfill", Schedule: schedule, Run: func(context.Context, *Run) error { return nil }}) == nil {
+		t.Error("Test failed - Add() expected job exists error")
+	}
+
+	if s.Trigger(context.Background(), "blah") != ErrJobNotFound {
+		t.Error("Test failed - Trigger() expected job not found error")
+	}
+
+	if s.Trigger(context.Background(), "backfill") == nil {
+		t.Error("Test failed - Trigger() expected exchange error")
+	}
+
+	if ran["Bitstamp"] != 1 || ran["Kraken"] != 1 {
+		t.Error("Test failed - Trigger() job not run for each exchange", ran)
+	}
+
+	status := s.GetStatus()
+	if len(status) != 1 || status[0].Running || status[0].LastRun.IsZero() ||
+		status[0].Errors["Kraken"] != "unavailable" {
+		t.Errorf("Test failed - GetStatus() unexpected status %+v", status)
+	}
+
+	s.Trigger(context.Background(), "backfill")
+	loaded, err := New(progressFile)
+	if err != nil {
+		t.Fatal("Test failed - New() error", err)
+	}
+
+	run := Run{Job: "backfill", Exchange: "Bitstamp", s: loaded}
+	if run.GetProgress("cursor") != "11" {
+		t.Error("Test failed - GetProgress() progress not persisted", run.GetProgress("cursor"))
+	}
+}
+
+func TestSchedulerStart(t *testing.T) {
+	s, err := New("")
+	if err != nil {
+		t.Fatal("Test failed - New() error", err)
+	}
+
+	schedule, _ := ParseSchedule("@every 1s")
+	runs := make(chan struct{}, 10)
+	err = s.Add(Job{
+		Name:     "snapshot",
+		Schedule: schedule,
+		Run: func(ctx context.Context, run *Run) error {
+			runs <- struct{}{}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal("Test failed - Add() error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		s.Start(ctx)
+		close(stopped)
+	}()
+
+	select {
+	case <-runs:
+	case <-time.After(time.Second * 5):
+		t.Error("Test failed - Start() job not run")
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second * 5):
+		t.Error("Test failed - Start() did not stop on cancel")
+	}
+}
@@ -0,0 +1,36 @@
+package strategy
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// PluginSymbol is the func a Go plugin exports to create its strategy, of
+// type func() strategy.Strategy
+const PluginSymbol = "NewStrategy"
+
+// LoadPlugin opens a Go plugin built with go build -buildmode=plugin and
+// returns the strategy created by its NewStrategy func. Plugins must be built
+// with the same Go version and package versions as the bot
+func LoadPlugin(path string) (Strategy, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	newStrategy, ok := sym.(func() Strategy)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s %s is not a func() strategy.Strategy", path, PluginSymbol)
+	}
+
+	s := newStrategy()
+	if s == nil {
+		return nil, fmt.Errorf("plugin %s %s returned no strategy", path, PluginSymbol)
+	}
+	return s, nil
+}
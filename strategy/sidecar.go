@@ -0,0 +1,222 @@
+package strategy
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Sidecar message types besides the event types
+const (
+	messageInit = "init"
+	messageStop = "stop"
+)
+
+var (
+	// ErrSidecarNotRunning is returned when sending a message to a sidecar
+	// which hasn't been started or has been stopped
+	ErrSidecarNotRunning = errors.New("strategy sidecar not running")
+	// ErrSidecarExited is returned when a sidecar exits before responding
+	ErrSidecarExited = errors.New("strategy sidecar exited")
+	// ErrSidecarTimeout is returned when a sidecar doesn't respond within its
+	// timeout, after which it is killed
+	ErrSidecarTimeout = errors.New("strategy sidecar timed out")
+)
+
+// sidecarMessage holds a message sent to a sidecar
+type sidecarMessage struct {
+	Type     string            `json:"type"`
+	Name     string            `json:"name,omitempty"`
+	Settings map[string]string `json:"settings,omitempty"`
+	Ticker   *Ticker           `json:"ticker,omitempty"`
+	Fill     *Fill             `json:"fill,omitempty"`
+}
+
+// sidecarResponse holds the response of a sidecar to a message
+type sidecarResponse struct {
+	Intents []OrderIntent `json:"intents,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// Sidecar runs a strategy as an external process, so strategies can be
+// written in any language. Messages are JSON objects written one per line to
+// the standard input of the process, each answered by one line on its
+// standard output holding the order intents of the strategy and an error
+// message, if any. Lines written to standard error are logged
+//
+// The first message is {"type":"init","name":...,"settings":{...}}, events
+// are sent as {"type":"ticker","ticker":{...}} and {"type":"fill","fill":{...}}
+// and the last message is {"type":"stop"}, after which standard input is
+// closed and the process is expected to exit
+type Sidecar struct {
+	Command string
+	Args    []string
+	Timeout time.Duration
+
+	mtx       sync.Mutex
+	name      string
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan sidecarResponse
+	err       error
+}
+
+// NewSidecar returns a sidecar strategy running the command with args, each
+// message failing if the process doesn't respond within timeout
+func NewSidecar(command string, args []string, timeout time.Duration) *Sidecar {
+	return &Sidecar{Command: command, Args: args, Timeout: timeout}
+}
+
+// Init starts the sidecar process and sends it the init message
+func (s *Sidecar) Init(name string, settings map[string]string) error {
+	s.mtx.Lock()
+	if s.cmd != nil {
+		s.mtx.Unlock()
+		return errors.New("strategy sidecar already running")
+	}
+
+	cmd := exec.Command(s.Command, s.Args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		s.mtx.Unlock()
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		s.mtx.Unlock()
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		s.mtx.Unlock()
+		return err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		s.mtx.Unlock()
+		return err
+	}
+
+	s.name, s.cmd, s.stdin, s.err = name, cmd, stdin, nil
+	s.responses = make(chan sidecarResponse)
+	go s.readResponses(stdout, s.responses)
+	go s.logErrors(stderr)
+	s.mtx.Unlock()
+
+	_, err = s.request(sidecarMessage{Type: messageInit, Name: name, Settings: settings})
+	return err
+}
+
+// OnEvent sends an event to the sidecar and returns its order intents
+func (s *Sidecar) OnEvent(e Event) ([]OrderIntent, error) {
+	return s.request(sidecarMessage{Type: e.Type, Ticker: e.Ticker, Fill: e.Fill})
+}
+
+// Stop sends the stop message to the sidecar and closes its standard input,
+// killing the process if it doesn't exit within the timeout
+func (s *Sidecar) Stop() error {
+	_, err := s.request(sidecarMessage{Type: messageStop})
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.cmd == nil {
+		return err
+	}
+
+	s.stdin.Close()
+	done := make(chan struct{})
+	go func() {
+		s.cmd.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(s.Timeout):
+		s.cmd.Process.Kill()
+		<-done
+	}
+
+	// Drain any responses left unread so the reader can return
+	go func(responses <-chan sidecarResponse) {
+		for range responses {
+		}
+	}(s.responses)
+	s.cmd = nil
+	if err == ErrSidecarNotRunning {
+		return nil
+	}
+	return err
+}
+
+// request sends a message to the sidecar and waits for its response
+func (s *Sidecar) request(msg sidecarMessage) ([]OrderIntent, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.cmd == nil {
+		return nil, ErrSidecarNotRunning
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.stdin.Write(append(data, '\n'))
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := time.NewTimer(s.Timeout)
+	defer timeout.Stop()
+	select {
+	case resp, ok := <-s.responses:
+		if !ok {
+			s.err = ErrSidecarExited
+			return nil, s.err
+		}
+		if resp.Error != "" {
+			return resp.Intents, errors.New(resp.Error)
+		}
+		return resp.Intents, nil
+	case <-timeout.C:
+		// The response of a sidecar which missed its timeout could be
+		// mistaken for the response of the next message, so it is killed
+		s.cmd.Process.Kill()
+		s.err = ErrSidecarTimeout
+		return nil, s.err
+	}
+}
+
+// readResponses reads the responses of the sidecar until its standard output
+// is closed
+func (s *Sidecar) readResponses(stdout io.Reader, responses chan<- sidecarResponse) {
+	defer close(responses)
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var resp sidecarResponse
+		err := json.Unmarshal(scanner.Bytes(), &resp)
+		if err != nil {
+			resp.Error = "invalid strategy sidecar response: " + err.Error()
+		}
+		responses <- resp
+	}
+}
+
+// logErrors logs the lines the sidecar writes to standard error
+func (s *Sidecar) logErrors(stderr io.Reader) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		log.Printf("Strategy sidecar %s: %s\n", s.name, scanner.Text())
+	}
+}
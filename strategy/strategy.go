@@ -0,0 +1,106 @@
+package strategy
+
+import (
+	"errors"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Event types delivered to strategies
+const (
+	EventTicker = "ticker"
+	EventFill   = "fill"
+)
+
+// Order intent sides and types
+const (
+	SideBuy    = "BUY"
+	SideSell   = "SELL"
+	TypeMarket = "MARKET"
+	TypeLimit  = "LIMIT"
+)
+
+// ErrInvalidOrderIntent is returned when a strategy returns an order intent
+// missing required details
+var ErrInvalidOrderIntent = errors.New("order intent requires an exchange, pair, side, type, amount and a price for limit orders")
+
+// Strategy is implemented by external strategies, loaded from Go plugins or
+// run as sidecar processes. Market data and the fills of the strategy's orders
+// are delivered to OnEvent, which returns the orders the strategy wants placed
+type Strategy interface {
+	Init(name string, settings map[string]string) error
+	OnEvent(e Event) ([]OrderIntent, error)
+	Stop() error
+}
+
+// Ticker holds a ticker update of an exchange pair, the pair being formatted
+// with a dash delimiter such as BTC-USD
+type Ticker struct {
+	Exchange  string    `json:"exchange"`
+	Pair      string    `json:"pair"`
+	AssetType string    `json:"assetType"`
+	Timestamp time.Time `json:"timestamp"`
+	Last      float64   `json:"last"`
+	Bid       float64   `json:"bid"`
+	Ask       float64   `json:"ask"`
+	Volume    float64   `json:"volume"`
+}
+
+// Fill holds a fill of an order placed by the strategy
+type Fill struct {
+	Exchange  string    `json:"exchange"`
+	Pair      string    `json:"pair"`
+	Side      string    `json:"side"`
+	OrderID   int64     `json:"orderID"`
+	ClientID  string    `json:"clientID,omitempty"`
+	Amount    float64   `json:"amount"`
+	Price     float64   `json:"price"`
+	Fee       float64   `json:"fee"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Event holds market data or a fill delivered to a strategy, Type selecting
+// which of Ticker and Fill is set
+type Event struct {
+	Type   string  `json:"type"`
+	Ticker *Ticker `json:"ticker,omitempty"`
+	Fill   *Fill   `json:"fill,omitempty"`
+}
+
+// OrderIntent holds an order a strategy wants placed. The bot checks it
+// against the risk limits of the strategy before submitting it
+type OrderIntent struct {
+	Exchange string  `json:"exchange"`
+	Pair     string  `json:"pair"`
+	Side     string  `json:"side"`
+	Type     string  `json:"type"`
+	Amount   float64 `json:"amount"`
+	Price    float64 `json:"price,omitempty"`
+	ClientID string  `json:"clientID,omitempty"`
+}
+
+// Validate checks an order intent holds what an order needs, normalising the
+// case of its side and type
+func (o *OrderIntent) Validate() error {
+	o.Side = common.StringToUpper(o.Side)
+	o.Type = common.StringToUpper(o.Type)
+	if o.Exchange == "" || len(common.SplitStrings(o.Pair, "-")) != 2 || o.Amount <= 0 {
+		return ErrInvalidOrderIntent
+	}
+
+	if o.Side != SideBuy && o.Side != SideSell {
+		return ErrInvalidOrderIntent
+	}
+
+	switch o.Type {
+	case TypeMarket:
+	case TypeLimit:
+		if o.Price <= 0 {
+			return ErrInvalidOrderIntent
+		}
+	default:
+		return ErrInvalidOrderIntent
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package strategy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOrderIntentValidate(t *testing.T) {
+	o := OrderIntent{Exchange: "Bitstamp", Pair: "BTC-USD", Side: "buy", Type: "limit", Amount: 1}
+	if err := o.Validate(); err != ErrInvalidOrderIntent {
+		t.Errorf("Test Failed - Validate() expected %s for a limit order without a price, got %v",
+			ErrInvalidOrderIntent, err)
+	}
+
+	o.Price = 100
+	if err := o.Validate(); err != nil || o.Side != SideBuy || o.Type != TypeLimit {
+		t.Errorf("Test Failed - Validate() unexpected result %+v %v", o, err)
+	}
+
+	o.Pair = "BTCUSD"
+	if err := o.Validate(); err != ErrInvalidOrderIntent {
+		t.Errorf("Test Failed - Validate() expected %s for an undelimited pair, got %v",
+			ErrInvalidOrderIntent, err)
+	}
+
+	o = OrderIntent{Exchange: "Bitstamp", Pair: "BTC-USD", Side: "hold", Type: TypeMarket, Amount: 1}
+	if err := o.Validate(); err != ErrInvalidOrderIntent {
+		t.Errorf("Test Failed - Validate() expected %s for an invalid side, got %v",
+			ErrInvalidOrderIntent, err)
+	}
+}
+
+func TestLoadPlugin(t *testing.T) {
+	if _, err := LoadPlugin("testdata/missing.so"); err == nil {
+		t.Error("Test Failed - LoadPlugin() expected an error loading a missing plugin")
+	}
+}
+
+// TestSidecarHelperProcess isn't a real test, it runs as the sidecar process
+// of the sidecar tests, selling whenever the last price is above 100
+func TestSidecarHelperProcess(t *testing.T) {
+	mode := os.Getenv("GCT_STRATEGY_SIDECAR")
+	if mode == "" {
+		return
+	}
+	defer os.Exit(0)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var msg sidecarMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			fmt.Println(`{"error":"bad message"}`)
+			continue
+		}
+
+		var resp sidecarResponse
+		switch msg.Type {
+		case messageInit:
+			fmt.Fprintf(os.Stderr, "initialised %s\n", msg.Name)
+		case EventTicker:
+			if mode == "hang" {
+				time.Sleep(time.Minute)
+			}
+			if msg.Ticker.Last > 100 {
+				resp.Intents = []OrderIntent{{Exchange: msg.Ticker.Exchange, Pair: msg.Ticker.Pair,
+					Side: SideSell, Type: TypeMarket, Amount: 1}}
+			}
+		case EventFill:
+			resp.Error = "unexpected fill"
+		}
+
+		data, _ := json.Marshal(resp)
+		fmt.Println(string(data))
+		if msg.Type == messageStop {
+			return
+		}
+	}
+}
+
+func newTestSidecar(mode string) *Sidecar {
+	os.Setenv("GCT_STRATEGY_SIDECAR", mode)
+	return NewSidecar(os.Args[0], []string{"-test.run=TestSidecarHelperProcess"}, time.Second*5)
+}
+
+func TestSidecar(t *testing.T) {
+	s := newTestSidecar("default")
+	defer os.Unsetenv("GCT_STRATEGY_SIDECAR")
+
+	if _, err := s.OnEvent(Event{Type: EventTicker, Ticker: &Ticker{}}); err != ErrSidecarNotRunning {
+		t.Errorf("Test Failed - OnEvent() expected %s, got %v", ErrSidecarNotRunning, err)
+	}
+
+	if err := s.Init("sidecar", map[string]string{"threshold": "100"}); err != nil {
+		t.Fatalf("Test Failed - Init() error: %s", err)
+	}
+
+	intents, err := s.OnEvent(Event{Type: EventTicker,
+		Ticker: &Ticker{Exchange: "Bitstamp", Pair: "BTC-USD", Last: 101}})
+	if err != nil || len(intents) != 1 || intents[0].Side != SideSell || intents[0].Pair != "BTC-USD" {
+		t.Errorf("Test Failed - OnEvent() unexpected intents %+v %v", intents, err)
+	}
+
+	intents, err = s.OnEvent(Event{Type: EventTicker,
+		Ticker: &Ticker{Exchange: "Bitstamp", Pair: "BTC-USD", Last: 99}})
+	if err != nil || len(intents) != 0 {
+		t.Errorf("Test Failed - OnEvent() unexpected intents %+v %v", intents, err)
+	}
+
+	if _, err = s.OnEvent(Event{Type: EventFill, Fill: &Fill{}}); err == nil || err.Error() != "unexpected fill" {
+		t.Errorf("Test Failed - OnEvent() expected the sidecar error, got %v", err)
+	}
+
+	if err = s.Stop(); err != nil {
+		t.Errorf("Test Failed - Stop() error: %s", err)
+	}
+
+	if _, err = s.OnEvent(Event{Type: EventTicker, Ticker: &Ticker{}}); err != ErrSidecarNotRunning {
+		t.Errorf("Test Failed - OnEvent() expected %s after stopping, got %v", ErrSidecarNotRunning, err)
+	}
+}
+
+func TestSidecarTimeout(t *testing.T) {
+	s := newTestSidecar("hang")
+	defer os.Unsetenv("GCT_STRATEGY_SIDECAR")
+	s.Timeout = time.Millisecond * 500
+
+	if err := s.Init("sidecar", nil); err != nil {
+		t.Fatalf("Test Failed - Init() error: %s", err)
+	}
+
+	if _, err := s.OnEvent(Event{Type: EventTicker, Ticker: &Ticker{Last: 101}}); err != ErrSidecarTimeout {
+		t.Errorf("Test Failed - OnEvent() expected %s, got %v", ErrSidecarTimeout, err)
+	}
+
+	if _, err := s.OnEvent(Event{Type: EventTicker, Ticker: &Ticker{Last: 101}}); err != ErrSidecarTimeout {
+		t.Errorf("Test Failed - OnEvent() expected %s after timing out, got %v", ErrSidecarTimeout, err)
+	}
+
+	if err := s.Stop(); err != ErrSidecarTimeout {
+		t.Errorf("Test Failed - Stop() expected %s, got %v", ErrSidecarTimeout, err)
+	}
+}
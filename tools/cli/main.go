@@ -0,0 +1,275 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+
+	// Exchanges register themselves with the exchange registry when imported
+	_ "github.com/thrasher-/gocryptotrader/exchanges/anx"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/binance"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bitfinex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bitflyer"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bithumb"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bitmex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bitstamp"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/bittrex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/btcc"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/btcmarkets"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/coinbasepro"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/coinut"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/exmo"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/gateio"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/gemini"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/hitbtc"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/huobi"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/huobihadax"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/itbit"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/kraken"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/lakebtc"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/liqui"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/localbitcoins"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/okcoin"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/okex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/poloniex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/virtual"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/wex"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/yobit"
+	_ "github.com/thrasher-/gocryptotrader/exchanges/zb"
+)
+
+const usage = `Usage: cli -exchange <name> [flags] <command>
+
+Commands:
+  ticker     prints the ticker of -pair
+  orderbook  prints the top -depth levels of the orderbook of -pair
+  balances   lists the account balances
+  order      places a -side -type order for -amount of -pair at -price
+  cancel     cancels the order with -id
+  deposit    prints the deposit address of -currency
+
+Flags:
+`
+
+// newExchange returns the exchange wrapper for an exchange name
+func newExchange(name string) (exchange.IBotExchange, error) {
+	exch, err := exchange.NewExchangeByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("exchange %s not supported, supported exchanges: %s",
+			name, common.JoinStrings(exchange.RegisteredExchanges(), ", "))
+	}
+	return exch, nil
+}
+
+// loadExchange sets up an exchange from the config file, enabling it if it
+// is disabled in the config
+func loadExchange(configFile, name string) (exchange.IBotExchange, error) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config file: %s", err)
+	}
+
+	exchCfg, err := cfg.GetExchangeConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	exch, err := newExchange(exchCfg.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	exch.SetDefaults()
+	exchCfg.Enabled = true
+	err = exch.Setup(exchCfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s setup failed: %s", exchCfg.Name, err)
+	}
+	return exch, nil
+}
+
+func main() {
+	var configFile, exchangeName, currencyPair, assetType, side, orderType string
+	var currency, clientID string
+	var amount, price float64
+	var orderID int64
+	var depth int
+	flag.StringVar(&configFile, "config", config.ConfigFile, "the config file to load exchange settings and API keys from")
+	flag.StringVar(&exchangeName, "exchange", "", "the exchange to operate on")
+	flag.StringVar(&currencyPair, "pair", "", "the currency pair e.g. BTC-USD")
+	flag.StringVar(&assetType, "asset", ticker.Spot, "the asset type of the pair")
+	flag.StringVar(&side, "side", "buy", "the order side, buy or sell")
+	flag.StringVar(&orderType, "type", "limit", "the order type, limit or market")
+	flag.Float64Var(&amount, "amount", 0, "the order amount")
+	flag.Float64Var(&price, "price", 0, "the order price, unused by market orders")
+	flag.StringVar(&clientID, "clientid", "", "the optional client order ID of the order")
+	flag.Int64Var(&orderID, "id", 0, "the order ID to cancel")
+	flag.StringVar(&currency, "currency", "", "the currency of the deposit address e.g. BTC")
+	flag.IntVar(&depth, "depth", 10, "the orderbook levels to print per side")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if exchangeName == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	exch, err := loadExchange(configFile, exchangeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := pair.NewCurrencyPairFromString(common.StringToUpper(currencyPair))
+	switch flag.Arg(0) {
+	case "ticker":
+		requirePair(currencyPair)
+		err = printTicker(exch, p, assetType)
+	case "orderbook":
+		requirePair(currencyPair)
+		err = printOrderbook(exch, p, assetType, depth)
+	case "balances":
+		err = printBalances(exch)
+	case "order":
+		requirePair(currencyPair)
+		err = submitOrder(exch, p, side, orderType, amount, price, clientID)
+	case "cancel":
+		if orderID == 0 {
+			log.Fatal("An order ID must be specified with -id")
+		}
+		err = exch.CancelExchangeOrder(orderID)
+		if err == nil {
+			fmt.Printf("Cancelled %s order %d\n", exch.GetName(), orderID)
+		}
+	case "deposit":
+		if currency == "" {
+			log.Fatal("A currency must be specified with -currency")
+		}
+		var address string
+		address, err = exch.GetExchangeDepositAddress(pair.CurrencyItem(common.StringToUpper(currency)))
+		if err == nil {
+			fmt.Printf("%s %s deposit address: %s\n", exch.GetName(),
+				common.StringToUpper(currency), address)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("%s %s failed: %s", exch.GetName(), flag.Arg(0), err)
+	}
+}
+
+// requirePair exits when no currency pair was supplied
+func requirePair(currencyPair string) {
+	if currencyPair == "" {
+		log.Fatal("A currency pair must be specified with -pair")
+	}
+}
+
+// printTicker fetches and prints the ticker of a pair
+func printTicker(exch exchange.IBotExchange, p pair.CurrencyPair, assetType string) error {
+	tick, err := exch.UpdateTicker(p, assetType)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Pair\tLast\tBid\tAsk\tHigh\tLow\tVolume\t")
+	fmt.Fprintf(w, "%s\t%f\t%f\t%f\t%f\t%f\t%f\t\n", p.Pair(), tick.Last, tick.Bid,
+		tick.Ask, tick.High, tick.Low, tick.Volume)
+	return w.Flush()
+}
+
+// printOrderbook fetches a pair's orderbook and prints the top levels of each
+// side
+func printOrderbook(exch exchange.IBotExchange, p pair.CurrencyPair, assetType string, depth int) error {
+	ob, err := exch.UpdateOrderbook(p, assetType)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Bid amount\tBid\tAsk\tAsk amount\t")
+	for x := 0; x < depth && (x < len(ob.Bids) || x < len(ob.Asks)); x++ {
+		bid, bidAmount, ask, askAmount := "", "", "", ""
+		if x < len(ob.Bids) {
+			bid = fmt.Sprintf("%f", ob.Bids[x].Price)
+			bidAmount = fmt.Sprintf("%f", ob.Bids[x].Amount)
+		}
+		if x < len(ob.Asks) {
+			ask = fmt.Sprintf("%f", ob.Asks[x].Price)
+			askAmount = fmt.Sprintf("%f", ob.Asks[x].Amount)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", bidAmount, bid, ask, askAmount)
+	}
+	return w.Flush()
+}
+
+// printBalances fetches and prints the non zero account balances
+func printBalances(exch exchange.IBotExchange) error {
+	account, err := exch.GetExchangeAccountInfo()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Currency\tFree\tLocked\tTotal\t")
+	for _, x := range account.Currencies {
+		if x.Total == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%f\t%f\t%f\t\n", x.CurrencyName, x.Free, x.Locked, x.Total)
+	}
+	return w.Flush()
+}
+
+// submitOrder places an order and prints its order ID
+func submitOrder(exch exchange.IBotExchange, p pair.CurrencyPair, side, orderType string, amount, price float64, clientID string) error {
+	var orderSide exchange.OrderSide
+	switch common.StringToLower(side) {
+	case "buy":
+		orderSide = exchange.OrderSideBuy()
+	case "sell":
+		orderSide = exchange.OrderSideSell()
+	default:
+		return fmt.Errorf("invalid order side %s", side)
+	}
+
+	var typ exchange.OrderType
+	switch common.StringToLower(orderType) {
+	case "limit":
+		typ = exchange.OrderTypeLimit()
+		if price <= 0 {
+			return fmt.Errorf("limit orders require a -price")
+		}
+	case "market":
+		typ = exchange.OrderTypeMarket()
+	default:
+		return fmt.Errorf("invalid order type %s", orderType)
+	}
+
+	if amount <= 0 {
+		return fmt.Errorf("orders require an -amount")
+	}
+
+	orderID, err := exch.SubmitExchangeOrder(p, orderSide, typ, amount, price, clientID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Placed %s %s %s order for %f %s, order ID %d\n", exch.GetName(),
+		orderType, side, amount, p.Pair(), orderID)
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 )
 
 const (
+	backtestPath                    = "..%s..%sbacktest%s"
 	commonPath                      = "..%s..%scommon%s"
 	communicationsPath              = "..%s..%scommunications%s"
 	communicationsBasePath          = "..%s..%scommunications%sbase%s"
@@ -28,18 +29,24 @@ const (
 	currencyFXCurrencylayerPath     = "..%s..%scurrency%sforexprovider%scurrencylayer%s"
 	currencyFXFixerPath             = "..%s..%scurrency%sforexprovider%sfixer.io%s"
 	currencyFXOpenExchangeRatesPath = "..%s..%scurrency%sforexprovider%sopenexchangerates%s"
+	currencyMarketDataPath          = "..%s..%scurrency%smarketdata%s"
 	currencyPairPath                = "..%s..%scurrency%spair%s"
 	currencySymbolPath              = "..%s..%scurrency%ssymbol%s"
 	currencyTranslationPath         = "..%s..%scurrency%stranslation%s"
 	eventsPath                      = "..%s..%sevents%s"
 	exchangesPath                   = "..%s..%sexchanges%s"
+	exchangesKlinePath              = "..%s..%sexchanges%skline%s"
 	exchangesNoncePath              = "..%s..%sexchanges%snonce%s"
 	exchangesOrderbookPath          = "..%s..%sexchanges%sorderbook%s"
 	exchangesStatsPath              = "..%s..%sexchanges%sstats%s"
 	exchangesTickerPath             = "..%s..%sexchanges%sticker%s"
+	exchangesTradesPath             = "..%s..%sexchanges%strades%s"
 	exchangesOrdersPath             = "..%s..%sexchanges%sorders%s"
 	exchangesRequestPath            = "..%s..%sexchanges%srequest%s"
 	portfolioPath                   = "..%s..%sportfolio%s"
+	publisherPath                   = "..%s..%spublisher%s"
+	schedulerPath                   = "..%s..%sscheduler%s"
+	strategyPath                    = "..%s..%sstrategy%s"
 	testdataPath                    = "..%s..%stestdata%s"
 	toolsPath                       = "..%s..%stools%s"
 	webPath                         = "..%s..%sweb%s"
@@ -73,6 +80,7 @@ const (
 	okcoin        = "..%s..%sexchanges%sokcoin%s"
 	okex          = "..%s..%sexchanges%sokex%s"
 	poloniex      = "..%s..%sexchanges%spoloniex%s"
+	virtual       = "..%s..%sexchanges%svirtual%s"
 	wex           = "..%s..%sexchanges%swex%s"
 	yobit         = "..%s..%sexchanges%syobit%s"
 	zb            = "..%s..%sexchanges%szb%s"
@@ -183,6 +191,8 @@ func updateReadme() error {
 
 // Adds paths to different potential README.md files in the codebase
 func addPaths() {
+	codebasePaths["backtest"] = fmt.Sprintf(backtestPath, path, path, path)
+
 	codebasePaths["common"] = fmt.Sprintf(commonPath, path, path, path)
 
 	codebasePaths["communications comms"] = fmt.Sprintf(communicationsPath, path, path, path)
@@ -201,6 +211,7 @@ func addPaths() {
 	codebasePaths["currency forexprovider currencylayer"] = fmt.Sprintf(currencyFXCurrencylayerPath, path, path, path, path, path)
 	codebasePaths["currency forexprovider fixer"] = fmt.Sprintf(currencyFXFixerPath, path, path, path, path, path)
 	codebasePaths["currency forexprovider openexchangerates"] = fmt.Sprintf(currencyFXOpenExchangeRatesPath, path, path, path, path, path)
+	codebasePaths["currency marketdata"] = fmt.Sprintf(currencyMarketDataPath, path, path, path, path)
 	codebasePaths["currency pair"] = fmt.Sprintf(currencyPairPath, path, path, path, path)
 	codebasePaths["currency symbol"] = fmt.Sprintf(currencySymbolPath, path, path, path, path)
 	codebasePaths["currency translation"] = fmt.Sprintf(currencyTranslationPath, path, path, path, path)
@@ -208,16 +219,21 @@ func addPaths() {
 	codebasePaths["events"] = fmt.Sprintf(eventsPath, path, path, path)
 
 	codebasePaths["portfolio"] = fmt.Sprintf(portfolioPath, path, path, path)
+	codebasePaths["publisher"] = fmt.Sprintf(publisherPath, path, path, path)
+	codebasePaths["scheduler"] = fmt.Sprintf(schedulerPath, path, path, path)
+	codebasePaths["strategy"] = fmt.Sprintf(strategyPath, path, path, path)
 	codebasePaths["testdata"] = fmt.Sprintf(testdataPath, path, path, path)
 	codebasePaths["tools"] = fmt.Sprintf(toolsPath, path, path, path)
 	codebasePaths["web"] = fmt.Sprintf(webPath, path, path, path)
 	codebasePaths["root"] = fmt.Sprintf(rootPath, path, path)
 
 	codebasePaths["exchanges"] = fmt.Sprintf(exchangesPath, path, path, path)
+	codebasePaths["exchanges kline"] = fmt.Sprintf(exchangesKlinePath, path, path, path, path)
 	codebasePaths["exchanges nonce"] = fmt.Sprintf(exchangesNoncePath, path, path, path, path)
 	codebasePaths["exchanges orderbook"] = fmt.Sprintf(exchangesOrderbookPath, path, path, path, path)
 	codebasePaths["exchanges stats"] = fmt.Sprintf(exchangesStatsPath, path, path, path, path)
 	codebasePaths["exchanges ticker"] = fmt.Sprintf(exchangesTickerPath, path, path, path, path)
+	codebasePaths["exchanges trades"] = fmt.Sprintf(exchangesTradesPath, path, path, path, path)
 	codebasePaths["exchanges orders"] = fmt.Sprintf(exchangesOrdersPath, path, path, path, path)
 	codebasePaths["exchanges request"] = fmt.Sprintf(exchangesRequestPath, path, path, path, path)
 
@@ -248,6 +264,7 @@ func addPaths() {
 	codebasePaths["exchanges okcoin"] = fmt.Sprintf(okcoin, path, path, path, path)
 	codebasePaths["exchanges okex"] = fmt.Sprintf(okex, path, path, path, path)
 	codebasePaths["exchanges poloniex"] = fmt.Sprintf(poloniex, path, path, path, path)
+	codebasePaths["exchanges virtual"] = fmt.Sprintf(virtual, path, path, path, path)
 	codebasePaths["exchanges wex"] = fmt.Sprintf(wex, path, path, path, path)
 	codebasePaths["exchanges yobit"] = fmt.Sprintf(yobit, path, path, path, path)
 	codebasePaths["exchanges zb"] = fmt.Sprintf(zb, path, path, path, path)
@@ -303,12 +320,16 @@ func getslashFromName(packageName string) string {
 var globS = []string{
 	fmt.Sprintf("common_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("communications_templates%s*", common.GetOSPathSlash()),
+	fmt.Sprintf("backtest_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("config_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("currency_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("events_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("exchanges_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("portfolio_templates%s*", common.GetOSPathSlash()),
+	fmt.Sprintf("publisher_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("root_templates%s*", common.GetOSPathSlash()),
+	fmt.Sprintf("scheduler_templates%s*", common.GetOSPathSlash()),
+	fmt.Sprintf("strategy_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("sub_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("testdata_templates%s*", common.GetOSPathSlash()),
 	fmt.Sprintf("tools_templates%s*", common.GetOSPathSlash()),
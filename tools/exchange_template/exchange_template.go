@@ -214,7 +214,7 @@ func main() {
 	}
 
 	fmt.Println("GoCryptoTrader: Exchange templating tool service complete")
-	fmt.Println("When wrapper is finished add exchange to exchange.go")
+	fmt.Println("When wrapper is finished import the exchange package in exchange.go")
 	fmt.Println("Test exchange.go")
 	fmt.Println("Update the config_test.go file")
 	fmt.Println("Test config.go")
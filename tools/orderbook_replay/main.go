@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+func main() {
+	var file string
+	var speed float64
+	flag.StringVar(&file, "file", "", "orderbook recording to replay")
+	flag.Float64Var(&speed, "speed", 1, "replay speed multiplier, 0 replays as fast as possible")
+	flag.Parse()
+
+	if file == "" {
+		log.Fatal("An orderbook recording must be specified with -file")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		log.Fatalf("Unable to open orderbook recording: %s", err)
+	}
+	defer f.Close()
+
+	log.Printf("Replaying orderbook recording at %gx speed.", speed)
+
+	var count int
+	var first, last time.Time
+	var orderbooks []exchange.OrderbookRecord
+	seen := make(map[string]bool)
+	var local exchange.WebsocketOrderbookLocal
+	err = exchange.ReplayOrderbook(&local, f, speed, func(rec exchange.OrderbookRecord) {
+		if count == 0 {
+			first = rec.Timestamp
+		}
+		last = rec.Timestamp
+		count++
+
+		key := rec.Exchange + rec.Pair.Pair().String() + rec.AssetType
+		if !seen[key] {
+			seen[key] = true
+			orderbooks = append(orderbooks, rec)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Orderbook replay failed: %s", err)
+	}
+
+	if count == 0 {
+		log.Fatal("Orderbook recording is empty")
+	}
+
+	log.Printf("Replayed %d orderbook records spanning %s.", count,
+		last.Sub(first))
+
+	for x := range orderbooks {
+		ob, err := orderbook.GetOrderbook(orderbooks[x].Exchange,
+			orderbooks[x].Pair, orderbooks[x].AssetType)
+		if err != nil {
+			log.Printf("%s %s %s: %s", orderbooks[x].Exchange,
+				orderbooks[x].Pair.Pair(), orderbooks[x].AssetType, err)
+			continue
+		}
+
+		log.Printf("%s %s %s: %d bids %d asks last updated %s",
+			orderbooks[x].Exchange,
+			orderbooks[x].Pair.Pair(),
+			orderbooks[x].AssetType,
+			len(ob.Bids),
+			len(ob.Asks),
+			ob.LastUpdated)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+// ExchangeUsage holds the request, rate limit and websocket usage for an
+// exchange as returned by the bot
+type ExchangeUsage struct {
+	ExchangeName      string                 `json:"exchangeName"`
+	Requests          request.UsageStats     `json:"requests"`
+	AuthRateLimit     request.RateLimitUsage `json:"authRateLimit"`
+	UnauthRateLimit   request.RateLimitUsage `json:"unauthRateLimit"`
+	WebsocketEnabled  bool                   `json:"websocketEnabled"`
+	WebsocketMessages int64                  `json:"websocketMessages"`
+}
+
+// ExchangeFailure holds the error an exchange failed to load with as returned
+// by the bot
+type ExchangeFailure struct {
+	ExchangeName string    `json:"exchangeName"`
+	Error        string    `json:"error"`
+	Time         time.Time `json:"time"`
+}
+
+// UsageStatus holds the usage of all enabled exchanges, the exchanges which
+// failed to load and the ticker and orderbook stores as returned by the bot
+type UsageStatus struct {
+	Timestamp      int64                `json:"timestamp"`
+	Exchanges      []ExchangeUsage      `json:"exchanges"`
+	Failures       []ExchangeFailure    `json:"failures"`
+	TickerCache    ticker.CacheStats    `json:"tickerCache"`
+	OrderbookCache orderbook.CacheStats `json:"orderbookCache"`
+}
+
+// GetUsageStatus fetches the usage status from the running bot
+func GetUsageStatus(host string) (UsageStatus, error) {
+	var status UsageStatus
+	err := common.SendHTTPGetRequest(host+"/status/usage", true, false, &status)
+	return status, err
+}
+
+// perSecond returns the rate of change of a counter between two polls
+func perSecond(current, previous int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 || current < previous {
+		return 0
+	}
+	return float64(current-previous) / elapsed.Seconds()
+}
+
+// render writes the usage tables, deriving rates from the previous poll
+func render(status, previous UsageStatus, elapsed time.Duration) {
+	prev := make(map[string]ExchangeUsage)
+	for _, x := range previous.Exchanges {
+		prev[x.ExchangeName] = x
+	}
+
+	fmt.Printf("GoCryptoTrader status at %s\n\n",
+		time.Unix(status.Timestamp, 0).Format(time.RFC1123))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Exchange\tRequests\tReq/s\tAuth\tErrors\tLimited\tKB/s\tAuth limit\tUnauth limit\tWS msgs\tWS msg/s\t")
+
+	var totalRequests, totalMessages int64
+	var totalRequestRate, totalBandwidth, totalMessageRate float64
+	for _, x := range status.Exchanges {
+		p := prev[x.ExchangeName]
+		requestRate := perSecond(x.Requests.Requests, p.Requests.Requests, elapsed)
+		bandwidth := perSecond(x.Requests.BytesReceived, p.Requests.BytesReceived, elapsed) / 1024
+		messageRate := perSecond(x.WebsocketMessages, p.WebsocketMessages, elapsed)
+
+		websocketMessages := "-"
+		websocketRate := "-"
+		if x.WebsocketEnabled {
+			websocketMessages = fmt.Sprintf("%d", x.WebsocketMessages)
+			websocketRate = fmt.Sprintf("%.2f", messageRate)
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%.2f\t%d\t%d\t%d\t%.2f\t%s\t%s\t%s\t%s\t\n",
+			x.ExchangeName,
+			x.Requests.Requests,
+			requestRate,
+			x.Requests.AuthRequests,
+			x.Requests.Errors,
+			x.Requests.RateLimited,
+			bandwidth,
+			formatRateLimit(x.AuthRateLimit),
+			formatRateLimit(x.UnauthRateLimit),
+			websocketMessages,
+			websocketRate,
+		)
+
+		totalRequests += x.Requests.Requests
+		totalMessages += x.WebsocketMessages
+		totalRequestRate += requestRate
+		totalBandwidth += bandwidth
+		totalMessageRate += messageRate
+	}
+
+	fmt.Fprintf(w, "Total\t%d\t%.2f\t\t\t\t%.2f\t\t\t%d\t%.2f\t\n",
+		totalRequests, totalRequestRate, totalBandwidth, totalMessages, totalMessageRate)
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Store\tHits\tMisses\tHit rate\t")
+	fmt.Fprintf(w, "Ticker\t%d\t%d\t%.2f%%\t\n", status.TickerCache.Hits,
+		status.TickerCache.Misses, status.TickerCache.HitRate())
+	fmt.Fprintf(w, "Orderbook\t%d\t%d\t%.2f%%\t\n", status.OrderbookCache.Hits,
+		status.OrderbookCache.Misses, status.OrderbookCache.HitRate())
+	w.Flush()
+
+	if len(status.Failures) == 0 {
+		return
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Failed exchange\tSince\tError\t")
+	for _, x := range status.Failures {
+		fmt.Fprintf(w, "%s\t%s\t%s\t\n", x.ExchangeName,
+			x.Time.Format(time.RFC1123), x.Error)
+	}
+	w.Flush()
+}
+
+// formatRateLimit returns the rate limiter utilisation for display
+func formatRateLimit(r request.RateLimitUsage) string {
+	if r.Rate == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d (%.0f%%)", r.Requests, r.Rate, r.Utilisation)
+}
+
+func main() {
+	var host string
+	var refresh time.Duration
+	var once bool
+	flag.StringVar(&host, "host", "", "bot RESTful server address, defaults to the config webserver listen address")
+	flag.DurationVar(&refresh, "refresh", time.Second*2, "dashboard refresh interval")
+	flag.BoolVar(&once, "once", false, "render the dashboard once and exit")
+	flag.Parse()
+
+	if host == "" {
+		cfg := config.GetConfig()
+		err := cfg.LoadConfig(config.ConfigFile)
+		if err != nil {
+			log.Fatalf("Failed to load config file: %s", err)
+		}
+
+		listenAddr := cfg.Webserver.ListenAddress
+		host = fmt.Sprintf("%s:%d", common.ExtractHost(listenAddr),
+			common.ExtractPort(listenAddr))
+	}
+	host = "http://" + host
+
+	previous, err := GetUsageStatus(host)
+	if err != nil {
+		log.Fatalf("Unable to fetch status from %s: %s", host, err)
+	}
+	lastPoll := time.Now()
+
+	if once {
+		render(previous, UsageStatus{}, 0)
+		return
+	}
+
+	for range time.Tick(refresh) {
+		status, err := GetUsageStatus(host)
+		if err != nil {
+			log.Printf("Unable to fetch status from %s: %s", host, err)
+			continue
+		}
+
+		now := time.Now()
+		fmt.Print(clearScreen)
+		render(status, previous, now.Sub(lastPoll))
+		previous = status
+		lastPoll = now
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
 // Const vars for websocket
@@ -19,6 +26,9 @@ const (
 var (
 	wsHub        *WebsocketHub
 	wsHubStarted bool
+
+	orderbookEncoders    = make(map[string]*orderbook.DeltaEncoder)
+	orderbookEncodersMtx sync.Mutex
 )
 
 type wsCommandHandler struct {
@@ -27,16 +37,20 @@ type wsCommandHandler struct {
 }
 
 var wsHandlers = map[string]wsCommandHandler{
-	"auth":             {authRequired: false, handler: wsAuth},
-	"getconfig":        {authRequired: true, handler: wsGetConfig},
-	"saveconfig":       {authRequired: true, handler: wsSaveConfig},
-	"getaccountinfo":   {authRequired: true, handler: wsGetAccountInfo},
-	"gettickers":       {authRequired: false, handler: wsGetTickers},
-	"getticker":        {authRequired: false, handler: wsGetTicker},
-	"getorderbooks":    {authRequired: false, handler: wsGetOrderbooks},
-	"getorderbook":     {authRequired: false, handler: wsGetOrderbook},
-	"getexchangerates": {authRequired: false, handler: wsGetExchangeRates},
-	"getportfolio":     {authRequired: true, handler: wsGetPortfolio},
+	"auth":                 {authRequired: false, handler: wsAuth},
+	"getconfig":            {authRequired: true, handler: wsGetConfig},
+	"saveconfig":           {authRequired: true, handler: wsSaveConfig},
+	"getaccountinfo":       {authRequired: true, handler: wsGetAccountInfo},
+	"gettickers":           {authRequired: false, handler: wsGetTickers},
+	"getticker":            {authRequired: false, handler: wsGetTicker},
+	"getorderbooks":        {authRequired: false, handler: wsGetOrderbooks},
+	"getorderbook":         {authRequired: false, handler: wsGetOrderbook},
+	"getexchangerates":     {authRequired: false, handler: wsGetExchangeRates},
+	"getportfolio":         {authRequired: true, handler: wsGetPortfolio},
+	"subscribetickers":     {authRequired: false, handler: wsSubscribeTickers},
+	"unsubscribetickers":   {authRequired: false, handler: wsUnsubscribeTickers},
+	"subscribeorderbook":   {authRequired: false, handler: wsSubscribeOrderbook},
+	"unsubscribeorderbook": {authRequired: false, handler: wsUnsubscribeOrderbook},
 }
 
 // WebsocketClient stores information related to the websocket client
@@ -46,16 +60,65 @@ type WebsocketClient struct {
 	Authenticated bool
 	authFailures  int
 	Send          chan []byte
+	tickerStream  bool
+	orderbooks    map[string]bool
 }
 
 // WebsocketHub stores the data for managing websocket clients
 type WebsocketHub struct {
-	Clients    map[*WebsocketClient]bool
-	Broadcast  chan []byte
-	Register   chan *WebsocketClient
-	Unregister chan *WebsocketClient
+	Clients      map[*WebsocketClient]bool
+	Broadcast    chan []byte
+	Register     chan *WebsocketClient
+	Unregister   chan *WebsocketClient
+	TickerStream chan []byte
+	Subscribe    chan websocketTickerSubscription
+
+	OrderbookStream    chan websocketOrderbookMessage
+	OrderbookSubscribe chan websocketOrderbookSubscription
+}
+
+// websocketTickerSubscription toggles the ticker stream for a client
+type websocketTickerSubscription struct {
+	client    *WebsocketClient
+	subscribe bool
+}
+
+// websocketOrderbookSubscription toggles an orderbook stream for a client,
+// the snapshot is sent to the client by the hub so that it is queued ahead of
+// any subsequent deltas
+type websocketOrderbookSubscription struct {
+	client    *WebsocketClient
+	key       string
+	subscribe bool
+	snapshot  []byte
+}
+
+// websocketOrderbookMessage is an encoded orderbook delta for a stream
+type websocketOrderbookMessage struct {
+	key  string
+	data []byte
 }
 
+// WebsocketTickerStreamUpdate is a normalised ticker update pushed to every
+// client subscribed to the ticker stream. The sequence number increases by
+// one for every update so clients can detect dropped messages
+type WebsocketTickerStreamUpdate struct {
+	Sequence  uint64  `json:"sequence"`
+	Exchange  string  `json:"exchange"`
+	Pair      string  `json:"pair"`
+	AssetType string  `json:"assetType"`
+	Last      float64 `json:"last"`
+	Bid       float64 `json:"bid"`
+	Ask       float64 `json:"ask"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Volume    float64 `json:"volume"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+// tickerStreamSequence holds the last sent ticker stream sequence number
+var tickerStreamSequence uint64
+
 // WebsocketEvent is the struct used for websocket events
 type WebsocketEvent struct {
 	Exchange  string `json:"exchange,omitempty"`
@@ -92,6 +155,11 @@ func NewWebsocketHub() *WebsocketHub {
 		Register:   make(chan *WebsocketClient),
 		Unregister: make(chan *WebsocketClient),
 		Clients:    make(map[*WebsocketClient]bool),
+		// Buffered so exchange routines are not blocked by slow clients
+		TickerStream:       make(chan []byte, 1024),
+		Subscribe:          make(chan websocketTickerSubscription),
+		OrderbookStream:    make(chan websocketOrderbookMessage, 1024),
+		OrderbookSubscribe: make(chan websocketOrderbookSubscription),
 	}
 }
 
@@ -108,18 +176,55 @@ func (h *WebsocketHub) run() {
 			}
 		case message := <-h.Broadcast:
 			for client := range h.Clients {
-				select {
-				case client.Send <- message:
-				default:
-					log.Printf("websocket: disconnected client")
-					close(client.Send)
-					delete(h.Clients, client)
+				h.send(client, message)
+			}
+		case sub := <-h.Subscribe:
+			if _, ok := h.Clients[sub.client]; ok {
+				sub.client.tickerStream = sub.subscribe
+			}
+		case message := <-h.TickerStream:
+			for client := range h.Clients {
+				if client.tickerStream {
+					h.send(client, message)
+				}
+			}
+		case sub := <-h.OrderbookSubscribe:
+			if _, ok := h.Clients[sub.client]; !ok {
+				continue
+			}
+			if !sub.subscribe {
+				delete(sub.client.orderbooks, sub.key)
+				continue
+			}
+			if sub.client.orderbooks == nil {
+				sub.client.orderbooks = make(map[string]bool)
+			}
+			sub.client.orderbooks[sub.key] = true
+			if sub.snapshot != nil {
+				h.send(sub.client, sub.snapshot)
+			}
+		case message := <-h.OrderbookStream:
+			for client := range h.Clients {
+				if client.orderbooks[message.key] {
+					h.send(client, message.data)
 				}
 			}
 		}
 	}
 }
 
+// send queues a message for a client, disconnecting it if its send buffer
+// is full
+func (h *WebsocketHub) send(client *WebsocketClient, message []byte) {
+	select {
+	case client.Send <- message:
+	default:
+		log.Printf("websocket: disconnected client")
+		close(client.Send)
+		delete(h.Clients, client)
+	}
+}
+
 // SendWebsocketMessage sends a websocket event to the client
 func (c *WebsocketClient) SendWebsocketMessage(evt interface{}) error {
 	data, err := common.JSONEncode(evt)
@@ -249,6 +354,108 @@ func BroadcastWebsocketMessage(evt WebsocketEvent) error {
 	return nil
 }
 
+// StreamTickerUpdate pushes a normalised ticker update to all clients
+// subscribed to the ticker stream
+func StreamTickerUpdate(exchangeName, assetType string, p pair.CurrencyPair, price ticker.Price) error {
+	if !wsHubStarted {
+		return errors.New("websocket service not started")
+	}
+
+	timestamp := price.LastUpdated
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	data, err := common.JSONEncode(WebsocketEventResponse{
+		Event: "ticker_stream",
+		Data: WebsocketTickerStreamUpdate{
+			Sequence:  atomic.AddUint64(&tickerStreamSequence, 1),
+			Exchange:  exchangeName,
+			Pair:      exchange.FormatCurrency(p).String(),
+			AssetType: assetType,
+			Last:      price.Last,
+			Bid:       price.Bid,
+			Ask:       price.Ask,
+			High:      price.High,
+			Low:       price.Low,
+			Volume:    price.Volume,
+			Timestamp: timestamp.Unix(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case wsHub.TickerStream <- data:
+		return nil
+	default:
+		return errors.New("ticker stream buffer full, update dropped")
+	}
+}
+
+// orderbookStreamKey returns the key identifying an orderbook stream
+func orderbookStreamKey(exchangeName, assetType string, p pair.CurrencyPair) string {
+	return common.StringToLower(exchangeName) + " " +
+		p.FirstCurrency.Upper().String() + p.SecondCurrency.Upper().String() + " " +
+		common.StringToUpper(assetType)
+}
+
+// getOrderbookEncoder returns the delta encoder for an orderbook stream,
+// creating it if it does not exist
+func getOrderbookEncoder(key, exchangeName string) *orderbook.DeltaEncoder {
+	orderbookEncodersMtx.Lock()
+	defer orderbookEncodersMtx.Unlock()
+
+	encoder, ok := orderbookEncoders[key]
+	if !ok {
+		encoder = orderbook.NewDeltaEncoder(exchangeName, orderbook.DefaultSnapshotInterval)
+		orderbookEncoders[key] = encoder
+	}
+	return encoder
+}
+
+// findOrderbookEncoder returns the delta encoder for an orderbook stream if it
+// exists
+func findOrderbookEncoder(key string) (*orderbook.DeltaEncoder, bool) {
+	orderbookEncodersMtx.Lock()
+	defer orderbookEncodersMtx.Unlock()
+
+	encoder, ok := orderbookEncoders[key]
+	return encoder, ok
+}
+
+// StreamOrderbookUpdate delta encodes an orderbook update and pushes it to all
+// clients subscribed to the orderbook stream. A full snapshot is sent
+// periodically so clients can recover from dropped updates
+func StreamOrderbookUpdate(exchangeName, assetType string, p pair.CurrencyPair, book orderbook.Base) error {
+	if !wsHubStarted {
+		return errors.New("websocket service not started")
+	}
+
+	key := orderbookStreamKey(exchangeName, assetType, p)
+	book.AssetType = assetType
+	delta, changed := getOrderbookEncoder(key, exchangeName).Encode(book)
+	if !changed {
+		return nil
+	}
+
+	data, err := common.JSONEncode(WebsocketEventResponse{
+		Event: "orderbook_stream",
+		Data:  delta,
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case wsHub.OrderbookStream <- websocketOrderbookMessage{key: key, data: data}:
+		return nil
+	default:
+		return errors.New("orderbook stream buffer full, update dropped")
+	}
+}
+
 // WebsocketClientHandler upgrades the HTTP connection to a websocket
 // compatible one
 func WebsocketClientHandler(w http.ResponseWriter, r *http.Request) {
@@ -401,6 +608,100 @@ func wsGetTicker(client *WebsocketClient, data interface{}) error {
 	return client.SendWebsocketMessage(wsResp)
 }
 
+func wsSubscribeTickers(client *WebsocketClient, data interface{}) error {
+	client.Hub.Subscribe <- websocketTickerSubscription{client: client, subscribe: true}
+	return client.SendWebsocketMessage(WebsocketEventResponse{
+		Event: "SubscribeTickers",
+		Data:  WebsocketResponseSuccess,
+	})
+}
+
+func wsUnsubscribeTickers(client *WebsocketClient, data interface{}) error {
+	client.Hub.Subscribe <- websocketTickerSubscription{client: client, subscribe: false}
+	return client.SendWebsocketMessage(WebsocketEventResponse{
+		Event: "UnsubscribeTickers",
+		Data:  WebsocketResponseSuccess,
+	})
+}
+
+func wsSubscribeOrderbook(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "SubscribeOrderbook",
+	}
+	var orderbookReq WebsocketOrderbookTickerRequest
+	err := common.JSONDecode(data.([]byte), &orderbookReq)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	p := pair.NewCurrencyPairFromString(orderbookReq.Currency)
+	key := orderbookStreamKey(orderbookReq.Exchange, orderbookReq.AssetType, p)
+	var snapshot orderbook.Delta
+	encoder, ok := findOrderbookEncoder(key)
+	if ok {
+		snapshot, ok = encoder.Snapshot()
+	}
+	if !ok {
+		// Only create an encoder once the orderbook is known to exist so
+		// subscriptions to unknown books don't leave encoders behind
+		book, err := orderbook.GetOrderbook(orderbookReq.Exchange, p, orderbookReq.AssetType)
+		if err != nil {
+			wsResp.Error = err.Error()
+			client.SendWebsocketMessage(wsResp)
+			return err
+		}
+		book.AssetType = orderbookReq.AssetType
+		encoder = getOrderbookEncoder(key, orderbookReq.Exchange)
+		encoder.Encode(book)
+		snapshot, _ = encoder.Snapshot()
+	}
+
+	snapshotData, err := common.JSONEncode(WebsocketEventResponse{
+		Event: "orderbook_stream",
+		Data:  snapshot,
+	})
+	if err != nil {
+		return err
+	}
+
+	wsResp.Data = WebsocketResponseSuccess
+	err = client.SendWebsocketMessage(wsResp)
+	if err != nil {
+		return err
+	}
+
+	client.Hub.OrderbookSubscribe <- websocketOrderbookSubscription{
+		client:    client,
+		key:       key,
+		subscribe: true,
+		snapshot:  snapshotData,
+	}
+	return nil
+}
+
+func wsUnsubscribeOrderbook(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "UnsubscribeOrderbook",
+	}
+	var orderbookReq WebsocketOrderbookTickerRequest
+	err := common.JSONDecode(data.([]byte), &orderbookReq)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	p := pair.NewCurrencyPairFromString(orderbookReq.Currency)
+	client.Hub.OrderbookSubscribe <- websocketOrderbookSubscription{
+		client: client,
+		key:    orderbookStreamKey(orderbookReq.Exchange, orderbookReq.AssetType, p),
+	}
+	wsResp.Data = WebsocketResponseSuccess
+	return client.SendWebsocketMessage(wsResp)
+}
+
 func wsGetOrderbooks(client *WebsocketClient, data interface{}) error {
 	wsResp := WebsocketEventResponse{
 		Event: "GetOrderbooks",
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+func TestStreamTickerUpdate(t *testing.T) {
+	loadConfig(t)
+	StartWebsocketHandler()
+
+	subscribed := &WebsocketClient{Hub: wsHub, Send: make(chan []byte, 10)}
+	unsubscribed := &WebsocketClient{Hub: wsHub, Send: make(chan []byte, 10)}
+	wsHub.Register <- subscribed
+	wsHub.Register <- unsubscribed
+	wsHub.Subscribe <- websocketTickerSubscription{client: subscribed, subscribe: true}
+
+	p := pair.NewCurrencyPair("BTC", "USD")
+	for i := 0; i < 2; i++ {
+		err := StreamTickerUpdate("Bitstamp", ticker.Spot, p, ticker.Price{Last: 1337})
+		if err != nil {
+			t.Fatal("Test failed - StreamTickerUpdate() error", err)
+		}
+	}
+
+	var sequences []uint64
+	for i := 0; i < 2; i++ {
+		select {
+		case data := <-subscribed.Send:
+			var resp struct {
+				Event string                      `json:"event"`
+				Data  WebsocketTickerStreamUpdate `json:"data"`
+			}
+			err := common.JSONDecode(data, &resp)
+			if err != nil {
+				t.Fatal("Test failed - unable to decode ticker stream update", err)
+			}
+
+			if resp.Event != "ticker_stream" || resp.Data.Exchange != "Bitstamp" ||
+				resp.Data.Pair != exchange.FormatCurrency(p).String() || resp.Data.AssetType != ticker.Spot ||
+				resp.Data.Last != 1337 {
+				t.Errorf("Test failed - unexpected ticker stream update %+v", resp)
+			}
+			sequences = append(sequences, resp.Data.Sequence)
+		case <-time.After(time.Second):
+			t.Fatal("Test failed - ticker stream update not received")
+		}
+	}
+
+	if sequences[1] != sequences[0]+1 {
+		t.Errorf("Test failed - ticker stream sequence not incremented %v",
+			sequences)
+	}
+
+	select {
+	case <-unsubscribed.Send:
+		t.Error("Test failed - ticker stream update sent to unsubscribed client")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	wsHub.Unregister <- subscribed
+	wsHub.Unregister <- unsubscribed
+}
+
+func TestStreamOrderbookUpdate(t *testing.T) {
+	loadConfig(t)
+	StartWebsocketHandler()
+
+	subscribed := &WebsocketClient{Hub: wsHub, Send: make(chan []byte, 10)}
+	unsubscribed := &WebsocketClient{Hub: wsHub, Send: make(chan []byte, 10)}
+	wsHub.Register <- subscribed
+	wsHub.Register <- unsubscribed
+
+	p := pair.NewCurrencyPair("BTC", "USD")
+	wsHub.OrderbookSubscribe <- websocketOrderbookSubscription{
+		client:    subscribed,
+		key:       orderbookStreamKey("Bitstamp", orderbook.Spot, p),
+		subscribe: true,
+	}
+
+	books := []orderbook.Base{
+		{Pair: p, Bids: []orderbook.Item{{Price: 99, Amount: 1}}, Asks: []orderbook.Item{{Price: 101, Amount: 1}}},
+		{Pair: p, Bids: []orderbook.Item{{Price: 99, Amount: 2}}, Asks: []orderbook.Item{{Price: 101, Amount: 1}}},
+	}
+	for x := range books {
+		err := StreamOrderbookUpdate("Bitstamp", orderbook.Spot, p, books[x])
+		if err != nil {
+			t.Fatal("Test failed - StreamOrderbookUpdate() error", err)
+		}
+	}
+
+	var book orderbook.DeltaBook
+	for i := 0; i < 2; i++ {
+		select {
+		case data := <-subscribed.Send:
+			var resp struct {
+				Event string          `json:"event"`
+				Data  orderbook.Delta `json:"data"`
+			}
+			err := common.JSONDecode(data, &resp)
+			if err != nil {
+				t.Fatal("Test failed - unable to decode orderbook stream update", err)
+			}
+
+			if resp.Event != "orderbook_stream" || resp.Data.Exchange != "Bitstamp" ||
+				resp.Data.AssetType != orderbook.Spot || resp.Data.Snapshot != (i == 0) {
+				t.Errorf("Test failed - unexpected orderbook stream update %+v", resp)
+			}
+
+			err = book.Apply(resp.Data)
+			if err != nil {
+				t.Error("Test failed - unable to apply orderbook stream update", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Test failed - orderbook stream update not received")
+		}
+	}
+
+	bids := book.GetBids()
+	if len(bids) != 1 || bids[0].Amount != 2 {
+		t.Errorf("Test failed - unexpected streamed orderbook bids %v", bids)
+	}
+
+	select {
+	case <-unsubscribed.Send:
+		t.Error("Test failed - orderbook stream update sent to unsubscribed client")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	wsHub.Unregister <- subscribed
+	wsHub.Unregister <- unsubscribed
+}
+
+func TestWsSubscribeOrderbookUnknownBook(t *testing.T) {
+	client := &WebsocketClient{Send: make(chan []byte, 10)}
+	req := []byte(`{"exchangeName":"Unknown","currency":"BTCUSD","assetType":"SPOT"}`)
+	err := wsSubscribeOrderbook(client, req)
+	if err == nil {
+		t.Error("Test failed - wsSubscribeOrderbook() subscribed to an unknown orderbook")
+	}
+
+	key := orderbookStreamKey("Unknown", orderbook.Spot, pair.NewCurrencyPairFromString("BTCUSD"))
+	if _, ok := findOrderbookEncoder(key); ok {
+		t.Error("Test failed - wsSubscribeOrderbook() stored an encoder for an unknown orderbook")
+	}
+}